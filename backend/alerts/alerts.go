@@ -8,12 +8,16 @@ import (
 
 	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
 	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/opsgenie"
+	"github.com/highlight-run/highlight/backend/pagerduty"
 	"github.com/highlight-run/highlight/backend/routing"
+	"github.com/highlight-run/highlight/backend/splunkoncall"
 	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/highlight-run/highlight/backend/alerts/integrations"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoft_teams"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -27,29 +31,43 @@ type SendErrorAlertEvent struct {
 	ErrorCount      int64
 	VisitedURL      string
 	FirstErrorAlert bool
+
+	// The number of distinct users that hit the error within the threshold window of the alert
+	AffectedUserCount int64
 }
 
 func SendErrorAlert(ctx context.Context, event SendErrorAlertEvent) error {
 	payload := integrations.ErrorAlertPayload{
-		ErrorCount:      event.ErrorCount,
-		ErrorTitle:      event.ErrorGroup.Event,
-		UserIdentifier:  event.Session.Identifier,
-		ErrorURL:        getErrorURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
-		ErrorResolveURL: getErrorResolveURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
-		ErrorIgnoreURL:  getErrorIgnoreURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
-		ErrorSnoozeURL:  getErrorSnoozeURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
-		SessionSecureID: event.Session.SecureID,
-		SessionURL:      getSessionURL(event.ErrorAlert.ProjectID, event.Session),
-		SessionExcluded: event.Session.Excluded && *event.Session.Processed,
-		VisitedURL:      event.VisitedURL,
-		FirstTimeAlert:  event.FirstErrorAlert,
+		ErrorCount:        event.ErrorCount,
+		AffectedUserCount: event.AffectedUserCount,
+		ErrorTitle:        event.ErrorGroup.Event,
+		UserIdentifier:    event.Session.Identifier,
+		ErrorURL:          getErrorURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
+		ErrorResolveURL:   getErrorResolveURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
+		ErrorIgnoreURL:    getErrorIgnoreURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
+		ErrorSnoozeURL:    getErrorSnoozeURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
+		SessionSecureID:   event.Session.SecureID,
+		SessionURL:        getSessionURL(event.ErrorAlert.ProjectID, event.Session),
+		SessionExcluded:   event.Session.Excluded && *event.Session.Processed,
+		VisitedURL:        event.VisitedURL,
+		FirstTimeAlert:    event.FirstErrorAlert,
+	}
+	if event.ErrorGroup.SuspectCommit != nil {
+		payload.SuspectCommit = event.ErrorGroup.SuspectCommit.String()
+		payload.SuspectCommitURL = event.ErrorGroup.SuspectCommit.URL
 	}
 
+	pagerDutyPayload := attachReferrerToErrorAlertPayload(ctx, payload, routing.PagerDuty)
+	opsgeniePayload := attachReferrerToErrorAlertPayload(ctx, payload, routing.Opsgenie)
+	splunkOnCallPayload := attachReferrerToErrorAlertPayload(ctx, payload, routing.SplunkOnCall)
+	discordWebhookPayload := attachReferrerToErrorAlertPayload(ctx, payload, routing.Discord)
+	microsoftTeamsPayload := attachReferrerToErrorAlertPayload(ctx, payload, routing.MicrosoftTeams)
+
 	var g errgroup.Group
 	g.Go(func() error {
 		payload = attachReferrerToErrorAlertPayload(ctx, payload, routing.Webhook)
 		for _, wh := range event.ErrorAlert.WebhookDestinations {
-			if err := webhook.SendErrorAlert(wh, &payload); err != nil {
+			if err := webhook.SendErrorAlert(event.ErrorAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -77,6 +95,58 @@ func SendErrorAlert(ctx context.Context, event SendErrorAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.ErrorAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendErrorAlert(channel, microsoftTeamsPayload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.ErrorAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendErrorAlert(discordWebhookPayload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		if len(event.ErrorAlert.PagerDutyDestinations) == 0 {
+			return nil
+		}
+
+		client := pagerduty.NewClient()
+		for _, destination := range event.ErrorAlert.PagerDutyDestinations {
+			if _, err := client.Trigger(ctx, getPagerDutyTriggerInput(destination, event, pagerDutyPayload)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, destination := range event.ErrorAlert.OpsgenieDestinations {
+			client := opsgenie.NewClient(destination.APIKey, opsgenie.Region(destination.Region))
+			if _, err := client.CreateAlert(ctx, getOpsgenieAlertInput(destination, event, opsgeniePayload)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, destination := range event.ErrorAlert.SplunkOnCallDestinations {
+			client := splunkoncall.NewClient(destination.APIKey, destination.RoutingKey)
+			if _, err := client.Send(ctx, getSplunkOnCallAlert(destination, event, splunkOnCallPayload)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -143,7 +213,7 @@ func SendNewUserAlert(event SendNewUserAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendNewUserAlert(wh, &payload); err != nil {
+			if err := webhook.SendNewUserAlert(event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -170,6 +240,24 @@ func SendNewUserAlert(event SendNewUserAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.SessionAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendNewUserAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.SessionAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendNewUserAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -205,7 +293,7 @@ func SendNewSessionAlert(event SendNewSessionAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendNewSessionAlert(wh, &payload); err != nil {
+			if err := webhook.SendNewSessionAlert(event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -234,6 +322,24 @@ func SendNewSessionAlert(event SendNewSessionAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.SessionAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendNewSessionAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.SessionAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendNewSessionAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -272,7 +378,7 @@ func SendTrackPropertiesAlert(event TrackPropertiesAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendTrackPropertiesAlert(wh, &payload); err != nil {
+			if err := webhook.SendTrackPropertiesAlert(event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -301,6 +407,24 @@ func SendTrackPropertiesAlert(event TrackPropertiesAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.SessionAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendTrackPropertiesAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.SessionAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendTrackPropertiesAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -330,7 +454,7 @@ func SendUserPropertiesAlert(event UserPropertiesAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendUserPropertiesAlert(wh, &payload); err != nil {
+			if err := webhook.SendUserPropertiesAlert(event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -359,6 +483,24 @@ func SendUserPropertiesAlert(event UserPropertiesAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.SessionAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendUserPropertiesAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.SessionAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendUserPropertiesAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -388,7 +530,7 @@ func SendErrorFeedbackAlert(event ErrorFeedbackAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.ErrorAlert.WebhookDestinations {
-			if err := webhook.SendErrorFeedbackAlert(wh, &payload); err != nil {
+			if err := webhook.SendErrorFeedbackAlert(event.ErrorAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -417,6 +559,24 @@ func SendErrorFeedbackAlert(event ErrorFeedbackAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.ErrorAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendErrorFeedbackAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.ErrorAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendErrorFeedbackAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -437,7 +597,7 @@ func SendRageClicksAlert(event RageClicksAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendRageClicksAlert(wh, &payload); err != nil {
+			if err := webhook.SendRageClicksAlert(event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -466,6 +626,24 @@ func SendRageClicksAlert(event RageClicksAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		for _, channel := range event.SessionAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendRageClicksAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.SessionAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendRageClicksAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
 	return g.Wait()
 }
 
@@ -491,7 +669,7 @@ func SendMetricMonitorAlert(event MetricMonitorAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.MetricMonitor.WebhookDestinations {
-			if err := webhook.SendMetricMonitorAlert(wh, &payload); err != nil {
+			if err := webhook.SendMetricMonitorAlert(event.MetricMonitor.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -529,6 +707,7 @@ type LogAlertEvent struct {
 	Count     int
 	StartDate time.Time
 	EndDate   time.Time
+	Resolved  bool
 }
 
 func SendLogAlert(event LogAlertEvent) error {
@@ -540,11 +719,24 @@ func SendLogAlert(event LogAlertEvent) error {
 		EndDate:        event.EndDate,
 		Threshold:      event.LogAlert.CountThreshold,
 		BelowThreshold: event.LogAlert.BelowThreshold,
+		Resolved:       event.Resolved,
 		AlertURL:       tempalerts.GetLogAlertURL(event.LogAlert.ProjectID, event.LogAlert.Query, event.StartDate, event.EndDate),
 	}
 
 	for _, wh := range event.LogAlert.WebhookDestinations {
-		if err := webhook.SendLogAlert(wh, &payload); err != nil {
+		if err := webhook.SendLogAlert(event.LogAlert.ProjectID, wh, &payload); err != nil {
+			return err
+		}
+	}
+
+	for _, channel := range event.LogAlert.MicrosoftTeamsChannelsToNotify {
+		if err := microsoft_teams.SendLogAlert(channel, payload); err != nil {
+			return err
+		}
+	}
+
+	for _, wh := range event.LogAlert.DiscordWebhooksToNotify {
+		if err := discord.NewWebhook(wh).SendLogAlert(payload); err != nil {
 			return err
 		}
 	}
@@ -571,6 +763,175 @@ func SendLogAlert(event LogAlertEvent) error {
 	return nil
 }
 
+type TraceAlertEvent struct {
+	TraceAlert *model.TraceAlert
+	Workspace  *model.Workspace
+	Value      float64
+	SpanCount  uint64
+	AlertURL   string
+	Resolved   bool
+}
+
+func SendTraceAlert(event TraceAlertEvent) error {
+	payload := integrations.TraceAlertPayload{
+		Name:        event.TraceAlert.Name,
+		Description: event.TraceAlert.Describe(),
+		Value:       event.TraceAlert.FormatValue(event.Value),
+		Threshold:   event.TraceAlert.FormatValue(event.TraceAlert.Threshold),
+		SpanCount:   event.SpanCount,
+		Resolved:    event.Resolved,
+		AlertURL:    event.AlertURL,
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		for _, wh := range event.TraceAlert.WebhookDestinations {
+			if err := webhook.SendTraceAlert(event.TraceAlert.ProjectID, wh, &payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, channel := range event.TraceAlert.MicrosoftTeamsChannelsToNotify {
+			if err := microsoft_teams.SendTraceAlert(channel, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, wh := range event.TraceAlert.DiscordWebhooksToNotify {
+			if err := discord.NewWebhook(wh).SendTraceAlert(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		if !isWorkspaceIntegratedWithDiscord(*event.Workspace) {
+			return nil
+		}
+
+		bot, err := discord.NewDiscordBot(*event.Workspace.DiscordGuildId)
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range event.TraceAlert.DiscordChannelsToNotify {
+			if err := bot.SendTraceAlert(channel.ID, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
+
+type UptimeMonitorAlertEvent struct {
+	UptimeMonitor      *model.UptimeMonitor
+	Workspace          *model.Workspace
+	FailedChecks       int
+	FailureReason      string
+	StatusCode         int
+	LatencyMs          int
+	ErrorCount         uint64
+	BaselineErrorCount uint64
+}
+
+func SendUptimeMonitorAlert(event UptimeMonitorAlertEvent) error {
+	payload := integrations.UptimeMonitorAlertPayload{
+		Name:               event.UptimeMonitor.Name,
+		URL:                event.UptimeMonitor.URL,
+		FailedChecks:       event.FailedChecks,
+		FailureReason:      event.FailureReason,
+		StatusCode:         event.StatusCode,
+		LatencyMs:          event.LatencyMs,
+		ErrorCount:         event.ErrorCount,
+		BaselineErrorCount: event.BaselineErrorCount,
+		MonitorURL:         getUptimeMonitorURL(event.UptimeMonitor),
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		for _, wh := range event.UptimeMonitor.WebhookDestinations {
+			if err := webhook.SendUptimeMonitorAlert(event.UptimeMonitor.ProjectID, wh, &payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		if !isWorkspaceIntegratedWithDiscord(*event.Workspace) {
+			return nil
+		}
+
+		bot, err := discord.NewDiscordBot(*event.Workspace.DiscordGuildId)
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range event.UptimeMonitor.DiscordChannelsToNotify {
+			if err := bot.SendUptimeMonitorAlert(channel.ID, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
+
+type HeartbeatMonitorAlertEvent struct {
+	HeartbeatMonitor *model.HeartbeatMonitor
+	Workspace        *model.Workspace
+	Reason           string
+}
+
+func SendHeartbeatMonitorAlert(event HeartbeatMonitorAlertEvent) error {
+	payload := integrations.HeartbeatMonitorAlertPayload{
+		Name:          event.HeartbeatMonitor.Name,
+		Reason:        event.Reason,
+		LastCheckInAt: event.HeartbeatMonitor.LastCheckInAt,
+		MonitorURL:    getHeartbeatMonitorURL(event.HeartbeatMonitor),
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		for _, wh := range event.HeartbeatMonitor.WebhookDestinations {
+			if err := webhook.SendHeartbeatMonitorAlert(event.HeartbeatMonitor.ProjectID, wh, &payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		if !isWorkspaceIntegratedWithDiscord(*event.Workspace) {
+			return nil
+		}
+
+		bot, err := discord.NewDiscordBot(*event.Workspace.DiscordGuildId)
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range event.HeartbeatMonitor.DiscordChannelsToNotify {
+			if err := bot.SendHeartbeatMonitorAlert(channel.ID, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
+
 func isWorkspaceIntegratedWithDiscord(workspace model.Workspace) bool {
 	return workspace.DiscordGuildId != nil
 }
@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"math"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+// Error alerts in anomaly mode compare the errors of an error group to a baseline of the group for
+// the hour of the week, so that groups which are naturally bursty at some times do not alert then.
+
+const (
+	// baselineAlpha is the weight of the latest week in the baseline of an hour of the week.
+	baselineAlpha = 0.3
+	// BaselineMinSamples is the number of weeks of an hour of the week needed before its baseline is
+	// used. Until then, an alert in anomaly mode falls back to its count threshold.
+	BaselineMinSamples = 3
+	// DefaultAnomalySensitivity is the number of standard deviations above the baseline of a spike.
+	DefaultAnomalySensitivity = 3.
+)
+
+// HourOfWeek returns the hour of the week of the baseline that a time belongs to.
+func HourOfWeek(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// UpdateBaseline adds the count of errors of an hour to the baseline of its hour of the week, as an
+// exponentially weighted mean and variance.
+func UpdateBaseline(baseline *model.ErrorGroupBaseline, count float64) {
+	if baseline.Samples == 0 {
+		baseline.Mean = count
+		baseline.Variance = 0
+	} else {
+		diff := count - baseline.Mean
+		increment := baselineAlpha * diff
+		baseline.Mean += increment
+		baseline.Variance = (1 - baselineAlpha) * (baseline.Variance + diff*increment)
+	}
+	baseline.Samples++
+}
+
+// GetAnomalyScore returns the number of standard deviations that the count of errors in a window is
+// above the hourly baseline scaled to the window. Counts are treated as at least as variable as a
+// poisson process, so that a group with a steady count does not alert on any increase.
+func GetAnomalyScore(baseline *model.ErrorGroupBaseline, count float64, window time.Duration) float64 {
+	scale := window.Hours()
+	expected := baseline.Mean * scale
+	variance := math.Max(1, math.Max(baseline.Variance, baseline.Mean)*scale)
+	return (count - expected) / math.Sqrt(variance)
+}
+
+// IsErrorSpike returns whether the count of errors of a group in the window of an error alert in
+// anomaly mode is a spike above the baseline of the group.
+func IsErrorSpike(errorAlert *model.ErrorAlert, baseline *model.ErrorGroupBaseline, count int64, window time.Duration) bool {
+	if count < int64(errorAlert.CountThreshold) {
+		return false
+	}
+	if baseline == nil || baseline.Samples < BaselineMinSamples {
+		return true
+	}
+	sensitivity := errorAlert.AnomalySensitivity
+	if sensitivity <= 0 {
+		sensitivity = DefaultAnomalySensitivity
+	}
+	return GetAnomalyScore(baseline, float64(count), window) >= sensitivity
+}
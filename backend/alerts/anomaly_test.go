@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHourOfWeek(t *testing.T) {
+	assert.Equal(t, 0, HourOfWeek(time.Date(2024, 1, 7, 0, 30, 0, 0, time.UTC)))
+	assert.Equal(t, 24+13, HourOfWeek(time.Date(2024, 1, 8, 13, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 167, HourOfWeek(time.Date(2024, 1, 13, 23, 59, 0, 0, time.UTC)))
+}
+
+func TestUpdateBaseline(t *testing.T) {
+	baseline := &model.ErrorGroupBaseline{}
+	UpdateBaseline(baseline, 10)
+	assert.Equal(t, 1, baseline.Samples)
+	assert.Equal(t, 10., baseline.Mean)
+	assert.Equal(t, 0., baseline.Variance)
+
+	UpdateBaseline(baseline, 20)
+	assert.Equal(t, 2, baseline.Samples)
+	assert.InDelta(t, 13., baseline.Mean, 1e-9)
+	assert.InDelta(t, 21., baseline.Variance, 1e-9)
+
+	for i := 0; i < 50; i++ {
+		UpdateBaseline(baseline, 10)
+	}
+	assert.InDelta(t, 10., baseline.Mean, 1e-3)
+	assert.InDelta(t, 0., baseline.Variance, 1e-3)
+}
+
+func TestIsErrorSpike(t *testing.T) {
+	errorAlert := &model.ErrorAlert{Alert: model.Alert{CountThreshold: 5}, AnomalyDetection: true}
+	baseline := &model.ErrorGroupBaseline{Mean: 120, Variance: 400, Samples: BaselineMinSamples}
+
+	// below the count threshold
+	assert.False(t, IsErrorSpike(errorAlert, baseline, 4, time.Hour))
+	// without enough of a baseline, the count threshold alerts
+	assert.True(t, IsErrorSpike(errorAlert, nil, 5, time.Hour))
+	assert.True(t, IsErrorSpike(errorAlert, &model.ErrorGroupBaseline{Mean: 120, Samples: 1}, 5, time.Hour))
+
+	// a bursty group at its usual rate
+	assert.False(t, IsErrorSpike(errorAlert, baseline, 150, time.Hour))
+	assert.False(t, IsErrorSpike(errorAlert, baseline, 40, 30*time.Minute))
+	// three standard deviations above the baseline
+	assert.True(t, IsErrorSpike(errorAlert, baseline, 180, time.Hour))
+	assert.True(t, IsErrorSpike(errorAlert, baseline, 120, 30*time.Minute))
+
+	errorAlert.AnomalySensitivity = 5
+	assert.False(t, IsErrorSpike(errorAlert, baseline, 180, time.Hour))
+}
+
+func TestGetAnomalyScore(t *testing.T) {
+	// a steady count is as variable as a poisson process
+	baseline := &model.ErrorGroupBaseline{Mean: 100, Variance: 0, Samples: 10}
+	assert.InDelta(t, 3., GetAnomalyScore(baseline, 130, time.Hour), 1e-9)
+	// a quiet group needs more than a single error to spike
+	baseline = &model.ErrorGroupBaseline{Samples: 10}
+	assert.InDelta(t, 2., GetAnomalyScore(baseline, 2, time.Hour), 1e-9)
+}
@@ -0,0 +1,239 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/pagerduty"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Alerts with an escalation policy are escalated through its steps by the escalation worker, which
+// sends each step once its delay has passed until the alert is acknowledged or resolved.
+
+// EscalationEvent is an alert that starts an escalation.
+type EscalationEvent struct {
+	EscalationPolicyID *int
+	ProjectID          int
+	AlertType          string
+	AlertID            int
+	DedupKey           string
+	Title              string
+	Message            string
+	URL                string
+}
+
+// ErrorGroupEscalationKey returns the dedup key of the escalations of an error group, so that the
+// alerts of an error group are escalated once until it is resolved.
+func ErrorGroupEscalationKey(errorGroupID int) string {
+	return fmt.Sprintf("error-group-%d", errorGroupID)
+}
+
+// AlertEscalationKey returns the dedup key of the escalations of an alert which resolves on its own,
+// such as a log alert.
+func AlertEscalationKey(alertType string, alertID int) string {
+	return fmt.Sprintf("%s-%d", alertType, alertID)
+}
+
+// getEscalationPagerDutyKey returns the PagerDuty dedup key of the incidents of an escalation.
+func getEscalationPagerDutyKey(escalation *model.AlertEscalation) string {
+	return fmt.Sprintf("highlight-escalation-%s", escalation.DedupKey)
+}
+
+func GetEscalationAcknowledgeURL(escalation *model.AlertEscalation) string {
+	frontendURL := os.Getenv("FRONTEND_URI")
+	return fmt.Sprintf("%s/%d/alerts/escalations/%d?action=acknowledge", frontendURL, escalation.ProjectID, escalation.ID)
+}
+
+// GetNextStepAt returns when the step of a policy is due after the previous step was sent, or nil
+// if the policy has no more steps.
+func GetNextStepAt(policy *model.EscalationPolicy, step int, previous time.Time) *time.Time {
+	if step >= len(policy.Steps) {
+		return nil
+	}
+	return lo.ToPtr(previous.Add(policy.Steps[step].GetDelay()))
+}
+
+// GetEscalationMessage returns the message of a step of an escalation.
+func GetEscalationMessage(escalation *model.AlertEscalation, step int, now time.Time) string {
+	message := fmt.Sprintf("*%s*: %s", escalation.Title, escalation.Message)
+	if step > 0 {
+		message += fmt.Sprintf("\nUnacknowledged for %d minutes.", int(now.Sub(escalation.CreatedAt).Minutes()))
+	}
+	if escalation.URL != "" {
+		message += fmt.Sprintf("\nView alert: %s", escalation.URL)
+	}
+	return message + fmt.Sprintf("\nAcknowledge: %s", GetEscalationAcknowledgeURL(escalation))
+}
+
+// StartEscalation escalates an alert by its policy, unless the alert has no policy or its dedup key
+// already has an open escalation of the policy.
+func StartEscalation(ctx context.Context, db *gorm.DB, event EscalationEvent) error {
+	if event.EscalationPolicyID == nil {
+		return nil
+	}
+
+	var policies []*model.EscalationPolicy
+	if err := db.WithContext(ctx).
+		Where(&model.EscalationPolicy{Model: model.Model{ID: *event.EscalationPolicyID}, ProjectID: event.ProjectID}).
+		Limit(1).Find(&policies).Error; err != nil {
+		return errors.Wrap(err, "error querying escalation policy")
+	}
+	if len(policies) == 0 || len(policies[0].Steps) == 0 {
+		return nil
+	}
+
+	escalation := &model.AlertEscalation{
+		ProjectID:          event.ProjectID,
+		EscalationPolicyID: policies[0].ID,
+		DedupKey:           event.DedupKey,
+		AlertType:          event.AlertType,
+		AlertID:            event.AlertID,
+		Title:              event.Title,
+		Message:            event.Message,
+		URL:                event.URL,
+		NextStepAt:         GetNextStepAt(policies[0], 0, time.Now()),
+	}
+	// the open escalation of a dedup key is unique, so an alert that is already escalated is skipped
+	if err := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(escalation).Error; err != nil {
+		return errors.Wrap(err, "error creating alert escalation")
+	}
+	return nil
+}
+
+// StartErrorEscalation escalates an error alert by its policy, once for each error group.
+func StartErrorEscalation(ctx context.Context, db *gorm.DB, event SendErrorAlertEvent) error {
+	return StartEscalation(ctx, db, EscalationEvent{
+		EscalationPolicyID: event.ErrorAlert.EscalationPolicyID,
+		ProjectID:          event.ErrorAlert.ProjectID,
+		AlertType:          model.AlertType.ERROR,
+		AlertID:            event.ErrorAlert.ID,
+		DedupKey:           ErrorGroupEscalationKey(event.ErrorGroup.ID),
+		Title:              event.ErrorAlert.Name,
+		Message:            fmt.Sprintf("%s (%d errors)", event.ErrorGroup.Event, event.ErrorCount),
+		URL:                getErrorURL(event.ErrorAlert, event.ErrorGroup, event.ErrorObject),
+	})
+}
+
+// AcknowledgeEscalation stops escalating an alert, acknowledging the PagerDuty incidents that its
+// steps opened. It returns false if the escalation was already acknowledged or resolved.
+func AcknowledgeEscalation(ctx context.Context, db *gorm.DB, escalation *model.AlertEscalation, adminID *int) (bool, error) {
+	result := db.WithContext(ctx).Model(escalation).
+		Where("acknowledged_at IS NULL AND resolved_at IS NULL").
+		Updates(map[string]interface{}{
+			"acknowledged_at":    time.Now(),
+			"acknowledged_by_id": adminID,
+		})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "error acknowledging alert escalation")
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	updateEscalationIncidents(ctx, db, escalation, pagerduty.ActionAcknowledge)
+	return true, nil
+}
+
+// AcknowledgeEscalations acknowledges the open escalations of a dedup key, such as when an error
+// group is snoozed.
+func AcknowledgeEscalations(ctx context.Context, db *gorm.DB, projectID int, dedupKey string) error {
+	escalations, err := getOpenEscalations(ctx, db, projectID, dedupKey)
+	if err != nil {
+		return err
+	}
+	for _, escalation := range escalations {
+		if _, err := AcknowledgeEscalation(ctx, db, escalation, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveEscalations resolves the open escalations of a dedup key when its alert resolves, resolving
+// the PagerDuty incidents that their steps opened.
+func ResolveEscalations(ctx context.Context, db *gorm.DB, projectID int, dedupKey string) error {
+	escalations, err := getOpenEscalations(ctx, db, projectID, dedupKey)
+	if err != nil {
+		return err
+	}
+	for _, escalation := range escalations {
+		result := db.WithContext(ctx).Model(escalation).
+			Where("resolved_at IS NULL").
+			Updates(map[string]interface{}{
+				"resolved_at":  time.Now(),
+				"next_step_at": nil,
+			})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "error resolving alert escalation")
+		}
+		if result.RowsAffected > 0 {
+			updateEscalationIncidents(ctx, db, escalation, pagerduty.ActionResolve)
+		}
+	}
+	return nil
+}
+
+func getOpenEscalations(ctx context.Context, db *gorm.DB, projectID int, dedupKey string) ([]*model.AlertEscalation, error) {
+	var escalations []*model.AlertEscalation
+	if err := db.WithContext(ctx).
+		Where(&model.AlertEscalation{ProjectID: projectID, DedupKey: dedupKey}).
+		Where("resolved_at IS NULL").
+		Find(&escalations).Error; err != nil {
+		return nil, errors.Wrap(err, "error querying open alert escalations")
+	}
+	return escalations, nil
+}
+
+// updateEscalationIncidents acknowledges or resolves the PagerDuty incidents of the steps of an
+// escalation that were sent. Failures are only logged, like the incidents of error alerts.
+func updateEscalationIncidents(ctx context.Context, db *gorm.DB, escalation *model.AlertEscalation, action pagerduty.Action) {
+	var policy model.EscalationPolicy
+	if err := db.WithContext(ctx).Where(&model.EscalationPolicy{Model: model.Model{ID: escalation.EscalationPolicyID}}).Take(&policy).Error; err != nil {
+		return
+	}
+
+	client := pagerduty.NewClient()
+	for i, step := range policy.Steps {
+		if i >= escalation.NextStep {
+			break
+		}
+		if step.Type != model.EscalationStepPagerDuty || step.PagerDuty == nil {
+			continue
+		}
+		if _, err := client.Send(ctx, &pagerduty.Event{
+			RoutingKey:  step.PagerDuty.RoutingKey,
+			EventAction: action,
+			DedupKey:    getEscalationPagerDutyKey(escalation),
+		}); err != nil {
+			log.WithContext(ctx).WithError(err).WithField("escalation_id", escalation.ID).Error("error updating PagerDuty incident of escalation")
+		}
+	}
+}
+
+// TriggerEscalationIncident opens the PagerDuty incident of a step of an escalation.
+func TriggerEscalationIncident(ctx context.Context, escalation *model.AlertEscalation, destination *model.PagerDutyDestination) error {
+	var links []*pagerduty.Link
+	if escalation.URL != "" {
+		links = append(links, &pagerduty.Link{Href: escalation.URL, Text: "View alert"})
+	}
+	_, err := pagerduty.NewClient().Trigger(ctx, &pagerduty.TriggerInput{
+		RoutingKey: destination.RoutingKey,
+		DedupKey:   getEscalationPagerDutyKey(escalation),
+		Summary:    fmt.Sprintf("%s: %s", escalation.Title, escalation.Message),
+		Severity:   pagerduty.Severity(destination.Severity),
+		Class:      escalation.AlertType,
+		CustomDetails: map[string]any{
+			"alert":           escalation.Title,
+			"acknowledge_url": GetEscalationAcknowledgeURL(escalation),
+		},
+		Links: links,
+	})
+	return err
+}
@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNextStepAt(t *testing.T) {
+	policy := &model.EscalationPolicy{Steps: model.EscalationSteps{
+		{Type: model.EscalationStepSlack},
+		{Type: model.EscalationStepEmail, DelayMinutes: 15},
+	}}
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, now, *GetNextStepAt(policy, 0, now))
+	assert.Equal(t, now.Add(15*time.Minute), *GetNextStepAt(policy, 1, now))
+	assert.Nil(t, GetNextStepAt(policy, 2, now))
+}
+
+func TestGetEscalationMessage(t *testing.T) {
+	t.Setenv("FRONTEND_URI", "https://app.highlight.io")
+	createdAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	escalation := &model.AlertEscalation{
+		Model:     model.Model{ID: 7, CreatedAt: createdAt},
+		ProjectID: 1,
+		Title:     "Checkout errors",
+		Message:   "TypeError (3 errors)",
+		URL:       "https://app.highlight.io/1/errors/abc",
+	}
+
+	assert.Equal(t, "*Checkout errors*: TypeError (3 errors)\n"+
+		"View alert: https://app.highlight.io/1/errors/abc\n"+
+		"Acknowledge: https://app.highlight.io/1/alerts/escalations/7?action=acknowledge",
+		GetEscalationMessage(escalation, 0, createdAt))
+	assert.Contains(t, GetEscalationMessage(escalation, 1, createdAt.Add(20*time.Minute)), "\nUnacknowledged for 20 minutes.\n")
+}
+
+func TestEscalationKeys(t *testing.T) {
+	assert.Equal(t, "error-group-5", ErrorGroupEscalationKey(5))
+	assert.Equal(t, "LOG-3", AlertEscalationKey(model.AlertType.LOG, 3))
+}
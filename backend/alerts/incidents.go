@@ -0,0 +1,91 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/opsgenie"
+	"github.com/highlight-run/highlight/backend/pagerduty"
+	"github.com/highlight-run/highlight/backend/splunkoncall"
+)
+
+// The incidents of the incident management destinations are deduplicated by error group, so that
+// repeated alerts of an error group update its open incident, which is resolved with the error group.
+
+func getErrorAlertSummary(event SendErrorAlertEvent, payload integrations.ErrorAlertPayload) string {
+	return fmt.Sprintf("%s: %s", event.ErrorAlert.Name, payload.ErrorTitle)
+}
+
+func getErrorAlertDetails(event SendErrorAlertEvent, payload integrations.ErrorAlertPayload) map[string]string {
+	details := map[string]string{
+		"alert":       event.ErrorAlert.Name,
+		"error_count": fmt.Sprint(payload.ErrorCount),
+		"user":        payload.UserIdentifier,
+		"error_url":   payload.ErrorURL,
+	}
+	if !payload.SessionExcluded {
+		details["session_url"] = payload.SessionURL
+	}
+	if payload.VisitedURL != "" {
+		details["visited_url"] = payload.VisitedURL
+	}
+	if payload.SuspectCommit != "" {
+		details["suspect_commit"] = payload.SuspectCommit
+	}
+	return details
+}
+
+func getPagerDutyTriggerInput(destination *model.PagerDutyDestination, event SendErrorAlertEvent, payload integrations.ErrorAlertPayload) *pagerduty.TriggerInput {
+	customDetails := map[string]any{}
+	for key, value := range getErrorAlertDetails(event, payload) {
+		customDetails[key] = value
+	}
+
+	links := []*pagerduty.Link{{Href: payload.ErrorURL, Text: "View error"}}
+	if !payload.SessionExcluded {
+		links = append(links, &pagerduty.Link{Href: payload.SessionURL, Text: "View session"})
+	}
+
+	return &pagerduty.TriggerInput{
+		RoutingKey:    destination.RoutingKey,
+		DedupKey:      pagerduty.ErrorGroupDedupKey(event.ErrorGroup.ID),
+		Summary:       getErrorAlertSummary(event, payload),
+		Severity:      pagerduty.Severity(destination.Severity),
+		Class:         event.ErrorGroup.Type,
+		CustomDetails: customDetails,
+		Links:         links,
+	}
+}
+
+func getOpsgenieAlertInput(destination *model.OpsgenieDestination, event SendErrorAlertEvent, payload integrations.ErrorAlertPayload) *opsgenie.CreateAlertInput {
+	description := []string{payload.ErrorTitle, "", fmt.Sprintf("View error: %s", payload.ErrorURL)}
+	if !payload.SessionExcluded {
+		description = append(description, fmt.Sprintf("View session: %s", payload.SessionURL))
+	}
+
+	tags := []string{"highlight"}
+	if event.ErrorGroup.Type != "" {
+		tags = append(tags, event.ErrorGroup.Type)
+	}
+
+	return &opsgenie.CreateAlertInput{
+		Message:     getErrorAlertSummary(event, payload),
+		Alias:       opsgenie.ErrorGroupAlias(event.ErrorGroup.ID),
+		Description: strings.Join(description, "\n"),
+		Priority:    opsgenie.PriorityForSeverity(destination.Severity),
+		Details:     getErrorAlertDetails(event, payload),
+		Tags:        tags,
+	}
+}
+
+func getSplunkOnCallAlert(destination *model.SplunkOnCallDestination, event SendErrorAlertEvent, payload integrations.ErrorAlertPayload) *splunkoncall.Alert {
+	return &splunkoncall.Alert{
+		MessageType:       splunkoncall.MessageTypeForSeverity(destination.Severity),
+		EntityID:          splunkoncall.ErrorGroupEntityID(event.ErrorGroup.ID),
+		EntityDisplayName: getErrorAlertSummary(event, payload),
+		StateMessage:      payload.ErrorTitle,
+		Details:           getErrorAlertDetails(event, payload),
+	}
+}
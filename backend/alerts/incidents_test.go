@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/opsgenie"
+	"github.com/highlight-run/highlight/backend/pagerduty"
+	"github.com/highlight-run/highlight/backend/splunkoncall"
+	"github.com/stretchr/testify/assert"
+)
+
+var testErrorAlertEvent = SendErrorAlertEvent{
+	ErrorAlert: &model.ErrorAlert{Alert: model.Alert{Name: "Checkout errors"}},
+	ErrorGroup: &model.ErrorGroup{Model: model.Model{ID: 5}, Type: "Frontend"},
+}
+
+var testErrorAlertPayload = integrations.ErrorAlertPayload{
+	ErrorCount:      3,
+	ErrorTitle:      "TypeError: cannot read 'id'",
+	ErrorURL:        "https://app.highlight.io/1/errors/abc",
+	SessionURL:      "https://app.highlight.io/1/sessions/def",
+	SessionExcluded: true,
+	UserIdentifier:  "jane@example.com",
+}
+
+func TestGetPagerDutyTriggerInput(t *testing.T) {
+	assert := assert.New(t)
+
+	input := getPagerDutyTriggerInput(&model.PagerDutyDestination{RoutingKey: "key", Severity: "critical"}, testErrorAlertEvent, testErrorAlertPayload)
+	assert.Equal("key", input.RoutingKey)
+	assert.Equal("highlight-error-group-5", input.DedupKey)
+	assert.Equal("Checkout errors: TypeError: cannot read 'id'", input.Summary)
+	assert.Equal(pagerduty.SeverityCritical, input.Severity)
+	assert.Equal("Frontend", input.Class)
+	assert.Equal([]*pagerduty.Link{{Href: "https://app.highlight.io/1/errors/abc", Text: "View error"}}, input.Links)
+	assert.NotContains(input.CustomDetails, "visited_url")
+	assert.NotContains(input.CustomDetails, "session_url")
+}
+
+func TestGetOpsgenieAlertInput(t *testing.T) {
+	assert := assert.New(t)
+
+	input := getOpsgenieAlertInput(&model.OpsgenieDestination{APIKey: "key", Severity: "warning"}, testErrorAlertEvent, testErrorAlertPayload)
+	assert.Equal("Checkout errors: TypeError: cannot read 'id'", input.Message)
+	assert.Equal("highlight-error-group-5", input.Alias)
+	assert.Equal("TypeError: cannot read 'id'\n\nView error: https://app.highlight.io/1/errors/abc", input.Description)
+	assert.Equal(opsgenie.PriorityP3, input.Priority)
+	assert.Equal([]string{"highlight", "Frontend"}, input.Tags)
+	assert.Equal("3", input.Details["error_count"])
+	assert.NotContains(input.Details, "suspect_commit")
+
+	payload := testErrorAlertPayload
+	payload.SuspectCommit = "a1b2c3d Load users lazily (Jane Doe)"
+	input = getOpsgenieAlertInput(&model.OpsgenieDestination{APIKey: "key", Severity: "warning"}, testErrorAlertEvent, payload)
+	assert.Equal("a1b2c3d Load users lazily (Jane Doe)", input.Details["suspect_commit"])
+}
+
+func TestGetSplunkOnCallAlert(t *testing.T) {
+	assert := assert.New(t)
+
+	alert := getSplunkOnCallAlert(&model.SplunkOnCallDestination{APIKey: "key", RoutingKey: "team"}, testErrorAlertEvent, testErrorAlertPayload)
+	assert.Equal(splunkoncall.MessageTypeCritical, alert.MessageType)
+	assert.Equal("highlight-error-group-5", alert.EntityID)
+	assert.Equal("Checkout errors: TypeError: cannot read 'id'", alert.EntityDisplayName)
+	assert.Equal("jane@example.com", alert.Details["user"])
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/highlight-run/highlight/backend/alerts/integrations"
@@ -16,9 +17,10 @@ func newMessageEmbed() *discordgo.MessageEmbed {
 }
 
 var RED_ALERT = 0x961e13
+var GREEN_ALERT = 0x2eb886
 var YELLOW_ALERT = 0xf2c94c
 
-func (bot *Bot) SendErrorAlert(channelId string, payload integrations.ErrorAlertPayload) error {
+func errorAlertMessage(payload integrations.ErrorAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 
 	if payload.VisitedURL != "" {
@@ -45,6 +47,22 @@ func (bot *Bot) SendErrorAlert(channelId string, payload integrations.ErrorAlert
 		Inline: true,
 	})
 
+	if payload.AffectedUserCount > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Affected users",
+			Value:  strconv.FormatInt(payload.AffectedUserCount, 10),
+			Inline: true,
+		})
+	}
+
+	if payload.SuspectCommit != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Suspect commit",
+			Value:  fmt.Sprintf("[%s](%s)", payload.SuspectCommit, payload.SuspectCommitURL),
+			Inline: false,
+		})
+	}
+
 	embed := newMessageEmbed()
 	if payload.FirstTimeAlert {
 		embed.Title = "Highlight Error Alert (New Occurence ❇️)"
@@ -109,11 +127,10 @@ func (bot *Bot) SendErrorAlert(channelId string, payload integrations.ErrorAlert
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendNewUserAlert(channelId string, payload integrations.NewUserAlertPayload) error {
+func newUserAlertMessage(payload integrations.NewUserAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 	for key, value := range payload.UserProperties {
 		fields = append(fields, &discordgo.MessageEmbedField{
@@ -152,11 +169,10 @@ func (bot *Bot) SendNewUserAlert(channelId string, payload integrations.NewUserA
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendNewSessionAlert(channelId string, payload integrations.NewSessionAlertPayload) error {
+func newSessionAlertMessage(payload integrations.NewSessionAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 
 	if payload.VisitedURL != nil && *payload.VisitedURL != "" {
@@ -204,11 +220,10 @@ func (bot *Bot) SendNewSessionAlert(channelId string, payload integrations.NewSe
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendTrackPropertiesAlert(channelId string, payload integrations.TrackPropertiesAlertPayload) error {
+func trackPropertiesAlertMessage(payload integrations.TrackPropertiesAlertPayload) *discordgo.MessageSend {
 	matchedValue := []string{}
 	for _, field := range payload.MatchedProperties {
 		matchedValue = append(matchedValue, fmt.Sprintf("**%s**: %s", field.Key, field.Value))
@@ -244,12 +259,10 @@ func (bot *Bot) SendTrackPropertiesAlert(channelId string, payload integrations.
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendUserPropertiesAlert(channelId string, payload integrations.UserPropertiesAlertPayload) error {
+func userPropertiesAlertMessage(payload integrations.UserPropertiesAlertPayload) *discordgo.MessageSend {
 	matchedValue := []string{}
 	for _, field := range payload.MatchedProperties {
 		matchedValue = append(matchedValue, fmt.Sprintf("**%s**: %s", field.Key, field.Value))
@@ -284,12 +297,10 @@ func (bot *Bot) SendUserPropertiesAlert(channelId string, payload integrations.U
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendErrorFeedbackAlert(channelId string, payload integrations.ErrorFeedbackAlertPayload) error {
+func errorFeedbackAlertMessage(payload integrations.ErrorFeedbackAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 	fields = append(fields, &discordgo.MessageEmbedField{
 		Name:   "Comment",
@@ -320,12 +331,10 @@ func (bot *Bot) SendErrorFeedbackAlert(channelId string, payload integrations.Er
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendRageClicksAlert(channelId string, payload integrations.RageClicksAlertPayload) error {
+func rageClicksAlertMessage(payload integrations.RageClicksAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 
 	fields = append(fields, &discordgo.MessageEmbedField{
@@ -363,12 +372,10 @@ func (bot *Bot) SendRageClicksAlert(channelId string, payload integrations.RageC
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendMetricMonitorAlert(channelId string, payload integrations.MetricMonitorAlertPayload) error {
+func metricMonitorAlertMessage(payload integrations.MetricMonitorAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 
 	fields = append(fields, &discordgo.MessageEmbedField{
@@ -406,12 +413,10 @@ func (bot *Bot) SendMetricMonitorAlert(channelId string, payload integrations.Me
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
-
-	return err
+	return &messageSend
 }
 
-func (bot *Bot) SendLogAlert(channelId string, payload integrations.LogAlertPayload) error {
+func logAlertMessage(payload integrations.LogAlertPayload) *discordgo.MessageSend {
 	fields := []*discordgo.MessageEmbedField{}
 
 	if payload.Query != "" {
@@ -443,6 +448,11 @@ func (bot *Bot) SendLogAlert(channelId string, payload integrations.LogAlertPayl
 	embed.Title = "Highlight Log Alert"
 	embed.Color = RED_ALERT
 	embed.Description = fmt.Sprintf("*%s* is currently %s the threshold.", payload.Name, aboveStr)
+	if payload.Resolved {
+		embed.Title = "Highlight Log Alert Resolved"
+		embed.Color = GREEN_ALERT
+		embed.Description = fmt.Sprintf("*%s* is back within the threshold.", payload.Name)
+	}
 	embed.Fields = fields
 
 	messageSend := discordgo.MessageSend{
@@ -463,7 +473,200 @@ func (bot *Bot) SendLogAlert(channelId string, payload integrations.LogAlertPayl
 		},
 	}
 
-	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
+	return &messageSend
+}
+
+func uptimeMonitorAlertMessage(payload integrations.UptimeMonitorAlertPayload) *discordgo.MessageSend {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "URL",
+			Value:  payload.URL,
+			Inline: false,
+		},
+		{
+			Name:   "Failed Checks",
+			Value:  strconv.Itoa(payload.FailedChecks),
+			Inline: true,
+		},
+	}
+
+	if payload.FailureReason != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Reason",
+			Value:  payload.FailureReason,
+			Inline: true,
+		})
+	}
+
+	if payload.ErrorCount > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Backend Errors During Downtime",
+			Value:  fmt.Sprintf("%d (previously %d)", payload.ErrorCount, payload.BaselineErrorCount),
+			Inline: true,
+		})
+	}
+
+	embed := newMessageEmbed()
+	embed.Title = "Highlight Uptime Alert"
+	embed.Color = RED_ALERT
+	embed.Description = fmt.Sprintf("*%s* is failing its uptime checks.", payload.Name)
+	embed.Fields = fields
+
+	messageSend := discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			embed,
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "View Monitor",
+						Style:    discordgo.LinkButton,
+						Disabled: false,
+						URL:      payload.MonitorURL,
+					},
+				},
+			},
+		},
+	}
+
+	return &messageSend
+}
+
+func traceAlertMessage(payload integrations.TraceAlertPayload) *discordgo.MessageSend {
+	embed := newMessageEmbed()
+	embed.Title = "Highlight Trace Alert"
+	embed.Color = RED_ALERT
+	embed.Description = fmt.Sprintf("*%s*: the %s is %s, over the threshold of %s.", payload.Name, payload.Description, payload.Value, payload.Threshold)
+	if payload.Resolved {
+		embed.Title = "Highlight Trace Alert Resolved"
+		embed.Color = GREEN_ALERT
+		embed.Description = fmt.Sprintf("*%s*: the %s is back to %s, within the threshold of %s.", payload.Name, payload.Description, payload.Value, payload.Threshold)
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:   "Spans",
+			Value:  strconv.FormatUint(payload.SpanCount, 10),
+			Inline: true,
+		},
+	}
+
+	messageSend := discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			embed,
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "View Traces",
+						Style:    discordgo.LinkButton,
+						Disabled: false,
+						URL:      payload.AlertURL,
+					},
+				},
+			},
+		},
+	}
+
+	return &messageSend
+}
+
+func heartbeatMonitorAlertMessage(payload integrations.HeartbeatMonitorAlertPayload) *discordgo.MessageSend {
+	lastCheckIn := "Never"
+	if payload.LastCheckInAt != nil {
+		lastCheckIn = payload.LastCheckInAt.Format(time.RFC1123)
+	}
+
+	embed := newMessageEmbed()
+	embed.Title = "Highlight Heartbeat Alert"
+	embed.Color = RED_ALERT
+	embed.Description = fmt.Sprintf("*%s*: %s.", payload.Name, payload.Reason)
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:   "Last Check-In",
+			Value:  lastCheckIn,
+			Inline: true,
+		},
+	}
+
+	messageSend := discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			embed,
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "View Monitor",
+						Style:    discordgo.LinkButton,
+						Disabled: false,
+						URL:      payload.MonitorURL,
+					},
+				},
+			},
+		},
+	}
+
+	return &messageSend
+}
+
+func (bot *Bot) SendErrorAlert(channelId string, payload integrations.ErrorAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, errorAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendNewUserAlert(channelId string, payload integrations.NewUserAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, newUserAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendNewSessionAlert(channelId string, payload integrations.NewSessionAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, newSessionAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendTrackPropertiesAlert(channelId string, payload integrations.TrackPropertiesAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, trackPropertiesAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendUserPropertiesAlert(channelId string, payload integrations.UserPropertiesAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, userPropertiesAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendErrorFeedbackAlert(channelId string, payload integrations.ErrorFeedbackAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, errorFeedbackAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendRageClicksAlert(channelId string, payload integrations.RageClicksAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, rageClicksAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendMetricMonitorAlert(channelId string, payload integrations.MetricMonitorAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, metricMonitorAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendLogAlert(channelId string, payload integrations.LogAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, logAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendUptimeMonitorAlert(channelId string, payload integrations.UptimeMonitorAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, uptimeMonitorAlertMessage(payload))
+	return err
+}
+
+func (bot *Bot) SendHeartbeatMonitorAlert(channelId string, payload integrations.HeartbeatMonitorAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, heartbeatMonitorAlertMessage(payload))
+	return err
+}
 
+func (bot *Bot) SendTraceAlert(channelId string, payload integrations.TraceAlertPayload) error {
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, traceAlertMessage(payload))
 	return err
 }
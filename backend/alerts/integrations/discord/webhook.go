@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+)
+
+// webhookHosts are the hosts of the urls that Discord creates for channel webhooks.
+var webhookHosts = []string{"discord.com", "discordapp.com", "ptb.discord.com", "canary.discord.com"}
+
+// httpClient retries the webhook requests that fail with a server error or are rate limited.
+var httpClient = retryablehttp.NewClient()
+
+// Webhook posts alerts to a Discord channel through one of its webhooks, which doesn't require the
+// Highlight Discord bot to be added to the server.
+type Webhook struct {
+	webhook *model.DiscordWebhook
+}
+
+func NewWebhook(webhook *model.DiscordWebhook) *Webhook {
+	return &Webhook{webhook: webhook}
+}
+
+// ValidateWebhookURL checks that the url is the https url of a Discord channel webhook.
+func ValidateWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || !lo.Contains(webhookHosts, u.Host) || !strings.HasPrefix(u.Path, "/api/webhooks/") {
+		return fmt.Errorf("invalid Discord webhook url %s", webhookURL)
+	}
+	return nil
+}
+
+// webhookParams returns the webhook message of a bot message. Webhooks that are not owned by an
+// application can't send buttons, so the urls of the link buttons are added to the description of
+// the last embed instead.
+func webhookParams(message *discordgo.MessageSend) *discordgo.WebhookParams {
+	var links []string
+	for _, component := range message.Components {
+		row, ok := component.(discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			button, ok := rowComponent.(discordgo.Button)
+			if !ok || button.Disabled || button.URL == "" {
+				continue
+			}
+			links = append(links, fmt.Sprintf("[%s](%s)", button.Label, button.URL))
+		}
+	}
+
+	params := &discordgo.WebhookParams{
+		Content: message.Content,
+		Embeds:  message.Embeds,
+	}
+	if len(links) > 0 && len(params.Embeds) > 0 {
+		embed := params.Embeds[len(params.Embeds)-1]
+		if embed.Description != "" {
+			embed.Description += "\n\n"
+		}
+		embed.Description += strings.Join(links, " • ")
+	}
+	return params
+}
+
+// SendMessage posts a message to the webhook's channel.
+func (w *Webhook) SendMessage(message *discordgo.MessageSend) error {
+	if err := ValidateWebhookURL(w.webhook.WebhookURL); err != nil {
+		return err
+	}
+	body, err := json.Marshal(webhookParams(message))
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(w.webhook.WebhookURL, "application/json", body)
+	if err != nil {
+		return fmt.Errorf("error posting to Discord webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("Discord webhook %s received unexpected response code %d", w.webhook.Name, resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *Webhook) SendErrorAlert(payload integrations.ErrorAlertPayload) error {
+	return w.SendMessage(errorAlertMessage(payload))
+}
+
+func (w *Webhook) SendNewUserAlert(payload integrations.NewUserAlertPayload) error {
+	return w.SendMessage(newUserAlertMessage(payload))
+}
+
+func (w *Webhook) SendNewSessionAlert(payload integrations.NewSessionAlertPayload) error {
+	return w.SendMessage(newSessionAlertMessage(payload))
+}
+
+func (w *Webhook) SendTrackPropertiesAlert(payload integrations.TrackPropertiesAlertPayload) error {
+	return w.SendMessage(trackPropertiesAlertMessage(payload))
+}
+
+func (w *Webhook) SendUserPropertiesAlert(payload integrations.UserPropertiesAlertPayload) error {
+	return w.SendMessage(userPropertiesAlertMessage(payload))
+}
+
+func (w *Webhook) SendErrorFeedbackAlert(payload integrations.ErrorFeedbackAlertPayload) error {
+	return w.SendMessage(errorFeedbackAlertMessage(payload))
+}
+
+func (w *Webhook) SendRageClicksAlert(payload integrations.RageClicksAlertPayload) error {
+	return w.SendMessage(rageClicksAlertMessage(payload))
+}
+
+func (w *Webhook) SendLogAlert(payload integrations.LogAlertPayload) error {
+	return w.SendMessage(logAlertMessage(payload))
+}
+
+func (w *Webhook) SendTraceAlert(payload integrations.TraceAlertPayload) error {
+	return w.SendMessage(traceAlertMessage(payload))
+}
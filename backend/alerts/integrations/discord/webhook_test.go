@@ -0,0 +1,95 @@
+package discord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, ValidateWebhookURL("https://discord.com/api/webhooks/123/abc"))
+	assert.NoError(t, ValidateWebhookURL("https://discordapp.com/api/webhooks/123/abc"))
+	assert.Error(t, ValidateWebhookURL("http://discord.com/api/webhooks/123/abc"))
+	assert.Error(t, ValidateWebhookURL("https://example.com/api/webhooks/123/abc"))
+	assert.Error(t, ValidateWebhookURL("https://discord.com/channels/123"))
+	assert.Error(t, ValidateWebhookURL(""))
+}
+
+func TestWebhookParams(t *testing.T) {
+	params := webhookParams(errorAlertMessage(integrations.ErrorAlertPayload{
+		ErrorCount:        4,
+		AffectedUserCount: 2,
+		ErrorTitle:        "TypeError: cannot read 'id'",
+		ErrorURL:          "https://app.highlight.io/1/errors/abc",
+		ErrorResolveURL:   "https://app.highlight.io/1/errors/abc?action=resolved",
+		SessionSecureID:   "def",
+		SessionURL:        "https://app.highlight.io/1/sessions/def",
+		SessionExcluded:   true,
+		UserIdentifier:    "jane@example.com",
+	}))
+
+	assert.Empty(t, params.Components)
+	assert.Len(t, params.Embeds, 1)
+	embed := params.Embeds[0]
+	assert.Equal(t, "Highlight Error Alert", embed.Title)
+	// the excluded session and the empty urls are not linked
+	assert.Equal(t, "jane@example.com\n\n[View Error](https://app.highlight.io/1/errors/abc) • [Resolve Error](https://app.highlight.io/1/errors/abc?action=resolved)", embed.Description)
+
+	fields := map[string]string{}
+	for _, field := range embed.Fields {
+		fields[field.Name] = field.Value
+	}
+	assert.Equal(t, map[string]string{
+		"Error":          "TypeError: cannot read 'id'",
+		"Error count":    "4",
+		"Affected users": "2",
+	}, fields)
+}
+
+func TestWebhookSendErrorAlert(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if r.URL.Path == "/api/webhooks/1/invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := httpClient.HTTPClient
+	httpClient.HTTPClient = server.Client()
+	defer func() { httpClient.HTTPClient = client }()
+
+	serverURL, _ := url.Parse(server.URL)
+	hosts := webhookHosts
+	webhookHosts = append(webhookHosts, serverURL.Host)
+	defer func() { webhookHosts = hosts }()
+
+	payload := integrations.ErrorAlertPayload{
+		ErrorCount:      3,
+		ErrorTitle:      "TypeError: cannot read 'id'",
+		ErrorURL:        "https://app.highlight.io/1/errors/abc",
+		SessionSecureID: "def",
+		SessionURL:      "https://app.highlight.io/1/sessions/def",
+		UserIdentifier:  "jane@example.com",
+	}
+	assert.NoError(t, NewWebhook(&model.DiscordWebhook{Name: "alerts", WebhookURL: server.URL + "/api/webhooks/1/token"}).SendErrorAlert(payload))
+
+	embeds := received["embeds"].([]any)
+	assert.Len(t, embeds, 1)
+	assert.Equal(t, "Highlight Error Alert", embeds[0].(map[string]any)["title"])
+	// discordgo always sends the components of webhook messages, but no buttons are sent
+	assert.Nil(t, received["components"])
+
+	assert.Error(t, NewWebhook(&model.DiscordWebhook{Name: "alerts", WebhookURL: server.URL + "/api/webhooks/1/invalid"}).SendErrorAlert(payload))
+	assert.Error(t, NewWebhook(&model.DiscordWebhook{Name: "alerts", WebhookURL: "https://example.com/api/webhooks/1/token"}).SendErrorAlert(payload))
+}
@@ -7,18 +7,22 @@ import (
 )
 
 type ErrorAlertPayload struct {
-	ErrorCount      int64
-	ErrorTitle      string
-	SessionSecureID string
-	SessionURL      string
-	SessionExcluded bool
-	ErrorURL        string
-	ErrorResolveURL string
-	ErrorIgnoreURL  string
-	ErrorSnoozeURL  string
-	UserIdentifier  string
-	VisitedURL      string
-	FirstTimeAlert  bool
+	ErrorCount        int64
+	AffectedUserCount int64
+	ErrorTitle        string
+	SessionSecureID   string
+	SessionURL        string
+	SessionExcluded   bool
+	ErrorURL          string
+	ErrorResolveURL   string
+	ErrorIgnoreURL    string
+	ErrorSnoozeURL    string
+	UserIdentifier    string
+	VisitedURL        string
+	FirstTimeAlert    bool
+	// SuspectCommit is the commit that most likely introduced the error, if it is known.
+	SuspectCommit    string
+	SuspectCommitURL string
 }
 
 type NewUserAlertPayload struct {
@@ -82,7 +86,40 @@ type LogAlertPayload struct {
 	EndDate        time.Time
 	Threshold      int
 	BelowThreshold bool
-	AlertURL       string
+	// Resolved is set on the notification that the count is back within the threshold.
+	Resolved bool
+	AlertURL string
+}
+
+type TraceAlertPayload struct {
+	Name string
+	// Description is what the alert measures, eg. `P95 latency of checkout POST /orders`.
+	Description string
+	Value       string
+	Threshold   string
+	SpanCount   uint64
+	// Resolved is set on the notification that the value is back within the threshold.
+	Resolved bool
+	AlertURL string
+}
+
+type UptimeMonitorAlertPayload struct {
+	Name               string
+	URL                string
+	FailedChecks       int
+	FailureReason      string
+	StatusCode         int
+	LatencyMs          int
+	ErrorCount         uint64
+	BaselineErrorCount uint64
+	MonitorURL         string
+}
+
+type HeartbeatMonitorAlertPayload struct {
+	Name          string
+	Reason        string
+	LastCheckInAt *time.Time
+	MonitorURL    string
 }
 
 type BaseAlertIntegration interface {
@@ -96,4 +133,7 @@ type BaseAlertIntegration interface {
 	SendRageClicksAlert(channelId string, payload RageClicksAlertPayload) error
 	SendMetricMonitorAlert(channelId string, payload MetricMonitorAlertPayload) error
 	SendLogAlert(channelId string, payload MetricMonitorAlertPayload) error
+	SendUptimeMonitorAlert(channelId string, payload UptimeMonitorAlertPayload) error
+	SendHeartbeatMonitorAlert(channelId string, payload HeartbeatMonitorAlertPayload) error
+	SendTraceAlert(channelId string, payload TraceAlertPayload) error
 }
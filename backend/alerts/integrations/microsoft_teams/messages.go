@@ -0,0 +1,188 @@
+package microsoft_teams
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+// The colors of the card titles, which are named colors of the Teams theme.
+const (
+	colorAccent    = "Accent"
+	colorAttention = "Attention"
+	colorGood      = "Good"
+	colorWarning   = "Warning"
+)
+
+// propertyFacts returns the facts of a map of properties, sorted by key so that cards are stable.
+func propertyFacts(properties map[string]string) []*Fact {
+	var facts []*Fact
+	for key, value := range properties {
+		facts = append(facts, &Fact{Title: key, Value: value})
+	}
+	sort.Slice(facts, func(i, j int) bool {
+		return facts[i].Title < facts[j].Title
+	})
+	return facts
+}
+
+func listFacts(properties []integrations.Property) []*Fact {
+	var facts []*Fact
+	for _, property := range properties {
+		facts = append(facts, &Fact{Title: property.Key, Value: property.Value})
+	}
+	return facts
+}
+
+func ErrorAlertCard(payload integrations.ErrorAlertPayload) *AdaptiveCard {
+	title, color := "Highlight Error Alert", colorAttention
+	if payload.FirstTimeAlert {
+		title, color = "Highlight Error Alert (New Occurence ❇️)", colorWarning
+	}
+
+	var suspectCommit string
+	if payload.SuspectCommit != "" {
+		suspectCommit = fmt.Sprintf("[%s](%s)", payload.SuspectCommit, payload.SuspectCommitURL)
+	}
+
+	card := newCard(title, color, payload.UserIdentifier)
+	card.addFacts(
+		&Fact{Title: "Error", Value: payload.ErrorTitle},
+		&Fact{Title: "Error count", Value: strconv.FormatInt(payload.ErrorCount, 10)},
+		&Fact{Title: "Visited URL", Value: payload.VisitedURL},
+		&Fact{Title: "Suspect commit", Value: suspectCommit},
+	)
+	if payload.SessionSecureID != "" && !payload.SessionExcluded {
+		card.addAction("View Session", payload.SessionURL)
+	}
+	card.addAction("View Error", payload.ErrorURL)
+	card.addAction("Resolve Error", payload.ErrorResolveURL)
+	card.addAction("Ignore Error", payload.ErrorIgnoreURL)
+	card.addAction("Snooze Error", payload.ErrorSnoozeURL)
+	return card
+}
+
+func NewUserAlertCard(payload integrations.NewUserAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight New User Alert", colorAccent, payload.UserIdentifier)
+	card.addFacts(propertyFacts(payload.UserProperties)...)
+	card.addAction("View Session", payload.SessionURL)
+	return card
+}
+
+func NewSessionAlertCard(payload integrations.NewSessionAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight New Session Alert", colorAccent, payload.UserIdentifier)
+	if payload.VisitedURL != nil {
+		card.addFacts(&Fact{Title: "Visited URL", Value: *payload.VisitedURL})
+	}
+	card.addFacts(propertyFacts(payload.UserProperties)...)
+	card.addAction("View Session", payload.SessionURL)
+	return card
+}
+
+func TrackPropertiesAlertCard(payload integrations.TrackPropertiesAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight Track Properties Alert", colorAccent, payload.UserIdentifier)
+	card.Body = append(card.Body, &TextBlock{Type: "TextBlock", Text: "Matched Track Properties", Weight: "Bolder", Wrap: true})
+	card.addFacts(listFacts(payload.MatchedProperties)...)
+	if len(payload.RelatedProperties) > 0 {
+		card.Body = append(card.Body, &TextBlock{Type: "TextBlock", Text: "Related Track Properties", Weight: "Bolder", Wrap: true})
+		card.addFacts(listFacts(payload.RelatedProperties)...)
+	}
+	return card
+}
+
+func UserPropertiesAlertCard(payload integrations.UserPropertiesAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight User Properties Alert", colorAccent, payload.UserIdentifier)
+	card.Body = append(card.Body, &TextBlock{Type: "TextBlock", Text: "Matched User Properties", Weight: "Bolder", Wrap: true})
+	card.addFacts(listFacts(payload.MatchedProperties)...)
+	card.addAction("View Session", payload.SessionURL)
+	return card
+}
+
+func ErrorFeedbackAlertCard(payload integrations.ErrorFeedbackAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight Error Feedback Alert", colorAccent, payload.UserIdentifier)
+	card.addFacts(&Fact{Title: "Comment", Value: payload.CommentText})
+	card.addAction("View Comment", payload.SessionCommentURL)
+	return card
+}
+
+func RageClicksAlertCard(payload integrations.RageClicksAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight Rage Clicks Alert", colorAttention, payload.UserIdentifier)
+	card.addFacts(
+		&Fact{Title: "User", Value: payload.UserIdentifier},
+		&Fact{Title: "Rage click count", Value: strconv.FormatInt(payload.RageClicksCount, 10)},
+	)
+	card.addAction("View Session", payload.SessionURL)
+	return card
+}
+
+func LogAlertCard(payload integrations.LogAlertPayload) *AdaptiveCard {
+	aboveStr := "above"
+	if payload.BelowThreshold {
+		aboveStr = "below"
+	}
+
+	card := newCard("Highlight Log Alert", colorAttention, fmt.Sprintf("**%s** is currently %s the threshold.", payload.Name, aboveStr))
+	if payload.Resolved {
+		card = newCard("Highlight Log Alert Resolved", colorGood, fmt.Sprintf("**%s** is back within the threshold.", payload.Name))
+	}
+	card.addFacts(
+		&Fact{Title: "Query", Value: payload.Query},
+		&Fact{Title: "Count", Value: strconv.Itoa(payload.Count)},
+		&Fact{Title: "Threshold", Value: strconv.Itoa(payload.Threshold)},
+	)
+	card.addAction("View Logs", payload.AlertURL)
+	return card
+}
+
+func TraceAlertCard(payload integrations.TraceAlertPayload) *AdaptiveCard {
+	card := newCard("Highlight Trace Alert", colorAttention, fmt.Sprintf("**%s**: the %s is over the threshold.", payload.Name, payload.Description))
+	if payload.Resolved {
+		card = newCard("Highlight Trace Alert Resolved", colorGood, fmt.Sprintf("**%s**: the %s is back within the threshold.", payload.Name, payload.Description))
+	}
+	card.addFacts(
+		&Fact{Title: "Value", Value: payload.Value},
+		&Fact{Title: "Threshold", Value: payload.Threshold},
+		&Fact{Title: "Spans", Value: strconv.FormatUint(payload.SpanCount, 10)},
+	)
+	card.addAction("View Traces", payload.AlertURL)
+	return card
+}
+
+func SendErrorAlert(channel *model.MicrosoftTeamsChannel, payload integrations.ErrorAlertPayload) error {
+	return sendCard(channel, ErrorAlertCard(payload))
+}
+
+func SendNewUserAlert(channel *model.MicrosoftTeamsChannel, payload integrations.NewUserAlertPayload) error {
+	return sendCard(channel, NewUserAlertCard(payload))
+}
+
+func SendNewSessionAlert(channel *model.MicrosoftTeamsChannel, payload integrations.NewSessionAlertPayload) error {
+	return sendCard(channel, NewSessionAlertCard(payload))
+}
+
+func SendTrackPropertiesAlert(channel *model.MicrosoftTeamsChannel, payload integrations.TrackPropertiesAlertPayload) error {
+	return sendCard(channel, TrackPropertiesAlertCard(payload))
+}
+
+func SendUserPropertiesAlert(channel *model.MicrosoftTeamsChannel, payload integrations.UserPropertiesAlertPayload) error {
+	return sendCard(channel, UserPropertiesAlertCard(payload))
+}
+
+func SendErrorFeedbackAlert(channel *model.MicrosoftTeamsChannel, payload integrations.ErrorFeedbackAlertPayload) error {
+	return sendCard(channel, ErrorFeedbackAlertCard(payload))
+}
+
+func SendRageClicksAlert(channel *model.MicrosoftTeamsChannel, payload integrations.RageClicksAlertPayload) error {
+	return sendCard(channel, RageClicksAlertCard(payload))
+}
+
+func SendLogAlert(channel *model.MicrosoftTeamsChannel, payload integrations.LogAlertPayload) error {
+	return sendCard(channel, LogAlertCard(payload))
+}
+
+func SendTraceAlert(channel *model.MicrosoftTeamsChannel, payload integrations.TraceAlertPayload) error {
+	return sendCard(channel, TraceAlertCard(payload))
+}
@@ -0,0 +1,139 @@
+package microsoft_teams
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	// adaptiveCardVersion is the latest version of adaptive cards rendered by Teams on every client.
+	adaptiveCardVersion = "1.4"
+)
+
+// httpClient retries the webhook requests that fail with a server error.
+var httpClient = retryablehttp.NewClient()
+
+// AdaptiveCard is the card of an alert. The body is made of TextBlock and FactSet elements.
+type AdaptiveCard struct {
+	Type    string           `json:"type"`
+	Schema  string           `json:"$schema"`
+	Version string           `json:"version"`
+	Body    []any            `json:"body"`
+	Actions []*OpenURLAction `json:"actions,omitempty"`
+}
+
+type TextBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Color  string `json:"color,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type FactSet struct {
+	Type  string  `json:"type"`
+	Facts []*Fact `json:"facts"`
+}
+
+type OpenURLAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type attachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+type message struct {
+	Type        string        `json:"type"`
+	Attachments []*attachment `json:"attachments"`
+}
+
+func newCard(title string, color string, description string) *AdaptiveCard {
+	card := &AdaptiveCard{
+		Type:    "AdaptiveCard",
+		Schema:  adaptiveCardSchema,
+		Version: adaptiveCardVersion,
+		Body: []any{&TextBlock{
+			Type:   "TextBlock",
+			Text:   title,
+			Size:   "Large",
+			Weight: "Bolder",
+			Color:  color,
+			Wrap:   true,
+		}},
+	}
+	if description != "" {
+		card.Body = append(card.Body, &TextBlock{Type: "TextBlock", Text: description, Wrap: true})
+	}
+	return card
+}
+
+func (card *AdaptiveCard) addFacts(facts ...*Fact) {
+	var set []*Fact
+	for _, fact := range facts {
+		if fact.Value != "" {
+			set = append(set, fact)
+		}
+	}
+	if len(set) > 0 {
+		card.Body = append(card.Body, &FactSet{Type: "FactSet", Facts: set})
+	}
+}
+
+func (card *AdaptiveCard) addAction(title string, url string) {
+	if url == "" {
+		return
+	}
+	card.Actions = append(card.Actions, &OpenURLAction{Type: "Action.OpenUrl", Title: title, URL: url})
+}
+
+// ValidateWebhookURL checks that the url of a channel's incoming webhook is an https url, which
+// Teams creates for every incoming webhook and workflow.
+func ValidateWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return e.Errorf("invalid Microsoft Teams webhook url %s", webhookURL)
+	}
+	return nil
+}
+
+// sendCard posts the card to the channel's incoming webhook.
+func sendCard(channel *model.MicrosoftTeamsChannel, card *AdaptiveCard) error {
+	if err := ValidateWebhookURL(channel.WebhookURL); err != nil {
+		return err
+	}
+	body, err := json.Marshal(&message{
+		Type:        "message",
+		Attachments: []*attachment{{ContentType: adaptiveCardContentType, Content: card}},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(channel.WebhookURL, "application/json", body)
+	if err != nil {
+		return e.Wrap(err, "error posting to Microsoft Teams webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return e.Errorf("Microsoft Teams webhook of channel %s received unexpected response code %d", channel.Name, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package microsoft_teams
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendErrorAlert(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if r.URL.Path == "/invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	client := httpClient.HTTPClient
+	httpClient.HTTPClient = server.Client()
+	defer func() { httpClient.HTTPClient = client }()
+
+	payload := integrations.ErrorAlertPayload{
+		ErrorCount:      3,
+		ErrorTitle:      "TypeError: cannot read 'id'",
+		ErrorURL:        "https://app.highlight.io/1/errors/abc",
+		SessionSecureID: "def",
+		SessionURL:      "https://app.highlight.io/1/sessions/def",
+		UserIdentifier:  "jane@example.com",
+	}
+	assert.NoError(t, SendErrorAlert(&model.MicrosoftTeamsChannel{Name: "alerts", WebhookURL: server.URL + "/webhook"}, payload))
+
+	attachments := received["attachments"].([]any)
+	assert.Len(t, attachments, 1)
+	attachment := attachments[0].(map[string]any)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	card := attachment["content"].(map[string]any)
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	assert.Equal(t, "Highlight Error Alert", card["body"].([]any)[0].(map[string]any)["text"])
+
+	assert.Error(t, SendErrorAlert(&model.MicrosoftTeamsChannel{Name: "alerts", WebhookURL: server.URL + "/invalid"}, payload))
+	assert.Error(t, SendErrorAlert(&model.MicrosoftTeamsChannel{Name: "alerts", WebhookURL: "http://example.com/webhook"}, payload))
+}
+
+func TestErrorAlertCard(t *testing.T) {
+	card := ErrorAlertCard(integrations.ErrorAlertPayload{
+		ErrorCount:      1,
+		ErrorTitle:      "TypeError: cannot read 'id'",
+		ErrorURL:        "https://app.highlight.io/1/errors/abc",
+		SessionSecureID: "def",
+		SessionURL:      "https://app.highlight.io/1/sessions/def",
+		SessionExcluded: true,
+		FirstTimeAlert:  true,
+	})
+
+	title := card.Body[0].(*TextBlock)
+	assert.Equal(t, "Highlight Error Alert (New Occurence ❇️)", title.Text)
+	assert.Equal(t, colorWarning, title.Color)
+	// the empty user identifier and visited url are left out
+	assert.Equal(t, &FactSet{Type: "FactSet", Facts: []*Fact{
+		{Title: "Error", Value: "TypeError: cannot read 'id'"},
+		{Title: "Error count", Value: "1"},
+	}}, card.Body[1])
+	// the excluded session is not linked, nor are the empty action urls
+	assert.Equal(t, []*OpenURLAction{{Type: "Action.OpenUrl", Title: "View Error", URL: "https://app.highlight.io/1/errors/abc"}}, card.Actions)
+}
+
+func TestErrorAlertCardSuspectCommit(t *testing.T) {
+	card := ErrorAlertCard(integrations.ErrorAlertPayload{
+		ErrorCount:       1,
+		ErrorTitle:       "TypeError: cannot read 'id'",
+		ErrorURL:         "https://app.highlight.io/1/errors/abc",
+		SuspectCommit:    "a1b2c3d Load users lazily (Jane Doe)",
+		SuspectCommitURL: "https://github.com/highlight/highlight/commit/a1b2c3d",
+	})
+
+	assert.Equal(t, &FactSet{Type: "FactSet", Facts: []*Fact{
+		{Title: "Error", Value: "TypeError: cannot read 'id'"},
+		{Title: "Error count", Value: "1"},
+		{Title: "Suspect commit", Value: "[a1b2c3d Load users lazily (Jane Doe)](https://github.com/highlight/highlight/commit/a1b2c3d)"},
+	}}, card.Body[1])
+}
+
+func TestNewUserAlertCard(t *testing.T) {
+	card := NewUserAlertCard(integrations.NewUserAlertPayload{
+		UserIdentifier: "jane@example.com",
+		UserProperties: map[string]string{"Plan": "pro", "Email": "jane@example.com"},
+		SessionURL:     "https://app.highlight.io/1/sessions/def",
+	})
+	assert.Equal(t, &FactSet{Type: "FactSet", Facts: []*Fact{
+		{Title: "Email", Value: "jane@example.com"},
+		{Title: "Plan", Value: "pro"},
+	}}, card.Body[2])
+}
+
+func TestLogAlertCardResolved(t *testing.T) {
+	card := LogAlertCard(integrations.LogAlertPayload{
+		Name:      "Checkout timeouts",
+		Query:     "timeout",
+		Count:     2,
+		Threshold: 10,
+		Resolved:  true,
+		AlertURL:  "https://app.highlight.io/1/logs?query=timeout",
+	})
+
+	title := card.Body[0].(*TextBlock)
+	assert.Equal(t, "Highlight Log Alert Resolved", title.Text)
+	assert.Equal(t, colorGood, title.Color)
+	assert.Equal(t, []*OpenURLAction{{Type: "Action.OpenUrl", Title: "View Logs", URL: "https://app.highlight.io/1/logs?query=timeout"}}, card.Actions)
+}
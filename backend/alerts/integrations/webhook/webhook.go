@@ -2,165 +2,403 @@ package webhook
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/highlight-run/highlight/backend/alerts/integrations"
 	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
 	e "github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/segmentio/encoding/json"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+// EventType is the type of the alert of a webhook, sent in the `X-Highlight-Event` header and the
+// `EventType` field of the payload. The payload of each event type has the fields of its alert.
+type EventType string
+
+const (
+	EventTypeNewError           EventType = "error.new"
+	EventTypeErrorSpike         EventType = "error.spike"
+	EventTypeErrorFeedback      EventType = "error.feedback"
+	EventTypeNewUser            EventType = "session.new_user"
+	EventTypeNewSession         EventType = "session.new"
+	EventTypeTrackProperties    EventType = "session.track_properties"
+	EventTypeUserProperties     EventType = "session.user_properties"
+	EventTypeRageClicks         EventType = "session.rage_clicks"
+	EventTypeMetricMonitor      EventType = "metric_monitor.alert"
+	EventTypeLogAlert           EventType = "log.alert"
+	EventTypeLogAlertResolved   EventType = "log.resolved"
+	EventTypeTraceAlert         EventType = "trace.alert"
+	EventTypeTraceAlertResolved EventType = "trace.resolved"
+	EventTypeUptimeMonitor      EventType = "uptime_monitor.alert"
+	EventTypeHeartbeatMonitor   EventType = "heartbeat_monitor.alert"
+)
+
+const (
+	EventHeader     = "X-Highlight-Event"
+	DeliveryHeader  = "X-Highlight-Delivery"
+	TimestampHeader = "X-Highlight-Timestamp"
+	SignatureHeader = "X-Highlight-Signature"
+)
+
+const (
+	DefaultMaxRetries = 4
+	MaxRetries        = 10
+)
+
+// dialContext connects to webhook destinations. Destinations are user supplied, so internal
+// addresses are refused.
+var dialContext = util.NewPublicDialer(30 * time.Second).DialContext
+
 type ErrorAlertWebhook struct {
 	Event string
 	*integrations.ErrorAlertPayload
 }
 
-func sendWebhookData(destination *model.WebhookDestination, body []byte) error {
-	resp, err := retryablehttp.Post(destination.URL, "application/json", body)
+// Sign returns the signature of a webhook payload sent in the `X-Highlight-Signature` header, which
+// is the hex encoded HMAC-SHA256 of the timestamp and the body joined by a period, keyed with the
+// signing secret of the project.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type deliverySettings struct {
+	signingSecret string
+	maxRetries    int
+}
+
+// getDeliverySettings returns the webhook settings of the project, or the defaults if the project
+// has none.
+func getDeliverySettings(ctx context.Context, projectID int) deliverySettings {
+	settings := deliverySettings{maxRetries: DefaultMaxRetries}
+	if model.DB == nil {
+		return settings
+	}
+
+	var projectFilterSettings model.ProjectFilterSettings
+	if err := model.DB.WithContext(ctx).Model(&model.ProjectFilterSettings{}).Select("webhook_signing_secret", "webhook_max_retries").Where(&model.ProjectFilterSettings{ProjectID: projectID}).Take(&projectFilterSettings).Error; err != nil {
+		if !e.Is(err, gorm.ErrRecordNotFound) {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to query webhook settings")
+		}
+		return settings
+	}
+	if projectFilterSettings.WebhookSigningSecret != nil {
+		settings.signingSecret = *projectFilterSettings.WebhookSigningSecret
+	}
+	settings.maxRetries = projectFilterSettings.WebhookMaxRetries
+	return settings
+}
+
+func newHTTPClient(maxRetries int) *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.RetryMax = maxRetries
+	client.RetryWaitMin = time.Second
+	client.RetryWaitMax = 30 * time.Second
+	client.HTTPClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// a redirect is reported as the response of the delivery rather than followed
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	// return the response of the last attempt so that its status code is logged with the delivery
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if e.Is(err, util.ErrNonPublicAddress) {
+			return false, err
+		}
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+	return client
+}
+
+func sendWebhookData(projectID int, destination *model.WebhookDestination, eventType EventType, body []byte) error {
+	ctx := context.TODO()
+	delivery := &model.WebhookDelivery{
+		ProjectID:   projectID,
+		DeliveryID:  uuid.New().String(),
+		URL:         destination.URL,
+		EventType:   string(eventType),
+		RequestBody: string(body),
+	}
+	err := deliver(ctx, getDeliverySettings(ctx, projectID), destination, delivery, body)
+	if err != nil {
+		delivery.Error = lo.ToPtr(err.Error())
+	}
+
+	if model.DB != nil {
+		if dbErr := model.DB.WithContext(ctx).Create(delivery).Error; dbErr != nil {
+			log.WithContext(ctx).WithError(dbErr).WithField("project_id", projectID).Error("failed to log webhook delivery")
+		}
+	}
+	return err
+}
+
+func deliver(ctx context.Context, settings deliverySettings, destination *model.WebhookDestination, delivery *model.WebhookDelivery, body []byte) error {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, destination.URL, body)
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.DeliveryID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	if settings.signingSecret != "" {
+		req.Header.Set(SignatureHeader, Sign(settings.signingSecret, timestamp, body))
+	}
+	if destination.Authorization != nil && *destination.Authorization != "" {
+		req.Header.Set("Authorization", *destination.Authorization)
+	}
+
+	client := newHTTPClient(settings.maxRetries)
+	client.RequestLogHook = func(_ retryablehttp.Logger, _ *http.Request, attempt int) {
+		delivery.Attempts = attempt + 1
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = &resp.StatusCode
 
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 		logFields := log.Fields{}
 		if err := json.Unmarshal(body, &logFields); err == nil {
-			ctx := context.TODO()
-			logFields["Destination"] = destination
+			logFields["Destination"] = destination.URL
 			logFields["StatusCode"] = resp.StatusCode
+			logFields["Attempts"] = delivery.Attempts
 			log.WithContext(ctx).WithFields(logFields).Info("webhook sent successfully")
 		}
 
 		return nil
 	}
 
-	return e.New(fmt.Sprintf("webhook %+v received unexpected response code %d", destination, resp.StatusCode))
+	return e.New(fmt.Sprintf("webhook %s received unexpected response code %d after %d attempts", destination.URL, resp.StatusCode, delivery.Attempts))
 }
 
-func SendErrorAlert(destination *model.WebhookDestination, payload *integrations.ErrorAlertPayload) error {
+func SendErrorAlert(projectID int, destination *model.WebhookDestination, payload *integrations.ErrorAlertPayload) error {
+	eventType := EventTypeErrorSpike
+	if payload.FirstTimeAlert {
+		eventType = EventTypeNewError
+	}
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.ErrorAlertPayload
 	}{
 		Event:             model.AlertType.ERROR,
+		EventType:         eventType,
 		ErrorAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, eventType, body)
 }
 
-func SendNewUserAlert(destination *model.WebhookDestination, payload *integrations.NewUserAlertPayload) error {
+func SendNewUserAlert(projectID int, destination *model.WebhookDestination, payload *integrations.NewUserAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.NewUserAlertPayload
 	}{
 		Event:               model.AlertType.NEW_USER,
+		EventType:           EventTypeNewUser,
 		NewUserAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeNewUser, body)
 }
 
-func SendNewSessionAlert(destination *model.WebhookDestination, payload *integrations.NewSessionAlertPayload) error {
+func SendNewSessionAlert(projectID int, destination *model.WebhookDestination, payload *integrations.NewSessionAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.NewSessionAlertPayload
 	}{
 		Event:                  model.AlertType.NEW_USER,
+		EventType:              EventTypeNewSession,
 		NewSessionAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeNewSession, body)
 }
 
-func SendTrackPropertiesAlert(destination *model.WebhookDestination, payload *integrations.TrackPropertiesAlertPayload) error {
+func SendTrackPropertiesAlert(projectID int, destination *model.WebhookDestination, payload *integrations.TrackPropertiesAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.TrackPropertiesAlertPayload
 	}{
 		Event:                       model.AlertType.NEW_USER,
+		EventType:                   EventTypeTrackProperties,
 		TrackPropertiesAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeTrackProperties, body)
 }
 
-func SendUserPropertiesAlert(destination *model.WebhookDestination, payload *integrations.UserPropertiesAlertPayload) error {
+func SendUserPropertiesAlert(projectID int, destination *model.WebhookDestination, payload *integrations.UserPropertiesAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.UserPropertiesAlertPayload
 	}{
 		Event:                      model.AlertType.NEW_USER,
+		EventType:                  EventTypeUserProperties,
 		UserPropertiesAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeUserProperties, body)
 }
 
-func SendErrorFeedbackAlert(destination *model.WebhookDestination, payload *integrations.ErrorFeedbackAlertPayload) error {
+func SendErrorFeedbackAlert(projectID int, destination *model.WebhookDestination, payload *integrations.ErrorFeedbackAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.ErrorFeedbackAlertPayload
 	}{
 		Event:                     model.AlertType.ERROR_FEEDBACK,
+		EventType:                 EventTypeErrorFeedback,
 		ErrorFeedbackAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeErrorFeedback, body)
 }
 
-func SendRageClicksAlert(destination *model.WebhookDestination, payload *integrations.RageClicksAlertPayload) error {
+func SendRageClicksAlert(projectID int, destination *model.WebhookDestination, payload *integrations.RageClicksAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.RageClicksAlertPayload
 	}{
 		Event:                  model.AlertType.NEW_USER,
+		EventType:              EventTypeRageClicks,
 		RageClicksAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeRageClicks, body)
 }
 
-func SendMetricMonitorAlert(destination *model.WebhookDestination, payload *integrations.MetricMonitorAlertPayload) error {
+func SendMetricMonitorAlert(projectID int, destination *model.WebhookDestination, payload *integrations.MetricMonitorAlertPayload) error {
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.MetricMonitorAlertPayload
 	}{
 		Event:                     model.AlertType.NEW_USER,
+		EventType:                 EventTypeMetricMonitor,
 		MetricMonitorAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, EventTypeMetricMonitor, body)
 }
 
-func SendLogAlert(destination *model.WebhookDestination, payload *integrations.LogAlertPayload) error {
+func SendLogAlert(projectID int, destination *model.WebhookDestination, payload *integrations.LogAlertPayload) error {
+	eventType := EventTypeLogAlert
+	if payload.Resolved {
+		eventType = EventTypeLogAlertResolved
+	}
 	body, err := json.Marshal(&struct {
-		Event string
+		Event     string
+		EventType EventType
 		*integrations.LogAlertPayload
 	}{
 		Event:           model.AlertType.LOG,
+		EventType:       eventType,
 		LogAlertPayload: payload,
 	})
 	if err != nil {
 		return err
 	}
-	return sendWebhookData(destination, body)
+	return sendWebhookData(projectID, destination, eventType, body)
+}
+
+func SendTraceAlert(projectID int, destination *model.WebhookDestination, payload *integrations.TraceAlertPayload) error {
+	eventType := EventTypeTraceAlert
+	if payload.Resolved {
+		eventType = EventTypeTraceAlertResolved
+	}
+	body, err := json.Marshal(&struct {
+		Event     string
+		EventType EventType
+		*integrations.TraceAlertPayload
+	}{
+		Event:             model.AlertType.TRACE,
+		EventType:         eventType,
+		TraceAlertPayload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	return sendWebhookData(projectID, destination, eventType, body)
+}
+
+func SendUptimeMonitorAlert(projectID int, destination *model.WebhookDestination, payload *integrations.UptimeMonitorAlertPayload) error {
+	body, err := json.Marshal(&struct {
+		Event     string
+		EventType EventType
+		*integrations.UptimeMonitorAlertPayload
+	}{
+		Event:                     model.AlertType.UPTIME,
+		EventType:                 EventTypeUptimeMonitor,
+		UptimeMonitorAlertPayload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	return sendWebhookData(projectID, destination, EventTypeUptimeMonitor, body)
+}
+
+func SendHeartbeatMonitorAlert(projectID int, destination *model.WebhookDestination, payload *integrations.HeartbeatMonitorAlertPayload) error {
+	body, err := json.Marshal(&struct {
+		Event     string
+		EventType EventType
+		*integrations.HeartbeatMonitorAlertPayload
+	}{
+		Event:                        model.AlertType.HEARTBEAT,
+		EventType:                    EventTypeHeartbeatMonitor,
+		HeartbeatMonitorAlertPayload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	return sendWebhookData(projectID, destination, EventTypeHeartbeatMonitor, body)
 }
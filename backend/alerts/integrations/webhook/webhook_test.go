@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	// the test servers listen on a loopback address
+	dialContext = (&net.Dialer{}).DialContext
+	os.Exit(m.Run())
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686", Sign("secret", 1700000000, []byte(`{"a":1}`)))
+}
+
+func TestDeliver(t *testing.T) {
+	var requests []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	body := []byte(`{"EventType":"log.alert"}`)
+	delivery := &model.WebhookDelivery{DeliveryID: "abc", EventType: string(EventTypeLogAlert)}
+	err := deliver(context.TODO(), deliverySettings{signingSecret: "secret", maxRetries: 1}, &model.WebhookDestination{
+		URL:           server.URL,
+		Authorization: lo.ToPtr("Bearer token"),
+	}, delivery, body)
+	assert.NoError(t, err)
+
+	assert.Len(t, requests, 2)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, *delivery.StatusCode)
+
+	req := requests[1]
+	assert.Equal(t, body, bodies[1])
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	assert.Equal(t, "log.alert", req.Header.Get(EventHeader))
+	assert.Equal(t, "abc", req.Header.Get(DeliveryHeader))
+	timestamp, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, Sign("secret", timestamp, body), req.Header.Get(SignatureHeader))
+}
+
+func TestDeliverFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	defer server.Close()
+
+	delivery := &model.WebhookDelivery{}
+	err := deliver(context.TODO(), deliverySettings{maxRetries: 3}, &model.WebhookDestination{URL: server.URL}, delivery, []byte(`{}`))
+	assert.Error(t, err)
+	// client errors are not retried
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusBadRequest, *delivery.StatusCode)
+}
+
+func TestDeliverRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	delivery := &model.WebhookDelivery{}
+	err := deliver(context.TODO(), deliverySettings{}, &model.WebhookDestination{URL: server.URL}, delivery, []byte(`{}`))
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, *delivery.StatusCode)
+}
+
+func TestDeliverNonPublicAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("the request should not be sent to a loopback address")
+	}))
+	defer server.Close()
+
+	defer func(previous func(context.Context, string, string) (net.Conn, error)) { dialContext = previous }(dialContext)
+	dialContext = util.NewPublicDialer(time.Second).DialContext
+
+	delivery := &model.WebhookDelivery{}
+	err := deliver(context.TODO(), deliverySettings{maxRetries: 3}, &model.WebhookDestination{URL: server.URL}, delivery, []byte(`{}`))
+	assert.ErrorIs(t, err, util.ErrNonPublicAddress)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Nil(t, delivery.StatusCode)
+}
+
+func TestSendErrorAlert(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get(SignatureHeader))
+		assert.Equal(t, string(EventTypeNewError), r.Header.Get(EventHeader))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, SendErrorAlert(1, &model.WebhookDestination{URL: server.URL}, &integrations.ErrorAlertPayload{
+		ErrorCount:     1,
+		ErrorTitle:     "TypeError: cannot read 'id'",
+		FirstTimeAlert: true,
+	}))
+	assert.Equal(t, model.AlertType.ERROR, received["Event"])
+	assert.Equal(t, string(EventTypeNewError), received["EventType"])
+	assert.Equal(t, "TypeError: cannot read 'id'", received["ErrorTitle"])
+}
@@ -47,6 +47,8 @@ func BuildLogAlert(project *model.Project, workspace *model.Workspace, admin *mo
 		},
 		BelowThreshold: input.BelowThreshold,
 		Query:          input.Query,
+		// an edited alert starts over, notifying again if its new condition holds
+		State: model.AlertStateNormal,
 		AlertIntegrations: model.AlertIntegrations{
 			DiscordChannelsToNotify: discord.GQLInputToGo(input.DiscordChannels),
 			WebhookDestinations:     webhook.GQLInputToGo(input.WebhookDestinations),
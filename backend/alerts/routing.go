@@ -50,3 +50,13 @@ func getMonitorURL(metricMonitor *model.MetricMonitor) string {
 	frontendURL := os.Getenv("FRONTEND_URI")
 	return fmt.Sprintf("%s/%d/alerts/monitors/%d", frontendURL, metricMonitor.ProjectID, metricMonitor.ID)
 }
+
+func getUptimeMonitorURL(uptimeMonitor *model.UptimeMonitor) string {
+	frontendURL := os.Getenv("FRONTEND_URI")
+	return fmt.Sprintf("%s/%d/alerts/uptime/%d", frontendURL, uptimeMonitor.ProjectID, uptimeMonitor.ID)
+}
+
+func getHeartbeatMonitorURL(heartbeatMonitor *model.HeartbeatMonitor) string {
+	frontendURL := os.Getenv("FRONTEND_URI")
+	return fmt.Sprintf("%s/%d/alerts/heartbeats/%d", frontendURL, heartbeatMonitor.ProjectID, heartbeatMonitor.ID)
+}
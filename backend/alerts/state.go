@@ -0,0 +1,49 @@
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// The alerts that are evaluated on a schedule, such as log alerts, are deduplicated by their state,
+// so that an alert notifies once when its condition starts to hold and once when it resolves rather
+// than on every evaluation.
+
+// IsThresholdCrossed returns whether the value of an evaluation crosses the threshold of an alert,
+// which is at or above the threshold, or at or below it for alerts on a lack of data.
+func IsThresholdCrossed(value float64, threshold float64, belowThreshold bool) bool {
+	if belowThreshold {
+		return value <= threshold
+	}
+	return value >= threshold
+}
+
+// getStateTransition returns the state an alert changes from and to when it is evaluated.
+func getStateTransition(alerting bool) (model.AlertState, model.AlertState) {
+	if alerting {
+		return model.AlertStateNormal, model.AlertStateAlerting
+	}
+	return model.AlertStateAlerting, model.AlertStateNormal
+}
+
+// TransitionAlertState moves the alert with the id in the table of the model to the state of an
+// evaluation, and returns the new state and whether it changed. The change is made in the database
+// rather than against the state of a cached alert, so that only one evaluation notifies of it.
+func TransitionAlertState(ctx context.Context, db *gorm.DB, alertModel interface{}, id int, alerting bool, at time.Time) (model.AlertState, bool, error) {
+	from, to := getStateTransition(alerting)
+	result := db.WithContext(ctx).Model(alertModel).
+		Where("id = ?", id).
+		Where("state = ?", from).
+		Updates(map[string]interface{}{
+			"state":            to,
+			"state_changed_at": at,
+		})
+	if result.Error != nil {
+		return "", false, errors.Wrap(result.Error, "error updating alert state")
+	}
+	return to, result.RowsAffected > 0, nil
+}
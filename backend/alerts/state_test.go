@@ -0,0 +1,30 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsThresholdCrossed(t *testing.T) {
+	assert.True(t, IsThresholdCrossed(10, 10, false))
+	assert.True(t, IsThresholdCrossed(11, 10, false))
+	assert.False(t, IsThresholdCrossed(9, 10, false))
+
+	assert.True(t, IsThresholdCrossed(10, 10, true))
+	assert.False(t, IsThresholdCrossed(11, 10, true))
+	// a threshold of 0 below alerts on the absence of data
+	assert.True(t, IsThresholdCrossed(0, 0, true))
+	assert.False(t, IsThresholdCrossed(1, 0, true))
+}
+
+func TestGetStateTransition(t *testing.T) {
+	from, to := getStateTransition(true)
+	assert.Equal(t, model.AlertStateNormal, from)
+	assert.Equal(t, model.AlertStateAlerting, to)
+
+	from, to = getStateTransition(false)
+	assert.Equal(t, model.AlertStateAlerting, from)
+	assert.Equal(t, model.AlertStateNormal, to)
+}
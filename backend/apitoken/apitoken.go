@@ -0,0 +1,131 @@
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Scope is the data that an API token can read or write.
+type Scope string
+
+const (
+	ScopeLogsRead        Scope = "logs:read"
+	ScopeTracesRead      Scope = "traces:read"
+	ScopeSessionsRead    Scope = "sessions:read"
+	ScopeSessionsWrite   Scope = "sessions:write"
+	ScopeErrorsRead      Scope = "errors:read"
+	ScopeErrorsWrite     Scope = "errors:write"
+	ScopeMetricsRead     Scope = "metrics:read"
+	ScopeAlertsRead      Scope = "alerts:read"
+	ScopeAlertsWrite     Scope = "alerts:write"
+	ScopeProjectsRead    Scope = "projects:read"
+	ScopeSourcemapsRead  Scope = "sourcemaps:read"
+	ScopeSourcemapsWrite Scope = "sourcemaps:write"
+	ScopeReleasesRead    Scope = "releases:read"
+	ScopeReleasesWrite   Scope = "releases:write"
+)
+
+// Scopes are all the scopes, in the order they are shown.
+var Scopes = []Scope{
+	ScopeLogsRead,
+	ScopeTracesRead,
+	ScopeSessionsRead,
+	ScopeSessionsWrite,
+	ScopeErrorsRead,
+	ScopeErrorsWrite,
+	ScopeMetricsRead,
+	ScopeAlertsRead,
+	ScopeAlertsWrite,
+	ScopeProjectsRead,
+	ScopeSourcemapsRead,
+	ScopeSourcemapsWrite,
+	ScopeReleasesRead,
+	ScopeReleasesWrite,
+}
+
+func (s Scope) IsValid() bool {
+	for _, scope := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWrite is whether the scope allows changing data.
+func (s Scope) IsWrite() bool {
+	return strings.HasSuffix(string(s), ":write")
+}
+
+// ParseScopes validates the scopes of a new token, removing duplicates.
+func ParseScopes(scopes []string) ([]Scope, error) {
+	var parsed []Scope
+	for _, s := range scopes {
+		scope := Scope(strings.TrimSpace(s))
+		if !scope.IsValid() {
+			return nil, errors.Errorf("invalid scope %s", s)
+		}
+		if !HasScope(parsed, scope) {
+			parsed = append(parsed, scope)
+		}
+	}
+	if len(parsed) == 0 {
+		return nil, errors.New("tokens need at least one scope")
+	}
+	return parsed, nil
+}
+
+// HasScope is whether the scopes of a token allow the scope. The write scope of data allows reading it.
+func HasScope[T ~string](scopes []T, scope Scope) bool {
+	for _, s := range scopes {
+		if Scope(s) == scope {
+			return true
+		}
+		if resource, ok := strings.CutSuffix(string(s), ":write"); ok && Scope(resource+":read") == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix starts every token, so that tokens can be told apart from other credentials and found by
+// secret scanners.
+const Prefix = "hlt_"
+
+// DisplayLength is the length of the start of a token that is stored to tell tokens apart.
+const DisplayLength = len(Prefix) + 8
+
+// Generate returns a new token and its hash. Only the hash of a token is stored.
+func Generate() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", errors.Wrap(err, "error generating api token")
+	}
+	token = Prefix + hex.EncodeToString(b)
+	return token, Hash(token), nil
+}
+
+// Hash returns the hash of a token that it is looked up by.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// FromRequest returns the API token in the `Authorization: Bearer` header of a request, or "" if
+// the request has no API token.
+func FromRequest(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, Prefix) {
+		return ""
+	}
+	return token
+}
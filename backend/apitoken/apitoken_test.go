@@ -0,0 +1,72 @@
+package apitoken
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	token, hash, err := Generate()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, Prefix))
+	assert.Len(t, token, len(Prefix)+64)
+	assert.Equal(t, Hash(token), hash)
+	assert.NotEqual(t, token, hash)
+
+	other, _, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{"logs:read", " errors:write", "logs:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeLogsRead, ScopeErrorsWrite}, scopes)
+
+	_, err = ParseScopes([]string{"logs:write"})
+	assert.Error(t, err)
+	_, err = ParseScopes(nil)
+	assert.Error(t, err)
+}
+
+func TestHasScope(t *testing.T) {
+	scopes := []string{"errors:write", "logs:read"}
+	assert.True(t, HasScope(scopes, ScopeErrorsWrite))
+	assert.True(t, HasScope(scopes, ScopeErrorsRead))
+	assert.True(t, HasScope(scopes, ScopeLogsRead))
+	assert.False(t, HasScope(scopes, ScopeSessionsRead))
+	assert.False(t, HasScope([]string{"sessions:read"}, ScopeSessionsWrite))
+}
+
+func TestScope_IsWrite(t *testing.T) {
+	assert.True(t, ScopeErrorsWrite.IsWrite())
+	assert.False(t, ScopeErrorsRead.IsWrite())
+}
+
+func TestFromRequest(t *testing.T) {
+	for header, expected := range map[string]string{
+		"Bearer hlt_abc":  "hlt_abc",
+		"bearer  hlt_abc": "hlt_abc",
+		"Bearer abc":      "",
+		"Basic hlt_abc":   "",
+		"":                "",
+	} {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", header)
+		assert.Equal(t, expected, FromRequest(req), header)
+	}
+}
+
+func TestFieldScope(t *testing.T) {
+	scope, ok := FieldScope("updateErrorGroupState")
+	assert.True(t, ok)
+	assert.Equal(t, ScopeErrorsWrite, scope)
+
+	_, ok = FieldScope("changeAdminRole")
+	assert.False(t, ok)
+}
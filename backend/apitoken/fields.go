@@ -0,0 +1,95 @@
+package apitoken
+
+// fieldScopes are the scopes of the private graph queries and mutations that API tokens can use.
+// Tokens cannot use the other fields, such as the ones that manage workspaces, admins, billing or
+// integrations.
+var fieldScopes = map[string]Scope{
+	"logs":                          ScopeLogsRead,
+	"logs_total_count":              ScopeLogsRead,
+	"logs_histogram":                ScopeLogsRead,
+	"logs_metrics":                  ScopeLogsRead,
+	"logs_keys":                     ScopeLogsRead,
+	"logs_key_values":               ScopeLogsRead,
+	"logs_error_objects":            ScopeLogsRead,
+	"sessionLogs":                   ScopeLogsRead,
+	"trace":                         ScopeTracesRead,
+	"traces":                        ScopeTracesRead,
+	"traces_metrics":                ScopeTracesRead,
+	"traces_keys":                   ScopeTracesRead,
+	"traces_key_values":             ScopeTracesRead,
+	"services":                      ScopeTracesRead,
+	"serviceByName":                 ScopeTracesRead,
+	"session":                       ScopeSessionsRead,
+	"events":                        ScopeSessionsRead,
+	"session_intervals":             ScopeSessionsRead,
+	"rage_clicks":                   ScopeSessionsRead,
+	"rageClicksForProject":          ScopeSessionsRead,
+	"enhanced_user_details":         ScopeSessionsRead,
+	"sessions_clickhouse":           ScopeSessionsRead,
+	"sessions_histogram_clickhouse": ScopeSessionsRead,
+	"sessions_report":               ScopeSessionsRead,
+	"sessions_keys":                 ScopeSessionsRead,
+	"sessions_metrics":              ScopeSessionsRead,
+	"session_comments":              ScopeSessionsRead,
+	"session_insight":               ScopeSessionsRead,
+	"dailySessionsCount":            ScopeSessionsRead,
+	"createSessionComment":          ScopeSessionsWrite,
+	"replyToSessionComment":         ScopeSessionsWrite,
+	"deleteSessionComment":          ScopeSessionsWrite,
+	"updateSessionIsPublic":         ScopeSessionsWrite,
+	"error_groups_clickhouse":       ScopeErrorsRead,
+	"errors_histogram_clickhouse":   ScopeErrorsRead,
+	"error_group":                   ScopeErrorsRead,
+	"error_object":                  ScopeErrorsRead,
+	"error_objects":                 ScopeErrorsRead,
+	"error_instance":                ScopeErrorsRead,
+	"error_comments":                ScopeErrorsRead,
+	"errorGroupFrequencies":         ScopeErrorsRead,
+	"errorGroupTags":                ScopeErrorsRead,
+	"errors_keys":                   ScopeErrorsRead,
+	"errors_metrics":                ScopeErrorsRead,
+	"dailyErrorsCount":              ScopeErrorsRead,
+	"dailyErrorFrequency":           ScopeErrorsRead,
+	"updateErrorGroupState":         ScopeErrorsWrite,
+	"updateErrorGroupIsPublic":      ScopeErrorsWrite,
+	"createErrorComment":            ScopeErrorsWrite,
+	"replyToErrorComment":           ScopeErrorsWrite,
+	"deleteErrorComment":            ScopeErrorsWrite,
+	"metric_tags":                   ScopeMetricsRead,
+	"metric_tag_values":             ScopeMetricsRead,
+	"metrics_timeline":              ScopeMetricsRead,
+	"network_histogram":             ScopeMetricsRead,
+	"suggested_metrics":             ScopeMetricsRead,
+	"dashboard_definitions":         ScopeMetricsRead,
+	"error_alerts":                  ScopeAlertsRead,
+	"new_session_alerts":            ScopeAlertsRead,
+	"log_alerts":                    ScopeAlertsRead,
+	"log_alert":                     ScopeAlertsRead,
+	"metric_monitors":               ScopeAlertsRead,
+	"createErrorAlert":              ScopeAlertsWrite,
+	"updateErrorAlert":              ScopeAlertsWrite,
+	"deleteErrorAlert":              ScopeAlertsWrite,
+	"updateErrorAlertIsDisabled":    ScopeAlertsWrite,
+	"createSessionAlert":            ScopeAlertsWrite,
+	"updateSessionAlert":            ScopeAlertsWrite,
+	"deleteSessionAlert":            ScopeAlertsWrite,
+	"updateSessionAlertIsDisabled":  ScopeAlertsWrite,
+	"createLogAlert":                ScopeAlertsWrite,
+	"updateLogAlert":                ScopeAlertsWrite,
+	"deleteLogAlert":                ScopeAlertsWrite,
+	"updateLogAlertIsDisabled":      ScopeAlertsWrite,
+	"createMetricMonitor":           ScopeAlertsWrite,
+	"updateMetricMonitor":           ScopeAlertsWrite,
+	"deleteMetricMonitor":           ScopeAlertsWrite,
+	"updateMetricMonitorIsDisabled": ScopeAlertsWrite,
+	"project":                       ScopeProjectsRead,
+	"projects":                      ScopeProjectsRead,
+	"workspace":                     ScopeProjectsRead,
+}
+
+// FieldScope returns the scope that a private graph query or mutation needs, or false if API
+// tokens cannot use it.
+func FieldScope(field string) (Scope, bool) {
+	scope, ok := fieldScopes[field]
+	return scope, ok
+}
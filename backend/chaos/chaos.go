@@ -0,0 +1,237 @@
+// Package chaos injects latency and errors into clickhouse writes, kafka submits and outbound
+// integration calls so that retry, dead-letter and backpressure paths can be exercised on demand.
+// Injection is only possible on instances started with CHAOS_ENABLED=true, and faults are
+// configured by highlight admins through the private graph.
+package chaos
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/redis"
+	e "github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+const EnabledEnvVar = "CHAOS_ENABLED"
+
+// faults are shared by all instances through redis and reloaded every refreshInterval
+const faultsKey = "chaos-faults"
+const refreshInterval = 5 * time.Second
+
+const (
+	MaxLatency  = time.Minute
+	MaxDuration = 24 * time.Hour
+)
+
+type Subsystem string
+
+const (
+	SubsystemClickHouse   Subsystem = "clickhouse"
+	SubsystemKafka        Subsystem = "kafka"
+	SubsystemIntegrations Subsystem = "integrations"
+)
+
+var Subsystems = []Subsystem{SubsystemClickHouse, SubsystemKafka, SubsystemIntegrations}
+
+var ErrInjected = e.New("chaos: injected failure")
+
+// Fault fails ErrorPercent and delays LatencyPercent of the calls to a subsystem until ExpiresAt.
+// Integration faults apply to outbound requests made through http.DefaultTransport,
+// or only to those to Hosts (and their subdomains) when set.
+type Fault struct {
+	Subsystem      Subsystem `json:"subsystem"`
+	ErrorPercent   float64   `json:"error_percent"`
+	LatencyPercent float64   `json:"latency_percent"`
+	LatencyMs      int64     `json:"latency_ms"`
+	Hosts          []string  `json:"hosts,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+func (f *Fault) Validate() error {
+	if !lo.Contains(Subsystems, f.Subsystem) {
+		return e.Errorf("invalid subsystem %s", f.Subsystem)
+	}
+	if f.ErrorPercent < 0 || f.ErrorPercent > 100 || f.LatencyPercent < 0 || f.LatencyPercent > 100 {
+		return e.New("percentages must be between 0 and 100")
+	}
+	if f.LatencyMs < 0 || time.Duration(f.LatencyMs)*time.Millisecond > MaxLatency {
+		return e.Errorf("latency must be between 0 and %s", MaxLatency)
+	}
+	if len(f.Hosts) > 0 && f.Subsystem != SubsystemIntegrations {
+		return e.New("hosts can only be set for integration faults")
+	}
+	if f.ExpiresAt.IsZero() || f.ExpiresAt.Before(time.Now()) || time.Until(f.ExpiresAt) > MaxDuration {
+		return e.Errorf("expires_at must be within the next %s", MaxDuration)
+	}
+	return nil
+}
+
+func (f *Fault) matches(subsystem Subsystem, host string, now time.Time) bool {
+	if f.Subsystem != subsystem || !now.Before(f.ExpiresAt) {
+		return false
+	}
+	if len(f.Hosts) == 0 || host == "" {
+		return true
+	}
+	return lo.ContainsBy(f.Hosts, func(h string) bool {
+		return host == h || strings.HasSuffix(host, "."+h)
+	})
+}
+
+type Injector struct {
+	redis  *redis.Client
+	mu     sync.RWMutex
+	faults []*Fault
+	random func() float64
+	sleep  func(ctx context.Context, d time.Duration)
+}
+
+var injector atomic.Pointer[Injector]
+
+func Enabled() bool {
+	return os.Getenv(EnabledEnvVar) == "true"
+}
+
+// Start loads the configured faults and makes Inject and Transport apply them.
+// It returns nil without injecting anything unless chaos is enabled for the instance.
+func Start(ctx context.Context, redisClient *redis.Client) *Injector {
+	if !Enabled() {
+		return nil
+	}
+	log.WithContext(ctx).Warn("chaos fault injection is enabled")
+
+	i := New(redisClient)
+	i.refresh(ctx)
+	injector.Store(i)
+	http.DefaultTransport = Transport(http.DefaultTransport)
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				i.refresh(ctx)
+			}
+		}
+	}()
+	return i
+}
+
+// New returns an injector that reads and configures the faults shared through redis.
+func New(redisClient *redis.Client) *Injector {
+	return &Injector{
+		redis:  redisClient,
+		random: rand.Float64,
+		sleep: func(ctx context.Context, d time.Duration) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(d):
+			}
+		},
+	}
+}
+
+func (i *Injector) refresh(ctx context.Context) {
+	faults, err := i.GetFaults(ctx)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to load chaos faults")
+		return
+	}
+	i.mu.Lock()
+	i.faults = faults
+	i.mu.Unlock()
+}
+
+func (i *Injector) GetFaults(ctx context.Context) ([]*Fault, error) {
+	faults := []*Fault{}
+	data, err := i.redis.Client.Get(ctx, faultsKey).Result()
+	if err == goredis.Nil {
+		return faults, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(data), &faults); err != nil {
+		return nil, e.Wrap(err, "failed to parse chaos faults")
+	}
+	now := time.Now()
+	return lo.Filter(faults, func(f *Fault, _ int) bool {
+		return now.Before(f.ExpiresAt)
+	}), nil
+}
+
+// SetFaults replaces the configured faults of every instance.
+func (i *Injector) SetFaults(ctx context.Context, faults []*Fault) error {
+	if len(faults) == 0 {
+		return i.ClearFaults(ctx)
+	}
+	var expiresAt time.Time
+	for _, f := range faults {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+		if f.ExpiresAt.After(expiresAt) {
+			expiresAt = f.ExpiresAt
+		}
+	}
+
+	data, err := json.Marshal(faults)
+	if err != nil {
+		return err
+	}
+	if err := i.redis.Client.Set(ctx, faultsKey, data, time.Until(expiresAt)).Err(); err != nil {
+		return err
+	}
+	i.refresh(ctx)
+	return nil
+}
+
+func (i *Injector) ClearFaults(ctx context.Context) error {
+	if err := i.redis.Client.Del(ctx, faultsKey).Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	i.faults = nil
+	i.mu.Unlock()
+	return nil
+}
+
+func (i *Injector) inject(ctx context.Context, subsystem Subsystem, host string) error {
+	i.mu.RLock()
+	fault, ok := lo.Find(i.faults, func(f *Fault) bool {
+		return f.matches(subsystem, host, time.Now())
+	})
+	i.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if fault.LatencyMs > 0 && i.random()*100 < fault.LatencyPercent {
+		i.sleep(ctx, time.Duration(fault.LatencyMs)*time.Millisecond)
+	}
+	if i.random()*100 < fault.ErrorPercent {
+		return e.Wrapf(ErrInjected, "%s", subsystem)
+	}
+	return nil
+}
+
+// Inject delays or fails a call to the subsystem according to the configured faults.
+func Inject(ctx context.Context, subsystem Subsystem) error {
+	i := injector.Load()
+	if i == nil {
+		return nil
+	}
+	return i.inject(ctx, subsystem, "")
+}
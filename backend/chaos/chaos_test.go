@@ -0,0 +1,58 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultValidate(t *testing.T) {
+	valid := Fault{Subsystem: SubsystemKafka, ErrorPercent: 50, ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, valid.Validate())
+
+	for _, f := range []Fault{
+		{Subsystem: "postgres", ExpiresAt: valid.ExpiresAt},
+		{Subsystem: SubsystemKafka, ErrorPercent: 101, ExpiresAt: valid.ExpiresAt},
+		{Subsystem: SubsystemKafka, LatencyMs: 2 * time.Minute.Milliseconds(), ExpiresAt: valid.ExpiresAt},
+		{Subsystem: SubsystemKafka, Hosts: []string{"api.linear.app"}, ExpiresAt: valid.ExpiresAt},
+		{Subsystem: SubsystemKafka},
+		{Subsystem: SubsystemKafka, ExpiresAt: time.Now().Add(48 * time.Hour)},
+	} {
+		assert.Error(t, f.Validate(), "%+v", f)
+	}
+}
+
+func TestInject(t *testing.T) {
+	ctx := context.Background()
+	var slept time.Duration
+	random := 0.
+	i := &Injector{
+		random: func() float64 { return random },
+		sleep:  func(_ context.Context, d time.Duration) { slept += d },
+		faults: []*Fault{
+			{Subsystem: SubsystemClickHouse, ErrorPercent: 25, LatencyPercent: 100, LatencyMs: 100, ExpiresAt: time.Now().Add(time.Hour)},
+			{Subsystem: SubsystemIntegrations, ErrorPercent: 100, Hosts: []string{"clickup.com"}, ExpiresAt: time.Now().Add(time.Hour)},
+			{Subsystem: SubsystemKafka, ErrorPercent: 100, ExpiresAt: time.Now().Add(-time.Minute)},
+		},
+	}
+
+	err := i.inject(ctx, SubsystemClickHouse, "")
+	assert.True(t, errors.Is(err, ErrInjected))
+	assert.Equal(t, 100*time.Millisecond, slept)
+
+	random = 0.5
+	assert.NoError(t, i.inject(ctx, SubsystemClickHouse, ""))
+	assert.Equal(t, 200*time.Millisecond, slept)
+
+	assert.Error(t, i.inject(ctx, SubsystemIntegrations, "api.clickup.com"))
+	assert.NoError(t, i.inject(ctx, SubsystemIntegrations, "api.linear.app"))
+
+	// expired faults are not injected
+	assert.NoError(t, i.inject(ctx, SubsystemKafka, ""))
+
+	// nothing is injected before Start
+	assert.NoError(t, Inject(ctx, SubsystemClickHouse))
+}
@@ -0,0 +1,24 @@
+package chaos
+
+import (
+	"net/http"
+)
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Transport applies integration faults to the requests of the base round tripper.
+// Start wraps http.DefaultTransport so that integration clients using it are covered.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if i := injector.Load(); i != nil {
+		if err := i.inject(req.Context(), SubsystemIntegrations, req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,27 @@
+package clickhouse
+
+import (
+	"context"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/highlight-run/highlight/backend/chaos"
+)
+
+// chaosConn injects the configured clickhouse faults into writes.
+type chaosConn struct {
+	driver.Conn
+}
+
+func (c *chaosConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	if err := chaos.Inject(ctx, chaos.SubsystemClickHouse); err != nil {
+		return nil, err
+	}
+	return c.Conn.PrepareBatch(ctx, query, opts...)
+}
+
+func (c *chaosConn) Exec(ctx context.Context, query string, args ...any) error {
+	if err := chaos.Inject(ctx, chaos.SubsystemClickHouse); err != nil {
+		return err
+	}
+	return c.Conn.Exec(ctx, query, args...)
+}
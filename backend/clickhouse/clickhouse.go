@@ -14,6 +14,7 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	clickhouseMigrate "github.com/golang-migrate/migrate/v4/database/clickhouse"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/highlight-run/highlight/backend/chaos"
 	"github.com/highlight-run/highlight/backend/projectpath"
 	hmetric "github.com/highlight/highlight/sdk/highlight-go/metric"
 	e "github.com/pkg/errors"
@@ -42,6 +43,9 @@ func NewClient(dbName string) (*Client, error) {
 	opts.MaxOpenConns = 100
 
 	conn, err := clickhouse.Open(opts)
+	if err == nil && chaos.Enabled() {
+		conn = &chaosConn{Conn: conn}
+	}
 
 	go func() {
 		for {
@@ -28,6 +28,7 @@ type ClickhouseErrorGroup struct {
 	ErrorTagID          int64
 	ErrorTagTitle       string
 	ErrorTagDescription string
+	AssigneeID          *int64
 }
 
 type ClickhouseErrorObject struct {
@@ -43,6 +44,7 @@ type ClickhouseErrorObject struct {
 	ServiceVersion string
 	ClientID       string
 	VisitedURL     string
+	SessionID      int64
 }
 
 const ErrorGroupsTable = "error_groups"
@@ -66,6 +68,9 @@ func (client *Client) WriteErrorGroups(ctx context.Context, groups []*model.Erro
 			Status:    string(group.State),
 			Type:      group.Type,
 		}
+		if group.AssigneeID != nil {
+			chEg.AssigneeID = pointy.Int64(int64(*group.AssigneeID))
+		}
 		if group.ErrorTag != nil {
 			chEg.ErrorTagID = int64(group.ErrorTag.ID)
 			chEg.ErrorTagTitle = group.ErrorTag.Title
@@ -85,7 +90,7 @@ func (client *Client) WriteErrorGroups(ctx context.Context, groups []*model.Erro
 			NewStruct(new(ClickhouseErrorGroup)).
 			InsertInto(ErrorGroupsTable, chGroups...).
 			BuildWithFlavor(sqlbuilder.ClickHouse)
-		sql, args = replaceTimestampInserts(sql, args, 10, map[int]bool{1: true, 2: true}, MicroSeconds)
+		sql, args = replaceTimestampInserts(sql, args, 11, map[int]bool{1: true, 2: true}, MicroSeconds)
 		return client.conn.Exec(chCtx, sql, args...)
 	}
 
@@ -106,7 +111,9 @@ func (client *Client) WriteErrorObjects(ctx context.Context, objects []*model.Er
 
 		hasSession := false
 		clientId := ""
+		var sessionId int64
 		if object.SessionID != nil {
+			sessionId = int64(*object.SessionID)
 			relatedSession := sessionsById[*object.SessionID]
 			if relatedSession != nil {
 				clientId = relatedSession.ClientID
@@ -127,6 +134,7 @@ func (client *Client) WriteErrorObjects(ctx context.Context, objects []*model.Er
 			ServiceVersion: object.ServiceVersion,
 			ClientID:       clientId,
 			VisitedURL:     object.URL,
+			SessionID:      sessionId,
 		}
 
 		chObjects = append(chObjects, &chEg)
@@ -142,7 +150,7 @@ func (client *Client) WriteErrorObjects(ctx context.Context, objects []*model.Er
 			NewStruct(new(ClickhouseErrorObject)).
 			InsertInto(ErrorObjectsTable, chObjects...).
 			BuildWithFlavor(sqlbuilder.ClickHouse)
-		sql, args = replaceTimestampInserts(sql, args, 12, map[int]bool{1: true}, MicroSeconds)
+		sql, args = replaceTimestampInserts(sql, args, 13, map[int]bool{1: true}, MicroSeconds)
 		return client.conn.Exec(chCtx, sql, args...)
 	}
 
@@ -363,6 +371,164 @@ func (client *Client) QueryErrorGroupAggregateFrequency(ctx context.Context, pro
 	return items, err
 }
 
+// ErrorGroupImpact is how many times an error group occurred in a time range, and how many users and
+// sessions its errors affected.
+type ErrorGroupImpact struct {
+	ErrorGroupID     int
+	Occurrences      uint64
+	AffectedUsers    uint64
+	AffectedSessions uint64
+	// ImpactScore ranks error groups by the users and sessions they affect, so that an error thrown
+	// in a loop for a single user does not outrank one that every user sees.
+	ImpactScore float64
+}
+
+// ErrorGroupTrendBucket is the impact of an error group in a bucket of its trend.
+type ErrorGroupTrendBucket struct {
+	ErrorGroupID     int
+	Date             time.Time
+	Occurrences      uint64
+	AffectedUsers    uint64
+	AffectedSessions uint64
+}
+
+// errorGroupImpactColumns aggregate the errors of an error group into its ErrorGroupImpact. Affected
+// users are the distinct clients of the sessions of the errors, and the occurrences only add to the
+// score logarithmically.
+const errorGroupImpactColumns = `count() AS occurrences,
+	uniqIf(ClientID, ClientID != '') AS affectedUsers,
+	uniqIf(SessionID, SessionID != 0) AS affectedSessions,
+	affectedUsers + 0.5 * affectedSessions + log2(1 + occurrences) AS impactScore`
+
+// QueryErrorGroupImpacts returns the impact of the error groups that match the query, highest impact first.
+func (client *Client) QueryErrorGroupImpacts(ctx context.Context, projectId int, count int, query modelInputs.ClickhouseQuery, page *int, retentionDate time.Time) ([]*ErrorGroupImpact, int64, error) {
+	pageInt := 1
+	if page != nil {
+		pageInt = *page
+	}
+	offset := (pageInt - 1) * count
+
+	sql, args, err := getErrorQueryImpl(ErrorObjectsTable, fmt.Sprintf("ErrorGroupID, %s, count() OVER() AS total", errorGroupImpactColumns), query, projectId, retentionDate, pointy.String("ErrorGroupID"), pointy.String("impactScore DESC, ErrorGroupID DESC"), pointy.Int(count), pointy.Int(offset))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	impacts := []*ErrorGroupImpact{}
+	var total uint64
+	for rows.Next() {
+		var errorGroupId int64
+		var impact ErrorGroupImpact
+		if err := rows.Scan(&errorGroupId, &impact.Occurrences, &impact.AffectedUsers, &impact.AffectedSessions, &impact.ImpactScore, &total); err != nil {
+			return nil, 0, err
+		}
+		impact.ErrorGroupID = int(errorGroupId)
+		impacts = append(impacts, &impact)
+	}
+
+	return impacts, int64(total), rows.Err()
+}
+
+// QueryErrorGroupImpact returns the impact of the error groups in the time range, highest impact first.
+// Error groups without errors in the time range are omitted.
+func (client *Client) QueryErrorGroupImpact(ctx context.Context, projectId int, errorGroupIds []int, dateRange modelInputs.DateRangeRequiredInput) ([]*ErrorGroupImpact, error) {
+	if len(errorGroupIds) == 0 {
+		return []*ErrorGroupImpact{}, nil
+	}
+
+	sb := sqlbuilder.NewSelectBuilder()
+	sql, args := sb.Select("ErrorGroupID", errorGroupImpactColumns).
+		From("error_objects FINAL").
+		Where(sb.Equal("ProjectID", projectId)).
+		Where(sb.In("ErrorGroupID", errorGroupIds)).
+		Where(sb.Between("Timestamp", dateRange.StartDate, dateRange.EndDate)).
+		GroupBy("ErrorGroupID").
+		OrderBy("impactScore DESC, ErrorGroupID DESC").
+		BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	impacts := []*ErrorGroupImpact{}
+	for rows.Next() {
+		var errorGroupId int64
+		var impact ErrorGroupImpact
+		if err := rows.Scan(&errorGroupId, &impact.Occurrences, &impact.AffectedUsers, &impact.AffectedSessions, &impact.ImpactScore); err != nil {
+			return nil, err
+		}
+		impact.ErrorGroupID = int(errorGroupId)
+		impacts = append(impacts, &impact)
+	}
+
+	return impacts, rows.Err()
+}
+
+// QueryErrorGroupTrends returns the occurrences, affected users and affected sessions of the error
+// groups in buckets of the resolution, including the empty buckets of the date range.
+func (client *Client) QueryErrorGroupTrends(ctx context.Context, projectId int, errorGroupIds []int, params modelInputs.ErrorGroupFrequenciesParamsInput) ([]*ErrorGroupTrendBucket, error) {
+	if params.DateRange == nil {
+		return nil, errors.New("params.DateRange must not be nil")
+	}
+
+	sb := sqlbuilder.NewSelectBuilder()
+
+	mins := params.ResolutionMinutes
+
+	builders := []sqlbuilder.Builder{}
+	sbInner := sqlbuilder.NewSelectBuilder()
+	sbInner.Select(fmt.Sprintf(`ErrorGroupID, intDiv(toRelativeMinuteNum(Timestamp), %s) AS index,
+		count() AS occurrences,
+		uniqIf(ClientID, ClientID != '') AS affectedUsers,
+		uniqIf(SessionID, SessionID != 0) AS affectedSessions`, sbInner.Var(mins))).
+		From("error_objects FINAL").
+		Where(sbInner.Equal("ProjectID", projectId)).
+		Where(sbInner.In("ErrorGroupID", errorGroupIds)).
+		Where(sbInner.Between("Timestamp", params.DateRange.StartDate, params.DateRange.EndDate)).
+		GroupBy("1, 2")
+	builders = append(builders, sbInner)
+
+	for _, id := range errorGroupIds {
+		defaultInner := sqlbuilder.Buildf(`
+			SELECT %s as ErrorGroupID, intDiv(toRelativeMinuteNum(%s), %s), 0, 0, 0
+			ORDER BY
+				1 WITH FILL,
+				2 WITH FILL FROM intDiv(toRelativeMinuteNum(%s), %s) TO intDiv(toRelativeMinuteNum(%s), %s)`,
+			id, params.DateRange.StartDate, mins, params.DateRange.StartDate, mins, params.DateRange.EndDate, mins)
+		builders = append(builders, defaultInner)
+	}
+
+	// each bucket has at most one row of errors, so the distinct counts can be summed with the empty buckets
+	sql, args := sb.Select(fmt.Sprintf("ErrorGroupID, addMinutes(makeDate(0, 0), index * %s), sum(occurrences), sum(affectedUsers), sum(affectedSessions)", sb.Var(mins))).
+		From(sb.BuilderAs(sqlbuilder.UnionAll(builders...), "inner")).
+		GroupBy("1, 2").
+		OrderBy("1, 2").
+		BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []*ErrorGroupTrendBucket{}
+	for rows.Next() {
+		var errorGroupId int64
+		var bucket ErrorGroupTrendBucket
+		if err := rows.Scan(&errorGroupId, &bucket.Date, &bucket.Occurrences, &bucket.AffectedUsers, &bucket.AffectedSessions); err != nil {
+			return nil, err
+		}
+		bucket.ErrorGroupID = int(errorGroupId)
+		buckets = append(buckets, &bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
 func (client *Client) QueryErrorGroupOccurrences(ctx context.Context, projectId int, errorGroupId int) (*time.Time, *time.Time, error) {
 	sb := sqlbuilder.NewSelectBuilder()
 	sql, args := sb.Select(`
@@ -389,6 +555,23 @@ func (client *Client) QueryErrorGroupOccurrences(ctx context.Context, projectId
 	return &firstOccurrence, &lastOccurrence, nil
 }
 
+func (client *Client) QueryErrorObjectCount(ctx context.Context, projectId int, startDate time.Time, endDate time.Time) (uint64, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sql, args := sb.Select("count()").
+		From("error_objects FINAL").
+		Where(sb.Equal("ProjectID", projectId)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessEqualThan("Timestamp", endDate)).
+		BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	var count uint64
+	if err := client.conn.QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (client *Client) QueryErrorGroupTags(ctx context.Context, projectId int, errorGroupId int) ([]*modelInputs.ErrorGroupTagAggregation, error) {
 	tags := map[string]string{
 		"browser":     "Browser",
@@ -1,11 +1,17 @@
 package clickhouse
 
 import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	modelInputs "github.com/highlight-run/highlight/backend/public-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/queryparser"
+	"github.com/openlyinc/pointy"
 	"github.com/stretchr/testify/assert"
-	"testing"
-	"time"
 )
 
 func Test_ErrorMatchesQuery(t *testing.T) {
@@ -29,3 +35,63 @@ func Test_ErrorMatchesQuery(t *testing.T) {
 	matches = ErrorMatchesQuery(&errorObject, &filters)
 	assert.True(t, matches)
 }
+
+func TestQueryErrorGroupImpact(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now().UTC()
+	var objects []*model.ErrorObject
+	var sessions []*model.Session
+	// error group 1 is thrown in a loop in a single session
+	sessions = append(sessions, &model.Session{Model: model.Model{ID: 1}, ClientID: "client-1"})
+	for i := 0; i < 10; i++ {
+		objects = append(objects, &model.ErrorObject{ID: len(objects) + 1, ProjectID: 1, ErrorGroupID: 1, SessionID: pointy.Int(1), Timestamp: now})
+	}
+	// error group 2 affects three users
+	for i := 2; i <= 4; i++ {
+		sessions = append(sessions, &model.Session{Model: model.Model{ID: i}, ClientID: fmt.Sprintf("client-%d", i)})
+		objects = append(objects, &model.ErrorObject{ID: len(objects) + 1, ProjectID: 1, ErrorGroupID: 2, SessionID: pointy.Int(i), Timestamp: now})
+	}
+	// error group 3 is a backend error without a session
+	objects = append(objects, &model.ErrorObject{ID: len(objects) + 1, ProjectID: 1, ErrorGroupID: 3, Timestamp: now.Add(-time.Hour)})
+	assert.NoError(t, client.WriteErrorObjects(ctx, objects, sessions))
+
+	dateRange := privateModel.DateRangeRequiredInput{StartDate: now.Add(-2 * time.Hour), EndDate: now.Add(time.Hour)}
+	impacts, total, err := client.QueryErrorGroupImpacts(ctx, 1, 10, privateModel.ClickhouseQuery{IsAnd: true, Rules: [][]string{}, DateRange: &dateRange}, nil, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, impacts, 3)
+	// the error group that affects the most users ranks first, even though it occurred less
+	assert.Equal(t, 2, impacts[0].ErrorGroupID)
+	assert.Equal(t, uint64(3), impacts[0].Occurrences)
+	assert.Equal(t, uint64(3), impacts[0].AffectedUsers)
+	assert.Equal(t, uint64(3), impacts[0].AffectedSessions)
+	assert.Equal(t, 1, impacts[1].ErrorGroupID)
+	assert.Equal(t, uint64(10), impacts[1].Occurrences)
+	assert.Equal(t, uint64(1), impacts[1].AffectedUsers)
+	assert.Equal(t, 3, impacts[2].ErrorGroupID)
+	assert.Equal(t, uint64(0), impacts[2].AffectedUsers)
+	assert.Equal(t, uint64(0), impacts[2].AffectedSessions)
+
+	impacts, err = client.QueryErrorGroupImpact(ctx, 1, []int{1, 3}, dateRange)
+	assert.NoError(t, err)
+	assert.Len(t, impacts, 2)
+	assert.Equal(t, 1, impacts[0].ErrorGroupID)
+
+	buckets, err := client.QueryErrorGroupTrends(ctx, 1, []int{1, 3}, privateModel.ErrorGroupFrequenciesParamsInput{
+		DateRange:         &dateRange,
+		ResolutionMinutes: 60,
+	})
+	assert.NoError(t, err)
+	var occurrences, affectedSessions uint64
+	for _, bucket := range buckets {
+		occurrences += bucket.Occurrences
+		affectedSessions += bucket.AffectedSessions
+	}
+	assert.Equal(t, uint64(11), occurrences)
+	assert.Equal(t, uint64(1), affectedSessions)
+	// the empty buckets of the date range are included
+	assert.Greater(t, len(buckets), 2)
+}
@@ -0,0 +1,34 @@
+package clickhouse
+
+import (
+	"context"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/huandu/go-sqlbuilder"
+)
+
+// ObjectDestination is an object in an S3 compatible bucket, ie. S3 or GCS with an HMAC key, that
+// the results of a query are written to.
+type ObjectDestination struct {
+	URL             string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// ExportParquet writes the rows selected by the select builder to a Parquet object, replacing the
+// object if it exists so that an export can be retried.
+func (client *Client) ExportParquet(ctx context.Context, destination ObjectDestination, sb *sqlbuilder.SelectBuilder) error {
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+	sql = "INSERT INTO FUNCTION s3(?, ?, ?, 'Parquet') " + sql
+	args = append([]interface{}{destination.URL, destination.AccessKeyID, destination.SecretAccessKey}, args...)
+
+	chCtx := clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+		"s3_truncate_on_insert": 1,
+	}))
+
+	span, _ := util.StartSpanFromContext(ctx, "clickhouse", util.ResourceName("ExportParquet"))
+	err := client.conn.Exec(chCtx, sql, args...)
+	span.Finish(err)
+	return err
+}
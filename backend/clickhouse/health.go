@@ -0,0 +1,58 @@
+package clickhouse
+
+import (
+	"context"
+)
+
+type TableHealth struct {
+	Name        string `json:"name"`
+	Rows        uint64 `json:"rows"`
+	BytesOnDisk uint64 `json:"bytes_on_disk"`
+	ActiveParts uint64 `json:"active_parts"`
+}
+
+type HealthReport struct {
+	Version          string         `json:"version"`
+	UptimeSeconds    uint32         `json:"uptime_seconds"`
+	Tables           []*TableHealth `json:"tables"`
+	PendingMutations uint64         `json:"pending_mutations"`
+	FailedMutations  uint64         `json:"failed_mutations"`
+}
+
+// GetHealthReport reports the server version, the size of each table of the database and
+// mutations that have not completed, which are the usual suspects of a degraded instance.
+func (client *Client) GetHealthReport(ctx context.Context) (*HealthReport, error) {
+	var report HealthReport
+	if err := client.conn.QueryRow(ctx, "SELECT version(), uptime()").Scan(&report.Version, &report.UptimeSeconds); err != nil {
+		return nil, err
+	}
+
+	rows, err := client.conn.Query(ctx, `
+		SELECT table, sum(rows), sum(bytes_on_disk), count()
+		FROM system.parts
+		WHERE database = currentDatabase() AND active
+		GROUP BY table
+		ORDER BY sum(bytes_on_disk) DESC`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var table TableHealth
+		if err := rows.Scan(&table.Name, &table.Rows, &table.BytesOnDisk, &table.ActiveParts); err != nil {
+			return nil, err
+		}
+		report.Tables = append(report.Tables, &table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := client.conn.QueryRow(ctx, `
+		SELECT countIf(NOT is_done), countIf(NOT is_done AND latest_fail_reason != '')
+		FROM system.mutations
+		WHERE database = currentDatabase()`).Scan(&report.PendingMutations, &report.FailedMutations); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,86 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/huandu/go-sqlbuilder"
+	e "github.com/pkg/errors"
+)
+
+const HeartbeatCheckInsTable = "heartbeat_check_ins"
+
+type HeartbeatCheckInRow struct {
+	ProjectId  uint32
+	MonitorId  uint32
+	Timestamp  time.Time
+	UUID       string
+	Status     string
+	DurationMs uint32
+}
+
+func NewHeartbeatCheckInRow(timestamp time.Time, projectID int, monitorID int, status string) *HeartbeatCheckInRow {
+	return &HeartbeatCheckInRow{
+		Timestamp: timestamp,
+		UUID:      uuid.New().String(),
+		ProjectId: uint32(projectID),
+		MonitorId: uint32(monitorID),
+		Status:    status,
+	}
+}
+
+func (client *Client) BatchWriteHeartbeatCheckIns(ctx context.Context, rows []*HeartbeatCheckInRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	batch, err := client.conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s", HeartbeatCheckInsTable))
+	if err != nil {
+		return e.Wrap(err, "failed to create heartbeat check ins batch")
+	}
+
+	for _, row := range rows {
+		if err := batch.AppendStruct(row); err != nil {
+			return err
+		}
+	}
+
+	return batch.Send()
+}
+
+func (client *Client) ReadHeartbeatCheckIns(ctx context.Context, projectID int, monitorID int, startDate time.Time, endDate time.Time) ([]*HeartbeatCheckInRow, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(HeartbeatCheckInsTable).
+		Select("ProjectId, MonitorId, Timestamp, UUID, Status, DurationMs").
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.Equal("MonitorId", monitorID)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessEqualThan("Timestamp", endDate)).
+		OrderBy("Timestamp DESC").
+		Limit(1000)
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "heartbeats", util.ResourceName("ReadHeartbeatCheckIns"))
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	var checkIns []*HeartbeatCheckInRow
+	for rows.Next() {
+		var result HeartbeatCheckInRow
+		if err := rows.ScanStruct(&result); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+		checkIns = append(checkIns, &result)
+	}
+
+	span.Finish(rows.Err())
+	return checkIns, rows.Err()
+}
@@ -0,0 +1,28 @@
+package clickhouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadHeartbeatCheckIns(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now()
+	assert.NoError(t, client.BatchWriteHeartbeatCheckIns(ctx, []*HeartbeatCheckInRow{
+		NewHeartbeatCheckInRow(now.Add(-time.Minute), 1, 1, "ok"),
+		NewHeartbeatCheckInRow(now, 1, 1, "error"),
+		NewHeartbeatCheckInRow(now, 1, 2, "ok"),
+	}))
+
+	checkIns, err := client.ReadHeartbeatCheckIns(ctx, 1, 1, now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, checkIns, 2)
+	assert.Equal(t, "error", checkIns[0].Status)
+	assert.Equal(t, "ok", checkIns[1].Status)
+}
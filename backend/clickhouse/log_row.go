@@ -2,14 +2,13 @@ package clickhouse
 
 import (
 	"context"
-	hlog "github.com/highlight/highlight/sdk/highlight-go/log"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
-	"github.com/sirupsen/logrus"
-	log "github.com/sirupsen/logrus"
+	"github.com/highlight-run/highlight/backend/severity"
+	hlog "github.com/highlight/highlight/sdk/highlight-go/log"
+	"go.opentelemetry.io/collector/pdata/plog"
 )
 
 type LogRow struct {
@@ -28,6 +27,10 @@ type LogRow struct {
 	Body            string
 	LogAttributes   map[string]string
 	Environment     string
+	K8sPodName      string
+	K8sNamespace    string
+	ContainerId     string
+	HostName        string
 }
 
 func NewLogRow(timestamp time.Time, projectID uint32, opts ...LogRowOption) *LogRow {
@@ -43,8 +46,8 @@ func NewLogRow(timestamp time.Time, projectID uint32, opts ...LogRowOption) *Log
 		Timestamp:      timestamp.Truncate(time.Second),
 		ProjectId:      projectID,
 		UUID:           uuid.New().String(),
-		SeverityText:   makeLogLevel("INFO").String(),
-		SeverityNumber: int32(log.InfoLevel),
+		SeverityText:   severity.Default.Text.String(),
+		SeverityNumber: severity.Default.Number,
 	}
 
 	for _, opt := range opts {
@@ -60,6 +63,22 @@ func (l *LogRow) Cursor() string {
 
 type LogRowOption func(*LogRow)
 
+// The otel resource attributes that the LogResource columns are extracted from.
+const (
+	LogResourceK8sPodNameAttribute   = "k8s.pod.name"
+	LogResourceK8sNamespaceAttribute = "k8s.namespace.name"
+	LogResourceContainerIDAttribute  = "container.id"
+	LogResourceHostNameAttribute     = "host.name"
+)
+
+// LogResource describes the kubernetes pod, container and host that emitted a log.
+type LogResource struct {
+	K8sPodName   string
+	K8sNamespace string
+	ContainerID  string
+	HostName     string
+}
+
 func WithTraceID(traceID string) LogRowOption {
 	return func(l *LogRow) {
 		l.TraceId = traceID
@@ -85,10 +104,13 @@ func WithLogAttributes(attributes map[string]string) LogRowOption {
 }
 
 func WithSeverityText(severityText string) LogRowOption {
-	logLevel := makeLogLevel(severityText)
+	return WithSeverity(severity.Normalize(severityText, plog.SeverityNumberUnspecified))
+}
+
+func WithSeverity(s severity.Severity) LogRowOption {
 	return func(l *LogRow) {
-		l.SeverityText = logLevel.String()
-		l.SeverityNumber = getSeverityNumber(logLevel)
+		l.SeverityText = s.Text.String()
+		l.SeverityNumber = s.Number
 	}
 }
 
@@ -129,61 +151,12 @@ func WithEnvironment(environment string) LogRowOption {
 	}
 }
 
-func makeLogLevel(severityText string) modelInputs.LogLevel {
-	switch strings.ToLower(severityText) {
-	case "console.error":
-		return modelInputs.LogLevelError
-	case "window.onerror":
-		return modelInputs.LogLevelError
-	case "trace":
-		return modelInputs.LogLevelTrace
-	case "debug":
-		return modelInputs.LogLevelDebug
-	case "warn":
-		return modelInputs.LogLevelWarn
-	case "error":
-		return modelInputs.LogLevelError
-	case "fatal":
-		return modelInputs.LogLevelFatal
-	default:
-		return modelInputs.LogLevelInfo
-	}
-}
-
-func getSeverityNumber(logLevel modelInputs.LogLevel) int32 {
-	switch logLevel {
-	case modelInputs.LogLevelTrace:
-		return int32(log.TraceLevel)
-	case modelInputs.LogLevelDebug:
-		return int32(log.DebugLevel)
-	case modelInputs.LogLevelInfo:
-		return int32(log.InfoLevel)
-	case modelInputs.LogLevelWarn:
-		return int32(log.WarnLevel)
-	case modelInputs.LogLevelError:
-		return int32(log.ErrorLevel)
-	case modelInputs.LogLevelFatal:
-		return int32(log.FatalLevel)
-	default:
-		return int32(log.InfoLevel)
-	}
-}
-
-func getLogLevel(level logrus.Level) modelInputs.LogLevel {
-	switch level {
-	case log.TraceLevel:
-		return modelInputs.LogLevelTrace
-	case log.DebugLevel:
-		return modelInputs.LogLevelDebug
-	case log.InfoLevel:
-		return modelInputs.LogLevelInfo
-	case log.WarnLevel:
-		return modelInputs.LogLevelWarn
-	case log.ErrorLevel:
-		return modelInputs.LogLevelError
-	case log.FatalLevel:
-		return modelInputs.LogLevelFatal
-	default:
-		return modelInputs.LogLevelInfo
+// WithResource sets the columns of the kubernetes pod, container and host that emitted the log.
+func WithResource(resource LogResource) LogRowOption {
+	return func(l *LogRow) {
+		l.K8sPodName = resource.K8sPodName
+		l.K8sNamespace = resource.K8sNamespace
+		l.ContainerId = resource.ContainerID
+		l.HostName = resource.HostName
 	}
 }
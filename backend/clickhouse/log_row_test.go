@@ -6,6 +6,8 @@ import (
 	"time"
 
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/severity"
+	"go.opentelemetry.io/collector/pdata/plog"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,6 +19,14 @@ func TestNewLogRowWithSeverityText(t *testing.T) {
 	assert.Equal(t, int32(4), NewLogRow(now, 1, WithSeverityText("dir")).SeverityNumber, "it handles figuring out the severity number")
 }
 
+func TestNewLogRowWithSeverity(t *testing.T) {
+	now := time.Now()
+	lr := NewLogRow(now, 1, WithSeverity(severity.Normalize("", plog.SeverityNumberWarn2)))
+	assert.Equal(t, "warn", lr.SeverityText)
+	assert.Equal(t, int32(3), lr.SeverityNumber)
+	assert.Equal(t, "warn", NewLogRow(now, 1, WithSeverityText("warning")).SeverityText)
+}
+
 func TestNewLogRowWithServiceVersion(t *testing.T) {
 	now := time.Now()
 	assert.Equal(t, "abc123", NewLogRow(now, 1, WithServiceVersion("abc123")).ServiceVersion)
@@ -0,0 +1,215 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/logql"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/severity"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/samber/lo"
+)
+
+// LogQLStreamLabels are the labels of the log streams and series returned for LogQL queries.
+var LogQLStreamLabels = []string{
+	string(modelInputs.ReservedLogKeyServiceName),
+	string(modelInputs.ReservedLogKeyEnvironment),
+	string(modelInputs.ReservedLogKeyLevel),
+	string(modelInputs.ReservedLogKeySource),
+}
+
+// LogQLSeries is the number of logs of each step of a LogQL metric query for a set of labels.
+type LogQLSeries struct {
+	Labels map[string]string
+	// Values are the count, or rate per second, of the logs of each step with logs.
+	Values []LogQLValue
+}
+
+type LogQLValue struct {
+	// Step is the start of the step.
+	Step  time.Time
+	Value float64
+}
+
+// logQLLabelExpr returns the expression of a label: the column of a reserved log key or the
+// log attribute of the same name.
+func logQLLabelExpr(sb *sqlbuilder.SelectBuilder, label string) string {
+	if column, ok := logKeysToColumns[modelInputs.ReservedLogKey(label)]; ok {
+		return column
+	}
+	return fmt.Sprintf("LogAttributes[%s]", sb.Var(label))
+}
+
+// logQLWhere adds the conditions of the stream selector and line filters of a query.
+func logQLWhere(sb *sqlbuilder.SelectBuilder, projectID int, query *logql.Query, startDate time.Time, endDate time.Time) {
+	sb.Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessThan("Timestamp", endDate))
+
+	for _, matcher := range query.Matchers {
+		expr := logQLLabelExpr(sb, matcher.Name)
+		value := matcher.Value
+		if matcher.Name == string(modelInputs.ReservedLogKeyLevel) && (matcher.Type == logql.MatchEqual || matcher.Type == logql.MatchNotEqual) {
+			value = severity.NormalizeQueryValue(value)
+		}
+		switch matcher.Type {
+		case logql.MatchEqual:
+			sb.Where(sb.Equal(expr, value))
+		case logql.MatchNotEqual:
+			sb.Where(sb.NotEqual(expr, value))
+		// label regular expressions match the whole value
+		case logql.MatchRegexp:
+			sb.Where(fmt.Sprintf("match(%s, %s)", expr, sb.Var("^(?:"+value+")$")))
+		case logql.MatchNotRegexp:
+			sb.Where(fmt.Sprintf("NOT match(%s, %s)", expr, sb.Var("^(?:"+value+")$")))
+		}
+	}
+
+	for _, filter := range query.LineFilters {
+		switch filter.Type {
+		case logql.FilterContains:
+			sb.Where(fmt.Sprintf("position(Body, %s) > 0", sb.Var(filter.Value)))
+		case logql.FilterNotContains:
+			sb.Where(fmt.Sprintf("position(Body, %s) = 0", sb.Var(filter.Value)))
+		case logql.FilterRegexp:
+			sb.Where(fmt.Sprintf("match(Body, %s)", sb.Var(filter.Value)))
+		case logql.FilterNotRegexp:
+			sb.Where(fmt.Sprintf("NOT match(Body, %s)", sb.Var(filter.Value)))
+		}
+	}
+}
+
+// ReadLogQLLogs returns up to limit logs of a project within [startDate, endDate) that match a LogQL
+// log query, the newest first unless forward is set.
+func (client *Client) ReadLogQLLogs(ctx context.Context, projectID int, query *logql.Query, startDate time.Time, endDate time.Time, limit int, forward bool) ([]*LogRow, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(LogsTable).Select(logRowsColumns)
+	logQLWhere(sb, projectID, query, startDate, endDate)
+	order := OrderBackwardInverted
+	if forward {
+		order = OrderBackwardNatural
+	}
+	sb.OrderBy(order).Limit(limit)
+
+	return client.queryLogRows(ctx, projectID, sb, "ReadLogQLLogs")
+}
+
+// ReadLogQLMetrics returns the series of a LogQL metric query within [startDate, endDate). Logs are
+// counted in buckets of the step rather than over a sliding window of the query's range, which
+// matches Grafana's default of a range equal to the step. Steps without logs are omitted.
+func (client *Client) ReadLogQLMetrics(ctx context.Context, projectID int, query *logql.Query, startDate time.Time, endDate time.Time, step time.Duration) ([]*LogQLSeries, error) {
+	labels := LogQLStreamLabels
+	if query.Sum {
+		labels = query.SumBy
+	}
+
+	sb := sqlbuilder.NewSelectBuilder()
+	selects := []string{fmt.Sprintf("toStartOfInterval(Timestamp, INTERVAL %d second) AS Step", int64(step.Seconds())), "count() AS Count"}
+	groupBy := []string{"Step"}
+	for idx, label := range labels {
+		selects = append(selects, fmt.Sprintf("toString(%s) AS Label%d", logQLLabelExpr(sb, label), idx))
+		groupBy = append(groupBy, fmt.Sprintf("Label%d", idx))
+	}
+	sb.From(LogsTable).Select(selects...)
+	logQLWhere(sb, projectID, query, startDate, endDate)
+	sb.GroupBy(groupBy...).OrderBy("Step")
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "logs", util.ResourceName("ReadLogQLMetrics"))
+	span.SetAttribute("Query", sql)
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	seriesByLabels := map[string]*LogQLSeries{}
+	for rows.Next() {
+		var (
+			bucket      time.Time
+			count       uint64
+			labelValues = make([]string, len(labels))
+		)
+		dest := []any{&bucket, &count}
+		for idx := range labelValues {
+			dest = append(dest, &labelValues[idx])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%q", labelValues)
+		series, ok := seriesByLabels[key]
+		if !ok {
+			series = &LogQLSeries{Labels: map[string]string{}, Values: []LogQLValue{}}
+			for idx, label := range labels {
+				// like loki, series do not have labels with empty values
+				if labelValues[idx] != "" {
+					series.Labels[label] = labelValues[idx]
+				}
+			}
+			seriesByLabels[key] = series
+		}
+		value := float64(count)
+		if query.Aggregation == logql.Rate {
+			value /= step.Seconds()
+		}
+		series.Values = append(series.Values, LogQLValue{Step: bucket, Value: value})
+	}
+	span.Finish(rows.Err())
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keys := lo.Keys(seriesByLabels)
+	sort.Strings(keys)
+	return lo.Map(keys, func(key string, _ int) *LogQLSeries {
+		return seriesByLabels[key]
+	}), nil
+}
+
+// LogQLLabelValues returns the values of a label of a project's logs within [startDate, endDate).
+func (client *Client) LogQLLabelValues(ctx context.Context, projectID int, label string, startDate time.Time, endDate time.Time) ([]string, error) {
+	column, ok := logKeysToColumns[modelInputs.ReservedLogKey(label)]
+	if !ok {
+		return client.LogsKeyValues(ctx, projectID, label, startDate, endDate)
+	}
+
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(LogsTable).
+		Distinct().
+		Select(fmt.Sprintf("toString(%s) AS Value", column)).
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessThan("Timestamp", endDate)).
+		Where("Value != ''").
+		OrderBy("Value").
+		Limit(500)
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "logs", util.ResourceName("LogQLLabelValues"))
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	span.Finish(rows.Err())
+	return values, rows.Err()
+}
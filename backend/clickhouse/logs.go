@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/highlight-run/highlight/backend/queryparser"
+	"github.com/highlight-run/highlight/backend/severity"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	e "github.com/pkg/errors"
@@ -34,6 +36,10 @@ var logKeysToColumns = map[modelInputs.ReservedLogKey]string{
 	modelInputs.ReservedLogKeyServiceName:     "ServiceName",
 	modelInputs.ReservedLogKeyServiceVersion:  "ServiceVersion",
 	modelInputs.ReservedLogKeyEnvironment:     "Environment",
+	modelInputs.ReservedLogKeyK8sPodName:      "K8sPodName",
+	modelInputs.ReservedLogKeyK8sNamespace:    "K8sNamespace",
+	modelInputs.ReservedLogKeyContainerID:     "ContainerId",
+	modelInputs.ReservedLogKeyHostName:        "HostName",
 }
 
 var logsTableConfig = model.TableConfig[modelInputs.ReservedLogKey]{
@@ -55,6 +61,13 @@ var logsTableConfig = model.TableConfig[modelInputs.ReservedLogKey]{
 		"ServiceName",
 		"ServiceVersion",
 		"Environment",
+		"K8sPodName",
+		"K8sNamespace",
+		"ContainerId",
+		"HostName",
+	},
+	ValueNormalizers: map[modelInputs.ReservedLogKey]func(string) string{
+		modelInputs.ReservedLogKeyLevel: severity.NormalizeQueryValue,
 	},
 }
 
@@ -64,6 +77,7 @@ var logsSamplingTableConfig = model.TableConfig[modelInputs.ReservedLogKey]{
 	ReservedKeys:     modelInputs.AllReservedLogKey,
 	BodyColumn:       "Body",
 	AttributesColumn: "LogAttributes",
+	ValueNormalizers: logsTableConfig.ValueNormalizers,
 }
 
 var logsSampleableTableConfig = sampleableTableConfig[modelInputs.ReservedLogKey]{
@@ -133,16 +147,34 @@ func (client *Client) ReadLogs(ctx context.Context, projectID int, params modelI
 			ServiceName     string
 			ServiceVersion  string
 			Environment     string
+			K8sPodName      string
+			K8sNamespace    string
+			ContainerId     string
+			HostName        string
 		}
 		if err := rows.ScanStruct(&result); err != nil {
 			return nil, err
 		}
+		// the resource columns are shown with the attributes that they are extracted from
+		for key, value := range map[string]string{
+			LogResourceK8sPodNameAttribute:   result.K8sPodName,
+			LogResourceK8sNamespaceAttribute: result.K8sNamespace,
+			LogResourceContainerIDAttribute:  result.ContainerId,
+			LogResourceHostNameAttribute:     result.HostName,
+		} {
+			if value != "" {
+				if result.LogAttributes == nil {
+					result.LogAttributes = map[string]string{}
+				}
+				result.LogAttributes[key] = value
+			}
+		}
 
 		return &Edge[modelInputs.Log]{
 			Cursor: encodeCursor(result.Timestamp, result.UUID),
 			Node: &modelInputs.Log{
 				Timestamp:       result.Timestamp,
-				Level:           makeLogLevel(result.SeverityText),
+				Level:           severity.Level(result.SeverityText),
 				Message:         result.Body,
 				LogAttributes:   expandJSON(result.LogAttributes),
 				TraceID:         &result.TraceId,
@@ -175,6 +207,111 @@ func (client *Client) ReadLogs(ctx context.Context, projectID int, params modelI
 	}, nil
 }
 
+// TraceLogsWindow is how long before and after an error the logs of its trace are read.
+const TraceLogsWindow = 24 * time.Hour
+
+// TraceLogsQuery is the logs query matching the logs of a trace.
+func TraceLogsQuery(traceID string) string {
+	return fmt.Sprintf(`%s:"%s"`, modelInputs.ReservedLogKeyTraceID, traceID)
+}
+
+// ReadLogsByTrace returns the logs sharing a trace within the date range, paginated like ReadLogs.
+func (client *Client) ReadLogsByTrace(ctx context.Context, projectID int, traceID string, dateRange modelInputs.DateRangeRequiredInput, pagination Pagination) (*modelInputs.LogConnection, error) {
+	// trace ids are matched exactly, so characters with a meaning in the query syntax are not supported
+	if traceID == "" || strings.ContainsAny(traceID, `"\*`) {
+		return nil, e.Errorf("invalid trace id %q", traceID)
+	}
+	return client.ReadLogs(ctx, projectID, modelInputs.QueryInput{
+		Query:     TraceLogsQuery(traceID),
+		DateRange: &dateRange,
+	}, pagination)
+}
+
+const logRowsColumns = "Timestamp, UUID, TraceId, SpanId, SecureSessionId, SeverityText, Source, ServiceName, ServiceVersion, Body, LogAttributes, Environment, K8sPodName, K8sNamespace, ContainerId, HostName"
+
+// LogRowCursor is the position of a log row in the order of ReadLogRows.
+type LogRowCursor struct {
+	Timestamp time.Time
+	UUID      string
+}
+
+// ReadLogRows returns up to limit log rows of a project within [startDate, endDate), ordered by their
+// timestamp and starting after the row of the cursor when it is set, so that the rows can be exported.
+func (client *Client) ReadLogRows(ctx context.Context, projectID int, startDate time.Time, endDate time.Time, after *LogRowCursor, limit int) ([]*LogRow, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(LogsTable).
+		Select(logRowsColumns).
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessThan("Timestamp", endDate))
+	if after != nil {
+		sb.Where(fmt.Sprintf("(Timestamp, UUID) > (%s, %s)", sb.Var(after.Timestamp), sb.Var(after.UUID)))
+	}
+	sb.OrderBy(OrderBackwardNatural).Limit(limit)
+
+	return client.queryLogRows(ctx, projectID, sb, "ReadLogRows")
+}
+
+// queryLogRows runs a select of the logs table that selects logRowsColumns and scans the log rows.
+func (client *Client) queryLogRows(ctx context.Context, projectID int, sb *sqlbuilder.SelectBuilder, resourceName string) ([]*LogRow, error) {
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "logs", util.ResourceName(resourceName))
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	var logRows []*LogRow
+	for rows.Next() {
+		var result struct {
+			Timestamp       time.Time
+			UUID            string
+			TraceId         string
+			SpanId          string
+			SecureSessionId string
+			SeverityText    string
+			Source          string
+			ServiceName     string
+			ServiceVersion  string
+			Body            string
+			LogAttributes   map[string]string
+			Environment     string
+			K8sPodName      string
+			K8sNamespace    string
+			ContainerId     string
+			HostName        string
+		}
+		if err := rows.ScanStruct(&result); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+		logRows = append(logRows, &LogRow{
+			Timestamp:       result.Timestamp,
+			ProjectId:       uint32(projectID),
+			TraceId:         result.TraceId,
+			SpanId:          result.SpanId,
+			SecureSessionId: result.SecureSessionId,
+			UUID:            result.UUID,
+			SeverityText:    result.SeverityText,
+			Source:          modelInputs.LogSource(result.Source),
+			ServiceName:     result.ServiceName,
+			ServiceVersion:  result.ServiceVersion,
+			Body:            result.Body,
+			LogAttributes:   result.LogAttributes,
+			Environment:     result.Environment,
+			K8sPodName:      result.K8sPodName,
+			K8sNamespace:    result.K8sNamespace,
+			ContainerId:     result.ContainerId,
+			HostName:        result.HostName,
+		})
+	}
+
+	span.Finish(rows.Err())
+	return logRows, rows.Err()
+}
+
 // This is a lighter weight version of the previous function for loading the minimal about of data for a session
 func (client *Client) ReadSessionLogs(ctx context.Context, projectID int, params modelInputs.QueryInput) ([]*modelInputs.LogEdge, error) {
 	selectStr := "Timestamp, UUID, SeverityText, Body"
@@ -228,7 +365,7 @@ func (client *Client) ReadSessionLogs(ctx context.Context, projectID int, params
 			Cursor: encodeCursor(result.Timestamp, result.UUID),
 			Node: &modelInputs.Log{
 				Timestamp: result.Timestamp,
-				Level:     makeLogLevel(result.SeverityText),
+				Level:     severity.Level(result.SeverityText),
 				Message:   result.Body,
 			},
 		})
@@ -285,6 +422,45 @@ func (client *Client) ReadLogsDailyAverage(ctx context.Context, projectIds []int
 	return readDailyImpl[float64](ctx, client, "log_count_daily_mv", "avg", projectIds, dateRange)
 }
 
+// ReadLogsDailySumByProject is the count of log rows ingested by each project in the date range.
+func (client *Client) ReadLogsDailySumByProject(ctx context.Context, projectIds []int, dateRange modelInputs.DateRangeRequiredInput) (map[int]uint64, error) {
+	return readDailySumByProject(ctx, client, "log_count_daily_mv", projectIds, dateRange)
+}
+
+// ReadTracesDailySumByProject is the count of spans ingested by each project in the date range.
+func (client *Client) ReadTracesDailySumByProject(ctx context.Context, projectIds []int, dateRange modelInputs.DateRangeRequiredInput) (map[int]uint64, error) {
+	return readDailySumByProject(ctx, client, "trace_count_daily_mv", projectIds, dateRange)
+}
+
+func readDailySumByProject(ctx context.Context, client *Client, table string, projectIds []int, dateRange modelInputs.DateRangeRequiredInput) (map[int]uint64, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.Select("ProjectId, sum(Count) AS Count").
+		From(table).
+		Where(sb.In("ProjectId", projectIds)).
+		Where(sb.LessThan("toUInt64(Day)", uint64(dateRange.EndDate.Unix()))).
+		Where(sb.GreaterEqualThan("toUInt64(Day)", uint64(dateRange.StartDate.Unix()))).
+		GroupBy("ProjectId")
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[int]uint64{}
+	for rows.Next() {
+		var projectId uint32
+		var count uint64
+		if err := rows.Scan(&projectId, &count); err != nil {
+			return nil, err
+		}
+		counts[int(projectId)] = count
+	}
+	return counts, rows.Err()
+}
+
 func readDailyImpl[N number](ctx context.Context, client *Client, table string, aggFn string, projectIds []int, dateRange modelInputs.DateRangeRequiredInput) (N, error) {
 	sb := sqlbuilder.NewSelectBuilder()
 	sb.Select(fmt.Sprintf("COALESCE(%s(Count), 0) AS Count", aggFn)).
@@ -407,7 +583,7 @@ func (client *Client) ReadLogsHistogram(ctx context.Context, projectID int, para
 		}
 
 		// add count to bucket
-		buckets[bucketId][getLogLevel(level)] = count
+		buckets[bucketId][severity.FromNumber(level)] = count
 	}
 
 	var objectCount uint64
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/logql"
 	"github.com/highlight-run/highlight/backend/queryparser"
 	"github.com/samber/lo"
 
@@ -43,6 +44,24 @@ func setupTest(tb testing.TB) (*Client, func(tb testing.TB)) {
 
 		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", TracesTable))
 		assert.NoError(tb, err)
+
+		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", UptimeChecksTable))
+		assert.NoError(tb, err)
+
+		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", HeartbeatCheckInsTable))
+		assert.NoError(tb, err)
+
+		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", WebVitalsTable))
+		assert.NoError(tb, err)
+
+		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", ServiceGraphEdgesTable))
+		assert.NoError(tb, err)
+
+		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", MetricsTable))
+		assert.NoError(tb, err)
+
+		err = client.conn.Exec(context.Background(), fmt.Sprintf("TRUNCATE TABLE %s", ErrorObjectsTable))
+		assert.NoError(tb, err)
 	}
 }
 
@@ -857,6 +876,128 @@ func TestReadLogsWithTraceIdFilter(t *testing.T) {
 	assert.Len(t, payload.Edges, 2)
 }
 
+func TestReadLogsByTrace(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now()
+	rows := []*LogRow{
+		NewLogRow(now, 1, WithTraceID("abc123"), WithBody(ctx, "second")),
+		NewLogRow(now.Add(-time.Minute), 1, WithTraceID("abc123"), WithBody(ctx, "first")),
+		NewLogRow(now.Add(time.Minute), 1, WithTraceID("abc123"), WithBody(ctx, "third")),
+		NewLogRow(now, 1, WithTraceID("abc1234")),
+		NewLogRow(now, 2, WithTraceID("abc123")),
+	}
+	assert.NoError(t, client.BatchWriteLogRows(ctx, rows))
+
+	payload, err := client.ReadLogsByTrace(ctx, 1, "abc123", *makeDateWithinRange(now), Pagination{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"third", "second", "first"}, lo.Map(payload.Edges, func(edge *modelInputs.LogEdge, _ int) string {
+		return edge.Node.Message
+	}))
+
+	payload, err = client.ReadLogsByTrace(ctx, 1, "abc123", *makeDateWithinRange(now), Pagination{After: &payload.Edges[0].Cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second", "first"}, lo.Map(payload.Edges, func(edge *modelInputs.LogEdge, _ int) string {
+		return edge.Node.Message
+	}))
+
+	_, err = client.ReadLogsByTrace(ctx, 1, `abc" OR "`, *makeDateWithinRange(now), Pagination{})
+	assert.Error(t, err)
+}
+
+func TestReadLogRows(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now().Truncate(time.Second)
+	rows := []*LogRow{
+		NewLogRow(now.Add(-time.Minute), 1, WithBody(ctx, "first"), WithLogAttributes(map[string]string{"foo": "bar"})),
+		NewLogRow(now, 1, WithBody(ctx, "second"), WithServiceName("api")),
+		NewLogRow(now.Add(time.Second), 1, WithBody(ctx, "third")),
+		NewLogRow(now.Add(time.Minute), 1, WithBody(ctx, "outside the range")),
+		NewLogRow(now, 2, WithBody(ctx, "another project")),
+	}
+	assert.NoError(t, client.BatchWriteLogRows(ctx, rows))
+
+	start, end := now.Add(-time.Hour), now.Add(time.Minute)
+	logRows, err := client.ReadLogRows(ctx, 1, start, end, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, lo.Map(logRows, func(row *LogRow, _ int) string {
+		return row.Body
+	}))
+	assert.Equal(t, map[string]string{"foo": "bar"}, logRows[0].LogAttributes)
+	assert.Equal(t, "api", logRows[1].ServiceName)
+	assert.Equal(t, uint32(1), logRows[1].ProjectId)
+
+	logRows, err = client.ReadLogRows(ctx, 1, start, end, &LogRowCursor{Timestamp: logRows[1].Timestamp, UUID: logRows[1].UUID}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"third"}, lo.Map(logRows, func(row *LogRow, _ int) string {
+		return row.Body
+	}))
+}
+
+func TestReadLogQL(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now().Truncate(time.Minute)
+	rows := []*LogRow{
+		NewLogRow(now, 1, WithBody(ctx, "payment failed"), WithServiceName("api"), WithSeverityText("error"), WithLogAttributes(map[string]string{"customer": "42"})),
+		NewLogRow(now.Add(time.Second), 1, WithBody(ctx, "payment succeeded"), WithServiceName("api"), WithSeverityText("info")),
+		NewLogRow(now.Add(time.Minute), 1, WithBody(ctx, "payment failed"), WithServiceName("worker"), WithSeverityText("error")),
+		NewLogRow(now.Add(time.Minute), 2, WithBody(ctx, "another project"), WithServiceName("api")),
+	}
+	assert.NoError(t, client.BatchWriteLogRows(ctx, rows))
+
+	start, end := now.Add(-time.Hour), now.Add(time.Hour)
+	bodies := func(logRows []*LogRow) []string {
+		return lo.Map(logRows, func(row *LogRow, _ int) string {
+			return row.Body
+		})
+	}
+
+	query, err := logql.Parse(`{service_name=~"api|worker"} |= "payment" !~ "succeeded$"`)
+	assert.NoError(t, err)
+	logRows, err := client.ReadLogQLLogs(ctx, 1, query, start, end, 10, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payment failed", "payment failed"}, bodies(logRows))
+	assert.Equal(t, "worker", logRows[0].ServiceName)
+
+	query, err = logql.Parse(`{service_name="api", customer="42"}`)
+	assert.NoError(t, err)
+	logRows, err = client.ReadLogQLLogs(ctx, 1, query, start, end, 10, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payment failed"}, bodies(logRows))
+
+	query, err = logql.Parse(`sum by (level) (count_over_time({service_name!="cron"}[1m]))`)
+	assert.NoError(t, err)
+	series, err := client.ReadLogQLMetrics(ctx, 1, query, start, end, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, series, 2)
+	assert.Equal(t, map[string]string{"level": "error"}, series[0].Labels)
+	assert.Len(t, series[0].Values, 2)
+	assert.Equal(t, now.Unix(), series[0].Values[0].Step.Unix())
+	assert.Equal(t, float64(1), series[0].Values[0].Value)
+	assert.Equal(t, map[string]string{"level": "info"}, series[1].Labels)
+
+	query, err = logql.Parse(`sum(rate({service_name="api"}[1m]))`)
+	assert.NoError(t, err)
+	series, err = client.ReadLogQLMetrics(ctx, 1, query, start, end, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, series, 1)
+	assert.Empty(t, series[0].Labels)
+	assert.Len(t, series[0].Values, 1)
+	assert.Equal(t, float64(2)/60, series[0].Values[0].Value)
+
+	values, err := client.LogQLLabelValues(ctx, 1, "service_name", start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "worker"}, values)
+}
+
 func TestReadLogsWithSourceFilter(t *testing.T) {
 	ctx := context.Background()
 	client, teardown := setupTest(t)
@@ -1029,6 +1170,36 @@ func TestReadLogsWithEnvironmentFilter(t *testing.T) {
 	assert.Len(t, payload.Edges, 2)
 }
 
+func TestReadLogsWithResourceFilter(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now()
+	rows := []*LogRow{
+		NewLogRow(now, 1),
+		NewLogRow(now, 1, WithResource(LogResource{K8sPodName: "api-5d8f7", K8sNamespace: "production", HostName: "node-1"})),
+		NewLogRow(now, 1, WithResource(LogResource{K8sPodName: "worker-9c2b1", K8sNamespace: "production", HostName: "node-2"})),
+	}
+
+	assert.NoError(t, client.BatchWriteLogRows(ctx, rows))
+
+	payload, err := client.ReadLogs(ctx, 1, modelInputs.QueryInput{
+		DateRange: makeDateWithinRange(now),
+		Query:     "k8s_pod_name:api-*",
+	}, Pagination{})
+	assert.NoError(t, err)
+	assert.Len(t, payload.Edges, 1)
+	assert.Equal(t, "node-1", payload.Edges[0].Node.LogAttributes["host"].(map[string]interface{})["name"])
+
+	payload, err = client.ReadLogs(ctx, 1, modelInputs.QueryInput{
+		DateRange: makeDateWithinRange(now),
+		Query:     "k8s_namespace:production host_name:node-2",
+	}, Pagination{})
+	assert.NoError(t, err)
+	assert.Len(t, payload.Edges, 1)
+}
+
 func TestReadLogsWithMultipleFilters(t *testing.T) {
 	ctx := context.Background()
 	client, teardown := setupTest(t)
@@ -0,0 +1,68 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/util"
+	e "github.com/pkg/errors"
+)
+
+const MetricsTable = "metrics"
+
+const (
+	MetricTypeGauge     = "Gauge"
+	MetricTypeSum       = "Sum"
+	MetricTypeHistogram = "Histogram"
+)
+
+// MetricRow is a single OpenTelemetry metric datapoint.
+// Gauges and sums set Value while histograms set Count, Sum, Min, Max and the buckets.
+type MetricRow struct {
+	ProjectId              uint32
+	Timestamp              time.Time
+	StartTimestamp         time.Time
+	SecureSessionId        string
+	ServiceName            string
+	ServiceVersion         string
+	Environment            string
+	MetricName             string
+	MetricDescription      string
+	MetricUnit             string
+	MetricType             string
+	Attributes             map[string]string
+	Value                  float64
+	AggregationTemporality string
+	IsMonotonic            bool
+	Count                  uint64
+	Sum                    float64
+	Min                    float64
+	Max                    float64
+	BucketCounts           []uint64
+	ExplicitBounds         []float64
+}
+
+func (client *Client) BatchWriteMetricRows(ctx context.Context, rows []*MetricRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	span, _ := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName("worker.kafka.batched.flushMetrics.prepareRows"))
+	span.SetAttribute("BatchSize", len(rows))
+	batch, err := client.conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s", MetricsTable))
+	if err != nil {
+		span.Finish(err)
+		return e.Wrap(err, "failed to create metrics batch")
+	}
+
+	for _, row := range rows {
+		if err := batch.AppendStruct(row); err != nil {
+			span.Finish(err)
+			return err
+		}
+	}
+	span.Finish()
+
+	return batch.Send()
+}
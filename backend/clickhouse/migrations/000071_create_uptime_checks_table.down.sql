@@ -0,0 +1 @@
+DROP TABLE IF EXISTS uptime_checks;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS uptime_checks (
+    ProjectId UInt32,
+    MonitorId UInt32,
+    Timestamp DateTime64(6),
+    UUID UUID,
+    Success Bool,
+    StatusCode UInt16,
+    LatencyMs UInt32,
+    FailureReason String
+) ENGINE = MergeTree
+ORDER BY (ProjectId, MonitorId, Timestamp) TTL toDateTime(Timestamp) + toIntervalDay(30);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS heartbeat_check_ins (
+    ProjectId UInt32,
+    MonitorId UInt32,
+    Timestamp DateTime64(6),
+    UUID UUID,
+    Status LowCardinality(String),
+    DurationMs UInt32
+) ENGINE = MergeTree
+ORDER BY (ProjectId, MonitorId, Timestamp) TTL toDateTime(Timestamp) + toIntervalDay(30);
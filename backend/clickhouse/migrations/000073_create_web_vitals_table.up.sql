@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS web_vitals (
+    ProjectId UInt32,
+    Timestamp DateTime64(6),
+    SecureSessionId String,
+    Name LowCardinality(String),
+    Value Float64,
+    URL String,
+    Environment LowCardinality(String),
+    ServiceName LowCardinality(String)
+) ENGINE = MergeTree
+ORDER BY (ProjectId, Name, Timestamp) TTL toDateTime(Timestamp) + toIntervalDay(30);
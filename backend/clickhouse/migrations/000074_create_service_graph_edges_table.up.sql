@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS service_graph_edges (
+    ProjectId UInt32,
+    Timestamp DateTime,
+    Environment LowCardinality(String),
+    Client LowCardinality(String),
+    Server LowCardinality(String),
+    Requests UInt64,
+    Errors UInt64,
+    DurationSum Int64
+) ENGINE = SummingMergeTree((Requests, Errors, DurationSum))
+ORDER BY (ProjectId, Timestamp, Environment, Client, Server) TTL Timestamp + toIntervalDay(30);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS metrics (
+    ProjectId UInt32,
+    Timestamp DateTime64(9),
+    StartTimestamp DateTime64(9),
+    SecureSessionId String,
+    ServiceName LowCardinality(String),
+    ServiceVersion String,
+    Environment LowCardinality(String),
+    MetricName LowCardinality(String),
+    MetricDescription String,
+    MetricUnit LowCardinality(String),
+    MetricType LowCardinality(String),
+    Attributes Map(LowCardinality(String), String) CODEC (ZSTD(1)),
+    Value Float64,
+    AggregationTemporality LowCardinality(String),
+    IsMonotonic Bool,
+    Count UInt64,
+    Sum Float64,
+    Min Float64,
+    Max Float64,
+    BucketCounts Array(UInt64),
+    ExplicitBounds Array(Float64)
+) ENGINE = MergeTree
+ORDER BY (ProjectId, MetricName, Timestamp) TTL toDateTime(Timestamp) + toIntervalDay(30);
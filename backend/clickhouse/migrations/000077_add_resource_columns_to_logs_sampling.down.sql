@@ -0,0 +1,5 @@
+alter table logs_sampling
+    drop column K8sPodName,
+    drop column K8sNamespace,
+    drop column ContainerId,
+    drop column HostName;
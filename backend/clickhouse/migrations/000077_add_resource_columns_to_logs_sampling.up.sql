@@ -0,0 +1,5 @@
+alter table logs_sampling
+    add column K8sPodName String,
+    add column K8sNamespace LowCardinality(String),
+    add column ContainerId String,
+    add column HostName LowCardinality(String);
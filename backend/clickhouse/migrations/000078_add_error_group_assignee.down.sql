@@ -0,0 +1,2 @@
+alter table error_groups
+    drop column AssigneeID;
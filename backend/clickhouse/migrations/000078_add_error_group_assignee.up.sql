@@ -0,0 +1,2 @@
+alter table error_groups
+    add column AssigneeID Nullable(Int64);
@@ -0,0 +1,2 @@
+alter table error_objects
+    drop column SessionID;
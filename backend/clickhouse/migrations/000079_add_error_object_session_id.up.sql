@@ -0,0 +1,2 @@
+alter table error_objects
+    add column SessionID Int64;
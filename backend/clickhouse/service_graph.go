@@ -0,0 +1,162 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/huandu/go-sqlbuilder"
+)
+
+const ServiceGraphEdgesTable = "service_graph_edges"
+
+// client spans start before the server spans they call, so look back further for them
+const serviceGraphClientLookback = 5 * time.Minute
+
+type ServiceGraphParams struct {
+	StartDate   time.Time
+	EndDate     time.Time
+	Environment *string
+}
+
+type ServiceGraphNode struct {
+	Name      string  `json:"name"`
+	Requests  uint64  `json:"requests"`
+	Errors    uint64  `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+type ServiceGraphEdge struct {
+	Client      string  `json:"client"`
+	Server      string  `json:"server"`
+	Requests    uint64  `json:"requests"`
+	Errors      uint64  `json:"errors"`
+	RequestRate float64 `json:"request_rate"`
+	ErrorRate   float64 `json:"error_rate"`
+	AvgDuration float64 `json:"avg_duration"`
+}
+
+type ServiceGraph struct {
+	Nodes []*ServiceGraphNode `json:"nodes"`
+	Edges []*ServiceGraphEdge `json:"edges"`
+}
+
+// AggregateServiceGraph counts the requests between services of every project for server spans
+// starting within [startDate, endDate), pairing each server (or consumer) span with the
+// client (or producer) span of another service that is its parent.
+func (client *Client) AggregateServiceGraph(ctx context.Context, startDate time.Time, endDate time.Time) error {
+	clients := sqlbuilder.NewSelectBuilder()
+	clients.From(TracesTable).
+		Select("ProjectId", "TraceId", "SpanId", "ServiceName").
+		Where(clients.GreaterEqualThan("Timestamp", startDate.Add(-serviceGraphClientLookback))).
+		Where(clients.LessThan("Timestamp", endDate)).
+		Where(clients.In("SpanKind", "Client", "Producer")).
+		Where(clients.NotEqual("ServiceName", ""))
+
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(sb.As(TracesTable, "server")).
+		Select(
+			"server.ProjectId",
+			"toStartOfMinute(server.Timestamp)",
+			"server.Environment",
+			"client.ServiceName",
+			"server.ServiceName",
+			"count()",
+			"countIf(server.StatusCode = 'Error')",
+			"sum(server.Duration)",
+		).
+		JoinWithOption(sqlbuilder.InnerJoin, sb.BuilderAs(clients, "client"),
+			"server.ProjectId = client.ProjectId",
+			"server.TraceId = client.TraceId",
+			"server.ParentSpanId = client.SpanId").
+		Where(sb.GreaterEqualThan("server.Timestamp", startDate)).
+		Where(sb.LessThan("server.Timestamp", endDate)).
+		Where(sb.In("server.SpanKind", "Server", "Consumer")).
+		Where("server.ServiceName != client.ServiceName").
+		Where(sb.NotEqual("server.ServiceName", "")).
+		GroupBy("1", "2", "3", "4", "5")
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+	sql = fmt.Sprintf("INSERT INTO %s %s", ServiceGraphEdgesTable, sql)
+
+	span, _ := util.StartSpanFromContext(ctx, "service-graph", util.ResourceName("AggregateServiceGraph"))
+	err := client.conn.Exec(ctx, sql, args...)
+	span.Finish(err)
+	return err
+}
+
+// ReadServiceGraph returns the services of a project and the requests between them.
+// Services that only act as clients are included as nodes without requests.
+func (client *Client) ReadServiceGraph(ctx context.Context, projectID int, params ServiceGraphParams) (*ServiceGraph, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(ServiceGraphEdgesTable).
+		Select("Client", "Server", "sum(Requests)", "sum(Errors)", "sum(DurationSum)").
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.GreaterEqualThan("Timestamp", params.StartDate)).
+		Where(sb.LessThan("Timestamp", params.EndDate)).
+		GroupBy("Client", "Server").
+		OrderBy("sum(Requests) DESC").
+		Limit(1000)
+	if params.Environment != nil {
+		sb.Where(sb.Equal("Environment", *params.Environment))
+	}
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "service-graph", util.ResourceName("ReadServiceGraph"))
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	minutes := params.EndDate.Sub(params.StartDate).Minutes()
+	graph := &ServiceGraph{Nodes: []*ServiceGraphNode{}, Edges: []*ServiceGraphEdge{}}
+	nodes := map[string]*ServiceGraphNode{}
+	getNode := func(name string) *ServiceGraphNode {
+		if _, ok := nodes[name]; !ok {
+			nodes[name] = &ServiceGraphNode{Name: name}
+			graph.Nodes = append(graph.Nodes, nodes[name])
+		}
+		return nodes[name]
+	}
+
+	for rows.Next() {
+		var edge ServiceGraphEdge
+		var durationSum int64
+		if err := rows.Scan(&edge.Client, &edge.Server, &edge.Requests, &edge.Errors, &durationSum); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+		if edge.Requests > 0 {
+			edge.ErrorRate = float64(edge.Errors) / float64(edge.Requests)
+			edge.AvgDuration = float64(durationSum) / float64(edge.Requests)
+		}
+		if minutes > 0 {
+			edge.RequestRate = float64(edge.Requests) / minutes
+		}
+		graph.Edges = append(graph.Edges, &edge)
+
+		getNode(edge.Client)
+		server := getNode(edge.Server)
+		server.Requests += edge.Requests
+		server.Errors += edge.Errors
+	}
+	if err := rows.Err(); err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+	span.Finish()
+
+	for _, node := range graph.Nodes {
+		if node.Requests > 0 {
+			node.ErrorRate = float64(node.Errors) / float64(node.Requests)
+		}
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Name < graph.Nodes[j].Name
+	})
+	return graph, nil
+}
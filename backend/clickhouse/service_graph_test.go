@@ -0,0 +1,54 @@
+package clickhouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateServiceGraph(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now().Truncate(time.Minute).Add(-time.Minute)
+	var rows []*TraceRow
+	for i, status := range []string{"Ok", "Ok", "Error"} {
+		traceID := []string{"trace-1", "trace-2", "trace-3"}[i]
+		rows = append(rows,
+			NewTraceRow(now, 1).WithTraceId(traceID).WithSpanId("frontend-span").WithSpanKind("Client").
+				WithServiceName("frontend"),
+			NewTraceRow(now.Add(time.Millisecond), 1).WithTraceId(traceID).WithSpanId("backend-span").
+				WithParentSpanId("frontend-span").WithSpanKind("Server").WithServiceName("backend").
+				WithStatusCode(status).WithDuration(now, now.Add(time.Second)),
+			// calls within a service are not part of the graph
+			NewTraceRow(now.Add(2*time.Millisecond), 1).WithTraceId(traceID).WithSpanId("internal-span").
+				WithParentSpanId("backend-span").WithSpanKind("Server").WithServiceName("backend"),
+		)
+	}
+	assert.NoError(t, client.BatchWriteTraceRows(ctx, rows))
+	assert.NoError(t, client.AggregateServiceGraph(ctx, now, now.Add(time.Minute)))
+
+	graph, err := client.ReadServiceGraph(ctx, 1, ServiceGraphParams{
+		StartDate: now.Add(-time.Hour),
+		EndDate:   now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, graph.Nodes, 2)
+	assert.Len(t, graph.Edges, 1)
+
+	edge := graph.Edges[0]
+	assert.Equal(t, "frontend", edge.Client)
+	assert.Equal(t, "backend", edge.Server)
+	assert.Equal(t, uint64(3), edge.Requests)
+	assert.Equal(t, uint64(1), edge.Errors)
+	assert.InDelta(t, 1./3, edge.ErrorRate, 0.001)
+	assert.Equal(t, float64(time.Second), edge.AvgDuration)
+
+	assert.Equal(t, "backend", graph.Nodes[0].Name)
+	assert.Equal(t, uint64(3), graph.Nodes[0].Requests)
+	assert.Equal(t, "frontend", graph.Nodes[1].Name)
+	assert.Equal(t, uint64(0), graph.Nodes[1].Requests)
+}
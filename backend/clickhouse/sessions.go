@@ -57,6 +57,7 @@ var fieldMap map[string]string = map[string]string{
 	"service_name":    "ServiceName",
 	"service_version": "ServiceVersion",
 	"Tag":             "ErrorTagTitle",
+	"assignee_id":     "AssigneeID",
 }
 
 type ClickhouseSession struct {
@@ -339,6 +339,50 @@ func (client *Client) ReadTracesMetrics(ctx context.Context, projectID int, para
 	return readMetrics(ctx, client, tracesSampleableTableConfig, projectID, params, column, metricTypes, groupBy, nBuckets, bucketBy, limit, limitAggregator, limitColumn)
 }
 
+// TracesAggregate is the number of spans matching a query, how many of them errored, and their
+// aggregated latency.
+type TracesAggregate struct {
+	SpanCount  uint64
+	ErrorCount uint64
+	Latency    time.Duration
+}
+
+// ReadTracesAggregate aggregates the spans of the project matching the query within its date range,
+// aggregating their duration by the aggregator.
+func (client *Client) ReadTracesAggregate(ctx context.Context, projectID int, params modelInputs.QueryInput, aggregator modelInputs.MetricAggregator) (*TracesAggregate, error) {
+	latencyFn := getFnStr(aggregator, traceKeysToColumns[modelInputs.ReservedTraceKeyDuration], false)
+	if latencyFn == "" || aggregator == modelInputs.MetricAggregatorCount || aggregator == modelInputs.MetricAggregatorCountDistinctKey {
+		return nil, e.Errorf("cannot aggregate the latency of spans by %s", aggregator)
+	}
+
+	sb, err := makeSelectBuilder(
+		TracesTableConfig,
+		fmt.Sprintf("count(), countIf(StatusCode = 'Error'), ifNotFinite(%s, 0)", latencyFn),
+		nil,
+		nil,
+		projectID,
+		params,
+		Pagination{CountOnly: true},
+		OrderBackwardNatural,
+		OrderForwardNatural,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "traces", util.ResourceName("ReadTracesAggregate"))
+	var aggregate TracesAggregate
+	var latency float64
+	err = client.conn.QueryRow(ctx, sql, args...).Scan(&aggregate.SpanCount, &aggregate.ErrorCount, &latency)
+	span.Finish(err)
+	if err != nil {
+		return nil, err
+	}
+	aggregate.Latency = time.Duration(latency)
+	return &aggregate, nil
+}
+
 func (client *Client) TracesKeys(ctx context.Context, projectID int, startDate time.Time, endDate time.Time, query *string, typeArg *modelInputs.KeyType) ([]*modelInputs.QueryKey, error) {
 	traceKeys, err := KeysAggregated(ctx, client, TraceKeysTable, projectID, startDate, endDate, query, typeArg)
 	if err != nil {
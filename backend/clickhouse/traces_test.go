@@ -80,3 +80,38 @@ func TestReadTracesWithEnvironmentFilter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, payload.Edges, 2)
 }
+
+func TestReadTracesAggregate(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now()
+	rows := []*TraceRow{
+		NewTraceRow(now, 1).WithServiceName("checkout").WithSpanName("POST /orders").WithDuration(now, now.Add(100*time.Millisecond)),
+		NewTraceRow(now, 1).WithServiceName("checkout").WithSpanName("POST /orders").WithDuration(now, now.Add(300*time.Millisecond)).WithStatusCode("Error"),
+		NewTraceRow(now, 1).WithServiceName("checkout").WithSpanName("GET /orders").WithDuration(now, now.Add(time.Second)),
+		NewTraceRow(now, 2).WithServiceName("checkout").WithSpanName("POST /orders").WithDuration(now, now.Add(time.Second)),
+	}
+	assert.NoError(t, client.BatchWriteTraceRows(ctx, rows))
+
+	params := modelInputs.QueryInput{
+		Query: `service_name:checkout span_name:"POST /orders"`,
+		DateRange: &modelInputs.DateRangeRequiredInput{
+			StartDate: now.Add(-time.Minute),
+			EndDate:   now.Add(time.Minute),
+		},
+	}
+	aggregate, err := client.ReadTracesAggregate(ctx, 1, params, modelInputs.MetricAggregatorMax)
+	assert.NoError(t, err)
+	assert.Equal(t, &TracesAggregate{SpanCount: 2, ErrorCount: 1, Latency: 300 * time.Millisecond}, aggregate)
+
+	// a window without spans has no latency
+	params.Query = "service_name:unknown"
+	aggregate, err = client.ReadTracesAggregate(ctx, 1, params, modelInputs.MetricAggregatorP95)
+	assert.NoError(t, err)
+	assert.Equal(t, &TracesAggregate{}, aggregate)
+
+	_, err = client.ReadTracesAggregate(ctx, 1, params, modelInputs.MetricAggregatorCount)
+	assert.Error(t, err)
+}
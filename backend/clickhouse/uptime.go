@@ -0,0 +1,145 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/huandu/go-sqlbuilder"
+	e "github.com/pkg/errors"
+)
+
+const UptimeChecksTable = "uptime_checks"
+
+type UptimeCheckRow struct {
+	ProjectId     uint32
+	MonitorId     uint32
+	Timestamp     time.Time
+	UUID          string
+	Success       bool
+	StatusCode    uint16
+	LatencyMs     uint32
+	FailureReason string
+}
+
+func NewUptimeCheckRow(timestamp time.Time, projectID int, monitorID int) *UptimeCheckRow {
+	return &UptimeCheckRow{
+		Timestamp: timestamp,
+		UUID:      uuid.New().String(),
+		ProjectId: uint32(projectID),
+		MonitorId: uint32(monitorID),
+	}
+}
+
+// UptimeDowntimeWindow is a run of consecutive failed checks for a monitor,
+// along with the number of backend errors seen for the project during the window
+// and during the window of equal length immediately preceding it.
+type UptimeDowntimeWindow struct {
+	StartDate          time.Time
+	EndDate            time.Time
+	FailedChecks       int
+	ErrorCount         uint64
+	BaselineErrorCount uint64
+}
+
+func (client *Client) WriteUptimeChecks(ctx context.Context, rows []*UptimeCheckRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	batch, err := client.conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s", UptimeChecksTable))
+	if err != nil {
+		return e.Wrap(err, "failed to create uptime checks batch")
+	}
+
+	for _, row := range rows {
+		if err := batch.AppendStruct(row); err != nil {
+			return err
+		}
+	}
+
+	return batch.Send()
+}
+
+func (client *Client) ReadUptimeChecks(ctx context.Context, projectID int, monitorID int, startDate time.Time, endDate time.Time) ([]*UptimeCheckRow, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(UptimeChecksTable).
+		Select("ProjectId, MonitorId, Timestamp, UUID, Success, StatusCode, LatencyMs, FailureReason").
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.Equal("MonitorId", monitorID)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessEqualThan("Timestamp", endDate)).
+		OrderBy("Timestamp ASC")
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "uptime", util.ResourceName("ReadUptimeChecks"))
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	var checks []*UptimeCheckRow
+	for rows.Next() {
+		var result UptimeCheckRow
+		if err := rows.ScanStruct(&result); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+		checks = append(checks, &result)
+	}
+
+	span.Finish(rows.Err())
+	return checks, rows.Err()
+}
+
+func (client *Client) ReadUptimeDowntimeWindows(ctx context.Context, projectID int, monitorID int, startDate time.Time, endDate time.Time) ([]*UptimeDowntimeWindow, error) {
+	checks, err := client.ReadUptimeChecks(ctx, projectID, monitorID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := GetDowntimeWindows(checks)
+	for _, window := range windows {
+		window.ErrorCount, err = client.QueryErrorObjectCount(ctx, projectID, window.StartDate, window.EndDate)
+		if err != nil {
+			return nil, err
+		}
+
+		duration := window.EndDate.Sub(window.StartDate)
+		window.BaselineErrorCount, err = client.QueryErrorObjectCount(ctx, projectID, window.StartDate.Add(-duration), window.StartDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return windows, nil
+}
+
+// GetDowntimeWindows groups time-ordered checks into windows of consecutive failures.
+// A window ends at the first successful check following the failures, or at the last
+// failed check if the monitor has not yet recovered.
+func GetDowntimeWindows(checks []*UptimeCheckRow) []*UptimeDowntimeWindow {
+	var windows []*UptimeDowntimeWindow
+	var current *UptimeDowntimeWindow
+	for _, check := range checks {
+		if !check.Success {
+			if current == nil {
+				current = &UptimeDowntimeWindow{StartDate: check.Timestamp}
+			}
+			current.FailedChecks += 1
+			current.EndDate = check.Timestamp
+		} else if current != nil {
+			current.EndDate = check.Timestamp
+			windows = append(windows, current)
+			current = nil
+		}
+	}
+	if current != nil {
+		windows = append(windows, current)
+	}
+	return windows
+}
@@ -0,0 +1,60 @@
+package clickhouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteUptimeChecks(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now()
+	success := NewUptimeCheckRow(now, 1, 1)
+	success.Success = true
+	success.StatusCode = 200
+
+	assert.NoError(t, client.WriteUptimeChecks(ctx, []*UptimeCheckRow{
+		success,
+		NewUptimeCheckRow(now.Add(time.Minute), 1, 1),
+	}))
+
+	checks, err := client.ReadUptimeChecks(ctx, 1, 1, now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, checks, 2)
+	assert.True(t, checks[0].Success)
+	assert.False(t, checks[1].Success)
+}
+
+func TestGetDowntimeWindows(t *testing.T) {
+	now := time.Now()
+	check := func(offset int, success bool) *UptimeCheckRow {
+		row := NewUptimeCheckRow(now.Add(time.Duration(offset)*time.Minute), 1, 1)
+		row.Success = success
+		return row
+	}
+
+	assert.Empty(t, GetDowntimeWindows(nil))
+	assert.Empty(t, GetDowntimeWindows([]*UptimeCheckRow{check(0, true), check(1, true)}))
+
+	windows := GetDowntimeWindows([]*UptimeCheckRow{
+		check(0, true),
+		check(1, false),
+		check(2, false),
+		check(3, true),
+		check(4, false),
+	})
+	assert.Len(t, windows, 2)
+
+	assert.Equal(t, now.Add(time.Minute), windows[0].StartDate)
+	assert.Equal(t, now.Add(3*time.Minute), windows[0].EndDate)
+	assert.Equal(t, 2, windows[0].FailedChecks)
+
+	assert.Equal(t, now.Add(4*time.Minute), windows[1].StartDate)
+	assert.Equal(t, now.Add(4*time.Minute), windows[1].EndDate)
+	assert.Equal(t, 1, windows[1].FailedChecks)
+}
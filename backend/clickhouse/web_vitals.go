@@ -0,0 +1,182 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/huandu/go-sqlbuilder"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+const WebVitalsTable = "web_vitals"
+
+// WebVitalNames are the core web vitals reported by the browser SDK.
+var WebVitalNames = []string{"CLS", "FCP", "FID", "INP", "LCP", "TTFB"}
+
+func IsWebVital(name string) bool {
+	return lo.Contains(WebVitalNames, name)
+}
+
+type WebVitalRow struct {
+	ProjectId       uint32
+	Timestamp       time.Time
+	SecureSessionId string
+	Name            string
+	Value           float64
+	URL             string
+	Environment     string
+	ServiceName     string
+}
+
+type WebVitalPercentiles struct {
+	Name  string  `json:"name"`
+	URL   string  `json:"url,omitempty"`
+	Count uint64  `json:"count"`
+	P50   float64 `json:"p50"`
+	P75   float64 `json:"p75"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+}
+
+type WebVitalsParams struct {
+	StartDate  time.Time
+	EndDate    time.Time
+	Name       *string
+	URL        *string
+	GroupByURL bool
+	Limit      int
+}
+
+func (client *Client) BatchWriteWebVitals(ctx context.Context, rows []*WebVitalRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	batch, err := client.conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s", WebVitalsTable))
+	if err != nil {
+		return e.Wrap(err, "failed to create web vitals batch")
+	}
+
+	for _, row := range rows {
+		if err := batch.AppendStruct(row); err != nil {
+			return err
+		}
+	}
+
+	return batch.Send()
+}
+
+// QueryWebVitalPercentiles returns the distribution of each web vital, optionally per url.
+func (client *Client) QueryWebVitalPercentiles(ctx context.Context, projectID int, params WebVitalsParams) ([]*WebVitalPercentiles, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	cols := []string{"Name", "count()", "quantile(.5)(Value)", "quantile(.75)(Value)", "quantile(.9)(Value)", "quantile(.99)(Value)"}
+	groupBy := []string{"Name"}
+	if params.GroupByURL {
+		cols = append(cols, "URL")
+		groupBy = append(groupBy, "URL")
+	}
+
+	sb.From(WebVitalsTable).
+		Select(cols...).
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.GreaterEqualThan("Timestamp", params.StartDate)).
+		Where(sb.LessEqualThan("Timestamp", params.EndDate)).
+		GroupBy(groupBy...).
+		OrderBy("count() DESC")
+	if params.Name != nil {
+		sb.Where(sb.Equal("Name", *params.Name))
+	}
+	if params.URL != nil {
+		sb.Where(sb.Equal("URL", *params.URL))
+	}
+	if params.Limit > 0 {
+		sb.Limit(params.Limit)
+	}
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	span, _ := util.StartSpanFromContext(ctx, "web-vitals", util.ResourceName("QueryWebVitalPercentiles"))
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	var results []*WebVitalPercentiles
+	for rows.Next() {
+		var result WebVitalPercentiles
+		dest := []interface{}{&result.Name, &result.Count, &result.P50, &result.P75, &result.P90, &result.P99}
+		if params.GroupByURL {
+			dest = append(dest, &result.URL)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			span.Finish(err)
+			return nil, err
+		}
+		results = append(results, &result)
+	}
+
+	span.Finish(rows.Err())
+	return results, rows.Err()
+}
+
+// QueryWebVitalAggregate computes a single aggregate of a web vital, used to evaluate metric monitors.
+func (client *Client) QueryWebVitalAggregate(ctx context.Context, projectID int, name string, aggregator modelInputs.MetricAggregator, url *string, startDate time.Time, endDate time.Time) (float64, error) {
+	fn := getFnStr(aggregator, "Value", false)
+	if fn == "" || aggregator == modelInputs.MetricAggregatorCountDistinctKey {
+		return 0, e.Errorf("unsupported web vital aggregator %s", aggregator)
+	}
+
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.From(WebVitalsTable).
+		Select(fmt.Sprintf("toFloat64(%s)", fn)).
+		Where(sb.Equal("ProjectId", projectID)).
+		Where(sb.Equal("Name", name)).
+		Where(sb.GreaterEqualThan("Timestamp", startDate)).
+		Where(sb.LessEqualThan("Timestamp", endDate))
+	if url != nil {
+		sb.Where(sb.Equal("URL", *url))
+	}
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	var value float64
+	if err := client.conn.QueryRow(ctx, sql, args...).Scan(&value); err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+func (client *Client) QuerySessionWebVitals(ctx context.Context, projectID int, sessionSecureID string) ([]*model.Metric, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sql, args := sb.
+		Select("Name, Value").
+		From(WebVitalsTable).
+		Where(sb.And(
+			sb.Equal("ProjectId", projectID),
+			sb.Equal("SecureSessionId", sessionSecureID))).
+		OrderBy("Timestamp ASC").
+		BuildWithFlavor(sqlbuilder.ClickHouse)
+
+	rows, err := client.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := []*model.Metric{}
+	for rows.Next() {
+		var name string
+		var value float64
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, &model.Metric{Name: name, Value: value, Category: "WebVital"})
+	}
+
+	return metrics, rows.Err()
+}
@@ -0,0 +1,56 @@
+package clickhouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+)
+
+func TestQueryWebVitalPercentiles(t *testing.T) {
+	ctx := context.Background()
+	client, teardown := setupTest(t)
+	defer teardown(t)
+
+	now := time.Now()
+	var rows []*WebVitalRow
+	for i := 1; i <= 100; i++ {
+		url := "https://app.highlight.io/a"
+		if i%2 == 0 {
+			url = "https://app.highlight.io/b"
+		}
+		rows = append(rows, &WebVitalRow{ProjectId: 1, Timestamp: now, SecureSessionId: "abc", Name: "LCP", Value: float64(i), URL: url})
+	}
+	rows = append(rows, &WebVitalRow{ProjectId: 1, Timestamp: now, SecureSessionId: "abc", Name: "CLS", Value: 0.1, URL: "https://app.highlight.io/a"})
+	assert.NoError(t, client.BatchWriteWebVitals(ctx, rows))
+
+	results, err := client.QueryWebVitalPercentiles(ctx, 1, WebVitalsParams{
+		StartDate: now.Add(-time.Hour),
+		EndDate:   now.Add(time.Hour),
+		Name:      pointy.String("LCP"),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, uint64(100), results[0].Count)
+	assert.InDelta(t, 50, results[0].P50, 2)
+
+	results, err = client.QueryWebVitalPercentiles(ctx, 1, WebVitalsParams{
+		StartDate:  now.Add(-time.Hour),
+		EndDate:    now.Add(time.Hour),
+		GroupByURL: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	value, err := client.QueryWebVitalAggregate(ctx, 1, "LCP", modelInputs.MetricAggregatorMax, nil, now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 100., value)
+
+	metrics, err := client.QuerySessionWebVitals(ctx, 1, "abc")
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 101)
+}
@@ -0,0 +1,76 @@
+package clickup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	metadataCacheTTL         = 10 * time.Minute
+	metadataCacheLockTimeout = 5 * time.Second
+)
+
+// MetadataCache keeps the teams, spaces, folders and lists of a workspace's ClickUp integration
+// in redis, as they rarely change but are listed every time a task is created. The cached values
+// expire after metadataCacheTTL or when the cache is invalidated, ie. to pick up a new list.
+type MetadataCache struct {
+	redis       *redis.Client
+	workspaceID int
+}
+
+func NewMetadataCache(redis *redis.Client, workspaceID int) *MetadataCache {
+	return &MetadataCache{redis: redis, workspaceID: workspaceID}
+}
+
+func (c *MetadataCache) versionKey() string {
+	return fmt.Sprintf("clickup-metadata-version-%d", c.workspaceID)
+}
+
+// version is part of the keys of the cached values, so that the values cached before the cache
+// was invalidated are no longer read.
+func (c *MetadataCache) version(ctx context.Context) (int64, error) {
+	version, err := c.redis.Client.Get(ctx, c.versionKey()).Int64()
+	if err == goredis.Nil {
+		return 0, nil
+	}
+	return version, errors.Wrap(err, "error getting ClickUp metadata cache version")
+}
+
+// Invalidate drops the cached values of the workspace.
+func (c *MetadataCache) Invalidate(ctx context.Context) error {
+	if c == nil || c.redis == nil {
+		return nil
+	}
+	return errors.Wrap(c.redis.Client.Incr(ctx, c.versionKey()).Err(), "error invalidating ClickUp metadata cache")
+}
+
+// cachedList returns the cached items of the resource, listing them with fn when they are not cached.
+// The cache is bypassed when it cannot be read.
+func cachedList[T any](ctx context.Context, c *Client, resource string, fn func() ([]T, error)) ([]T, error) {
+	if c.cache == nil || c.cache.redis == nil {
+		return fn()
+	}
+	version, err := c.cache.version(ctx)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("workspace_id", c.cache.workspaceID).Warn("bypassing ClickUp metadata cache")
+		return fn()
+	}
+	key := fmt.Sprintf("clickup-metadata-%d-%d-%s", c.cache.workspaceID, version, resource)
+	items, err := redis.CachedEval(ctx, c.cache.redis, key, metadataCacheLockTimeout, metadataCacheTTL, func() (*[]T, error) {
+		items, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		return &items, nil
+	})
+	if err != nil || items == nil {
+		return nil, err
+	}
+	return *items, nil
+}
@@ -1,12 +1,17 @@
 package clickup
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"io"
+	"mime/multipart"
 	"net/http"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/segmentio/encoding/json"
 	"golang.org/x/oauth2"
@@ -65,112 +70,353 @@ func GetAccessToken(ctx context.Context, code string) (*oauth2.Token, error) {
 	return conf.Exchange(ctx, code)
 }
 
-func doClickUpPostRequest[TOut any, TIn any](accessToken string, relativeUrl string, input TIn) (TOut, error) {
-	var zero TOut
-	b, err := json.Marshal(input)
+// TokenStore persists the oauth token of the ClickUp integration of a workspace.
+type TokenStore interface {
+	// GetToken returns the stored token, or nil when ClickUp is not connected.
+	GetToken(ctx context.Context) (*oauth2.Token, error)
+	SetToken(ctx context.Context, token *oauth2.Token) error
+}
+
+// Client calls the ClickUp api with the token of a TokenStore. The token is refreshed when it
+// expires or is rejected by ClickUp, and the refreshed token is written back to the store.
+type Client struct {
+	conf       *oauth2.Config
+	store      TokenStore
+	httpClient *http.Client
+	maxRetries int
+	maxPages   int
+	cache      *MetadataCache
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func NewClient(ctx context.Context, store TokenStore) (*Client, error) {
+	conf, err := oauthConfig()
 	if err != nil {
-		return zero, err
+		return nil, err
 	}
-
-	return doClickUpRequest[TOut]("POST", accessToken, relativeUrl, string(b))
+	token, err := store.GetToken(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting ClickUp token")
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, errors.New("workspace does not have a ClickUp access token")
+	}
+	options, err := requestConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conf:       conf,
+		store:      store,
+		httpClient: &http.Client{Timeout: options.timeout},
+		maxRetries: options.maxRetries,
+		maxPages:   options.maxPages,
+		token:      token,
+	}, nil
 }
 
-func doClickUpGetRequest[T any](accessToken string, relativeUrl string) (T, error) {
-	return doClickUpRequest[T]("GET", accessToken, relativeUrl, "")
+// WithCache caches the teams, spaces, folders and lists listed by the client.
+func (c *Client) WithCache(cache *MetadataCache) *Client {
+	c.cache = cache
+	return c
 }
 
-func doClickUpRequest[T any](method string, accessToken string, relativeUrl string, body string) (T, error) {
-	var unmarshalled T
-	client := &http.Client{}
+// accessToken returns a valid access token, refreshing the token when it is expired or when
+// forceRefresh is set because ClickUp rejected it.
+func (c *Client) accessToken(ctx context.Context, forceRefresh bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token := c.token
+	if forceRefresh {
+		if token.RefreshToken == "" {
+			return "", errors.New("ClickUp access token was rejected and cannot be refreshed")
+		}
+		expired := *token
+		expired.Expiry = time.Now().Add(-time.Minute)
+		token = &expired
+	}
 
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", ClickUpApiBaseUrl, relativeUrl), strings.NewReader(body))
+	refreshed, err := c.conf.TokenSource(ctx, token).Token()
 	if err != nil {
-		return unmarshalled, errors.Wrap(err, "error creating api request to ClickUp")
+		return "", errors.Wrap(err, "error refreshing ClickUp access token")
 	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-	if method != "GET" {
-		req.Header.Set("Content-Type", "application/json")
+	if refreshed.AccessToken != c.token.AccessToken {
+		if refreshed.RefreshToken == "" {
+			refreshed.RefreshToken = c.token.RefreshToken
+		}
+		if err := c.store.SetToken(ctx, refreshed); err != nil {
+			return "", errors.Wrap(err, "error storing refreshed ClickUp access token")
+		}
+		c.token = refreshed
 	}
+	return refreshed.AccessToken, nil
+}
 
-	res, err := client.Do(req)
+func doClickUpPostRequest[TOut any, TIn any](ctx context.Context, c *Client, relativeUrl string, input TIn) (TOut, error) {
+	var zero TOut
+	b, err := json.Marshal(input)
 	if err != nil {
-		return unmarshalled, errors.Wrap(err, "error getting response from ClickUp Teams endpoint")
+		return zero, err
 	}
 
-	b, err := io.ReadAll(res.Body)
-	if res.StatusCode != 200 {
-		return unmarshalled, errors.New("ClickUp API responded with error; status_code=" + res.Status + "; body=" + string(b))
-	}
+	return doClickUpRequest[TOut](ctx, c, "POST", relativeUrl, string(b))
+}
+
+func doClickUpGetRequest[T any](ctx context.Context, c *Client, relativeUrl string) (T, error) {
+	return doClickUpRequest[T](ctx, c, "GET", relativeUrl, "")
+}
 
+func doClickUpRequest[T any](ctx context.Context, c *Client, method string, relativeUrl string, body string) (T, error) {
+	var unmarshalled T
+	var contentType string
+	if method != "GET" {
+		contentType = "application/json"
+	}
+	b, err := c.do(ctx, method, relativeUrl, contentType, []byte(body))
 	if err != nil {
-		return unmarshalled, errors.Wrap(err, "error reading response body from ClickUp Teams endpoint")
+		return unmarshalled, err
 	}
 
 	err = json.Unmarshal(b, &unmarshalled)
 	if err != nil {
-		return unmarshalled, errors.Wrap(err, "error unmarshaling ClickUp folders response")
+		return unmarshalled, errors.Wrap(err, "error unmarshaling ClickUp response")
 	}
 
 	return unmarshalled, nil
 }
 
-func GetFolders(accessToken string, spaceId string) ([]*model.ClickUpFolder, error) {
+func (c *Client) GetFolders(ctx context.Context, spaceId string) ([]*model.ClickUpFolder, error) {
 	type foldersResponse struct {
-		Folders []*model.ClickUpFolder `json:"folders"`
+		Folders  []*model.ClickUpFolder `json:"folders"`
+		LastPage bool                   `json:"last_page"`
 	}
-	res, err := doClickUpGetRequest[foldersResponse](accessToken, fmt.Sprintf("/space/%s/folder", spaceId))
-	if err != nil {
-		return nil, err
+	return cachedList(ctx, c, "space-"+spaceId+"-folders", func() ([]*model.ClickUpFolder, error) {
+		return getAllPages(ctx, c, fmt.Sprintf("/space/%s/folder", spaceId),
+			func(res foldersResponse) ([]*model.ClickUpFolder, bool) { return res.Folders, res.LastPage },
+			func(folder *model.ClickUpFolder) string { return folder.ID })
+	})
+}
+
+func (c *Client) GetFolderlessLists(ctx context.Context, spaceId string) ([]*model.ClickUpList, error) {
+	type listsResponse struct {
+		Lists    []*model.ClickUpList `json:"lists"`
+		LastPage bool                 `json:"last_page"`
+	}
+	return cachedList(ctx, c, "space-"+spaceId+"-lists", func() ([]*model.ClickUpList, error) {
+		return getAllPages(ctx, c, fmt.Sprintf("/space/%s/list", spaceId),
+			func(res listsResponse) ([]*model.ClickUpList, bool) { return res.Lists, res.LastPage },
+			func(list *model.ClickUpList) string { return list.ID })
+	})
+}
+
+func (c *Client) GetSpaces(ctx context.Context, teamId string) ([]*model.ClickUpSpace, error) {
+	type spacesResponse struct {
+		Spaces   []*model.ClickUpSpace `json:"spaces"`
+		LastPage bool                  `json:"last_page"`
+	}
+	return cachedList(ctx, c, "team-"+teamId+"-spaces", func() ([]*model.ClickUpSpace, error) {
+		return getAllPages(ctx, c, fmt.Sprintf("/team/%s/space", teamId),
+			func(res spacesResponse) ([]*model.ClickUpSpace, bool) { return res.Spaces, res.LastPage },
+			func(space *model.ClickUpSpace) string { return space.ID })
+	})
+}
+
+func (c *Client) GetTeams(ctx context.Context) ([]*model.ClickUpTeam, error) {
+	type teamsResponse struct {
+		Teams    []*model.ClickUpTeam `json:"teams"`
+		LastPage bool                 `json:"last_page"`
 	}
+	return cachedList(ctx, c, "teams", func() ([]*model.ClickUpTeam, error) {
+		return getAllPages(ctx, c, "/team",
+			func(res teamsResponse) ([]*model.ClickUpTeam, bool) { return res.Teams, res.LastPage },
+			func(team *model.ClickUpTeam) string { return team.ID })
+	})
+}
 
-	return res.Folders, nil
+// Member is a ClickUp user with access to a list, who tasks of the list can be assigned to.
+type Member struct {
+	ID             int    `json:"id"`
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	Initials       string `json:"initials"`
+	ProfilePicture string `json:"profilePicture"`
 }
 
-func GetFolderlessLists(accessToken string, spaceId string) ([]*model.ClickUpList, error) {
-	type listsResponse struct {
-		Lists []*model.ClickUpList `json:"lists"`
+func (c *Client) GetListMembers(ctx context.Context, listId string) ([]*Member, error) {
+	type membersResponse struct {
+		Members []*Member `json:"members"`
 	}
-	res, err := doClickUpGetRequest[listsResponse](accessToken, fmt.Sprintf("/space/%s/list", spaceId))
+	res, err := doClickUpGetRequest[membersResponse](ctx, c, fmt.Sprintf("/list/%s/member", listId))
 	if err != nil {
 		return nil, err
 	}
 
-	return res.Lists, nil
+	return res.Members, nil
 }
 
-func GetSpaces(accessToken string, teamId string) ([]*model.ClickUpSpace, error) {
-	type spacesResponse struct {
-		Spaces []*model.ClickUpSpace `json:"spaces"`
+// CreateTask creates a task in a list, with the assignees, priority, tags, status, due date and
+// custom field values of the options when they are set.
+func (c *Client) CreateTask(ctx context.Context, listId string, name string, description string, options *model.ClickUpTaskInput) (*model.ClickUpTask, error) {
+	input := struct {
+		Name         string              `json:"name"`
+		Description  string              `json:"description"`
+		Assignees    []int               `json:"assignees,omitempty"`
+		Priority     *int                `json:"priority,omitempty"`
+		Tags         []string            `json:"tags,omitempty"`
+		Status       *string             `json:"status,omitempty"`
+		DueDate      *int64              `json:"due_date,omitempty"`
+		DueDateTime  bool                `json:"due_date_time,omitempty"`
+		CustomFields []*customFieldValue `json:"custom_fields,omitempty"`
+	}{Name: name, Description: description}
+	if options != nil {
+		for _, assignee := range options.Assignees {
+			id, err := strconv.Atoi(assignee)
+			if err != nil {
+				return nil, errors.Errorf("invalid ClickUp assignee id %q", assignee)
+			}
+			input.Assignees = append(input.Assignees, id)
+		}
+		if options.Priority != nil && (*options.Priority < 1 || *options.Priority > 4) {
+			return nil, errors.Errorf("invalid ClickUp priority %d", *options.Priority)
+		}
+		input.Priority = options.Priority
+		input.Tags = options.Tags
+		input.Status = options.Status
+		if options.DueDate != nil {
+			dueDate := options.DueDate.UnixMilli()
+			input.DueDate = &dueDate
+			input.DueDateTime = true
+		}
+		if len(options.CustomFields) > 0 {
+			customFields, err := c.customFieldValues(ctx, listId, options.CustomFields)
+			if err != nil {
+				return nil, err
+			}
+			input.CustomFields = customFields
+		}
 	}
-	res, err := doClickUpGetRequest[spacesResponse](accessToken, fmt.Sprintf("/team/%s/space", teamId))
+	res, err := doClickUpPostRequest[*model.ClickUpTask](ctx, c, fmt.Sprintf("/list/%s/task", listId), input)
 	if err != nil {
 		return nil, err
 	}
 
-	return res.Spaces, nil
+	return res, nil
 }
 
-func GetTeams(accessToken string) ([]*model.ClickUpTeam, error) {
-	type teamsResponse struct {
-		Teams []*model.ClickUpTeam `json:"teams"`
+// CommentBlock is a part of the text of a task comment, linking to Link when it is set.
+type CommentBlock struct {
+	Text string
+	Link string
+}
+
+// AddComment posts a comment to a task without notifying its watchers.
+func (c *Client) AddComment(ctx context.Context, taskId string, blocks []*CommentBlock) error {
+	type commentBlock struct {
+		Text       string         `json:"text"`
+		Attributes map[string]any `json:"attributes"`
 	}
-	res, err := doClickUpGetRequest[teamsResponse](accessToken, "/team")
+	input := struct {
+		Comment   []*commentBlock `json:"comment"`
+		NotifyAll bool            `json:"notify_all"`
+	}{}
+	for _, block := range blocks {
+		attributes := map[string]any{}
+		if block.Link != "" {
+			attributes["link"] = block.Link
+		}
+		input.Comment = append(input.Comment, &commentBlock{Text: block.Text, Attributes: attributes})
+	}
+	_, err := doClickUpPostRequest[map[string]any](ctx, c, fmt.Sprintf("/task/%s/comment", taskId), input)
+	return err
+}
+
+// UploadAttachment uploads a file as an attachment of a task.
+func (c *Client) UploadAttachment(ctx context.Context, taskId string, filename string, data []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("attachment", filename)
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "error creating ClickUp attachment")
 	}
+	if _, err := part.Write(data); err != nil {
+		return errors.Wrap(err, "error writing ClickUp attachment")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "error writing ClickUp attachment")
+	}
+	_, err = c.do(ctx, "POST", fmt.Sprintf("/task/%s/attachment", taskId), writer.FormDataContentType(), body.Bytes())
+	return err
+}
+
+// maxSearchTasks caps the tasks returned by a search.
+const maxSearchTasks = 20
+
+// taskIDPattern matches the ids of tasks, ie. `86a1b2c3d`, which may be prefixed with a `#` as shown in ClickUp.
+var taskIDPattern = regexp.MustCompile(`^#?[0-9a-z]{5,12}$`)
 
-	return res.Teams, nil
+func (c *Client) GetTask(ctx context.Context, taskId string) (*model.ClickUpTask, error) {
+	return doClickUpGetRequest[*model.ClickUpTask](ctx, c, fmt.Sprintf("/task/%s", taskId))
 }
 
-func CreateTask(accessToken string, listId string, name string, description string) (*model.ClickUpTask, error) {
-	input := struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-	}{Name: name, Description: description}
-	res, err := doClickUpPostRequest[*model.ClickUpTask](accessToken, fmt.Sprintf("/list/%s/task", listId), input)
+// TaskStatus is the status of a task. Its type is `open`, `custom`, `done` or `closed`.
+type TaskStatus struct {
+	Status string `json:"status"`
+	Type   string `json:"type"`
+}
+
+func (c *Client) GetTaskStatus(ctx context.Context, taskId string) (*TaskStatus, error) {
+	type taskResponse struct {
+		Status *TaskStatus `json:"status"`
+	}
+	res, err := doClickUpGetRequest[taskResponse](ctx, c, fmt.Sprintf("/task/%s", taskId))
 	if err != nil {
 		return nil, err
 	}
+	if res.Status == nil {
+		return nil, errors.Errorf("ClickUp task %s has no status", taskId)
+	}
+	return res.Status, nil
+}
 
-	return res, nil
+// SearchTasks returns the tasks of a team whose name contains the query, most recently updated first.
+// When the query is a task id, the task with the id is returned first.
+func (c *Client) SearchTasks(ctx context.Context, teamId string, query string) ([]*model.ClickUpTask, error) {
+	query = strings.TrimSpace(query)
+	var results []*model.ClickUpTask
+	if taskIDPattern.MatchString(query) {
+		task, err := c.GetTask(ctx, strings.TrimPrefix(query, "#"))
+		var apiErr *APIError
+		if err != nil && (!errors.As(err, &apiErr) || apiErr.retryable()) {
+			return nil, err
+		}
+		if task != nil && task.ID != "" {
+			results = append(results, task)
+		}
+	}
+
+	type tasksResponse struct {
+		Tasks    []*model.ClickUpTask `json:"tasks"`
+		LastPage bool                 `json:"last_page"`
+	}
+	tasks, err := getAllPages(ctx, c, fmt.Sprintf("/team/%s/task?include_closed=true&subtasks=true&order_by=updated", teamId),
+		func(res tasksResponse) ([]*model.ClickUpTask, bool) { return res.Tasks, res.LastPage },
+		func(task *model.ClickUpTask) string { return task.ID })
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if len(results) >= maxSearchTasks {
+			break
+		}
+		if len(results) > 0 && results[0].ID == task.ID {
+			continue
+		}
+		if strings.Contains(strings.ToLower(task.Name), strings.ToLower(query)) {
+			results = append(results, task)
+		}
+	}
+	return results, nil
 }
@@ -0,0 +1,438 @@
+package clickup
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+type mockTokenStore struct {
+	token *oauth2.Token
+}
+
+func (s *mockTokenStore) GetToken(context.Context) (*oauth2.Token, error) {
+	return s.token, nil
+}
+
+func (s *mockTokenStore) SetToken(_ context.Context, token *oauth2.Token) error {
+	s.token = token
+	return nil
+}
+
+func TestClient_RefreshesRejectedToken(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	var teamRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh-token", r.Form.Get("refresh_token"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"new-token","token_type":"Bearer","expires_in":3600}`))
+		case "/team":
+			teamRequests++
+			if r.Header.Get("Authorization") != "Bearer new-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"teams":[{"id":"1","name":"team"}],"last_page":true}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	store := &mockTokenStore{token: &oauth2.Token{AccessToken: "old-token", RefreshToken: "refresh-token"}}
+	client, err := NewClient(ctx, store)
+	assert.NoError(t, err)
+	client.conf.Endpoint.TokenURL = server.URL + "/oauth/token"
+
+	teams, err := client.GetTeams(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, teams, 1)
+	assert.Equal(t, 2, teamRequests)
+	assert.Equal(t, "new-token", store.token.AccessToken)
+	assert.Equal(t, "refresh-token", store.token.RefreshToken)
+
+	store.token = &oauth2.Token{AccessToken: "old-token"}
+	client, err = NewClient(ctx, store)
+	assert.NoError(t, err)
+	_, err = client.GetTeams(ctx)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.Unauthorized())
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+	t.Setenv(MaxRetriesEnvVar, "2")
+
+	delay := minRetryDelay
+	minRetryDelay = time.Millisecond
+	defer func() { minRetryDelay = delay }()
+
+	var requests int
+	var statuses []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[requests]
+		requests++
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"teams":[]}`))
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.NoError(t, err)
+
+	requests, statuses = 0, []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusOK}
+	_, err = client.GetTeams(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	requests, statuses = 0, []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests}
+	_, err = client.GetTeams(ctx)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.RateLimited())
+	assert.Equal(t, 3, requests)
+
+	requests, statuses = 0, []int{http.StatusBadRequest}
+	_, err = client.GetTeams(ctx)
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, 1, requests)
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	assert.Equal(t, minRetryDelay, retryDelay(http.Header{}, 0, now))
+	assert.Equal(t, 4*minRetryDelay, retryDelay(http.Header{}, 2, now))
+	assert.Equal(t, maxRetryDelay, retryDelay(http.Header{}, 20, now))
+	assert.Equal(t, 5*time.Second, retryDelay(http.Header{"Retry-After": []string{"5"}}, 0, now))
+	assert.Equal(t, 12*time.Second, retryDelay(http.Header{"X-Ratelimit-Reset": []string{"1700000012"}}, 0, now))
+}
+
+func TestClient_CreateTask(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/list/list-1/task", r.URL.Path)
+		body = nil
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"id":"task-1","name":"task"}`))
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.NoError(t, err)
+
+	priority, status := 2, "in progress"
+	dueDate := time.UnixMilli(1700000000000)
+	task, err := client.CreateTask(ctx, "list-1", "task", "description", &model.ClickUpTaskInput{
+		Assignees: []string{"12", "34"},
+		Priority:  &priority,
+		Tags:      []string{"bug"},
+		Status:    &status,
+		DueDate:   &dueDate,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+	assert.Equal(t, map[string]any{
+		"name":          "task",
+		"description":   "description",
+		"assignees":     []any{12.0, 34.0},
+		"priority":      2.0,
+		"tags":          []any{"bug"},
+		"status":        "in progress",
+		"due_date":      1700000000000.0,
+		"due_date_time": true,
+	}, body)
+
+	_, err = client.CreateTask(ctx, "list-1", "task", "description", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "task", "description": "description"}, body)
+
+	_, err = client.CreateTask(ctx, "list-1", "task", "description", &model.ClickUpTaskInput{Assignees: []string{"me"}})
+	assert.Error(t, err)
+}
+
+func TestClient_AddCommentAndUploadAttachment(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	var comment map[string]any
+	var attachment []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/task/task-1/comment":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+		case "/task/task-1/attachment":
+			file, header, err := r.FormFile("attachment")
+			assert.NoError(t, err)
+			assert.Equal(t, "session.png", header.Filename)
+			attachment, err = io.ReadAll(file)
+			assert.NoError(t, err)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.NoError(t, err)
+
+	err = client.AddComment(ctx, "task-1", []*CommentBlock{{Text: "stacktrace\n"}, {Text: "View", Link: "https://app.highlight.io"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"comment": []any{
+			map[string]any{"text": "stacktrace\n", "attributes": map[string]any{}},
+			map[string]any{"text": "View", "attributes": map[string]any{"link": "https://app.highlight.io"}},
+		},
+		"notify_all": false,
+	}, comment)
+
+	err = client.UploadAttachment(ctx, "task-1", "session.png", []byte("png"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("png"), attachment)
+}
+
+func TestClient_GetAllPages(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		switch r.URL.Path {
+		case "/space/paginated/list":
+			lists, ok := map[string]string{"0": `[{"id":"1"},{"id":"2"}]`, "1": `[{"id":"3"}]`}[page]
+			if !ok {
+				lists = `[]`
+			}
+			_, _ = w.Write([]byte(`{"lists":` + lists + `}`))
+		case "/space/last-page/list":
+			_, _ = w.Write([]byte(`{"lists":[{"id":"` + page + `"}],"last_page":` + strconv.FormatBool(page == "1") + `}`))
+		default:
+			_, _ = w.Write([]byte(`{"lists":[{"id":"1"},{"id":"2"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		spaceId  string
+		maxPages int
+		lists    int
+		pages    []string
+	}{
+		{"paginated", 10, 3, []string{"0", "1", "2"}},
+		{"paginated", 1, 2, []string{"0"}},
+		{"last-page", 10, 2, []string{"0", "1"}},
+		{"not-paginated", 10, 2, []string{"0", "1"}},
+	} {
+		pages = nil
+		client.maxPages = tc.maxPages
+		lists, err := client.GetFolderlessLists(ctx, tc.spaceId)
+		assert.NoError(t, err)
+		assert.Len(t, lists, tc.lists)
+		assert.Equal(t, tc.pages, pages)
+	}
+}
+
+func TestCustomField_Value(t *testing.T) {
+	options := CustomFieldTypeConfig{Options: []*CustomFieldOption{
+		{ID: "option-1", Name: "High", Label: "bug"},
+		{ID: "option-2", Name: "Low", Label: "frontend"},
+	}}
+	for _, tc := range []struct {
+		field    CustomField
+		value    string
+		expected any
+	}{
+		{CustomField{Type: "short_text"}, "production", "production"},
+		{CustomField{Type: "number"}, "1.5", 1.5},
+		{CustomField{Type: "checkbox"}, "true", true},
+		{CustomField{Type: "date"}, "2023-11-14T22:13:20Z", int64(1700000000000)},
+		{CustomField{Type: "drop_down", TypeConfig: options}, "high", "option-1"},
+		{CustomField{Type: "drop_down", TypeConfig: options}, "option-2", "option-2"},
+		{CustomField{Type: "labels", TypeConfig: options}, "bug, frontend", []string{"option-1", "option-2"}},
+	} {
+		value, err := tc.field.value(tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, value)
+	}
+
+	for _, tc := range []struct {
+		field CustomField
+		value string
+	}{
+		{CustomField{Type: "number"}, "many"},
+		{CustomField{Type: "drop_down", TypeConfig: options}, "Medium"},
+		{CustomField{Type: "labels", TypeConfig: options}, "bug,backend"},
+	} {
+		_, err := tc.field.value(tc.value)
+		assert.Error(t, err)
+	}
+}
+
+func TestClient_SearchTasks(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/task/abc123":
+			_, _ = w.Write([]byte(`{"id":"abc123","name":"Login fails"}`))
+		case "/task/login":
+			w.WriteHeader(http.StatusNotFound)
+		case "/team/1/task":
+			if r.URL.Query().Get("page") != "0" {
+				_, _ = w.Write([]byte(`{"tasks":[],"last_page":true}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tasks":[{"id":"abc123","name":"Login fails"},{"id":"def456","name":"TypeError on login page"},{"id":"ghi789","name":"Checkout"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.NoError(t, err)
+
+	tasks, err := client.SearchTasks(ctx, "1", "login")
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.ClickUpTask{{ID: "abc123", Name: "Login fails"}, {ID: "def456", Name: "TypeError on login page"}}, tasks)
+
+	tasks, err = client.SearchTasks(ctx, "1", "#abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.ClickUpTask{{ID: "abc123", Name: "Login fails"}}, tasks)
+}
+
+func TestVerifyIntegration(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"user":{"id":1,"username":"vadim","email":"vadim@highlight.io"}}`))
+		case "/team":
+			_, _ = w.Write([]byte(`{"teams":[{"id":"1","name":"team"}],"last_page":true}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	status := VerifyIntegration(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.True(t, status.Connected)
+	assert.False(t, status.ReconnectRequired)
+	assert.Equal(t, &User{ID: 1, Username: "vadim", Email: "vadim@highlight.io"}, status.User)
+	assert.Equal(t, []*model.ClickUpTeam{{ID: "1", Name: "team"}}, status.Teams)
+
+	status = VerifyIntegration(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "revoked"}})
+	assert.False(t, status.Connected)
+	assert.True(t, status.ReconnectRequired)
+	assert.Empty(t, status.Teams)
+
+	status = VerifyIntegration(ctx, &mockTokenStore{})
+	assert.False(t, status.Connected)
+	assert.False(t, status.ReconnectRequired)
+	assert.Equal(t, "ClickUp is not connected", status.Error)
+}
+
+func TestClient_CachesMetadata(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	var spaceRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spaceRequests++
+		_, _ = w.Write([]byte(`{"spaces":[{"id":"1","name":"space"}],"last_page":true}`))
+	}))
+	defer server.Close()
+
+	baseUrl := ClickUpApiBaseUrl
+	ClickUpApiBaseUrl = server.URL
+	defer func() { ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	cache := NewMetadataCache(redis.NewClient(), int(time.Now().UnixNano()%1_000_000_000))
+	client, err := NewClient(ctx, &mockTokenStore{token: &oauth2.Token{AccessToken: "token"}})
+	assert.NoError(t, err)
+	client.WithCache(cache)
+
+	for i := 0; i < 2; i++ {
+		spaces, err := client.GetSpaces(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.ClickUpSpace{{ID: "1", Name: "space"}}, spaces)
+	}
+	assert.Equal(t, 1, spaceRequests)
+
+	assert.NoError(t, cache.Invalidate(ctx))
+	_, err = client.GetSpaces(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, spaceRequests)
+}
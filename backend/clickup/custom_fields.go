@@ -0,0 +1,118 @@
+package clickup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/pkg/errors"
+)
+
+// CustomFieldOption is an option of a drop down or labels custom field.
+type CustomFieldOption struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Label string `json:"label,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+type CustomFieldTypeConfig struct {
+	Options []*CustomFieldOption `json:"options,omitempty"`
+}
+
+// CustomField is a custom field definition of a list, ie. a `drop_down` Severity field.
+type CustomField struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Type       string                `json:"type"`
+	TypeConfig CustomFieldTypeConfig `json:"type_config"`
+	Required   bool                  `json:"required"`
+}
+
+// GetAccessibleCustomFields returns the custom fields of a list that the token has access to.
+func (c *Client) GetAccessibleCustomFields(ctx context.Context, listId string) ([]*CustomField, error) {
+	type fieldsResponse struct {
+		Fields []*CustomField `json:"fields"`
+	}
+	res, err := doClickUpGetRequest[fieldsResponse](ctx, c, fmt.Sprintf("/list/%s/field", listId))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Fields, nil
+}
+
+type customFieldValue struct {
+	ID    string `json:"id"`
+	Value any    `json:"value"`
+}
+
+// customFieldValues converts the values of custom fields, which are set as strings, to the values
+// that ClickUp expects for the types of the fields of the list.
+func (c *Client) customFieldValues(ctx context.Context, listId string, inputs []*model.ClickUpCustomFieldInput) ([]*customFieldValue, error) {
+	fields, err := c.GetAccessibleCustomFields(ctx, listId)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*CustomField, len(fields))
+	for _, field := range fields {
+		byID[field.ID] = field
+	}
+
+	values := make([]*customFieldValue, 0, len(inputs))
+	for _, input := range inputs {
+		field, ok := byID[input.ID]
+		if !ok {
+			return nil, errors.Errorf("ClickUp list does not have custom field %s", input.ID)
+		}
+		value, err := field.value(input.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of ClickUp custom field %s", field.Name)
+		}
+		values = append(values, &customFieldValue{ID: field.ID, Value: value})
+	}
+	return values, nil
+}
+
+// value converts a value to the type of the field. Options of drop down and labels fields are set by
+// their id or name, labels are separated by commas and dates are RFC3339 timestamps.
+func (f *CustomField) value(value string) (any, error) {
+	switch f.Type {
+	case "number", "currency", "emoji", "manual_progress":
+		return strconv.ParseFloat(value, 64)
+	case "checkbox":
+		return strconv.ParseBool(value)
+	case "date":
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnixMilli(), nil
+	case "drop_down":
+		return f.option(value)
+	case "labels":
+		var ids []string
+		for _, label := range strings.Split(value, ",") {
+			id, err := f.option(strings.TrimSpace(label))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	default:
+		return value, nil
+	}
+}
+
+func (f *CustomField) option(value string) (string, error) {
+	for _, option := range f.TypeConfig.Options {
+		if option.ID == value || strings.EqualFold(option.Name, value) || strings.EqualFold(option.Label, value) {
+			return option.ID, nil
+		}
+	}
+	return "", errors.Errorf("no option %q", value)
+}
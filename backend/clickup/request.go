@@ -0,0 +1,206 @@
+package clickup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	RequestTimeoutEnvVar = "CLICKUP_REQUEST_TIMEOUT"
+	MaxRetriesEnvVar     = "CLICKUP_MAX_RETRIES"
+	MaxPagesEnvVar       = "CLICKUP_MAX_PAGES"
+)
+
+const (
+	defaultRequestTimeout = 10 * time.Second
+	defaultMaxRetries     = 3
+	defaultMaxPages       = 10
+)
+
+// delays between retries of rate limited and failed requests, doubling with each attempt
+var (
+	minRetryDelay = 500 * time.Millisecond
+	maxRetryDelay = 30 * time.Second
+)
+
+// APIError is an error response of the ClickUp api.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	// RetryAfter is how long ClickUp asked to wait before retrying a rate limited request.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return "ClickUp API responded with error; status_code=" + e.Status + "; body=" + e.Body
+}
+
+// RateLimited returns whether the request was rejected by the rate limit of the workspace.
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// Unauthorized returns whether the access token was rejected, ie. because ClickUp was disconnected.
+func (e *APIError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *APIError) retryable() bool {
+	return e.RateLimited() || e.StatusCode >= http.StatusInternalServerError
+}
+
+type requestOptions struct {
+	timeout    time.Duration
+	maxRetries int
+	maxPages   int
+}
+
+// requestConfig returns the request timeout, the number of retries and the number of pages of
+// list endpoints configured by the environment.
+func requestConfig() (requestOptions, error) {
+	options := requestOptions{timeout: defaultRequestTimeout, maxRetries: defaultMaxRetries, maxPages: defaultMaxPages}
+	if value := os.Getenv(RequestTimeoutEnvVar); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return options, errors.Errorf("invalid %s %q", RequestTimeoutEnvVar, value)
+		}
+		options.timeout = timeout
+	}
+	for envVar, option := range map[string]*int{
+		MaxRetriesEnvVar: &options.maxRetries,
+		MaxPagesEnvVar:   &options.maxPages,
+	} {
+		if value := os.Getenv(envVar); value != "" {
+			v, err := strconv.Atoi(value)
+			if err != nil || v < 0 {
+				return options, errors.Errorf("invalid %s %q", envVar, value)
+			}
+			*option = v
+		}
+	}
+	if options.maxPages == 0 {
+		return options, errors.Errorf("invalid %s %q", MaxPagesEnvVar, os.Getenv(MaxPagesEnvVar))
+	}
+	return options, nil
+}
+
+// retryDelay returns how long to wait before retrying a request. Rate limited requests wait for the
+// Retry-After or X-RateLimit-Reset of the response, other requests back off exponentially.
+func retryDelay(header http.Header, attempt int, now time.Time) time.Duration {
+	delay := minRetryDelay * time.Duration(math.Pow(2, float64(attempt)))
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if until := time.Unix(reset, 0).Sub(now); until > 0 {
+			delay = until
+		}
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// do makes a request with the access token of the client, returning the response body. A request
+// whose token is rejected is retried once with a refreshed token, and rate limited or failed
+// requests are retried up to the max retries of the client.
+func (c *Client) do(ctx context.Context, method string, relativeUrl string, contentType string, body []byte) ([]byte, error) {
+	var unauthorized *APIError
+	var refresh bool
+	for attempt := 0; ; attempt++ {
+		accessToken, err := c.accessToken(ctx, refresh)
+		refresh = false
+		if err != nil {
+			if unauthorized != nil {
+				// the token cannot be refreshed, ie. because ClickUp was disconnected
+				return nil, unauthorized
+			}
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", ClickUpApiBaseUrl, relativeUrl), bytes.NewReader(body))
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating api request to ClickUp")
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting response from ClickUp")
+		}
+		b, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading response body from ClickUp")
+		}
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return b, nil
+		}
+
+		apiErr := &APIError{StatusCode: res.StatusCode, Status: res.Status, Body: string(b)}
+		if apiErr.Unauthorized() && unauthorized == nil {
+			unauthorized, refresh = apiErr, true
+			continue
+		}
+		if !apiErr.retryable() || attempt >= c.maxRetries {
+			if apiErr.RateLimited() {
+				apiErr.RetryAfter = retryDelay(res.Header, attempt, time.Now())
+			}
+			return nil, apiErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryDelay(res.Header, attempt, time.Now())):
+		}
+	}
+}
+
+// getAllPages requests the pages of a list endpoint and returns their items. Pages are requested
+// until ClickUp reports the last page, a page has no items that were not already returned, or the
+// max pages of the client are read. The endpoints that are not paginated return all their items on
+// each page, so that they stop after the second page.
+func getAllPages[T any, R any](ctx context.Context, c *Client, relativeUrl string, items func(R) ([]T, bool), id func(T) string) ([]T, error) {
+	separator := "?"
+	if strings.Contains(relativeUrl, "?") {
+		separator = "&"
+	}
+
+	var all []T
+	seen := map[string]bool{}
+	for page := 0; page < c.maxPages; page++ {
+		res, err := doClickUpGetRequest[R](ctx, c, fmt.Sprintf("%s%spage=%d", relativeUrl, separator, page))
+		if err != nil {
+			return nil, err
+		}
+		pageItems, lastPage := items(res)
+		var added int
+		for _, item := range pageItems {
+			if seen[id(item)] {
+				continue
+			}
+			seen[id(item)] = true
+			all = append(all, item)
+			added++
+		}
+		if lastPage || added == 0 {
+			break
+		}
+	}
+	return all, nil
+}
@@ -0,0 +1,79 @@
+package clickup
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/pkg/errors"
+)
+
+// User is the ClickUp user that authorized the integration.
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// IntegrationStatus reports whether the ClickUp integration of a workspace works. ClickUp tokens
+// are not scoped; instead they are granted access to the teams (ClickUp workspaces) chosen when
+// the integration is authorized, so the teams are the access granted to the integration.
+type IntegrationStatus struct {
+	Connected bool `json:"connected"`
+	// ReconnectRequired is set when the token was revoked, cannot be refreshed or has no access
+	// to any team, so that the integration must be authorized again.
+	ReconnectRequired bool                 `json:"reconnect_required"`
+	User              *User                `json:"user,omitempty"`
+	Teams             []*model.ClickUpTeam `json:"teams"`
+	Error             string               `json:"error,omitempty"`
+}
+
+// VerifyIntegration checks that the token of the store is valid and reports the user that it
+// belongs to and the teams that it can reach.
+func VerifyIntegration(ctx context.Context, store TokenStore) *IntegrationStatus {
+	status := &IntegrationStatus{Teams: []*model.ClickUpTeam{}}
+	token, err := store.GetToken(ctx)
+	if err != nil {
+		status.Error = errors.Wrap(err, "error getting ClickUp token").Error()
+		return status
+	} else if token == nil || token.AccessToken == "" {
+		status.Error = "ClickUp is not connected"
+		return status
+	}
+
+	client, err := NewClient(ctx, store)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	type userResponse struct {
+		User *User `json:"user"`
+	}
+	user, err := doClickUpGetRequest[userResponse](ctx, client, "/user")
+	if err != nil {
+		return status.failed(err)
+	}
+	status.User = user.User
+
+	teams, err := client.GetTeams(ctx)
+	if err != nil {
+		return status.failed(err)
+	}
+	if len(teams) == 0 {
+		status.ReconnectRequired = true
+		status.Error = "ClickUp token does not have access to any team"
+		return status
+	}
+	status.Connected = true
+	status.Teams = teams
+	return status
+}
+
+// failed reports the error of a ClickUp request, requiring the integration to be reconnected when
+// the token was rejected.
+func (s *IntegrationStatus) failed(err error) *IntegrationStatus {
+	var apiErr *APIError
+	s.ReconnectRequired = errors.As(err, &apiErr) && apiErr.Unauthorized()
+	s.Error = err.Error()
+	return s
+}
@@ -0,0 +1,246 @@
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	// LogsIntakeUrlFormat and ApiUrlFormat are formatted with the site of the Datadog account.
+	LogsIntakeUrlFormat = "https://http-intake.logs.%s"
+	ApiUrlFormat        = "https://api.%s"
+)
+
+const requestTimeout = 30 * time.Second
+
+// The limits of a request to the logs intake api.
+// See https://docs.datadoghq.com/api/latest/logs/#send-logs
+const (
+	maxBatchLogs  = 1000
+	maxBatchBytes = 5_000_000
+)
+
+// Source is the `ddsource` of the forwarded logs.
+const Source = "highlight"
+
+const DefaultSite Site = "datadoghq.com"
+
+// Site is the site of the Datadog account, which serves the api of its region.
+type Site string
+
+var sites = []Site{DefaultSite, "us3.datadoghq.com", "us5.datadoghq.com", "datadoghq.eu", "ap1.datadoghq.com", "ddog-gov.com"}
+
+func (s Site) IsValid() bool {
+	for _, site := range sites {
+		if s == site {
+			return true
+		}
+	}
+	return false
+}
+
+// Log is an entry of the logs intake api. The attributes are sent as top level fields of the entry.
+type Log struct {
+	Source     string
+	Tags       []string
+	Hostname   string
+	Service    string
+	Status     string
+	Message    string
+	Timestamp  time.Time
+	Attributes map[string]string
+}
+
+func (l *Log) MarshalJSON() ([]byte, error) {
+	entry := make(map[string]any, len(l.Attributes)+7)
+	for k, v := range l.Attributes {
+		entry[k] = v
+	}
+	for k, v := range map[string]string{
+		"ddsource": l.Source,
+		"ddtags":   strings.Join(l.Tags, ","),
+		"hostname": l.Hostname,
+		"service":  l.Service,
+		"status":   l.Status,
+	} {
+		if v != "" {
+			entry[k] = v
+		}
+	}
+	entry["message"] = l.Message
+	entry["timestamp"] = l.Timestamp.UnixMilli()
+	return json.Marshal(entry)
+}
+
+// AttributeMapping renames the attributes of the forwarded logs, ie. to the standard attributes of
+// Datadog. Attributes mapped to an empty name are not forwarded.
+type AttributeMapping map[string]string
+
+// DefaultAttributeMapping maps the highlight attributes that have a Datadog standard attribute.
+var DefaultAttributeMapping = AttributeMapping{
+	"http.user_agent":      "http.useragent",
+	"exception.type":       "error.kind",
+	"exception.message":    "error.message",
+	"exception.stacktrace": "error.stack",
+}
+
+// LogFromRow converts a highlight log row to a Datadog log. The resource columns are sent as tags,
+// the trace, span and session ids as attributes, and the log attributes are renamed by the mapping.
+func LogFromRow(row *clickhouse.LogRow, mapping AttributeMapping) *Log {
+	l := &Log{
+		Source:     Source,
+		Hostname:   row.HostName,
+		Service:    row.ServiceName,
+		Status:     row.SeverityText,
+		Message:    row.Body,
+		Timestamp:  row.Timestamp,
+		Attributes: make(map[string]string, len(row.LogAttributes)+3),
+	}
+	for tag, value := range map[string]string{
+		"env":            row.Environment,
+		"version":        row.ServiceVersion,
+		"kube_namespace": row.K8sNamespace,
+		"pod_name":       row.K8sPodName,
+		"container_id":   row.ContainerId,
+	} {
+		if value != "" {
+			l.Tags = append(l.Tags, tag+":"+value)
+		}
+	}
+	sort.Strings(l.Tags)
+
+	for k, v := range row.LogAttributes {
+		if name, ok := mapping[k]; ok {
+			if name == "" {
+				continue
+			}
+			k = name
+		} else if name, ok := DefaultAttributeMapping[k]; ok {
+			k = name
+		}
+		l.Attributes[k] = v
+	}
+	for k, v := range map[string]string{
+		"trace_id":             row.TraceId,
+		"span_id":              row.SpanId,
+		"highlight.session_id": row.SecureSessionId,
+		"highlight.source":     string(row.Source),
+	} {
+		if v != "" {
+			l.Attributes[k] = v
+		}
+	}
+	return l
+}
+
+// StatusError is the error of a request that Datadog responded to with an error status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Datadog API responded with error; status_code=%d; body=%s", e.StatusCode, e.Body)
+}
+
+// Client sends logs to the logs intake api of a Datadog site with an api key of the account.
+type Client struct {
+	apiKey     string
+	site       Site
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, site Site) *Client {
+	if site == "" {
+		site = DefaultSite
+	}
+	return &Client{apiKey: apiKey, site: site, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+func (c *Client) doRequest(ctx context.Context, method string, url string, body []byte) ([]byte, error) {
+	if c.apiKey == "" {
+		return nil, errors.New("Datadog api key is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request to Datadog")
+	}
+	req.Header.Set("DD-API-KEY", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting response from Datadog endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body from Datadog endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, &StatusError{StatusCode: res.StatusCode, Body: string(b)}
+	}
+	return b, nil
+}
+
+// ValidateAPIKey returns whether the api key is a valid key of an account of the site.
+func (c *Client) ValidateAPIKey(ctx context.Context) (bool, error) {
+	body, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf(ApiUrlFormat, c.site)+"/api/v1/validate", nil)
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden {
+			return false, nil
+		}
+		return false, err
+	}
+	var response struct {
+		Valid bool `json:"valid"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return false, errors.Wrap(err, "error unmarshaling Datadog response")
+	}
+	return response.Valid, nil
+}
+
+// SendLogs sends the logs to the logs intake api, in as many requests as the intake limits require.
+func (c *Client) SendLogs(ctx context.Context, logs []*Log) error {
+	url := fmt.Sprintf(LogsIntakeUrlFormat, c.site) + "/api/v2/logs"
+	var batch [][]byte
+	var batchBytes int
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		body := append([]byte("["), bytes.Join(batch, []byte(","))...)
+		body = append(body, ']')
+		batch, batchBytes = nil, 0
+		_, err := c.doRequest(ctx, http.MethodPost, url, body)
+		return err
+	}
+
+	for _, l := range logs {
+		b, err := json.Marshal(l)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling Datadog log")
+		}
+		if len(batch) == maxBatchLogs || batchBytes+len(b)+1 > maxBatchBytes {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+		batch = append(batch, b)
+		batchBytes += len(b) + 1
+	}
+	return send()
+}
@@ -0,0 +1,112 @@
+package datadog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogFromRow(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+	l := LogFromRow(&clickhouse.LogRow{
+		Timestamp:       ts,
+		TraceId:         "abc",
+		SecureSessionId: "session",
+		SeverityText:    "error",
+		Source:          modelInputs.LogSourceBackend,
+		ServiceName:     "api",
+		ServiceVersion:  "1.2.3",
+		Body:            "failed to charge card",
+		Environment:     "production",
+		HostName:        "web-1",
+		LogAttributes: map[string]string{
+			"customer.id":     "42",
+			"http.user_agent": "curl/8.0",
+			"user.email":      "jay@example.com",
+		},
+	}, AttributeMapping{"customer.id": "usr.id", "user.email": ""})
+
+	b, err := json.Marshal(l)
+	assert.NoError(t, err)
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(b, &entry))
+	assert.Equal(t, map[string]any{
+		"ddsource":             "highlight",
+		"ddtags":               "env:production,version:1.2.3",
+		"hostname":             "web-1",
+		"service":              "api",
+		"status":               "error",
+		"message":              "failed to charge card",
+		"timestamp":            float64(1700000000123),
+		"usr.id":               "42",
+		"http.useragent":       "curl/8.0",
+		"trace_id":             "abc",
+		"highlight.session_id": "session",
+		"highlight.source":     "backend",
+	}, entry)
+}
+
+func TestSite(t *testing.T) {
+	assert.True(t, DefaultSite.IsValid())
+	assert.True(t, Site("datadoghq.eu").IsValid())
+	assert.False(t, Site("example.com").IsValid())
+}
+
+func TestClient(t *testing.T) {
+	var batches [][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("DD-API-KEY") != "key" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["Forbidden"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/validate":
+			_, _ = w.Write([]byte(`{"valid":true}`))
+		case "/api/v2/logs":
+			var batch []map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+			batches = append(batches, batch)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	intakeUrlFormat, apiUrlFormat := LogsIntakeUrlFormat, ApiUrlFormat
+	LogsIntakeUrlFormat = strings.Replace(server.URL, "127.0.0.1", "%s", 1)
+	ApiUrlFormat = LogsIntakeUrlFormat
+	defer func() { LogsIntakeUrlFormat, ApiUrlFormat = intakeUrlFormat, apiUrlFormat }()
+
+	ctx := context.Background()
+	client := NewClient("key", "127.0.0.1")
+	valid, err := client.ValidateAPIKey(ctx)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = NewClient("invalid", "127.0.0.1").ValidateAPIKey(ctx)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	logs := make([]*Log, maxBatchLogs+1)
+	for i := range logs {
+		logs[i] = &Log{Message: "hello", Timestamp: time.Now()}
+	}
+	assert.NoError(t, client.SendLogs(ctx, logs))
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], maxBatchLogs)
+	assert.Len(t, batches[1], 1)
+	assert.Equal(t, "hello", batches[1][0]["message"])
+
+	var statusErr *StatusError
+	assert.ErrorAs(t, NewClient("invalid", "127.0.0.1").SendLogs(ctx, logs[:1]), &statusErr)
+	assert.Equal(t, http.StatusForbidden, statusErr.StatusCode)
+}
@@ -1,6 +1,7 @@
 package errorgroups
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/highlight-run/highlight/backend/model"
@@ -27,3 +28,39 @@ func IsFrameChromeExtension(frame privateModel.ErrorTrace) bool {
 func IsFrameFirefoxExtension(frame privateModel.ErrorTrace) bool {
 	return frame.FileName != nil && strings.HasPrefix(*frame.FileName, "moz-extension")
 }
+
+// GetIgnoringRule returns the first of the ignore rules that matches an error, or nil if the error
+// is not ignored.
+func GetIgnoringRule(rules []*model.ErrorIgnoreRule, errorObj *model.ErrorObject) *model.ErrorIgnoreRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	message := GetErrorMessage(errorObj.Event)
+	types := []string{GetExceptionType(message), errorObj.Type}
+	for _, rule := range rules {
+		if matchesRegex(rule.TypeRegex, types...) &&
+			matchesRegex(rule.MessageRegex, message) &&
+			matchesRegex(rule.BrowserRegex, errorObj.Browser) &&
+			matchesRegex(rule.URLRegex, errorObj.URL) &&
+			matchesRegex(rule.SourceRegex, errorObj.Source) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matchesRegex returns whether an unset regex or any of the non-empty values match.
+func matchesRegex(regex *string, values ...string) bool {
+	if regex == nil || *regex == "" {
+		return true
+	}
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if matched, err := regexp.MatchString(*regex, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -98,3 +98,37 @@ func TestIsErrorTraceFilteredForFirefoxExtension(t *testing.T) {
 
 	assert.False(t, IsErrorTraceFiltered(project, nonChromeTrace))
 }
+
+func TestGetIgnoringRule(t *testing.T) {
+	extensionRule := &model.ErrorIgnoreRule{SourceRegex: ptr.String("^(chrome|moz)-extension://")}
+	botRule := &model.ErrorIgnoreRule{BrowserRegex: ptr.String("(?i)headless|bot"), TypeRegex: ptr.String("^TypeError$")}
+	rules := []*model.ErrorIgnoreRule{extensionRule, botRule}
+
+	assert.Equal(t, extensionRule, GetIgnoringRule(rules, &model.ErrorObject{
+		Event:  `["\"Uncaught Error: boom\""]`,
+		Source: "chrome-extension://abc/content.js",
+	}))
+	assert.Equal(t, botRule, GetIgnoringRule(rules, &model.ErrorObject{
+		Event:   `["\"TypeError: x is undefined\""]`,
+		Browser: "HeadlessChrome",
+	}))
+	// every set regex of a rule must match
+	assert.Nil(t, GetIgnoringRule(rules, &model.ErrorObject{
+		Event:   `["\"ReferenceError: x is not defined\""]`,
+		Browser: "HeadlessChrome",
+	}))
+	assert.Nil(t, GetIgnoringRule(rules, &model.ErrorObject{
+		Event:   `["\"TypeError: x is undefined\""]`,
+		Browser: "Chrome",
+	}))
+
+	// the type regex also matches the type of the error
+	typeRule := &model.ErrorIgnoreRule{TypeRegex: ptr.String("^console.error$"), URLRegex: ptr.String("/admin")}
+	assert.Equal(t, typeRule, GetIgnoringRule([]*model.ErrorIgnoreRule{typeRule}, &model.ErrorObject{
+		Event: "failed", Type: "console.error", URL: "https://app.example.com/admin/users",
+	}))
+
+	assert.Error(t, (&model.ErrorIgnoreRule{}).Validate())
+	assert.Error(t, (&model.ErrorIgnoreRule{MessageRegex: ptr.String("(")}).Validate())
+	assert.NoError(t, botRule.Validate())
+}
@@ -0,0 +1,98 @@
+package errorgroups
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	exceptionTypeRegex = regexp.MustCompile(`^(?:Uncaught\s+)?([A-Za-z_$][\w.$]*)\s*:`)
+	uuidRegex          = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexRegex           = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]*[0-9][0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*|[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*[0-9][0-9a-fA-F]*)\b`)
+	numberRegex        = regexp.MustCompile(`\d+(?:\.\d+)?`)
+)
+
+// GetGroupingRuleFingerprint returns the fingerprint of an error by the first of the rules that
+// matches it, or nil if the error is grouped by default. The fingerprint includes the rule so that
+// editing the type of a rule starts new error groups.
+func GetGroupingRuleFingerprint(projectID int, rules []*model.ErrorGroupingRule, errorObj *model.ErrorObject, errorTraces []*privateModel.ErrorTrace) *model.ErrorFingerprint {
+	message := GetErrorMessage(errorObj.Event)
+	for _, rule := range rules {
+		if !rule.Matches(message, errorObj.ServiceName) {
+			continue
+		}
+
+		var value string
+		switch rule.Type {
+		case model.ErrorGroupingRuleTypeExceptionType:
+			value = GetExceptionType(message)
+		case model.ErrorGroupingRuleTypeStrippedMessage:
+			value = StripMessageVariables(message)
+		case model.ErrorGroupingRuleTypeTopFrame:
+			value = getTopFrame(errorTraces)
+		}
+		// rules that cannot fingerprint the error, such as by the top frame of an error without a
+		// stack trace, leave it to the next rule
+		if value == "" {
+			continue
+		}
+
+		return &model.ErrorFingerprint{
+			ProjectID: projectID,
+			Type:      model.Fingerprint.GroupingRule,
+			Value:     fmt.Sprintf("%d:%s:%s", rule.ID, rule.Type, value),
+		}
+	}
+	return nil
+}
+
+// GetErrorMessage returns the message of an error event, which the frontend SDK sends as a JSON
+// array of JSON encoded console arguments.
+func GetErrorMessage(event string) string {
+	var args []string
+	if err := json.Unmarshal([]byte(event), &args); err != nil {
+		return event
+	}
+	for idx, arg := range args {
+		var decoded string
+		if err := json.Unmarshal([]byte(arg), &decoded); err == nil {
+			args[idx] = decoded
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// GetExceptionType returns the exception type that prefixes an error message, such as `TypeError`
+// for `Uncaught TypeError: x is undefined`, or an empty string if the message has none.
+func GetExceptionType(message string) string {
+	matches := exceptionTypeRegex.FindStringSubmatch(strings.TrimSpace(message))
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// StripMessageVariables replaces the UUIDs, hex ids and numbers of an error message with placeholders,
+// so that errors that only differ by them have the same message.
+func StripMessageVariables(message string) string {
+	message = uuidRegex.ReplaceAllString(message, "<uuid>")
+	message = hexRegex.ReplaceAllString(message, "<hex>")
+	return numberRegex.ReplaceAllString(message, "<number>")
+}
+
+func getTopFrame(errorTraces []*privateModel.ErrorTrace) string {
+	for _, frame := range errorTraces {
+		if frame == nil {
+			continue
+		}
+		if value := joinStringPtrs(frame.FileName, frame.FunctionName); value != "" {
+			return value
+		}
+	}
+	return ""
+}
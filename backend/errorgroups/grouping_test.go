@@ -0,0 +1,57 @@
+package errorgroups
+
+import (
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrorMessage(t *testing.T) {
+	assert.Equal(t, "TypeError: x is undefined", GetErrorMessage(`["\"TypeError: x is undefined\""]`))
+	assert.Equal(t, "failed to fetch 404", GetErrorMessage(`["\"failed to fetch\"","404"]`))
+	assert.Equal(t, "connection refused", GetErrorMessage("connection refused"))
+}
+
+func TestGetExceptionType(t *testing.T) {
+	assert.Equal(t, "TypeError", GetExceptionType("Uncaught TypeError: x is undefined"))
+	assert.Equal(t, "java.lang.NullPointerException", GetExceptionType("java.lang.NullPointerException: foo"))
+	assert.Equal(t, "", GetExceptionType("error querying project: connection refused"))
+	assert.Equal(t, "", GetExceptionType("connection refused"))
+}
+
+func TestStripMessageVariables(t *testing.T) {
+	assert.Equal(t, "user <uuid> not found", StripMessageVariables("user 9b2c5f0e-3c1a-4f7e-9d6b-1a2b3c4d5e6f not found"))
+	assert.Equal(t, "timeout after <number>s for request <hex>", StripMessageVariables("timeout after 2.5s for request 5f3a9c0e12"))
+	assert.Equal(t, "pointer <hex> is nil", StripMessageVariables("pointer 0xc000123abc is nil"))
+	assert.Equal(t, "deadline exceeded", StripMessageVariables("deadline exceeded"))
+}
+
+func TestGetGroupingRuleFingerprint(t *testing.T) {
+	rules := []*model.ErrorGroupingRule{
+		{Model: model.Model{ID: 1}, Type: model.ErrorGroupingRuleTypeTopFrame, ServiceName: ptr.String("worker")},
+		{Model: model.Model{ID: 2}, Type: model.ErrorGroupingRuleTypeExceptionType, EventRegex: ptr.String("^TypeError")},
+		{Model: model.Model{ID: 3}, Type: model.ErrorGroupingRuleTypeStrippedMessage, EventRegex: ptr.String("not found")},
+	}
+	traces := []*privateModel.ErrorTrace{
+		{},
+		{FileName: ptr.String("worker.go"), FunctionName: ptr.String("process"), LineNumber: ptr.Int(10)},
+	}
+
+	fingerprint := GetGroupingRuleFingerprint(1, rules, &model.ErrorObject{Event: "foo", ServiceName: "worker"}, traces)
+	assert.Equal(t, model.Fingerprint.GroupingRule, fingerprint.Type)
+	assert.Equal(t, "1:top_frame:worker.go;process;", fingerprint.Value)
+
+	// the top frame rule cannot fingerprint errors without a stack trace
+	fingerprint = GetGroupingRuleFingerprint(1, rules, &model.ErrorObject{Event: `["\"TypeError: a is undefined\""]`, ServiceName: "worker"}, nil)
+	assert.Equal(t, "2:exception_type:TypeError", fingerprint.Value)
+	other := GetGroupingRuleFingerprint(1, rules, &model.ErrorObject{Event: `["\"TypeError: b is null\""]`}, traces)
+	assert.Equal(t, fingerprint.Value, other.Value)
+
+	fingerprint = GetGroupingRuleFingerprint(1, rules, &model.ErrorObject{Event: "user 12 not found"}, traces)
+	assert.Equal(t, "3:stripped_message:user <number> not found", fingerprint.Value)
+
+	assert.Nil(t, GetGroupingRuleFingerprint(1, rules, &model.ErrorObject{Event: "connection refused"}, traces))
+}
@@ -0,0 +1,220 @@
+package githubissues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+	githubIntegration "github.com/highlight-run/highlight/backend/integrations/github"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/openlyinc/pointy"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultLabel is added to the issues created from error groups so that they can be filtered
+// in the repository.
+const DefaultLabel = "highlight"
+
+// WebhookSecretEnvVar is the secret of the GitHub app that signs its webhooks.
+const WebhookSecretEnvVar = "GITHUB_WEBHOOK_SECRET"
+
+var ErrNoRepository = errors.New("no GitHub repository is mapped to the project")
+
+// ProjectRepository returns the name of the repository of the installation that the issues of
+// the project are created in.
+func ProjectRepository(ctx context.Context, db *gorm.DB, projectID int) (string, error) {
+	var mapping model.IntegrationProjectMapping
+	if err := db.WithContext(ctx).Where(&model.IntegrationProjectMapping{
+		ProjectID:       projectID,
+		IntegrationType: modelInputs.IntegrationTypeGitHub,
+	}).Take(&mapping).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrNoRepository
+		}
+		return "", err
+	}
+	if mapping.ExternalID == "" {
+		return "", ErrNoRepository
+	}
+	return mapping.ExternalID, nil
+}
+
+// SetProjectRepository maps the project to a repository, or removes its mapping when the
+// repository is empty.
+func SetProjectRepository(ctx context.Context, db *gorm.DB, projectID int, repository string) error {
+	mapping := &model.IntegrationProjectMapping{
+		ProjectID:       projectID,
+		IntegrationType: modelInputs.IntegrationTypeGitHub,
+		ExternalID:      repository,
+	}
+	if repository == "" {
+		return db.WithContext(ctx).Where(&model.IntegrationProjectMapping{
+			ProjectID:       projectID,
+			IntegrationType: modelInputs.IntegrationTypeGitHub,
+		}).Delete(&model.IntegrationProjectMapping{}).Error
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "integration_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_id"}),
+	}).Create(mapping).Error
+}
+
+// IssueDetails are the details of an error added to the body of an issue.
+type IssueDetails struct {
+	Event      string
+	StackTrace string
+	ErrorURL   string
+	SessionURL string
+}
+
+// IssueBody returns the markdown body of an issue, with the details of the error appended to the
+// description when they are set.
+func IssueBody(description string, details *IssueDetails) string {
+	var body strings.Builder
+	body.WriteString(strings.TrimSpace(description))
+	if details == nil {
+		return body.String()
+	}
+
+	if details.Event != "" || details.StackTrace != "" {
+		body.WriteString("\n\n### Error")
+		if details.Event != "" {
+			body.WriteString(fmt.Sprintf("\n\n**%s**", strings.TrimSpace(details.Event)))
+		}
+		if details.StackTrace != "" {
+			fence := codeFence(details.StackTrace)
+			body.WriteString(fmt.Sprintf("\n\n%s\n%s\n%s", fence, strings.TrimRight(details.StackTrace, "\n"), fence))
+		}
+	}
+	var links []string
+	if details.ErrorURL != "" {
+		links = append(links, fmt.Sprintf("[View the error on Highlight](%s)", details.ErrorURL))
+	}
+	if details.SessionURL != "" {
+		links = append(links, fmt.Sprintf("[Watch the session](%s)", details.SessionURL))
+	}
+	if len(links) > 0 {
+		body.WriteString("\n\n" + strings.Join(links, " · "))
+	}
+	return body.String()
+}
+
+// codeFence returns a fence longer than any run of backticks in the code so that it cannot be
+// closed early.
+func codeFence(code string) string {
+	length, run := 3, 0
+	for _, c := range code {
+		if c != '`' {
+			run = 0
+			continue
+		}
+		if run++; run >= length {
+			length = run + 1
+		}
+	}
+	return strings.Repeat("`", length)
+}
+
+// Labels returns the labels of an issue created from an error group, which always include the
+// DefaultLabel.
+func Labels(tags ...string) []string {
+	labels := []string{DefaultLabel}
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			labels = append(labels, tag)
+		}
+	}
+	return lo.Uniq(labels)
+}
+
+type CreateIssueInput struct {
+	Repository  string
+	Title       string
+	Description string
+	Labels      []string
+	Details     *IssueDetails
+}
+
+// CreateIssue creates a labeled issue with the details of the error in the repository.
+func CreateIssue(ctx context.Context, client githubIntegration.ClientInterface, input *CreateIssueInput) (*github.Issue, error) {
+	if input.Repository == "" {
+		return nil, ErrNoRepository
+	}
+	labels := Labels(input.Labels...)
+	issue, err := client.CreateIssue(ctx, input.Repository, &github.IssueRequest{
+		Title:  pointy.String(input.Title),
+		Body:   pointy.String(IssueBody(input.Description, input.Details)),
+		Labels: &labels,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating GitHub issue")
+	}
+	return issue, nil
+}
+
+// ParseWebhook verifies the signature of a webhook of the GitHub app and returns its issues
+// event, or nil for other events.
+func ParseWebhook(req *http.Request) (*github.IssuesEvent, error) {
+	secret := os.Getenv(WebhookSecretEnvVar)
+	if secret == "" {
+		return nil, errors.New(WebhookSecretEnvVar + " not set")
+	}
+	payload, err := github.ValidatePayload(req, []byte(secret))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid GitHub webhook signature")
+	}
+	event, err := github.ParseWebHook(github.WebHookType(req), payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid GitHub webhook payload")
+	}
+	issuesEvent, _ := event.(*github.IssuesEvent)
+	return issuesEvent, nil
+}
+
+// Backlink is an error group linked to a GitHub issue.
+type Backlink struct {
+	Title string
+	URL   string
+}
+
+// BacklinkComment returns the comment added to an issue closed by a commit, linking back to the
+// errors of the issue on Highlight.
+func BacklinkComment(commitID string, backlinks []*Backlink) string {
+	var comment strings.Builder
+	comment.WriteString(fmt.Sprintf("Closed by %s. Keep an eye on the errors of this issue on Highlight to confirm the fix:\n", commitID))
+	for _, backlink := range backlinks {
+		title := strings.TrimSpace(backlink.Title)
+		if title == "" {
+			title = backlink.URL
+		}
+		comment.WriteString(fmt.Sprintf("\n- [%s](%s)", strings.ReplaceAll(title, "\n", " "), backlink.URL))
+	}
+	return comment.String()
+}
+
+// CommentClosingCommit adds the backlink comment to the issue of a `closed` event when the issue
+// was closed by a commit. Returns whether the comment was added.
+func CommentClosingCommit(ctx context.Context, client githubIntegration.ClientInterface, event *github.IssuesEvent, backlinks []*Backlink) (bool, error) {
+	if event.GetAction() != "closed" || len(backlinks) == 0 {
+		return false, nil
+	}
+	repo, number := event.GetRepo().GetName(), event.GetIssue().GetNumber()
+	commitID, err := client.GetIssueClosingCommit(ctx, repo, number)
+	if err != nil {
+		return false, errors.Wrap(err, "error querying the closing commit of the GitHub issue")
+	}
+	if commitID == "" {
+		return false, nil
+	}
+	if err := client.CreateIssueComment(ctx, repo, number, BacklinkComment(commitID, backlinks)); err != nil {
+		return false, errors.Wrap(err, "error commenting on the GitHub issue")
+	}
+	return true, nil
+}
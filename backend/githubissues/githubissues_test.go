@@ -0,0 +1,116 @@
+package githubissues
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	githubIntegration "github.com/highlight-run/highlight/backend/integrations/github"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClient struct {
+	githubIntegration.ClientInterface
+	closingCommit string
+	issueRequest  *github.IssueRequest
+	comments      []string
+}
+
+func (c *fakeClient) CreateIssue(_ context.Context, repo string, issueRequest *github.IssueRequest) (*github.Issue, error) {
+	c.issueRequest = issueRequest
+	return &github.Issue{Title: issueRequest.Title, HTMLURL: pointy.String("https://github.com/highlight/" + repo + "/issues/1")}, nil
+}
+
+func (c *fakeClient) CreateIssueComment(_ context.Context, _ string, _ int, body string) error {
+	c.comments = append(c.comments, body)
+	return nil
+}
+
+func (c *fakeClient) GetIssueClosingCommit(context.Context, string, int) (string, error) {
+	return c.closingCommit, nil
+}
+
+func TestIssueBody(t *testing.T) {
+	assert.Equal(t, "Checkout is broken", IssueBody(" Checkout is broken\n", nil))
+
+	body := IssueBody("Checkout is broken", &IssueDetails{
+		Event:      "TypeError: cannot read 'id'",
+		StackTrace: "at checkout (cart.js:10)\n```",
+		ErrorURL:   "https://app.highlight.io/1/errors/abc",
+		SessionURL: "https://app.highlight.io/1/sessions/def",
+	})
+	assert.Equal(t, "Checkout is broken\n\n### Error\n\n**TypeError: cannot read 'id'**\n\n````\nat checkout (cart.js:10)\n```\n````\n\n"+
+		"[View the error on Highlight](https://app.highlight.io/1/errors/abc) · [Watch the session](https://app.highlight.io/1/sessions/def)", body)
+}
+
+func TestCreateIssue(t *testing.T) {
+	client := &fakeClient{}
+	_, err := CreateIssue(context.TODO(), client, &CreateIssueInput{Title: "Checkout is broken"})
+	assert.ErrorIs(t, err, ErrNoRepository)
+
+	issue, err := CreateIssue(context.TODO(), client, &CreateIssueInput{
+		Repository: "frontend",
+		Title:      "Checkout is broken",
+		Labels:     []string{"bug", "highlight", " "},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/highlight/frontend/issues/1", issue.GetHTMLURL())
+	assert.Equal(t, []string{"highlight", "bug"}, client.issueRequest.GetLabels())
+}
+
+func TestParseWebhook(t *testing.T) {
+	t.Setenv(WebhookSecretEnvVar, "secret")
+	payload := []byte(`{"action":"closed","issue":{"number":1,"html_url":"https://github.com/highlight/frontend/issues/1"},"repository":{"name":"frontend"}}`)
+
+	request := func(signature string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/github-webhook", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", "issues")
+		req.Header.Set("X-Hub-Signature-256", signature)
+		return req
+	}
+
+	_, err := ParseWebhook(request("sha256=invalid"))
+	assert.Error(t, err)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	event, err := ParseWebhook(request("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+	assert.NoError(t, err)
+	assert.Equal(t, "closed", event.GetAction())
+	assert.Equal(t, "frontend", event.GetRepo().GetName())
+}
+
+func TestCommentClosingCommit(t *testing.T) {
+	event := &github.IssuesEvent{
+		Action: pointy.String("closed"),
+		Issue:  &github.Issue{Number: pointy.Int(1)},
+		Repo:   &github.Repository{Name: pointy.String("frontend")},
+	}
+	backlinks := []*Backlink{{Title: "TypeError: cannot read 'id'", URL: "https://app.highlight.io/1/errors/abc"}}
+
+	// closed without a commit
+	client := &fakeClient{}
+	commented, err := CommentClosingCommit(context.TODO(), client, event, backlinks)
+	assert.NoError(t, err)
+	assert.False(t, commented)
+
+	client.closingCommit = "0987654321"
+	commented, err = CommentClosingCommit(context.TODO(), client, event, backlinks)
+	assert.NoError(t, err)
+	assert.True(t, commented)
+	assert.Equal(t, []string{"Closed by 0987654321. Keep an eye on the errors of this issue on Highlight to confirm the fix:\n\n" +
+		"- [TypeError: cannot read 'id'](https://app.highlight.io/1/errors/abc)"}, client.comments)
+
+	event.Action = pointy.String("reopened")
+	commented, err = CommentClosingCommit(context.TODO(), client, event, backlinks)
+	assert.NoError(t, err)
+	assert.False(t, commented)
+}
@@ -0,0 +1,84 @@
+package heartbeat
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/public-graph/graph"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	MonitorIDParam     = "monitor_id"
+	StatusQueryParam   = "status"
+	DurationQueryParam = "duration"
+)
+
+type Handler struct {
+	resolver *graph.Resolver
+}
+
+// HandleCheckIn records a check-in for the heartbeat monitor identified by the secure id in the url.
+// Jobs may optionally report `?status=ok|error|in_progress` and `?duration=<ms>`.
+func (h *Handler) HandleCheckIn(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	secureID := chi.URLParam(r, MonitorIDParam)
+
+	monitor, err := h.resolver.Store.GetHeartbeatMonitorBySecureID(ctx, secureID)
+	if err != nil || monitor == nil {
+		http.Error(w, "heartbeat monitor not found", http.StatusNotFound)
+		return
+	}
+
+	status := r.URL.Query().Get(StatusQueryParam)
+	switch status {
+	case "":
+		status = model.HeartbeatCheckInStatusOK
+	case model.HeartbeatCheckInStatusOK, model.HeartbeatCheckInStatusError, model.HeartbeatCheckInStatusInProgress:
+	default:
+		http.Error(w, "invalid status", http.StatusBadRequest)
+		return
+	}
+
+	row := clickhouse.NewHeartbeatCheckInRow(time.Now(), monitor.ProjectID, monitor.ID, status)
+	if duration := r.URL.Query().Get(DurationQueryParam); duration != "" {
+		ms, err := strconv.ParseUint(duration, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid duration", http.StatusBadRequest)
+			return
+		}
+		row.DurationMs = uint32(ms)
+	}
+
+	err = h.resolver.BatchedQueue.Submit(ctx, "", &kafkaqueue.Message{
+		Type: kafkaqueue.PushHeartbeatCheckIn,
+		PushHeartbeatCheckIn: &kafkaqueue.PushHeartbeatCheckInArgs{
+			CheckInRow: row,
+		},
+	})
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("monitor_id", monitor.ID).Error("failed to submit heartbeat check in")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) Listen(r *chi.Mux) {
+	r.Route("/heartbeats", func(r chi.Router) {
+		r.Get("/{monitor_id}", h.HandleCheckIn)
+		r.Post("/{monitor_id}", h.HandleCheckIn)
+	})
+}
+
+func New(resolver *graph.Resolver) *Handler {
+	return &Handler{
+		resolver: resolver,
+	}
+}
@@ -0,0 +1,119 @@
+package amplitude
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+// Region is the data center of the Amplitude project, which serves the api of its region.
+type Region string
+
+const (
+	RegionUS Region = "us"
+	RegionEU Region = "eu"
+)
+
+// ApiBaseUrls are the HTTP V2 api hosts of each region.
+var ApiBaseUrls = map[Region]string{
+	RegionUS: "https://api2.amplitude.com",
+	RegionEU: "https://api.eu.amplitude.com",
+}
+
+func (r Region) IsValid() bool {
+	_, ok := ApiBaseUrls[r]
+	return ok
+}
+
+const requestTimeout = 30 * time.Second
+
+// maxBatchEvents is the number of events sent per request, below the 2000 events limit of the api.
+// See https://www.docs.developers.amplitude.com/analytics/apis/http-v2-api/
+const maxBatchEvents = 1000
+
+// Client sends events to the HTTP V2 api of an Amplitude project with its api key.
+type Client struct {
+	apiKey     string
+	region     Region
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, region Region) *Client {
+	if region == "" {
+		region = RegionUS
+	}
+	return &Client{apiKey: apiKey, region: region, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type event struct {
+	UserID          string         `json:"user_id,omitempty"`
+	DeviceID        string         `json:"device_id,omitempty"`
+	EventType       string         `json:"event_type"`
+	Time            int64          `json:"time"`
+	InsertID        string         `json:"insert_id,omitempty"`
+	EventProperties map[string]any `json:"event_properties,omitempty"`
+}
+
+func (c *Client) sendBatch(ctx context.Context, events []*analytics.Event) error {
+	batch := make([]*event, 0, len(events))
+	for _, e := range events {
+		batch = append(batch, &event{
+			UserID:          e.UserID,
+			DeviceID:        e.DeviceID,
+			EventType:       e.Name,
+			Time:            e.Time.UnixMilli(),
+			InsertID:        e.InsertID,
+			EventProperties: e.Properties,
+		})
+	}
+	body, err := json.Marshal(map[string]any{"api_key": c.apiKey, "events": batch})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ApiBaseUrls[c.region]+"/2/httpapi", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating api request to Amplitude")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "*/*")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error getting response from Amplitude endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading response body from Amplitude endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("Amplitude API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+	return nil
+}
+
+// SendEvents sends events in batches. Amplitude drops events without a user or device id, as
+// well as events with an insert id it received in the last 7 days.
+func (c *Client) SendEvents(ctx context.Context, events []*analytics.Event) error {
+	if c.apiKey == "" {
+		return errors.New("Amplitude api key is not set")
+	}
+	for start := 0; start < len(events); start += maxBatchEvents {
+		end := start + maxBatchEvents
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := c.sendBatch(ctx, events[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package amplitude
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SendEvents(t *testing.T) {
+	var batches [][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2/httpapi", r.URL.Path)
+		var body struct {
+			APIKey string           `json:"api_key"`
+			Events []map[string]any `json:"events"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.APIKey != "key" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400,"error":"Invalid API key: invalid"}`))
+			return
+		}
+		batches = append(batches, body.Events)
+		_, _ = w.Write([]byte(`{"code":200}`))
+	}))
+	defer server.Close()
+
+	baseUrl := ApiBaseUrls[RegionEU]
+	ApiBaseUrls[RegionEU] = server.URL
+	defer func() { ApiBaseUrls[RegionEU] = baseUrl }()
+
+	ctx := context.Background()
+	timestamp := time.UnixMilli(1700000000000)
+	events := make([]*analytics.Event, maxBatchEvents+1)
+	for idx := range events {
+		events[idx] = &analytics.Event{Name: analytics.RageClickEventName, UserID: "chilly@highlight.io", Time: timestamp, InsertID: "insert-id", Properties: map[string]any{"total_clicks": 5}}
+	}
+
+	assert.NoError(t, NewClient("key", RegionEU).SendEvents(ctx, events))
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], maxBatchEvents)
+	assert.Equal(t, map[string]any{
+		"user_id":          "chilly@highlight.io",
+		"event_type":       analytics.RageClickEventName,
+		"time":             float64(1700000000000),
+		"insert_id":        "insert-id",
+		"event_properties": map[string]any{"total_clicks": float64(5)},
+	}, batches[1][0])
+
+	assert.Error(t, NewClient("invalid", RegionEU).SendEvents(ctx, events[:1]))
+	assert.Error(t, NewClient("", RegionEU).SendEvents(ctx, events[:1]))
+}
@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+// The names of the session events that are exported to product analytics tools.
+const (
+	IdentifiedEventName = "Highlight Session Identified"
+	RageClickEventName  = "Highlight Rage Click"
+)
+
+// Destination is the product analytics tool that events are exported to.
+type Destination string
+
+const (
+	DestinationAmplitude Destination = "amplitude"
+	DestinationMixpanel  Destination = "mixpanel"
+)
+
+func (d Destination) IsValid() bool {
+	return d == DestinationAmplitude || d == DestinationMixpanel
+}
+
+// DefaultUserIDProperty maps the identifier of a session to the user id of its events.
+const DefaultUserIDProperty = "identifier"
+
+// Event is a session event exported to a product analytics project. Events of sessions that were
+// not identified are attributed to the device of the session.
+type Event struct {
+	Name     string
+	UserID   string
+	DeviceID string
+	Time     time.Time
+	// InsertID deduplicates events that are exported more than once.
+	InsertID   string
+	Properties map[string]any
+}
+
+// Client sends events to a product analytics project.
+type Client interface {
+	SendEvents(ctx context.Context, events []*Event) error
+}
+
+// SessionURL is the replay link of a session, starting at a timestamp when it is set.
+func SessionURL(frontendURL string, session *model.Session, timestamp *time.Time) string {
+	u := fmt.Sprintf("%s/%d/sessions/%s", frontendURL, session.ProjectID, session.SecureID)
+	if timestamp != nil {
+		u += "?tsAbs=" + strconv.FormatInt(timestamp.UnixMilli(), 10)
+	}
+	return u
+}
+
+// UserID returns the user id of a session's events. The property is `identifier`, `email` or the
+// name of a user property of the session; sessions without it fall back to their identifier.
+func UserID(session *model.Session, userIDProperty string) string {
+	switch userIDProperty {
+	case "", DefaultUserIDProperty:
+	case "email":
+		if session.Email != nil && *session.Email != "" {
+			return *session.Email
+		}
+	default:
+		if userProperties, err := session.GetUserProperties(); err == nil && userProperties[userIDProperty] != "" {
+			return userProperties[userIDProperty]
+		}
+	}
+	return session.Identifier
+}
+
+func sessionEvent(name string, session *model.Session, userIDProperty string, frontendURL string, timestamp *time.Time) *Event {
+	event := &Event{
+		Name:     name,
+		UserID:   UserID(session, userIDProperty),
+		DeviceID: session.ClientID,
+		Time:     session.CreatedAt,
+		Properties: map[string]any{
+			"highlight_session_id": session.SecureID,
+			"highlight_replay_url": SessionURL(frontendURL, session, timestamp),
+		},
+	}
+	if timestamp != nil {
+		event.Time = *timestamp
+	}
+	return event
+}
+
+// IdentifiedSessionEvent is the event of a session that was identified, with the user properties
+// of the session.
+func IdentifiedSessionEvent(session *model.Session, userIDProperty string, frontendURL string) *Event {
+	event := sessionEvent(IdentifiedEventName, session, userIDProperty, frontendURL, nil)
+	event.InsertID = fmt.Sprintf("highlight-identified-%d", session.ID)
+	if userProperties, err := session.GetUserProperties(); err == nil {
+		for name, value := range userProperties {
+			if _, ok := event.Properties[name]; !ok {
+				event.Properties[name] = value
+			}
+		}
+	}
+	return event
+}
+
+// RageClickEvent is the event of a rage click, linking to the replay of the session where the
+// rage click started.
+func RageClickEvent(rageClick *model.RageClickEvent, session *model.Session, userIDProperty string, frontendURL string) *Event {
+	event := sessionEvent(RageClickEventName, session, userIDProperty, frontendURL, &rageClick.StartTimestamp)
+	event.InsertID = fmt.Sprintf("highlight-rage-click-%d", rageClick.ID)
+	event.Properties["total_clicks"] = rageClick.TotalClicks
+	event.Properties["duration_ms"] = rageClick.EndTimestamp.Sub(rageClick.StartTimestamp).Milliseconds()
+	return event
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserID(t *testing.T) {
+	email := "chilly@highlight.io"
+	session := &model.Session{Identifier: "user-1", Email: &email, UserProperties: `{"account_id":"acct-1"}`}
+
+	assert.Equal(t, "user-1", UserID(session, ""))
+	assert.Equal(t, "user-1", UserID(session, DefaultUserIDProperty))
+	assert.Equal(t, email, UserID(session, "email"))
+	assert.Equal(t, "acct-1", UserID(session, "account_id"))
+	assert.Equal(t, "user-1", UserID(session, "missing"))
+}
+
+func TestRageClickEvent(t *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	session := &model.Session{Model: model.Model{ID: 1}, ProjectID: 2, SecureID: "abc", ClientID: "device", Identifier: "user-1"}
+	rageClick := &model.RageClickEvent{Model: model.Model{ID: 3}, TotalClicks: 8, StartTimestamp: start, EndTimestamp: start.Add(2 * time.Second)}
+
+	event := RageClickEvent(rageClick, session, "", "https://app.highlight.io")
+	assert.Equal(t, &Event{
+		Name:     RageClickEventName,
+		UserID:   "user-1",
+		DeviceID: "device",
+		Time:     start,
+		InsertID: "highlight-rage-click-3",
+		Properties: map[string]any{
+			"highlight_session_id": "abc",
+			"highlight_replay_url": "https://app.highlight.io/2/sessions/abc?tsAbs=1700000000000",
+			"total_clicks":         8,
+			"duration_ms":          int64(2000),
+		},
+	}, event)
+}
+
+func TestIdentifiedSessionEvent(t *testing.T) {
+	created := time.UnixMilli(1700000000000)
+	session := &model.Session{Model: model.Model{ID: 1, CreatedAt: created}, ProjectID: 2, SecureID: "abc", Identifier: "user-1", UserProperties: `{"plan":"enterprise","highlight_session_id":"ignored"}`}
+
+	event := IdentifiedSessionEvent(session, "plan", "https://app.highlight.io")
+	assert.Equal(t, "enterprise", event.UserID)
+	assert.Equal(t, created, event.Time)
+	assert.Equal(t, "highlight-identified-1", event.InsertID)
+	assert.Equal(t, map[string]any{
+		"highlight_session_id": "abc",
+		"highlight_replay_url": "https://app.highlight.io/2/sessions/abc",
+		"plan":                 "enterprise",
+	}, event.Properties)
+}
@@ -0,0 +1,229 @@
+package asana
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+var (
+	AsanaAuthBaseUrl = "https://app.asana.com/-"
+	AsanaApiBaseUrl  = "https://app.asana.com/api/1.0"
+)
+
+const requestTimeout = 10 * time.Second
+
+// pageSize is the page size of the Asana collections that are listed.
+const pageSize = 100
+
+// taskFields are the fields of the tasks returned by the api.
+const taskFields = "name,permalink_url,completed"
+
+func GetOAuthConfig() (*oauth2.Config, []oauth2.AuthCodeOption, error) {
+	var (
+		ok                bool
+		asanaClientID     string
+		asanaClientSecret string
+		frontendUri       string
+	)
+	if asanaClientID, ok = os.LookupEnv("ASANA_CLIENT_ID"); !ok || asanaClientID == "" {
+		return nil, nil, errors.New("ASANA_CLIENT_ID not set")
+	}
+	if asanaClientSecret, ok = os.LookupEnv("ASANA_CLIENT_SECRET"); !ok || asanaClientSecret == "" {
+		return nil, nil, errors.New("ASANA_CLIENT_SECRET not set")
+	}
+	if frontendUri, ok = os.LookupEnv("REACT_APP_FRONTEND_URI"); !ok || frontendUri == "" {
+		return nil, nil, errors.New("REACT_APP_FRONTEND_URI not set")
+	}
+
+	return &oauth2.Config{
+		ClientID:     asanaClientID,
+		ClientSecret: asanaClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   fmt.Sprintf("%s/oauth_authorize", AsanaAuthBaseUrl),
+			TokenURL:  fmt.Sprintf("%s/oauth_token", AsanaAuthBaseUrl),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: fmt.Sprintf("%s/callback/asana", frontendUri),
+	}, nil, nil
+}
+
+// GetRefreshToken exchanges the refresh token of an expired token, as Asana access tokens
+// expire after an hour.
+func GetRefreshToken(ctx context.Context, oldToken *oauth2.Token) (*oauth2.Token, error) {
+	conf, _, err := GetOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	token, err := conf.TokenSource(ctx, oldToken).Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "error refreshing Asana access token")
+	}
+	return token, nil
+}
+
+// Client calls the Asana api with the access token of a workspace.
+type Client struct {
+	accessToken string
+	httpClient  *http.Client
+}
+
+func NewClient(accessToken string) *Client {
+	return &Client{accessToken: accessToken, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type nextPage struct {
+	Offset string `json:"offset"`
+}
+
+// response is the envelope of the Asana api responses.
+type response[T any] struct {
+	Data     T         `json:"data"`
+	NextPage *nextPage `json:"next_page"`
+}
+
+func doRequest[T any](ctx context.Context, c *Client, method string, path string, query url.Values, data any) (*response[T], error) {
+	var body io.Reader
+	if data != nil {
+		b, err := json.Marshal(map[string]any{"data": data})
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	u := AsanaApiBaseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request to Asana")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting response from Asana endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body from Asana endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, errors.New("Asana API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+
+	var unmarshalled response[T]
+	if err := json.Unmarshal(b, &unmarshalled); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Asana response")
+	}
+	return &unmarshalled, nil
+}
+
+// getPaginated returns all the pages of a collection.
+func getPaginated[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	var data []T
+	query.Set("limit", fmt.Sprint(pageSize))
+	for {
+		res, err := doRequest[[]T](ctx, c, http.MethodGet, path, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, res.Data...)
+		if res.NextPage == nil || res.NextPage.Offset == "" {
+			return data, nil
+		}
+		query.Set("offset", res.NextPage.Offset)
+	}
+}
+
+type Workspace struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+func (c *Client) GetWorkspaces(ctx context.Context) ([]*Workspace, error) {
+	return getPaginated[*Workspace](ctx, c, "/workspaces", url.Values{})
+}
+
+type Project struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+// GetProjects returns the projects of a workspace that are not archived.
+func (c *Client) GetProjects(ctx context.Context, workspaceGID string) ([]*Project, error) {
+	return getPaginated[*Project](ctx, c, "/projects", url.Values{
+		"workspace": {workspaceGID},
+		"archived":  {"false"},
+	})
+}
+
+type Task struct {
+	GID          string `json:"gid"`
+	Name         string `json:"name"`
+	PermalinkURL string `json:"permalink_url"`
+	Completed    bool   `json:"completed"`
+}
+
+type CreateTaskInput struct {
+	Name     string   `json:"name"`
+	Notes    string   `json:"notes,omitempty"`
+	Projects []string `json:"projects"`
+}
+
+func (c *Client) CreateTask(ctx context.Context, input *CreateTaskInput) (*Task, error) {
+	res, err := doRequest[*Task](ctx, c, http.MethodPost, "/tasks", url.Values{"opt_fields": {taskFields}}, input)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, gid string) (*Task, error) {
+	res, err := doRequest[*Task](ctx, c, http.MethodGet, "/tasks/"+url.PathEscape(gid), url.Values{"opt_fields": {taskFields}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// maxSearchTasks caps the tasks returned by a search of a workspace.
+const maxSearchTasks = 20
+
+// SearchTasks returns the tasks of a workspace whose name matches the query. The typeahead api is
+// used as the search api is only available to premium workspaces.
+func (c *Client) SearchTasks(ctx context.Context, workspaceGID string, query string) ([]*Task, error) {
+	res, err := doRequest[[]*Task](ctx, c, http.MethodGet, "/workspaces/"+url.PathEscape(workspaceGID)+"/typeahead", url.Values{
+		"resource_type": {"task"},
+		"query":         {query},
+		"count":         {fmt.Sprint(maxSearchTasks)},
+		"opt_fields":    {taskFields},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// CreateComment adds a comment to the activity of a task.
+func (c *Client) CreateComment(ctx context.Context, taskGID string, text string) error {
+	_, err := doRequest[map[string]any](ctx, c, http.MethodPost, "/tasks/"+url.PathEscape(taskGID)+"/stories", nil, map[string]string{"text": text})
+	return err
+}
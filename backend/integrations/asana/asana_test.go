@@ -0,0 +1,64 @@
+package asana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/workspaces":
+			_, _ = w.Write([]byte(`{"data":[{"gid":"1","name":"Acme"}],"next_page":null}`))
+		case "/projects":
+			assert.Equal(t, "1", r.URL.Query().Get("workspace"))
+			if r.URL.Query().Get("offset") == "" {
+				_, _ = w.Write([]byte(`{"data":[{"gid":"2","name":"Bugs"}],"next_page":{"offset":"next"}}`))
+			} else {
+				_, _ = w.Write([]byte(`{"data":[{"gid":"3","name":"Support"}],"next_page":null}`))
+			}
+		case "/tasks":
+			var body struct {
+				Data map[string]any `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			created = body.Data
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data":{"gid":"4","name":"TypeError","permalink_url":"https://app.asana.com/0/2/4","completed":false}}`))
+		case "/tasks/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"task: Unknown object: missing"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := AsanaApiBaseUrl
+	AsanaApiBaseUrl = server.URL
+	defer func() { AsanaApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("token")
+
+	workspaces, err := client.GetWorkspaces(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Workspace{{GID: "1", Name: "Acme"}}, workspaces)
+
+	projects, err := client.GetProjects(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Project{{GID: "2", Name: "Bugs"}, {GID: "3", Name: "Support"}}, projects)
+
+	task, err := client.CreateTask(ctx, &CreateTaskInput{Name: "TypeError", Notes: "desc", Projects: []string{"2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &Task{GID: "4", Name: "TypeError", PermalinkURL: "https://app.asana.com/0/2/4"}, task)
+	assert.Equal(t, map[string]any{"name": "TypeError", "notes": "desc", "projects": []any{"2"}}, created)
+
+	_, err = client.GetTask(ctx, "missing")
+	assert.ErrorContains(t, err, "Unknown object")
+}
@@ -0,0 +1,27 @@
+package crm
+
+import "context"
+
+// Contact is the account information of a CRM contact that identified sessions are enriched with.
+type Contact struct {
+	Company string
+	Plan    string
+	ARR     string
+}
+
+// Properties returns the session user properties of the contact's non empty fields.
+func (c *Contact) Properties() map[string]string {
+	properties := map[string]string{}
+	for name, value := range map[string]string{"company": c.Company, "plan": c.Plan, "arr": c.ARR} {
+		if value != "" {
+			properties[name] = value
+		}
+	}
+	return properties
+}
+
+// Client looks up contacts in a CRM.
+type Client interface {
+	// FindContact returns the contact with an email, or nil if the CRM does not have one.
+	FindContact(ctx context.Context, email string) (*Contact, error)
+}
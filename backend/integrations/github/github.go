@@ -55,12 +55,16 @@ func parseInstallation(installation string) (int64, error) {
 
 type ClientInterface interface {
 	CreateIssue(ctx context.Context, repo string, issueRequest *github.IssueRequest) (*github.Issue, error)
+	CreateIssueComment(ctx context.Context, repo string, number int, body string) error
+	GetIssueClosingCommit(ctx context.Context, repo string, number int) (string, error)
 	ListLabels(ctx context.Context, repo string) ([]*github.Label, error)
 	ListRepos(ctx context.Context) ([]*github.Repository, error)
 	DeleteInstallation(ctx context.Context, installation string) error
 	GetRepoContent(ctx context.Context, githubPath string, path string, version string) (fileContent *github.RepositoryContent, directoryContent []*github.RepositoryContent, resp *github.Response, err error)
 	GetRepoBlob(ctx context.Context, githubPath string, blobSHA string) (*github.Blob, *github.Response, error)
 	GetLatestCommitHash(ctx context.Context, githubPath string) (string, *github.Response, error)
+	CompareCommits(ctx context.Context, githubPath string, base string, head string) ([]*github.RepositoryCommit, error)
+	GetCommit(ctx context.Context, githubPath string, sha string) (*github.RepositoryCommit, error)
 }
 
 type Client struct {
@@ -155,6 +159,44 @@ func (c *Client) CreateIssue(ctx context.Context, repo string, issueRequest *git
 	return issue, err
 }
 
+func (c *Client) CreateIssueComment(ctx context.Context, repo string, number int, body string) error {
+	owner, err := c.GetInstallationOwner(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.client.Issues.CreateComment(ctx, *owner, repo, number, &github.IssueComment{Body: pointy.String(body)})
+	return err
+}
+
+// GetIssueClosingCommit returns the sha of the commit that last closed an issue, or an empty string
+// when the issue was not closed by a commit.
+func (c *Client) GetIssueClosingCommit(ctx context.Context, repo string, number int) (string, error) {
+	owner, err := c.GetInstallationOwner(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	events, err := getPaginated(func(page int) ([]*github.IssueEvent, *int, *bool, error) {
+		list, _, err := c.client.Issues.ListIssueEvents(ctx, *owner, repo, number, &github.ListOptions{Page: page})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return list, nil, pointy.Bool(len(list) > 0), nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var commitID string
+	for _, event := range events {
+		if event.GetEvent() == "closed" {
+			commitID = event.GetCommitID()
+		}
+	}
+	return commitID, nil
+}
+
 func (c *Client) ListLabels(ctx context.Context, repo string) ([]*github.Label, error) {
 	return getPaginated(func(page int) ([]*github.Label, *int, *bool, error) {
 		owner, err := c.GetInstallationOwner(ctx)
@@ -207,3 +249,20 @@ func (c *Client) GetLatestCommitHash(ctx context.Context, githubPath string) (st
 	repoPath := strings.Split(githubPath, "/")
 	return c.client.Repositories.GetCommitSHA1(ctx, repoPath[0], repoPath[1], "HEAD", "")
 }
+
+// CompareCommits returns the commits after base up to head, oldest first.
+func (c *Client) CompareCommits(ctx context.Context, githubPath string, base string, head string) ([]*github.RepositoryCommit, error) {
+	repoPath := strings.Split(githubPath, "/")
+	comparison, _, err := c.client.Repositories.CompareCommits(ctx, repoPath[0], repoPath[1], base, head, &github.ListOptions{PerPage: 250})
+	if err != nil {
+		return nil, err
+	}
+	return comparison.Commits, nil
+}
+
+// GetCommit returns a commit along with the files that it changed.
+func (c *Client) GetCommit(ctx context.Context, githubPath string, sha string) (*github.RepositoryCommit, error) {
+	repoPath := strings.Split(githubPath, "/")
+	commit, _, err := c.client.Repositories.GetCommit(ctx, repoPath[0], repoPath[1], sha, nil)
+	return commit, err
+}
@@ -364,3 +364,70 @@ func RevokeGitlabAccessToken(accessToken string) error {
 
 	return nil
 }
+
+type GitlabRepoFile struct {
+	FileName string `json:"file_name"`
+	FilePath string `json:"file_path"`
+	Encoding string `json:"encoding"`
+	// Content is base64 encoded, as the content of github repository files.
+	Content  string `json:"content"`
+	CommitID string `json:"commit_id"`
+}
+
+type GitlabCommit struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	WebURL      string `json:"web_url"`
+}
+
+type GitlabComparison struct {
+	Commits []*GitlabCommit `json:"commits"`
+}
+
+type GitlabDiff struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+}
+
+// GetRepoFile returns a file of a project repository at a commit. The project is the path of the
+// repository, such as `group/project`.
+func GetRepoFile(accessToken string, project string, filePath string, ref string) (*GitlabRepoFile, error) {
+	url := fmt.Sprintf("%s/projects/%s/repository/files/%s?ref=%s", GitlabApiBaseUrl, nUrl.PathEscape(project), nUrl.PathEscape(strings.TrimPrefix(filePath, "/")), nUrl.QueryEscape(ref))
+	return doGitlabGetRequest[*GitlabRepoFile](accessToken, url)
+}
+
+// GetLatestCommitHash returns the latest commit of the default branch of a project repository.
+func GetLatestCommitHash(accessToken string, project string) (string, error) {
+	url := fmt.Sprintf("%s/projects/%s/repository/commits?per_page=1", GitlabApiBaseUrl, nUrl.PathEscape(project))
+	commits, err := doGitlabGetRequest[[]*GitlabCommit](accessToken, url)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", errors.New("gitlab repository has no commits")
+	}
+	return commits[0].ID, nil
+}
+
+// CompareCommits returns the commits of a project repository after from up to to, oldest first.
+func CompareCommits(accessToken string, project string, from string, to string) ([]*GitlabCommit, error) {
+	url := fmt.Sprintf("%s/projects/%s/repository/compare?from=%s&to=%s", GitlabApiBaseUrl, nUrl.PathEscape(project), nUrl.QueryEscape(from), nUrl.QueryEscape(to))
+	comparison, err := doGitlabGetRequest[*GitlabComparison](accessToken, url)
+	if err != nil {
+		return nil, err
+	}
+	return comparison.Commits, nil
+}
+
+// GetCommitDiff returns the files that a commit of a project repository changed.
+func GetCommitDiff(accessToken string, project string, sha string) ([]*GitlabDiff, error) {
+	url := fmt.Sprintf("%s/projects/%s/repository/commits/%s/diff?per_page=100", GitlabApiBaseUrl, nUrl.PathEscape(project), nUrl.PathEscape(sha))
+	return doGitlabGetRequest[[]*GitlabDiff](accessToken, url)
+}
+
+// GetFileLink returns the permalink to a line of a repository file at a commit.
+func GetFileLink(project string, ref string, filePath string, line int) string {
+	return fmt.Sprintf("%s/%s/-/blob/%s/%s#L%d", GitlabAuthBaseUrl, project, ref, strings.TrimPrefix(filePath, "/"), line)
+}
@@ -0,0 +1,189 @@
+package hubspot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/crm"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+// This package connects the HubSpot account of a workspace to enrich sessions with its contacts.
+// The backend/hubspot package syncs Highlight's own customers to Highlight's HubSpot account.
+
+var (
+	HubSpotAuthBaseUrl = "https://app.hubspot.com"
+	HubSpotApiBaseUrl  = "https://api.hubapi.com"
+)
+
+const requestTimeout = 10 * time.Second
+
+// companyProperties are the properties of the company of a contact that are returned. `plan` is a
+// custom property that accounts can add, and `hs_arr` is only set with the Sales Hub, otherwise the
+// annual revenue of the company is used as its ARR.
+var companyProperties = []string{"name", "plan", "hs_arr", "annualrevenue"}
+
+func GetOAuthConfig() (*oauth2.Config, []oauth2.AuthCodeOption, error) {
+	var (
+		ok                  bool
+		hubspotClientID     string
+		hubspotClientSecret string
+		frontendUri         string
+	)
+	if hubspotClientID, ok = os.LookupEnv("HUBSPOT_CLIENT_ID"); !ok || hubspotClientID == "" {
+		return nil, nil, errors.New("HUBSPOT_CLIENT_ID not set")
+	}
+	if hubspotClientSecret, ok = os.LookupEnv("HUBSPOT_CLIENT_SECRET"); !ok || hubspotClientSecret == "" {
+		return nil, nil, errors.New("HUBSPOT_CLIENT_SECRET not set")
+	}
+	if frontendUri, ok = os.LookupEnv("REACT_APP_FRONTEND_URI"); !ok || frontendUri == "" {
+		return nil, nil, errors.New("REACT_APP_FRONTEND_URI not set")
+	}
+
+	return &oauth2.Config{
+		ClientID:     hubspotClientID,
+		ClientSecret: hubspotClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   fmt.Sprintf("%s/oauth/authorize", HubSpotAuthBaseUrl),
+			TokenURL:  fmt.Sprintf("%s/oauth/v1/token", HubSpotApiBaseUrl),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: fmt.Sprintf("%s/callback/hubspot", frontendUri),
+		Scopes:      []string{"crm.objects.contacts.read", "crm.objects.companies.read"},
+	}, nil, nil
+}
+
+// GetRefreshToken exchanges the refresh token of an expired token, as HubSpot access tokens
+// expire after 30 minutes.
+func GetRefreshToken(ctx context.Context, oldToken *oauth2.Token) (*oauth2.Token, error) {
+	conf, _, err := GetOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	token, err := conf.TokenSource(ctx, oldToken).Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "error refreshing HubSpot access token")
+	}
+	return token, nil
+}
+
+type Client struct {
+	accessToken string
+	httpClient  *http.Client
+}
+
+func NewClient(accessToken string) *Client {
+	return &Client{accessToken: accessToken, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+func (c *Client) doRequest(ctx context.Context, method string, path string, query url.Values, data any, result any) error {
+	var body io.Reader
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	u := HubSpotApiBaseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return errors.Wrap(err, "error creating api request to HubSpot")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error getting response from HubSpot endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading response body from HubSpot endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("HubSpot API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+	if err := json.Unmarshal(b, result); err != nil {
+		return errors.Wrap(err, "error unmarshaling HubSpot response")
+	}
+	return nil
+}
+
+type object struct {
+	ID           string            `json:"id"`
+	Properties   map[string]string `json:"properties"`
+	Associations map[string]struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+	} `json:"associations"`
+}
+
+// FindContact returns the company of the contact with an email. Contacts without a company are
+// returned with the name of the company set on the contact.
+func (c *Client) FindContact(ctx context.Context, email string) (*crm.Contact, error) {
+	var search struct {
+		Results []*object `json:"results"`
+	}
+	if err := c.doRequest(ctx, http.MethodPost, "/crm/v3/objects/contacts/search", nil, map[string]any{
+		"filterGroups": []map[string]any{{
+			"filters": []map[string]string{{"propertyName": "email", "operator": "EQ", "value": email}},
+		}},
+		"properties": []string{"email", "company"},
+		"limit":      1,
+	}, &search); err != nil {
+		return nil, err
+	}
+	if len(search.Results) == 0 {
+		return nil, nil
+	}
+
+	var contact object
+	if err := c.doRequest(ctx, http.MethodGet, "/crm/v3/objects/contacts/"+url.PathEscape(search.Results[0].ID), url.Values{
+		"properties":   {"company"},
+		"associations": {"companies"},
+	}, nil, &contact); err != nil {
+		return nil, err
+	}
+	result := &crm.Contact{Company: contact.Properties["company"]}
+	companies := contact.Associations["companies"].Results
+	if len(companies) == 0 {
+		return result, nil
+	}
+
+	var company object
+	query := url.Values{}
+	for _, property := range companyProperties {
+		query.Add("properties", property)
+	}
+	if err := c.doRequest(ctx, http.MethodGet, "/crm/v3/objects/companies/"+url.PathEscape(companies[0].ID), query, nil, &company); err != nil {
+		return nil, err
+	}
+	if name := company.Properties["name"]; name != "" {
+		result.Company = name
+	}
+	result.Plan = company.Properties["plan"]
+	result.ARR = company.Properties["hs_arr"]
+	if result.ARR == "" {
+		result.ARR = company.Properties["annualrevenue"]
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package hubspot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/integrations/crm"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FindContact(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/crm/v3/objects/contacts/search":
+			var body struct {
+				FilterGroups []struct {
+					Filters []map[string]string `json:"filters"`
+				} `json:"filterGroups"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			switch body.FilterGroups[0].Filters[0]["value"] {
+			case "chilly@highlight.io":
+				_, _ = w.Write([]byte(`{"total":1,"results":[{"id":"1","properties":{"email":"chilly@highlight.io"}}]}`))
+			case "solo@highlight.io":
+				_, _ = w.Write([]byte(`{"total":1,"results":[{"id":"2","properties":{"email":"solo@highlight.io"}}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"total":0,"results":[]}`))
+			}
+		case "/crm/v3/objects/contacts/1":
+			assert.Equal(t, "companies", r.URL.Query().Get("associations"))
+			_, _ = w.Write([]byte(`{"id":"1","properties":{"company":"Acme Inc"},"associations":{"companies":{"results":[{"id":"10","type":"contact_to_company"}]}}}`))
+		case "/crm/v3/objects/contacts/2":
+			_, _ = w.Write([]byte(`{"id":"2","properties":{"company":"Solo LLC"}}`))
+		case "/crm/v3/objects/companies/10":
+			assert.Equal(t, companyProperties, r.URL.Query()["properties"])
+			_, _ = w.Write([]byte(`{"id":"10","properties":{"name":"Acme","plan":"enterprise","hs_arr":null,"annualrevenue":"500000"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := HubSpotApiBaseUrl
+	HubSpotApiBaseUrl = server.URL
+	defer func() { HubSpotApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("token")
+
+	contact, err := client.FindContact(ctx, "chilly@highlight.io")
+	assert.NoError(t, err)
+	assert.Equal(t, &crm.Contact{Company: "Acme", Plan: "enterprise", ARR: "500000"}, contact)
+
+	contact, err = client.FindContact(ctx, "solo@highlight.io")
+	assert.NoError(t, err)
+	assert.Equal(t, &crm.Contact{Company: "Solo LLC"}, contact)
+
+	contact, err = client.FindContact(ctx, "unknown@highlight.io")
+	assert.NoError(t, err)
+	assert.Nil(t, contact)
+}
@@ -2,13 +2,19 @@ package integrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/highlight-run/highlight/backend/integrations/asana"
 	"github.com/highlight-run/highlight/backend/integrations/gitlab"
 	"github.com/highlight-run/highlight/backend/integrations/height"
+	"github.com/highlight-run/highlight/backend/integrations/hubspot"
 	"github.com/highlight-run/highlight/backend/integrations/jira"
+	"github.com/highlight-run/highlight/backend/integrations/monday"
+	"github.com/highlight-run/highlight/backend/integrations/salesforce"
 	"github.com/highlight-run/highlight/backend/model"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"golang.org/x/oauth2"
@@ -43,6 +49,22 @@ func getOAuthConfig(integrationType modelInputs.IntegrationType) (*oauth2.Config
 		return gitlab.GetOAuthConfig()
 	}
 
+	if integrationType == modelInputs.IntegrationTypeAsana {
+		return asana.GetOAuthConfig()
+	}
+
+	if integrationType == modelInputs.IntegrationTypeMonday {
+		return monday.GetOAuthConfig()
+	}
+
+	if integrationType == modelInputs.IntegrationTypeSalesforce {
+		return salesforce.GetOAuthConfig()
+	}
+
+	if integrationType == modelInputs.IntegrationTypeHubSpot {
+		return hubspot.GetOAuthConfig()
+	}
+
 	return nil, nil, fmt.Errorf("invalid integrationType: %s", integrationType)
 }
 
@@ -59,6 +81,22 @@ func getRefreshOAuthToken(ctx context.Context, oldToken *oauth2.Token, integrati
 		return gitlab.GetRefreshToken(ctx, oldToken)
 	}
 
+	if integrationType == modelInputs.IntegrationTypeAsana {
+		return asana.GetRefreshToken(ctx, oldToken)
+	}
+
+	if integrationType == modelInputs.IntegrationTypeMonday {
+		return monday.GetRefreshToken(ctx, oldToken)
+	}
+
+	if integrationType == modelInputs.IntegrationTypeSalesforce {
+		return salesforce.GetRefreshToken(ctx, oldToken)
+	}
+
+	if integrationType == modelInputs.IntegrationTypeHubSpot {
+		return hubspot.GetRefreshToken(ctx, oldToken)
+	}
+
 	return nil, fmt.Errorf("invalid integrationType: %s", integrationType)
 }
 
@@ -71,6 +109,11 @@ func (c *Client) setWorkspaceToken(workspace *model.Workspace, integrationType m
 		Expiry:          token.Expiry,
 	}
 
+	// Salesforce tokens are returned without an expiry, so they are refreshed after the session timeout.
+	if integrationType == modelInputs.IntegrationTypeSalesforce && integrationWorkspaceMapping.Expiry.IsZero() {
+		integrationWorkspaceMapping.Expiry = time.Now().Add(salesforce.SessionTimeout)
+	}
+
 	if err := c.db.Clauses(clause.OnConflict{
 		UpdateAll: true,
 	}).Create(integrationWorkspaceMapping).Error; err != nil {
@@ -179,3 +222,58 @@ func (c *Client) IsProjectIntegrated(ctx context.Context, project *model.Project
 
 	return true, nil
 }
+
+// WorkspaceTokenStore keeps the oauth token of an integration of a workspace in its
+// integration workspace mapping.
+type WorkspaceTokenStore struct {
+	client          *Client
+	workspace       *model.Workspace
+	integrationType modelInputs.IntegrationType
+}
+
+func (c *Client) WorkspaceTokenStore(workspace *model.Workspace, integrationType modelInputs.IntegrationType) *WorkspaceTokenStore {
+	return &WorkspaceTokenStore{client: c, workspace: workspace, integrationType: integrationType}
+}
+
+// GetToken returns the token of the workspace mapping. ClickUp workspaces connected before their
+// tokens were stored in the mappings fall back to the access token of the workspace.
+func (s *WorkspaceTokenStore) GetToken(ctx context.Context) (*oauth2.Token, error) {
+	workspaceMapping := &model.IntegrationWorkspaceMapping{}
+	if err := s.client.db.WithContext(ctx).Where(&model.IntegrationWorkspaceMapping{
+		WorkspaceID:     s.workspace.ID,
+		IntegrationType: s.integrationType,
+	}).Take(&workspaceMapping).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if s.integrationType == modelInputs.IntegrationTypeClickUp && s.workspace.ClickupAccessToken != nil && *s.workspace.ClickupAccessToken != "" {
+			return &oauth2.Token{AccessToken: *s.workspace.ClickupAccessToken}, nil
+		}
+		return nil, nil
+	}
+
+	return &oauth2.Token{
+		AccessToken:  workspaceMapping.AccessToken,
+		RefreshToken: workspaceMapping.RefreshToken,
+		Expiry:       workspaceMapping.Expiry,
+	}, nil
+}
+
+// SetToken writes the token to the workspace mapping. The access token of a ClickUp workspace is
+// also kept up to date, as it marks the workspace as connected to ClickUp.
+func (s *WorkspaceTokenStore) SetToken(ctx context.Context, token *oauth2.Token) error {
+	if err := s.client.setWorkspaceToken(s.workspace, s.integrationType, token); err != nil {
+		return err
+	}
+
+	if s.integrationType == modelInputs.IntegrationTypeClickUp {
+		if err := s.client.db.WithContext(ctx).Where(&model.Workspace{Model: model.Model{ID: s.workspace.ID}}).
+			Select("clickup_access_token").
+			Updates(&model.Workspace{ClickupAccessToken: &token.AccessToken}).Error; err != nil {
+			return err
+		}
+		s.workspace.ClickupAccessToken = &token.AccessToken
+	}
+
+	return nil
+}
@@ -3,11 +3,13 @@ package integrations
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/highlight-run/highlight/backend/model"
 	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
 )
 
 func TestIsProjectIntegrated(t *testing.T) {
@@ -36,3 +38,38 @@ func TestIsProjectIntegrated(t *testing.T) {
 
 	})
 }
+
+func TestWorkspaceTokenStore(t *testing.T) {
+	util.RunTestWithDBWipe(t, client.db, func(t *testing.T) {
+		ctx := context.Background()
+		legacyToken := "legacy-token"
+		workspace := model.Workspace{ClickupAccessToken: &legacyToken}
+		err := client.db.Create(&workspace).Error
+		assert.NoError(t, err)
+
+		store := client.WorkspaceTokenStore(&workspace, privateModel.IntegrationTypeClickUp)
+		token, err := store.GetToken(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, legacyToken, token.AccessToken)
+		assert.Empty(t, token.RefreshToken)
+
+		expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+		err = store.SetToken(ctx, &oauth2.Token{AccessToken: "access-token", RefreshToken: "refresh-token", Expiry: expiry})
+		assert.NoError(t, err)
+
+		token, err = store.GetToken(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", token.AccessToken)
+		assert.Equal(t, "refresh-token", token.RefreshToken)
+		assert.True(t, expiry.Equal(token.Expiry))
+
+		var updated model.Workspace
+		err = client.db.Where(&model.Workspace{Model: model.Model{ID: workspace.ID}}).Take(&updated).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", *updated.ClickupAccessToken)
+
+		token, err = client.WorkspaceTokenStore(&workspace, privateModel.IntegrationTypeHeight).GetToken(ctx)
+		assert.NoError(t, err)
+		assert.Nil(t, token)
+	})
+}
@@ -0,0 +1,197 @@
+package intercom
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+var (
+	IntercomAuthBaseUrl = "https://app.intercom.com"
+	IntercomApiBaseUrl  = "https://api.intercom.io"
+)
+
+const requestTimeout = 10 * time.Second
+
+// SignatureHeader is the header of the hex encoded HMAC-SHA256 signature of the body of canvas
+// requests, signed with the client secret of the app.
+const SignatureHeader = "X-Body-Signature"
+
+func GetOAuthConfig() (*oauth2.Config, []oauth2.AuthCodeOption, error) {
+	var (
+		ok                   bool
+		intercomClientID     string
+		intercomClientSecret string
+		frontendUri          string
+	)
+	if intercomClientID, ok = os.LookupEnv("INTERCOM_CLIENT_ID"); !ok || intercomClientID == "" {
+		return nil, nil, errors.New("INTERCOM_CLIENT_ID not set")
+	}
+	if intercomClientSecret, ok = os.LookupEnv("INTERCOM_CLIENT_SECRET"); !ok || intercomClientSecret == "" {
+		return nil, nil, errors.New("INTERCOM_CLIENT_SECRET not set")
+	}
+	if frontendUri, ok = os.LookupEnv("REACT_APP_FRONTEND_URI"); !ok || frontendUri == "" {
+		return nil, nil, errors.New("REACT_APP_FRONTEND_URI not set")
+	}
+
+	return &oauth2.Config{
+		ClientID:     intercomClientID,
+		ClientSecret: intercomClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   fmt.Sprintf("%s/oauth", IntercomAuthBaseUrl),
+			TokenURL:  fmt.Sprintf("%s/auth/eagle/token", IntercomApiBaseUrl),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: fmt.Sprintf("%s/callback/intercom", frontendUri),
+	}, nil, nil
+}
+
+// GetWorkspaceID returns the id of the Intercom workspace that authorized an access token, which
+// identifies the workspace in canvas requests.
+func GetWorkspaceID(ctx context.Context, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, IntercomApiBaseUrl+"/me", nil)
+	if err != nil {
+		return "", errors.Wrap(err, "error creating api request to Intercom")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	req.Header.Set("Accept", "application/json")
+
+	res, err := (&http.Client{Timeout: requestTimeout}).Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "error getting response from Intercom endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "error reading response body from Intercom endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return "", errors.New("Intercom API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+
+	var me struct {
+		App struct {
+			IDCode string `json:"id_code"`
+		} `json:"app"`
+	}
+	if err := json.Unmarshal(b, &me); err != nil {
+		return "", errors.Wrap(err, "error unmarshaling Intercom response")
+	}
+	if me.App.IDCode == "" {
+		return "", errors.New("Intercom response does not have a workspace id")
+	}
+	return me.App.IDCode, nil
+}
+
+// VerifySignature checks the signature of the body of a canvas request.
+func VerifySignature(body []byte, signature string) error {
+	secret, ok := os.LookupEnv("INTERCOM_CLIENT_SECRET")
+	if !ok || secret == "" {
+		return errors.New("INTERCOM_CLIENT_SECRET not set")
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return errors.New("invalid Intercom request signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return errors.New("invalid Intercom request signature")
+	}
+	return nil
+}
+
+type contact struct {
+	Email string `json:"email"`
+}
+
+// CanvasRequest is the request sent by Intercom to initialize the canvas of the app in a conversation.
+type CanvasRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Contact     *contact `json:"contact"`
+	Customer    *contact `json:"customer"`
+}
+
+// Email returns the email of the contact of the conversation.
+func (r *CanvasRequest) Email() string {
+	if r.Contact != nil && r.Contact.Email != "" {
+		return r.Contact.Email
+	}
+	if r.Customer != nil {
+		return r.Customer.Email
+	}
+	return ""
+}
+
+// Component is a Canvas Kit component. See https://developers.intercom.com/docs/canvas-kit
+type Component struct {
+	Type  string  `json:"type"`
+	Text  string  `json:"text,omitempty"`
+	Style string  `json:"style,omitempty"`
+	Items []*Item `json:"items,omitempty"`
+}
+
+// Item is an item of a list component.
+type Item struct {
+	Type     string     `json:"type"`
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Subtitle string     `json:"subtitle,omitempty"`
+	Action   *URLAction `json:"action,omitempty"`
+}
+
+type URLAction struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type CanvasResponse struct {
+	Canvas struct {
+		Content struct {
+			Components []*Component `json:"components"`
+		} `json:"content"`
+	} `json:"canvas"`
+}
+
+// ListItem is a link of a list of the canvas.
+type ListItem struct {
+	ID       string
+	Title    string
+	Subtitle string
+	URL      string
+}
+
+// NewListCanvas returns a canvas with a header and a list of links, or a muted text if there are none.
+func NewListCanvas(header string, empty string, items []*ListItem) *CanvasResponse {
+	components := []*Component{{Type: "text", Text: header, Style: "header"}}
+	if len(items) == 0 {
+		components = append(components, &Component{Type: "text", Text: empty, Style: "muted"})
+	} else {
+		list := &Component{Type: "list"}
+		for _, item := range items {
+			list.Items = append(list.Items, &Item{
+				Type:     "item",
+				ID:       item.ID,
+				Title:    item.Title,
+				Subtitle: item.Subtitle,
+				Action:   &URLAction{Type: "url", URL: item.URL},
+			})
+		}
+		components = append(components, list)
+	}
+
+	var res CanvasResponse
+	res.Canvas.Content.Components = components
+	return &res
+}
@@ -0,0 +1,73 @@
+package intercom
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWorkspaceID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/me", r.URL.Path)
+		_, _ = w.Write([]byte(`{"type":"admin","id":"1","app":{"type":"app","id_code":"abc123"}}`))
+	}))
+	defer server.Close()
+
+	baseUrl := IntercomApiBaseUrl
+	IntercomApiBaseUrl = server.URL
+	defer func() { IntercomApiBaseUrl = baseUrl }()
+
+	id, err := GetWorkspaceID(context.Background(), "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", id)
+
+	_, err = GetWorkspaceID(context.Background(), "invalid")
+	assert.Error(t, err)
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Setenv("INTERCOM_CLIENT_SECRET", "secret")
+	body := []byte(`{"workspace_id":"abc123"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+
+	assert.NoError(t, VerifySignature(body, hex.EncodeToString(mac.Sum(nil))))
+	assert.Error(t, VerifySignature([]byte(`{"workspace_id":"other"}`), hex.EncodeToString(mac.Sum(nil))))
+	assert.Error(t, VerifySignature(body, "not hex"))
+}
+
+func TestCanvasRequest_Email(t *testing.T) {
+	var req CanvasRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{"workspace_id":"abc123","customer":{"email":"customer@highlight.io"}}`), &req))
+	assert.Equal(t, "customer@highlight.io", req.Email())
+
+	req.Contact = &contact{Email: "contact@highlight.io"}
+	assert.Equal(t, "contact@highlight.io", req.Email())
+}
+
+func TestNewListCanvas(t *testing.T) {
+	b, err := json.Marshal(NewListCanvas("Sessions", "No sessions", []*ListItem{{ID: "1", Title: "Session", URL: "https://app.highlight.io/1/sessions/abc"}}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"canvas":{"content":{"components":[
+		{"type":"text","text":"Sessions","style":"header"},
+		{"type":"list","items":[{"type":"item","id":"1","title":"Session","action":{"type":"url","url":"https://app.highlight.io/1/sessions/abc"}}]}
+	]}}}`, string(b))
+
+	b, err = json.Marshal(NewListCanvas("Sessions", "No sessions", nil))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"canvas":{"content":{"components":[
+		{"type":"text","text":"Sessions","style":"header"},
+		{"type":"text","text":"No sessions","style":"muted"}
+	]}}}`, string(b))
+}
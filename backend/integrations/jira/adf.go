@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ADFNode is a node of a document in the Atlassian document format, which the v3 api uses for
+// rich text fields like the description of an issue.
+type ADFNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []*ADFMark     `json:"marks,omitempty"`
+	Content []*ADFNode     `json:"content,omitempty"`
+}
+
+type ADFMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// IssueDetails are the details of an error added to the description of an issue.
+type IssueDetails struct {
+	Event      string
+	StackTrace string
+	ErrorURL   string
+	SessionURL string
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s]+`)
+
+func adfText(text string, marks ...*ADFMark) *ADFNode {
+	return &ADFNode{Type: "text", Text: text, Marks: marks}
+}
+
+func adfLink(text string, url string) *ADFNode {
+	return adfText(text, &ADFMark{Type: "link", Attrs: map[string]any{"href": url}})
+}
+
+func adfHeading(text string) *ADFNode {
+	return &ADFNode{Type: "heading", Attrs: map[string]any{"level": 3}, Content: []*ADFNode{adfText(text)}}
+}
+
+// adfParagraph returns a paragraph of the lines of text, linking the urls in the text.
+func adfParagraph(text string) *ADFNode {
+	paragraph := &ADFNode{Type: "paragraph"}
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			paragraph.Content = append(paragraph.Content, &ADFNode{Type: "hardBreak"})
+		}
+		last := 0
+		for _, match := range urlPattern.FindAllStringIndex(line, -1) {
+			if match[0] > last {
+				paragraph.Content = append(paragraph.Content, adfText(line[last:match[0]]))
+			}
+			url := line[match[0]:match[1]]
+			paragraph.Content = append(paragraph.Content, adfLink(url, url))
+			last = match[1]
+		}
+		if last < len(line) {
+			paragraph.Content = append(paragraph.Content, adfText(line[last:]))
+		}
+	}
+	return paragraph
+}
+
+// IssueDescription returns the description of an issue as a document, with a paragraph per block
+// of the text followed by the details of the error when they are set.
+func IssueDescription(text string, details *IssueDetails) *ADFNode {
+	doc := &ADFNode{Type: "doc", Version: 1}
+	for _, block := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if block = strings.TrimSpace(block); block != "" {
+			doc.Content = append(doc.Content, adfParagraph(block))
+		}
+	}
+	if details == nil {
+		return doc
+	}
+
+	if details.Event != "" || details.StackTrace != "" {
+		doc.Content = append(doc.Content, adfHeading("Error"))
+		if details.Event != "" {
+			doc.Content = append(doc.Content, &ADFNode{Type: "paragraph", Content: []*ADFNode{adfText(details.Event, &ADFMark{Type: "strong"})}})
+		}
+		if details.StackTrace != "" {
+			doc.Content = append(doc.Content, &ADFNode{Type: "codeBlock", Content: []*ADFNode{adfText(details.StackTrace)}})
+		}
+	}
+	var links []*ADFNode
+	if details.ErrorURL != "" {
+		links = append(links, adfLink("View the error on Highlight", details.ErrorURL))
+	}
+	if details.SessionURL != "" {
+		if len(links) > 0 {
+			links = append(links, adfText(" · "))
+		}
+		links = append(links, adfLink("Watch the session", details.SessionURL))
+	}
+	if len(links) > 0 {
+		doc.Content = append(doc.Content, &ADFNode{Type: "paragraph", Content: links})
+	}
+	return doc
+}
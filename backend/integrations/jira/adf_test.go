@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueDescription(t *testing.T) {
+	doc := IssueDescription("Checkout fails\n\nSee the error page on Highlight:\nhttps://app.highlight.io/1/errors/abc", &IssueDetails{
+		Event:      "TypeError: x is undefined",
+		StackTrace: "at render (app.js:1:2)",
+		SessionURL: "https://app.highlight.io/1/sessions/def",
+	})
+
+	assert.Equal(t, "doc", doc.Type)
+	assert.Equal(t, 1, doc.Version)
+	assert.Len(t, doc.Content, 6)
+	assert.Equal(t, []*ADFNode{
+		adfText("See the error page on Highlight:"),
+		{Type: "hardBreak"},
+		adfLink("https://app.highlight.io/1/errors/abc", "https://app.highlight.io/1/errors/abc"),
+	}, doc.Content[1].Content)
+	assert.Equal(t, "heading", doc.Content[2].Type)
+	assert.Equal(t, "codeBlock", doc.Content[4].Type)
+	assert.Equal(t, []*ADFNode{adfLink("Watch the session", "https://app.highlight.io/1/sessions/def")}, doc.Content[5].Content)
+
+	doc = IssueDescription("", nil)
+	assert.Empty(t, doc.Content)
+}
+
+func TestJiraProjectKey(t *testing.T) {
+	assert.Equal(t, "ENG", JiraProjectKey("ENG-123"))
+	assert.Equal(t, "MY-PROJ", JiraProjectKey("MY-PROJ-1"))
+}
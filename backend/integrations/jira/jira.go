@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/segmentio/encoding/json"
 	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
@@ -55,7 +56,7 @@ type JiraIssueTypeData struct {
 }
 
 type JiraCreateIssueFields struct {
-	Description string               `json:"description"`
+	Description *ADFNode             `json:"description"`
 	Summary     string               `json:"summary"`
 	Project     JiraIssueProjectData `json:"project"`
 	IssueType   JiraIssueTypeData    `json:"issuetype"`
@@ -110,6 +111,16 @@ func doJiraPostRequest[TOut any, TIn any](accessToken string, url string, input
 	return doJiraRequest[TOut]("POST", accessToken, url, string(b))
 }
 
+func doJiraPutRequest[TOut any, TIn any](accessToken string, url string, input TIn) (TOut, error) {
+	var zero TOut
+	b, err := json.Marshal(input)
+	if err != nil {
+		return zero, err
+	}
+
+	return doJiraRequest[TOut]("PUT", accessToken, url, string(b))
+}
+
 func doJiraGetRequest[T any](accessToken string, url string) (T, error) {
 	return doJiraRequest[T]("GET", accessToken, url, "")
 }
@@ -175,6 +186,22 @@ func getJiraSiteFromAccessibleResources(responses []*modelInputs.AccessibleJiraR
 	return JiraSite, errors.New("No jira site found")
 }
 
+// GetJiraSites returns the sites that the token can create issues in.
+func GetJiraSites(accessToken string) ([]*modelInputs.AccessibleJiraResources, error) {
+	res, err := doJiraGetRequest[[]*modelInputs.AccessibleJiraResources](accessToken, "/oauth/token/accessible-resources")
+	if err != nil {
+		return nil, err
+	}
+
+	var sites []*modelInputs.AccessibleJiraResources
+	for _, site := range res {
+		if slices.Contains(site.Scopes, "write:jira-work") {
+			sites = append(sites, site)
+		}
+	}
+	return sites, nil
+}
+
 func GetJiraSite(accessToken string) (*modelInputs.AccessibleJiraResources, error) {
 	url := "/oauth/token/accessible-resources"
 	res, err := doJiraGetRequest[[]*modelInputs.AccessibleJiraResources](accessToken, url)
@@ -209,7 +236,7 @@ func MakeExternalIdForJiraTask(workspace *model.Workspace, issue *JiraIssue) str
 }
 
 func CreateJiraTask(workspace *model.Workspace, accessToken string, payload JiraCreateIssuePayload) (*JiraIssue, error) {
-	url := fmt.Sprintf("/ex/jira/%s/rest/api/2/issue", *workspace.JiraCloudID)
+	url := fmt.Sprintf("/ex/jira/%s/rest/api/3/issue", *workspace.JiraCloudID)
 	res, err := doJiraPostRequest[*JiraIssue](accessToken, url, payload)
 	if err != nil {
 		return nil, err
@@ -249,3 +276,181 @@ func GetRefreshToken(ctx context.Context, oldToken *oauth2.Token) (*oauth2.Token
 
 	return newToken, nil
 }
+
+// GetJiraIssueTypes returns the issue types that issues of a project can be created with.
+func GetJiraIssueTypes(workspace *model.Workspace, accessToken string, projectID string) ([]*modelInputs.JiraIssueType, error) {
+	type issueTypesResponse struct {
+		IssueTypes []*modelInputs.JiraIssueType `json:"issueTypes"`
+	}
+	url := fmt.Sprintf("/ex/jira/%s/rest/api/3/issue/createmeta/%s/issuetypes", *workspace.JiraCloudID, projectID)
+	res, err := doJiraGetRequest[issueTypesResponse](accessToken, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.IssueTypes, nil
+}
+
+// JiraIssueStatus is the status of an issue. The key of its category is `new`, `indeterminate` or `done`.
+type JiraIssueStatus struct {
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+func (s *JiraIssueStatus) Done() bool {
+	return s.StatusCategory.Key == "done"
+}
+
+type JiraIssueDetails struct {
+	Id     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string           `json:"summary"`
+		Status  *JiraIssueStatus `json:"status"`
+	} `json:"fields"`
+}
+
+func GetJiraIssue(workspace *model.Workspace, accessToken string, issueKey string) (*JiraIssueDetails, error) {
+	url := fmt.Sprintf("/ex/jira/%s/rest/api/3/issue/%s?fields=summary,status", *workspace.JiraCloudID, nUrl.PathEscape(issueKey))
+	return doJiraGetRequest[*JiraIssueDetails](accessToken, url)
+}
+
+// maxSearchIssues caps the issues returned by a search.
+const maxSearchIssues = 20
+
+// SearchJiraIssues returns the issues whose text contains the query, most recently updated first.
+func SearchJiraIssues(workspace *model.Workspace, accessToken string, query string) ([]*JiraIssueDetails, error) {
+	type searchResponse struct {
+		Issues []*JiraIssueDetails `json:"issues"`
+	}
+	query = strings.ReplaceAll(strings.TrimSpace(query), `"`, `\"`)
+	url := fmt.Sprintf("/ex/jira/%s/rest/api/3/search", *workspace.JiraCloudID)
+	res, err := doJiraPostRequest[searchResponse](accessToken, url, map[string]any{
+		"jql":        fmt.Sprintf(`text ~ "%s" ORDER BY updated DESC`, query),
+		"fields":     []string{"summary", "status"},
+		"maxResults": maxSearchIssues,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Issues, nil
+}
+
+// CreateJiraRemoteLink links an issue to a url, which Jira shows with the title in the issue links.
+func CreateJiraRemoteLink(workspace *model.Workspace, accessToken string, issueKey string, url string, title string) error {
+	type remoteLinkResponse struct {
+		Id int `json:"id"`
+	}
+	_, err := doJiraPostRequest[remoteLinkResponse](accessToken, fmt.Sprintf("/ex/jira/%s/rest/api/3/issue/%s/remotelink", *workspace.JiraCloudID, nUrl.PathEscape(issueKey)), map[string]any{
+		"object": map[string]any{"url": url, "title": title},
+	})
+	return err
+}
+
+// JiraWebhookEvent is the payload of the `jira:issue_updated` webhooks registered by RegisterJiraWebhook.
+type JiraWebhookEvent struct {
+	WebhookEvent      string            `json:"webhookEvent"`
+	MatchedWebhookIds []int             `json:"matchedWebhookIds"`
+	Issue             *JiraIssueDetails `json:"issue"`
+}
+
+// jiraWebhookJQL filters the webhooks of the issues of a project.
+func jiraWebhookJQL(projectKey string) string {
+	return fmt.Sprintf(`project = "%s"`, projectKey)
+}
+
+// RegisterJiraWebhook registers a webhook notifying url of the updates of the issues of a project.
+// Webhooks of oauth apps expire after 30 days, so the webhook of a project that is already
+// registered is refreshed instead.
+func RegisterJiraWebhook(workspace *model.Workspace, accessToken string, url string, projectKey string) error {
+	type webhook struct {
+		Id        int    `json:"id"`
+		JqlFilter string `json:"jqlFilter"`
+	}
+	type webhooksResponse struct {
+		Values []*webhook `json:"values"`
+		IsLast bool       `json:"isLast"`
+	}
+	baseUrl := fmt.Sprintf("/ex/jira/%s/rest/api/3/webhook", *workspace.JiraCloudID)
+	jql := jiraWebhookJQL(projectKey)
+	for startAt := 0; ; {
+		res, err := doJiraGetRequest[webhooksResponse](accessToken, fmt.Sprintf("%s?startAt=%d&maxResults=100", baseUrl, startAt))
+		if err != nil {
+			return err
+		}
+		for _, w := range res.Values {
+			if w.JqlFilter == jql {
+				_, err := doJiraPutRequest[map[string]any](accessToken, baseUrl+"/refresh", map[string]any{"webhookIds": []int{w.Id}})
+				return err
+			}
+		}
+		if res.IsLast || len(res.Values) == 0 {
+			break
+		}
+		startAt += len(res.Values)
+	}
+
+	type registrationResponse struct {
+		WebhookRegistrationResult []struct {
+			CreatedWebhookId int      `json:"createdWebhookId"`
+			Errors           []string `json:"errors"`
+		} `json:"webhookRegistrationResult"`
+	}
+	res, err := doJiraPostRequest[registrationResponse](accessToken, baseUrl, map[string]any{
+		"url": url,
+		"webhooks": []map[string]any{{
+			"events":    []string{"jira:issue_updated"},
+			"jqlFilter": jql,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, result := range res.WebhookRegistrationResult {
+		if len(result.Errors) > 0 {
+			return errors.Errorf("error registering Jira webhook: %s", strings.Join(result.Errors, "; "))
+		}
+	}
+	return nil
+}
+
+// ParseJiraWebhook verifies that a webhook request was sent by Jira, whose requests carry a jwt
+// signed with the client secret of the app, and returns its event.
+func ParseJiraWebhook(req *http.Request) (*JiraWebhookEvent, error) {
+	conf, _, err := GetOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token, ok = strings.CutPrefix(req.Header.Get("Authorization"), "JWT ")
+	}
+	if !ok {
+		return nil, errors.New("Jira webhook is missing an authorization token")
+	}
+	if _, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return []byte(conf.ClientSecret), nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "invalid Jira webhook authorization token")
+	}
+
+	var event JiraWebhookEvent
+	if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+		return nil, errors.Wrap(err, "error decoding Jira webhook")
+	}
+	return &event, nil
+}
+
+// JiraProjectKey returns the key of the project of an issue key, ie. `ENG` of `ENG-123`.
+func JiraProjectKey(issueKey string) string {
+	if i := strings.LastIndex(issueKey, "-"); i > 0 {
+		return issueKey[:i]
+	}
+	return issueKey
+}
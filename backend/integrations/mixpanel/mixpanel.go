@@ -0,0 +1,143 @@
+package mixpanel
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+// Region is the data residency of the Mixpanel project, which serves the api of its region.
+type Region string
+
+const (
+	RegionUS Region = "us"
+	RegionEU Region = "eu"
+)
+
+// ApiBaseUrls are the ingestion api hosts of each region.
+var ApiBaseUrls = map[Region]string{
+	RegionUS: "https://api.mixpanel.com",
+	RegionEU: "https://api-eu.mixpanel.com",
+}
+
+func (r Region) IsValid() bool {
+	_, ok := ApiBaseUrls[r]
+	return ok
+}
+
+const requestTimeout = 30 * time.Second
+
+// maxBatchEvents is the limit of events per request of the track api.
+// See https://developer.mixpanel.com/reference/track-event
+const maxBatchEvents = 2000
+
+// Client sends events to the track api of a Mixpanel project with its project token.
+type Client struct {
+	token      string
+	region     Region
+	httpClient *http.Client
+}
+
+func NewClient(token string, region Region) *Client {
+	if region == "" {
+		region = RegionUS
+	}
+	return &Client{token: token, region: region, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type event struct {
+	Event      string         `json:"event"`
+	Properties map[string]any `json:"properties"`
+}
+
+// toEvent maps an event onto a Mixpanel event. Events are attributed to the user, or to the device
+// of the session when it was not identified.
+func (c *Client) toEvent(e *analytics.Event) *event {
+	properties := make(map[string]any, len(e.Properties)+5)
+	for k, v := range e.Properties {
+		properties[k] = v
+	}
+	properties["token"] = c.token
+	properties["time"] = e.Time.UnixMilli()
+	if e.InsertID != "" {
+		properties["$insert_id"] = e.InsertID
+	}
+	if e.DeviceID != "" {
+		properties["$device_id"] = e.DeviceID
+	}
+	if e.UserID != "" {
+		properties["$user_id"] = e.UserID
+		properties["distinct_id"] = e.UserID
+	} else {
+		properties["distinct_id"] = "$device:" + e.DeviceID
+	}
+	return &event{Event: e.Name, Properties: properties}
+}
+
+func (c *Client) sendBatch(ctx context.Context, events []*analytics.Event) error {
+	batch := make([]*event, 0, len(events))
+	for _, e := range events {
+		batch = append(batch, c.toEvent(e))
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ApiBaseUrls[c.region]+"/track?verbose=1", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating api request to Mixpanel")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error getting response from Mixpanel endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading response body from Mixpanel endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("Mixpanel API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+
+	// the track api responds with 200 and a status of 0 when it rejects the events
+	var result struct {
+		Status int    `json:"status"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return errors.Wrap(err, "error unmarshaling Mixpanel response")
+	}
+	if result.Status != 1 {
+		return errors.New("Mixpanel API rejected events; error=" + result.Error)
+	}
+	return nil
+}
+
+// SendEvents sends events in batches. Mixpanel deduplicates events by their insert id.
+func (c *Client) SendEvents(ctx context.Context, events []*analytics.Event) error {
+	if c.token == "" {
+		return errors.New("Mixpanel project token is not set")
+	}
+	for start := 0; start < len(events); start += maxBatchEvents {
+		end := start + maxBatchEvents
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := c.sendBatch(ctx, events[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
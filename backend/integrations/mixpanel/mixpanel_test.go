@@ -0,0 +1,63 @@
+package mixpanel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SendEvents(t *testing.T) {
+	var events []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/track", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("verbose"))
+		var body []map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body[0]["properties"].(map[string]any)["token"] != "token" {
+			_, _ = w.Write([]byte(`{"status":0,"error":"token, missing or empty"}`))
+			return
+		}
+		events = append(events, body...)
+		_, _ = w.Write([]byte(`{"status":1,"error":null}`))
+	}))
+	defer server.Close()
+
+	baseUrl := ApiBaseUrls[RegionUS]
+	ApiBaseUrls[RegionUS] = server.URL
+	defer func() { ApiBaseUrls[RegionUS] = baseUrl }()
+
+	ctx := context.Background()
+	timestamp := time.UnixMilli(1700000000000)
+	assert.NoError(t, NewClient("token", "").SendEvents(ctx, []*analytics.Event{
+		{Name: analytics.IdentifiedEventName, UserID: "chilly@highlight.io", DeviceID: "device", Time: timestamp, InsertID: "identified", Properties: map[string]any{"plan": "enterprise"}},
+		{Name: analytics.RageClickEventName, DeviceID: "device", Time: timestamp},
+	}))
+	assert.Equal(t, []map[string]any{{
+		"event": analytics.IdentifiedEventName,
+		"properties": map[string]any{
+			"token":       "token",
+			"time":        float64(1700000000000),
+			"$insert_id":  "identified",
+			"$device_id":  "device",
+			"$user_id":    "chilly@highlight.io",
+			"distinct_id": "chilly@highlight.io",
+			"plan":        "enterprise",
+		},
+	}, {
+		"event": analytics.RageClickEventName,
+		"properties": map[string]any{
+			"token":       "token",
+			"time":        float64(1700000000000),
+			"$device_id":  "device",
+			"distinct_id": "$device:device",
+		},
+	}}, events)
+
+	assert.Error(t, NewClient("invalid", RegionUS).SendEvents(ctx, []*analytics.Event{{Name: analytics.RageClickEventName, Time: timestamp}}))
+}
@@ -0,0 +1,289 @@
+package monday
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+var (
+	MondayAuthBaseUrl = "https://auth.monday.com"
+	MondayApiBaseUrl  = "https://api.monday.com"
+)
+
+// apiVersion is the version of the Monday.com api that the queries are written for.
+const apiVersion = "2023-10"
+
+const requestTimeout = 10 * time.Second
+
+// pageSize is the page size of the boards that are listed.
+const pageSize = 100
+
+// doneLabel is the label of the default status column of a board that marks an item as done.
+const doneLabel = "Done"
+
+func GetOAuthConfig() (*oauth2.Config, []oauth2.AuthCodeOption, error) {
+	var (
+		ok                 bool
+		mondayClientID     string
+		mondayClientSecret string
+		frontendUri        string
+	)
+	if mondayClientID, ok = os.LookupEnv("MONDAY_CLIENT_ID"); !ok || mondayClientID == "" {
+		return nil, nil, errors.New("MONDAY_CLIENT_ID not set")
+	}
+	if mondayClientSecret, ok = os.LookupEnv("MONDAY_CLIENT_SECRET"); !ok || mondayClientSecret == "" {
+		return nil, nil, errors.New("MONDAY_CLIENT_SECRET not set")
+	}
+	if frontendUri, ok = os.LookupEnv("REACT_APP_FRONTEND_URI"); !ok || frontendUri == "" {
+		return nil, nil, errors.New("REACT_APP_FRONTEND_URI not set")
+	}
+
+	return &oauth2.Config{
+		ClientID:     mondayClientID,
+		ClientSecret: mondayClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   fmt.Sprintf("%s/oauth2/authorize", MondayAuthBaseUrl),
+			TokenURL:  fmt.Sprintf("%s/oauth2/token", MondayAuthBaseUrl),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: fmt.Sprintf("%s/callback/monday", frontendUri),
+		Scopes:      []string{"boards:read", "boards:write", "updates:write"},
+	}, nil, nil
+}
+
+// GetRefreshToken is never needed as Monday.com access tokens do not expire until the app is
+// uninstalled.
+func GetRefreshToken(ctx context.Context, oldToken *oauth2.Token) (*oauth2.Token, error) {
+	return nil, errors.New("Monday.com access tokens cannot be refreshed")
+}
+
+// Client calls the Monday.com graphql api with the access token of a workspace.
+type Client struct {
+	accessToken string
+	httpClient  *http.Client
+}
+
+func NewClient(accessToken string) *Client {
+	return &Client{accessToken: accessToken, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// doGraphQLRequest runs a graphql query and returns its data, including the errors of the query
+// that Monday.com responds to with a 200.
+func doGraphQLRequest[T any](ctx context.Context, c *Client, query string, variables map[string]any) (T, error) {
+	var res struct {
+		Data         T              `json:"data"`
+		Errors       []graphQLError `json:"errors"`
+		ErrorMessage string         `json:"error_message"`
+	}
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return res.Data, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", MondayApiBaseUrl+"/v2", bytes.NewReader(body))
+	if err != nil {
+		return res.Data, errors.Wrap(err, "error creating api request to Monday.com")
+	}
+	req.Header.Set("Authorization", c.accessToken)
+	req.Header.Set("API-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return res.Data, errors.Wrap(err, "error getting response from Monday.com graphql endpoint")
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return res.Data, errors.Wrap(err, "error reading response body from Monday.com graphql endpoint")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return res.Data, errors.New("Monday.com graphql API responded with error; status_code=" + resp.Status + "; body=" + string(b))
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return res.Data, errors.Wrap(err, "error unmarshaling Monday.com graphql response")
+	}
+	if res.ErrorMessage != "" {
+		return res.Data, errors.Errorf("Monday.com graphql API responded with error: %s", res.ErrorMessage)
+	}
+	if len(res.Errors) > 0 {
+		messages := make([]string, len(res.Errors))
+		for i, e := range res.Errors {
+			messages[i] = e.Message
+		}
+		return res.Data, errors.Errorf("Monday.com graphql API responded with error: %s", strings.Join(messages, "; "))
+	}
+	return res.Data, nil
+}
+
+type BoardWorkspace struct {
+	Name string `json:"name"`
+}
+
+type Board struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Workspace *BoardWorkspace `json:"workspace"`
+}
+
+// GetBoards returns the active boards of the account.
+func (c *Client) GetBoards(ctx context.Context) ([]*Board, error) {
+	type boardsResponse struct {
+		Boards []*Board `json:"boards"`
+	}
+	var boards []*Board
+	for page := 1; ; page++ {
+		res, err := doGraphQLRequest[boardsResponse](ctx, c, `
+		query boards($limit: Int!, $page: Int!) {
+			boards(limit: $limit, page: $page, state: active) {
+				id
+				name
+				workspace {
+					name
+				}
+			}
+		}
+		`, map[string]any{"limit": pageSize, "page": page})
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, res.Boards...)
+		if len(res.Boards) < pageSize {
+			return boards, nil
+		}
+	}
+}
+
+type ColumnValue struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Item is a row of a board. Its state is `active`, `archived` or `deleted`.
+type Item struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	URL          string         `json:"url"`
+	State        string         `json:"state"`
+	ColumnValues []*ColumnValue `json:"column_values"`
+}
+
+const itemFields = `
+	id
+	name
+	url
+	state
+	column_values(types: [status]) {
+		type
+		text
+	}
+`
+
+// Status returns the label of the first status column of the item.
+func (i *Item) Status() string {
+	for _, value := range i.ColumnValues {
+		if value.Type == "status" && value.Text != "" {
+			return value.Text
+		}
+	}
+	return ""
+}
+
+// Done returns whether the item is marked as done or is no longer active.
+func (i *Item) Done() bool {
+	return (i.State != "" && i.State != "active") || strings.EqualFold(i.Status(), doneLabel)
+}
+
+func (c *Client) CreateItem(ctx context.Context, boardID string, name string) (*Item, error) {
+	type createItemResponse struct {
+		CreateItem *Item `json:"create_item"`
+	}
+	res, err := doGraphQLRequest[createItemResponse](ctx, c, `
+	mutation createItem($boardId: ID!, $name: String!) {
+		create_item(board_id: $boardId, item_name: $name) {`+itemFields+`}
+	}
+	`, map[string]any{"boardId": boardID, "name": name})
+	if err != nil {
+		return nil, err
+	}
+	if res.CreateItem == nil {
+		return nil, errors.New("failed to create Monday.com item")
+	}
+	return res.CreateItem, nil
+}
+
+// CreateUpdate posts an update to an item, which is how descriptions and comments are added to items.
+func (c *Client) CreateUpdate(ctx context.Context, itemID string, body string) error {
+	_, err := doGraphQLRequest[map[string]any](ctx, c, `
+	mutation createUpdate($itemId: ID!, $body: String!) {
+		create_update(item_id: $itemId, body: $body) {
+			id
+		}
+	}
+	`, map[string]any{"itemId": itemID, "body": body})
+	return err
+}
+
+func (c *Client) GetItem(ctx context.Context, id string) (*Item, error) {
+	type itemsResponse struct {
+		Items []*Item `json:"items"`
+	}
+	res, err := doGraphQLRequest[itemsResponse](ctx, c, `
+	query items($ids: [ID!]) {
+		items(ids: $ids) {`+itemFields+`}
+	}
+	`, map[string]any{"ids": []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Items) == 0 {
+		return nil, errors.Errorf("Monday.com item %s not found", id)
+	}
+	return res.Items[0], nil
+}
+
+// maxSearchItems caps the items returned per board by a search.
+const maxSearchItems = 20
+
+// SearchItems returns the items of the boards whose name contains the query.
+func (c *Client) SearchItems(ctx context.Context, query string) ([]*Item, error) {
+	type searchResponse struct {
+		Boards []struct {
+			ItemsPage struct {
+				Items []*Item `json:"items"`
+			} `json:"items_page"`
+		} `json:"boards"`
+	}
+	res, err := doGraphQLRequest[searchResponse](ctx, c, `
+	query searchItems($boards: Int!, $limit: Int!, $query: CompareValue!) {
+		boards(limit: $boards, state: active) {
+			items_page(limit: $limit, query_params: {rules: [{column_id: "name", compare_value: $query, operator: contains_text}]}) {
+				items {`+itemFields+`}
+			}
+		}
+	}
+	`, map[string]any{"boards": pageSize, "limit": maxSearchItems, "query": strings.TrimSpace(query)})
+	if err != nil {
+		return nil, err
+	}
+	var items []*Item
+	for _, board := range res.Boards {
+		items = append(items, board.ItemsPage.Items...)
+	}
+	return items, nil
+}
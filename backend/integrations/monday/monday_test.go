@@ -0,0 +1,68 @@
+package monday
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var update map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.Equal(t, apiVersion, r.Header.Get("API-Version"))
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "create_item"):
+			_, _ = w.Write([]byte(`{"data":{"create_item":{"id":"10","name":"TypeError","url":"https://acme.monday.com/boards/1/pulses/10","state":"active","column_values":[]}}}`))
+		case strings.Contains(req.Query, "create_update"):
+			update = req.Variables
+			_, _ = w.Write([]byte(`{"data":{"create_update":{"id":"20"}}}`))
+		case strings.Contains(req.Query, "items(ids"):
+			if req.Variables["ids"].([]any)[0] == "missing" {
+				_, _ = w.Write([]byte(`{"data":{"items":[]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"items":[{"id":"10","name":"TypeError","state":"active","column_values":[{"type":"status","text":"Done"}]}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"error_message":"Not Authenticated","status_code":401}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := MondayApiBaseUrl
+	MondayApiBaseUrl = server.URL
+	defer func() { MondayApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("token")
+
+	item, err := client.CreateItem(ctx, "1", "TypeError")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://acme.monday.com/boards/1/pulses/10", item.URL)
+	assert.False(t, item.Done())
+
+	assert.NoError(t, client.CreateUpdate(ctx, item.ID, "desc"))
+	assert.Equal(t, map[string]any{"itemId": "10", "body": "desc"}, update)
+
+	item, err = client.GetItem(ctx, "10")
+	assert.NoError(t, err)
+	assert.Equal(t, "Done", item.Status())
+	assert.True(t, item.Done())
+
+	_, err = client.GetItem(ctx, "missing")
+	assert.Error(t, err)
+
+	_, err = client.GetBoards(ctx)
+	assert.ErrorContains(t, err, "Not Authenticated")
+}
@@ -0,0 +1,171 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/crm"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+var SalesforceLoginBaseUrl = "https://login.salesforce.com"
+
+// apiVersion is the version of the Salesforce REST api that the queries are written for.
+const apiVersion = "v59.0"
+
+const requestTimeout = 10 * time.Second
+
+// SessionTimeout is how long access tokens are used before they are refreshed. Salesforce does not
+// return the expiry of access tokens, which are valid for the session timeout of the org (2 hours by default).
+const SessionTimeout = time.Hour
+
+func GetOAuthConfig() (*oauth2.Config, []oauth2.AuthCodeOption, error) {
+	var (
+		ok                     bool
+		salesforceClientID     string
+		salesforceClientSecret string
+		frontendUri            string
+	)
+	if salesforceClientID, ok = os.LookupEnv("SALESFORCE_CLIENT_ID"); !ok || salesforceClientID == "" {
+		return nil, nil, errors.New("SALESFORCE_CLIENT_ID not set")
+	}
+	if salesforceClientSecret, ok = os.LookupEnv("SALESFORCE_CLIENT_SECRET"); !ok || salesforceClientSecret == "" {
+		return nil, nil, errors.New("SALESFORCE_CLIENT_SECRET not set")
+	}
+	if frontendUri, ok = os.LookupEnv("REACT_APP_FRONTEND_URI"); !ok || frontendUri == "" {
+		return nil, nil, errors.New("REACT_APP_FRONTEND_URI not set")
+	}
+
+	return &oauth2.Config{
+		ClientID:     salesforceClientID,
+		ClientSecret: salesforceClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   fmt.Sprintf("%s/services/oauth2/authorize", SalesforceLoginBaseUrl),
+			TokenURL:  fmt.Sprintf("%s/services/oauth2/token", SalesforceLoginBaseUrl),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: fmt.Sprintf("%s/callback/salesforce", frontendUri),
+		Scopes:      []string{"api", "refresh_token"},
+	}, nil, nil
+}
+
+func GetRefreshToken(ctx context.Context, oldToken *oauth2.Token) (*oauth2.Token, error) {
+	conf, _, err := GetOAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	token, err := conf.TokenSource(ctx, oldToken).Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "error refreshing Salesforce access token")
+	}
+	return token, nil
+}
+
+// Client queries the org of an access token. The url of the org's instance is looked up with the
+// first query, as the stored tokens do not keep the instance url returned with them.
+type Client struct {
+	accessToken string
+	instanceUrl string
+	httpClient  *http.Client
+}
+
+func NewClient(accessToken string) *Client {
+	return &Client{accessToken: accessToken, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+func (c *Client) get(ctx context.Context, u string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return errors.Wrap(err, "error creating api request to Salesforce")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error getting response from Salesforce endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading response body from Salesforce endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("Salesforce API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+	if err := json.Unmarshal(b, result); err != nil {
+		return errors.Wrap(err, "error unmarshaling Salesforce response")
+	}
+	return nil
+}
+
+func (c *Client) getInstanceUrl(ctx context.Context) (string, error) {
+	if c.instanceUrl != "" {
+		return c.instanceUrl, nil
+	}
+	var userInfo struct {
+		URLs struct {
+			Rest string `json:"rest"`
+		} `json:"urls"`
+	}
+	if err := c.get(ctx, SalesforceLoginBaseUrl+"/services/oauth2/userinfo", &userInfo); err != nil {
+		return "", err
+	}
+	u, err := url.Parse(userInfo.URLs.Rest)
+	if err != nil || u.Host == "" {
+		return "", errors.Errorf("invalid Salesforce rest url %q", userInfo.URLs.Rest)
+	}
+	c.instanceUrl = u.Scheme + "://" + u.Host
+	return c.instanceUrl, nil
+}
+
+// escapeSOQL escapes a string literal of a SOQL query.
+func escapeSOQL(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
+
+// FindContact returns the account of the most recently modified contact with an email. The plan is
+// the type of the account, ie. `Customer - Direct`, and the ARR its annual revenue.
+func (c *Client) FindContact(ctx context.Context, email string) (*crm.Contact, error) {
+	instanceUrl, err := c.getInstanceUrl(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT Account.Name, Account.Type, Account.AnnualRevenue FROM Contact WHERE Email = '%s' ORDER BY LastModifiedDate DESC LIMIT 1", escapeSOQL(email))
+	var result struct {
+		Records []struct {
+			Account *struct {
+				Name          string   `json:"Name"`
+				Type          string   `json:"Type"`
+				AnnualRevenue *float64 `json:"AnnualRevenue"`
+			} `json:"Account"`
+		} `json:"records"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("%s/services/data/%s/query?%s", instanceUrl, apiVersion, url.Values{"q": {query}}.Encode()), &result); err != nil {
+		return nil, err
+	}
+	if len(result.Records) == 0 {
+		return nil, nil
+	}
+
+	contact := &crm.Contact{}
+	if account := result.Records[0].Account; account != nil {
+		contact.Company = account.Name
+		contact.Plan = account.Type
+		if account.AnnualRevenue != nil {
+			contact.ARR = strconv.FormatFloat(*account.AnnualRevenue, 'f', -1, 64)
+		}
+	}
+	return contact, nil
+}
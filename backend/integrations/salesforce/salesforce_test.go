@@ -0,0 +1,57 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/integrations/crm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FindContact(t *testing.T) {
+	var queries []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`[{"message":"Session expired or invalid","errorCode":"INVALID_SESSION_ID"}]`))
+			return
+		}
+		switch r.URL.Path {
+		case "/services/oauth2/userinfo":
+			_, _ = w.Write([]byte(`{"urls":{"rest":"` + server.URL + `/services/data/v{version}/"}}`))
+		case "/services/data/" + apiVersion + "/query":
+			q := r.URL.Query().Get("q")
+			queries = append(queries, q)
+			if q == "SELECT Account.Name, Account.Type, Account.AnnualRevenue FROM Contact WHERE Email = 'chilly@highlight.io' ORDER BY LastModifiedDate DESC LIMIT 1" {
+				_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[{"Account":{"Name":"Acme","Type":"Customer - Direct","AnnualRevenue":1200000.0}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := SalesforceLoginBaseUrl
+	SalesforceLoginBaseUrl = server.URL
+	defer func() { SalesforceLoginBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("token")
+
+	contact, err := client.FindContact(ctx, "chilly@highlight.io")
+	assert.NoError(t, err)
+	assert.Equal(t, &crm.Contact{Company: "Acme", Plan: "Customer - Direct", ARR: "1200000"}, contact)
+
+	contact, err = client.FindContact(ctx, "o'brien@highlight.io")
+	assert.NoError(t, err)
+	assert.Nil(t, contact)
+	assert.Contains(t, queries[1], `Email = 'o\'brien@highlight.io'`)
+
+	_, err = NewClient("invalid").FindContact(ctx, "chilly@highlight.io")
+	assert.Error(t, err)
+}
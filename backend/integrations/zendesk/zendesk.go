@@ -0,0 +1,79 @@
+package zendesk
+
+import (
+	"os"
+	"strings"
+
+	"github.com/dchest/uniuri"
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+)
+
+// The Highlight Zendesk app shows the latest sessions of the requester of a ticket. The app stores the
+// access token of a project in a secure setting, which Zendesk adds to the requests of the app to the
+// sessions endpoint so that the token is not exposed to the agents' browsers.
+
+var signingKey = os.Getenv("ZENDESK_INTEGRATION_SIGNING_KEY")
+
+// AccessToken is the parsed access token of a project. The magic is stored with the project's
+// integration so that the token can be revoked.
+type AccessToken struct {
+	ProjectID int
+	Magic     string
+}
+
+// GenerateAccessToken returns a new access token for a project.
+func GenerateAccessToken(projectID int) (string, *AccessToken, error) {
+	if signingKey == "" {
+		return "", nil, errors.New("ZENDESK_INTEGRATION_SIGNING_KEY not set")
+	}
+	parsed := &AccessToken{ProjectID: projectID, Magic: uniuri.NewLen(32)}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"project_id": parsed.ProjectID,
+		"magic":      parsed.Magic,
+	}).SignedString([]byte(signingKey))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error signing Zendesk access token")
+	}
+	return token, parsed, nil
+}
+
+// ParseAccessToken parses the access token of an authorization header, with or without its Bearer prefix.
+func ParseAccessToken(header string) (*AccessToken, error) {
+	if signingKey == "" {
+		return nil, errors.New("ZENDESK_INTEGRATION_SIGNING_KEY not set")
+	}
+	token, err := jwt.Parse(strings.TrimPrefix(header, "Bearer "), func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(signingKey), nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing Zendesk access token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("Zendesk access token is not valid")
+	}
+	magic, _ := claims["magic"].(string)
+	projectID, _ := claims["project_id"].(float64)
+	if magic == "" || projectID == 0 {
+		return nil, errors.New("Zendesk access token is not valid")
+	}
+	return &AccessToken{ProjectID: int(projectID), Magic: magic}, nil
+}
+
+// Session is a session of the requester of a ticket, as shown by the app.
+type Session struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Subtitle  string `json:"subtitle"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"created_at"`
+}
+
+type SessionsResponse struct {
+	Sessions []*Session `json:"sessions"`
+}
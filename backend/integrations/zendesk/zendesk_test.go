@@ -0,0 +1,37 @@
+package zendesk
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessToken(t *testing.T) {
+	key := signingKey
+	signingKey = "test-signing-key"
+	defer func() { signingKey = key }()
+
+	token, generated, err := GenerateAccessToken(12)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, generated.ProjectID)
+	assert.Len(t, generated.Magic, 32)
+
+	parsed, err := ParseAccessToken("Bearer " + token)
+	assert.NoError(t, err)
+	assert.Equal(t, generated, parsed)
+
+	parsed, err = ParseAccessToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, generated, parsed)
+
+	other, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"project_id": 12, "magic": "magic"}).SignedString([]byte("other-key"))
+	assert.NoError(t, err)
+	_, err = ParseAccessToken(other)
+	assert.Error(t, err)
+
+	missing, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"project_id": 12}).SignedString([]byte(signingKey))
+	assert.NoError(t, err)
+	_, err = ParseAccessToken(missing)
+	assert.Error(t, err)
+}
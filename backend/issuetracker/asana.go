@@ -0,0 +1,107 @@
+package issuetracker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/highlight-run/highlight/backend/integrations/asana"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register(modelInputs.IntegrationTypeAsana, func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error) {
+		accessToken, err := deps.Integrations.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeAsana)
+		if err != nil {
+			return nil, err
+		}
+		if accessToken == nil {
+			return nil, errors.New("workspace does not have an Asana access token")
+		}
+		return &Asana{client: asana.NewClient(*accessToken)}, nil
+	})
+}
+
+// Asana creates issues as tasks of the projects of the Asana workspaces of the user that
+// authorized the integration.
+type Asana struct {
+	client *asana.Client
+}
+
+func asanaIssue(task *asana.Task) *Issue {
+	return &Issue{ID: task.GID, Title: task.Name, URL: task.PermalinkURL}
+}
+
+func (a *Asana) ListProjects(ctx context.Context) ([]*Project, error) {
+	workspaces, err := a.client.GetWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var projects []*Project
+	for _, workspace := range workspaces {
+		asanaProjects, err := a.client.GetProjects(ctx, workspace.GID)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range asanaProjects {
+			projects = append(projects, &Project{ID: project.GID, Name: project.Name, Path: workspace.Name})
+		}
+	}
+	return projects, nil
+}
+
+func (a *Asana) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	task, err := a.client.CreateTask(ctx, &asana.CreateTaskInput{
+		Name:     input.Title,
+		Notes:    input.Description,
+		Projects: []string{input.ProjectID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asanaIssue(task), nil
+}
+
+func (a *Asana) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	workspaces, err := a.client.GetWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []*Issue
+	for _, workspace := range workspaces {
+		tasks, err := a.client.SearchTasks(ctx, workspace.GID, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			issues = append(issues, asanaIssue(task))
+		}
+	}
+	return issues, nil
+}
+
+// LinkIssue comments the url on the task.
+func (a *Asana) LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error) {
+	task, err := a.client.GetTask(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if err := a.client.CreateComment(ctx, task.GID, fmt.Sprintf("Linked to Highlight: %s", url)); err != nil {
+			return nil, err
+		}
+	}
+	return asanaIssue(task), nil
+}
+
+func (a *Asana) GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error) {
+	task, err := a.client.GetTask(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Completed {
+		return &IssueStatus{Status: "Completed", Closed: true}, nil
+	}
+	return &IssueStatus{Status: "Incomplete"}, nil
+}
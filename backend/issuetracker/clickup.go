@@ -0,0 +1,133 @@
+package issuetracker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/clickup"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+func init() {
+	Register(modelInputs.IntegrationTypeClickUp, func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error) {
+		client, err := NewClickUpClient(ctx, deps, workspace)
+		if err != nil {
+			return nil, err
+		}
+		return &ClickUp{client: client}, nil
+	})
+}
+
+// NewClickUpClient returns a ClickUp client for the workspace, which refreshes the workspace token
+// when it expires and caches the teams, spaces, folders and lists of the workspace.
+func NewClickUpClient(ctx context.Context, deps Deps, workspace *model.Workspace) (*clickup.Client, error) {
+	client, err := clickup.NewClient(ctx, deps.Integrations.WorkspaceTokenStore(workspace, modelInputs.IntegrationTypeClickUp))
+	if err != nil {
+		return nil, err
+	}
+	return client.WithCache(clickup.NewMetadataCache(deps.Redis, workspace.ID)), nil
+}
+
+// ClickUp creates issues as tasks of the lists of the ClickUp teams authorized by the workspace.
+type ClickUp struct {
+	client *clickup.Client
+}
+
+func clickUpIssue(task *modelInputs.ClickUpTask) *Issue {
+	return &Issue{ID: task.ID, Title: task.Name, URL: fmt.Sprintf("https://app.clickup.com/t/%s", task.ID)}
+}
+
+func (c *ClickUp) ListProjects(ctx context.Context) ([]*Project, error) {
+	teams, err := c.client.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var projects []*Project
+	for _, team := range teams {
+		spaces, err := c.client.GetSpaces(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			folders, err := c.client.GetFolders(ctx, space.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, folder := range folders {
+				for _, list := range folder.Lists {
+					projects = append(projects, &Project{ID: list.ID, Name: list.Name, Path: strings.Join([]string{team.Name, space.Name, folder.Name}, " / ")})
+				}
+			}
+			lists, err := c.client.GetFolderlessLists(ctx, space.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, list := range lists {
+				projects = append(projects, &Project{ID: list.ID, Name: list.Name, Path: strings.Join([]string{team.Name, space.Name}, " / ")})
+			}
+		}
+	}
+	return projects, nil
+}
+
+func (c *ClickUp) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	task, err := c.client.CreateTask(ctx, input.ProjectID, input.Title, input.Description, &modelInputs.ClickUpTaskInput{
+		Assignees: input.Assignees,
+		Priority:  input.Priority,
+		Tags:      input.Tags,
+		Status:    input.Status,
+		DueDate:   input.DueDate,
+		CustomFields: lo.Map(input.CustomFields, func(field *CustomField, _ int) *modelInputs.ClickUpCustomFieldInput {
+			return &modelInputs.ClickUpCustomFieldInput{ID: field.ID, Value: field.Value}
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clickUpIssue(task), nil
+}
+
+// SearchIssues searches the tasks of all the teams of the workspace.
+func (c *ClickUp) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	teams, err := c.client.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []*Issue
+	for _, team := range teams {
+		tasks, err := c.client.SearchTasks(ctx, team.ID, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			issues = append(issues, clickUpIssue(task))
+		}
+	}
+	return issues, nil
+}
+
+// LinkIssue comments the url on the task, so that the task can be traced back to Highlight.
+// Looking up the task first ensures that only tasks the workspace can access are linked.
+func (c *ClickUp) LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error) {
+	task, err := c.client.GetTask(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if err := c.client.AddComment(ctx, task.ID, []*clickup.CommentBlock{{Text: "Linked to Highlight", Link: url}}); err != nil {
+			return nil, err
+		}
+	}
+	return clickUpIssue(task), nil
+}
+
+func (c *ClickUp) GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error) {
+	status, err := c.client.GetTaskStatus(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	return &IssueStatus{Status: status.Status, Closed: status.Type == "done" || status.Type == "closed"}, nil
+}
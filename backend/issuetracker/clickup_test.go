@@ -0,0 +1,87 @@
+package issuetracker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/clickup"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+type tokenStore struct{}
+
+func (tokenStore) GetToken(context.Context) (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: "token"}, nil
+}
+
+func (tokenStore) SetToken(context.Context, *oauth2.Token) error {
+	return nil
+}
+
+func TestClickUp(t *testing.T) {
+	t.Setenv("CLICKUP_CLIENT_ID", "client-id")
+	t.Setenv("CLICKUP_CLIENT_SECRET", "client-secret")
+	t.Setenv("REACT_APP_FRONTEND_URI", "http://localhost:3000")
+
+	var comment string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team":
+			_, _ = w.Write([]byte(`{"teams":[{"id":"1","name":"Team"}],"last_page":true}`))
+		case "/team/1/space":
+			_, _ = w.Write([]byte(`{"spaces":[{"id":"2","name":"Space"}],"last_page":true}`))
+		case "/space/2/folder":
+			_, _ = w.Write([]byte(`{"folders":[{"id":"3","name":"Folder","lists":[{"id":"4","name":"Bugs"}]}],"last_page":true}`))
+		case "/space/2/list":
+			_, _ = w.Write([]byte(`{"lists":[{"id":"5","name":"Backlog"}],"last_page":true}`))
+		case "/list/4/task":
+			_, _ = w.Write([]byte(`{"id":"abc123","name":"TypeError"}`))
+		case "/task/abc123":
+			_, _ = w.Write([]byte(`{"id":"abc123","name":"TypeError","status":{"status":"complete","type":"closed"}}`))
+		case "/task/abc123/comment":
+			body, _ := io.ReadAll(r.Body)
+			comment = string(body)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := clickup.ClickUpApiBaseUrl
+	clickup.ClickUpApiBaseUrl = server.URL
+	defer func() { clickup.ClickUpApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	assert.True(t, Supported(modelInputs.IntegrationTypeClickUp))
+	_, err := New(ctx, Deps{}, &model.Workspace{}, modelInputs.IntegrationTypeSlack)
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	client, err := clickup.NewClient(ctx, tokenStore{})
+	assert.NoError(t, err)
+	var tracker IssueTracker = &ClickUp{client: client}
+
+	projects, err := tracker.ListProjects(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Project{
+		{ID: "4", Name: "Bugs", Path: "Team / Space / Folder"},
+		{ID: "5", Name: "Backlog", Path: "Team / Space"},
+	}, projects)
+
+	issue, err := tracker.CreateIssue(ctx, &CreateIssueInput{ProjectID: "4", Title: "TypeError"})
+	assert.NoError(t, err)
+	assert.Equal(t, &Issue{ID: "abc123", Title: "TypeError", URL: "https://app.clickup.com/t/abc123"}, issue)
+
+	issue, err = tracker.LinkIssue(ctx, "abc123", "http://localhost:3000/1/errors/secure")
+	assert.NoError(t, err)
+	assert.Equal(t, "TypeError", issue.Title)
+	assert.Contains(t, comment, "http://localhost:3000/1/errors/secure")
+
+	status, err := tracker.GetIssueStatus(ctx, "abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, &IssueStatus{Status: "complete", Closed: true}, status)
+}
@@ -0,0 +1,104 @@
+package issuetracker
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/pkg/errors"
+)
+
+var ErrNotSupported = errors.New("integration is not an issue tracker")
+
+// Project is a container that issues are created in, ie. a ClickUp list.
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Path names the containers of the project, ie. `Team / Space / Folder`.
+	Path string `json:"path"`
+}
+
+type Issue struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// IssueStatus is the status of an issue as named by the tracker. Closed is set when the issue
+// is done or closed.
+type IssueStatus struct {
+	Status string `json:"status"`
+	Closed bool   `json:"closed"`
+}
+
+// CustomField is the value of a custom field of a tracker, converted by the tracker to the type
+// of the field.
+type CustomField struct {
+	ID    string
+	Value string
+}
+
+// CreateIssueInput describes an issue to create in a project. The optional fields are ignored by
+// the trackers that do not support them.
+type CreateIssueInput struct {
+	ProjectID   string
+	Title       string
+	Description string
+	// URL is the Highlight page the issue is created from.
+	URL          string
+	Assignees    []string
+	Priority     *int
+	Tags         []string
+	Status       *string
+	DueDate      *time.Time
+	CustomFields []*CustomField
+}
+
+// IssueTracker is the integration of a workspace with an issue tracker, so that issues of error
+// groups and sessions can be created and linked without depending on the tracker.
+type IssueTracker interface {
+	// ListProjects returns the projects that the workspace can create issues in.
+	ListProjects(ctx context.Context) ([]*Project, error)
+	CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error)
+	// SearchIssues returns the issues matching the query, ie. to link an existing issue instead
+	// of creating a duplicate.
+	SearchIssues(ctx context.Context, query string) ([]*Issue, error)
+	// LinkIssue returns an existing issue after linking it back to the Highlight page at url.
+	LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error)
+	GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error)
+}
+
+// Deps are the clients that issue trackers are created with.
+type Deps struct {
+	Integrations *integrations.Client
+	Redis        *redis.Client
+}
+
+// Factory creates the issue tracker of a workspace.
+type Factory func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error)
+
+var factories = map[modelInputs.IntegrationType]Factory{}
+
+// Register makes the issue tracker of an integration type available to New.
+func Register(integrationType modelInputs.IntegrationType, factory Factory) {
+	factories[integrationType] = factory
+}
+
+// Supported returns whether the integration type is a registered issue tracker.
+func Supported(integrationType modelInputs.IntegrationType) bool {
+	_, ok := factories[integrationType]
+	return ok
+}
+
+// New returns the issue tracker of the integration type for the workspace, or ErrNotSupported
+// if the integration type is not a registered issue tracker.
+func New(ctx context.Context, deps Deps, workspace *model.Workspace, integrationType modelInputs.IntegrationType) (IssueTracker, error) {
+	factory, ok := factories[integrationType]
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return factory(ctx, deps, workspace)
+}
@@ -0,0 +1,121 @@
+package issuetracker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/integrations/jira"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register(modelInputs.IntegrationTypeJira, func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error) {
+		if workspace.JiraCloudID == nil || workspace.JiraDomain == nil {
+			return nil, errors.New("workspace does not have a Jira site")
+		}
+		accessToken, err := deps.Integrations.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeJira)
+		if err != nil {
+			return nil, err
+		}
+		if accessToken == nil {
+			return nil, errors.New("workspace does not have a Jira access token")
+		}
+		return &Jira{workspace: workspace, accessToken: *accessToken}, nil
+	})
+}
+
+// Jira creates issues in the projects of the Jira site of the workspace. As issues are created
+// with an issue type, the ids of the projects are `<project id>/<issue type id>`. Issues are
+// identified by their url, ie. `https://highlight.atlassian.net/browse/ENG-1`.
+type Jira struct {
+	workspace   *model.Workspace
+	accessToken string
+}
+
+func (j *Jira) issue(key string, title string) *Issue {
+	url := jira.MakeExternalIdForJiraTask(j.workspace, &jira.JiraIssue{Key: key})
+	if title == "" {
+		title = key
+	}
+	return &Issue{ID: url, Title: title, URL: url}
+}
+
+// issueKey returns the key of an issue identified by its key or url.
+func issueKey(issueID string) string {
+	return issueID[strings.LastIndex(issueID, "/")+1:]
+}
+
+func (j *Jira) ListProjects(ctx context.Context) ([]*Project, error) {
+	jiraProjects, err := jira.GetJiraProjects(j.workspace, j.accessToken)
+	if err != nil {
+		return nil, err
+	}
+	var projects []*Project
+	for _, project := range jiraProjects {
+		for _, issueType := range project.IssueTypes {
+			if issueType.Subtask {
+				continue
+			}
+			projects = append(projects, &Project{ID: project.ID + "/" + issueType.ID, Name: issueType.Name, Path: project.Name})
+		}
+	}
+	return projects, nil
+}
+
+func (j *Jira) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	projectID, issueTypeID, ok := strings.Cut(input.ProjectID, "/")
+	if !ok {
+		return nil, errors.Errorf("invalid Jira project %q", input.ProjectID)
+	}
+	task, err := jira.CreateJiraTask(j.workspace, j.accessToken, jira.JiraCreateIssuePayload{
+		Fields: jira.JiraCreateIssueFields{
+			Description: jira.IssueDescription(input.Description, nil),
+			Summary:     input.Title,
+			Project:     jira.JiraIssueProjectData{Id: projectID},
+			IssueType:   jira.JiraIssueTypeData{Id: issueTypeID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return j.issue(task.Key, input.Title), nil
+}
+
+func (j *Jira) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	jiraIssues, err := jira.SearchJiraIssues(j.workspace, j.accessToken, query)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]*Issue, len(jiraIssues))
+	for i, issue := range jiraIssues {
+		issues[i] = j.issue(issue.Key, issue.Fields.Summary)
+	}
+	return issues, nil
+}
+
+// LinkIssue adds the url to the links of the issue.
+func (j *Jira) LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error) {
+	issue, err := jira.GetJiraIssue(j.workspace, j.accessToken, issueKey(issueID))
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if err := jira.CreateJiraRemoteLink(j.workspace, j.accessToken, issue.Key, url, "Highlight"); err != nil {
+			return nil, err
+		}
+	}
+	return j.issue(issue.Key, issue.Fields.Summary), nil
+}
+
+func (j *Jira) GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error) {
+	issue, err := jira.GetJiraIssue(j.workspace, j.accessToken, issueKey(issueID))
+	if err != nil {
+		return nil, err
+	}
+	if issue.Fields.Status == nil {
+		return nil, errors.Errorf("Jira issue %s has no status", issue.Key)
+	}
+	return &IssueStatus{Status: issue.Fields.Status.Name, Closed: issue.Fields.Status.Done()}, nil
+}
@@ -0,0 +1,100 @@
+package issuetracker
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/linear"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register(modelInputs.IntegrationTypeLinear, func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error) {
+		if workspace.LinearAccessToken == nil || *workspace.LinearAccessToken == "" {
+			return nil, errors.New("workspace does not have a Linear access token")
+		}
+		return &Linear{client: linear.NewClient(*workspace.LinearAccessToken)}, nil
+	})
+}
+
+// Linear creates issues in the teams of the Linear workspace that authorized the integration,
+// optionally adding them to a project of the team.
+type Linear struct {
+	client *linear.Client
+}
+
+func linearIssue(issue *linear.Issue) *Issue {
+	return &Issue{ID: issue.ID, Title: issue.Identifier, URL: issue.URL}
+}
+
+// ListProjects returns the teams and the projects of the teams, whose ids are `<team id>/<project id>`.
+func (l *Linear) ListProjects(ctx context.Context) ([]*Project, error) {
+	teams, err := l.client.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var projects []*Project
+	for _, team := range teams {
+		projects = append(projects, &Project{ID: team.ID, Name: team.Name})
+		teamProjects, err := l.client.GetProjects(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range teamProjects {
+			projects = append(projects, &Project{ID: team.ID + "/" + project.ID, Name: project.Name, Path: team.Name})
+		}
+	}
+	return projects, nil
+}
+
+func (l *Linear) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	teamID, projectID := linear.SplitProjectID(input.ProjectID)
+	issue, err := l.client.CreateIssue(ctx, &linear.CreateIssueInput{
+		TeamID:      teamID,
+		ProjectID:   projectID,
+		Title:       input.Title,
+		Description: input.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return linearIssue(issue), nil
+}
+
+func (l *Linear) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	issues, err := l.client.SearchIssues(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*Issue, len(issues))
+	for i, issue := range issues {
+		results[i] = linearIssue(issue)
+	}
+	return results, nil
+}
+
+// LinkIssue attaches the url to the issue, which Linear shows in the issue sidebar.
+func (l *Linear) LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error) {
+	issue, err := l.client.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if _, err := l.client.CreateAttachment(ctx, issue.ID, "Highlight", issue.Title, url); err != nil {
+			return nil, err
+		}
+	}
+	return linearIssue(issue), nil
+}
+
+func (l *Linear) GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error) {
+	issue, err := l.client.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.State == nil {
+		return nil, errors.Errorf("Linear issue %s has no state", issueID)
+	}
+	return &IssueStatus{Status: issue.State.Name, Closed: issue.State.Type == "completed" || issue.State.Type == "canceled"}, nil
+}
@@ -0,0 +1,100 @@
+package issuetracker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/highlight-run/highlight/backend/integrations/monday"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	Register(modelInputs.IntegrationTypeMonday, func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error) {
+		accessToken, err := deps.Integrations.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeMonday)
+		if err != nil {
+			return nil, err
+		}
+		if accessToken == nil {
+			return nil, errors.New("workspace does not have a Monday.com access token")
+		}
+		return &Monday{client: monday.NewClient(*accessToken)}, nil
+	})
+}
+
+// Monday creates issues as items of the boards of the Monday.com account that authorized the
+// integration. As items have no description, it is posted as the first update of the item.
+type Monday struct {
+	client *monday.Client
+}
+
+func mondayIssue(item *monday.Item) *Issue {
+	return &Issue{ID: item.ID, Title: item.Name, URL: item.URL}
+}
+
+func (m *Monday) ListProjects(ctx context.Context) ([]*Project, error) {
+	boards, err := m.client.GetBoards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]*Project, len(boards))
+	for i, board := range boards {
+		projects[i] = &Project{ID: board.ID, Name: board.Name}
+		if board.Workspace != nil {
+			projects[i].Path = board.Workspace.Name
+		}
+	}
+	return projects, nil
+}
+
+func (m *Monday) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	item, err := m.client.CreateItem(ctx, input.ProjectID, input.Title)
+	if err != nil {
+		return nil, err
+	}
+	if input.Description != "" {
+		if err := m.client.CreateUpdate(ctx, item.ID, input.Description); err != nil {
+			return nil, err
+		}
+	}
+	return mondayIssue(item), nil
+}
+
+func (m *Monday) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	items, err := m.client.SearchItems(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]*Issue, len(items))
+	for i, item := range items {
+		issues[i] = mondayIssue(item)
+	}
+	return issues, nil
+}
+
+// LinkIssue posts the url as an update of the item.
+func (m *Monday) LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error) {
+	item, err := m.client.GetItem(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if err := m.client.CreateUpdate(ctx, item.ID, fmt.Sprintf("Linked to Highlight: %s", url)); err != nil {
+			return nil, err
+		}
+	}
+	return mondayIssue(item), nil
+}
+
+func (m *Monday) GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error) {
+	item, err := m.client.GetItem(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	status := item.Status()
+	if status == "" {
+		status = item.State
+	}
+	return &IssueStatus{Status: status, Closed: item.Done()}, nil
+}
@@ -0,0 +1,133 @@
+package issuetracker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/shortcut"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+func init() {
+	Register(modelInputs.IntegrationTypeShortcut, func(ctx context.Context, deps Deps, workspace *model.Workspace) (IssueTracker, error) {
+		apiToken, err := deps.Integrations.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeShortcut)
+		if err != nil {
+			return nil, err
+		}
+		if apiToken == nil {
+			return nil, errors.New("workspace does not have a Shortcut API token")
+		}
+		return &Shortcut{client: shortcut.NewClient(*apiToken)}, nil
+	})
+}
+
+// Shortcut creates issues as bug stories in the first state of a workflow, optionally adding them
+// to an epic. Stories link back to the Highlight page they are created from.
+type Shortcut struct {
+	client *shortcut.Client
+}
+
+func shortcutIssue(story *shortcut.Story) *Issue {
+	return &Issue{ID: strconv.FormatInt(story.ID, 10), Title: story.Name, URL: story.AppURL}
+}
+
+// ListProjects returns the workflows and the epics of each workflow, whose ids are
+// `<workflow id>/<epic id>`.
+func (s *Shortcut) ListProjects(ctx context.Context) ([]*Project, error) {
+	workflows, err := s.client.GetWorkflows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epics, err := s.client.GetEpics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var projects []*Project
+	for _, workflow := range workflows {
+		workflowID := strconv.FormatInt(workflow.ID, 10)
+		projects = append(projects, &Project{ID: workflowID, Name: workflow.Name})
+		for _, epic := range epics {
+			projects = append(projects, &Project{ID: fmt.Sprintf("%s/%d", workflowID, epic.ID), Name: epic.Name, Path: workflow.Name})
+		}
+	}
+	return projects, nil
+}
+
+func (s *Shortcut) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	workflowID, epicID, err := shortcut.SplitProjectID(input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	workflows, err := s.client.GetWorkflows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workflow, ok := lo.Find(workflows, func(w *shortcut.Workflow) bool { return w.ID == workflowID })
+	if !ok {
+		return nil, errors.Errorf("Shortcut workflow %d not found", workflowID)
+	}
+
+	storyInput := &shortcut.CreateStoryInput{
+		Name:            input.Title,
+		Description:     input.Description,
+		WorkflowStateID: workflow.DefaultStateID,
+		EpicID:          epicID,
+	}
+	if input.URL != "" {
+		storyInput.ExternalLinks = []string{input.URL}
+	}
+	story, err := s.client.CreateStory(ctx, storyInput)
+	if err != nil {
+		return nil, err
+	}
+	return shortcutIssue(story), nil
+}
+
+func (s *Shortcut) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	stories, err := s.client.SearchStories(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]*Issue, len(stories))
+	for i, story := range stories {
+		issues[i] = shortcutIssue(story)
+	}
+	return issues, nil
+}
+
+// LinkIssue adds the url to the external links of the story.
+func (s *Shortcut) LinkIssue(ctx context.Context, issueID string, url string) (*Issue, error) {
+	story, err := s.client.GetStory(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		if story, err = s.client.AddExternalLink(ctx, story, url); err != nil {
+			return nil, err
+		}
+	}
+	return shortcutIssue(story), nil
+}
+
+// GetIssueStatus returns the workflow state of the story, which is closed once the story is completed.
+func (s *Shortcut) GetIssueStatus(ctx context.Context, issueID string) (*IssueStatus, error) {
+	story, err := s.client.GetStory(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	workflows, err := s.client.GetWorkflows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &IssueStatus{Closed: story.Completed}
+	for _, workflow := range workflows {
+		if state, ok := lo.Find(workflow.States, func(state *shortcut.WorkflowState) bool { return state.ID == story.WorkflowStateID }); ok {
+			status.Status = state.Name
+		}
+	}
+	return status, nil
+}
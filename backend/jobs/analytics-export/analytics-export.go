@@ -0,0 +1,198 @@
+package analytics_export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/amplitude"
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
+	"github.com/highlight-run/highlight/backend/integrations/mixpanel"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	evalFreq = time.Minute
+	// sessions are given time to be identified before they are exported, as they are scanned in
+	// the order they were created
+	identifyDelay = 5 * time.Minute
+	// limit how far back events are exported when an export is enabled
+	maxBackfill = time.Hour
+	// sessions and rage clicks are read and sent in pages of this many rows
+	pageSize = 500
+)
+
+// WatchAnalyticsExports exports the identified sessions and rage clicks of every enabled export's
+// project every minute. Events carry an insert id so that a page that is sent again after a
+// failure is deduplicated by the destination.
+func WatchAnalyticsExports(ctx context.Context, DB *gorm.DB, redisClient *redis.Client) {
+	log.WithContext(ctx).Info("Starting to export product analytics events")
+
+	for range time.NewTicker(evalFreq).C {
+		var exports []*model.ProductAnalyticsExport
+		if err := DB.WithContext(ctx).Where(&model.ProductAnalyticsExport{Enabled: true}).Find(&exports).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for product analytics exports")
+			continue
+		}
+
+		now := time.Now()
+		for _, export := range exports {
+			if err := exportEvents(ctx, DB, redisClient, export, now); err != nil {
+				log.WithContext(ctx).WithError(err).
+					WithField("project_id", export.ProjectID).
+					WithField("destination", export.Destination).
+					Error("error exporting product analytics events")
+			}
+		}
+	}
+}
+
+func newClient(export *model.ProductAnalyticsExport) analytics.Client {
+	if analytics.Destination(export.Destination) == analytics.DestinationMixpanel {
+		return mixpanel.NewClient(export.APIKey, mixpanel.Region(export.Region))
+	}
+	return amplitude.NewClient(export.APIKey, amplitude.Region(export.Region))
+}
+
+func exportEvents(ctx context.Context, DB *gorm.DB, redisClient *redis.Client, export *model.ProductAnalyticsExport, now time.Time) error {
+	// only one worker may export the events of a project, as they share the cursors of the export
+	mutex, err := redisClient.AcquireLock(ctx, fmt.Sprintf("product-analytics-export-%d-lock", export.ID), 5*time.Second)
+	if err != nil {
+		return errors.Wrap(err, "error acquiring product analytics export lock")
+	}
+	defer func() {
+		if _, err := mutex.Unlock(); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to release product analytics export lock")
+		}
+	}()
+
+	// another worker may have exported events while the lock was held
+	if err := DB.WithContext(ctx).Take(export, export.ID).Error; err != nil {
+		return errors.Wrap(err, "error querying product analytics export")
+	}
+	if !export.Enabled {
+		return nil
+	}
+
+	client := newClient(export)
+	frontendURL := os.Getenv("FRONTEND_URI")
+	extend := func() error {
+		_, err := mutex.Extend()
+		return err
+	}
+	if export.ExportIdentifiedSessions {
+		err = exportIdentifiedSessions(ctx, DB, client, export, frontendURL, now, extend)
+	}
+	if err == nil && export.ExportRageClicks {
+		err = exportRageClicks(ctx, DB, client, export, frontendURL, now, extend)
+	}
+
+	if err != nil {
+		lastError := err.Error()
+		if updateErr := DB.WithContext(ctx).Model(export).Update("last_error", &lastError).Error; updateErr != nil {
+			log.WithContext(ctx).WithError(updateErr).Error("error updating product analytics export error")
+		}
+		return err
+	}
+	if export.LastError != nil {
+		return DB.WithContext(ctx).Model(export).Update("last_error", nil).Error
+	}
+	return nil
+}
+
+func exportIdentifiedSessions(ctx context.Context, DB *gorm.DB, client analytics.Client, export *model.ProductAnalyticsExport, frontendURL string, now time.Time, extend func() error) error {
+	for {
+		query := DB.WithContext(ctx).
+			Where(&model.Session{ProjectID: export.ProjectID, Identified: true}).
+			Where("id > ?", export.LastSessionID).
+			Where("created_at < ?", now.Add(-identifyDelay))
+		if export.LastSessionID == 0 {
+			query = query.Where("created_at >= ?", now.Add(-identifyDelay-maxBackfill))
+		}
+		var sessions []*model.Session
+		if err := query.Order("id ASC").Limit(pageSize).Find(&sessions).Error; err != nil {
+			return errors.Wrap(err, "error querying identified sessions")
+		}
+		if len(sessions) == 0 {
+			return nil
+		}
+
+		events := make([]*analytics.Event, 0, len(sessions))
+		for _, session := range sessions {
+			if session.Excluded {
+				continue
+			}
+			events = append(events, analytics.IdentifiedSessionEvent(session, export.UserIDProperty, frontendURL))
+		}
+		if err := client.SendEvents(ctx, events); err != nil {
+			return errors.Wrapf(err, "error sending identified sessions to %s", export.Destination)
+		}
+		export.LastSessionID = sessions[len(sessions)-1].ID
+		if err := DB.WithContext(ctx).Model(export).Update("last_session_id", export.LastSessionID).Error; err != nil {
+			return errors.Wrap(err, "error updating product analytics export progress")
+		}
+
+		if len(sessions) < pageSize {
+			return nil
+		}
+		if err := extend(); err != nil {
+			return errors.Wrap(err, "error extending product analytics export lock")
+		}
+	}
+}
+
+func exportRageClicks(ctx context.Context, DB *gorm.DB, client analytics.Client, export *model.ProductAnalyticsExport, frontendURL string, now time.Time, extend func() error) error {
+	for {
+		query := DB.WithContext(ctx).
+			Where(&model.RageClickEvent{ProjectID: export.ProjectID}).
+			Where("id > ?", export.LastRageClickID)
+		if export.LastRageClickID == 0 {
+			query = query.Where("created_at >= ?", now.Add(-maxBackfill))
+		}
+		var rageClicks []*model.RageClickEvent
+		if err := query.Order("id ASC").Limit(pageSize).Find(&rageClicks).Error; err != nil {
+			return errors.Wrap(err, "error querying rage clicks")
+		}
+		if len(rageClicks) == 0 {
+			return nil
+		}
+
+		var sessions []*model.Session
+		if err := DB.WithContext(ctx).
+			Where("project_id = ?", export.ProjectID).
+			Where("secure_id IN ?", lo.Uniq(lo.Map(rageClicks, func(r *model.RageClickEvent, _ int) string { return r.SessionSecureID }))).
+			Find(&sessions).Error; err != nil {
+			return errors.Wrap(err, "error querying rage click sessions")
+		}
+		sessionsBySecureID := lo.KeyBy(sessions, func(s *model.Session) string { return s.SecureID })
+
+		events := make([]*analytics.Event, 0, len(rageClicks))
+		for _, rageClick := range rageClicks {
+			session, ok := sessionsBySecureID[rageClick.SessionSecureID]
+			if !ok || session.Excluded {
+				continue
+			}
+			events = append(events, analytics.RageClickEvent(rageClick, session, export.UserIDProperty, frontendURL))
+		}
+		if err := client.SendEvents(ctx, events); err != nil {
+			return errors.Wrapf(err, "error sending rage clicks to %s", export.Destination)
+		}
+		export.LastRageClickID = rageClicks[len(rageClicks)-1].ID
+		if err := DB.WithContext(ctx).Model(export).Update("last_rage_click_id", export.LastRageClickID).Error; err != nil {
+			return errors.Wrap(err, "error updating product analytics export progress")
+		}
+
+		if len(rageClicks) < pageSize {
+			return nil
+		}
+		if err := extend(); err != nil {
+			return errors.Wrap(err, "error extending product analytics export lock")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package digests
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/lambda-functions/digests/handlers"
+	"github.com/highlight-run/highlight/backend/lambda-functions/digests/utils"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+	"github.com/sendgrid/sendgrid-go"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const evalFreq = 5 * time.Minute
+
+// WatchDigests sends the digest emails of the projects with a digest setting on their schedule. The
+// digests are compiled and sent by the handlers of the digests lambda, which sends the weekly
+// digests of the other projects.
+func WatchDigests(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, mailClient *sendgrid.Client) {
+	log.WithContext(ctx).Info("Starting to watch digests")
+
+	h := handlers.InitHandlers(DB, ccClient, mailClient)
+	for range time.NewTicker(evalFreq).C {
+		now := time.Now()
+		var settings []*model.ProjectDigestSetting
+		if err := DB.WithContext(ctx).Model(&model.ProjectDigestSetting{}).
+			Where("frequency <> ?", model.DigestFrequencyNever).
+			Find(&settings).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for digest settings")
+			continue
+		}
+
+		for _, setting := range settings {
+			if !setting.IsDue(now) {
+				continue
+			}
+			if err := processDigest(ctx, DB, h, setting, now); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("project_id", setting.ProjectID).Error("error sending digest")
+			}
+		}
+	}
+}
+
+func processDigest(ctx context.Context, DB *gorm.DB, h handlers.Handlers, setting *model.ProjectDigestSetting, now time.Time) error {
+	end := setting.GetScheduledAt(now)
+
+	// claim the digest before sending it so that it is sent once
+	query := DB.WithContext(ctx).Model(setting).Where("last_sent_at IS NULL OR last_sent_at < ?", end)
+	result := query.Update("last_sent_at", now)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "error updating digest last sent time")
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	start := end.Add(-setting.GetPeriod())
+	data, err := h.GetDigestData(ctx, utils.ProjectIdResponse{
+		ProjectId: setting.ProjectID,
+		Frequency: setting.Frequency,
+		End:       end,
+		Start:     start,
+		Prior:     start.Add(-setting.GetPeriod()),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.WithContext(ctx).WithField("project_id", setting.ProjectID).Info("Sending digest")
+	return h.SendDigestEmails(ctx, *data)
+}
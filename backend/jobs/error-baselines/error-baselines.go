@@ -0,0 +1,117 @@
+package error_baselines
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// baselineDelay is how long after the end of an hour its errors are counted, so that the count has
+// the errors that were ingested late.
+const baselineDelay = 5 * time.Minute
+
+const batchSize = 1000
+
+type errorGroupCount struct {
+	ErrorGroupID int
+	Count        int64
+}
+
+// WatchErrorBaselines adds the count of errors of each hour to the baselines of the error groups of
+// projects with an error alert in anomaly mode.
+func WatchErrorBaselines(ctx context.Context, DB *gorm.DB) {
+	log.WithContext(ctx).Info("Starting to watch error baselines")
+
+	for {
+		end := time.Now().Truncate(time.Hour)
+		time.Sleep(time.Until(end.Add(time.Hour + baselineDelay)))
+		end = end.Add(time.Hour)
+
+		if err := updateErrorBaselines(ctx, DB, end.Add(-time.Hour), end); err != nil {
+			log.WithContext(ctx).WithError(err).Error("error updating error baselines")
+		}
+	}
+}
+
+func updateErrorBaselines(ctx context.Context, DB *gorm.DB, start time.Time, end time.Time) error {
+	var projectIDs []int
+	if err := DB.WithContext(ctx).Model(&model.ErrorAlert{}).
+		Where("anomaly_detection = ? AND disabled = ?", true, false).
+		Distinct().Pluck("project_id", &projectIDs).Error; err != nil {
+		return errors.Wrap(err, "error querying projects with anomaly error alerts")
+	}
+	if len(projectIDs) == 0 {
+		return nil
+	}
+
+	var counts []errorGroupCount
+	if err := DB.WithContext(ctx).Raw(`
+		SELECT error_group_id, COUNT(*) AS count
+		FROM error_objects
+		WHERE
+			project_id IN ?
+			AND error_group_id IS NOT NULL
+			AND created_at >= ?
+			AND created_at < ?
+		GROUP BY error_group_id
+	`, projectIDs, start, end).Scan(&counts).Error; err != nil {
+		return errors.Wrap(err, "error counting errors of error groups")
+	}
+
+	// groups with a baseline for the hour but no errors in it have a count of 0, so that baselines
+	// also learn the hours that a group is quiet
+	hourOfWeek := alerts.HourOfWeek(start)
+	var baselines []*model.ErrorGroupBaseline
+	if err := DB.WithContext(ctx).Raw(`
+		SELECT b.*
+		FROM error_group_baselines b
+		INNER JOIN error_groups g
+		ON g.id = b.error_group_id
+		WHERE
+			b.hour_of_week = ?
+			AND g.project_id IN ?
+	`, hourOfWeek, projectIDs).Scan(&baselines).Error; err != nil {
+		return errors.Wrap(err, "error querying error group baselines")
+	}
+	baselinesByGroup := lo.KeyBy(baselines, func(baseline *model.ErrorGroupBaseline) int {
+		return baseline.ErrorGroupID
+	})
+	for _, count := range counts {
+		if _, ok := baselinesByGroup[count.ErrorGroupID]; !ok {
+			baselinesByGroup[count.ErrorGroupID] = &model.ErrorGroupBaseline{ErrorGroupID: count.ErrorGroupID, HourOfWeek: hourOfWeek}
+		}
+	}
+	countsByGroup := lo.SliceToMap(counts, func(count errorGroupCount) (int, int64) {
+		return count.ErrorGroupID, count.Count
+	})
+
+	var updated []*model.ErrorGroupBaseline
+	for groupID, baseline := range baselinesByGroup {
+		// skip the hour if it was already added, such as after a restart
+		if !baseline.UpdatedAt.Before(end) {
+			continue
+		}
+		alerts.UpdateBaseline(baseline, float64(countsByGroup[groupID]))
+		baseline.UpdatedAt = time.Now()
+		updated = append(updated, baseline)
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+
+	if err := DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "error_group_id"}, {Name: "hour_of_week"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mean", "variance", "samples", "updated_at"}),
+	}).CreateInBatches(updated, batchSize).Error; err != nil {
+		return errors.Wrap(err, "error saving error group baselines")
+	}
+	log.WithContext(ctx).WithField("hour_of_week", hourOfWeek).Infof("updated %d error group baselines", len(updated))
+	return nil
+}
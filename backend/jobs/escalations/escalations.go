@@ -0,0 +1,115 @@
+package escalations
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	Email "github.com/highlight-run/highlight/backend/email"
+	"github.com/highlight-run/highlight/backend/model"
+	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/sendgrid/sendgrid-go"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const evalFreq = 30 * time.Second
+
+// WatchEscalations sends the steps of the open alert escalations that are due. Each step is claimed
+// by advancing the escalation before it is sent, so that it is sent once.
+func WatchEscalations(ctx context.Context, DB *gorm.DB, mailClient *sendgrid.Client) {
+	log.WithContext(ctx).Info("Starting to watch alert escalations")
+
+	for range time.NewTicker(evalFreq).C {
+		now := time.Now()
+		var escalations []*model.AlertEscalation
+		if err := DB.WithContext(ctx).Model(&model.AlertEscalation{}).
+			Where("acknowledged_at IS NULL AND resolved_at IS NULL").
+			Where("next_step_at <= ?", now).
+			Find(&escalations).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for alert escalations")
+			continue
+		}
+
+		for _, escalation := range escalations {
+			if err := processEscalation(ctx, DB, mailClient, escalation, now); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("escalation_id", escalation.ID).Error("error processing alert escalation")
+			}
+		}
+	}
+}
+
+func processEscalation(ctx context.Context, DB *gorm.DB, mailClient *sendgrid.Client, escalation *model.AlertEscalation, now time.Time) error {
+	var policies []*model.EscalationPolicy
+	if err := DB.WithContext(ctx).Where(&model.EscalationPolicy{Model: model.Model{ID: escalation.EscalationPolicyID}}).Limit(1).Find(&policies).Error; err != nil {
+		return errors.Wrap(err, "error querying escalation policy")
+	}
+	// the escalations of a deleted policy, or of steps that were since removed, are finished
+	step := escalation.NextStep
+	if len(policies) == 0 || step >= len(policies[0].Steps) {
+		return DB.WithContext(ctx).Model(escalation).Update("next_step_at", nil).Error
+	}
+	policy := policies[0]
+
+	result := DB.WithContext(ctx).Model(escalation).
+		Where("next_step = ?", step).
+		Where("acknowledged_at IS NULL AND resolved_at IS NULL").
+		Updates(map[string]interface{}{
+			"next_step":    step + 1,
+			"next_step_at": alerts.GetNextStepAt(policy, step+1, now),
+		})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "error advancing alert escalation")
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	log.WithContext(ctx).WithField("escalation_id", escalation.ID).WithField("step", step).Info("Sending alert escalation step")
+	return sendEscalationStep(ctx, DB, mailClient, escalation, policy.Steps[step], alerts.GetEscalationMessage(escalation, step, now), now)
+}
+
+func sendEscalationStep(ctx context.Context, DB *gorm.DB, mailClient *sendgrid.Client, escalation *model.AlertEscalation, step *model.EscalationStep, message string, now time.Time) error {
+	switch step.Type {
+	case model.EscalationStepSlack:
+		var project model.Project
+		if err := DB.WithContext(ctx).Model(&model.Project{}).Where("id = ?", escalation.ProjectID).Take(&project).Error; err != nil {
+			return errors.Wrap(err, "error querying project for alert escalation")
+		}
+		var workspace model.Workspace
+		if err := DB.WithContext(ctx).Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
+			return errors.Wrap(err, "error querying workspace for alert escalation")
+		}
+		return tempalerts.SendSlackEscalation(ctx, escalation, &tempalerts.SendSlackEscalationInput{
+			Message:   message,
+			Workspace: &workspace,
+			Channels:  step.SlackChannels,
+		})
+	case model.EscalationStepEmail:
+		emails := step.Emails
+		if step.OnCallScheduleID != nil {
+			var schedule model.OnCallSchedule
+			if err := DB.WithContext(ctx).Where(&model.OnCallSchedule{Model: model.Model{ID: *step.OnCallScheduleID}, ProjectID: escalation.ProjectID}).Take(&schedule).Error; err != nil {
+				return errors.Wrap(err, "error querying on-call schedule for alert escalation")
+			}
+			if onCall := schedule.GetOnCall(now); onCall != "" {
+				emails = append(emails, onCall)
+			}
+		}
+		for _, email := range lo.Uniq(emails) {
+			if err := Email.SendAlertEmail(ctx, mailClient, email, message, escalation.AlertType, escalation.Title); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("escalation_id", escalation.ID).Error("error sending alert escalation email")
+			}
+		}
+		return nil
+	case model.EscalationStepPagerDuty:
+		if step.PagerDuty == nil {
+			return nil
+		}
+		return alerts.TriggerEscalationIncident(ctx, escalation, step.PagerDuty)
+	default:
+		return errors.Errorf("unknown escalation step type %s", step.Type)
+	}
+}
@@ -0,0 +1,82 @@
+package heartbeat_monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const evalFreq = 30 * time.Second
+
+// WatchHeartbeatMonitors periodically checks every enabled heartbeat monitor for
+// missed or failed check-ins. Check-ins themselves are ingested by the batched kafka worker,
+// which keeps the last check-in of each monitor up to date in postgres.
+func WatchHeartbeatMonitors(ctx context.Context, DB *gorm.DB, redisClient *redis.Client) {
+	log.WithContext(ctx).Info("Starting to watch heartbeat monitors")
+
+	for range time.NewTicker(evalFreq).C {
+		now := time.Now()
+		var monitors []*model.HeartbeatMonitor
+		if err := DB.WithContext(ctx).Model(&model.HeartbeatMonitor{}).Where("disabled = ?", false).Find(&monitors).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for heartbeat monitors")
+			continue
+		}
+
+		for _, monitor := range monitors {
+			reason := monitor.GetAlertReason(now)
+			if reason == "" {
+				continue
+			}
+			if err := redisClient.PublishAlertStateChange(ctx, redis.AlertStateChange{
+				ProjectID: monitor.ProjectID,
+				AlertID:   monitor.ID,
+				AlertType: model.AlertType.HEARTBEAT,
+				Title:     monitor.Name,
+				State:     redis.AlertStateAlerting,
+				Timestamp: now,
+			}); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("monitor_id", monitor.ID).Warn("failed to publish heartbeat monitor state change")
+			}
+			if err := processHeartbeatMonitor(ctx, DB, monitor, reason, now); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("monitor_id", monitor.ID).Error("error processing heartbeat monitor")
+			}
+		}
+	}
+}
+
+func processHeartbeatMonitor(ctx context.Context, DB *gorm.DB, monitor *model.HeartbeatMonitor, reason string, now time.Time) error {
+	// record the alert before sending so that a failure to notify does not cause repeated alerts
+	if err := DB.WithContext(ctx).Model(monitor).Update("last_alert_sent_at", now).Error; err != nil {
+		return errors.Wrap(err, "error updating heartbeat monitor last alert time")
+	}
+
+	var project model.Project
+	if err := DB.WithContext(ctx).Model(&model.Project{}).Where("id = ?", monitor.ProjectID).Take(&project).Error; err != nil {
+		return errors.Wrap(err, "error querying project for heartbeat monitor")
+	}
+	var workspace model.Workspace
+	if err := DB.WithContext(ctx).Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
+		return errors.Wrap(err, "error querying workspace for heartbeat monitor")
+	}
+
+	log.WithContext(ctx).WithField("monitor_id", monitor.ID).Info(fmt.Sprintf("Firing heartbeat alert for %s", monitor.Name))
+
+	message := fmt.Sprintf("*%s*: %s.", monitor.Name, reason)
+	if err := tempalerts.SendSlackHeartbeatMonitorAlert(ctx, monitor, &tempalerts.SendSlackAlertForHeartbeatMonitorInput{Message: message, Workspace: &workspace}); err != nil {
+		log.WithContext(ctx).Error("error sending slack alert for heartbeat monitor", err)
+	}
+
+	return alerts.SendHeartbeatMonitorAlert(alerts.HeartbeatMonitorAlertEvent{
+		HeartbeatMonitor: monitor,
+		Workspace:        &workspace,
+		Reason:           reason,
+	})
+}
@@ -98,7 +98,7 @@ func getLogAlerts(ctx context.Context, DB *gorm.DB) []*model.LogAlert {
 	return alerts
 }
 
-func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Client, alert *model.LogAlert, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client, lambdaClient *lambda.Client) error {
+func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Client, alert *model.LogAlert, rh *resthooks.Resthook, redisClient *redis.Client, ccClient *clickhouse.Client, lambdaClient *lambda.Client) error {
 	end := time.Now().Add(-time.Minute)
 	start := end.Add(-time.Duration(alert.Frequency) * time.Second)
 
@@ -111,10 +111,7 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 	}
 	count := int(count64)
 
-	alertCondition := count >= alert.CountThreshold
-	if alert.BelowThreshold {
-		alertCondition = count <= alert.CountThreshold
-	}
+	alertCondition := alerts.IsThresholdCrossed(float64(count), float64(alert.CountThreshold), alert.BelowThreshold)
 
 	log.WithContext(ctx).WithFields(log.Fields{
 		"id":        alert.ID,
@@ -127,89 +124,157 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 		"alerting":  alertCondition,
 	}).Info("evaluated log alert")
 
-	if alertCondition {
-		var project model.Project
-		if err := DB.Model(&model.Project{}).Where("id = ?", alert.ProjectID).Take(&project).Error; err != nil {
-			return errors.Wrap(err, "error querying project for processMetricMonitor")
-		}
-		var workspace model.Workspace
-		if err := DB.Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
-			return errors.Wrap(err, "error querying workspace for processMetricMonitor")
-		}
+	// notify only when the alert starts firing or resolves
+	state, changed, err := alerts.TransitionAlertState(ctx, DB, &model.LogAlert{}, alert.ID, alertCondition, end)
+	if err != nil {
+		return errors.Wrap(err, "error transitioning log alert state")
+	}
+	if !changed {
+		return nil
+	}
 
-		aboveStr := "above"
-		if alert.BelowThreshold {
-			aboveStr = "below"
-		}
+	var project model.Project
+	if err := DB.Model(&model.Project{}).Where("id = ?", alert.ProjectID).Take(&project).Error; err != nil {
+		return errors.Wrap(err, "error querying project for processLogAlert")
+	}
+	var workspace model.Workspace
+	if err := DB.Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
+		return errors.Wrap(err, "error querying workspace for processLogAlert")
+	}
 
-		hookPayload := zapier.HookPayload{
-			MetricValue:     pointy.Float64(float64(count)),
-			MetricThreshold: pointy.Float64(float64(alert.CountThreshold)),
-		}
-		if err := rh.Notify(project.ID, fmt.Sprintf("LogAlert_%d", alert.ID), hookPayload); err != nil {
-			log.WithContext(ctx).Error("error notifying zapier", err)
-		}
+	if state == model.AlertStateNormal {
+		return sendLogAlertResolved(ctx, DB, redisClient, alert, &workspace, count, start, end)
+	}
 
-		queryStr := ""
-		if alert.Query != "" {
-			queryStr = fmt.Sprintf(`for query *%s* `, alert.Query)
-		}
-		body := fmt.Sprintf(
-			"Log count %swas %s the threshold.\n"+
-				"_Count_: %d | _Threshold_: %d",
-			queryStr,
-			aboveStr,
-			count,
-			alert.CountThreshold,
-		)
-
-		log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Firing alert for %s", alert.Name))
-
-		if err := tempalerts.SendSlackLogAlert(ctx, DB, alert, &tempalerts.SendSlackAlertForLogAlertInput{Body: body, Workspace: &workspace, StartDate: start, EndDate: end}); err != nil {
-			log.WithContext(ctx).Error("error sending slack alert for metric monitor", err)
-		}
+	aboveStr := "above"
+	if alert.BelowThreshold {
+		aboveStr = "below"
+	}
 
-		if err = alerts.SendLogAlert(alerts.LogAlertEvent{
-			LogAlert:  alert,
-			Workspace: &workspace,
-			Count:     count,
-			StartDate: start,
-			EndDate:   end,
-		}); err != nil {
-			log.WithContext(ctx).Error(err)
-		}
+	hookPayload := zapier.HookPayload{
+		MetricValue:     pointy.Float64(float64(count)),
+		MetricThreshold: pointy.Float64(float64(alert.CountThreshold)),
+	}
+	if err := rh.Notify(project.ID, fmt.Sprintf("LogAlert_%d", alert.ID), hookPayload); err != nil {
+		log.WithContext(ctx).Error("error notifying zapier", err)
+	}
 
-		emailsToNotify, err := model.GetEmailsToNotify(alert.EmailsToNotify)
-		if err != nil {
-			log.WithContext(ctx).Error(err)
-		}
+	queryStr := ""
+	if alert.Query != "" {
+		queryStr = fmt.Sprintf(`for query *%s* `, alert.Query)
+	}
+	body := fmt.Sprintf(
+		"Log count %swas %s the threshold.\n"+
+			"_Count_: %d | _Threshold_: %d",
+		queryStr,
+		aboveStr,
+		count,
+		alert.CountThreshold,
+	)
+	if alert.BelowThreshold && alert.CountThreshold == 0 {
+		body = fmt.Sprintf("No logs were received %sin the last %s.", queryStr, end.Sub(start))
+	}
 
-		logsUrl := tempalerts.GetLogAlertURL(alert.ProjectID, alert.Query, start, end)
-		frontendURL := os.Getenv("FRONTEND_URI")
-		alertUrl := fmt.Sprintf("%s/%d/alerts/logs/%d", frontendURL, alert.ProjectID, alert.ID)
-
-		templateData := map[string]interface{}{
-			"alertLink":      alertUrl,
-			"alertName":      alert.Name,
-			"belowThreshold": alert.BelowThreshold,
-			"count":          count,
-			"logsLink":       logsUrl,
-			"projectName":    project.Name,
-			"query":          alert.Query,
-			"threshold":      alert.CountThreshold,
-		}
+	log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Firing alert for %s", alert.Name))
 
-		subjectLine := alert.Name
-		emailHtml, err := lambdaClient.FetchReactEmailHTML(ctx, lambda.ReactEmailTemplateLogAlert, templateData)
-		if err != nil {
-			return errors.Wrap(err, "error fetching email html")
-		}
+	publishLogAlertStateChange(ctx, redisClient, alert, redis.AlertStateAlerting, end)
 
-		for _, email := range emailsToNotify {
-			if err := Email.SendReactEmailAlert(ctx, MailClient, *email, emailHtml, subjectLine); err != nil {
-				log.WithContext(ctx).Error(err)
-			}
+	if err := tempalerts.SendSlackLogAlert(ctx, DB, alert, &tempalerts.SendSlackAlertForLogAlertInput{Body: body, Workspace: &workspace, StartDate: start, EndDate: end}); err != nil {
+		log.WithContext(ctx).Error("error sending slack alert for log alert", err)
+	}
+
+	if err = alerts.SendLogAlert(alerts.LogAlertEvent{
+		LogAlert:  alert,
+		Workspace: &workspace,
+		Count:     count,
+		StartDate: start,
+		EndDate:   end,
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	emailsToNotify, err := model.GetEmailsToNotify(alert.EmailsToNotify)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	logsUrl := tempalerts.GetLogAlertURL(alert.ProjectID, alert.Query, start, end)
+	frontendURL := os.Getenv("FRONTEND_URI")
+	alertUrl := fmt.Sprintf("%s/%d/alerts/logs/%d", frontendURL, alert.ProjectID, alert.ID)
+
+	if err := alerts.StartEscalation(ctx, DB, alerts.EscalationEvent{
+		EscalationPolicyID: alert.EscalationPolicyID,
+		ProjectID:          alert.ProjectID,
+		AlertType:          model.AlertType.LOG,
+		AlertID:            alert.ID,
+		DedupKey:           alerts.AlertEscalationKey(model.AlertType.LOG, alert.ID),
+		Title:              alert.Name,
+		Message:            body,
+		URL:                logsUrl,
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	templateData := map[string]interface{}{
+		"alertLink":      alertUrl,
+		"alertName":      alert.Name,
+		"belowThreshold": alert.BelowThreshold,
+		"count":          count,
+		"logsLink":       logsUrl,
+		"projectName":    project.Name,
+		"query":          alert.Query,
+		"threshold":      alert.CountThreshold,
+	}
+
+	subjectLine := alert.Name
+	emailHtml, err := lambdaClient.FetchReactEmailHTML(ctx, lambda.ReactEmailTemplateLogAlert, templateData)
+	if err != nil {
+		return errors.Wrap(err, "error fetching email html")
+	}
+
+	for _, email := range emailsToNotify {
+		if err := Email.SendReactEmailAlert(ctx, MailClient, *email, emailHtml, subjectLine); err != nil {
+			log.WithContext(ctx).Error(err)
 		}
 	}
 	return nil
 }
+
+func publishLogAlertStateChange(ctx context.Context, redisClient *redis.Client, alert *model.LogAlert, state redis.AlertState, timestamp time.Time) {
+	if err := redisClient.PublishAlertStateChange(ctx, redis.AlertStateChange{
+		ProjectID: alert.ProjectID,
+		AlertID:   alert.ID,
+		AlertType: model.AlertType.LOG,
+		Title:     alert.Name,
+		State:     state,
+		Timestamp: timestamp,
+	}); err != nil {
+		log.WithContext(ctx).WithError(err).Warn("failed to publish log alert state change")
+	}
+}
+
+// sendLogAlertResolved notifies the slack, webhook, discord and teams destinations of a log alert
+// that its count is back within the threshold.
+func sendLogAlertResolved(ctx context.Context, DB *gorm.DB, redisClient *redis.Client, alert *model.LogAlert, workspace *model.Workspace, count int, start time.Time, end time.Time) error {
+	log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Resolving alert for %s", alert.Name))
+
+	publishLogAlertStateChange(ctx, redisClient, alert, redis.AlertStateNormal, end)
+
+	if err := alerts.ResolveEscalations(ctx, DB, alert.ProjectID, alerts.AlertEscalationKey(model.AlertType.LOG, alert.ID)); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	body := fmt.Sprintf("Log count is back within the threshold.\n_Count_: %d | _Threshold_: %d", count, alert.CountThreshold)
+	if err := tempalerts.SendSlackLogAlert(ctx, DB, alert, &tempalerts.SendSlackAlertForLogAlertInput{Body: body, Workspace: workspace, StartDate: start, EndDate: end, Resolved: true}); err != nil {
+		log.WithContext(ctx).Error("error sending slack resolution for log alert", err)
+	}
+
+	return alerts.SendLogAlert(alerts.LogAlertEvent{
+		LogAlert:  alert,
+		Workspace: workspace,
+		Count:     count,
+		StartDate: start,
+		EndDate:   end,
+		Resolved:  true,
+	})
+}
@@ -0,0 +1,130 @@
+package log_forwarding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/datadog"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	evalFreq = time.Minute
+	// logs are written by the batched kafka worker, so give them time to arrive before forwarding
+	ingestDelay = 2 * time.Minute
+	// limit how far back windows missed while no worker was running are forwarded
+	maxBackfill = time.Hour
+	// the logs of a window are read and sent in pages of this many rows
+	pageSize = 5000
+)
+
+// WatchLogForwarders forwards the logs ingested by every enabled log forwarder's project every
+// minute. Each minute of logs is forwarded at most once unless sending it fails part way through,
+// in which case the whole minute is retried.
+func WatchLogForwarders(ctx context.Context, DB *gorm.DB, clickhouseClient *clickhouse.Client, redisClient *redis.Client) {
+	log.WithContext(ctx).Info("Starting to forward logs")
+
+	for range time.NewTicker(evalFreq).C {
+		var forwarders []*model.DatadogLogForwarder
+		if err := DB.WithContext(ctx).Where(&model.DatadogLogForwarder{Enabled: true}).Find(&forwarders).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for log forwarders")
+			continue
+		}
+
+		now := time.Now()
+		for _, forwarder := range forwarders {
+			if err := forwardLogs(ctx, DB, clickhouseClient, redisClient, forwarder, now); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("project_id", forwarder.ProjectID).Error("error forwarding logs")
+			}
+		}
+	}
+}
+
+func forwardLogs(ctx context.Context, DB *gorm.DB, clickhouseClient *clickhouse.Client, redisClient *redis.Client, forwarder *model.DatadogLogForwarder, now time.Time) error {
+	// only one worker may forward the logs of a project, otherwise they would be sent twice
+	mutex, err := redisClient.AcquireLock(ctx, fmt.Sprintf("datadog-log-forwarder-%d-lock", forwarder.ProjectID), 5*time.Second)
+	if err != nil {
+		return errors.Wrap(err, "error acquiring log forwarder lock")
+	}
+	defer func() {
+		if _, err := mutex.Unlock(); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to release log forwarder lock")
+		}
+	}()
+
+	// another worker may have forwarded logs while the lock was held
+	if err := DB.WithContext(ctx).Take(forwarder, forwarder.ID).Error; err != nil {
+		return errors.Wrap(err, "error querying log forwarder")
+	}
+	if !forwarder.Enabled {
+		return nil
+	}
+
+	end := now.Add(-ingestDelay).Truncate(time.Minute)
+	start := end.Add(-evalFreq)
+	if forwarder.ForwardedUntil != nil {
+		start = *forwarder.ForwardedUntil
+	}
+	if start.Before(end.Add(-maxBackfill)) {
+		start = end.Add(-maxBackfill)
+	}
+
+	client := datadog.NewClient(forwarder.APIKey, datadog.Site(forwarder.Site))
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(evalFreq) {
+		windowEnd := windowStart.Add(evalFreq)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		if err := forwardWindow(ctx, clickhouseClient, client, forwarder, windowStart, windowEnd); err != nil {
+			lastError := err.Error()
+			if updateErr := DB.WithContext(ctx).Model(forwarder).Update("last_error", &lastError).Error; updateErr != nil {
+				log.WithContext(ctx).WithError(updateErr).Error("error updating log forwarder error")
+			}
+			return err
+		}
+		if err := DB.WithContext(ctx).Model(forwarder).Updates(map[string]interface{}{
+			"forwarded_until": windowEnd,
+			"last_error":      nil,
+		}).Error; err != nil {
+			return errors.Wrap(err, "error updating log forwarder progress")
+		}
+		if _, err := mutex.Extend(); err != nil {
+			return errors.Wrap(err, "error extending log forwarder lock")
+		}
+	}
+	return nil
+}
+
+func forwardWindow(ctx context.Context, clickhouseClient *clickhouse.Client, client *datadog.Client, forwarder *model.DatadogLogForwarder, start time.Time, end time.Time) error {
+	var cursor *clickhouse.LogRowCursor
+	for {
+		rows, err := clickhouseClient.ReadLogRows(ctx, forwarder.ProjectID, start, end, cursor, pageSize)
+		if err != nil {
+			return errors.Wrap(err, "error reading logs to forward")
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		logs := make([]*datadog.Log, 0, len(rows))
+		for _, row := range rows {
+			logs = append(logs, datadog.LogFromRow(row, datadog.AttributeMapping(forwarder.AttributeMapping)))
+		}
+		if err := client.SendLogs(ctx, logs); err != nil {
+			return errors.Wrap(err, "error sending logs to Datadog")
+		}
+
+		if len(rows) < pageSize {
+			return nil
+		}
+		last := rows[len(rows)-1]
+		cursor = &clickhouse.LogRowCursor{Timestamp: last.Timestamp, UUID: last.UUID}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/highlight-run/go-resthooks"
 	Email "github.com/highlight-run/highlight/backend/email"
 	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
 	"github.com/highlight-run/highlight/backend/zapier"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -30,13 +31,13 @@ const (
 	sigFigs = 4
 )
 
-func WatchMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, MailClient *sendgrid.Client, rh *resthooks.Resthook) {
+func WatchMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, MailClient *sendgrid.Client, rh *resthooks.Resthook, redisClient *redis.Client) {
 	log.WithContext(ctx).Info("Starting to watch Metric Monitors")
 
 	for range time.Tick(time.Minute * 1) {
 		go func() {
 			metricMonitors := getMetricMonitors(ctx, DB)
-			processMetricMonitors(ctx, DB, ccClient, MailClient, metricMonitors, rh)
+			processMetricMonitors(ctx, DB, ccClient, MailClient, metricMonitors, rh, redisClient)
 		}()
 	}
 }
@@ -52,10 +53,11 @@ func getMetricMonitors(ctx context.Context, DB *gorm.DB) []*model.MetricMonitor
 	return metricMonitors
 }
 
-func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, MailClient *sendgrid.Client, metricMonitors []*model.MetricMonitor, rh *resthooks.Resthook) {
+func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, MailClient *sendgrid.Client, metricMonitors []*model.MetricMonitor, rh *resthooks.Resthook, redisClient *redis.Client) {
 	log.WithContext(ctx).Info("Number of Metric Monitors to Process: ", len(metricMonitors))
 	for _, metricMonitor := range metricMonitors {
 		var value float64
+		var err error
 		end := time.Now()
 		start := end.Add(-time.Minute)
 		resMins := 1
@@ -70,25 +72,41 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 				Value: f.Value,
 			})
 		}
-		payload, err := graph.GetMetricTimeline(context.Background(), ccClient, metricMonitor.ProjectID, metricMonitor.MetricToMonitor, modelInputs.DashboardParamsInput{
-			DateRange: &modelInputs.DateRangeRequiredInput{
-				StartDate: start,
-				EndDate:   end,
-			},
-			ResolutionMinutes: pointy.Int(resMins),
-			Aggregator:        metricMonitor.Aggregator,
-			Units:             metricMonitor.Units,
-			Filters:           filters,
-		})
-		if err != nil {
-			log.WithContext(ctx).Error(err)
-			continue
-		}
-		if len(payload) < 1 {
-			log.WithContext(ctx).Warn("invalid empty metrics payload")
-			continue
+		if clickhouse.IsWebVital(metricMonitor.MetricToMonitor) {
+			// web vitals are stored in their own table, filtered by the page url they were recorded on
+			var url *string
+			for _, f := range filters {
+				if f.Tag == "url" && f.Op == modelInputs.MetricTagFilterOpEquals {
+					url = pointy.String(f.Value)
+				}
+			}
+			value, err = ccClient.QueryWebVitalAggregate(ctx, metricMonitor.ProjectID, metricMonitor.MetricToMonitor, metricMonitor.Aggregator, url, end.Add(-time.Duration(resMins)*time.Minute), end)
+			if err != nil {
+				log.WithContext(ctx).Error(err)
+				continue
+			}
+		} else {
+			var payload []*modelInputs.DashboardPayload
+			payload, err = graph.GetMetricTimeline(context.Background(), ccClient, metricMonitor.ProjectID, metricMonitor.MetricToMonitor, modelInputs.DashboardParamsInput{
+				DateRange: &modelInputs.DateRangeRequiredInput{
+					StartDate: start,
+					EndDate:   end,
+				},
+				ResolutionMinutes: pointy.Int(resMins),
+				Aggregator:        metricMonitor.Aggregator,
+				Units:             metricMonitor.Units,
+				Filters:           filters,
+			})
+			if err != nil {
+				log.WithContext(ctx).Error(err)
+				continue
+			}
+			if len(payload) < 1 {
+				log.WithContext(ctx).Warn("invalid empty metrics payload")
+				continue
+			}
+			value = payload[len(payload)-1].Value
 		}
-		value = payload[len(payload)-1].Value
 
 		log.WithContext(ctx).Infof("Processing %s for Project %d. ID: %d", metricMonitor.Name, metricMonitor.ProjectID, metricMonitor.ID)
 		log.WithContext(ctx).Infof("Current value: %f, Threshold: %f", value, metricMonitor.Threshold)
@@ -136,6 +154,17 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 
 			log.WithContext(ctx).Info(message)
 
+			if err := redisClient.PublishAlertStateChange(ctx, redis.AlertStateChange{
+				ProjectID: metricMonitor.ProjectID,
+				AlertID:   metricMonitor.ID,
+				AlertType: model.AlertType.METRIC_MONITOR,
+				Title:     metricMonitor.Name,
+				State:     redis.AlertStateAlerting,
+				Timestamp: end,
+			}); err != nil {
+				log.WithContext(ctx).WithError(err).Warn("failed to publish metric monitor state change")
+			}
+
 			if err := tempalerts.SendSlackMetricMonitorAlert(ctx, metricMonitor, &tempalerts.SendSlackAlertForMetricMonitorInput{Message: message, Workspace: &workspace}); err != nil {
 				log.WithContext(ctx).Error("error sending slack alert for metric monitor", err)
 			}
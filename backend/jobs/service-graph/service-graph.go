@@ -0,0 +1,62 @@
+package service_graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	evalFreq = time.Minute
+	// spans are written by the batched kafka worker, so give them time to arrive before aggregating
+	ingestDelay = 2 * time.Minute
+	// limit how far back windows missed while no worker was running are aggregated
+	maxBackfill   = time.Hour
+	lastWindowKey = "service-graph-last-window"
+)
+
+// WatchServiceGraph aggregates the requests between services from the ingested traces
+// every minute, so that the service map only needs to read the aggregated edges.
+func WatchServiceGraph(ctx context.Context, clickhouseClient *clickhouse.Client, redisClient *redis.Client) {
+	log.WithContext(ctx).Info("Starting to build the service graph")
+
+	for range time.NewTicker(evalFreq).C {
+		if err := aggregateServiceGraph(ctx, clickhouseClient, redisClient, time.Now()); err != nil {
+			log.WithContext(ctx).WithError(err).Error("error aggregating service graph")
+		}
+	}
+}
+
+func aggregateServiceGraph(ctx context.Context, clickhouseClient *clickhouse.Client, redisClient *redis.Client, now time.Time) error {
+	// only one worker may aggregate a window, otherwise requests would be counted twice
+	mutex, err := redisClient.AcquireLock(ctx, lastWindowKey+"-lock", 5*time.Second)
+	if err != nil {
+		return errors.Wrap(err, "error acquiring service graph lock")
+	}
+	defer func() {
+		if _, err := mutex.Unlock(); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to release service graph lock")
+		}
+	}()
+
+	end := now.Add(-ingestDelay).Truncate(time.Minute)
+	start := end.Add(-evalFreq)
+	if last, err := redisClient.Client.Get(ctx, lastWindowKey).Int64(); err == nil {
+		start = time.Unix(last, 0)
+	}
+	if start.Before(end.Add(-maxBackfill)) {
+		start = end.Add(-maxBackfill)
+	}
+	if !start.Before(end) {
+		return nil
+	}
+
+	if err := clickhouseClient.AggregateServiceGraph(ctx, start, end); err != nil {
+		return errors.Wrap(err, "error aggregating service graph edges")
+	}
+	return redisClient.Client.Set(ctx, lastWindowKey, end.Unix(), 0).Err()
+}
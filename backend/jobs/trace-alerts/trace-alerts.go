@@ -0,0 +1,169 @@
+package trace_alerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/highlight-run/workerpool"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const maxWorkers = 20
+const evalFreq = time.Minute
+const defaultWindow = 5 * time.Minute
+
+// WatchTraceAlerts evaluates every enabled trace alert against the spans of its window each minute.
+// Trace alerts share the threshold and state machinery of log alerts, notifying once when they fire
+// and once when they resolve.
+func WatchTraceAlerts(ctx context.Context, DB *gorm.DB, redisClient *redis.Client, ccClient *clickhouse.Client) {
+	log.WithContext(ctx).Info("Starting to watch trace alerts")
+
+	alertWorkerpool := workerpool.New(maxWorkers)
+	alertWorkerpool.SetPanicHandler(util.Recover)
+
+	for range time.NewTicker(evalFreq).C {
+		var traceAlerts []*model.TraceAlert
+		if err := DB.WithContext(ctx).Model(&model.TraceAlert{}).Where("disabled = ?", false).Find(&traceAlerts).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for trace alerts")
+			continue
+		}
+
+		for _, alert := range traceAlerts {
+			// copy `alert` by value so each call to processTraceAlert references a different alert
+			alert := alert
+			alertWorkerpool.SubmitRecover(func() {
+				if err := processTraceAlert(ctx, DB, redisClient, ccClient, alert); err != nil {
+					log.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("error processing trace alert")
+				}
+			})
+		}
+	}
+}
+
+func processTraceAlert(ctx context.Context, DB *gorm.DB, redisClient *redis.Client, ccClient *clickhouse.Client, alert *model.TraceAlert) error {
+	// like log alerts, the window ends a minute ago so that it only has spans that were ingested
+	end := time.Now().Add(-time.Minute)
+	window := time.Duration(alert.WindowMinutes) * time.Minute
+	if window <= 0 {
+		window = defaultWindow
+	}
+	start := end.Add(-window)
+
+	aggregator := alert.Aggregator
+	if aggregator == "" {
+		aggregator = modelInputs.MetricAggregatorP95
+	}
+	query := alert.GetQuery()
+	aggregate, err := ccClient.ReadTracesAggregate(ctx, alert.ProjectID, modelInputs.QueryInput{
+		Query: query,
+		DateRange: &modelInputs.DateRangeRequiredInput{
+			StartDate: start,
+			EndDate:   end,
+		},
+	}, aggregator)
+	if err != nil {
+		return errors.Wrap(err, "error querying clickhouse for trace aggregate")
+	}
+
+	value := alert.GetValue(aggregate.SpanCount, aggregate.ErrorCount, aggregate.Latency)
+	// a window with too few spans to be significant does not fire, and resolves a firing alert
+	minSpans := uint64(1)
+	if alert.MinSpans > 1 {
+		minSpans = uint64(alert.MinSpans)
+	}
+	alertCondition := aggregate.SpanCount >= minSpans && alerts.IsThresholdCrossed(value, alert.Threshold, false)
+
+	log.WithContext(ctx).WithFields(log.Fields{
+		"id":         alert.ID,
+		"query":      query,
+		"metric":     alert.Metric,
+		"start":      start.Format(time.RFC3339),
+		"end":        end.Format(time.RFC3339),
+		"span_count": aggregate.SpanCount,
+		"value":      value,
+		"threshold":  alert.Threshold,
+		"alerting":   alertCondition,
+	}).Info("evaluated trace alert")
+
+	state, changed, err := alerts.TransitionAlertState(ctx, DB, &model.TraceAlert{}, alert.ID, alertCondition, end)
+	if err != nil {
+		return errors.Wrap(err, "error transitioning trace alert state")
+	}
+	if !changed {
+		return nil
+	}
+
+	var project model.Project
+	if err := DB.WithContext(ctx).Model(&model.Project{}).Where("id = ?", alert.ProjectID).Take(&project).Error; err != nil {
+		return errors.Wrap(err, "error querying project for trace alert")
+	}
+	var workspace model.Workspace
+	if err := DB.WithContext(ctx).Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
+		return errors.Wrap(err, "error querying workspace for trace alert")
+	}
+
+	resolved := state == model.AlertStateNormal
+	redisState := redis.AlertStateAlerting
+	message := fmt.Sprintf("*%s*: the %s is %s, over the threshold of %s.", alert.Name, alert.Describe(), alert.FormatValue(value), alert.FormatValue(alert.Threshold))
+	if resolved {
+		redisState = redis.AlertStateNormal
+		message = fmt.Sprintf("*%s* resolved: the %s is back to %s, within the threshold of %s.", alert.Name, alert.Describe(), alert.FormatValue(value), alert.FormatValue(alert.Threshold))
+	}
+
+	log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Trace alert %s is %s", alert.Name, state))
+
+	if err := redisClient.PublishAlertStateChange(ctx, redis.AlertStateChange{
+		ProjectID: alert.ProjectID,
+		AlertID:   alert.ID,
+		AlertType: model.AlertType.TRACE,
+		Title:     alert.Name,
+		State:     redisState,
+		Timestamp: end,
+	}); err != nil {
+		log.WithContext(ctx).WithError(err).Warn("failed to publish trace alert state change")
+	}
+
+	alertURL := tempalerts.GetTraceAlertURL(alert.ProjectID, query, start, end)
+
+	escalationKey := alerts.AlertEscalationKey(model.AlertType.TRACE, alert.ID)
+	if resolved {
+		err = alerts.ResolveEscalations(ctx, DB, alert.ProjectID, escalationKey)
+	} else {
+		err = alerts.StartEscalation(ctx, DB, alerts.EscalationEvent{
+			EscalationPolicyID: alert.EscalationPolicyID,
+			ProjectID:          alert.ProjectID,
+			AlertType:          model.AlertType.TRACE,
+			AlertID:            alert.ID,
+			DedupKey:           escalationKey,
+			Title:              alert.Name,
+			Message:            fmt.Sprintf("The %s is %s, over the threshold of %s.", alert.Describe(), alert.FormatValue(value), alert.FormatValue(alert.Threshold)),
+			URL:                alertURL,
+		})
+	}
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("error escalating trace alert")
+	}
+
+	if err := tempalerts.SendSlackTraceAlert(ctx, alert, &tempalerts.SendSlackAlertForTraceAlertInput{Message: message, Workspace: &workspace, AlertURL: alertURL}); err != nil {
+		log.WithContext(ctx).Error("error sending slack alert for trace alert", err)
+	}
+
+	return alerts.SendTraceAlert(alerts.TraceAlertEvent{
+		TraceAlert: alert,
+		Workspace:  &workspace,
+		Value:      value,
+		SpanCount:  aggregate.SpanCount,
+		AlertURL:   alertURL,
+		Resolved:   resolved,
+	})
+}
@@ -0,0 +1,357 @@
+package uptime_monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/highlight-run/workerpool"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const maxWorkers = 40
+const checkEvalFreq = 5 * time.Second
+const minIntervalSeconds = 15
+
+// only read this much of a response body when asserting on its contents
+const maxBodyBytes = 1 << 20
+
+type CheckResult struct {
+	Success       bool
+	StatusCode    int
+	Latency       time.Duration
+	FailureReason string
+}
+
+// monitorState tracks the current run of failures for a monitor between checks.
+type monitorState struct {
+	failedChecks int
+	firstFailure time.Time
+}
+
+type watcher struct {
+	db       *gorm.DB
+	ccClient *clickhouse.Client
+	redis    *redis.Client
+	client   *http.Client
+
+	mu     sync.Mutex
+	states map[int]*monitorState
+}
+
+func WatchUptimeMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, redisClient *redis.Client) {
+	log.WithContext(ctx).Info("Starting to watch uptime monitors")
+
+	w := &watcher{
+		db:       DB,
+		ccClient: ccClient,
+		redis:    redisClient,
+		client: &http.Client{
+			// monitor urls are user supplied, so internal addresses are refused
+			Transport: &http.Transport{
+				DialContext:         util.NewPublicDialer(0).DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			// do not follow redirects so that status code assertions see the original response
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		states: map[int]*monitorState{},
+	}
+
+	monitorsByInterval := &map[int64][]*model.UptimeMonitor{}
+
+	getMonitors := func() {
+		bucketed := map[int64][]*model.UptimeMonitor{}
+
+		monitors := getUptimeMonitors(ctx, DB)
+		for _, monitor := range monitors {
+			interval := int64(monitor.IntervalSeconds)
+			if interval < minIntervalSeconds {
+				interval = minIntervalSeconds
+			}
+			bucketed[interval] = append(bucketed[interval], monitor)
+		}
+
+		monitorsByInterval = &bucketed
+		log.WithContext(ctx).Infof("Watching %d uptime monitors", len(monitors))
+	}
+
+	getMonitors()
+	go func() {
+		// Every minute, check for new monitors and bucket by interval
+		for range time.Tick(time.Minute) {
+			getMonitors()
+		}
+	}()
+
+	checkWorkerpool := workerpool.New(maxWorkers)
+	checkWorkerpool.SetPanicHandler(util.Recover)
+
+	startTime := time.Now().Unix()
+	for range time.NewTicker(checkEvalFreq).C {
+		curTime := time.Now().Unix()
+		for interval, monitors := range *monitorsByInterval {
+			// If at least one interval has passed since the last loop,
+			// run the checks for this bucket
+			if (curTime / interval) > (startTime / interval) {
+				for _, monitor := range monitors {
+					monitor := monitor
+					checkWorkerpool.SubmitRecover(
+						func() {
+							ctx := context.Background()
+							if err := w.processUptimeMonitor(ctx, monitor); err != nil {
+								log.WithContext(ctx).WithField("monitor_id", monitor.ID).Error(err)
+							}
+						})
+				}
+			}
+		}
+		startTime = curTime
+	}
+}
+
+func getUptimeMonitors(ctx context.Context, DB *gorm.DB) []*model.UptimeMonitor {
+	var monitors []*model.UptimeMonitor
+	if err := DB.Model(&model.UptimeMonitor{}).Where("disabled = ?", false).Find(&monitors).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WithContext(ctx).Error("Error querying for uptime monitors")
+		}
+	}
+
+	return monitors
+}
+
+func (w *watcher) processUptimeMonitor(ctx context.Context, monitor *model.UptimeMonitor) error {
+	now := time.Now()
+	result := RunCheck(ctx, w.client, monitor)
+
+	row := clickhouse.NewUptimeCheckRow(now, monitor.ProjectID, monitor.ID)
+	row.Success = result.Success
+	row.StatusCode = uint16(result.StatusCode)
+	row.LatencyMs = uint32(result.Latency.Milliseconds())
+	row.FailureReason = result.FailureReason
+	if err := w.ccClient.WriteUptimeChecks(ctx, []*clickhouse.UptimeCheckRow{row}); err != nil {
+		return errors.Wrap(err, "error writing uptime check")
+	}
+
+	w.mu.Lock()
+	state, ok := w.states[monitor.ID]
+	if !ok {
+		state = &monitorState{}
+		w.states[monitor.ID] = state
+	}
+	previousFailedChecks := state.failedChecks
+	if result.Success {
+		state.failedChecks = 0
+	} else {
+		if state.failedChecks == 0 {
+			state.firstFailure = now
+		}
+		state.failedChecks += 1
+	}
+	failedChecks, firstFailure := state.failedChecks, state.firstFailure
+	w.mu.Unlock()
+
+	log.WithContext(ctx).WithFields(log.Fields{
+		"monitor_id":    monitor.ID,
+		"url":           monitor.URL,
+		"success":       result.Success,
+		"status_code":   result.StatusCode,
+		"latency_ms":    result.Latency.Milliseconds(),
+		"failed_checks": failedChecks,
+	}).Info("evaluated uptime monitor")
+
+	threshold := monitor.FailureThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	if result.Success && previousFailedChecks >= threshold {
+		w.publishStateChange(ctx, monitor, redis.AlertStateNormal, now)
+	}
+	// alert once per downtime, when the run of failures first crosses the threshold
+	if failedChecks != threshold {
+		return nil
+	}
+
+	w.publishStateChange(ctx, monitor, redis.AlertStateAlerting, now)
+	return w.sendAlert(ctx, monitor, result, failedChecks, firstFailure, now)
+}
+
+func (w *watcher) publishStateChange(ctx context.Context, monitor *model.UptimeMonitor, state redis.AlertState, now time.Time) {
+	if err := w.redis.PublishAlertStateChange(ctx, redis.AlertStateChange{
+		ProjectID: monitor.ProjectID,
+		AlertID:   monitor.ID,
+		AlertType: model.AlertType.UPTIME,
+		Title:     monitor.Name,
+		State:     state,
+		Timestamp: now,
+	}); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("monitor_id", monitor.ID).Warn("failed to publish uptime monitor state change")
+	}
+}
+
+func (w *watcher) sendAlert(ctx context.Context, monitor *model.UptimeMonitor, result *CheckResult, failedChecks int, firstFailure time.Time, now time.Time) error {
+	var project model.Project
+	if err := w.db.Model(&model.Project{}).Where("id = ?", monitor.ProjectID).Take(&project).Error; err != nil {
+		return errors.Wrap(err, "error querying project for uptime monitor")
+	}
+	var workspace model.Workspace
+	if err := w.db.Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
+		return errors.Wrap(err, "error querying workspace for uptime monitor")
+	}
+
+	// correlate the downtime with backend errors reported for the project,
+	// compared to a window of the same length before the downtime started
+	duration := now.Sub(firstFailure)
+	if duration < time.Minute {
+		duration = time.Minute
+	}
+	start := now.Add(-duration)
+	errorCount, err := w.ccClient.QueryErrorObjectCount(ctx, monitor.ProjectID, start, now)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("error querying error count for uptime monitor")
+	}
+	baselineErrorCount, err := w.ccClient.QueryErrorObjectCount(ctx, monitor.ProjectID, start.Add(-duration), start)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("error querying baseline error count for uptime monitor")
+	}
+
+	log.WithContext(ctx).WithField("monitor_id", monitor.ID).Info(fmt.Sprintf("Firing uptime alert for %s", monitor.Name))
+
+	message := fmt.Sprintf("*%s* (%s) failed %d consecutive checks: %s", monitor.Name, monitor.URL, failedChecks, result.FailureReason)
+	if errorCount > baselineErrorCount {
+		message += fmt.Sprintf("\nBackend errors rose to %d during the downtime (previously %d).", errorCount, baselineErrorCount)
+	}
+	if err := tempalerts.SendSlackUptimeMonitorAlert(ctx, monitor, &tempalerts.SendSlackAlertForUptimeMonitorInput{Message: message, Workspace: &workspace}); err != nil {
+		log.WithContext(ctx).Error("error sending slack alert for uptime monitor", err)
+	}
+
+	return alerts.SendUptimeMonitorAlert(alerts.UptimeMonitorAlertEvent{
+		UptimeMonitor:      monitor,
+		Workspace:          &workspace,
+		FailedChecks:       failedChecks,
+		FailureReason:      result.FailureReason,
+		StatusCode:         result.StatusCode,
+		LatencyMs:          int(result.Latency.Milliseconds()),
+		ErrorCount:         errorCount,
+		BaselineErrorCount: baselineErrorCount,
+	})
+}
+
+// RunCheck performs a single check for the monitor and evaluates its assertions.
+func RunCheck(ctx context.Context, client *http.Client, monitor *model.UptimeMonitor) *CheckResult {
+	timeout := time.Duration(monitor.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var result *CheckResult
+	if monitor.Type == model.UptimeMonitorTypePing {
+		result = checkPing(ctx, monitor)
+	} else {
+		result = checkHTTP(ctx, client, monitor)
+	}
+
+	if result.Success && monitor.MaxLatencyMs != nil && result.Latency > time.Duration(*monitor.MaxLatencyMs)*time.Millisecond {
+		result.Success = false
+		result.FailureReason = fmt.Sprintf("latency %dms exceeded %dms", result.Latency.Milliseconds(), *monitor.MaxLatencyMs)
+	}
+	return result
+}
+
+func checkHTTP(ctx context.Context, client *http.Client, monitor *model.UptimeMonitor) *CheckResult {
+	method := monitor.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, monitor.URL, nil)
+	if err != nil {
+		return &CheckResult{FailureReason: fmt.Sprintf("invalid request: %s", err)}
+	}
+	req.Header.Set("User-Agent", "highlight-uptime-monitor")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return &CheckResult{Latency: time.Since(start), FailureReason: dialFailureReason(err)}
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	if monitor.BodyContains != nil {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		if err != nil {
+			return &CheckResult{StatusCode: resp.StatusCode, Latency: time.Since(start), FailureReason: fmt.Sprintf("failed to read body: %s", err)}
+		}
+	}
+	result := &CheckResult{StatusCode: resp.StatusCode, Latency: time.Since(start)}
+
+	if monitor.ExpectedStatusCode != nil {
+		if resp.StatusCode != *monitor.ExpectedStatusCode {
+			result.FailureReason = fmt.Sprintf("expected status %d, got %d", *monitor.ExpectedStatusCode, resp.StatusCode)
+			return result
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.FailureReason = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+		return result
+	}
+
+	if monitor.BodyContains != nil && !strings.Contains(string(body), *monitor.BodyContains) {
+		result.FailureReason = fmt.Sprintf("body does not contain %q", *monitor.BodyContains)
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+func checkPing(ctx context.Context, monitor *model.UptimeMonitor) *CheckResult {
+	address := monitor.URL
+	if u, err := url.Parse(monitor.URL); err == nil && u.Host != "" {
+		address = u.Host
+		if u.Port() == "" {
+			port := "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+			address = net.JoinHostPort(u.Hostname(), port)
+		}
+	}
+
+	start := time.Now()
+	conn, err := util.NewPublicDialer(0).DialContext(ctx, "tcp", address)
+	latency := time.Since(start)
+	if err != nil {
+		return &CheckResult{Latency: latency, FailureReason: dialFailureReason(err)}
+	}
+	_ = conn.Close()
+
+	return &CheckResult{Success: true, Latency: latency}
+}
+
+// dialFailureReason returns the failure reason of a check that could not connect, without the
+// resolved address of a host that is not public.
+func dialFailureReason(err error) string {
+	if errors.Is(err, util.ErrNonPublicAddress) {
+		return util.ErrNonPublicAddress.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,131 @@
+package warehouse_export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/warehouse"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	evalFreq = time.Hour
+	// days are exported once the data of the day has been ingested and sessions have been processed
+	exportDelay = 3 * time.Hour
+	// limit how many days missed while no worker was running are exported
+	maxBackfillDays = 7
+	// the lock of an export is held while a day is exported, and extended after every table
+	lockTimeout = 30 * time.Minute
+)
+
+// WatchWarehouseExports exports the days that have ended of every enabled warehouse export's
+// project every hour. Each day is loaded into the warehouse once all of its tables are exported,
+// and a day that fails part way through is exported again, replacing the rows it loaded.
+func WatchWarehouseExports(ctx context.Context, DB *gorm.DB, clickhouseClient *clickhouse.Client, redisClient *redis.Client) {
+	log.WithContext(ctx).Info("Starting to export to warehouses")
+
+	for range time.NewTicker(evalFreq).C {
+		var exports []*model.WarehouseExport
+		if err := DB.WithContext(ctx).Where(&model.WarehouseExport{Enabled: true}).Find(&exports).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying for warehouse exports")
+			continue
+		}
+
+		now := time.Now()
+		for _, export := range exports {
+			if err := exportDays(ctx, DB, clickhouseClient, redisClient, export, now); err != nil {
+				log.WithContext(ctx).WithError(err).
+					WithField("project_id", export.ProjectID).
+					WithField("destination", export.Destination).
+					Error("error exporting to warehouse")
+			}
+		}
+	}
+}
+
+func exportDays(ctx context.Context, DB *gorm.DB, clickhouseClient *clickhouse.Client, redisClient *redis.Client, export *model.WarehouseExport, now time.Time) error {
+	// only one worker may export a project, otherwise its days would be loaded twice
+	mutex, err := redisClient.AcquireLock(ctx, fmt.Sprintf("warehouse-export-%d-lock", export.ProjectID), lockTimeout)
+	if err != nil {
+		return errors.Wrap(err, "error acquiring warehouse export lock")
+	}
+	defer func() {
+		if _, err := mutex.Unlock(); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to release warehouse export lock")
+		}
+	}()
+
+	// another worker may have exported days while the lock was held
+	if err := DB.WithContext(ctx).Take(export, export.ID).Error; err != nil {
+		return errors.Wrap(err, "error querying warehouse export")
+	}
+	if !export.Enabled {
+		return nil
+	}
+
+	end := now.Add(-exportDelay).UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -1)
+	if export.ExportedUntil != nil {
+		start = export.ExportedUntil.UTC()
+	}
+	if start.Before(end.AddDate(0, 0, -maxBackfillDays)) {
+		start = end.AddDate(0, 0, -maxBackfillDays)
+	}
+	if !start.Before(end) {
+		return nil
+	}
+
+	storage, err := warehouse.NewStorage(export)
+	if err != nil {
+		return setLastError(ctx, DB, export, err)
+	}
+	loader, err := warehouse.NewLoader(ctx, export)
+	if err != nil {
+		return setLastError(ctx, DB, export, err)
+	}
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, table := range warehouse.ExportTables(export) {
+			if err := exportTable(ctx, clickhouseClient, loader, storage, export, table, day); err != nil {
+				return setLastError(ctx, DB, export, err)
+			}
+			if _, err := mutex.Extend(); err != nil {
+				return errors.Wrap(err, "error extending warehouse export lock")
+			}
+		}
+
+		if err := DB.WithContext(ctx).Model(export).Updates(map[string]interface{}{
+			"exported_until": day.AddDate(0, 0, 1),
+			"schema_version": warehouse.SchemaVersion,
+			"last_error":     nil,
+		}).Error; err != nil {
+			return errors.Wrap(err, "error updating warehouse export progress")
+		}
+	}
+	return nil
+}
+
+// exportTable writes the Parquet file of a table for a day to the bucket and loads it.
+func exportTable(ctx context.Context, clickhouseClient *clickhouse.Client, loader warehouse.Loader, storage *warehouse.Storage, export *model.WarehouseExport, table *warehouse.Table, day time.Time) error {
+	if err := clickhouseClient.ExportParquet(ctx, storage.Destination(table, day), table.Select(export.ProjectID, day, export.LogSampleRate)); err != nil {
+		return errors.Wrapf(err, "error exporting %s of %s", table.Name, day.Format(time.DateOnly))
+	}
+	if err := loader.Load(ctx, table, day, storage); err != nil {
+		return errors.Wrapf(err, "error loading %s of %s", table.Name, day.Format(time.DateOnly))
+	}
+	return nil
+}
+
+func setLastError(ctx context.Context, DB *gorm.DB, export *model.WarehouseExport, err error) error {
+	lastError := err.Error()
+	if updateErr := DB.WithContext(ctx).Model(export).Update("last_error", &lastError).Error; updateErr != nil {
+		log.WithContext(ctx).WithError(updateErr).Error("error updating warehouse export error")
+	}
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/highlight-run/highlight/backend/chaos"
 	"github.com/highlight-run/highlight/backend/util"
 	hmetric "github.com/highlight/highlight/sdk/highlight-go/metric"
 	"github.com/pkg/errors"
@@ -94,6 +95,9 @@ type ConfigOverride struct {
 	MinBytes         *int
 	MaxWait          *time.Duration
 	MessageSizeBytes *int64
+	// GroupID consumes with a separate consumer group, ie. to inspect a topic without taking messages from workers
+	GroupID     *string
+	StartOffset *int64
 }
 
 func New(ctx context.Context, topic string, mode Mode, configOverride *ConfigOverride) *Queue {
@@ -261,6 +265,13 @@ func New(ctx context.Context, topic string, mode Mode, configOverride *ConfigOve
 				config.MaxBytes = int(*deref.MessageSizeBytes)
 				pool.MessageSizeBytes = *deref.MessageSizeBytes
 			}
+			if deref.GroupID != nil {
+				config.GroupID = *deref.GroupID
+				pool.ConsumerGroup = *deref.GroupID
+			}
+			if deref.StartOffset != nil {
+				config.StartOffset = *deref.StartOffset
+			}
 		}
 
 		if !util.IsDevOrTestEnv() {
@@ -304,6 +315,10 @@ func (p *Queue) Stop(ctx context.Context) {
 
 func (p *Queue) Submit(ctx context.Context, partitionKey string, messages ...*Message) error {
 	start := time.Now()
+	if err := chaos.Inject(ctx, chaos.SubsystemKafka); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("topic", p.Topic).Warn("failed to send kafka messages")
+		return err
+	}
 	if partitionKey == "" {
 		partitionKey = util.GenerateRandomString(32)
 	}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/symbolication"
 
 	customModels "github.com/highlight-run/highlight/backend/public-graph/graph/model"
 	"github.com/segmentio/kafka-go"
@@ -34,6 +35,12 @@ const (
 	ErrorGroupDataSync                     PayloadType = iota
 	ErrorObjectDataSync                    PayloadType = iota
 	PushCompressedPayload                  PayloadType = iota
+	PushHeartbeatCheckIn                   PayloadType = iota
+	PushMobileCrash                        PayloadType = iota
+	PushWebVital                           PayloadType = iota
+	PushOTeLMetrics                        PayloadType = iota
+	PushSessionNetworkResources            PayloadType = iota
+	PushSegmentEvent                       PayloadType = iota
 	HealthCheck                            PayloadType = math.MaxInt
 )
 
@@ -96,6 +103,8 @@ type PushBackendPayloadArgs struct {
 	ProjectVerboseID *string
 	SessionSecureID  *string
 	Errors           []*customModels.BackendErrorObjectInput
+	// DedupeKey identifies the errors of a retried request so that they are only written once.
+	DedupeKey string
 }
 
 type PushMetricsArgs struct {
@@ -114,12 +123,58 @@ type AddSessionFeedbackArgs struct {
 
 type PushLogsArgs struct {
 	LogRow *clickhouse.LogRow
+	// DedupeKey identifies the log of a retried request so that it is only written once.
+	DedupeKey string
 }
 
 type PushTracesArgs struct {
 	TraceRow *clickhouse.TraceRow
 }
 
+type PushHeartbeatCheckInArgs struct {
+	CheckInRow *clickhouse.HeartbeatCheckInRow
+}
+
+type PushMobileCrashArgs struct {
+	ProjectVerboseID string
+	Report           *symbolication.CrashReport
+}
+
+type PushWebVitalArgs struct {
+	WebVitalRow *clickhouse.WebVitalRow
+}
+
+type PushOTeLMetricsArgs struct {
+	MetricRows []*clickhouse.MetricRow
+}
+
+// PushSessionNetworkResourcesArgs adds network resources observed by otel spans to a session.
+// Resources is serialized like the resources of a PushPayload, ie. `{"resources": [...]}`.
+type PushSessionNetworkResourcesArgs struct {
+	SessionSecureID string
+	Resources       string
+}
+
+// PushSegmentEventArgs is an identify or track call received from a Segment destination. Segment
+// calls are not sent with a session, which is resolved from the call when it is processed.
+type PushSegmentEventArgs struct {
+	ProjectID int
+	// Type is the type of the call, ie. `identify` or `track`.
+	Type string
+	// SessionSecureID is the highlight.session_id of the context of the call, when it is set.
+	SessionSecureID string
+	UserID          string
+	Email           string
+	IP              string
+	// Event is the name of the event of a track call.
+	Event string
+	// Properties are the traits of an identify call or the properties of a track call.
+	Properties map[string]interface{}
+	Timestamp  time.Time
+	// DedupeKey identifies the call of a retried request so that it is only processed once.
+	DedupeKey string
+}
+
 type SessionDataSyncArgs struct {
 	SessionID int
 }
@@ -151,6 +206,13 @@ type Message struct {
 	ErrorGroupDataSync    *ErrorGroupDataSyncArgs    `json:",omitempty"`
 	ErrorObjectDataSync   *ErrorObjectDataSyncArgs   `json:",omitempty"`
 	PushCompressedPayload *PushCompressedPayloadArgs `json:",omitempty"`
+	PushHeartbeatCheckIn  *PushHeartbeatCheckInArgs  `json:",omitempty"`
+	PushMobileCrash       *PushMobileCrashArgs       `json:",omitempty"`
+	PushWebVital          *PushWebVitalArgs          `json:",omitempty"`
+	PushOTeLMetrics       *PushOTeLMetricsArgs       `json:",omitempty"`
+
+	PushSessionNetworkResources *PushSessionNetworkResourcesArgs `json:",omitempty"`
+	PushSegmentEvent            *PushSegmentEventArgs            `json:",omitempty"`
 }
 
 type PartitionMessage struct {
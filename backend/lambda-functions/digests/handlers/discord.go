@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/lambda-functions/digests/utils"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// digestEmbedColor is the color of the Highlight Discord embeds.
+const digestEmbedColor = 0x6c37f4
+
+// maxEmbedFieldLength is the longest value of an embed field accepted by Discord.
+const maxEmbedFieldLength = 1024
+
+// discordLink returns a markdown link whose label fits in a Discord embed field.
+func discordLink(label string, url string) string {
+	label = strings.Join(strings.Fields(label), " ")
+	label = strings.NewReplacer("[", "(", "]", ")").Replace(label)
+	if runes := []rune(label); len(runes) > 50 {
+		label = string(runes[:50]) + "..."
+	}
+	return fmt.Sprintf("[%s](%s)", label, url)
+}
+
+func digestListField(name string, lines []string) *discordgo.MessageEmbedField {
+	value := ""
+	for _, line := range lines {
+		if len(value)+len(line)+1 > maxEmbedFieldLength {
+			break
+		}
+		value += line + "\n"
+	}
+	return &discordgo.MessageEmbedField{
+		Name:   name,
+		Value:  value,
+		Inline: false,
+	}
+}
+
+// getDigestPeriodName returns the name of the period of a digest, ie. `Weekly`.
+func getDigestPeriodName(frequency string) string {
+	if frequency == model.DigestFrequencyDaily {
+		return "Daily"
+	}
+	return "Weekly"
+}
+
+func digestDiscordMessage(input utils.DigestDataResponse) *discordgo.MessageSend {
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Users", Value: fmt.Sprintf("%s (%s)", input.UserCount, input.UserDelta), Inline: true},
+		{Name: "Sessions", Value: fmt.Sprintf("%s (%s)", input.SessionCount, input.SessionDelta), Inline: true},
+		{Name: "Errors", Value: fmt.Sprintf("%s (%s)", input.ErrorCount, input.ErrorDelta), Inline: true},
+		{Name: "Total Activity", Value: fmt.Sprintf("%s (%s)", input.ActivityTotal, input.ActivityDelta), Inline: true},
+	}
+	if input.LogCount != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Logs", Value: fmt.Sprintf("%s (%s)", input.LogCount, input.LogDelta), Inline: true})
+	}
+
+	if len(input.NewErrors) > 0 {
+		var lines []string
+		for _, item := range input.NewErrors {
+			lines = append(lines, fmt.Sprintf("%s: %s affected users", discordLink(item.Message, item.URL), item.AffectedUserCount))
+		}
+		fields = append(fields, digestListField("New Errors", lines))
+	}
+
+	if len(input.FrequentErrors) > 0 {
+		var lines []string
+		for _, item := range input.FrequentErrors {
+			lines = append(lines, fmt.Sprintf("%s: %s occurrences (%s)", discordLink(item.Message, item.URL), item.Count, item.Delta))
+		}
+		fields = append(fields, digestListField("Frequent Errors", lines))
+	}
+
+	if len(input.ActiveSessions) > 0 {
+		var lines []string
+		for _, item := range input.ActiveSessions {
+			lines = append(lines, fmt.Sprintf("%s: %s active", discordLink(item.Identifier, item.URL), item.ActiveLength))
+		}
+		fields = append(fields, digestListField("Most Active Sessions", lines))
+	}
+
+	if len(input.ErrorSessions) > 0 {
+		var lines []string
+		for _, item := range input.ErrorSessions {
+			lines = append(lines, fmt.Sprintf("%s: %s errors", discordLink(item.Identifier, item.URL), item.ErrorCount))
+		}
+		fields = append(fields, digestListField("Sessions With Errors", lines))
+	}
+
+	if len(input.WebVitals) > 0 {
+		var lines []string
+		for _, item := range input.WebVitals {
+			lines = append(lines, fmt.Sprintf("%s: %s p75 (%s)", item.Name, item.P75, item.Delta))
+		}
+		fields = append(fields, digestListField("Web Vitals", lines))
+	}
+
+	return &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       fmt.Sprintf("Highlight %s Digest: %s", getDigestPeriodName(input.Frequency), input.ProjectName),
+			Description: fmt.Sprintf("%s - %s", input.StartFmt, input.EndFmt),
+			URL:         fmt.Sprintf("https://app.highlight.io/%d", input.ProjectId),
+			Color:       digestEmbedColor,
+			Fields:      fields,
+		}},
+	}
+}
+
+// sendDigestDiscordMessages posts the digest to the Discord webhooks of the project. A webhook that
+// fails doesn't prevent the digest from being posted to the others.
+func (h *handlers) sendDigestDiscordMessages(ctx context.Context, input utils.DigestDataResponse) error {
+	var project model.Project
+	if err := h.db.WithContext(ctx).Model(&model.Project{}).Select("id", "discord_digest_webhooks").Where("id = ?", input.ProjectId).Take(&project).Error; err != nil {
+		return errors.Wrap(err, "error querying project Discord digest webhooks")
+	}
+
+	for _, webhook := range project.DiscordDigestWebhooks {
+		if err := discord.NewWebhook(webhook).SendMessage(digestDiscordMessage(input)); err != nil {
+			log.WithContext(ctx).WithField("project_id", input.ProjectId).Error(errors.Wrap(err, "error sending digest to Discord webhook"))
+		}
+	}
+	return nil
+}
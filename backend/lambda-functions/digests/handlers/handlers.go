@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"time"
 
@@ -14,10 +15,13 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/highlight-run/highlight/backend/clickhouse"
 	"github.com/highlight-run/highlight/backend/email"
 	"github.com/highlight-run/highlight/backend/lambda-functions/digests/utils"
 	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 )
@@ -29,14 +33,16 @@ type Handlers interface {
 }
 
 type handlers struct {
-	db             *gorm.DB
-	sendgridClient *sendgrid.Client
+	db               *gorm.DB
+	clickhouseClient *clickhouse.Client
+	sendgridClient   *sendgrid.Client
 }
 
-func InitHandlers(db *gorm.DB, sendgridClient *sendgrid.Client) *handlers {
+func InitHandlers(db *gorm.DB, clickhouseClient *clickhouse.Client, sendgridClient *sendgrid.Client) *handlers {
 	return &handlers{
-		db:             db,
-		sendgridClient: sendgridClient,
+		db:               db,
+		clickhouseClient: clickhouseClient,
+		sendgridClient:   sendgridClient,
 	}
 }
 
@@ -47,9 +53,14 @@ func NewHandlers() *handlers {
 		log.WithContext(ctx).Fatal(errors.Wrap(err, "error setting up DB"))
 	}
 
+	clickhouseClient, err := clickhouse.NewClient(clickhouse.PrimaryDatabase)
+	if err != nil {
+		log.WithContext(ctx).Fatal(errors.Wrap(err, "error creating clickhouse client"))
+	}
+
 	sendgridClient := sendgrid.NewSendClient(os.Getenv("SENDGRID_API_KEY"))
 
-	return InitHandlers(db, sendgridClient)
+	return InitHandlers(db, clickhouseClient, sendgridClient)
 }
 
 func (h *handlers) GetProjectIds(ctx context.Context, input utils.DigestsInput) ([]utils.ProjectIdResponse, error) {
@@ -59,12 +70,17 @@ func (h *handlers) GetProjectIds(ctx context.Context, input utils.DigestsInput)
 	start := end.AddDate(0, 0, -7)
 	prior := start.AddDate(0, 0, -7)
 
+	// projects with a digest setting are sent their digests by the digest worker
 	var projectIds []int
 	if err := h.db.Raw(`
 		SELECT project_id
 		FROM daily_session_counts_view
 		WHERE date >= ?
 		AND date < ?
+		AND project_id NOT IN (
+			SELECT project_id
+			FROM project_digest_settings
+		)
 		GROUP BY 1
 		HAVING sum(count) >= 50
 	`, start, end).Scan(&projectIds).Error; err != nil {
@@ -75,6 +91,7 @@ func (h *handlers) GetProjectIds(ctx context.Context, input utils.DigestsInput)
 	for _, id := range projectIds {
 		response = append(response, utils.ProjectIdResponse{
 			ProjectId: id,
+			Frequency: model.DigestFrequencyWeekly,
 			DryRun:    input.DryRun,
 			End:       end,
 			Start:     start,
@@ -309,8 +326,33 @@ func (h *handlers) GetDigestData(ctx context.Context, input utils.ProjectIdRespo
 		})
 	}
 
+	var err error
+	var logCount, prevLogCount uint64
+	webVitals := []utils.WebVitalTrend{}
+	if h.clickhouseClient != nil {
+		if logCount, err = h.clickhouseClient.ReadLogsTotalCount(ctx, input.ProjectId, modelInputs.QueryInput{
+			DateRange: &modelInputs.DateRangeRequiredInput{StartDate: input.Start, EndDate: input.End},
+		}); err != nil {
+			return nil, errors.Wrap(err, "error querying current log count")
+		}
+		if prevLogCount, err = h.clickhouseClient.ReadLogsTotalCount(ctx, input.ProjectId, modelInputs.QueryInput{
+			DateRange: &modelInputs.DateRangeRequiredInput{StartDate: input.Prior, EndDate: input.Start},
+		}); err != nil {
+			return nil, errors.Wrap(err, "error querying previous log count")
+		}
+		if webVitals, err = h.getWebVitalTrends(ctx, input); err != nil {
+			return nil, err
+		}
+	}
+
+	frequency := input.Frequency
+	if frequency == "" {
+		frequency = model.DigestFrequencyWeekly
+	}
+
 	return &utils.DigestDataResponse{
 		ProjectId:      input.ProjectId,
+		Frequency:      frequency,
 		StartFmt:       input.Start.Format("01/02"),
 		EndFmt:         input.End.Format("01/02"),
 		ProjectName:    projectName,
@@ -322,14 +364,72 @@ func (h *handlers) GetDigestData(ctx context.Context, input utils.ProjectIdRespo
 		ErrorDelta:     formatDelta(curErrors - prevErrors),
 		ActivityTotal:  formatDurationSecond(time.Duration(curActivity) * time.Millisecond),
 		ActivityDelta:  formatDurationDelta(time.Duration(curActivity-prevActivity) * time.Millisecond),
+		LogCount:       formatNumber(int(logCount)),
+		LogDelta:       formatDelta(int(logCount) - int(prevLogCount)),
 		ActiveSessions: activeSessions,
 		ErrorSessions:  errorSessions,
 		NewErrors:      newErrors,
 		FrequentErrors: frequentErrors,
+		WebVitals:      webVitals,
 		DryRun:         input.DryRun,
 	}, nil
 }
 
+// getWebVitalTrends returns the 75th percentile of each web vital reported in the period, compared
+// to the prior period.
+func (h *handlers) getWebVitalTrends(ctx context.Context, input utils.ProjectIdResponse) ([]utils.WebVitalTrend, error) {
+	current, err := h.clickhouseClient.QueryWebVitalPercentiles(ctx, input.ProjectId, clickhouse.WebVitalsParams{StartDate: input.Start, EndDate: input.End})
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying current web vitals")
+	}
+	prior, err := h.clickhouseClient.QueryWebVitalPercentiles(ctx, input.ProjectId, clickhouse.WebVitalsParams{StartDate: input.Prior, EndDate: input.Start})
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying previous web vitals")
+	}
+	return getWebVitalTrends(current, prior), nil
+}
+
+func getWebVitalTrends(current []*clickhouse.WebVitalPercentiles, prior []*clickhouse.WebVitalPercentiles) []utils.WebVitalTrend {
+	currentByName := lo.KeyBy(current, func(p *clickhouse.WebVitalPercentiles) string { return p.Name })
+	priorByName := lo.KeyBy(prior, func(p *clickhouse.WebVitalPercentiles) string { return p.Name })
+
+	trends := []utils.WebVitalTrend{}
+	for _, name := range clickhouse.WebVitalNames {
+		cur, ok := currentByName[name]
+		if !ok || cur.Count == 0 {
+			continue
+		}
+		delta := "-"
+		if prev, ok := priorByName[name]; ok && prev.Count > 0 {
+			delta = formatWebVitalDelta(name, cur.P75-prev.P75)
+		}
+		trends = append(trends, utils.WebVitalTrend{
+			Name:  name,
+			P75:   formatWebVital(name, cur.P75),
+			Delta: delta,
+		})
+	}
+	return trends
+}
+
+// formatWebVital formats the value of a web vital, which is in milliseconds except for the unitless CLS.
+func formatWebVital(name string, value float64) string {
+	if name == "CLS" {
+		return fmt.Sprintf("%.3f", value)
+	}
+	return formatNumber(int(math.Round(value))) + "ms"
+}
+
+func formatWebVitalDelta(name string, delta float64) string {
+	if formatWebVital(name, math.Abs(delta)) == formatWebVital(name, 0) {
+		return "-"
+	}
+	if delta > 0 {
+		return "+" + formatWebVital(name, delta)
+	}
+	return "-" + formatWebVital(name, -delta)
+}
+
 func formatNumber(input int) string {
 	p := message.NewPrinter(language.English)
 	return p.Sprintf("%d", input)
@@ -476,6 +576,12 @@ func (h *handlers) SendDigestEmails(ctx context.Context, input utils.DigestDataR
 		return errors.Wrap(err, "error unmarshalling marshalled input")
 	}
 
+	if !input.DryRun {
+		if err := h.sendDigestDiscordMessages(ctx, input); err != nil {
+			log.WithContext(ctx).Error(err)
+		}
+	}
+
 	if input.DryRun {
 		toAddrs = []struct {
 			AdminID int
@@ -9,6 +9,7 @@ type DigestsInput struct {
 
 type ProjectIdResponse struct {
 	ProjectId int       `json:"projectId"`
+	Frequency string    `json:"frequency"`
 	DryRun    bool      `json:"dryRun"`
 	End       time.Time `json:"end"`
 	Start     time.Time `json:"start"`
@@ -75,8 +76,16 @@ type FrequentError struct {
 	URL     string `json:"url"`
 }
 
+// WebVitalTrend is the 75th percentile of a web vital and its change from the prior period.
+type WebVitalTrend struct {
+	Name  string `json:"name"`
+	P75   string `json:"p75"`
+	Delta string `json:"delta"`
+}
+
 type DigestDataResponse struct {
 	ProjectId      int             `json:"projectId"`
+	Frequency      string          `json:"frequency"`
 	EndFmt         string          `json:"endFmt"`
 	StartFmt       string          `json:"startFmt"`
 	ProjectName    string          `json:"projectName"`
@@ -88,9 +97,12 @@ type DigestDataResponse struct {
 	ErrorDelta     string          `json:"errorDelta"`
 	ActivityTotal  string          `json:"activityTotal"`
 	ActivityDelta  string          `json:"activityDelta"`
+	LogCount       string          `json:"logCount"`
+	LogDelta       string          `json:"logDelta"`
 	ActiveSessions []ActiveSession `json:"activeSessions"`
 	ErrorSessions  []ErrorSession  `json:"errorSessions"`
 	NewErrors      []NewError      `json:"newErrors"`
 	FrequentErrors []FrequentError `json:"frequentErrors"`
+	WebVitals      []WebVitalTrend `json:"webVitals"`
 	DryRun         bool            `json:"dryRun"`
 }
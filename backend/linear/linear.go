@@ -0,0 +1,362 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	LinearApiBaseUrl = "https://api.linear.app"
+)
+
+const requestTimeout = 10 * time.Second
+
+// maxNodes is the page size of the Linear connections that are listed, which covers the teams
+// and projects of a workspace.
+const maxNodes = 250
+
+type AccessTokenResponse struct {
+	AccessToken string   `json:"access_token"`
+	TokenType   string   `json:"token_type"`
+	ExpiresIn   int64    `json:"expires_in"`
+	Scope       []string `json:"scope"`
+}
+
+func oauthCredentials() (string, string, error) {
+	clientID, clientSecret := os.Getenv("LINEAR_CLIENT_ID"), os.Getenv("LINEAR_CLIENT_SECRET")
+	if clientID == "" {
+		return "", "", errors.New("LINEAR_CLIENT_ID not set")
+	}
+	if clientSecret == "" {
+		return "", "", errors.New("LINEAR_CLIENT_SECRET not set")
+	}
+	return clientID, clientSecret, nil
+}
+
+func doOAuthRequest(ctx context.Context, path string, data url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", LinearApiBaseUrl+path, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request to Linear")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := (&http.Client{Timeout: requestTimeout}).Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting response from Linear oauth endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body from Linear oauth endpoint")
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("Linear API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+	return b, nil
+}
+
+// GetAccessToken exchanges the code of the oauth callback at redirectURL for an access token.
+func GetAccessToken(ctx context.Context, code string, redirectURL string) (*AccessTokenResponse, error) {
+	clientID, clientSecret, err := oauthCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	data := url.Values{}
+	data.Set("code", code)
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("grant_type", "authorization_code")
+	data.Set("redirect_uri", redirectURL)
+
+	b, err := doOAuthRequest(ctx, "/oauth/token", data)
+	if err != nil {
+		return nil, err
+	}
+	var token AccessTokenResponse
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Linear oauth token response")
+	}
+	return &token, nil
+}
+
+func RevokeAccessToken(ctx context.Context, accessToken string) error {
+	data := url.Values{}
+	data.Set("access_token", accessToken)
+	_, err := doOAuthRequest(ctx, "/oauth/revoke", data)
+	return err
+}
+
+// Client calls the Linear graphql api with the access token of a workspace.
+type Client struct {
+	accessToken string
+	httpClient  *http.Client
+}
+
+func NewClient(accessToken string) *Client {
+	return &Client{accessToken: accessToken, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// doGraphQLRequest runs a graphql query and returns its data. The errors of the query are returned
+// even when Linear responds with a 200.
+func doGraphQLRequest[T any](ctx context.Context, c *Client, query string, variables map[string]any) (T, error) {
+	var res struct {
+		Data   T              `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return res.Data, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", LinearApiBaseUrl+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return res.Data, errors.Wrap(err, "error creating api request to Linear")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return res.Data, errors.Wrap(err, "error getting response from Linear graphql endpoint")
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return res.Data, errors.Wrap(err, "error reading response body from Linear graphql endpoint")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return res.Data, errors.New("Linear graphql API responded with error; status_code=" + resp.Status + "; body=" + string(b))
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return res.Data, errors.Wrap(err, "error unmarshaling Linear graphql response")
+	}
+	if len(res.Errors) > 0 {
+		messages := make([]string, len(res.Errors))
+		for i, e := range res.Errors {
+			messages[i] = e.Message
+		}
+		return res.Data, errors.Errorf("Linear graphql API responded with error: %s", strings.Join(messages, "; "))
+	}
+	return res.Data, nil
+}
+
+type Team struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+func (c *Client) GetTeams(ctx context.Context) ([]*Team, error) {
+	type teamsResponse struct {
+		Teams struct {
+			Nodes []*Team `json:"nodes"`
+		} `json:"teams"`
+	}
+	res, err := doGraphQLRequest[teamsResponse](ctx, c, `
+	query teams($first: Int!) {
+		teams(first: $first) {
+			nodes {
+				id
+				name
+				key
+			}
+		}
+	}
+	`, map[string]any{"first": maxNodes})
+	if err != nil {
+		return nil, err
+	}
+	return res.Teams.Nodes, nil
+}
+
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetProjects returns the projects of a team that issues of the team can be added to.
+func (c *Client) GetProjects(ctx context.Context, teamID string) ([]*Project, error) {
+	type projectsResponse struct {
+		Team struct {
+			Projects struct {
+				Nodes []*Project `json:"nodes"`
+			} `json:"projects"`
+		} `json:"team"`
+	}
+	res, err := doGraphQLRequest[projectsResponse](ctx, c, `
+	query projects($teamId: String!, $first: Int!) {
+		team(id: $teamId) {
+			projects(first: $first) {
+				nodes {
+					id
+					name
+				}
+			}
+		}
+	}
+	`, map[string]any{"teamId": teamID, "first": maxNodes})
+	if err != nil {
+		return nil, err
+	}
+	return res.Team.Projects.Nodes, nil
+}
+
+// IssueState is the workflow state of an issue. Its type is `triage`, `backlog`, `unstarted`,
+// `started`, `completed` or `canceled`.
+type IssueState struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Issue struct {
+	ID         string      `json:"id"`
+	Identifier string      `json:"identifier"`
+	Title      string      `json:"title"`
+	URL        string      `json:"url"`
+	State      *IssueState `json:"state"`
+}
+
+const issueFields = `
+	id
+	identifier
+	title
+	url
+	state {
+		name
+		type
+	}
+`
+
+type CreateIssueInput struct {
+	TeamID      string `json:"teamId"`
+	ProjectID   string `json:"projectId,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (c *Client) CreateIssue(ctx context.Context, input *CreateIssueInput) (*Issue, error) {
+	type createIssueResponse struct {
+		IssueCreate struct {
+			Success bool   `json:"success"`
+			Issue   *Issue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	res, err := doGraphQLRequest[createIssueResponse](ctx, c, `
+	mutation createIssue($input: IssueCreateInput!) {
+		issueCreate(input: $input) {
+			success
+			issue {`+issueFields+`}
+		}
+	}
+	`, map[string]any{"input": input})
+	if err != nil {
+		return nil, err
+	}
+	if !res.IssueCreate.Success || res.IssueCreate.Issue == nil {
+		return nil, errors.New("failed to create Linear issue")
+	}
+	return res.IssueCreate.Issue, nil
+}
+
+// GetIssue returns an issue by its id or identifier, ie. `ENG-123`.
+func (c *Client) GetIssue(ctx context.Context, id string) (*Issue, error) {
+	type issueResponse struct {
+		Issue *Issue `json:"issue"`
+	}
+	res, err := doGraphQLRequest[issueResponse](ctx, c, `
+	query issue($id: String!) {
+		issue(id: $id) {`+issueFields+`}
+	}
+	`, map[string]any{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	if res.Issue == nil {
+		return nil, errors.Errorf("Linear issue %s not found", id)
+	}
+	return res.Issue, nil
+}
+
+// maxSearchIssues caps the issues returned by a search.
+const maxSearchIssues = 20
+
+// SearchIssues returns the issues matching the query by their identifier, title or description.
+func (c *Client) SearchIssues(ctx context.Context, query string) ([]*Issue, error) {
+	type searchResponse struct {
+		SearchIssues struct {
+			Nodes []*Issue `json:"nodes"`
+		} `json:"searchIssues"`
+	}
+	res, err := doGraphQLRequest[searchResponse](ctx, c, `
+	query searchIssues($term: String!, $first: Int!) {
+		searchIssues(term: $term, first: $first) {
+			nodes {`+issueFields+`}
+		}
+	}
+	`, map[string]any{"term": strings.TrimSpace(query), "first": maxSearchIssues})
+	if err != nil {
+		return nil, err
+	}
+	return res.SearchIssues.Nodes, nil
+}
+
+type Attachment struct {
+	ID string `json:"id"`
+}
+
+// CreateAttachment links an issue to a url, which Linear shows with the title, subtitle and icon.
+func (c *Client) CreateAttachment(ctx context.Context, issueID string, title string, subtitle string, url string) (*Attachment, error) {
+	type createAttachmentResponse struct {
+		AttachmentCreate struct {
+			Success    bool        `json:"success"`
+			Attachment *Attachment `json:"attachment"`
+		} `json:"attachmentCreate"`
+	}
+	res, err := doGraphQLRequest[createAttachmentResponse](ctx, c, `
+	mutation createAttachment($issueId: String!, $url: String!, $iconUrl: String!, $title: String!, $subtitle: String) {
+		attachmentCreate(input: {issueId: $issueId, url: $url, iconUrl: $iconUrl, title: $title, subtitle: $subtitle}) {
+			success
+			attachment {
+				id
+			}
+		}
+	}
+	`, map[string]any{
+		"issueId":  issueID,
+		"title":    title,
+		"subtitle": subtitle,
+		"url":      url,
+		"iconUrl":  fmt.Sprintf("%s/logo_with_gradient_bg.png", os.Getenv("FRONTEND_URI")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !res.AttachmentCreate.Success || res.AttachmentCreate.Attachment == nil {
+		return nil, errors.New("failed to create Linear attachment")
+	}
+	return res.AttachmentCreate.Attachment, nil
+}
+
+// SplitProjectID returns the team and project of an id of the issue trackers' projects, which is
+// a team id or `<team id>/<project id>` for the projects of a team.
+func SplitProjectID(id string) (string, string) {
+	teamID, projectID, _ := strings.Cut(id, "/")
+	return teamID, projectID
+}
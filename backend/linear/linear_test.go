@@ -0,0 +1,65 @@
+package linear
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var input map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case req.Variables["teamId"] == "missing":
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"Entity not found"}]}`))
+		case req.Variables["teamId"] != nil:
+			_, _ = w.Write([]byte(`{"data":{"team":{"projects":{"nodes":[{"id":"p1","name":"Launch"}]}}}}`))
+		case req.Variables["input"] != nil:
+			input = req.Variables["input"].(map[string]any)
+			_, _ = w.Write([]byte(`{"data":{"issueCreate":{"success":true,"issue":{"id":"i1","identifier":"ENG-1","title":"TypeError","url":"https://linear.app/eng/issue/ENG-1","state":{"name":"Todo","type":"unstarted"}}}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"teams":{"nodes":[{"id":"t1","name":"Engineering","key":"ENG"}]}}}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := LinearApiBaseUrl
+	LinearApiBaseUrl = server.URL
+	defer func() { LinearApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("token")
+
+	teams, err := client.GetTeams(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Team{{ID: "t1", Name: "Engineering", Key: "ENG"}}, teams)
+
+	projects, err := client.GetProjects(ctx, "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Project{{ID: "p1", Name: "Launch"}}, projects)
+
+	_, err = client.GetProjects(ctx, "missing")
+	assert.ErrorContains(t, err, "Entity not found")
+
+	teamID, projectID := SplitProjectID("t1/p1")
+	issue, err := client.CreateIssue(ctx, &CreateIssueInput{TeamID: teamID, ProjectID: projectID, Title: "TypeError", Description: "desc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ENG-1", issue.Identifier)
+	assert.Equal(t, &IssueState{Name: "Todo", Type: "unstarted"}, issue.State)
+	assert.Equal(t, map[string]any{"teamId": "t1", "projectId": "p1", "title": "TypeError", "description": "desc"}, input)
+
+	teamID, projectID = SplitProjectID("t1")
+	assert.Equal(t, "t1", teamID)
+	assert.Empty(t, projectID)
+}
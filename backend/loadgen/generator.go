@@ -0,0 +1,219 @@
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var defaultServices = []string{"frontend", "api", "auth", "billing", "worker"}
+
+var logMessages = []string{
+	"request completed",
+	"cache miss for key",
+	"retrying upstream request",
+	"user session refreshed",
+	"background job finished",
+}
+
+var errorMessages = []string{
+	"connection refused",
+	"context deadline exceeded",
+	"record not found",
+	"invalid request payload",
+}
+
+type GeneratorConfig struct {
+	// Projects are the verbose ids of the projects data is spread across.
+	Projects []string
+	// Services form a call chain, each service calling the next one.
+	Services []string
+	// ErrorRate is the fraction of traces and logs that are errors.
+	ErrorRate float64
+	// SpansPerTrace is the number of spans of each trace.
+	SpansPerTrace int
+	// TracesPerRequest is the number of traces sent in each export request.
+	TracesPerRequest int
+	// LogsPerRequest is the number of log records sent in each export request.
+	LogsPerRequest int
+	// AttributeBytes pads each span and log with an attribute of this size to control payload size.
+	AttributeBytes int
+	Environment    string
+}
+
+func (config *GeneratorConfig) setDefaults() {
+	if len(config.Services) == 0 {
+		config.Services = defaultServices
+	}
+	if config.SpansPerTrace <= 0 {
+		config.SpansPerTrace = 10
+	}
+	if config.TracesPerRequest <= 0 {
+		config.TracesPerRequest = 10
+	}
+	if config.LogsPerRequest <= 0 {
+		config.LogsPerRequest = 100
+	}
+	if config.Environment == "" {
+		config.Environment = "loadgen"
+	}
+}
+
+// Generator creates synthetic OTLP traces and logs resembling those of a small distributed system.
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	config  GeneratorConfig
+	rand    *rand.Rand
+	padding string
+}
+
+func NewGenerator(config GeneratorConfig, seed int64) *Generator {
+	config.setDefaults()
+	return &Generator{
+		config:  config,
+		rand:    rand.New(rand.NewSource(seed)),
+		padding: strings.Repeat("x", config.AttributeBytes),
+	}
+}
+
+func (g *Generator) project() string {
+	return g.config.Projects[g.rand.Intn(len(g.config.Projects))]
+}
+
+func (g *Generator) isError() bool {
+	return g.rand.Float64() < g.config.ErrorRate
+}
+
+func (g *Generator) traceID() pcommon.TraceID {
+	var id pcommon.TraceID
+	g.rand.Read(id[:])
+	return id
+}
+
+func (g *Generator) spanID() pcommon.SpanID {
+	var id pcommon.SpanID
+	g.rand.Read(id[:])
+	return id
+}
+
+func (g *Generator) setResource(resource pcommon.Resource, project string, service string) {
+	resource.Attributes().PutStr(highlight.ProjectIDAttribute, project)
+	resource.Attributes().PutStr("service.name", service)
+	resource.Attributes().PutStr("deployment.environment", g.config.Environment)
+}
+
+// Traces returns an export request worth of traces. Each trace walks the service chain with a
+// client span in the caller and a server span in the callee, so that services are linked.
+func (g *Generator) Traces() ptrace.Traces {
+	traces := ptrace.NewTraces()
+	spansByService := map[string]ptrace.SpanSlice{}
+	getSpans := func(project string, service string) ptrace.SpanSlice {
+		key := project + "/" + service
+		if spans, ok := spansByService[key]; ok {
+			return spans
+		}
+		rs := traces.ResourceSpans().AppendEmpty()
+		g.setResource(rs.Resource(), project, service)
+		spansByService[key] = rs.ScopeSpans().AppendEmpty().Spans()
+		return spansByService[key]
+	}
+
+	now := time.Now()
+	for i := 0; i < g.config.TracesPerRequest; i++ {
+		project := g.project()
+		traceID := g.traceID()
+		failed := g.isError()
+		start := now.Add(-time.Duration(g.rand.Intn(1000)) * time.Millisecond)
+		duration := time.Duration(50+g.rand.Intn(500)) * time.Millisecond
+
+		parentID := pcommon.NewSpanIDEmpty()
+		for s := 0; s < g.config.SpansPerTrace; s++ {
+			serviceIdx := s / 2 % len(g.config.Services)
+			service := g.config.Services[serviceIdx]
+			span := getSpans(project, service).AppendEmpty()
+			span.SetTraceID(traceID)
+			span.SetSpanID(g.spanID())
+			span.SetParentSpanID(parentID)
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+			span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(duration)))
+			if g.config.AttributeBytes > 0 {
+				span.Attributes().PutStr("loadgen.padding", g.padding)
+			}
+
+			switch {
+			case s == 0:
+				span.SetKind(ptrace.SpanKindServer)
+				span.SetName(fmt.Sprintf("GET /%s", service))
+			case s%2 == 1:
+				next := g.config.Services[(serviceIdx+1)%len(g.config.Services)]
+				span.SetKind(ptrace.SpanKindClient)
+				span.SetName(fmt.Sprintf("call %s", next))
+			default:
+				span.SetKind(ptrace.SpanKindServer)
+				span.SetName(fmt.Sprintf("handle %s", service))
+			}
+
+			// the deepest span fails and the error propagates up the call chain
+			if failed {
+				span.Status().SetCode(ptrace.StatusCodeError)
+				if s == g.config.SpansPerTrace-1 {
+					message := errorMessages[g.rand.Intn(len(errorMessages))]
+					span.Status().SetMessage(message)
+					event := span.Events().AppendEmpty()
+					event.SetName("exception")
+					event.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(duration)))
+					event.Attributes().PutStr("exception.type", "LoadgenError")
+					event.Attributes().PutStr("exception.message", message)
+					event.Attributes().PutStr("exception.stacktrace", fmt.Sprintf("LoadgenError: %s\n\tat %s.handle (main.go:42)", message, service))
+				}
+			}
+
+			parentID = span.SpanID()
+			start = start.Add(duration / 10)
+			duration = duration * 8 / 10
+		}
+	}
+	return traces
+}
+
+// Logs returns an export request worth of log records.
+func (g *Generator) Logs() plog.Logs {
+	logs := plog.NewLogs()
+	recordsByService := map[string]plog.LogRecordSlice{}
+
+	now := time.Now()
+	for i := 0; i < g.config.LogsPerRequest; i++ {
+		project := g.project()
+		service := g.config.Services[g.rand.Intn(len(g.config.Services))]
+		key := project + "/" + service
+		records, ok := recordsByService[key]
+		if !ok {
+			rl := logs.ResourceLogs().AppendEmpty()
+			g.setResource(rl.Resource(), project, service)
+			records = rl.ScopeLogs().AppendEmpty().LogRecords()
+			recordsByService[key] = records
+		}
+
+		record := records.AppendEmpty()
+		record.SetTimestamp(pcommon.NewTimestampFromTime(now.Add(-time.Duration(g.rand.Intn(1000)) * time.Millisecond)))
+		if g.isError() {
+			record.SetSeverityText("ERROR")
+			record.SetSeverityNumber(plog.SeverityNumberError)
+			record.Body().SetStr(errorMessages[g.rand.Intn(len(errorMessages))])
+		} else {
+			record.SetSeverityText("INFO")
+			record.SetSeverityNumber(plog.SeverityNumberInfo)
+			record.Body().SetStr(logMessages[g.rand.Intn(len(logMessages))])
+		}
+		if g.config.AttributeBytes > 0 {
+			record.Attributes().PutStr("loadgen.padding", g.padding)
+		}
+	}
+	return logs
+}
@@ -0,0 +1,87 @@
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestGeneratorTraces(t *testing.T) {
+	g := NewGenerator(GeneratorConfig{
+		Projects:         []string{"1"},
+		Services:         []string{"frontend", "backend"},
+		ErrorRate:        1,
+		SpansPerTrace:    4,
+		TracesPerRequest: 3,
+		AttributeBytes:   16,
+	}, 1)
+
+	traces := g.Traces()
+	assert.Equal(t, 12, traces.SpanCount())
+	assert.Equal(t, 2, traces.ResourceSpans().Len())
+
+	kinds := map[string]map[ptrace.SpanKind]int{}
+	exceptions := 0
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		project, _ := rs.Resource().Attributes().Get(highlight.ProjectIDAttribute)
+		assert.Equal(t, "1", project.Str())
+		service, _ := rs.Resource().Attributes().Get("service.name")
+		kinds[service.Str()] = map[ptrace.SpanKind]int{}
+
+		spans := rs.ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			span := spans.At(j)
+			kinds[service.Str()][span.Kind()]++
+			assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+			padding, _ := span.Attributes().Get("loadgen.padding")
+			assert.Len(t, padding.Str(), 16)
+			exceptions += span.Events().Len()
+		}
+	}
+
+	// the frontend serves the request and calls the backend once per trace
+	assert.Equal(t, 3, kinds["frontend"][ptrace.SpanKindServer])
+	assert.Equal(t, 3, kinds["frontend"][ptrace.SpanKindClient])
+	assert.Equal(t, 3, kinds["backend"][ptrace.SpanKindServer])
+	assert.Equal(t, 3, kinds["backend"][ptrace.SpanKindClient])
+	assert.Equal(t, 3, exceptions)
+}
+
+func TestGeneratorLogs(t *testing.T) {
+	g := NewGenerator(GeneratorConfig{Projects: []string{"1", "2"}, LogsPerRequest: 50}, 1)
+	logs := g.Logs()
+	assert.Equal(t, 50, logs.LogRecordCount())
+
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		records := logs.ResourceLogs().At(i).ScopeLogs().At(0).LogRecords()
+		for j := 0; j < records.Len(); j++ {
+			// an error rate of 0 generates no errors
+			assert.Equal(t, "INFO", records.At(j).SeverityText())
+		}
+	}
+
+	body, err := MarshalLogs(logs)
+	assert.NoError(t, err)
+	compressed, err := Compress(body)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, compressed)
+}
+
+func TestReportSummarize(t *testing.T) {
+	report := &Report{Requests: 10, Spans: 100, Elapsed: 2 * time.Second}
+	var latencies []time.Duration
+	for i := 10; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	report.summarize(latencies)
+
+	assert.Equal(t, 5., report.RequestsPerSec)
+	assert.Equal(t, 50., report.ItemsPerSec)
+	assert.Equal(t, 5*time.Millisecond, report.LatencyP50)
+	assert.Equal(t, 9*time.Millisecond, report.LatencyP90)
+	assert.Equal(t, 10*time.Millisecond, report.LatencyMax)
+}
@@ -0,0 +1,62 @@
+package loadgen
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+
+	e "github.com/pkg/errors"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+const (
+	TracesPath = "/traces"
+	LogsPath   = "/logs"
+)
+
+func MarshalTraces(traces ptrace.Traces) ([]byte, error) {
+	return ptraceotlp.NewExportRequestFromTraces(traces).MarshalProto()
+}
+
+func MarshalLogs(logs plog.Logs) ([]byte, error) {
+	return plogotlp.NewExportRequestFromLogs(logs).MarshalProto()
+}
+
+// Compress gzips a payload the way the OTLP exporters send it to the otel routes.
+func Compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Post sends a gzipped OTLP protobuf payload, returning an error for non 2xx responses.
+func Post(ctx context.Context, client *http.Client, url string, compressed []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(compressed))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return e.Errorf("unexpected response %s: %s", resp.Status, body)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package loadgen
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type RunnerConfig struct {
+	// Endpoint is the base url of the otel routes, ie. https://pub.highlight.io/otel/v1
+	Endpoint string
+	// RequestsPerSecond across all workers, split evenly between traces and logs. 0 sends as fast as possible.
+	RequestsPerSecond float64
+	Duration          time.Duration
+	Concurrency       int
+	SendTraces        bool
+	SendLogs          bool
+	Client            *http.Client
+}
+
+type Report struct {
+	Requests       int           `json:"requests"`
+	Failures       int           `json:"failures"`
+	Spans          int           `json:"spans"`
+	Logs           int           `json:"logs"`
+	Bytes          int64         `json:"bytes"`
+	Elapsed        time.Duration `json:"elapsed"`
+	RequestsPerSec float64       `json:"requests_per_sec"`
+	ItemsPerSec    float64       `json:"items_per_sec"`
+	LatencyP50     time.Duration `json:"latency_p50"`
+	LatencyP90     time.Duration `json:"latency_p90"`
+	LatencyP99     time.Duration `json:"latency_p99"`
+	LatencyMax     time.Duration `json:"latency_max"`
+}
+
+type result struct {
+	latency time.Duration
+	items   int
+	bytes   int
+	isTrace bool
+	err     error
+}
+
+// Run sends generated traffic until the duration elapses or the context is cancelled.
+// Each worker uses its own generator so that payloads are generated concurrently.
+func Run(ctx context.Context, config RunnerConfig, generatorConfig GeneratorConfig) *Report {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if !config.SendTraces && !config.SendLogs {
+		config.SendTraces, config.SendLogs = true, true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	// workers share the ticker so that each tick is a single request
+	var ticks <-chan time.Time
+	if config.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / config.RequestsPerSecond))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	results := make(chan result, config.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			generator := NewGenerator(generatorConfig, start.UnixNano()+int64(worker))
+			sendTraces := config.SendTraces
+			for {
+				if ticks != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticks:
+					}
+				} else if ctx.Err() != nil {
+					return
+				}
+				results <- send(ctx, config, generator, sendTraces)
+				if config.SendTraces && config.SendLogs {
+					sendTraces = !sendTraces
+				}
+			}
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	report := &Report{}
+	var latencies []time.Duration
+	for r := range results {
+		// requests cut short by the end of the run are not counted
+		if r.err != nil && ctx.Err() != nil {
+			continue
+		}
+		report.Requests++
+		if r.err != nil {
+			report.Failures++
+			log.WithContext(ctx).WithError(r.err).Debug("loadgen request failed")
+			continue
+		}
+		if r.isTrace {
+			report.Spans += r.items
+		} else {
+			report.Logs += r.items
+		}
+		report.Bytes += int64(r.bytes)
+		latencies = append(latencies, r.latency)
+	}
+	report.Elapsed = time.Since(start)
+	report.summarize(latencies)
+	return report
+}
+
+func send(ctx context.Context, config RunnerConfig, generator *Generator, isTrace bool) result {
+	var body []byte
+	var err error
+	var items int
+	path := LogsPath
+	if isTrace {
+		traces := generator.Traces()
+		items = traces.SpanCount()
+		body, err = MarshalTraces(traces)
+		path = TracesPath
+	} else {
+		logs := generator.Logs()
+		items = logs.LogRecordCount()
+		body, err = MarshalLogs(logs)
+	}
+	if err != nil {
+		return result{err: err}
+	}
+	compressed, err := Compress(body)
+	if err != nil {
+		return result{err: err}
+	}
+
+	start := time.Now()
+	err = Post(ctx, config.Client, config.Endpoint+path, compressed)
+	return result{latency: time.Since(start), items: items, bytes: len(compressed), isTrace: isTrace, err: err}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+func (report *Report) summarize(latencies []time.Duration) {
+	if seconds := report.Elapsed.Seconds(); seconds > 0 {
+		report.RequestsPerSec = float64(report.Requests) / seconds
+		report.ItemsPerSec = float64(report.Spans+report.Logs) / seconds
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	report.LatencyP50 = percentile(latencies, .5)
+	report.LatencyP90 = percentile(latencies, .9)
+	report.LatencyP99 = percentile(latencies, .99)
+	report.LatencyMax = percentile(latencies, 1)
+}
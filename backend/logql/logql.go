@@ -0,0 +1,335 @@
+package logql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// MatchType is the operator of a label matcher.
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// FilterType is the operator of a line filter.
+type FilterType string
+
+const (
+	FilterContains    FilterType = "|="
+	FilterNotContains FilterType = "!="
+	FilterRegexp      FilterType = "|~"
+	FilterNotRegexp   FilterType = "!~"
+)
+
+// RangeAggregation is the function of a metric query over the logs of a range.
+type RangeAggregation string
+
+const (
+	CountOverTime RangeAggregation = "count_over_time"
+	Rate          RangeAggregation = "rate"
+)
+
+type LabelMatcher struct {
+	Name  string
+	Type  MatchType
+	Value string
+}
+
+type LineFilter struct {
+	Type  FilterType
+	Value string
+}
+
+// Query is a LogQL query of the supported subset: a stream selector of label matchers followed by
+// line filters, optionally aggregated by count_over_time or rate, which may be summed by labels.
+// See https://grafana.com/docs/loki/latest/query/
+type Query struct {
+	Matchers    []*LabelMatcher
+	LineFilters []*LineFilter
+
+	// Aggregation is set for metric queries, with the Range of logs that are counted.
+	Aggregation RangeAggregation
+	Range       time.Duration
+	// Sum is set when the series of a metric query are summed, grouped by the SumBy labels.
+	Sum   bool
+	SumBy []string
+}
+
+func (q *Query) IsMetric() bool {
+	return q.Aggregation != ""
+}
+
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// IsValidLabelName returns whether a name can be used as a label of a LogQL query.
+func IsValidLabelName(name string) bool {
+	return labelNamePattern.MatchString(name)
+}
+
+// Parse parses a LogQL query. Parsers, formatters and other pipeline stages are not supported.
+func Parse(query string) (*Query, error) {
+	p := &parser{input: query}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return q, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return errors.Errorf("parse error at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek(s string) bool {
+	p.skipSpace()
+	return strings.HasPrefix(p.input[p.pos:], s)
+}
+
+func (p *parser) consume(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.consume(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+func (p *parser) ident() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+// string parses a double quoted string with go escapes, or a backtick quoted raw string.
+func (p *parser) string() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return "", p.errorf("expected string")
+	}
+	quote := p.input[p.pos]
+	if quote != '"' && quote != '`' {
+		return "", p.errorf("expected string")
+	}
+	for end := p.pos + 1; end < len(p.input); end++ {
+		if quote == '"' && p.input[end] == '\\' {
+			end++
+			continue
+		}
+		if p.input[end] == quote {
+			raw := p.input[p.pos : end+1]
+			p.pos = end + 1
+			if quote == '`' {
+				return raw[1 : len(raw)-1], nil
+			}
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return "", p.errorf("invalid string %s", raw)
+			}
+			return s, nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	start := p.pos
+	switch name := p.ident(); name {
+	case "sum":
+		var by []string
+		var err error
+		if by, err = p.parseBy(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		q, err := p.parseRangeAggregation()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if by == nil {
+			if by, err = p.parseBy(); err != nil {
+				return nil, err
+			}
+		}
+		q.Sum = true
+		q.SumBy = by
+		return q, nil
+	case "":
+		return p.parseLogQuery()
+	default:
+		p.pos = start
+		return p.parseRangeAggregation()
+	}
+}
+
+// parseBy parses an optional `by (label, ...)` clause of a sum.
+func (p *parser) parseBy() ([]string, error) {
+	start := p.pos
+	if p.ident() != "by" {
+		p.pos = start
+		return nil, nil
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	by := []string{}
+	for !p.consume(")") {
+		if len(by) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		label := p.ident()
+		if label == "" {
+			return nil, p.errorf("expected label name")
+		}
+		by = append(by, label)
+	}
+	return by, nil
+}
+
+func (p *parser) parseRangeAggregation() (*Query, error) {
+	name := p.ident()
+	aggregation := RangeAggregation(name)
+	if aggregation != CountOverTime && aggregation != Rate {
+		return nil, p.errorf("unsupported function %q", name)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	q, err := p.parseLogQuery()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	end := strings.IndexByte(p.input[p.pos:], ']')
+	if end < 0 {
+		return nil, p.errorf("expected \"]\"")
+	}
+	if q.Range, err = time.ParseDuration(strings.TrimSpace(p.input[p.pos : p.pos+end])); err != nil || q.Range <= 0 {
+		return nil, p.errorf("invalid range %q", p.input[p.pos:p.pos+end])
+	}
+	p.pos += end + 1
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	q.Aggregation = aggregation
+	return q, nil
+}
+
+func (p *parser) parseLogQuery() (*Query, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	q := &Query{}
+	for !p.consume("}") {
+		if len(q.Matchers) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		matcher := &LabelMatcher{Name: p.ident()}
+		if matcher.Name == "" {
+			return nil, p.errorf("expected label name")
+		}
+		for _, t := range []MatchType{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+			if p.consume(string(t)) {
+				matcher.Type = t
+				break
+			}
+		}
+		if matcher.Type == "" {
+			return nil, p.errorf("expected label matcher operator")
+		}
+		var err error
+		if matcher.Value, err = p.string(); err != nil {
+			return nil, err
+		}
+		if err := validateRegexp(matcher.Type == MatchRegexp || matcher.Type == MatchNotRegexp, matcher.Value); err != nil {
+			return nil, err
+		}
+		q.Matchers = append(q.Matchers, matcher)
+	}
+	if len(q.Matchers) == 0 {
+		return nil, p.errorf("stream selector must contain at least one label matcher")
+	}
+
+	for {
+		var filter *LineFilter
+		for _, t := range []FilterType{FilterContains, FilterNotContains, FilterRegexp, FilterNotRegexp} {
+			if p.consume(string(t)) {
+				filter = &LineFilter{Type: t}
+				break
+			}
+		}
+		if filter == nil {
+			if p.peek("|") {
+				return nil, p.errorf("unsupported pipeline stage")
+			}
+			return q, nil
+		}
+		var err error
+		if filter.Value, err = p.string(); err != nil {
+			return nil, err
+		}
+		if err := validateRegexp(filter.Type == FilterRegexp || filter.Type == FilterNotRegexp, filter.Value); err != nil {
+			return nil, err
+		}
+		q.LineFilters = append(q.LineFilters, filter)
+	}
+}
+
+func validateRegexp(isRegexp bool, value string) error {
+	if !isRegexp {
+		return nil
+	}
+	if _, err := regexp.Compile(value); err != nil {
+		return errors.Wrapf(err, "invalid regular expression %q", value)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package logql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for name, tc := range map[string]struct {
+		query    string
+		expected *Query
+	}{
+		"selector": {
+			query: `{service_name="api", level!="debug", environment=~"prod.*", source!~"front.*"}`,
+			expected: &Query{Matchers: []*LabelMatcher{
+				{Name: "service_name", Type: MatchEqual, Value: "api"},
+				{Name: "level", Type: MatchNotEqual, Value: "debug"},
+				{Name: "environment", Type: MatchRegexp, Value: "prod.*"},
+				{Name: "source", Type: MatchNotRegexp, Value: "front.*"},
+			}},
+		},
+		"line filters": {
+			query: "{service_name=\"api\"} |= \"error\" != `timeout` |~ \"user \\\"[0-9]+\\\"\" !~ `health.*`",
+			expected: &Query{
+				Matchers: []*LabelMatcher{{Name: "service_name", Type: MatchEqual, Value: "api"}},
+				LineFilters: []*LineFilter{
+					{Type: FilterContains, Value: "error"},
+					{Type: FilterNotContains, Value: "timeout"},
+					{Type: FilterRegexp, Value: `user "[0-9]+"`},
+					{Type: FilterNotRegexp, Value: "health.*"},
+				},
+			},
+		},
+		"count over time": {
+			query: `count_over_time({level="error"} |= "panic" [5m])`,
+			expected: &Query{
+				Matchers:    []*LabelMatcher{{Name: "level", Type: MatchEqual, Value: "error"}},
+				LineFilters: []*LineFilter{{Type: FilterContains, Value: "panic"}},
+				Aggregation: CountOverTime,
+				Range:       5 * time.Minute,
+			},
+		},
+		"sum by rate": {
+			query: `sum by (service_name, level) (rate({environment="production"}[1m]))`,
+			expected: &Query{
+				Matchers:    []*LabelMatcher{{Name: "environment", Type: MatchEqual, Value: "production"}},
+				Aggregation: Rate,
+				Range:       time.Minute,
+				Sum:         true,
+				SumBy:       []string{"service_name", "level"},
+			},
+		},
+		"sum with trailing by": {
+			query: `sum(count_over_time({environment="production"}[30s])) by (level)`,
+			expected: &Query{
+				Matchers:    []*LabelMatcher{{Name: "environment", Type: MatchEqual, Value: "production"}},
+				Aggregation: CountOverTime,
+				Range:       30 * time.Second,
+				Sum:         true,
+				SumBy:       []string{"level"},
+			},
+		},
+		"sum": {
+			query: `sum(rate({environment="production"}[1h]))`,
+			expected: &Query{
+				Matchers:    []*LabelMatcher{{Name: "environment", Type: MatchEqual, Value: "production"}},
+				Aggregation: Rate,
+				Range:       time.Hour,
+				Sum:         true,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q, err := Parse(tc.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, q)
+			assert.Equal(t, tc.expected.Aggregation != "", q.IsMetric())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{
+		``,
+		`{}`,
+		`{service_name}`,
+		`{service_name="api"`,
+		`{service_name="api} |= "error"`,
+		`{service_name=~"("}`,
+		`{service_name="api"} |= "error" | json`,
+		`{service_name="api"} | level="error"`,
+		`{service_name="api"} |~ "["`,
+		`avg_over_time({service_name="api"}[5m])`,
+		`rate({service_name="api"})`,
+		`rate({service_name="api"}[five minutes])`,
+		`sum(rate({service_name="api"}[1m])`,
+		`{service_name="api"} extra`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, err := Parse(query)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIsValidLabelName(t *testing.T) {
+	assert.True(t, IsValidLabelName("service_name"))
+	assert.True(t, IsValidLabelName("_private2"))
+	assert.False(t, IsValidLabelName("http.method"))
+	assert.False(t, IsValidLabelName("2xx"))
+	assert.False(t, IsValidLabelName(""))
+}
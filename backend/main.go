@@ -29,11 +29,14 @@ import (
 	"github.com/go-chi/httplog"
 	"github.com/gorilla/websocket"
 	"github.com/highlight-run/go-resthooks"
+	"github.com/highlight-run/highlight/backend/chaos"
 	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/heartbeat"
 	highlightHttp "github.com/highlight-run/highlight/backend/http"
 	"github.com/highlight-run/highlight/backend/integrations"
 	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
 	"github.com/highlight-run/highlight/backend/lambda"
+	"github.com/highlight-run/highlight/backend/mobile"
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/highlight-run/highlight/backend/oauth"
 	"github.com/highlight-run/highlight/backend/otel"
@@ -42,10 +45,13 @@ import (
 	privategen "github.com/highlight-run/highlight/backend/private-graph/graph/generated"
 	public "github.com/highlight-run/highlight/backend/public-graph/graph"
 	publicgen "github.com/highlight-run/highlight/backend/public-graph/graph/generated"
+	"github.com/highlight-run/highlight/backend/ratelimit"
 	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/restapi"
 	"github.com/highlight-run/highlight/backend/stepfunctions"
 	"github.com/highlight-run/highlight/backend/storage"
 	"github.com/highlight-run/highlight/backend/store"
+	"github.com/highlight-run/highlight/backend/symbolication"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight-run/highlight/backend/vercel"
 	"github.com/highlight-run/highlight/backend/worker"
@@ -305,6 +311,7 @@ func main() {
 	}
 
 	redisClient := redis.NewClient()
+	chaos.Start(ctx, redisClient)
 	sfnClient := stepfunctions.NewClient()
 
 	clickhouseClient, err := clickhouse.NewClient(clickhouse.PrimaryDatabase)
@@ -363,6 +370,19 @@ func main() {
 		AllowCredentials:       true,
 		AllowedHeaders:         []string{"*"},
 	}).Handler)
+	var limiter *ratelimit.Limiter
+	if runtimeParsed == util.PublicGraph || runtimeParsed == util.All {
+		policies, err := ratelimit.LoadPolicies()
+		if err != nil {
+			log.WithContext(ctx).Fatalf("Error loading rate limit policies: %v", err)
+		}
+		trustedProxies, err := ratelimit.LoadTrustedProxies()
+		if err != nil {
+			log.WithContext(ctx).Fatalf("Error loading rate limit trusted proxies: %v", err)
+		}
+		limiter = ratelimit.New(redisClient, policies, trustedProxies, privateResolver.Store)
+		r.Use(limiter.Middleware)
+	}
 	r.HandleFunc("/health", healthRouter(runtimeParsed, db, redisClient, clickhouseClient, kafkaProducer, kafkaBatchedProducer))
 
 	zapierStore := zapier.ZapierResthookStore{
@@ -394,11 +414,55 @@ func main() {
 			r.HandleFunc("/revoke", oauthSrv.HandleRevoke)
 		})
 		r.HandleFunc("/stripe-webhook", privateResolver.StripeWebhook(ctx, stripeWebhookSecret))
+		r.Post("/jira-webhook/{workspace_id}", privateResolver.JiraWebhookHandler)
+		r.Post("/github-webhook", privateResolver.GitHubWebhookHandler)
 		r.Route("/zapier", func(r chi.Router) {
 			zapier.CreateZapierRoutes(r, db, &zapierStore, &rh)
 		})
 		r.HandleFunc("/slack-events", privateResolver.SlackEventsWebhook(ctx, slackSigningSecret))
+		r.Post("/slack-interactions", privateResolver.SlackInteractionsWebhook(ctx, slackSigningSecret))
+		r.Post("/intercom/initialize", privateResolver.IntercomCanvasHandler)
+		r.Get("/zendesk/sessions", privateResolver.ZendeskSessionsHandler)
 		r.Post(fmt.Sprintf("%s/%s", privateEndpoint, "login"), privateResolver.Login)
+		r.Route("/sso", func(r chi.Router) {
+			r.Get("/login", privateResolver.SSODiscoveryHandler)
+			r.Get("/{workspace_id}/login", privateResolver.SSOLoginHandler)
+			r.Get("/{workspace_id}/saml/metadata", privateResolver.SAMLMetadataHandler)
+			r.Post("/{workspace_id}/saml/acs", privateResolver.SAMLACSHandler)
+			r.Get("/{workspace_id}/oidc/callback", privateResolver.OIDCCallbackHandler)
+		})
+		// the scim v2 endpoint of an identity provider that provisions the admins of a workspace
+		r.Route("/scim/v2/{workspace_id}", func(r chi.Router) {
+			r.Get("/ServiceProviderConfig", privateResolver.SCIMServiceProviderConfigHandler)
+			r.Get("/Users", privateResolver.SCIMUsersHandler)
+			r.Post("/Users", privateResolver.CreateSCIMUserHandler)
+			r.Get("/Users/{user_id}", privateResolver.SCIMUserHandler)
+			r.Put("/Users/{user_id}", privateResolver.ReplaceSCIMUserHandler)
+			r.Patch("/Users/{user_id}", privateResolver.PatchSCIMUserHandler)
+			r.Delete("/Users/{user_id}", privateResolver.DeleteSCIMUserHandler)
+		})
+		// the versioned REST API, authenticated with an `Authorization: Bearer` api token
+		r.Route(restapi.BasePath, func(r chi.Router) {
+			r.Use(highlightChi.Middleware)
+			r.Get("/openapi.json", privateResolver.OpenAPISpecHandler)
+			r.Group(func(r chi.Router) {
+				r.Use(privateResolver.APITokenMiddleware)
+				r.Get("/projects", privateResolver.RESTProjectsHandler)
+				r.Route("/projects/{project_id}", func(r chi.Router) {
+					r.Get("/errors", privateResolver.RESTErrorGroupsHandler)
+					r.Get("/errors/{error_group_secure_id}", privateResolver.RESTErrorGroupHandler)
+					r.Get("/errors/{error_group_secure_id}/trends", privateResolver.RESTErrorGroupTrendsHandler)
+					r.Put("/errors/{error_group_secure_id}/state", privateResolver.UpdateRESTErrorGroupStateHandler)
+					r.Get("/sessions", privateResolver.RESTSessionsHandler)
+					r.Get("/logs/search", privateResolver.RESTSearchLogsHandler)
+					r.Get("/traces/search", privateResolver.RESTSearchTracesHandler)
+					r.Get("/sourcemaps", privateResolver.RESTSourcemapsHandler)
+					r.Post("/sourcemaps", privateResolver.UploadRESTSourcemapsHandler)
+					r.Get("/releases", privateResolver.RESTReleasesHandler)
+					r.Post("/releases", privateResolver.CreateRESTDeployHandler)
+				})
+			})
+		})
 		r.Route(privateEndpoint, func(r chi.Router) {
 			r.Use(highlightChi.Middleware)
 			r.Use(private.PrivateMiddleware)
@@ -407,6 +471,14 @@ func main() {
 			}
 			r.Get("/assets/{project_id}/{hash_val}", privateResolver.AssetHandler)
 			r.Get("/project-token/{project_id}", privateResolver.ProjectJWTHandler)
+			r.Get("/profiles/{project_id}/download", privateResolver.ProfileDownloadHandler)
+			// the url of a Grafana Loki data source for the project's logs is <private graph>/loki/<project_id>
+			r.Route("/loki/{project_id}/loki/api/v1", func(r chi.Router) {
+				r.Get("/query_range", privateResolver.LokiQueryRangeHandler)
+				r.Get("/query", privateResolver.LokiQueryHandler)
+				r.Get("/labels", privateResolver.LokiLabelsHandler)
+				r.Get("/label/{name}/values", privateResolver.LokiLabelValuesHandler)
+			})
 
 			r.Get("/validate-token", privateResolver.ValidateAuthToken)
 
@@ -439,10 +511,12 @@ func main() {
 				Cache: lru.New(10000),
 			})
 			privateServer.Use(private.NewGraphqlOAuthValidator(privateResolver.Store))
+			privateServer.Use(private.NewGraphqlRBACValidator(privateResolver))
+			privateServer.Use(private.NewGraphqlAPITokenValidator())
 			privateServer.Use(htrace.NewGraphqlTracer(string(util.PrivateGraph)).WithRequestFieldLogging())
 			privateServer.SetErrorPresenter(htrace.GraphQLErrorPresenter(string(util.PrivateGraph)))
 			privateServer.SetRecoverFunc(htrace.GraphQLRecoverFunc())
-			r.Handle("/",
+			r.With(privateResolver.APITokenMiddleware).Handle("/",
 				privateServer,
 			)
 		})
@@ -469,6 +543,7 @@ func main() {
 		r.Route(publicEndpoint, func(r chi.Router) {
 			r.Use(highlightChi.Middleware)
 			r.Use(public.PublicMiddleware)
+			r.Use(limiter.Policy("public-graph"))
 
 			publicServer := ghandler.NewDefaultServer(publicgen.NewExecutableSchema(
 				publicgen.Config{
@@ -483,8 +558,15 @@ func main() {
 		})
 		otelHandler := otel.New(publicResolver)
 		otelHandler.Listen(r)
+		go func() {
+			if err := otelHandler.ListenGRPC(ctx); err != nil {
+				log.WithContext(ctx).WithError(err).Error("otlp grpc server stopped")
+			}
+		}()
 		vercel.Listen(r)
 		highlightHttp.Listen(r)
+		heartbeat.New(publicResolver).Listen(r)
+		mobile.New(publicResolver).Listen(r)
 	}
 
 	/*
@@ -551,8 +633,9 @@ func main() {
 			RH:               &rh,
 			Store:            store.NewStore(db, redisClient, integrationsClient, storageClient, kafkaDataSyncProducer, clickhouseClient),
 			LambdaClient:     lambda,
+			Symbolicator:     symbolication.NewSymbolicator(storageClient),
 		}
-		w := &worker.Worker{Resolver: privateResolver, PublicResolver: publicResolver, StorageClient: storageClient}
+		w := &worker.Worker{Resolver: privateResolver, PublicResolver: publicResolver, StorageClient: storageClient, Symbolicator: publicResolver.Symbolicator}
 		if runtimeParsed == util.Worker {
 			if handlerFlag != nil && *handlerFlag != "" {
 				func() {
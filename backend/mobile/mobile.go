@@ -0,0 +1,190 @@
+package mobile
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight-run/highlight/backend/symbolication"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ProjectHeader       = "x-highlight-project"
+	APIKeyHeader        = "x-highlight-api-key"
+	PlatformQueryParam  = "platform"
+	VersionQueryParam   = "version"
+	MaxCrashBodyBytes   = 10 * 1024 * 1024
+	MaxMappingBodyBytes = 512 * 1024 * 1024
+)
+
+type Handler struct {
+	resolver *graph.Resolver
+}
+
+type crashRequest struct {
+	ProjectID string `json:"project_id"`
+	symbolication.CrashReport
+}
+
+func getBody(r *http.Request) (io.Reader, error) {
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		return gzip.NewReader(r.Body)
+	}
+	return r.Body, nil
+}
+
+// HandleCrash accepts a native crash report and queues it for symbolication.
+func (h *Handler) HandleCrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	r.Body = http.MaxBytesReader(w, r.Body, MaxCrashBodyBytes)
+
+	body, err := getBody(r)
+	if err != nil {
+		http.Error(w, "invalid gzip body", http.StatusBadRequest)
+		return
+	}
+
+	var crash crashRequest
+	if err := json.NewDecoder(body).Decode(&crash); err != nil {
+		http.Error(w, "invalid crash report", http.StatusBadRequest)
+		return
+	}
+	if crash.ProjectID == "" {
+		crash.ProjectID = r.Header.Get(ProjectHeader)
+	}
+	if _, err := model.FromVerboseID(crash.ProjectID); err != nil {
+		http.Error(w, "invalid project", http.StatusBadRequest)
+		return
+	}
+	if crash.Platform != symbolication.PlatformIOS && crash.Platform != symbolication.PlatformAndroid {
+		http.Error(w, "invalid platform", http.StatusBadRequest)
+		return
+	}
+
+	partitionKey := ""
+	if crash.SessionSecureID != nil {
+		partitionKey = *crash.SessionSecureID
+	}
+	report := crash.CrashReport
+	err = h.resolver.ProducerQueue.Submit(ctx, partitionKey, &kafkaqueue.Message{
+		Type: kafkaqueue.PushMobileCrash,
+		PushMobileCrash: &kafkaqueue.PushMobileCrashArgs{
+			ProjectVerboseID: crash.ProjectID,
+			Report:           &report,
+		},
+	})
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit mobile crash")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleMappingUpload stores a ProGuard mapping.txt for an android release, or a dSYM
+// for an iOS build. dSYMs are uploaded as a Mach-O file or a zipped .dSYM bundle, and
+// are stored once per architecture uuid found in the file.
+func (h *Handler) HandleMappingUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiKey := r.Header.Get(APIKeyHeader)
+	if apiKey == "" {
+		http.Error(w, "missing api key", http.StatusUnauthorized)
+		return
+	}
+
+	var projectID int
+	if err := h.resolver.DB.WithContext(ctx).Model(&model.Project{}).Select("id").
+		Where("secret = ?", apiKey).Scan(&projectID).Error; err != nil || projectID == 0 {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxMappingBodyBytes)
+	body, err := getBody(r)
+	if err != nil {
+		http.Error(w, "invalid gzip body", http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, "failed to read mapping file", http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Query().Get(PlatformQueryParam) {
+	case symbolication.PlatformAndroid, symbolication.PlatformJVM:
+		version := r.URL.Query().Get(VersionQueryParam)
+		if version == "" {
+			http.Error(w, "version is required for proguard mappings", http.StatusBadRequest)
+			return
+		}
+		if _, err := h.resolver.StorageClient.PushSourceMapFile(ctx, projectID, &version, symbolication.ProGuardMappingFileName(), data); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to store proguard mapping")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	case symbolication.PlatformIOS:
+		binaries, err := symbolication.ExtractDSYMBinaries(data)
+		if err != nil {
+			http.Error(w, "invalid dsym archive", http.StatusBadRequest)
+			return
+		}
+		for _, binary := range binaries {
+			tables, err := symbolication.ParseSymbolTables(binary)
+			if err != nil {
+				http.Error(w, "invalid dsym", http.StatusBadRequest)
+				return
+			}
+			if err := h.storeDSYM(r, projectID, tables, binary); err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to store dsym")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+	default:
+		http.Error(w, "invalid platform", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) storeDSYM(r *http.Request, projectID int, tables []*symbolication.SymbolTable, data []byte) error {
+	version := symbolication.DSYMVersion
+	stored := 0
+	for _, table := range tables {
+		if table.UUID == "" {
+			continue
+		}
+		stored += 1
+		if _, err := h.resolver.StorageClient.PushSourceMapFile(r.Context(), projectID, &version, symbolication.DSYMFileName(table.UUID), data); err != nil {
+			return e.Wrapf(err, "failed to store dsym %s", table.UUID)
+		}
+	}
+	if stored == 0 {
+		return e.New("dsym does not contain a uuid")
+	}
+	return nil
+}
+
+func (h *Handler) Listen(r *chi.Mux) {
+	r.Route("/mobile/v1", func(r chi.Router) {
+		r.Post("/crashes", h.HandleCrash)
+		r.Post("/mappings", h.HandleMappingUpload)
+	})
+}
+
+func New(resolver *graph.Resolver) *Handler {
+	return &Handler{
+		resolver: resolver,
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+type EscalationStepType = string
+
+const (
+	EscalationStepSlack     EscalationStepType = "slack"
+	EscalationStepEmail     EscalationStepType = "email"
+	EscalationStepPagerDuty EscalationStepType = "pagerduty"
+)
+
+// EscalationStep is a notification of an escalation policy, which is sent DelayMinutes after the
+// previous step unless the alert was acknowledged or resolved by then. An email step notifies its
+// emails and whoever is on call of its schedule.
+type EscalationStep struct {
+	Type             EscalationStepType                   `json:"type"`
+	DelayMinutes     int                                  `json:"delay_minutes"`
+	SlackChannels    []*modelInputs.SanitizedSlackChannel `json:"slack_channels,omitempty"`
+	Emails           []string                             `json:"emails,omitempty"`
+	OnCallScheduleID *int                                 `json:"on_call_schedule_id,omitempty"`
+	PagerDuty        *PagerDutyDestination                `json:"pagerduty,omitempty"`
+}
+
+func (step *EscalationStep) GetDelay() time.Duration {
+	return time.Duration(step.DelayMinutes) * time.Minute
+}
+
+type EscalationSteps []*EscalationStep
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (es *EscalationSteps) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &es)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (es EscalationSteps) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(es)
+	return string(bytes), err
+}
+
+// EscalationPolicy is the ordered steps that notify of an alert until it is acknowledged.
+type EscalationPolicy struct {
+	Model
+	ProjectID         int             `gorm:"index;not null;" json:"project_id"`
+	Name              string          `gorm:"not null" json:"name"`
+	Steps             EscalationSteps `gorm:"type:jsonb;default:'[]'" json:"steps"`
+	LastAdminToEditID int             `json:"last_admin_to_edit_id"`
+}
+
+// OnCallSchedule rotates being on call between the participants of a project, each for
+// RotationHours in turn starting at StartsAt.
+type OnCallSchedule struct {
+	Model
+	ProjectID     int            `gorm:"index;not null;" json:"project_id"`
+	Name          string         `gorm:"not null" json:"name"`
+	Participants  pq.StringArray `gorm:"type:text[]" json:"participants"` // emails, in the order of the rotation
+	RotationHours int            `gorm:"default:168" json:"rotation_hours"`
+	StartsAt      time.Time      `json:"starts_at"`
+}
+
+// GetOnCall returns the participant of the schedule that is on call at a time, or an empty string
+// if nobody is.
+func (schedule *OnCallSchedule) GetOnCall(at time.Time) string {
+	if len(schedule.Participants) == 0 || schedule.RotationHours <= 0 || at.Before(schedule.StartsAt) {
+		return ""
+	}
+	rotation := int(at.Sub(schedule.StartsAt) / (time.Duration(schedule.RotationHours) * time.Hour))
+	return schedule.Participants[rotation%len(schedule.Participants)]
+}
+
+// AlertEscalation is an alert that is escalated through the steps of a policy until it is
+// acknowledged or resolved. An alert has one open escalation per policy, identified by its DedupKey.
+type AlertEscalation struct {
+	Model
+	ProjectID          int        `gorm:"index;not null;" json:"project_id"`
+	EscalationPolicyID int        `gorm:"uniqueIndex:idx_alert_escalations_open,where:resolved_at IS NULL" json:"escalation_policy_id"`
+	DedupKey           string     `gorm:"uniqueIndex:idx_alert_escalations_open,where:resolved_at IS NULL" json:"dedup_key"`
+	AlertType          string     `json:"alert_type"`
+	AlertID            int        `json:"alert_id"`
+	Title              string     `json:"title"`
+	Message            string     `json:"message"`
+	URL                string     `json:"url"`
+	NextStep           int        `json:"next_step"`
+	NextStepAt         *time.Time `gorm:"index" json:"next_step_at"` // unset once every step was sent
+	AcknowledgedAt     *time.Time `json:"acknowledged_at"`
+	AcknowledgedByID   *int       `json:"acknowledged_by_id"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"os"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -78,6 +79,10 @@ var AlertType = struct {
 	RAGE_CLICK       string
 	NEW_SESSION      string
 	LOG              string
+	UPTIME           string
+	HEARTBEAT        string
+	METRIC_MONITOR   string
+	TRACE            string
 }{
 	ERROR:            "ERROR_ALERT",
 	NEW_USER:         "NEW_USER_ALERT",
@@ -87,14 +92,23 @@ var AlertType = struct {
 	RAGE_CLICK:       "RAGE_CLICK_ALERT",
 	NEW_SESSION:      "NEW_SESSION_ALERT",
 	LOG:              "LOG",
+	UPTIME:           "UPTIME",
+	HEARTBEAT:        "HEARTBEAT",
+	METRIC_MONITOR:   "METRIC_MONITOR",
+	TRACE:            "TRACE",
 }
 
+// AdminRole are the built-in roles of workspace admins. Workspaces can also define custom roles.
 var AdminRole = struct {
+	OWNER  string
 	ADMIN  string
 	MEMBER string
+	VIEWER string
 }{
+	OWNER:  "OWNER",
 	ADMIN:  "ADMIN",
 	MEMBER: "MEMBER",
+	VIEWER: "VIEWER",
 }
 
 var SessionCommentTypes = struct {
@@ -121,6 +135,8 @@ var ContextKeys = struct {
 	ZapierToken    contextString
 	ZapierProject  contextString
 	SessionId      contextString
+	// The API token that authenticated the request, if any.
+	APIToken contextString
 }{
 	IP:             "ip",
 	UserAgent:      "userAgent",
@@ -131,6 +147,7 @@ var ContextKeys = struct {
 	ZapierToken:    "parsedToken",
 	ZapierProject:  "project",
 	SessionId:      "sessionId",
+	APIToken:       "apiToken",
 }
 
 var Models = []interface{}{
@@ -155,6 +172,7 @@ var Models = []interface{}{
 	&EmailSignup{},
 	&ResourcesObject{},
 	&ExternalAttachment{},
+	&ExternalIssue{},
 	&SessionComment{},
 	&SessionCommentTag{},
 	&ErrorComment{},
@@ -163,6 +181,7 @@ var Models = []interface{}{
 	&CommentSlackThread{},
 	&ErrorAlert{},
 	&ErrorAlertEvent{},
+	&ErrorGroupBaseline{},
 	&SessionAlert{},
 	&SessionAlertEvent{},
 	&LogAlert{},
@@ -178,6 +197,12 @@ var Models = []interface{}{
 	&MetricGroup{},
 	&Metric{},
 	&MetricMonitor{},
+	&UptimeMonitor{},
+	&HeartbeatMonitor{},
+	&TraceAlert{},
+	&EscalationPolicy{},
+	&OnCallSchedule{},
+	&AlertEscalation{},
 	&ErrorFingerprint{},
 	&EventChunk{},
 	&SavedAsset{},
@@ -189,6 +214,14 @@ var Models = []interface{}{
 	&OAuthClientStore{},
 	&OAuthOperation{},
 	&ResthookSubscription{},
+	&WebhookDelivery{},
+	&DatadogLogForwarder{},
+	&ProductAnalyticsExport{},
+	&WarehouseExport{},
+	&WorkspaceSSOConfig{},
+	&WorkspaceSCIMUser{},
+	&WorkspaceRole{},
+	&APIToken{},
 	&IntegrationProjectMapping{},
 	&IntegrationWorkspaceMapping{},
 	&EmailOptOut{},
@@ -200,8 +233,18 @@ var Models = []interface{}{
 	&ErrorGroupAdminsView{},
 	&LogAdminsView{},
 	&ProjectFilterSettings{},
+	&ProjectDigestSetting{},
 	&AllWorkspaceSettings{},
 	&ErrorGroupActivityLog{},
+	&ErrorWorkflowRule{},
+	&ErrorOwnershipRule{},
+	&ErrorGroupingRule{},
+	&ErrorIgnoreRule{},
+	&SourcemapArtifact{},
+	&SourcemapBucket{},
+	&Release{},
+	&IngestFilterRule{},
+	&ProjectSDK{},
 	&UserJourneyStep{},
 	&SystemConfiguration{},
 	&SessionInsight{},
@@ -354,6 +397,41 @@ type WorkspaceAdminRole struct {
 	Role  string
 }
 
+// WorkspaceRole is a custom role of a workspace, that admins are assigned by its name.
+type WorkspaceRole struct {
+	Model
+	WorkspaceID int            `json:"workspace_id" gorm:"uniqueIndex:idx_workspace_roles_name"`
+	Name        string         `json:"name" gorm:"uniqueIndex:idx_workspace_roles_name"`
+	Description string         `json:"description"`
+	Permissions pq.StringArray `json:"permissions" gorm:"type:text[]"`
+}
+
+// APIToken authenticates requests to the private graph and the REST API as an admin, limited to
+// the data of its scopes. Only the hash of the token is stored.
+type APIToken struct {
+	Model
+	// WorkspaceID is the only workspace that a workspace token can access. Personal tokens can
+	// access every workspace of their admin.
+	WorkspaceID *int `json:"workspace_id" gorm:"index"`
+	// AdminID is the admin that the token acts as: the owner of a personal token, or the admin that
+	// created a workspace token.
+	AdminID int    `json:"admin_id" gorm:"index"`
+	Name    string `json:"name"`
+	// Prefix is the start of the token, that tells tokens apart.
+	Prefix     string         `json:"prefix"`
+	TokenHash  string         `json:"-" gorm:"uniqueIndex"`
+	Scopes     pq.StringArray `json:"scopes" gorm:"type:text[]"`
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+}
+
+// CreatedAPIToken is a new or rotated API token, with the token that is only returned once.
+type CreatedAPIToken struct {
+	APIToken *APIToken `json:"api_token"`
+	Token    string    `json:"token"`
+}
+
 type WorkspaceInviteLink struct {
 	Model
 	WorkspaceID    *int
@@ -402,6 +480,12 @@ type Project struct {
 	// Applies to all browser extensions
 	// TODO - rename to FilterBrowserExtension #5811
 	FilterChromeExtension *bool `gorm:"default:false"`
+
+	// Reject otel data that is not sent with the project secret as its ingest key
+	RequireIngestKey bool `gorm:"default:false"`
+
+	// Discord webhooks that the weekly digest of the project is posted to
+	DiscordDigestWebhooks DiscordWebhooks `gorm:"type:jsonb;default:'[]'"`
 }
 
 type MarkBackendSetupType = string
@@ -440,6 +524,75 @@ type ProjectFilterSettings struct {
 	ErrorExclusionQuery               *string
 	LogExclusionQuery                 *string
 	TraceExclusionQuery               *string
+	// Attribute keys whose values are redacted from otel data at ingest
+	RedactedAttributeKeys pq.StringArray `gorm:"type:text[]"`
+	// Regular expressions, or the names of redaction presets, matching attribute values to redact
+	RedactionPatterns pq.StringArray `gorm:"type:text[]"`
+	// Create errors from otel spans with an error status but no exception event
+	ErrorsFromSpanStatus bool `gorm:"default:false"`
+	// Service names whose otel spans and logs are dropped at ingest
+	ExcludedServiceNames pq.StringArray `gorm:"type:text[]"`
+	// Log levels, ie. `debug`, whose otel logs are dropped at ingest
+	ExcludedLogLevels pq.StringArray `gorm:"type:text[]"`
+	// Secret that the payloads of the project's alert webhooks are signed with
+	WebhookSigningSecret *string `json:"-"`
+	// Number of times a failed alert webhook delivery is retried
+	WebhookMaxRetries int `gorm:"default:4"`
+	// Number of errors of an error group per hour whose payloads are all stored, after which they are sampled
+	ErrorPayloadHourlyLimit *int64
+	// Share of the errors over the hourly limit whose payloads are stored, the rest only count towards the aggregates of their group
+	ErrorPayloadSamplingRate float64 `gorm:"default:0"`
+	// Days that the payloads of errors are kept, which are deleted before the errors themselves
+	ErrorPayloadRetentionDays *int
+}
+
+type DigestFrequency = string
+
+const (
+	DigestFrequencyDaily  DigestFrequency = "DAILY"
+	DigestFrequencyWeekly DigestFrequency = "WEEKLY"
+	DigestFrequencyNever  DigestFrequency = "NEVER"
+)
+
+// ProjectDigestSetting is the schedule of the digest emails of a project, which are sent by the
+// digest worker at Hour (UTC), on Weekday for weekly digests. Projects without one get the weekly
+// digest of the digests lambda.
+type ProjectDigestSetting struct {
+	Model
+	ProjectID  int             `gorm:"uniqueIndex" json:"project_id"`
+	Frequency  DigestFrequency `gorm:"default:WEEKLY" json:"frequency"`
+	Weekday    time.Weekday    `json:"weekday"`
+	Hour       int             `gorm:"default:0" json:"hour"`
+	LastSentAt *time.Time      `json:"last_sent_at"`
+}
+
+// GetPeriod returns the duration that a digest summarizes.
+func (obj *ProjectDigestSetting) GetPeriod() time.Duration {
+	if obj.Frequency == DigestFrequencyDaily {
+		return 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// GetScheduledAt returns the latest time that a digest was scheduled at, at or before a time.
+func (obj *ProjectDigestSetting) GetScheduledAt(now time.Time) time.Time {
+	now = now.UTC()
+	scheduledAt := time.Date(now.Year(), now.Month(), now.Day(), obj.Hour, 0, 0, 0, time.UTC)
+	if obj.Frequency != DigestFrequencyDaily {
+		scheduledAt = scheduledAt.AddDate(0, 0, int(obj.Weekday-now.Weekday()))
+	}
+	if scheduledAt.After(now) {
+		scheduledAt = scheduledAt.Add(-obj.GetPeriod())
+	}
+	return scheduledAt
+}
+
+// IsDue returns whether the digest scheduled at or before a time was not sent yet.
+func (obj *ProjectDigestSetting) IsDue(now time.Time) bool {
+	if obj.Frequency == DigestFrequencyNever {
+		return false
+	}
+	return obj.LastSentAt == nil || obj.LastSentAt.Before(obj.GetScheduledAt(now))
 }
 
 type AllWorkspaceSettings struct {
@@ -488,6 +641,115 @@ type ResthookSubscription struct {
 	TargetUrl *string `json:"target_url"`
 }
 
+// DatadogLogForwarder forwards the logs of a project to the logs intake of a Datadog account, so that
+// projects migrating between Datadog and highlight can write their logs to both.
+type DatadogLogForwarder struct {
+	Model
+	ProjectID        int       `json:"project_id" gorm:"uniqueIndex"`
+	Site             string    `json:"site"`
+	APIKey           string    `json:"-"`
+	AttributeMapping StringMap `json:"attribute_mapping" gorm:"type:jsonb"`
+	Enabled          bool      `json:"enabled"`
+	// ForwardedUntil is the end of the last window of logs that was forwarded.
+	ForwardedUntil *time.Time `json:"forwarded_until"`
+	LastError      *string    `json:"last_error"`
+}
+
+// ProductAnalyticsExport exports the rage clicks and identified sessions of a project as events of
+// an Amplitude or Mixpanel project, linking them to their session replays.
+type ProductAnalyticsExport struct {
+	Model
+	ProjectID   int    `json:"project_id" gorm:"uniqueIndex:idx_product_analytics_export_project_destination"`
+	Destination string `json:"destination" gorm:"uniqueIndex:idx_product_analytics_export_project_destination"`
+	Region      string `json:"region"`
+	// APIKey is the api key of an Amplitude project or the project token of a Mixpanel project.
+	APIKey string `json:"-"`
+	// UserIDProperty is the session field or user property that is the user id of the events.
+	UserIDProperty           string `json:"user_id_property"`
+	ExportRageClicks         bool   `json:"export_rage_clicks"`
+	ExportIdentifiedSessions bool   `json:"export_identified_sessions"`
+	Enabled                  bool   `json:"enabled"`
+	// LastSessionID and LastRageClickID are the ids of the last exported session and rage click.
+	LastSessionID   int     `json:"last_session_id"`
+	LastRageClickID int     `json:"last_rage_click_id"`
+	LastError       *string `json:"last_error"`
+}
+
+// WarehouseExport exports the sessions, error groups and optionally sampled logs of a project every
+// day as Parquet files to a bucket, and loads them into a BigQuery dataset or a Snowflake schema.
+type WarehouseExport struct {
+	Model
+	ProjectID   int    `json:"project_id" gorm:"uniqueIndex"`
+	Destination string `json:"destination"`
+	// StorageURL is the bucket and prefix of the exported files, ie. `gs://bucket/prefix`.
+	StorageURL             string `json:"storage_url"`
+	StorageAccessKeyID     string `json:"storage_access_key_id"`
+	StorageSecretAccessKey string `json:"-"`
+	// BigQueryCredentials is the json key of a service account that can run load jobs.
+	BigQueryProjectID   string `json:"bigquery_project_id"`
+	BigQueryDataset     string `json:"bigquery_dataset"`
+	BigQueryCredentials string `json:"-"`
+	// SnowflakePrivateKey is the private key of the key pair of the Snowflake user.
+	SnowflakeAccount    string  `json:"snowflake_account"`
+	SnowflakeUser       string  `json:"snowflake_user"`
+	SnowflakeRole       string  `json:"snowflake_role"`
+	SnowflakeWarehouse  string  `json:"snowflake_warehouse"`
+	SnowflakeDatabase   string  `json:"snowflake_database"`
+	SnowflakeSchema     string  `json:"snowflake_schema"`
+	SnowflakeStage      string  `json:"snowflake_stage"`
+	SnowflakePrivateKey string  `json:"-"`
+	ExportLogs          bool    `json:"export_logs"`
+	LogSampleRate       float64 `json:"log_sample_rate"`
+	Enabled             bool    `json:"enabled"`
+	// ExportedUntil is the end of the last day that was exported, with the SchemaVersion of its tables.
+	ExportedUntil *time.Time `json:"exported_until"`
+	SchemaVersion int        `json:"schema_version"`
+	LastError     *string    `json:"last_error"`
+}
+
+// WorkspaceSSOConfig signs in the admins of a workspace with the SAML or OIDC app of an Okta or
+// Azure AD identity provider, and lets the identity provider provision admins over SCIM.
+type WorkspaceSSOConfig struct {
+	Model
+	WorkspaceID int    `json:"workspace_id" gorm:"uniqueIndex"`
+	Provider    string `json:"provider"`
+	Protocol    string `json:"protocol"`
+	// Domain is the email domain of the admins that must sign in with the identity provider.
+	Domain string `json:"domain" gorm:"index"`
+	// DomainVerificationToken is the value of the TXT record that proves the workspace owns the domain.
+	DomainVerificationToken string `json:"-"`
+	// DomainVerifiedAt is when the TXT record was found. Single sign-on and scim provisioning
+	// require a verified domain.
+	DomainVerifiedAt   *time.Time `json:"domain_verified_at"`
+	SAMLIdPEntityID    string     `json:"saml_idp_entity_id"`
+	SAMLIdPSSOURL      string     `json:"saml_idp_sso_url"`
+	SAMLIdPCertificate string     `json:"saml_idp_certificate"`
+	OIDCIssuer         string     `json:"oidc_issuer"`
+	OIDCClientID       string     `json:"oidc_client_id"`
+	OIDCClientSecret   string     `json:"-"`
+	// GroupRoles maps the groups of the identity provider to the ADMIN or MEMBER workspace role.
+	GroupRoles  StringMap `json:"group_roles" gorm:"type:jsonb"`
+	DefaultRole string    `json:"default_role"`
+	// SCIMToken is the bearer token of the SCIM requests of the identity provider.
+	SCIMToken string `json:"-"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// WorkspaceSCIMUser is an admin provisioned in a workspace by the identity provider of its SSO
+// config. Deactivated users are removed from the workspace but kept so that they can be reactivated.
+type WorkspaceSCIMUser struct {
+	Model
+	WorkspaceID int            `json:"workspace_id" gorm:"uniqueIndex:idx_workspace_scim_users_admin"`
+	AdminID     int            `json:"admin_id" gorm:"uniqueIndex:idx_workspace_scim_users_admin"`
+	ExternalID  string         `json:"external_id"`
+	UserName    string         `json:"user_name"`
+	Email       string         `json:"email"`
+	GivenName   string         `json:"given_name"`
+	FamilyName  string         `json:"family_name"`
+	Roles       pq.StringArray `json:"roles" gorm:"type:text[]"`
+	Active      bool           `json:"active"`
+}
+
 type RegistrationData struct {
 	Model
 	WorkspaceID int
@@ -962,6 +1224,163 @@ type MetricMonitor struct {
 	AlertIntegrations
 }
 
+type UptimeMonitorType = string
+
+const (
+	UptimeMonitorTypeHTTP UptimeMonitorType = "HTTP"
+	// UptimeMonitorTypePing checks that a TCP connection can be opened to the monitor host.
+	UptimeMonitorTypePing UptimeMonitorType = "PING"
+)
+
+type UptimeMonitor struct {
+	Model
+	ProjectID          int               `gorm:"index;not null;"`
+	Name               string            `gorm:"not null"`
+	Type               UptimeMonitorType `gorm:"default:HTTP"`
+	URL                string            `gorm:"not null"`
+	Method             string            `gorm:"default:GET"`
+	IntervalSeconds    int               `gorm:"default:60"`
+	TimeoutSeconds     int               `gorm:"default:10"`
+	ExpectedStatusCode *int              // when nil, any 2xx response is considered healthy
+	BodyContains       *string
+	MaxLatencyMs       *int
+	FailureThreshold   int `gorm:"default:2"` // consecutive failed checks before an alert is sent
+	ChannelsToNotify   *string
+	EmailsToNotify     *string
+	LastAdminToEditID  int
+	Disabled           *bool `gorm:"default:false"`
+	AlertIntegrations
+}
+
+type HeartbeatCheckInStatus = string
+
+const (
+	HeartbeatCheckInStatusOK         HeartbeatCheckInStatus = "ok"
+	HeartbeatCheckInStatusError      HeartbeatCheckInStatus = "error"
+	HeartbeatCheckInStatusInProgress HeartbeatCheckInStatus = "in_progress"
+)
+
+// HeartbeatMonitor expects a check-in from a cron job or other periodic task
+// every IntervalSeconds, and alerts when a check-in is missed or reports an error.
+type HeartbeatMonitor struct {
+	Model
+	ProjectID         int    `gorm:"index;not null;"`
+	SecureID          string `gorm:"uniqueIndex;not null"` // used in the check-in url so that it cannot be guessed
+	Name              string `gorm:"not null"`
+	IntervalSeconds   int    `gorm:"default:3600"`
+	GraceSeconds      int    `gorm:"default:300"` // how late a check-in may arrive before it is considered missed
+	LastCheckInAt     *time.Time
+	LastCheckInStatus *HeartbeatCheckInStatus
+	LastAlertSentAt   *time.Time
+	ChannelsToNotify  *string
+	EmailsToNotify    *string
+	LastAdminToEditID int
+	Disabled          *bool `gorm:"default:false"`
+	AlertIntegrations
+}
+
+// GetAlertReason returns why the monitor should alert at the given time, or an empty string
+// if it should not. Each missed deadline or failed check-in alerts at most once.
+func (obj *HeartbeatMonitor) GetAlertReason(now time.Time) string {
+	lastCheckIn := obj.CreatedAt
+	if obj.LastCheckInAt != nil {
+		lastCheckIn = *obj.LastCheckInAt
+	}
+
+	if obj.LastCheckInAt != nil && obj.LastCheckInStatus != nil && *obj.LastCheckInStatus == HeartbeatCheckInStatusError {
+		if obj.LastAlertSentAt == nil || obj.LastAlertSentAt.Before(lastCheckIn) {
+			return fmt.Sprintf("check-in at %s reported an error", lastCheckIn.Format(time.RFC3339))
+		}
+	}
+
+	deadline := lastCheckIn.Add(time.Duration(obj.IntervalSeconds+obj.GraceSeconds) * time.Second)
+	if now.After(deadline) && (obj.LastAlertSentAt == nil || obj.LastAlertSentAt.Before(deadline)) {
+		return fmt.Sprintf("no check-in received since %s", lastCheckIn.Format(time.RFC3339))
+	}
+	return ""
+}
+
+type TraceAlertMetric = string
+
+const (
+	// TraceAlertMetricLatency is the latency of the spans in milliseconds, aggregated by the
+	// aggregator of the alert.
+	TraceAlertMetricLatency TraceAlertMetric = "LATENCY"
+	// TraceAlertMetricErrorRate is the percentage of the spans with an error status.
+	TraceAlertMetricErrorRate TraceAlertMetric = "ERROR_RATE"
+)
+
+// TraceAlert alerts when the latency or error rate of the spans of a service, or of one of its
+// operations such as an endpoint, crosses the threshold over the last WindowMinutes.
+type TraceAlert struct {
+	Model
+	ProjectID          int    `gorm:"index;not null;"`
+	Name               string `gorm:"not null"`
+	ServiceName        string
+	SpanName           string
+	Query              string                       // additional trace search filters of the spans
+	Metric             TraceAlertMetric             `gorm:"default:LATENCY"`
+	Aggregator         modelInputs.MetricAggregator `gorm:"default:P95"`
+	Threshold          float64                      // milliseconds of latency, or a percentage of errors
+	WindowMinutes      int                          `gorm:"default:5"`
+	MinSpans           int                          `gorm:"default:1"` // windows with fewer spans are not evaluated, so that a few slow requests do not alert
+	State              AlertState                   `gorm:"default:NORMAL"`
+	StateChangedAt     *time.Time
+	ChannelsToNotify   *string
+	LastAdminToEditID  int
+	Disabled           *bool `gorm:"default:false"`
+	EscalationPolicyID *int
+	AlertIntegrations
+}
+
+// GetQuery returns the trace search query of the spans of the alert.
+func (obj *TraceAlert) GetQuery() string {
+	var filters []string
+	if obj.ServiceName != "" {
+		filters = append(filters, fmt.Sprintf("%s:%q", modelInputs.ReservedTraceKeyServiceName, obj.ServiceName))
+	}
+	if obj.SpanName != "" {
+		filters = append(filters, fmt.Sprintf("%s:%q", modelInputs.ReservedTraceKeySpanName, obj.SpanName))
+	}
+	if obj.Query != "" {
+		filters = append(filters, obj.Query)
+	}
+	return strings.Join(filters, " ")
+}
+
+// GetValue returns the value of the metric of the alert for the spans of a window, in the units of
+// its threshold.
+func (obj *TraceAlert) GetValue(spanCount uint64, errorCount uint64, latency time.Duration) float64 {
+	if obj.Metric == TraceAlertMetricErrorRate {
+		if spanCount == 0 {
+			return 0
+		}
+		return 100 * float64(errorCount) / float64(spanCount)
+	}
+	return float64(latency) / float64(time.Millisecond)
+}
+
+// Describe returns what the alert measures, eg. `P95 latency of checkout POST /orders`.
+func (obj *TraceAlert) Describe() string {
+	metric := fmt.Sprintf("%s latency", obj.Aggregator)
+	if obj.Metric == TraceAlertMetricErrorRate {
+		metric = "error rate"
+	}
+	target := strings.TrimSpace(fmt.Sprintf("%s %s", obj.ServiceName, obj.SpanName))
+	if target == "" {
+		return metric
+	}
+	return fmt.Sprintf("%s of %s", metric, target)
+}
+
+// FormatValue formats a value of the metric of the alert with its units.
+func (obj *TraceAlert) FormatValue(value float64) string {
+	if obj.Metric == TraceAlertMetricErrorRate {
+		return fmt.Sprintf("%.2f%%", value)
+	}
+	return fmt.Sprintf("%.0fms", value)
+}
+
 func (m *MessagesObject) Contents() string {
 	return m.Messages
 }
@@ -990,6 +1409,38 @@ type ErrorResults struct {
 	TotalCount  int64
 }
 
+// ErrorGroupImpact is how many times an error group occurred in a time range, and how many users and
+// sessions its errors affected.
+type ErrorGroupImpact struct {
+	Occurrences      int64
+	AffectedUsers    int64
+	AffectedSessions int64
+	ImpactScore      float64
+}
+
+type ErrorGroupWithImpact struct {
+	ErrorGroup *ErrorGroup
+	Impact     *ErrorGroupImpact
+}
+
+type ErrorGroupImpactResults struct {
+	ErrorGroups []*ErrorGroupWithImpact
+	TotalCount  int64
+}
+
+// ErrorGroupTrends are the impact of an error group in a time range and in buckets of the time range.
+type ErrorGroupTrends struct {
+	Impact  *ErrorGroupImpact
+	Buckets []*ErrorGroupTrendBucket
+}
+
+type ErrorGroupTrendBucket struct {
+	Date             time.Time
+	Occurrences      int64
+	AffectedUsers    int64
+	AffectedSessions int64
+}
+
 type ErrorSearchParams struct {
 	DateRange  *DateRange              `json:"date_range"`
 	Browser    *string                 `json:"browser"`
@@ -1022,6 +1473,8 @@ type ErrorObject struct {
 	SessionID               *int
 	TraceID                 *string
 	SpanID                  *string
+	ParentSpanID            *string
+	SpanLinks               *string `gorm:"type:jsonb"` // the links of the span, as TraceLinks
 	LogCursor               *string `gorm:"index:idx_error_object_log_cursor,option:CONCURRENTLY"`
 	ErrorGroupID            int     `gorm:"index:idx_error_group_id_id,priority:1,option:CONCURRENTLY"`
 	ErrorGroupIDAlternative int     // the alternative algorithm for grouping the object
@@ -1068,6 +1521,10 @@ type ErrorGroup struct {
 	MappedStackTrace *string
 	State            modelInputs.ErrorState `json:"state" gorm:"default:OPEN"`
 	SnoozedUntil     *time.Time             `json:"snoozed_until"`
+	SnoozedVersion   *string                `json:"snoozed_version"`  // set when snoozed until the next release
+	ResolvedVersion  *string                `json:"resolved_version"` // set when resolved in a service version
+	FirstSeenVersion *string                `json:"first_seen_version"`
+	LastSeenVersion  *string                `json:"last_seen_version"`
 	Fields           []*ErrorField          `gorm:"many2many:error_group_fields;" json:"fields"`
 	Fingerprints     []*ErrorFingerprint
 	FieldGroup       *string
@@ -1077,8 +1534,13 @@ type ErrorGroup struct {
 	ErrorMetrics     []*modelInputs.ErrorDistributionItem `gorm:"-"`
 	FirstOccurrence  *time.Time                           `gorm:"-"`
 	LastOccurrence   *time.Time                           `gorm:"-"`
+	Regressed        bool                                 `gorm:"-" json:"-"` // reopened by the error being processed
+	Created          bool                                 `gorm:"-" json:"-"` // created by the error being processed
 	ErrorObjects     []ErrorObject
 	ServiceName      string
+	AssigneeID       *int           `json:"assignee_id" gorm:"index"` // the admin that owns the error group
+	MergedIntoID     *int           `json:"merged_into_id"`           // set when merged into another error group, which gets its new errors
+	SuspectCommit    *SuspectCommit `json:"suspect_commit" gorm:"type:jsonb"`
 
 	// manually migrate as gorm wants to make this have a default value otherwise
 	ErrorTagID *int      `gorm:"-:migration"`
@@ -1089,6 +1551,126 @@ type ErrorGroup struct {
 	Viewed         *bool   `json:"viewed"`
 }
 
+type ErrorResolutionState = string
+
+const (
+	ErrorResolutionOpen              ErrorResolutionState = "open"
+	ErrorResolutionResolved          ErrorResolutionState = "resolved"
+	ErrorResolutionResolvedInVersion ErrorResolutionState = "resolved_in_version"
+	ErrorResolutionIgnored           ErrorResolutionState = "ignored"
+	ErrorResolutionSnoozed           ErrorResolutionState = "snoozed"
+)
+
+// GetResolutionState returns the state of an error group, distinguishing the error groups that are
+// resolved in a service version and the open ones that are snoozed.
+func (eg *ErrorGroup) GetResolutionState(now time.Time) ErrorResolutionState {
+	switch eg.State {
+	case modelInputs.ErrorStateResolved:
+		if eg.ResolvedVersion != nil {
+			return ErrorResolutionResolvedInVersion
+		}
+		return ErrorResolutionResolved
+	case modelInputs.ErrorStateIgnored:
+		return ErrorResolutionIgnored
+	}
+	if eg.SnoozedUntil != nil && eg.SnoozedUntil.After(now) {
+		return ErrorResolutionSnoozed
+	}
+	return ErrorResolutionOpen
+}
+
+// IsRegression returns whether an error of a service version reopens a resolved error group. An
+// error group resolved in a version is only reopened by errors of that version or a newer one, so
+// that clients that have not updated yet do not reopen it.
+func (eg *ErrorGroup) IsRegression(serviceVersion string) bool {
+	if eg.State != modelInputs.ErrorStateResolved {
+		return false
+	}
+	if eg.ResolvedVersion == nil {
+		return true
+	}
+	if serviceVersion == "" {
+		return false
+	}
+	if cmp, ok := CompareServiceVersions(serviceVersion, *eg.ResolvedVersion); ok {
+		return cmp >= 0
+	}
+	// versions that are not numeric, such as commit hashes, cannot be ordered
+	return serviceVersion != *eg.ResolvedVersion
+}
+
+// SeenInVersion records the service version of an error of the group, returning whether its first or
+// last seen version changed. Versions that cannot be ordered, such as commit hashes, replace the last
+// seen version, while older numeric versions that are still running only move the first seen version.
+func (eg *ErrorGroup) SeenInVersion(serviceVersion string) bool {
+	if serviceVersion == "" {
+		return false
+	}
+	changed := false
+	if eg.FirstSeenVersion == nil {
+		eg.FirstSeenVersion = &serviceVersion
+		changed = true
+	} else if cmp, ok := CompareServiceVersions(serviceVersion, *eg.FirstSeenVersion); ok && cmp < 0 {
+		eg.FirstSeenVersion = &serviceVersion
+		changed = true
+	}
+	if eg.LastSeenVersion == nil {
+		eg.LastSeenVersion = &serviceVersion
+		changed = true
+	} else if *eg.LastSeenVersion != serviceVersion {
+		if cmp, ok := CompareServiceVersions(serviceVersion, *eg.LastSeenVersion); !ok || cmp > 0 {
+			eg.LastSeenVersion = &serviceVersion
+			changed = true
+		}
+	}
+	return changed
+}
+
+// CompareServiceVersions compares dotted numeric versions such as `v1.2.10`, ignoring pre-release
+// and build suffixes. It returns false if either version is not numeric.
+func CompareServiceVersions(a string, b string) (int, bool) {
+	aParts, aOk := parseServiceVersion(a)
+	bParts, bOk := parseServiceVersion(b)
+	if !aOk || !bOk {
+		return 0, false
+	}
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aPart, bPart int
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		if aPart != bPart {
+			if aPart < bPart {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseServiceVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
 type ErrorTag struct {
 	Model
 	Title       string `gorm:"uniqueIndex;not null"`
@@ -1107,6 +1689,10 @@ const (
 	ErrorGroupResolvedEvent ErrorGroupEventType = "ErrorGroupResolved"
 	ErrorGroupIgnoredEvent  ErrorGroupEventType = "ErrorGroupIgnored"
 	ErrorGroupOpenedEvent   ErrorGroupEventType = "ErrorGroupOpened"
+	ErrorGroupSnoozedEvent  ErrorGroupEventType = "ErrorGroupSnoozed"
+	ErrorGroupAssignedEvent ErrorGroupEventType = "ErrorGroupAssigned"
+	ErrorGroupMergedEvent   ErrorGroupEventType = "ErrorGroupMerged"
+	ErrorGroupSplitEvent    ErrorGroupEventType = "ErrorGroupSplit"
 )
 
 type ErrorGroupActivityLog struct {
@@ -1118,6 +1704,453 @@ type ErrorGroupActivityLog struct {
 	EventData    JSONB
 }
 
+type ErrorWorkflowRuleAction string
+
+const (
+	ErrorWorkflowRuleActionIgnore  ErrorWorkflowRuleAction = "ignore"
+	ErrorWorkflowRuleActionResolve ErrorWorkflowRuleAction = "resolve"
+	ErrorWorkflowRuleActionSnooze  ErrorWorkflowRuleAction = "snooze"
+)
+
+// ErrorWorkflowRule automatically changes the state of matching error groups.
+// Ignore and snooze rules are evaluated as errors are processed, while resolve rules
+// are evaluated periodically by the auto-resolver.
+type ErrorWorkflowRule struct {
+	Model
+	ProjectID int                     `gorm:"index;not null;"`
+	Name      string                  `gorm:"not null"`
+	Action    ErrorWorkflowRuleAction `gorm:"not null"`
+	// Only error groups with an event matching the regex are affected. Matches all events when empty.
+	EventRegex *string
+	// Resolve rules resolve open error groups that have not been seen for this many days.
+	StaleDays *int
+	// Snooze rules snooze for this long, or until the error is seen in a new service version when unset.
+	SnoozeMinutes     *int
+	Disabled          bool `gorm:"default:false"`
+	LastAdminToEditID int
+}
+
+func (rule *ErrorWorkflowRule) Validate() error {
+	switch rule.Action {
+	case ErrorWorkflowRuleActionIgnore, ErrorWorkflowRuleActionSnooze:
+	case ErrorWorkflowRuleActionResolve:
+		if rule.StaleDays == nil || *rule.StaleDays <= 0 {
+			return e.New("stale_days must be positive for resolve rules")
+		}
+	default:
+		return e.Errorf("invalid action %s", rule.Action)
+	}
+	if rule.SnoozeMinutes != nil && *rule.SnoozeMinutes <= 0 {
+		return e.New("snooze_minutes must be positive")
+	}
+	if rule.EventRegex != nil && *rule.EventRegex != "" {
+		if _, err := regexp.Compile(*rule.EventRegex); err != nil {
+			return e.Wrap(err, "invalid event_regex")
+		}
+	}
+	return nil
+}
+
+func (rule *ErrorWorkflowRule) MatchesEvent(event string) bool {
+	if rule.EventRegex == nil || *rule.EventRegex == "" {
+		return true
+	}
+	matched, err := regexp.MatchString(*rule.EventRegex, event)
+	return err == nil && matched
+}
+
+// ErrorOwnershipRule assigns new error groups to the admin that owns them, similar to a CODEOWNERS
+// file. A rule matches the errors of its service with a stack frame in a file matching its pattern.
+type ErrorOwnershipRule struct {
+	Model
+	ProjectID int    `gorm:"index;not null;"`
+	Name      string `gorm:"not null"`
+	// A CODEOWNERS path pattern such as `*.go`, `src/api/` or `docs/**/*.md`, matched against the file
+	// names of stack frames in any directory. Matches all files when empty.
+	Pattern *string
+	// Only errors of this service are matched. Matches all services when empty.
+	ServiceName       *string
+	AssigneeID        int  `gorm:"not null"`
+	Disabled          bool `gorm:"default:false"`
+	LastAdminToEditID int
+}
+
+func (rule *ErrorOwnershipRule) Validate() error {
+	if (rule.Pattern == nil || *rule.Pattern == "") && (rule.ServiceName == nil || *rule.ServiceName == "") {
+		return e.New("a pattern or service_name is required")
+	}
+	if rule.AssigneeID == 0 {
+		return e.New("assignee_id is required")
+	}
+	if rule.Pattern != nil && *rule.Pattern != "" {
+		if _, err := compileOwnershipPattern(*rule.Pattern); err != nil {
+			return e.Wrap(err, "invalid pattern")
+		}
+	}
+	return nil
+}
+
+func (rule *ErrorOwnershipRule) hasPattern() bool {
+	return rule.Pattern != nil && *rule.Pattern != ""
+}
+
+func (rule *ErrorOwnershipRule) matchesService(serviceName string) bool {
+	return rule.ServiceName == nil || *rule.ServiceName == "" || *rule.ServiceName == serviceName
+}
+
+func (rule *ErrorOwnershipRule) MatchesFile(fileName string) bool {
+	if !rule.hasPattern() {
+		return true
+	}
+	pattern, err := compileOwnershipPattern(*rule.Pattern)
+	return err == nil && pattern.MatchString(normalizeOwnershipFileName(fileName))
+}
+
+// FindErrorOwnershipRule returns the rule that owns an error of a service with the file names of its
+// stack frames, from the top of the stack. As with CODEOWNERS, the last matching rule of a frame
+// wins. Rules without a pattern own the errors of their service that no frame is owned for.
+func FindErrorOwnershipRule(rules []*ErrorOwnershipRule, serviceName string, fileNames []string) *ErrorOwnershipRule {
+	var owner *ErrorOwnershipRule
+	for _, fileName := range fileNames {
+		for _, rule := range rules {
+			if rule.hasPattern() && rule.matchesService(serviceName) && rule.MatchesFile(fileName) {
+				owner = rule
+			}
+		}
+		if owner != nil {
+			return owner
+		}
+	}
+	for _, rule := range rules {
+		if !rule.hasPattern() && rule.matchesService(serviceName) {
+			owner = rule
+		}
+	}
+	return owner
+}
+
+// compileOwnershipPattern converts a CODEOWNERS path pattern to a regex matching file paths that
+// contain it at a directory boundary. A trailing slash matches everything in the directory.
+func compileOwnershipPattern(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimPrefix(pattern, "/")
+	isDir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return nil, e.New("empty pattern")
+	}
+
+	var expr strings.Builder
+	expr.WriteString("(^|/)")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if isDir {
+		expr.WriteString("/")
+	} else {
+		expr.WriteString("(/|$)")
+	}
+	return regexp.Compile(expr.String())
+}
+
+// normalizeOwnershipFileName strips the scheme and host of the file names of frontend stack frames.
+func normalizeOwnershipFileName(fileName string) string {
+	if _, path, found := strings.Cut(fileName, "://"); found {
+		if idx := strings.Index(path, "/"); idx >= 0 {
+			return path[idx:]
+		}
+		return ""
+	}
+	return fileName
+}
+
+type ErrorGroupingRuleType string
+
+const (
+	// Groups errors by the type of their exception, such as `TypeError`, ignoring their message.
+	ErrorGroupingRuleTypeExceptionType ErrorGroupingRuleType = "exception_type"
+	// Groups errors by their message, with the numbers, UUIDs and hex ids in it stripped.
+	ErrorGroupingRuleTypeStrippedMessage ErrorGroupingRuleType = "stripped_message"
+	// Groups errors by the file and function of the top frame of their stack trace.
+	ErrorGroupingRuleTypeTopFrame ErrorGroupingRuleType = "top_frame"
+)
+
+// ErrorGroupingRule overrides how the errors of a project are grouped. The first enabled rule that
+// matches an error, in order of creation, fingerprints it, and errors with the same fingerprint are
+// grouped together instead of by the similarity of their stack traces.
+type ErrorGroupingRule struct {
+	Model
+	ProjectID int                   `gorm:"index;not null;"`
+	Name      string                `gorm:"not null"`
+	Type      ErrorGroupingRuleType `gorm:"not null"`
+	// A regex matched against the error event. Matches all errors when empty.
+	EventRegex *string
+	// Only errors of this service are matched. Matches all services when empty.
+	ServiceName       *string
+	Disabled          bool `gorm:"default:false"`
+	LastAdminToEditID int
+}
+
+func (rule *ErrorGroupingRule) Validate() error {
+	switch rule.Type {
+	case ErrorGroupingRuleTypeExceptionType, ErrorGroupingRuleTypeStrippedMessage, ErrorGroupingRuleTypeTopFrame:
+	default:
+		return e.Errorf("invalid type %s", rule.Type)
+	}
+	if rule.EventRegex != nil && *rule.EventRegex != "" {
+		if _, err := regexp.Compile(*rule.EventRegex); err != nil {
+			return e.Wrap(err, "invalid event_regex")
+		}
+	}
+	return nil
+}
+
+func (rule *ErrorGroupingRule) Matches(event string, serviceName string) bool {
+	if rule.ServiceName != nil && *rule.ServiceName != "" && *rule.ServiceName != serviceName {
+		return false
+	}
+	if rule.EventRegex == nil || *rule.EventRegex == "" {
+		return true
+	}
+	matched, err := regexp.MatchString(*rule.EventRegex, event)
+	return err == nil && matched
+}
+
+// IngestFilterRule drops or keeps the otel spans and logs of a project that match all of its set conditions.
+// The enabled rules of a project are evaluated in order of creation and the first matching rule decides,
+// so keep rules take precedence over the excluded services and log levels of the project.
+type IngestFilterRule struct {
+	Model
+	ProjectID int                                `gorm:"index;not null;"`
+	Name      string                             `gorm:"not null"`
+	Action    modelInputs.IngestFilterRuleAction `gorm:"not null"`
+	// Matches the service.name of the span or log.
+	ServiceName *string
+	// Matches the log level of logs. Rules with a severity never match spans.
+	Severity *string
+	// Matches spans and logs with an attribute of this key, whose value matches AttributeRegex when set.
+	AttributeKey      *string
+	AttributeRegex    *string
+	Disabled          bool `gorm:"default:false"`
+	LastAdminToEditID int
+}
+
+func (rule *IngestFilterRule) Validate() error {
+	if !rule.Action.IsValid() {
+		return e.Errorf("invalid action %s", rule.Action)
+	}
+	isSet := func(s *string) bool { return s != nil && *s != "" }
+	if !isSet(rule.ServiceName) && !isSet(rule.Severity) && !isSet(rule.AttributeKey) {
+		return e.New("one of service_name, severity or attribute_key must be set")
+	}
+	if isSet(rule.AttributeRegex) {
+		if !isSet(rule.AttributeKey) {
+			return e.New("attribute_key must be set with attribute_regex")
+		}
+		if _, err := regexp.Compile(*rule.AttributeRegex); err != nil {
+			return e.Wrap(err, "invalid attribute_regex")
+		}
+	}
+	return nil
+}
+
+// ErrorIgnoreRule drops the errors of a project that match all of its set regexes when they are
+// ingested, before they count against the billing quota or alert, such as errors of browser
+// extensions or bots.
+type ErrorIgnoreRule struct {
+	Model
+	ProjectID int    `gorm:"index;not null;"`
+	Name      string `gorm:"not null"`
+	// Matches the exception type of the error message, such as `TypeError`, or the error type, such
+	// as `console.error`.
+	TypeRegex    *string
+	MessageRegex *string
+	BrowserRegex *string
+	// Matches the URL of the page of frontend errors.
+	URLRegex *string
+	// Matches the source of the error, such as the file of frontend errors.
+	SourceRegex       *string
+	Disabled          bool `gorm:"default:false"`
+	LastAdminToEditID int
+}
+
+func (rule *ErrorIgnoreRule) regexes() map[string]*string {
+	return map[string]*string{
+		"type_regex":    rule.TypeRegex,
+		"message_regex": rule.MessageRegex,
+		"browser_regex": rule.BrowserRegex,
+		"url_regex":     rule.URLRegex,
+		"source_regex":  rule.SourceRegex,
+	}
+}
+
+func (rule *ErrorIgnoreRule) Validate() error {
+	isSet := false
+	for name, regex := range rule.regexes() {
+		if regex == nil || *regex == "" {
+			continue
+		}
+		isSet = true
+		if _, err := regexp.Compile(*regex); err != nil {
+			return e.Wrapf(err, "invalid %s", name)
+		}
+	}
+	if !isSet {
+		return e.New("one of type_regex, message_regex, browser_regex, url_regex or source_regex must be set")
+	}
+	return nil
+}
+
+// SourcemapArtifact is a minified file or sourcemap uploaded for a release version of a project,
+// which is stored in the sourcemaps bucket at its path. Errors of the version are only enhanced
+// with uploaded artifacts, rather than files fetched from their public URLs.
+type SourcemapArtifact struct {
+	Model
+	ProjectID int `gorm:"not null;uniqueIndex:idx_sourcemap_artifacts_path"`
+	// Version is empty for artifacts that are not of a release version.
+	Version string `gorm:"not null;uniqueIndex:idx_sourcemap_artifacts_path"`
+	// Path is relative to the root that the files are served from, such as `assets/index.js.map`.
+	Path   string `gorm:"not null;uniqueIndex:idx_sourcemap_artifacts_path"`
+	Size   int64
+	SHA256 string `gorm:"column:sha256"`
+	// UploadedByAdminID is the admin of the API token that uploaded the artifact.
+	UploadedByAdminID int
+}
+
+type SourcemapBucketProvider string
+
+const (
+	SourcemapBucketProviderS3  SourcemapBucketProvider = "s3"
+	SourcemapBucketProviderGCS SourcemapBucketProvider = "gcs"
+)
+
+// SourcemapBucketVersionPlaceholder is replaced by the release version in the prefix of a bucket.
+const SourcemapBucketVersionPlaceholder = "{version}"
+
+// SourcemapBucket is a private bucket of a customer that the minified files and sourcemaps of a
+// project are fetched from to enhance stack traces, instead of from their public URLs. S3 buckets
+// are read by assuming a role of the customer, and GCS buckets with a service account key.
+type SourcemapBucket struct {
+	Model
+	ProjectID int                     `gorm:"uniqueIndex;not null"`
+	Provider  SourcemapBucketProvider `gorm:"not null"`
+	Bucket    string                  `gorm:"not null"`
+	// Prefix is joined with the paths of the URLs of files to get their keys, with the
+	// SourcemapBucketVersionPlaceholder replaced by the release version, eg. `releases/{version}`.
+	Prefix  string
+	Region  *string
+	RoleARN *string
+	// ExternalID is generated for the project, and is the sts:ExternalId that the trust policy of
+	// the role needs to require.
+	ExternalID        string
+	ServiceAccountKey *string `json:"-"`
+	Disabled          bool    `gorm:"default:false"`
+	LastAdminToEditID int
+}
+
+func (bucket *SourcemapBucket) Validate() error {
+	if bucket.Bucket == "" {
+		return e.New("bucket is required")
+	}
+	switch bucket.Provider {
+	case SourcemapBucketProviderS3:
+		if bucket.RoleARN == nil || !strings.HasPrefix(*bucket.RoleARN, "arn:aws:iam::") {
+			return e.New("s3 buckets need the arn of a role to assume")
+		}
+		if bucket.Region == nil || *bucket.Region == "" {
+			return e.New("s3 buckets need a region")
+		}
+	case SourcemapBucketProviderGCS:
+		if bucket.ServiceAccountKey == nil || !json.Valid([]byte(*bucket.ServiceAccountKey)) {
+			return e.New("gcs buckets need a json service account key")
+		}
+	default:
+		return e.Errorf("invalid provider %q", bucket.Provider)
+	}
+	return nil
+}
+
+// Key returns the key of the file at a path, or false when the prefix needs a version that the
+// file does not have.
+func (bucket *SourcemapBucket) Key(version *string, filePath string) (string, bool) {
+	prefix := bucket.Prefix
+	if strings.Contains(prefix, SourcemapBucketVersionPlaceholder) {
+		if version == nil || *version == "" {
+			return "", false
+		}
+		prefix = strings.ReplaceAll(prefix, SourcemapBucketVersionPlaceholder, *version)
+	}
+	return strings.TrimPrefix(path.Join(prefix, filePath), "/"), true
+}
+
+// Release is a service version of a project. Releases are recorded when telemetry of the version is
+// first received, and by the deploy notifications that CI pipelines send through the REST API.
+type Release struct {
+	Model
+	ProjectID   int    `json:"project_id" gorm:"not null;uniqueIndex:idx_releases_project_service_version"`
+	ServiceName string `json:"service_name" gorm:"not null;uniqueIndex:idx_releases_project_service_version"`
+	Version     string `json:"version" gorm:"not null;uniqueIndex:idx_releases_project_service_version"`
+	// FirstSeenAt is when telemetry of the version was first received, and is unset for versions that
+	// were deployed without reporting any yet.
+	FirstSeenAt *time.Time `json:"first_seen_at"`
+	// DeployedAt is when the version was last deployed.
+	DeployedAt  *time.Time `json:"deployed_at"`
+	Environment *string    `json:"environment"`
+	CommitSHA   *string    `json:"commit_sha"`
+	URL         *string    `json:"url"`
+}
+
+// SuspectCommit is the commit that most likely introduced an error group, among the commits since
+// the previous release of its service that changed the files of its stacktrace.
+type SuspectCommit struct {
+	SHA         string `json:"sha"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	URL         string `json:"url"`
+	// Files are the files of the stacktrace that the commit changed.
+	Files []string `json:"files"`
+}
+
+// String formats the commit as its short hash, message and author.
+func (sc *SuspectCommit) String() string {
+	sha := sc.SHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	if sc.AuthorName == "" {
+		return fmt.Sprintf("%s %s", sha, sc.Message)
+	}
+	return fmt.Sprintf("%s %s (%s)", sha, sc.Message, sc.AuthorName)
+}
+
+func (sc *SuspectCommit) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), sc)
+	case []byte:
+		return json.Unmarshal(v, sc)
+	}
+	return nil
+}
+
+func (sc SuspectCommit) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(sc)
+	return string(bytes), err
+}
+
 type ErrorGroupAdminsView struct {
 	ErrorGroupID int       `gorm:"primaryKey"`
 	AdminID      int       `gorm:"primaryKey"`
@@ -1151,10 +2184,12 @@ var Fingerprint = struct {
 	StackFrameCode     FingerprintType
 	StackFrameMetadata FingerprintType
 	JsonResult         FingerprintType
+	GroupingRule       FingerprintType
 }{
 	StackFrameCode:     "CODE",
 	StackFrameMetadata: "META",
 	JsonResult:         "JSON",
+	GroupingRule:       "RULE",
 }
 
 type ErrorFingerprint struct {
@@ -1179,6 +2214,25 @@ type ExternalAttachment struct {
 	Removed bool `gorm:"default:false"`
 }
 
+// ExternalIssue links an error group to at most one issue per issue tracker, so that
+// issues created manually and by automation are not duplicated.
+// An issue without an ExternalID is still being created in the tracker.
+type ExternalIssue struct {
+	Model
+	ProjectID       int                         `gorm:"index;not null;"`
+	ErrorGroupID    int                         `gorm:"uniqueIndex:idx_external_issue_error_group_integration;not null;"`
+	IntegrationType modelInputs.IntegrationType `gorm:"uniqueIndex:idx_external_issue_error_group_integration;not null;"`
+	ExternalID      string
+	Title           string
+	// The attachment of the comment the issue was created from
+	ExternalAttachmentID *int
+	// The admin who created or linked the issue, unset when created by automation
+	AdminID *int
+	// The status of the issue in the tracker, kept up to date by the trackers that notify status changes
+	Status string
+	Closed bool
+}
+
 type SessionCommentTag struct {
 	Model
 	SessionComments []SessionComment `json:"session_comments" gorm:"many2many:session_tags;"`
@@ -1790,6 +2844,23 @@ func (j *JSONB) Scan(value interface{}) error {
 	return nil
 }
 
+type StringMap map[string]string
+
+func (m StringMap) Value() (driver.Value, error) {
+	valueString, err := json.Marshal(m)
+	return string(valueString), err
+}
+
+func (m *StringMap) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &m)
+	case []byte:
+		return json.Unmarshal(v, &m)
+	}
+	return nil
+}
+
 // Vector is serialized as '[-0.0123,0.456]' aka like a json list
 type Vector []float32
 
@@ -1995,7 +3066,15 @@ type ErrorAlert struct {
 	Model
 	Alert
 	RegexGroups *string
+	// AnomalyDetection alerts on spikes of an error group above its baseline rather than whenever
+	// the CountThreshold is reached, which then is the least number of errors of a spike.
+	AnomalyDetection   bool    `gorm:"default:false"`
+	AnomalySensitivity float64 `gorm:"default:3"` // standard deviations above the baseline of a spike
+	EscalationPolicyID *int
 	AlertIntegrations
+	PagerDutyDestinations    PagerDutyDestinations    `gorm:"type:jsonb;default:'[]'" json:"pagerduty_destinations"`
+	OpsgenieDestinations     OpsgenieDestinations     `gorm:"type:jsonb;default:'[]'" json:"opsgenie_destinations"`
+	SplunkOnCallDestinations SplunkOnCallDestinations `gorm:"type:jsonb;default:'[]'" json:"splunk_on_call_destinations"`
 }
 
 type ErrorAlertEvent struct {
@@ -2005,6 +3084,17 @@ type ErrorAlertEvent struct {
 	SentAt        time.Time
 }
 
+// ErrorGroupBaseline is the baseline hourly count of an error group for an hour of the week,
+// as an exponentially weighted mean and variance of the counts of that hour in past weeks.
+type ErrorGroupBaseline struct {
+	ErrorGroupID int `gorm:"primaryKey"`
+	HourOfWeek   int `gorm:"primaryKey"`
+	Mean         float64
+	Variance     float64
+	Samples      int
+	UpdatedAt    time.Time
+}
+
 func SendBillingNotifications(ctx context.Context, db *gorm.DB, mailClient *sendgrid.Client, emailType Email.EmailType, workspace *Workspace) error {
 	// Skip sending email if sending was attempted within the cache TTL
 	cacheKey := fmt.Sprintf("%s;%d", emailType, workspace.ID)
@@ -2081,10 +3171,13 @@ type SessionAlertEvent struct {
 
 type Service struct {
 	Model
-	ProjectID          int                       `gorm:"not null;uniqueIndex:idx_project_id_name"`
-	Name               string                    `gorm:"not null;uniqueIndex:idx_project_id_name"`
-	Status             modelInputs.ServiceStatus `gorm:"not null;default:created"`
-	GithubRepoPath     *string
+	ProjectID      int                       `gorm:"not null;uniqueIndex:idx_project_id_name"`
+	Name           string                    `gorm:"not null;uniqueIndex:idx_project_id_name"`
+	Status         modelInputs.ServiceStatus `gorm:"not null;default:created"`
+	GithubRepoPath *string
+	// GitlabRepoPath is the path of the gitlab project used to enhance stacktraces when the
+	// service has no github repository. GithubPrefix is the prefix of files in either repository.
+	GitlabRepoPath     *string
 	BuildPrefix        *string
 	GithubPrefix       *string
 	ErrorDetails       pq.StringArray `gorm:"type:text[]"`
@@ -2093,11 +3186,33 @@ type Service struct {
 	ProcessDescription *string
 }
 
+// ProjectSDK is an otel SDK version that a project has sent data from, fingerprinted by the
+// telemetry.sdk.name and telemetry.sdk.version resource attributes.
+type ProjectSDK struct {
+	Model
+	ProjectID  int       `gorm:"not null;uniqueIndex:idx_project_sdk_name_version"`
+	Name       string    `gorm:"not null;uniqueIndex:idx_project_sdk_name_version"`
+	Version    string    `gorm:"not null;uniqueIndex:idx_project_sdk_name_version"`
+	LastSeenAt time.Time `gorm:"not null"`
+}
+
+// AlertState is the state of an alert that notifies once when it starts firing and once when it
+// resolves, rather than on every evaluation.
+type AlertState = string
+
+const (
+	AlertStateNormal   AlertState = "NORMAL"
+	AlertStateAlerting AlertState = "ALERTING"
+)
+
 type LogAlert struct {
 	Model
 	Alert
-	Query          string
-	BelowThreshold bool
+	Query              string
+	BelowThreshold     bool       // alerts when the count is at most the threshold, or on the absence of logs with a threshold of 0
+	State              AlertState `gorm:"default:NORMAL"`
+	StateChangedAt     *time.Time
+	EscalationPolicyID *int
 	AlertIntegrations
 }
 
@@ -2261,6 +3376,51 @@ func (obj *MetricMonitor) GetId() int {
 	return obj.ID
 }
 
+func (obj *HeartbeatMonitor) GetChannelsToNotify() ([]*modelInputs.SanitizedSlackChannel, error) {
+	if obj == nil {
+		return nil, e.New("empty heartbeat monitor object for channels to notify")
+	}
+	channelString := "[]"
+	if obj.ChannelsToNotify != nil {
+		channelString = *obj.ChannelsToNotify
+	}
+	var sanitizedChannels []*modelInputs.SanitizedSlackChannel
+	if err := json.Unmarshal([]byte(channelString), &sanitizedChannels); err != nil {
+		return nil, e.Wrap(err, "error unmarshalling sanitized slack channels")
+	}
+	return sanitizedChannels, nil
+}
+
+func (obj *TraceAlert) GetChannelsToNotify() ([]*modelInputs.SanitizedSlackChannel, error) {
+	if obj == nil {
+		return nil, e.New("empty trace alert object for channels to notify")
+	}
+	channelString := "[]"
+	if obj.ChannelsToNotify != nil {
+		channelString = *obj.ChannelsToNotify
+	}
+	var sanitizedChannels []*modelInputs.SanitizedSlackChannel
+	if err := json.Unmarshal([]byte(channelString), &sanitizedChannels); err != nil {
+		return nil, e.Wrap(err, "error unmarshalling sanitized slack channels")
+	}
+	return sanitizedChannels, nil
+}
+
+func (obj *UptimeMonitor) GetChannelsToNotify() ([]*modelInputs.SanitizedSlackChannel, error) {
+	if obj == nil {
+		return nil, e.New("empty uptime monitor object for channels to notify")
+	}
+	channelString := "[]"
+	if obj.ChannelsToNotify != nil {
+		channelString = *obj.ChannelsToNotify
+	}
+	var sanitizedChannels []*modelInputs.SanitizedSlackChannel
+	if err := json.Unmarshal([]byte(channelString), &sanitizedChannels); err != nil {
+		return nil, e.Wrap(err, "error unmarshalling sanitized slack channels")
+	}
+	return sanitizedChannels, nil
+}
+
 func (obj *SessionAlert) GetTrackProperties() ([]*TrackProperty, error) {
 	if obj == nil {
 		return nil, e.New("empty session alert object for track properties")
@@ -2455,4 +3615,7 @@ type TableConfig[TReservedKey ~string] struct {
 	ReservedKeys     []TReservedKey
 	SelectColumns    []string
 	DefaultFilters   map[string]string
+	// ValueNormalizers rewrite the values searched for in a reserved key column
+	// to the form in which they are stored.
+	ValueNormalizers map[TReservedKey]func(string) string
 }
@@ -1,11 +1,287 @@
 package model
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_FromVerboseID(t *testing.T) {
 	id, _ := FromVerboseID("1jdkoe52")
 	assert.Equal(t, 1, id)
 }
+
+func TestHeartbeatMonitor_GetAlertReason(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+	ok, failed := HeartbeatCheckInStatusOK, HeartbeatCheckInStatusError
+
+	monitor := HeartbeatMonitor{
+		Model:           Model{CreatedAt: now.Add(-time.Hour)},
+		IntervalSeconds: 600,
+		GraceSeconds:    60,
+	}
+	assert.NotEmpty(t, monitor.GetAlertReason(now), "never checked in since creation")
+
+	monitor.LastCheckInAt = ago(5 * time.Minute)
+	monitor.LastCheckInStatus = &ok
+	assert.Empty(t, monitor.GetAlertReason(now))
+
+	monitor.LastCheckInAt = ago(20 * time.Minute)
+	assert.NotEmpty(t, monitor.GetAlertReason(now))
+
+	monitor.LastAlertSentAt = ago(time.Minute)
+	assert.Empty(t, monitor.GetAlertReason(now), "already alerted for this missed check-in")
+
+	monitor.LastCheckInAt = ago(0)
+	monitor.LastCheckInStatus = &failed
+	assert.NotEmpty(t, monitor.GetAlertReason(now))
+
+	monitor.LastAlertSentAt = ago(0)
+	assert.Empty(t, monitor.GetAlertReason(now))
+}
+
+func TestTraceAlert(t *testing.T) {
+	alert := TraceAlert{
+		ServiceName: "checkout",
+		SpanName:    "POST /orders",
+		Query:       "environment:production",
+		Metric:      TraceAlertMetricLatency,
+		Aggregator:  modelInputs.MetricAggregatorP95,
+	}
+	assert.Equal(t, `service_name:"checkout" span_name:"POST /orders" environment:production`, alert.GetQuery())
+	assert.Equal(t, "P95 latency of checkout POST /orders", alert.Describe())
+	assert.Equal(t, 750., alert.GetValue(10, 1, 750*time.Millisecond))
+	assert.Equal(t, "750ms", alert.FormatValue(750))
+
+	alert.Metric = TraceAlertMetricErrorRate
+	alert.SpanName = ""
+	assert.Equal(t, "error rate of checkout", alert.Describe())
+	assert.Equal(t, 2.5, alert.GetValue(40, 1, time.Second))
+	assert.Equal(t, 0., alert.GetValue(0, 0, 0))
+	assert.Equal(t, "2.50%", alert.FormatValue(2.5))
+}
+
+func TestOnCallScheduleGetOnCall(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	schedule := OnCallSchedule{
+		Participants:  []string{"ada@example.com", "grace@example.com"},
+		RotationHours: 24,
+		StartsAt:      start,
+	}
+	assert.Equal(t, "", schedule.GetOnCall(start.Add(-time.Minute)))
+	assert.Equal(t, "ada@example.com", schedule.GetOnCall(start))
+	assert.Equal(t, "ada@example.com", schedule.GetOnCall(start.Add(23*time.Hour)))
+	assert.Equal(t, "grace@example.com", schedule.GetOnCall(start.Add(24*time.Hour)))
+	assert.Equal(t, "ada@example.com", schedule.GetOnCall(start.Add(48*time.Hour)))
+
+	schedule.Participants = nil
+	assert.Equal(t, "", schedule.GetOnCall(start))
+}
+
+func TestProjectDigestSetting(t *testing.T) {
+	// a wednesday
+	now := time.Date(2024, 1, 3, 10, 30, 0, 0, time.UTC)
+
+	weekly := ProjectDigestSetting{Frequency: DigestFrequencyWeekly, Weekday: time.Monday, Hour: 9}
+	assert.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), weekly.GetScheduledAt(now))
+	weekly.Weekday = time.Friday
+	assert.Equal(t, time.Date(2023, 12, 29, 9, 0, 0, 0, time.UTC), weekly.GetScheduledAt(now))
+	weekly.Weekday = time.Wednesday
+	assert.Equal(t, time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC), weekly.GetScheduledAt(now))
+
+	daily := ProjectDigestSetting{Frequency: DigestFrequencyDaily, Hour: 12}
+	assert.Equal(t, time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), daily.GetScheduledAt(now))
+	assert.True(t, daily.IsDue(now))
+	sentAt := time.Date(2024, 1, 2, 12, 5, 0, 0, time.UTC)
+	daily.LastSentAt = &sentAt
+	assert.False(t, daily.IsDue(now))
+	assert.True(t, daily.IsDue(now.Add(2*time.Hour)))
+
+	daily.Frequency = DigestFrequencyNever
+	assert.False(t, daily.IsDue(now.Add(48*time.Hour)))
+}
+
+func TestErrorWorkflowRule(t *testing.T) {
+	regex := `^TypeError: .* is undefined$`
+	rule := ErrorWorkflowRule{Action: ErrorWorkflowRuleActionIgnore, EventRegex: &regex}
+	assert.NoError(t, rule.Validate())
+	assert.True(t, rule.MatchesEvent("TypeError: foo is undefined"))
+	assert.False(t, rule.MatchesEvent("ReferenceError: foo is not defined"))
+
+	rule.EventRegex = nil
+	assert.True(t, rule.MatchesEvent("anything"))
+
+	rule.Action = ErrorWorkflowRuleActionResolve
+	assert.Error(t, rule.Validate(), "resolve rules require stale_days")
+	days := 14
+	rule.StaleDays = &days
+	assert.NoError(t, rule.Validate())
+
+	invalid := `(unclosed`
+	rule.EventRegex = &invalid
+	assert.Error(t, rule.Validate())
+	assert.False(t, rule.MatchesEvent("(unclosed"))
+
+	rule.Action = "delete"
+	assert.Error(t, rule.Validate())
+}
+
+func TestErrorGroupIsRegression(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		cmp  int
+		ok   bool
+	}{
+		{"1.2.10", "1.2.9", 1, true},
+		{"v1.2", "1.2.0", 0, true},
+		{"1.2.0-rc.1", "1.3.0", -1, true},
+		{"2.0.0+build.5", "10.0.0", -1, true},
+		{"a1b2c3d", "1.0.0", 0, false},
+		{"", "1.0.0", 0, false},
+	} {
+		cmp, ok := CompareServiceVersions(tc.a, tc.b)
+		assert.Equal(t, tc.ok, ok, "%s %s", tc.a, tc.b)
+		assert.Equal(t, tc.cmp, cmp, "%s %s", tc.a, tc.b)
+	}
+
+	now := time.Now()
+	errorGroup := ErrorGroup{State: modelInputs.ErrorStateResolved}
+	assert.Equal(t, ErrorResolutionResolved, errorGroup.GetResolutionState(now))
+	assert.True(t, errorGroup.IsRegression(""))
+
+	version := "1.4.0"
+	errorGroup.ResolvedVersion = &version
+	assert.Equal(t, ErrorResolutionResolvedInVersion, errorGroup.GetResolutionState(now))
+	assert.False(t, errorGroup.IsRegression(""))
+	assert.False(t, errorGroup.IsRegression("1.3.9"))
+	assert.True(t, errorGroup.IsRegression("1.4.0"))
+	assert.True(t, errorGroup.IsRegression("v1.5.0"))
+
+	commit := "a1b2c3d"
+	errorGroup.ResolvedVersion = &commit
+	assert.False(t, errorGroup.IsRegression("a1b2c3d"))
+	assert.True(t, errorGroup.IsRegression("e4f5a6b"))
+
+	snoozedUntil := now.Add(time.Hour)
+	errorGroup = ErrorGroup{State: modelInputs.ErrorStateOpen, SnoozedUntil: &snoozedUntil}
+	assert.Equal(t, ErrorResolutionSnoozed, errorGroup.GetResolutionState(now))
+	assert.Equal(t, ErrorResolutionOpen, errorGroup.GetResolutionState(now.Add(2*time.Hour)))
+	assert.False(t, errorGroup.IsRegression("1.0.0"))
+}
+
+func TestErrorOwnershipRule(t *testing.T) {
+	for _, tc := range []struct {
+		pattern  string
+		fileName string
+		matches  bool
+	}{
+		{"*.go", "/app/backend/main.go", true},
+		{"*.go", "/app/backend/main.ts", false},
+		{"src/api/", "/app/src/api/handlers/user.ts", true},
+		{"src/api/", "/app/src/apis/user.ts", false},
+		{"/src/api", "src/api/user.ts", true},
+		{"docs/**/*.md", "docs/a/b/readme.md", true},
+		{"docs/**/*.md", "docs/readme.md", true},
+		{"docs/*.md", "docs/a/readme.md", false},
+		{"static/js/", "https://app.example.com/static/js/main.3f2a.js", true},
+		{"app.example.com", "https://app.example.com/static/js/main.js", false},
+	} {
+		rule := ErrorOwnershipRule{Pattern: &tc.pattern, AssigneeID: 1}
+		assert.NoError(t, rule.Validate())
+		assert.Equal(t, tc.matches, rule.MatchesFile(tc.fileName), "%s %s", tc.pattern, tc.fileName)
+	}
+
+	goFiles, apiDir, service := "*.go", "backend/api/", "worker"
+	rules := []*ErrorOwnershipRule{
+		{Model: Model{ID: 1}, Pattern: &goFiles, AssigneeID: 1},
+		{Model: Model{ID: 2}, Pattern: &apiDir, AssigneeID: 2},
+		{Model: Model{ID: 3}, ServiceName: &service, AssigneeID: 3},
+	}
+	// the last matching rule of the top frame wins
+	assert.Equal(t, 2, FindErrorOwnershipRule(rules, "api", []string{"/backend/api/user.go", "/backend/main.go"}).ID)
+	assert.Equal(t, 1, FindErrorOwnershipRule(rules, "api", []string{"/backend/main.go", "/backend/api/user.go"}).ID)
+	assert.Equal(t, 1, FindErrorOwnershipRule(rules, "api", []string{"/vendor/lib.c", "/backend/main.go"}).ID)
+	// service rules own the errors without an owned frame
+	assert.Equal(t, 3, FindErrorOwnershipRule(rules, "worker", []string{"/vendor/lib.c"}).ID)
+	assert.Nil(t, FindErrorOwnershipRule(rules, "api", []string{"/vendor/lib.c"}))
+
+	assert.Error(t, (&ErrorOwnershipRule{AssigneeID: 1}).Validate())
+	assert.Error(t, (&ErrorOwnershipRule{Pattern: &goFiles}).Validate())
+}
+
+func TestErrorGroupSeenInVersion(t *testing.T) {
+	errorGroup := ErrorGroup{}
+	assert.False(t, errorGroup.SeenInVersion(""))
+	assert.Nil(t, errorGroup.FirstSeenVersion)
+
+	assert.True(t, errorGroup.SeenInVersion("1.2.0"))
+	assert.Equal(t, "1.2.0", *errorGroup.FirstSeenVersion)
+	assert.Equal(t, "1.2.0", *errorGroup.LastSeenVersion)
+	assert.False(t, errorGroup.SeenInVersion("1.2.0"))
+
+	assert.True(t, errorGroup.SeenInVersion("1.3.0"))
+	assert.Equal(t, "1.2.0", *errorGroup.FirstSeenVersion)
+	assert.Equal(t, "1.3.0", *errorGroup.LastSeenVersion)
+
+	// an older version that is still running does not move the last seen version back
+	assert.True(t, errorGroup.SeenInVersion("1.1.0"))
+	assert.Equal(t, "1.1.0", *errorGroup.FirstSeenVersion)
+	assert.Equal(t, "1.3.0", *errorGroup.LastSeenVersion)
+
+	assert.True(t, errorGroup.SeenInVersion("a1b2c3d"))
+	assert.Equal(t, "1.1.0", *errorGroup.FirstSeenVersion)
+	assert.Equal(t, "a1b2c3d", *errorGroup.LastSeenVersion)
+}
+
+func TestSourcemapBucket(t *testing.T) {
+	version := "1.2.0"
+	bucket := &SourcemapBucket{Provider: SourcemapBucketProviderS3, Bucket: "builds", Prefix: "releases/{version}/"}
+	key, ok := bucket.Key(&version, "/assets/index.js.map")
+	assert.True(t, ok)
+	assert.Equal(t, "releases/1.2.0/assets/index.js.map", key)
+	_, ok = bucket.Key(nil, "/assets/index.js.map")
+	assert.False(t, ok)
+
+	bucket.Prefix = ""
+	key, ok = bucket.Key(nil, "/assets/index.js.map")
+	assert.True(t, ok)
+	assert.Equal(t, "assets/index.js.map", key)
+
+	assert.Error(t, bucket.Validate())
+	roleARN, region := "arn:aws:iam::123456789012:role/highlight", "us-west-2"
+	bucket.RoleARN, bucket.Region = &roleARN, &region
+	assert.NoError(t, bucket.Validate())
+
+	serviceAccountKey := "not json"
+	assert.Error(t, (&SourcemapBucket{Provider: SourcemapBucketProviderGCS, Bucket: "builds", ServiceAccountKey: &serviceAccountKey}).Validate())
+}
+
+func TestParseSlackErrorGroupActionValue(t *testing.T) {
+	projectID, secureID, err := ParseSlackErrorGroupActionValue(SlackErrorGroupActionValue(12, "abc123"))
+	assert.NoError(t, err)
+	assert.Equal(t, 12, projectID)
+	assert.Equal(t, "abc123", secureID)
+
+	for _, value := range []string{"", "12", "12/", "x/abc123"} {
+		_, _, err := ParseSlackErrorGroupActionValue(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSuspectCommitString(t *testing.T) {
+	commit := &SuspectCommit{SHA: "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678", Message: "Load users lazily", AuthorName: "Jane Doe"}
+	assert.Equal(t, "a1b2c3d Load users lazily (Jane Doe)", commit.String())
+
+	commit.AuthorName = ""
+	assert.Equal(t, "a1b2c3d Load users lazily", commit.String())
+
+	var scanned SuspectCommit
+	assert.NoError(t, scanned.Scan([]byte(`{"sha":"a1b2c3d","files":["src/db/client.ts"]}`)))
+	assert.Equal(t, []string{"src/db/client.ts"}, scanned.Files)
+}
@@ -30,6 +30,54 @@ func (dc DiscordChannels) Value() (driver.Value, error) {
 	return string(bytes), err
 }
 
+// MicrosoftTeamsChannel is a Teams channel that alerts are posted to through the url of one of its
+// incoming webhooks.
+type MicrosoftTeamsChannel struct {
+	Name       string
+	WebhookURL string
+}
+
+type MicrosoftTeamsChannels []*MicrosoftTeamsChannel
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *MicrosoftTeamsChannels) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc MicrosoftTeamsChannels) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
+// DiscordWebhook is a Discord channel webhook that alerts and digests are posted to without the
+// Highlight Discord bot being added to the server.
+type DiscordWebhook struct {
+	Name       string
+	WebhookURL string
+}
+
+type DiscordWebhooks []*DiscordWebhook
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *DiscordWebhooks) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc DiscordWebhooks) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
 type WebhookDestination struct {
 	URL           string
 	Authorization *string
@@ -52,7 +100,97 @@ func (dc WebhookDestinations) Value() (driver.Value, error) {
 	return string(bytes), err
 }
 
+// WebhookDelivery is the log of the delivery of an alert to a webhook destination, including its retries.
+type WebhookDelivery struct {
+	Model
+	ProjectID   int     `gorm:"index" json:"project_id"`
+	DeliveryID  string  `json:"delivery_id"`
+	URL         string  `json:"url"`
+	EventType   string  `json:"event_type"`
+	RequestBody string  `json:"request_body"`
+	StatusCode  *int    `json:"status_code"`
+	Error       *string `json:"error"`
+	Attempts    int     `json:"attempts"`
+	DurationMs  int64   `json:"duration_ms"`
+}
+
+// PagerDutyDestination is the integration of a PagerDuty service that an alert pages, with the
+// severity of the events it is sent.
+type PagerDutyDestination struct {
+	RoutingKey string
+	Severity   string
+}
+
+type PagerDutyDestinations []*PagerDutyDestination
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *PagerDutyDestinations) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc PagerDutyDestinations) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
+// OpsgenieDestination is the API integration of an Opsgenie team that an alert creates alerts in,
+// with the severity that sets the priority of the alerts.
+type OpsgenieDestination struct {
+	APIKey   string
+	Region   string
+	Severity string
+}
+
+type OpsgenieDestinations []*OpsgenieDestination
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *OpsgenieDestinations) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc OpsgenieDestinations) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
+// SplunkOnCallDestination is the REST endpoint integration of a Splunk On-Call organization and the
+// routing key of the team that an alert pages, with the severity that sets the message type.
+type SplunkOnCallDestination struct {
+	APIKey     string
+	RoutingKey string
+	Severity   string
+}
+
+type SplunkOnCallDestinations []*SplunkOnCallDestination
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *SplunkOnCallDestinations) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc SplunkOnCallDestinations) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
 type AlertIntegrations struct {
-	DiscordChannelsToNotify DiscordChannels     `gorm:"type:jsonb;default:'[]'" json:"discord_channels_to_notify"`
-	WebhookDestinations     WebhookDestinations `gorm:"type:jsonb;default:'[]'" json:"webhook_destinations"`
+	DiscordChannelsToNotify        DiscordChannels        `gorm:"type:jsonb;default:'[]'" json:"discord_channels_to_notify"`
+	DiscordWebhooksToNotify        DiscordWebhooks        `gorm:"type:jsonb;default:'[]'" json:"discord_webhooks_to_notify"`
+	MicrosoftTeamsChannelsToNotify MicrosoftTeamsChannels `gorm:"type:jsonb;default:'[]'" json:"microsoft_teams_channels_to_notify"`
+	WebhookDestinations            WebhookDestinations    `gorm:"type:jsonb;default:'[]'" json:"webhook_destinations"`
 }
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The action ids of the buttons of Slack error alerts, handled by the Slack interactions webhook.
+const (
+	SlackActionResolveErrorGroup = "error_group_resolve"
+	SlackActionIgnoreErrorGroup  = "error_group_ignore"
+	SlackActionSnoozeErrorGroup  = "error_group_snooze"
+	SlackActionCreateErrorTicket = "error_group_create_ticket"
+)
+
+// SlackErrorGroupActionValue is the value of the buttons of a Slack error alert, identifying its error group.
+func SlackErrorGroupActionValue(projectID int, errorGroupSecureID string) string {
+	return strconv.Itoa(projectID) + "/" + errorGroupSecureID
+}
+
+// ParseSlackErrorGroupActionValue returns the project id and error group secure id of a button value.
+func ParseSlackErrorGroupActionValue(value string) (int, string, error) {
+	project, secureID, ok := strings.Cut(value, "/")
+	if !ok || secureID == "" {
+		return 0, "", errors.Errorf("invalid error group action value %q", value)
+	}
+	projectID, err := strconv.Atoi(project)
+	if err != nil {
+		return 0, "", errors.Errorf("invalid error group action value %q", value)
+	}
+	return projectID, secureID, nil
+}
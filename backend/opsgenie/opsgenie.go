@@ -0,0 +1,183 @@
+package opsgenie
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	OpsgenieApiBaseUrl   = "https://api.opsgenie.com"
+	OpsgenieEUApiBaseUrl = "https://api.eu.opsgenie.com"
+)
+
+const requestTimeout = 10 * time.Second
+
+// maxMessageLength and maxDescriptionLength are the longest message and description of an alert
+// accepted by Opsgenie.
+const (
+	maxMessageLength     = 130
+	maxDescriptionLength = 15000
+)
+
+// source is the source of the alerts, shown on the Opsgenie alerts.
+const source = "highlight.io"
+
+// Region is the region of the Opsgenie account, which is served by a separate api.
+type Region string
+
+const (
+	RegionUS Region = "us"
+	RegionEU Region = "eu"
+)
+
+func (r Region) IsValid() bool {
+	return r == "" || r == RegionUS || r == RegionEU
+}
+
+// Priority is the priority of an alert, from P1 (critical) to P5 (informational).
+type Priority string
+
+const (
+	PriorityP1 Priority = "P1"
+	PriorityP2 Priority = "P2"
+	PriorityP3 Priority = "P3"
+	PriorityP4 Priority = "P4"
+	PriorityP5 Priority = "P5"
+)
+
+// PriorityForSeverity maps the severity of a Highlight alert destination, which is one of
+// `critical`, `error`, `warning` or `info`, to the priority of an Opsgenie alert.
+func PriorityForSeverity(severity string) Priority {
+	switch severity {
+	case "critical":
+		return PriorityP1
+	case "warning":
+		return PriorityP3
+	case "info":
+		return PriorityP5
+	default:
+		return PriorityP2
+	}
+}
+
+// ErrorGroupAlias returns the alias of the alerts of an error group, so that all the alerts of an
+// error group are deduplicated into a single Opsgenie alert which is closed with the error group.
+func ErrorGroupAlias(errorGroupID int) string {
+	return fmt.Sprintf("highlight-error-group-%d", errorGroupID)
+}
+
+// Client calls the alert api of Opsgenie with the api key of an API integration of a team.
+type Client struct {
+	apiKey     string
+	baseUrl    string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, region Region) *Client {
+	baseUrl := OpsgenieApiBaseUrl
+	if region == RegionEU {
+		baseUrl = OpsgenieEUApiBaseUrl
+	}
+	return &Client{apiKey: apiKey, baseUrl: baseUrl, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type Response struct {
+	Result    string `json:"result"`
+	RequestID string `json:"requestId"`
+}
+
+func (c *Client) doRequest(ctx context.Context, path string, query url.Values, input any) (*Response, error) {
+	if c.apiKey == "" {
+		return nil, errors.New("Opsgenie api key is not set")
+	}
+	b, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	u := c.baseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request to Opsgenie")
+	}
+	req.Header.Set("Authorization", "GenieKey "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting response from Opsgenie endpoint")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body from Opsgenie endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, errors.New("Opsgenie API responded with error; status_code=" + res.Status + "; body=" + string(body))
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Opsgenie response")
+	}
+	return &response, nil
+}
+
+type CreateAlertInput struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Priority    Priority          `json:"priority,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+// CreateAlert creates an alert, or increments the count of the open alert of the alias.
+// Opsgenie processes requests asynchronously, so a response only means the request was accepted.
+func (c *Client) CreateAlert(ctx context.Context, input *CreateAlertInput) (*Response, error) {
+	input.Message = truncate(input.Message, maxMessageLength)
+	input.Description = truncate(input.Description, maxDescriptionLength)
+	if input.Source == "" {
+		input.Source = source
+	}
+	return c.doRequest(ctx, "/v2/alerts", nil, input)
+}
+
+// AcknowledgeAlert acknowledges the open alert of the alias.
+func (c *Client) AcknowledgeAlert(ctx context.Context, alias string, note string) (*Response, error) {
+	return c.doRequest(ctx, "/v2/alerts/"+url.PathEscape(alias)+"/acknowledge", url.Values{"identifierType": {"alias"}}, map[string]string{
+		"source": source,
+		"note":   note,
+	})
+}
+
+// CloseAlert closes the open alert of the alias.
+func (c *Client) CloseAlert(ctx context.Context, alias string, note string) (*Response, error) {
+	return c.doRequest(ctx, "/v2/alerts/"+url.PathEscape(alias)+"/close", url.Values{"identifierType": {"alias"}}, map[string]string{
+		"source": source,
+		"note":   note,
+	})
+}
+
+func truncate(s string, length int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,71 @@
+package opsgenie
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var paths []string
+	var created CreateAlertInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "GenieKey key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Key format is not valid!"}`))
+			return
+		}
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/v2/alerts" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		} else {
+			assert.Equal(t, "alias", r.URL.Query().Get("identifierType"))
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"result":"Request will be processed","took":0.2,"requestId":"43a29c5c"}`))
+	}))
+	defer server.Close()
+
+	baseUrl := OpsgenieEUApiBaseUrl
+	OpsgenieEUApiBaseUrl = server.URL
+	defer func() { OpsgenieEUApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("key", RegionEU)
+	alias := ErrorGroupAlias(1)
+
+	res, err := client.CreateAlert(ctx, &CreateAlertInput{
+		Message:  strings.Repeat("a", 200),
+		Alias:    alias,
+		Priority: PriorityForSeverity("critical"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "43a29c5c", res.RequestID)
+	assert.Len(t, []rune(created.Message), maxMessageLength)
+	assert.Equal(t, PriorityP1, created.Priority)
+	assert.Equal(t, "highlight.io", created.Source)
+
+	_, err = client.AcknowledgeAlert(ctx, alias, "snoozed")
+	assert.NoError(t, err)
+	_, err = client.CloseAlert(ctx, alias, "resolved")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/v2/alerts", "/v2/alerts/highlight-error-group-1/acknowledge", "/v2/alerts/highlight-error-group-1/close"}, paths)
+
+	_, err = NewClient("invalid", RegionEU).CloseAlert(ctx, alias, "")
+	assert.Error(t, err)
+	_, err = NewClient("", RegionEU).CloseAlert(ctx, alias, "")
+	assert.Error(t, err)
+}
+
+func TestPriorityForSeverity(t *testing.T) {
+	assert.Equal(t, PriorityP1, PriorityForSeverity("critical"))
+	assert.Equal(t, PriorityP2, PriorityForSeverity("error"))
+	assert.Equal(t, PriorityP2, PriorityForSeverity(""))
+	assert.Equal(t, PriorityP3, PriorityForSeverity("warning"))
+	assert.Equal(t, PriorityP5, PriorityForSeverity("info"))
+}
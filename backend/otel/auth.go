@@ -0,0 +1,150 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The ingest key is the project secret. It can be sent as a request header (or grpc metadata),
+// which authenticates every item of the request, or as a resource attribute.
+const (
+	IngestKeyHeader    = "x-highlight-key"
+	IngestKeyAttribute = "highlight.key"
+)
+
+var (
+	errInvalidIngestKey  = e.New("invalid highlight ingest key")
+	errIngestKeyProject  = e.New("highlight ingest key does not match the project id")
+	errIngestKeyRequired = e.New("project requires a highlight ingest key")
+)
+
+type projectStore interface {
+	GetProject(ctx context.Context, id int) (*model.Project, error)
+	GetProjectIDBySecret(ctx context.Context, secret string) (int, error)
+	GetProjectFilterSettings(ctx context.Context, projectID int, opts ...redis.Option) (*model.ProjectFilterSettings, error)
+	GetEnabledIngestFilterRules(ctx context.Context, projectID int) ([]*model.IngestFilterRule, error)
+	RecordProjectSDK(ctx context.Context, projectID int, name string, version string) error
+}
+
+type ingestKeyProjectContextKey struct{}
+
+// authenticate resolves the project of the ingest key sent with a request.
+func (o *Handler) authenticate(ctx context.Context, key string) (context.Context, error) {
+	if key == "" || o.projects == nil {
+		return ctx, nil
+	}
+	projectID, err := o.projects.GetProjectIDBySecret(ctx, key)
+	if err != nil {
+		return ctx, err
+	}
+	if projectID == 0 {
+		return ctx, errInvalidIngestKey
+	}
+	return context.WithValue(ctx, ingestKeyProjectContextKey{}, projectID), nil
+}
+
+func (o *Handler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := o.authenticate(r.Context(), r.Header.Get(IngestKeyHeader))
+		if e.Is(err, errInvalidIngestKey) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to authenticate otel ingest key")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (o *Handler) grpcAuthInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(IngestKeyHeader); len(keys) > 0 {
+			key = keys[0]
+		}
+	}
+	ctx, err := o.authenticate(ctx, key)
+	if e.Is(err, errInvalidIngestKey) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	} else if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to authenticate otel grpc ingest key")
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return handler(ctx, req)
+}
+
+// ingestAuthorizer checks that every item of an export request may be written to its project,
+// caching the lookups for the duration of the request.
+type ingestAuthorizer struct {
+	projects         projectStore
+	requestProjectID int
+	keyProjectIDs    map[string]int
+	requireKey       map[int]bool
+}
+
+func (o *Handler) newIngestAuthorizer(ctx context.Context) *ingestAuthorizer {
+	requestProjectID, _ := ctx.Value(ingestKeyProjectContextKey{}).(int)
+	return &ingestAuthorizer{
+		projects:         o.projects,
+		requestProjectID: requestProjectID,
+		keyProjectIDs:    make(map[string]int),
+		requireKey:       make(map[int]bool),
+	}
+}
+
+// authorize accepts items of the project authenticated by the request ingest key,
+// items with a valid ingest key resource attribute, and items of projects that allow
+// unauthenticated ingestion.
+func (a *ingestAuthorizer) authorize(ctx context.Context, fields *extractedFields) error {
+	if a.projects == nil {
+		return nil
+	}
+	if a.requestProjectID != 0 {
+		if fields.projectIDInt != a.requestProjectID {
+			return errIngestKeyProject
+		}
+		return nil
+	}
+
+	if fields.ingestKey != "" {
+		projectID, ok := a.keyProjectIDs[fields.ingestKey]
+		if !ok {
+			var err error
+			if projectID, err = a.projects.GetProjectIDBySecret(ctx, fields.ingestKey); err != nil {
+				return err
+			}
+			a.keyProjectIDs[fields.ingestKey] = projectID
+		}
+		if projectID == 0 {
+			return errInvalidIngestKey
+		} else if projectID != fields.projectIDInt {
+			return errIngestKeyProject
+		}
+		return nil
+	}
+
+	requireKey, ok := a.requireKey[fields.projectIDInt]
+	if !ok {
+		project, err := a.projects.GetProject(ctx, fields.projectIDInt)
+		if err != nil {
+			return e.Wrapf(err, "failed to get project %d", fields.projectIDInt)
+		}
+		requireKey = project.RequireIngestKey
+		a.requireKey[fields.projectIDInt] = requireKey
+	}
+	if requireKey {
+		return errIngestKeyRequired
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/go-chi/chi"
+	highlightHttp "github.com/highlight-run/highlight/backend/http"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProjectStore struct {
+	secrets           map[string]int
+	requireKey        map[int]bool
+	redactedKeys      []string
+	redactionPatterns []string
+	spanStatusErrors  map[int]bool
+	excludedServices  []string
+	excludedLogLevels []string
+	filterRules       []*model.IngestFilterRule
+	sdks              []string
+}
+
+func (m *mockProjectStore) GetProject(_ context.Context, id int) (*model.Project, error) {
+	project := &model.Project{Model: model.Model{ID: id}, RequireIngestKey: m.requireKey[id]}
+	for secret, projectID := range m.secrets {
+		if projectID == id {
+			project.Secret = ptr.String(secret)
+		}
+	}
+	return project, nil
+}
+
+func (m *mockProjectStore) GetProjectFilterSettings(_ context.Context, projectID int, _ ...redis.Option) (*model.ProjectFilterSettings, error) {
+	return &model.ProjectFilterSettings{
+		ProjectID:             projectID,
+		RedactedAttributeKeys: m.redactedKeys,
+		RedactionPatterns:     m.redactionPatterns,
+		ErrorsFromSpanStatus:  m.spanStatusErrors[projectID],
+		ExcludedServiceNames:  m.excludedServices,
+		ExcludedLogLevels:     m.excludedLogLevels,
+	}, nil
+}
+
+func (m *mockProjectStore) GetEnabledIngestFilterRules(_ context.Context, _ int) ([]*model.IngestFilterRule, error) {
+	return m.filterRules, nil
+}
+
+func (m *mockProjectStore) RecordProjectSDK(_ context.Context, projectID int, name string, version string) error {
+	m.sdks = append(m.sdks, fmt.Sprintf("%d:%s@%s", projectID, name, version))
+	return nil
+}
+
+func (m *mockProjectStore) GetProjectIDBySecret(_ context.Context, secret string) (int, error) {
+	return m.secrets[secret], nil
+}
+
+func newMockProjectStore() *mockProjectStore {
+	return &mockProjectStore{
+		secrets:    map[string]int{"secret-1": 1, "secret-2": 2},
+		requireKey: map[int]bool{2: true},
+	}
+}
+
+func TestIngestAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	h := Handler{projects: newMockProjectStore()}
+
+	auth := h.newIngestAuthorizer(ctx)
+	assert.NoError(t, auth.authorize(ctx, &extractedFields{projectIDInt: 1}))
+	assert.NoError(t, auth.authorize(ctx, &extractedFields{projectIDInt: 1, ingestKey: "secret-1"}))
+	assert.ErrorIs(t, auth.authorize(ctx, &extractedFields{projectIDInt: 1, ingestKey: "secret-2"}), errIngestKeyProject)
+	assert.ErrorIs(t, auth.authorize(ctx, &extractedFields{projectIDInt: 1, ingestKey: "invalid"}), errInvalidIngestKey)
+	assert.ErrorIs(t, auth.authorize(ctx, &extractedFields{projectIDInt: 2}), errIngestKeyRequired)
+	assert.NoError(t, auth.authorize(ctx, &extractedFields{projectIDInt: 2, ingestKey: "secret-2"}))
+
+	ctx, err := h.authenticate(ctx, "secret-2")
+	assert.NoError(t, err)
+	auth = h.newIngestAuthorizer(ctx)
+	assert.NoError(t, auth.authorize(ctx, &extractedFields{projectIDInt: 2}))
+	assert.ErrorIs(t, auth.authorize(ctx, &extractedFields{projectIDInt: 1}), errIngestKeyProject)
+}
+
+func TestHandler_AuthMiddleware(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+	var projectID int
+	next := h.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectID, _ = r.Context().Value(ingestKeyProjectContextKey{}).(int)
+	}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/otel/v1/logs", nil)
+	r.Header.Set(IngestKeyHeader, "invalid")
+	next.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/otel/v1/logs", nil)
+	r.Header.Set(IngestKeyHeader, "secret-1")
+	next.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, projectID)
+}
+
+func TestHandler_Listen(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+	r := chi.NewMux()
+	h.Listen(r)
+	highlightHttp.Listen(r)
+
+	for _, path := range []string{"/otel/v1/traces", "/otel/v1/logs", "/v1/traces", "/v1/logs", "/v1/metrics", "/v1/profiles"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(IngestKeyHeader, "invalid")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+	}
+}
+
+func TestGetProjectMetricRows_RequireIngestKey(t *testing.T) {
+	ctx := context.Background()
+	h := Handler{projects: &mockProjectStore{
+		secrets:    map[string]int{"secret-1": 1},
+		requireKey: map[int]bool{1: true},
+	}}
+
+	metrics := newTestMetrics()
+	projectMetrics, rejected := getProjectMetricRows(ctx, metrics, h.newIngestAuthorizer(ctx))
+	assert.Empty(t, projectMetrics)
+	// the three supported datapoints, the summary and the datapoint without a project
+	assert.Equal(t, int64(5), rejected.count)
+	assert.ErrorIs(t, rejected.err, errIngestKeyRequired)
+
+	metrics.ResourceMetrics().At(0).Resource().Attributes().PutStr(IngestKeyAttribute, "secret-1")
+	projectMetrics, rejected = getProjectMetricRows(ctx, metrics, h.newIngestAuthorizer(ctx))
+	assert.Len(t, projectMetrics["1"], 3)
+	assert.Equal(t, int64(2), rejected.count)
+	assert.NotContains(t, projectMetrics["1"][0].Attributes, IngestKeyAttribute)
+}
@@ -0,0 +1,117 @@
+package otel
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	e "github.com/pkg/errors"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	ClockSkewMaxFutureEnvVar = "OTEL_CLOCK_SKEW_MAX_FUTURE"
+	ClockSkewMaxPastEnvVar   = "OTEL_CLOCK_SKEW_MAX_PAST"
+	ClockSkewModeEnvVar      = "OTEL_CLOCK_SKEW_MODE"
+)
+
+// OriginalTimestampAttribute records the timestamp sent by the client when it was corrected for clock skew.
+const OriginalTimestampAttribute = "highlight.original_timestamp"
+
+type ClockSkewMode string
+
+const (
+	// ClockSkewModeReceive replaces skewed timestamps with the time the export request was received.
+	ClockSkewModeReceive ClockSkewMode = "receive"
+	// ClockSkewModeClamp moves skewed timestamps to the nearest accepted time.
+	ClockSkewModeClamp ClockSkewMode = "clamp"
+)
+
+// ClockSkew bounds how far the timestamps of spans and logs may be from the time their export
+// request was received, ie. the logs of a mobile device with a wrong clock. A zero bound disables
+// the correction in that direction.
+type ClockSkew struct {
+	MaxFuture time.Duration
+	MaxPast   time.Duration
+	Mode      ClockSkewMode
+}
+
+var DefaultClockSkew = ClockSkew{
+	MaxFuture: time.Hour,
+	MaxPast:   30 * 24 * time.Hour,
+	Mode:      ClockSkewModeReceive,
+}
+
+// LoadClockSkew returns the default clock skew correction with any overrides from the environment applied.
+// The bounds are durations, ie. `2h`.
+func LoadClockSkew() (ClockSkew, error) {
+	clockSkew := DefaultClockSkew
+	for envVar, bound := range map[string]*time.Duration{
+		ClockSkewMaxFutureEnvVar: &clockSkew.MaxFuture,
+		ClockSkewMaxPastEnvVar:   &clockSkew.MaxPast,
+	} {
+		value := os.Getenv(envVar)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return DefaultClockSkew, e.Errorf("invalid %s %q", envVar, value)
+		}
+		*bound = d
+	}
+	if value := os.Getenv(ClockSkewModeEnvVar); value != "" {
+		switch mode := ClockSkewMode(strings.ToLower(value)); mode {
+		case ClockSkewModeReceive, ClockSkewModeClamp:
+			clockSkew.Mode = mode
+		default:
+			return DefaultClockSkew, e.Errorf("invalid %s %q", ClockSkewModeEnvVar, value)
+		}
+	}
+	return clockSkew, nil
+}
+
+// clockSkewCorrector corrects the timestamps of an export request relative to the time it was received.
+type clockSkewCorrector struct {
+	ClockSkew
+	receivedAt time.Time
+}
+
+func (o *Handler) newClockSkewCorrector() *clockSkewCorrector {
+	return &clockSkewCorrector{ClockSkew: o.clockSkew, receivedAt: time.Now()}
+}
+
+// offset returns the shift that corrects a timestamp, or zero when it is within the bounds.
+func (c *clockSkewCorrector) offset(t time.Time) time.Duration {
+	var bound time.Time
+	if c.MaxFuture > 0 && t.After(c.receivedAt.Add(c.MaxFuture)) {
+		bound = c.receivedAt.Add(c.MaxFuture)
+	} else if c.MaxPast > 0 && t.Before(c.receivedAt.Add(-c.MaxPast)) {
+		bound = c.receivedAt.Add(-c.MaxPast)
+	} else {
+		return 0
+	}
+	if c.Mode == ClockSkewModeClamp {
+		return bound.Sub(t)
+	}
+	return c.receivedAt.Sub(t)
+}
+
+// apply corrects the timestamp of a log or span event, recording the original with OriginalTimestampAttribute.
+func (c *clockSkewCorrector) apply(fields *extractedFields) {
+	if offset := c.offset(fields.timestamp); offset != 0 {
+		fields.attrs[OriginalTimestampAttribute] = fields.timestamp.UTC().Format(time.RFC3339Nano)
+		fields.timestamp = fields.timestamp.Add(offset)
+	}
+}
+
+// span returns the corrected start and end of a span. Both are shifted by the correction of the start
+// so that the duration of the span is kept.
+func (c *clockSkewCorrector) span(span ptrace.Span, fields *extractedFields) (time.Time, time.Time) {
+	start, end := span.StartTimestamp().AsTime(), span.EndTimestamp().AsTime()
+	if offset := c.offset(start); offset != 0 {
+		fields.attrs[OriginalTimestampAttribute] = start.UTC().Format(time.RFC3339Nano)
+		return start.Add(offset), end.Add(offset)
+	}
+	return start, end
+}
@@ -0,0 +1,89 @@
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestLoadClockSkew(t *testing.T) {
+	t.Setenv(ClockSkewMaxFutureEnvVar, "5m")
+	t.Setenv(ClockSkewModeEnvVar, "Clamp")
+	clockSkew, err := LoadClockSkew()
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, clockSkew.MaxFuture)
+	assert.Equal(t, DefaultClockSkew.MaxPast, clockSkew.MaxPast)
+	assert.Equal(t, ClockSkewModeClamp, clockSkew.Mode)
+
+	t.Setenv(ClockSkewModeEnvVar, "shift")
+	_, err = LoadClockSkew()
+	assert.Error(t, err)
+
+	t.Setenv(ClockSkewModeEnvVar, "")
+	t.Setenv(ClockSkewMaxPastEnvVar, "7")
+	_, err = LoadClockSkew()
+	assert.Error(t, err)
+}
+
+func TestClockSkewCorrector(t *testing.T) {
+	receivedAt := time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC)
+	future := time.Date(2106, 2, 7, 6, 28, 15, 0, time.UTC)
+	past := time.Unix(0, 0).UTC()
+
+	clock := &clockSkewCorrector{ClockSkew: ClockSkew{MaxFuture: time.Hour, MaxPast: 24 * time.Hour}, receivedAt: receivedAt}
+	for _, ts := range []time.Time{future, past} {
+		fields := newExtractedFields()
+		fields.timestamp = ts
+		clock.apply(fields)
+		assert.Equal(t, receivedAt, fields.timestamp)
+		assert.Equal(t, ts.Format(time.RFC3339Nano), fields.attrs[OriginalTimestampAttribute])
+	}
+
+	fields := newExtractedFields()
+	fields.timestamp = receivedAt.Add(-time.Hour)
+	clock.apply(fields)
+	assert.Equal(t, receivedAt.Add(-time.Hour), fields.timestamp)
+	assert.NotContains(t, fields.attrs, OriginalTimestampAttribute)
+
+	clock.Mode = ClockSkewModeClamp
+	fields.timestamp = future
+	clock.apply(fields)
+	assert.Equal(t, receivedAt.Add(time.Hour), fields.timestamp)
+
+	span := ptrace.NewSpan()
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(past))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(past.Add(time.Second)))
+	fields = newExtractedFields()
+	start, end := clock.span(span, fields)
+	assert.Equal(t, receivedAt.Add(-24*time.Hour), start)
+	assert.Equal(t, time.Second, end.Sub(start))
+	assert.Equal(t, past.Format(time.RFC3339Nano), fields.attrs[OriginalTimestampAttribute])
+
+	clock = &clockSkewCorrector{receivedAt: receivedAt}
+	fields = newExtractedFields()
+	fields.timestamp = future
+	clock.apply(fields)
+	assert.Equal(t, future, fields.timestamp, "zero bounds disable the correction")
+}
+
+func TestClockSkewCorrector_SpanStatusError(t *testing.T) {
+	receivedAt := time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC)
+	past := time.Unix(0, 0).UTC()
+	clock := &clockSkewCorrector{ClockSkew: ClockSkew{MaxFuture: time.Hour, MaxPast: 24 * time.Hour}, receivedAt: receivedAt}
+
+	span := ptrace.NewSpan()
+	span.SetName("GET /api/users")
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(past))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(past.Add(time.Second)))
+	span.Status().SetCode(ptrace.StatusCodeError)
+
+	fields := newExtractedFields()
+	errorFields := getSpanStatusErrorFields(span, fields)
+	clock.apply(errorFields)
+	assert.Equal(t, receivedAt, errorFields.timestamp)
+	assert.Equal(t, past.Add(time.Second).Format(time.RFC3339Nano), errorFields.attrs[OriginalTimestampAttribute])
+	assert.NotContains(t, fields.attrs, OriginalTimestampAttribute, "the span attributes are not modified")
+}
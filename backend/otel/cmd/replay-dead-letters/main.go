@@ -0,0 +1,48 @@
+// replay-dead-letters resubmits the otel messages that were written to the dead-letter store
+// because they could not be submitted to kafka, ie. after a kafka outage.
+// The store is configured by the same OTEL_DEAD_LETTER_BUCKET and OTEL_DEAD_LETTER_PREFIX
+// environment as the otel handler.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/otel"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	ctx := context.Background()
+
+	dryRun := flag.Bool("dry-run", false, "list the dead letters that would be replayed without submitting them")
+	flag.Parse()
+
+	store, err := otel.NewS3DeadLetterStore(ctx)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Fatal("failed to create the otel dead-letter store")
+	}
+	if store == nil {
+		log.WithContext(ctx).Fatalf("%s is not set", otel.DeadLetterBucketEnvVar)
+	}
+
+	queues := map[kafkaqueue.TopicType]kafkaqueue.MessageQueue{}
+	for _, topic := range []kafkaqueue.TopicType{kafkaqueue.TopicTypeDefault, kafkaqueue.TopicTypeBatched, kafkaqueue.TopicTypeTraces} {
+		if *dryRun {
+			queues[topic] = &kafkaqueue.MockMessageQueue{}
+			continue
+		}
+		queue := kafkaqueue.New(ctx, kafkaqueue.GetTopic(kafkaqueue.GetTopicOptions{Type: topic}), kafkaqueue.Producer, nil)
+		defer queue.Stop(ctx)
+		queues[topic] = queue
+	}
+
+	replayed, err := otel.ReplayDeadLetters(ctx, store, queues, *dryRun)
+	log.WithContext(ctx).Infof("replayed %d otel dead letters", replayed)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to replay otel dead letters")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,236 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DeadLetterBucketEnvVar = "OTEL_DEAD_LETTER_BUCKET"
+	DeadLetterPrefixEnvVar = "OTEL_DEAD_LETTER_PREFIX"
+)
+
+const defaultDeadLetterPrefix = "otel-dead-letter"
+
+// DeadLetter is a batch of messages that could not be submitted to kafka,
+// kept so that it can be replayed once kafka is available again.
+type DeadLetter struct {
+	Topic     kafkaqueue.TopicType  `json:"topic"`
+	Key       string                `json:"key"`
+	Messages  []*kafkaqueue.Message `json:"messages"`
+	Error     string                `json:"error"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+type DeadLetterStore interface {
+	Put(ctx context.Context, letter *DeadLetter) error
+	// List returns the keys of the stored dead letters, oldest first.
+	List(ctx context.Context) ([]string, error)
+	Get(ctx context.Context, key string) (*DeadLetter, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// S3DeadLetterStore keeps dead letters as gzipped json objects under a prefix of a bucket,
+// ie. `otel-dead-letter/2023-10-16/traces/<uuid>.json.gz`.
+type S3DeadLetterStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3DeadLetterStore returns the dead-letter store configured by the environment,
+// or nil when no bucket is configured.
+func NewS3DeadLetterStore(ctx context.Context) (*S3DeadLetterStore, error) {
+	bucket := os.Getenv(DeadLetterBucketEnvVar)
+	if bucket == "" {
+		return nil, nil
+	}
+	prefix := strings.Trim(os.Getenv(DeadLetterPrefixEnvVar), "/")
+	if prefix == "" {
+		prefix = defaultDeadLetterPrefix
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(model.AWS_REGION_US_EAST_2))
+	if err != nil {
+		return nil, e.Wrap(err, "error loading default from config")
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = true
+	})
+	return &S3DeadLetterStore{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *S3DeadLetterStore) Put(ctx context.Context, letter *DeadLetter) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(letter); err != nil {
+		return e.Wrap(err, "failed to encode dead letter")
+	}
+	if err := gz.Close(); err != nil {
+		return e.Wrap(err, "failed to compress dead letter")
+	}
+	key := path.Join(s.prefix, letter.Timestamp.UTC().Format("2006-01-02"), string(letter.Topic), uuid.New().String()+".json.gz")
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(buf.Bytes()),
+		ContentType:     aws.String("application/json"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	return e.Wrapf(err, "failed to write dead letter %s", key)
+}
+
+func (s *S3DeadLetterStore) List(ctx context.Context) ([]string, error) {
+	type object struct {
+		key          string
+		lastModified time.Time
+	}
+	var objects []object
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix + "/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, e.Wrap(err, "failed to list dead letters")
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, object{key: aws.ToString(obj.Key), lastModified: aws.ToTime(obj.LastModified)})
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].lastModified.Before(objects[j].lastModified)
+	})
+	keys := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		keys = append(keys, obj.key)
+	}
+	return keys, nil
+}
+
+func (s *S3DeadLetterStore) Get(ctx context.Context, key string) (*DeadLetter, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, e.Wrapf(err, "failed to read dead letter %s", key)
+	}
+	defer output.Body.Close()
+
+	gz, err := gzip.NewReader(output.Body)
+	if err != nil {
+		return nil, e.Wrapf(err, "failed to decompress dead letter %s", key)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, e.Wrapf(err, "failed to decompress dead letter %s", key)
+	}
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, e.Wrapf(err, "failed to decode dead letter %s", key)
+	}
+	return &letter, nil
+}
+
+func (s *S3DeadLetterStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return e.Wrapf(err, "failed to delete dead letter %s", key)
+}
+
+// queue returns the kafka queue that messages of the topic are submitted to.
+func (o *Handler) queue(topic kafkaqueue.TopicType) kafkaqueue.MessageQueue {
+	switch topic {
+	case kafkaqueue.TopicTypeBatched:
+		return o.resolver.BatchedQueue
+	case kafkaqueue.TopicTypeTraces:
+		return o.resolver.TracesQueue
+	default:
+		return o.resolver.ProducerQueue
+	}
+}
+
+// submit submits messages to the kafka queue of the topic. When the submission fails, the
+// messages are written to the dead-letter store to be replayed later, and the error is only
+// returned when they could not be stored either. Submissions cancelled by the client disconnecting
+// are not stored, as the client retries the export.
+func (o *Handler) submit(ctx context.Context, topic kafkaqueue.TopicType, key string, messages ...*kafkaqueue.Message) error {
+	err := o.queue(topic).Submit(ctx, key, messages...)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil || o.deadLetters == nil {
+		return err
+	}
+	letter := &DeadLetter{
+		Topic:     topic,
+		Key:       key,
+		Messages:  messages,
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	}
+	if dlErr := o.deadLetters.Put(ctx, letter); dlErr != nil {
+		log.WithContext(ctx).WithError(dlErr).WithField("topic", topic).Error("failed to write otel dead letter")
+		return err
+	}
+	log.WithContext(ctx).WithError(err).
+		WithField("topic", topic).
+		WithField("num_messages", len(messages)).
+		Warn("wrote otel messages that failed to submit to the dead-letter store")
+	return nil
+}
+
+// ReplayDeadLetters resubmits the stored dead letters to the queues of their topics, oldest first,
+// deleting each once it is submitted. It stops at the first submission error, as kafka is likely
+// still unavailable. Dead letters of topics without a queue are kept.
+func ReplayDeadLetters(ctx context.Context, store DeadLetterStore, queues map[kafkaqueue.TopicType]kafkaqueue.MessageQueue, dryRun bool) (int, error) {
+	keys, err := store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var replayed int
+	for _, key := range keys {
+		letter, err := store.Get(ctx, key)
+		if err != nil {
+			return replayed, err
+		}
+		queue, ok := queues[letter.Topic]
+		if !ok {
+			log.WithContext(ctx).WithField("key", key).WithField("topic", letter.Topic).Warn("skipping otel dead letter of unknown topic")
+			continue
+		}
+		if dryRun {
+			log.WithContext(ctx).WithField("key", key).WithField("topic", letter.Topic).WithField("num_messages", len(letter.Messages)).Info("would replay otel dead letter")
+			replayed++
+			continue
+		}
+		if err := queue.Submit(ctx, letter.Key, letter.Messages...); err != nil {
+			return replayed, e.Wrapf(err, "failed to replay dead letter %s", key)
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
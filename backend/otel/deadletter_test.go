@@ -0,0 +1,111 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	public "github.com/highlight-run/highlight/backend/public-graph/graph"
+	e "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+type mockDeadLetterStore struct {
+	letters map[string]*DeadLetter
+}
+
+func (m *mockDeadLetterStore) Put(_ context.Context, letter *DeadLetter) error {
+	m.letters[fmt.Sprintf("%d", len(m.letters))] = letter
+	return nil
+}
+
+func (m *mockDeadLetterStore) List(_ context.Context) ([]string, error) {
+	var keys []string
+	for k := range m.letters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *mockDeadLetterStore) Get(_ context.Context, key string) (*DeadLetter, error) {
+	return m.letters[key], nil
+}
+
+func (m *mockDeadLetterStore) Delete(_ context.Context, key string) error {
+	delete(m.letters, key)
+	return nil
+}
+
+type failingKafkaProducer struct {
+	MockKafkaProducer
+}
+
+func (m *failingKafkaProducer) Submit(_ context.Context, _ string, _ ...*kafkaqueue.Message) error {
+	return e.New("kafka unavailable")
+}
+
+func TestHandler_SubmitDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	producer := MockKafkaProducer{}
+	failing := failingKafkaProducer{}
+	h := Handler{resolver: &public.Resolver{
+		ProducerQueue: &producer,
+		BatchedQueue:  &failing,
+		TracesQueue:   &producer,
+	}}
+	message := &kafkaqueue.Message{Type: kafkaqueue.PushLogs}
+
+	assert.Error(t, h.submit(ctx, kafkaqueue.TopicTypeBatched, "", message))
+
+	store := &mockDeadLetterStore{letters: map[string]*DeadLetter{}}
+	h.deadLetters = store
+	assert.NoError(t, h.submit(ctx, kafkaqueue.TopicTypeTraces, "trace", message))
+	assert.NoError(t, h.submit(ctx, kafkaqueue.TopicTypeBatched, "key", message))
+	assert.Len(t, producer.messages, 1)
+	assert.Len(t, store.letters, 1)
+	assert.Equal(t, kafkaqueue.TopicTypeBatched, store.letters["0"].Topic)
+	assert.Equal(t, "key", store.letters["0"].Key)
+	assert.Equal(t, "kafka unavailable", store.letters["0"].Error)
+
+	replayed, err := ReplayDeadLetters(ctx, store, map[kafkaqueue.TopicType]kafkaqueue.MessageQueue{
+		kafkaqueue.TopicTypeBatched: &failing,
+	}, false)
+	assert.Error(t, err)
+	assert.Equal(t, 0, replayed)
+	assert.Len(t, store.letters, 1)
+
+	replayed, err = ReplayDeadLetters(ctx, store, map[kafkaqueue.TopicType]kafkaqueue.MessageQueue{
+		kafkaqueue.TopicTypeBatched: &producer,
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	assert.Empty(t, store.letters)
+	assert.Len(t, producer.messages, 2)
+}
+
+func TestHandler_SubmitCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failing := failingKafkaProducer{}
+	store := &mockDeadLetterStore{letters: map[string]*DeadLetter{}}
+	h := Handler{resolver: &public.Resolver{BatchedQueue: &failing}, deadLetters: store}
+
+	err := h.submit(ctx, kafkaqueue.TopicTypeBatched, "key", &kafkaqueue.Message{Type: kafkaqueue.PushLogs})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, store.letters, "the client retries exports cancelled by its disconnect")
+	assert.Equal(t, dropReasonCanceled, submitErrorReason(e.Wrap(err, "failed to submit")))
+
+	body, err := pmetricotlp.NewExportRequestFromMetrics(newTestMetrics()).MarshalProto()
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.HandleMetric(w, httptest.NewRequest(http.MethodPost, "/otel/v1/metrics", bytes.NewReader(body)).WithContext(ctx))
+	assert.Equal(t, statusClientClosedRequest, w.Code)
+	assert.Empty(t, store.letters)
+}
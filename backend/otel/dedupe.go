@@ -0,0 +1,66 @@
+package otel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// spanStatusEventIndex is the event index of an error created from a span status,
+// which is not recorded as a span event.
+const spanStatusEventIndex = -1
+
+// dedupeKeys holds the dedupe keys of the log rows and errors of an export request. They are sent
+// with the kafka messages so that the consumers drop the rows of requests retried by exporters.
+type dedupeKeys struct {
+	logs   map[*clickhouse.LogRow]string
+	errors map[*model.BackendErrorObjectInput]string
+}
+
+func newDedupeKeys() *dedupeKeys {
+	return &dedupeKeys{
+		logs:   make(map[*clickhouse.LogRow]string),
+		errors: make(map[*model.BackendErrorObjectInput]string),
+	}
+}
+
+// dedupeKey is a deterministic hash of the trace id, span id and event index of an error or log.
+func dedupeKey(traceID, spanID string, eventIndex int) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", traceID, spanID, eventIndex)))
+	return hex.EncodeToString(h[:])
+}
+
+// resourceDigest is a deterministic hash of the attributes of a resource, which identify the
+// process that emitted its logs.
+func resourceDigest(resource pcommon.Resource) string {
+	h := sha256.Sum256([]byte(fmt.Sprint(resource.Attributes().AsRaw())))
+	return hex.EncodeToString(h[:])
+}
+
+// logRecordDedupeKey is a deterministic hash of the delivery of an otel log record: the resource
+// that emitted it, its position in the export request and its raw and observed times. A retried
+// export request has the same keys, while identical lines logged by different processes or
+// requests within the same second are distinct.
+func logRecordDedupeKey(resourceDigest string, scopeIndex int, recordIndex int, logRecord plog.LogRecord) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/%d/%d/%d/%s/%s/%d/%q",
+		resourceDigest, scopeIndex, recordIndex, logRecord.Timestamp(), logRecord.ObservedTimestamp(),
+		logRecord.TraceID(), logRecord.SpanID(), logRecord.SeverityNumber(), logRecord.Body().AsString())))
+	return hex.EncodeToString(h[:])
+}
+
+// payloadDedupeIDs returns the dedupe ids of the records of a log drain payload without record ids,
+// from a hash of the payload and the index of each record, so that only redelivered payloads are deduped.
+func payloadDedupeIDs(payload []byte, count int) []string {
+	h := sha256.Sum256(payload)
+	digest := hex.EncodeToString(h[:])
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s/%d", digest, i)
+	}
+	return ids
+}
@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newTestLogRecord(body string, timestamp time.Time) plog.LogRecord {
+	logRecord := plog.NewLogRecord()
+	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	logRecord.SetSeverityText("INFO")
+	logRecord.Body().SetStr(body)
+	return logRecord
+}
+
+func TestDedupeKey(t *testing.T) {
+	assert.Equal(t, dedupeKey("trace", "span", 0), dedupeKey("trace", "span", 0))
+	assert.NotEqual(t, dedupeKey("trace", "span", 0), dedupeKey("trace", "span", 1))
+	assert.NotEqual(t, dedupeKey("trace", "span", 0), dedupeKey("trace", "other", 0))
+
+	now := time.Now()
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "api")
+	resource.Attributes().PutStr("host.name", "pod-1")
+	otherResource := pcommon.NewResource()
+	otherResource.Attributes().PutStr("service.name", "api")
+	otherResource.Attributes().PutStr("host.name", "pod-2")
+
+	a := logRecordDedupeKey(resourceDigest(resource), 0, 0, newTestLogRecord("a", now))
+	assert.Equal(t, a, logRecordDedupeKey(resourceDigest(resource), 0, 0, newTestLogRecord("a", now)))
+	assert.NotEqual(t, a, logRecordDedupeKey(resourceDigest(resource), 0, 0, newTestLogRecord("b", now)))
+	assert.NotEqual(t, a, logRecordDedupeKey(resourceDigest(resource), 0, 1, newTestLogRecord("a", now)))
+	assert.NotEqual(t, a, logRecordDedupeKey(resourceDigest(resource), 1, 0, newTestLogRecord("a", now)))
+	assert.NotEqual(t, a, logRecordDedupeKey(resourceDigest(otherResource), 0, 0, newTestLogRecord("a", now)))
+	assert.NotEqual(t, a, logRecordDedupeKey(resourceDigest(resource), 0, 0, newTestLogRecord("a", now.Add(time.Millisecond))))
+}
+
+// identical lines logged within the same second by different processes, or by different requests
+// of the same process, are distinct logs, while a retried export request has the same logs
+func TestLogRecordDedupeKey_SameSecond(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	pod := func(name string) string {
+		resource := pcommon.NewResource()
+		resource.Attributes().PutStr("service.name", "api")
+		resource.Attributes().PutStr("host.name", name)
+		return resourceDigest(resource)
+	}
+
+	first := logRecordDedupeKey(pod("pod-1"), 0, 0, newTestLogRecord("GET /health", now))
+	otherPod := logRecordDedupeKey(pod("pod-2"), 0, 0, newTestLogRecord("GET /health", now))
+	otherRequest := logRecordDedupeKey(pod("pod-1"), 0, 0, newTestLogRecord("GET /health", now.Add(time.Millisecond)))
+	retried := logRecordDedupeKey(pod("pod-1"), 0, 0, newTestLogRecord("GET /health", now))
+	assert.NotEqual(t, first, otherPod)
+	assert.NotEqual(t, first, otherRequest)
+	assert.Equal(t, first, retried)
+
+	r := redis.NewClient()
+	defer func() {
+		assert.NoError(t, r.ReleaseDedupeKeys(ctx, redis.DedupeKindLog, []string{first, otherPod, otherRequest}))
+	}()
+	claimed, err := r.ClaimDedupeKeys(ctx, redis.DedupeKindLog, []string{first})
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+	claimed, err = r.ClaimDedupeKeys(ctx, redis.DedupeKindLog, []string{otherPod, otherRequest, retried})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{retried: true}, claimed)
+}
+
+func TestPayloadDedupeIDs(t *testing.T) {
+	ids := payloadDedupeIDs([]byte(`[{"log":"hello"},{"log":"hello"}]`), 2)
+	assert.Len(t, ids, 2)
+	assert.NotEqual(t, ids[0], ids[1])
+	assert.Equal(t, ids, payloadDedupeIDs([]byte(`[{"log":"hello"},{"log":"hello"}]`), 2))
+	assert.NotEqual(t, ids[0], payloadDedupeIDs([]byte(`[{"log":"hello"}]`), 1)[0])
+}
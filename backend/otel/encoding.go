@@ -0,0 +1,188 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	e "github.com/pkg/errors"
+)
+
+var (
+	ErrUnsupportedEncoding = e.New("unsupported content encoding")
+	ErrPayloadTooLarge     = e.New("otel payload too large")
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// maxPooledBufferBytes keeps the buffers of unusually large requests out of the pool,
+// so that a single large batch does not pin its memory for the lifetime of the process.
+const maxPooledBufferBytes = 16 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+var gzipReaderPool sync.Pool
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferBytes {
+		bufferPool.Put(buf)
+	}
+}
+
+// getBody returns the request body decompressed according to its Content-Encoding.
+// Bodies without the header are sniffed, since older highlight SDKs send gzip without setting it.
+// The body is read into pooled buffers and rejected with ErrPayloadTooLarge when it exceeds the limits.
+// The body must not be used after calling release, which returns the buffers to the pool.
+func getBody(w http.ResponseWriter, r *http.Request, limits Limits) (body []byte, release func(), err error) {
+	reader := r.Body
+	if limits.MaxRequestBytes > 0 {
+		reader = http.MaxBytesReader(w, r.Body, int64(limits.MaxRequestBytes))
+	}
+
+	compressed := getBuffer()
+	if _, err := compressed.ReadFrom(reader); err != nil {
+		putBuffer(compressed)
+		var maxBytesErr *http.MaxBytesError
+		if e.As(err, &maxBytesErr) {
+			return nil, nil, e.Wrapf(ErrPayloadTooLarge, "request body exceeds %d bytes", maxBytesErr.Limit)
+		}
+		return nil, nil, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	if encoding == "" {
+		if bytes.HasPrefix(compressed.Bytes(), gzipMagic) {
+			encoding = "gzip"
+		} else if bytes.HasPrefix(compressed.Bytes(), zstdMagic) {
+			encoding = "zstd"
+		}
+	}
+
+	var decompressor io.Reader
+	switch encoding {
+	case "", "identity":
+		return compressed.Bytes(), func() { putBuffer(compressed) }, nil
+	case "gzip", "x-gzip":
+		gz, _ := gzipReaderPool.Get().(*gzip.Reader)
+		if gz == nil {
+			gz = new(gzip.Reader)
+		}
+		defer gzipReaderPool.Put(gz)
+		if err := gz.Reset(bytes.NewReader(compressed.Bytes())); err != nil {
+			putBuffer(compressed)
+			return nil, nil, e.Wrap(err, "invalid gzip format")
+		}
+		decompressor = gz
+	case "snappy":
+		// snappy bodies, ie. of prometheus remote-write, are a single block rather than a stream
+		output, err := decodeSnappy(compressed.Bytes(), limits.MaxDecompressedBytes)
+		putBuffer(compressed)
+		if err != nil {
+			return nil, nil, err
+		}
+		return output.Bytes(), func() { putBuffer(output) }, nil
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(compressed.Bytes()), zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			putBuffer(compressed)
+			return nil, nil, err
+		}
+		defer zr.Close()
+		decompressor = zr
+	default:
+		putBuffer(compressed)
+		return nil, nil, e.Wrap(ErrUnsupportedEncoding, encoding)
+	}
+
+	output, err := decompress(decompressor, limits.MaxDecompressedBytes)
+	putBuffer(compressed)
+	if e.Is(err, ErrPayloadTooLarge) {
+		return nil, nil, err
+	} else if err != nil {
+		return nil, nil, e.Wrapf(err, "invalid %s stream", encoding)
+	}
+	return output.Bytes(), func() { putBuffer(output) }, nil
+}
+
+// decompress reads the decompressed stream into a pooled buffer, up to limit bytes.
+func decompress(reader io.Reader, limit int) (*bytes.Buffer, error) {
+	output := getBuffer()
+	if limit > 0 {
+		reader = io.LimitReader(reader, int64(limit)+1)
+	}
+	if _, err := output.ReadFrom(reader); err != nil {
+		putBuffer(output)
+		return nil, err
+	}
+	if limit > 0 && output.Len() > limit {
+		putBuffer(output)
+		return nil, e.Wrapf(ErrPayloadTooLarge, "decompressed body exceeds %d bytes", limit)
+	}
+	return output, nil
+}
+
+// decodeSnappy decodes a snappy block into a pooled buffer, when it decodes to at most limit bytes.
+func decodeSnappy(compressed []byte, limit int) (*bytes.Buffer, error) {
+	n, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, e.Wrap(err, "invalid snappy block")
+	}
+	if limit > 0 && n > limit {
+		return nil, e.Wrapf(ErrPayloadTooLarge, "decompressed body exceeds %d bytes", limit)
+	}
+	output := getBuffer()
+	// decode into the spare capacity of the buffer, then extend the buffer over the decoded bytes
+	output.Grow(n)
+	decoded, err := snappy.Decode(output.Bytes()[:n], compressed)
+	if err != nil {
+		putBuffer(output)
+		return nil, e.Wrap(err, "invalid snappy block")
+	}
+	output.Write(decoded)
+	return output, nil
+}
+
+func getBodyErrorStatus(err error) int {
+	if e.Is(err, ErrUnsupportedEncoding) {
+		return http.StatusUnsupportedMediaType
+	} else if e.Is(err, ErrPayloadTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+type otlpRequest interface {
+	UnmarshalProto(data []byte) error
+	UnmarshalJSON(data []byte) error
+}
+
+// unmarshalRequest decodes an OTLP export request in the wire format of the Content-Type,
+// defaulting to protobuf which is what the SDK exporters send.
+func unmarshalRequest(r *http.Request, body []byte, req otlpRequest) error {
+	if isJSONRequest(r) {
+		return req.UnmarshalJSON(body)
+	}
+	return req.UnmarshalProto(body)
+}
+
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBody(t *testing.T) {
+	payload := []byte("otlp payload")
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = gz.Write(payload)
+	_ = gz.Close()
+
+	zw, _ := zstd.NewWriter(nil)
+	zstdCompressed := zw.EncodeAll(payload, nil)
+	_ = zw.Close()
+
+	snappyCompressed := snappy.Encode(nil, payload)
+
+	for name, tc := range map[string]struct {
+		body     []byte
+		encoding string
+		limits   Limits
+		status   int
+	}{
+		"identity":          {body: payload},
+		"explicit identity": {body: payload, encoding: "identity"},
+		"gzip":              {body: gzipped.Bytes(), encoding: "gzip"},
+		"sniffed gzip":      {body: gzipped.Bytes()},
+		"zstd":              {body: zstdCompressed, encoding: "zstd"},
+		"sniffed zstd":      {body: zstdCompressed},
+		"snappy":            {body: snappyCompressed, encoding: "snappy"},
+		"invalid snappy":    {body: payload, encoding: "snappy", status: http.StatusBadRequest},
+		"invalid gzip":      {body: payload, encoding: "gzip", status: http.StatusBadRequest},
+		"unsupported":       {body: payload, encoding: "br", status: http.StatusUnsupportedMediaType},
+		"within limits":     {body: gzipped.Bytes(), limits: Limits{MaxRequestBytes: 1024, MaxDecompressedBytes: len(payload)}},
+		"request too large": {body: payload, limits: Limits{MaxRequestBytes: 4}, status: http.StatusRequestEntityTooLarge},
+		"gzip bomb":         {body: gzipped.Bytes(), limits: Limits{MaxDecompressedBytes: 4}, status: http.StatusRequestEntityTooLarge},
+		"zstd bomb":         {body: zstdCompressed, limits: Limits{MaxDecompressedBytes: 4}, status: http.StatusRequestEntityTooLarge},
+		"snappy bomb":       {body: snappyCompressed, encoding: "snappy", limits: Limits{MaxDecompressedBytes: 4}, status: http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/otel/v1/traces", bytes.NewReader(tc.body))
+			if tc.encoding != "" {
+				r.Header.Set("Content-Encoding", tc.encoding)
+			}
+			body, release, err := getBody(httptest.NewRecorder(), r, tc.limits)
+			if tc.status != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tc.status, getBodyErrorStatus(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, payload, body)
+			release()
+		})
+	}
+}
+
+func TestIsJSONRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/otel/v1/logs", nil)
+	assert.False(t, isJSONRequest(r))
+
+	r.Header.Set("Content-Type", "application/x-protobuf")
+	assert.False(t, isJSONRequest(r))
+
+	r.Header.Set("Content-Type", "application/json")
+	assert.True(t, isJSONRequest(r))
+}
@@ -23,10 +23,14 @@ import (
 var ExternalHighlightData = e.New("dropping otel data from external highlight instance")
 var fluentProjectPattern = regexp.MustCompile(fmt.Sprintf(`%s=([\S]+)`, highlight.ProjectIDAttribute))
 
+// BodyTypeAttribute records the original type of a log body that was not a string, ie. `Map`.
+const BodyTypeAttribute = "highlight.body_type"
+
 // Extracted fields
 type extractedFields struct {
 	projectID      string
 	projectIDInt   int
+	ingestKey      string
 	sessionID      string
 	requestID      string
 	logBody        string
@@ -37,8 +41,9 @@ type extractedFields struct {
 
 	timestamp time.Time
 
-	logSeverity string
-	logMessage  string
+	logSeverity       string
+	logSeverityNumber plog.SeverityNumber
+	logMessage        string
 
 	exceptionType       string
 	exceptionMessage    string
@@ -69,17 +74,20 @@ func newExtractedFields() *extractedFields {
 }
 
 type extractFieldsParams struct {
-	resource  *pcommon.Resource
+	resource *pcommon.Resource
+	// instrumentation scope of the spans or logs
+	scope     *pcommon.InstrumentationScope
 	span      *ptrace.Span
 	event     *ptrace.SpanEvent
-	scopeLogs *plog.ScopeLogs
 	logRecord *plog.LogRecord
+	// attributes of a metric datapoint
+	dataPointAttributes *pcommon.Map
 }
 
 func extractFields(ctx context.Context, params extractFieldsParams) (*extractedFields, error) {
 	fields := newExtractedFields()
 
-	var resourceAttributes, spanAttributes, eventAttributes, scopeAttributes, logAttributes map[string]any
+	var resourceAttributes, spanAttributes, eventAttributes, scopeAttributes, bodyAttributes, logAttributes, dataPointAttributes map[string]any
 	if params.resource != nil {
 		resourceAttributes = params.resource.Attributes().AsRaw()
 	}
@@ -119,18 +127,19 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 		fields.timestamp = params.event.Timestamp().AsTime()
 	}
 
-	if params.scopeLogs != nil {
-		scopeAttributes = params.scopeLogs.Scope().Attributes().AsRaw()
+	if params.scope != nil {
+		scopeAttributes = params.scope.Attributes().AsRaw()
 	}
 
 	if params.logRecord != nil {
 		fields.timestamp = params.logRecord.Timestamp().AsTime()
 		fields.logSeverity = params.logRecord.SeverityText()
+		fields.logSeverityNumber = params.logRecord.SeverityNumber()
 		logAttributes = params.logRecord.Attributes().AsRaw()
 		// this could be a log record from syslog, with a projectID token prefix. ie:
 		// 1jdkoe52 <1>1 2023-07-27T05:43:22.401882Z render render-log-endpoint-test 1 render-log-endpoint-test - Render test log
-		fields.logBody = params.logRecord.Body().Str()
-		if len(fields.logBody) > 0 {
+		fields.logBody, bodyAttributes = extractBody(params.logRecord.Body())
+		if len(fields.logBody) > 0 && params.logRecord.Body().Type() == pcommon.ValueTypeStr {
 			if fields.logBody[0] != '<' {
 				parts := strings.SplitN(fields.logBody, " <", 2)
 				if len(parts) == 2 {
@@ -141,34 +150,28 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 		}
 	}
 
-	originalAttrs := mergeMaps(
-		resourceAttributes,
-		spanAttributes,
-		eventAttributes,
-		scopeAttributes,
-		logAttributes,
-	)
-
-	if val, ok := originalAttrs[highlight.DeprecatedSourceAttribute]; ok {
-		if val == modelInputs.LogSourceFrontend.String() {
-			fields.source = modelInputs.LogSourceFrontend
-		}
-		delete(originalAttrs, highlight.DeprecatedSourceAttribute)
+	if params.dataPointAttributes != nil {
+		dataPointAttributes = params.dataPointAttributes.AsRaw()
 	}
 
-	if val, ok := originalAttrs[highlight.SourceAttribute]; ok {
+	// attributes are merged in increasing order of precedence so that the most specific value wins
+	// when they disagree, ie. the highlight.project_id of an event over that of its span, scope or resource.
+	// each level is flattened first so that nested and dotted keys of the same attribute are merged consistently.
+	fields.attrs = mergeMaps(
+		formatAttributes(ctx, resourceAttributes),
+		formatAttributes(ctx, scopeAttributes),
+		formatAttributes(ctx, spanAttributes),
+		formatAttributes(ctx, bodyAttributes),
+		formatAttributes(ctx, logAttributes),
+		formatAttributes(ctx, eventAttributes),
+		formatAttributes(ctx, dataPointAttributes),
+	)
+
+	if val, ok := fields.attrs[highlight.SourceAttribute]; ok {
 		if val == modelInputs.LogSourceFrontend.String() {
 			fields.source = modelInputs.LogSourceFrontend
 		}
-		delete(originalAttrs, highlight.SourceAttribute)
-	}
-
-	for k, v := range originalAttrs {
-		for key, value := range hlog.FormatLogAttributes(ctx, k, v) {
-			if v != "" {
-				fields.attrs[key] = value
-			}
-		}
+		delete(fields.attrs, highlight.SourceAttribute)
 	}
 
 	// process potential syslog message
@@ -176,25 +179,20 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 		extractSyslog(fields)
 	}
 	// process potential systemd message
-	if params.logRecord != nil && params.logRecord.Body().Type().String() == "Map" {
-		if m := params.logRecord.Body().Map().AsRaw(); len(m) > 0 {
+	if params.logRecord != nil && params.logRecord.Body().Type() == pcommon.ValueTypeMap {
+		if m := params.logRecord.Body().Map().AsRaw(); isSystemd(m) {
 			extractSystemd(fields, m)
 		}
 	}
 
-	if val, ok := fields.attrs[highlight.DeprecatedProjectIDAttribute]; ok {
-		fields.projectID = val
-		delete(fields.attrs, highlight.DeprecatedProjectIDAttribute)
-	}
-
 	if val, ok := fields.attrs[highlight.ProjectIDAttribute]; ok {
 		fields.projectID = val
 		delete(fields.attrs, highlight.ProjectIDAttribute)
 	}
 
-	if val, ok := fields.attrs[highlight.DeprecatedSessionIDAttribute]; ok {
-		fields.sessionID = val
-		delete(fields.attrs, highlight.DeprecatedSessionIDAttribute)
+	if val, ok := fields.attrs[IngestKeyAttribute]; ok {
+		fields.ingestKey = val
+		delete(fields.attrs, IngestKeyAttribute)
 	}
 
 	if val, ok := fields.attrs[highlight.SessionIDAttribute]; ok {
@@ -231,12 +229,12 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 	}
 
 	if val, ok := eventAttributes[string(semconv.ExceptionTypeKey)]; ok { // we know that exception.type will be in the event attributes map
-		fields.exceptionType = val.(string)
+		fields.exceptionType = attributeString(val)
 		delete(fields.attrs, string(semconv.ExceptionTypeKey))
 	}
 
 	if val, ok := eventAttributes[string(semconv.ExceptionMessageKey)]; ok { // we know that exception.message will be in the event attributes map
-		fields.exceptionMessage = val.(string)
+		fields.exceptionMessage = attributeString(val)
 		delete(fields.attrs, string(semconv.ExceptionMessageKey))
 		// if this is a log that is emitted from an error,
 		// we should use the error text as the log body
@@ -246,12 +244,12 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 	}
 
 	if val, ok := eventAttributes[string(semconv.ExceptionStacktraceKey)]; ok { // we know that exception.stacktrace will be in the event attributes map
-		fields.exceptionStackTrace = val.(string)
+		fields.exceptionStackTrace = attributeString(val)
 		delete(fields.attrs, string(semconv.ExceptionStacktraceKey))
 	}
 
 	if val, ok := eventAttributes[highlight.ErrorURLAttribute]; ok { // we know that URL will be in the event attributes map
-		fields.errorUrl = val.(string)
+		fields.errorUrl = attributeString(val)
 		delete(fields.attrs, highlight.ErrorURLAttribute)
 	}
 
@@ -299,8 +297,86 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 	return fields, err
 }
 
-func mergeMaps(maps ...map[string]any) map[string]any {
-	merged := make(map[string]any)
+// extractBody returns the log body as a string. Structured bodies are serialized to json
+// and the top-level keys of map bodies are returned to be promoted to log attributes,
+// so that structured logs from otel sdks can be queried by their fields.
+func extractBody(body pcommon.Value) (string, map[string]any) {
+	switch body.Type() {
+	case pcommon.ValueTypeEmpty:
+		return "", nil
+	case pcommon.ValueTypeStr:
+		return body.Str(), nil
+	}
+
+	attributes := map[string]any{BodyTypeAttribute: body.Type().String()}
+	if body.Type() == pcommon.ValueTypeMap {
+		m := body.Map().AsRaw()
+		// systemd journal entries are extracted separately
+		if isSystemd(m) {
+			return "", nil
+		}
+		for k, v := range m {
+			attributes[k] = v
+		}
+	}
+	return body.AsString(), attributes
+}
+
+// deprecatedAttributes maps deprecated highlight attributes to their replacements.
+var deprecatedAttributes = map[string]string{
+	highlight.DeprecatedProjectIDAttribute: highlight.ProjectIDAttribute,
+	highlight.DeprecatedSessionIDAttribute: highlight.SessionIDAttribute,
+	highlight.DeprecatedSourceAttribute:    highlight.SourceAttribute,
+}
+
+// formatAttributes flattens otel attributes to the string values written to the attributes columns.
+// Typed values are formatted, ie. an int highlight.project_id of `1` is extracted as "1", and
+// deprecated highlight attributes are renamed unless their replacement is set at the same level.
+func formatAttributes(ctx context.Context, attrs map[string]any) map[string]string {
+	formatted := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if b, ok := v.(bool); ok {
+			formatted[k] = strconv.FormatBool(b)
+			continue
+		}
+		for key, value := range hlog.FormatLogAttributes(ctx, k, v) {
+			if v != "" {
+				formatted[key] = value
+			}
+		}
+	}
+	for deprecated, replacement := range deprecatedAttributes {
+		if value, ok := formatted[deprecated]; ok {
+			if _, ok := formatted[replacement]; !ok {
+				formatted[replacement] = value
+			}
+			delete(formatted, deprecated)
+		}
+	}
+	return formatted
+}
+
+// attributeString returns the untruncated string value of a typed attribute.
+func attributeString(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// mergeMaps merges maps in order, so that the values of later maps take precedence.
+func mergeMaps[V any](maps ...map[string]V) map[string]V {
+	merged := make(map[string]V)
 
 	for _, m := range maps {
 		for key, value := range m {
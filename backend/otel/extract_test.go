@@ -8,6 +8,7 @@ import (
 	"github.com/highlight/highlight/sdk/highlight-go"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
@@ -344,6 +345,41 @@ func TestExtractFields_TrimLongFields(t *testing.T) {
 	assert.Equal(t, 65536+3, len(fields.attrs["foo"]))
 }
 
+func TestExtractFields_StructuredLogBody(t *testing.T) {
+	resource := newResource(t, map[string]any{})
+
+	logRecord := plog.NewLogRecord()
+	body := logRecord.Body().SetEmptyMap()
+	body.PutStr("msg", "user signed in")
+	body.PutInt("user_id", 42)
+	body.PutEmptyMap("request").PutStr("method", "GET")
+	logRecord.Attributes().PutStr("msg", "overridden")
+
+	fields, err := extractFields(context.TODO(), extractFieldsParams{resource: &resource, logRecord: &logRecord})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"msg":"user signed in","request":{"method":"GET"},"user_id":42}`, fields.logBody)
+	assert.Equal(t, map[string]string{
+		BodyTypeAttribute: "Map",
+		"msg":             "overridden",
+		"user_id":         "42",
+		"request.method":  "GET",
+	}, fields.attrs)
+
+	logRecord = plog.NewLogRecord()
+	logRecord.Body().SetInt(404)
+	fields, err = extractFields(context.TODO(), extractFieldsParams{resource: &resource, logRecord: &logRecord})
+	assert.NoError(t, err)
+	assert.Equal(t, "404", fields.logBody)
+	assert.Equal(t, "Int", fields.attrs[BodyTypeAttribute])
+
+	logRecord = plog.NewLogRecord()
+	logRecord.Body().SetEmptySlice().AppendEmpty().SetStr("a")
+	fields, err = extractFields(context.TODO(), extractFieldsParams{resource: &resource, logRecord: &logRecord})
+	assert.NoError(t, err)
+	assert.Equal(t, `["a"]`, fields.logBody)
+	assert.Equal(t, "Slice", fields.attrs[BodyTypeAttribute])
+}
+
 func TestMergeMaps(t *testing.T) {
 	logAttributes := map[string]any{
 		"foo": "bar",
@@ -359,3 +395,117 @@ func TestMergeMaps(t *testing.T) {
 		"baz": "buzz",
 	}, merged)
 }
+
+func TestExtractFields_AttributePrecedence(t *testing.T) {
+	ctx := context.TODO()
+	resource := newResource(t, map[string]any{highlight.ProjectIDAttribute: "1", highlight.SessionIDAttribute: "resource-session"})
+	scope := pcommon.NewInstrumentationScope()
+	scope.Attributes().PutStr(highlight.ProjectIDAttribute, "2")
+
+	fields, err := extractFields(ctx, extractFieldsParams{resource: &resource, scope: &scope})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fields.projectIDInt)
+	assert.Equal(t, "resource-session", fields.sessionID)
+
+	span := ptrace.NewSpan()
+	span.Attributes().PutStr(highlight.ProjectIDAttribute, "3")
+	fields, err = extractFields(ctx, extractFieldsParams{resource: &resource, scope: &scope, span: &span})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fields.projectIDInt)
+
+	event := ptrace.NewSpanEvent()
+	event.Attributes().PutStr(highlight.ProjectIDAttribute, "4")
+	event.Attributes().PutStr(highlight.SessionIDAttribute, "event-session")
+	fields, err = extractFields(ctx, extractFieldsParams{resource: &resource, scope: &scope, span: &span, event: &event})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fields.projectIDInt)
+	assert.Equal(t, "event-session", fields.sessionID)
+	assert.Equal(t, map[string]string{}, fields.attrs)
+
+	logRecord := plog.NewLogRecord()
+	logRecord.Attributes().PutStr(highlight.ProjectIDAttribute, "5")
+	fields, err = extractFields(ctx, extractFieldsParams{resource: &resource, scope: &scope, logRecord: &logRecord})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, fields.projectIDInt)
+
+	// a deprecated attribute of a more specific level takes precedence over the replacement of a less specific one
+	span = ptrace.NewSpan()
+	span.Attributes().PutStr(highlight.DeprecatedProjectIDAttribute, "6")
+	fields, err = extractFields(ctx, extractFieldsParams{resource: &resource, span: &span})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, fields.projectIDInt)
+	assert.Equal(t, map[string]string{}, fields.attrs)
+}
+
+func TestExtractFields_TypedAttributes(t *testing.T) {
+	ctx := context.TODO()
+	resource := pcommon.NewResource()
+	resource.Attributes().PutInt(highlight.ProjectIDAttribute, 7)
+	resource.Attributes().PutBool("feature.enabled", true)
+	resource.Attributes().PutDouble("sample.rate", 0.5)
+
+	event := ptrace.NewSpanEvent()
+	event.Attributes().PutInt(string(semconv.ExceptionMessageKey), 500)
+	event.Attributes().PutStr(string(semconv.ExceptionTypeKey), "HTTPError")
+
+	fields, err := extractFields(ctx, extractFieldsParams{resource: &resource, event: &event})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, fields.projectIDInt)
+	assert.Equal(t, "500", fields.exceptionMessage)
+	assert.Equal(t, "HTTPError", fields.exceptionType)
+	assert.Equal(t, map[string]string{
+		"feature.enabled": "true",
+		"sample.rate":     "0.5",
+	}, fields.attrs)
+}
+
+func TestExtractFields_MixedProjectBatch(t *testing.T) {
+	traces := ptrace.NewTraces()
+
+	first := traces.ResourceSpans().AppendEmpty()
+	first.Resource().Attributes().PutStr(highlight.ProjectIDAttribute, "1")
+	spans := first.ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetName("resource-project")
+	override := spans.AppendEmpty()
+	override.SetName("span-project")
+	override.Attributes().PutInt(highlight.ProjectIDAttribute, 2)
+
+	second := traces.ResourceSpans().AppendEmpty()
+	second.Resource().Attributes().PutInt(highlight.ProjectIDAttribute, 3)
+	scopeSpans := second.ScopeSpans().AppendEmpty()
+	scopeSpans.Scope().Attributes().PutStr(highlight.ProjectIDAttribute, "4")
+	scopeSpans.Spans().AppendEmpty().SetName("scope-project")
+	withEvent := scopeSpans.Spans().AppendEmpty()
+	withEvent.SetName("event-project")
+	withEvent.Events().AppendEmpty().Attributes().PutStr(highlight.ProjectIDAttribute, "5")
+
+	spanProjects := map[string]int{}
+	eventProjects := map[string]int{}
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		resource := traces.ResourceSpans().At(i).Resource()
+		for j := 0; j < traces.ResourceSpans().At(i).ScopeSpans().Len(); j++ {
+			scope := traces.ResourceSpans().At(i).ScopeSpans().At(j).Scope()
+			spans := traces.ResourceSpans().At(i).ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				fields, err := extractFields(context.TODO(), extractFieldsParams{resource: &resource, scope: &scope, span: &span})
+				assert.NoError(t, err)
+				spanProjects[span.Name()] = fields.projectIDInt
+				for l := 0; l < span.Events().Len(); l++ {
+					event := span.Events().At(l)
+					fields, err := extractFields(context.TODO(), extractFieldsParams{resource: &resource, scope: &scope, span: &span, event: &event})
+					assert.NoError(t, err)
+					eventProjects[span.Name()] = fields.projectIDInt
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]int{
+		"resource-project": 1,
+		"span-project":     2,
+		"scope-project":    4,
+		"event-project":    4,
+	}, spanProjects)
+	assert.Equal(t, map[string]int{"event-project": 5}, eventProjects)
+}
@@ -0,0 +1,229 @@
+package otel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// FluentLogsPath receives the records of the Fluent Bit http output and the Fluentd out_http plugin,
+// so that fluent pipelines ship logs without a collector.
+const FluentLogsPath = "/integrations/fluent/logs"
+
+// FluentTagHeader is the header that the tag of the records is sent as, ie. the `header_tag` of the Fluent Bit http output.
+const FluentTagHeader = "x-fluent-tag"
+
+// FluentTagAttribute records the fluent tag of a log.
+const FluentTagAttribute = "fluent.tag"
+
+// record keys read into the fields of a log, in order of precedence
+var (
+	fluentTimeKeys     = []string{"date", "time", "timestamp"}
+	fluentMessageKeys  = []string{"log", "message", "msg"}
+	fluentSeverityKeys = []string{"level", "severity", highlight.LogSeverityAttribute}
+	fluentProjectKeys  = []string{highlight.ProjectIDAttribute, "project_id"}
+	fluentServiceKeys  = []string{string(semconv.ServiceNameKey), "service_name"}
+)
+
+// fluentRecord is a record of a fluent batch. Batches are either the records themselves, with the time
+// in the record, or forward protocol style entries of a tag, time and record.
+type fluentRecord struct {
+	Tag    string         `json:"tag"`
+	Time   any            `json:"time"`
+	Record map[string]any `json:"record"`
+}
+
+// parseFluentRecords decodes a json array, a single json object or json lines of records.
+func parseFluentRecords(body []byte) ([]*fluentRecord, error) {
+	body = bytes.TrimSpace(body)
+	var entries []map[string]any
+	if bytes.HasPrefix(body, []byte("[")) {
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return nil, e.Wrap(err, "invalid fluent records")
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(nil, len(body)+1)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var entry map[string]any
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return nil, e.Wrap(err, "invalid fluent record")
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	records := make([]*fluentRecord, 0, len(entries))
+	for _, entry := range entries {
+		if record, ok := entry["record"].(map[string]any); ok {
+			tag, _ := entry["tag"].(string)
+			records = append(records, &fluentRecord{Tag: tag, Time: entry["time"], Record: record})
+			continue
+		}
+		record := &fluentRecord{Record: entry}
+		for _, key := range fluentTimeKeys {
+			if t, ok := entry[key]; ok {
+				record.Time = t
+				delete(entry, key)
+				break
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseFluentTime parses the time of a record, which is a unix timestamp in (fractional) seconds
+// or an RFC3339 string.
+func parseFluentTime(t any) (time.Time, bool) {
+	switch value := t.(type) {
+	case float64:
+		sec, frac := math.Modf(value)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return parsed, true
+		}
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return parseFluentTime(f)
+		}
+	}
+	return time.Time{}, false
+}
+
+// popFluentKey removes the first of the keys set on a record, returning its value.
+func popFluentKey(record map[string]any, keys []string) string {
+	for _, key := range keys {
+		if value, ok := record[key]; ok {
+			if str := attributeString(value); str != "" {
+				delete(record, key)
+				return str
+			}
+		}
+	}
+	return ""
+}
+
+// getFluentLogFields maps a fluent record onto the fields of a log row. The project is the header
+// project, or the record one when it is unset.
+func getFluentLogFields(ctx context.Context, record *fluentRecord, projectID string) (*extractedFields, error) {
+	fields := newExtractedFields()
+	fields.timestamp = time.Now()
+	if t, ok := parseFluentTime(record.Time); ok {
+		fields.timestamp = t
+	}
+
+	fields.projectID = popFluentKey(record.Record, fluentProjectKeys)
+	if projectID != "" {
+		fields.projectID = projectID
+	}
+	if fields.projectID == "" {
+		return fields, errMissingProject
+	}
+	projectIDInt, err := projectToInt(fields.projectID)
+	if err != nil {
+		return fields, err
+	}
+	fields.projectIDInt = projectIDInt
+	fields.projectID = strconv.Itoa(projectIDInt)
+
+	fields.ingestKey = popFluentKey(record.Record, []string{IngestKeyAttribute})
+	fields.logBody = popFluentKey(record.Record, fluentMessageKeys)
+	fields.logSeverity = popFluentKey(record.Record, fluentSeverityKeys)
+	fields.serviceName = popFluentKey(record.Record, fluentServiceKeys)
+	fields.sessionID = popFluentKey(record.Record, []string{highlight.SessionIDAttribute})
+	fields.attrs = formatAttributes(ctx, record.Record)
+	if record.Tag != "" {
+		fields.attrs[FluentTagAttribute] = record.Tag
+	}
+	return fields, nil
+}
+
+// HandleFluent writes the records of a fluent batch to the project of the x-highlight-project header,
+// or of the highlight.project_id (or project_id) key of each record. The message of a record is its
+// log, message or msg key and its other keys are the attributes of the log.
+func (o *Handler) HandleFluent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid fluent body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	records, err := parseFluentRecords(body)
+	payloadBytes.add(float64(len(body)), signalLogs)
+	// fluent redelivers the same payload when a batch is retried
+	recordIDs := payloadDedupeIDs(body, len(records))
+	release()
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid fluent payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag := r.Header.Get(FluentTagHeader)
+	projectID := r.Header.Get(ratelimit.ProjectHeader)
+	auth := o.newIngestAuthorizer(ctx)
+	rejected := &rejection{}
+	logs := make([]*extractedFields, 0, len(records))
+	dedupeIDs := make([]string, 0, len(records))
+	for i, record := range records {
+		if record.Tag == "" {
+			record.Tag = tag
+		}
+		fields, err := getFluentLogFields(ctx, record, projectID)
+		if err != nil {
+			rejected.add(err)
+			continue
+		}
+		if err := auth.authorize(ctx, fields); err != nil {
+			lg(ctx, fields).WithError(err).Info("unauthorized fluent log")
+			rejected.add(err)
+			continue
+		}
+		logs = append(logs, fields)
+		dedupeIDs = append(dedupeIDs, recordIDs[i])
+	}
+
+	err = o.submitDrainLogs(ctx, "fluent", logs, dedupeIDs)
+	recordSubmission(logsReceived, signalLogs, len(records), rejected, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit fluent logs")
+		writeSubmitError(w, err)
+		return
+	}
+	// fluent retries batches on errors, which would not accept the rejected records either
+	if len(logs) == 0 && rejected.count > 0 {
+		status := http.StatusBadRequest
+		if e.Is(rejected.err, errInvalidIngestKey) || e.Is(rejected.err, errIngestKeyProject) || e.Is(rejected.err, errIngestKeyRequired) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, rejected.message(), status)
+		return
+	}
+	if rejected.partial() {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(rejected.message()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFluentRecords(t *testing.T) {
+	for _, body := range []string{
+		`[{"date":1700000000.5,"log":"hello","level":"warn"},{"tag":"app.web","time":"2023-11-14T22:13:20Z","record":{"message":"world"}}]`,
+		"{\"date\":1700000000.5,\"log\":\"hello\",\"level\":\"warn\"}\n\n{\"tag\":\"app.web\",\"time\":\"2023-11-14T22:13:20Z\",\"record\":{\"message\":\"world\"}}\n",
+	} {
+		records, err := parseFluentRecords([]byte(body))
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, 1700000000.5, records[0].Time)
+		assert.Equal(t, map[string]any{"log": "hello", "level": "warn"}, records[0].Record)
+		assert.Equal(t, "app.web", records[1].Tag)
+		assert.Equal(t, map[string]any{"message": "world"}, records[1].Record)
+	}
+
+	_, err := parseFluentRecords([]byte(`[{"log":`))
+	assert.Error(t, err)
+}
+
+func TestParseFluentTime(t *testing.T) {
+	ts, ok := parseFluentTime(1700000000.5)
+	assert.True(t, ok)
+	assert.Equal(t, time.Unix(1700000000, 5e8), ts)
+
+	ts, ok = parseFluentTime("2023-11-14T22:13:20Z")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1700000000), ts.Unix())
+
+	_, ok = parseFluentTime(nil)
+	assert.False(t, ok)
+}
+
+func TestGetFluentLogFields(t *testing.T) {
+	ctx := context.Background()
+	record := &fluentRecord{Tag: "app.web", Time: 1700000000.0, Record: map[string]any{
+		"highlight.project_id": "2",
+		"highlight.key":        "secret-2",
+		"log":                  "hello",
+		"level":                "warn",
+		"service_name":         "web",
+		"kubernetes":           map[string]any{"pod_name": "web-1"},
+	}}
+	fields, err := getFluentLogFields(ctx, record, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fields.projectIDInt)
+	assert.Equal(t, "secret-2", fields.ingestKey)
+	assert.Equal(t, "hello", fields.logBody)
+	assert.Equal(t, "warn", fields.logSeverity)
+	assert.Equal(t, "web", fields.serviceName)
+	assert.Equal(t, int64(1700000000), fields.timestamp.Unix())
+	assert.Equal(t, "app.web", fields.attrs[FluentTagAttribute])
+	assert.Equal(t, "web-1", fields.attrs["kubernetes.pod_name"])
+	assert.NotContains(t, fields.attrs, "highlight.key")
+
+	fields, err = getFluentLogFields(ctx, &fluentRecord{Record: map[string]any{"project_id": "2", "log": "hello"}}, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fields.projectIDInt)
+
+	_, err = getFluentLogFields(ctx, &fluentRecord{Record: map[string]any{"log": "hello"}}, "")
+	assert.ErrorIs(t, err, errMissingProject)
+}
+
+func TestHandler_HandleFluent(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+
+	for _, tc := range []struct {
+		name    string
+		project string
+		body    string
+		code    int
+	}{
+		{"invalid json", "1", `[{"log":`, http.StatusBadRequest},
+		{"missing project", "", `[{"log":"hello"}]`, http.StatusBadRequest},
+		{"requires key", "2", `[{"log":"hello"}]`, http.StatusUnauthorized},
+		{"key of other project", "", `[{"log":"hello","highlight.project_id":"2","highlight.key":"secret-1"}]`, http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, FluentLogsPath, strings.NewReader(tc.body))
+			r.Header.Set("x-highlight-project", tc.project)
+			w := httptest.NewRecorder()
+			h.HandleFluent(w, r)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package otel
+
+import (
+	"context"
+	"net"
+	"os"
+
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	GRPCPortEnvVar  = "OTLP_GRPC_PORT"
+	DefaultGRPCPort = "4317"
+	// matches the http body limit of the public graph
+	maxGRPCMessageBytes = 128 * 1024 * 1024
+)
+
+type grpcTraceServer struct {
+	handler *Handler
+}
+
+func (s *grpcTraceServer) Export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	resp := ptraceotlp.NewExportResponse()
+	rejected, err := s.handler.submitTraces(ctx, req.Traces())
+	recordSubmission(spansReceived, signalTraces, req.Traces().SpanCount(), rejected, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit otel grpc traces")
+		return resp, grpcSubmitError(err)
+	}
+	if rejected.partial() {
+		resp.PartialSuccess().SetRejectedSpans(rejected.count)
+		resp.PartialSuccess().SetErrorMessage(rejected.message())
+	}
+	return resp, nil
+}
+
+type grpcLogServer struct {
+	handler *Handler
+}
+
+func (s *grpcLogServer) Export(ctx context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	resp := plogotlp.NewExportResponse()
+	rejected, err := s.handler.submitLogs(ctx, req.Logs())
+	recordSubmission(logsReceived, signalLogs, req.Logs().LogRecordCount(), rejected, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit otel grpc logs")
+		return resp, grpcSubmitError(err)
+	}
+	if rejected.partial() {
+		resp.PartialSuccess().SetRejectedLogRecords(rejected.count)
+		resp.PartialSuccess().SetErrorMessage(rejected.message())
+	}
+	return resp, nil
+}
+
+type grpcMetricServer struct {
+	handler *Handler
+}
+
+func (s *grpcMetricServer) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	resp := pmetricotlp.NewExportResponse()
+	rejected, err := s.handler.submitMetrics(ctx, req.Metrics())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit otel grpc metrics")
+		return resp, grpcSubmitError(err)
+	}
+	if rejected.partial() {
+		resp.PartialSuccess().SetRejectedDataPoints(rejected.count)
+		resp.PartialSuccess().SetErrorMessage(rejected.message())
+	}
+	return resp, nil
+}
+
+// NewGRPCServer returns a server implementing the OTLP trace, logs and metrics services,
+// feeding the same queues as the http routes.
+func (o *Handler) NewGRPCServer() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxGRPCMessageBytes),
+		grpc.UnaryInterceptor(o.grpcAuthInterceptor),
+	)
+	ptraceotlp.RegisterGRPCServer(server, &grpcTraceServer{handler: o})
+	plogotlp.RegisterGRPCServer(server, &grpcLogServer{handler: o})
+	pmetricotlp.RegisterGRPCServer(server, &grpcMetricServer{handler: o})
+	return server
+}
+
+// ListenGRPC serves the OTLP grpc services on OTLP_GRPC_PORT, or the standard OTLP port 4317.
+func (o *Handler) ListenGRPC(ctx context.Context) error {
+	port := os.Getenv(GRPCPortEnvVar)
+	if port == "" {
+		port = DefaultGRPCPort
+	}
+
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return e.Wrapf(err, "failed to listen on otlp grpc port %s", port)
+	}
+
+	server := o.NewGRPCServer()
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	log.WithContext(ctx).Infof("serving otlp grpc on port %s", port)
+	return server.Serve(lis)
+}
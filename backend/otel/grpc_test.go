@@ -0,0 +1,43 @@
+package otel
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	public "github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	ctx := context.Background()
+	producer := MockKafkaProducer{}
+	h := Handler{resolver: &public.Resolver{BatchedQueue: &producer}}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := h.NewGRPCServer()
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := pmetricotlp.NewGRPCClient(conn).Export(ctx, pmetricotlp.NewExportRequestFromMetrics(newTestMetrics()))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.PartialSuccess().RejectedDataPoints())
+
+	assert.Len(t, producer.messages, 1)
+	assert.Equal(t, kafkaqueue.PushOTeLMetrics, producer.messages[0].Type)
+}
@@ -0,0 +1,188 @@
+package otel
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	hlog "github.com/highlight/highlight/sdk/highlight-go/log"
+	e "github.com/pkg/errors"
+)
+
+const (
+	MaxAttributesEnvVar          = "OTEL_MAX_ATTRIBUTES"
+	MaxAttributeKeyBytesEnvVar   = "OTEL_MAX_ATTRIBUTE_KEY_BYTES"
+	MaxAttributeValueBytesEnvVar = "OTEL_MAX_ATTRIBUTE_VALUE_BYTES"
+	MaxBodyBytesEnvVar           = "OTEL_MAX_BODY_BYTES"
+	MaxRequestBytesEnvVar        = "OTEL_MAX_REQUEST_BYTES"
+	MaxDecompressedBytesEnvVar   = "OTEL_MAX_DECOMPRESSED_BYTES"
+	MaxLogBatchRowsEnvVar        = "OTEL_MAX_LOG_BATCH_ROWS"
+	MaxLogBatchBytesEnvVar       = "OTEL_MAX_LOG_BATCH_BYTES"
+)
+
+// logRowOverheadBytes approximates the serialized size of the fixed fields of a log message.
+const logRowOverheadBytes = 512
+
+// TruncatedAttribute lists what was truncated on a span or log, ie. `attribute_count,body`.
+const TruncatedAttribute = "highlight.truncated"
+
+const (
+	truncatedAttributeCount = "attribute_count"
+	truncatedAttributeKey   = "attribute_key"
+	truncatedAttributeValue = "attribute_value"
+	truncatedBody           = "body"
+)
+
+// Limits caps the size of export requests and the attributes and bodies of spans and logs
+// before they are written. A zero limit disables the cap.
+type Limits struct {
+	MaxAttributes          int
+	MaxAttributeKeyBytes   int
+	MaxAttributeValueBytes int
+	MaxBodyBytes           int
+	// MaxRequestBytes caps the export request body as sent, ie. compressed.
+	MaxRequestBytes int
+	// MaxDecompressedBytes caps the export request body once decompressed.
+	MaxDecompressedBytes int
+	// MaxLogBatchRows and MaxLogBatchBytes cap the logs of a project submitted to kafka at once,
+	// so that the logs of a large export request are split across several submissions.
+	MaxLogBatchRows  int
+	MaxLogBatchBytes int
+}
+
+var DefaultLimits = Limits{
+	MaxAttributes:          256,
+	MaxAttributeKeyBytes:   256,
+	MaxAttributeValueBytes: hlog.LogAttributeValueLengthLimit,
+	MaxBodyBytes:           hlog.LogAttributeValueLengthLimit,
+	MaxRequestBytes:        64 << 20,
+	MaxDecompressedBytes:   256 << 20,
+	MaxLogBatchRows:        1_000,
+	MaxLogBatchBytes:       16 << 20,
+}
+
+// LoadLimits returns the default limits with any overrides from the environment applied.
+func LoadLimits() (Limits, error) {
+	limits := DefaultLimits
+	for envVar, limit := range map[string]*int{
+		MaxAttributesEnvVar:          &limits.MaxAttributes,
+		MaxAttributeKeyBytesEnvVar:   &limits.MaxAttributeKeyBytes,
+		MaxAttributeValueBytesEnvVar: &limits.MaxAttributeValueBytes,
+		MaxBodyBytesEnvVar:           &limits.MaxBodyBytes,
+		MaxRequestBytesEnvVar:        &limits.MaxRequestBytes,
+		MaxDecompressedBytesEnvVar:   &limits.MaxDecompressedBytes,
+		MaxLogBatchRowsEnvVar:        &limits.MaxLogBatchRows,
+		MaxLogBatchBytesEnvVar:       &limits.MaxLogBatchBytes,
+	} {
+		value := os.Getenv(envVar)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil || v < 0 {
+			return DefaultLimits, e.Errorf("invalid %s %q", envVar, value)
+		}
+		*limit = v
+	}
+	return limits, nil
+}
+
+// apply truncates the extracted fields to the limits, marking them with TruncatedAttribute.
+// Attributes over the count limit are dropped in key order so that the kept ones are stable.
+func (l Limits) apply(fields *extractedFields) {
+	truncated := map[string]bool{}
+
+	var keys []string
+	for k := range fields.attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make(map[string]string, len(fields.attrs))
+	for _, k := range keys {
+		if l.MaxAttributes > 0 && len(attrs) >= l.MaxAttributes {
+			truncated[truncatedAttributeCount] = true
+			break
+		}
+		v := fields.attrs[k]
+		if key, ok := truncate(k, l.MaxAttributeKeyBytes); ok {
+			truncated[truncatedAttributeKey] = true
+			k = key
+		}
+		if value, ok := truncate(v, l.MaxAttributeValueBytes); ok {
+			truncated[truncatedAttributeValue] = true
+			v = value
+		}
+		attrs[k] = v
+	}
+
+	for _, body := range []*string{&fields.logBody, &fields.logMessage, &fields.exceptionMessage} {
+		if value, ok := truncate(*body, l.MaxBodyBytes); ok {
+			truncated[truncatedBody] = true
+			*body = value
+		}
+	}
+
+	if len(truncated) > 0 {
+		var reasons []string
+		for reason := range truncated {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		attrs[TruncatedAttribute] = strings.Join(reasons, ",")
+	}
+	fields.attrs = attrs
+}
+
+// truncate cuts s to at most limit bytes without splitting a utf-8 character.
+func truncate(s string, limit int) (string, bool) {
+	if limit <= 0 || len(s) <= limit {
+		return s, false
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit], true
+}
+
+// chunkLogs splits log messages into batches of at most MaxLogBatchRows rows and MaxLogBatchBytes
+// estimated bytes. A log larger than MaxLogBatchBytes is batched on its own.
+func (l Limits) chunkLogs(messages []*kafkaqueue.Message) [][]*kafkaqueue.Message {
+	var chunks [][]*kafkaqueue.Message
+	var chunk []*kafkaqueue.Message
+	var chunkBytes int
+	for _, msg := range messages {
+		size := logRowBytes(msg.PushLogs.LogRow)
+		if len(chunk) > 0 && ((l.MaxLogBatchRows > 0 && len(chunk) >= l.MaxLogBatchRows) ||
+			(l.MaxLogBatchBytes > 0 && chunkBytes+size > l.MaxLogBatchBytes)) {
+			chunks = append(chunks, chunk)
+			chunk, chunkBytes = nil, 0
+		}
+		chunk = append(chunk, msg)
+		chunkBytes += size
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// logRowBytes estimates the serialized size of a log row.
+func logRowBytes(logRow *clickhouse.LogRow) int {
+	size := logRowOverheadBytes
+	for _, value := range []string{
+		logRow.TraceId, logRow.SpanId, logRow.SecureSessionId, logRow.UUID, logRow.SeverityText,
+		logRow.ServiceName, logRow.ServiceVersion, logRow.Body, logRow.Environment,
+		logRow.K8sPodName, logRow.K8sNamespace, logRow.ContainerId, logRow.HostName,
+	} {
+		size += len(value)
+	}
+	for k, v := range logRow.LogAttributes {
+		size += len(k) + len(v)
+	}
+	return size
+}
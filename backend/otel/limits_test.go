@@ -0,0 +1,77 @@
+package otel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_Apply(t *testing.T) {
+	limits := Limits{MaxAttributes: 2, MaxAttributeKeyBytes: 4, MaxAttributeValueBytes: 5, MaxBodyBytes: 3}
+
+	fields := newExtractedFields()
+	fields.attrs = map[string]string{"a": "1", "bbbbbb": "héllo world", "c": "3"}
+	fields.logBody = "hello"
+	limits.apply(fields)
+
+	assert.Equal(t, map[string]string{
+		"a":                "1",
+		"bbbb":             "héll",
+		TruncatedAttribute: "attribute_count,attribute_key,attribute_value,body",
+	}, fields.attrs)
+	assert.Equal(t, "hel", fields.logBody)
+
+	fields = newExtractedFields()
+	fields.attrs = map[string]string{"a": "1"}
+	fields.logMessage = "ok"
+	limits.apply(fields)
+	assert.Equal(t, map[string]string{"a": "1"}, fields.attrs)
+	assert.Equal(t, "ok", fields.logMessage)
+
+	fields = newExtractedFields()
+	fields.attrs = map[string]string{"a": strings.Repeat("x", 1024)}
+	Limits{}.apply(fields)
+	assert.Len(t, fields.attrs["a"], 1024)
+}
+
+func TestLoadLimits(t *testing.T) {
+	t.Setenv(MaxAttributesEnvVar, "10")
+	limits, err := LoadLimits()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, limits.MaxAttributes)
+	assert.Equal(t, DefaultLimits.MaxBodyBytes, limits.MaxBodyBytes)
+
+	t.Setenv(MaxBodyBytesEnvVar, "-1")
+	_, err = LoadLimits()
+	assert.Error(t, err)
+}
+
+func TestLimits_ChunkLogs(t *testing.T) {
+	newMessage := func(body string) *kafkaqueue.Message {
+		return &kafkaqueue.Message{Type: kafkaqueue.PushLogs, PushLogs: &kafkaqueue.PushLogsArgs{
+			LogRow: &clickhouse.LogRow{Body: body},
+		}}
+	}
+	var messages []*kafkaqueue.Message
+	for i := 0; i < 5; i++ {
+		messages = append(messages, newMessage("log"))
+	}
+
+	assert.Len(t, Limits{}.chunkLogs(messages), 1)
+	assert.Empty(t, Limits{}.chunkLogs(nil))
+
+	chunks := Limits{MaxLogBatchRows: 2}.chunkLogs(messages)
+	assert.Equal(t, []int{2, 2, 1}, []int{len(chunks[0]), len(chunks[1]), len(chunks[2])})
+
+	// a log over the byte limit is batched on its own
+	size := logRowBytes(messages[0].PushLogs.LogRow)
+	large := newMessage(strings.Repeat("x", 4*size))
+	chunks = Limits{MaxLogBatchBytes: 3 * size}.chunkLogs(append([]*kafkaqueue.Message{large}, messages...))
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, []*kafkaqueue.Message{large}, chunks[0])
+	assert.Len(t, chunks[1], 3)
+	assert.Len(t, chunks[2], 2)
+}
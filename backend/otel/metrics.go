@@ -0,0 +1,202 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+func (o *Handler) HandleMetric(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := pmetricotlp.NewExportRequest()
+	if status, err := o.decodeRequest(w, r, signalMetrics, req); err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid metric payload")
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	rejected, err := o.submitMetrics(ctx, req.Metrics())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit otel project metrics")
+		writeSubmitError(w, err)
+		return
+	}
+
+	resp := pmetricotlp.NewExportResponse()
+	if rejected.partial() {
+		resp.PartialSuccess().SetRejectedDataPoints(rejected.count)
+		resp.PartialSuccess().SetErrorMessage(rejected.message())
+	}
+	writeResponse(w, r, resp)
+}
+
+// getProjectMetricRows converts the gauge, sum and histogram datapoints of an export request
+// to metric rows by project. Summaries and exponential histograms are not supported.
+func getProjectMetricRows(ctx context.Context, metrics pmetric.Metrics, auth *ingestAuthorizer) (map[string][]*clickhouse.MetricRow, *rejection) {
+	var projectMetrics = make(map[string][]*clickhouse.MetricRow)
+	rejected := &rejection{}
+
+	resourceMetrics := metrics.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		resource := resourceMetrics.At(i).Resource()
+		scopeMetrics := resourceMetrics.At(i).ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			metrics := scopeMetrics.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+
+				var rows []*projectMetricRow
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					rows = getNumberRows(ctx, &resource, metric, clickhouse.MetricTypeGauge, metric.Gauge().DataPoints(), rejected)
+				case pmetric.MetricTypeSum:
+					rows = getNumberRows(ctx, &resource, metric, clickhouse.MetricTypeSum, metric.Sum().DataPoints(), rejected)
+					for _, row := range rows {
+						row.AggregationTemporality = metric.Sum().AggregationTemporality().String()
+						row.IsMonotonic = metric.Sum().IsMonotonic()
+					}
+				case pmetric.MetricTypeHistogram:
+					rows = getHistogramRows(ctx, &resource, metric, rejected)
+				case pmetric.MetricTypeSummary:
+					rejectUnsupported(ctx, metric, metric.Summary().DataPoints().Len(), rejected)
+				case pmetric.MetricTypeExponentialHistogram:
+					rejectUnsupported(ctx, metric, metric.ExponentialHistogram().DataPoints().Len(), rejected)
+				}
+
+				for _, row := range rows {
+					if err := auth.authorize(ctx, row.fields); err != nil {
+						lg(ctx, row.fields).WithError(err).Info("unauthorized otel metric")
+						rejected.add(err)
+						continue
+					}
+					projectMetrics[row.projectID] = append(projectMetrics[row.projectID], row.MetricRow)
+				}
+			}
+		}
+	}
+
+	return projectMetrics, rejected
+}
+
+func rejectUnsupported(ctx context.Context, metric pmetric.Metric, dataPoints int, rejected *rejection) {
+	log.WithContext(ctx).WithField("metric", metric.Name()).Debugf("otel received unsupported metric type %s", metric.Type())
+	for i := 0; i < dataPoints; i++ {
+		rejected.add(e.Errorf("unsupported metric type %s", metric.Type()))
+	}
+}
+
+type projectMetricRow struct {
+	*clickhouse.MetricRow
+	projectID string
+	fields    *extractedFields
+}
+
+func newMetricRow(ctx context.Context, resource *pcommon.Resource, metric pmetric.Metric, attributes pcommon.Map, start pcommon.Timestamp, ts pcommon.Timestamp, rejected *rejection) *projectMetricRow {
+	fields, err := extractFields(ctx, extractFieldsParams{
+		resource:            resource,
+		dataPointAttributes: &attributes,
+	})
+	if err != nil {
+		lg(ctx, fields).WithError(err).Info("failed to extract fields from metric")
+		rejected.add(err)
+		return nil
+	}
+
+	return &projectMetricRow{
+		projectID: fields.projectID,
+		fields:    fields,
+		MetricRow: &clickhouse.MetricRow{
+			ProjectId:         uint32(fields.projectIDInt),
+			Timestamp:         ts.AsTime(),
+			StartTimestamp:    start.AsTime(),
+			SecureSessionId:   fields.sessionID,
+			ServiceName:       fields.serviceName,
+			ServiceVersion:    fields.serviceVersion,
+			Environment:       fields.environment,
+			MetricName:        metric.Name(),
+			MetricDescription: metric.Description(),
+			MetricUnit:        metric.Unit(),
+			Attributes:        fields.attrs,
+		},
+	}
+}
+
+func getNumberRows(ctx context.Context, resource *pcommon.Resource, metric pmetric.Metric, metricType string, dataPoints pmetric.NumberDataPointSlice, rejected *rejection) []*projectMetricRow {
+	var rows []*projectMetricRow
+	for i := 0; i < dataPoints.Len(); i++ {
+		dp := dataPoints.At(i)
+		row := newMetricRow(ctx, resource, metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), rejected)
+		if row == nil {
+			continue
+		}
+		row.MetricType = metricType
+		switch dp.ValueType() {
+		case pmetric.NumberDataPointValueTypeInt:
+			row.Value = float64(dp.IntValue())
+		case pmetric.NumberDataPointValueTypeDouble:
+			row.Value = dp.DoubleValue()
+		default:
+			rejected.add(e.New("datapoint has no value"))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func getHistogramRows(ctx context.Context, resource *pcommon.Resource, metric pmetric.Metric, rejected *rejection) []*projectMetricRow {
+	var rows []*projectMetricRow
+	dataPoints := metric.Histogram().DataPoints()
+	for i := 0; i < dataPoints.Len(); i++ {
+		dp := dataPoints.At(i)
+		row := newMetricRow(ctx, resource, metric, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), rejected)
+		if row == nil {
+			continue
+		}
+		row.MetricType = clickhouse.MetricTypeHistogram
+		row.AggregationTemporality = metric.Histogram().AggregationTemporality().String()
+		row.Count = dp.Count()
+		if dp.HasSum() {
+			row.Sum = dp.Sum()
+		}
+		if dp.HasMin() {
+			row.Min = dp.Min()
+		}
+		if dp.HasMax() {
+			row.Max = dp.Max()
+		}
+		row.BucketCounts = dp.BucketCounts().AsRaw()
+		row.ExplicitBounds = dp.ExplicitBounds().AsRaw()
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (o *Handler) submitMetrics(ctx context.Context, metrics pmetric.Metrics) (*rejection, error) {
+	projectMetrics, rejected := getProjectMetricRows(ctx, metrics, o.newIngestAuthorizer(ctx))
+	if err := o.submitMetricRows(ctx, projectMetrics); err != nil {
+		return nil, err
+	}
+	return rejected, nil
+}
+
+func (o *Handler) submitMetricRows(ctx context.Context, projectMetrics map[string][]*clickhouse.MetricRow) error {
+	for _, metricRows := range projectMetrics {
+		err := o.submit(ctx, kafkaqueue.TopicTypeBatched, "", &kafkaqueue.Message{
+			Type: kafkaqueue.PushOTeLMetrics,
+			PushOTeLMetrics: &kafkaqueue.PushOTeLMetricsArgs{
+				MetricRows: metricRows,
+			}})
+		if err != nil {
+			return e.Wrap(err, "failed to submit otel project metrics to public worker queue")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	public "github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+func newTestMetrics() pmetric.Metrics {
+	ts := pcommon.NewTimestampFromTime(time.Now())
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(highlight.ProjectIDAttribute, "1")
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("deployment.environment", "production")
+	sm := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := sm.AppendEmpty()
+	gauge.SetName("memory.usage")
+	gauge.SetUnit("By")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(1024)
+	dp.Attributes().PutStr(highlight.SessionIDAttribute, "abc123")
+
+	sum := sm.AppendEmpty()
+	sum.SetName("http.requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(42)
+	dp.Attributes().PutStr("http.route", "/users")
+
+	histogram := sm.AppendEmpty()
+	histogram.SetName("http.duration")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(0.6)
+	hdp.SetMin(0.1)
+	hdp.SetMax(0.3)
+	hdp.BucketCounts().FromRaw([]uint64{1, 2, 0})
+	hdp.ExplicitBounds().FromRaw([]float64{0.1, 0.5})
+
+	summary := sm.AppendEmpty()
+	summary.SetName("unsupported")
+	summary.SetEmptySummary().DataPoints().AppendEmpty().SetTimestamp(ts)
+
+	// datapoints without a project are dropped
+	other := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	other.SetName("no.project")
+	other.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+
+	return metrics
+}
+
+func TestGetProjectMetricRows(t *testing.T) {
+	projectMetrics, rejected := getProjectMetricRows(context.Background(), newTestMetrics(), &ingestAuthorizer{})
+	assert.Len(t, projectMetrics, 1)
+	// the summary and the datapoint without a project
+	assert.Equal(t, int64(2), rejected.count)
+
+	rows := projectMetrics["1"]
+	assert.Len(t, rows, 3)
+
+	gauge := rows[0]
+	assert.Equal(t, uint32(1), gauge.ProjectId)
+	assert.Equal(t, "memory.usage", gauge.MetricName)
+	assert.Equal(t, "By", gauge.MetricUnit)
+	assert.Equal(t, clickhouse.MetricTypeGauge, gauge.MetricType)
+	assert.Equal(t, 1024., gauge.Value)
+	assert.Equal(t, "abc123", gauge.SecureSessionId)
+	assert.Equal(t, "api", gauge.ServiceName)
+	assert.Equal(t, "production", gauge.Environment)
+	assert.NotContains(t, gauge.Attributes, highlight.SessionIDAttribute)
+
+	sum := rows[1]
+	assert.Equal(t, clickhouse.MetricTypeSum, sum.MetricType)
+	assert.Equal(t, 42., sum.Value)
+	assert.True(t, sum.IsMonotonic)
+	assert.Equal(t, "Cumulative", sum.AggregationTemporality)
+	assert.Equal(t, "/users", sum.Attributes["http.route"])
+
+	histogram := rows[2]
+	assert.Equal(t, clickhouse.MetricTypeHistogram, histogram.MetricType)
+	assert.Equal(t, uint64(3), histogram.Count)
+	assert.Equal(t, 0.6, histogram.Sum)
+	assert.Equal(t, 0.1, histogram.Min)
+	assert.Equal(t, 0.3, histogram.Max)
+	assert.Equal(t, []uint64{1, 2, 0}, histogram.BucketCounts)
+	assert.Equal(t, []float64{0.1, 0.5}, histogram.ExplicitBounds)
+}
+
+func TestHandler_HandleMetric(t *testing.T) {
+	body, err := pmetricotlp.NewExportRequestFromMetrics(newTestMetrics()).MarshalProto()
+	assert.NoError(t, err)
+
+	b := bytes.Buffer{}
+	gz := gzip.NewWriter(&b)
+	_, err = gz.Write(body)
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+
+	producer := MockKafkaProducer{}
+	h := Handler{resolver: &public.Resolver{BatchedQueue: &producer}}
+
+	w := httptest.NewRecorder()
+	h.HandleMetric(w, httptest.NewRequest(http.MethodPost, "/otel/v1/metrics", &b))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp := pmetricotlp.NewExportResponse()
+	assert.NoError(t, resp.UnmarshalProto(w.Body.Bytes()))
+	assert.Equal(t, int64(2), resp.PartialSuccess().RejectedDataPoints())
+	assert.Contains(t, resp.PartialSuccess().ErrorMessage(), "rejected 2 items")
+
+	assert.Len(t, producer.messages, 1)
+	assert.Equal(t, kafkaqueue.PushOTeLMetrics, producer.messages[0].Type)
+	assert.Len(t, producer.messages[0].PushOTeLMetrics.MetricRows, 3)
+}
+
+func TestHandler_HandleMetricJSON(t *testing.T) {
+	body, err := pmetricotlp.NewExportRequestFromMetrics(newTestMetrics()).MarshalJSON()
+	assert.NoError(t, err)
+
+	producer := MockKafkaProducer{}
+	h := Handler{resolver: &public.Resolver{BatchedQueue: &producer}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/otel/v1/metrics", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	h.HandleMetric(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	assert.Len(t, producer.messages, 1)
+	assert.Len(t, producer.messages[0].PushOTeLMetrics.MetricRows, 3)
+}
@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"context"
+	"strconv"
+
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// NetworkResourceInitiatorType is the initiator type of the network resources derived from otel spans.
+const NetworkResourceInitiatorType = "otel"
+
+// http attributes of the current semantic conventions, which replace the v1.17.0 ones.
+const (
+	httpRequestMethodAttribute      = "http.request.method"
+	httpResponseStatusCodeAttribute = "http.response.status_code"
+	urlFullAttribute                = "url.full"
+)
+
+// getNetworkResource derives a session network resource from a span of an http request,
+// so that requests observed by otel instrumentation show up in the session replay
+// even when the client did not record them.
+func getNetworkResource(span ptrace.Span, fields *extractedFields) *graph.NetworkResource {
+	if fields.sessionID == "" {
+		return nil
+	}
+	// spans of network requests recorded by the client are already part of the session
+	if fields.attrs[highlight.TraceTypeAttribute] == string(highlight.TraceTypeNetworkRequest) {
+		return nil
+	}
+
+	method := firstAttribute(fields.attrs, string(semconv.HTTPMethodKey), httpRequestMethodAttribute)
+	url := firstAttribute(fields.attrs, string(semconv.HTTPURLKey), urlFullAttribute)
+	if method == "" || url == "" {
+		return nil
+	}
+	status, _ := strconv.ParseFloat(firstAttribute(fields.attrs, string(semconv.HTTPStatusCodeKey), httpResponseStatusCodeAttribute), 64)
+	size, _ := strconv.ParseFloat(fields.attrs[string(semconv.HTTPResponseContentLengthKey)], 64)
+
+	requestID := fields.requestID
+	if requestID == "" {
+		requestID = span.SpanID().String()
+	}
+	return &graph.NetworkResource{
+		StartTimeAbs:   float64(span.StartTimestamp().AsTime().UnixMicro()) / 1000.,
+		ResponseEndAbs: float64(span.EndTimestamp().AsTime().UnixMicro()) / 1000.,
+		InitiatorType:  NetworkResourceInitiatorType,
+		TransferSize:   size,
+		Name:           url,
+		RequestResponsePairs: graph.RequestResponsePairs{
+			Request: graph.Request{
+				ID:     requestID,
+				URL:    url,
+				Method: method,
+			},
+			Response: graph.Response{
+				Status: status,
+				Size:   size,
+			},
+		},
+	}
+}
+
+func firstAttribute(attrs map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if v := attrs[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// submitSessionNetworkResources adds the network resources derived from spans to their sessions.
+func (o *Handler) submitSessionNetworkResources(ctx context.Context, sessionResources map[string][]*graph.NetworkResource) error {
+	for sessionID, resources := range sessionResources {
+		data, err := json.Marshal(map[string][]*graph.NetworkResource{"resources": resources})
+		if err != nil {
+			return e.Wrap(err, "failed to marshal otel session network resources")
+		}
+		if err := o.submit(ctx, kafkaqueue.TopicTypeDefault, sessionID, &kafkaqueue.Message{
+			Type: kafkaqueue.PushSessionNetworkResources,
+			PushSessionNetworkResources: &kafkaqueue.PushSessionNetworkResourcesArgs{
+				SessionSecureID: sessionID,
+				Resources:       string(data),
+			},
+		}); err != nil {
+			return e.Wrap(err, "failed to submit otel session network resources to public worker queue")
+		}
+	}
+	return nil
+}
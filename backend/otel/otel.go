@@ -1,10 +1,7 @@
 package otel
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -14,15 +11,20 @@ import (
 
 	"github.com/samber/lo"
 
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 
 	"github.com/go-chi/chi"
 	"github.com/highlight-run/highlight/backend/clickhouse"
 	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/public-graph/graph"
 	"github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	"github.com/highlight-run/highlight/backend/severity"
 	"github.com/highlight-run/highlight/backend/stacktraces"
 	"github.com/highlight/highlight/sdk/highlight-go"
 	"github.com/openlyinc/pointy"
@@ -32,6 +34,20 @@ import (
 
 type Handler struct {
 	resolver *graph.Resolver
+	// projects authorizes ingestion with project ingest keys. Authorization is skipped when unset.
+	projects projectStore
+	// limiter rejects export requests of projects over their per minute limit
+	limiter *ratelimit.TokenBucket
+	// quotas rejects export requests of projects over their billing limit. Quotas are not enforced when unset.
+	quotas quotaStore
+	// limits caps the attributes and bodies of spans and logs
+	limits Limits
+	// deadLetters keeps messages that failed to submit to kafka. Failed submissions are errors when unset.
+	deadLetters DeadLetterStore
+	// sdkBlocklist rejects or warns about data from SDK versions with known bugs
+	sdkBlocklist SDKBlocklist
+	// clockSkew corrects the timestamps of spans and logs from clients with wrong clocks
+	clockSkew ClockSkew
 }
 
 var IgnoredSpanNamePrefixes = []string{"fs "}
@@ -95,6 +111,21 @@ func getBackendError(ctx context.Context, ts time.Time, fields *extractedFields,
 	}
 }
 
+// getSpanLinks returns the links of a span, so that errors can be traced across the spans they link to.
+func getSpanLinks(span ptrace.Span) []*model.SpanLinkInput {
+	var links []*model.SpanLinkInput
+	for i := 0; i < span.Links().Len(); i++ {
+		link := span.Links().At(i)
+		links = append(links, &model.SpanLinkInput{
+			TraceID:    link.TraceID().String(),
+			SpanID:     link.SpanID().String(),
+			TraceState: pointy.String(link.TraceState().AsRaw()),
+			Attributes: link.Attributes().AsRaw(),
+		})
+	}
+	return links
+}
+
 func getMetric(ctx context.Context, ts time.Time, fields *extractedFields, spanID, parentSpanID, traceID string) (*model.MetricInput, error) {
 	if fields.metricEventName == "" {
 		return nil, e.New("otel received metric with no name")
@@ -128,46 +159,58 @@ func getMetric(ctx context.Context, ts time.Time, fields *extractedFields, spanI
 
 func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid trace logBody")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	gz, err := gzip.NewReader(bytes.NewReader(body))
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip format for trace")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	req := ptraceotlp.NewExportRequest()
+	if status, err := o.decodeRequest(w, r, signalTraces, req); err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid trace payload")
+		http.Error(w, err.Error(), status)
 		return
 	}
 
-	output, err := io.ReadAll(gz)
+	rejected, err := o.submitTraces(ctx, req.Traces())
+	recordSubmission(spansReceived, signalTraces, req.Traces().SpanCount(), rejected, err)
 	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip stream for trace")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.WithContext(ctx).WithError(err).Error("failed to submit otel traces")
+		writeSubmitError(w, err)
 		return
 	}
 
-	req := ptraceotlp.NewExportRequest()
-	err = req.UnmarshalProto(output)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid trace protobuf")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	resp := ptraceotlp.NewExportResponse()
+	if rejected.partial() {
+		resp.PartialSuccess().SetRejectedSpans(rejected.count)
+		resp.PartialSuccess().SetErrorMessage(rejected.message())
 	}
+	writeResponse(w, r, resp)
+}
 
+// submitTraces queues the spans of an export request, along with the errors, logs and metrics
+// recorded as span events. Every span is written to the traces table with its name, kind,
+// duration, status, attributes, events and links, except for the spans the highlight SDKs
+// create only to carry a log or metric event.
+func (o *Handler) submitTraces(ctx context.Context, traces ptrace.Traces) (rejected *rejection, err error) {
+	rejected = &rejection{}
+	requestCtx := ctx
+	transformSpan, ctx := startStage(ctx, signalTraces, stageTransform)
+	auth := o.newIngestAuthorizer(ctx)
+	redactors := o.newProjectRedactors()
+	spanStatusErrors := o.newSpanStatusErrorSettings()
+	sampler := o.newIngestSampler()
+	sdks := o.newSDKFingerprints()
+	clock := o.newClockSkewCorrector()
+	keys := newDedupeKeys()
+	projectSpanCounts := make(map[int]int64)
 	var projectSessionErrors = make(map[string]map[string][]*model.BackendErrorObjectInput)
 	var projectLogs = make(map[string][]*clickhouse.LogRow)
 
 	var traceSpans = make(map[string][]*clickhouse.TraceRow)
 	var projectTraceMetrics = make(map[string]map[string][]*model.MetricInput)
+	var sessionResources = make(map[string][]*graph.NetworkResource)
 
-	spans := req.Traces().ResourceSpans()
+	spans := traces.ResourceSpans()
 	for i := 0; i < spans.Len(); i++ {
 		resource := spans.At(i).Resource()
 		scopeScans := spans.At(i).ScopeSpans()
 		for j := 0; j < scopeScans.Len(); j++ {
+			scope := scopeScans.At(j).Scope()
 			spans := scopeScans.At(j).Spans()
 			for k := 0; k < spans.Len(); k++ {
 				span := spans.At(k)
@@ -184,16 +227,76 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 
 				fields, err := extractFields(ctx, extractFieldsParams{
 					resource: &resource,
+					scope:    &scope,
 					span:     &span,
 				})
 				if err != nil {
 					lg(ctx, fields).WithError(err).Info("failed to extract fields from span")
+					rejected.add(err)
+					continue
+				}
+				if err := auth.authorize(ctx, fields); err != nil {
+					lg(ctx, fields).WithError(err).Info("unauthorized otel span")
+					rejected.add(err)
 					continue
 				}
+				if err := sdks.check(ctx, fields, rejected); err != nil {
+					rejected.add(err)
+					continue
+				}
+				// excluded spans are not written as traces, but their exceptions are still written as errors
+				excluded := sampler.excludesSpan(ctx, fields)
+				if err := redactors.apply(ctx, fields); err != nil {
+					lg(ctx, fields).WithError(err).Error("failed to redact otel span")
+					rejected.add(err)
+					continue
+				}
+				if !excluded {
+					projectSpanCounts[fields.projectIDInt]++
+				}
+				o.limits.apply(fields)
 				traceID := cast(fields.requestID, span.TraceID().String())
 				spanID := span.SpanID().String()
 
-				shouldWriteTrace := true
+				// addSpanError writes an exception of the span as an error log and a backend error
+				addSpanError := func(fields *extractedFields, eventIndex int) {
+					var logCursor *string
+					logRow := clickhouse.NewLogRow(
+						fields.timestamp, uint32(fields.projectIDInt),
+						clickhouse.WithTraceID(traceID),
+						clickhouse.WithSpanID(spanID),
+						clickhouse.WithSecureSessionID(fields.sessionID),
+						clickhouse.WithBody(ctx, fields.exceptionMessage),
+						withLogAttributes(fields.attrs),
+						clickhouse.WithServiceName(fields.serviceName),
+						clickhouse.WithServiceVersion(fields.serviceVersion),
+						clickhouse.WithSeverityText("ERROR"),
+						clickhouse.WithSource(fields.source),
+						clickhouse.WithEnvironment(fields.environment),
+					)
+
+					projectLogs[fields.projectID] = append(projectLogs[fields.projectID], logRow)
+					keys.logs[logRow] = dedupeKey(traceID, spanID, eventIndex)
+					logCursor = pointy.String(logRow.Cursor())
+
+					_, backendError := getBackendError(ctx, fields.timestamp, fields, traceID, spanID, logCursor)
+					if backendError == nil {
+						lg(ctx, fields).Error("otel span error got no session and no project")
+					} else {
+						if parentSpanID := span.ParentSpanID(); !parentSpanID.IsEmpty() {
+							backendError.ParentSpanID = pointy.String(parentSpanID.String())
+						}
+						backendError.SpanLinks = getSpanLinks(span)
+						keys.errors[backendError] = dedupeKey(traceID, spanID, eventIndex)
+						if _, ok := projectSessionErrors[fields.projectID]; !ok {
+							projectSessionErrors[fields.projectID] = make(map[string][]*model.BackendErrorObjectInput)
+						}
+						projectSessionErrors[fields.projectID][fields.sessionID] = append(projectSessionErrors[fields.projectID][fields.sessionID], backendError)
+					}
+				}
+
+				shouldWriteTrace := !excluded
+				hasException := false
 				for l := 0; l < events.Len(); l++ {
 					if skipped {
 						break
@@ -201,9 +304,27 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 					event := events.At(l)
 					fields, err := extractFields(ctx, extractFieldsParams{
 						resource: &resource,
+						scope:    &scope,
 						span:     &span,
 						event:    &event,
 					})
+					if err != nil {
+						lg(ctx, fields).WithError(err).Info("failed to extract fields from span event")
+						rejected.add(err)
+						continue
+					}
+					// an event can set its own project, which is authorized like the project of a span
+					if err := auth.authorize(ctx, fields); err != nil {
+						lg(ctx, fields).WithError(err).Info("unauthorized otel span event")
+						rejected.add(err)
+						continue
+					}
+					if err := redactors.apply(ctx, fields); err != nil {
+						lg(ctx, fields).WithError(err).Error("failed to redact otel span event")
+						continue
+					}
+					o.limits.apply(fields)
+					clock.apply(fields)
 
 					if event.Name() == semconv.ExceptionEventName {
 						if fields.external {
@@ -218,33 +339,8 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 							continue
 						}
 
-						var logCursor *string
-						logRow := clickhouse.NewLogRow(
-							fields.timestamp, uint32(fields.projectIDInt),
-							clickhouse.WithTraceID(traceID),
-							clickhouse.WithSpanID(spanID),
-							clickhouse.WithSecureSessionID(fields.sessionID),
-							clickhouse.WithBody(ctx, fields.exceptionMessage),
-							clickhouse.WithLogAttributes(fields.attrs),
-							clickhouse.WithServiceName(fields.serviceName),
-							clickhouse.WithServiceVersion(fields.serviceVersion),
-							clickhouse.WithSeverityText("ERROR"),
-							clickhouse.WithSource(fields.source),
-							clickhouse.WithEnvironment(fields.environment),
-						)
-
-						projectLogs[fields.projectID] = append(projectLogs[fields.projectID], logRow)
-						logCursor = pointy.String(logRow.Cursor())
-
-						_, backendError := getBackendError(ctx, fields.timestamp, fields, traceID, spanID, logCursor)
-						if backendError == nil {
-							lg(ctx, fields).Error("otel span error got no session and no project")
-						} else {
-							if _, ok := projectSessionErrors[fields.projectID]; !ok {
-								projectSessionErrors[fields.projectID] = make(map[string][]*model.BackendErrorObjectInput)
-							}
-							projectSessionErrors[fields.projectID][fields.sessionID] = append(projectSessionErrors[fields.projectID][fields.sessionID], backendError)
-						}
+						hasException = true
+						addSpanError(fields, l)
 					} else if event.Name() == highlight.LogEvent {
 						shouldWriteTrace = false
 						if fields.logMessage == "" {
@@ -255,6 +351,9 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 						if fields.logSeverity == "" {
 							fields.logSeverity = "unknown"
 						}
+						if sampler.excludesLog(ctx, fields, severity.Level(fields.logSeverity)) {
+							continue
+						}
 
 						logRow := clickhouse.NewLogRow(
 							fields.timestamp, uint32(fields.projectIDInt),
@@ -262,7 +361,7 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 							clickhouse.WithSpanID(spanID),
 							clickhouse.WithSecureSessionID(fields.sessionID),
 							clickhouse.WithBody(ctx, fields.logMessage),
-							clickhouse.WithLogAttributes(fields.attrs),
+							withLogAttributes(fields.attrs),
 							clickhouse.WithServiceName(fields.serviceName),
 							clickhouse.WithServiceVersion(fields.serviceVersion),
 							clickhouse.WithSeverityText(fields.logSeverity),
@@ -271,6 +370,7 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 						)
 
 						projectLogs[fields.projectID] = append(projectLogs[fields.projectID], logRow)
+						keys.logs[logRow] = dedupeKey(traceID, spanID, l)
 					} else if event.Name() == highlight.MetricEvent {
 						shouldWriteTrace = false
 						metric, err := getMetric(ctx, fields.timestamp, fields, spanID, span.ParentSpanID().String(), traceID)
@@ -282,13 +382,33 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 							projectTraceMetrics[fields.projectID] = make(map[string][]*model.MetricInput)
 						}
 						projectTraceMetrics[fields.projectID][fields.sessionID] = append(projectTraceMetrics[fields.projectID][fields.sessionID], metric)
+					} else if name, ok := getWebVitalName(event.Name()); ok {
+						metric, err := getWebVital(ctx, name, fields, spanID, span.ParentSpanID().String(), traceID)
+						if err != nil {
+							lg(ctx, fields).WithError(err).Warn("failed to create web vital")
+							continue
+						}
+						if _, ok := projectTraceMetrics[fields.projectID]; !ok {
+							projectTraceMetrics[fields.projectID] = make(map[string][]*model.MetricInput)
+						}
+						projectTraceMetrics[fields.projectID][fields.sessionID] = append(projectTraceMetrics[fields.projectID][fields.sessionID], metric)
 					} else {
 						lg(ctx, fields).Warnf("otel received unknown event %s", event.Name())
 					}
 				}
 
+				if !skipped && !hasException && !fields.external && span.Status().Code() == ptrace.StatusCodeError && spanStatusErrors.enabled(ctx, fields.projectIDInt) {
+					errorFields := getSpanStatusErrorFields(span, fields)
+					clock.apply(errorFields)
+					addSpanError(errorFields, spanStatusEventIndex)
+				}
+
 				if shouldWriteTrace {
-					traceRow := clickhouse.NewTraceRow(span.StartTimestamp().AsTime(), fields.projectIDInt).
+					if resource := getNetworkResource(span, fields); resource != nil {
+						sessionResources[fields.sessionID] = append(sessionResources[fields.sessionID], resource)
+					}
+					start, end := clock.span(span, fields)
+					traceRow := clickhouse.NewTraceRow(start, fields.projectIDInt).
 						WithSecureSessionId(fields.sessionID).
 						WithTraceId(traceID).
 						WithSpanId(spanID).
@@ -296,7 +416,7 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 						WithTraceState(span.TraceState().AsRaw()).
 						WithSpanName(span.Name()).
 						WithSpanKind(span.Kind().String()).
-						WithDuration(span.StartTimestamp().AsTime(), span.EndTimestamp().AsTime()).
+						WithDuration(start, end).
 						WithServiceName(fields.serviceName).
 						WithServiceVersion(fields.serviceVersion).
 						WithEnvironment(fields.environment).
@@ -311,6 +431,23 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	transformSpan.Finish()
+	// the items are not submitted once the client disconnected, as it will retry the export
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	submitSpan, ctx := startStage(requestCtx, signalTraces, stageSubmit)
+	defer func() {
+		submitSpan.Finish(err)
+	}()
+
+	if err := o.checkQuotas(ctx, privateModel.ProductTypeTraces, projectSpanCounts); err != nil {
+		return nil, err
+	}
+	if err := o.checkRateLimits(ctx, privateModel.ProductTypeTraces, projectSpanCounts); err != nil {
+		return nil, err
+	}
+
 	keyedErrorMessages := make(map[string][]*kafkaqueue.Message)
 	for projectID, sessionErrors := range projectSessionErrors {
 		for sessionID, errors := range sessionErrors {
@@ -328,16 +465,14 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 						ProjectVerboseID: pointy.String(projectID),
 						SessionSecureID:  pointy.String(sessionID),
 						Errors:           []*model.BackendErrorObjectInput{errorObject},
+						DedupeKey:        keys.errors[errorObject],
 					}})
 			}
 		}
 	}
 	for key, messages := range keyedErrorMessages {
-		err = o.resolver.ProducerQueue.Submit(ctx, key, messages...)
-		if err != nil {
-			log.WithContext(ctx).WithError(err).Error("failed to submit otel errors to public worker queue")
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
+		if err := o.submit(ctx, kafkaqueue.TopicTypeDefault, key, messages...); err != nil {
+			return nil, e.Wrap(err, "failed to submit otel errors to public worker queue")
 		}
 	}
 
@@ -353,81 +488,106 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 						Metrics:          []*model.MetricInput{metric},
 					}})
 			}
-			err = o.resolver.ProducerQueue.Submit(ctx, sessionID, messages...)
-			if err != nil {
-				log.WithContext(ctx).WithError(err).Error("failed to submit otel project metrics to public worker queue")
-				w.WriteHeader(http.StatusServiceUnavailable)
-				return
+			if err := o.submit(ctx, kafkaqueue.TopicTypeDefault, sessionID, messages...); err != nil {
+				return nil, e.Wrap(err, "failed to submit otel project metrics to public worker queue")
 			}
 		}
 	}
 
-	if err := o.submitTraceSpans(ctx, traceSpans); err != nil {
-		log.WithContext(ctx).WithError(err).Error("failed to submit otel project spans")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		return
+	if err := o.submitSessionNetworkResources(ctx, sessionResources); err != nil {
+		return nil, err
 	}
 
-	if err := o.submitProjectLogs(ctx, projectLogs); err != nil {
-		log.WithContext(ctx).WithError(err).Error("failed to submit otel project logs")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		return
+	if err := o.submitTraceSpans(ctx, traceSpans, sampler); err != nil {
+		return nil, err
 	}
 
-	w.WriteHeader(http.StatusOK)
+	if err := o.submitProjectLogs(ctx, projectLogs, keys, sampler); err != nil {
+		return nil, err
+	}
+	o.recordSampledOut(ctx, sampler)
+	return rejected, nil
 }
 
 func (o *Handler) HandleLog(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid log logBody")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	gz, err := gzip.NewReader(bytes.NewReader(body))
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip format for log")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	req := plogotlp.NewExportRequest()
+	if status, err := o.decodeRequest(w, r, signalLogs, req); err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid log payload")
+		http.Error(w, err.Error(), status)
 		return
 	}
 
-	output, err := io.ReadAll(gz)
+	rejected, err := o.submitLogs(ctx, req.Logs())
+	recordSubmission(logsReceived, signalLogs, req.Logs().LogRecordCount(), rejected, err)
 	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip stream for log")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.WithContext(ctx).WithError(err).Error("failed to submit otel project logs")
+		writeSubmitError(w, err)
 		return
 	}
 
-	req := plogotlp.NewExportRequest()
-	err = req.UnmarshalProto(output)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid log protobuf")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	resp := plogotlp.NewExportResponse()
+	if rejected.partial() {
+		resp.PartialSuccess().SetRejectedLogRecords(rejected.count)
+		resp.PartialSuccess().SetErrorMessage(rejected.message())
 	}
+	writeResponse(w, r, resp)
+}
 
+func (o *Handler) submitLogs(ctx context.Context, logs plog.Logs) (rejected *rejection, err error) {
+	rejected = &rejection{}
+	requestCtx := ctx
+	transformSpan, ctx := startStage(ctx, signalLogs, stageTransform)
+	auth := o.newIngestAuthorizer(ctx)
+	redactors := o.newProjectRedactors()
+	sampler := o.newIngestSampler()
+	sdks := o.newSDKFingerprints()
+	clock := o.newClockSkewCorrector()
+	keys := newDedupeKeys()
 	var projectLogs = make(map[string][]*clickhouse.LogRow)
+	projectLogCounts := make(map[int]int64)
 
-	resourceLogs := req.Logs().ResourceLogs()
+	resourceLogs := logs.ResourceLogs()
 	for i := 0; i < resourceLogs.Len(); i++ {
 		resource := resourceLogs.At(i).Resource()
+		resourceKey := resourceDigest(resource)
 		scopeLogs := resourceLogs.At(i).ScopeLogs()
 		for j := 0; j < scopeLogs.Len(); j++ {
 			scopeLogs := scopeLogs.At(j)
+			scope := scopeLogs.Scope()
 			logRecords := scopeLogs.LogRecords()
 			for k := 0; k < logRecords.Len(); k++ {
 				logRecord := logRecords.At(k)
 
 				fields, err := extractFields(ctx, extractFieldsParams{
 					resource:  &resource,
+					scope:     &scope,
 					logRecord: &logRecord,
 				})
 				if err != nil {
 					lg(ctx, fields).WithError(err).Info("failed to extract fields from log")
+					rejected.add(err)
+					continue
+				}
+				if err := auth.authorize(ctx, fields); err != nil {
+					lg(ctx, fields).WithError(err).Info("unauthorized otel log")
+					rejected.add(err)
+					continue
+				}
+				if err := sdks.check(ctx, fields, rejected); err != nil {
+					rejected.add(err)
 					continue
 				}
+				if sampler.excludesLog(ctx, fields, severity.Normalize(fields.logSeverity, fields.logSeverityNumber).Text) {
+					continue
+				}
+				if err := redactors.apply(ctx, fields); err != nil {
+					lg(ctx, fields).WithError(err).Error("failed to redact otel log")
+					rejected.add(err)
+					continue
+				}
+				o.limits.apply(fields)
+				clock.apply(fields)
 
 				logRow := clickhouse.NewLogRow(
 					fields.timestamp, uint32(fields.projectIDInt),
@@ -435,10 +595,10 @@ func (o *Handler) HandleLog(w http.ResponseWriter, r *http.Request) {
 					clickhouse.WithSpanID(logRecord.SpanID().String()),
 					clickhouse.WithSecureSessionID(fields.sessionID),
 					clickhouse.WithBody(ctx, fields.logBody),
-					clickhouse.WithLogAttributes(fields.attrs),
+					withLogAttributes(fields.attrs),
 					clickhouse.WithServiceName(fields.serviceName),
 					clickhouse.WithServiceVersion(fields.serviceVersion),
-					clickhouse.WithSeverityText(fields.logSeverity),
+					clickhouse.WithSeverity(severity.Normalize(fields.logSeverity, fields.logSeverityNumber)),
 					clickhouse.WithSource(fields.source),
 					clickhouse.WithEnvironment(fields.environment),
 				)
@@ -448,49 +608,124 @@ func (o *Handler) HandleLog(w http.ResponseWriter, r *http.Request) {
 						projectLogs[fields.projectID] = []*clickhouse.LogRow{}
 					}
 					projectLogs[fields.projectID] = append(projectLogs[fields.projectID], logRow)
+					keys.logs[logRow] = logRecordDedupeKey(resourceKey, j, k, logRecord)
+					projectLogCounts[fields.projectIDInt]++
 				} else {
 					lg(ctx, fields).Errorf("otel log got no project")
+					rejected.add(errMissingProject)
 					continue
 				}
 			}
 		}
 	}
 
-	if err := o.submitProjectLogs(ctx, projectLogs); err != nil {
-		log.WithContext(ctx).WithError(err).Error("failed to submit otel project logs")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		return
+	transformSpan.Finish()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	submitSpan, ctx := startStage(requestCtx, signalLogs, stageSubmit)
+	defer func() {
+		submitSpan.Finish(err)
+	}()
+
+	if err := o.checkQuotas(ctx, privateModel.ProductTypeLogs, projectLogCounts); err != nil {
+		return nil, err
+	}
+	if err := o.checkRateLimits(ctx, privateModel.ProductTypeLogs, projectLogCounts); err != nil {
+		return nil, err
 	}
 
-	w.WriteHeader(http.StatusOK)
+	if err := o.submitProjectLogs(ctx, projectLogs, keys, sampler); err != nil {
+		return nil, err
+	}
+	o.recordSampledOut(ctx, sampler)
+	return rejected, nil
 }
 
-func (o *Handler) submitProjectLogs(ctx context.Context, projectLogs map[string][]*clickhouse.LogRow) error {
+func (o *Handler) submitProjectLogs(ctx context.Context, projectLogs map[string][]*clickhouse.LogRow, keys *dedupeKeys, sampler *ingestSampler) error {
 	for _, logRows := range projectLogs {
 		var messages []*kafkaqueue.Message
 		for _, logRow := range logRows {
-			if !o.resolver.IsLogIngested(ctx, logRow) {
+			if ingested, reason := o.resolver.IsLogIngestedWithReason(ctx, logRow); !ingested {
+				sampler.drop(int(logRow.ProjectId), privateModel.ProductTypeLogs, reason)
 				continue
 			}
 			messages = append(messages, &kafkaqueue.Message{
 				Type: kafkaqueue.PushLogs,
 				PushLogs: &kafkaqueue.PushLogsArgs{
-					LogRow: logRow,
+					LogRow:    logRow,
+					DedupeKey: keys.logs[logRow],
 				}})
 		}
-		err := o.resolver.BatchedQueue.Submit(ctx, "", messages...)
-		if err != nil {
-			return e.Wrap(err, "failed to submit otel project logs to public worker queue")
+		for _, chunk := range o.limits.chunkLogs(messages) {
+			err := o.submit(ctx, kafkaqueue.TopicTypeBatched, "", chunk...)
+			if err != nil {
+				return e.Wrap(err, "failed to submit otel project logs to public worker queue")
+			}
+		}
+	}
+	return nil
+}
+
+// submitDrainLogs submits the logs of a log drain integration to their projects. The logs are deduplicated
+// by the delivery id of each log when it is set.
+func (o *Handler) submitDrainLogs(ctx context.Context, drain string, logs []*extractedFields, dedupeIDs []string) error {
+	redactors := o.newProjectRedactors()
+	sampler := o.newIngestSampler()
+	clock := o.newClockSkewCorrector()
+	keys := newDedupeKeys()
+	var projectLogs = make(map[string][]*clickhouse.LogRow)
+	projectLogCounts := make(map[int]int64)
+
+	for index, fields := range logs {
+		logSeverity := severity.Normalize(fields.logSeverity, fields.logSeverityNumber)
+		if sampler.excludesLog(ctx, fields, logSeverity.Text) {
+			continue
+		}
+		if err := redactors.apply(ctx, fields); err != nil {
+			lg(ctx, fields).WithError(err).Errorf("failed to redact %s log", drain)
+			continue
+		}
+		projectLogCounts[fields.projectIDInt]++
+		o.limits.apply(fields)
+		clock.apply(fields)
+
+		logRow := clickhouse.NewLogRow(
+			fields.timestamp, uint32(fields.projectIDInt),
+			clickhouse.WithSecureSessionID(fields.sessionID),
+			clickhouse.WithBody(ctx, fields.logBody),
+			withLogAttributes(fields.attrs),
+			clickhouse.WithServiceName(fields.serviceName),
+			clickhouse.WithServiceVersion(fields.serviceVersion),
+			clickhouse.WithSeverity(logSeverity),
+			clickhouse.WithSource(fields.source),
+			clickhouse.WithEnvironment(fields.environment),
+		)
+		projectLogs[fields.projectID] = append(projectLogs[fields.projectID], logRow)
+		if index < len(dedupeIDs) && dedupeIDs[index] != "" {
+			keys.logs[logRow] = dedupeKey(drain, dedupeIDs[index], 0)
 		}
 	}
+
+	if err := o.checkQuotas(ctx, privateModel.ProductTypeLogs, projectLogCounts); err != nil {
+		return err
+	}
+	if err := o.checkRateLimits(ctx, privateModel.ProductTypeLogs, projectLogCounts); err != nil {
+		return err
+	}
+	if err := o.submitProjectLogs(ctx, projectLogs, keys, sampler); err != nil {
+		return err
+	}
+	o.recordSampledOut(ctx, sampler)
 	return nil
 }
 
-func (o *Handler) submitTraceSpans(ctx context.Context, traceRows map[string][]*clickhouse.TraceRow) error {
+func (o *Handler) submitTraceSpans(ctx context.Context, traceRows map[string][]*clickhouse.TraceRow, sampler *ingestSampler) error {
 	for traceID, traceRows := range traceRows {
 		var messages []*kafkaqueue.Message
 		for _, traceRow := range traceRows {
-			if !o.resolver.IsTraceIngested(ctx, traceRow) {
+			if ingested, reason := o.resolver.IsTraceIngestedWithReason(ctx, traceRow); !ingested {
+				sampler.drop(int(traceRow.ProjectId), privateModel.ProductTypeTraces, reason)
 				continue
 			}
 			messages = append(messages, &kafkaqueue.Message{
@@ -501,7 +736,7 @@ func (o *Handler) submitTraceSpans(ctx context.Context, traceRows map[string][]*
 			})
 		}
 
-		err := o.resolver.TracesQueue.Submit(ctx, traceID, messages...)
+		err := o.submit(ctx, kafkaqueue.TopicTypeTraces, traceID, messages...)
 		if err != nil {
 			return e.Wrap(err, "failed to submit otel project traces to public worker queue")
 		}
@@ -511,14 +746,61 @@ func (o *Handler) submitTraceSpans(ctx context.Context, traceRows map[string][]*
 }
 
 func (o *Handler) Listen(r *chi.Mux) {
-	r.Route("/otel/v1", func(r chi.Router) {
-		r.HandleFunc("/traces", o.HandleTrace)
-		r.HandleFunc("/logs", o.HandleLog)
+	// /v1 are the default paths of otel exporters, so that only the endpoint host needs to be configured.
+	// the routes are registered individually since /v1 is also the prefix of the http log routes.
+	for _, prefix := range []string{"/otel/v1", "/v1"} {
+		r.Group(func(r chi.Router) {
+			r.Use(o.authMiddleware)
+			r.HandleFunc(prefix+"/traces", instrument(signalTraces, o.HandleTrace))
+			r.HandleFunc(prefix+"/logs", instrument(signalLogs, o.HandleLog))
+			r.HandleFunc(prefix+"/metrics", instrument(signalMetrics, o.HandleMetric))
+			r.Post(prefix+"/profiles", instrument(signalProfiles, o.HandleProfile))
+		})
+	}
+	r.Post(VercelLogsPath, instrument(signalLogs, o.HandleVercelLog))
+	r.Group(func(r chi.Router) {
+		r.Use(o.authMiddleware)
+		r.Post(SyslogLogsPath, instrument(signalLogs, o.HandleSyslog))
+		r.Post(FluentLogsPath, instrument(signalLogs, o.HandleFluent))
+		r.Post(RemoteWritePath, instrument(signalMetrics, o.HandleRemoteWrite))
 	})
+	r.Post(SentryStorePath, instrument(signalErrors, o.HandleSentryStore))
+	r.Post(SentryEnvelopePath, instrument(signalErrors, o.HandleSentryEnvelope))
+	r.Post(SegmentPath, instrument(signalEvents, o.HandleSegment))
+	r.Get(MetricsPath, o.HandlePrometheus)
 }
 
 func New(resolver *graph.Resolver) *Handler {
-	return &Handler{
-		resolver: resolver,
+	limits, err := LoadLimits()
+	if err != nil {
+		log.WithError(err).Error("failed to load otel limits, using the defaults")
+	}
+	sdkBlocklist, err := LoadSDKBlocklist()
+	if err != nil {
+		log.WithError(err).Error("failed to load otel sdk blocklist")
+	}
+	clockSkew, err := LoadClockSkew()
+	if err != nil {
+		log.WithError(err).Error("failed to load otel clock skew correction, using the defaults")
+	}
+	h := &Handler{
+		resolver:     resolver,
+		limits:       limits,
+		sdkBlocklist: sdkBlocklist,
+		clockSkew:    clockSkew,
+	}
+	if resolver.Store != nil {
+		h.projects = resolver.Store
+	}
+	if resolver.Redis != nil {
+		h.limiter = ratelimit.NewTokenBucket(resolver.Redis, "otel")
+		h.quotas = resolver.Redis
+	}
+	deadLetters, err := NewS3DeadLetterStore(context.Background())
+	if err != nil {
+		log.WithError(err).Error("failed to create the otel dead-letter store")
+	} else if deadLetters != nil {
+		h.deadLetters = deadLetters
 	}
+	return h
 }
@@ -9,13 +9,17 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/smithy-go/ptr"
 	"github.com/highlight-run/highlight/backend/integrations"
+	backendModel "github.com/highlight-run/highlight/backend/model"
 	model2 "github.com/highlight-run/highlight/backend/public-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/redis"
 	"github.com/highlight-run/highlight/backend/storage"
 	"github.com/highlight-run/highlight/backend/store"
 	"github.com/highlight-run/highlight/backend/util"
+	"github.com/highlight/highlight/sdk/highlight-go"
 	"github.com/openlyinc/pointy"
 	e "github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -24,7 +28,10 @@ import (
 	"github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	public "github.com/highlight-run/highlight/backend/public-graph/graph"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
 type MockKafkaProducer struct {
@@ -83,6 +90,8 @@ func TestHandler_HandleTrace(t *testing.T) {
 				kafkaqueue.PushBackendPayload: 4,   // 4 exceptions, pushed as individual messages
 				kafkaqueue.PushLogs:           15,  // 4 exceptions, 11 logs
 				kafkaqueue.PushTraces:         501, // 512 spans - 11 logs
+				// 13 http spans of 2 sessions
+				kafkaqueue.PushSessionNetworkResources: 2,
 			},
 			expectedLogCounts: map[model.LogSource]int{
 				model.LogSourceFrontend: 1,
@@ -94,6 +103,8 @@ func TestHandler_HandleTrace(t *testing.T) {
 				// no errors expected
 				kafkaqueue.PushLogs:   11,  // 11 logs
 				kafkaqueue.PushTraces: 501, // 512 spans - 11 logs
+				// 13 http spans of 2 sessions
+				kafkaqueue.PushSessionNetworkResources: 2,
 			},
 			external: true,
 		},
@@ -184,3 +195,184 @@ func TestHandler_HandleTrace(t *testing.T) {
 	}
 
 }
+
+func TestGetSpanLinks(t *testing.T) {
+	span := ptrace.NewSpan()
+	assert.Empty(t, getSpanLinks(span))
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	link.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+	link.TraceState().FromRaw("vendor=value")
+	link.Attributes().PutStr("messaging.operation", "receive")
+
+	links := getSpanLinks(span)
+	assert.Len(t, links, 1)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", links[0].TraceID)
+	assert.Equal(t, "0102030405060708", links[0].SpanID)
+	assert.Equal(t, "vendor=value", *links[0].TraceState)
+	assert.Equal(t, map[string]interface{}{"messaging.operation": "receive"}, links[0].Attributes)
+}
+
+func TestGetNetworkResource(t *testing.T) {
+	span := ptrace.NewSpan()
+	span.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1700000000000)))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1700000000250)))
+
+	fields := newExtractedFields()
+	fields.attrs["http.method"] = "POST"
+	fields.attrs["http.url"] = "https://example.com/api"
+	assert.Nil(t, getNetworkResource(span, fields), "spans without a session are skipped")
+
+	fields.sessionID = "abc123"
+	fields.attrs["http.status_code"] = "201"
+	resource := getNetworkResource(span, fields)
+	assert.NotNil(t, resource)
+	assert.Equal(t, "https://example.com/api", resource.Name)
+	assert.Equal(t, "POST", resource.RequestResponsePairs.Request.Method)
+	assert.Equal(t, "0102030405060708", resource.RequestResponsePairs.Request.ID)
+	assert.Equal(t, float64(201), resource.RequestResponsePairs.Response.Status)
+	assert.Equal(t, float64(1700000000000), resource.StartTimeAbs)
+	assert.Equal(t, float64(1700000000250), resource.ResponseEndAbs)
+
+	fields.attrs[highlight.TraceTypeAttribute] = string(highlight.TraceTypeNetworkRequest)
+	assert.Nil(t, getNetworkResource(span, fields), "network requests recorded by the client are skipped")
+}
+
+func TestHandler_SubmitTraces_EventProject(t *testing.T) {
+	db, err := util.CreateAndMigrateTestDB("highlight_testing_db")
+	if err != nil {
+		t.Fatal(e.Wrap(err, "error creating testdb"))
+	}
+
+	red := redis.NewClient()
+	producer := MockKafkaProducer{}
+	h := Handler{
+		resolver: &public.Resolver{
+			Redis:         red,
+			Store:         store.NewStore(db, red, integrations.NewIntegrationsClient(db), &storage.FilesystemClient{}, &producer, nil),
+			ProducerQueue: &producer,
+			BatchedQueue:  &producer,
+			TracesQueue:   &producer,
+		},
+		projects: newMockProjectStore(),
+	}
+	ctx, err := h.authenticate(context.Background(), "secret-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traces := ptrace.NewTraces()
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().PutStr(highlight.ProjectIDAttribute, "1")
+	span := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("cross-project")
+	// the event of the other project is rejected even though the span is of the authenticated project
+	for _, project := range []string{"1", "2"} {
+		event := span.Events().AppendEmpty()
+		event.SetName(highlight.LogEvent)
+		event.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+		event.Attributes().PutStr(highlight.ProjectIDAttribute, project)
+		event.Attributes().PutStr(highlight.LogSeverityAttribute, "info")
+		event.Attributes().PutStr(highlight.LogMessageAttribute, fmt.Sprintf("log of project %s", project))
+	}
+
+	rejected, err := h.submitTraces(ctx, traces)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), rejected.count)
+	assert.ErrorIs(t, rejected.err, errIngestKeyProject)
+
+	var bodies []string
+	for _, message := range producer.messages {
+		if message.Type == kafkaqueue.PushLogs {
+			assert.Equal(t, uint32(1), message.PushLogs.LogRow.ProjectId)
+			bodies = append(bodies, message.PushLogs.LogRow.Body)
+		}
+	}
+	assert.Equal(t, []string{"log of project 1"}, bodies)
+}
+
+// newTestSubmitHandler returns a handler that submits to a mock producer, authenticated as project 1.
+func newTestSubmitHandler(t *testing.T, projects *mockProjectStore) (context.Context, *Handler, *MockKafkaProducer) {
+	db, err := util.CreateAndMigrateTestDB("highlight_testing_db")
+	if err != nil {
+		t.Fatal(e.Wrap(err, "error creating testdb"))
+	}
+
+	red := redis.NewClient()
+	producer := &MockKafkaProducer{}
+	h := &Handler{
+		resolver: &public.Resolver{
+			Redis:         red,
+			Store:         store.NewStore(db, red, integrations.NewIntegrationsClient(db), &storage.FilesystemClient{}, producer, nil),
+			ProducerQueue: producer,
+			BatchedQueue:  producer,
+			TracesQueue:   producer,
+		},
+		projects: projects,
+	}
+	ctx, err := h.authenticate(context.Background(), "secret-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx, h, producer
+}
+
+// newExceptionTraces returns the traces of a span of the service with an exception event.
+func newExceptionTraces(serviceName string, attrs map[string]string) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().PutStr(highlight.ProjectIDAttribute, "1")
+	resourceSpans.Resource().Attributes().PutStr(string(semconv.ServiceNameKey), serviceName)
+	span := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /healthz")
+	span.SetTraceID(pcommon.TraceID([16]byte{1}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1}))
+	for k, v := range attrs {
+		span.Attributes().PutStr(k, v)
+	}
+	event := span.Events().AppendEmpty()
+	event.SetName(semconv.ExceptionEventName)
+	event.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	event.Attributes().PutStr(string(semconv.ExceptionTypeKey), "DatabaseError")
+	event.Attributes().PutStr(string(semconv.ExceptionMessageKey), "connection refused")
+	return traces
+}
+
+func countMessages(producer *MockKafkaProducer) map[kafkaqueue.PayloadType]int {
+	counts := map[kafkaqueue.PayloadType]int{}
+	for _, message := range producer.messages {
+		counts[message.Type]++
+	}
+	return counts
+}
+
+// the spans of excluded services are not written as traces, but their exceptions are written as errors
+func TestHandler_SubmitTraces_ExcludedServiceErrors(t *testing.T) {
+	projects := newMockProjectStore()
+	projects.excludedServices = []string{"health-check"}
+	ctx, h, producer := newTestSubmitHandler(t, projects)
+
+	_, err := h.submitTraces(ctx, newExceptionTraces("health-check", nil))
+	assert.NoError(t, err)
+	counts := countMessages(producer)
+	assert.Equal(t, 1, counts[kafkaqueue.PushBackendPayload])
+	assert.Equal(t, 1, counts[kafkaqueue.PushLogs])
+	assert.Zero(t, counts[kafkaqueue.PushTraces])
+}
+
+func TestHandler_SubmitTraces_DroppedSpanErrors(t *testing.T) {
+	projects := newMockProjectStore()
+	projects.filterRules = []*backendModel.IngestFilterRule{
+		{Action: model.IngestFilterRuleActionDrop, AttributeKey: ptr.String("http.target"), AttributeRegex: ptr.String("^/health")},
+	}
+	ctx, h, producer := newTestSubmitHandler(t, projects)
+
+	_, err := h.submitTraces(ctx, newExceptionTraces("api", map[string]string{"http.target": "/healthz"}))
+	assert.NoError(t, err)
+	counts := countMessages(producer)
+	assert.Equal(t, 1, counts[kafkaqueue.PushBackendPayload])
+	assert.Equal(t, 1, counts[kafkaqueue.PushLogs])
+	assert.Zero(t, counts[kafkaqueue.PushTraces])
+}
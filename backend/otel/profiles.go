@@ -0,0 +1,112 @@
+package otel
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	"github.com/highlight-run/highlight/backend/storage"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultProfileType = "cpu"
+
+// ProfileResponse is the stored profile returned by the profile export handler.
+type ProfileResponse struct {
+	Profile *storage.Profile `json:"profile"`
+}
+
+// HandleProfile stores a pprof profile of a service, sent as the (optionally compressed) protobuf body.
+// The project is set by the `project_id` query parameter or the x-highlight-project header, and the
+// profile is described by the `service_name`, `type` (cpu by default) and `timestamp` (RFC3339, the
+// time of the request by default) query parameters.
+// OTLP profiles export requests are not decoded as the signal is not yet supported by the collector pdata.
+func (o *Handler) HandleProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if isJSONRequest(r) {
+		http.Error(w, "profiles must be sent as pprof protobuf", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	query := r.URL.Query()
+	projectID := query.Get("project_id")
+	if projectID == "" {
+		projectID = r.Header.Get(ratelimit.ProjectHeader)
+	}
+	fields := &extractedFields{
+		projectID:   projectID,
+		serviceName: strings.TrimSpace(query.Get("service_name")),
+	}
+	var err error
+	if fields.projectIDInt, err = projectToInt(fields.projectID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if fields.serviceName == "" {
+		http.Error(w, "service_name is required", http.StatusBadRequest)
+		return
+	}
+	profile := &storage.Profile{
+		ServiceName: fields.serviceName,
+		Type:        defaultProfileType,
+		Timestamp:   time.Now().UTC(),
+	}
+	if value := query.Get("type"); value != "" {
+		profile.Type = value
+	}
+	if err := storage.ValidateProfileType(profile.Type); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("timestamp"); value != "" {
+		if profile.Timestamp, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "invalid timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := o.newIngestAuthorizer(ctx).authorize(ctx, fields); err != nil {
+		if e.Is(err, errInvalidIngestKey) || e.Is(err, errIngestKeyProject) || e.Is(err, errIngestKeyRequired) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		log.WithContext(ctx).WithError(err).Error("failed to authorize otel profile")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if o.resolver.StorageClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	output, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid profile body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	defer release()
+	if len(output) == 0 {
+		http.Error(w, "empty profile", http.StatusBadRequest)
+		return
+	}
+
+	payloadBytes.add(float64(len(output)), signalProfiles)
+	profilesReceived.add(1)
+
+	if err := o.resolver.StorageClient.PushProfile(ctx, fields.projectIDInt, profile, output); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", fields.projectIDInt).Error("failed to store otel profile")
+		itemsDropped.add(1, signalProfiles, dropReasonUnavailable)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ProfileResponse{Profile: profile}); err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to write otel profile response")
+	}
+}
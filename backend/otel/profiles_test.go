@@ -0,0 +1,106 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	public "github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight-run/highlight/backend/storage"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_HandleProfile(t *testing.T) {
+	ctx := context.Background()
+	client, err := storage.NewFSClient(ctx, "", t.TempDir())
+	assert.NoError(t, err)
+	h := Handler{
+		resolver: &public.Resolver{StorageClient: client},
+		projects: newMockProjectStore(),
+	}
+	pprof := []byte("\x0a\x04\x08\x01\x10\x02pprof")
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		key    string
+		header string
+		code   int
+	}{
+		{"missing service", "/v1/profiles?project_id=1", "", "", http.StatusBadRequest},
+		{"invalid type", "/v1/profiles?project_id=1&service_name=api&type=../cpu", "", "", http.StatusBadRequest},
+		{"invalid timestamp", "/v1/profiles?project_id=1&service_name=api&timestamp=now", "", "", http.StatusBadRequest},
+		{"json", "/v1/profiles?project_id=1&service_name=api", "", "application/json", http.StatusUnsupportedMediaType},
+		{"requires key", "/v1/profiles?project_id=2&service_name=api", "", "", http.StatusUnauthorized},
+		{"key of other project", "/v1/profiles?project_id=2&service_name=api", "secret-1", "", http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewReader(pprof))
+			if tc.header != "" {
+				r.Header.Set("Content-Type", tc.header)
+			}
+			if tc.key != "" {
+				authCtx, err := h.authenticate(r.Context(), tc.key)
+				assert.NoError(t, err)
+				r = r.WithContext(authCtx)
+			}
+			w := httptest.NewRecorder()
+			h.HandleProfile(w, r)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+
+	timestamp := time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC)
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(pprof)
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/profiles?project_id=2&service_name=my/api&type=heap&timestamp="+timestamp.Format(time.RFC3339), &compressed)
+	authCtx, err := h.authenticate(r.Context(), "secret-2")
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.HandleProfile(w, r.WithContext(authCtx))
+	if !assert.Equal(t, http.StatusOK, w.Code) {
+		return
+	}
+
+	var resp ProfileResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "my/api", resp.Profile.ServiceName)
+	assert.Equal(t, "heap", resp.Profile.Type)
+
+	profiles, err := client.ListProfiles(ctx, 2, "my/api", timestamp.Add(-time.Minute), timestamp.Add(time.Minute))
+	assert.NoError(t, err)
+	if !assert.Len(t, profiles, 1) {
+		return
+	}
+	assert.Equal(t, resp.Profile.ID, profiles[0].ID)
+	assert.True(t, timestamp.Equal(profiles[0].Timestamp))
+	assert.Equal(t, resp.Profile.Size, profiles[0].Size)
+
+	profiles, err = client.ListProfiles(ctx, 1, "my/api", timestamp.Add(-time.Minute), timestamp.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, profiles)
+	profiles, err = client.ListProfiles(ctx, 2, "my/api", timestamp.Add(time.Second), timestamp.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, profiles)
+
+	data, err := client.ReadProfile(ctx, 2, resp.Profile.ID)
+	assert.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, pprof, decompressed)
+
+	_, err = client.ReadProfile(ctx, 2, "../1/"+resp.Profile.ID)
+	assert.Error(t, err)
+}
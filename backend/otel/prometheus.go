@@ -0,0 +1,256 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/util"
+	e "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MetricsPath serves the ingestion metrics of the otel handlers in the prometheus text format,
+// for operators of self-hosted deployments to scrape.
+const MetricsPath = "/otel/metrics"
+
+const (
+	signalTraces   = "traces"
+	signalLogs     = "logs"
+	signalMetrics  = "metrics"
+	signalProfiles = "profiles"
+	signalErrors   = "errors"
+	signalEvents   = "events"
+)
+
+// reasons that items are dropped in addition to the ingest reasons of the project sampling settings
+const (
+	dropReasonRejected      = "rejected"
+	dropReasonRateLimited   = "rate_limited"
+	dropReasonQuotaExceeded = "quota_exceeded"
+	dropReasonUnavailable   = "unavailable"
+	dropReasonCanceled      = "canceled"
+)
+
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	spansReceived    = newMetricVec("highlight_otel_spans_received_total", "Spans received in otel export requests.", nil)
+	logsReceived     = newMetricVec("highlight_otel_logs_received_total", "Log records received in otel export requests.", nil)
+	profilesReceived = newMetricVec("highlight_otel_profiles_received_total", "Profiles received by the otel profiles handler.", nil)
+	errorsReceived   = newMetricVec("highlight_otel_errors_received_total", "Events received by the sentry compatible handlers.", nil)
+	samplesReceived  = newMetricVec("highlight_otel_samples_received_total", "Samples received in prometheus remote-write requests.", nil)
+	eventsReceived   = newMetricVec("highlight_otel_events_received_total", "Identify and track calls received by the segment handler.", nil)
+	itemsDropped     = newMetricVec("highlight_otel_dropped_total", "Spans, log records and errors that were not ingested, by reason.", nil, "signal", "reason")
+	itemsSoftCapped  = newMetricVec("highlight_otel_soft_capped_total", "Spans and log records ingested for projects approaching their billing limit.", nil, "signal")
+	payloadBytes     = newMetricVec("highlight_otel_payload_bytes_total", "Decompressed bytes of otel export requests.", nil, "signal")
+	handlerDuration  = newMetricVec("highlight_otel_handler_duration_seconds", "Latency of the otel export handlers.", latencyBuckets, "signal", "code")
+)
+
+var registry = []*metricVec{spansReceived, logsReceived, profilesReceived, errorsReceived, samplesReceived, eventsReceived, itemsDropped, itemsSoftCapped, payloadBytes, handlerDuration}
+
+// metricVec is a prometheus counter, or a histogram when it has buckets, partitioned by label values.
+type metricVec struct {
+	name    string
+	help    string
+	buckets []float64
+	labels  []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	// value is the counter value or the sum of the observations of a histogram
+	value        float64
+	count        uint64
+	bucketCounts []uint64
+}
+
+func newMetricVec(name string, help string, buckets []float64, labels ...string) *metricVec {
+	return &metricVec{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		labels:  labels,
+		series:  make(map[string]*series),
+	}
+}
+
+func (m *metricVec) get(labelValues []string) *series {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues, bucketCounts: make([]uint64, len(m.buckets))}
+		m.series[key] = s
+	}
+	return s
+}
+
+// add increments a counter.
+func (m *metricVec) add(v float64, labelValues ...string) {
+	if v <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(labelValues).value += v
+}
+
+// observe records an observation of a histogram.
+func (m *metricVec) observe(v float64, labelValues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.get(labelValues)
+	s.value += v
+	s.count++
+	for i, bound := range m.buckets {
+		if v <= bound {
+			s.bucketCounts[i]++
+		}
+	}
+}
+
+func (m *metricVec) write(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kind := "counter"
+	if m.buckets != nil {
+		kind = "histogram"
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, kind); err != nil {
+		return err
+	}
+
+	var keys []string
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := m.series[key]
+		if m.buckets == nil {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", m.name, formatLabels(m.labels, s.labelValues), formatValue(s.value)); err != nil {
+				return err
+			}
+			continue
+		}
+		labels := append(m.labels[:len(m.labels):len(m.labels)], "le")
+		for i, bound := range m.buckets {
+			values := append(s.labelValues[:len(s.labelValues):len(s.labelValues)], formatValue(bound))
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, formatLabels(labels, values), s.bucketCounts[i]); err != nil {
+				return err
+			}
+		}
+		values := append(s.labelValues[:len(s.labelValues):len(s.labelValues)], "+Inf")
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			m.name, formatLabels(labels, values), s.count,
+			m.name, formatLabels(m.labels, s.labelValues), formatValue(s.value),
+			m.name, formatLabels(m.labels, s.labelValues), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []string, values []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, label, labelValueReplacer.Replace(values[i]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// recordSubmission counts the items of an export request as received, and as dropped when they
+// were rejected or the whole request could not be submitted.
+func recordSubmission(received *metricVec, signal string, count int, rejected *rejection, err error) {
+	received.add(float64(count))
+	if err != nil {
+		itemsDropped.add(float64(count), signal, submitErrorReason(err))
+	} else if rejected != nil {
+		itemsDropped.add(float64(rejected.count), signal, dropReasonRejected)
+	}
+}
+
+// productSignal is the signal of the spans or log records of a product.
+func productSignal(product privateModel.ProductType) string {
+	if product == privateModel.ProductTypeTraces {
+		return signalTraces
+	}
+	return signalLogs
+}
+
+// recordDropped counts the items of a project sampling decision as dropped.
+func recordDropped(product privateModel.ProductType, reason privateModel.IngestReason, count int64) {
+	itemsDropped.add(float64(count), productSignal(product), strings.ToLower(reason.String()))
+}
+
+// submitErrorReason is the reason the items of an export request that could not be submitted are dropped.
+func submitErrorReason(err error) string {
+	var rateLimited *rateLimitError
+	var quotaExceeded *quotaError
+	if e.As(err, &rateLimited) {
+		return dropReasonRateLimited
+	} else if e.As(err, &quotaExceeded) {
+		return dropReasonQuotaExceeded
+	} else if e.Is(err, context.Canceled) {
+		return dropReasonCanceled
+	}
+	return dropReasonUnavailable
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// instrument records the latency and response status of an export handler, tracing the request
+// as the parent span of its stages.
+func instrument(signal string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		span, ctx := util.StartSpanFromContext(r.Context(), "otel.handle", util.ResourceName("otel."+signal), util.Tag("signal", signal), util.WithSpanKind(trace.SpanKindServer))
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r.WithContext(ctx))
+		span.SetAttribute("status_code", recorder.status)
+		span.Finish()
+		handlerDuration.observe(time.Since(start).Seconds(), signal, strconv.Itoa(recorder.status))
+	}
+}
+
+// HandlePrometheus serves the ingestion metrics in the prometheus text exposition format.
+func (o *Handler) HandlePrometheus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, m := range registry {
+		if err := m.write(w); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package otel
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricVec_Write(t *testing.T) {
+	counter := newMetricVec("test_total", "A test counter.", nil, "signal", "reason")
+	counter.add(2, "logs", "sample")
+	counter.add(1, "logs", `"quoted"`)
+	counter.add(0, "traces", "sample")
+
+	var buf bytes.Buffer
+	assert.NoError(t, counter.write(&buf))
+	assert.Equal(t, `# HELP test_total A test counter.
+# TYPE test_total counter
+test_total{signal="logs",reason="\"quoted\""} 1
+test_total{signal="logs",reason="sample"} 2
+`, buf.String())
+
+	histogram := newMetricVec("test_seconds", "A test histogram.", []float64{.1, 1}, "signal")
+	histogram.observe(.0625, "traces")
+	histogram.observe(.5, "traces")
+	histogram.observe(4, "traces")
+
+	buf.Reset()
+	assert.NoError(t, histogram.write(&buf))
+	assert.Equal(t, `# HELP test_seconds A test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{signal="traces",le="0.1"} 1
+test_seconds_bucket{signal="traces",le="1"} 2
+test_seconds_bucket{signal="traces",le="+Inf"} 3
+test_seconds_sum{signal="traces"} 4.5625
+test_seconds_count{signal="traces"} 3
+`, buf.String())
+}
+
+func TestInstrument(t *testing.T) {
+	h := instrument("test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/otel/v1/traces", nil))
+
+	w := httptest.NewRecorder()
+	(&Handler{}).HandlePrometheus(w, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	assert.Contains(t, w.Body.String(), `highlight_otel_handler_duration_seconds_count{signal="test",code="503"} 1`)
+}
@@ -0,0 +1,64 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// quotaProducts are the products with billing quotas enforced by the otel handlers.
+var quotaProducts = map[privateModel.ProductType]model.PricingProductType{
+	privateModel.ProductTypeLogs:   model.PricingProductTypeLogs,
+	privateModel.ProductTypeTraces: model.PricingProductTypeTraces,
+}
+
+// quotaStore has the billing quota status of projects, as computed by the kafka workers.
+type quotaStore interface {
+	IsBillingQuotaExceeded(ctx context.Context, projectId int, productType model.PricingProductType) (*bool, error)
+	IsBillingQuotaSoftCapped(ctx context.Context, projectId int, productType model.PricingProductType) (*bool, error)
+}
+
+// quotaError rejects an export request because a project exceeded the billing limit of its
+// workspace. The data would be dropped by the workers, so exporters are asked not to retry.
+type quotaError struct {
+	projectID int
+	product   privateModel.ProductType
+}
+
+func (q *quotaError) Error() string {
+	return fmt.Sprintf("project %d exceeded the %s billing quota of its workspace", q.projectID, q.product)
+}
+
+// checkQuotas enforces the billing quotas of the projects of an export request. Projects over
+// their billing limit are hard capped and the request is rejected. Projects approaching it are
+// soft capped, their items are ingested and counted so that operators can follow up.
+func (o *Handler) checkQuotas(ctx context.Context, product privateModel.ProductType, projectCounts map[int]int64) error {
+	productType, ok := quotaProducts[product]
+	if o.quotas == nil || !ok {
+		return nil
+	}
+	for projectID, count := range projectCounts {
+		// an unset status is computed when the items are flushed by the workers
+		exceeded, err := o.quotas.IsBillingQuotaExceeded(ctx, projectID, productType)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to check otel billing quota")
+			continue
+		}
+		if exceeded != nil && *exceeded {
+			return &quotaError{projectID: projectID, product: product}
+		}
+
+		softCapped, err := o.quotas.IsBillingQuotaSoftCapped(ctx, projectID, productType)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to check otel billing quota soft cap")
+			continue
+		}
+		if softCapped != nil && *softCapped {
+			itemsSoftCapped.add(float64(count), productSignal(product))
+		}
+	}
+	return nil
+}
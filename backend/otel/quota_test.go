@@ -0,0 +1,71 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockQuotaStore struct {
+	exceeded   map[int]bool
+	softCapped map[int]bool
+}
+
+func (m *mockQuotaStore) IsBillingQuotaExceeded(_ context.Context, projectId int, _ model.PricingProductType) (*bool, error) {
+	if exceeded, ok := m.exceeded[projectId]; ok {
+		return &exceeded, nil
+	}
+	return nil, nil
+}
+
+func (m *mockQuotaStore) IsBillingQuotaSoftCapped(_ context.Context, projectId int, _ model.PricingProductType) (*bool, error) {
+	if softCapped, ok := m.softCapped[projectId]; ok {
+		return &softCapped, nil
+	}
+	return nil, nil
+}
+
+func TestCheckQuotas(t *testing.T) {
+	ctx := context.Background()
+	h := Handler{quotas: &mockQuotaStore{
+		exceeded:   map[int]bool{1: false, 2: true, 3: false},
+		softCapped: map[int]bool{3: true},
+	}}
+
+	assert.NoError(t, h.checkQuotas(ctx, privateModel.ProductTypeLogs, map[int]int64{1: 10, 4: 10}))
+
+	err := h.checkQuotas(ctx, privateModel.ProductTypeTraces, map[int]int64{2: 10})
+	var quotaExceeded *quotaError
+	assert.ErrorAs(t, err, &quotaExceeded)
+	assert.Equal(t, 2, quotaExceeded.projectID)
+	assert.Equal(t, dropReasonQuotaExceeded, submitErrorReason(err))
+
+	// only the logs and traces quotas are enforced by the otel handlers
+	assert.NoError(t, h.checkQuotas(ctx, privateModel.ProductTypeErrors, map[int]int64{2: 1}))
+
+	before := itemsSoftCapped.get([]string{signalLogs}).value
+	assert.NoError(t, h.checkQuotas(ctx, privateModel.ProductTypeLogs, map[int]int64{3: 10}))
+	assert.Equal(t, before+10, itemsSoftCapped.get([]string{signalLogs}).value)
+
+	assert.NoError(t, (&Handler{}).checkQuotas(ctx, privateModel.ProductTypeLogs, map[int]int64{2: 10}))
+}
+
+func TestQuotaSubmitError(t *testing.T) {
+	err := &quotaError{projectID: 1, product: privateModel.ProductTypeLogs}
+
+	w := httptest.NewRecorder()
+	writeSubmitError(w, err)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	st := status.Convert(grpcSubmitError(err))
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Empty(t, st.Details())
+}
@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// rateLimitError rejects an export request because a project is over its per minute limit.
+// Exporters retry the request after retryAfter.
+type rateLimitError struct {
+	projectID  int
+	product    privateModel.ProductType
+	retryAfter time.Duration
+}
+
+func (r *rateLimitError) Error() string {
+	return fmt.Sprintf("project %d exceeded its %s rate limit, retry after %s", r.projectID, r.product, r.retryAfter)
+}
+
+// checkRateLimits takes the items of an export request from the token bucket of each project
+// with a per minute limit configured for the product. Unlike the per item rate sampling, which
+// silently drops items over the limit, the whole request is rejected so that exporters back off.
+func (o *Handler) checkRateLimits(ctx context.Context, product privateModel.ProductType, projectCounts map[int]int64) error {
+	if o.limiter == nil {
+		return nil
+	}
+	for projectID, count := range projectCounts {
+		perMinute := o.resolver.GetMinuteRateLimit(ctx, product, projectID)
+		if perMinute == nil {
+			continue
+		}
+		ok, retryAfter, err := o.limiter.Take(ctx, fmt.Sprintf("%d-%s", projectID, product), *perMinute, count)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to check otel rate limit")
+			continue
+		}
+		if !ok {
+			return &rateLimitError{projectID: projectID, product: product, retryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// writeSubmitError responds to an export request that could not be submitted,
+// asking exporters to retry later unless the project is over its billing quota.
+func writeSubmitError(w http.ResponseWriter, err error) {
+	var quotaExceeded *quotaError
+	if e.As(err, &quotaExceeded) {
+		http.Error(w, quotaExceeded.Error(), http.StatusPaymentRequired)
+		return
+	}
+	var rateLimited *rateLimitError
+	if e.As(err, &rateLimited) {
+		w.Header().Set(ratelimit.RetryAfterHeader, strconv.Itoa(int((rateLimited.retryAfter+time.Second-1)/time.Second)))
+		http.Error(w, rateLimited.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if e.Is(err, context.Canceled) {
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+}
+
+// grpcSubmitError is the grpc status of an export request that could not be submitted.
+func grpcSubmitError(err error) error {
+	var quotaExceeded *quotaError
+	if e.As(err, &quotaExceeded) {
+		// without retry info, exporters drop the data rather than retrying it
+		return status.Error(codes.ResourceExhausted, quotaExceeded.Error())
+	}
+	var rateLimited *rateLimitError
+	if e.As(err, &rateLimited) {
+		st, detailsErr := status.New(codes.ResourceExhausted, rateLimited.Error()).
+			WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(rateLimited.retryAfter)})
+		if detailsErr != nil {
+			return status.Error(codes.ResourceExhausted, rateLimited.Error())
+		}
+		return st.Err()
+	}
+	if e.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
@@ -0,0 +1,35 @@
+package otel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWriteSubmitError(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeSubmitError(w, &rateLimitError{projectID: 1, product: privateModel.ProductTypeLogs, retryAfter: 1500 * time.Millisecond})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	writeSubmitError(w, e.New("kafka is down"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGRPCSubmitError(t *testing.T) {
+	st := status.Convert(grpcSubmitError(&rateLimitError{projectID: 1, product: privateModel.ProductTypeTraces, retryAfter: 5 * time.Second}))
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Len(t, st.Details(), 1)
+	assert.Equal(t, 5*time.Second, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+
+	assert.Equal(t, codes.Unavailable, status.Code(grpcSubmitError(e.New("kafka is down"))))
+}
@@ -0,0 +1,60 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/redact"
+	e "github.com/pkg/errors"
+)
+
+// projectRedactors redacts the attributes of spans and logs with the rules of their project,
+// caching the rules for the duration of an export request.
+type projectRedactors struct {
+	projects  projectStore
+	byProject map[int]*redact.Redactor
+}
+
+func (o *Handler) newProjectRedactors() *projectRedactors {
+	return &projectRedactors{
+		projects:  o.projects,
+		byProject: make(map[int]*redact.Redactor),
+	}
+}
+
+func (p *projectRedactors) get(ctx context.Context, projectID int) (*redact.Redactor, error) {
+	if r, ok := p.byProject[projectID]; ok {
+		return r, nil
+	}
+	settings, err := p.projects.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		return nil, e.Wrapf(err, "failed to get redaction rules of project %d", projectID)
+	}
+	r, err := redact.Cached(settings.RedactedAttributeKeys, settings.RedactionPatterns)
+	if err != nil {
+		return nil, err
+	}
+	p.byProject[projectID] = r
+	return r, nil
+}
+
+// apply redacts the merged attributes and the span event and link attributes of the fields.
+// Items are rejected rather than written unredacted when the rules cannot be loaded.
+func (p *projectRedactors) apply(ctx context.Context, fields *extractedFields) error {
+	if p.projects == nil {
+		return nil
+	}
+	r, err := p.get(ctx, fields.projectIDInt)
+	if err != nil || r == nil {
+		return err
+	}
+
+	r.Attributes(fields.attrs)
+	for _, values := range [][]map[string]any{fields.events, fields.links} {
+		for _, value := range values {
+			if attrs, ok := value["Attributes"].(map[string]any); ok {
+				r.RawAttributes(attrs)
+			}
+		}
+	}
+	return nil
+}
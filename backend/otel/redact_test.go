@@ -0,0 +1,37 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectRedactors_Apply(t *testing.T) {
+	ctx := context.Background()
+	h := Handler{projects: &mockProjectStore{
+		redactedKeys:      []string{"authorization"},
+		redactionPatterns: []string{"email"},
+	}}
+
+	fields := newExtractedFields()
+	fields.projectIDInt = 1
+	fields.attrs = map[string]string{
+		"http.request.header.authorization": "Bearer abc",
+		"user.email":                        "jane@example.com",
+		"http.route":                        "/users",
+	}
+	fields.events = []map[string]any{{
+		"Name":       "login",
+		"Attributes": map[string]any{"email": "jane@example.com"},
+	}}
+
+	assert.NoError(t, h.newProjectRedactors().apply(ctx, fields))
+	assert.Equal(t, map[string]string{
+		"http.request.header.authorization": redact.Redacted,
+		"user.email":                        redact.Redacted,
+		"http.route":                        "/users",
+	}, fields.attrs)
+	assert.Equal(t, map[string]any{"email": redact.Redacted}, fields.events[0]["Attributes"])
+}
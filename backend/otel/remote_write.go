@@ -0,0 +1,387 @@
+package otel
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWritePath receives the samples of the Prometheus remote-write 1.0 protocol, so that
+// Prometheus servers and agents ship metrics without a collector.
+// See https://prometheus.io/docs/specs/remote_write_spec/
+const RemoteWritePath = "/metrics/v1/write"
+
+// RemoteWriteProjectLabel sets the project of a series, ie. as an external label of the Prometheus server,
+// when the request does not have the x-highlight-project header.
+const RemoteWriteProjectLabel = "highlight_project_id"
+
+const remoteWriteMetricNameLabel = "__name__"
+
+// series labels read into the fields of a metric row, in order of precedence
+var (
+	remoteWriteServiceLabels     = []string{"service_name", "job"}
+	remoteWriteEnvironmentLabels = []string{"environment", "env"}
+)
+
+// remoteWriteStaleNaN is the value of the samples that mark a series as stale, which are not stored.
+const remoteWriteStaleNaN = 0x7ff0000000000002
+
+// remoteWriteCounterSuffixes are the suffixes of the names of counter series, ie. of the buckets,
+// count and sum of a histogram, of the family of the metadata they are sent with.
+var remoteWriteCounterSuffixes = []string{"_total", "_bucket", "_count", "_sum"}
+
+// the counter types of the prometheus.MetricMetadata message
+const (
+	remoteWriteCounter   = 1
+	remoteWriteSummary   = 3
+	remoteWriteHistogram = 4
+)
+
+var (
+	errMissingMetricName          = e.New("series is missing the __name__ label")
+	errNativeHistogramUnsupported = e.New("native histograms are not supported")
+)
+
+type remoteWriteSample struct {
+	value     float64
+	timestamp int64
+}
+
+type remoteWriteSeries struct {
+	labels  map[string]string
+	samples []remoteWriteSample
+	// histograms is the number of native histogram samples of the series
+	histograms int
+}
+
+type remoteWriteMetadata struct {
+	metricType uint64
+	help       string
+	unit       string
+}
+
+// remoteWriteRequest is a decoded prometheus.WriteRequest message.
+type remoteWriteRequest struct {
+	series   []*remoteWriteSeries
+	metadata map[string]*remoteWriteMetadata
+}
+
+func (r *remoteWriteRequest) samples() int {
+	var count int
+	for _, series := range r.series {
+		count += len(series.samples) + series.histograms
+	}
+	return count
+}
+
+// readProtoFields calls fn with each field of a protobuf message: the bytes of length delimited
+// fields and the bits of varint and fixed size fields.
+func readProtoFields(b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, bits uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var value []byte
+		var bits uint64
+		switch typ {
+		case protowire.VarintType:
+			bits, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			bits, n = protowire.ConsumeFixed64(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			bits = uint64(v)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, typ, value, bits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRemoteWriteRequest decodes the series and metadata of a prometheus.WriteRequest message.
+// Exemplars are ignored.
+func parseRemoteWriteRequest(b []byte) (*remoteWriteRequest, error) {
+	req := &remoteWriteRequest{metadata: make(map[string]*remoteWriteMetadata)}
+	err := readProtoFields(b, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			series, err := parseRemoteWriteSeries(value)
+			if err != nil {
+				return err
+			}
+			req.series = append(req.series, series)
+		case 3:
+			var name string
+			metadata := &remoteWriteMetadata{}
+			if err := readProtoFields(value, func(num protowire.Number, typ protowire.Type, value []byte, bits uint64) error {
+				switch {
+				case num == 1 && typ == protowire.VarintType:
+					metadata.metricType = bits
+				case num == 2 && typ == protowire.BytesType:
+					name = string(value)
+				case num == 4 && typ == protowire.BytesType:
+					metadata.help = string(value)
+				case num == 5 && typ == protowire.BytesType:
+					metadata.unit = string(value)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			req.metadata[name] = metadata
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, e.Wrap(err, "invalid remote-write request")
+	}
+	return req, nil
+}
+
+func parseRemoteWriteSeries(b []byte) (*remoteWriteSeries, error) {
+	series := &remoteWriteSeries{labels: make(map[string]string)}
+	err := readProtoFields(b, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			var name, labelValue string
+			if err := readProtoFields(value, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+				if typ == protowire.BytesType && num == 1 {
+					name = string(value)
+				} else if typ == protowire.BytesType && num == 2 {
+					labelValue = string(value)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			series.labels[name] = labelValue
+		case 2:
+			var sample remoteWriteSample
+			if err := readProtoFields(value, func(num protowire.Number, typ protowire.Type, _ []byte, bits uint64) error {
+				if num == 1 && typ == protowire.Fixed64Type {
+					sample.value = math.Float64frombits(bits)
+				} else if num == 2 && typ == protowire.VarintType {
+					sample.timestamp = int64(bits)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			series.samples = append(series.samples, sample)
+		case 4:
+			series.histograms++
+		}
+		return nil
+	})
+	return series, err
+}
+
+// getRemoteWriteMetadata returns the metadata of the family of a series, and the suffix of the series
+// name when it is not the family name.
+func getRemoteWriteMetadata(name string, metadata map[string]*remoteWriteMetadata) (*remoteWriteMetadata, string) {
+	if m, ok := metadata[name]; ok {
+		return m, ""
+	}
+	for _, suffix := range remoteWriteCounterSuffixes {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			if m, ok := metadata[family]; ok {
+				return m, suffix
+			}
+		}
+	}
+	return nil, ""
+}
+
+// isRemoteWriteCounter returns whether the samples of a series are those of a monotonic counter.
+// Series without metadata are counters when their name has a conventional counter suffix.
+func isRemoteWriteCounter(name string, metadata map[string]*remoteWriteMetadata) bool {
+	m, suffix := getRemoteWriteMetadata(name, metadata)
+	if m == nil {
+		for _, suffix := range remoteWriteCounterSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+	switch m.metricType {
+	case remoteWriteCounter:
+		return true
+	case remoteWriteHistogram, remoteWriteSummary:
+		// the quantiles of a summary are gauges
+		return suffix != ""
+	}
+	return false
+}
+
+func popRemoteWriteLabel(labels map[string]string, names []string) string {
+	for _, name := range names {
+		if value, ok := labels[name]; ok {
+			delete(labels, name)
+			return value
+		}
+	}
+	return ""
+}
+
+// getRemoteWriteMetricRows converts the samples of a remote-write request to metric rows by project.
+// The project of a series is the header project, or its highlight_project_id label when it is unset.
+// The job label is the service name of the series and its other labels are the row attributes.
+func getRemoteWriteMetricRows(ctx context.Context, req *remoteWriteRequest, projectID string, auth *ingestAuthorizer) (map[string][]*clickhouse.MetricRow, *rejection) {
+	projectMetrics := make(map[string][]*clickhouse.MetricRow)
+	rejected := &rejection{}
+
+	for _, series := range req.series {
+		for i := 0; i < series.histograms; i++ {
+			rejected.add(errNativeHistogramUnsupported)
+		}
+		if len(series.samples) == 0 {
+			continue
+		}
+
+		labels := make(map[string]string, len(series.labels))
+		for k, v := range series.labels {
+			labels[k] = v
+		}
+		name := popRemoteWriteLabel(labels, []string{remoteWriteMetricNameLabel})
+		if name == "" {
+			for range series.samples {
+				rejected.add(errMissingMetricName)
+			}
+			continue
+		}
+
+		fields := newExtractedFields()
+		fields.projectID = popRemoteWriteLabel(labels, []string{RemoteWriteProjectLabel})
+		if projectID != "" {
+			fields.projectID = projectID
+		}
+		err := errMissingProject
+		if fields.projectID != "" {
+			fields.projectIDInt, err = projectToInt(fields.projectID)
+		}
+		if err == nil {
+			fields.projectID = strconv.Itoa(fields.projectIDInt)
+			err = auth.authorize(ctx, fields)
+		}
+		if err != nil {
+			lg(ctx, fields).WithError(err).WithField("metric", name).Info("rejected remote-write series")
+			for range series.samples {
+				rejected.add(err)
+			}
+			continue
+		}
+		fields.serviceName = popRemoteWriteLabel(labels, remoteWriteServiceLabels)
+		fields.environment = popRemoteWriteLabel(labels, remoteWriteEnvironmentLabels)
+
+		metricType, temporality := clickhouse.MetricTypeGauge, ""
+		isCounter := isRemoteWriteCounter(name, req.metadata)
+		if isCounter {
+			metricType, temporality = clickhouse.MetricTypeSum, "Cumulative"
+		}
+		var description, unit string
+		if m, _ := getRemoteWriteMetadata(name, req.metadata); m != nil {
+			description, unit = m.help, m.unit
+		}
+
+		for _, sample := range series.samples {
+			if math.Float64bits(sample.value) == remoteWriteStaleNaN {
+				continue
+			}
+			projectMetrics[fields.projectID] = append(projectMetrics[fields.projectID], &clickhouse.MetricRow{
+				ProjectId:              uint32(fields.projectIDInt),
+				Timestamp:              time.UnixMilli(sample.timestamp),
+				StartTimestamp:         time.Unix(0, 0),
+				ServiceName:            fields.serviceName,
+				Environment:            fields.environment,
+				MetricName:             name,
+				MetricDescription:      description,
+				MetricUnit:             unit,
+				MetricType:             metricType,
+				Attributes:             labels,
+				Value:                  sample.value,
+				AggregationTemporality: temporality,
+				IsMonotonic:            isCounter,
+			})
+		}
+	}
+
+	return projectMetrics, rejected
+}
+
+// HandleRemoteWrite writes the samples of a Prometheus remote-write request to the project of the
+// x-highlight-project header, or of the ingest key header when the project header is unset.
+func (o *Handler) HandleRemoteWrite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if strings.Contains(r.Header.Get("Content-Type"), "io.prometheus.write.v2.Request") {
+		http.Error(w, "remote-write 2.0 is not supported, use the 1.0 protocol", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid remote-write body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	req, err := parseRemoteWriteRequest(body)
+	payloadBytes.add(float64(len(body)), signalMetrics)
+	release()
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid remote-write payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	auth := o.newIngestAuthorizer(ctx)
+	projectID := r.Header.Get(ratelimit.ProjectHeader)
+	if projectID == "" && auth.requestProjectID != 0 {
+		projectID = strconv.Itoa(auth.requestProjectID)
+	}
+	projectMetrics, rejected := getRemoteWriteMetricRows(ctx, req, projectID, auth)
+
+	err = o.submitMetricRows(ctx, projectMetrics)
+	recordSubmission(samplesReceived, signalMetrics, req.samples(), rejected, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit remote-write metrics")
+		writeSubmitError(w, err)
+		return
+	}
+	// prometheus retries requests on server errors only, so rejected samples are a client error
+	if len(projectMetrics) == 0 && rejected.count > 0 {
+		status := http.StatusBadRequest
+		if e.Is(rejected.err, errInvalidIngestKey) || e.Is(rejected.err, errIngestKeyProject) || e.Is(rejected.err, errIngestKeyRequired) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, rejected.message(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
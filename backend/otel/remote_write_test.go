@@ -0,0 +1,195 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	public "github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testRemoteWriteSeries struct {
+	labels     map[string]string
+	samples    []remoteWriteSample
+	histograms int
+}
+
+// encodeRemoteWriteRequest encodes a prometheus.WriteRequest message.
+func encodeRemoteWriteRequest(series []testRemoteWriteSeries, metadata map[string]*remoteWriteMetadata) []byte {
+	appendBytes := func(b []byte, num protowire.Number, value []byte) []byte {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendBytes(b, value)
+	}
+
+	var req []byte
+	for _, s := range series {
+		names := make([]string, 0, len(s.labels))
+		for name := range s.labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var ts []byte
+		for _, name := range names {
+			label := appendBytes(nil, 1, []byte(name))
+			label = appendBytes(label, 2, []byte(s.labels[name]))
+			ts = appendBytes(ts, 1, label)
+		}
+		for _, sample := range s.samples {
+			b := protowire.AppendTag(nil, 1, protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, math.Float64bits(sample.value))
+			b = protowire.AppendTag(b, 2, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(sample.timestamp))
+			ts = appendBytes(ts, 2, b)
+		}
+		for i := 0; i < s.histograms; i++ {
+			ts = appendBytes(ts, 4, nil)
+		}
+		req = appendBytes(req, 1, ts)
+	}
+	for name, m := range metadata {
+		b := protowire.AppendTag(nil, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, m.metricType)
+		b = appendBytes(b, 2, []byte(name))
+		b = appendBytes(b, 4, []byte(m.help))
+		b = appendBytes(b, 5, []byte(m.unit))
+		req = appendBytes(req, 3, b)
+	}
+	return req
+}
+
+func newTestRemoteWriteRequest() []byte {
+	ts := time.UnixMilli(1700000000000).UnixMilli()
+	return encodeRemoteWriteRequest([]testRemoteWriteSeries{
+		{
+			labels:  map[string]string{"__name__": "node_memory_usage_bytes", "job": "node", "instance": "web-1:9100", "env": "production"},
+			samples: []remoteWriteSample{{value: 1024, timestamp: ts}, {value: 2048, timestamp: ts + 15000}},
+		},
+		{
+			labels:  map[string]string{"__name__": "http_requests_total", "job": "api", "code": "200"},
+			samples: []remoteWriteSample{{value: 42, timestamp: ts}, {value: math.Float64frombits(remoteWriteStaleNaN), timestamp: ts + 15000}},
+		},
+		{
+			labels:  map[string]string{"__name__": "rpc_duration_seconds", "quantile": "0.99"},
+			samples: []remoteWriteSample{{value: 0.3, timestamp: ts}},
+		},
+		{
+			labels:  map[string]string{"__name__": "rpc_duration_seconds_count"},
+			samples: []remoteWriteSample{{value: 10, timestamp: ts}},
+		},
+		{
+			labels:     map[string]string{"__name__": "native_histogram"},
+			histograms: 1,
+		},
+		{
+			labels:  map[string]string{"job": "unnamed"},
+			samples: []remoteWriteSample{{value: 1, timestamp: ts}},
+		},
+	}, map[string]*remoteWriteMetadata{
+		"node_memory_usage_bytes": {metricType: 2, help: "Memory in use.", unit: "bytes"},
+		"rpc_duration_seconds":    {metricType: remoteWriteSummary},
+	})
+}
+
+func TestParseRemoteWriteRequest(t *testing.T) {
+	req, err := parseRemoteWriteRequest(newTestRemoteWriteRequest())
+	assert.NoError(t, err)
+	assert.Len(t, req.series, 6)
+	assert.Equal(t, "node", req.series[0].labels["job"])
+	assert.Equal(t, []remoteWriteSample{{value: 1024, timestamp: 1700000000000}, {value: 2048, timestamp: 1700000015000}}, req.series[0].samples)
+	assert.Equal(t, 1, req.series[4].histograms)
+	assert.Equal(t, 8, req.samples())
+	assert.Equal(t, &remoteWriteMetadata{metricType: 2, help: "Memory in use.", unit: "bytes"}, req.metadata["node_memory_usage_bytes"])
+
+	_, err = parseRemoteWriteRequest([]byte("not protobuf"))
+	assert.Error(t, err)
+}
+
+func TestGetRemoteWriteMetricRows(t *testing.T) {
+	req, err := parseRemoteWriteRequest(newTestRemoteWriteRequest())
+	assert.NoError(t, err)
+
+	projectMetrics, rejected := getRemoteWriteMetricRows(context.Background(), req, "1", &ingestAuthorizer{})
+	// the native histogram and the series without a name
+	assert.Equal(t, int64(2), rejected.count)
+	assert.Len(t, projectMetrics, 1)
+
+	rows := projectMetrics["1"]
+	assert.Len(t, rows, 5)
+
+	gauge := rows[0]
+	assert.Equal(t, uint32(1), gauge.ProjectId)
+	assert.Equal(t, "node_memory_usage_bytes", gauge.MetricName)
+	assert.Equal(t, clickhouse.MetricTypeGauge, gauge.MetricType)
+	assert.Equal(t, "Memory in use.", gauge.MetricDescription)
+	assert.Equal(t, "bytes", gauge.MetricUnit)
+	assert.Equal(t, 1024., gauge.Value)
+	assert.Equal(t, time.UnixMilli(1700000000000), gauge.Timestamp)
+	assert.Equal(t, "node", gauge.ServiceName)
+	assert.Equal(t, "production", gauge.Environment)
+	assert.Equal(t, map[string]string{"instance": "web-1:9100"}, gauge.Attributes)
+	assert.Equal(t, 2048., rows[1].Value)
+
+	// the stale marker of the counter is not stored
+	counter := rows[2]
+	assert.Equal(t, "http_requests_total", counter.MetricName)
+	assert.Equal(t, clickhouse.MetricTypeSum, counter.MetricType)
+	assert.True(t, counter.IsMonotonic)
+	assert.Equal(t, "Cumulative", counter.AggregationTemporality)
+	assert.Equal(t, 42., counter.Value)
+
+	assert.Equal(t, clickhouse.MetricTypeGauge, rows[3].MetricType)
+	assert.Equal(t, clickhouse.MetricTypeSum, rows[4].MetricType)
+
+	// series with a project label are written to its project
+	req, err = parseRemoteWriteRequest(encodeRemoteWriteRequest([]testRemoteWriteSeries{{
+		labels:  map[string]string{"__name__": "up", RemoteWriteProjectLabel: "2"},
+		samples: []remoteWriteSample{{value: 1, timestamp: 1700000000000}},
+	}}, nil))
+	assert.NoError(t, err)
+	projectMetrics, rejected = getRemoteWriteMetricRows(context.Background(), req, "", &ingestAuthorizer{})
+	assert.Equal(t, int64(0), rejected.count)
+	assert.Len(t, projectMetrics["2"], 1)
+	assert.Empty(t, projectMetrics["2"][0].Attributes)
+}
+
+func TestHandler_HandleRemoteWrite(t *testing.T) {
+	producer := MockKafkaProducer{}
+	h := Handler{resolver: &public.Resolver{BatchedQueue: &producer}, projects: newMockProjectStore()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, RemoteWritePath, bytes.NewReader(snappy.Encode(nil, newTestRemoteWriteRequest())))
+	r.Header.Set("Content-Encoding", "snappy")
+	r.Header.Set("Content-Type", "application/x-protobuf")
+	r.Header.Set(ratelimit.ProjectHeader, "1")
+	h.HandleRemoteWrite(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, producer.messages, 1)
+	assert.Equal(t, kafkaqueue.PushOTeLMetrics, producer.messages[0].Type)
+	assert.Len(t, producer.messages[0].PushOTeLMetrics.MetricRows, 5)
+
+	// project 2 requires an ingest key
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, RemoteWritePath, bytes.NewReader(snappy.Encode(nil, newTestRemoteWriteRequest())))
+	r.Header.Set("Content-Encoding", "snappy")
+	r.Header.Set(ratelimit.ProjectHeader, "2")
+	h.HandleRemoteWrite(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, RemoteWritePath, bytes.NewReader(nil))
+	r.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
+	h.HandleRemoteWrite(w, r)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
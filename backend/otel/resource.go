@@ -0,0 +1,40 @@
+package otel
+
+import "github.com/highlight-run/highlight/backend/clickhouse"
+
+// k8sNamespaceAttribute is the namespace attribute set by older kubernetes detectors.
+const k8sNamespaceAttribute = "k8s.namespace"
+
+// detectResource returns the kubernetes pod, container and host described by the attributes
+// and the remaining attributes, so that the resource is written to its own log columns.
+func detectResource(attrs map[string]string) (clickhouse.LogResource, map[string]string) {
+	var resource clickhouse.LogResource
+	detected := map[string]*string{
+		clickhouse.LogResourceK8sPodNameAttribute:   &resource.K8sPodName,
+		clickhouse.LogResourceK8sNamespaceAttribute: &resource.K8sNamespace,
+		k8sNamespaceAttribute:                       &resource.K8sNamespace,
+		clickhouse.LogResourceContainerIDAttribute:  &resource.ContainerID,
+		clickhouse.LogResourceHostNameAttribute:     &resource.HostName,
+	}
+
+	remaining := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if value, ok := detected[k]; ok {
+			if *value == "" || k != k8sNamespaceAttribute {
+				*value = v
+			}
+			continue
+		}
+		remaining[k] = v
+	}
+	return resource, remaining
+}
+
+// withLogAttributes sets the attributes of a log row, writing its resource to the resource columns.
+func withLogAttributes(attrs map[string]string) clickhouse.LogRowOption {
+	resource, logAttrs := detectResource(attrs)
+	return func(l *clickhouse.LogRow) {
+		clickhouse.WithLogAttributes(logAttrs)(l)
+		clickhouse.WithResource(resource)(l)
+	}
+}
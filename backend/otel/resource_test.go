@@ -0,0 +1,31 @@
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectResource(t *testing.T) {
+	resource, attrs := detectResource(map[string]string{
+		"k8s.pod.name":       "api-5d8f7",
+		"k8s.namespace":      "legacy",
+		"k8s.namespace.name": "production",
+		"container.id":       "3f2a",
+		"host.name":          "node-1",
+		"user.id":            "42",
+	})
+	assert.Equal(t, clickhouse.LogResource{
+		K8sPodName:   "api-5d8f7",
+		K8sNamespace: "production",
+		ContainerID:  "3f2a",
+		HostName:     "node-1",
+	}, resource)
+	assert.Equal(t, map[string]string{"user.id": "42"}, attrs)
+
+	logRow := clickhouse.NewLogRow(time.Now(), 1, withLogAttributes(map[string]string{"k8s.namespace": "legacy"}))
+	assert.Equal(t, "legacy", logRow.K8sNamespace)
+	assert.Empty(t, logRow.LogAttributes)
+}
@@ -0,0 +1,82 @@
+package otel
+
+import (
+	"fmt"
+	"net/http"
+
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+var errMissingProject = e.New("missing highlight.project_id attribute")
+
+// rejection counts the spans, log records or datapoints of an export request that were dropped,
+// reported to exporters as an OTLP partial success.
+type rejection struct {
+	count int64
+	err   error
+	// warning is reported in the partial success even when no items are rejected
+	warning string
+}
+
+func (r *rejection) add(err error) {
+	r.count++
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *rejection) warn(warning string) {
+	if r.warning == "" {
+		r.warning = warning
+	}
+}
+
+// partial returns whether the export response should report a partial success.
+func (r *rejection) partial() bool {
+	return r.count > 0 || r.warning != ""
+}
+
+func (r *rejection) message() string {
+	var message string
+	if r.err == nil {
+		message = fmt.Sprintf("rejected %d items", r.count)
+	} else {
+		message = fmt.Sprintf("rejected %d items: %s", r.count, r.err.Error())
+	}
+	if r.warning == "" {
+		return message
+	}
+	if r.count == 0 {
+		return r.warning
+	}
+	return message + "; " + r.warning
+}
+
+type otlpResponse interface {
+	MarshalProto() ([]byte, error)
+	MarshalJSON() ([]byte, error)
+}
+
+// writeResponse writes the export response in the wire format of the request.
+func writeResponse(w http.ResponseWriter, r *http.Request, resp otlpResponse) {
+	var body []byte
+	var err error
+	if isJSONRequest(r) {
+		w.Header().Set("Content-Type", "application/json")
+		body, err = resp.MarshalJSON()
+	} else {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		body, err = resp.MarshalProto()
+	}
+	if err != nil {
+		log.WithContext(r.Context()).WithError(err).Error("failed to marshal otel export response")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.WithContext(r.Context()).WithError(err).Error("failed to write otel export response")
+	}
+}
@@ -0,0 +1,159 @@
+package otel
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/severity"
+)
+
+// ingestExclusions are the services and log levels whose spans and logs a project drops at ingest,
+// along with the filter rules that take precedence over them.
+type ingestExclusions struct {
+	serviceNames map[string]bool
+	logLevels    map[privateModel.LogLevel]bool
+	rules        []*ingestFilterRule
+}
+
+type ingestFilterRule struct {
+	*model.IngestFilterRule
+	attributeRegex *regexp.Regexp
+}
+
+// matches returns whether a span or log matches all of the set conditions of the rule.
+// A nil level is a span, which rules with a severity never match.
+func (r *ingestFilterRule) matches(fields *extractedFields, level *privateModel.LogLevel) bool {
+	if r.ServiceName != nil && *r.ServiceName != "" && *r.ServiceName != fields.serviceName {
+		return false
+	}
+	if r.Severity != nil && *r.Severity != "" && (level == nil || *r.Severity != level.String()) {
+		return false
+	}
+	if r.AttributeKey != nil && *r.AttributeKey != "" {
+		value, ok := fields.attrs[*r.AttributeKey]
+		if !ok || (r.attributeRegex != nil && !r.attributeRegex.MatchString(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns the action of the first rule matching a span or log, if any.
+func (exclusions *ingestExclusions) filter(fields *extractedFields, level *privateModel.LogLevel) (privateModel.IngestFilterRuleAction, bool) {
+	for _, rule := range exclusions.rules {
+		if rule.matches(fields, level) {
+			return rule.Action, true
+		}
+	}
+	return "", false
+}
+
+// ingestSampler drops the spans and logs excluded by the project settings before they are submitted,
+// caching the settings for the duration of an export request. It counts the items dropped at ingest,
+// including those sampled out by the resolver, so that they can be reported with the project usage.
+type ingestSampler struct {
+	projects   projectStore
+	byProject  map[int]*ingestExclusions
+	sampledOut map[int]map[string]int64
+}
+
+func (o *Handler) newIngestSampler() *ingestSampler {
+	return &ingestSampler{
+		projects:   o.projects,
+		byProject:  make(map[int]*ingestExclusions),
+		sampledOut: make(map[int]map[string]int64),
+	}
+}
+
+func (s *ingestSampler) exclusions(ctx context.Context, projectID int) *ingestExclusions {
+	if exclusions, ok := s.byProject[projectID]; ok {
+		return exclusions
+	}
+	exclusions := &ingestExclusions{
+		serviceNames: make(map[string]bool),
+		logLevels:    make(map[privateModel.LogLevel]bool),
+	}
+	if s.projects != nil {
+		settings, err := s.projects.GetProjectFilterSettings(ctx, projectID)
+		if err != nil {
+			lg(ctx, nil).WithError(err).WithField("project_id", projectID).Error("failed to get ingest exclusions")
+		} else {
+			for _, name := range settings.ExcludedServiceNames {
+				exclusions.serviceNames[name] = true
+			}
+			for _, level := range settings.ExcludedLogLevels {
+				exclusions.logLevels[severity.Level(level)] = true
+			}
+		}
+		rules, err := s.projects.GetEnabledIngestFilterRules(ctx, projectID)
+		if err != nil {
+			lg(ctx, nil).WithError(err).WithField("project_id", projectID).Error("failed to get ingest filter rules")
+		}
+		for _, rule := range rules {
+			filterRule := &ingestFilterRule{IngestFilterRule: rule}
+			if rule.AttributeRegex != nil && *rule.AttributeRegex != "" {
+				if filterRule.attributeRegex, err = regexp.Compile(*rule.AttributeRegex); err != nil {
+					lg(ctx, nil).WithError(err).WithField("project_id", projectID).WithField("rule_id", rule.ID).Error("invalid ingest filter rule regex")
+					continue
+				}
+			}
+			exclusions.rules = append(exclusions.rules, filterRule)
+		}
+	}
+	s.byProject[projectID] = exclusions
+	return exclusions
+}
+
+// excludesSpan drops the trace of spans matching a drop rule, or of excluded services when no rule matches.
+// The exceptions of an excluded span are still written as errors.
+func (s *ingestSampler) excludesSpan(ctx context.Context, fields *extractedFields) bool {
+	exclusions := s.exclusions(ctx, fields.projectIDInt)
+	action, matched := exclusions.filter(fields, nil)
+	if matched && action != privateModel.IngestFilterRuleActionDrop {
+		return false
+	}
+	if !matched && !exclusions.serviceNames[fields.serviceName] {
+		return false
+	}
+	s.drop(fields.projectIDInt, privateModel.ProductTypeTraces, privateModel.IngestReasonFilter)
+	return true
+}
+
+// excludesLog drops the logs matching a drop rule, or of excluded services and log levels when no rule matches.
+func (s *ingestSampler) excludesLog(ctx context.Context, fields *extractedFields, level privateModel.LogLevel) bool {
+	exclusions := s.exclusions(ctx, fields.projectIDInt)
+	action, matched := exclusions.filter(fields, &level)
+	if matched && action != privateModel.IngestFilterRuleActionDrop {
+		return false
+	}
+	if !matched && !exclusions.serviceNames[fields.serviceName] && !exclusions.logLevels[level] {
+		return false
+	}
+	s.drop(fields.projectIDInt, privateModel.ProductTypeLogs, privateModel.IngestReasonFilter)
+	return true
+}
+
+// drop counts an item of the project dropped at ingest.
+func (s *ingestSampler) drop(projectID int, product privateModel.ProductType, reason privateModel.IngestReason) {
+	if _, ok := s.sampledOut[projectID]; !ok {
+		s.sampledOut[projectID] = make(map[string]int64)
+	}
+	s.sampledOut[projectID][redis.SampledOutField(product.String(), reason.String())]++
+	recordDropped(product, reason, 1)
+}
+
+// recordSampledOut adds the items dropped while handling an export request to the daily counts of their projects.
+func (o *Handler) recordSampledOut(ctx context.Context, sampler *ingestSampler) {
+	if len(sampler.sampledOut) == 0 || o.resolver.Redis == nil {
+		return
+	}
+	for projectID, counts := range sampler.sampledOut {
+		if err := o.resolver.Redis.IncrementSampledOut(ctx, projectID, time.Now(), counts); err != nil {
+			lg(ctx, nil).WithError(err).WithField("project_id", projectID).Error("failed to record sampled out counts")
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngestSampler(t *testing.T) {
+	ctx := context.Background()
+	h := Handler{projects: &mockProjectStore{
+		excludedServices:  []string{"health-check"},
+		excludedLogLevels: []string{"debug", "TRACE"},
+	}}
+	sampler := h.newIngestSampler()
+
+	assert.False(t, sampler.excludesSpan(ctx, &extractedFields{projectIDInt: 1, serviceName: "api"}))
+	assert.True(t, sampler.excludesSpan(ctx, &extractedFields{projectIDInt: 1, serviceName: "health-check"}))
+
+	assert.False(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "api"}, privateModel.LogLevelInfo))
+	assert.True(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "api"}, privateModel.LogLevelDebug))
+	assert.True(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "api"}, privateModel.LogLevelTrace))
+	assert.True(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "health-check"}, privateModel.LogLevelError))
+
+	sampler.drop(1, privateModel.ProductTypeLogs, privateModel.IngestReasonSample)
+	assert.Equal(t, map[int]map[string]int64{
+		1: {
+			"Traces:Filter": 1,
+			"Logs:Filter":   3,
+			"Logs:Sample":   1,
+		},
+	}, sampler.sampledOut)
+}
+
+func TestIngestSampler_FilterRules(t *testing.T) {
+	ctx := context.Background()
+	h := Handler{projects: &mockProjectStore{
+		excludedLogLevels: []string{"debug"},
+		filterRules: []*model.IngestFilterRule{
+			{Action: privateModel.IngestFilterRuleActionKeep, ServiceName: ptr.String("payments"), Severity: ptr.String("debug")},
+			{Action: privateModel.IngestFilterRuleActionDrop, AttributeKey: ptr.String("http.target"), AttributeRegex: ptr.String("^/health")},
+			{Action: privateModel.IngestFilterRuleActionDrop, ServiceName: ptr.String("worker"), Severity: ptr.String("info")},
+			{Action: privateModel.IngestFilterRuleActionDrop, AttributeKey: ptr.String("invalid"), AttributeRegex: ptr.String("(")},
+		},
+	}}
+	sampler := h.newIngestSampler()
+
+	healthCheck := map[string]string{"http.target": "/healthz"}
+	assert.True(t, sampler.excludesSpan(ctx, &extractedFields{projectIDInt: 1, serviceName: "api", attrs: healthCheck}))
+	assert.False(t, sampler.excludesSpan(ctx, &extractedFields{projectIDInt: 1, serviceName: "api", attrs: map[string]string{"http.target": "/users"}}))
+	assert.False(t, sampler.excludesSpan(ctx, &extractedFields{projectIDInt: 1, serviceName: "api", attrs: map[string]string{"invalid": "("}}))
+	// rules with a severity only match logs
+	assert.False(t, sampler.excludesSpan(ctx, &extractedFields{projectIDInt: 1, serviceName: "worker"}))
+
+	assert.True(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "worker"}, privateModel.LogLevelInfo))
+	assert.False(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "worker"}, privateModel.LogLevelWarn))
+	// keep rules take precedence over the excluded log levels
+	assert.False(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "payments"}, privateModel.LogLevelDebug))
+	assert.True(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "api"}, privateModel.LogLevelDebug))
+	assert.True(t, sampler.excludesLog(ctx, &extractedFields{projectIDInt: 1, serviceName: "payments", attrs: healthCheck}, privateModel.LogLevelInfo))
+
+	assert.Equal(t, map[string]int64{
+		"Traces:Filter": 1,
+		"Logs:Filter":   3,
+	}, sampler.sampledOut[1])
+}
@@ -0,0 +1,145 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	e "github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// SDKBlocklistEnvVar lists SDK versions with known bugs as comma separated `name@version[:action]`
+// entries, ie. `opentelemetry@1.21.0,highlight-go@0.9.*:warn`. A version ending with `*` matches
+// versions with that prefix. Data from a `reject` version, the default action, is rejected,
+// while a `warn` version is ingested with a warning in the export response.
+const SDKBlocklistEnvVar = "OTEL_SDK_VERSION_BLOCKLIST"
+
+type sdkAction string
+
+const (
+	sdkActionReject sdkAction = "reject"
+	sdkActionWarn   sdkAction = "warn"
+)
+
+type blockedSDK struct {
+	name    string
+	version string
+	// prefix matches the versions starting with version
+	prefix bool
+	action sdkAction
+}
+
+func (b blockedSDK) matches(name string, version string) bool {
+	if b.name != name {
+		return false
+	}
+	if b.prefix {
+		return strings.HasPrefix(version, b.version)
+	}
+	return b.version == version
+}
+
+type SDKBlocklist []blockedSDK
+
+// ParseSDKBlocklist parses the entries of SDKBlocklistEnvVar.
+func ParseSDKBlocklist(value string) (SDKBlocklist, error) {
+	var blocklist SDKBlocklist
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		sdk, action, _ := strings.Cut(entry, ":")
+		name, version, ok := strings.Cut(sdk, "@")
+		if !ok || name == "" || version == "" {
+			return nil, e.Errorf("invalid sdk blocklist entry %q", entry)
+		}
+		blocked := blockedSDK{name: name, version: version, action: sdkActionReject}
+		if strings.HasSuffix(version, "*") {
+			blocked.version, blocked.prefix = strings.TrimSuffix(version, "*"), true
+		}
+		switch sdkAction(action) {
+		case "", sdkActionReject:
+		case sdkActionWarn:
+			blocked.action = sdkActionWarn
+		default:
+			return nil, e.Errorf("invalid sdk blocklist action %q", action)
+		}
+		blocklist = append(blocklist, blocked)
+	}
+	return blocklist, nil
+}
+
+// LoadSDKBlocklist returns the blocklist configured by the environment.
+func LoadSDKBlocklist() (SDKBlocklist, error) {
+	return ParseSDKBlocklist(os.Getenv(SDKBlocklistEnvVar))
+}
+
+func (l SDKBlocklist) match(name string, version string) (sdkAction, bool) {
+	for _, blocked := range l {
+		if blocked.matches(name, version) {
+			return blocked.action, true
+		}
+	}
+	return "", false
+}
+
+type sdkFingerprint struct {
+	projectID int
+	name      string
+	version   string
+}
+
+// sdkFingerprints records the SDK versions that projects send data from and enforces the
+// blocklist, checking each SDK version once per export request.
+type sdkFingerprints struct {
+	projects  projectStore
+	blocklist SDKBlocklist
+	checked   map[sdkFingerprint]error
+}
+
+func (o *Handler) newSDKFingerprints() *sdkFingerprints {
+	return &sdkFingerprints{
+		projects:  o.projects,
+		blocklist: o.sdkBlocklist,
+		checked:   make(map[sdkFingerprint]error),
+	}
+}
+
+// check records the SDK of a span or log, returning an error when its version is rejected.
+// Warnings about the version are added to the export response.
+func (s *sdkFingerprints) check(ctx context.Context, fields *extractedFields, rejected *rejection) error {
+	fingerprint := sdkFingerprint{
+		projectID: fields.projectIDInt,
+		name:      fields.attrs[string(semconv.TelemetrySDKNameKey)],
+		version:   fields.attrs[string(semconv.TelemetrySDKVersionKey)],
+	}
+	if fingerprint.name == "" || fingerprint.version == "" {
+		return nil
+	}
+	if err, ok := s.checked[fingerprint]; ok {
+		return err
+	}
+
+	if s.projects != nil {
+		if err := s.projects.RecordProjectSDK(ctx, fingerprint.projectID, fingerprint.name, fingerprint.version); err != nil {
+			lg(ctx, fields).WithError(err).Error("failed to record otel sdk")
+		}
+	}
+
+	var err error
+	if action, ok := s.blocklist.match(fingerprint.name, fingerprint.version); ok {
+		message := fmt.Sprintf("sdk %s version %s has known issues, please upgrade", fingerprint.name, fingerprint.version)
+		lg(ctx, fields).WithField("sdk_name", fingerprint.name).WithField("sdk_version", fingerprint.version).
+			WithField("action", action).Warn("otel data from blocklisted sdk version")
+		if action == sdkActionReject {
+			err = e.New(message)
+		} else {
+			rejected.warn(message)
+		}
+	}
+	s.checked[fingerprint] = err
+	return err
+}
@@ -0,0 +1,55 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestParseSDKBlocklist(t *testing.T) {
+	blocklist, err := ParseSDKBlocklist(" opentelemetry@1.21.0, highlight-go@0.9.*:warn ,")
+	assert.NoError(t, err)
+	assert.Equal(t, SDKBlocklist{
+		{name: "opentelemetry", version: "1.21.0", action: sdkActionReject},
+		{name: "highlight-go", version: "0.9.", prefix: true, action: sdkActionWarn},
+	}, blocklist)
+
+	blocklist, err = ParseSDKBlocklist("")
+	assert.NoError(t, err)
+	assert.Empty(t, blocklist)
+
+	for _, value := range []string{"opentelemetry", "@1.0.0", "opentelemetry@1.0.0:ignore"} {
+		_, err := ParseSDKBlocklist(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSDKFingerprints(t *testing.T) {
+	ctx := context.Background()
+	blocklist, err := ParseSDKBlocklist("opentelemetry@1.21.0,highlight-go@0.9.*:warn")
+	assert.NoError(t, err)
+	projects := newMockProjectStore()
+	h := Handler{projects: projects, sdkBlocklist: blocklist}
+	sdks := h.newSDKFingerprints()
+
+	newFields := func(projectID int, name string, version string) *extractedFields {
+		fields := newExtractedFields()
+		fields.projectIDInt = projectID
+		fields.attrs[string(semconv.TelemetrySDKNameKey)] = name
+		fields.attrs[string(semconv.TelemetrySDKVersionKey)] = version
+		return fields
+	}
+
+	rejected := &rejection{}
+	assert.NoError(t, sdks.check(ctx, newFields(1, "opentelemetry", "1.22.0"), rejected))
+	assert.NoError(t, sdks.check(ctx, newFields(1, "opentelemetry", "1.22.0"), rejected))
+	assert.Error(t, sdks.check(ctx, newFields(2, "opentelemetry", "1.21.0"), rejected))
+	assert.NoError(t, sdks.check(ctx, newFields(1, "highlight-go", "0.9.3"), rejected))
+	assert.NoError(t, sdks.check(ctx, newFields(1, "", ""), rejected))
+
+	assert.Equal(t, []string{"1:opentelemetry@1.22.0", "2:opentelemetry@1.21.0", "1:highlight-go@0.9.3"}, projects.sdks)
+	assert.True(t, rejected.partial())
+	assert.Equal(t, "sdk highlight-go version 0.9.3 has known issues, please upgrade", rejected.message())
+}
@@ -0,0 +1,207 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+// SegmentPath receives the calls of a Segment webhook destination, so that the identify and track
+// calls of an app instrumented with Segment populate the sessions of the project. The shared secret
+// of the destination is the project secret.
+const SegmentPath = "/integrations/segment/{project_id}"
+
+// SegmentSignatureHeader is the hex encoded HMAC-SHA1 of the body of a call, signed with the shared
+// secret of the destination.
+const SegmentSignatureHeader = "x-signature"
+
+var errInvalidSegmentSignature = e.New("invalid segment signature")
+
+// segmentCallTypes are the calls that are written to sessions, other calls are accepted and dropped.
+var segmentCallTypes = map[string]bool{"identify": true, "track": true}
+
+// segmentCall is a call of the Segment spec, ie. https://segment.com/docs/connections/spec/common/
+type segmentCall struct {
+	Type       string         `json:"type"`
+	MessageID  string         `json:"messageId"`
+	UserID     string         `json:"userId"`
+	Event      string         `json:"event"`
+	Traits     map[string]any `json:"traits"`
+	Properties map[string]any `json:"properties"`
+	Timestamp  time.Time      `json:"timestamp"`
+	Context    struct {
+		IP     string         `json:"ip"`
+		Traits map[string]any `json:"traits"`
+		// Highlight holds the session of the call, ie. `{"highlight": {"session_id": "..."}}`.
+		Highlight struct {
+			SessionID string `json:"session_id"`
+		} `json:"highlight"`
+	} `json:"context"`
+}
+
+// parseSegmentCalls decodes a single call, a batch of calls (`{"batch": [...]}`) or a list of calls.
+func parseSegmentCalls(body []byte) ([]*segmentCall, error) {
+	body = bytes.TrimSpace(body)
+	var calls []*segmentCall
+	if bytes.HasPrefix(body, []byte("[")) {
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil, e.Wrap(err, "invalid segment calls")
+		}
+		return calls, nil
+	}
+
+	var batch struct {
+		Batch []*segmentCall `json:"batch"`
+	}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, e.Wrap(err, "invalid segment call")
+	}
+	if batch.Batch != nil {
+		return batch.Batch, nil
+	}
+	call := &segmentCall{}
+	if err := json.Unmarshal(body, call); err != nil {
+		return nil, e.Wrap(err, "invalid segment call")
+	}
+	return []*segmentCall{call}, nil
+}
+
+// getSegmentEvent maps a call onto the arguments of a segment event message. The session is the
+// session_id of the highlight context of the call, or its highlight.session_id trait or property.
+func getSegmentEvent(projectID int, call *segmentCall) *kafkaqueue.PushSegmentEventArgs {
+	event := &kafkaqueue.PushSegmentEventArgs{
+		ProjectID:       projectID,
+		Type:            call.Type,
+		SessionSecureID: call.Context.Highlight.SessionID,
+		UserID:          call.UserID,
+		IP:              call.Context.IP,
+		Event:           call.Event,
+		Properties:      call.Properties,
+		Timestamp:       call.Timestamp,
+	}
+	traits := call.Context.Traits
+	if call.Type == "identify" {
+		traits = call.Traits
+		event.Properties = call.Traits
+	}
+	if email, ok := traits["email"].(string); ok {
+		event.Email = email
+	}
+	if event.Properties == nil {
+		event.Properties = map[string]any{}
+	}
+	if sessionID, ok := event.Properties[highlight.SessionIDAttribute].(string); ok {
+		event.SessionSecureID = sessionID
+		delete(event.Properties, highlight.SessionIDAttribute)
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if call.MessageID != "" {
+		// segment retries calls with the same message id
+		event.DedupeKey = dedupeKey("segment", call.MessageID, projectID)
+	}
+	return event
+}
+
+// verifySegmentSignature checks the signature of a call against the secret of its project.
+func verifySegmentSignature(body []byte, secret string, signature string) bool {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	expected, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// authorizeSegmentRequest checks the signature of a request signed with the shared secret of the
+// destination. Projects with a secret only accept signed requests, and requests to projects
+// without one are authorized by the ingest key header, like the sentry ones.
+func (o *Handler) authorizeSegmentRequest(ctx context.Context, projectID int, r *http.Request, body []byte) (int, error) {
+	if o.projects == nil {
+		return http.StatusServiceUnavailable, e.New("projects are unavailable")
+	}
+	project, err := o.projects.GetProject(ctx, projectID)
+	if err != nil {
+		return http.StatusServiceUnavailable, e.Wrapf(err, "failed to get project %d", projectID)
+	}
+	if project.Secret == nil || *project.Secret == "" {
+		return o.authorizeSentryRequest(ctx, projectID, r.Header.Get(IngestKeyHeader))
+	}
+	if !verifySegmentSignature(body, *project.Secret, r.Header.Get(SegmentSignatureHeader)) {
+		return http.StatusUnauthorized, errInvalidSegmentSignature
+	}
+	return http.StatusOK, nil
+}
+
+// HandleSegment writes the identify calls of a Segment destination to the user properties of their
+// session and the track calls to its track events.
+func (o *Handler) HandleSegment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, err := projectToInt(chi.URLParam(r, "project_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid segment body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	defer release()
+
+	if status, err := o.authorizeSegmentRequest(ctx, projectID, r, body); err != nil {
+		if status == http.StatusServiceUnavailable {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to authorize segment request")
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	calls, err := parseSegmentCalls(body)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Warn("invalid segment payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	payloadBytes.add(float64(len(body)), signalEvents)
+
+	var messages []*kafkaqueue.Message
+	for _, call := range calls {
+		if !segmentCallTypes[call.Type] {
+			continue
+		}
+		messages = append(messages, &kafkaqueue.Message{
+			Type:             kafkaqueue.PushSegmentEvent,
+			PushSegmentEvent: getSegmentEvent(projectID, call),
+		})
+	}
+
+	if len(messages) > 0 {
+		// calls are keyed by project so that the identify and track calls of a user are processed in order
+		err = o.submit(ctx, kafkaqueue.TopicTypeDefault, strconv.Itoa(projectID), messages...)
+	}
+	recordSubmission(eventsReceived, signalEvents, len(messages), nil, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit segment calls")
+		writeSubmitError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,129 @@
+package otel
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSegmentIdentify = `{
+	"type": "identify",
+	"messageId": "msg-1",
+	"userId": "user-1",
+	"traits": {"email": "chilly@highlight.io", "plan": "enterprise"},
+	"context": {"ip": "1.1.1.1"},
+	"timestamp": "2023-12-01T10:00:00.000Z"
+}`
+
+func TestParseSegmentCalls(t *testing.T) {
+	calls, err := parseSegmentCalls([]byte(testSegmentIdentify))
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+	assert.Equal(t, "identify", calls[0].Type)
+	assert.Equal(t, "1.1.1.1", calls[0].Context.IP)
+
+	calls, err = parseSegmentCalls([]byte(`{"batch": [{"type": "track", "event": "Signed Up"}, {"type": "page"}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, calls, 2)
+	assert.Equal(t, "Signed Up", calls[0].Event)
+
+	calls, err = parseSegmentCalls([]byte(`[{"type": "track", "event": "Signed Up"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+
+	_, err = parseSegmentCalls([]byte(`{"type":`))
+	assert.Error(t, err)
+}
+
+func TestGetSegmentEvent(t *testing.T) {
+	calls, err := parseSegmentCalls([]byte(testSegmentIdentify))
+	assert.NoError(t, err)
+	event := getSegmentEvent(1, calls[0])
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, "chilly@highlight.io", event.Email)
+	assert.Equal(t, "1.1.1.1", event.IP)
+	assert.Equal(t, map[string]any{"email": "chilly@highlight.io", "plan": "enterprise"}, event.Properties)
+	assert.Equal(t, time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC), event.Timestamp)
+	assert.NotEmpty(t, event.DedupeKey)
+
+	calls, err = parseSegmentCalls([]byte(`{
+		"type": "track",
+		"event": "Checkout",
+		"properties": {"total": 42, "highlight.session_id": "abc"},
+		"context": {"traits": {"email": "chilly@highlight.io"}}
+	}`))
+	assert.NoError(t, err)
+	event = getSegmentEvent(1, calls[0])
+	assert.Equal(t, "Checkout", event.Event)
+	assert.Equal(t, "abc", event.SessionSecureID)
+	assert.Equal(t, "chilly@highlight.io", event.Email)
+	assert.Equal(t, map[string]any{"total": float64(42)}, event.Properties)
+	assert.False(t, event.Timestamp.IsZero())
+	assert.Empty(t, event.DedupeKey)
+
+	calls, err = parseSegmentCalls([]byte(`{"type": "track", "event": "Checkout", "context": {"highlight": {"session_id": "def"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "def", getSegmentEvent(1, calls[0]).SessionSecureID)
+}
+
+func TestVerifySegmentSignature(t *testing.T) {
+	body := []byte(testSegmentIdentify)
+	mac := hmac.New(sha1.New, []byte("secret-1"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	assert.True(t, verifySegmentSignature(body, "secret-1", signature))
+	assert.False(t, verifySegmentSignature(body, "secret-2", signature))
+	assert.False(t, verifySegmentSignature(body, "secret-1", "not hex"))
+}
+
+func TestHandler_HandleSegment(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+	r := chi.NewRouter()
+	r.Post(SegmentPath, h.HandleSegment)
+
+	sign := func(body string, secret string) string {
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for _, tc := range []struct {
+		name      string
+		path      string
+		signature string
+		key       string
+		body      string
+		code      int
+	}{
+		{"invalid project", "/integrations/segment/not-a-project", "", "secret-1", testSegmentIdentify, http.StatusBadRequest},
+		{"invalid signature", "/integrations/segment/1", sign(testSegmentIdentify, "secret-2"), "", testSegmentIdentify, http.StatusUnauthorized},
+		{"invalid call", "/integrations/segment/1", sign(`{"type":`, "secret-1"), "", `{"type":`, http.StatusBadRequest},
+		{"unsigned call to a project with a secret", "/integrations/segment/1", "", "", testSegmentIdentify, http.StatusUnauthorized},
+		{"unsigned call with the ingest key", "/integrations/segment/1", "", "secret-1", testSegmentIdentify, http.StatusUnauthorized},
+		{"unsigned call to a project without a secret", "/integrations/segment/3", "", "", `{"type":"page"}`, http.StatusOK},
+		{"secret of another project", "/integrations/segment/3", "", "secret-2", `{"type":"page"}`, http.StatusForbidden},
+		{"ignored calls", "/integrations/segment/1", sign(`{"type":"page"}`, "secret-1"), "", `{"type":"page"}`, http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			if tc.signature != "" {
+				req.Header.Set(SegmentSignatureHeader, tc.signature)
+			}
+			if tc.key != "" {
+				req.Header.Set(IngestKeyHeader, tc.key)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,513 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/openlyinc/pointy"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// SentryStorePath and SentryEnvelopePath are the ingestion endpoints of the Sentry SDKs, so that
+// existing Sentry SDKs can send errors to highlight by setting the DSN to
+// `https://<project secret>@<highlight backend>/<project id>`.
+const (
+	SentryStorePath    = "/api/{project_id}/store/"
+	SentryEnvelopePath = "/api/{project_id}/envelope/"
+)
+
+const (
+	sentryAuthHeader = "x-sentry-auth"
+	sentryKeyParam   = "sentry_key"
+)
+
+// sentryEventType is the type of errors of sentry events that captured a message rather than an exception.
+const sentryEventType = "sentry.message"
+
+// sentryFrame is a frame of a sentry stack trace. Frames are sorted from the outermost to the innermost call.
+type sentryFrame struct {
+	Filename    string   `json:"filename"`
+	AbsPath     string   `json:"abs_path"`
+	Function    string   `json:"function"`
+	Module      string   `json:"module"`
+	Lineno      *int     `json:"lineno"`
+	Colno       *int     `json:"colno"`
+	ContextLine *string  `json:"context_line"`
+	PreContext  []string `json:"pre_context"`
+	PostContext []string `json:"post_context"`
+}
+
+type sentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Module     string `json:"module"`
+	Stacktrace *struct {
+		Frames []*sentryFrame `json:"frames"`
+	} `json:"stacktrace"`
+}
+
+// sentryExceptions are the chained exceptions of an event, sent as `{"values": [...]}` by current
+// SDKs and as a list by older ones. The last exception is the one that was captured.
+type sentryExceptions []*sentryException
+
+func (s *sentryExceptions) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]*sentryException)(s))
+	}
+	var values struct {
+		Values []*sentryException `json:"values"`
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*s = values.Values
+	return nil
+}
+
+// sentryMessage is the message of an event, sent as a string or as a `{"formatted": ...}` log entry.
+type sentryMessage string
+
+func (s *sentryMessage) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		return json.Unmarshal(data, (*string)(s))
+	}
+	var entry struct {
+		Formatted string `json:"formatted"`
+		Message   string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	*s = sentryMessage(entry.Formatted)
+	if *s == "" {
+		*s = sentryMessage(entry.Message)
+	}
+	return nil
+}
+
+// sentryTags are the tags of an event, sent as an object or as a list of key value pairs.
+type sentryTags map[string]string
+
+func (s *sentryTags) UnmarshalJSON(data []byte) error {
+	*s = make(sentryTags)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var pairs [][]any
+		if err := json.Unmarshal(data, &pairs); err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			if len(pair) == 2 {
+				(*s)[attributeString(pair[0])] = attributeString(pair[1])
+			}
+		}
+		return nil
+	}
+	var tags map[string]any
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return err
+	}
+	for k, v := range tags {
+		(*s)[k] = attributeString(v)
+	}
+	return nil
+}
+
+// sentryEvent is the subset of the sentry event payload that is converted to a highlight error.
+// See https://develop.sentry.dev/sdk/event-payloads/
+type sentryEvent struct {
+	EventID     string           `json:"event_id"`
+	Timestamp   any              `json:"timestamp"`
+	Platform    string           `json:"platform"`
+	Level       string           `json:"level"`
+	Logger      string           `json:"logger"`
+	Transaction string           `json:"transaction"`
+	ServerName  string           `json:"server_name"`
+	Release     string           `json:"release"`
+	Environment string           `json:"environment"`
+	Message     sentryMessage    `json:"message"`
+	LogEntry    sentryMessage    `json:"logentry"`
+	Exception   sentryExceptions `json:"exception"`
+	Tags        sentryTags       `json:"tags"`
+	Extra       map[string]any   `json:"extra"`
+	User        map[string]any   `json:"user"`
+	Request     struct {
+		URL    string `json:"url"`
+		Method string `json:"method"`
+	} `json:"request"`
+	Contexts struct {
+		Trace struct {
+			TraceID string `json:"trace_id"`
+			SpanID  string `json:"span_id"`
+		} `json:"trace"`
+	} `json:"contexts"`
+	SDK struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"sdk"`
+}
+
+// parseSentryEnvelope returns the event of an envelope, which is a header line followed by items of
+// a header line and a payload. Items other than events, ie. transactions and sessions, are ignored.
+// See https://develop.sentry.dev/sdk/envelopes/
+func parseSentryEnvelope(body []byte) (dsn string, event *sentryEvent, err error) {
+	line, body := nextSentryEnvelopeLine(body)
+	var header struct {
+		DSN string `json:"dsn"`
+	}
+	if err := json.Unmarshal(line, &header); err != nil {
+		return "", nil, e.Wrap(err, "invalid sentry envelope header")
+	}
+
+	for len(bytes.TrimSpace(body)) > 0 {
+		line, body = nextSentryEnvelopeLine(body)
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var item struct {
+			Type   string `json:"type"`
+			Length *int   `json:"length"`
+		}
+		if err := json.Unmarshal(line, &item); err != nil {
+			return "", nil, e.Wrap(err, "invalid sentry envelope item header")
+		}
+
+		var payload []byte
+		if item.Length != nil {
+			if *item.Length < 0 || *item.Length > len(body) {
+				return "", nil, e.New("invalid sentry envelope item length")
+			}
+			payload, body = body[:*item.Length], body[*item.Length:]
+			body = bytes.TrimPrefix(body, []byte("\n"))
+		} else {
+			payload, body = nextSentryEnvelopeLine(body)
+		}
+
+		if item.Type != "event" || event != nil {
+			continue
+		}
+		event = &sentryEvent{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			return "", nil, e.Wrap(err, "invalid sentry event")
+		}
+	}
+	return header.DSN, event, nil
+}
+
+func nextSentryEnvelopeLine(body []byte) (line []byte, rest []byte) {
+	if idx := bytes.IndexByte(body, '\n'); idx >= 0 {
+		return body[:idx], body[idx+1:]
+	}
+	return body, nil
+}
+
+// getSentryKey returns the public key of the DSN that a request was sent with, which SDKs send in the
+// X-Sentry-Auth header, the `sentry_key` query parameter or the DSN of the envelope header.
+func getSentryKey(r *http.Request, dsn string) string {
+	for _, part := range strings.Split(strings.TrimPrefix(r.Header.Get(sentryAuthHeader), "Sentry "), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok && k == sentryKeyParam {
+			return v
+		}
+	}
+	if key := r.URL.Query().Get(sentryKeyParam); key != "" {
+		return key
+	}
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		return u.User.Username()
+	}
+	return ""
+}
+
+// parseSentryTimestamp parses the timestamp of an event, which is a unix timestamp in (fractional)
+// seconds or an RFC3339 string.
+func parseSentryTimestamp(ts any) time.Time {
+	switch value := ts.(type) {
+	case float64:
+		sec, frac := math.Modf(value)
+		return time.Unix(int64(sec), int64(frac*1e9))
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return parsed
+		}
+		// the timestamps of older SDKs have no timezone and are in UTC
+		if parsed, err := time.Parse("2006-01-02T15:04:05.999999999", value); err == nil {
+			return parsed
+		}
+	}
+	return time.Now()
+}
+
+// getSentryStackTrace converts the frames of a sentry exception to a structured stack trace,
+// which is stored with the innermost frame first.
+func getSentryStackTrace(exception *sentryException) string {
+	if exception.Stacktrace == nil || len(exception.Stacktrace.Frames) == 0 {
+		return ""
+	}
+	frames := exception.Stacktrace.Frames
+	trace := make([]*privateModel.ErrorTrace, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		errorTrace := &privateModel.ErrorTrace{
+			LineNumber:   frame.Lineno,
+			ColumnNumber: frame.Colno,
+			LineContent:  frame.ContextLine,
+		}
+		if frame.AbsPath != "" {
+			errorTrace.FileName = pointy.String(frame.AbsPath)
+		} else if frame.Filename != "" {
+			errorTrace.FileName = pointy.String(frame.Filename)
+		} else if frame.Module != "" {
+			errorTrace.FileName = pointy.String(frame.Module)
+		}
+		if frame.Function != "" {
+			errorTrace.FunctionName = pointy.String(frame.Function)
+		}
+		if len(frame.PreContext) > 0 {
+			errorTrace.LinesBefore = pointy.String(strings.Join(frame.PreContext, "\n"))
+		}
+		if len(frame.PostContext) > 0 {
+			errorTrace.LinesAfter = pointy.String(strings.Join(frame.PostContext, "\n"))
+		}
+		trace = append(trace, errorTrace)
+	}
+	output, err := json.Marshal(trace)
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
+
+// isSentryBrowserSDK returns whether the SDK of an event runs in the browser, ie. sentry.javascript.react.
+func isSentryBrowserSDK(name string) bool {
+	sdk, ok := strings.CutPrefix(name, "sentry.javascript.")
+	if !ok {
+		return false
+	}
+	for _, server := range []string{"node", "serverless", "aws-serverless", "google-cloud-serverless", "bun", "deno"} {
+		if sdk == server {
+			return false
+		}
+	}
+	return true
+}
+
+// getSentryBackendError converts a sentry event to a highlight error. Events of captured messages
+// are recorded as errors of the sentryEventType.
+func getSentryBackendError(event *sentryEvent) *model.BackendErrorObjectInput {
+	errorObject := &model.BackendErrorObjectInput{
+		Timestamp:   parseSentryTimestamp(event.Timestamp),
+		URL:         event.Request.URL,
+		Environment: event.Environment,
+		Source:      privateModel.LogSourceBackend.String(),
+		Service: &model.ServiceInput{
+			Name:    event.Tags[string(semconv.ServiceNameKey)],
+			Version: event.Release,
+		},
+	}
+	if isSentryBrowserSDK(event.SDK.Name) {
+		errorObject.Source = privateModel.LogSourceFrontend.String()
+	}
+
+	if len(event.Exception) > 0 {
+		exception := event.Exception[len(event.Exception)-1]
+		errorObject.Type = exception.Type
+		errorObject.Event = exception.Value
+		errorObject.StackTrace = getSentryStackTrace(exception)
+	}
+	if errorObject.Event == "" {
+		errorObject.Event = string(event.LogEntry)
+	}
+	if errorObject.Event == "" {
+		errorObject.Event = string(event.Message)
+	}
+	if errorObject.Type == "" {
+		errorObject.Type = sentryEventType
+	}
+
+	if sessionID := event.Tags[highlight.SessionIDAttribute]; sessionID != "" {
+		errorObject.SessionSecureID = pointy.String(sessionID)
+	}
+	if requestID := event.Tags[highlight.RequestIDAttribute]; requestID != "" {
+		errorObject.RequestID = pointy.String(requestID)
+	}
+	if event.Contexts.Trace.TraceID != "" {
+		errorObject.TraceID = pointy.String(event.Contexts.Trace.TraceID)
+	}
+	if event.Contexts.Trace.SpanID != "" {
+		errorObject.SpanID = pointy.String(event.Contexts.Trace.SpanID)
+	}
+
+	attrs := map[string]any{
+		"sentry.event_id":             event.EventID,
+		"sentry.platform":             event.Platform,
+		"sentry.level":                event.Level,
+		"sentry.logger":               event.Logger,
+		"sentry.transaction":          event.Transaction,
+		"sentry.sdk.name":             event.SDK.Name,
+		"sentry.sdk.version":          event.SDK.Version,
+		"server_name":                 event.ServerName,
+		string(semconv.HTTPMethodKey): event.Request.Method,
+	}
+	for k, v := range attrs {
+		if v == "" {
+			delete(attrs, k)
+		}
+	}
+	for k, v := range event.Tags {
+		attrs[k] = v
+	}
+	if len(event.Extra) > 0 {
+		attrs["extra"] = event.Extra
+	}
+	if len(event.User) > 0 {
+		attrs["user"] = event.User
+	}
+	payload, _ := json.Marshal(attrs)
+	errorObject.Payload = pointy.String(string(payload))
+	return errorObject
+}
+
+// authorizeSentryRequest checks the DSN key of a request, which is the project secret. Projects that
+// don't require an ingest key accept any key, so that the DSN of a migrated project only needs its
+// host and project id replaced.
+func (o *Handler) authorizeSentryRequest(ctx context.Context, projectID int, key string) (int, error) {
+	if o.projects == nil {
+		return http.StatusServiceUnavailable, e.New("projects are unavailable")
+	}
+	keyProjectID, err := o.projects.GetProjectIDBySecret(ctx, key)
+	if err != nil {
+		return http.StatusServiceUnavailable, err
+	}
+	if keyProjectID == projectID {
+		return http.StatusOK, nil
+	} else if keyProjectID != 0 {
+		return http.StatusForbidden, errIngestKeyProject
+	}
+	project, err := o.projects.GetProject(ctx, projectID)
+	if err != nil {
+		return http.StatusServiceUnavailable, e.Wrapf(err, "failed to get project %d", projectID)
+	}
+	if project.RequireIngestKey {
+		return http.StatusUnauthorized, errInvalidIngestKey
+	}
+	return http.StatusOK, nil
+}
+
+// HandleSentryStore receives the events of the store endpoint of older Sentry SDKs.
+func (o *Handler) HandleSentryStore(w http.ResponseWriter, r *http.Request) {
+	o.handleSentry(w, r, false)
+}
+
+// HandleSentryEnvelope receives the envelopes of current Sentry SDKs, writing their event as an error.
+func (o *Handler) HandleSentryEnvelope(w http.ResponseWriter, r *http.Request) {
+	o.handleSentry(w, r, true)
+}
+
+func (o *Handler) handleSentry(w http.ResponseWriter, r *http.Request, envelope bool) {
+	ctx := r.Context()
+	projectID, err := projectToInt(chi.URLParam(r, "project_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid sentry body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	defer release()
+
+	var dsn string
+	event := &sentryEvent{}
+	if envelope {
+		dsn, event, err = parseSentryEnvelope(body)
+	} else {
+		err = json.Unmarshal(body, event)
+	}
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Warn("invalid sentry payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if status, err := o.authorizeSentryRequest(ctx, projectID, getSentryKey(r, dsn)); err != nil {
+		if status == http.StatusServiceUnavailable {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to authorize sentry request")
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// envelopes of transactions, sessions and client reports are accepted and dropped
+	if event == nil {
+		writeSentryResponse(w, r, "")
+		return
+	}
+
+	payloadBytes.add(float64(len(body)), signalErrors)
+	err = o.submitSentryEvent(ctx, projectID, event)
+	recordSubmission(errorsReceived, signalErrors, 1, nil, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit sentry event")
+		writeSubmitError(w, err)
+		return
+	}
+	writeSentryResponse(w, r, event.EventID)
+}
+
+func (o *Handler) submitSentryEvent(ctx context.Context, projectID int, event *sentryEvent) error {
+	if err := o.checkRateLimits(ctx, privateModel.ProductTypeErrors, map[int]int64{projectID: 1}); err != nil {
+		return err
+	}
+
+	errorObject := getSentryBackendError(event)
+	if o.resolver != nil && !o.resolver.IsErrorIngested(ctx, projectID, errorObject) {
+		return nil
+	}
+
+	var sessionID string
+	if errorObject.SessionSecureID != nil {
+		sessionID = *errorObject.SessionSecureID
+	}
+	message := &kafkaqueue.Message{
+		Type: kafkaqueue.PushBackendPayload,
+		PushBackendPayload: &kafkaqueue.PushBackendPayloadArgs{
+			ProjectVerboseID: pointy.String(strconv.Itoa(projectID)),
+			SessionSecureID:  pointy.String(sessionID),
+			Errors:           []*model.BackendErrorObjectInput{errorObject},
+		},
+	}
+	if event.EventID != "" {
+		// sentry SDKs retry events with the same id
+		message.PushBackendPayload.DedupeKey = dedupeKey("sentry", event.EventID, 0)
+	}
+	if err := o.submit(ctx, kafkaqueue.TopicTypeDefault, sessionID, message); err != nil {
+		return e.Wrap(err, "failed to submit sentry event to public worker queue")
+	}
+	return nil
+}
+
+// writeSentryResponse writes the id of the received event, which is the response the Sentry SDKs expect.
+func writeSentryResponse(w http.ResponseWriter, r *http.Request, eventID string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(w, `{"id":%q}`, eventID); err != nil {
+		log.WithContext(r.Context()).WithError(err).Error("failed to write sentry response")
+	}
+}
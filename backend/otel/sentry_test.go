@@ -0,0 +1,132 @@
+package otel
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSentryEvent = `{"event_id":"9ec79c33ec9942ab8353589fcb2e04dc","timestamp":1700000000.5,"platform":"python","level":"error","server_name":"web-1","release":"1.2.3","environment":"production","exception":{"values":[{"type":"KeyError","value":"'id'","stacktrace":{"frames":[{"filename":"app.py","function":"main","lineno":10},{"abs_path":"/srv/handlers.py","function":"handle","lineno":42,"context_line":"return user['id']","pre_context":["def handle(user):"]}]}},{"type":"ValueError","value":"invalid user","stacktrace":{"frames":[{"filename":"app.py","function":"main","lineno":12}]}}]},"tags":{"highlight.session_id":"abc","service.name":"api"},"extra":{"attempt":2},"request":{"url":"https://example.com/users","method":"GET"},"contexts":{"trace":{"trace_id":"0af7651916cd43dd8448eb211c80319c","span_id":"b7ad6b7169203331"}},"sdk":{"name":"sentry.python","version":"1.40.0"}}`
+
+func testSentryEnvelope() []byte {
+	transaction := `{"type":"transaction"}`
+	return []byte(`{"event_id":"9ec79c33ec9942ab8353589fcb2e04dc","dsn":"https://secret-1@highlight.example.com/1"}
+{"type":"transaction","length":` + strconv.Itoa(len(transaction)) + `}
+` + transaction + `
+{"type":"event"}
+` + testSentryEvent + `
+`)
+}
+
+func TestParseSentryEnvelope(t *testing.T) {
+	dsn, event, err := parseSentryEnvelope(testSentryEnvelope())
+	assert.NoError(t, err)
+	assert.Equal(t, "https://secret-1@highlight.example.com/1", dsn)
+	assert.Equal(t, "9ec79c33ec9942ab8353589fcb2e04dc", event.EventID)
+	assert.Len(t, event.Exception, 2)
+
+	_, event, err = parseSentryEnvelope([]byte("{}\n{\"type\":\"session\"}\n{\"sid\":\"1\"}\n"))
+	assert.NoError(t, err)
+	assert.Nil(t, event)
+
+	_, _, err = parseSentryEnvelope([]byte("{}\n{\"type\":\"event\",\"length\":100}\n{}"))
+	assert.Error(t, err)
+}
+
+func TestGetSentryKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/1/envelope/?sentry_key=query-key", nil)
+	r.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client=sentry.python/1.40.0, sentry_key=header-key")
+	assert.Equal(t, "header-key", getSentryKey(r, ""))
+
+	r.Header.Del("X-Sentry-Auth")
+	assert.Equal(t, "query-key", getSentryKey(r, ""))
+
+	r = httptest.NewRequest(http.MethodPost, "/api/1/envelope/", nil)
+	assert.Equal(t, "dsn-key", getSentryKey(r, "https://dsn-key@highlight.example.com/1"))
+	assert.Equal(t, "", getSentryKey(r, ""))
+}
+
+func TestGetSentryBackendError(t *testing.T) {
+	var event sentryEvent
+	assert.NoError(t, json.Unmarshal([]byte(testSentryEvent), &event))
+
+	errorObject := getSentryBackendError(&event)
+	assert.Equal(t, "ValueError", errorObject.Type)
+	assert.Equal(t, "invalid user", errorObject.Event)
+	assert.Equal(t, "https://example.com/users", errorObject.URL)
+	assert.Equal(t, "production", errorObject.Environment)
+	assert.Equal(t, privateModel.LogSourceBackend.String(), errorObject.Source)
+	assert.Equal(t, "api", errorObject.Service.Name)
+	assert.Equal(t, "1.2.3", errorObject.Service.Version)
+	assert.Equal(t, "abc", *errorObject.SessionSecureID)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", *errorObject.TraceID)
+	assert.Equal(t, time.Unix(1700000000, 5e8), errorObject.Timestamp)
+
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(*errorObject.Payload), &payload))
+	assert.Equal(t, "9ec79c33ec9942ab8353589fcb2e04dc", payload["sentry.event_id"])
+	assert.Equal(t, "web-1", payload["server_name"])
+	assert.Equal(t, map[string]any{"attempt": float64(2)}, payload["extra"])
+
+	var frames []*privateModel.ErrorTrace
+	assert.NoError(t, json.Unmarshal([]byte(getSentryStackTrace(event.Exception[0])), &frames))
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "/srv/handlers.py", *frames[0].FileName)
+	assert.Equal(t, "handle", *frames[0].FunctionName)
+	assert.Equal(t, 42, *frames[0].LineNumber)
+	assert.Equal(t, "return user['id']", *frames[0].LineContent)
+	assert.Equal(t, "def handle(user):", *frames[0].LinesBefore)
+	assert.Equal(t, "app.py", *frames[1].FileName)
+
+	event = sentryEvent{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"message":{"formatted":"checkout failed"},"tags":[["browser","Chrome"]],"sdk":{"name":"sentry.javascript.react"}}`), &event))
+	errorObject = getSentryBackendError(&event)
+	assert.Equal(t, sentryEventType, errorObject.Type)
+	assert.Equal(t, "checkout failed", errorObject.Event)
+	assert.Equal(t, "", errorObject.StackTrace)
+	assert.Equal(t, privateModel.LogSourceFrontend.String(), errorObject.Source)
+	assert.Nil(t, errorObject.SessionSecureID)
+}
+
+func TestIsSentryBrowserSDK(t *testing.T) {
+	assert.True(t, isSentryBrowserSDK("sentry.javascript.browser"))
+	assert.True(t, isSentryBrowserSDK("sentry.javascript.vue"))
+	assert.False(t, isSentryBrowserSDK("sentry.javascript.node"))
+	assert.False(t, isSentryBrowserSDK("sentry.python"))
+}
+
+func TestHandler_HandleSentry(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+	r := chi.NewRouter()
+	r.Post(SentryStorePath, h.HandleSentryStore)
+	r.Post(SentryEnvelopePath, h.HandleSentryEnvelope)
+
+	for _, tc := range []struct {
+		name string
+		path string
+		key  string
+		body []byte
+		code int
+	}{
+		{"invalid event", "/api/1/store/", "secret-1", []byte("not json"), http.StatusBadRequest},
+		{"secret of another project", "/api/1/store/", "secret-2", []byte(testSentryEvent), http.StatusForbidden},
+		{"project requires ingest key", "/api/2/store/", "public-key", []byte(testSentryEvent), http.StatusUnauthorized},
+		{"envelope without event", "/api/1/envelope/", "secret-1", []byte("{}\n{\"type\":\"session\"}\n{}\n"), http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewReader(tc.body))
+			req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_key="+tc.key)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
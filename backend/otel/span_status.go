@@ -0,0 +1,62 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// errorTypeAttribute describes the class of error of a failed operation in the current semantic conventions.
+const errorTypeAttribute = "error.type"
+
+// spanStatusErrorSettings looks up whether projects create errors from spans with an error status
+// and no exception event, caching the setting for the duration of an export request.
+type spanStatusErrorSettings struct {
+	projects  projectStore
+	byProject map[int]bool
+}
+
+func (o *Handler) newSpanStatusErrorSettings() *spanStatusErrorSettings {
+	return &spanStatusErrorSettings{
+		projects:  o.projects,
+		byProject: make(map[int]bool),
+	}
+}
+
+func (s *spanStatusErrorSettings) enabled(ctx context.Context, projectID int) bool {
+	if s.projects == nil {
+		return false
+	}
+	if enabled, ok := s.byProject[projectID]; ok {
+		return enabled
+	}
+	settings, err := s.projects.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		lg(ctx, nil).WithError(err).WithField("project_id", projectID).Error("failed to get span status error settings")
+		return false
+	}
+	s.byProject[projectID] = settings.ErrorsFromSpanStatus
+	return settings.ErrorsFromSpanStatus
+}
+
+// getSpanStatusErrorFields returns the exception fields of a span with an error status,
+// using the status message as the error message and the error.type attribute as the error type.
+func getSpanStatusErrorFields(span ptrace.Span, fields *extractedFields) *extractedFields {
+	errorFields := *fields
+	errorFields.attrs = make(map[string]string, len(fields.attrs))
+	for k, v := range fields.attrs {
+		errorFields.attrs[k] = v
+	}
+
+	errorFields.exceptionType = span.Name()
+	if errorType, ok := errorFields.attrs[errorTypeAttribute]; ok {
+		errorFields.exceptionType = errorType
+		delete(errorFields.attrs, errorTypeAttribute)
+	}
+	errorFields.exceptionMessage = span.Status().Message()
+	if errorFields.exceptionMessage == "" {
+		errorFields.exceptionMessage = span.Name()
+	}
+	errorFields.timestamp = span.EndTimestamp().AsTime()
+	return &errorFields
+}
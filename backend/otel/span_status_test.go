@@ -0,0 +1,44 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestSpanStatusErrorSettings(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, (&Handler{}).newSpanStatusErrorSettings().enabled(ctx, 1))
+
+	h := Handler{projects: &mockProjectStore{spanStatusErrors: map[int]bool{2: true}}}
+	settings := h.newSpanStatusErrorSettings()
+	assert.False(t, settings.enabled(ctx, 1))
+	assert.True(t, settings.enabled(ctx, 2))
+}
+
+func TestGetSpanStatusErrorFields(t *testing.T) {
+	end := time.UnixMilli(1700000000000).UTC()
+	span := ptrace.NewSpan()
+	span.SetName("GET /api/users")
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+	span.Status().SetCode(ptrace.StatusCodeError)
+
+	fields := newExtractedFields()
+	fields.attrs["http.status_code"] = "500"
+	errorFields := getSpanStatusErrorFields(span, fields)
+	assert.Equal(t, "GET /api/users", errorFields.exceptionType)
+	assert.Equal(t, "GET /api/users", errorFields.exceptionMessage)
+	assert.Equal(t, end, errorFields.timestamp)
+
+	span.Status().SetMessage("connection refused")
+	fields.attrs[errorTypeAttribute] = "ECONNREFUSED"
+	errorFields = getSpanStatusErrorFields(span, fields)
+	assert.Equal(t, "ECONNREFUSED", errorFields.exceptionType)
+	assert.Equal(t, "connection refused", errorFields.exceptionMessage)
+	assert.NotContains(t, errorFields.attrs, errorTypeAttribute)
+	assert.Contains(t, fields.attrs, errorTypeAttribute, "the span attributes are not modified")
+}
@@ -1,10 +1,35 @@
 package otel
 
 import (
+	"bytes"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	"github.com/highlight-run/highlight/backend/severity"
 	"github.com/influxdata/go-syslog/v3/rfc5424"
-	"go.opentelemetry.io/collector/pdata/plog"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// SyslogLogsPath receives RFC5424 syslog messages over https, ie. from a Heroku log drain.
+const SyslogLogsPath = "/integrations/syslog/logs"
+
+// headers of the Heroku Logplex log drain deliveries
+const (
+	logplexFrameIDHeader    = "Logplex-Frame-Id"
+	logplexDrainTokenHeader = "Logplex-Drain-Token"
+)
+
+// attributes of the dyno of a Heroku log, which is the process id of its syslog message
+const (
+	herokuDynoAttribute   = "heroku.dyno"
+	herokuSourceAttribute = "heroku.source"
 )
 
 func extractSyslog(fields *extractedFields) {
@@ -18,7 +43,9 @@ func extractSyslog(fields *extractedFields) {
 			fields.attrs["facility"] = strconv.Itoa(int(*msg.Facility))
 		}
 		if msg.Severity != nil {
-			fields.logSeverity = plog.SeverityNumber(*msg.Severity).String()
+			if level, ok := severity.FromSyslog(int(*msg.Severity)); ok {
+				fields.logSeverity = level.String()
+			}
 		}
 		if msg.Priority != nil {
 			fields.attrs["priority"] = strconv.Itoa(int(*msg.Priority))
@@ -48,3 +75,150 @@ func extractSyslog(fields *extractedFields) {
 		}
 	}
 }
+
+// splitSyslogFrames splits a drain body into syslog messages. Bodies in the octet counting framing
+// of Logplex prefix each message with its length, ie. `83 <40>1 2012-11-30T06:45:29+00:00 host app web.3 - State changed`,
+// while others are newline delimited.
+func splitSyslogFrames(body []byte) ([]string, error) {
+	body = bytes.TrimLeft(body, "\r\n ")
+	if len(body) == 0 || body[0] < '0' || body[0] > '9' {
+		var frames []string
+		for _, line := range strings.Split(string(body), "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				frames = append(frames, line)
+			}
+		}
+		return frames, nil
+	}
+
+	var frames []string
+	for len(body) > 0 {
+		space := bytes.IndexByte(body, ' ')
+		if space <= 0 {
+			return nil, e.New("invalid syslog frame length")
+		}
+		length, err := strconv.Atoi(string(body[:space]))
+		if err != nil || length <= 0 || space+1+length > len(body) {
+			return nil, e.Errorf("invalid syslog frame length %q", body[:space])
+		}
+		frames = append(frames, strings.TrimRight(string(body[space+1:space+1+length]), "\r\n"))
+		body = bytes.TrimLeft(body[space+1+length:], "\r\n ")
+	}
+	return frames, nil
+}
+
+// withNilStructuredData adds the nil structured data that rfc5424 requires after the header of a
+// message to frames that omit it, like those of Logplex, ie. `<40>1 2012-11-30T06:45:29+00:00 host app web.3 - State changed`.
+func withNilStructuredData(frame string) string {
+	if _, err := rfc5424.NewParser().Parse([]byte(frame)); err == nil {
+		return frame
+	}
+	parts := strings.SplitN(frame, " ", 7)
+	if len(parts) < 6 {
+		return frame
+	}
+	return strings.Join(append(parts[:6], append([]string{"-"}, parts[6:]...)...), " ")
+}
+
+// getSyslogFields parses a syslog message onto the fields of a log row. The host of the message is
+// its resource host, and its app name is the service unless one is set for the drain. Heroku logs
+// also record their dyno, and the source of the log (ie. `app` or `router`).
+func getSyslogFields(projectID int, frame string, serviceName string, heroku bool) *extractedFields {
+	fields := newExtractedFields()
+	fields.projectIDInt = projectID
+	fields.projectID = strconv.Itoa(projectID)
+	fields.source = privateModel.LogSourceBackend
+	fields.timestamp = time.Now()
+	fields.logBody = withNilStructuredData(frame)
+	extractSyslog(fields)
+
+	if hostname, ok := fields.attrs["hostname"]; ok {
+		fields.attrs[clickhouse.LogResourceHostNameAttribute] = hostname
+		delete(fields.attrs, "hostname")
+	}
+	fields.serviceName = serviceName
+	if appName := fields.attrs["app_name"]; appName != "" {
+		if heroku {
+			fields.attrs[herokuSourceAttribute] = appName
+			delete(fields.attrs, "app_name")
+		} else if fields.serviceName == "" {
+			fields.serviceName = appName
+		}
+	}
+	if procID := fields.attrs["proc_id"]; procID != "" && heroku {
+		fields.attrs[herokuDynoAttribute] = procID
+		delete(fields.attrs, "proc_id")
+	}
+	return fields
+}
+
+// HandleSyslog writes the syslog messages of a drain to the project of the `project_id` query parameter
+// or x-highlight-project header, ie. `heroku drains:add https://pub.highlight.io/integrations/syslog/logs?project_id=1`.
+// The ingest key is the x-highlight-key header, or the password of the drain url, and the `service_name`
+// query parameter sets the service of the logs.
+func (o *Handler) HandleSyslog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	projectID := query.Get("project_id")
+	if projectID == "" {
+		projectID = r.Header.Get(ratelimit.ProjectHeader)
+	}
+	fields := &extractedFields{projectID: projectID}
+	var err error
+	if fields.projectIDInt, err = projectToInt(projectID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		fields.ingestKey = password
+	}
+	if err := o.newIngestAuthorizer(ctx).authorize(ctx, fields); err != nil {
+		if e.Is(err, errInvalidIngestKey) || e.Is(err, errIngestKeyProject) || e.Is(err, errIngestKeyRequired) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		log.WithContext(ctx).WithError(err).Error("failed to authorize syslog drain")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid syslog body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	defer release()
+	frames, err := splitSyslogFrames(body)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid syslog payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	heroku := r.Header.Get(logplexDrainTokenHeader) != ""
+	logs := make([]*extractedFields, 0, len(frames))
+	// logplex retries deliveries with the same frame id, other drains with the same payload
+	dedupeIDs := payloadDedupeIDs(body, len(frames))
+	frameID := r.Header.Get(logplexFrameIDHeader)
+	for i, frame := range frames {
+		logFields := getSyslogFields(fields.projectIDInt, frame, query.Get("service_name"), heroku)
+		if heroku {
+			logFields.attrs[string(semconv.CloudProviderKey)] = "heroku"
+		}
+		logs = append(logs, logFields)
+		if frameID != "" {
+			dedupeIDs[i] = frameID + "/" + strconv.Itoa(i)
+		}
+	}
+
+	payloadBytes.add(float64(len(body)), signalLogs)
+	err = o.submitDrainLogs(ctx, "syslog", logs, dedupeIDs)
+	recordSubmission(logsReceived, signalLogs, len(logs), nil, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit syslog drain logs")
+		writeSubmitError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
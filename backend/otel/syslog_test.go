@@ -1,7 +1,12 @@
 package otel
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -26,3 +31,76 @@ func Test_extractSyslogWithStructuredData(t *testing.T) {
 	assert.Equal(t, "Application", fields.attrs["exampleSDID@32473.eventSource"])
 	assert.Equal(t, "1011", fields.attrs["exampleSDID@32473.eventID"])
 }
+
+func logplexFrames(messages ...string) string {
+	var body string
+	for _, message := range messages {
+		body += fmt.Sprintf("%d %s", len(message), message)
+	}
+	return body
+}
+
+func TestSplitSyslogFrames(t *testing.T) {
+	first := "<40>1 2012-11-30T06:45:29+00:00 host app web.3 - State changed from starting to up"
+	second := "<190>1 2012-11-30T06:45:26+00:00 host heroku router - at=info method=GET path=\"/\" status=200\n"
+	frames, err := splitSyslogFrames([]byte(logplexFrames(first, second)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{first, strings.TrimSpace(second)}, frames)
+
+	frames, err = splitSyslogFrames([]byte(first + "\r\n" + first + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{first, first}, frames)
+
+	_, err = splitSyslogFrames([]byte("500 <40>1 truncated"))
+	assert.Error(t, err)
+}
+
+func TestWithNilStructuredData(t *testing.T) {
+	assert.Equal(t, "<40>1 2012-11-30T06:45:29+00:00 host app web.3 - - State changed", withNilStructuredData("<40>1 2012-11-30T06:45:29+00:00 host app web.3 - State changed"))
+	assert.Equal(t, "<40>1 2012-11-30T06:45:29+00:00 host app web.3 - -", withNilStructuredData("<40>1 2012-11-30T06:45:29+00:00 host app web.3 -"))
+	assert.Equal(t, "<40>1 truncated", withNilStructuredData("<40>1 truncated"))
+	// valid messages are kept
+	assert.Equal(t, `<14>1 2023-07-27T05:43:22Z web-1 billing 42 - [meta id="1"] charged`, withNilStructuredData(`<14>1 2023-07-27T05:43:22Z web-1 billing 42 - [meta id="1"] charged`))
+}
+
+func TestGetSyslogFields(t *testing.T) {
+	frame := "<40>1 2012-11-30T06:45:29+00:00 host app web.3 - State changed from starting to up"
+	fields := getSyslogFields(1, frame, "my-app", true)
+	assert.Equal(t, "State changed from starting to up", fields.logBody)
+	assert.Equal(t, "my-app", fields.serviceName)
+	assert.Equal(t, "web.3", fields.attrs[herokuDynoAttribute])
+	assert.Equal(t, "app", fields.attrs[herokuSourceAttribute])
+	assert.Equal(t, "host", fields.attrs["host.name"])
+	assert.Equal(t, time.Date(2012, 11, 30, 6, 45, 29, 0, time.UTC), fields.timestamp.UTC())
+
+	fields = getSyslogFields(1, "<14>1 2023-07-27T05:43:22Z web-1 billing 42 - charged", "", false)
+	assert.Equal(t, "billing", fields.serviceName)
+	assert.Equal(t, "42", fields.attrs["proc_id"])
+	assert.Equal(t, "info", fields.logSeverity)
+}
+
+func TestHandler_HandleSyslog(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+	body := logplexFrames("<40>1 2012-11-30T06:45:29+00:00 host app web.3 - State changed from starting to up")
+
+	for _, tc := range []struct {
+		name string
+		path string
+		key  string
+		code int
+	}{
+		{"missing project", SyslogLogsPath, "", http.StatusBadRequest},
+		{"requires key", SyslogLogsPath + "?project_id=2", "", http.StatusUnauthorized},
+		{"key of other project", SyslogLogsPath + "?project_id=2", "secret-1", http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(body))
+			if tc.key != "" {
+				r.SetBasicAuth("", tc.key)
+			}
+			w := httptest.NewRecorder()
+			h.HandleSyslog(w, r)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -1,8 +1,9 @@
 package otel
 
 import (
-	"go.opentelemetry.io/collector/pdata/plog"
 	"strconv"
+
+	"github.com/highlight-run/highlight/backend/severity"
 )
 
 type SystemdKey = string
@@ -10,20 +11,18 @@ type SystemdKey = string
 const Message SystemdKey = "MESSAGE"
 const Priority SystemdKey = "PRIORITY"
 
+// isSystemd returns whether a map log body is a systemd journal entry.
+func isSystemd(m map[string]any) bool {
+	_, ok := m[Message].(string)
+	return ok
+}
+
 func extractSystemd(fields *extractedFields, m map[string]any) {
 	fields.logBody = m[Message].(string)
-	if priority, err := strconv.ParseInt(m[Priority].(string), 10, 4); err == nil {
-		switch priority {
-		case 0, 1:
-			fields.logSeverity = plog.SeverityNumberFatal.String()
-		case 2, 3:
-			fields.logSeverity = plog.SeverityNumberError.String()
-		case 4, 5:
-			fields.logSeverity = plog.SeverityNumberWarn.String()
-		case 6:
-			fields.logSeverity = plog.SeverityNumberInfo.String()
-		case 7:
-			fields.logSeverity = plog.SeverityNumberDebug.String()
+	priority, _ := m[Priority].(string)
+	if priority, err := strconv.Atoi(priority); err == nil {
+		if level, ok := severity.FromSyslog(priority); ok {
+			fields.logSeverity = level.String()
 		}
 	}
 	for k, v := range m {
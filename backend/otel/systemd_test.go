@@ -15,7 +15,7 @@ func Test_extractSystemd(t *testing.T) {
 	}
 	extractSystemd(fields, m)
 	assert.Equal(t, "msg king of flavor", fields.logBody)
-	assert.Equal(t, "Info", fields.logSeverity)
+	assert.Equal(t, "info", fields.logSeverity)
 	assert.Equal(t, "abc123", fields.attrs["__CURSOR"])
 	assert.Equal(t, "2353958120941", fields.attrs["__MONOTONIC_TIMESTAMP"])
 }
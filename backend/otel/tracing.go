@@ -0,0 +1,45 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/highlight-run/highlight/backend/util"
+)
+
+// the stages of handling an export request, traced as spans of the request
+const (
+	stageDecode    = "decode"
+	stageTransform = "transform"
+	stageSubmit    = "submit"
+)
+
+// statusClientClosedRequest is recorded for export requests whose client disconnected before they were submitted.
+const statusClientClosedRequest = 499
+
+func startStage(ctx context.Context, signal string, stage string) (util.MultiSpan, context.Context) {
+	return util.StartSpanFromContext(ctx, "otel."+stage, util.ResourceName(fmt.Sprintf("otel.%s.%s", signal, stage)), util.Tag("signal", signal))
+}
+
+// decodeRequest reads and unmarshals the body of an export request, returning the status
+// of the response when it is invalid.
+func (o *Handler) decodeRequest(w http.ResponseWriter, r *http.Request, signal string, req otlpRequest) (int, error) {
+	span, _ := startStage(r.Context(), signal, stageDecode)
+	output, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		span.Finish(err)
+		return getBodyErrorStatus(err), err
+	}
+
+	payloadBytes.add(float64(len(output)), signal)
+
+	err = unmarshalRequest(r, output, req)
+	// the unmarshalled request copies the data it needs out of the body
+	release()
+	span.Finish(err)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,255 @@
+package otel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/ratelimit"
+	"github.com/highlight-run/highlight/backend/severity"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// VercelLogsPath receives the logs of a Vercel log drain, so that Vercel projects send logs
+// without running a collector.
+const VercelLogsPath = "/integrations/vercel/logs"
+
+const (
+	// VercelLogDrainSecretEnvVar is a drain secret accepted for every project, ie. the secret of the
+	// drains created by a self-hosted Vercel integration. The project secret is always accepted.
+	VercelLogDrainSecretEnvVar = "VERCEL_LOG_DRAIN_SECRET"
+	// VercelLogDrainVerifyEnvVar is the token that Vercel expects in responses to verify ownership of the endpoint.
+	VercelLogDrainVerifyEnvVar = "VERCEL_LOG_DRAIN_VERIFY"
+)
+
+const (
+	vercelSignatureHeader = "x-vercel-signature"
+	vercelVerifyHeader    = "x-vercel-verify"
+)
+
+var errInvalidVercelSignature = e.New("invalid vercel log drain signature")
+
+type vercelProxy struct {
+	Method     string   `json:"method"`
+	Scheme     string   `json:"scheme"`
+	Host       string   `json:"host"`
+	Path       string   `json:"path"`
+	UserAgent  []string `json:"userAgent"`
+	Referer    string   `json:"referer"`
+	StatusCode int64    `json:"statusCode"`
+	Region     string   `json:"region"`
+}
+
+// vercelLog is a log of the Vercel log drain format.
+// See https://vercel.com/docs/observability/log-drains/log-drains-reference#format
+type vercelLog struct {
+	ID           string      `json:"id"`
+	Message      string      `json:"message"`
+	Timestamp    int64       `json:"timestamp"`
+	Source       string      `json:"source"`
+	ProjectID    string      `json:"projectId"`
+	DeploymentID string      `json:"deploymentId"`
+	Host         string      `json:"host"`
+	Type         string      `json:"type"`
+	Level        string      `json:"level"`
+	Environment  string      `json:"environment"`
+	Entrypoint   string      `json:"entrypoint"`
+	RequestID    string      `json:"requestId"`
+	StatusCode   int64       `json:"statusCode"`
+	Path         string      `json:"path"`
+	Proxy        vercelProxy `json:"proxy"`
+}
+
+// parseVercelLogs decodes a log drain body in the ndjson delivery format, or the json one which is an array of logs.
+func parseVercelLogs(body []byte) ([]*vercelLog, error) {
+	body = bytes.TrimSpace(body)
+	var logs []*vercelLog
+	if bytes.HasPrefix(body, []byte("[")) {
+		if err := json.Unmarshal(body, &logs); err != nil {
+			return nil, e.Wrap(err, "invalid vercel logs")
+		}
+		return logs, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var l vercelLog
+		if err := json.Unmarshal(line, &l); err != nil {
+			return nil, e.Wrap(err, "invalid vercel log")
+		}
+		logs = append(logs, &l)
+	}
+	return logs, scanner.Err()
+}
+
+// verifyVercelSignature checks the hex encoded HMAC-SHA1 of the body that Vercel signs deliveries with.
+func verifyVercelSignature(body []byte, signature string, secrets ...string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal(mac.Sum(nil), expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// getVercelLogFields maps a Vercel log onto the fields of a log row. Request logs without a message
+// are described by their request.
+func getVercelLogFields(projectID int, l *vercelLog) *extractedFields {
+	fields := newExtractedFields()
+	fields.projectIDInt = projectID
+	fields.projectID = strconv.Itoa(projectID)
+	fields.source = privateModel.LogSourceBackend
+	fields.serviceName = "vercel-log-drain-" + l.ProjectID
+	fields.serviceVersion = l.DeploymentID
+	fields.environment = l.Environment
+	fields.requestID = l.RequestID
+	fields.timestamp = time.UnixMilli(l.Timestamp)
+
+	fields.logBody = l.Message
+	if fields.logBody == "" && l.Proxy.Method != "" {
+		fields.logBody = fmt.Sprintf("%s %s://%s%s", l.Proxy.Method, l.Proxy.Scheme, l.Proxy.Host, l.Proxy.Path)
+	}
+
+	level := l.Level
+	if level == "" {
+		switch l.Type {
+		case "stderr":
+			level = "error"
+		case "fatal":
+			level = l.Type
+		case "stdout":
+			level = "info"
+		}
+	}
+	if level == "" && l.StatusCode >= 500 {
+		level = "error"
+	}
+	fields.logSeverity = severity.Level(level).String()
+
+	for k, v := range map[string]string{
+		"vercel.source":                  l.Source,
+		"vercel.type":                    l.Type,
+		"vercel.project_id":              l.ProjectID,
+		"vercel.deployment_id":           l.DeploymentID,
+		"vercel.request_id":              l.RequestID,
+		"vercel.region":                  l.Proxy.Region,
+		string(semconv.HostNameKey):      l.Host,
+		string(semconv.CodeFilepathKey):  l.Path,
+		string(semconv.CodeFunctionKey):  l.Entrypoint,
+		string(semconv.HTTPMethodKey):    l.Proxy.Method,
+		string(semconv.HTTPUserAgentKey): strings.Join(l.Proxy.UserAgent, ","),
+		string(semconv.HTTPURLKey):       l.Proxy.Path,
+		"http.referer":                   l.Proxy.Referer,
+	} {
+		if v != "" {
+			fields.attrs[k] = v
+		}
+	}
+	statusCode := l.StatusCode
+	if statusCode == 0 {
+		statusCode = l.Proxy.StatusCode
+	}
+	if statusCode != 0 {
+		fields.attrs[string(semconv.HTTPStatusCodeKey)] = strconv.FormatInt(statusCode, 10)
+	}
+	return fields
+}
+
+// HandleVercelLog writes the logs of a Vercel log drain to the project of the x-highlight-project
+// header or `project_id` query parameter. Deliveries must be signed with the project secret, or the
+// secret of VercelLogDrainSecretEnvVar, configured as the secret of the drain.
+func (o *Handler) HandleVercelLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if token := os.Getenv(VercelLogDrainVerifyEnvVar); token != "" {
+		w.Header().Set(vercelVerifyHeader, token)
+	}
+
+	body, release, err := getBody(w, r, o.limits)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid vercel logs body")
+		http.Error(w, err.Error(), getBodyErrorStatus(err))
+		return
+	}
+	defer release()
+
+	projectID := r.Header.Get(ratelimit.ProjectHeader)
+	if projectID == "" {
+		projectID = r.URL.Query().Get("project_id")
+	}
+	projectIDInt, err := projectToInt(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if o.projects == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	project, err := o.projects.GetProject(ctx, projectIDInt)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectIDInt).Error("failed to get vercel log drain project")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var projectSecret string
+	if project.Secret != nil {
+		projectSecret = *project.Secret
+	}
+	if !verifyVercelSignature(body, r.Header.Get(vercelSignatureHeader), projectSecret, os.Getenv(VercelLogDrainSecretEnvVar)) {
+		http.Error(w, errInvalidVercelSignature.Error(), http.StatusForbidden)
+		return
+	}
+
+	logs, err := parseVercelLogs(body)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid vercel logs payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payloadBytes.add(float64(len(body)), signalLogs)
+	err = o.submitVercelLogs(ctx, projectIDInt, logs)
+	recordSubmission(logsReceived, signalLogs, len(logs), nil, err)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to submit vercel logs")
+		writeSubmitError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (o *Handler) submitVercelLogs(ctx context.Context, projectID int, logs []*vercelLog) error {
+	fields := make([]*extractedFields, 0, len(logs))
+	dedupeIDs := make([]string, 0, len(logs))
+	for _, l := range logs {
+		fields = append(fields, getVercelLogFields(projectID, l))
+		dedupeIDs = append(dedupeIDs, l.ID)
+	}
+	return o.submitDrainLogs(ctx, "vercel", fields, dedupeIDs)
+}
@@ -0,0 +1,98 @@
+package otel
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const testVercelLogs = `{"id":"1","message":"hello","timestamp":1700000000000,"source":"lambda","projectId":"prj_1","deploymentId":"dpl_1","host":"app.vercel.app","type":"stdout","environment":"production","requestId":"req_1","path":"/api/hello","entrypoint":"api/hello.ts"}
+{"id":"2","timestamp":1700000001000,"source":"edge","projectId":"prj_1","deploymentId":"dpl_1","type":"stderr","proxy":{"method":"GET","scheme":"https","host":"app.vercel.app","path":"/home","userAgent":["curl/8.0"],"statusCode":502,"region":"iad1"}}
+`
+
+func signVercelLogs(secret string, body string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseVercelLogs(t *testing.T) {
+	logs, err := parseVercelLogs([]byte(testVercelLogs))
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "hello", logs[0].Message)
+	assert.Equal(t, "GET", logs[1].Proxy.Method)
+
+	logs, err = parseVercelLogs([]byte(`[{"id":"1","message":"a"},{"id":"2","message":"b"}]`))
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+
+	_, err = parseVercelLogs([]byte("{\"id\":\"1\"}\nnot json"))
+	assert.Error(t, err)
+}
+
+func TestVerifyVercelSignature(t *testing.T) {
+	signature := signVercelLogs("secret-1", testVercelLogs)
+	assert.True(t, verifyVercelSignature([]byte(testVercelLogs), signature, "secret-2", "secret-1"))
+	assert.False(t, verifyVercelSignature([]byte(testVercelLogs), signature, "secret-2", ""))
+	assert.False(t, verifyVercelSignature([]byte(testVercelLogs), "", "secret-1"))
+	assert.False(t, verifyVercelSignature([]byte(testVercelLogs), "not hex", "secret-1"))
+}
+
+func TestGetVercelLogFields(t *testing.T) {
+	logs, err := parseVercelLogs([]byte(testVercelLogs))
+	assert.NoError(t, err)
+
+	fields := getVercelLogFields(1, logs[0])
+	assert.Equal(t, 1, fields.projectIDInt)
+	assert.Equal(t, "hello", fields.logBody)
+	assert.Equal(t, "info", fields.logSeverity)
+	assert.Equal(t, "vercel-log-drain-prj_1", fields.serviceName)
+	assert.Equal(t, "dpl_1", fields.serviceVersion)
+	assert.Equal(t, "production", fields.environment)
+	assert.Equal(t, privateModel.LogSourceBackend, fields.source)
+	assert.Equal(t, time.UnixMilli(1700000000000), fields.timestamp)
+	assert.Equal(t, "lambda", fields.attrs["vercel.source"])
+	assert.Equal(t, "api/hello.ts", fields.attrs["code.function"])
+
+	fields = getVercelLogFields(1, logs[1])
+	assert.Equal(t, "GET https://app.vercel.app/home", fields.logBody)
+	assert.Equal(t, "error", fields.logSeverity)
+	assert.Equal(t, "502", fields.attrs["http.status_code"])
+	assert.Equal(t, "curl/8.0", fields.attrs["http.user_agent"])
+	assert.Equal(t, "iad1", fields.attrs["vercel.region"])
+}
+
+func TestHandler_HandleVercelLog(t *testing.T) {
+	h := Handler{projects: newMockProjectStore()}
+	t.Setenv(VercelLogDrainVerifyEnvVar, "verify-token")
+
+	for _, tc := range []struct {
+		name      string
+		project   string
+		signature string
+		code      int
+	}{
+		{"missing project", "", signVercelLogs("secret-1", testVercelLogs), http.StatusBadRequest},
+		{"unsigned", "1", "", http.StatusForbidden},
+		{"secret of another project", "1", signVercelLogs("secret-2", testVercelLogs), http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, VercelLogsPath, bytes.NewReader([]byte(testVercelLogs)))
+			r.Header.Set("x-highlight-project", tc.project)
+			r.Header.Set(vercelSignatureHeader, tc.signature)
+			w := httptest.NewRecorder()
+			h.HandleVercelLog(w, r)
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, "verify-token", w.Header().Get(vercelVerifyHeader))
+		})
+	}
+}
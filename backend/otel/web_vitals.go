@@ -0,0 +1,60 @@
+package otel
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/openlyinc/pointy"
+	e "github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// attributes of the web vital span events of browser RUM instrumentation.
+const (
+	// sessionIDAttribute is the session id of the current semantic conventions, used when
+	// the client does not set the highlight session id.
+	sessionIDAttribute     = "session.id"
+	webVitalValueAttribute = "web_vital.value"
+	valueAttribute         = "value"
+)
+
+// getWebVitalName returns the web vital that a span event is named after, ie. `CLS` for a `cls` event.
+func getWebVitalName(eventName string) (string, bool) {
+	name := strings.ToUpper(eventName)
+	return name, clickhouse.IsWebVital(name)
+}
+
+// getWebVital converts a web vital span event of a browser session to a metric, which the session
+// metrics pipeline writes as a web vital of the session. The value is read from the `<name>.value`,
+// `web_vital.value` or `value` attribute, and the page from the url of the event or span.
+func getWebVital(ctx context.Context, name string, fields *extractedFields, spanID, parentSpanID, traceID string) (*model.MetricInput, error) {
+	if fields.sessionID == "" {
+		fields.sessionID = fields.attrs[sessionIDAttribute]
+		delete(fields.attrs, sessionIDAttribute)
+	}
+	if fields.sessionID == "" {
+		return nil, e.Errorf("otel received web vital %s with no session", name)
+	}
+
+	valueKeys := []string{strings.ToLower(name) + ".value", webVitalValueAttribute, valueAttribute}
+	value, err := strconv.ParseFloat(firstAttribute(fields.attrs, valueKeys...), 64)
+	if err != nil {
+		return nil, e.Wrapf(err, "otel received web vital %s with an invalid value", name)
+	}
+	for _, key := range valueKeys {
+		delete(fields.attrs, key)
+	}
+
+	metricFields := *fields
+	metricFields.metricEventName = name
+	metricFields.metricEventValue = value
+	metric, err := getMetric(ctx, fields.timestamp, &metricFields, spanID, parentSpanID, traceID)
+	if err != nil {
+		return nil, err
+	}
+	metric.Group = pointy.String(firstAttribute(fields.attrs, urlFullAttribute, string(semconv.HTTPURLKey)))
+	return metric, nil
+}
@@ -0,0 +1,53 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebVitalName(t *testing.T) {
+	for eventName, expected := range map[string]string{"cls": "CLS", "LCP": "LCP", "Fid": "FID", "inp": "INP"} {
+		name, ok := getWebVitalName(eventName)
+		assert.True(t, ok, eventName)
+		assert.Equal(t, expected, name)
+	}
+	for _, eventName := range []string{"exception", highlight.MetricEvent, "click"} {
+		_, ok := getWebVitalName(eventName)
+		assert.False(t, ok, eventName)
+	}
+}
+
+func TestGetWebVital(t *testing.T) {
+	ctx := context.Background()
+
+	fields := newExtractedFields()
+	fields.attrs["lcp.value"] = "1234.5"
+	_, err := getWebVital(ctx, "LCP", fields, "span", "", "trace")
+	assert.Error(t, err, "web vitals require a session")
+
+	fields.attrs[sessionIDAttribute] = "session-1"
+	fields.attrs[urlFullAttribute] = "https://app.example.com/home"
+	metric, err := getWebVital(ctx, "LCP", fields, "span", "", "trace")
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", metric.SessionSecureID)
+	assert.Equal(t, "LCP", metric.Name)
+	assert.Equal(t, 1234.5, metric.Value)
+	assert.Equal(t, "https://app.example.com/home", *metric.Group)
+	assert.NotContains(t, fields.attrs, "lcp.value")
+
+	fields = newExtractedFields()
+	fields.sessionID = "session-2"
+	fields.attrs[valueAttribute] = "0.1"
+	metric, err = getWebVital(ctx, "CLS", fields, "span", "", "trace")
+	assert.NoError(t, err)
+	assert.Equal(t, "session-2", metric.SessionSecureID)
+	assert.Equal(t, 0.1, metric.Value)
+
+	fields = newExtractedFields()
+	fields.sessionID = "session-3"
+	_, err = getWebVital(ctx, "INP", fields, "span", "", "trace")
+	assert.Error(t, err, "web vitals require a value")
+}
@@ -0,0 +1,196 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	EventsApiUrl = "https://events.pagerduty.com/v2/enqueue"
+)
+
+const requestTimeout = 10 * time.Second
+
+// maxSummaryLength is the longest summary of an event accepted by PagerDuty.
+const maxSummaryLength = 1024
+
+// source is the source of the events, shown on the PagerDuty alerts.
+const source = "highlight.io"
+
+type Action string
+
+const (
+	ActionTrigger     Action = "trigger"
+	ActionAcknowledge Action = "acknowledge"
+	ActionResolve     Action = "resolve"
+)
+
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+// DefaultSeverity is the severity of the events of destinations that do not set one.
+const DefaultSeverity = SeverityError
+
+var severities = []Severity{SeverityCritical, SeverityError, SeverityWarning, SeverityInfo}
+
+func (s Severity) IsValid() bool {
+	return lo.Contains(severities, s)
+}
+
+// ErrorGroupDedupKey returns the dedup key of the events of an error group, so that all the alerts
+// of an error group open a single PagerDuty incident which is resolved with the error group.
+func ErrorGroupDedupKey(errorGroupID int) string {
+	return fmt.Sprintf("highlight-error-group-%d", errorGroupID)
+}
+
+type Payload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      Severity       `json:"severity"`
+	Timestamp     *time.Time     `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Group         string         `json:"group,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+type Link struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+// Event is an event of the Events API v2. The payload is only set for `trigger` events.
+type Event struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction Action   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key,omitempty"`
+	Payload     *Payload `json:"payload,omitempty"`
+	Client      string   `json:"client,omitempty"`
+	ClientURL   string   `json:"client_url,omitempty"`
+	Links       []*Link  `json:"links,omitempty"`
+}
+
+type Response struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	DedupKey string `json:"dedup_key"`
+}
+
+// Client sends events to the integrations of PagerDuty services, which are addressed by the
+// routing key of the event.
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+// Send enqueues an event. PagerDuty processes events asynchronously, so a response only means
+// the event was accepted.
+func (c *Client) Send(ctx context.Context, event *Event) (*Response, error) {
+	if event.RoutingKey == "" {
+		return nil, errors.New("PagerDuty routing key is not set")
+	}
+	if event.Payload != nil {
+		event.Payload.Summary = truncate(event.Payload.Summary, maxSummaryLength)
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, EventsApiUrl, bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request to PagerDuty")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting response from PagerDuty events endpoint")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body from PagerDuty events endpoint")
+	}
+	if res.StatusCode != http.StatusAccepted {
+		return nil, errors.New("PagerDuty events API responded with error; status_code=" + res.Status + "; body=" + string(body))
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling PagerDuty response")
+	}
+	return &response, nil
+}
+
+type TriggerInput struct {
+	RoutingKey    string
+	DedupKey      string
+	Summary       string
+	Severity      Severity
+	Class         string
+	CustomDetails map[string]any
+	Links         []*Link
+}
+
+// Trigger opens an incident, or adds an alert to the open incident of the dedup key.
+func (c *Client) Trigger(ctx context.Context, input *TriggerInput) (*Response, error) {
+	severity := input.Severity
+	if !severity.IsValid() {
+		severity = DefaultSeverity
+	}
+	timestamp := time.Now()
+	return c.Send(ctx, &Event{
+		RoutingKey:  input.RoutingKey,
+		EventAction: ActionTrigger,
+		DedupKey:    input.DedupKey,
+		Payload: &Payload{
+			Summary:       input.Summary,
+			Source:        source,
+			Severity:      severity,
+			Timestamp:     &timestamp,
+			Class:         input.Class,
+			CustomDetails: input.CustomDetails,
+		},
+		Client: "Highlight",
+		Links:  input.Links,
+	})
+}
+
+// Acknowledge acknowledges the open incident of the dedup key.
+func (c *Client) Acknowledge(ctx context.Context, routingKey string, dedupKey string) (*Response, error) {
+	return c.Send(ctx, &Event{RoutingKey: routingKey, EventAction: ActionAcknowledge, DedupKey: dedupKey})
+}
+
+// Resolve resolves the open incident of the dedup key.
+func (c *Client) Resolve(ctx context.Context, routingKey string, dedupKey string) (*Response, error) {
+	return c.Send(ctx, &Event{RoutingKey: routingKey, EventAction: ActionResolve, DedupKey: dedupKey})
+}
+
+func truncate(s string, length int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
@@ -0,0 +1,69 @@
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var events []*Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		if event.RoutingKey == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"invalid event","message":"Event object is invalid"}`))
+			return
+		}
+		events = append(events, &event)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status":"success","message":"Event processed","dedup_key":"` + event.DedupKey + `"}`))
+	}))
+	defer server.Close()
+
+	eventsApiUrl := EventsApiUrl
+	EventsApiUrl = server.URL
+	defer func() { EventsApiUrl = eventsApiUrl }()
+
+	ctx := context.Background()
+	client := NewClient()
+	dedupKey := ErrorGroupDedupKey(1)
+	assert.Equal(t, "highlight-error-group-1", dedupKey)
+
+	res, err := client.Trigger(ctx, &TriggerInput{
+		RoutingKey: "key",
+		DedupKey:   dedupKey,
+		Summary:    strings.Repeat("a", 2000),
+		Severity:   "fatal",
+		Links:      []*Link{{Href: "https://app.highlight.io/1/errors/abc", Text: "View error"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, dedupKey, res.DedupKey)
+
+	_, err = client.Acknowledge(ctx, "key", dedupKey)
+	assert.NoError(t, err)
+	_, err = client.Resolve(ctx, "key", dedupKey)
+	assert.NoError(t, err)
+
+	_, err = client.Resolve(ctx, "invalid", dedupKey)
+	assert.Error(t, err)
+	_, err = client.Resolve(ctx, "", dedupKey)
+	assert.Error(t, err)
+
+	assert.Len(t, events, 3)
+	assert.Equal(t, ActionTrigger, events[0].EventAction)
+	assert.Equal(t, DefaultSeverity, events[0].Payload.Severity)
+	assert.Equal(t, "highlight.io", events[0].Payload.Source)
+	assert.Len(t, []rune(events[0].Payload.Summary), maxSummaryLength)
+	assert.Equal(t, "https://app.highlight.io/1/errors/abc", events[0].Links[0].Href)
+	assert.Equal(t, ActionAcknowledge, events[1].EventAction)
+	assert.Nil(t, events[1].Payload)
+	assert.Equal(t, ActionResolve, events[2].EventAction)
+	assert.Equal(t, dedupKey, events[2].DedupKey)
+}
@@ -171,6 +171,8 @@ func (s *searchListener[T]) appendRules(value string) {
 	filterKey, ok := s.tableConfig.KeysToColumns[T(s.currentKey)]
 	if !ok {
 		traceAttributeKey = true
+	} else if normalize, ok := s.tableConfig.ValueNormalizers[T(s.currentKey)]; ok && !strings.Contains(value, "*") {
+		value = normalize(value)
 	}
 
 	if s.currentOp == ":" || s.currentOp == "=" {
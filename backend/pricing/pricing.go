@@ -417,15 +417,19 @@ func get7DayAverageImpl(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.C
 	return avgFn(ctx, projectIds, backend.DateRangeRequiredInput{StartDate: startDate, EndDate: endDate})
 }
 
-func getWorkspaceMeterImpl(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, workspace *model.Workspace, productType model.PricingProductType) (int64, error) {
+// getBillingPeriod is the billing period of the workspace that usage is metered in,
+// defaulting to the current calendar month.
+func getBillingPeriod(workspace *model.Workspace, now time.Time) (time.Time, time.Time) {
+	currentYear, currentMonth, _ := now.Date()
+	monthStart := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, time.UTC)
+
 	var startDate time.Time
 	if workspace.NextInvoiceDate != nil {
 		startDate = workspace.NextInvoiceDate.AddDate(0, -1, 0)
 	} else if workspace.BillingPeriodStart != nil {
 		startDate = *workspace.BillingPeriodStart
 	} else {
-		currentYear, currentMonth, _ := time.Now().Date()
-		startDate = time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, time.UTC)
+		startDate = monthStart
 	}
 
 	var endDate time.Time
@@ -434,30 +438,48 @@ func getWorkspaceMeterImpl(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 	} else if workspace.BillingPeriodEnd != nil {
 		endDate = *workspace.BillingPeriodEnd
 	} else {
-		currentYear, currentMonth, _ := time.Now().Date()
-		endDate = time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+		endDate = monthStart.AddDate(0, 1, 0)
 	}
+	return startDate, endDate
+}
+
+// GetWorkspaceMeterByProject is the count of log rows or spans ingested by each project of the
+// workspace in its current billing period.
+func GetWorkspaceMeterByProject(ctx context.Context, ccClient *clickhouse.Client, workspace *model.Workspace, productType model.PricingProductType) (map[int]int64, error) {
+	startDate, endDate := getBillingPeriod(workspace, time.Now())
 
 	projectIds := lo.Map(workspace.Projects, func(p model.Project, _ int) int {
 		return p.ID
 	})
 
-	var sumFn func(ctx context.Context, projectIds []int, dateRange backend.DateRangeRequiredInput) (uint64, error)
+	var sumFn func(ctx context.Context, projectIds []int, dateRange backend.DateRangeRequiredInput) (map[int]uint64, error)
 	switch productType {
 	case model.PricingProductTypeLogs:
-		sumFn = ccClient.ReadLogsDailySum
+		sumFn = ccClient.ReadLogsDailySumByProject
 	case model.PricingProductTypeTraces:
-		sumFn = ccClient.ReadTracesDailySum
+		sumFn = ccClient.ReadTracesDailySumByProject
 	default:
-		return 0, fmt.Errorf("invalid product type %s", productType)
+		return nil, fmt.Errorf("invalid product type %s", productType)
 	}
 
-	count, err := sumFn(ctx, projectIds, backend.DateRangeRequiredInput{StartDate: startDate, EndDate: endDate})
+	counts, err := sumFn(ctx, projectIds, backend.DateRangeRequiredInput{StartDate: startDate, EndDate: endDate})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return int64(count), nil
+	meters := map[int]int64{}
+	for projectId, count := range counts {
+		meters[projectId] = int64(count)
+	}
+	return meters, nil
+}
+
+func getWorkspaceMeterImpl(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, workspace *model.Workspace, productType model.PricingProductType) (int64, error) {
+	meters, err := GetWorkspaceMeterByProject(ctx, ccClient, workspace, productType)
+	if err != nil {
+		return 0, err
+	}
+	return lo.Sum(lo.Values(meters)), nil
 }
 
 func GetLogs7DayAverage(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, workspace *model.Workspace) (float64, error) {
@@ -599,6 +621,14 @@ func GetBaseLookupKey(productTier backend.PlanType, interval model.PricingSubscr
 	return
 }
 
+// usageBasedMeteredProducts are billed with metered prices on usage-based plans,
+// their overage is reported to Stripe as usage records rather than invoice items.
+var usageBasedMeteredProducts = map[model.PricingProductType]bool{
+	model.PricingProductTypeSessions: true,
+	model.PricingProductTypeLogs:     true,
+	model.PricingProductTypeTraces:   true,
+}
+
 func GetOverageKey(productType model.PricingProductType, retentionPeriod backend.RetentionPeriod, planType backend.PlanType) string {
 	result := string(productType)
 	if retentionPeriod != backend.RetentionPeriodThreeMonths {
@@ -607,7 +637,7 @@ func GetOverageKey(productType model.PricingProductType, retentionPeriod backend
 
 	if planType == backend.PlanTypeGraduated {
 		result += "|" + backend.PlanTypeGraduated.String()
-	} else if planType == backend.PlanTypeUsageBased && productType == model.PricingProductTypeSessions {
+	} else if planType == backend.PlanTypeUsageBased && usageBasedMeteredProducts[productType] {
 		result += "|" + backend.PlanTypeUsageBased.String()
 	}
 	return result
@@ -1025,9 +1055,9 @@ func (w *Worker) reportUsage(ctx context.Context, workspaceID int, productType *
 	}
 
 	// Update logs overage
-	logsMeter, err := GetWorkspaceLogsMeter(ctx, w.db, w.ccClient, &workspace)
+	logsMeter, err := w.getProjectsMeter(ctx, &workspace, model.PricingProductTypeLogs)
 	if err != nil {
-		return e.Wrap(err, "error getting errors meter")
+		return e.Wrap(err, "error getting logs meter")
 	}
 	logsLimit := IncludedAmount(backend.PlanType(workspace.PlanTier), model.PricingProductTypeLogs)
 	if workspace.MonthlyLogsLimit != nil {
@@ -1038,7 +1068,7 @@ func (w *Worker) reportUsage(ctx context.Context, workspaceID int, productType *
 	}
 
 	// Update traces overage
-	tracesMeter, err := GetWorkspaceTracesMeter(ctx, w.db, w.ccClient, &workspace)
+	tracesMeter, err := w.getProjectsMeter(ctx, &workspace, model.PricingProductTypeTraces)
 	if err != nil {
 		return e.Wrap(err, "error getting traces meter")
 	}
@@ -1220,6 +1250,24 @@ func AddOrUpdateOverageItem(stripeClient *client.API, workspace *model.Workspace
 	return nil
 }
 
+// getProjectsMeter is the log rows or spans ingested by the workspace in its billing period,
+// counted per project so that the usage reported to Stripe can be attributed to projects.
+func (w *Worker) getProjectsMeter(ctx context.Context, workspace *model.Workspace, productType model.PricingProductType) (int64, error) {
+	meters, err := GetWorkspaceMeterByProject(ctx, w.ccClient, workspace, productType)
+	if err != nil {
+		return 0, err
+	}
+	for projectID, meter := range meters {
+		log.WithContext(ctx).
+			WithField("workspace_id", workspace.ID).
+			WithField("project_id", projectID).
+			WithField("product_type", productType).
+			WithField("meter", meter).
+			Info("STRIPE_INTEGRATION_INFO project usage")
+	}
+	return lo.Sum(lo.Values(meters)), nil
+}
+
 func (w *Worker) ReportAllUsage(ctx context.Context) {
 	// Get all workspace IDs
 	var workspaceIDs []int
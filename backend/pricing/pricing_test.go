@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"math"
 	"testing"
+	"time"
 )
 
 func TestGetLimitAmount(t *testing.T) {
@@ -60,3 +61,32 @@ func TestGetLimitAmount(t *testing.T) {
 		}
 	}
 }
+
+func TestGetOverageKey(t *testing.T) {
+	assert.Equal(t, "SESSIONS", GetOverageKey(model.PricingProductTypeSessions, backend.RetentionPeriodThreeMonths, backend.PlanTypeStartup))
+	assert.Equal(t, "ERRORS|SixMonths", GetOverageKey(model.PricingProductTypeErrors, backend.RetentionPeriodSixMonths, backend.PlanTypeStartup))
+	assert.Equal(t, "LOGS|Graduated", GetOverageKey(model.PricingProductTypeLogs, backend.RetentionPeriodThreeMonths, backend.PlanTypeGraduated))
+	assert.Equal(t, "SESSIONS|UsageBased", GetOverageKey(model.PricingProductTypeSessions, backend.RetentionPeriodThreeMonths, backend.PlanTypeUsageBased))
+	assert.Equal(t, "LOGS|UsageBased", GetOverageKey(model.PricingProductTypeLogs, backend.RetentionPeriodThreeMonths, backend.PlanTypeUsageBased))
+	assert.Equal(t, "TRACES|UsageBased", GetOverageKey(model.PricingProductTypeTraces, backend.RetentionPeriodThreeMonths, backend.PlanTypeUsageBased))
+	assert.Equal(t, "ERRORS", GetOverageKey(model.PricingProductTypeErrors, backend.RetentionPeriodThreeMonths, backend.PlanTypeUsageBased))
+}
+
+func TestGetBillingPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	start, end := getBillingPeriod(&model.Workspace{}, now)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), end)
+
+	periodStart := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	start, end = getBillingPeriod(&model.Workspace{BillingPeriodStart: &periodStart, BillingPeriodEnd: &periodEnd}, now)
+	assert.Equal(t, periodStart, start)
+	assert.Equal(t, periodEnd, end)
+
+	nextInvoice := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	start, end = getBillingPeriod(&model.Workspace{NextInvoiceDate: &nextInvoice, BillingPeriodStart: &periodStart, BillingPeriodEnd: &periodEnd}, now)
+	assert.Equal(t, time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, nextInvoice, end)
+}
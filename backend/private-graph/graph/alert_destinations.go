@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/opsgenie"
+	"github.com/highlight-run/highlight/backend/pagerduty"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+func validateSeverity(severity *string) error {
+	if *severity == "" {
+		*severity = string(pagerduty.DefaultSeverity)
+	}
+	if !pagerduty.Severity(*severity).IsValid() {
+		return e.Errorf("invalid severity %s", *severity)
+	}
+	return nil
+}
+
+// getErrorAlertDestinations validates the incident management destinations of an error alert. The
+// severity of a destination is one of `critical`, `error`, `warning` or `info`, which is mapped to
+// the severity, priority or message type of each provider. The api keys of the destinations are
+// not returned, so an empty key keeps the key of the current destination of the error alert.
+func getErrorAlertDestinations(input modelInputs.ErrorAlertDestinationsInput, errorAlert *model.ErrorAlert) (model.PagerDutyDestinations, model.OpsgenieDestinations, model.SplunkOnCallDestinations, error) {
+	pagerDuty := model.PagerDutyDestinations{}
+	for _, destination := range input.PagerDuty {
+		routingKey := strings.TrimSpace(destination.RoutingKey)
+		if routingKey == "" {
+			return nil, nil, nil, e.New("PagerDuty routing key is required")
+		}
+		severity := ptr.ToString(destination.Severity)
+		if err := validateSeverity(&severity); err != nil {
+			return nil, nil, nil, err
+		}
+		pagerDuty = append(pagerDuty, &model.PagerDutyDestination{RoutingKey: routingKey, Severity: severity})
+	}
+
+	opsgenieDestinations := model.OpsgenieDestinations{}
+	for idx, destination := range input.Opsgenie {
+		apiKey := strings.TrimSpace(ptr.ToString(destination.APIKey))
+		// opsgenie destinations have no other identifier, so they are matched by their position
+		if apiKey == "" && idx < len(errorAlert.OpsgenieDestinations) {
+			apiKey = errorAlert.OpsgenieDestinations[idx].APIKey
+		}
+		if apiKey == "" {
+			return nil, nil, nil, e.New("Opsgenie api key is required")
+		}
+		if !opsgenie.Region(destination.Region).IsValid() {
+			return nil, nil, nil, e.Errorf("invalid Opsgenie region %s", destination.Region)
+		}
+		severity := ptr.ToString(destination.Severity)
+		if err := validateSeverity(&severity); err != nil {
+			return nil, nil, nil, err
+		}
+		opsgenieDestinations = append(opsgenieDestinations, &model.OpsgenieDestination{APIKey: apiKey, Region: destination.Region, Severity: severity})
+	}
+
+	splunkOnCall := model.SplunkOnCallDestinations{}
+	for _, destination := range input.SplunkOnCall {
+		apiKey := strings.TrimSpace(ptr.ToString(destination.APIKey))
+		routingKey := strings.TrimSpace(destination.RoutingKey)
+		if apiKey == "" {
+			if existing, ok := lo.Find(errorAlert.SplunkOnCallDestinations, func(d *model.SplunkOnCallDestination) bool {
+				return d.RoutingKey == routingKey
+			}); ok {
+				apiKey = existing.APIKey
+			}
+		}
+		if apiKey == "" || routingKey == "" {
+			return nil, nil, nil, e.New("Splunk On-Call api key and routing key are required")
+		}
+		severity := ptr.ToString(destination.Severity)
+		if err := validateSeverity(&severity); err != nil {
+			return nil, nil, nil, err
+		}
+		splunkOnCall = append(splunkOnCall, &model.SplunkOnCallDestination{APIKey: apiKey, RoutingKey: routingKey, Severity: severity})
+	}
+
+	return pagerDuty, opsgenieDestinations, splunkOnCall, nil
+}
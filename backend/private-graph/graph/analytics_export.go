@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/integrations/amplitude"
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
+	"github.com/highlight-run/highlight/backend/integrations/mixpanel"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+func isValidAnalyticsRegion(destination analytics.Destination, region string) bool {
+	if destination == analytics.DestinationAmplitude {
+		return amplitude.Region(region).IsValid()
+	}
+	return mixpanel.Region(region).IsValid()
+}
+
+// applyProductAnalyticsExportInput configures the export of a project's session events to Amplitude
+// or Mixpanel. Events are exported from the time the export is enabled.
+func applyProductAnalyticsExportInput(input modelInputs.ProductAnalyticsExportInput, export *model.ProductAnalyticsExport) error {
+	region := "us"
+	if input.Region != nil && *input.Region != "" {
+		region = *input.Region
+	}
+	if !isValidAnalyticsRegion(analytics.Destination(export.Destination), region) {
+		return e.Errorf("invalid region %s", region)
+	}
+	userIDProperty := analytics.DefaultUserIDProperty
+	if input.UserIDProperty != nil && *input.UserIDProperty != "" {
+		userIDProperty = *input.UserIDProperty
+	}
+
+	if input.APIKey != nil {
+		export.APIKey = strings.TrimSpace(*input.APIKey)
+	}
+	if export.APIKey == "" {
+		return e.New("api key is required")
+	}
+
+	// an export that was disabled starts exporting from when it is enabled again
+	if input.Enabled && !export.Enabled {
+		export.LastSessionID = 0
+		export.LastRageClickID = 0
+	}
+	export.Region = region
+	export.UserIDProperty = userIDProperty
+	export.ExportRageClicks = input.ExportRageClicks
+	export.ExportIdentifiedSessions = input.ExportIdentifiedSessions
+	export.Enabled = input.Enabled
+	return nil
+}
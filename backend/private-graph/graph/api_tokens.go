@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAPITokenNameLength = 64
+	maxAPITokenExpiryDays = 366
+)
+
+func apiTokenFromContext(ctx context.Context) *model.APIToken {
+	apiToken, _ := ctx.Value(model.ContextKeys.APIToken).(*model.APIToken)
+	return apiToken
+}
+
+// apiTokenAllowsWorkspace is whether the API token that authenticated the request, if any, can
+// access the workspace.
+func apiTokenAllowsWorkspace(ctx context.Context, workspaceID int) bool {
+	apiToken := apiTokenFromContext(ctx)
+	return apiToken == nil || apiToken.WorkspaceID == nil || *apiToken.WorkspaceID == workspaceID
+}
+
+// APITokenMiddleware authenticates requests with an `Authorization: Bearer` API token as the admin
+// of the token. Requests without an API token are passed on unchanged.
+func (r *Resolver) APITokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := apitoken.FromRequest(req)
+		if token == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		ctx := req.Context()
+		apiToken, err := r.Store.GetActiveAPIToken(ctx, token)
+		if err != nil {
+			if !e.Is(err, gorm.ErrRecordNotFound) {
+				log.WithContext(ctx).Error(e.Wrap(err, "error querying api token"))
+			}
+			http.Error(w, "invalid api token", http.StatusUnauthorized)
+			return
+		}
+		var admin model.Admin
+		if err := r.DB.WithContext(ctx).Where(&model.Admin{Model: model.Model{ID: apiToken.AdminID}}).Take(&admin).Error; err != nil || admin.UID == nil {
+			http.Error(w, "invalid api token", http.StatusUnauthorized)
+			return
+		}
+		if err := r.Store.MarkAPITokenUsed(ctx, apiToken); err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error marking api token as used"))
+		}
+		ctx = context.WithValue(ctx, model.ContextKeys.UID, *admin.UID)
+		ctx = context.WithValue(ctx, model.ContextKeys.APIToken, apiToken)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+type APITokenValidator interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+}
+
+type apiTokenValidator struct{}
+
+// NewGraphqlAPITokenValidator limits the queries and mutations of requests authenticated with an API
+// token to the scopes of the token.
+func NewGraphqlAPITokenValidator() APITokenValidator {
+	return apiTokenValidator{}
+}
+
+func (v apiTokenValidator) ExtensionName() string {
+	return "HighlightAPITokenValidator"
+}
+
+func (v apiTokenValidator) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (v apiTokenValidator) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	apiToken := apiTokenFromContext(ctx)
+	if apiToken == nil {
+		return next(ctx)
+	}
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || (fc.Object != "Query" && fc.Object != "Mutation") {
+		return next(ctx)
+	}
+	scope, ok := apitoken.FieldScope(fc.Field.Name)
+	if !ok {
+		return nil, e.Wrapf(AuthorizationError, "%s cannot be used with an api token", fc.Field.Name)
+	}
+	if !apitoken.HasScope(apiToken.Scopes, scope) {
+		return nil, e.Wrapf(AuthorizationError, "%s requires the %s scope", fc.Field.Name, scope)
+	}
+	return next(ctx)
+}
+
+// applyAPITokenInput validates the input of a new API token.
+func applyAPITokenInput(input modelInputs.APITokenInput, apiToken *model.APIToken) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxAPITokenNameLength {
+		return e.Errorf("token name must be between 1 and %d characters", maxAPITokenNameLength)
+	}
+	scopes, err := apitoken.ParseScopes(input.Scopes)
+	if err != nil {
+		return err
+	}
+	apiToken.Name = name
+	apiToken.Scopes = nil
+	for _, scope := range scopes {
+		apiToken.Scopes = append(apiToken.Scopes, string(scope))
+	}
+	if input.ExpiresInDays != nil {
+		if *input.ExpiresInDays <= 0 || *input.ExpiresInDays > maxAPITokenExpiryDays {
+			return e.Errorf("tokens must expire in between 1 and %d days", maxAPITokenExpiryDays)
+		}
+		expiresAt := time.Now().AddDate(0, 0, *input.ExpiresInDays)
+		apiToken.ExpiresAt = &expiresAt
+	}
+	return nil
+}
+
+// setAPITokenSecret generates a new secret for the API token, returning it.
+func setAPITokenSecret(apiToken *model.APIToken) (string, error) {
+	token, hash, err := apitoken.Generate()
+	if err != nil {
+		return "", err
+	}
+	apiToken.TokenHash = hash
+	apiToken.Prefix = token[:apitoken.DisplayLength]
+	return token, nil
+}
+
+// authorizeAPITokenScopes checks that the admin of a new API token can edit with its write scopes: in
+// the workspace of a workspace token, or in at least one workspace of a personal token.
+func (r *Resolver) authorizeAPITokenScopes(ctx context.Context, apiToken *model.APIToken) error {
+	if !lo.ContainsBy(apiToken.Scopes, func(scope string) bool { return apitoken.Scope(scope).IsWrite() }) {
+		return nil
+	}
+	var workspaceIDs []int
+	if apiToken.WorkspaceID != nil {
+		workspaceIDs = []int{*apiToken.WorkspaceID}
+	} else if err := r.DB.WithContext(ctx).Model(&model.WorkspaceAdmin{}).Where(&model.WorkspaceAdmin{AdminID: apiToken.AdminID}).Pluck("workspace_id", &workspaceIDs).Error; err != nil {
+		return e.Wrap(err, "error querying workspaces of admin")
+	}
+	for _, workspaceID := range workspaceIDs {
+		if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionEdit); err == nil {
+			return nil
+		}
+	}
+	return e.Wrapf(AuthorizationError, "write scopes require the %s permission", rbac.PermissionEdit)
+}
+
+// getManagedAPIToken returns the unrevoked API token if the current admin can manage it: their own
+// personal token, or a token of a workspace that they can manage.
+func (r *Resolver) getManagedAPIToken(ctx context.Context, id int) (*model.APIToken, error) {
+	var apiToken model.APIToken
+	if err := r.DB.WithContext(ctx).Where(&model.APIToken{Model: model.Model{ID: id}}).Where("revoked_at IS NULL").Take(&apiToken).Error; err != nil {
+		return nil, e.Wrap(err, "error querying api token")
+	}
+	if apiToken.WorkspaceID != nil {
+		if err := r.authorizeWorkspace(ctx, *apiToken.WorkspaceID, rbac.PermissionManageWorkspace); err != nil {
+			return nil, err
+		}
+		return &apiToken, nil
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if apiToken.AdminID != admin.ID {
+		return nil, AuthorizationError
+	}
+	return &apiToken, nil
+}
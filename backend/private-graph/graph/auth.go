@@ -58,14 +58,7 @@ func (r *Resolver) Login(w http.ResponseWriter, req *http.Request) {
 
 	user := GetPasswordAuthUser(credentials.Email)
 
-	atClaims := jwt.MapClaims{}
-	atClaims["authorized"] = true
-	atClaims["exp"] = time.Now().Add(AdminPasswordTokenDuration).Unix()
-	atClaims["email"] = user.Email
-	atClaims["uid"] = user.UID
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-
-	token, err := at.SignedString([]byte(JwtAccessSecret))
+	token, err := signPasswordAuthToken(user.Email, user.UID)
 	if err != nil {
 		log.WithContext(ctx).Error(err)
 		http.Error(w, "", http.StatusInternalServerError)
@@ -122,6 +115,17 @@ func (r *Resolver) ValidateAuthToken(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// signPasswordAuthToken signs the token of a user in password auth mode.
+func signPasswordAuthToken(email string, uid string) (string, error) {
+	atClaims := jwt.MapClaims{}
+	atClaims["authorized"] = true
+	atClaims["exp"] = time.Now().Add(AdminPasswordTokenDuration).Unix()
+	atClaims["email"] = email
+	atClaims["uid"] = uid
+	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
+	return at.SignedString([]byte(JwtAccessSecret))
+}
+
 func GetPasswordAuthUser(email string) *auth.UserInfo {
 	return &auth.UserInfo{
 		DisplayName: "Hobby Highlighter",
@@ -0,0 +1,338 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+const workspaceIdUrlParam = "workspace_id"
+
+func customRole(role *model.WorkspaceRole) *rbac.Role {
+	return &rbac.Role{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: rbac.ParsePermissions(role.Permissions),
+	}
+}
+
+// getWorkspaceRoleByName returns the built-in role or the custom role of the workspace with the name.
+func (r *Resolver) getWorkspaceRoleByName(ctx context.Context, workspaceID int, name string) (*rbac.Role, error) {
+	if role := rbac.BuiltInRole(name); role != nil {
+		return role, nil
+	}
+	var role model.WorkspaceRole
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceRole{WorkspaceID: workspaceID, Name: name}).Take(&role).Error; err != nil {
+		return nil, e.Wrapf(err, "error querying workspace role %s", name)
+	}
+	return customRole(&role), nil
+}
+
+// getWorkspaceRole returns the role of the admin in the workspace.
+func (r *Resolver) getWorkspaceRole(ctx context.Context, adminID int, workspaceID int) (*rbac.Role, error) {
+	name, err := r.GetAdminRole(ctx, adminID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return r.getWorkspaceRoleByName(ctx, workspaceID, name)
+}
+
+// getCurrentWorkspaceRole returns the role of the current admin in the workspace. Whitelisted
+// accounts are owners of every workspace.
+func (r *Resolver) getCurrentWorkspaceRole(ctx context.Context, workspaceID int) (*rbac.Role, error) {
+	if r.isWhitelistedAccount(ctx) {
+		return rbac.BuiltInRole(rbac.RoleOwner), nil
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.getWorkspaceRole(ctx, admin.ID, workspaceID)
+}
+
+// authorizeWorkspace checks that the role of the current admin in the workspace has the permission.
+func (r *Resolver) authorizeWorkspace(ctx context.Context, workspaceID int, permission rbac.Permission) error {
+	role, err := r.getCurrentWorkspaceRole(ctx, workspaceID)
+	if err != nil || !role.Can(permission) {
+		return AuthorizationError
+	}
+	return nil
+}
+
+// authorizeManageAdmin checks that the current admin can change the role of, or remove, another
+// admin of the workspace.
+func (r *Resolver) authorizeManageAdmin(ctx context.Context, workspaceID int, adminID int) (*rbac.Role, error) {
+	role, err := r.getCurrentWorkspaceRole(ctx, workspaceID)
+	if err != nil {
+		return nil, AuthorizationError
+	}
+	managed, err := r.getWorkspaceRole(ctx, adminID, workspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not in workspace")
+	}
+	if !role.CanManage(managed) {
+		return nil, AuthorizationError
+	}
+	return role, nil
+}
+
+// authorizeProjectRequest parses the project_id url param and checks that the
+// current admin has access to the project, and that their role in its workspace
+// has the permission, writing an error response if not.
+func (r *Resolver) authorizeProjectRequest(w http.ResponseWriter, req *http.Request, permission rbac.Permission) (*model.Project, bool) {
+	ctx := req.Context()
+	projectID, err := strconv.Atoi(chi.URLParam(req, projectIdUrlParam))
+	if err != nil {
+		http.Error(w, "invalid project_id", http.StatusBadRequest)
+		return nil, false
+	}
+
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusForbidden)
+		return nil, false
+	}
+	if err := r.authorizeWorkspace(ctx, project.WorkspaceID, permission); err != nil {
+		log.WithContext(ctx).Error(e.Wrapf(err, "%s %s requires the %s permission", req.Method, req.URL.Path, permission))
+		http.Error(w, "", http.StatusForbidden)
+		return nil, false
+	}
+	return project, true
+}
+
+func writeJSONResponse(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.WithContext(req.Context()).WithError(err).Error("failed to write json response")
+	}
+}
+
+// mutationWorkspaceIDArguments are the arguments of the workspace of the mutations that do not take
+// a workspace_id.
+var mutationWorkspaceIDArguments = map[string]string{
+	"editWorkspace": "id",
+}
+
+// mutationProjectIDArguments are the arguments of the project of the mutations that do not take a
+// project_id.
+var mutationProjectIDArguments = map[string]string{
+	"editProject":         "id",
+	"deleteProject":       "id",
+	"editProjectSettings": "projectId",
+}
+
+// mutationObject is the object that a mutation changes, found by the condition on its argument.
+type mutationObject struct {
+	argument string
+	model    interface{}
+	where    string
+}
+
+// mutationObjectArguments are the objects of the mutations that take neither a workspace nor a
+// project, whose project is the project of the object.
+var mutationObjectArguments = map[string]mutationObject{
+	"updateErrorGroupState":    {argument: "secure_id", model: &model.ErrorGroup{}, where: "secure_id = ?"},
+	"updateErrorGroupAssignee": {argument: "secure_id", model: &model.ErrorGroup{}, where: "secure_id = ?"},
+	"updateErrorGroupIsPublic": {argument: "error_group_secure_id", model: &model.ErrorGroup{}, where: "secure_id = ?"},
+	"updateSessionIsPublic":    {argument: "session_secure_id", model: &model.Session{}, where: "secure_id = ?"},
+	"deleteSessionComment":     {argument: "id", model: &model.SessionComment{}, where: "id = ?"},
+	"replyToSessionComment":    {argument: "comment_id", model: &model.SessionComment{}, where: "id = ?"},
+	"deleteErrorComment":       {argument: "id", model: &model.ErrorComment{}, where: "id = ?"},
+	"replyToErrorComment":      {argument: "comment_id", model: &model.ErrorComment{}, where: "id = ?"},
+	"removeErrorIssue":         {argument: "error_issue_id", model: &model.ErrorComment{}, where: "id = (SELECT error_comment_id FROM external_attachments WHERE id = ?)"},
+	"updateSessionAlert":       {argument: "id", model: &model.SessionAlert{}, where: "id = ?"},
+	"updateLogAlert":           {argument: "id", model: &model.LogAlert{}, where: "id = ?"},
+	"deleteSegment":            {argument: "segment_id", model: &model.Segment{}, where: "id = ?"},
+	"deleteErrorSegment":       {argument: "segment_id", model: &model.ErrorSegment{}, where: "id = ?"},
+	"deleteSavedSegment":       {argument: "segment_id", model: &model.SavedSegment{}, where: "id = ?"},
+	"deleteDashboard":          {argument: "id", model: &model.Dashboard{}, where: "id = ?"},
+	"testErrorEnhancement":     {argument: "error_object_id", model: &model.ErrorObject{}, where: "id = ?"},
+}
+
+func intArgument(args map[string]interface{}, name string) (int, bool) {
+	switch value := args[name].(type) {
+	case int:
+		return value, true
+	case *int:
+		if value != nil {
+			return *value, true
+		}
+	case string:
+		if id, err := strconv.Atoi(value); err == nil {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// inputProjectID returns the project_id of the input argument of a mutation.
+func inputProjectID(args map[string]interface{}) (int, bool) {
+	switch input := args["input"].(type) {
+	case modelInputs.SessionAlertInput:
+		return input.ProjectID, true
+	case *modelInputs.SessionAlertInput:
+		if input != nil {
+			return input.ProjectID, true
+		}
+	case modelInputs.LogAlertInput:
+		return input.ProjectID, true
+	case *modelInputs.LogAlertInput:
+		if input != nil {
+			return input.ProjectID, true
+		}
+	}
+	return 0, false
+}
+
+// mutationProjectID returns the project of a mutation from the object it changes, or from its
+// project argument.
+func (r *Resolver) mutationProjectID(ctx context.Context, mutation string, args map[string]interface{}) (int, bool) {
+	if object, ok := mutationObjectArguments[mutation]; ok {
+		value, ok := args[object.argument]
+		if !ok {
+			return 0, false
+		}
+		var projectID int
+		if err := r.DB.WithContext(ctx).Model(object.model).Select("project_id").Where(object.where, value).Take(&projectID).Error; err != nil {
+			return 0, false
+		}
+		return projectID, true
+	}
+	projectIDArgument, ok := mutationProjectIDArguments[mutation]
+	if !ok {
+		projectIDArgument = "project_id"
+	}
+	if projectID, ok := intArgument(args, projectIDArgument); ok {
+		return projectID, true
+	}
+	return inputProjectID(args)
+}
+
+// mutationWorkspaceID returns the workspace of a mutation from its workspace argument, or from the
+// workspace of its project.
+func (r *Resolver) mutationWorkspaceID(ctx context.Context, mutation string, args map[string]interface{}) (int, bool) {
+	workspaceIDArgument, ok := mutationWorkspaceIDArguments[mutation]
+	if !ok {
+		workspaceIDArgument = "workspace_id"
+	}
+	if workspaceID, ok := intArgument(args, workspaceIDArgument); ok {
+		return workspaceID, true
+	}
+	projectID, ok := r.mutationProjectID(ctx, mutation, args)
+	if !ok {
+		return 0, false
+	}
+	project, err := r.Store.GetProject(ctx, projectID)
+	if err != nil {
+		return 0, false
+	}
+	return project.WorkspaceID, true
+}
+
+type RBACValidator interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+}
+
+type rbacValidator struct {
+	resolver *Resolver
+}
+
+// NewGraphqlRBACValidator checks that the role of the admin in the workspace of a mutation has the
+// permission of the mutation. Mutations whose workspace cannot be found are rejected, unless they
+// are run by a whitelisted account.
+func NewGraphqlRBACValidator(resolver *Resolver) RBACValidator {
+	return rbacValidator{resolver: resolver}
+}
+
+func (v rbacValidator) ExtensionName() string {
+	return "HighlightRBACValidator"
+}
+
+func (v rbacValidator) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (v rbacValidator) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" {
+		return next(ctx)
+	}
+	permission, ok := rbac.MutationPermission(fc.Field.Name)
+	if !ok {
+		return next(ctx)
+	}
+	workspaceID, ok := v.resolver.mutationWorkspaceID(ctx, fc.Field.Name, fc.Args)
+	if !ok {
+		// mutations without a workspace, like those of the error tags, are internal
+		if v.resolver.isWhitelistedAccount(ctx) {
+			return next(ctx)
+		}
+		return nil, e.Wrapf(AuthorizationError, "the workspace of %s could not be found", fc.Field.Name)
+	}
+	role, err := v.resolver.getCurrentWorkspaceRole(ctx, workspaceID)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrapf(err, "error querying the workspace role for %s", fc.Field.Name))
+		return nil, e.Wrapf(AuthorizationError, "%s requires the %s permission", fc.Field.Name, permission)
+	}
+	if !role.Can(permission) {
+		return nil, e.Wrapf(AuthorizationError, "%s requires the %s permission", fc.Field.Name, permission)
+	}
+	return next(ctx)
+}
+
+func roleOutput(role *rbac.Role, id *int) *modelInputs.Role {
+	return &modelInputs.Role{
+		ID:          id,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissionStrings(role.Permissions),
+		BuiltIn:     role.BuiltIn,
+	}
+}
+
+func workspaceRoleOutput(role *model.WorkspaceRole) *modelInputs.Role {
+	return roleOutput(customRole(role), &role.ID)
+}
+
+// newWorkspaceRole validates a custom role, which cannot have permissions that the current admin
+// does not have.
+func (r *Resolver) newWorkspaceRole(ctx context.Context, workspaceID int, name string, input modelInputs.WorkspaceRoleInput) (*rbac.Role, error) {
+	role, err := rbac.NewCustomRole(name, ptr.ToString(input.Description), input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	currentRole, err := r.getCurrentWorkspaceRole(ctx, workspaceID)
+	if err != nil || !currentRole.CanGrant(role) {
+		return nil, e.Wrap(AuthorizationError, "roles cannot have permissions that you do not have")
+	}
+	return role, nil
+}
+
+func permissionStrings(permissions []rbac.Permission) []string {
+	strs := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		strs = append(strs, string(permission))
+	}
+	return strs
+}
+
+func (r *Resolver) getWorkspaceCustomRole(ctx context.Context, workspaceID int, id int) (*model.WorkspaceRole, error) {
+	var role model.WorkspaceRole
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceRole{Model: model.Model{ID: id}, WorkspaceID: workspaceID}).Take(&role).Error; err != nil {
+		return nil, e.Wrap(err, "error querying workspace role")
+	}
+	return &role, nil
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/chaos"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+// chaosFaultsOutput converts the faults injected on instances running with chaos enabled.
+func chaosFaultsOutput(faults []*chaos.Fault) []*modelInputs.ChaosFault {
+	return lo.Map(faults, func(f *chaos.Fault, _ int) *modelInputs.ChaosFault {
+		return &modelInputs.ChaosFault{
+			Subsystem:      string(f.Subsystem),
+			ErrorPercent:   f.ErrorPercent,
+			LatencyPercent: f.LatencyPercent,
+			LatencyMs:      f.LatencyMs,
+			Hosts:          append([]string{}, f.Hosts...),
+			ExpiresAt:      f.ExpiresAt,
+		}
+	})
+}
+
+// newChaosFaults validates the faults that replace the injected ones.
+func newChaosFaults(input []*modelInputs.ChaosFaultInput) ([]*chaos.Fault, error) {
+	faults := make([]*chaos.Fault, 0, len(input))
+	for _, f := range input {
+		fault := &chaos.Fault{
+			Subsystem:      chaos.Subsystem(f.Subsystem),
+			ErrorPercent:   f.ErrorPercent,
+			LatencyPercent: f.LatencyPercent,
+			LatencyMs:      f.LatencyMs,
+			Hosts:          f.Hosts,
+			ExpiresAt:      f.ExpiresAt,
+		}
+		if err := fault.Validate(); err != nil {
+			return nil, err
+		}
+		faults = append(faults, fault)
+	}
+	return faults, nil
+}
@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/clickup"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+// clickUpProjectClient returns the ClickUp client of the workspace of the project.
+func (r *Resolver) clickUpProjectClient(ctx context.Context, project *model.Project) (*clickup.Client, error) {
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return r.ClickUpClient(ctx, workspace)
+}
+
+// clickUpCustomFields returns the custom fields of a ClickUp list, so that the values of its
+// required fields can be set on new tasks.
+func clickUpCustomFields(fields []*clickup.CustomField) []*modelInputs.ClickUpCustomField {
+	return lo.Map(fields, func(field *clickup.CustomField, _ int) *modelInputs.ClickUpCustomField {
+		return &modelInputs.ClickUpCustomField{
+			ID:       field.ID,
+			Name:     field.Name,
+			Type:     field.Type,
+			Required: field.Required,
+			Options: lo.Map(field.TypeConfig.Options, func(option *clickup.CustomFieldOption, _ int) *modelInputs.ClickUpCustomFieldOption {
+				return &modelInputs.ClickUpCustomFieldOption{
+					ID:    option.ID,
+					Name:  lo.EmptyableToPtr(option.Name),
+					Label: lo.EmptyableToPtr(option.Label),
+					Color: lo.EmptyableToPtr(option.Color),
+				}
+			}),
+		}
+	})
+}
+
+// clickUpIntegrationStatus returns the status of the ClickUp integration of a workspace, so that
+// the settings page can ask for it to be reconnected when it is broken.
+func clickUpIntegrationStatus(status *clickup.IntegrationStatus) *modelInputs.ClickUpIntegrationStatus {
+	result := &modelInputs.ClickUpIntegrationStatus{
+		Connected:         status.Connected,
+		ReconnectRequired: status.ReconnectRequired,
+		Teams:             status.Teams,
+	}
+	if status.User != nil {
+		result.User = &modelInputs.ClickUpUser{
+			ID:       status.User.ID,
+			Username: status.User.Username,
+			Email:    status.User.Email,
+		}
+	}
+	if status.Error != "" {
+		result.Error = &status.Error
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// newDigestSetting validates the digest schedule of a project. The digests are then sent by the
+// digest worker, starting with the next scheduled one.
+func newDigestSetting(projectID int, input modelInputs.ProjectDigestSettingInput) (*model.ProjectDigestSetting, error) {
+	if !lo.Contains([]model.DigestFrequency{model.DigestFrequencyDaily, model.DigestFrequencyWeekly, model.DigestFrequencyNever}, input.Frequency) {
+		return nil, e.Errorf("invalid frequency %s", input.Frequency)
+	}
+	weekday := time.Weekday(input.Weekday)
+	if weekday < time.Sunday || weekday > time.Saturday {
+		return nil, e.New("weekday must be between 0 and 6")
+	}
+	if input.Hour < 0 || input.Hour > 23 {
+		return nil, e.New("hour must be between 0 and 23")
+	}
+	return &model.ProjectDigestSetting{
+		ProjectID:  projectID,
+		Frequency:  input.Frequency,
+		Weekday:    weekday,
+		Hour:       input.Hour,
+		LastSentAt: lo.ToPtr(time.Now()),
+	}, nil
+}
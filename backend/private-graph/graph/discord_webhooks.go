@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// getDiscordWebhooks validates the Discord webhooks that an alert or the weekly digest of a project
+// is posted to, which are set alongside the channels of the Discord bot.
+func getDiscordWebhooks(input []*modelInputs.DiscordWebhookInput) (model.DiscordWebhooks, error) {
+	webhooks := model.DiscordWebhooks{}
+	for _, webhook := range input {
+		name := strings.TrimSpace(webhook.Name)
+		if name == "" {
+			return nil, e.New("webhook name is required")
+		}
+		if err := discord.ValidateWebhookURL(webhook.WebhookURL); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &model.DiscordWebhook{Name: name, WebhookURL: webhook.WebhookURL})
+	}
+	return webhooks, nil
+}
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+)
+
+// applyErrorAlertAnomalyDetection switches an error alert between alerting on its count threshold
+// and alerting on spikes above the baselines of error groups. The sensitivity is the number of
+// standard deviations above the baseline of an error group that alerts.
+func applyErrorAlertAnomalyDetection(anomalyDetection *bool, anomalySensitivity *float64, errorAlert *model.ErrorAlert) error {
+	if anomalyDetection != nil {
+		errorAlert.AnomalyDetection = *anomalyDetection
+	}
+	if anomalySensitivity != nil {
+		errorAlert.AnomalySensitivity = *anomalySensitivity
+	}
+	if errorAlert.AnomalySensitivity == 0 {
+		errorAlert.AnomalySensitivity = alerts.DefaultAnomalySensitivity
+	}
+	if errorAlert.AnomalySensitivity < 1 || errorAlert.AnomalySensitivity > 10 {
+		return e.New("sensitivity must be between 1 and 10")
+	}
+	return nil
+}
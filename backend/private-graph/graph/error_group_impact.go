@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/restapi"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+func newErrorGroupImpact(impact *clickhouse.ErrorGroupImpact) *model.ErrorGroupImpact {
+	return &model.ErrorGroupImpact{
+		Occurrences:      int64(impact.Occurrences),
+		AffectedUsers:    int64(impact.AffectedUsers),
+		AffectedSessions: int64(impact.AffectedSessions),
+		ImpactScore:      impact.ImpactScore,
+	}
+}
+
+// getErrorGroupsByImpact returns the page of the error groups that match the query, ranked by the
+// users and sessions that their errors affected in the time range of the query.
+func (r *Resolver) getErrorGroupsByImpact(ctx context.Context, project *model.Project, query modelInputs.ClickhouseQuery, page *int, count int) (*model.ErrorGroupImpactResults, error) {
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying workspace")
+	}
+	impacts, total, err := r.ClickhouseClient.QueryErrorGroupImpacts(ctx, project.ID, count, query, page, GetRetentionDate(workspace.RetentionPeriod))
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group impacts")
+	}
+
+	var results []*model.ErrorGroup
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorGroup{}).
+		Where("id IN ?", lo.Map(impacts, func(impact *clickhouse.ErrorGroupImpact, _ int) int { return impact.ErrorGroupID })).
+		Where("project_id = ?", project.ID).
+		Find(&results).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error groups")
+	}
+	errorGroupsById := lo.KeyBy(results, func(errorGroup *model.ErrorGroup) int { return errorGroup.ID })
+
+	errorGroups := []*model.ErrorGroupWithImpact{}
+	for _, impact := range impacts {
+		if errorGroup := errorGroupsById[impact.ErrorGroupID]; errorGroup != nil {
+			errorGroups = append(errorGroups, &model.ErrorGroupWithImpact{ErrorGroup: errorGroup, Impact: newErrorGroupImpact(impact)})
+		}
+	}
+	return &model.ErrorGroupImpactResults{ErrorGroups: errorGroups, TotalCount: total}, nil
+}
+
+// validateErrorGroupTrendsParams checks that the time range of the trend of an error group is not
+// split into more than the maximum number of buckets.
+func validateErrorGroupTrendsParams(params modelInputs.ErrorGroupFrequenciesParamsInput) error {
+	if params.DateRange == nil {
+		return e.New("the trend of an error group needs a date range")
+	}
+	if params.ResolutionMinutes <= 0 {
+		return e.Errorf("invalid resolution_minutes %d", params.ResolutionMinutes)
+	}
+	if params.DateRange.EndDate.Sub(params.DateRange.StartDate) > time.Duration(params.ResolutionMinutes*restapi.MaxTrendBuckets)*time.Minute {
+		return e.Errorf("the time range has more than %d buckets of resolution_minutes", restapi.MaxTrendBuckets)
+	}
+	return nil
+}
+
+// getErrorGroupTrends returns the impact of an error group over the time range, and in buckets of
+// the resolution.
+func (r *Resolver) getErrorGroupTrends(ctx context.Context, errorGroup *model.ErrorGroup, params modelInputs.ErrorGroupFrequenciesParamsInput) (*model.ErrorGroupTrends, error) {
+	if err := validateErrorGroupTrendsParams(params); err != nil {
+		return nil, err
+	}
+	buckets, err := r.ClickhouseClient.QueryErrorGroupTrends(ctx, errorGroup.ProjectID, []int{errorGroup.ID}, params)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group trends")
+	}
+	impacts, err := r.ClickhouseClient.QueryErrorGroupImpact(ctx, errorGroup.ProjectID, []int{errorGroup.ID}, *params.DateRange)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group impact")
+	}
+
+	trends := &model.ErrorGroupTrends{
+		// error groups without errors in the time range have no impact
+		Impact: &model.ErrorGroupImpact{},
+		Buckets: lo.Map(buckets, func(bucket *clickhouse.ErrorGroupTrendBucket, _ int) *model.ErrorGroupTrendBucket {
+			return &model.ErrorGroupTrendBucket{
+				Date:             bucket.Date,
+				Occurrences:      int64(bucket.Occurrences),
+				AffectedUsers:    int64(bucket.AffectedUsers),
+				AffectedSessions: int64(bucket.AffectedSessions),
+			}
+		}),
+	}
+	if len(impacts) > 0 {
+		trends.Impact = newErrorGroupImpact(impacts[0])
+	}
+	return trends, nil
+}
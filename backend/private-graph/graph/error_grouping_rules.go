@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+func applyErrorGroupingRuleInput(input modelInputs.ErrorGroupingRuleInput, rule *model.ErrorGroupingRule) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+
+	rule.Name = input.Name
+	rule.Type = model.ErrorGroupingRuleType(input.Type)
+	rule.EventRegex = input.EventRegex
+	rule.ServiceName = input.ServiceName
+	rule.Disabled = input.Disabled != nil && *input.Disabled
+	return rule.Validate()
+}
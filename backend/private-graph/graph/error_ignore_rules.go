@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+func applyErrorIgnoreRuleInput(input modelInputs.ErrorIgnoreRuleInput, rule *model.ErrorIgnoreRule) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+
+	rule.Name = input.Name
+	rule.TypeRegex = input.TypeRegex
+	rule.MessageRegex = input.MessageRegex
+	rule.BrowserRegex = input.BrowserRegex
+	rule.URLRegex = input.URLRegex
+	rule.SourceRegex = input.SourceRegex
+	rule.Disabled = input.Disabled != nil && *input.Disabled
+	return rule.Validate()
+}
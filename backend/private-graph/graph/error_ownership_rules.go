@@ -0,0 +1,30 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+func applyErrorOwnershipRuleInput(input modelInputs.ErrorOwnershipRuleInput, rule *model.ErrorOwnershipRule) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+
+	rule.Name = input.Name
+	rule.Pattern = input.Pattern
+	rule.ServiceName = input.ServiceName
+	rule.AssigneeID = input.AssigneeID
+	rule.Disabled = input.Disabled != nil && *input.Disabled
+	return rule.Validate()
+}
+
+// validateAssignee checks that an admin can be assigned the error groups of a project.
+func (r *Resolver) validateAssignee(ctx context.Context, project *model.Project, assigneeID int) error {
+	if _, err := r.GetAdminRole(ctx, assigneeID, project.WorkspaceID); err != nil {
+		return e.New("assignee_id is not a member of the workspace")
+	}
+	return nil
+}
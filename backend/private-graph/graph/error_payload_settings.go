@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// errorPayloadSettings control how many of the payloads of a project's errors are stored, which
+// are the marshalled attributes of the spans of otel errors. Once an error group has more errors in
+// an hour than the hourly limit, only the sampling rate of their payloads is stored, so a rate of 0
+// keeps only the aggregates of the group. Payloads are deleted after the retention days.
+func errorPayloadSettings(settings *model.ProjectFilterSettings) *modelInputs.ErrorPayloadSettings {
+	return &modelInputs.ErrorPayloadSettings{
+		HourlyLimit:   settings.ErrorPayloadHourlyLimit,
+		SamplingRate:  settings.ErrorPayloadSamplingRate,
+		RetentionDays: settings.ErrorPayloadRetentionDays,
+	}
+}
+
+func validateErrorPayloadSettingsInput(input *modelInputs.ErrorPayloadSettingsInput) error {
+	if input.HourlyLimit != nil && *input.HourlyLimit < 0 {
+		return e.New("hourly_limit must not be negative")
+	}
+	if input.SamplingRate < 0 || input.SamplingRate > 1 {
+		return e.New("sampling_rate must be between 0 and 1")
+	}
+	if input.RetentionDays != nil && *input.RetentionDays <= 0 {
+		return e.New("retention_days must be positive")
+	}
+	return nil
+}
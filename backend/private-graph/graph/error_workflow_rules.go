@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+func applyErrorWorkflowRuleInput(input modelInputs.ErrorWorkflowRuleInput, rule *model.ErrorWorkflowRule) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+
+	rule.Name = input.Name
+	rule.Action = model.ErrorWorkflowRuleAction(input.Action)
+	rule.EventRegex = input.EventRegex
+	rule.StaleDays = input.StaleDays
+	rule.SnoozeMinutes = input.SnoozeMinutes
+	rule.Disabled = input.Disabled != nil && *input.Disabled
+	return rule.Validate()
+}
+
+// newErrorWorkflowRuleActivity returns the audit trail of error group state changes made by rules.
+func newErrorWorkflowRuleActivity(logs []*model.ErrorGroupActivityLog) []*modelInputs.ErrorWorkflowRuleActivity {
+	activity := make([]*modelInputs.ErrorWorkflowRuleActivity, 0, len(logs))
+	for _, activityLog := range logs {
+		activity = append(activity, &modelInputs.ErrorWorkflowRuleActivity{
+			ID:           activityLog.ID,
+			CreatedAt:    activityLog.CreatedAt,
+			ErrorGroupID: activityLog.ErrorGroupID,
+			EventType:    string(activityLog.EventType),
+			EventData:    activityLog.EventData,
+		})
+	}
+	return activity
+}
@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// escalationStepMaxDelayMinutes is the longest delay of an escalation step, a day.
+const escalationStepMaxDelayMinutes = 24 * 60
+
+var escalationStepTypes = []model.EscalationStepType{
+	model.EscalationStepSlack,
+	model.EscalationStepEmail,
+	model.EscalationStepPagerDuty,
+}
+
+func validateEmails(emails []string) ([]string, error) {
+	var result []string
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if !strings.Contains(email, "@") {
+			return nil, e.Errorf("invalid email %s", email)
+		}
+		result = append(result, email)
+	}
+	return result, nil
+}
+
+// applyEscalationPolicyInput sets the name and the steps of an escalation policy, validating the
+// destinations of each step.
+func applyEscalationPolicyInput(input modelInputs.EscalationPolicyInput, policy *model.EscalationPolicy) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+	if len(input.Steps) == 0 {
+		return e.New("an escalation policy needs at least one step")
+	}
+	var steps model.EscalationSteps
+	for _, stepInput := range input.Steps {
+		if !lo.Contains(escalationStepTypes, stepInput.Type) {
+			return e.Errorf("invalid step type %s", stepInput.Type)
+		}
+		if stepInput.DelayMinutes < 0 || stepInput.DelayMinutes > escalationStepMaxDelayMinutes {
+			return e.Errorf("delay_minutes must be between 0 and %d", escalationStepMaxDelayMinutes)
+		}
+		step := &model.EscalationStep{
+			Type:             stepInput.Type,
+			DelayMinutes:     stepInput.DelayMinutes,
+			OnCallScheduleID: stepInput.OnCallScheduleID,
+		}
+		switch step.Type {
+		case model.EscalationStepSlack:
+			if len(stepInput.SlackChannels) == 0 {
+				return e.New("a slack step needs at least one channel")
+			}
+			step.SlackChannels = lo.Map(stepInput.SlackChannels, func(ch *modelInputs.SanitizedSlackChannelInput, _ int) *modelInputs.SanitizedSlackChannel {
+				return &modelInputs.SanitizedSlackChannel{WebhookChannel: ch.WebhookChannelName, WebhookChannelID: ch.WebhookChannelID}
+			})
+		case model.EscalationStepEmail:
+			emails, err := validateEmails(stepInput.Emails)
+			if err != nil {
+				return err
+			}
+			step.Emails = emails
+			if len(step.Emails) == 0 && step.OnCallScheduleID == nil {
+				return e.New("an email step needs emails or an on-call schedule")
+			}
+		case model.EscalationStepPagerDuty:
+			if stepInput.PagerDuty == nil || strings.TrimSpace(stepInput.PagerDuty.RoutingKey) == "" {
+				return e.New("PagerDuty routing key is required")
+			}
+			severity := lo.FromPtr(stepInput.PagerDuty.Severity)
+			if err := validateSeverity(&severity); err != nil {
+				return err
+			}
+			step.PagerDuty = &model.PagerDutyDestination{
+				RoutingKey: strings.TrimSpace(stepInput.PagerDuty.RoutingKey),
+				Severity:   severity,
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	policy.Name = input.Name
+	policy.Steps = steps
+	return nil
+}
+
+// validateEscalationSchedules checks that the on-call schedules of the steps of a policy belong to
+// the project.
+func (r *Resolver) validateEscalationSchedules(ctx context.Context, projectID int, steps []*model.EscalationStep) error {
+	for _, step := range steps {
+		if step.OnCallScheduleID == nil {
+			continue
+		}
+		if _, err := r.Store.GetOnCallSchedule(ctx, projectID, *step.OnCallScheduleID); err != nil {
+			return e.Errorf("invalid on_call_schedule_id %d", *step.OnCallScheduleID)
+		}
+	}
+	return nil
+}
+
+// applyOnCallScheduleInput sets the participants and the rotation of an on-call schedule, which
+// rotates weekly from the current hour by default.
+func applyOnCallScheduleInput(input modelInputs.OnCallScheduleInput, schedule *model.OnCallSchedule) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+	participants, err := validateEmails(input.Participants)
+	if err != nil {
+		return err
+	}
+	if len(participants) == 0 {
+		return e.New("an on-call schedule needs at least one participant")
+	}
+	rotationHours := lo.FromPtr(input.RotationHours)
+	if rotationHours <= 0 {
+		rotationHours = 7 * 24
+	}
+	startsAt := lo.FromPtr(input.StartsAt)
+	if startsAt.IsZero() {
+		startsAt = time.Now().Truncate(time.Hour)
+	}
+
+	schedule.Name = input.Name
+	schedule.Participants = participants
+	schedule.RotationHours = rotationHours
+	schedule.StartsAt = startsAt
+	return nil
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/store"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// claimErrorIssue reserves the tracker of the attachment before an issue is created for the error group.
+// If the error group already has an issue in the tracker, the attachment references that issue instead
+// and false is returned.
+func (r *Resolver) claimErrorIssue(ctx context.Context, projectID int, errorGroupID int, adminID *int, attachment *model.ExternalAttachment) (*model.ExternalIssue, bool, error) {
+	issue, claimed, err := r.Store.ClaimExternalIssue(ctx, store.ClaimExternalIssueParams{
+		ProjectID:       projectID,
+		ErrorGroupID:    errorGroupID,
+		IntegrationType: attachment.IntegrationType,
+		AdminID:         adminID,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return issue, true, nil
+	}
+
+	attachment.ExternalID = issue.ExternalID
+	attachment.Title = issue.Title
+	if err := r.DB.WithContext(ctx).Create(attachment).Error; err != nil {
+		return nil, false, e.Wrap(err, "error creating external attachment")
+	}
+	return issue, false, nil
+}
+
+// completeErrorIssue records the issue created for a claim, or releases the claim if the
+// issue could not be created.
+func (r *Resolver) completeErrorIssue(ctx context.Context, issue *model.ExternalIssue, attachment *model.ExternalAttachment) {
+	var err error
+	if attachment.ExternalID == "" {
+		err = r.Store.ReleaseExternalIssue(ctx, issue.ID)
+	} else {
+		err = r.Store.CompleteExternalIssue(ctx, issue.ID, attachment)
+	}
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("external_issue_id", issue.ID).Error("failed to update external issue")
+	}
+}
+
+func (r *Resolver) getProjectErrorGroup(ctx context.Context, projectID int, secureID string) (*model.ErrorGroup, error) {
+	var errorGroup model.ErrorGroup
+	if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{SecureID: secureID, ProjectID: projectID}).Take(&errorGroup).Error; err != nil {
+		return nil, err
+	}
+	return &errorGroup, nil
+}
@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// applyHeartbeatMonitorInput sets the expected check-in interval and the notifications of a
+// heartbeat monitor.
+func applyHeartbeatMonitorInput(input modelInputs.HeartbeatMonitorInput, monitor *model.HeartbeatMonitor) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+	if input.IntervalSeconds <= 0 {
+		return e.New("interval_seconds must be positive")
+	}
+	graceSeconds := 0
+	if input.GraceSeconds != nil {
+		graceSeconds = *input.GraceSeconds
+	}
+	if graceSeconds < 0 {
+		return e.New("grace_seconds must not be negative")
+	}
+	disabled := input.Disabled != nil && *input.Disabled
+
+	monitor.Name = input.Name
+	monitor.IntervalSeconds = input.IntervalSeconds
+	monitor.GraceSeconds = graceSeconds
+	monitor.ChannelsToNotify = input.ChannelsToNotify
+	monitor.EmailsToNotify = input.EmailsToNotify
+	monitor.Disabled = &disabled
+	return nil
+}
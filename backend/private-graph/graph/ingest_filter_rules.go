@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/severity"
+	e "github.com/pkg/errors"
+)
+
+// applyIngestFilterRuleInput sets the conditions and action of an ingest filter rule, normalizing
+// its severity to the log level names stored on logs.
+func applyIngestFilterRuleInput(input modelInputs.IngestFilterRuleInput, rule *model.IngestFilterRule) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+
+	rule.Name = input.Name
+	rule.Action = input.Action
+	rule.ServiceName = input.ServiceName
+	rule.Severity = nil
+	if input.Severity != nil && *input.Severity != "" {
+		level, ok := severity.ParseText(*input.Severity)
+		if !ok {
+			return e.Errorf("invalid severity %s", *input.Severity)
+		}
+		value := level.String()
+		rule.Severity = &value
+	}
+	rule.AttributeKey = input.AttributeKey
+	rule.AttributeRegex = input.AttributeRegex
+	rule.Disabled = input.Disabled
+	return rule.Validate()
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/severity"
+	e "github.com/pkg/errors"
+)
+
+const maxSampledOutDays = 90
+
+// parseExcludedLogLevels normalizes the log levels whose otel logs are dropped at ingest.
+func parseExcludedLogLevels(levels []string) ([]string, error) {
+	logLevels := []string{}
+	for _, level := range levels {
+		logLevel, ok := severity.ParseText(level)
+		if !ok {
+			return nil, e.Errorf("invalid log level %s", level)
+		}
+		logLevels = append(logLevels, logLevel.String())
+	}
+	return logLevels, nil
+}
+
+// getSampledOutCounts returns the daily counts of a project's items dropped at ingest over the last `days`.
+func (r *Resolver) getSampledOutCounts(ctx context.Context, projectID int, days int) ([]*modelInputs.SampledOutCount, error) {
+	results := []*modelInputs.SampledOutCount{}
+	now := time.Now()
+	for i := 0; i < days; i++ {
+		date := now.AddDate(0, 0, -i)
+		counts, err := r.Redis.GetSampledOut(ctx, projectID, date)
+		if err != nil {
+			return nil, e.Wrap(err, "error querying sampled out counts")
+		}
+		for field, count := range counts {
+			product, reason, _ := strings.Cut(field, ":")
+			results = append(results, &modelInputs.SampledOutCount{
+				Date:    date.UTC().Format("2006-01-02"),
+				Product: modelInputs.ProductType(product),
+				Reason:  modelInputs.IngestReason(reason),
+				Count:   count,
+			})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/issuetracker"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// projectIssueTracker returns the issue tracker of the integration type for the workspace of the project.
+func (r *Resolver) projectIssueTracker(ctx context.Context, project *model.Project, integrationType modelInputs.IntegrationType) (issuetracker.IssueTracker, error) {
+	if !issuetracker.Supported(integrationType) {
+		return nil, e.New("integration_type is not an issue tracker")
+	}
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return r.IssueTracker(ctx, workspace, integrationType)
+}
@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// jiraProjectAccessToken returns the workspace of the project along with its Jira access token.
+func (r *Resolver) jiraProjectAccessToken(ctx context.Context, project *model.Project) (*model.Workspace, string, error) {
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, "", err
+	}
+	accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeJira)
+	if err != nil || accessToken == nil || workspace.JiraCloudID == nil {
+		return nil, "", e.New("workspace does not have a Jira integration")
+	}
+	return workspace, *accessToken, nil
+}
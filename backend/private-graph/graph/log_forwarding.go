@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/datadog"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// applyDatadogLogForwarderInput configures the forwarding of a project's logs to Datadog, returning
+// whether the api key of the forwarder must be validated with its site because either has changed.
+// Logs are forwarded from the time the forwarder is enabled.
+func applyDatadogLogForwarderInput(input modelInputs.DatadogLogForwarderInput, forwarder *model.DatadogLogForwarder) (bool, error) {
+	site := string(datadog.DefaultSite)
+	if input.Site != nil && *input.Site != "" {
+		site = *input.Site
+	}
+	if !datadog.Site(site).IsValid() {
+		return false, e.Errorf("invalid Datadog site %s", site)
+	}
+
+	attributeMapping := model.StringMap{}
+	for key, value := range input.AttributeMapping {
+		str, ok := value.(string)
+		if !ok {
+			return false, e.Errorf("the Datadog attribute of %s must be a string", key)
+		}
+		attributeMapping[key] = str
+	}
+
+	if input.APIKey != nil {
+		forwarder.APIKey = strings.TrimSpace(*input.APIKey)
+	}
+	if forwarder.APIKey == "" {
+		return false, e.New("Datadog api key is required")
+	}
+	validateAPIKey := input.APIKey != nil || forwarder.Site != site
+
+	// a forwarder that was disabled starts forwarding from when it is enabled again
+	if input.Enabled && !forwarder.Enabled {
+		forwarder.ForwardedUntil = nil
+	}
+	forwarder.Site = site
+	forwarder.AttributeMapping = attributeMapping
+	forwarder.Enabled = input.Enabled
+	return validateAPIKey, nil
+}
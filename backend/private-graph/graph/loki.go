@@ -0,0 +1,335 @@
+package graph
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/logql"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// The handlers in this file implement the subset of the Loki HTTP API that the Grafana Loki data
+// source uses to explore and chart logs, so that a project's logs can be queried with LogQL from
+// existing Grafana dashboards. See https://grafana.com/docs/loki/latest/reference/loki-http-api/
+const (
+	lokiDefaultLookback = time.Hour
+	lokiDefaultLimit    = 100
+	lokiMaxLimit        = 5000
+	// like loki, the default step of a range query splits it in about this many points
+	lokiDefaultPoints = 250
+	lokiMaxPoints     = 11000
+)
+
+type lokiResponse struct {
+	Status string `json:"status"`
+	Data   any    `json:"data"`
+}
+
+type lokiQueryData struct {
+	ResultType string `json:"resultType"`
+	Result     any    `json:"result"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	// Values are pairs of the unix nanoseconds timestamp and line of each log.
+	Values [][2]string `json:"values"`
+}
+
+type lokiMatrixSeries struct {
+	Metric map[string]string `json:"metric"`
+	// Values are pairs of the unix seconds timestamp and formatted value of each point.
+	Values [][2]any `json:"values"`
+}
+
+type lokiVectorSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]any            `json:"value"`
+}
+
+func writeLokiResponse(w http.ResponseWriter, req *http.Request, data any) {
+	writeJSONResponse(w, req, http.StatusOK, lokiResponse{Status: "success", Data: data})
+}
+
+// parseLokiTime parses a timestamp in unix nanoseconds, unix seconds with an optional fractional
+// part, or RFC3339 format. Like loki, integers of up to 10 digits are seconds.
+func parseLokiTime(value string, defaultTime time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultTime, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if len(value) <= 10 {
+			return time.Unix(n, 0), nil
+		}
+		return time.Unix(0, n), nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		whole, frac := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(frac*float64(time.Second))), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, e.Errorf("invalid timestamp %q", value)
+	}
+	return t, nil
+}
+
+// parseLokiDuration parses a duration like `30s`, or a number of seconds.
+func parseLokiDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, e.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+func parseLokiRange(req *http.Request) (time.Time, time.Time, error) {
+	now := time.Now()
+	end, err := parseLokiTime(req.URL.Query().Get("end"), now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start, err := parseLokiTime(req.URL.Query().Get("start"), end.Add(-lokiDefaultLookback))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, e.New("end timestamp must be after start timestamp")
+	}
+	return start, end, nil
+}
+
+func parseLokiLimit(req *http.Request) (int, error) {
+	value := req.URL.Query().Get("limit")
+	if value == "" {
+		return lokiDefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, e.Errorf("invalid limit %q", value)
+	}
+	if limit > lokiMaxLimit {
+		limit = lokiMaxLimit
+	}
+	return limit, nil
+}
+
+// lokiStreams groups logs by the values of their stream labels.
+func lokiStreams(rows []*clickhouse.LogRow) []*lokiStream {
+	streams := []*lokiStream{}
+	streamsByKey := map[string]*lokiStream{}
+	for _, row := range rows {
+		labels := map[string]string{}
+		for label, value := range map[modelInputs.ReservedLogKey]string{
+			modelInputs.ReservedLogKeyServiceName: row.ServiceName,
+			modelInputs.ReservedLogKeyEnvironment: row.Environment,
+			modelInputs.ReservedLogKeyLevel:       row.SeverityText,
+			modelInputs.ReservedLogKeySource:      string(row.Source),
+		} {
+			if value != "" {
+				labels[string(label)] = value
+			}
+		}
+		key := strings.Join([]string{row.ServiceName, row.Environment, row.SeverityText, string(row.Source)}, "\x00")
+		stream, ok := streamsByKey[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels, Values: [][2]string{}}
+			streamsByKey[key] = stream
+			streams = append(streams, stream)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(row.Timestamp.UnixNano(), 10), row.Body})
+	}
+	return streams
+}
+
+func formatLokiValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// LokiQueryRangeHandler runs a LogQL query over a time range, returning the matching logs as
+// streams or the series of a metric query as a matrix.
+func (r *Resolver) LokiQueryRangeHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeProjectRequest(w, req, rbac.PermissionView)
+	if !ok {
+		return
+	}
+
+	query, err := logql.Parse(req.URL.Query().Get("query"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	start, end, err := parseLokiRange(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !query.IsMetric() {
+		limit, err := parseLokiLimit(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		forward := strings.EqualFold(req.URL.Query().Get("direction"), "forward")
+		rows, err := r.ClickhouseClient.ReadLogQLLogs(ctx, project.ID, query, start, end, limit, forward)
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error reading loki logs"))
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+		writeLokiResponse(w, req, lokiQueryData{ResultType: "streams", Result: lokiStreams(rows)})
+		return
+	}
+
+	step := end.Sub(start) / lokiDefaultPoints
+	if value := req.URL.Query().Get("step"); value != "" {
+		if step, err = parseLokiDuration(value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	step = step.Truncate(time.Second)
+	if step < time.Second {
+		step = time.Second
+	}
+	if end.Sub(start)/step > lokiMaxPoints {
+		http.Error(w, "exceeded maximum resolution of 11,000 points per time series, try increasing the step", http.StatusBadRequest)
+		return
+	}
+
+	series, err := r.ClickhouseClient.ReadLogQLMetrics(ctx, project.ID, query, start, end, step)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error reading loki metrics"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	matrix := make([]*lokiMatrixSeries, 0, len(series))
+	for _, s := range series {
+		values := make([][2]any, 0, len(s.Values))
+		for _, v := range s.Values {
+			values = append(values, [2]any{v.Step.Unix(), formatLokiValue(v.Value)})
+		}
+		matrix = append(matrix, &lokiMatrixSeries{Metric: s.Labels, Values: values})
+	}
+	writeLokiResponse(w, req, lokiQueryData{ResultType: "matrix", Result: matrix})
+}
+
+// LokiQueryHandler evaluates a LogQL metric query at a single time, over the range of the query.
+func (r *Resolver) LokiQueryHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeProjectRequest(w, req, rbac.PermissionView)
+	if !ok {
+		return
+	}
+
+	query, err := logql.Parse(req.URL.Query().Get("query"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !query.IsMetric() {
+		http.Error(w, "log queries are not supported as an instant query type, please change your query to a range query type", http.StatusBadRequest)
+		return
+	}
+	at, err := parseLokiTime(req.URL.Query().Get("time"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := r.ClickhouseClient.ReadLogQLMetrics(ctx, project.ID, query, at.Add(-query.Range), at, query.Range)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error reading loki metrics"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	vector := make([]*lokiVectorSample, 0, len(series))
+	for _, s := range series {
+		// the range may span two steps of the series, which together count the logs of the range
+		var value float64
+		for _, v := range s.Values {
+			value += v.Value
+		}
+		vector = append(vector, &lokiVectorSample{Metric: s.Labels, Value: [2]any{at.Unix(), formatLokiValue(value)}})
+	}
+	writeLokiResponse(w, req, lokiQueryData{ResultType: "vector", Result: vector})
+}
+
+// LokiLabelsHandler returns the names of the labels that can be queried: the reserved log keys
+// and the most common log attributes with a valid label name.
+func (r *Resolver) LokiLabelsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeProjectRequest(w, req, rbac.PermissionView)
+	if !ok {
+		return
+	}
+	start, end, err := parseLokiRange(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	keys, err := r.ClickhouseClient.LogsKeys(ctx, project.ID, start, end, nil, nil)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying loki labels"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	labels := map[string]bool{}
+	for _, label := range clickhouse.LogQLStreamLabels {
+		labels[label] = true
+	}
+	for _, key := range modelInputs.AllReservedLogKey {
+		if key != modelInputs.ReservedLogKeyMessage {
+			labels[string(key)] = true
+		}
+	}
+	for _, key := range keys {
+		if logql.IsValidLabelName(key.Name) {
+			labels[key.Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	writeLokiResponse(w, req, names)
+}
+
+// LokiLabelValuesHandler returns the values of a label.
+func (r *Resolver) LokiLabelValuesHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeProjectRequest(w, req, rbac.PermissionView)
+	if !ok {
+		return
+	}
+	start, end, err := parseLokiRange(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	values, err := r.ClickhouseClient.LogQLLabelValues(ctx, project.ID, chi.URLParam(req, "name"), start, end)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying loki label values"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	writeLokiResponse(w, req, values)
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoft_teams"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+// getMicrosoftTeamsChannels validates the Teams channels that an alert is posted to, which are set
+// alongside the Slack and Discord channels of the alert.
+func getMicrosoftTeamsChannels(input []*modelInputs.MicrosoftTeamsChannelInput) (model.MicrosoftTeamsChannels, error) {
+	channels := model.MicrosoftTeamsChannels{}
+	for _, channel := range input {
+		name := strings.TrimSpace(channel.Name)
+		if name == "" {
+			return nil, e.New("channel name is required")
+		}
+		if err := microsoft_teams.ValidateWebhookURL(channel.WebhookURL); err != nil {
+			return nil, err
+		}
+		channels = append(channels, &model.MicrosoftTeamsChannel{Name: name, WebhookURL: channel.WebhookURL})
+	}
+	return channels, nil
+}
+
+// getAlertIntegrations returns the alert of a kind, which must belong to the project, and its integrations.
+func (r *Resolver) getAlertIntegrations(ctx context.Context, project *model.Project, alertKind modelInputs.AlertKind, alertID int) (interface{}, *model.AlertIntegrations, error) {
+	var alert interface{}
+	var alertIntegrations *model.AlertIntegrations
+	switch alertKind {
+	case modelInputs.AlertKindErrorAlert:
+		errorAlert := &model.ErrorAlert{}
+		alert, alertIntegrations = errorAlert, &errorAlert.AlertIntegrations
+	case modelInputs.AlertKindSessionAlert:
+		sessionAlert := &model.SessionAlert{}
+		alert, alertIntegrations = sessionAlert, &sessionAlert.AlertIntegrations
+	case modelInputs.AlertKindLogAlert:
+		logAlert := &model.LogAlert{}
+		alert, alertIntegrations = logAlert, &logAlert.AlertIntegrations
+	default:
+		return nil, nil, e.Errorf("invalid alert kind %s", alertKind)
+	}
+
+	if err := r.DB.WithContext(ctx).Where("id = ? AND project_id = ?", alertID, project.ID).Take(alert).Error; err != nil {
+		return nil, nil, e.Wrap(err, "error querying alert")
+	}
+	return alert, alertIntegrations, nil
+}
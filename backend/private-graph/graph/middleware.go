@@ -3,6 +3,7 @@ package graph
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/go-oauth2/oauth2/v4"
+	"github.com/highlight-run/highlight/backend/apitoken"
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/highlight-run/highlight/backend/oauth"
 	"github.com/highlight-run/highlight/backend/util"
@@ -36,6 +38,10 @@ var (
 	workspaceTokenHandler APITokenHandler
 )
 
+// errSSOAccountExists is returned instead of signing in to an account that a single sign-on login
+// did not create.
+var errSSOAccountExists = e.New("an account with this email already exists")
+
 var HighlightAdminEmailDomains = []string{"@highlight.run", "@highlight.io", "@runhighlight.com"}
 
 type AuthMode = string
@@ -59,6 +65,10 @@ func GetEnvAuthMode() AuthMode {
 type Client interface {
 	updateContextWithAuthenticatedUser(ctx context.Context, token string) (context.Context, error)
 	GetUser(ctx context.Context, uid string) (*auth.UserRecord, error)
+	// createSSOToken returns a token that the frontend signs in with as the user with the uid. Without
+	// a uid, it creates a user with the email verified by a single sign-on identity provider, and
+	// returns errSSOAccountExists when the email already has a user.
+	createSSOToken(ctx context.Context, uid *string, email string, name string) (string, string, error)
 }
 
 type SimpleAuthClient struct{}
@@ -121,6 +131,43 @@ func (c *SimpleAuthClient) GetUser(_ context.Context, _ string) (*auth.UserRecor
 	}, nil
 }
 
+func (c *SimpleAuthClient) createSSOToken(_ context.Context, _ *string, _ string, _ string) (string, string, error) {
+	return "", "", e.New("single sign-on is not supported in simple auth mode")
+}
+
+func (c *FirebaseAuthClient) createSSOToken(ctx context.Context, uid *string, email string, name string) (string, string, error) {
+	if uid == nil {
+		params := (&auth.UserToCreate{}).Email(email).EmailVerified(true)
+		if name != "" {
+			params = params.DisplayName(name)
+		}
+		user, err := c.AuthClient.CreateUser(ctx, params)
+		if auth.IsEmailAlreadyExists(err) {
+			return "", "", errSSOAccountExists
+		} else if err != nil {
+			return "", "", e.Wrap(err, "error creating firebase user of sso login")
+		}
+		uid = &user.UID
+	}
+	token, err := c.AuthClient.CustomToken(ctx, *uid)
+	if err != nil {
+		return "", "", e.Wrap(err, "error creating firebase custom token")
+	}
+	return *uid, token, nil
+}
+
+func (c *PasswordAuthClient) createSSOToken(_ context.Context, uid *string, email string, _ string) (string, string, error) {
+	if uid == nil {
+		// each sso user is a separate admin, unlike the shared user of the admin password
+		uid = lo.ToPtr(fmt.Sprintf("sso-%x", sha256.Sum256([]byte(strings.ToLower(email)))))
+	}
+	token, err := signPasswordAuthToken(email, *uid)
+	if err != nil {
+		return "", "", err
+	}
+	return *uid, token, nil
+}
+
 func authenticateToken(tokenString string) (jwt.MapClaims, error) {
 	claims := jwt.MapClaims{}
 	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -247,6 +294,9 @@ func PrivateMiddleware(next http.Handler) http.Handler {
 				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
 			}
+		} else if apitoken.FromRequest(r) != "" {
+			// api tokens are authenticated by the APITokenMiddleware of the routes that accept them
+			span.SetAttribute("type", "apiToken")
 		} else if OAuthServer.HasCookie(r) || OAuthServer.HasBearer(r) {
 			span.SetAttribute("type", "oauth")
 			var cookie *http.Cookie
@@ -21,6 +21,12 @@ type Edge interface {
 	GetCursor() string
 }
 
+type APITokenInput struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays *int     `json:"expires_in_days"`
+}
+
 type AccessibleJiraResources struct {
 	ID        string   `json:"id"`
 	URL       string   `json:"url"`
@@ -90,23 +96,36 @@ type AdminAndWorkspaceDetails struct {
 	PromoCode                   *string `json:"promo_code"`
 }
 
+type AlertStateChange struct {
+	ProjectID int       `json:"project_id"`
+	AlertID   int       `json:"alert_id"`
+	AlertType string    `json:"alert_type"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 type AllProjectSettings struct {
-	ID                                int            `json:"id"`
-	VerboseID                         string         `json:"verbose_id"`
-	Name                              string         `json:"name"`
-	BillingEmail                      *string        `json:"billing_email"`
-	Secret                            *string        `json:"secret"`
-	WorkspaceID                       int            `json:"workspace_id"`
-	ExcludedUsers                     pq.StringArray `json:"excluded_users"`
-	ErrorFilters                      pq.StringArray `json:"error_filters"`
-	ErrorJSONPaths                    pq.StringArray `json:"error_json_paths"`
-	RageClickWindowSeconds            *int           `json:"rage_click_window_seconds"`
-	RageClickRadiusPixels             *int           `json:"rage_click_radius_pixels"`
-	RageClickCount                    *int           `json:"rage_click_count"`
-	FilterChromeExtension             *bool          `json:"filter_chrome_extension"`
-	FilterSessionsWithoutError        bool           `json:"filterSessionsWithoutError"`
-	AutoResolveStaleErrorsDayInterval int            `json:"autoResolveStaleErrorsDayInterval"`
-	Sampling                          *Sampling      `json:"sampling"`
+	ID                                int                   `json:"id"`
+	VerboseID                         string                `json:"verbose_id"`
+	Name                              string                `json:"name"`
+	BillingEmail                      *string               `json:"billing_email"`
+	Secret                            *string               `json:"secret"`
+	WorkspaceID                       int                   `json:"workspace_id"`
+	ExcludedUsers                     pq.StringArray        `json:"excluded_users"`
+	ErrorFilters                      pq.StringArray        `json:"error_filters"`
+	ErrorJSONPaths                    pq.StringArray        `json:"error_json_paths"`
+	RageClickWindowSeconds            *int                  `json:"rage_click_window_seconds"`
+	RageClickRadiusPixels             *int                  `json:"rage_click_radius_pixels"`
+	RageClickCount                    *int                  `json:"rage_click_count"`
+	FilterChromeExtension             *bool                 `json:"filter_chrome_extension"`
+	FilterSessionsWithoutError        bool                  `json:"filterSessionsWithoutError"`
+	AutoResolveStaleErrorsDayInterval int                   `json:"autoResolveStaleErrorsDayInterval"`
+	Sampling                          *Sampling             `json:"sampling"`
+	ExcludedServiceNames              []string              `json:"excluded_service_names"`
+	ExcludedLogLevels                 []string              `json:"excluded_log_levels"`
+	ErrorPayload                      *ErrorPayloadSettings `json:"error_payload"`
+	ErrorsFromSpanStatus              bool                  `json:"errors_from_span_status"`
 }
 
 type AverageSessionLength struct {
@@ -139,17 +158,71 @@ type CategoryHistogramPayload struct {
 	Buckets []*CategoryHistogramBucket `json:"buckets"`
 }
 
+type ChaosFault struct {
+	Subsystem      string    `json:"subsystem"`
+	ErrorPercent   float64   `json:"error_percent"`
+	LatencyPercent float64   `json:"latency_percent"`
+	LatencyMs      int64     `json:"latency_ms"`
+	Hosts          []string  `json:"hosts"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type ChaosFaultInput struct {
+	Subsystem      string    `json:"subsystem"`
+	ErrorPercent   float64   `json:"error_percent"`
+	LatencyPercent float64   `json:"latency_percent"`
+	LatencyMs      int64     `json:"latency_ms"`
+	Hosts          []string  `json:"hosts"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type ClickUpCustomField struct {
+	ID       string                      `json:"id"`
+	Name     string                      `json:"name"`
+	Type     string                      `json:"type"`
+	Required bool                        `json:"required"`
+	Options  []*ClickUpCustomFieldOption `json:"options"`
+}
+
+type ClickUpCustomFieldInput struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+type ClickUpCustomFieldOption struct {
+	ID    string  `json:"id"`
+	Name  *string `json:"name"`
+	Label *string `json:"label"`
+	Color *string `json:"color"`
+}
+
 type ClickUpFolder struct {
 	ID    string         `json:"id"`
 	Name  string         `json:"name"`
 	Lists []*ClickUpList `json:"lists"`
 }
 
+type ClickUpIntegrationStatus struct {
+	Connected         bool           `json:"connected"`
+	ReconnectRequired bool           `json:"reconnect_required"`
+	User              *ClickUpUser   `json:"user"`
+	Teams             []*ClickUpTeam `json:"teams"`
+	Error             *string        `json:"error"`
+}
+
 type ClickUpList struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
+type ClickUpMember struct {
+	ID             int    `json:"id"`
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	Initials       string `json:"initials"`
+	ProfilePicture string `json:"profile_picture"`
+}
+
 type ClickUpProjectMapping struct {
 	ProjectID      int    `json:"project_id"`
 	ClickupSpaceID string `json:"clickup_space_id"`
@@ -170,12 +243,27 @@ type ClickUpTask struct {
 	Name string `json:"name"`
 }
 
+type ClickUpTaskInput struct {
+	Assignees    []string                   `json:"assignees"`
+	Priority     *int                       `json:"priority"`
+	Tags         []string                   `json:"tags"`
+	Status       *string                    `json:"status"`
+	DueDate      *time.Time                 `json:"due_date"`
+	CustomFields []*ClickUpCustomFieldInput `json:"custom_fields"`
+}
+
 type ClickUpTeam struct {
 	ID     string          `json:"id"`
 	Name   string          `json:"name"`
 	Spaces []*ClickUpSpace `json:"spaces"`
 }
 
+type ClickUpUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
 type ClickhouseQuery struct {
 	IsAnd     bool                    `json:"isAnd"`
 	Rules     [][]string              `json:"rules"`
@@ -248,6 +336,13 @@ type DashboardPayload struct {
 	Group      *string          `json:"group"`
 }
 
+type DatadogLogForwarderInput struct {
+	Site             *string                `json:"site"`
+	APIKey           *string                `json:"api_key"`
+	AttributeMapping map[string]interface{} `json:"attribute_mapping"`
+	Enabled          bool                   `json:"enabled"`
+}
+
 type DateHistogramBucketSize struct {
 	CalendarInterval OpenSearchCalendarInterval `json:"calendar_interval"`
 	Multiple         int                        `json:"multiple"`
@@ -274,6 +369,11 @@ type DiscordChannelInput struct {
 	ID   string `json:"id"`
 }
 
+type DiscordWebhookInput struct {
+	Name       string `json:"name"`
+	WebhookURL string `json:"webhook_url"`
+}
+
 type EnhancedUserDetailsResult struct {
 	ID      *int          `json:"id"`
 	Name    *string       `json:"name"`
@@ -283,6 +383,12 @@ type EnhancedUserDetailsResult struct {
 	Email   *string       `json:"email"`
 }
 
+type ErrorAlertDestinationsInput struct {
+	PagerDuty    []*PagerDutyDestinationInput    `json:"pager_duty"`
+	Opsgenie     []*OpsgenieDestinationInput     `json:"opsgenie"`
+	SplunkOnCall []*SplunkOnCallDestinationInput `json:"splunk_on_call"`
+}
+
 type ErrorDistributionItem struct {
 	ErrorGroupID int       `json:"error_group_id"`
 	Date         time.Time `json:"date"`
@@ -306,6 +412,24 @@ type ErrorGroupTagAggregationBucket struct {
 	Percent  float64 `json:"percent"`
 }
 
+type ErrorGroupingRuleInput struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	EventRegex  *string `json:"event_regex"`
+	ServiceName *string `json:"service_name"`
+	Disabled    *bool   `json:"disabled"`
+}
+
+type ErrorIgnoreRuleInput struct {
+	Name         string  `json:"name"`
+	TypeRegex    *string `json:"type_regex"`
+	MessageRegex *string `json:"message_regex"`
+	BrowserRegex *string `json:"browser_regex"`
+	URLRegex     *string `json:"url_regex"`
+	SourceRegex  *string `json:"source_regex"`
+	Disabled     *bool   `json:"disabled"`
+}
+
 type ErrorMetadata struct {
 	ErrorID         int        `json:"error_id"`
 	SessionID       int        `json:"session_id"`
@@ -356,6 +480,32 @@ type ErrorObjectNodeSession struct {
 	Excluded    bool    `json:"excluded"`
 }
 
+type ErrorObjectTraceLogs struct {
+	TraceID string         `json:"trace_id"`
+	LogsURL string         `json:"logs_url"`
+	Logs    *LogConnection `json:"logs"`
+}
+
+type ErrorOwnershipRuleInput struct {
+	Name        string  `json:"name"`
+	Pattern     *string `json:"pattern"`
+	ServiceName *string `json:"service_name"`
+	AssigneeID  int     `json:"assignee_id"`
+	Disabled    *bool   `json:"disabled"`
+}
+
+type ErrorPayloadSettings struct {
+	HourlyLimit   *int64  `json:"hourly_limit"`
+	SamplingRate  float64 `json:"sampling_rate"`
+	RetentionDays *int    `json:"retention_days"`
+}
+
+type ErrorPayloadSettingsInput struct {
+	HourlyLimit   *int64  `json:"hourly_limit"`
+	SamplingRate  float64 `json:"sampling_rate"`
+	RetentionDays *int    `json:"retention_days"`
+}
+
 type ErrorTrace struct {
 	FileName                   *string             `json:"fileName"`
 	LineNumber                 *int                `json:"lineNumber"`
@@ -371,6 +521,37 @@ type ErrorTrace struct {
 	EnhancementVersion         *string             `json:"enhancementVersion"`
 }
 
+type ErrorWorkflowRuleActivity struct {
+	ID           int                    `json:"id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	ErrorGroupID int                    `json:"error_group_id"`
+	EventType    string                 `json:"event_type"`
+	EventData    map[string]interface{} `json:"event_data"`
+}
+
+type ErrorWorkflowRuleInput struct {
+	Name          string  `json:"name"`
+	Action        string  `json:"action"`
+	EventRegex    *string `json:"event_regex"`
+	StaleDays     *int    `json:"stale_days"`
+	SnoozeMinutes *int    `json:"snooze_minutes"`
+	Disabled      *bool   `json:"disabled"`
+}
+
+type EscalationPolicyInput struct {
+	Name  string                 `json:"name"`
+	Steps []*EscalationStepInput `json:"steps"`
+}
+
+type EscalationStepInput struct {
+	Type             string                        `json:"type"`
+	DelayMinutes     int                           `json:"delay_minutes"`
+	SlackChannels    []*SanitizedSlackChannelInput `json:"slack_channels"`
+	Emails           []string                      `json:"emails"`
+	OnCallScheduleID *int                          `json:"on_call_schedule_id"`
+	PagerDuty        *PagerDutyDestinationInput    `json:"pager_duty"`
+}
+
 type GitHubRepo struct {
 	RepoID string `json:"repo_id"`
 	Name   string `json:"name"`
@@ -383,6 +564,21 @@ type GitlabProject struct {
 	NameWithNameSpace string `json:"nameWithNameSpace"`
 }
 
+type HeartbeatCheckIn struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Status     string    `json:"status"`
+	DurationMs int       `json:"duration_ms"`
+}
+
+type HeartbeatMonitorInput struct {
+	Name             string  `json:"name"`
+	IntervalSeconds  int     `json:"interval_seconds"`
+	GraceSeconds     *int    `json:"grace_seconds"`
+	ChannelsToNotify *string `json:"channels_to_notify"`
+	EmailsToNotify   *string `json:"emails_to_notify"`
+	Disabled         *bool   `json:"disabled"`
+}
+
 type HeightList struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -408,6 +604,16 @@ type HistogramBucket struct {
 	Count      int     `json:"count"`
 }
 
+type IngestFilterRuleInput struct {
+	Name           string                 `json:"name"`
+	Action         IngestFilterRuleAction `json:"action"`
+	ServiceName    *string                `json:"service_name"`
+	Severity       *string                `json:"severity"`
+	AttributeKey   *string                `json:"attribute_key"`
+	AttributeRegex *string                `json:"attribute_regex"`
+	Disabled       bool                   `json:"disabled"`
+}
+
 type IntegrationProjectMappingInput struct {
 	ProjectID  int    `json:"project_id"`
 	ExternalID string `json:"external_id"`
@@ -428,6 +634,18 @@ type Invoice struct {
 	Status       *string    `json:"status"`
 }
 
+type IssueTrackerIssue struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type IssueTrackerProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
 type JiraIssueType struct {
 	Self             string              `json:"self"`
 	ID               string              `json:"id"`
@@ -467,6 +685,11 @@ type LengthRangeInput struct {
 	Max *float64 `json:"max"`
 }
 
+type LinearProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type LinearTeam struct {
 	TeamID string `json:"team_id"`
 	Name   string `json:"name"`
@@ -574,6 +797,11 @@ type MetricsBuckets struct {
 	SampleFactor float64         `json:"sample_factor"`
 }
 
+type MicrosoftTeamsChannelInput struct {
+	Name       string `json:"name"`
+	WebhookURL string `json:"webhook_url"`
+}
+
 type NamedCount struct {
 	Name  string `json:"name"`
 	Count int    `json:"count"`
@@ -594,6 +822,19 @@ type OAuthClient struct {
 	AppName   string    `json:"app_name"`
 }
 
+type OnCallScheduleInput struct {
+	Name          string     `json:"name"`
+	Participants  []string   `json:"participants"`
+	RotationHours *int       `json:"rotation_hours"`
+	StartsAt      *time.Time `json:"starts_at"`
+}
+
+type OpsgenieDestinationInput struct {
+	APIKey   *string `json:"api_key"`
+	Region   string  `json:"region"`
+	Severity *string `json:"severity"`
+}
+
 type PageInfo struct {
 	HasNextPage     bool   `json:"hasNextPage"`
 	HasPreviousPage bool   `json:"hasPreviousPage"`
@@ -601,6 +842,11 @@ type PageInfo struct {
 	EndCursor       string `json:"endCursor"`
 }
 
+type PagerDutyDestinationInput struct {
+	RoutingKey string  `json:"routing_key"`
+	Severity   *string `json:"severity"`
+}
+
 type Plan struct {
 	Type                PlanType             `json:"type"`
 	Interval            SubscriptionInterval `json:"interval"`
@@ -616,6 +862,29 @@ type Plan struct {
 	TracesRate          float64              `json:"tracesRate"`
 }
 
+type ProductAnalyticsExportInput struct {
+	Region                   *string `json:"region"`
+	APIKey                   *string `json:"api_key"`
+	UserIDProperty           *string `json:"user_id_property"`
+	ExportRageClicks         bool    `json:"export_rage_clicks"`
+	ExportIdentifiedSessions bool    `json:"export_identified_sessions"`
+	Enabled                  bool    `json:"enabled"`
+}
+
+type Profile struct {
+	ID          string    `json:"id"`
+	ServiceName string    `json:"service_name"`
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	Size        int64     `json:"size"`
+}
+
+type ProjectDigestSettingInput struct {
+	Frequency string `json:"frequency"`
+	Weekday   int    `json:"weekday"`
+	Hour      int    `json:"hour"`
+}
+
 type QueryInput struct {
 	Query     string                  `json:"query"`
 	DateRange *DateRangeRequiredInput `json:"date_range"`
@@ -633,16 +902,92 @@ type RageClickEventForProject struct {
 	UserProperties  string `json:"user_properties"`
 }
 
+type RedactionPreset struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+type RedactionRules struct {
+	Keys     []string           `json:"keys"`
+	Patterns []string           `json:"patterns"`
+	Presets  []*RedactionPreset `json:"presets"`
+}
+
 type ReferrerTablePayload struct {
 	Host    string  `json:"host"`
 	Count   int     `json:"count"`
 	Percent float64 `json:"percent"`
 }
 
+type Role struct {
+	ID          *int     `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"built_in"`
+}
+
 type S3File struct {
 	Key *string `json:"key"`
 }
 
+type SSOConfig struct {
+	WorkspaceID                   int             `json:"workspace_id"`
+	Provider                      string          `json:"provider"`
+	Protocol                      string          `json:"protocol"`
+	Domain                        string          `json:"domain"`
+	DomainVerified                bool            `json:"domain_verified"`
+	DomainVerificationRecordName  string          `json:"domain_verification_record_name"`
+	DomainVerificationRecordValue string          `json:"domain_verification_record_value"`
+	SamlIdpEntityID               string          `json:"saml_idp_entity_id"`
+	SamlIdpSsoURL                 string          `json:"saml_idp_sso_url"`
+	SamlIdpCertificate            string          `json:"saml_idp_certificate"`
+	OidcIssuer                    string          `json:"oidc_issuer"`
+	OidcClientID                  string          `json:"oidc_client_id"`
+	OidcClientSecretSet           bool            `json:"oidc_client_secret_set"`
+	GroupRoles                    []*SSOGroupRole `json:"group_roles"`
+	DefaultRole                   string          `json:"default_role"`
+	ScimTokenSet                  bool            `json:"scim_token_set"`
+	Enabled                       bool            `json:"enabled"`
+	LoginURL                      string          `json:"login_url"`
+	SamlEntityID                  string          `json:"saml_entity_id"`
+	SamlAcsURL                    string          `json:"saml_acs_url"`
+	OidcRedirectURL               string          `json:"oidc_redirect_url"`
+	ScimBaseURL                   string          `json:"scim_base_url"`
+}
+
+type SSOConfigInput struct {
+	Provider           string               `json:"provider"`
+	Protocol           string               `json:"protocol"`
+	Domain             string               `json:"domain"`
+	SamlIdpEntityID    *string              `json:"saml_idp_entity_id"`
+	SamlIdpSsoURL      *string              `json:"saml_idp_sso_url"`
+	SamlIdpCertificate *string              `json:"saml_idp_certificate"`
+	OidcIssuer         *string              `json:"oidc_issuer"`
+	OidcClientID       *string              `json:"oidc_client_id"`
+	OidcClientSecret   *string              `json:"oidc_client_secret"`
+	GroupRoles         []*SSOGroupRoleInput `json:"group_roles"`
+	DefaultRole        *string              `json:"default_role"`
+	Enabled            bool                 `json:"enabled"`
+}
+
+type SSOGroupRole struct {
+	Group string `json:"group"`
+	Role  string `json:"role"`
+}
+
+type SSOGroupRoleInput struct {
+	Group string `json:"group"`
+	Role  string `json:"role"`
+}
+
+type SampledOutCount struct {
+	Date    string       `json:"date"`
+	Product ProductType  `json:"product"`
+	Reason  IngestReason `json:"reason"`
+	Count   int64        `json:"count"`
+}
+
 type Sampling struct {
 	SessionSamplingRate    float64 `json:"session_sampling_rate"`
 	ErrorSamplingRate      float64 `json:"error_sampling_rate"`
@@ -712,6 +1057,28 @@ type ServiceEdge struct {
 func (ServiceEdge) IsEdge()                {}
 func (this ServiceEdge) GetCursor() string { return this.Cursor }
 
+type ServiceGraph struct {
+	Nodes []*ServiceGraphNode `json:"nodes"`
+	Edges []*ServiceGraphEdge `json:"edges"`
+}
+
+type ServiceGraphEdge struct {
+	Client      string  `json:"client"`
+	Server      string  `json:"server"`
+	Requests    uint64  `json:"requests"`
+	Errors      uint64  `json:"errors"`
+	RequestRate float64 `json:"request_rate"`
+	ErrorRate   float64 `json:"error_rate"`
+	AvgDuration float64 `json:"avg_duration"`
+}
+
+type ServiceGraphNode struct {
+	Name      string  `json:"name"`
+	Requests  uint64  `json:"requests"`
+	Errors    uint64  `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
 type ServiceNode struct {
 	ID             int           `json:"id"`
 	ProjectID      int           `json:"projectID"`
@@ -801,6 +1168,22 @@ type SourceMappingError struct {
 	MappedColumnNumber         *int                    `json:"mappedColumnNumber"`
 }
 
+type SourcemapBucketInput struct {
+	Provider          string  `json:"provider"`
+	Bucket            string  `json:"bucket"`
+	Prefix            *string `json:"prefix"`
+	Region            *string `json:"region"`
+	RoleArn           *string `json:"role_arn"`
+	ServiceAccountKey *string `json:"service_account_key"`
+	Disabled          *bool   `json:"disabled"`
+}
+
+type SplunkOnCallDestinationInput struct {
+	APIKey     *string `json:"api_key"`
+	RoutingKey string  `json:"routing_key"`
+	Severity   *string `json:"severity"`
+}
+
 type SubscriptionDetails struct {
 	BaseAmount           int64                 `json:"baseAmount"`
 	Discount             *SubscriptionDiscount `json:"discount"`
@@ -846,6 +1229,22 @@ type Trace struct {
 	Links           []*TraceLink           `json:"links"`
 }
 
+type TraceAlertInput struct {
+	Name                string                        `json:"name"`
+	ServiceName         *string                       `json:"service_name"`
+	SpanName            *string                       `json:"span_name"`
+	Query               *string                       `json:"query"`
+	Metric              *string                       `json:"metric"`
+	Aggregator          *MetricAggregator             `json:"aggregator"`
+	Threshold           float64                       `json:"threshold"`
+	WindowMinutes       *int                          `json:"window_minutes"`
+	MinSpans            *int                          `json:"min_spans"`
+	SlackChannels       []*SanitizedSlackChannelInput `json:"slack_channels"`
+	DiscordChannels     []*DiscordChannelInput        `json:"discord_channels"`
+	WebhookDestinations []*WebhookDestinationInput    `json:"webhook_destinations"`
+	Disabled            *bool                         `json:"disabled"`
+}
+
 type TraceConnection struct {
 	Edges    []*TraceEdge `json:"edges"`
 	PageInfo *PageInfo    `json:"pageInfo"`
@@ -898,6 +1297,43 @@ type TrackPropertyInput struct {
 	Value string `json:"value"`
 }
 
+type UptimeCheck struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Success       bool      `json:"success"`
+	StatusCode    int       `json:"status_code"`
+	LatencyMs     int       `json:"latency_ms"`
+	FailureReason string    `json:"failure_reason"`
+}
+
+type UptimeChecks struct {
+	Checks          []*UptimeCheck          `json:"checks"`
+	DowntimeWindows []*UptimeDowntimeWindow `json:"downtime_windows"`
+}
+
+type UptimeDowntimeWindow struct {
+	StartDate          time.Time `json:"start_date"`
+	EndDate            time.Time `json:"end_date"`
+	FailedChecks       int       `json:"failed_checks"`
+	ErrorCount         uint64    `json:"error_count"`
+	BaselineErrorCount uint64    `json:"baseline_error_count"`
+}
+
+type UptimeMonitorInput struct {
+	Name               string  `json:"name"`
+	Type               *string `json:"type"`
+	URL                string  `json:"url"`
+	Method             *string `json:"method"`
+	IntervalSeconds    *int    `json:"interval_seconds"`
+	TimeoutSeconds     *int    `json:"timeout_seconds"`
+	ExpectedStatusCode *int    `json:"expected_status_code"`
+	BodyContains       *string `json:"body_contains"`
+	MaxLatencyMs       *int    `json:"max_latency_ms"`
+	FailureThreshold   *int    `json:"failure_threshold"`
+	ChannelsToNotify   *string `json:"channels_to_notify"`
+	EmailsToNotify     *string `json:"emails_to_notify"`
+	Disabled           *bool   `json:"disabled"`
+}
+
 type User struct {
 	ID int `json:"id"`
 }
@@ -935,6 +1371,27 @@ type VercelProjectMappingInput struct {
 	ProjectID       *int    `json:"project_id"`
 }
 
+type WarehouseExportInput struct {
+	Destination            string   `json:"destination"`
+	StorageURL             string   `json:"storage_url"`
+	StorageAccessKeyID     string   `json:"storage_access_key_id"`
+	StorageSecretAccessKey *string  `json:"storage_secret_access_key"`
+	BigqueryProjectID      *string  `json:"bigquery_project_id"`
+	BigqueryDataset        *string  `json:"bigquery_dataset"`
+	BigqueryCredentials    *string  `json:"bigquery_credentials"`
+	SnowflakeAccount       *string  `json:"snowflake_account"`
+	SnowflakeUser          *string  `json:"snowflake_user"`
+	SnowflakeRole          *string  `json:"snowflake_role"`
+	SnowflakeWarehouse     *string  `json:"snowflake_warehouse"`
+	SnowflakeDatabase      *string  `json:"snowflake_database"`
+	SnowflakeSchema        *string  `json:"snowflake_schema"`
+	SnowflakeStage         *string  `json:"snowflake_stage"`
+	SnowflakePrivateKey    *string  `json:"snowflake_private_key"`
+	ExportLogs             bool     `json:"export_logs"`
+	LogSampleRate          *float64 `json:"log_sample_rate"`
+	Enabled                bool     `json:"enabled"`
+}
+
 type WebSocketEvent struct {
 	Message   string  `json:"message"`
 	Name      string  `json:"name"`
@@ -944,11 +1401,26 @@ type WebSocketEvent struct {
 	Size      int     `json:"size"`
 }
 
+type WebVitalPercentiles struct {
+	Name  string  `json:"name"`
+	URL   *string `json:"url"`
+	Count uint64  `json:"count"`
+	P50   float64 `json:"p50"`
+	P75   float64 `json:"p75"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+}
+
 type WebhookDestinationInput struct {
 	URL           string  `json:"url"`
 	Authorization *string `json:"authorization"`
 }
 
+type WebhookSettings struct {
+	MaxRetries    int     `json:"max_retries"`
+	SigningSecret *string `json:"signing_secret"`
+}
+
 type WorkspaceForInviteLink struct {
 	ExpirationDate  *time.Time `json:"expiration_date"`
 	InviteeEmail    *string    `json:"invitee_email"`
@@ -958,6 +1430,61 @@ type WorkspaceForInviteLink struct {
 	ExistingAccount bool       `json:"existing_account"`
 }
 
+type WorkspaceRoleInput struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type WorkspaceRoles struct {
+	Roles       []*Role  `json:"roles"`
+	Permissions []string `json:"permissions"`
+	CurrentRole *Role    `json:"current_role"`
+}
+
+type AlertKind string
+
+const (
+	AlertKindErrorAlert   AlertKind = "ErrorAlert"
+	AlertKindSessionAlert AlertKind = "SessionAlert"
+	AlertKindLogAlert     AlertKind = "LogAlert"
+)
+
+var AllAlertKind = []AlertKind{
+	AlertKindErrorAlert,
+	AlertKindSessionAlert,
+	AlertKindLogAlert,
+}
+
+func (e AlertKind) IsValid() bool {
+	switch e {
+	case AlertKindErrorAlert, AlertKindSessionAlert, AlertKindLogAlert:
+		return true
+	}
+	return false
+}
+
+func (e AlertKind) String() string {
+	return string(e)
+}
+
+func (e *AlertKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AlertKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AlertKind", str)
+	}
+	return nil
+}
+
+func (e AlertKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type DashboardChartType string
 
 const (
@@ -1050,17 +1577,19 @@ type EnhancementSource string
 
 const (
 	EnhancementSourceGithub    EnhancementSource = "github"
+	EnhancementSourceGitlab    EnhancementSource = "gitlab"
 	EnhancementSourceSourcemap EnhancementSource = "sourcemap"
 )
 
 var AllEnhancementSource = []EnhancementSource{
 	EnhancementSourceGithub,
+	EnhancementSourceGitlab,
 	EnhancementSourceSourcemap,
 }
 
 func (e EnhancementSource) IsValid() bool {
 	switch e {
-	case EnhancementSourceGithub, EnhancementSourceSourcemap:
+	case EnhancementSourceGithub, EnhancementSourceGitlab, EnhancementSourceSourcemap:
 		return true
 	}
 	return false
@@ -1130,6 +1659,47 @@ func (e ErrorState) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type IngestFilterRuleAction string
+
+const (
+	IngestFilterRuleActionDrop IngestFilterRuleAction = "drop"
+	IngestFilterRuleActionKeep IngestFilterRuleAction = "keep"
+)
+
+var AllIngestFilterRuleAction = []IngestFilterRuleAction{
+	IngestFilterRuleActionDrop,
+	IngestFilterRuleActionKeep,
+}
+
+func (e IngestFilterRuleAction) IsValid() bool {
+	switch e {
+	case IngestFilterRuleActionDrop, IngestFilterRuleActionKeep:
+		return true
+	}
+	return false
+}
+
+func (e IngestFilterRuleAction) String() string {
+	return string(e)
+}
+
+func (e *IngestFilterRuleAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IngestFilterRuleAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IngestFilterRuleAction", str)
+	}
+	return nil
+}
+
+func (e IngestFilterRuleAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type IngestReason string
 
 const (
@@ -1176,17 +1746,24 @@ func (e IngestReason) MarshalGQL(w io.Writer) {
 type IntegrationType string
 
 const (
-	IntegrationTypeSlack   IntegrationType = "Slack"
-	IntegrationTypeLinear  IntegrationType = "Linear"
-	IntegrationTypeZapier  IntegrationType = "Zapier"
-	IntegrationTypeFront   IntegrationType = "Front"
-	IntegrationTypeVercel  IntegrationType = "Vercel"
-	IntegrationTypeDiscord IntegrationType = "Discord"
-	IntegrationTypeClickUp IntegrationType = "ClickUp"
-	IntegrationTypeHeight  IntegrationType = "Height"
-	IntegrationTypeGitHub  IntegrationType = "GitHub"
-	IntegrationTypeJira    IntegrationType = "Jira"
-	IntegrationTypeGitLab  IntegrationType = "GitLab"
+	IntegrationTypeSlack      IntegrationType = "Slack"
+	IntegrationTypeLinear     IntegrationType = "Linear"
+	IntegrationTypeZapier     IntegrationType = "Zapier"
+	IntegrationTypeFront      IntegrationType = "Front"
+	IntegrationTypeVercel     IntegrationType = "Vercel"
+	IntegrationTypeDiscord    IntegrationType = "Discord"
+	IntegrationTypeClickUp    IntegrationType = "ClickUp"
+	IntegrationTypeHeight     IntegrationType = "Height"
+	IntegrationTypeGitHub     IntegrationType = "GitHub"
+	IntegrationTypeJira       IntegrationType = "Jira"
+	IntegrationTypeGitLab     IntegrationType = "GitLab"
+	IntegrationTypeAsana      IntegrationType = "Asana"
+	IntegrationTypeMonday     IntegrationType = "Monday"
+	IntegrationTypeShortcut   IntegrationType = "Shortcut"
+	IntegrationTypeIntercom   IntegrationType = "Intercom"
+	IntegrationTypeZendesk    IntegrationType = "Zendesk"
+	IntegrationTypeSalesforce IntegrationType = "Salesforce"
+	IntegrationTypeHubSpot    IntegrationType = "HubSpot"
 )
 
 var AllIntegrationType = []IntegrationType{
@@ -1201,11 +1778,18 @@ var AllIntegrationType = []IntegrationType{
 	IntegrationTypeGitHub,
 	IntegrationTypeJira,
 	IntegrationTypeGitLab,
+	IntegrationTypeAsana,
+	IntegrationTypeMonday,
+	IntegrationTypeShortcut,
+	IntegrationTypeIntercom,
+	IntegrationTypeZendesk,
+	IntegrationTypeSalesforce,
+	IntegrationTypeHubSpot,
 }
 
 func (e IntegrationType) IsValid() bool {
 	switch e {
-	case IntegrationTypeSlack, IntegrationTypeLinear, IntegrationTypeZapier, IntegrationTypeFront, IntegrationTypeVercel, IntegrationTypeDiscord, IntegrationTypeClickUp, IntegrationTypeHeight, IntegrationTypeGitHub, IntegrationTypeJira, IntegrationTypeGitLab:
+	case IntegrationTypeSlack, IntegrationTypeLinear, IntegrationTypeZapier, IntegrationTypeFront, IntegrationTypeVercel, IntegrationTypeDiscord, IntegrationTypeClickUp, IntegrationTypeHeight, IntegrationTypeGitHub, IntegrationTypeJira, IntegrationTypeGitLab, IntegrationTypeAsana, IntegrationTypeMonday, IntegrationTypeShortcut, IntegrationTypeIntercom, IntegrationTypeZendesk, IntegrationTypeSalesforce, IntegrationTypeHubSpot:
 		return true
 	}
 	return false
@@ -1886,6 +2470,10 @@ const (
 	ReservedLogKeySource          ReservedLogKey = "source"
 	ReservedLogKeyServiceName     ReservedLogKey = "service_name"
 	ReservedLogKeyServiceVersion  ReservedLogKey = "service_version"
+	ReservedLogKeyK8sPodName      ReservedLogKey = "k8s_pod_name"
+	ReservedLogKeyK8sNamespace    ReservedLogKey = "k8s_namespace"
+	ReservedLogKeyContainerID     ReservedLogKey = "container_id"
+	ReservedLogKeyHostName        ReservedLogKey = "host_name"
 )
 
 var AllReservedLogKey = []ReservedLogKey{
@@ -1898,11 +2486,15 @@ var AllReservedLogKey = []ReservedLogKey{
 	ReservedLogKeySource,
 	ReservedLogKeyServiceName,
 	ReservedLogKeyServiceVersion,
+	ReservedLogKeyK8sPodName,
+	ReservedLogKeyK8sNamespace,
+	ReservedLogKeyContainerID,
+	ReservedLogKeyHostName,
 }
 
 func (e ReservedLogKey) IsValid() bool {
 	switch e {
-	case ReservedLogKeyEnvironment, ReservedLogKeyLevel, ReservedLogKeyMessage, ReservedLogKeySecureSessionID, ReservedLogKeySpanID, ReservedLogKeyTraceID, ReservedLogKeySource, ReservedLogKeyServiceName, ReservedLogKeyServiceVersion:
+	case ReservedLogKeyEnvironment, ReservedLogKeyLevel, ReservedLogKeyMessage, ReservedLogKeySecureSessionID, ReservedLogKeySpanID, ReservedLogKeyTraceID, ReservedLogKeySource, ReservedLogKeyServiceName, ReservedLogKeyServiceVersion, ReservedLogKeyK8sPodName, ReservedLogKeyK8sNamespace, ReservedLogKeyContainerID, ReservedLogKeyHostName:
 		return true
 	}
 	return false
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	"github.com/highlight-run/highlight/backend/storage"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxProfilesRange = 31 * 24 * time.Hour
+
+// newProfilesRange returns the range to list profiles in, the last day by default.
+// The range may span at most 31 days.
+func newProfilesRange(dateRange *modelInputs.DateRangeRequiredInput) (time.Time, time.Time, error) {
+	startDate, endDate := time.Now().Add(-24*time.Hour), time.Now()
+	if dateRange != nil {
+		startDate, endDate = dateRange.StartDate, dateRange.EndDate
+	}
+	if endDate.Before(startDate) || endDate.Sub(startDate) > maxProfilesRange {
+		return startDate, endDate, e.New("the date range must span at most 31 days")
+	}
+	return startDate, endDate, nil
+}
+
+func profiles(results []*storage.Profile) []*modelInputs.Profile {
+	return lo.Map(results, func(profile *storage.Profile, _ int) *modelInputs.Profile {
+		result := modelInputs.Profile(*profile)
+		return &result
+	})
+}
+
+// ProfileDownloadHandler returns the gzipped pprof profile of the `id` returned by the profiles query.
+func (r *Resolver) ProfileDownloadHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeProjectRequest(w, req, rbac.PermissionView)
+	if !ok {
+		return
+	}
+
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	data, err := r.StorageClient.ReadProfile(ctx, project.ID, id)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error reading profile"))
+		http.Error(w, "", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", `attachment; filename="profile.pb.gz"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error writing profile"))
+	}
+}
@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redact"
+	"github.com/samber/lo"
+)
+
+// redactionRules returns the attribute keys and value patterns redacted from a project's otel data
+// before it is written, along with the redact.Presets that can be used in place of a pattern.
+func redactionRules(settings *model.ProjectFilterSettings) *modelInputs.RedactionRules {
+	presets := lo.MapToSlice(redact.Presets, func(name string, pattern string) *modelInputs.RedactionPreset {
+		return &modelInputs.RedactionPreset{Name: name, Pattern: pattern}
+	})
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+
+	return &modelInputs.RedactionRules{
+		Keys:     append([]string{}, settings.RedactedAttributeKeys...),
+		Patterns: append([]string{}, settings.RedactionPatterns...),
+		Presets:  presets,
+	}
+}
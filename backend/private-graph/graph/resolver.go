@@ -18,6 +18,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	github2 "github.com/google/go-github/v50/github"
 	parse "github.com/highlight-run/highlight/backend/event-parse"
+	"github.com/highlight-run/highlight/backend/githubissues"
 	"github.com/highlight-run/highlight/backend/integrations/github"
 	"github.com/highlight-run/highlight/backend/integrations/gitlab"
 	"github.com/highlight-run/highlight/backend/integrations/jira"
@@ -36,10 +37,13 @@ import (
 	"github.com/highlight-run/highlight/backend/front"
 	"github.com/highlight-run/highlight/backend/integrations"
 	"github.com/highlight-run/highlight/backend/integrations/height"
+	"github.com/highlight-run/highlight/backend/issuetracker"
 	kafka_queue "github.com/highlight-run/highlight/backend/kafka-queue"
 	"github.com/highlight-run/highlight/backend/lambda"
+	"github.com/highlight-run/highlight/backend/linear"
 	"github.com/highlight-run/highlight/backend/oauth"
 	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/shortcut"
 	"github.com/highlight-run/highlight/backend/stepfunctions"
 	"github.com/highlight-run/highlight/backend/store"
 	"github.com/highlight-run/highlight/backend/vercel"
@@ -454,6 +458,10 @@ func (r *Resolver) isAdminInWorkspace(ctx context.Context, workspaceID int) (*mo
 
 	span.SetAttribute("WorkspaceID", workspaceID)
 
+	if !apiTokenAllowsWorkspace(ctx, workspaceID) {
+		return nil, AuthorizationError
+	}
+
 	if r.isWhitelistedAccount(ctx) {
 		return r.GetWorkspace(workspaceID)
 	}
@@ -492,8 +500,12 @@ func (r *Resolver) isAdminInProject(ctx context.Context, project_id int) (*model
 		if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: project_id}}).Take(&project).Error; err != nil {
 			return nil, e.Wrap(err, "error querying project")
 		}
+		if !apiTokenAllowsWorkspace(ctx, project.WorkspaceID) {
+			return nil, AuthorizationError
+		}
 		return project, nil
 	}
+	// the projects of the admin are limited to the workspace of an api token
 	projects, err := r.Query().Projects(ctx)
 	if err != nil {
 		return nil, e.Wrap(err, "error querying projects")
@@ -1224,24 +1236,6 @@ func (r *Resolver) UnmarshalStackTrace(stackTraceString string) ([]*modelInputs.
 	return ret, nil
 }
 
-func (r *Resolver) validateAdminRole(ctx context.Context, workspaceID int) error {
-	if r.isWhitelistedAccount(ctx) {
-		return nil
-	}
-
-	admin, err := r.getCurrentAdmin(ctx)
-	if err != nil {
-		return err
-	}
-
-	role, err := r.GetAdminRole(ctx, admin.ID, workspaceID)
-	if err != nil || role != model.AdminRole.ADMIN {
-		return AuthorizationError
-	}
-
-	return nil
-}
-
 // GenerateRandomBytes returns securely generated random bytes.
 // It will return an error if the system's secure random
 // number generator fails to function correctly, in which
@@ -1389,30 +1383,39 @@ func getIdForPageFromUrl(parsedUrl *url.URL, page string) (string, error) {
 	return pathParts[3], nil
 }
 
+// verifySlackRequest reads the body of a request and verifies that it was signed by slack with the signing secret.
+func verifySlackRequest(ctx context.Context, w http.ResponseWriter, req *http.Request, signingSecret string) ([]byte, bool) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "couldn't read request body"))
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	}
+
+	// verify request is from slack
+	sv, err := slack.NewSecretsVerifier(req.Header, signingSecret)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error verifying request headers"))
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	}
+	if _, err := sv.Write(body); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error when verifying request"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	if err := sv.Ensure(); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "couldn't verify that request is from slack with the signing secret"))
+		w.WriteHeader(http.StatusUnauthorized)
+		return nil, false
+	}
+	return body, true
+}
+
 func (r *Resolver) SlackEventsWebhook(ctx context.Context, signingSecret string) func(w http.ResponseWriter, req *http.Request) {
 	return func(w http.ResponseWriter, req *http.Request) {
-		body, err := io.ReadAll(req.Body)
-		if err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "couldn't read request body"))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
-		// verify request is from slack
-		sv, err := slack.NewSecretsVerifier(req.Header, signingSecret)
-		if err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error verifying request headers"))
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-		if _, err := sv.Write(body); err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error when verifying request"))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		if err := sv.Ensure(); err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "couldn't verify that request is from slack with the signing secret"))
-			w.WriteHeader(http.StatusUnauthorized)
+		body, ok := verifySlackRequest(ctx, w, req, signingSecret)
+		if !ok {
 			return
 		}
 
@@ -1776,13 +1779,39 @@ func (r *Resolver) AddClickUpToWorkspace(ctx context.Context, workspace *model.W
 		return e.Wrap(err, "error getting ClickUp oauth access token")
 	}
 
-	if err := r.DB.WithContext(ctx).Where(&workspace).Select("clickup_access_token").Updates(&model.Workspace{ClickupAccessToken: &res.AccessToken}).Error; err != nil {
+	if err := r.IntegrationsClient.WorkspaceTokenStore(workspace, modelInputs.IntegrationTypeClickUp).SetToken(ctx, res); err != nil {
 		return e.Wrap(err, "error updating ClickUp access token in workspace")
 	}
 
+	// the teams of a reconnected integration may differ from the cached ones
+	if err := clickup.NewMetadataCache(r.Redis, workspace.ID).Invalidate(ctx); err != nil {
+		log.WithContext(ctx).WithError(err).Warn("failed to invalidate ClickUp metadata cache")
+	}
+
 	return nil
 }
 
+// ClickUpClient returns a ClickUp client for the workspace, which refreshes the workspace token
+// when it expires and caches the teams, spaces, folders and lists of the workspace.
+func (r *Resolver) ClickUpClient(ctx context.Context, workspace *model.Workspace) (*clickup.Client, error) {
+	return issuetracker.NewClickUpClient(ctx, issuetracker.Deps{Integrations: r.IntegrationsClient, Redis: r.Redis}, workspace)
+}
+
+// clickUpError returns the error of a failed ClickUp request as shown to the user.
+func clickUpError(err error) error {
+	var apiErr *clickup.APIError
+	if !e.As(err, &apiErr) {
+		return err
+	}
+	if apiErr.RateLimited() {
+		return e.Errorf("ClickUp is rate limiting requests, try again in %s", apiErr.RetryAfter.Round(time.Second))
+	}
+	if apiErr.Unauthorized() {
+		return e.New("ClickUp access was revoked, reconnect the ClickUp integration")
+	}
+	return err
+}
+
 func (r *Resolver) AddJiraToWorkspace(ctx context.Context, workspace *model.Workspace, code string) error {
 	err := r.IntegrationsClient.GetAndSetWorkspaceToken(ctx, workspace, modelInputs.IntegrationTypeJira, code)
 	if err != nil {
@@ -1838,6 +1867,26 @@ func (r *Resolver) AddGitHubToWorkspace(ctx context.Context, workspace *model.Wo
 	return nil
 }
 
+// AddShortcutToWorkspace stores the api token of a Shortcut member, which the integration uses
+// instead of oauth. The token is verified before it is stored.
+func (r *Resolver) AddShortcutToWorkspace(ctx context.Context, workspace *model.Workspace, apiToken string) error {
+	if _, err := shortcut.NewClient(apiToken).GetCurrentMember(ctx); err != nil {
+		return e.Wrap(err, "failed to verify Shortcut API token")
+	}
+
+	integrationWorkspaceMapping := &model.IntegrationWorkspaceMapping{
+		WorkspaceID:     workspace.ID,
+		IntegrationType: modelInputs.IntegrationTypeShortcut,
+		AccessToken:     apiToken,
+	}
+	if err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(integrationWorkspaceMapping).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func (r *Resolver) AddDiscordToWorkspace(ctx context.Context, workspace *model.Workspace, code string) error {
 	token, err := discord.OAuth(ctx, code)
 
@@ -2067,12 +2116,23 @@ func (r *Resolver) RemoveClickUpFromWorkspace(workspace *model.Workspace) error
 		return err
 	}
 
+	if err := r.DB.WithContext(context.TODO()).Where(&model.IntegrationWorkspaceMapping{
+		WorkspaceID:     workspace.ID,
+		IntegrationType: modelInputs.IntegrationTypeClickUp,
+	}).Delete(&model.IntegrationWorkspaceMapping{}).Error; err != nil {
+		return e.Wrap(err, "error removing ClickUp workspace token")
+	}
+
 	if err := r.DB.WithContext(context.TODO()).Where(workspace).
 		Select("clickup_access_token").
 		Updates(&model.Workspace{ClickupAccessToken: nil}).Error; err != nil {
 		return e.Wrap(err, "error removing ClickUp access token")
 	}
 
+	if err := clickup.NewMetadataCache(r.Redis, workspace.ID).Invalidate(context.TODO()); err != nil {
+		log.WithContext(context.TODO()).WithError(err).Warn("failed to invalidate ClickUp metadata cache")
+	}
+
 	return nil
 }
 
@@ -2149,21 +2209,7 @@ func (r *Resolver) RemoveDiscordFromWorkspace(workspace *model.Workspace) error
 }
 
 func (r *Resolver) AddLinearToWorkspace(workspace *model.Workspace, code string) error {
-	var (
-		LINEAR_CLIENT_ID     string
-		LINEAR_CLIENT_SECRET string
-	)
-
-	if tempLinearClientID, ok := os.LookupEnv("LINEAR_CLIENT_ID"); ok && tempLinearClientID != "" {
-		LINEAR_CLIENT_ID = tempLinearClientID
-	}
-	if tempLinearClientSecret, ok := os.LookupEnv("LINEAR_CLIENT_SECRET"); ok && tempLinearClientSecret != "" {
-		LINEAR_CLIENT_SECRET = tempLinearClientSecret
-	}
-
-	redirect := FrontendURI + "/callback/linear"
-
-	res, err := r.GetLinearAccessToken(code, redirect, LINEAR_CLIENT_ID, LINEAR_CLIENT_SECRET)
+	res, err := linear.GetAccessToken(context.TODO(), code, FrontendURI+"/callback/linear")
 	if err != nil {
 		return e.Wrap(err, "error getting linear oauth access token")
 	}
@@ -2176,7 +2222,7 @@ func (r *Resolver) AddLinearToWorkspace(workspace *model.Workspace, code string)
 }
 
 func (r *Resolver) RemoveLinearFromWorkspace(workspace *model.Workspace) error {
-	if err := r.RevokeLinearAccessToken(*workspace.LinearAccessToken); err != nil {
+	if err := linear.RevokeAccessToken(context.TODO(), *workspace.LinearAccessToken); err != nil {
 		return err
 	}
 
@@ -2187,212 +2233,9 @@ func (r *Resolver) RemoveLinearFromWorkspace(workspace *model.Workspace) error {
 	return nil
 }
 
-func (r *Resolver) MakeLinearGraphQLRequest(accessToken string, body string) ([]byte, error) {
-	client := &http.Client{}
-
-	req, err := http.NewRequest("POST", "https://api.linear.app/graphql", strings.NewReader(body))
-	if err != nil {
-		return nil, e.Wrap(err, "error creating api request to linear")
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, e.Wrap(err, "error getting response from linear graphql endpoint")
-	}
-
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, e.Wrap(err, "error reading response body from linear graphql endpoint")
-	}
-
-	if res.StatusCode != 200 {
-		return nil, e.New("linear graphql API responded with error; status_code=" + res.Status + "; body=" + string(b))
-	}
-
-	return b, nil
-}
-
-type LinearTeam struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Key  string `json:"key"`
-}
-type LinearTeamsResponse struct {
-	Data struct {
-		Teams struct {
-			Nodes []LinearTeam `json:"nodes"`
-		} `json:"teams"`
-	} `json:"data"`
-}
-
-func (r *Resolver) GetLinearTeams(accessToken string) (*LinearTeamsResponse, error) {
-	requestQuery := `
-	query {
-		teams {
-			nodes {
-				id
-				name
-				key
-			}
-		}
-	}
-	`
-
-	type GraphQLReq struct {
-		Query string `json:"query"`
-	}
-
-	req := GraphQLReq{Query: requestQuery}
-	requestBytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := r.MakeLinearGraphQLRequest(accessToken, string(requestBytes))
-	if err != nil {
-		return nil, err
-	}
-
-	teamsResponse := &LinearTeamsResponse{}
-
-	err = json.Unmarshal(b, teamsResponse)
-	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling linear oauth token response")
-	}
-
-	return teamsResponse, nil
-}
-
-type LinearCreateIssueResponse struct {
-	Data struct {
-		IssueCreate struct {
-			Issue struct {
-				ID         string `json:"id"`
-				Identifier string `json:"identifier"`
-			} `json:"issue"`
-		} `json:"issueCreate"`
-	} `json:"data"`
-}
-
-func (r *Resolver) CreateLinearIssue(accessToken string, teamID string, title string, description string) (*LinearCreateIssueResponse, error) {
-	requestQuery := `
-	mutation createIssue($teamId: String!, $title: String!, $desc: String!) {
-		issueCreate(input: {teamId: $teamId, title: $title, description: $desc}) {
-			issue {
-				id,
-				identifier
-			}
-		}
-	}
-	`
-
-	type GraphQLVars struct {
-		TeamID string `json:"teamId"`
-		Title  string `json:"title"`
-		Desc   string `json:"desc"`
-	}
-
-	type GraphQLReq struct {
-		Query     string      `json:"query"`
-		Variables GraphQLVars `json:"variables"`
-	}
-
-	req := GraphQLReq{Query: requestQuery, Variables: GraphQLVars{TeamID: teamID, Title: title, Desc: description}}
-
-	requestBytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := r.MakeLinearGraphQLRequest(accessToken, string(requestBytes))
-	if err != nil {
-		return nil, err
-	}
-
-	createIssueRes := &LinearCreateIssueResponse{}
-
-	err = json.Unmarshal(b, createIssueRes)
-	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling linear oauth token response")
-	}
-
-	return createIssueRes, nil
-}
-
-type LinearCreateAttachmentResponse struct {
-	Data struct {
-		AttachmentCreate struct {
-			Attachment struct {
-				ID string `json:"id"`
-			} `json:"Attachment"`
-			Success bool `json:"success"`
-		} `json:"attachmentCreate"`
-	} `json:"data"`
-}
-
-func (r *Resolver) CreateLinearAttachment(accessToken string, issueID string, title string, subtitle string, url string) (*LinearCreateAttachmentResponse, error) {
-	requestQuery := `
-	mutation createAttachment($issueId: String!, $url: String!, $iconUrl: String!, $title: String!, $subtitle: String) {
-		attachmentCreate(input: {issueId: $issueId, url: $url, iconUrl: $iconUrl, title: $title, subtitle: $subtitle}) {
-		  attachment {
-			id
-		  }
-		  success
-		}
-	  }
-	`
-
-	type GraphQLVars struct {
-		IssueID  string `json:"issueId"`
-		Title    string `json:"title"`
-		Subtitle string `json:"subtitle"`
-		Url      string `json:"url"`
-		IconUrl  string `json:"iconUrl"`
-	}
-
-	type GraphQLReq struct {
-		Query     string      `json:"query"`
-		Variables GraphQLVars `json:"variables"`
-	}
-
-	req := GraphQLReq{
-		Query: requestQuery,
-		Variables: GraphQLVars{
-			IssueID:  issueID,
-			Title:    title,
-			Subtitle: subtitle,
-			Url:      url,
-			IconUrl:  fmt.Sprintf("%s/logo_with_gradient_bg.png", os.Getenv("FRONTEND_URI")),
-		},
-	}
-
-	requestBytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := r.MakeLinearGraphQLRequest(accessToken, string(requestBytes))
-	if err != nil {
-		return nil, err
-	}
-
-	createAttachmentRes := &LinearCreateAttachmentResponse{}
-
-	err = json.Unmarshal(b, createAttachmentRes)
-	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling linear oauth token response")
-	}
-
-	if !createAttachmentRes.Data.AttachmentCreate.Success {
-		return nil, e.New("failed to create linear attachment")
-	}
-
-	return createAttachmentRes, nil
-}
-
+// CreateLinearIssueAndAttachment creates an issue in the team, or the team project, of teamId and
+// attaches the comment that it was created from. The first team of the workspace is used when
+// teamId is not set.
 func (r *Resolver) CreateLinearIssueAndAttachment(
 	ctx context.Context,
 	workspace *model.Workspace,
@@ -2404,31 +2247,41 @@ func (r *Resolver) CreateLinearIssueAndAttachment(
 	viewLink string,
 	teamId *string,
 ) error {
-	if teamId == nil {
-		teamRes, err := r.GetLinearTeams(*workspace.LinearAccessToken)
+	client := linear.NewClient(*workspace.LinearAccessToken)
+
+	var teamID, projectID string
+	if teamId != nil {
+		teamID, projectID = linear.SplitProjectID(*teamId)
+	} else {
+		teams, err := client.GetTeams(ctx)
 		if err != nil {
 			return err
 		}
 
-		if len(teamRes.Data.Teams.Nodes) <= 0 {
+		if len(teams) <= 0 {
 			return e.New("no teams to make a linear issue to")
 		}
 
-		teamId = &teamRes.Data.Teams.Nodes[0].ID
+		teamID = teams[0].ID
 	}
 
-	issueRes, err := r.CreateLinearIssue(*workspace.LinearAccessToken, *teamId, issueTitle, issueDescription)
+	issue, err := client.CreateIssue(ctx, &linear.CreateIssueInput{
+		TeamID:      teamID,
+		ProjectID:   projectID,
+		Title:       issueTitle,
+		Description: issueDescription,
+	})
 	if err != nil {
 		return err
 	}
 
-	attachmentRes, err := r.CreateLinearAttachment(*workspace.LinearAccessToken, issueRes.Data.IssueCreate.Issue.ID, commentText, authorName, viewLink)
+	linearAttachment, err := client.CreateAttachment(ctx, issue.ID, commentText, authorName, viewLink)
 	if err != nil {
 		return err
 	}
 
-	attachment.ExternalID = attachmentRes.Data.AttachmentCreate.Attachment.ID
-	attachment.Title = issueRes.Data.IssueCreate.Issue.Identifier
+	attachment.ExternalID = linearAttachment.ID
+	attachment.Title = issue.Identifier
 
 	if err := r.DB.WithContext(ctx).Create(attachment).Error; err != nil {
 		return e.Wrap(err, "error creating external attachment")
@@ -2436,32 +2289,159 @@ func (r *Resolver) CreateLinearIssueAndAttachment(
 	return nil
 }
 
-func (r *Resolver) CreateClickUpTaskAndAttachment(
+// IssueTracker returns the issue tracker of the integration type for the workspace, or
+// issuetracker.ErrNotSupported when the integration is not an issue tracker.
+func (r *Resolver) IssueTracker(ctx context.Context, workspace *model.Workspace, integrationType modelInputs.IntegrationType) (issuetracker.IssueTracker, error) {
+	return issuetracker.New(ctx, issuetracker.Deps{Integrations: r.IntegrationsClient, Redis: r.Redis}, workspace, integrationType)
+}
+
+// CreateTrackerIssueAndAttachment creates an issue in the project of the issue tracker of the
+// attachment, with the fields of the task options when the tracker supports them. The issue links
+// back to the Highlight page at url in the trackers that support links.
+func (r *Resolver) CreateTrackerIssueAndAttachment(
 	ctx context.Context,
 	workspace *model.Workspace,
 	attachment *model.ExternalAttachment,
 	issueTitle string,
 	issueDescription string,
-	teamId *string,
+	url string,
+	projectId *string,
+	options *modelInputs.ClickUpTaskInput,
 ) error {
-	// raise an error if the team id is not set
-	if teamId == nil {
-		return e.New("illegal argument: listId is nil")
+	// raise an error if the project id is not set
+	if projectId == nil {
+		return e.New("illegal argument: projectId is nil")
 	}
 
-	task, err := clickup.CreateTask(*workspace.ClickupAccessToken, *teamId, issueTitle, issueDescription)
+	tracker, err := r.IssueTracker(ctx, workspace, attachment.IntegrationType)
 	if err != nil {
 		return err
 	}
 
-	attachment.ExternalID = task.ID
-	attachment.Title = task.Name
+	input := &issuetracker.CreateIssueInput{
+		ProjectID:   *projectId,
+		Title:       issueTitle,
+		Description: issueDescription,
+		URL:         url,
+	}
+	if options != nil {
+		input.Assignees = options.Assignees
+		input.Priority = options.Priority
+		input.Tags = options.Tags
+		input.Status = options.Status
+		input.DueDate = options.DueDate
+		for _, field := range options.CustomFields {
+			input.CustomFields = append(input.CustomFields, &issuetracker.CustomField{ID: field.ID, Value: field.Value})
+		}
+	}
+	issue, err := tracker.CreateIssue(ctx, input)
+	if err != nil {
+		return clickUpError(err)
+	}
+
+	attachment.ExternalID = issue.ID
+	attachment.Title = issue.Title
 	if err := r.DB.WithContext(ctx).Create(attachment).Error; err != nil {
 		return e.Wrap(err, "error creating external attachment")
 	}
 	return nil
 }
 
+// issueMaxStackFrames caps the frames of the stacktrace added to the issues of error groups.
+const issueMaxStackFrames = 20
+
+// AddClickUpErrorContext comments the stacktrace of an error group, the number of sessions it
+// affected and a link to it on the ClickUp task created for it. When the latest error of the group
+// has a session, a screenshot of the session at the time of the error is attached to the task.
+// The context is added in the background, as it is not needed to create the task.
+func (r *Resolver) AddClickUpErrorContext(workspace *model.Workspace, taskID string, errorGroupID int, errorURL string) {
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		ctx := context.Background()
+		client, err := r.ClickUpClient(ctx, workspace)
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error creating ClickUp client"))
+			return
+		}
+
+		var errorGroup model.ErrorGroup
+		if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{Model: model.Model{ID: errorGroupID}}).Take(&errorGroup).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error querying error group"))
+			return
+		}
+		var affectedSessions int64
+		if err := r.DB.WithContext(ctx).Model(&model.ErrorObject{}).
+			Where(&model.ErrorObject{ErrorGroupID: errorGroupID}).
+			Where("session_id IS NOT NULL").
+			Distinct("session_id").
+			Count(&affectedSessions).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error counting error group sessions"))
+		}
+
+		stackTrace := errorGroup.StackTrace
+		if errorGroup.MappedStackTrace != nil && *errorGroup.MappedStackTrace != "" {
+			stackTrace = *errorGroup.MappedStackTrace
+		}
+		blocks := []*clickup.CommentBlock{
+			{Text: fmt.Sprintf("%s\n\n%s\n\n", errorGroup.Event, r.formatIssueStackTrace(stackTrace))},
+			{Text: fmt.Sprintf("Affected sessions: %d\n\n", affectedSessions)},
+			{Text: "View the error on Highlight", Link: errorURL},
+		}
+		if err := client.AddComment(ctx, taskID, blocks); err != nil {
+			log.WithContext(ctx).WithField("task_id", taskID).Error(e.Wrap(err, "error commenting ClickUp task"))
+		}
+
+		var errorObject model.ErrorObject
+		if err := r.DB.WithContext(ctx).
+			Where(&model.ErrorObject{ErrorGroupID: errorGroupID}).
+			Where("session_id IS NOT NULL").
+			Order("id DESC").
+			Limit(1).
+			Find(&errorObject).Error; err != nil || errorObject.SessionID == nil {
+			return
+		}
+		var session model.Session
+		if err := r.DB.WithContext(ctx).Where(&model.Session{Model: model.Model{ID: *errorObject.SessionID}}).Take(&session).Error; err != nil {
+			return
+		}
+		chunkIdx, chunkTs := r.GetSessionChunk(ctx, session.ID, int(errorObject.Timestamp.Sub(session.CreatedAt).Milliseconds()))
+		format := model.SessionExportFormatPng
+		screenshot, err := r.LambdaClient.GetSessionScreenshot(ctx, errorGroup.ProjectID, session.ID, pointy.Int(chunkTs), pointy.Int(chunkIdx), &format)
+		if err != nil {
+			log.WithContext(ctx).WithField("session_id", session.ID).Error(e.Wrap(err, "error rendering session screenshot"))
+			return
+		}
+		if err := client.UploadAttachment(ctx, taskID, fmt.Sprintf("session-%s.png", session.SecureID), screenshot.Image); err != nil {
+			log.WithContext(ctx).WithField("task_id", taskID).Error(e.Wrap(err, "error attaching session screenshot to ClickUp task"))
+		}
+	})
+}
+
+// formatIssueStackTrace formats the frames of a stacktrace as lines of an issue description or comment,
+// or returns the stacktrace as is when it is not structured.
+func (r *Resolver) formatIssueStackTrace(stackTrace string) string {
+	frames, _ := r.UnmarshalStackTrace(stackTrace)
+	if len(frames) == 0 {
+		if len(stackTrace) > 4000 {
+			return stackTrace[:4000] + "..."
+		}
+		return stackTrace
+	}
+
+	var lines []string
+	for i, frame := range frames {
+		if i == issueMaxStackFrames {
+			lines = append(lines, fmt.Sprintf("... %d more frames", len(frames)-i))
+			break
+		}
+		line := "at " + lo.FromPtr(frame.FunctionName)
+		if frame.FileName != nil {
+			line += fmt.Sprintf(" (%s:%d:%d)", *frame.FileName, lo.FromPtr(frame.LineNumber), lo.FromPtr(frame.ColumnNumber))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (r *Resolver) CreateHeightTaskAndAttachment(
 	ctx context.Context,
 	workspace *model.Workspace,
@@ -2492,6 +2472,8 @@ func (r *Resolver) CreateHeightTaskAndAttachment(
 	return nil
 }
 
+// CreateJiraTaskAndAttachment creates an issue with the details of the error in its description,
+// and registers the webhook that syncs the status of the issues of its project.
 func (r *Resolver) CreateJiraTaskAndAttachment(
 	ctx context.Context,
 	workspace *model.Workspace,
@@ -2500,6 +2482,7 @@ func (r *Resolver) CreateJiraTaskAndAttachment(
 	issueDescription string,
 	projectId string,
 	issueTypeId string,
+	details *jira.IssueDetails,
 ) error {
 	accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeJira)
 
@@ -2512,7 +2495,7 @@ func (r *Resolver) CreateJiraTaskAndAttachment(
 	}
 
 	jiraIssuePayload := jira.JiraCreateIssueFields{
-		Description: issueDescription,
+		Description: jira.IssueDescription(issueDescription, details),
 		Summary:     issueTitle,
 		Project:     jira.JiraIssueProjectData{Id: projectId},
 		IssueType:   jira.JiraIssueTypeData{Id: issueTypeId},
@@ -2533,9 +2516,80 @@ func (r *Resolver) CreateJiraTaskAndAttachment(
 	if err := r.DB.WithContext(ctx).Create(attachment).Error; err != nil {
 		return e.Wrap(err, "error creating external attachment")
 	}
+
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		webhookURL := fmt.Sprintf("%s/jira-webhook/%d", os.Getenv("REACT_APP_PRIVATE_GRAPH_URI"), workspace.ID)
+		if err := jira.RegisterJiraWebhook(workspace, *accessToken, webhookURL, jira.JiraProjectKey(task.Key)); err != nil {
+			log.WithContext(ctx).WithField("workspace_id", workspace.ID).Warn(e.Wrap(err, "error registering Jira webhook"))
+		}
+	})
 	return nil
 }
 
+// errorIssueDetails returns the details of an error group added to the issues created for it, with
+// a link to the latest session that the error occurred in.
+func (r *Resolver) errorIssueDetails(ctx context.Context, errorGroupID int, errorURL string) *jira.IssueDetails {
+	details := &jira.IssueDetails{ErrorURL: errorURL}
+	var errorGroup model.ErrorGroup
+	if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{Model: model.Model{ID: errorGroupID}}).Take(&errorGroup).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying error group"))
+		return details
+	}
+	details.Event = errorGroup.Event
+	details.StackTrace = errorGroup.StackTrace
+	if errorGroup.MappedStackTrace != nil && *errorGroup.MappedStackTrace != "" {
+		details.StackTrace = *errorGroup.MappedStackTrace
+	}
+	details.StackTrace = r.formatIssueStackTrace(details.StackTrace)
+
+	var session model.Session
+	if err := r.DB.WithContext(ctx).Model(&model.Session{}).
+		Where("id = (?)", r.DB.Model(&model.ErrorObject{}).
+			Select("session_id").
+			Where(&model.ErrorObject{ErrorGroupID: errorGroupID}).
+			Where("session_id IS NOT NULL").
+			Order("id DESC").
+			Limit(1)).
+		Find(&session).Error; err == nil && session.SecureID != "" {
+		details.SessionURL = fmt.Sprintf("%s/%d/sessions/%s", FrontendURI, errorGroup.ProjectID, session.SecureID)
+	}
+	return details
+}
+
+// JiraWebhookHandler records the status of the Jira issues of a workspace when they are updated.
+func (r *Resolver) JiraWebhookHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	workspaceID, err := strconv.Atoi(chi.URLParam(req, "workspace_id"))
+	if err != nil {
+		http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+		return
+	}
+	event, err := jira.ParseJiraWebhook(req)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Warn("invalid Jira webhook")
+		http.Error(w, "", http.StatusUnauthorized)
+		return
+	}
+	if event.Issue == nil || event.Issue.Fields.Status == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	workspace, err := r.GetWorkspace(workspaceID)
+	if err != nil || workspace.JiraDomain == nil {
+		http.Error(w, "", http.StatusNotFound)
+		return
+	}
+	externalID := jira.MakeExternalIdForJiraTask(workspace, &jira.JiraIssue{Key: event.Issue.Key})
+	status := event.Issue.Fields.Status
+	if err := r.Store.UpdateExternalIssueStatus(ctx, modelInputs.IntegrationTypeJira, externalID, status.Name, status.Done()); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error updating Jira issue status"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (r *Resolver) CreateGitlabTaskAndAttachment(
 	ctx context.Context,
 	workspace *model.Workspace,
@@ -2573,14 +2627,18 @@ func (r *Resolver) CreateGitlabTaskAndAttachment(
 	return nil
 }
 
+// CreateGitHubTaskAndAttachment creates a labeled issue in the repository, or in the repository
+// mapped to the project when none is passed.
 func (r *Resolver) CreateGitHubTaskAndAttachment(
 	ctx context.Context,
 	workspace *model.Workspace,
+	projectID int,
 	attachment *model.ExternalAttachment,
 	issueTitle string,
 	issueDescription string,
 	repo *string,
 	tags []*modelInputs.SessionCommentTagInput,
+	details *githubissues.IssueDetails,
 ) error {
 	labels := lo.Map(tags, func(t *modelInputs.SessionCommentTagInput, i int) string {
 		return t.Name
@@ -2595,19 +2653,31 @@ func (r *Resolver) CreateGitHubTaskAndAttachment(
 	if accessToken == nil {
 		return errors.New("No GitHub integration access token found.")
 	}
-	var task *github2.Issue
-	if c, err := github.NewClient(ctx, *accessToken, r.Redis); err == nil {
-		task, err = c.CreateIssue(ctx, *repo, &github2.IssueRequest{
-			Title:  pointy.String(issueTitle),
-			Body:   pointy.String(issueDescription),
-			Labels: &labels,
-		})
-		if err != nil {
+
+	var repository string
+	if repo != nil {
+		repository = *repo
+	}
+	if repository == "" {
+		if repository, err = githubissues.ProjectRepository(ctx, r.DB, projectID); err != nil {
 			return err
 		}
-	} else {
+	}
+
+	c, err := github.NewClient(ctx, *accessToken, r.Redis)
+	if err != nil {
 		return e.Wrap(err, "failed to create github client")
 	}
+	task, err := githubissues.CreateIssue(ctx, c, &githubissues.CreateIssueInput{
+		Repository:  repository,
+		Title:       issueTitle,
+		Description: issueDescription,
+		Labels:      labels,
+		Details:     details,
+	})
+	if err != nil {
+		return err
+	}
 
 	attachment.ExternalID = task.GetHTMLURL()
 	attachment.Title = task.GetTitle()
@@ -2617,6 +2687,59 @@ func (r *Resolver) CreateGitHubTaskAndAttachment(
 	return nil
 }
 
+// GitHubWebhookHandler records the status of the GitHub issues linked to errors when they are
+// closed or reopened, commenting links to the errors on the issues closed by a commit.
+func (r *Resolver) GitHubWebhookHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	event, err := githubissues.ParseWebhook(req)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Warn("invalid GitHub webhook")
+		http.Error(w, "", http.StatusUnauthorized)
+		return
+	}
+	if event == nil || (event.GetAction() != "closed" && event.GetAction() != "reopened") {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	issueURL := event.GetIssue().GetHTMLURL()
+	errorGroups, err := r.Store.GetExternalIssueErrorGroups(ctx, modelInputs.IntegrationTypeGitHub, issueURL)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying the error groups of the GitHub issue"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	if len(errorGroups) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err := r.Store.UpdateExternalIssueStatus(ctx, modelInputs.IntegrationTypeGitHub, issueURL, event.GetIssue().GetState(), event.GetAction() == "closed"); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error updating GitHub issue status"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	installationID := strconv.FormatInt(event.GetInstallation().GetID(), 10)
+	backlinks := lo.Map(errorGroups, func(errorGroup *model.ErrorGroup, _ int) *githubissues.Backlink {
+		return &githubissues.Backlink{
+			Title: errorGroup.Event,
+			URL:   fmt.Sprintf("%s/%d/errors/%s", FrontendURI, errorGroup.ProjectID, errorGroup.SecureID),
+		}
+	})
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		ctx := context.Background()
+		c, err := github.NewClient(ctx, installationID, r.Redis)
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "failed to create github client"))
+			return
+		}
+		if _, err := githubissues.CommentClosingCommit(ctx, c, event, backlinks); err != nil {
+			log.WithContext(ctx).WithField("issue_url", issueURL).Warn(err)
+		}
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (r *Resolver) GetGitHubRepos(
 	ctx context.Context,
 	workspace *model.Workspace,
@@ -2676,79 +2799,6 @@ func (r *Resolver) GetGitHubIssueLabels(
 	}), nil
 }
 
-type LinearAccessTokenResponse struct {
-	AccessToken string   `json:"access_token"`
-	TokenType   string   `json:"token_type"`
-	ExpiresIn   int64    `json:"expires_in"`
-	Scope       []string `json:"scope"`
-}
-
-func (r *Resolver) GetLinearAccessToken(code string, redirectURL string, clientID string, clientSecret string) (LinearAccessTokenResponse, error) {
-	client := &http.Client{}
-
-	data := url.Values{}
-	data.Set("code", code)
-	data.Set("client_id", clientID)
-	data.Set("client_secret", clientSecret)
-	data.Set("grant_type", "authorization_code")
-	data.Set("redirect_uri", redirectURL)
-
-	accessTokenResponse := LinearAccessTokenResponse{}
-
-	req, err := http.NewRequest("POST", "https://api.linear.app/oauth/token", strings.NewReader(data.Encode()))
-	if err != nil {
-		return accessTokenResponse, e.Wrap(err, "error creating api request to linear")
-	}
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	res, err := client.Do(req)
-
-	if err != nil {
-		return accessTokenResponse, e.Wrap(err, "error getting response from linear oauth token endpoint")
-	}
-
-	b, err := io.ReadAll(res.Body)
-
-	if res.StatusCode != 200 {
-		return accessTokenResponse, e.New("linear API responded with error; status_code=" + res.Status + "; body=" + string(b))
-	}
-
-	if err != nil {
-		return accessTokenResponse, e.Wrap(err, "error reading response body from linear oauth token endpoint")
-	}
-	err = json.Unmarshal(b, &accessTokenResponse)
-	if err != nil {
-		return accessTokenResponse, e.Wrap(err, "error unmarshaling linear oauth token response")
-	}
-
-	return accessTokenResponse, nil
-}
-
-func (r *Resolver) RevokeLinearAccessToken(accessToken string) error {
-	client := &http.Client{}
-
-	data := url.Values{}
-	data.Set("access_token", accessToken)
-
-	req, err := http.NewRequest("POST", "https://api.linear.app/oauth/revoke", strings.NewReader(data.Encode()))
-	if err != nil {
-		return e.Wrap(err, "error creating api request to linear")
-	}
-
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	res, err := client.Do(req)
-	if err != nil {
-		return e.Wrap(err, "error getting response from linear revoke oauth token endpoint")
-	}
-
-	if res.StatusCode != 200 {
-		return e.New("linear API responded with error; status_code=" + res.Status)
-	}
-
-	return nil
-}
-
 func (r *Resolver) getCommentFollowers(ctx context.Context, followers []*model.CommentFollower) (existingAdmins []int, existingSlackChannelIDs []string) {
 	for _, f := range followers {
 		if f.AdminId > 0 {
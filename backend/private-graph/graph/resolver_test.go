@@ -2,20 +2,36 @@ package graph
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/clickup"
 	"github.com/highlight-run/highlight/backend/integrations"
 	kafka_queue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/private-graph/graph/generated"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	"github.com/highlight-run/highlight/backend/redact"
 	"github.com/highlight-run/highlight/backend/redis"
 	"github.com/highlight-run/highlight/backend/storage"
 	"github.com/highlight-run/highlight/backend/store"
 	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
 
 	pointy "github.com/openlyinc/pointy"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/aws/smithy-go/ptr"
+	"github.com/go-chi/chi"
 	"github.com/highlight-run/workerpool"
 	e "github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -160,6 +176,8 @@ func TestMutationResolver_ChangeAdminRole(t *testing.T) {
 	tests := map[string]struct {
 		currentAdminEmail string
 		currentAdminRole  string
+		updatedAdminRole  string
+		newRole           string
 		updatingSelf      bool
 		errorExpected     bool
 	}{
@@ -181,6 +199,35 @@ func TestMutationResolver_ChangeAdminRole(t *testing.T) {
 			updatingSelf:      false,
 			errorExpected:     false,
 		},
+		"admin updating an owner role": {
+			currentAdminEmail: "zoo@bar.com",
+			currentAdminRole:  "ADMIN",
+			updatedAdminRole:  "OWNER",
+			updatingSelf:      false,
+			errorExpected:     true,
+		},
+		"admin granting the owner role": {
+			currentAdminEmail: "zoo@bar.com",
+			currentAdminRole:  "ADMIN",
+			newRole:           "OWNER",
+			updatingSelf:      false,
+			errorExpected:     true,
+		},
+		"owner updating an owner role": {
+			currentAdminEmail: "zoo@bar.com",
+			currentAdminRole:  "OWNER",
+			updatedAdminRole:  "OWNER",
+			newRole:           "VIEWER",
+			updatingSelf:      false,
+			errorExpected:     false,
+		},
+		"admin granting a role that does not exist": {
+			currentAdminEmail: "zoo@bar.com",
+			currentAdminRole:  "ADMIN",
+			newRole:           "Support",
+			updatingSelf:      false,
+			errorExpected:     true,
+		},
 	}
 	for _, v := range tests {
 		util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
@@ -233,10 +280,14 @@ func TestMutationResolver_ChangeAdminRole(t *testing.T) {
 					t.Fatal(e.Wrap(err, "error inserting admin"))
 				}
 
+				updatedAdminRole := "ADMIN"
+				if v.updatedAdminRole != "" {
+					updatedAdminRole = v.updatedAdminRole
+				}
 				updatedWorkspaceAdmin := model.WorkspaceAdmin{
 					AdminID:     updatedAdmin.ID,
 					WorkspaceID: workspace.ID,
-					Role:        ptr.String("ADMIN"),
+					Role:        ptr.String(updatedAdminRole),
 				}
 
 				if err := DB.Create(&updatedWorkspaceAdmin).Error; err != nil {
@@ -249,7 +300,11 @@ func TestMutationResolver_ChangeAdminRole(t *testing.T) {
 			ctx = context.WithValue(ctx, model.ContextKeys.UID, *currentAdmin.UID)
 			r := &mutationResolver{Resolver: &Resolver{DB: DB, PrivateWorkerPool: workerpool.New(1)}}
 
-			_, err := r.ChangeAdminRole(ctx, workspace.ID, updatedAdmin.ID, "MEMBER")
+			newRole := "MEMBER"
+			if v.newRole != "" {
+				newRole = v.newRole
+			}
+			_, err := r.ChangeAdminRole(ctx, workspace.ID, updatedAdmin.ID, newRole)
 			if v.errorExpected != (err != nil) {
 				t.Fatalf("error result invalid, expected? %t but saw %s", v.errorExpected, err)
 			}
@@ -550,3 +605,1280 @@ func TestGetSlackChannelsFromSlack(t *testing.T) {
 		assert.Greater(t, len(*channels), 0)
 	})
 }
+
+// ensure that the embedded schema of the executable schema contains every type of schema.graphqls
+func TestPrivateGraphSchema(t *testing.T) {
+	source, err := os.ReadFile("schema.graphqls")
+	if err != nil {
+		t.Fatal(e.Wrap(err, "error reading schema"))
+	}
+	expected, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphqls", Input: string(source)})
+	if err != nil {
+		t.Fatal(e.Wrap(err, "error loading schema"))
+	}
+
+	schema := generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}).Schema()
+	for name, def := range expected.Types {
+		loaded, ok := schema.Types[name]
+		if !ok {
+			t.Errorf("type %s is missing from the embedded schema", name)
+			continue
+		}
+		for _, field := range def.Fields {
+			assert.NotNil(t, loaded.Fields.ForName(field.Name), "field %s.%s is missing from the embedded schema", name, field.Name)
+		}
+	}
+	assert.NotNil(t, schema.Subscription.Fields.ForName("alert_state_changed"))
+}
+
+func createSubscriptionProject(t *testing.T, ctx context.Context, r *subscriptionResolver) model.Project {
+	w := model.Workspace{}
+	if err := DB.Create(&w).Error; err != nil {
+		t.Fatal(e.Wrap(err, "error inserting workspace"))
+	}
+	admin, _ := r.getCurrentAdmin(ctx)
+	if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+		t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+	}
+	p := model.Project{WorkspaceID: w.ID}
+	if err := DB.Create(&p).Error; err != nil {
+		t.Fatal(e.Wrap(err, "error inserting project"))
+	}
+	return p
+}
+
+func TestSubscriptionResolver_AlertStateChanged(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r := &subscriptionResolver{Resolver: &Resolver{DB: DB, Redis: redis.NewClient()}}
+		p := createSubscriptionProject(t, ctx, r)
+
+		_, err := r.AlertStateChanged(ctx, p.ID+1)
+		assert.Error(t, err)
+
+		changes, err := r.AlertStateChanged(ctx, p.ID)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error subscribing to alert state changes"))
+		}
+		timestamp := time.Now().Truncate(time.Second)
+		err = r.Redis.PublishAlertStateChange(ctx, redis.AlertStateChange{
+			ProjectID: p.ID,
+			AlertID:   1,
+			AlertType: "ERRORS_ALERT",
+			Title:     "Error alert",
+			State:     redis.AlertStateAlerting,
+			Timestamp: timestamp,
+		})
+		assert.NoError(t, err)
+
+		select {
+		case change := <-changes:
+			assert.Equal(t, p.ID, change.ProjectID)
+			assert.Equal(t, 1, change.AlertID)
+			assert.Equal(t, "ERRORS_ALERT", change.AlertType)
+			assert.Equal(t, "Error alert", change.Title)
+			assert.Equal(t, "ALERTING", change.State)
+			assert.True(t, timestamp.Equal(change.Timestamp))
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for alert state change")
+		}
+
+		cancel()
+		select {
+		case _, ok := <-changes:
+			assert.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription was not closed after the context was cancelled")
+		}
+	})
+}
+
+func TestSubscriptionResolver_ErrorObjectCreated(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r := &subscriptionResolver{Resolver: &Resolver{DB: DB, Redis: redis.NewClient()}}
+		p := createSubscriptionProject(t, ctx, r)
+
+		_, err := r.ErrorObjectCreated(ctx, p.ID+1)
+		assert.Error(t, err)
+
+		errorObjects, err := r.ErrorObjectCreated(ctx, p.ID)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error subscribing to error objects"))
+		}
+		err = r.Redis.Publish(ctx, redis.LiveErrorsChannel(p.ID), model.ErrorObject{ID: 1, ProjectID: p.ID, Event: "boom"})
+		assert.NoError(t, err)
+
+		select {
+		case errorObject := <-errorObjects:
+			assert.Equal(t, 1, errorObject.ID)
+			assert.Equal(t, "boom", errorObject.Event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for error object")
+		}
+	})
+}
+
+func TestSubscriptionResolver_LiveSessionCount(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r := &subscriptionResolver{Resolver: &Resolver{DB: DB, Redis: redis.NewClient(), SubscriptionWorkerPool: workerpool.New(1)}}
+		p := createSubscriptionProject(t, ctx, r)
+
+		_, err := r.LiveSessionCount(ctx, p.ID+1)
+		assert.Error(t, err)
+
+		assert.NoError(t, r.Redis.MarkSessionLive(ctx, p.ID, "abc123"))
+		counts, err := r.LiveSessionCount(ctx, p.ID)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error subscribing to live session count"))
+		}
+
+		select {
+		case count := <-counts:
+			assert.Equal(t, 1, *count)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for live session count")
+		}
+	})
+}
+
+// ensure that mutations of viewers are rejected, whether their workspace is found from a project
+// argument, an input or the object that they change
+func TestRBACValidator_InterceptField(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		workspace := model.Workspace{Name: ptr.String("test1")}
+		if err := DB.Create(&workspace).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		project := model.Project{WorkspaceID: workspace.ID}
+		if err := DB.Create(&project).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+		admins := map[string]model.Admin{}
+		for idx, role := range []string{"VIEWER", "MEMBER"} {
+			admin := model.Admin{
+				Model:         model.Model{ID: idx + 1},
+				UID:           ptr.String("rbac-" + role),
+				Email:         ptr.String(role + "@bar.com"),
+				EmailVerified: ptr.Bool(true),
+			}
+			if err := DB.Create(&admin).Error; err != nil {
+				t.Fatal(e.Wrap(err, "error inserting admin"))
+			}
+			if err := DB.Create(&model.WorkspaceAdmin{AdminID: admin.ID, WorkspaceID: workspace.ID, Role: ptr.String(role)}).Error; err != nil {
+				t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+			}
+			admins[role] = admin
+		}
+		errorGroup := model.ErrorGroup{ProjectID: project.ID, SecureID: "rbac-error-group"}
+		if err := DB.Create(&errorGroup).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error group"))
+		}
+		session := model.Session{ProjectID: project.ID, SecureID: "rbac-session"}
+		if err := DB.Create(&session).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting session"))
+		}
+		errorComment := model.ErrorComment{ProjectID: project.ID, ErrorId: errorGroup.ID}
+		if err := DB.Create(&errorComment).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error comment"))
+		}
+
+		errorObject := model.ErrorObject{ProjectID: project.ID, ErrorGroupID: errorGroup.ID}
+		if err := DB.Create(&errorObject).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error object"))
+		}
+
+		mutations := map[string]map[string]interface{}{
+			"createSessionAlert":       {"input": modelInputs.SessionAlertInput{ProjectID: project.ID}},
+			"createLogAlert":           {"input": modelInputs.LogAlertInput{ProjectID: project.ID}},
+			"updateErrorGroupState":    {"secure_id": errorGroup.SecureID},
+			"updateSessionIsPublic":    {"session_secure_id": session.SecureID},
+			"updateErrorGroupIsPublic": {"error_group_secure_id": errorGroup.SecureID},
+			"deleteErrorComment":       {"id": errorComment.ID},
+			"testErrorEnhancement":     {"error_object_id": errorObject.ID},
+		}
+
+		r := &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}
+		validator := NewGraphqlRBACValidator(r)
+		intercept := func(role string, mutation string, args map[string]interface{}) (bool, error) {
+			ctx := context.WithValue(context.Background(), model.ContextKeys.UID, *admins[role].UID)
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Object: "Mutation",
+				Field:  graphql.CollectedField{Field: &ast.Field{Name: mutation}},
+				Args:   args,
+			})
+			called := false
+			_, err := validator.InterceptField(ctx, func(ctx context.Context) (interface{}, error) {
+				called = true
+				return true, nil
+			})
+			return called, err
+		}
+
+		for mutation, args := range mutations {
+			called, err := intercept("VIEWER", mutation, args)
+			assert.ErrorIs(t, err, AuthorizationError, mutation)
+			assert.False(t, called, mutation)
+
+			called, err = intercept("MEMBER", mutation, args)
+			assert.NoError(t, err, mutation)
+			assert.True(t, called, mutation)
+		}
+
+		// mutations whose workspace cannot be found are rejected
+		called, err := intercept("MEMBER", "updateErrorGroupState", map[string]interface{}{"secure_id": "missing"})
+		assert.ErrorIs(t, err, AuthorizationError)
+		assert.False(t, called)
+	})
+}
+
+// ensure that the workspace of every mutation that needs a permission can be found from its
+// arguments, as the rbac validator rejects the mutations whose workspace cannot be found
+func TestRBACValidator_MutationWorkspaces(t *testing.T) {
+	schema := generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}).Schema()
+	for _, mutation := range rbac.Mutations() {
+		field := schema.Mutation.Fields.ForName(mutation)
+		if field == nil {
+			t.Errorf("mutation %s is not in the schema", mutation)
+			continue
+		}
+		arguments := []string{"workspace_id", "project_id"}
+		if argument, ok := mutationWorkspaceIDArguments[mutation]; ok {
+			arguments = append(arguments, argument)
+		}
+		if argument, ok := mutationProjectIDArguments[mutation]; ok {
+			arguments = append(arguments, argument)
+		}
+		if object, ok := mutationObjectArguments[mutation]; ok {
+			arguments = append(arguments, object.argument)
+		}
+		found := false
+		for _, argument := range arguments {
+			found = found || field.Arguments.ForName(argument) != nil
+		}
+		// inputProjectID finds the project of the alert inputs
+		if input := field.Arguments.ForName("input"); input != nil {
+			found = found || input.Type.Name() == "SessionAlertInput" || input.Type.Name() == "LogAlertInput"
+		}
+		assert.True(t, found, "the workspace of %s cannot be found from its arguments", mutation)
+	}
+}
+
+// ensure that the private REST handlers refuse viewers the permissions they do not have
+func TestResolver_authorizeProjectRequest(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		workspace := model.Workspace{Name: ptr.String("test1")}
+		if err := DB.Create(&workspace).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		project := model.Project{WorkspaceID: workspace.ID}
+		if err := DB.Create(&project).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+		viewer := model.Admin{UID: ptr.String("rest-viewer"), Email: ptr.String("viewer@bar.com"), EmailVerified: ptr.Bool(true)}
+		if err := DB.Create(&viewer).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting admin"))
+		}
+		if err := DB.Create(&model.WorkspaceAdmin{AdminID: viewer.ID, WorkspaceID: workspace.ID, Role: ptr.String("VIEWER")}).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+
+		r := &Resolver{DB: DB}
+		request := func(method string) *http.Request {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add(projectIdUrlParam, strconv.Itoa(project.ID))
+			ctx := context.WithValue(context.Background(), model.ContextKeys.UID, *viewer.UID)
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+			return httptest.NewRequest(method, "/", strings.NewReader("{}")).WithContext(ctx)
+		}
+
+		w := httptest.NewRecorder()
+		p, ok := r.authorizeProjectRequest(w, request(http.MethodGet), rbac.PermissionView)
+		assert.True(t, ok)
+		assert.Equal(t, project.ID, p.ID)
+
+		w = httptest.NewRecorder()
+		_, ok = r.authorizeProjectRequest(w, request(http.MethodPut), rbac.PermissionManageIntegrations)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestResolver_authorizeSlackInteractionAdmin(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		workspace := model.Workspace{Name: ptr.String("test1")}
+		if err := DB.Create(&workspace).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		r := &Resolver{DB: DB}
+		for role, authorized := range map[string]bool{rbac.RoleMember: true, rbac.RoleViewer: false} {
+			admin := model.Admin{UID: ptr.String("slack-" + role), Email: ptr.String(role + "@bar.com"), EmailVerified: ptr.Bool(true)}
+			if err := DB.Create(&admin).Error; err != nil {
+				t.Fatal(e.Wrap(err, "error inserting admin"))
+			}
+			if err := DB.Create(&model.WorkspaceAdmin{AdminID: admin.ID, WorkspaceID: workspace.ID, Role: ptr.String(role)}).Error; err != nil {
+				t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+			}
+
+			_, err := r.authorizeSlackInteractionAdmin(context.Background(), workspace.ID, &admin)
+			assert.Equal(t, authorized, err == nil, role)
+		}
+	})
+}
+
+func TestMutationResolver_APITokens(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		workspace := model.Workspace{Name: ptr.String("test1")}
+		if err := DB.Create(&workspace).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		adminContexts := map[string]context.Context{}
+		for _, role := range []string{rbac.RoleMember, rbac.RoleViewer} {
+			admin := model.Admin{UID: ptr.String("api-token-" + role), Email: ptr.String(role + "@bar.com"), EmailVerified: ptr.Bool(true)}
+			if err := DB.Create(&admin).Error; err != nil {
+				t.Fatal(e.Wrap(err, "error inserting admin"))
+			}
+			if err := DB.Create(&model.WorkspaceAdmin{AdminID: admin.ID, WorkspaceID: workspace.ID, Role: ptr.String(role)}).Error; err != nil {
+				t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+			}
+			adminContexts[role] = context.WithValue(context.Background(), model.ContextKeys.UID, *admin.UID)
+		}
+		member, viewer := adminContexts[rbac.RoleMember], adminContexts[rbac.RoleViewer]
+		r := &mutationResolver{Resolver: &Resolver{DB: DB}}
+		read := modelInputs.APITokenInput{Name: "read", Scopes: []string{"errors:read"}}
+		write := modelInputs.APITokenInput{Name: "write", Scopes: []string{"errors:write"}}
+
+		// viewers cannot create tokens that write
+		_, err := r.CreateAPIToken(viewer, nil, write)
+		assert.Error(t, err)
+		_, err = r.CreateAPIToken(viewer, &workspace.ID, read)
+		assert.Error(t, err)
+		created, err := r.CreateAPIToken(viewer, nil, read)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error creating api token"))
+		}
+		assert.True(t, strings.HasPrefix(created.Token, created.APIToken.Prefix))
+		_, err = r.CreateAPIToken(member, nil, write)
+		assert.NoError(t, err)
+
+		// personal tokens can only be managed by their admin
+		_, err = r.RevokeAPIToken(member, created.APIToken.ID)
+		assert.Error(t, err)
+		revoked, err := r.RevokeAPIToken(viewer, created.APIToken.ID)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		apiTokens, err := (&queryResolver{Resolver: r.Resolver}).APITokens(viewer, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, apiTokens)
+	})
+}
+
+func TestMutationResolver_MergeErrorGroups(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &mutationResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		projects := []*model.Project{{WorkspaceID: w.ID}, {WorkspaceID: w.ID}}
+		if err := DB.Create(&projects).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting projects"))
+		}
+		errorGroups := []*model.ErrorGroup{
+			{ProjectID: projects[0].ID, SecureID: "target", Event: "target"},
+			{ProjectID: projects[0].ID, SecureID: "source", Event: "source"},
+			{ProjectID: projects[1].ID, SecureID: "other", Event: "other"},
+		}
+		if err := DB.Create(&errorGroups).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error groups"))
+		}
+
+		// error groups of other projects cannot be merged
+		_, err := r.MergeErrorGroups(ctx, projects[0].ID, "target", []string{"other"})
+		assert.Error(t, err)
+		_, err = r.MergeErrorGroups(ctx, projects[0].ID, "target", nil)
+		assert.Error(t, err)
+
+		merged, err := r.MergeErrorGroups(ctx, projects[0].ID, "target", []string{"source"})
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error merging error groups"))
+		}
+		assert.Equal(t, errorGroups[0].ID, merged.ID)
+		var source model.ErrorGroup
+		if err := DB.Where(&model.ErrorGroup{SecureID: "source"}).Take(&source).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error querying error group"))
+		}
+		assert.Equal(t, &merged.ID, source.MergedIntoID)
+	})
+}
+
+func TestResolver_Webhooks(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &mutationResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		_, err := r.UpdateWebhookSettings(ctx, p.ID, webhook.MaxRetries+1)
+		assert.Error(t, err)
+		settings, err := r.UpdateWebhookSettings(ctx, p.ID, 2)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error updating webhook settings"))
+		}
+		assert.Equal(t, 2, settings.MaxRetries)
+		settings, err = r.RotateWebhookSigningSecret(ctx, p.ID)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error rotating webhook signing secret"))
+		}
+		assert.True(t, strings.HasPrefix(*settings.SigningSecret, "whsec_"))
+
+		deliveries := []*model.WebhookDelivery{
+			{ProjectID: p.ID, EventType: "ERROR_ALERT"},
+			{ProjectID: p.ID, EventType: "LOG_ALERT"},
+			{ProjectID: p.ID + 1, EventType: "ERROR_ALERT"},
+		}
+		if err := DB.Create(&deliveries).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting webhook deliveries"))
+		}
+		q := &queryResolver{Resolver: r.Resolver}
+		results, err := q.WebhookDeliveries(ctx, p.ID, nil, ptr.String("ERROR_ALERT"), nil)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		_, err = q.WebhookDeliveries(ctx, p.ID, nil, nil, ptr.Int(maxWebhookDeliveriesLimit+1))
+		assert.Error(t, err)
+	})
+}
+
+func TestMutationResolver_LinkExternalIssue(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &mutationResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+		errorGroup := model.ErrorGroup{ProjectID: p.ID, SecureID: "error", Event: "error"}
+		if err := DB.Create(&errorGroup).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error group"))
+		}
+
+		_, err := r.LinkExternalIssue(ctx, p.ID, "missing", modelInputs.IntegrationTypeHeight, "task-1", nil)
+		assert.Error(t, err)
+
+		issue, err := r.LinkExternalIssue(ctx, p.ID, errorGroup.SecureID, modelInputs.IntegrationTypeHeight, "task-1", ptr.String("Task"))
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error linking external issue"))
+		}
+		assert.Equal(t, "task-1", issue.ExternalID)
+		assert.Equal(t, "Task", issue.Title)
+		assert.Equal(t, &admin.ID, issue.AdminID)
+
+		// linking the same issue again is a no-op, but an error group has one issue per tracker
+		_, err = r.LinkExternalIssue(ctx, p.ID, errorGroup.SecureID, modelInputs.IntegrationTypeHeight, "task-1", ptr.String("Task"))
+		assert.NoError(t, err)
+		_, err = r.LinkExternalIssue(ctx, p.ID, errorGroup.SecureID, modelInputs.IntegrationTypeHeight, "task-2", ptr.String("Other"))
+		assert.Error(t, err)
+
+		q := &queryResolver{Resolver: r.Resolver}
+		issues, err := q.ExternalIssues(ctx, p.ID, errorGroup.SecureID)
+		assert.NoError(t, err)
+		assert.Len(t, issues, 1)
+
+		unlinked, err := r.UnlinkExternalIssue(ctx, p.ID, errorGroup.SecureID, modelInputs.IntegrationTypeHeight)
+		assert.NoError(t, err)
+		assert.True(t, unlinked)
+		issues, err = q.ExternalIssues(ctx, p.ID, errorGroup.SecureID)
+		assert.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+}
+
+func TestQueryResolver_LinearProjects(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &queryResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		// the projects of workspaces the admin is not a member of cannot be read
+		_, err := r.LinearProjects(ctx, p.ID, "team")
+		assert.Error(t, err)
+
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		_, err = r.LinearProjects(ctx, p.ID, "team")
+		assert.ErrorContains(t, err, "does not have a Linear access token")
+	})
+}
+
+func TestQueryResolver_Jira(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &queryResolver{Resolver: &Resolver{DB: DB, IntegrationsClient: integrations.NewIntegrationsClient(DB), Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		// the Jira sites of workspaces the admin is not a member of cannot be read
+		_, err := r.JiraSites(ctx, p.ID)
+		assert.Error(t, err)
+
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		_, err = r.JiraSites(ctx, p.ID)
+		assert.ErrorContains(t, err, "does not have a Jira integration")
+		_, err = r.JiraIssueTypes(ctx, p.ID, "10000")
+		assert.ErrorContains(t, err, "does not have a Jira integration")
+	})
+}
+
+func TestQueryResolver_ClickUpLists(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &queryResolver{Resolver: &Resolver{DB: DB, IntegrationsClient: integrations.NewIntegrationsClient(DB), Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		// the lists of workspaces the admin is not a member of cannot be read
+		_, err := r.ClickupListMembers(ctx, p.ID, "list")
+		assert.Error(t, err)
+
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		// the workspace is not connected to ClickUp
+		_, err = r.ClickupListMembers(ctx, p.ID, "list")
+		assert.Error(t, err)
+		_, err = r.ClickupListFields(ctx, p.ID, "list")
+		assert.Error(t, err)
+	})
+}
+
+func TestMutationResolver_IngestFilterRules(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &mutationResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		input := modelInputs.IngestFilterRuleInput{
+			Name:        "health checks",
+			Action:      modelInputs.IngestFilterRuleActionDrop,
+			ServiceName: ptr.String("api"),
+			Severity:    ptr.String("DEBUG"),
+		}
+		rule, err := r.CreateIngestFilterRule(ctx, p.ID, input)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error creating ingest filter rule"))
+		}
+		assert.Equal(t, "debug", *rule.Severity)
+		assert.Equal(t, admin.ID, rule.LastAdminToEditID)
+
+		_, err = r.CreateIngestFilterRule(ctx, p.ID, modelInputs.IngestFilterRuleInput{Name: "no conditions", Action: modelInputs.IngestFilterRuleActionDrop})
+		assert.Error(t, err)
+		_, err = r.CreateIngestFilterRule(ctx, p.ID, modelInputs.IngestFilterRuleInput{Name: "invalid regex", Action: modelInputs.IngestFilterRuleActionDrop, AttributeKey: ptr.String("http.target"), AttributeRegex: ptr.String("(")})
+		assert.Error(t, err)
+		_, err = r.CreateIngestFilterRule(ctx, p.ID+1, input)
+		assert.Error(t, err)
+
+		input.Action = modelInputs.IngestFilterRuleActionKeep
+		input.Disabled = true
+		rule, err = r.UpdateIngestFilterRule(ctx, p.ID, rule.ID, input)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error updating ingest filter rule"))
+		}
+		assert.Equal(t, modelInputs.IngestFilterRuleActionKeep, rule.Action)
+
+		rules, err := (&queryResolver{Resolver: r.Resolver}).IngestFilterRules(ctx, p.ID)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error querying ingest filter rules"))
+		}
+		assert.Len(t, rules, 1)
+		assert.True(t, rules[0].Disabled)
+
+		deleted, err := r.DeleteIngestFilterRule(ctx, p.ID, rule.ID)
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+		_, err = r.UpdateIngestFilterRule(ctx, p.ID, rule.ID, input)
+		assert.Error(t, err)
+	})
+}
+
+func TestQueryResolver_ProjectSdks(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &queryResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		for _, version := range []string{"1.19.0", "1.20.0"} {
+			if err := r.Store.RecordProjectSDK(ctx, p.ID, "opentelemetry", version); err != nil {
+				t.Fatal(e.Wrap(err, "error recording project sdk"))
+			}
+		}
+
+		sdks, err := r.ProjectSdks(ctx, p.ID)
+		if err != nil {
+			t.Fatal(e.Wrap(err, "error querying project sdks"))
+		}
+		assert.Len(t, sdks, 2)
+		assert.Equal(t, "1.20.0", sdks[0].Version)
+
+		_, err = r.ProjectSdks(ctx, p.ID+1)
+		assert.Error(t, err)
+	})
+}
+
+func TestQueryResolver_ErrorObjectTraceLogs(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		r := &queryResolver{Resolver: &Resolver{DB: DB, Store: store.NewStore(DB, redis.NewClient(), integrations.NewIntegrationsClient(DB), &storage.FilesystemClient{}, &kafka_queue.MockMessageQueue{}, nil)}}
+		w := model.Workspace{}
+		if err := DB.Create(&w).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace"))
+		}
+		admin, _ := r.getCurrentAdmin(ctx)
+		if err := DB.Model(&w).Association("Admins").Append(admin); err != nil {
+			t.Fatal(e.Wrap(err, "error inserting workspace admin"))
+		}
+		p := model.Project{WorkspaceID: w.ID}
+		if err := DB.Create(&p).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+		otherProject := model.Project{}
+		if err := DB.Create(&otherProject).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting project"))
+		}
+
+		errorGroup := model.ErrorGroup{ProjectID: p.ID, SecureID: "trace-logs"}
+		otherErrorGroup := model.ErrorGroup{ProjectID: otherProject.ID, SecureID: "other-trace-logs"}
+		if err := DB.Create(&[]*model.ErrorGroup{&errorGroup, &otherErrorGroup}).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error groups"))
+		}
+		errorObject := model.ErrorObject{ProjectID: p.ID, ErrorGroupID: errorGroup.ID, Event: "error without a trace", Timestamp: time.Now()}
+		otherErrorObject := model.ErrorObject{ProjectID: otherProject.ID, ErrorGroupID: otherErrorGroup.ID, Event: "error of another project", TraceID: ptr.String("abc"), Timestamp: time.Now()}
+		if err := DB.Create(&[]*model.ErrorObject{&errorObject, &otherErrorObject}).Error; err != nil {
+			t.Fatal(e.Wrap(err, "error inserting error objects"))
+		}
+
+		// errors without a trace have no trace logs
+		traceLogs, err := r.ErrorObjectTraceLogs(ctx, errorObject.ID, nil, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, traceLogs)
+
+		_, err = r.ErrorObjectTraceLogs(ctx, otherErrorObject.ID, nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetTraceLogsURL(t *testing.T) {
+	dateRange := modelInputs.DateRangeRequiredInput{
+		StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, FrontendURI+"/1/logs?query=trace_id%3A%22abc%22&start_date=2023-01-01T00%3A00%3A00.000Z&end_date=2023-01-02T00%3A00%3A00.000Z", getTraceLogsURL(1, "abc", dateRange))
+}
+
+func TestValidateErrorGroupTrendsParams(t *testing.T) {
+	dateRange := &modelInputs.DateRangeRequiredInput{
+		StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	assert.NoError(t, validateErrorGroupTrendsParams(modelInputs.ErrorGroupFrequenciesParamsInput{DateRange: dateRange, ResolutionMinutes: 60}))
+	assert.Error(t, validateErrorGroupTrendsParams(modelInputs.ErrorGroupFrequenciesParamsInput{DateRange: dateRange, ResolutionMinutes: 0}))
+	assert.Error(t, validateErrorGroupTrendsParams(modelInputs.ErrorGroupFrequenciesParamsInput{ResolutionMinutes: 60}))
+	// a minute resolution over a day has more buckets than the maximum
+	assert.Error(t, validateErrorGroupTrendsParams(modelInputs.ErrorGroupFrequenciesParamsInput{DateRange: dateRange, ResolutionMinutes: 1}))
+}
+
+func TestGetErrorAlertDestinations(t *testing.T) {
+	errorAlert := &model.ErrorAlert{}
+	pagerDuty, opsgenie, splunkOnCall, err := getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		PagerDuty:    []*modelInputs.PagerDutyDestinationInput{{RoutingKey: " key "}},
+		Opsgenie:     []*modelInputs.OpsgenieDestinationInput{{APIKey: ptr.String("key"), Region: "eu", Severity: ptr.String("warning")}},
+		SplunkOnCall: []*modelInputs.SplunkOnCallDestinationInput{},
+	}, errorAlert)
+	assert.NoError(t, err)
+	assert.Equal(t, model.PagerDutyDestinations{{RoutingKey: "key", Severity: "error"}}, pagerDuty)
+	assert.Equal(t, model.OpsgenieDestinations{{APIKey: "key", Region: "eu", Severity: "warning"}}, opsgenie)
+	assert.Equal(t, model.SplunkOnCallDestinations{}, splunkOnCall)
+
+	_, _, _, err = getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		PagerDuty: []*modelInputs.PagerDutyDestinationInput{{RoutingKey: "key", Severity: ptr.String("fatal")}},
+	}, errorAlert)
+	assert.Error(t, err)
+	_, _, _, err = getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		Opsgenie: []*modelInputs.OpsgenieDestinationInput{{APIKey: ptr.String("key"), Region: "asia"}},
+	}, errorAlert)
+	assert.Error(t, err)
+	_, _, _, err = getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		SplunkOnCall: []*modelInputs.SplunkOnCallDestinationInput{{APIKey: ptr.String("key")}},
+	}, errorAlert)
+	assert.Error(t, err)
+	_, _, _, err = getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		Opsgenie: []*modelInputs.OpsgenieDestinationInput{{Region: "eu"}},
+	}, errorAlert)
+	assert.Error(t, err)
+
+	// empty api keys keep the keys of the current destinations
+	errorAlert.OpsgenieDestinations = model.OpsgenieDestinations{{APIKey: "opsgenie", Region: "us"}}
+	errorAlert.SplunkOnCallDestinations = model.SplunkOnCallDestinations{{APIKey: "splunk", RoutingKey: "team"}}
+	_, opsgenie, splunkOnCall, err = getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		Opsgenie:     []*modelInputs.OpsgenieDestinationInput{{Region: "eu"}},
+		SplunkOnCall: []*modelInputs.SplunkOnCallDestinationInput{{APIKey: ptr.String(""), RoutingKey: "team"}},
+	}, errorAlert)
+	assert.NoError(t, err)
+	assert.Equal(t, "opsgenie", opsgenie[0].APIKey)
+	assert.Equal(t, "eu", opsgenie[0].Region)
+	assert.Equal(t, "splunk", splunkOnCall[0].APIKey)
+	_, _, _, err = getErrorAlertDestinations(modelInputs.ErrorAlertDestinationsInput{
+		SplunkOnCall: []*modelInputs.SplunkOnCallDestinationInput{{RoutingKey: "other"}},
+	}, errorAlert)
+	assert.Error(t, err)
+}
+
+func TestClickUpIntegrationStatus(t *testing.T) {
+	teams := []*modelInputs.ClickUpTeam{{ID: "1", Name: "Team"}}
+	assert.Equal(t, &modelInputs.ClickUpIntegrationStatus{
+		Connected: true,
+		User:      &modelInputs.ClickUpUser{ID: 2, Username: "user", Email: "user@example.com"},
+		Teams:     teams,
+	}, clickUpIntegrationStatus(&clickup.IntegrationStatus{
+		Connected: true,
+		User:      &clickup.User{ID: 2, Username: "user", Email: "user@example.com"},
+		Teams:     teams,
+	}))
+
+	assert.Equal(t, &modelInputs.ClickUpIntegrationStatus{
+		ReconnectRequired: true,
+		Teams:             []*modelInputs.ClickUpTeam{},
+		Error:             ptr.String("ClickUp token was revoked"),
+	}, clickUpIntegrationStatus(&clickup.IntegrationStatus{
+		ReconnectRequired: true,
+		Teams:             []*modelInputs.ClickUpTeam{},
+		Error:             "ClickUp token was revoked",
+	}))
+}
+
+func TestClickUpCustomFields(t *testing.T) {
+	assert.Equal(t, []*modelInputs.ClickUpCustomField{
+		{ID: "1", Name: "Notes", Type: "text", Options: []*modelInputs.ClickUpCustomFieldOption{}},
+		{ID: "2", Name: "Severity", Type: "drop_down", Required: true, Options: []*modelInputs.ClickUpCustomFieldOption{
+			{ID: "a", Name: ptr.String("High"), Color: ptr.String("#f00")},
+		}},
+	}, clickUpCustomFields([]*clickup.CustomField{
+		{ID: "1", Name: "Notes", Type: "text"},
+		{ID: "2", Name: "Severity", Type: "drop_down", Required: true, TypeConfig: clickup.CustomFieldTypeConfig{
+			Options: []*clickup.CustomFieldOption{{ID: "a", Name: "High", Color: "#f00"}},
+		}},
+	}))
+}
+
+func TestApplyUptimeMonitorInput(t *testing.T) {
+	monitor := &model.UptimeMonitor{}
+	assert.Error(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api"}, monitor))
+	assert.Error(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api", URL: "https://example.com", Type: ptr.String("DNS")}, monitor))
+	assert.Error(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api", URL: "file:///etc/passwd"}, monitor))
+	assert.Error(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api", URL: "https://example.com", Method: ptr.String("DELETE")}, monitor))
+	assert.Error(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api", URL: "https://example.com", IntervalSeconds: ptr.Int(30), TimeoutSeconds: ptr.Int(30)}, monitor))
+
+	assert.NoError(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api", URL: "https://example.com", MaxLatencyMs: ptr.Int(500)}, monitor))
+	assert.Equal(t, model.UptimeMonitorTypeHTTP, monitor.Type)
+	assert.Equal(t, http.MethodGet, monitor.Method)
+	assert.Equal(t, 60, monitor.IntervalSeconds)
+	assert.Equal(t, 10, monitor.TimeoutSeconds)
+	assert.Equal(t, 2, monitor.FailureThreshold)
+	assert.Equal(t, ptr.Int(500), monitor.MaxLatencyMs)
+	assert.False(t, *monitor.Disabled)
+
+	assert.NoError(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "db", URL: "db.internal:5432", Type: ptr.String(model.UptimeMonitorTypePing), IntervalSeconds: ptr.Int(30), Disabled: ptr.Bool(true)}, monitor))
+	assert.Equal(t, model.UptimeMonitorTypePing, monitor.Type)
+	assert.Equal(t, 30, monitor.IntervalSeconds)
+	assert.Nil(t, monitor.MaxLatencyMs)
+	assert.Equal(t, 10, monitor.TimeoutSeconds)
+	assert.True(t, *monitor.Disabled)
+
+	assert.NoError(t, applyUptimeMonitorInput(modelInputs.UptimeMonitorInput{Name: "api", URL: "http://example.com/health", Method: ptr.String("head"), IntervalSeconds: ptr.Int(5)}, monitor))
+	assert.Equal(t, http.MethodHead, monitor.Method)
+	assert.Equal(t, 4, monitor.TimeoutSeconds)
+}
+
+func TestApplyHeartbeatMonitorInput(t *testing.T) {
+	monitor := &model.HeartbeatMonitor{}
+	assert.Error(t, applyHeartbeatMonitorInput(modelInputs.HeartbeatMonitorInput{Name: "cron"}, monitor))
+	assert.Error(t, applyHeartbeatMonitorInput(modelInputs.HeartbeatMonitorInput{Name: "cron", IntervalSeconds: 60, GraceSeconds: ptr.Int(-1)}, monitor))
+
+	assert.NoError(t, applyHeartbeatMonitorInput(modelInputs.HeartbeatMonitorInput{Name: "cron", IntervalSeconds: 3600, GraceSeconds: ptr.Int(120)}, monitor))
+	assert.Equal(t, "cron", monitor.Name)
+	assert.Equal(t, 3600, monitor.IntervalSeconds)
+	assert.Equal(t, 120, monitor.GraceSeconds)
+	assert.False(t, *monitor.Disabled)
+
+	assert.NoError(t, applyHeartbeatMonitorInput(modelInputs.HeartbeatMonitorInput{Name: "cron", IntervalSeconds: 60, Disabled: ptr.Bool(true)}, monitor))
+	assert.Equal(t, 0, monitor.GraceSeconds)
+	assert.True(t, *monitor.Disabled)
+}
+
+func TestApplyTraceAlertInput(t *testing.T) {
+	r := &Resolver{}
+	alert := &model.TraceAlert{State: model.AlertStateAlerting}
+	assert.Error(t, r.applyTraceAlertInput(modelInputs.TraceAlertInput{Name: "checkout", Threshold: 0}, alert))
+	assert.Error(t, r.applyTraceAlertInput(modelInputs.TraceAlertInput{Name: "checkout", Threshold: 150, Metric: ptr.String(model.TraceAlertMetricErrorRate)}, alert))
+	assert.Error(t, r.applyTraceAlertInput(modelInputs.TraceAlertInput{Name: "checkout", Threshold: 500, WindowMinutes: ptr.Int(120)}, alert))
+
+	assert.NoError(t, r.applyTraceAlertInput(modelInputs.TraceAlertInput{
+		Name:          "checkout",
+		ServiceName:   ptr.String("api"),
+		Threshold:     500,
+		SlackChannels: []*modelInputs.SanitizedSlackChannelInput{{WebhookChannelName: ptr.String("#alerts"), WebhookChannelID: ptr.String("C123")}},
+	}, alert))
+	assert.Equal(t, model.TraceAlertMetricLatency, alert.Metric)
+	assert.Equal(t, modelInputs.MetricAggregatorP95, alert.Aggregator)
+	assert.Equal(t, 5, alert.WindowMinutes)
+	assert.Equal(t, 1, alert.MinSpans)
+	assert.Equal(t, "api", alert.ServiceName)
+	assert.Equal(t, model.AlertStateNormal, alert.State)
+	assert.False(t, *alert.Disabled)
+
+	channels, err := alert.GetChannelsToNotify()
+	assert.NoError(t, err)
+	assert.Len(t, channels, 1)
+	assert.Equal(t, "C123", *channels[0].WebhookChannelID)
+}
+
+func TestApplyEscalationPolicyInput(t *testing.T) {
+	policy := &model.EscalationPolicy{}
+	assert.Error(t, applyEscalationPolicyInput(modelInputs.EscalationPolicyInput{Name: "on call"}, policy))
+	assert.Error(t, applyEscalationPolicyInput(modelInputs.EscalationPolicyInput{Name: "on call", Steps: []*modelInputs.EscalationStepInput{{Type: model.EscalationStepSlack}}}, policy))
+	assert.Error(t, applyEscalationPolicyInput(modelInputs.EscalationPolicyInput{Name: "on call", Steps: []*modelInputs.EscalationStepInput{{Type: model.EscalationStepEmail, Emails: []string{"oncall"}}}}, policy))
+	assert.Error(t, applyEscalationPolicyInput(modelInputs.EscalationPolicyInput{Name: "on call", Steps: []*modelInputs.EscalationStepInput{{Type: model.EscalationStepEmail, Emails: []string{"oncall@example.com"}, DelayMinutes: 2 * 24 * 60}}}, policy))
+
+	assert.NoError(t, applyEscalationPolicyInput(modelInputs.EscalationPolicyInput{Name: "on call", Steps: []*modelInputs.EscalationStepInput{
+		{Type: model.EscalationStepSlack, SlackChannels: []*modelInputs.SanitizedSlackChannelInput{{WebhookChannelName: ptr.String("#incidents"), WebhookChannelID: ptr.String("C123")}}},
+		{Type: model.EscalationStepEmail, DelayMinutes: 15, Emails: []string{" oncall@example.com "}},
+		{Type: model.EscalationStepPagerDuty, DelayMinutes: 30, PagerDuty: &modelInputs.PagerDutyDestinationInput{RoutingKey: " key "}},
+	}}, policy))
+	assert.Equal(t, "on call", policy.Name)
+	assert.Len(t, policy.Steps, 3)
+	assert.Equal(t, "C123", *policy.Steps[0].SlackChannels[0].WebhookChannelID)
+	assert.Equal(t, []string{"oncall@example.com"}, policy.Steps[1].Emails)
+	assert.Equal(t, "key", policy.Steps[2].PagerDuty.RoutingKey)
+	assert.Equal(t, "error", policy.Steps[2].PagerDuty.Severity)
+}
+
+func TestApplyOnCallScheduleInput(t *testing.T) {
+	schedule := &model.OnCallSchedule{}
+	assert.Error(t, applyOnCallScheduleInput(modelInputs.OnCallScheduleInput{Name: "primary"}, schedule))
+	assert.Error(t, applyOnCallScheduleInput(modelInputs.OnCallScheduleInput{Name: "primary", Participants: []string{"alice"}}, schedule))
+
+	startsAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, applyOnCallScheduleInput(modelInputs.OnCallScheduleInput{Name: "primary", Participants: []string{"alice@example.com", "bob@example.com"}, RotationHours: ptr.Int(24), StartsAt: &startsAt}, schedule))
+	assert.Equal(t, 24, schedule.RotationHours)
+	assert.Equal(t, "bob@example.com", schedule.GetOnCall(startsAt.Add(36*time.Hour)))
+
+	assert.NoError(t, applyOnCallScheduleInput(modelInputs.OnCallScheduleInput{Name: "primary", Participants: []string{"alice@example.com"}}, schedule))
+	assert.Equal(t, 7*24, schedule.RotationHours)
+}
+
+func TestApplySSOConfigInput(t *testing.T) {
+	ctx := context.Background()
+	config := &model.WorkspaceSSOConfig{}
+	assert.Error(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{Provider: "google", Protocol: "saml", Domain: "example.com"}, config))
+	assert.Error(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{Provider: "okta", Protocol: "ldap", Domain: "example.com"}, config))
+	assert.Error(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{
+		Provider:   "okta",
+		Protocol:   "saml",
+		Domain:     "example.com",
+		GroupRoles: []*modelInputs.SSOGroupRoleInput{{Group: "engineering", Role: "OWNER"}},
+	}, config))
+	assert.Error(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{Provider: "okta", Protocol: "saml", Domain: "example.com", DefaultRole: ptr.String("OWNER")}, config))
+
+	err := applySSOConfigInput(ctx, modelInputs.SSOConfigInput{
+		Provider:   "okta",
+		Protocol:   "saml",
+		Domain:     " Example.com ",
+		GroupRoles: []*modelInputs.SSOGroupRoleInput{{Group: "engineering", Role: "MEMBER"}, {Group: "admins", Role: "ADMIN"}},
+	}, config)
+	assert.EqualError(t, err, "saml entity id and sso url are required")
+	assert.Equal(t, "example.com", config.Domain)
+
+	output := ssoConfigOutput(config)
+	assert.Equal(t, []*modelInputs.SSOGroupRole{{Group: "admins", Role: "ADMIN"}, {Group: "engineering", Role: "MEMBER"}}, output.GroupRoles)
+	assert.False(t, output.OidcClientSecretSet)
+	assert.False(t, output.DomainVerified)
+	assert.Equal(t, "_highlight-challenge.example.com", output.DomainVerificationRecordName)
+
+	token := config.DomainVerificationToken
+	assert.NotEmpty(t, token)
+
+	// single sign-on cannot be enabled until the domain is verified
+	assert.Error(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{Provider: "okta", Protocol: "saml", Domain: "example.com", Enabled: true}, config))
+	verifiedAt := time.Now()
+	config.DomainVerifiedAt = &verifiedAt
+	assert.EqualError(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{Provider: "okta", Protocol: "saml", Domain: "example.com", Enabled: true}, config), "saml entity id and sso url are required")
+	assert.Equal(t, token, config.DomainVerificationToken)
+	assert.NotNil(t, config.DomainVerifiedAt)
+
+	// a new domain is verified with a new token
+	assert.Error(t, applySSOConfigInput(ctx, modelInputs.SSOConfigInput{Provider: "okta", Protocol: "saml", Domain: "highlight.io"}, config))
+	assert.NotEqual(t, token, config.DomainVerificationToken)
+	assert.Nil(t, config.DomainVerifiedAt)
+}
+
+func TestApplyErrorWorkflowRuleInput(t *testing.T) {
+	rule := &model.ErrorWorkflowRule{}
+	assert.Error(t, applyErrorWorkflowRuleInput(modelInputs.ErrorWorkflowRuleInput{Action: "ignore"}, rule))
+	assert.Error(t, applyErrorWorkflowRuleInput(modelInputs.ErrorWorkflowRuleInput{Name: "stale", Action: "resolve"}, rule))
+
+	assert.NoError(t, applyErrorWorkflowRuleInput(modelInputs.ErrorWorkflowRuleInput{Name: "stale", Action: "resolve", StaleDays: ptr.Int(14)}, rule))
+	assert.Equal(t, model.ErrorWorkflowRuleActionResolve, rule.Action)
+	assert.Equal(t, ptr.Int(14), rule.StaleDays)
+	assert.False(t, rule.Disabled)
+
+	assert.NoError(t, applyErrorWorkflowRuleInput(modelInputs.ErrorWorkflowRuleInput{Name: "flaky", Action: "snooze", EventRegex: ptr.String("^Timeout"), Disabled: ptr.Bool(true)}, rule))
+	assert.Equal(t, model.ErrorWorkflowRuleActionSnooze, rule.Action)
+	assert.Nil(t, rule.StaleDays)
+	assert.True(t, rule.Disabled)
+}
+
+func TestApplyErrorOwnershipRuleInput(t *testing.T) {
+	rule := &model.ErrorOwnershipRule{}
+	assert.Error(t, applyErrorOwnershipRuleInput(modelInputs.ErrorOwnershipRuleInput{Pattern: ptr.String("*.go"), AssigneeID: 1}, rule))
+	assert.Error(t, applyErrorOwnershipRuleInput(modelInputs.ErrorOwnershipRuleInput{Name: "backend", AssigneeID: 1}, rule))
+	assert.Error(t, applyErrorOwnershipRuleInput(modelInputs.ErrorOwnershipRuleInput{Name: "backend", Pattern: ptr.String("*.go")}, rule))
+
+	assert.NoError(t, applyErrorOwnershipRuleInput(modelInputs.ErrorOwnershipRuleInput{Name: "backend", Pattern: ptr.String("*.go"), AssigneeID: 1, Disabled: ptr.Bool(true)}, rule))
+	assert.Equal(t, 1, rule.AssigneeID)
+	assert.True(t, rule.Disabled)
+}
+
+func TestApplyErrorIgnoreRuleInput(t *testing.T) {
+	rule := &model.ErrorIgnoreRule{}
+	assert.Error(t, applyErrorIgnoreRuleInput(modelInputs.ErrorIgnoreRuleInput{MessageRegex: ptr.String("ResizeObserver")}, rule))
+
+	assert.NoError(t, applyErrorIgnoreRuleInput(modelInputs.ErrorIgnoreRuleInput{Name: "resize observer", MessageRegex: ptr.String("ResizeObserver")}, rule))
+	assert.Equal(t, ptr.String("ResizeObserver"), rule.MessageRegex)
+	assert.False(t, rule.Disabled)
+
+	assert.NoError(t, applyErrorIgnoreRuleInput(modelInputs.ErrorIgnoreRuleInput{Name: "extensions", SourceRegex: ptr.String("^chrome-extension://"), Disabled: ptr.Bool(true)}, rule))
+	assert.Nil(t, rule.MessageRegex)
+	assert.True(t, rule.Disabled)
+}
+
+func TestApplyErrorGroupingRuleInput(t *testing.T) {
+	rule := &model.ErrorGroupingRule{}
+	assert.Error(t, applyErrorGroupingRuleInput(modelInputs.ErrorGroupingRuleInput{Type: "top_frame"}, rule))
+	assert.Error(t, applyErrorGroupingRuleInput(modelInputs.ErrorGroupingRuleInput{Name: "by file", Type: "file"}, rule))
+
+	assert.NoError(t, applyErrorGroupingRuleInput(modelInputs.ErrorGroupingRuleInput{Name: "by frame", Type: "top_frame", ServiceName: ptr.String("api")}, rule))
+	assert.Equal(t, model.ErrorGroupingRuleTypeTopFrame, rule.Type)
+	assert.Equal(t, ptr.String("api"), rule.ServiceName)
+	assert.False(t, rule.Disabled)
+}
+
+func TestRedactionRules(t *testing.T) {
+	rules := redactionRules(&model.ProjectFilterSettings{})
+	assert.Equal(t, []string{}, rules.Keys)
+	assert.Equal(t, []string{}, rules.Patterns)
+	assert.Len(t, rules.Presets, len(redact.Presets))
+	for i := 1; i < len(rules.Presets); i++ {
+		assert.Less(t, rules.Presets[i-1].Name, rules.Presets[i].Name)
+	}
+
+	rules = redactionRules(&model.ProjectFilterSettings{RedactedAttributeKeys: []string{"authorization"}, RedactionPatterns: []string{"email"}})
+	assert.Equal(t, []string{"authorization"}, rules.Keys)
+	assert.Equal(t, []string{"email"}, rules.Patterns)
+}
+
+func TestParseExcludedLogLevels(t *testing.T) {
+	levels, err := parseExcludedLogLevels(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, levels)
+
+	levels, err = parseExcludedLogLevels([]string{"DEBUG", "warning"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"debug", "warn"}, levels)
+
+	_, err = parseExcludedLogLevels([]string{"verbose-ish"})
+	assert.Error(t, err)
+}
+
+func TestValidateErrorPayloadSettingsInput(t *testing.T) {
+	assert.NoError(t, validateErrorPayloadSettingsInput(&modelInputs.ErrorPayloadSettingsInput{SamplingRate: 0}))
+	assert.NoError(t, validateErrorPayloadSettingsInput(&modelInputs.ErrorPayloadSettingsInput{HourlyLimit: ptr.Int64(100), SamplingRate: 0.5, RetentionDays: ptr.Int(7)}))
+	assert.Error(t, validateErrorPayloadSettingsInput(&modelInputs.ErrorPayloadSettingsInput{HourlyLimit: ptr.Int64(-1)}))
+	assert.Error(t, validateErrorPayloadSettingsInput(&modelInputs.ErrorPayloadSettingsInput{SamplingRate: 1.5}))
+	assert.Error(t, validateErrorPayloadSettingsInput(&modelInputs.ErrorPayloadSettingsInput{RetentionDays: ptr.Int(0)}))
+}
+
+func TestNewChaosFaults(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	_, err := newChaosFaults([]*modelInputs.ChaosFaultInput{{Subsystem: "postgres", ErrorPercent: 10, ExpiresAt: expiresAt}})
+	assert.Error(t, err)
+	_, err = newChaosFaults([]*modelInputs.ChaosFaultInput{{Subsystem: "kafka", ErrorPercent: 10, Hosts: []string{"api.github.com"}, ExpiresAt: expiresAt}})
+	assert.Error(t, err)
+
+	faults, err := newChaosFaults([]*modelInputs.ChaosFaultInput{{Subsystem: "integrations", ErrorPercent: 10, LatencyPercent: 50, LatencyMs: 500, Hosts: []string{"api.github.com"}, ExpiresAt: expiresAt}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*modelInputs.ChaosFault{{Subsystem: "integrations", ErrorPercent: 10, LatencyPercent: 50, LatencyMs: 500, Hosts: []string{"api.github.com"}, ExpiresAt: expiresAt}}, chaosFaultsOutput(faults))
+}
+
+func TestGetMicrosoftTeamsChannels(t *testing.T) {
+	_, err := getMicrosoftTeamsChannels([]*modelInputs.MicrosoftTeamsChannelInput{{Name: " ", WebhookURL: "https://example.webhook.office.com/webhookb2/1"}})
+	assert.Error(t, err)
+	_, err = getMicrosoftTeamsChannels([]*modelInputs.MicrosoftTeamsChannelInput{{Name: "alerts", WebhookURL: "http://localhost/webhook"}})
+	assert.Error(t, err)
+
+	channels, err := getMicrosoftTeamsChannels(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, model.MicrosoftTeamsChannels{}, channels)
+
+	channels, err = getMicrosoftTeamsChannels([]*modelInputs.MicrosoftTeamsChannelInput{{Name: " alerts ", WebhookURL: "https://example.webhook.office.com/webhookb2/1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, model.MicrosoftTeamsChannels{{Name: "alerts", WebhookURL: "https://example.webhook.office.com/webhookb2/1"}}, channels)
+}
+
+func TestGetDiscordWebhooks(t *testing.T) {
+	_, err := getDiscordWebhooks([]*modelInputs.DiscordWebhookInput{{Name: "", WebhookURL: "https://discord.com/api/webhooks/1/token"}})
+	assert.Error(t, err)
+	_, err = getDiscordWebhooks([]*modelInputs.DiscordWebhookInput{{Name: "alerts", WebhookURL: "https://example.com/api/webhooks/1/token"}})
+	assert.Error(t, err)
+
+	webhooks, err := getDiscordWebhooks([]*modelInputs.DiscordWebhookInput{{Name: " alerts ", WebhookURL: "https://discord.com/api/webhooks/1/token"}})
+	assert.NoError(t, err)
+	assert.Equal(t, model.DiscordWebhooks{{Name: "alerts", WebhookURL: "https://discord.com/api/webhooks/1/token"}}, webhooks)
+}
+
+func TestApplyDatadogLogForwarderInput(t *testing.T) {
+	forwarder := &model.DatadogLogForwarder{}
+	_, err := applyDatadogLogForwarderInput(modelInputs.DatadogLogForwarderInput{Enabled: true}, forwarder)
+	assert.Error(t, err)
+	_, err = applyDatadogLogForwarderInput(modelInputs.DatadogLogForwarderInput{Site: ptr.String("datadoghq.io"), APIKey: ptr.String("key")}, forwarder)
+	assert.Error(t, err)
+	_, err = applyDatadogLogForwarderInput(modelInputs.DatadogLogForwarderInput{APIKey: ptr.String("key"), AttributeMapping: map[string]interface{}{"level": 1}}, forwarder)
+	assert.Error(t, err)
+
+	validateAPIKey, err := applyDatadogLogForwarderInput(modelInputs.DatadogLogForwarderInput{APIKey: ptr.String(" key "), AttributeMapping: map[string]interface{}{"level": "status"}, Enabled: true}, forwarder)
+	assert.NoError(t, err)
+	assert.True(t, validateAPIKey)
+	assert.Equal(t, "key", forwarder.APIKey)
+	assert.Equal(t, "datadoghq.com", forwarder.Site)
+	assert.Equal(t, model.StringMap{"level": "status"}, forwarder.AttributeMapping)
+
+	validateAPIKey, err = applyDatadogLogForwarderInput(modelInputs.DatadogLogForwarderInput{Site: ptr.String("datadoghq.com")}, forwarder)
+	assert.NoError(t, err)
+	assert.False(t, validateAPIKey)
+	assert.False(t, forwarder.Enabled)
+
+	validateAPIKey, err = applyDatadogLogForwarderInput(modelInputs.DatadogLogForwarderInput{Site: ptr.String("datadoghq.eu")}, forwarder)
+	assert.NoError(t, err)
+	assert.True(t, validateAPIKey)
+}
+
+func TestApplyProductAnalyticsExportInput(t *testing.T) {
+	export := &model.ProductAnalyticsExport{Destination: "amplitude"}
+	assert.Error(t, applyProductAnalyticsExportInput(modelInputs.ProductAnalyticsExportInput{Enabled: true}, export))
+	assert.Error(t, applyProductAnalyticsExportInput(modelInputs.ProductAnalyticsExportInput{Region: ptr.String("apac"), APIKey: ptr.String("key")}, export))
+
+	assert.NoError(t, applyProductAnalyticsExportInput(modelInputs.ProductAnalyticsExportInput{APIKey: ptr.String(" key "), ExportRageClicks: true, Enabled: true}, export))
+	assert.Equal(t, "key", export.APIKey)
+	assert.Equal(t, "us", export.Region)
+	assert.Equal(t, "identifier", export.UserIDProperty)
+	assert.True(t, export.Enabled)
+
+	export.LastSessionID = 10
+	assert.NoError(t, applyProductAnalyticsExportInput(modelInputs.ProductAnalyticsExportInput{Region: ptr.String("eu"), UserIDProperty: ptr.String("email"), Enabled: true}, export))
+	assert.Equal(t, "key", export.APIKey)
+	assert.Equal(t, "email", export.UserIDProperty)
+	assert.Equal(t, 10, export.LastSessionID)
+}
+
+func TestApplyWarehouseExportInput(t *testing.T) {
+	export := &model.WarehouseExport{}
+	assert.Error(t, applyWarehouseExportInput(modelInputs.WarehouseExportInput{Destination: "redshift", StorageAccessKeyID: "id", StorageSecretAccessKey: ptr.String("secret")}, export))
+	assert.Error(t, applyWarehouseExportInput(modelInputs.WarehouseExportInput{Destination: "bigquery", StorageAccessKeyID: "id", StorageSecretAccessKey: ptr.String("secret"), LogSampleRate: ptr.Float64(0)}, export))
+	assert.Error(t, applyWarehouseExportInput(modelInputs.WarehouseExportInput{Destination: "bigquery", StorageAccessKeyID: "id"}, export))
+
+	assert.NoError(t, applyWarehouseExportInput(modelInputs.WarehouseExportInput{Destination: "bigquery", StorageURL: " gs://bucket/prefix ", StorageAccessKeyID: "id", StorageSecretAccessKey: ptr.String("secret"), BigqueryDataset: ptr.String("highlight"), Enabled: true}, export))
+	assert.Equal(t, "gs://bucket/prefix", export.StorageURL)
+	assert.Equal(t, "highlight", export.BigQueryDataset)
+	assert.Equal(t, 1., export.LogSampleRate)
+
+	assert.NoError(t, applyWarehouseExportInput(modelInputs.WarehouseExportInput{Destination: "bigquery", StorageAccessKeyID: "id", LogSampleRate: ptr.Float64(0.5)}, export))
+	assert.Equal(t, "secret", export.StorageSecretAccessKey)
+	assert.Equal(t, 0.5, export.LogSampleRate)
+}
+
+func TestApplyErrorAlertAnomalyDetection(t *testing.T) {
+	errorAlert := &model.ErrorAlert{}
+	assert.NoError(t, applyErrorAlertAnomalyDetection(ptr.Bool(true), nil, errorAlert))
+	assert.True(t, errorAlert.AnomalyDetection)
+	assert.Equal(t, alerts.DefaultAnomalySensitivity, errorAlert.AnomalySensitivity)
+
+	assert.NoError(t, applyErrorAlertAnomalyDetection(nil, ptr.Float64(5), errorAlert))
+	assert.True(t, errorAlert.AnomalyDetection)
+	assert.Equal(t, 5., errorAlert.AnomalySensitivity)
+
+	assert.Error(t, applyErrorAlertAnomalyDetection(ptr.Bool(false), ptr.Float64(11), errorAlert))
+}
+
+func TestNewDigestSetting(t *testing.T) {
+	_, err := newDigestSetting(1, modelInputs.ProjectDigestSettingInput{Frequency: "MONTHLY"})
+	assert.Error(t, err)
+	_, err = newDigestSetting(1, modelInputs.ProjectDigestSettingInput{Frequency: "WEEKLY", Weekday: 7})
+	assert.Error(t, err)
+	_, err = newDigestSetting(1, modelInputs.ProjectDigestSettingInput{Frequency: "DAILY", Hour: 24})
+	assert.Error(t, err)
+
+	setting, err := newDigestSetting(1, modelInputs.ProjectDigestSettingInput{Frequency: "WEEKLY", Weekday: 5, Hour: 9})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, setting.ProjectID)
+	assert.Equal(t, time.Friday, setting.Weekday)
+	assert.Equal(t, 9, setting.Hour)
+	assert.NotNil(t, setting.LastSentAt)
+}
+
+func TestApplySourcemapBucketInput(t *testing.T) {
+	bucket := &model.SourcemapBucket{ProjectID: 1, ServiceAccountKey: ptr.String(`{"type": "service_account"}`)}
+	assert.Error(t, applySourcemapBucketInput(modelInputs.SourcemapBucketInput{Provider: "azure", Bucket: "builds"}, bucket))
+	assert.Error(t, applySourcemapBucketInput(modelInputs.SourcemapBucketInput{Provider: "s3", Bucket: "builds", Region: ptr.String("us-east-2")}, bucket))
+
+	assert.NoError(t, applySourcemapBucketInput(modelInputs.SourcemapBucketInput{Provider: "gcs", Bucket: "builds", Prefix: ptr.String("releases/{version}/"), Disabled: ptr.Bool(true)}, bucket))
+	assert.Equal(t, model.SourcemapBucketProviderGCS, bucket.Provider)
+	assert.Equal(t, "releases/{version}/", bucket.Prefix)
+	assert.Equal(t, `{"type": "service_account"}`, *bucket.ServiceAccountKey)
+	assert.True(t, bucket.Disabled)
+}
+
+func TestResolver_projectIssueTracker(t *testing.T) {
+	r := &Resolver{}
+	_, err := r.projectIssueTracker(context.Background(), &model.Project{}, modelInputs.IntegrationTypeSlack)
+	assert.Error(t, err)
+}
+
+func TestNewWebVitalsParams(t *testing.T) {
+	_, err := newWebVitalsParams(nil, ptr.String("FPS"), nil, nil)
+	assert.Error(t, err)
+
+	params, err := newWebVitalsParams(nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, params.EndDate.Sub(params.StartDate))
+	assert.False(t, params.GroupByURL)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	params, err = newWebVitalsParams(&modelInputs.DateRangeRequiredInput{StartDate: start, EndDate: start.Add(time.Hour)}, ptr.String("LCP"), ptr.String("/"), ptr.Bool(true))
+	assert.NoError(t, err)
+	assert.Equal(t, start, params.StartDate)
+	assert.Equal(t, "LCP", *params.Name)
+	assert.True(t, params.GroupByURL)
+
+	percentiles := webVitalPercentiles([]*clickhouse.WebVitalPercentiles{{Name: "LCP", Count: 2}, {Name: "CLS", URL: "/"}})
+	assert.Nil(t, percentiles[0].URL)
+	assert.Equal(t, "/", *percentiles[1].URL)
+}
+
+func TestNewServiceGraphParams(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := newServiceGraphParams(&modelInputs.DateRangeRequiredInput{StartDate: start, EndDate: start}, nil)
+	assert.Error(t, err)
+
+	params, err := newServiceGraphParams(nil, ptr.String(""))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, params.EndDate.Sub(params.StartDate))
+	assert.Nil(t, params.Environment)
+
+	params, err = newServiceGraphParams(&modelInputs.DateRangeRequiredInput{StartDate: start, EndDate: start.Add(time.Minute)}, ptr.String("production"))
+	assert.NoError(t, err)
+	assert.Equal(t, start, params.StartDate)
+	assert.Equal(t, "production", *params.Environment)
+
+	graph := serviceGraph(&clickhouse.ServiceGraph{Nodes: []*clickhouse.ServiceGraphNode{{Name: "api", Requests: 2}}})
+	assert.Equal(t, "api", graph.Nodes[0].Name)
+	assert.Equal(t, uint64(2), graph.Nodes[0].Requests)
+	assert.Empty(t, graph.Edges)
+}
+
+func TestNewProfilesRange(t *testing.T) {
+	startDate, endDate, err := newProfilesRange(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, endDate.Sub(startDate).Round(time.Second))
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, _, err = newProfilesRange(&modelInputs.DateRangeRequiredInput{StartDate: start, EndDate: start.Add(-time.Hour)})
+	assert.Error(t, err)
+	_, _, err = newProfilesRange(&modelInputs.DateRangeRequiredInput{StartDate: start, EndDate: start.Add(32 * 24 * time.Hour)})
+	assert.Error(t, err)
+
+	startDate, _, err = newProfilesRange(&modelInputs.DateRangeRequiredInput{StartDate: start, EndDate: start.Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, start, startDate)
+}
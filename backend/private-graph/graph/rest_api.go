@@ -0,0 +1,770 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	"github.com/highlight-run/highlight/backend/restapi"
+	"github.com/highlight-run/highlight/backend/stacktraces"
+	"github.com/highlight-run/highlight/backend/store"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+// The handlers in this file are the versioned REST API that customers automate highlight with,
+// authenticated with API tokens. The endpoints are described by restapi.Endpoints, which the
+// OpenAPI spec is generated from.
+const errorGroupSecureIdUrlParam = "error_group_secure_id"
+
+// sourcemapHashHeader is the header of an uploaded file with its hex encoded SHA-256 hash.
+const sourcemapHashHeader = "Content-SHA256"
+
+func writeRESTError(w http.ResponseWriter, req *http.Request, status int, message string) {
+	writeJSONResponse(w, req, status, restapi.Error{Message: message})
+}
+
+// authorizeRESTRequest checks that the request is authenticated with an API token that has the scope.
+func authorizeRESTRequest(w http.ResponseWriter, req *http.Request, scope apitoken.Scope) bool {
+	apiToken := apiTokenFromContext(req.Context())
+	if apiToken == nil {
+		writeRESTError(w, req, http.StatusUnauthorized, "an api token is required")
+		return false
+	}
+	if !apitoken.HasScope(apiToken.Scopes, scope) {
+		writeRESTError(w, req, http.StatusForbidden, fmt.Sprintf("the api token requires the %s scope", scope))
+		return false
+	}
+	return true
+}
+
+// authorizeRESTProjectRequest checks that the API token of the request has the scope and can access
+// the project in the url.
+func (r *Resolver) authorizeRESTProjectRequest(w http.ResponseWriter, req *http.Request, scope apitoken.Scope) (*model.Project, bool) {
+	if !authorizeRESTRequest(w, req, scope) {
+		return nil, false
+	}
+	projectID, err := strconv.Atoi(chi.URLParam(req, projectIdUrlParam))
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, "invalid project_id")
+		return nil, false
+	}
+	project, err := r.isAdminInProject(req.Context(), projectID)
+	if err != nil {
+		writeRESTError(w, req, http.StatusNotFound, "project not found")
+		return nil, false
+	}
+	return project, true
+}
+
+// parseRESTDateRange parses the RFC3339 `start_date` and `end_date` parameters.
+func parseRESTDateRange(req *http.Request) (*modelInputs.DateRangeRequiredInput, error) {
+	dateRange := &modelInputs.DateRangeRequiredInput{EndDate: time.Now()}
+	if value := req.URL.Query().Get("end_date"); value != "" {
+		endDate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, e.Errorf("invalid end_date %q", value)
+		}
+		dateRange.EndDate = endDate
+	}
+	dateRange.StartDate = dateRange.EndDate.Add(-restapi.DefaultLookback)
+	if value := req.URL.Query().Get("start_date"); value != "" {
+		startDate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, e.Errorf("invalid start_date %q", value)
+		}
+		dateRange.StartDate = startDate
+	}
+	if !dateRange.EndDate.After(dateRange.StartDate) {
+		return nil, e.New("end_date must be after start_date")
+	}
+	return dateRange, nil
+}
+
+// parseRESTPage parses the `page` and `count` parameters.
+func parseRESTPage(req *http.Request) (int, int, error) {
+	page, count := 1, restapi.DefaultCount
+	if value := req.URL.Query().Get("page"); value != "" {
+		var err error
+		if page, err = strconv.Atoi(value); err != nil || page <= 0 {
+			return 0, 0, e.Errorf("invalid page %q", value)
+		}
+	}
+	if value := req.URL.Query().Get("count"); value != "" {
+		var err error
+		if count, err = strconv.Atoi(value); err != nil || count <= 0 {
+			return 0, 0, e.Errorf("invalid count %q", value)
+		}
+		count = lo.Min([]int{count, restapi.MaxCount})
+	}
+	return page, count, nil
+}
+
+// parseRESTSearch parses the parameters of a paginated search.
+func parseRESTSearch(req *http.Request, fields map[string]string) (query modelInputs.ClickhouseQuery, page int, count int, err error) {
+	query.IsAnd = true
+	if query.DateRange, err = parseRESTDateRange(req); err != nil {
+		return
+	}
+	if query.Rules, err = restapi.ParseFilters(fields, req.URL.Query()["filter"]); err != nil {
+		return
+	}
+	page, count, err = parseRESTPage(req)
+	return
+}
+
+func newRESTProject(project *model.Project) *restapi.Project {
+	p := &restapi.Project{ID: project.ID, WorkspaceID: project.WorkspaceID}
+	if project.Name != nil {
+		p.Name = *project.Name
+	}
+	return p
+}
+
+func newRESTErrorGroup(errorGroup *model.ErrorGroup) *restapi.ErrorGroup {
+	eg := &restapi.ErrorGroup{
+		SecureID:         errorGroup.SecureID,
+		ProjectID:        errorGroup.ProjectID,
+		Type:             errorGroup.Type,
+		Event:            errorGroup.Event,
+		State:            string(errorGroup.State),
+		SnoozedUntil:     errorGroup.SnoozedUntil,
+		ServiceName:      errorGroup.ServiceName,
+		Resolution:       errorGroup.GetResolutionState(time.Now()),
+		ResolvedVersion:  errorGroup.ResolvedVersion,
+		FirstSeenVersion: errorGroup.FirstSeenVersion,
+		LastSeenVersion:  errorGroup.LastSeenVersion,
+		AssigneeID:       errorGroup.AssigneeID,
+		Environments:     []string{},
+		CreatedAt:        errorGroup.CreatedAt,
+		UpdatedAt:        errorGroup.UpdatedAt,
+		URL:              fmt.Sprintf("%s/%d/errors/%s", FrontendURI, errorGroup.ProjectID, errorGroup.SecureID),
+	}
+	if commit := errorGroup.SuspectCommit; commit != nil {
+		eg.SuspectCommit = &restapi.SuspectCommit{
+			SHA:         commit.SHA,
+			Message:     commit.Message,
+			AuthorName:  commit.AuthorName,
+			AuthorEmail: commit.AuthorEmail,
+			URL:         commit.URL,
+			Files:       commit.Files,
+		}
+	}
+	// the environments of an error group are stored as a json object of their counts
+	var environments map[string]int64
+	if err := json.Unmarshal([]byte(errorGroup.Environments), &environments); err == nil {
+		eg.Environments = lo.Keys(environments)
+		sort.Strings(eg.Environments)
+	}
+	return eg
+}
+
+func newRESTErrorGroupImpact(impact *model.ErrorGroupImpact) *restapi.ErrorGroupImpact {
+	return &restapi.ErrorGroupImpact{
+		Occurrences:      impact.Occurrences,
+		AffectedUsers:    impact.AffectedUsers,
+		AffectedSessions: impact.AffectedSessions,
+		ImpactScore:      impact.ImpactScore,
+	}
+}
+
+func newRESTErrorGroupTrends(trends *model.ErrorGroupTrends) *restapi.ErrorGroupTrends {
+	return &restapi.ErrorGroupTrends{
+		Impact: *newRESTErrorGroupImpact(trends.Impact),
+		Buckets: lo.Map(trends.Buckets, func(bucket *model.ErrorGroupTrendBucket, _ int) restapi.ErrorGroupTrendBucket {
+			return restapi.ErrorGroupTrendBucket{
+				Date:             bucket.Date,
+				Occurrences:      bucket.Occurrences,
+				AffectedUsers:    bucket.AffectedUsers,
+				AffectedSessions: bucket.AffectedSessions,
+			}
+		}),
+	}
+}
+
+func newRESTSession(session *model.Session) *restapi.Session {
+	return &restapi.Session{
+		SecureID:       session.SecureID,
+		ProjectID:      session.ProjectID,
+		Identifier:     session.Identifier,
+		Identified:     session.Identified,
+		CreatedAt:      session.CreatedAt,
+		LengthMs:       session.Length,
+		ActiveLengthMs: session.ActiveLength,
+		City:           session.City,
+		Country:        session.Country,
+		OSName:         session.OSName,
+		BrowserName:    session.BrowserName,
+		Environment:    session.Environment,
+		AppVersion:     session.AppVersion,
+		HasErrors:      session.HasErrors != nil && *session.HasErrors,
+		HasRageClicks:  session.HasRageClicks != nil && *session.HasRageClicks,
+		URL:            fmt.Sprintf("%s/%d/sessions/%s", FrontendURI, session.ProjectID, session.SecureID),
+	}
+}
+
+func newRESTLog(logRow *modelInputs.Log) *restapi.Log {
+	return &restapi.Log{
+		Timestamp:       logRow.Timestamp,
+		Level:           string(logRow.Level),
+		Message:         logRow.Message,
+		ServiceName:     logRow.ServiceName,
+		Environment:     logRow.Environment,
+		TraceID:         logRow.TraceID,
+		SpanID:          logRow.SpanID,
+		SecureSessionID: logRow.SecureSessionID,
+		Attributes:      logRow.LogAttributes,
+	}
+}
+
+func newRESTTrace(trace *modelInputs.Trace) *restapi.Trace {
+	return &restapi.Trace{
+		Timestamp:       trace.Timestamp,
+		TraceID:         trace.TraceID,
+		SpanID:          trace.SpanID,
+		ParentSpanID:    trace.ParentSpanID,
+		SpanName:        trace.SpanName,
+		DurationNs:      trace.Duration,
+		ServiceName:     trace.ServiceName,
+		Environment:     trace.Environment,
+		StatusCode:      trace.StatusCode,
+		SecureSessionID: trace.SecureSessionID,
+		Attributes:      trace.TraceAttributes,
+	}
+}
+
+func newRESTSourcemapArtifact(artifact *model.SourcemapArtifact) *restapi.SourcemapArtifact {
+	return &restapi.SourcemapArtifact{
+		Version:   artifact.Version,
+		Path:      artifact.Path,
+		Size:      artifact.Size,
+		SHA256:    artifact.SHA256,
+		UpdatedAt: artifact.UpdatedAt,
+	}
+}
+
+func newRESTRelease(release *model.Release) *restapi.Release {
+	return &restapi.Release{
+		ServiceName: release.ServiceName,
+		Version:     release.Version,
+		FirstSeenAt: release.FirstSeenAt,
+		DeployedAt:  release.DeployedAt,
+		Environment: release.Environment,
+		CommitSHA:   release.CommitSHA,
+		URL:         release.URL,
+		CreatedAt:   release.CreatedAt,
+	}
+}
+
+func nextCursor(pageInfo *modelInputs.PageInfo) *string {
+	if pageInfo == nil || !pageInfo.HasNextPage {
+		return nil
+	}
+	return &pageInfo.EndCursor
+}
+
+// OpenAPISpecHandler returns the OpenAPI spec of the REST API.
+func (r *Resolver) OpenAPISpecHandler(w http.ResponseWriter, req *http.Request) {
+	writeJSONResponse(w, req, http.StatusOK, restapi.Spec())
+}
+
+func (r *Resolver) RESTProjectsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if !authorizeRESTRequest(w, req, apitoken.ScopeProjectsRead) {
+		return
+	}
+	projects, err := r.Query().Projects(ctx)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying projects"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying projects")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, lo.Map(projects, func(project *model.Project, _ int) *restapi.Project {
+		return newRESTProject(project)
+	}))
+}
+
+func (r *Resolver) RESTErrorGroupsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeErrorsRead)
+	if !ok {
+		return
+	}
+	query, page, count, err := parseRESTSearch(req, restapi.ErrorGroupFilterFields)
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch sortBy := req.URL.Query().Get("sort"); sortBy {
+	case "", restapi.ErrorGroupSortUpdatedAt:
+	case restapi.ErrorGroupSortImpact:
+		r.writeRESTErrorGroupsByImpact(w, req, project, query, page, count)
+		return
+	default:
+		writeRESTError(w, req, http.StatusBadRequest, fmt.Sprintf("invalid sort %q", sortBy))
+		return
+	}
+	results, err := r.Query().ErrorGroupsClickhouse(ctx, project.ID, count, query, &page)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying error groups"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying error groups")
+		return
+	}
+	errorGroups := lo.Map(results.ErrorGroups, func(errorGroup model.ErrorGroup, _ int) *restapi.ErrorGroup {
+		return newRESTErrorGroup(&errorGroup)
+	})
+	writeJSONResponse(w, req, http.StatusOK, restapi.NewPage(errorGroups, page, count, results.TotalCount))
+}
+
+// writeRESTErrorGroupsByImpact writes the page of the error groups that match the query, ranked by
+// the users and sessions that their errors affected in the time range of the query.
+func (r *Resolver) writeRESTErrorGroupsByImpact(w http.ResponseWriter, req *http.Request, project *model.Project, query modelInputs.ClickhouseQuery, page int, count int) {
+	ctx := req.Context()
+	results, err := r.getErrorGroupsByImpact(ctx, project, query, &page, count)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying error groups")
+		return
+	}
+	errorGroups := lo.Map(results.ErrorGroups, func(errorGroup *model.ErrorGroupWithImpact, _ int) *restapi.ErrorGroup {
+		eg := newRESTErrorGroup(errorGroup.ErrorGroup)
+		eg.Impact = newRESTErrorGroupImpact(errorGroup.Impact)
+		return eg
+	})
+	writeJSONResponse(w, req, http.StatusOK, restapi.NewPage(errorGroups, page, count, results.TotalCount))
+}
+
+// getRESTErrorGroup returns the error group in the url, which must be in the project.
+func (r *Resolver) getRESTErrorGroup(w http.ResponseWriter, req *http.Request, project *model.Project) (*model.ErrorGroup, bool) {
+	errorGroup, err := r.canAdminModifyErrorGroup(req.Context(), chi.URLParam(req, errorGroupSecureIdUrlParam))
+	if err != nil || errorGroup.ProjectID != project.ID {
+		writeRESTError(w, req, http.StatusNotFound, "error group not found")
+		return nil, false
+	}
+	return errorGroup, true
+}
+
+func (r *Resolver) RESTErrorGroupHandler(w http.ResponseWriter, req *http.Request) {
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeErrorsRead)
+	if !ok {
+		return
+	}
+	errorGroup, ok := r.getRESTErrorGroup(w, req, project)
+	if !ok {
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, newRESTErrorGroup(errorGroup))
+}
+
+// parseErrorGroupTrendsParams parses the time range and the `resolution_minutes` parameter of the
+// trends of an error group.
+func parseErrorGroupTrendsParams(req *http.Request) (*modelInputs.ErrorGroupFrequenciesParamsInput, error) {
+	dateRange, err := parseRESTDateRange(req)
+	if err != nil {
+		return nil, err
+	}
+	resolutionMinutes := restapi.DefaultResolutionMinutes
+	if value := req.URL.Query().Get("resolution_minutes"); value != "" {
+		if resolutionMinutes, err = strconv.Atoi(value); err != nil || resolutionMinutes <= 0 {
+			return nil, e.Errorf("invalid resolution_minutes %q", value)
+		}
+	}
+	params := modelInputs.ErrorGroupFrequenciesParamsInput{
+		DateRange:         dateRange,
+		ResolutionMinutes: resolutionMinutes,
+	}
+	if err := validateErrorGroupTrendsParams(params); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+// RESTErrorGroupTrendsHandler returns the impact of an error group over the time range, and in
+// buckets of the `resolution_minutes` parameter.
+func (r *Resolver) RESTErrorGroupTrendsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeErrorsRead)
+	if !ok {
+		return
+	}
+	errorGroup, ok := r.getRESTErrorGroup(w, req, project)
+	if !ok {
+		return
+	}
+	params, err := parseErrorGroupTrendsParams(req)
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	trends, err := r.getErrorGroupTrends(ctx, errorGroup, *params)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying error group trends")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, newRESTErrorGroupTrends(trends))
+}
+
+func (r *Resolver) UpdateRESTErrorGroupStateHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeErrorsWrite)
+	if !ok {
+		return
+	}
+	if err := r.authorizeWorkspace(ctx, project.WorkspaceID, rbac.PermissionEdit); err != nil {
+		writeRESTError(w, req, http.StatusForbidden, "the admin of the api token cannot edit error groups")
+		return
+	}
+	errorGroup, ok := r.getRESTErrorGroup(w, req, project)
+	if !ok {
+		return
+	}
+
+	var input restapi.ErrorGroupStateInput
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	state := modelInputs.ErrorState(input.State)
+	if !state.IsValid() {
+		writeRESTError(w, req, http.StatusBadRequest, fmt.Sprintf("invalid state %q", input.State))
+		return
+	}
+
+	if input.ResolvedVersion != nil && state != modelInputs.ErrorStateResolved {
+		writeRESTError(w, req, http.StatusBadRequest, "resolved_version requires the RESOLVED state")
+		return
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		writeRESTError(w, req, http.StatusForbidden, "the admin of the api token cannot edit error groups")
+		return
+	}
+
+	updated, err := r.Store.UpdateErrorGroupStateByAdmin(ctx, *admin, store.UpdateErrorGroupParams{
+		ID:              errorGroup.ID,
+		State:           state,
+		SnoozedUntil:    input.SnoozedUntil,
+		ResolvedVersion: input.ResolvedVersion,
+	})
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error updating error group state"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error updating error group state")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, newRESTErrorGroup(&updated))
+}
+
+func (r *Resolver) RESTSessionsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeSessionsRead)
+	if !ok {
+		return
+	}
+	query, page, count, err := parseRESTSearch(req, restapi.SessionFilterFields)
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	results, err := r.Query().SessionsClickhouse(ctx, project.ID, count, query, nil, true, &page)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying sessions"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying sessions")
+		return
+	}
+	sessions := lo.Map(results.Sessions, func(session model.Session, _ int) *restapi.Session {
+		return newRESTSession(&session)
+	})
+	writeJSONResponse(w, req, http.StatusOK, restapi.NewPage(sessions, page, count, results.TotalCount))
+}
+
+func (r *Resolver) RESTSearchLogsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeLogsRead)
+	if !ok {
+		return
+	}
+	dateRange, err := parseRESTDateRange(req)
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	logs, err := r.Query().Logs(ctx, project.ID, modelInputs.QueryInput{
+		Query:     req.URL.Query().Get("query"),
+		DateRange: dateRange,
+	}, optionalQueryParam(req, "cursor"), nil, nil, modelInputs.SortDirectionDesc)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying logs"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying logs")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, &restapi.CursorPage[*restapi.Log]{
+		Data: lo.Map(logs.Edges, func(edge *modelInputs.LogEdge, _ int) *restapi.Log {
+			return newRESTLog(edge.Node)
+		}),
+		NextCursor: nextCursor(logs.PageInfo),
+	})
+}
+
+func (r *Resolver) RESTSearchTracesHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeTracesRead)
+	if !ok {
+		return
+	}
+	dateRange, err := parseRESTDateRange(req)
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	traces, err := r.Query().Traces(ctx, project.ID, modelInputs.QueryInput{
+		Query:     req.URL.Query().Get("query"),
+		DateRange: dateRange,
+	}, optionalQueryParam(req, "cursor"), nil, nil, modelInputs.SortDirectionDesc)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying traces"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying traces")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, &restapi.CursorPage[*restapi.Trace]{
+		Data: lo.Map(traces.Edges, func(edge *modelInputs.TraceEdge, _ int) *restapi.Trace {
+			return newRESTTrace(edge.Node)
+		}),
+		NextCursor: nextCursor(traces.PageInfo),
+	})
+}
+
+func (r *Resolver) RESTSourcemapsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeSourcemapsRead)
+	if !ok {
+		return
+	}
+	artifacts, err := r.Store.GetSourcemapArtifacts(ctx, project.ID, req.URL.Query().Get("version"))
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying sourcemaps"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying sourcemaps")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, lo.Map(artifacts, func(artifact *model.SourcemapArtifact, _ int) *restapi.SourcemapArtifact {
+		return newRESTSourcemapArtifact(artifact)
+	}))
+}
+
+// sourcemapArtifactPath returns the path of an uploaded file from its file name. The raw file name
+// is parsed since multipart.Part.FileName strips its directories.
+func sourcemapArtifactPath(part *multipart.Part) (string, error) {
+	_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+	if err != nil || params["filename"] == "" {
+		return "", e.New("files need a file name")
+	}
+	filePath := path.Clean(strings.TrimLeft(strings.ReplaceAll(params["filename"], "\\", "/"), "/"))
+	if filePath == "." || filePath == ".." || strings.HasPrefix(filePath, "../") {
+		return "", e.Errorf("invalid file name %q", params["filename"])
+	}
+	return filePath, nil
+}
+
+// UploadRESTSourcemapsHandler stores the `file` parts of a multipart request in the sourcemaps
+// bucket as they are read, so the `version` field must precede them. Files that were uploaded
+// unchanged before are not stored again.
+func (r *Resolver) UploadRESTSourcemapsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeSourcemapsWrite)
+	if !ok {
+		return
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		writeRESTError(w, req, http.StatusForbidden, "the admin of the api token cannot upload sourcemaps")
+		return
+	}
+	if err := r.authorizeWorkspace(ctx, project.WorkspaceID, rbac.PermissionEdit); err != nil {
+		writeRESTError(w, req, http.StatusForbidden, "the admin of the api token cannot upload sourcemaps")
+		return
+	}
+	reader, err := req.MultipartReader()
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, "the request body must be multipart/form-data")
+		return
+	}
+
+	var version *string
+	// the hashes of the artifacts of the version by path, which are queried with the first file
+	var hashes map[string]string
+	var artifacts []*model.SourcemapArtifact
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			writeRESTError(w, req, http.StatusBadRequest, "invalid multipart request body")
+			return
+		}
+
+		switch part.FormName() {
+		case "version":
+			if hashes != nil {
+				writeRESTError(w, req, http.StatusBadRequest, "the version must precede the files")
+				return
+			}
+			value, err := io.ReadAll(io.LimitReader(part, 256))
+			if err != nil {
+				writeRESTError(w, req, http.StatusBadRequest, "invalid version")
+				return
+			}
+			if v := strings.TrimSpace(string(value)); v != "" {
+				version = &v
+			}
+		case "file":
+			if hashes == nil {
+				existing, err := r.Store.GetSourcemapArtifacts(ctx, project.ID, lo.FromPtr(version))
+				if err != nil {
+					log.WithContext(ctx).Error(e.Wrap(err, "error querying sourcemaps"))
+					writeRESTError(w, req, http.StatusInternalServerError, "error querying sourcemaps")
+					return
+				}
+				hashes = lo.SliceToMap(existing, func(artifact *model.SourcemapArtifact) (string, string) {
+					return artifact.Path, artifact.SHA256
+				})
+			}
+
+			filePath, err := sourcemapArtifactPath(part)
+			if err != nil {
+				writeRESTError(w, req, http.StatusBadRequest, err.Error())
+				return
+			}
+			if lo.ContainsBy(artifacts, func(artifact *model.SourcemapArtifact) bool { return artifact.Path == filePath }) {
+				writeRESTError(w, req, http.StatusBadRequest, fmt.Sprintf("file %s was uploaded more than once", filePath))
+				return
+			}
+			fileBytes, err := io.ReadAll(io.LimitReader(part, stacktraces.SOURCE_MAP_MAX_FILE_SIZE+1))
+			if err != nil {
+				writeRESTError(w, req, http.StatusBadRequest, fmt.Sprintf("error reading file %s", filePath))
+				return
+			}
+			if len(fileBytes) > stacktraces.SOURCE_MAP_MAX_FILE_SIZE {
+				writeRESTError(w, req, http.StatusRequestEntityTooLarge, fmt.Sprintf("file %s is over %dmb", filePath, int(stacktraces.SOURCE_MAP_MAX_FILE_SIZE/1e6)))
+				return
+			}
+			hash := fmt.Sprintf("%x", sha256.Sum256(fileBytes))
+			if expected := part.Header.Get(sourcemapHashHeader); expected != "" && !strings.EqualFold(expected, hash) {
+				writeRESTError(w, req, http.StatusBadRequest, fmt.Sprintf("file %s does not match its %s header", filePath, sourcemapHashHeader))
+				return
+			}
+
+			if hashes[filePath] != hash {
+				if _, err := r.StorageClient.PushSourceMapFile(ctx, project.ID, version, filePath, fileBytes); err != nil {
+					log.WithContext(ctx).Error(e.Wrapf(err, "error storing sourcemap file %s", filePath))
+					writeRESTError(w, req, http.StatusInternalServerError, fmt.Sprintf("error storing file %s", filePath))
+					return
+				}
+			}
+			artifacts = append(artifacts, &model.SourcemapArtifact{
+				ProjectID:         project.ID,
+				Version:           lo.FromPtr(version),
+				Path:              filePath,
+				Size:              int64(len(fileBytes)),
+				SHA256:            hash,
+				UploadedByAdminID: admin.ID,
+			})
+		}
+	}
+	if len(artifacts) == 0 {
+		writeRESTError(w, req, http.StatusBadRequest, "no files were uploaded")
+		return
+	}
+
+	if err := r.Store.UpsertSourcemapArtifacts(ctx, artifacts); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error saving sourcemaps"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error saving sourcemaps")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, lo.Map(artifacts, func(artifact *model.SourcemapArtifact, _ int) *restapi.SourcemapArtifact {
+		return newRESTSourcemapArtifact(artifact)
+	}))
+}
+
+func (r *Resolver) RESTReleasesHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeReleasesRead)
+	if !ok {
+		return
+	}
+	_, count, err := parseRESTPage(req)
+	if err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	releases, err := r.Store.GetReleases(ctx, project.ID, req.URL.Query().Get("service_name"), count)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying releases"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error querying releases")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, lo.Map(releases, func(release *model.Release, _ int) *restapi.Release {
+		return newRESTRelease(release)
+	}))
+}
+
+// CreateRESTDeployHandler records a deploy that a CI pipeline reports. Deploys of a version that was
+// deployed before replace its deploy.
+func (r *Resolver) CreateRESTDeployHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	project, ok := r.authorizeRESTProjectRequest(w, req, apitoken.ScopeReleasesWrite)
+	if !ok {
+		return
+	}
+	if err := r.authorizeWorkspace(ctx, project.WorkspaceID, rbac.PermissionEdit); err != nil {
+		writeRESTError(w, req, http.StatusForbidden, "the admin of the api token cannot create deploys")
+		return
+	}
+
+	var input restapi.DeployInput
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		writeRESTError(w, req, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if input.ServiceName == "" || input.Version == "" {
+		writeRESTError(w, req, http.StatusBadRequest, "deploys need a service_name and version")
+		return
+	}
+
+	release := &model.Release{
+		ProjectID:   project.ID,
+		ServiceName: input.ServiceName,
+		Version:     input.Version,
+		DeployedAt:  input.DeployedAt,
+		Environment: input.Environment,
+		CommitSHA:   input.CommitSHA,
+		URL:         input.URL,
+	}
+	if err := r.Store.CreateDeploy(ctx, release); err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error saving deploy"))
+		writeRESTError(w, req, http.StatusInternalServerError, "error saving deploy")
+		return
+	}
+	writeJSONResponse(w, req, http.StatusOK, newRESTRelease(release))
+}
+
+func optionalQueryParam(req *http.Request, name string) *string {
+	if value := req.URL.Query().Get(name); value != "" {
+		return &value
+	}
+	return nil
+}
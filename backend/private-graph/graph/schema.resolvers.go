@@ -8,6 +8,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
@@ -26,25 +27,37 @@ import (
 	"github.com/highlight-run/highlight/backend/alerts"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
+	"github.com/highlight-run/highlight/backend/apitoken"
 	"github.com/highlight-run/highlight/backend/apolloio"
+	"github.com/highlight-run/highlight/backend/chaos"
 	"github.com/highlight-run/highlight/backend/clickhouse"
 	"github.com/highlight-run/highlight/backend/clickup"
+	"github.com/highlight-run/highlight/backend/datadog"
 	Email "github.com/highlight-run/highlight/backend/email"
 	"github.com/highlight-run/highlight/backend/front"
+	"github.com/highlight-run/highlight/backend/githubissues"
+	"github.com/highlight-run/highlight/backend/integrations/analytics"
 	"github.com/highlight-run/highlight/backend/integrations/height"
+	"github.com/highlight-run/highlight/backend/integrations/jira"
+	"github.com/highlight-run/highlight/backend/integrations/zendesk"
+	"github.com/highlight-run/highlight/backend/issuetracker"
 	kafka_queue "github.com/highlight-run/highlight/backend/kafka-queue"
 	"github.com/highlight-run/highlight/backend/lambda-functions/deleteSessions/utils"
 	utils2 "github.com/highlight-run/highlight/backend/lambda-functions/sessionExport/utils"
+	"github.com/highlight-run/highlight/backend/linear"
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/highlight-run/highlight/backend/phonehome"
 	"github.com/highlight-run/highlight/backend/pricing"
 	"github.com/highlight-run/highlight/backend/private-graph/graph/generated"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	"github.com/highlight-run/highlight/backend/redact"
 	"github.com/highlight-run/highlight/backend/redis"
 	"github.com/highlight-run/highlight/backend/storage"
 	"github.com/highlight-run/highlight/backend/store"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight-run/highlight/backend/vercel"
+	"github.com/highlight-run/highlight/backend/warehouse"
 	"github.com/highlight-run/highlight/backend/zapier"
 	highlight "github.com/highlight/highlight/sdk/highlight-go"
 	hmetric "github.com/highlight/highlight/sdk/highlight-go/metric"
@@ -73,6 +86,20 @@ func (r *commentReplyResolver) Author(ctx context.Context, obj *model.CommentRep
 	return r.formatSanitizedAuthor(admin), nil
 }
 
+// APIKeySet is the resolver for the api_key_set field.
+func (r *datadogLogForwarderResolver) APIKeySet(ctx context.Context, obj *model.DatadogLogForwarder) (bool, error) {
+	return obj.APIKey != "", nil
+}
+
+// AttributeMapping is the resolver for the attribute_mapping field.
+func (r *datadogLogForwarderResolver) AttributeMapping(ctx context.Context, obj *model.DatadogLogForwarder) (map[string]interface{}, error) {
+	attributeMapping := map[string]interface{}{}
+	for key, value := range obj.AttributeMapping {
+		attributeMapping[key] = value
+	}
+	return attributeMapping, nil
+}
+
 // ChannelsToNotify is the resolver for the ChannelsToNotify field.
 func (r *errorAlertResolver) ChannelsToNotify(ctx context.Context, obj *model.ErrorAlert) ([]*modelInputs.SanitizedSlackChannel, error) {
 	return obj.GetChannelsToNotify()
@@ -83,6 +110,16 @@ func (r *errorAlertResolver) DiscordChannelsToNotify(ctx context.Context, obj *m
 	return obj.DiscordChannelsToNotify, nil
 }
 
+// DiscordWebhooksToNotify is the resolver for the DiscordWebhooksToNotify field.
+func (r *errorAlertResolver) DiscordWebhooksToNotify(ctx context.Context, obj *model.ErrorAlert) ([]*model.DiscordWebhook, error) {
+	return obj.DiscordWebhooksToNotify, nil
+}
+
+// MicrosoftTeamsChannelsToNotify is the resolver for the MicrosoftTeamsChannelsToNotify field.
+func (r *errorAlertResolver) MicrosoftTeamsChannelsToNotify(ctx context.Context, obj *model.ErrorAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannelsToNotify, nil
+}
+
 // WebhookDestinations is the resolver for the WebhookDestinations field.
 func (r *errorAlertResolver) WebhookDestinations(ctx context.Context, obj *model.ErrorAlert) ([]*model.WebhookDestination, error) {
 	return obj.WebhookDestinations, nil
@@ -108,6 +145,21 @@ func (r *errorAlertResolver) DailyFrequency(ctx context.Context, obj *model.Erro
 	return obj.GetDailyErrorEventFrequency(r.DB, obj.ID)
 }
 
+// PagerDutyDestinations is the resolver for the pager_duty_destinations field.
+func (r *errorAlertResolver) PagerDutyDestinations(ctx context.Context, obj *model.ErrorAlert) ([]*model.PagerDutyDestination, error) {
+	return obj.PagerDutyDestinations, nil
+}
+
+// OpsgenieDestinations is the resolver for the opsgenie_destinations field.
+func (r *errorAlertResolver) OpsgenieDestinations(ctx context.Context, obj *model.ErrorAlert) ([]*model.OpsgenieDestination, error) {
+	return obj.OpsgenieDestinations, nil
+}
+
+// SplunkOnCallDestinations is the resolver for the splunk_on_call_destinations field.
+func (r *errorAlertResolver) SplunkOnCallDestinations(ctx context.Context, obj *model.ErrorAlert) ([]*model.SplunkOnCallDestination, error) {
+	return obj.SplunkOnCallDestinations, nil
+}
+
 // Author is the resolver for the author field.
 func (r *errorCommentResolver) Author(ctx context.Context, obj *model.ErrorComment) (*modelInputs.SanitizedAdmin, error) {
 	admin := &model.Admin{}
@@ -189,6 +241,18 @@ func (r *errorGroupResolver) MetadataLog(ctx context.Context, obj *model.ErrorGr
 	return metadataLogs, nil
 }
 
+// SpanLinks is the resolver for the span_links field.
+func (r *errorObjectResolver) SpanLinks(ctx context.Context, obj *model.ErrorObject) ([]*modelInputs.TraceLink, error) {
+	if obj.SpanLinks == nil || *obj.SpanLinks == "" {
+		return nil, nil
+	}
+	var links []*modelInputs.TraceLink
+	if err := json.Unmarshal([]byte(*obj.SpanLinks), &links); err != nil {
+		return nil, e.Wrapf(err, "error unmarshalling span links of error object %d", obj.ID)
+	}
+	return links, nil
+}
+
 // ErrorGroupSecureID is the resolver for the error_group_secure_id field.
 func (r *errorObjectResolver) ErrorGroupSecureID(ctx context.Context, obj *model.ErrorObject) (string, error) {
 	if obj != nil {
@@ -240,6 +304,21 @@ func (r *errorSegmentResolver) Params(ctx context.Context, obj *model.ErrorSegme
 	return params, nil
 }
 
+// Steps is the resolver for the steps field.
+func (r *escalationPolicyResolver) Steps(ctx context.Context, obj *model.EscalationPolicy) ([]*model.EscalationStep, error) {
+	return obj.Steps, nil
+}
+
+// Type is the resolver for the type field.
+func (r *escalationStepResolver) Type(ctx context.Context, obj *model.EscalationStep) (string, error) {
+	return obj.Type, nil
+}
+
+// LastCheckInStatus is the resolver for the last_check_in_status field.
+func (r *heartbeatMonitorResolver) LastCheckInStatus(ctx context.Context, obj *model.HeartbeatMonitor) (*string, error) {
+	return obj.LastCheckInStatus, nil
+}
+
 // ChannelsToNotify is the resolver for the ChannelsToNotify field.
 func (r *logAlertResolver) ChannelsToNotify(ctx context.Context, obj *model.LogAlert) ([]*modelInputs.SanitizedSlackChannel, error) {
 	return obj.GetChannelsToNotify()
@@ -250,6 +329,16 @@ func (r *logAlertResolver) DiscordChannelsToNotify(ctx context.Context, obj *mod
 	return obj.DiscordChannelsToNotify, nil
 }
 
+// DiscordWebhooksToNotify is the resolver for the DiscordWebhooksToNotify field.
+func (r *logAlertResolver) DiscordWebhooksToNotify(ctx context.Context, obj *model.LogAlert) ([]*model.DiscordWebhook, error) {
+	return obj.DiscordWebhooksToNotify, nil
+}
+
+// MicrosoftTeamsChannelsToNotify is the resolver for the MicrosoftTeamsChannelsToNotify field.
+func (r *logAlertResolver) MicrosoftTeamsChannelsToNotify(ctx context.Context, obj *model.LogAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannelsToNotify, nil
+}
+
 // WebhookDestinations is the resolver for the WebhookDestinations field.
 func (r *logAlertResolver) WebhookDestinations(ctx context.Context, obj *model.LogAlert) ([]*model.WebhookDestination, error) {
 	return obj.WebhookDestinations, nil
@@ -556,7 +645,7 @@ func (r *mutationResolver) EditProject(ctx context.Context, id int, name *string
 }
 
 // EditProjectSettings is the resolver for the editProjectSettings field.
-func (r *mutationResolver) EditProjectSettings(ctx context.Context, projectID int, name *string, billingEmail *string, excludedUsers pq.StringArray, errorFilters pq.StringArray, errorJSONPaths pq.StringArray, rageClickWindowSeconds *int, rageClickRadiusPixels *int, rageClickCount *int, filterChromeExtension *bool, filterSessionsWithoutError *bool, autoResolveStaleErrorsDayInterval *int, sampling *modelInputs.SamplingInput) (*modelInputs.AllProjectSettings, error) {
+func (r *mutationResolver) EditProjectSettings(ctx context.Context, projectID int, name *string, billingEmail *string, excludedUsers pq.StringArray, errorFilters pq.StringArray, errorJSONPaths pq.StringArray, rageClickWindowSeconds *int, rageClickRadiusPixels *int, rageClickCount *int, filterChromeExtension *bool, filterSessionsWithoutError *bool, autoResolveStaleErrorsDayInterval *int, sampling *modelInputs.SamplingInput, excludedServiceNames []string, excludedLogLevels []string, errorPayload *modelInputs.ErrorPayloadSettingsInput, errorsFromSpanStatus *bool) (*modelInputs.AllProjectSettings, error) {
 	project, err := r.EditProject(ctx, projectID, name, billingEmail, excludedUsers, errorFilters, errorJSONPaths, rageClickWindowSeconds, rageClickRadiusPixels, rageClickCount, filterChromeExtension)
 	if err != nil {
 		return nil, err
@@ -575,6 +664,48 @@ func (r *mutationResolver) EditProjectSettings(ctx context.Context, projectID in
 		RageClickCount:         &project.RageClickCount,
 	}
 
+	if sampling != nil {
+		for _, rate := range []*float64{sampling.SessionSamplingRate, sampling.ErrorSamplingRate, sampling.LogSamplingRate, sampling.TraceSamplingRate} {
+			if rate != nil && (*rate < 0 || *rate > 1) {
+				return nil, e.New("sampling rates must be between 0 and 1")
+			}
+		}
+	}
+	// the exclusions are saved first as updating them invalidates the cached settings
+	// that the rest of the settings are then applied to
+	if excludedServiceNames != nil || excludedLogLevels != nil {
+		current, err := r.Store.GetProjectFilterSettings(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		serviceNames := []string(current.ExcludedServiceNames)
+		if excludedServiceNames != nil {
+			serviceNames = excludedServiceNames
+		}
+		logLevels := []string(current.ExcludedLogLevels)
+		if excludedLogLevels != nil {
+			if logLevels, err = parseExcludedLogLevels(excludedLogLevels); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := r.Store.UpdateProjectIngestExclusions(ctx, project.ID, serviceNames, logLevels); err != nil {
+			return nil, e.Wrap(err, "error updating ingest exclusions")
+		}
+	}
+	if errorPayload != nil {
+		if err := validateErrorPayloadSettingsInput(errorPayload); err != nil {
+			return nil, err
+		}
+		if _, err := r.Store.UpdateProjectErrorPayloadSettings(ctx, project.ID, errorPayload.HourlyLimit, errorPayload.SamplingRate, errorPayload.RetentionDays); err != nil {
+			return nil, e.Wrap(err, "error updating error payload settings")
+		}
+	}
+	if errorsFromSpanStatus != nil {
+		if _, err := r.Store.UpdateProjectErrorsFromSpanStatus(ctx, project.ID, *errorsFromSpanStatus); err != nil {
+			return nil, e.Wrap(err, "error updating span status error settings")
+		}
+	}
+
 	projectFilterSettings, err := r.Store.UpdateProjectFilterSettings(ctx, project.ID, store.UpdateProjectFilterSettingsParams{
 		FilterSessionsWithoutError:        filterSessionsWithoutError,
 		AutoResolveStaleErrorsDayInterval: autoResolveStaleErrorsDayInterval,
@@ -585,6 +716,10 @@ func (r *mutationResolver) EditProjectSettings(ctx context.Context, projectID in
 	}
 	allProjectSettings.FilterSessionsWithoutError = projectFilterSettings.FilterSessionsWithoutError
 	allProjectSettings.AutoResolveStaleErrorsDayInterval = projectFilterSettings.AutoResolveStaleErrorsDayInterval
+	allProjectSettings.ExcludedServiceNames = append([]string{}, projectFilterSettings.ExcludedServiceNames...)
+	allProjectSettings.ExcludedLogLevels = append([]string{}, projectFilterSettings.ExcludedLogLevels...)
+	allProjectSettings.ErrorPayload = errorPayloadSettings(projectFilterSettings)
+	allProjectSettings.ErrorsFromSpanStatus = projectFilterSettings.ErrorsFromSpanStatus
 	allProjectSettings.Sampling = &modelInputs.Sampling{
 		SessionSamplingRate:    projectFilterSettings.SessionSamplingRate,
 		ErrorSamplingRate:      projectFilterSettings.SessionSamplingRate,
@@ -603,95 +738,84 @@ func (r *mutationResolver) EditProjectSettings(ctx context.Context, projectID in
 	return &allProjectSettings, nil
 }
 
-// EditWorkspace is the resolver for the editWorkspace field.
-func (r *mutationResolver) EditWorkspace(ctx context.Context, id int, name *string) (*model.Workspace, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, id)
+// UpdateProjectRequireIngestKey is the resolver for the updateProjectRequireIngestKey field.
+func (r *mutationResolver) UpdateProjectRequireIngestKey(ctx context.Context, projectID int, requireIngestKey bool) (*model.Project, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, e.Wrap(err, "error querying workspace")
+		return nil, err
 	}
-	if err := r.DB.WithContext(ctx).Model(workspace).Updates(&model.Workspace{
-		Name: name,
-	}).Error; err != nil {
-		return nil, e.Wrap(err, "error updating workspace fields")
+
+	if err := r.Store.UpdateProjectRequireIngestKey(ctx, project.ID, requireIngestKey); err != nil {
+		return nil, e.Wrap(err, "error updating ingest key setting")
 	}
-	return workspace, nil
+	project.RequireIngestKey = requireIngestKey
+
+	return project, nil
 }
 
-// EditWorkspaceSettings is the resolver for the editWorkspaceSettings field.
-func (r *mutationResolver) EditWorkspaceSettings(ctx context.Context, workspaceID int, aiApplication *bool, aiInsights *bool) (*model.AllWorkspaceSettings, error) {
-	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+// CreateIngestFilterRule is the resolver for the createIngestFilterRule field.
+func (r *mutationResolver) CreateIngestFilterRule(ctx context.Context, projectID int, input modelInputs.IngestFilterRuleInput) (*model.IngestFilterRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	workspaceSettings := &model.AllWorkspaceSettings{}
-	workspaceSettingsUpdates := map[string]interface{}{
-		"AIApplication": *aiApplication,
-		"AIInsights":    *aiInsights,
-	}
-
-	if err := r.DB.WithContext(ctx).Where(&model.AllWorkspaceSettings{WorkspaceID: workspaceID}).Take(&workspaceSettings).Updates(&workspaceSettingsUpdates).Error; err != nil {
+	rule := &model.IngestFilterRule{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyIngestFilterRuleInput(input, rule); err != nil {
 		return nil, err
 	}
-	return workspaceSettings, nil
+	if err := r.Store.CreateIngestFilterRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error creating ingest filter rule")
+	}
+	return rule, nil
 }
 
-// ExportSession is the resolver for the exportSession field.
-func (r *mutationResolver) ExportSession(ctx context.Context, sessionSecureID string) (bool, error) {
-	admin, err := r.getCurrentAdmin(ctx)
+// UpdateIngestFilterRule is the resolver for the updateIngestFilterRule field.
+func (r *mutationResolver) UpdateIngestFilterRule(ctx context.Context, projectID int, id int, input modelInputs.IngestFilterRuleInput) (*model.IngestFilterRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-
-	session, err := r.canAdminViewSession(ctx, sessionSecureID)
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	cfg, err := r.Store.GetAllWorkspaceSettingsByProject(ctx, session.ProjectID)
-	if !cfg.EnableSessionExport {
-		return false, e.New("session export is not enabled")
+	rule, err := r.Store.GetIngestFilterRule(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying ingest filter rule")
 	}
-
-	export := model.SessionExport{
-		SessionID:    session.ID,
-		Type:         model.SessionExportFormatMP4,
-		TargetEmails: []string{*admin.Email},
+	if err := applyIngestFilterRuleInput(input, rule); err != nil {
+		return nil, err
 	}
+	rule.LastAdminToEditID = admin.ID
 
-	tx := r.DB.WithContext(ctx).Model(&export).Where(&model.SessionExport{
-		SessionID: session.ID,
-		Type:      model.SessionExportFormatMP4,
-	}).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "session_id"}, {Name: "type"}},
-		DoUpdates: clause.AssignmentColumns([]string{"target_emails"}),
-	}).FirstOrCreate(&export)
-	if tx.Error != nil {
-		log.WithContext(context.Background()).WithError(tx.Error).Error("failed to create session export record")
-		return false, tx.Error
+	if err := r.Store.UpdateIngestFilterRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error updating ingest filter rule")
 	}
+	return rule, nil
+}
 
-	_, err = r.StepFunctions.SessionExport(ctx, utils2.SessionExportInput{
-		Project:      session.ProjectID,
-		Session:      session.ID,
-		Format:       export.Type,
-		TargetEmails: export.TargetEmails,
-	})
+// DeleteIngestFilterRule is the resolver for the deleteIngestFilterRule field.
+func (r *mutationResolver) DeleteIngestFilterRule(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		log.WithContext(context.Background()).WithError(err).Error("failed to export session video")
 		return false, err
 	}
 
+	if err := r.Store.DeleteIngestFilterRule(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting ingest filter rule")
+	}
 	return true, nil
 }
 
-// MarkErrorGroupAsViewed is the resolver for the markErrorGroupAsViewed field.
-func (r *mutationResolver) MarkErrorGroupAsViewed(ctx context.Context, errorSecureID string, viewed *bool) (*model.ErrorGroup, error) {
-	eg, err := r.canAdminModifyErrorGroup(ctx, errorSecureID)
+// CreateErrorWorkflowRule is the resolver for the createErrorWorkflowRule field.
+func (r *mutationResolver) CreateErrorWorkflowRule(ctx context.Context, projectID int, input modelInputs.ErrorWorkflowRuleInput) (*model.ErrorWorkflowRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -700,64 +824,19 @@ func (r *mutationResolver) MarkErrorGroupAsViewed(ctx context.Context, errorSecu
 		return nil, err
 	}
 
-	// Update the the number of error groups viewed for the current admin.
-	r.PrivateWorkerPool.SubmitRecover(func() {
-		ctx := context.Background()
-		// Check if this admin has already viewed
-		if _, err := r.isAdminInProject(ctx, eg.ProjectID); err != nil {
-			log.WithContext(ctx).Infof("not adding error groups count to admin in hubspot; this is probably a demo project, with id [%v]", eg.ProjectID)
-			return
-		}
-		var currentErrorGroupCount int64
-		if err := r.DB.WithContext(ctx).Raw(`
-			select count(*)
-			from error_group_admins_views
-			where error_group_id = ? and admin_id = ?
-	`, eg.ID, admin.ID).Scan(&currentErrorGroupCount).Error; err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error querying count of error group views from admin"))
-			return
-		} else if currentErrorGroupCount > 0 {
-			log.WithContext(ctx).Info("not updating hubspot error group count; admin has already viewed this error group")
-			return
-		}
-
-		var totalErrorGroupCount int64
-		if err := r.DB.WithContext(ctx).Raw(`
-			select count(*)
-			from error_group_admins_views
-			where admin_id = ?
-	`, admin.ID).Scan(&totalErrorGroupCount).Error; err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error querying total count of error group views from admin"))
-			return
-		}
-		totalErrorGroupCountAsInt := int(totalErrorGroupCount) + 1
-
-		if err := r.DB.WithContext(ctx).Where(admin).Updates(&model.Admin{NumberOfErrorGroupsViewed: &totalErrorGroupCountAsInt}).Error; err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error updating error group count for admin in postgres"))
-		}
-		phonehome.ReportUsageMetrics(ctx, phonehome.AdminUsage, admin.ID, []attribute.KeyValue{
-			attribute.Int(phonehome.ErrorViewCount, totalErrorGroupCountAsInt),
-		})
-	})
-
-	newErrorGroup := &model.ErrorGroup{}
-	updatedFields := &model.ErrorGroup{
-		Viewed: viewed,
-	}
-	if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{Model: model.Model{ID: eg.ID}}).Take(&newErrorGroup).Updates(updatedFields).Error; err != nil {
-		return nil, e.Wrap(err, "error writing error as viewed")
+	rule := &model.ErrorWorkflowRule{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyErrorWorkflowRuleInput(input, rule); err != nil {
+		return nil, err
 	}
-
-	if err := r.DB.WithContext(ctx).Model(&eg).Association("ViewedByAdmins").Append(admin); err != nil {
-		return nil, e.Wrap(err, "error adding admin to ViewedByAdmins")
+	if err := r.Store.CreateErrorWorkflowRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error creating error workflow rule")
 	}
-
-	return newErrorGroup, nil
+	return rule, nil
 }
 
-// MarkSessionAsViewed is the resolver for the markSessionAsViewed field.
-func (r *mutationResolver) MarkSessionAsViewed(ctx context.Context, secureID string, viewed *bool) (*model.Session, error) {
-	s, err := r.canAdminModifySession(ctx, secureID)
+// UpdateErrorWorkflowRule is the resolver for the updateErrorWorkflowRule field.
+func (r *mutationResolver) UpdateErrorWorkflowRule(ctx context.Context, projectID int, id int, input modelInputs.ErrorWorkflowRuleInput) (*model.ErrorWorkflowRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -766,97 +845,101 @@ func (r *mutationResolver) MarkSessionAsViewed(ctx context.Context, secureID str
 		return nil, err
 	}
 
-	// Update the the number of sessions viewed for the current admin.
-	r.PrivateWorkerPool.SubmitRecover(func() {
-		ctx := context.Background()
-		// Check if this admin has already viewed
-		if _, err := r.isAdminInProject(ctx, s.ProjectID); err != nil {
-			log.WithContext(ctx).Infof("not adding session count to admin in hubspot; this is probably a demo project, with id [%v]", s.ProjectID)
-			return
-		}
-		var currentSessionCount int64
-		if err := r.DB.WithContext(ctx).Raw(`
-			select count(*)
-			from session_admins_views
-			where session_id = ? and admin_id = ?
-	`, s.ID, admin.ID).Scan(&currentSessionCount).Error; err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error querying count of session views from admin"))
-			return
-		} else if currentSessionCount > 0 {
-			log.WithContext(ctx).Info("not updating hubspot session count; admin has already viewed this session")
-			return
-		}
+	rule, err := r.Store.GetErrorWorkflowRule(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error workflow rule")
+	}
+	if err := applyErrorWorkflowRuleInput(input, rule); err != nil {
+		return nil, err
+	}
+	rule.LastAdminToEditID = admin.ID
+	if err := r.Store.UpdateErrorWorkflowRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error updating error workflow rule")
+	}
+	return rule, nil
+}
 
-		var totalSessionCount int64
-		if err := r.DB.WithContext(ctx).Raw(`
-			select count(*)
-			from session_admins_views
-			where admin_id = ?
-	`, admin.ID).Scan(&totalSessionCount).Error; err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error querying total count of session views from admin"))
-			return
-		}
-		totalSessionCountAsInt := int(totalSessionCount) + 1
+// DeleteErrorWorkflowRule is the resolver for the deleteErrorWorkflowRule field.
+func (r *mutationResolver) DeleteErrorWorkflowRule(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
 
-		if err := r.DB.WithContext(ctx).Where(admin).Updates(&model.Admin{NumberOfSessionsViewed: &totalSessionCountAsInt}).Error; err != nil {
-			log.WithContext(ctx).Error(e.Wrap(err, "error updating session count for admin in postgres"))
-		}
-		phonehome.ReportUsageMetrics(ctx, phonehome.AdminUsage, admin.ID, []attribute.KeyValue{
-			attribute.Int(phonehome.SessionViewCount, totalSessionCountAsInt),
-		})
-	})
+	if err := r.Store.DeleteErrorWorkflowRule(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting error workflow rule")
+	}
+	return true, nil
+}
 
-	newSession := &model.Session{}
-	updatedFields := &model.Session{
-		Viewed: viewed,
+// CreateErrorOwnershipRule is the resolver for the createErrorOwnershipRule field.
+func (r *mutationResolver) CreateErrorOwnershipRule(ctx context.Context, projectID int, input modelInputs.ErrorOwnershipRuleInput) (*model.ErrorOwnershipRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
-	if err := r.DB.WithContext(ctx).Where(&model.Session{Model: model.Model{ID: s.ID}}).Take(&newSession).Updates(updatedFields).Error; err != nil {
-		return nil, e.Wrap(err, "error writing session as viewed")
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := r.DB.WithContext(ctx).Model(&s).Association("ViewedByAdmins").Append(admin); err != nil {
-		return nil, e.Wrap(err, "error adding admin to ViewedByAdmins")
+	rule := &model.ErrorOwnershipRule{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyErrorOwnershipRuleInput(input, rule); err != nil {
+		return nil, err
 	}
-
-	if err := r.DataSyncQueue.Submit(ctx, strconv.Itoa(s.ID), &kafka_queue.Message{Type: kafka_queue.SessionDataSync, SessionDataSync: &kafka_queue.SessionDataSyncArgs{SessionID: s.ID}}); err != nil {
+	if err := r.validateAssignee(ctx, project, rule.AssigneeID); err != nil {
 		return nil, err
 	}
-
-	return newSession, nil
+	if err := r.Store.CreateErrorOwnershipRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error creating error ownership rule")
+	}
+	return rule, nil
 }
 
-// UpdateErrorGroupState is the resolver for the updateErrorGroupState field.
-func (r *mutationResolver) UpdateErrorGroupState(ctx context.Context, secureID string, state modelInputs.ErrorState, snoozedUntil *time.Time) (*model.ErrorGroup, error) {
-	errorGroup, err := r.canAdminModifyErrorGroup(ctx, secureID)
+// UpdateErrorOwnershipRule is the resolver for the updateErrorOwnershipRule field.
+func (r *mutationResolver) UpdateErrorOwnershipRule(ctx context.Context, projectID int, id int, input modelInputs.ErrorOwnershipRuleInput) (*model.ErrorOwnershipRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	updatedErrorGroup, err := r.Store.UpdateErrorGroupStateByAdmin(ctx, *admin, store.UpdateErrorGroupParams{
-		ID:           errorGroup.ID,
-		State:        state,
-		SnoozedUntil: snoozedUntil,
-	})
-
-	return &updatedErrorGroup, err
+	rule, err := r.Store.GetErrorOwnershipRule(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error ownership rule")
+	}
+	if err := applyErrorOwnershipRuleInput(input, rule); err != nil {
+		return nil, err
+	}
+	if err := r.validateAssignee(ctx, project, rule.AssigneeID); err != nil {
+		return nil, err
+	}
+	rule.LastAdminToEditID = admin.ID
+	if err := r.Store.UpdateErrorOwnershipRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error updating error ownership rule")
+	}
+	return rule, nil
 }
 
-// DeleteProject is the resolver for the deleteProject field.
-func (r *mutationResolver) DeleteProject(ctx context.Context, id int) (*bool, error) {
-	_, err := r.isAdminInProject(ctx, id)
+// DeleteErrorOwnershipRule is the resolver for the deleteErrorOwnershipRule field.
+func (r *mutationResolver) DeleteErrorOwnershipRule(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	if err := r.DB.WithContext(ctx).Model(&model.Project{}).Delete("id = ?", id).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting project")
+
+	if err := r.Store.DeleteErrorOwnershipRule(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting error ownership rule")
 	}
-	return &model.T, nil
+	return true, nil
 }
 
-// SendAdminWorkspaceInvite is the resolver for the sendAdminWorkspaceInvite field.
-func (r *mutationResolver) SendAdminWorkspaceInvite(ctx context.Context, workspaceID int, email string, baseURL string, role string) (*string, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// CreateErrorIgnoreRule is the resolver for the createErrorIgnoreRule field.
+func (r *mutationResolver) CreateErrorIgnoreRule(ctx context.Context, projectID int, input modelInputs.ErrorIgnoreRuleInput) (*model.ErrorIgnoreRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -865,603 +948,1564 @@ func (r *mutationResolver) SendAdminWorkspaceInvite(ctx context.Context, workspa
 		return nil, err
 	}
 
-	if role != model.AdminRole.ADMIN && role != model.AdminRole.MEMBER {
-		return nil, e.Errorf("invalid role %s", role)
+	rule := &model.ErrorIgnoreRule{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyErrorIgnoreRuleInput(input, rule); err != nil {
+		return nil, err
 	}
-
-	// If the new invite is for an admin role, the inviter must be an admin
-	if role == model.AdminRole.ADMIN {
-		err := r.validateAdminRole(ctx, workspaceID)
-		if err != nil {
-			return nil, err
-		}
+	if err := r.Store.CreateErrorIgnoreRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error creating error ignore rule")
 	}
+	return rule, nil
+}
 
-	inviteLink := r.CreateInviteLink(workspaceID, &email, role, false)
-
-	if err := r.DB.WithContext(ctx).Create(inviteLink).Error; err != nil {
-		return nil, e.Wrap(err, "error creating new invite link")
+// UpdateErrorIgnoreRule is the resolver for the updateErrorIgnoreRule field.
+func (r *mutationResolver) UpdateErrorIgnoreRule(ctx context.Context, projectID int, id int, input modelInputs.ErrorIgnoreRuleInput) (*model.ErrorIgnoreRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	inviteLinkUrl := baseURL + "/w/" + strconv.Itoa(workspaceID) + "/invite/" + *inviteLink.Secret
-	return r.SendAdminInviteImpl(*admin.Name, *workspace.Name, inviteLinkUrl, email)
-}
-
-// AddAdminToWorkspace is the resolver for the addAdminToWorkspace field.
-func (r *mutationResolver) AddAdminToWorkspace(ctx context.Context, workspaceID int, inviteID string) (*int, error) {
-	return r.addAdminMembership(ctx, workspaceID, inviteID)
+	rule, err := r.Store.GetErrorIgnoreRule(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error ignore rule")
+	}
+	if err := applyErrorIgnoreRuleInput(input, rule); err != nil {
+		return nil, err
+	}
+	rule.LastAdminToEditID = admin.ID
+	if err := r.Store.UpdateErrorIgnoreRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error updating error ignore rule")
+	}
+	return rule, nil
 }
 
-// DeleteInviteLinkFromWorkspace is the resolver for the deleteInviteLinkFromWorkspace field.
-func (r *mutationResolver) DeleteInviteLinkFromWorkspace(ctx context.Context, workspaceID int, workspaceInviteLinkID int) (bool, error) {
-	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+// DeleteErrorIgnoreRule is the resolver for the deleteErrorIgnoreRule field.
+func (r *mutationResolver) DeleteErrorIgnoreRule(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return false, err
 	}
 
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
-		return false, e.Wrap(err, "a non-Admin role Admin tried deleting an invite.")
+	if err := r.Store.DeleteErrorIgnoreRule(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting error ignore rule")
 	}
+	return true, nil
+}
 
-	result := r.DB.WithContext(ctx).Where("id = ?", workspaceInviteLinkID).Where("workspace_id = ?", workspaceID).Delete(&model.WorkspaceInviteLink{})
-	if result.Error != nil {
-		return false, e.Wrap(err, "error deleting workspace invite link")
+// CreateErrorGroupingRule is the resolver for the createErrorGroupingRule field.
+func (r *mutationResolver) CreateErrorGroupingRule(ctx context.Context, projectID int, input modelInputs.ErrorGroupingRuleInput) (*model.ErrorGroupingRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return int(result.RowsAffected) > 0, nil
+	rule := &model.ErrorGroupingRule{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyErrorGroupingRuleInput(input, rule); err != nil {
+		return nil, err
+	}
+	if err := r.Store.CreateErrorGroupingRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error creating error grouping rule")
+	}
+	return rule, nil
 }
 
-// JoinWorkspace is the resolver for the joinWorkspace field.
-func (r *mutationResolver) JoinWorkspace(ctx context.Context, workspaceID int) (*int, error) {
+// UpdateErrorGroupingRule is the resolver for the updateErrorGroupingRule field.
+func (r *mutationResolver) UpdateErrorGroupingRule(ctx context.Context, projectID int, id int, input modelInputs.ErrorGroupingRuleInput) (*model.ErrorGroupingRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
 	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	domain, err := r.getCustomVerifiedAdminEmailDomain(admin)
+
+	rule, err := r.Store.GetErrorGroupingRule(ctx, project.ID, id)
 	if err != nil {
-		return nil, e.Wrap(err, "error getting custom verified admin email domain")
+		return nil, e.Wrap(err, "error querying error grouping rule")
 	}
-	// if more than one workspace has the auto join email origin, pick the first (oldest) one
-	workspace := &model.Workspace{Model: model.Model{ID: workspaceID}}
-	if err := r.DB.WithContext(ctx).Model(&workspace).Where("jsonb_exists(allowed_auto_join_email_origins::jsonb, LOWER(?))", domain).First(workspace).Error; err != nil {
-		return nil, e.Wrap(err, "error querying workspace")
+	if err := applyErrorGroupingRuleInput(input, rule); err != nil {
+		return nil, err
 	}
-	if err := r.DB.WithContext(ctx).Model(&workspace).Association("Admins").Append(admin); err != nil {
-		return nil, e.Wrap(err, "error adding admin to association")
+	rule.LastAdminToEditID = admin.ID
+	if err := r.Store.UpdateErrorGroupingRule(ctx, rule); err != nil {
+		return nil, e.Wrap(err, "error updating error grouping rule")
 	}
-	return &workspace.ID, nil
+	return rule, nil
 }
 
-// UpdateAllowedEmailOrigins is the resolver for the updateAllowedEmailOrigins field.
-func (r *mutationResolver) UpdateAllowedEmailOrigins(ctx context.Context, workspaceID int, allowedAutoJoinEmailOrigins string) (*int, error) {
-	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+// DeleteErrorGroupingRule is the resolver for the deleteErrorGroupingRule field.
+func (r *mutationResolver) DeleteErrorGroupingRule(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	err = r.validateAdminRole(ctx, workspaceID)
-	if err != nil {
-		return nil, e.Wrap(err, "error retrieving admin user")
+	if err := r.Store.DeleteErrorGroupingRule(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting error grouping rule")
 	}
+	return true, nil
+}
 
-	if !json.Valid([]byte(allowedAutoJoinEmailOrigins)) {
-		return nil, e.Wrap(err, "allowedAutoJoinEmailOrigins is not valid JSON")
+// UpdateRedactionRules is the resolver for the updateRedactionRules field.
+func (r *mutationResolver) UpdateRedactionRules(ctx context.Context, projectID int, keys []string, patterns []string) (*modelInputs.RedactionRules, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
-
-	if err := r.DB.WithContext(ctx).Model(&model.Workspace{Model: model.Model{ID: workspaceID}}).Updates(&model.Workspace{
-		AllowedAutoJoinEmailOrigins: &allowedAutoJoinEmailOrigins}).Error; err != nil {
-		return nil, e.Wrap(err, "error updating workspace")
+	if _, err := redact.New(keys, patterns); err != nil {
+		return nil, e.Wrap(err, "invalid redaction rules")
 	}
 
-	return &workspaceID, nil
+	settings, err := r.Store.UpdateProjectRedactionRules(ctx, project.ID, keys, patterns)
+	if err != nil {
+		return nil, e.Wrap(err, "error updating redaction rules")
+	}
+	return redactionRules(settings), nil
 }
 
-// ChangeAdminRole is the resolver for the changeAdminRole field.
-func (r *mutationResolver) ChangeAdminRole(ctx context.Context, workspaceID int, adminID int, newRole string) (bool, error) {
-	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+// UpdateDatadogLogForwarder is the resolver for the updateDatadogLogForwarder field.
+func (r *mutationResolver) UpdateDatadogLogForwarder(ctx context.Context, projectID int, input modelInputs.DatadogLogForwarderInput) (*model.DatadogLogForwarder, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
-		return false, e.Wrap(err, "A non-Admin role Admin tried changing an admin role.")
+	forwarder := &model.DatadogLogForwarder{ProjectID: project.ID}
+	if err := r.DB.WithContext(ctx).Where(forwarder).Take(forwarder).Error; err != nil && !e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, e.Wrap(err, "error querying datadog log forwarder")
 	}
-
-	admin, err := r.getCurrentAdmin(ctx)
+	validateAPIKey, err := applyDatadogLogForwarderInput(input, forwarder)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	if validateAPIKey {
+		valid, err := datadog.NewClient(forwarder.APIKey, datadog.Site(forwarder.Site)).ValidateAPIKey(ctx)
+		if err != nil {
+			return nil, e.Wrap(err, "error validating datadog api key")
+		} else if !valid {
+			return nil, e.New("invalid Datadog api key")
+		}
 	}
 
-	if admin.ID == adminID {
-		return false, e.New("A admin tried changing their own role.")
+	if err := r.DB.WithContext(ctx).Save(forwarder).Error; err != nil {
+		return nil, e.Wrap(err, "error saving datadog log forwarder")
 	}
+	return forwarder, nil
+}
 
-	if err := r.DB.WithContext(ctx).Model(&model.WorkspaceAdmin{AdminID: adminID, WorkspaceID: workspaceID}).Update("Role", newRole).Error; err != nil {
-		return false, e.Wrap(err, "error updating workspace_admin role")
+// DeleteDatadogLogForwarder is the resolver for the deleteDatadogLogForwarder field.
+func (r *mutationResolver) DeleteDatadogLogForwarder(ctx context.Context, projectID int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
 	}
 
+	if err := r.DB.WithContext(ctx).Where(&model.DatadogLogForwarder{ProjectID: project.ID}).Delete(&model.DatadogLogForwarder{}).Error; err != nil {
+		return false, e.Wrap(err, "error deleting datadog log forwarder")
+	}
 	return true, nil
 }
 
-// DeleteAdminFromProject is the resolver for the deleteAdminFromProject field.
-func (r *mutationResolver) DeleteAdminFromProject(ctx context.Context, projectID int, adminID int) (*int, error) {
+// UpdateProductAnalyticsExport is the resolver for the updateProductAnalyticsExport field.
+func (r *mutationResolver) UpdateProductAnalyticsExport(ctx context.Context, projectID int, destination string, input modelInputs.ProductAnalyticsExportInput) (*model.ProductAnalyticsExport, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
+	if !analytics.Destination(destination).IsValid() {
+		return nil, e.Errorf("invalid destination %s", destination)
+	}
 
-	return r.DeleteAdminAssociation(ctx, project, adminID)
-}
+	export := &model.ProductAnalyticsExport{ProjectID: project.ID, Destination: destination}
+	if err := r.DB.WithContext(ctx).Where(export).Take(export).Error; err != nil && !e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, e.Wrap(err, "error querying product analytics export")
+	}
+	if err := applyProductAnalyticsExportInput(input, export); err != nil {
+		return nil, err
+	}
 
-// DeleteAdminFromWorkspace is the resolver for the deleteAdminFromWorkspace field.
-func (r *mutationResolver) DeleteAdminFromWorkspace(ctx context.Context, workspaceID int, adminID int) (*int, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
-	if err != nil {
-		return nil, e.Wrap(err, "current admin is not in workspace")
+	if err := r.DB.WithContext(ctx).Save(export).Error; err != nil {
+		return nil, e.Wrap(err, "error saving product analytics export")
 	}
+	return export, nil
+}
 
-	deletedAdminId, err := r.DeleteAdminAssociation(ctx, workspace, adminID)
+// DeleteProductAnalyticsExport is the resolver for the deleteProductAnalyticsExport field.
+func (r *mutationResolver) DeleteProductAnalyticsExport(ctx context.Context, projectID int, destination string) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, e.Wrap(err, "error deleting admin association")
+		return false, err
+	}
+	if !analytics.Destination(destination).IsValid() {
+		return false, e.Errorf("invalid destination %s", destination)
 	}
 
-	return deletedAdminId, nil
+	if err := r.DB.WithContext(ctx).
+		Where(&model.ProductAnalyticsExport{ProjectID: project.ID, Destination: destination}).
+		Delete(&model.ProductAnalyticsExport{}).Error; err != nil {
+		return false, e.Wrap(err, "error deleting product analytics export")
+	}
+	return true, nil
 }
 
-// CreateSegment is the resolver for the createSegment field.
-func (r *mutationResolver) CreateSegment(ctx context.Context, projectID int, name string, query string) (*model.Segment, error) {
-	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
-		return nil, err
-	}
-	modelParams := SavedSegmentQueryToParams(query)
-	// Convert to json to store in the db.
-	paramBytes, err := json.Marshal(modelParams)
+// UpdateWarehouseExport is the resolver for the updateWarehouseExport field.
+func (r *mutationResolver) UpdateWarehouseExport(ctx context.Context, projectID int, input modelInputs.WarehouseExportInput) (*model.WarehouseExport, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling search params")
+		return nil, err
 	}
-	paramString := string(paramBytes)
 
-	// check if such a segment exists
-	var count int64
-	if err := r.DB.WithContext(ctx).Model(&model.Segment{}).Where("project_id = ? AND name = ?", projectID, name).Count(&count).Error; err != nil {
-		return nil, e.Wrap(err, "error checking if segment exists")
+	export := &model.WarehouseExport{ProjectID: project.ID}
+	if err := r.DB.WithContext(ctx).Where(export).Take(export).Error; err != nil && !e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, e.Wrap(err, "error querying warehouse export")
 	}
-	if count > 0 {
-		return nil, e.New("segment with this name already exists")
+	if err := applyWarehouseExportInput(input, export); err != nil {
+		return nil, err
 	}
-
-	segment := &model.Segment{
-		Name:      &name,
-		Params:    &paramString,
-		ProjectID: projectID,
+	if _, err := warehouse.NewLoader(ctx, export); err != nil {
+		return nil, err
 	}
-	if err := r.DB.WithContext(ctx).Create(segment).Error; err != nil {
-		return nil, e.Wrap(err, "error creating segment")
+
+	if err := r.DB.WithContext(ctx).Save(export).Error; err != nil {
+		return nil, e.Wrap(err, "error saving warehouse export")
 	}
-	return segment, nil
+	return export, nil
 }
 
-// EmailSignup is the resolver for the emailSignup field.
-func (r *mutationResolver) EmailSignup(ctx context.Context, email string) (string, error) {
-	short, long, err := apolloio.Enrich(email)
+// DeleteWarehouseExport is the resolver for the deleteWarehouseExport field.
+func (r *mutationResolver) DeleteWarehouseExport(ctx context.Context, projectID int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		log.WithContext(ctx).Errorf("error enriching email: %v", err)
-		return email, nil
+		return false, err
 	}
 
-	model.DB.WithContext(ctx).Create(&model.EmailSignup{
-		Email:               email,
-		ApolloData:          *long,
-		ApolloDataShortened: *short,
-	})
-
-	r.PrivateWorkerPool.SubmitRecover(func() {
-		ctx := context.Background()
-		if contact, err := apolloio.CreateContact(email); err != nil {
-			log.WithContext(ctx).Errorf("error creating apollo contact: %v", err)
-		} else {
-			sequenceID := "60fb134ce97fa1014c1cc141" // represents the "Landing Page Signups" sequence.
-			if err := apolloio.AddToSequence(contact.ID, sequenceID); err != nil {
-				log.WithContext(ctx).Errorf("error adding to apollo sequence: %v", err)
-			}
-		}
-	})
-
-	return email, nil
+	if err := r.DB.WithContext(ctx).Where(&model.WarehouseExport{ProjectID: project.ID}).Delete(&model.WarehouseExport{}).Error; err != nil {
+		return false, e.Wrap(err, "error deleting warehouse export")
+	}
+	return true, nil
 }
 
-// EditSegment is the resolver for the editSegment field.
-func (r *mutationResolver) EditSegment(ctx context.Context, id int, projectID int, query string, name string) (*bool, error) {
-	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+// UpsertSourcemapBucket is the resolver for the upsertSourcemapBucket field.
+func (r *mutationResolver) UpsertSourcemapBucket(ctx context.Context, projectID int, input modelInputs.SourcemapBucketInput) (*model.SourcemapBucket, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
 		return nil, err
 	}
-	modelParams := SavedSegmentQueryToParams(query)
-	// Convert to json to store in the db.
-	paramBytes, err := json.Marshal(modelParams)
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling search params")
+		return nil, err
 	}
-	paramString := string(paramBytes)
 
-	// check if such a segment exists
-	var count int64
-	if err := r.DB.WithContext(ctx).Model(&model.Segment{}).Where("project_id = ? AND name = ? AND id <> ?", projectID, name, id).Count(&count).Error; err != nil {
-		return nil, e.Wrap(err, "error checking if segment exists")
+	bucket := &model.SourcemapBucket{ProjectID: project.ID}
+	if existing, err := r.Store.GetSourcemapBucket(ctx, project.ID); err == nil {
+		bucket.ServiceAccountKey = existing.ServiceAccountKey
+	} else if !e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, e.Wrap(err, "error querying sourcemap bucket")
 	}
-	if count > 0 {
-		return nil, e.New("segment with this name already exists")
+	if err := applySourcemapBucketInput(input, bucket); err != nil {
+		return nil, err
 	}
+	bucket.LastAdminToEditID = admin.ID
 
-	if err := r.DB.WithContext(ctx).Model(&model.Segment{Model: model.Model{ID: id}}).Updates(&model.Segment{
-		Params: &paramString,
-		Name:   &name,
-	}).Error; err != nil {
-		return nil, e.Wrap(err, "error writing new recording settings")
+	if err := r.Store.UpsertSourcemapBucket(ctx, bucket); err != nil {
+		return nil, e.Wrap(err, "error saving sourcemap bucket")
 	}
-
-	return &model.T, nil
+	return bucket, nil
 }
 
-// DeleteSegment is the resolver for the deleteSegment field.
-func (r *mutationResolver) DeleteSegment(ctx context.Context, segmentID int) (*bool, error) {
-	_, err := r.isAdminSegmentOwner(ctx, segmentID)
+// DeleteSourcemapBucket is the resolver for the deleteSourcemapBucket field.
+func (r *mutationResolver) DeleteSourcemapBucket(ctx context.Context, projectID int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, e.Wrap(err, "admin is not segment owner")
+		return false, err
 	}
-	if err := r.DB.Delete(&model.Segment{Model: model.Model{ID: segmentID}}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting segment")
+
+	if err := r.Store.DeleteSourcemapBucket(ctx, project.ID); err != nil {
+		return false, e.Wrap(err, "error deleting sourcemap bucket")
 	}
-	return &model.T, nil
+	return true, nil
 }
 
-// CreateErrorSegment is the resolver for the createErrorSegment field.
-func (r *mutationResolver) CreateErrorSegment(ctx context.Context, projectID int, name string, query string) (*model.ErrorSegment, error) {
-	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
-		return nil, err
+// SetChaosFaults is the resolver for the setChaosFaults field.
+func (r *mutationResolver) SetChaosFaults(ctx context.Context, faults []*modelInputs.ChaosFaultInput) ([]*modelInputs.ChaosFault, error) {
+	if !r.isWhitelistedAccount(ctx) {
+		return nil, AuthorizationError
 	}
-	modelParams := SavedSegmentQueryToParams(query)
-	// Convert to json to store in the db.
-	paramBytes, err := json.Marshal(modelParams)
+
+	chaosFaults, err := newChaosFaults(faults)
 	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling search params")
+		return nil, err
 	}
-	paramString := string(paramBytes)
+	if err := chaos.New(r.Redis).SetFaults(ctx, chaosFaults); err != nil {
+		return nil, e.Wrap(err, "error setting chaos faults")
+	}
+	log.WithContext(ctx).WithField("faults", chaosFaults).Warn("chaos faults updated")
+	return chaosFaultsOutput(chaosFaults), nil
+}
 
-	// check if such a segment exists
-	var count int64
-	if err := r.DB.WithContext(ctx).Model(&model.ErrorSegment{}).Where("project_id = ? AND name = ?", projectID, name).Count(&count).Error; err != nil {
-		return nil, e.Wrap(err, "error checking if segment exists")
+// ClearChaosFaults is the resolver for the clearChaosFaults field.
+func (r *mutationResolver) ClearChaosFaults(ctx context.Context) (bool, error) {
+	if !r.isWhitelistedAccount(ctx) {
+		return false, AuthorizationError
 	}
-	if count > 0 {
-		return nil, e.New("segment with this name already exists")
+
+	if err := chaos.New(r.Redis).ClearFaults(ctx); err != nil {
+		return false, e.Wrap(err, "error clearing chaos faults")
 	}
+	log.WithContext(ctx).Warn("chaos faults cleared")
+	return true, nil
+}
 
-	segment := &model.ErrorSegment{
-		Name:      &name,
-		Params:    &paramString,
-		ProjectID: projectID,
+// UpdateDigestSetting is the resolver for the updateDigestSetting field.
+func (r *mutationResolver) UpdateDigestSetting(ctx context.Context, projectID int, input modelInputs.ProjectDigestSettingInput) (*model.ProjectDigestSetting, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
-	if err := r.DB.WithContext(ctx).Create(segment).Error; err != nil {
-		return nil, e.Wrap(err, "error creating segment")
+
+	setting, err := newDigestSetting(project.ID, input)
+	if err != nil {
+		return nil, err
 	}
-	return segment, nil
+	if err := r.Store.UpdateProjectDigestSetting(ctx, setting); err != nil {
+		return nil, e.Wrap(err, "error updating digest setting")
+	}
+	return setting, nil
 }
 
-// EditErrorSegment is the resolver for the editErrorSegment field.
-func (r *mutationResolver) EditErrorSegment(ctx context.Context, id int, projectID int, query string, name string) (*bool, error) {
-	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+// UpdateWebhookSettings is the resolver for the updateWebhookSettings field.
+func (r *mutationResolver) UpdateWebhookSettings(ctx context.Context, projectID int, maxRetries int) (*modelInputs.WebhookSettings, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
 		return nil, err
 	}
-	modelParams := SavedSegmentQueryToParams(query)
-	// Convert to json to store in the db.
-	paramBytes, err := json.Marshal(modelParams)
+	if maxRetries < 0 || maxRetries > webhook.MaxRetries {
+		return nil, e.Errorf("max_retries must be between 0 and %d", webhook.MaxRetries)
+	}
+
+	settings, err := r.Store.UpdateProjectWebhookSettings(ctx, project.ID, store.UpdateProjectWebhookSettingsParams{
+		MaxRetries: &maxRetries,
+	})
 	if err != nil {
-		return nil, e.Wrap(err, "error unmarshaling search params")
+		return nil, e.Wrap(err, "error updating project webhook settings")
 	}
-	paramString := string(paramBytes)
+	return webhookSettings(settings), nil
+}
 
-	var count int64
-	if err := r.DB.WithContext(ctx).Model(&model.ErrorSegment{}).Where("project_id = ? AND name = ? AND id <> ?", projectID, name, id).Count(&count).Error; err != nil {
-		return nil, e.Wrap(err, "error checking if segment exists")
+// RotateWebhookSigningSecret is the resolver for the rotateWebhookSigningSecret field.
+func (r *mutationResolver) RotateWebhookSigningSecret(ctx context.Context, projectID int) (*modelInputs.WebhookSettings, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
-	if count > 0 {
-		return nil, e.New("segment with this name already exists")
+
+	secret, err := generateWebhookSigningSecret()
+	if err != nil {
+		return nil, err
 	}
+	settings, err := r.Store.UpdateProjectWebhookSettings(ctx, project.ID, store.UpdateProjectWebhookSettingsParams{
+		SigningSecret: &secret,
+	})
+	if err != nil {
+		return nil, e.Wrap(err, "error updating project webhook signing secret")
+	}
+	return webhookSettings(settings), nil
+}
 
-	if err := r.DB.WithContext(ctx).Model(&model.ErrorSegment{Model: model.Model{ID: id}}).Updates(&model.ErrorSegment{
-		Params: &paramString,
-		Name:   &name,
+// EditWorkspace is the resolver for the editWorkspace field.
+func (r *mutationResolver) EditWorkspace(ctx context.Context, id int, name *string) (*model.Workspace, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying workspace")
+	}
+	if err := r.DB.WithContext(ctx).Model(workspace).Updates(&model.Workspace{
+		Name: name,
 	}).Error; err != nil {
-		return nil, e.Wrap(err, "error writing new recording settings")
+		return nil, e.Wrap(err, "error updating workspace fields")
 	}
-	return &model.T, nil
+	return workspace, nil
 }
 
-// DeleteErrorSegment is the resolver for the deleteErrorSegment field.
-func (r *mutationResolver) DeleteErrorSegment(ctx context.Context, segmentID int) (*bool, error) {
-	_, err := r.isAdminErrorSegmentOwner(ctx, segmentID)
+// EditWorkspaceSettings is the resolver for the editWorkspaceSettings field.
+func (r *mutationResolver) EditWorkspaceSettings(ctx context.Context, workspaceID int, aiApplication *bool, aiInsights *bool) (*model.AllWorkspaceSettings, error) {
+	_, err := r.isAdminInWorkspace(ctx, workspaceID)
 	if err != nil {
-		return nil, e.Wrap(err, "admin is not error segment owner")
+		return nil, err
 	}
-	if err := r.DB.Delete(&model.ErrorSegment{Model: model.Model{ID: segmentID}}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting segment")
+
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageWorkspace); err != nil {
+		return nil, err
 	}
-	return &model.T, nil
-}
 
-// CreateSavedSegment is the resolver for the createSavedSegment field.
-func (r *mutationResolver) CreateSavedSegment(ctx context.Context, projectID int, name string, entityType modelInputs.SavedSegmentEntityType, query string) (*model.SavedSegment, error) {
-	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+	workspaceSettings := &model.AllWorkspaceSettings{}
+	workspaceSettingsUpdates := map[string]interface{}{
+		"AIApplication": *aiApplication,
+		"AIInsights":    *aiInsights,
+	}
+
+	if err := r.DB.WithContext(ctx).Where(&model.AllWorkspaceSettings{WorkspaceID: workspaceID}).Take(&workspaceSettings).Updates(&workspaceSettingsUpdates).Error; err != nil {
 		return nil, err
 	}
-	modelParams := SavedSegmentQueryToParams(query)
-	// Convert to json to store in the db.
-	paramBytes, err := json.Marshal(modelParams)
+	return workspaceSettings, nil
+}
+
+// CreateAPIToken is the resolver for the createAPIToken field.
+func (r *mutationResolver) CreateAPIToken(ctx context.Context, workspaceID *int, input modelInputs.APITokenInput) (*model.CreatedAPIToken, error) {
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	paramString := string(paramBytes)
+	// workspace tokens act with the role of the admin creating them in the workspace
+	if workspaceID != nil {
+		if err := r.authorizeWorkspace(ctx, *workspaceID, rbac.PermissionManageWorkspace); err != nil {
+			return nil, err
+		}
+	}
 
-	// check if such a segment exists
-	var count int64
-	if err := r.DB.WithContext(ctx).Model(&model.SavedSegment{}).Where("project_id = ? AND name = ? AND entity_type = ?", projectID, name, entityType).Count(&count).Error; err != nil {
+	apiToken := &model.APIToken{WorkspaceID: workspaceID, AdminID: admin.ID}
+	if err := applyAPITokenInput(input, apiToken); err != nil {
 		return nil, err
 	}
-	if count > 0 {
-		return nil, e.New("saved segment with this name already exists")
+	if err := r.authorizeAPITokenScopes(ctx, apiToken); err != nil {
+		return nil, err
+	}
+	token, err := setAPITokenSecret(apiToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.DB.WithContext(ctx).Create(apiToken).Error; err != nil {
+		return nil, e.Wrap(err, "error creating api token")
 	}
+	return &model.CreatedAPIToken{APIToken: apiToken, Token: token}, nil
+}
 
-	savedSegment := &model.SavedSegment{
-		ProjectID:  projectID,
-		Name:       name,
-		EntityType: entityType,
-		Params:     paramString,
+// RotateAPIToken is the resolver for the rotateAPIToken field.
+func (r *mutationResolver) RotateAPIToken(ctx context.Context, id int) (*model.CreatedAPIToken, error) {
+	apiToken, err := r.getManagedAPIToken(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if err := r.DB.WithContext(ctx).Create(savedSegment).Error; err != nil {
+
+	// the previous token stops working immediately
+	token, err := setAPITokenSecret(apiToken)
+	if err != nil {
 		return nil, err
 	}
-	return savedSegment, nil
+	if err := r.DB.WithContext(ctx).Model(apiToken).Select("token_hash", "prefix").Updates(apiToken).Error; err != nil {
+		return nil, e.Wrap(err, "error rotating api token")
+	}
+	return &model.CreatedAPIToken{APIToken: apiToken, Token: token}, nil
 }
 
-// EditSavedSegment is the resolver for the editSavedSegment field.
-func (r *mutationResolver) EditSavedSegment(ctx context.Context, id int, projectID int, name string, entityType modelInputs.SavedSegmentEntityType, query string) (*bool, error) {
-	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+// RevokeAPIToken is the resolver for the revokeAPIToken field.
+func (r *mutationResolver) RevokeAPIToken(ctx context.Context, id int) (bool, error) {
+	apiToken, err := r.getManagedAPIToken(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.DB.WithContext(ctx).Model(apiToken).Update("revoked_at", time.Now()).Error; err != nil {
+		return false, e.Wrap(err, "error revoking api token")
+	}
+	return true, nil
+}
+
+// CreateWorkspaceRole is the resolver for the createWorkspaceRole field.
+func (r *mutationResolver) CreateWorkspaceRole(ctx context.Context, workspaceID int, input modelInputs.WorkspaceRoleInput) (*modelInputs.Role, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageRoles); err != nil {
 		return nil, err
 	}
-	modelParams := SavedSegmentQueryToParams(query)
-	// Convert to json to store in the db.
-	paramBytes, err := json.Marshal(modelParams)
+	role, err := r.newWorkspaceRole(ctx, workspaceID, input.Name, input)
 	if err != nil {
 		return nil, err
 	}
-	paramString := string(paramBytes)
 
-	var count int64
-	if err := r.DB.WithContext(ctx).Model(&model.SavedSegment{}).Where("project_id = ? AND name = ? AND entity_type = ? AND id <> ?", projectID, name, entityType, id).Count(&count).Error; err != nil {
-		return nil, err
+	var existing int64
+	if err := r.DB.WithContext(ctx).Model(&model.WorkspaceRole{}).
+		Where(&model.WorkspaceRole{WorkspaceID: workspaceID, Name: role.Name}).Count(&existing).Error; err != nil {
+		return nil, e.Wrap(err, "error querying workspace roles")
 	}
-	if count > 0 {
-		return nil, e.New("saved segment with this name already exists")
+	if existing > 0 {
+		return nil, e.New("a role with this name already exists")
 	}
 
-	if err := r.DB.WithContext(ctx).Model(&model.SavedSegment{}).Where("id = ?", id).Updates(&model.SavedSegment{
-		Params: paramString,
-		Name:   name,
-	}).Error; err != nil {
-		return nil, err
+	workspaceRole := &model.WorkspaceRole{
+		WorkspaceID: workspaceID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissionStrings(role.Permissions),
 	}
-	return &model.T, nil
+	if err := r.DB.WithContext(ctx).Create(workspaceRole).Error; err != nil {
+		return nil, e.Wrap(err, "error creating workspace role")
+	}
+	return workspaceRoleOutput(workspaceRole), nil
 }
 
-// DeleteSavedSegment is the resolver for the deleteSavedSegment field.
-func (r *mutationResolver) DeleteSavedSegment(ctx context.Context, segmentID int) (*bool, error) {
-	_, err := r.isAdminSavedSegmentOwner(ctx, segmentID)
+// UpdateWorkspaceRole is the resolver for the updateWorkspaceRole field.
+func (r *mutationResolver) UpdateWorkspaceRole(ctx context.Context, workspaceID int, id int, input modelInputs.WorkspaceRoleInput) (*modelInputs.Role, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageRoles); err != nil {
+		return nil, err
+	}
+	workspaceRole, err := r.getWorkspaceCustomRole(ctx, workspaceID, id)
 	if err != nil {
 		return nil, err
 	}
-	if err := r.DB.Where("id = ?", segmentID).Delete(&model.SavedSegment{}).Error; err != nil {
+	// roles cannot be renamed, as admins and invites reference them by name
+	role, err := r.newWorkspaceRole(ctx, workspaceID, workspaceRole.Name, input)
+	if err != nil {
 		return nil, err
 	}
-	return &model.T, nil
+
+	workspaceRole.Description = role.Description
+	workspaceRole.Permissions = permissionStrings(role.Permissions)
+	if err := r.DB.WithContext(ctx).Save(workspaceRole).Error; err != nil {
+		return nil, e.Wrap(err, "error saving workspace role")
+	}
+	return workspaceRoleOutput(workspaceRole), nil
 }
 
-// CreateOrUpdateStripeSubscription is the resolver for the createOrUpdateStripeSubscription field.
-func (r *mutationResolver) CreateOrUpdateStripeSubscription(ctx context.Context, workspaceID int) (*string, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// DeleteWorkspaceRole is the resolver for the deleteWorkspaceRole field.
+func (r *mutationResolver) DeleteWorkspaceRole(ctx context.Context, workspaceID int, id int) (bool, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageRoles); err != nil {
+		return false, err
+	}
+	workspaceRole, err := r.getWorkspaceCustomRole(ctx, workspaceID, id)
 	if err != nil {
-		return nil, e.Wrap(err, "admin is not in workspace")
+		return false, err
 	}
 
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
-		return nil, e.Wrap(err, "must have ADMIN role to create/update stripe subscription")
+	var assigned int64
+	if err := r.DB.WithContext(ctx).Model(&model.WorkspaceAdmin{}).
+		Where(&model.WorkspaceAdmin{WorkspaceID: workspaceID, Role: &workspaceRole.Name}).Count(&assigned).Error; err != nil {
+		return false, e.Wrap(err, "error querying admins of workspace role")
+	}
+	if assigned > 0 {
+		return false, e.New("change the role of the admins with this role before deleting it")
 	}
 
-	// For older projects, if there's no customer ID, we create a StripeCustomer obj.
-	if workspace.StripeCustomerID == nil {
-		params := &stripe.CustomerParams{}
-		c, err := r.StripeClient.Customers.New(params)
-		if err != nil {
-			log.WithContext(ctx).Error(err, "error creating stripe customer")
-		}
-		if err := r.DB.WithContext(ctx).Model(&workspace).Updates(&model.Workspace{
-			StripeCustomerID: &c.ID,
-		}).Error; err != nil {
-			return nil, e.Wrap(err, "error updating org fields")
+	if err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(&model.WorkspaceInviteLink{WorkspaceID: &workspaceID, InviteeRole: &workspaceRole.Name}).Delete(&model.WorkspaceInviteLink{}).Error; err != nil {
+			return e.Wrap(err, "error deleting invites with workspace role")
 		}
-		workspace.StripeCustomerID = &c.ID
+		return e.Wrap(tx.Delete(workspaceRole).Error, "error deleting workspace role")
+	}); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	params := &stripe.CustomerParams{}
-	params.AddExpand("subscriptions")
+// UpdateWorkspaceSSOConfig is the resolver for the updateWorkspaceSSOConfig field.
+func (r *mutationResolver) UpdateWorkspaceSSOConfig(ctx context.Context, workspaceID int, input modelInputs.SSOConfigInput) (*modelInputs.SSOConfig, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageWorkspace); err != nil {
+		return nil, err
+	}
 
-	c, err := r.StripeClient.Customers.Get(*workspace.StripeCustomerID, params)
-	if err != nil {
-		return nil, e.Wrap(err, "STRIPE_INTEGRATION_ERROR cannot update stripe subscription - couldn't retrieve stripe customer data")
+	if err := r.checkSSODomainUnclaimed(ctx, workspaceID, strings.ToLower(strings.TrimSpace(input.Domain))); err != nil {
+		return nil, err
 	}
 
-	// If there are multiple subscriptions, it's ambiguous which one should be updated, so throw an error
-	if len(c.Subscriptions.Data) > 1 {
-		return nil, e.New("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - customer has multiple subscriptions")
+	config, err := r.getSSOConfig(ctx, workspaceID)
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		config, err = &model.WorkspaceSSOConfig{WorkspaceID: workspaceID}, nil
+	}
+	if err != nil {
+		return nil, e.Wrap(err, "error querying sso config")
+	}
+	if err := applySSOConfigInput(ctx, input, config); err != nil {
+		return nil, err
+	}
+	if err := r.DB.WithContext(ctx).Save(config).Error; err != nil {
+		return nil, e.Wrap(err, "error saving sso config")
 	}
+	return ssoConfigOutput(config), nil
+}
 
-	subscriptions := c.Subscriptions.Data
-	pricing.FillProducts(r.StripeClient, subscriptions)
+// VerifyWorkspaceSSODomain is the resolver for the verifyWorkspaceSSODomain field.
+func (r *mutationResolver) VerifyWorkspaceSSODomain(ctx context.Context, workspaceID int) (*modelInputs.SSOConfig, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageWorkspace); err != nil {
+		return nil, err
+	}
 
-	pricingInterval := model.PricingSubscriptionIntervalMonthly
+	config, err := r.getSSOConfig(ctx, workspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying sso config")
+	}
+	if err := r.verifySSODomain(ctx, config); err != nil {
+		return nil, err
+	}
+	return ssoConfigOutput(config), nil
+}
 
-	defaultRetention := modelInputs.RetentionPeriodThreeMonths
+// RotateWorkspaceSCIMToken is the resolver for the rotateWorkspaceSCIMToken field.
+func (r *mutationResolver) RotateWorkspaceSCIMToken(ctx context.Context, workspaceID int) (string, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageWorkspace); err != nil {
+		return "", err
+	}
 
-	// default to unlimited members pricing
-	prices, err := pricing.GetStripePrices(r.StripeClient, workspace, modelInputs.PlanTypeGraduated, pricingInterval, true, &defaultRetention, &defaultRetention)
+	tokenBytes, err := GenerateRandomBytes(32)
 	if err != nil {
-		return nil, e.Wrap(err, "STRIPE_INTEGRATION_ERROR cannot update stripe subscription - failed to get Stripe prices")
+		return "", e.Wrap(err, "error generating scim token")
+	}
+	token := hex.EncodeToString(tokenBytes)
+	tx := r.DB.WithContext(ctx).Model(&model.WorkspaceSSOConfig{}).
+		Where(&model.WorkspaceSSOConfig{WorkspaceID: workspaceID}).
+		Update("scim_token", token)
+	if tx.Error != nil {
+		return "", e.Wrap(tx.Error, "error saving scim token")
+	}
+	if tx.RowsAffected == 0 {
+		return "", e.New("single sign-on is not configured for this workspace")
 	}
+	// the token is only returned once
+	return token, nil
+}
 
-	newBasePrice := prices[model.PricingProductTypeBase]
+// DeleteWorkspaceSSOConfig is the resolver for the deleteWorkspaceSSOConfig field.
+func (r *mutationResolver) DeleteWorkspaceSSOConfig(ctx context.Context, workspaceID int) (bool, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageWorkspace); err != nil {
+		return false, err
+	}
 
-	// If there's an existing subscription, update it
-	if len(subscriptions) == 1 {
-		subscription := subscriptions[0]
-		if len(subscription.Items.Data) != 1 {
-			return nil, e.New("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - subscription has multiple products")
-		}
+	// admins that were signed in or provisioned remain in the workspace
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceSSOConfig{WorkspaceID: workspaceID}).Delete(&model.WorkspaceSSOConfig{}).Error; err != nil {
+		return false, e.Wrap(err, "error deleting sso config")
+	}
+	return true, nil
+}
 
-		subscriptionItem := subscription.Items.Data[0]
-		productType, _, _, _, _ := pricing.GetProductMetadata(subscriptionItem.Price)
-		if productType == nil {
-			return nil, e.New(fmt.Sprintf("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - nil product from sub %s price %s", subscription.ID, subscriptionItem.Price.ID))
-		}
-		if *productType != model.PricingProductTypeBase {
-			return nil, e.New(fmt.Sprintf("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - expecting base product from sub %s price %s: %s", subscription.ID, subscriptionItem.Price.ID, *productType))
-		}
+// ExportSession is the resolver for the exportSession field.
+func (r *mutationResolver) ExportSession(ctx context.Context, sessionSecureID string) (bool, error) {
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return false, err
+	}
 
-		subscriptionParams := &stripe.SubscriptionParams{
-			CancelAtPeriodEnd: stripe.Bool(false),
-			ProrationBehavior: stripe.String(string(stripe.SubscriptionSchedulePhaseProrationBehaviorCreateProrations)),
-			Items: []*stripe.SubscriptionItemsParams{
-				{
-					ID:   &subscriptionItem.ID,
-					Plan: &newBasePrice.ID,
-				},
-			},
-		}
+	session, err := r.canAdminViewSession(ctx, sessionSecureID)
+	if err != nil {
+		return false, err
+	}
 
-		_, err := r.StripeClient.Subscriptions.Update(subscription.ID, subscriptionParams)
-		if err != nil {
-			return nil, e.Wrap(err, "couldn't update subscription")
-		}
-		ret := ""
-		return &ret, nil
+	cfg, err := r.Store.GetAllWorkspaceSettingsByProject(ctx, session.ProjectID)
+	if !cfg.EnableSessionExport {
+		return false, e.New("session export is not enabled")
 	}
 
-	// If there's no existing subscription, we create a checkout.
-	checkoutSessionParams := &stripe.CheckoutSessionParams{
-		SuccessURL: stripe.String(os.Getenv("FRONTEND_URI") + "/w/" + strconv.Itoa(workspaceID) + "/current-plan/success"),
-		CancelURL:  stripe.String(os.Getenv("FRONTEND_URI") + "/w/" + strconv.Itoa(workspaceID) + "/current-plan/update-plan"),
-		PaymentMethodTypes: stripe.StringSlice([]string{
-			"card",
-		}),
-		Customer: workspace.StripeCustomerID,
-		LineItems: []*stripe.CheckoutSessionLineItemParams{
-			{
-				Price:    &newBasePrice.ID,
-				Quantity: stripe.Int64(1),
-			},
-		},
-		Mode: stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+	export := model.SessionExport{
+		SessionID:    session.ID,
+		Type:         model.SessionExportFormatMP4,
+		TargetEmails: []string{*admin.Email},
 	}
-	checkoutSessionParams.AddExtra("allow_promotion_codes", "true")
 
-	stripeSession, err := r.StripeClient.CheckoutSessions.New(checkoutSessionParams)
+	tx := r.DB.WithContext(ctx).Model(&export).Where(&model.SessionExport{
+		SessionID: session.ID,
+		Type:      model.SessionExportFormatMP4,
+	}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_emails"}),
+	}).FirstOrCreate(&export)
+	if tx.Error != nil {
+		log.WithContext(context.Background()).WithError(tx.Error).Error("failed to create session export record")
+		return false, tx.Error
+	}
+
+	_, err = r.StepFunctions.SessionExport(ctx, utils2.SessionExportInput{
+		Project:      session.ProjectID,
+		Session:      session.ID,
+		Format:       export.Type,
+		TargetEmails: export.TargetEmails,
+	})
 	if err != nil {
-		return nil, e.Wrap(err, "error creating CheckoutSession in stripe")
+		log.WithContext(context.Background()).WithError(err).Error("failed to export session video")
+		return false, err
 	}
 
-	return &stripeSession.ID, nil
+	return true, nil
 }
 
-// UpdateBillingDetails is the resolver for the updateBillingDetails field.
-func (r *mutationResolver) UpdateBillingDetails(ctx context.Context, workspaceID int) (*bool, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// MarkErrorGroupAsViewed is the resolver for the markErrorGroupAsViewed field.
+func (r *mutationResolver) MarkErrorGroupAsViewed(ctx context.Context, errorSecureID string, viewed *bool) (*model.ErrorGroup, error) {
+	eg, err := r.canAdminModifyErrorGroup(ctx, errorSecureID)
 	if err != nil {
-		return nil, e.Wrap(err, "admin is not in workspace")
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
-		return nil, e.Wrap(err, "must have ADMIN role to update billing details")
+	// Update the the number of error groups viewed for the current admin.
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		ctx := context.Background()
+		// Check if this admin has already viewed
+		if _, err := r.isAdminInProject(ctx, eg.ProjectID); err != nil {
+			log.WithContext(ctx).Infof("not adding error groups count to admin in hubspot; this is probably a demo project, with id [%v]", eg.ProjectID)
+			return
+		}
+		var currentErrorGroupCount int64
+		if err := r.DB.WithContext(ctx).Raw(`
+			select count(*)
+			from error_group_admins_views
+			where error_group_id = ? and admin_id = ?
+	`, eg.ID, admin.ID).Scan(&currentErrorGroupCount).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error querying count of error group views from admin"))
+			return
+		} else if currentErrorGroupCount > 0 {
+			log.WithContext(ctx).Info("not updating hubspot error group count; admin has already viewed this error group")
+			return
+		}
+
+		var totalErrorGroupCount int64
+		if err := r.DB.WithContext(ctx).Raw(`
+			select count(*)
+			from error_group_admins_views
+			where admin_id = ?
+	`, admin.ID).Scan(&totalErrorGroupCount).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error querying total count of error group views from admin"))
+			return
+		}
+		totalErrorGroupCountAsInt := int(totalErrorGroupCount) + 1
+
+		if err := r.DB.WithContext(ctx).Where(admin).Updates(&model.Admin{NumberOfErrorGroupsViewed: &totalErrorGroupCountAsInt}).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error updating error group count for admin in postgres"))
+		}
+		phonehome.ReportUsageMetrics(ctx, phonehome.AdminUsage, admin.ID, []attribute.KeyValue{
+			attribute.Int(phonehome.ErrorViewCount, totalErrorGroupCountAsInt),
+		})
+	})
+
+	newErrorGroup := &model.ErrorGroup{}
+	updatedFields := &model.ErrorGroup{
+		Viewed: viewed,
+	}
+	if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{Model: model.Model{ID: eg.ID}}).Take(&newErrorGroup).Updates(updatedFields).Error; err != nil {
+		return nil, e.Wrap(err, "error writing error as viewed")
 	}
 
-	if err := r.updateBillingDetails(ctx, *workspace.StripeCustomerID); err != nil {
-		return nil, e.Wrap(err, "error updating billing details")
+	if err := r.DB.WithContext(ctx).Model(&eg).Association("ViewedByAdmins").Append(admin); err != nil {
+		return nil, e.Wrap(err, "error adding admin to ViewedByAdmins")
 	}
 
-	return &model.T, nil
+	return newErrorGroup, nil
 }
 
-// SaveBillingPlan is the resolver for the saveBillingPlan field.
-func (r *mutationResolver) SaveBillingPlan(ctx context.Context, workspaceID int, sessionsLimitCents *int, sessionsRetention modelInputs.RetentionPeriod, errorsLimitCents *int, errorsRetention modelInputs.RetentionPeriod, logsLimitCents *int, logsRetention modelInputs.RetentionPeriod, tracesLimitCents *int, tracesRetention modelInputs.RetentionPeriod) (*bool, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// MarkSessionAsViewed is the resolver for the markSessionAsViewed field.
+func (r *mutationResolver) MarkSessionAsViewed(ctx context.Context, secureID string, viewed *bool) (*model.Session, error) {
+	s, err := r.canAdminModifySession(ctx, secureID)
 	if err != nil {
-		return nil, e.Wrap(err, "admin is not in workspace")
+		return nil, err
 	}
-
-	settings, err := r.Store.GetAllWorkspaceSettings(ctx, workspaceID)
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	columns := []interface{}{"errors_retention_period", "retention_period"}
-	if settings.EnableBillingLimits {
-		columns = append(columns, "sessions_max_cents", "errors_max_cents", "logs_max_cents", "traces_max_cents")
-	}
-	if err := r.DB.WithContext(ctx).Model(&workspace).
-		Select(columns[0], columns[1:]...).
-		Updates(&model.Workspace{
-			SessionsMaxCents:      sessionsLimitCents,
-			RetentionPeriod:       &sessionsRetention,
-			ErrorsMaxCents:        errorsLimitCents,
-			ErrorsRetentionPeriod: &errorsRetention,
-			LogsMaxCents:          logsLimitCents,
-			LogsRetentionPeriod:   &logsRetention,
-			TracesMaxCents:        tracesLimitCents,
-			TracesRetentionPeriod: &tracesRetention,
-		}).Error; err != nil {
-		return nil, e.Wrap(err, "error updating workspace")
-	}
+	// Update the the number of sessions viewed for the current admin.
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		ctx := context.Background()
+		// Check if this admin has already viewed
+		if _, err := r.isAdminInProject(ctx, s.ProjectID); err != nil {
+			log.WithContext(ctx).Infof("not adding session count to admin in hubspot; this is probably a demo project, with id [%v]", s.ProjectID)
+			return
+		}
+		var currentSessionCount int64
+		if err := r.DB.WithContext(ctx).Raw(`
+			select count(*)
+			from session_admins_views
+			where session_id = ? and admin_id = ?
+	`, s.ID, admin.ID).Scan(&currentSessionCount).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error querying count of session views from admin"))
+			return
+		} else if currentSessionCount > 0 {
+			log.WithContext(ctx).Info("not updating hubspot session count; admin has already viewed this session")
+			return
+		}
 
-	return pointy.Bool(true), nil
-}
+		var totalSessionCount int64
+		if err := r.DB.WithContext(ctx).Raw(`
+			select count(*)
+			from session_admins_views
+			where admin_id = ?
+	`, admin.ID).Scan(&totalSessionCount).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error querying total count of session views from admin"))
+			return
+		}
+		totalSessionCountAsInt := int(totalSessionCount) + 1
 
-// CreateSessionComment is the resolver for the createSessionComment field.
-func (r *mutationResolver) CreateSessionComment(ctx context.Context, projectID int, sessionSecureID string, sessionTimestamp int, text string, textForEmail string, xCoordinate float64, yCoordinate float64, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput, sessionURL string, time float64, authorName string, sessionImage *string, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType, tags []*modelInputs.SessionCommentTagInput, additionalContext *string) (*model.SessionComment, error) {
-	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
+		if err := r.DB.WithContext(ctx).Where(admin).Updates(&model.Admin{NumberOfSessionsViewed: &totalSessionCountAsInt}).Error; err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error updating session count for admin in postgres"))
+		}
+		phonehome.ReportUsageMetrics(ctx, phonehome.AdminUsage, admin.ID, []attribute.KeyValue{
+			attribute.Int(phonehome.SessionViewCount, totalSessionCountAsInt),
+		})
+	})
 
-	// All viewers can leave a comment, including guests
-	session, err := r.canAdminViewSession(ctx, sessionSecureID)
-	if err != nil {
-		return nil, err
+	newSession := &model.Session{}
+	updatedFields := &model.Session{
+		Viewed: viewed,
+	}
+	if err := r.DB.WithContext(ctx).Where(&model.Session{Model: model.Model{ID: s.ID}}).Take(&newSession).Updates(updatedFields).Error; err != nil {
+		return nil, e.Wrap(err, "error writing session as viewed")
 	}
 
-	var project model.Project
-	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&s).Association("ViewedByAdmins").Append(admin); err != nil {
+		return nil, e.Wrap(err, "error adding admin to ViewedByAdmins")
+	}
+
+	if err := r.DataSyncQueue.Submit(ctx, strconv.Itoa(s.ID), &kafka_queue.Message{Type: kafka_queue.SessionDataSync, SessionDataSync: &kafka_queue.SessionDataSyncArgs{SessionID: s.ID}}); err != nil {
 		return nil, err
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	return newSession, nil
+}
+
+// UpdateErrorGroupState is the resolver for the updateErrorGroupState field.
+func (r *mutationResolver) UpdateErrorGroupState(ctx context.Context, secureID string, state modelInputs.ErrorState, snoozedUntil *time.Time) (*model.ErrorGroup, error) {
+	errorGroup, err := r.canAdminModifyErrorGroup(ctx, secureID)
 	if err != nil {
 		return nil, err
 	}
+	admin, err := r.getCurrentAdmin(ctx)
 
-	admins := r.getTaggedAdmins(taggedAdmins, isGuest)
+	updatedErrorGroup, err := r.Store.UpdateErrorGroupStateByAdmin(ctx, *admin, store.UpdateErrorGroupParams{
+		ID:           errorGroup.ID,
+		State:        state,
+		SnoozedUntil: snoozedUntil,
+	})
 
-	sessionImageStr := ""
-	if sessionImage != nil {
-		sessionImageStr = *sessionImage
-	}
+	return &updatedErrorGroup, err
+}
 
-	if sessionTimestamp >= math.MaxInt32 {
-		log.WithContext(ctx).Warnf("attempted to create session with invalid timestamp %d", sessionTimestamp)
-		sessionTimestamp = 0
+// UpdateErrorGroupAssignee is the resolver for the updateErrorGroupAssignee field.
+func (r *mutationResolver) UpdateErrorGroupAssignee(ctx context.Context, secureID string, assigneeID *int) (*model.ErrorGroup, error) {
+	errorGroup, err := r.canAdminModifyErrorGroup(ctx, secureID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if assigneeID != nil {
+		project, err := r.isAdminInProject(ctx, errorGroup.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.validateAssignee(ctx, project, *assigneeID); err != nil {
+			return nil, err
+		}
+	}
+
+	// assigning an error group overrides its ownership rules, which only assign unassigned error groups
+	updatedErrorGroup, err := r.Store.AssignErrorGroupByAdmin(ctx, *admin, store.AssignErrorGroupParams{
+		ID:         errorGroup.ID,
+		AssigneeID: assigneeID,
+	})
+	if err != nil {
+		return nil, e.Wrap(err, "error assigning error group")
+	}
+	return &updatedErrorGroup, nil
+}
+
+// MergeErrorGroups is the resolver for the mergeErrorGroups field.
+func (r *mutationResolver) MergeErrorGroups(ctx context.Context, projectID int, errorGroupSecureID string, sourceSecureIds []string) (*model.ErrorGroup, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	errorGroup, err := r.getProjectErrorGroup(ctx, project.ID, errorGroupSecureID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group")
+	}
+	if len(sourceSecureIds) == 0 {
+		return nil, e.New("source_secure_ids is required")
+	}
+
+	var sourceIDs []int
+	for _, secureID := range sourceSecureIds {
+		source, err := r.getProjectErrorGroup(ctx, project.ID, secureID)
+		if err != nil {
+			return nil, e.Errorf("error group %s not found", secureID)
+		}
+		sourceIDs = append(sourceIDs, source.ID)
+	}
+
+	mergedErrorGroup, err := r.Store.MergeErrorGroups(ctx, *admin, errorGroup.ID, sourceIDs)
+	if err != nil {
+		return nil, e.Wrap(err, "error merging error groups")
+	}
+	return &mergedErrorGroup, nil
+}
+
+// SplitErrorGroup is the resolver for the splitErrorGroup field.
+func (r *mutationResolver) SplitErrorGroup(ctx context.Context, projectID int, errorGroupSecureID string, errorObjectIds []int) (*model.ErrorGroup, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	errorGroup, err := r.getProjectErrorGroup(ctx, project.ID, errorGroupSecureID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group")
+	}
+	if len(errorObjectIds) == 0 {
+		return nil, e.New("error_object_ids is required")
+	}
+
+	newErrorGroup, err := r.Store.SplitErrorGroup(ctx, *admin, errorGroup.ID, errorObjectIds)
+	if err != nil {
+		return nil, e.Wrap(err, "error splitting error group")
+	}
+	return &newErrorGroup, nil
+}
+
+// DeleteProject is the resolver for the deleteProject field.
+func (r *mutationResolver) DeleteProject(ctx context.Context, id int) (*bool, error) {
+	_, err := r.isAdminInProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.DB.WithContext(ctx).Model(&model.Project{}).Delete("id = ?", id).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting project")
+	}
+	return &model.T, nil
+}
+
+// SendAdminWorkspaceInvite is the resolver for the sendAdminWorkspaceInvite field.
+func (r *mutationResolver) SendAdminWorkspaceInvite(ctx context.Context, workspaceID int, email string, baseURL string, role string) (*string, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitedRole, err := r.getWorkspaceRoleByName(ctx, workspaceID, role)
+	if err != nil {
+		return nil, e.Errorf("invalid role %s", role)
+	}
+
+	// the inviter cannot invite admins with permissions they do not have
+	inviterRole, err := r.getCurrentWorkspaceRole(ctx, workspaceID)
+	if err != nil || !inviterRole.Can(rbac.PermissionInviteMembers) || !inviterRole.CanGrant(invitedRole) {
+		return nil, AuthorizationError
+	}
+
+	inviteLink := r.CreateInviteLink(workspaceID, &email, role, false)
+
+	if err := r.DB.WithContext(ctx).Create(inviteLink).Error; err != nil {
+		return nil, e.Wrap(err, "error creating new invite link")
+	}
+
+	inviteLinkUrl := baseURL + "/w/" + strconv.Itoa(workspaceID) + "/invite/" + *inviteLink.Secret
+	return r.SendAdminInviteImpl(*admin.Name, *workspace.Name, inviteLinkUrl, email)
+}
+
+// AddAdminToWorkspace is the resolver for the addAdminToWorkspace field.
+func (r *mutationResolver) AddAdminToWorkspace(ctx context.Context, workspaceID int, inviteID string) (*int, error) {
+	return r.addAdminMembership(ctx, workspaceID, inviteID)
+}
+
+// DeleteInviteLinkFromWorkspace is the resolver for the deleteInviteLinkFromWorkspace field.
+func (r *mutationResolver) DeleteInviteLinkFromWorkspace(ctx context.Context, workspaceID int, workspaceInviteLinkID int) (bool, error) {
+	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageMembers); err != nil {
+		return false, e.Wrap(err, "an admin without the manage members permission tried deleting an invite.")
+	}
+
+	result := r.DB.WithContext(ctx).Where("id = ?", workspaceInviteLinkID).Where("workspace_id = ?", workspaceID).Delete(&model.WorkspaceInviteLink{})
+	if result.Error != nil {
+		return false, e.Wrap(err, "error deleting workspace invite link")
+	}
+
+	return int(result.RowsAffected) > 0, nil
+}
+
+// JoinWorkspace is the resolver for the joinWorkspace field.
+func (r *mutationResolver) JoinWorkspace(ctx context.Context, workspaceID int) (*int, error) {
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := r.getCustomVerifiedAdminEmailDomain(admin)
+	if err != nil {
+		return nil, e.Wrap(err, "error getting custom verified admin email domain")
+	}
+	// if more than one workspace has the auto join email origin, pick the first (oldest) one
+	workspace := &model.Workspace{Model: model.Model{ID: workspaceID}}
+	if err := r.DB.WithContext(ctx).Model(&workspace).Where("jsonb_exists(allowed_auto_join_email_origins::jsonb, LOWER(?))", domain).First(workspace).Error; err != nil {
+		return nil, e.Wrap(err, "error querying workspace")
+	}
+	if err := r.DB.WithContext(ctx).Model(&workspace).Association("Admins").Append(admin); err != nil {
+		return nil, e.Wrap(err, "error adding admin to association")
+	}
+	return &workspace.ID, nil
+}
+
+// UpdateAllowedEmailOrigins is the resolver for the updateAllowedEmailOrigins field.
+func (r *mutationResolver) UpdateAllowedEmailOrigins(ctx context.Context, workspaceID int, allowedAutoJoinEmailOrigins string) (*int, error) {
+	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageMembers)
+	if err != nil {
+		return nil, e.Wrap(err, "error retrieving admin user")
+	}
+
+	if !json.Valid([]byte(allowedAutoJoinEmailOrigins)) {
+		return nil, e.Wrap(err, "allowedAutoJoinEmailOrigins is not valid JSON")
+	}
+
+	if err := r.DB.WithContext(ctx).Model(&model.Workspace{Model: model.Model{ID: workspaceID}}).Updates(&model.Workspace{
+		AllowedAutoJoinEmailOrigins: &allowedAutoJoinEmailOrigins}).Error; err != nil {
+		return nil, e.Wrap(err, "error updating workspace")
+	}
+
+	return &workspaceID, nil
+}
+
+// ChangeAdminRole is the resolver for the changeAdminRole field.
+func (r *mutationResolver) ChangeAdminRole(ctx context.Context, workspaceID int, adminID int, newRole string) (bool, error) {
+	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return false, err
+	}
+
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if admin.ID == adminID {
+		return false, e.New("A admin tried changing their own role.")
+	}
+
+	currentRole, err := r.authorizeManageAdmin(ctx, workspaceID, adminID)
+	if err != nil {
+		return false, e.Wrap(err, "An admin without the manage members permission tried changing an admin role.")
+	}
+
+	role, err := r.getWorkspaceRoleByName(ctx, workspaceID, newRole)
+	if err != nil {
+		return false, e.Errorf("invalid role %s", newRole)
+	}
+	if !currentRole.CanGrant(role) {
+		return false, AuthorizationError
+	}
+
+	if err := r.DB.WithContext(ctx).Model(&model.WorkspaceAdmin{AdminID: adminID, WorkspaceID: workspaceID}).Update("Role", newRole).Error; err != nil {
+		return false, e.Wrap(err, "error updating workspace_admin role")
+	}
+
+	return true, nil
+}
+
+// DeleteAdminFromProject is the resolver for the deleteAdminFromProject field.
+func (r *mutationResolver) DeleteAdminFromProject(ctx context.Context, projectID int, adminID int) (*int, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DeleteAdminAssociation(ctx, project, adminID)
+}
+
+// DeleteAdminFromWorkspace is the resolver for the deleteAdminFromWorkspace field.
+func (r *mutationResolver) DeleteAdminFromWorkspace(ctx context.Context, workspaceID int, adminID int) (*int, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "current admin is not in workspace")
+	}
+
+	if _, err := r.authorizeManageAdmin(ctx, workspaceID, adminID); err != nil {
+		return nil, e.Wrap(err, "current admin cannot remove the admin from the workspace")
+	}
+
+	deletedAdminId, err := r.DeleteAdminAssociation(ctx, workspace, adminID)
+	if err != nil {
+		return nil, e.Wrap(err, "error deleting admin association")
+	}
+
+	return deletedAdminId, nil
+}
+
+// CreateSegment is the resolver for the createSegment field.
+func (r *mutationResolver) CreateSegment(ctx context.Context, projectID int, name string, query string) (*model.Segment, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	modelParams := SavedSegmentQueryToParams(query)
+	// Convert to json to store in the db.
+	paramBytes, err := json.Marshal(modelParams)
+	if err != nil {
+		return nil, e.Wrap(err, "error unmarshaling search params")
+	}
+	paramString := string(paramBytes)
+
+	// check if such a segment exists
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.Segment{}).Where("project_id = ? AND name = ?", projectID, name).Count(&count).Error; err != nil {
+		return nil, e.Wrap(err, "error checking if segment exists")
+	}
+	if count > 0 {
+		return nil, e.New("segment with this name already exists")
+	}
+
+	segment := &model.Segment{
+		Name:      &name,
+		Params:    &paramString,
+		ProjectID: projectID,
+	}
+	if err := r.DB.WithContext(ctx).Create(segment).Error; err != nil {
+		return nil, e.Wrap(err, "error creating segment")
+	}
+	return segment, nil
+}
+
+// EmailSignup is the resolver for the emailSignup field.
+func (r *mutationResolver) EmailSignup(ctx context.Context, email string) (string, error) {
+	short, long, err := apolloio.Enrich(email)
+	if err != nil {
+		log.WithContext(ctx).Errorf("error enriching email: %v", err)
+		return email, nil
+	}
+
+	model.DB.WithContext(ctx).Create(&model.EmailSignup{
+		Email:               email,
+		ApolloData:          *long,
+		ApolloDataShortened: *short,
+	})
+
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		ctx := context.Background()
+		if contact, err := apolloio.CreateContact(email); err != nil {
+			log.WithContext(ctx).Errorf("error creating apollo contact: %v", err)
+		} else {
+			sequenceID := "60fb134ce97fa1014c1cc141" // represents the "Landing Page Signups" sequence.
+			if err := apolloio.AddToSequence(contact.ID, sequenceID); err != nil {
+				log.WithContext(ctx).Errorf("error adding to apollo sequence: %v", err)
+			}
+		}
+	})
+
+	return email, nil
+}
+
+// EditSegment is the resolver for the editSegment field.
+func (r *mutationResolver) EditSegment(ctx context.Context, id int, projectID int, query string, name string) (*bool, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	modelParams := SavedSegmentQueryToParams(query)
+	// Convert to json to store in the db.
+	paramBytes, err := json.Marshal(modelParams)
+	if err != nil {
+		return nil, e.Wrap(err, "error unmarshaling search params")
+	}
+	paramString := string(paramBytes)
+
+	// check if such a segment exists
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.Segment{}).Where("project_id = ? AND name = ? AND id <> ?", projectID, name, id).Count(&count).Error; err != nil {
+		return nil, e.Wrap(err, "error checking if segment exists")
+	}
+	if count > 0 {
+		return nil, e.New("segment with this name already exists")
+	}
+
+	if err := r.DB.WithContext(ctx).Model(&model.Segment{Model: model.Model{ID: id}}).Updates(&model.Segment{
+		Params: &paramString,
+		Name:   &name,
+	}).Error; err != nil {
+		return nil, e.Wrap(err, "error writing new recording settings")
+	}
+
+	return &model.T, nil
+}
+
+// DeleteSegment is the resolver for the deleteSegment field.
+func (r *mutationResolver) DeleteSegment(ctx context.Context, segmentID int) (*bool, error) {
+	_, err := r.isAdminSegmentOwner(ctx, segmentID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not segment owner")
+	}
+	if err := r.DB.Delete(&model.Segment{Model: model.Model{ID: segmentID}}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting segment")
+	}
+	return &model.T, nil
+}
+
+// CreateErrorSegment is the resolver for the createErrorSegment field.
+func (r *mutationResolver) CreateErrorSegment(ctx context.Context, projectID int, name string, query string) (*model.ErrorSegment, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	modelParams := SavedSegmentQueryToParams(query)
+	// Convert to json to store in the db.
+	paramBytes, err := json.Marshal(modelParams)
+	if err != nil {
+		return nil, e.Wrap(err, "error unmarshaling search params")
+	}
+	paramString := string(paramBytes)
+
+	// check if such a segment exists
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorSegment{}).Where("project_id = ? AND name = ?", projectID, name).Count(&count).Error; err != nil {
+		return nil, e.Wrap(err, "error checking if segment exists")
+	}
+	if count > 0 {
+		return nil, e.New("segment with this name already exists")
+	}
+
+	segment := &model.ErrorSegment{
+		Name:      &name,
+		Params:    &paramString,
+		ProjectID: projectID,
+	}
+	if err := r.DB.WithContext(ctx).Create(segment).Error; err != nil {
+		return nil, e.Wrap(err, "error creating segment")
+	}
+	return segment, nil
+}
+
+// EditErrorSegment is the resolver for the editErrorSegment field.
+func (r *mutationResolver) EditErrorSegment(ctx context.Context, id int, projectID int, query string, name string) (*bool, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	modelParams := SavedSegmentQueryToParams(query)
+	// Convert to json to store in the db.
+	paramBytes, err := json.Marshal(modelParams)
+	if err != nil {
+		return nil, e.Wrap(err, "error unmarshaling search params")
+	}
+	paramString := string(paramBytes)
+
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorSegment{}).Where("project_id = ? AND name = ? AND id <> ?", projectID, name, id).Count(&count).Error; err != nil {
+		return nil, e.Wrap(err, "error checking if segment exists")
+	}
+	if count > 0 {
+		return nil, e.New("segment with this name already exists")
+	}
+
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorSegment{Model: model.Model{ID: id}}).Updates(&model.ErrorSegment{
+		Params: &paramString,
+		Name:   &name,
+	}).Error; err != nil {
+		return nil, e.Wrap(err, "error writing new recording settings")
+	}
+	return &model.T, nil
+}
+
+// DeleteErrorSegment is the resolver for the deleteErrorSegment field.
+func (r *mutationResolver) DeleteErrorSegment(ctx context.Context, segmentID int) (*bool, error) {
+	_, err := r.isAdminErrorSegmentOwner(ctx, segmentID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not error segment owner")
+	}
+	if err := r.DB.Delete(&model.ErrorSegment{Model: model.Model{ID: segmentID}}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting segment")
+	}
+	return &model.T, nil
+}
+
+// CreateSavedSegment is the resolver for the createSavedSegment field.
+func (r *mutationResolver) CreateSavedSegment(ctx context.Context, projectID int, name string, entityType modelInputs.SavedSegmentEntityType, query string) (*model.SavedSegment, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	modelParams := SavedSegmentQueryToParams(query)
+	// Convert to json to store in the db.
+	paramBytes, err := json.Marshal(modelParams)
+	if err != nil {
+		return nil, err
+	}
+	paramString := string(paramBytes)
+
+	// check if such a segment exists
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.SavedSegment{}).Where("project_id = ? AND name = ? AND entity_type = ?", projectID, name, entityType).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, e.New("saved segment with this name already exists")
+	}
+
+	savedSegment := &model.SavedSegment{
+		ProjectID:  projectID,
+		Name:       name,
+		EntityType: entityType,
+		Params:     paramString,
+	}
+	if err := r.DB.WithContext(ctx).Create(savedSegment).Error; err != nil {
+		return nil, err
+	}
+	return savedSegment, nil
+}
+
+// EditSavedSegment is the resolver for the editSavedSegment field.
+func (r *mutationResolver) EditSavedSegment(ctx context.Context, id int, projectID int, name string, entityType modelInputs.SavedSegmentEntityType, query string) (*bool, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	modelParams := SavedSegmentQueryToParams(query)
+	// Convert to json to store in the db.
+	paramBytes, err := json.Marshal(modelParams)
+	if err != nil {
+		return nil, err
+	}
+	paramString := string(paramBytes)
+
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.SavedSegment{}).Where("project_id = ? AND name = ? AND entity_type = ? AND id <> ?", projectID, name, entityType, id).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, e.New("saved segment with this name already exists")
+	}
+
+	if err := r.DB.WithContext(ctx).Model(&model.SavedSegment{}).Where("id = ?", id).Updates(&model.SavedSegment{
+		Params: paramString,
+		Name:   name,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &model.T, nil
+}
+
+// DeleteSavedSegment is the resolver for the deleteSavedSegment field.
+func (r *mutationResolver) DeleteSavedSegment(ctx context.Context, segmentID int) (*bool, error) {
+	_, err := r.isAdminSavedSegmentOwner(ctx, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.DB.Where("id = ?", segmentID).Delete(&model.SavedSegment{}).Error; err != nil {
+		return nil, err
+	}
+	return &model.T, nil
+}
+
+// CreateOrUpdateStripeSubscription is the resolver for the createOrUpdateStripeSubscription field.
+func (r *mutationResolver) CreateOrUpdateStripeSubscription(ctx context.Context, workspaceID int) (*string, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not in workspace")
+	}
+
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageBilling); err != nil {
+		return nil, e.Wrap(err, "must have manage billing permission to create/update stripe subscription")
+	}
+
+	// For older projects, if there's no customer ID, we create a StripeCustomer obj.
+	if workspace.StripeCustomerID == nil {
+		params := &stripe.CustomerParams{}
+		c, err := r.StripeClient.Customers.New(params)
+		if err != nil {
+			log.WithContext(ctx).Error(err, "error creating stripe customer")
+		}
+		if err := r.DB.WithContext(ctx).Model(&workspace).Updates(&model.Workspace{
+			StripeCustomerID: &c.ID,
+		}).Error; err != nil {
+			return nil, e.Wrap(err, "error updating org fields")
+		}
+		workspace.StripeCustomerID = &c.ID
+	}
+
+	params := &stripe.CustomerParams{}
+	params.AddExpand("subscriptions")
+
+	c, err := r.StripeClient.Customers.Get(*workspace.StripeCustomerID, params)
+	if err != nil {
+		return nil, e.Wrap(err, "STRIPE_INTEGRATION_ERROR cannot update stripe subscription - couldn't retrieve stripe customer data")
+	}
+
+	// If there are multiple subscriptions, it's ambiguous which one should be updated, so throw an error
+	if len(c.Subscriptions.Data) > 1 {
+		return nil, e.New("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - customer has multiple subscriptions")
+	}
+
+	subscriptions := c.Subscriptions.Data
+	pricing.FillProducts(r.StripeClient, subscriptions)
+
+	pricingInterval := model.PricingSubscriptionIntervalMonthly
+
+	defaultRetention := modelInputs.RetentionPeriodThreeMonths
+
+	// default to unlimited members pricing
+	prices, err := pricing.GetStripePrices(r.StripeClient, workspace, modelInputs.PlanTypeGraduated, pricingInterval, true, &defaultRetention, &defaultRetention)
+	if err != nil {
+		return nil, e.Wrap(err, "STRIPE_INTEGRATION_ERROR cannot update stripe subscription - failed to get Stripe prices")
+	}
+
+	newBasePrice := prices[model.PricingProductTypeBase]
+
+	// If there's an existing subscription, update it
+	if len(subscriptions) == 1 {
+		subscription := subscriptions[0]
+		if len(subscription.Items.Data) != 1 {
+			return nil, e.New("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - subscription has multiple products")
+		}
+
+		subscriptionItem := subscription.Items.Data[0]
+		productType, _, _, _, _ := pricing.GetProductMetadata(subscriptionItem.Price)
+		if productType == nil {
+			return nil, e.New(fmt.Sprintf("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - nil product from sub %s price %s", subscription.ID, subscriptionItem.Price.ID))
+		}
+		if *productType != model.PricingProductTypeBase {
+			return nil, e.New(fmt.Sprintf("STRIPE_INTEGRATION_ERROR cannot update stripe subscription - expecting base product from sub %s price %s: %s", subscription.ID, subscriptionItem.Price.ID, *productType))
+		}
+
+		subscriptionParams := &stripe.SubscriptionParams{
+			CancelAtPeriodEnd: stripe.Bool(false),
+			ProrationBehavior: stripe.String(string(stripe.SubscriptionSchedulePhaseProrationBehaviorCreateProrations)),
+			Items: []*stripe.SubscriptionItemsParams{
+				{
+					ID:   &subscriptionItem.ID,
+					Plan: &newBasePrice.ID,
+				},
+			},
+		}
+
+		_, err := r.StripeClient.Subscriptions.Update(subscription.ID, subscriptionParams)
+		if err != nil {
+			return nil, e.Wrap(err, "couldn't update subscription")
+		}
+		ret := ""
+		return &ret, nil
+	}
+
+	// If there's no existing subscription, we create a checkout.
+	checkoutSessionParams := &stripe.CheckoutSessionParams{
+		SuccessURL: stripe.String(os.Getenv("FRONTEND_URI") + "/w/" + strconv.Itoa(workspaceID) + "/current-plan/success"),
+		CancelURL:  stripe.String(os.Getenv("FRONTEND_URI") + "/w/" + strconv.Itoa(workspaceID) + "/current-plan/update-plan"),
+		PaymentMethodTypes: stripe.StringSlice([]string{
+			"card",
+		}),
+		Customer: workspace.StripeCustomerID,
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				Price:    &newBasePrice.ID,
+				Quantity: stripe.Int64(1),
+			},
+		},
+		Mode: stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+	}
+	checkoutSessionParams.AddExtra("allow_promotion_codes", "true")
+
+	stripeSession, err := r.StripeClient.CheckoutSessions.New(checkoutSessionParams)
+	if err != nil {
+		return nil, e.Wrap(err, "error creating CheckoutSession in stripe")
+	}
+
+	return &stripeSession.ID, nil
+}
+
+// UpdateBillingDetails is the resolver for the updateBillingDetails field.
+func (r *mutationResolver) UpdateBillingDetails(ctx context.Context, workspaceID int) (*bool, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not in workspace")
+	}
+
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageBilling); err != nil {
+		return nil, e.Wrap(err, "must have manage billing permission to update billing details")
+	}
+
+	if err := r.updateBillingDetails(ctx, *workspace.StripeCustomerID); err != nil {
+		return nil, e.Wrap(err, "error updating billing details")
+	}
+
+	return &model.T, nil
+}
+
+// SaveBillingPlan is the resolver for the saveBillingPlan field.
+func (r *mutationResolver) SaveBillingPlan(ctx context.Context, workspaceID int, sessionsLimitCents *int, sessionsRetention modelInputs.RetentionPeriod, errorsLimitCents *int, errorsRetention modelInputs.RetentionPeriod, logsLimitCents *int, logsRetention modelInputs.RetentionPeriod, tracesLimitCents *int, tracesRetention modelInputs.RetentionPeriod) (*bool, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not in workspace")
+	}
+
+	settings, err := r.Store.GetAllWorkspaceSettings(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := []interface{}{"errors_retention_period", "retention_period"}
+	if settings.EnableBillingLimits {
+		columns = append(columns, "sessions_max_cents", "errors_max_cents", "logs_max_cents", "traces_max_cents")
+	}
+	if err := r.DB.WithContext(ctx).Model(&workspace).
+		Select(columns[0], columns[1:]...).
+		Updates(&model.Workspace{
+			SessionsMaxCents:      sessionsLimitCents,
+			RetentionPeriod:       &sessionsRetention,
+			ErrorsMaxCents:        errorsLimitCents,
+			ErrorsRetentionPeriod: &errorsRetention,
+			LogsMaxCents:          logsLimitCents,
+			LogsRetentionPeriod:   &logsRetention,
+			TracesMaxCents:        tracesLimitCents,
+			TracesRetentionPeriod: &tracesRetention,
+		}).Error; err != nil {
+		return nil, e.Wrap(err, "error updating workspace")
+	}
+
+	return pointy.Bool(true), nil
+}
+
+// CreateSessionComment is the resolver for the createSessionComment field.
+func (r *mutationResolver) CreateSessionComment(ctx context.Context, projectID int, sessionSecureID string, sessionTimestamp int, text string, textForEmail string, xCoordinate float64, yCoordinate float64, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput, sessionURL string, time float64, authorName string, sessionImage *string, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType, tags []*modelInputs.SessionCommentTagInput, additionalContext *string, clickupTask *modelInputs.ClickUpTaskInput) (*model.SessionComment, error) {
+	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
+
+	// All viewers can leave a comment, including guests
+	session, err := r.canAdminViewSession(ctx, sessionSecureID)
+	if err != nil {
+		return nil, err
+	}
+
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := r.getTaggedAdmins(taggedAdmins, isGuest)
+
+	sessionImageStr := ""
+	if sessionImage != nil {
+		sessionImageStr = *sessionImage
+	}
+
+	if sessionTimestamp >= math.MaxInt32 {
+		log.WithContext(ctx).Warnf("attempted to create session with invalid timestamp %d", sessionTimestamp)
+		sessionTimestamp = 0
 	}
 	sessionComment := &model.SessionComment{
 		Admins:          admins,
@@ -1475,136 +2519,884 @@ func (r *mutationResolver) CreateSessionComment(ctx context.Context, projectID i
 		XCoordinate:     xCoordinate,
 		YCoordinate:     yCoordinate,
 	}
-	createSessionCommentSpan, _ := util.StartSpanFromContext(ctx, "db.createSessionComment",
-		util.ResourceName("resolver.createSessionComment"), util.Tag("project_id", projectID))
-	if err := r.DB.WithContext(ctx).Create(sessionComment).Error; err != nil {
-		return nil, e.Wrap(err, "error creating session comment")
+	createSessionCommentSpan, _ := util.StartSpanFromContext(ctx, "db.createSessionComment",
+		util.ResourceName("resolver.createSessionComment"), util.Tag("project_id", projectID))
+	if err := r.DB.WithContext(ctx).Create(sessionComment).Error; err != nil {
+		return nil, e.Wrap(err, "error creating session comment")
+	}
+	createSessionCommentSpan.Finish()
+
+	// Create associations between tags and comments.
+	if len(tags) > 0 {
+		// Create the tag if it's a new tag
+		newTags := []*model.SessionCommentTag{}
+		existingTags := []*model.SessionCommentTag{}
+		sessionComments := []model.SessionComment{*sessionComment}
+
+		for _, tag := range tags {
+			if tag.ID == nil {
+				newSessionCommentTag := model.SessionCommentTag{
+					ProjectID:       projectID,
+					Name:            tag.Name,
+					SessionComments: sessionComments,
+				}
+				newTags = append(newTags, &newSessionCommentTag)
+			} else {
+				newSessionCommentTag := model.SessionCommentTag{
+					ProjectID: projectID,
+					Name:      tag.Name,
+					Model: model.Model{
+						ID: *tag.ID,
+					},
+					SessionComments: sessionComments,
+				}
+				existingTags = append(existingTags, &newSessionCommentTag)
+			}
+		}
+
+		if len(newTags) > 0 {
+			if err := r.DB.WithContext(ctx).Create(&newTags).Error; err != nil {
+				log.WithContext(ctx).Error("Failed to create new session tags", err)
+			}
+		}
+
+		if len(existingTags) > 0 {
+			if err := r.DB.Save(&existingTags).Error; err != nil {
+				log.WithContext(ctx).Error("Failed to update existing session tags", err)
+			}
+		}
+	}
+
+	viewLink := fmt.Sprintf("%v?commentId=%v&ts=%v", sessionURL, sessionComment.ID, time)
+	muteLink := fmt.Sprintf("%v?commentId=%v&ts=%v&muted=1", sessionURL, sessionComment.ID, time)
+
+	r.PrivateWorkerPool.SubmitRecover(func() {
+		ctx := context.Background()
+		chunkIdx, chunkTs := r.GetSessionChunk(ctx, session.ID, sessionTimestamp)
+		log.WithContext(ctx).Infof("got chunk %d ts %d for session %d ts %d", chunkIdx, chunkTs, session.ID, sessionTimestamp)
+		format := model.SessionExportFormatPng
+		resp, err := r.LambdaClient.GetSessionScreenshot(ctx, projectID, session.ID, pointy.Int(chunkTs), pointy.Int(chunkIdx), &format)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to render screenshot for %d %d %d %s", projectID, session.ID, sessionTimestamp, err)
+		} else {
+			sessionImageStr = base64.StdEncoding.EncodeToString(resp.Image)
+			sessionImage = &sessionImageStr
+			if err := r.DB.WithContext(ctx).Model(&model.SessionComment{}).Where(
+				&model.SessionComment{Model: model.Model{ID: sessionComment.ID}},
+			).Updates(
+				model.SessionComment{
+					SessionImage: sessionImageStr,
+				},
+			).Error; err != nil {
+				log.WithContext(ctx).Error(e.Wrap(err, fmt.Sprintf("failed to update image for comment %d", sessionComment.ID)))
+			}
+		}
+		if len(taggedAdmins) > 0 && !isGuest {
+			r.sendCommentPrimaryNotification(
+				ctx,
+				admin,
+				*admin.Name,
+				taggedAdmins,
+				workspace,
+				project.ID,
+				&sessionComment.ID,
+				nil,
+				textForEmail,
+				viewLink,
+				muteLink,
+				sessionImage,
+				"tagged",
+				"session",
+				additionalContext,
+				&Email.SessionCommentMentionsAsmId,
+			)
+		}
+		if len(taggedSlackUsers) > 0 && !isGuest {
+			r.sendCommentMentionNotification(
+				ctx,
+				admin,
+				taggedSlackUsers,
+				workspace,
+				project.ID,
+				&sessionComment.ID,
+				nil,
+				textForEmail,
+				viewLink,
+				sessionImage,
+				"tagged",
+				"session",
+				additionalContext,
+			)
+		}
+	})
+
+	for _, s := range integrations {
+		attachment := &model.ExternalAttachment{
+			IntegrationType:  *s,
+			SessionCommentID: sessionComment.ID,
+		}
+		title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
+		desc += "See the error page on Highlight:\n"
+		desc += fmt.Sprintf("%s/%d/sessions/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, sessionComment.SessionSecureId)
+
+		if *s == modelInputs.IntegrationTypeLinear &&
+			workspace.LinearAccessToken != nil &&
+			*workspace.LinearAccessToken != "" {
+			if err := r.CreateLinearIssueAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				*issueTitle,
+				*issueDescription,
+				textForEmail,
+				authorName,
+				viewLink,
+				issueTeamID,
+			); err != nil {
+				return nil, e.Wrap(err, "error creating linear ticket or workspace")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeHeight {
+			if err := r.CreateHeightTaskAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				title,
+				desc,
+				issueTeamID,
+			); err != nil {
+				return nil, e.Wrap(err, "error creating Height task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitHub {
+			if err := r.CreateGitHubTaskAndAttachment(
+				ctx,
+				workspace,
+				project.ID,
+				attachment,
+				title,
+				desc,
+				issueTeamID,
+				tags,
+				nil,
+			); err != nil {
+				return nil, e.Wrap(err, "error creating GitHub task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeJira {
+			if issueTeamID == nil || issueTypeID == nil {
+				return nil, e.New("issue team and type are required")
+			}
+
+			if err := r.CreateJiraTaskAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				title,
+				desc,
+				*issueTeamID,
+				*issueTypeID,
+				&jira.IssueDetails{SessionURL: viewLink},
+			); err != nil {
+				return nil, e.Wrap(err, "error creating Jira task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitLab {
+			if err := r.CreateGitlabTaskAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				title,
+				desc,
+				*issueTeamID,
+			); err != nil {
+				return nil, e.Wrap(err, "error creating GitLab task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if issuetracker.Supported(*s) {
+			if err := r.CreateTrackerIssueAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				title,
+				desc,
+				viewLink,
+				issueTeamID,
+				clickupTask,
+			); err != nil {
+				return nil, e.Wrapf(err, "error creating %s issue", *s)
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		}
+	}
+
+	taggedUsers := append(taggedAdmins, &modelInputs.SanitizedAdminInput{
+		ID:    admin.ID,
+		Name:  admin.Name,
+		Email: *admin.Email,
+	})
+	newFollowers := r.findNewFollowers(taggedUsers, taggedSlackUsers, nil, nil)
+	for _, f := range newFollowers {
+		f.SessionCommentID = sessionComment.ID
+	}
+	if len(newFollowers) > 0 {
+		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
+			log.WithContext(ctx).Error("Failed to create new session comment followers", err)
+		}
+	}
+
+	return sessionComment, nil
+}
+
+// CreateIssueForSessionComment is the resolver for the createIssueForSessionComment field.
+func (r *mutationResolver) CreateIssueForSessionComment(ctx context.Context, projectID int, sessionURL string, sessionCommentID int, authorName string, textForAttachment string, time float64, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType, clickupTask *modelInputs.ClickUpTaskInput) (*model.SessionComment, error) {
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where("id = ?", projectID).Take(&project).Error; err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionComment := &model.SessionComment{}
+	if err := r.DB.WithContext(ctx).Preload("Attachments").Where(&model.SessionComment{Model: model.Model{ID: sessionCommentID}}).Find(sessionComment).Error; err != nil {
+		return nil, err
+	}
+
+	viewLink := fmt.Sprintf("%v?commentId=%v&ts=%v", sessionURL, sessionComment.ID, time)
+
+	for _, s := range integrations {
+		attachment := &model.ExternalAttachment{
+			IntegrationType:  *s,
+			SessionCommentID: sessionComment.ID,
+		}
+
+		title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
+		desc += "See the error page on Highlight:\n"
+		desc += fmt.Sprintf("%s/%d/sessions/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, sessionComment.SessionSecureId)
+
+		if *s == modelInputs.IntegrationTypeLinear && workspace.LinearAccessToken != nil && *workspace.LinearAccessToken != "" {
+			if err := r.CreateLinearIssueAndAttachment(ctx, workspace, attachment, *issueTitle, *issueDescription, sessionComment.Text, authorName, viewLink, issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating linear ticket or workspace")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeHeight {
+			if err := r.CreateHeightTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating Height task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitHub {
+			if err := r.CreateGitHubTaskAndAttachment(ctx, workspace, projectID, attachment, title, desc, issueTeamID, nil, nil); err != nil {
+				return nil, e.Wrap(err, "error creating GitHub task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeJira {
+			if issueTeamID == nil || issueTypeID == nil {
+				return nil, e.New("issue team and type are required")
+			}
+
+			if err := r.CreateJiraTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID, *issueTypeID, &jira.IssueDetails{SessionURL: viewLink}); err != nil {
+				return nil, e.Wrap(err, "error creating Jira task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitLab {
+			if err := r.CreateGitlabTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating GitLab task")
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		} else if issuetracker.Supported(*s) {
+			if err := r.CreateTrackerIssueAndAttachment(ctx, workspace, attachment, title, desc, viewLink, issueTeamID, clickupTask); err != nil {
+				return nil, e.Wrapf(err, "error creating %s issue", *s)
+			}
+
+			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+		}
+	}
+
+	return sessionComment, nil
+}
+
+// DeleteSessionComment is the resolver for the deleteSessionComment field.
+func (r *mutationResolver) DeleteSessionComment(ctx context.Context, id int) (*bool, error) {
+	var sessionComment model.SessionComment
+	if err := r.DB.WithContext(ctx).Where(model.SessionComment{Model: model.Model{ID: id}}).Take(&sessionComment).Error; err != nil {
+		return nil, e.Wrap(err, "error querying session comment")
+	}
+
+	_, err := r.canAdminModifySession(ctx, sessionComment.SessionSecureId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Delete(&model.SessionComment{Model: model.Model{ID: id}}).Error; err != nil {
+		return nil, e.Wrap(err, "error session comment")
+	}
+
+	if err := r.DB.WithContext(ctx).Where(&model.ExternalAttachment{SessionCommentID: id}).Delete(&model.ExternalAttachment{}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting session comment attachments")
+	}
+
+	if err := r.DB.WithContext(ctx).Where(&model.CommentReply{SessionCommentID: id}).Delete(&model.CommentReply{}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting session comment replies")
+	}
+
+	if err := r.DB.WithContext(ctx).Where(&model.CommentFollower{SessionCommentID: id}).Delete(&model.CommentFollower{}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting session comment followers")
+	}
+
+	var commentCount int64
+	if err := r.DB.Table("session_comments").Where(&model.SessionComment{SessionSecureId: sessionComment.SessionSecureId}).Count(&commentCount).Error; err != nil {
+		return nil, e.Wrap(err, "error counting session comments")
+	}
+
+	return &model.T, nil
+}
+
+// MuteSessionCommentThread is the resolver for the muteSessionCommentThread field.
+func (r *mutationResolver) MuteSessionCommentThread(ctx context.Context, id int, hasMuted *bool) (*bool, error) {
+	var sessionComment model.SessionComment
+	if err := r.DB.WithContext(ctx).Where(model.SessionComment{Model: model.Model{ID: id}}).Take(&sessionComment).Error; err != nil {
+		return nil, e.Wrap(err, "error querying session comment")
+	}
+
+	_, err := r.canAdminModifySession(ctx, sessionComment.SessionSecureId)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var commentFollower model.CommentFollower
+	if err := r.DB.WithContext(ctx).Where(&model.CommentFollower{SessionCommentID: id, AdminId: admin.ID}).Take(&commentFollower).Updates(
+		&model.CommentFollower{
+			HasMuted: hasMuted,
+		}).Error; err != nil {
+		return nil, e.Wrap(err, "error changing the muted status")
+	}
+
+	return &model.T, nil
+}
+
+// ReplyToSessionComment is the resolver for the replyToSessionComment field.
+func (r *mutationResolver) ReplyToSessionComment(ctx context.Context, commentID int, text string, textForEmail string, sessionURL string, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput) (*model.CommentReply, error) {
+	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
+	if isGuest {
+		return nil, e.New("must be logged in to add a comment reply")
+	}
+
+	var sessionComment model.SessionComment
+	if err := r.DB.WithContext(ctx).Preload("Followers").Preload("Threads").Where(model.SessionComment{Model: model.Model{ID: commentID}}).Take(&sessionComment).Error; err != nil {
+		return nil, e.Wrap(err, "error querying session comment")
+	}
+
+	// All viewers can leave a comment reply, including guests
+	_, err := r.canAdminViewSession(ctx, sessionComment.SessionSecureId)
+	if err != nil {
+		return nil, err
+	}
+
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: sessionComment.ProjectID}}).Take(&project).Error; err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := r.getTaggedAdmins(taggedAdmins, isGuest)
+
+	commentReply := &model.CommentReply{
+		SessionCommentID: sessionComment.ID,
+		Admins:           admins,
+		AdminId:          admin.ID,
+		Text:             text,
+	}
+	createSessionCommentReplySpan, _ := util.StartSpanFromContext(ctx, "db.createSessionCommentReply",
+		util.ResourceName("resolver.createSessionCommentReply"), util.Tag("project_id", sessionComment.ProjectID))
+	if err := r.DB.WithContext(ctx).Create(commentReply).Error; err != nil {
+		return nil, e.Wrap(err, "error creating session comment reply")
+	}
+	createSessionCommentReplySpan.Finish()
+
+	viewLink := fmt.Sprintf("%v?commentId=%v", sessionURL, sessionComment.ID)
+	muteLink := fmt.Sprintf("%v?commentId=%v&muted=1", sessionURL, sessionComment.ID)
+
+	if len(taggedAdmins) > 0 {
+		r.sendCommentPrimaryNotification(
+			ctx,
+			admin,
+			*admin.Name,
+			taggedAdmins,
+			workspace,
+			project.ID,
+			&sessionComment.ID,
+			nil,
+			textForEmail,
+			viewLink,
+			muteLink,
+			&sessionComment.SessionImage,
+			"replied to",
+			"session",
+			nil,
+			&Email.SessionCommentMentionsAsmId,
+		)
+	}
+	if len(sessionComment.Followers) > 0 {
+		var threadIDs []int
+		for _, thread := range sessionComment.Threads {
+			threadIDs = append(threadIDs, thread.ID)
+		}
+		r.sendFollowedCommentNotification(
+			ctx,
+			admin,
+			sessionComment.Followers,
+			workspace,
+			project.ID,
+			threadIDs,
+			textForEmail,
+			viewLink,
+			muteLink,
+			&sessionComment.SessionImage,
+			"replied to",
+			"session",
+			&Email.SessionCommentMentionsAsmId,
+		)
+	}
+
+	existingAdminIDs, existingSlackChannelIDs := r.getCommentFollowers(ctx, sessionComment.Followers)
+	taggedAdmins = append(taggedAdmins, &modelInputs.SanitizedAdminInput{
+		ID:    admin.ID,
+		Name:  admin.Name,
+		Email: *admin.Email,
+	})
+	newFollowers := r.findNewFollowers(taggedAdmins, taggedSlackUsers, existingAdminIDs, existingSlackChannelIDs)
+	for _, f := range newFollowers {
+		f.SessionCommentID = commentID
+	}
+
+	if len(newFollowers) > 0 {
+		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
+			log.WithContext(ctx).Error("Failed to create new session reply followers", err)
+		}
+	}
+
+	return commentReply, nil
+}
+
+// CreateErrorComment is the resolver for the createErrorComment field.
+func (r *mutationResolver) CreateErrorComment(ctx context.Context, projectID int, errorGroupSecureID string, text string, textForEmail string, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput, errorURL string, authorName string, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType, clickupTask *modelInputs.ClickUpTaskInput) (*model.ErrorComment, error) {
+	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
+
+	errorGroup, err := r.canAdminViewErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, err
+	}
+
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := []model.Admin{}
+	for _, a := range taggedAdmins {
+		admins = append(admins,
+			model.Admin{
+				Model: model.Model{ID: a.ID},
+			},
+		)
+	}
+
+	errorComment := &model.ErrorComment{
+		Admins:        admins,
+		ProjectID:     projectID,
+		AdminId:       admin.Model.ID,
+		ErrorId:       errorGroup.ID,
+		ErrorSecureId: errorGroup.SecureID,
+		Text:          text,
+	}
+
+	createErrorCommentSpan, _ := util.StartSpanFromContext(ctx, "db.createErrorComment",
+		util.ResourceName("resolver.createErrorComment"), util.Tag("project_id", projectID))
+
+	if err := r.DB.WithContext(ctx).Create(errorComment).Error; err != nil {
+		return nil, e.Wrap(err, "error creating error comment")
+	}
+
+	createErrorCommentSpan.Finish()
+
+	viewLink := fmt.Sprintf("%v?commentId=%v", errorURL, errorComment.ID)
+	muteLink := fmt.Sprintf("%v?commentId=%v&muted=1", errorURL, errorComment.ID)
+
+	if len(taggedAdmins) > 0 && !isGuest {
+		r.sendCommentPrimaryNotification(
+			ctx,
+			admin,
+			authorName,
+			taggedAdmins,
+			workspace,
+			projectID,
+			nil,
+			&errorComment.ID,
+			textForEmail,
+			viewLink,
+			muteLink,
+			nil,
+			"tagged",
+			"error",
+			nil,
+			&Email.ErrorCommentMentionsAsmId,
+		)
+	}
+	if len(taggedSlackUsers) > 0 && !isGuest {
+		r.sendCommentMentionNotification(
+			ctx,
+			admin,
+			taggedSlackUsers,
+			workspace,
+			projectID,
+			nil,
+			&errorComment.ID,
+			textForEmail,
+			viewLink,
+			nil,
+			"tagged",
+			"error",
+			nil,
+		)
+	}
+
+	for _, s := range integrations {
+		attachment := &model.ExternalAttachment{
+			IntegrationType: *s,
+			ErrorCommentID:  errorComment.ID,
+		}
+
+		issue, claimed, err := r.claimErrorIssue(ctx, projectID, errorGroup.ID, &admin.ID, attachment)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+			continue
+		}
+		defer r.completeErrorIssue(ctx, issue, attachment)
+
+		title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
+		desc += "See the error page on Highlight:\n"
+		desc += fmt.Sprintf("%s/%d/errors/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, errorComment.ErrorSecureId)
+
+		if *s == modelInputs.IntegrationTypeLinear && workspace.LinearAccessToken != nil && *workspace.LinearAccessToken != "" {
+			if err := r.CreateLinearIssueAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				*issueTitle,
+				*issueDescription,
+				textForEmail,
+				authorName,
+				viewLink,
+				issueTeamID,
+			); err != nil {
+				return nil, e.Wrap(err, "error creating linear ticket or workspace")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeHeight {
+			if err := r.CreateHeightTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating Height task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitHub {
+			if err := r.CreateGitHubTaskAndAttachment(ctx, workspace, projectID, attachment, title, desc, issueTeamID, nil, (*githubissues.IssueDetails)(r.errorIssueDetails(ctx, errorGroup.ID, viewLink))); err != nil {
+				return nil, e.Wrap(err, "error creating GitHub task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeJira {
+			if issueTeamID == nil || issueTypeID == nil {
+				return nil, e.New("issue team and type are required")
+			}
+
+			if err := r.CreateJiraTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID, *issueTypeID, r.errorIssueDetails(ctx, errorGroup.ID, viewLink)); err != nil {
+				return nil, e.Wrap(err, "error creating Jira task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitLab {
+			if err := r.CreateGitlabTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating GitLab task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if issuetracker.Supported(*s) {
+			if err := r.CreateTrackerIssueAndAttachment(
+				ctx,
+				workspace,
+				attachment,
+				title,
+				desc,
+				viewLink,
+				issueTeamID,
+				clickupTask,
+			); err != nil {
+				return nil, e.Wrapf(err, "error creating %s issue", *s)
+			}
+
+			if *s == modelInputs.IntegrationTypeClickUp {
+				r.AddClickUpErrorContext(workspace, attachment.ExternalID, errorGroup.ID, viewLink)
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		}
+	}
+
+	taggedUsers := append(taggedAdmins, &modelInputs.SanitizedAdminInput{
+		ID:    admin.ID,
+		Name:  admin.Name,
+		Email: *admin.Email,
+	})
+	newFollowers := r.findNewFollowers(taggedUsers, taggedSlackUsers, nil, nil)
+	for _, f := range newFollowers {
+		f.ErrorCommentID = errorComment.ID
+	}
+	if len(newFollowers) > 0 {
+		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
+			log.WithContext(ctx).Error("Failed to create new session comment followers", err)
+		}
+	}
+
+	return errorComment, nil
+}
+
+// RemoveErrorIssue is the resolver for the removeErrorIssue field.
+func (r *mutationResolver) RemoveErrorIssue(ctx context.Context, errorIssueID int) (*bool, error) {
+	var errorCommentID int
+	if err := r.DB.
+		Model(&model.ExternalAttachment{}).
+		Select("error_comment_id").
+		Where("id=?", errorIssueID).
+		First(&errorCommentID).
+		Error; err != nil {
+		return nil, e.Wrap(err, "error querying error issues")
+	}
+
+	var errorGroupSecureID string
+	if err := r.DB.
+		Model(&model.ErrorComment{}).
+		Select("error_secure_id").
+		Where("id=?", errorCommentID).
+		First(&errorGroupSecureID).
+		Error; err != nil {
+		return nil, e.Wrap(err, "error querying error comments")
+	}
+
+	_, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not authorized to modify error group")
+	}
+
+	var externalAttachment model.ExternalAttachment
+	if err := r.DB.
+		Model(&model.ExternalAttachment{}).
+		Where("id=?", errorIssueID).
+		First(&externalAttachment).
+		Updates(
+			&model.ExternalAttachment{
+				Removed: true,
+			}).
+		Error; err != nil {
+		return nil, e.Wrap(err, "error changing the muted status")
+	}
+
+	if err := r.Store.UnlinkExternalAttachment(ctx, externalAttachment.ID); err != nil {
+		return nil, e.Wrap(err, "error unlinking external issue")
+	}
+
+	return &model.T, nil
+}
+
+// LinkExternalIssue is the resolver for the linkExternalIssue field.
+func (r *mutationResolver) LinkExternalIssue(ctx context.Context, projectID int, errorGroupSecureID string, integrationType modelInputs.IntegrationType, externalID string, title *string) (*model.ExternalIssue, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if externalID == "" {
+		return nil, e.New("external_id is required")
+	}
+	errorGroup, err := r.getProjectErrorGroup(ctx, project.ID, errorGroupSecureID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group")
+	}
+
+	issueTitle := ptr.ToString(title)
+	// issues are looked up in their tracker so that only issues the workspace can access are linked
+	if issuetracker.Supported(integrationType) {
+		workspace, err := r.GetWorkspace(project.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		tracker, err := r.IssueTracker(ctx, workspace, integrationType)
+		if err != nil {
+			return nil, err
+		}
+		errorURL := fmt.Sprintf("%s/%d/errors/%s", os.Getenv("REACT_APP_FRONTEND_URI"), project.ID, errorGroup.SecureID)
+		trackerIssue, err := tracker.LinkIssue(ctx, externalID, errorURL)
+		if err != nil {
+			return nil, clickUpError(err)
+		}
+		// the issue is stored by the id of the tracker, ie. when it was looked up by its key
+		externalID = trackerIssue.ID
+		if issueTitle == "" {
+			issueTitle = trackerIssue.Title
+		}
+	}
+
+	return r.Store.LinkExternalIssue(ctx, store.ClaimExternalIssueParams{
+		ProjectID:       project.ID,
+		ErrorGroupID:    errorGroup.ID,
+		IntegrationType: integrationType,
+		AdminID:         &admin.ID,
+	}, externalID, issueTitle)
+}
+
+// UnlinkExternalIssue is the resolver for the unlinkExternalIssue field.
+func (r *mutationResolver) UnlinkExternalIssue(ctx context.Context, projectID int, errorGroupSecureID string, integrationType modelInputs.IntegrationType) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
+	errorGroup, err := r.getProjectErrorGroup(ctx, project.ID, errorGroupSecureID)
+	if err != nil {
+		return false, e.Wrap(err, "error querying error group")
+	}
+
+	if err := r.Store.DeleteExternalIssue(ctx, errorGroup.ID, integrationType); err != nil {
+		return false, e.Wrap(err, "error unlinking external issue")
+	}
+	return true, nil
+}
+
+// MuteErrorCommentThread is the resolver for the muteErrorCommentThread field.
+func (r *mutationResolver) MuteErrorCommentThread(ctx context.Context, id int, hasMuted *bool) (*bool, error) {
+	var errorGroupSecureID string
+	if err := r.DB.
+		Model(&model.ErrorComment{}).
+		Select("error_secure_id").
+		Where("id=?", id).
+		First(&errorGroupSecureID).
+		Error; err != nil {
+		return nil, e.Wrap(err, "error querying error comments")
+	}
+	_, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, err
 	}
-	createSessionCommentSpan.Finish()
 
-	// Create associations between tags and comments.
-	if len(tags) > 0 {
-		// Create the tag if it's a new tag
-		newTags := []*model.SessionCommentTag{}
-		existingTags := []*model.SessionCommentTag{}
-		sessionComments := []model.SessionComment{*sessionComment}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, tag := range tags {
-			if tag.ID == nil {
-				newSessionCommentTag := model.SessionCommentTag{
-					ProjectID:       projectID,
-					Name:            tag.Name,
-					SessionComments: sessionComments,
-				}
-				newTags = append(newTags, &newSessionCommentTag)
-			} else {
-				newSessionCommentTag := model.SessionCommentTag{
-					ProjectID: projectID,
-					Name:      tag.Name,
-					Model: model.Model{
-						ID: *tag.ID,
-					},
-					SessionComments: sessionComments,
-				}
-				existingTags = append(existingTags, &newSessionCommentTag)
-			}
-		}
+	var commentFollower model.CommentFollower
+	if err := r.DB.WithContext(ctx).Where(&model.CommentFollower{ErrorCommentID: id, AdminId: admin.ID}).
+		First(&commentFollower).
+		Updates(
+			&model.CommentFollower{
+				HasMuted: hasMuted,
+			}).
+		Error; err != nil {
+		return nil, e.Wrap(err, "error changing the muted status")
+	}
 
-		if len(newTags) > 0 {
-			if err := r.DB.WithContext(ctx).Create(&newTags).Error; err != nil {
-				log.WithContext(ctx).Error("Failed to create new session tags", err)
-			}
-		}
+	return &model.T, nil
+}
 
-		if len(existingTags) > 0 {
-			if err := r.DB.Save(&existingTags).Error; err != nil {
-				log.WithContext(ctx).Error("Failed to update existing session tags", err)
-			}
-		}
+// CreateIssueForErrorComment is the resolver for the createIssueForErrorComment field.
+func (r *mutationResolver) CreateIssueForErrorComment(ctx context.Context, projectID int, errorURL string, errorCommentID int, authorName string, textForAttachment string, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType, clickupTask *modelInputs.ClickUpTaskInput) (*model.ErrorComment, error) {
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
+		return nil, err
 	}
 
-	viewLink := fmt.Sprintf("%v?commentId=%v&ts=%v", sessionURL, sessionComment.ID, time)
-	muteLink := fmt.Sprintf("%v?commentId=%v&ts=%v&muted=1", sessionURL, sessionComment.ID, time)
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
 
-	r.PrivateWorkerPool.SubmitRecover(func() {
-		ctx := context.Background()
-		chunkIdx, chunkTs := r.GetSessionChunk(ctx, session.ID, sessionTimestamp)
-		log.WithContext(ctx).Infof("got chunk %d ts %d for session %d ts %d", chunkIdx, chunkTs, session.ID, sessionTimestamp)
-		format := model.SessionExportFormatPng
-		resp, err := r.LambdaClient.GetSessionScreenshot(ctx, projectID, session.ID, pointy.Int(chunkTs), pointy.Int(chunkIdx), &format)
-		if err != nil {
-			log.WithContext(ctx).Errorf("failed to render screenshot for %d %d %d %s", projectID, session.ID, sessionTimestamp, err)
-		} else {
-			sessionImageStr = base64.StdEncoding.EncodeToString(resp.Image)
-			sessionImage = &sessionImageStr
-			if err := r.DB.WithContext(ctx).Model(&model.SessionComment{}).Where(
-				&model.SessionComment{Model: model.Model{ID: sessionComment.ID}},
-			).Updates(
-				model.SessionComment{
-					SessionImage: sessionImageStr,
-				},
-			).Error; err != nil {
-				log.WithContext(ctx).Error(e.Wrap(err, fmt.Sprintf("failed to update image for comment %d", sessionComment.ID)))
-			}
-		}
-		if len(taggedAdmins) > 0 && !isGuest {
-			r.sendCommentPrimaryNotification(
-				ctx,
-				admin,
-				*admin.Name,
-				taggedAdmins,
-				workspace,
-				project.ID,
-				&sessionComment.ID,
-				nil,
-				textForEmail,
-				viewLink,
-				muteLink,
-				sessionImage,
-				"tagged",
-				"session",
-				additionalContext,
-				&Email.SessionCommentMentionsAsmId,
-			)
-		}
-		if len(taggedSlackUsers) > 0 && !isGuest {
-			r.sendCommentMentionNotification(
-				ctx,
-				admin,
-				taggedSlackUsers,
-				workspace,
-				project.ID,
-				&sessionComment.ID,
-				nil,
-				textForEmail,
-				viewLink,
-				sessionImage,
-				"tagged",
-				"session",
-				additionalContext,
-			)
-		}
-	})
+	errorComment := &model.ErrorComment{}
+	if err := r.DB.WithContext(ctx).Preload("Attachments").Where(&model.ErrorComment{Model: model.Model{ID: errorCommentID}}).Find(errorComment).Error; err != nil {
+		return nil, err
+	}
+
+	viewLink := fmt.Sprintf("%v", errorURL)
+
+	if issueDescription == nil {
+		return nil, e.New("issue description cannot be nil")
+	}
+
+	title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
+	desc += "See the error page on Highlight:\n"
+	desc += fmt.Sprintf("%s/%d/errors/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, errorComment.ErrorSecureId)
+
+	var adminID *int
+	if admin, err := r.getCurrentAdmin(ctx); err == nil {
+		adminID = &admin.ID
+	}
 
 	for _, s := range integrations {
 		attachment := &model.ExternalAttachment{
-			IntegrationType:  *s,
-			SessionCommentID: sessionComment.ID,
+			IntegrationType: *s,
+			ErrorCommentID:  errorComment.ID,
 		}
-		title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
-		desc += "See the error page on Highlight:\n"
-		desc += fmt.Sprintf("%s/%d/sessions/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, sessionComment.SessionSecureId)
 
-		if *s == modelInputs.IntegrationTypeLinear &&
-			workspace.LinearAccessToken != nil &&
-			*workspace.LinearAccessToken != "" {
+		issue, claimed, err := r.claimErrorIssue(ctx, projectID, errorComment.ErrorId, adminID, attachment)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+			continue
+		}
+		defer r.completeErrorIssue(ctx, issue, attachment)
+
+		if *s == modelInputs.IntegrationTypeLinear && workspace.LinearAccessToken != nil && *workspace.LinearAccessToken != "" {
 			if err := r.CreateLinearIssueAndAttachment(
 				ctx,
 				workspace,
 				attachment,
 				*issueTitle,
 				*issueDescription,
-				textForEmail,
+				errorComment.Text,
 				authorName,
 				viewLink,
 				issueTeamID,
@@ -1612,1496 +3404,1353 @@ func (r *mutationResolver) CreateSessionComment(ctx context.Context, projectID i
 				return nil, e.Wrap(err, "error creating linear ticket or workspace")
 			}
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeClickUp &&
-			workspace.ClickupAccessToken != nil &&
-			*workspace.ClickupAccessToken != "" {
-			if err := r.CreateClickUpTaskAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				title,
-				desc,
-				issueTeamID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating ClickUp task")
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeHeight {
+			if err := r.CreateHeightTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating Height task")
 			}
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeHeight {
-			if err := r.CreateHeightTaskAndAttachment(
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitHub {
+			if err := r.CreateGitHubTaskAndAttachment(ctx, workspace, projectID, attachment, title, desc, issueTeamID, nil, (*githubissues.IssueDetails)(r.errorIssueDetails(ctx, errorComment.ErrorId, viewLink))); err != nil {
+				return nil, e.Wrap(err, "error creating GitHub task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeJira {
+			if issueTeamID == nil || issueTypeID == nil {
+				return nil, e.New("issue team and type are required")
+			}
+
+			if err := r.CreateJiraTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID, *issueTypeID, r.errorIssueDetails(ctx, errorComment.ErrorId, viewLink)); err != nil {
+				return nil, e.Wrap(err, "error creating Jira task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if *s == modelInputs.IntegrationTypeGitLab {
+			if err := r.CreateGitlabTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID); err != nil {
+				return nil, e.Wrap(err, "error creating GitLab task")
+			}
+
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		} else if issuetracker.Supported(*s) {
+			if err := r.CreateTrackerIssueAndAttachment(
 				ctx,
 				workspace,
 				attachment,
 				title,
 				desc,
+				viewLink,
 				issueTeamID,
+				clickupTask,
 			); err != nil {
-				return nil, e.Wrap(err, "error creating Height task")
+				return nil, e.Wrapf(err, "error creating %s issue", *s)
+			}
+
+			if *s == modelInputs.IntegrationTypeClickUp {
+				r.AddClickUpErrorContext(workspace, attachment.ExternalID, errorComment.ErrorId, viewLink)
 			}
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitHub {
-			if err := r.CreateGitHubTaskAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				title,
-				desc,
-				issueTeamID,
-				tags,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating GitHub task")
-			}
+			errorComment.Attachments = append(errorComment.Attachments, attachment)
+		}
+	}
+
+	return errorComment, nil
+}
+
+// DeleteErrorComment is the resolver for the deleteErrorComment field.
+func (r *mutationResolver) DeleteErrorComment(ctx context.Context, id int) (*bool, error) {
+	var errorGroupSecureID string
+	if err := r.DB.Table("error_comments").Select("error_secure_id").Where("id=?", id).Scan(&errorGroupSecureID).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error comments")
+	}
+	_, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, e.Wrap(err, "admin is not authorized to modify error group")
+	}
+	if err := r.DB.Delete(&model.ErrorComment{Model: model.Model{ID: id}}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting error_comment")
+	}
+	if err := r.DB.WithContext(ctx).Where(&model.ExternalAttachment{ErrorCommentID: id}).Delete(&model.ExternalAttachment{}).Error; err != nil {
+		return nil, e.Wrap(err, "error deleting session comment attachments")
+	}
+	return &model.T, nil
+}
+
+// ReplyToErrorComment is the resolver for the replyToErrorComment field.
+func (r *mutationResolver) ReplyToErrorComment(ctx context.Context, commentID int, text string, textForEmail string, errorURL string, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput) (*model.CommentReply, error) {
+	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
+	if isGuest {
+		return nil, e.New("must be logged in to add a comment reply")
+	}
+
+	var errorComment model.ErrorComment
+	if err := r.DB.WithContext(ctx).Preload("Threads").Preload("Followers").Where(model.ErrorComment{Model: model.Model{ID: commentID}}).Take(&errorComment).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error comment")
+	}
+
+	_, err := r.canAdminViewErrorGroup(ctx, errorComment.ErrorSecureId)
+	if err != nil {
+		return nil, err
+	}
+
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: errorComment.ProjectID}}).Take(&project).Error; err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeJira {
-			if issueTeamID == nil || issueTypeID == nil {
-				return nil, e.New("issue team and type are required")
-			}
+	admins := r.getTaggedAdmins(taggedAdmins, isGuest)
 
-			if err := r.CreateJiraTaskAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				title,
-				desc,
-				*issueTeamID,
-				*issueTypeID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating Jira task")
-			}
+	commentReply := &model.CommentReply{
+		ErrorCommentID: errorComment.ID,
+		Admins:         admins,
+		AdminId:        admin.ID,
+		Text:           text,
+	}
+	createErrorCommentReplySpan, _ := util.StartSpanFromContext(ctx, "db.createErrorCommentReply",
+		util.ResourceName("resolver.createErrorCommentReply"), util.Tag("project_id", errorComment.ProjectID))
+	if err := r.DB.WithContext(ctx).Create(commentReply).Error; err != nil {
+		return nil, e.Wrap(err, "error creating error comment reply")
+	}
+	createErrorCommentReplySpan.Finish()
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitLab {
-			if err := r.CreateGitlabTaskAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				title,
-				desc,
-				*issueTeamID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating GitLab task")
-			}
+	viewLink := fmt.Sprintf("%v?commentId=%v", errorURL, errorComment.ID)
+	muteLink := fmt.Sprintf("%v?commentId=%v&muted=1", errorURL, errorComment.ID)
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+	if len(taggedAdmins) > 0 && !isGuest {
+		r.sendCommentPrimaryNotification(
+			ctx,
+			admin,
+			*admin.Name,
+			taggedAdmins,
+			workspace,
+			project.ID, nil,
+			&errorComment.ID,
+			textForEmail,
+			viewLink,
+			muteLink,
+			nil,
+			"replied to",
+			"error",
+			nil,
+			&Email.ErrorCommentMentionsAsmId,
+		)
+	}
+	if len(errorComment.Followers) > 0 && !isGuest {
+		var threadIDs []int
+		for _, thread := range errorComment.Threads {
+			threadIDs = append(threadIDs, thread.ID)
 		}
+		r.sendFollowedCommentNotification(
+			ctx,
+			admin,
+			errorComment.Followers,
+			workspace,
+			project.ID,
+			threadIDs,
+			textForEmail,
+			viewLink,
+			muteLink,
+			nil,
+			"replied to",
+			"error",
+			&Email.ErrorCommentMentionsAsmId,
+		)
 	}
 
-	taggedUsers := append(taggedAdmins, &modelInputs.SanitizedAdminInput{
+	existingAdminIDs, existingSlackChannelIDs := r.getCommentFollowers(ctx, errorComment.Followers)
+	taggedAdmins = append(taggedAdmins, &modelInputs.SanitizedAdminInput{
 		ID:    admin.ID,
 		Name:  admin.Name,
 		Email: *admin.Email,
 	})
-	newFollowers := r.findNewFollowers(taggedUsers, taggedSlackUsers, nil, nil)
+	newFollowers := r.findNewFollowers(taggedAdmins, taggedSlackUsers, existingAdminIDs, existingSlackChannelIDs)
 	for _, f := range newFollowers {
-		f.SessionCommentID = sessionComment.ID
+		f.ErrorCommentID = commentID
 	}
+
 	if len(newFollowers) > 0 {
 		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
-			log.WithContext(ctx).Error("Failed to create new session comment followers", err)
+			log.WithContext(ctx).Error("Failed to create new error reply followers", err)
 		}
 	}
 
-	return sessionComment, nil
+	return commentReply, nil
 }
 
-// CreateIssueForSessionComment is the resolver for the createIssueForSessionComment field.
-func (r *mutationResolver) CreateIssueForSessionComment(ctx context.Context, projectID int, sessionURL string, sessionCommentID int, authorName string, textForAttachment string, time float64, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType) (*model.SessionComment, error) {
-	var project model.Project
-	if err := r.DB.WithContext(ctx).Where("id = ?", projectID).Take(&project).Error; err != nil {
-		return nil, err
+// AddIntegrationToProject is the resolver for the addIntegrationToProject field.
+func (r *mutationResolver) AddIntegrationToProject(ctx context.Context, integrationType *modelInputs.IntegrationType, projectID int, code string) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
 	}
 
 	workspace, err := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
-		return nil, err
-	}
-
-	sessionComment := &model.SessionComment{}
-	if err := r.DB.WithContext(ctx).Preload("Attachments").Where(&model.SessionComment{Model: model.Model{ID: sessionCommentID}}).Find(sessionComment).Error; err != nil {
-		return nil, err
+		return false, err
 	}
 
-	viewLink := fmt.Sprintf("%v?commentId=%v&ts=%v", sessionURL, sessionComment.ID, time)
-
-	for _, s := range integrations {
-		attachment := &model.ExternalAttachment{
-			IntegrationType:  *s,
-			SessionCommentID: sessionComment.ID,
+	if *integrationType == modelInputs.IntegrationTypeLinear {
+		if err := r.AddLinearToWorkspace(workspace, code); err != nil {
+			return false, err
 		}
+	} else if *integrationType == modelInputs.IntegrationTypeJira {
+		if err := r.AddJiraToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeGitLab {
+		if err := r.AddGitlabToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeSlack {
+		if err := r.AddSlackToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeFront {
+		if err := r.AddFrontToProject(ctx, project, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeIntercom {
+		if err := r.AddIntercomToProject(ctx, project, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeVercel {
+		if err := r.AddVercelToWorkspace(workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeDiscord {
+		if err := r.AddDiscordToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else {
+		return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
+	}
 
-		title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
-		desc += "See the error page on Highlight:\n"
-		desc += fmt.Sprintf("%s/%d/sessions/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, sessionComment.SessionSecureId)
-
-		if *s == modelInputs.IntegrationTypeLinear && workspace.LinearAccessToken != nil && *workspace.LinearAccessToken != "" {
-			if err := r.CreateLinearIssueAndAttachment(ctx, workspace, attachment, *issueTitle, *issueDescription, sessionComment.Text, authorName, viewLink, issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating linear ticket or workspace")
-			}
-
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeClickUp && workspace.ClickupAccessToken != nil && *workspace.ClickupAccessToken != "" {
-			if err := r.CreateClickUpTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating ClickUp task")
-			}
-
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeHeight {
-			if err := r.CreateHeightTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating Height task")
-			}
-
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitHub {
-			if err := r.CreateGitHubTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID, nil); err != nil {
-				return nil, e.Wrap(err, "error creating GitHub task")
-			}
-
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeJira {
-			if issueTeamID == nil || issueTypeID == nil {
-				return nil, e.New("issue team and type are required")
-			}
+	return true, nil
+}
 
-			if err := r.CreateJiraTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID, *issueTypeID); err != nil {
-				return nil, e.Wrap(err, "error creating Jira task")
-			}
+// RemoveIntegrationFromProject is the resolver for the removeIntegrationFromProject field.
+func (r *mutationResolver) RemoveIntegrationFromProject(ctx context.Context, integrationType *modelInputs.IntegrationType, projectID int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitLab {
-			if err := r.CreateGitlabTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating GitLab task")
-			}
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return false, err
+	}
 
-			sessionComment.Attachments = append(sessionComment.Attachments, attachment)
+	if *integrationType == modelInputs.IntegrationTypeLinear {
+		if err := r.RemoveLinearFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeJira {
+		if err := r.RemoveJiraFromWorkspace(workspace); err != nil {
+			return false, err
 		}
+	} else if *integrationType == modelInputs.IntegrationTypeSlack {
+		if err := r.RemoveSlackFromWorkspace(workspace, projectID); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeZapier {
+		if err := r.RemoveZapierFromWorkspace(project); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeFront {
+		if err := r.RemoveFrontFromProject(project); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeIntercom || *integrationType == modelInputs.IntegrationTypeZendesk {
+		if err := r.removeIntegrationProjectMapping(ctx, project, *integrationType); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeVercel {
+		if err := r.RemoveVercelFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeDiscord {
+		if err := r.RemoveDiscordFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeGitHub {
+		if err := r.RemoveDiscordFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeGitLab {
+		if err := r.RemoveGitlabFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else {
+		return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
 	}
 
-	return sessionComment, nil
+	return true, nil
 }
 
-// DeleteSessionComment is the resolver for the deleteSessionComment field.
-func (r *mutationResolver) DeleteSessionComment(ctx context.Context, id int) (*bool, error) {
-	var sessionComment model.SessionComment
-	if err := r.DB.WithContext(ctx).Where(model.SessionComment{Model: model.Model{ID: id}}).Take(&sessionComment).Error; err != nil {
-		return nil, e.Wrap(err, "error querying session comment")
+// CreateZendeskAccessToken is the resolver for the createZendeskAccessToken field.
+func (r *mutationResolver) CreateZendeskAccessToken(ctx context.Context, projectID int) (string, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return "", err
 	}
 
-	_, err := r.canAdminModifySession(ctx, sessionComment.SessionSecureId)
-
+	// the previous token of the project is revoked by replacing its mapping
+	token, parsed, err := zendesk.GenerateAccessToken(project.ID)
 	if err != nil {
-		return nil, err
+		return "", e.Wrap(err, "error generating zendesk access token")
 	}
-
-	if err := r.DB.Delete(&model.SessionComment{Model: model.Model{ID: id}}).Error; err != nil {
-		return nil, e.Wrap(err, "error session comment")
+	if err := r.setIntegrationProjectMapping(ctx, project, modelInputs.IntegrationTypeZendesk, parsed.Magic); err != nil {
+		return "", e.Wrap(err, "error saving zendesk access token")
 	}
+	return token, nil
+}
 
-	if err := r.DB.WithContext(ctx).Where(&model.ExternalAttachment{SessionCommentID: id}).Delete(&model.ExternalAttachment{}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting session comment attachments")
+// AddIntegrationToWorkspace is the resolver for the addIntegrationToWorkspace field.
+func (r *mutationResolver) AddIntegrationToWorkspace(ctx context.Context, integrationType *modelInputs.IntegrationType, workspaceID int, code string) (bool, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return false, err
 	}
 
-	if err := r.DB.WithContext(ctx).Where(&model.CommentReply{SessionCommentID: id}).Delete(&model.CommentReply{}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting session comment replies")
+	if *integrationType == modelInputs.IntegrationTypeClickUp {
+		if err := r.AddClickUpToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeHeight {
+		if err := r.AddHeightToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeGitHub {
+		if err := r.AddGitHubToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeJira {
+		if err := r.AddJiraToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeGitLab {
+		if err := r.AddGitlabToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeAsana || *integrationType == modelInputs.IntegrationTypeMonday ||
+		*integrationType == modelInputs.IntegrationTypeSalesforce || *integrationType == modelInputs.IntegrationTypeHubSpot {
+		if err := r.IntegrationsClient.GetAndSetWorkspaceToken(ctx, workspace, *integrationType, code); err != nil {
+			return false, err
+		}
+	} else if *integrationType == modelInputs.IntegrationTypeShortcut {
+		if err := r.AddShortcutToWorkspace(ctx, workspace, code); err != nil {
+			return false, err
+		}
+	} else {
+		return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
 	}
 
-	if err := r.DB.WithContext(ctx).Where(&model.CommentFollower{SessionCommentID: id}).Delete(&model.CommentFollower{}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting session comment followers")
+	return true, nil
+}
+
+// RemoveIntegrationFromWorkspace is the resolver for the removeIntegrationFromWorkspace field.
+func (r *mutationResolver) RemoveIntegrationFromWorkspace(ctx context.Context, integrationType modelInputs.IntegrationType, workspaceID int) (bool, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return false, err
 	}
 
-	var commentCount int64
-	if err := r.DB.Table("session_comments").Where(&model.SessionComment{SessionSecureId: sessionComment.SessionSecureId}).Count(&commentCount).Error; err != nil {
-		return nil, e.Wrap(err, "error counting session comments")
+	if integrationType == modelInputs.IntegrationTypeClickUp {
+		if err := r.RemoveClickUpFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else if integrationType == modelInputs.IntegrationTypeGitHub {
+		if err := r.RemoveGitHubFromWorkspace(ctx, workspace); err != nil {
+			return false, err
+		}
+	} else if integrationType == modelInputs.IntegrationTypeJira {
+		if err := r.RemoveJiraFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else if integrationType == modelInputs.IntegrationTypeGitLab {
+		if err := r.RemoveGitlabFromWorkspace(workspace); err != nil {
+			return false, err
+		}
+	} else {
+		if err := r.RemoveIntegrationFromWorkspaceAndProjects(ctx, workspace, integrationType); err != nil {
+			return false, err
+		}
+
 	}
 
-	return &model.T, nil
+	return true, nil
 }
 
-// MuteSessionCommentThread is the resolver for the muteSessionCommentThread field.
-func (r *mutationResolver) MuteSessionCommentThread(ctx context.Context, id int, hasMuted *bool) (*bool, error) {
-	var sessionComment model.SessionComment
-	if err := r.DB.WithContext(ctx).Where(model.SessionComment{Model: model.Model{ID: id}}).Take(&sessionComment).Error; err != nil {
-		return nil, e.Wrap(err, "error querying session comment")
+// SyncSlackIntegration is the resolver for the syncSlackIntegration field.
+func (r *mutationResolver) SyncSlackIntegration(ctx context.Context, projectID int) (*modelInputs.SlackSyncResponse, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	response := modelInputs.SlackSyncResponse{
+		Success:               true,
+		NewChannelsAddedCount: 0,
 	}
-
-	_, err := r.canAdminModifySession(ctx, sessionComment.SessionSecureId)
 	if err != nil {
-		return nil, err
+		return &response, err
 	}
 
-	admin, err := r.getCurrentAdmin(ctx)
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
-		return nil, err
+		return &response, err
 	}
+	slackChannels, newChannelsCount, err := r.GetSlackChannelsFromSlack(ctx, workspace.ID)
 
-	var commentFollower model.CommentFollower
-	if err := r.DB.WithContext(ctx).Where(&model.CommentFollower{SessionCommentID: id, AdminId: admin.ID}).Take(&commentFollower).Updates(
-		&model.CommentFollower{
-			HasMuted: hasMuted,
-		}).Error; err != nil {
-		return nil, e.Wrap(err, "error changing the muted status")
+	if err != nil {
+		return &response, err
 	}
 
-	return &model.T, nil
-}
-
-// ReplyToSessionComment is the resolver for the replyToSessionComment field.
-func (r *mutationResolver) ReplyToSessionComment(ctx context.Context, commentID int, text string, textForEmail string, sessionURL string, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput) (*model.CommentReply, error) {
-	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
-	if isGuest {
-		return nil, e.New("must be logged in to add a comment reply")
+	channelBytes, err := json.Marshal(slackChannels)
+	if err != nil {
+		return &response, e.Wrap(err, "error marshaling slack channels")
 	}
-
-	var sessionComment model.SessionComment
-	if err := r.DB.WithContext(ctx).Preload("Followers").Preload("Threads").Where(model.SessionComment{Model: model.Model{ID: commentID}}).Take(&sessionComment).Error; err != nil {
-		return nil, e.Wrap(err, "error querying session comment")
+	channelString := string(channelBytes)
+	if err := r.DB.WithContext(ctx).Model(&workspace).Updates(&model.Workspace{
+		SlackChannels: &channelString,
+	}).Error; err != nil {
+		return &response, e.Wrap(err, "error updating workspace slack channels")
 	}
 
-	// All viewers can leave a comment reply, including guests
-	_, err := r.canAdminViewSession(ctx, sessionComment.SessionSecureId)
+	response.NewChannelsAddedCount = newChannelsCount
+
+	return &response, nil
+}
+
+// CreateMetricMonitor is the resolver for the createMetricMonitor field.
+func (r *mutationResolver) CreateMetricMonitor(ctx context.Context, projectID int, name string, aggregator modelInputs.MetricAggregator, periodMinutes *int, threshold float64, units *string, metricToMonitor string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, filters []*modelInputs.MetricTagFilterInput) (*model.MetricMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	var project model.Project
-	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: sessionComment.ProjectID}}).Take(&project).Error; err != nil {
+	channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
+	if err != nil {
 		return nil, err
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	emailsString, err := r.MarshalAlertEmails(emails)
 	if err != nil {
 		return nil, err
 	}
 
-	admins := r.getTaggedAdmins(taggedAdmins, isGuest)
-
-	commentReply := &model.CommentReply{
-		SessionCommentID: sessionComment.ID,
-		Admins:           admins,
-		AdminId:          admin.ID,
-		Text:             text,
-	}
-	createSessionCommentReplySpan, _ := util.StartSpanFromContext(ctx, "db.createSessionCommentReply",
-		util.ResourceName("resolver.createSessionCommentReply"), util.Tag("project_id", sessionComment.ProjectID))
-	if err := r.DB.WithContext(ctx).Create(commentReply).Error; err != nil {
-		return nil, e.Wrap(err, "error creating session comment reply")
+	var mmFilters []*model.DashboardMetricFilter
+	for _, f := range filters {
+		mmFilters = append(mmFilters, &model.DashboardMetricFilter{
+			Tag:   f.Tag,
+			Op:    f.Op,
+			Value: f.Value,
+		})
 	}
-	createSessionCommentReplySpan.Finish()
-
-	viewLink := fmt.Sprintf("%v?commentId=%v", sessionURL, sessionComment.ID)
-	muteLink := fmt.Sprintf("%v?commentId=%v&muted=1", sessionURL, sessionComment.ID)
 
-	if len(taggedAdmins) > 0 {
-		r.sendCommentPrimaryNotification(
-			ctx,
-			admin,
-			*admin.Name,
-			taggedAdmins,
-			workspace,
-			project.ID,
-			&sessionComment.ID,
-			nil,
-			textForEmail,
-			viewLink,
-			muteLink,
-			&sessionComment.SessionImage,
-			"replied to",
-			"session",
-			nil,
-			&Email.SessionCommentMentionsAsmId,
-		)
-	}
-	if len(sessionComment.Followers) > 0 {
-		var threadIDs []int
-		for _, thread := range sessionComment.Threads {
-			threadIDs = append(threadIDs, thread.ID)
-		}
-		r.sendFollowedCommentNotification(
-			ctx,
-			admin,
-			sessionComment.Followers,
-			workspace,
-			project.ID,
-			threadIDs,
-			textForEmail,
-			viewLink,
-			muteLink,
-			&sessionComment.SessionImage,
-			"replied to",
-			"session",
-			&Email.SessionCommentMentionsAsmId,
-		)
+	newMetricMonitor := &model.MetricMonitor{
+		ProjectID:         projectID,
+		Name:              name,
+		Aggregator:        aggregator,
+		PeriodMinutes:     periodMinutes,
+		Threshold:         threshold,
+		Units:             units,
+		MetricToMonitor:   metricToMonitor,
+		ChannelsToNotify:  channelsString,
+		EmailsToNotify:    emailsString,
+		LastAdminToEditID: admin.ID,
+		Filters:           mmFilters,
+		AlertIntegrations: model.AlertIntegrations{
+			DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
+			WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+		},
 	}
 
-	existingAdminIDs, existingSlackChannelIDs := r.getCommentFollowers(ctx, sessionComment.Followers)
-	taggedAdmins = append(taggedAdmins, &modelInputs.SanitizedAdminInput{
-		ID:    admin.ID,
-		Name:  admin.Name,
-		Email: *admin.Email,
-	})
-	newFollowers := r.findNewFollowers(taggedAdmins, taggedSlackUsers, existingAdminIDs, existingSlackChannelIDs)
-	for _, f := range newFollowers {
-		f.SessionCommentID = commentID
+	if err := r.DB.WithContext(ctx).Create(newMetricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "error creating a new error alert")
 	}
-
-	if len(newFollowers) > 0 {
-		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
-			log.WithContext(ctx).Error("Failed to create new session reply followers", err)
-		}
+	if err := model.SendWelcomeSlackMessage(ctx, newMetricMonitor, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "created",
+		OperationDescription: "Monitor alerts will be sent here",
+		ID:                   newMetricMonitor.ID,
+		Project:              project,
+		IncludeEditLink:      true,
+		URLSlug:              "alerts/monitors",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
 	}
 
-	return commentReply, nil
+	return newMetricMonitor, nil
 }
 
-// CreateErrorComment is the resolver for the createErrorComment field.
-func (r *mutationResolver) CreateErrorComment(ctx context.Context, projectID int, errorGroupSecureID string, text string, textForEmail string, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput, errorURL string, authorName string, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType) (*model.ErrorComment, error) {
-	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
-
-	errorGroup, err := r.canAdminViewErrorGroup(ctx, errorGroupSecureID)
+// UpdateMetricMonitor is the resolver for the updateMetricMonitor field.
+func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonitorID int, projectID int, name *string, aggregator *modelInputs.MetricAggregator, periodMinutes *int, threshold *float64, units *string, metricToMonitor *string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, disabled *bool, filters []*modelInputs.MetricTagFilterInput) (*model.MetricMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	var project model.Project
-	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
-		return nil, err
+	metricMonitor := &model.MetricMonitor{}
+	if err := r.DB.WithContext(ctx).Where(&model.MetricMonitor{Model: model.Model{ID: metricMonitorID}, ProjectID: projectID}).Find(&metricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "error querying metric monitor")
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
-	if err != nil {
-		return nil, err
+	var createdFilterIDs []int
+	for _, f := range filters {
+		var created struct{ ID int }
+		if err := r.DB.WithContext(ctx).Where(&model.DashboardMetricFilter{
+			MetricMonitorID: metricMonitor.ID,
+			Tag:             f.Tag,
+		}).Clauses(clause.Returning{}, clause.OnConflict{
+			OnConstraint: model.DASHBOARD_METRIC_FILTERS_UNIQ,
+			DoNothing:    true,
+		}).Create(&model.DashboardMetricFilter{
+			MetricMonitorID: metricMonitor.ID,
+			Tag:             f.Tag,
+			Op:              f.Op,
+			Value:           f.Value,
+		}).Scan(&created).Error; err != nil {
+			return nil, e.Wrap(err, "failed to create metric monitor filter")
+		}
+		createdFilterIDs = append(createdFilterIDs, created.ID)
 	}
+	r.DB.Exec(`DELETE FROM dashboard_metric_filters WHERE metric_monitor_id = ? AND id NOT IN ?`, metricMonitor.ID, createdFilterIDs)
 
-	admins := []model.Admin{}
-	for _, a := range taggedAdmins {
-		admins = append(admins,
-			model.Admin{
-				Model: model.Model{ID: a.ID},
-			},
-		)
+	if slackChannels != nil {
+		channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
+		if err != nil {
+			return nil, e.Wrap(err, "error marshalling slack channels")
+		}
+		metricMonitor.ChannelsToNotify = channelsString
 	}
 
-	errorComment := &model.ErrorComment{
-		Admins:        admins,
-		ProjectID:     projectID,
-		AdminId:       admin.Model.ID,
-		ErrorId:       errorGroup.ID,
-		ErrorSecureId: errorGroup.SecureID,
-		Text:          text,
+	metricMonitor.AlertIntegrations = model.AlertIntegrations{
+		DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
+		WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
 	}
 
-	createErrorCommentSpan, _ := util.StartSpanFromContext(ctx, "db.createErrorComment",
-		util.ResourceName("resolver.createErrorComment"), util.Tag("project_id", projectID))
-
-	if err := r.DB.WithContext(ctx).Create(errorComment).Error; err != nil {
-		return nil, e.Wrap(err, "error creating error comment")
+	if emails != nil {
+		emailsString, err := r.MarshalAlertEmails(emails)
+		if err != nil {
+			return nil, err
+		}
+		metricMonitor.EmailsToNotify = emailsString
 	}
 
-	createErrorCommentSpan.Finish()
-
-	viewLink := fmt.Sprintf("%v?commentId=%v", errorURL, errorComment.ID)
-	muteLink := fmt.Sprintf("%v?commentId=%v&muted=1", errorURL, errorComment.ID)
-
-	if len(taggedAdmins) > 0 && !isGuest {
-		r.sendCommentPrimaryNotification(
-			ctx,
-			admin,
-			authorName,
-			taggedAdmins,
-			workspace,
-			projectID,
-			nil,
-			&errorComment.ID,
-			textForEmail,
-			viewLink,
-			muteLink,
-			nil,
-			"tagged",
-			"error",
-			nil,
-			&Email.ErrorCommentMentionsAsmId,
-		)
-	}
-	if len(taggedSlackUsers) > 0 && !isGuest {
-		r.sendCommentMentionNotification(
-			ctx,
-			admin,
-			taggedSlackUsers,
-			workspace,
-			projectID,
-			nil,
-			&errorComment.ID,
-			textForEmail,
-			viewLink,
-			nil,
-			"tagged",
-			"error",
-			nil,
-		)
+	if name != nil {
+		metricMonitor.Name = *name
 	}
-
-	for _, s := range integrations {
-		attachment := &model.ExternalAttachment{
-			IntegrationType: *s,
-			ErrorCommentID:  errorComment.ID,
-		}
-
-		title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
-		desc += "See the error page on Highlight:\n"
-		desc += fmt.Sprintf("%s/%d/errors/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, errorComment.ErrorSecureId)
-
-		if *s == modelInputs.IntegrationTypeLinear && workspace.LinearAccessToken != nil && *workspace.LinearAccessToken != "" {
-			if err := r.CreateLinearIssueAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				*issueTitle,
-				*issueDescription,
-				textForEmail,
-				authorName,
-				viewLink,
-				issueTeamID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating linear ticket or workspace")
-			}
-
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeClickUp && workspace.ClickupAccessToken != nil && *workspace.ClickupAccessToken != "" {
-			if err := r.CreateClickUpTaskAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				title,
-				desc,
-				issueTeamID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating ClickUp task")
-			}
-
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeHeight {
-			if err := r.CreateHeightTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating Height task")
-			}
-
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitHub {
-			if err := r.CreateGitHubTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID, nil); err != nil {
-				return nil, e.Wrap(err, "error creating GitHub task")
-			}
-
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeJira {
-			if issueTeamID == nil || issueTypeID == nil {
-				return nil, e.New("issue team and type are required")
-			}
-
-			if err := r.CreateJiraTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID, *issueTypeID); err != nil {
-				return nil, e.Wrap(err, "error creating Jira task")
-			}
-
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitLab {
-			if err := r.CreateGitlabTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating GitLab task")
-			}
-
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		}
+	if aggregator != nil {
+		metricMonitor.Aggregator = *aggregator
 	}
-
-	taggedUsers := append(taggedAdmins, &modelInputs.SanitizedAdminInput{
-		ID:    admin.ID,
-		Name:  admin.Name,
-		Email: *admin.Email,
-	})
-	newFollowers := r.findNewFollowers(taggedUsers, taggedSlackUsers, nil, nil)
-	for _, f := range newFollowers {
-		f.ErrorCommentID = errorComment.ID
+	metricMonitor.PeriodMinutes = periodMinutes
+	if threshold != nil {
+		metricMonitor.Threshold = *threshold
 	}
-	if len(newFollowers) > 0 {
-		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
-			log.WithContext(ctx).Error("Failed to create new session comment followers", err)
-		}
+	metricMonitor.Units = units
+	if metricToMonitor != nil {
+		metricMonitor.MetricToMonitor = *metricToMonitor
 	}
 
-	return errorComment, nil
-}
-
-// RemoveErrorIssue is the resolver for the removeErrorIssue field.
-func (r *mutationResolver) RemoveErrorIssue(ctx context.Context, errorIssueID int) (*bool, error) {
-	var errorCommentID int
-	if err := r.DB.
-		Model(&model.ExternalAttachment{}).
-		Select("error_comment_id").
-		Where("id=?", errorIssueID).
-		First(&errorCommentID).
-		Error; err != nil {
-		return nil, e.Wrap(err, "error querying error issues")
-	}
+	metricMonitor.LastAdminToEditID = admin.ID
 
-	var errorGroupSecureID string
-	if err := r.DB.
-		Model(&model.ErrorComment{}).
-		Select("error_secure_id").
-		Where("id=?", errorCommentID).
-		First(&errorGroupSecureID).
-		Error; err != nil {
-		return nil, e.Wrap(err, "error querying error comments")
+	if disabled != nil {
+		metricMonitor.Disabled = disabled
 	}
 
-	_, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
-	if err != nil {
-		return nil, e.Wrap(err, "admin is not authorized to modify error group")
+	if err := r.DB.Save(&metricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "error updating metric monitor")
 	}
 
-	var externalAttachment model.ExternalAttachment
-	if err := r.DB.
-		Model(&model.ExternalAttachment{}).
-		Where("id=?", errorIssueID).
-		First(&externalAttachment).
-		Updates(
-			&model.ExternalAttachment{
-				Removed: true,
-			}).
-		Error; err != nil {
-		return nil, e.Wrap(err, "error changing the muted status")
+	if err := model.SendWelcomeSlackMessage(ctx, metricMonitor, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "updated",
+		OperationDescription: "Monitor alerts will now be sent to this channel.",
+		ID:                   metricMonitorID,
+		Project:              project,
+		IncludeEditLink:      true,
+		URLSlug:              "alerts/monitors",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
 	}
-
-	return &model.T, nil
+	return metricMonitor, nil
 }
 
-// MuteErrorCommentThread is the resolver for the muteErrorCommentThread field.
-func (r *mutationResolver) MuteErrorCommentThread(ctx context.Context, id int, hasMuted *bool) (*bool, error) {
-	var errorGroupSecureID string
-	if err := r.DB.
-		Model(&model.ErrorComment{}).
-		Select("error_secure_id").
-		Where("id=?", id).
-		First(&errorGroupSecureID).
-		Error; err != nil {
-		return nil, e.Wrap(err, "error querying error comments")
-	}
-	_, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
+// CreateErrorAlert is the resolver for the createErrorAlert field.
+func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int, name string, countThreshold int, thresholdWindow int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency int, defaultArg *bool) (*model.ErrorAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	admin, err := r.getCurrentAdmin(ctx)
+	envString, err := r.MarshalEnvironments(environments)
 	if err != nil {
 		return nil, err
 	}
 
-	var commentFollower model.CommentFollower
-	if err := r.DB.WithContext(ctx).Where(&model.CommentFollower{ErrorCommentID: id, AdminId: admin.ID}).
-		First(&commentFollower).
-		Updates(
-			&model.CommentFollower{
-				HasMuted: hasMuted,
-			}).
-		Error; err != nil {
-		return nil, e.Wrap(err, "error changing the muted status")
+	channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
+	if err != nil {
+		return nil, err
 	}
 
-	return &model.T, nil
-}
-
-// CreateIssueForErrorComment is the resolver for the createIssueForErrorComment field.
-func (r *mutationResolver) CreateIssueForErrorComment(ctx context.Context, projectID int, errorURL string, errorCommentID int, authorName string, textForAttachment string, issueTitle *string, issueDescription *string, issueTeamID *string, issueTypeID *string, integrations []*modelInputs.IntegrationType) (*model.ErrorComment, error) {
-	var project model.Project
-	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
-		return nil, err
+	regexGroupsBytes, err := json.Marshal(regexGroups)
+	if err != nil {
+		return nil, e.Wrap(err, "error marshalling regex groups")
 	}
+	regexGroupsString := string(regexGroupsBytes)
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	emailsString, err := r.MarshalAlertEmails(emails)
 	if err != nil {
 		return nil, err
 	}
 
-	errorComment := &model.ErrorComment{}
-	if err := r.DB.WithContext(ctx).Preload("Attachments").Where(&model.ErrorComment{Model: model.Model{ID: errorCommentID}}).Find(errorComment).Error; err != nil {
-		return nil, err
+	if defaultArg == nil {
+		defaultArg = pointy.Bool(false)
 	}
 
-	viewLink := fmt.Sprintf("%v", errorURL)
+	newAlert := &model.ErrorAlert{
+		Alert: model.Alert{
+			ProjectID:            projectID,
+			OrganizationID:       projectID,
+			ExcludedEnvironments: envString,
+			CountThreshold:       countThreshold,
+			ThresholdWindow:      &thresholdWindow,
+			Type:                 &model.AlertType.ERROR,
+			ChannelsToNotify:     channelsString,
+			EmailsToNotify:       emailsString,
+			Name:                 name,
+			LastAdminToEditID:    admin.ID,
+			Frequency:            frequency,
+			Default:              *defaultArg,
+		},
+		RegexGroups: &regexGroupsString,
+		AlertIntegrations: model.AlertIntegrations{
+			DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
+			WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+		},
+	}
 
-	if issueDescription == nil {
-		return nil, e.New("issue description cannot be nil")
+	if err := r.DB.WithContext(ctx).Create(newAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error creating a new error alert")
+	}
+	if err := model.SendWelcomeSlackMessage(ctx, newAlert, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "created",
+		OperationDescription: "Alerts will now be sent to this channel.",
+		ID:                   newAlert.ID,
+		Project:              project,
+		IncludeEditLink:      true,
+		URLSlug:              "alerts/errors",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
 	}
 
-	title, desc := r.Store.BuildIssueTitleAndDescription(*issueTitle, issueDescription)
-	desc += "See the error page on Highlight:\n"
-	desc += fmt.Sprintf("%s/%d/errors/%s", os.Getenv("REACT_APP_FRONTEND_URI"), projectID, errorComment.ErrorSecureId)
+	return newAlert, nil
+}
 
-	for _, s := range integrations {
-		attachment := &model.ExternalAttachment{
-			IntegrationType: *s,
-			ErrorCommentID:  errorComment.ID,
-		}
+// UpdateErrorAlert is the resolver for the updateErrorAlert field.
+func (r *mutationResolver) UpdateErrorAlert(ctx context.Context, projectID int, name *string, errorAlertID int, countThreshold *int, thresholdWindow *int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency *int, disabled *bool, anomalyDetection *bool, anomalySensitivity *float64) (*model.ErrorAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
 
-		if *s == modelInputs.IntegrationTypeLinear && workspace.LinearAccessToken != nil && *workspace.LinearAccessToken != "" {
-			if err := r.CreateLinearIssueAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				*issueTitle,
-				*issueDescription,
-				errorComment.Text,
-				authorName,
-				viewLink,
-				issueTeamID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating linear ticket or workspace")
-			}
+	projectAlert := &model.ErrorAlert{}
+	if err := r.DB.WithContext(ctx).Where(&model.ErrorAlert{Model: model.Model{ID: errorAlertID}}).Find(&projectAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error alert")
+	}
 
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeClickUp && workspace.ClickupAccessToken != nil && *workspace.ClickupAccessToken != "" {
-			if err := r.CreateClickUpTaskAndAttachment(
-				ctx,
-				workspace,
-				attachment,
-				title,
-				desc,
-				issueTeamID,
-			); err != nil {
-				return nil, e.Wrap(err, "error creating ClickUp task")
-			}
+	if environments != nil {
+		envString, err := r.MarshalEnvironments(environments)
+		if err != nil {
+			return nil, err
+		}
 
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeHeight {
-			if err := r.CreateHeightTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating Height task")
-			}
+		projectAlert.ExcludedEnvironments = envString
+	}
 
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitHub {
-			if err := r.CreateGitHubTaskAndAttachment(ctx, workspace, attachment, title, desc, issueTeamID, nil); err != nil {
-				return nil, e.Wrap(err, "error creating GitHub task")
-			}
+	if slackChannels != nil {
+		channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
+		if err != nil {
+			return nil, err
+		}
+		regexGroupsBytes, err := json.Marshal(regexGroups)
+		if err != nil {
+			return nil, e.Wrap(err, "error marshalling regex groups")
+		}
+		regexGroupsString := string(regexGroupsBytes)
 
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeJira {
-			if issueTeamID == nil || issueTypeID == nil {
-				return nil, e.New("issue team and type are required")
-			}
+		projectAlert.RegexGroups = &regexGroupsString
+		projectAlert.ChannelsToNotify = channelsString
+		projectAlert.RegexGroups = &regexGroupsString
+	}
 
-			if err := r.CreateJiraTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID, *issueTypeID); err != nil {
-				return nil, e.Wrap(err, "error creating Jira task")
-			}
+	if emails != nil {
+		emailsString, err := r.MarshalAlertEmails(emails)
+		if err != nil {
+			return nil, err
+		}
 
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		} else if *s == modelInputs.IntegrationTypeGitLab {
-			if err := r.CreateGitlabTaskAndAttachment(ctx, workspace, attachment, title, desc, *issueTeamID); err != nil {
-				return nil, e.Wrap(err, "error creating GitLab task")
-			}
+		projectAlert.EmailsToNotify = emailsString
+	}
 
-			errorComment.Attachments = append(errorComment.Attachments, attachment)
-		}
+	if countThreshold != nil {
+		projectAlert.CountThreshold = *countThreshold
+	}
+	if thresholdWindow != nil {
+		projectAlert.ThresholdWindow = thresholdWindow
+	}
+	if name != nil {
+		projectAlert.Name = *name
 	}
 
-	return errorComment, nil
-}
+	projectAlert.LastAdminToEditID = admin.ID
 
-// DeleteErrorComment is the resolver for the deleteErrorComment field.
-func (r *mutationResolver) DeleteErrorComment(ctx context.Context, id int) (*bool, error) {
-	var errorGroupSecureID string
-	if err := r.DB.Table("error_comments").Select("error_secure_id").Where("id=?", id).Scan(&errorGroupSecureID).Error; err != nil {
-		return nil, e.Wrap(err, "error querying error comments")
-	}
-	_, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
-	if err != nil {
-		return nil, e.Wrap(err, "admin is not authorized to modify error group")
+	if frequency != nil {
+		projectAlert.Frequency = *frequency
 	}
-	if err := r.DB.Delete(&model.ErrorComment{Model: model.Model{ID: id}}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting error_comment")
+	if disabled != nil {
+		projectAlert.Disabled = disabled
 	}
-	if err := r.DB.WithContext(ctx).Where(&model.ExternalAttachment{ErrorCommentID: id}).Delete(&model.ExternalAttachment{}).Error; err != nil {
-		return nil, e.Wrap(err, "error deleting session comment attachments")
+	if anomalyDetection != nil || anomalySensitivity != nil {
+		if err := applyErrorAlertAnomalyDetection(anomalyDetection, anomalySensitivity, projectAlert); err != nil {
+			return nil, err
+		}
 	}
-	return &model.T, nil
-}
 
-// ReplyToErrorComment is the resolver for the replyToErrorComment field.
-func (r *mutationResolver) ReplyToErrorComment(ctx context.Context, commentID int, text string, textForEmail string, errorURL string, taggedAdmins []*modelInputs.SanitizedAdminInput, taggedSlackUsers []*modelInputs.SanitizedSlackChannelInput) (*model.CommentReply, error) {
-	admin, isGuest := r.getCurrentAdminOrGuest(ctx)
-	if isGuest {
-		return nil, e.New("must be logged in to add a comment reply")
+	projectAlert.AlertIntegrations = model.AlertIntegrations{
+		DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
+		WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
 	}
 
-	var errorComment model.ErrorComment
-	if err := r.DB.WithContext(ctx).Preload("Threads").Preload("Followers").Where(model.ErrorComment{Model: model.Model{ID: commentID}}).Take(&errorComment).Error; err != nil {
-		return nil, e.Wrap(err, "error querying error comment")
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{
+		Model: model.Model{
+			ID: errorAlertID,
+		},
+	}).Where("project_id = ?", projectID).Updates(projectAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error updating org fields")
 	}
-
-	_, err := r.canAdminViewErrorGroup(ctx, errorComment.ErrorSecureId)
-	if err != nil {
-		return nil, err
+	// the anomaly mode is updated separately as Updates skips it when it is turned off
+	if anomalyDetection != nil {
+		if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{
+			Model: model.Model{
+				ID: errorAlertID,
+			},
+		}).Where("project_id = ?", projectID).Update("AnomalyDetection", projectAlert.AnomalyDetection).Error; err != nil {
+			return nil, e.Wrap(err, "error updating error alert anomaly detection")
+		}
 	}
 
-	var project model.Project
-	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: errorComment.ProjectID}}).Take(&project).Error; err != nil {
-		return nil, err
+	if err := model.SendWelcomeSlackMessage(ctx, projectAlert, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "updated",
+		OperationDescription: "Alerts will now be sent to this channel.",
+		ID:                   errorAlertID,
+		Project:              project,
+		IncludeEditLink:      true,
+		URLSlug:              "alerts/errors",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
 	}
+	return projectAlert, nil
+}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
+// DeleteErrorAlert is the resolver for the deleteErrorAlert field.
+func (r *mutationResolver) DeleteErrorAlert(ctx context.Context, projectID int, errorAlertID int) (*model.ErrorAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	admins := r.getTaggedAdmins(taggedAdmins, isGuest)
-
-	commentReply := &model.CommentReply{
-		ErrorCommentID: errorComment.ID,
-		Admins:         admins,
-		AdminId:        admin.ID,
-		Text:           text,
-	}
-	createErrorCommentReplySpan, _ := util.StartSpanFromContext(ctx, "db.createErrorCommentReply",
-		util.ResourceName("resolver.createErrorCommentReply"), util.Tag("project_id", errorComment.ProjectID))
-	if err := r.DB.WithContext(ctx).Create(commentReply).Error; err != nil {
-		return nil, e.Wrap(err, "error creating error comment reply")
-	}
-	createErrorCommentReplySpan.Finish()
-
-	viewLink := fmt.Sprintf("%v?commentId=%v", errorURL, errorComment.ID)
-	muteLink := fmt.Sprintf("%v?commentId=%v&muted=1", errorURL, errorComment.ID)
-
-	if len(taggedAdmins) > 0 && !isGuest {
-		r.sendCommentPrimaryNotification(
-			ctx,
-			admin,
-			*admin.Name,
-			taggedAdmins,
-			workspace,
-			project.ID, nil,
-			&errorComment.ID,
-			textForEmail,
-			viewLink,
-			muteLink,
-			nil,
-			"replied to",
-			"error",
-			nil,
-			&Email.ErrorCommentMentionsAsmId,
-		)
-	}
-	if len(errorComment.Followers) > 0 && !isGuest {
-		var threadIDs []int
-		for _, thread := range errorComment.Threads {
-			threadIDs = append(threadIDs, thread.ID)
-		}
-		r.sendFollowedCommentNotification(
-			ctx,
-			admin,
-			errorComment.Followers,
-			workspace,
-			project.ID,
-			threadIDs,
-			textForEmail,
-			viewLink,
-			muteLink,
-			nil,
-			"replied to",
-			"error",
-			&Email.ErrorCommentMentionsAsmId,
-		)
+	projectAlert := &model.ErrorAlert{}
+	if err := r.DB.WithContext(ctx).Where(&model.ErrorAlert{Model: model.Model{ID: errorAlertID}, Alert: model.Alert{ProjectID: projectID}}).Find(&projectAlert).Error; err != nil {
+		return nil, e.Wrap(err, "this error alert does not exist in this project.")
 	}
 
-	existingAdminIDs, existingSlackChannelIDs := r.getCommentFollowers(ctx, errorComment.Followers)
-	taggedAdmins = append(taggedAdmins, &modelInputs.SanitizedAdminInput{
-		ID:    admin.ID,
-		Name:  admin.Name,
-		Email: *admin.Email,
-	})
-	newFollowers := r.findNewFollowers(taggedAdmins, taggedSlackUsers, existingAdminIDs, existingSlackChannelIDs)
-	for _, f := range newFollowers {
-		f.ErrorCommentID = commentID
+	if err := r.DB.Delete(projectAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error trying to delete error alert")
 	}
 
-	if len(newFollowers) > 0 {
-		if err := r.DB.WithContext(ctx).Create(&newFollowers).Error; err != nil {
-			log.WithContext(ctx).Error("Failed to create new error reply followers", err)
-		}
+	if err := model.SendWelcomeSlackMessage(ctx, projectAlert, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "deleted",
+		OperationDescription: "Alerts will no longer be sent to this channel.",
+		ID:                   errorAlertID,
+		Project:              project,
+		IncludeEditLink:      false,
+		URLSlug:              "alerts/errors",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
 	}
 
-	return commentReply, nil
+	return projectAlert, nil
 }
 
-// AddIntegrationToProject is the resolver for the addIntegrationToProject field.
-func (r *mutationResolver) AddIntegrationToProject(ctx context.Context, integrationType *modelInputs.IntegrationType, projectID int, code string) (bool, error) {
+// DeleteMetricMonitor is the resolver for the deleteMetricMonitor field.
+func (r *mutationResolver) DeleteMetricMonitor(ctx context.Context, projectID int, metricMonitorID int) (*model.MetricMonitor, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
-	if err != nil {
-		return false, err
+	metricMonitor := &model.MetricMonitor{}
+	if err := r.DB.WithContext(ctx).Where(&model.MetricMonitor{Model: model.Model{ID: metricMonitorID}, ProjectID: projectID}).Find(&metricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "this metric monitor does not exist in this project.")
 	}
 
-	if *integrationType == modelInputs.IntegrationTypeLinear {
-		if err := r.AddLinearToWorkspace(workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeJira {
-		if err := r.AddJiraToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeGitLab {
-		if err := r.AddGitlabToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeSlack {
-		if err := r.AddSlackToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeFront {
-		if err := r.AddFrontToProject(ctx, project, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeVercel {
-		if err := r.AddVercelToWorkspace(workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeDiscord {
-		if err := r.AddDiscordToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else {
-		return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
+	if err := r.DB.Delete(metricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "error trying to delete metric monitor")
 	}
 
-	return true, nil
+	if err := model.SendWelcomeSlackMessage(ctx, metricMonitor, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "deleted",
+		OperationDescription: "Monitor alerts will no longer be sent to this channel.",
+		ID:                   metricMonitorID,
+		Project:              project,
+		IncludeEditLink:      false,
+		URLSlug:              "alerts/monitors",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	return metricMonitor, nil
 }
 
-// RemoveIntegrationFromProject is the resolver for the removeIntegrationFromProject field.
-func (r *mutationResolver) RemoveIntegrationFromProject(ctx context.Context, integrationType *modelInputs.IntegrationType, projectID int) (bool, error) {
-	project, err := r.isAdminInProject(ctx, projectID)
+// UpdateSessionAlertIsDisabled is the resolver for the updateSessionAlertIsDisabled field.
+func (r *mutationResolver) UpdateSessionAlertIsDisabled(ctx context.Context, id int, projectID int, disabled bool) (*model.SessionAlert, error) {
+	_, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
-	if err != nil {
-		return false, err
+	sessionAlert := &model.SessionAlert{
+		Alert: model.Alert{
+			Disabled: &disabled,
+		},
 	}
 
-	if *integrationType == modelInputs.IntegrationTypeLinear {
-		if err := r.RemoveLinearFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeJira {
-		if err := r.RemoveJiraFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeSlack {
-		if err := r.RemoveSlackFromWorkspace(workspace, projectID); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeZapier {
-		if err := r.RemoveZapierFromWorkspace(project); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeFront {
-		if err := r.RemoveFrontFromProject(project); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeVercel {
-		if err := r.RemoveVercelFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeDiscord {
-		if err := r.RemoveDiscordFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeGitHub {
-		if err := r.RemoveDiscordFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeGitLab {
-		if err := r.RemoveGitlabFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else {
-		return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
+	if err := r.DB.WithContext(ctx).Model(&model.SessionAlert{
+		Model: model.Model{
+			ID: id,
+		},
+	}).Where("project_id = ?", projectID).Updates(sessionAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error updating org fields for new session alert")
 	}
 
-	return true, nil
+	return sessionAlert, err
 }
 
-// AddIntegrationToWorkspace is the resolver for the addIntegrationToWorkspace field.
-func (r *mutationResolver) AddIntegrationToWorkspace(ctx context.Context, integrationType *modelInputs.IntegrationType, workspaceID int, code string) (bool, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// UpdateErrorAlertIsDisabled is the resolver for the updateErrorAlertIsDisabled field.
+func (r *mutationResolver) UpdateErrorAlertIsDisabled(ctx context.Context, id int, projectID int, disabled bool) (*model.ErrorAlert, error) {
+	_, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	if *integrationType == modelInputs.IntegrationTypeClickUp {
-		if err := r.AddClickUpToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeHeight {
-		if err := r.AddHeightToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeGitHub {
-		if err := r.AddGitHubToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeJira {
-		if err := r.AddJiraToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else if *integrationType == modelInputs.IntegrationTypeGitLab {
-		if err := r.AddGitlabToWorkspace(ctx, workspace, code); err != nil {
-			return false, err
-		}
-	} else {
-		return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
+	errorAlert := &model.ErrorAlert{
+		Alert: model.Alert{
+			Disabled: &disabled,
+		},
+	}
+
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{
+		Model: model.Model{
+			ID: id,
+		},
+	}).Where("project_id = ?", projectID).Updates(errorAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error updating disabled field for error alert")
 	}
 
-	return true, nil
+	return errorAlert, err
 }
 
-// RemoveIntegrationFromWorkspace is the resolver for the removeIntegrationFromWorkspace field.
-func (r *mutationResolver) RemoveIntegrationFromWorkspace(ctx context.Context, integrationType modelInputs.IntegrationType, workspaceID int) (bool, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// UpdateErrorAlertDestinations is the resolver for the updateErrorAlertDestinations field.
+func (r *mutationResolver) UpdateErrorAlertDestinations(ctx context.Context, projectID int, errorAlertID int, destinations modelInputs.ErrorAlertDestinationsInput) (*model.ErrorAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	if integrationType == modelInputs.IntegrationTypeClickUp {
-		if err := r.RemoveClickUpFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if integrationType == modelInputs.IntegrationTypeGitHub {
-		if err := r.RemoveGitHubFromWorkspace(ctx, workspace); err != nil {
-			return false, err
-		}
-	} else if integrationType == modelInputs.IntegrationTypeJira {
-		if err := r.RemoveJiraFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else if integrationType == modelInputs.IntegrationTypeGitLab {
-		if err := r.RemoveGitlabFromWorkspace(workspace); err != nil {
-			return false, err
-		}
-	} else {
-		if err := r.RemoveIntegrationFromWorkspaceAndProjects(ctx, workspace, integrationType); err != nil {
-			return false, err
-		}
+	errorAlert := &model.ErrorAlert{}
+	if err := r.DB.WithContext(ctx).Where(&model.ErrorAlert{
+		Model: model.Model{ID: errorAlertID},
+		Alert: model.Alert{ProjectID: project.ID},
+	}).Take(errorAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error alert")
+	}
 
+	pagerDuty, opsgenie, splunkOnCall, err := getErrorAlertDestinations(destinations, errorAlert)
+	if err != nil {
+		return nil, err
 	}
 
-	return true, nil
+	if err := r.DB.WithContext(ctx).Model(errorAlert).Updates(map[string]interface{}{
+		"PagerDutyDestinations":    pagerDuty,
+		"OpsgenieDestinations":     opsgenie,
+		"SplunkOnCallDestinations": splunkOnCall,
+	}).Error; err != nil {
+		return nil, e.Wrap(err, "error updating error alert destinations")
+	}
+	errorAlert.PagerDutyDestinations = pagerDuty
+	errorAlert.OpsgenieDestinations = opsgenie
+	errorAlert.SplunkOnCallDestinations = splunkOnCall
+
+	return errorAlert, nil
 }
 
-// SyncSlackIntegration is the resolver for the syncSlackIntegration field.
-func (r *mutationResolver) SyncSlackIntegration(ctx context.Context, projectID int) (*modelInputs.SlackSyncResponse, error) {
+// UpdateAlertMicrosoftTeamsChannels is the resolver for the updateAlertMicrosoftTeamsChannels field.
+func (r *mutationResolver) UpdateAlertMicrosoftTeamsChannels(ctx context.Context, projectID int, alertKind modelInputs.AlertKind, alertID int, channels []*modelInputs.MicrosoftTeamsChannelInput) ([]*model.MicrosoftTeamsChannel, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	response := modelInputs.SlackSyncResponse{
-		Success:               true,
-		NewChannelsAddedCount: 0,
-	}
 	if err != nil {
-		return &response, err
+		return nil, err
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	microsoftTeamsChannels, err := getMicrosoftTeamsChannels(channels)
 	if err != nil {
-		return &response, err
+		return nil, err
+	}
+	alert, _, err := r.getAlertIntegrations(ctx, project, alertKind, alertID)
+	if err != nil {
+		return nil, err
 	}
-	slackChannels, newChannelsCount, err := r.GetSlackChannelsFromSlack(ctx, workspace.ID)
 
+	if err := r.DB.WithContext(ctx).Model(alert).Update("MicrosoftTeamsChannelsToNotify", microsoftTeamsChannels).Error; err != nil {
+		return nil, e.Wrap(err, "error updating alert Microsoft Teams channels")
+	}
+	return microsoftTeamsChannels, nil
+}
+
+// UpdateAlertDiscordWebhooks is the resolver for the updateAlertDiscordWebhooks field.
+func (r *mutationResolver) UpdateAlertDiscordWebhooks(ctx context.Context, projectID int, alertKind modelInputs.AlertKind, alertID int, webhooks []*modelInputs.DiscordWebhookInput) ([]*model.DiscordWebhook, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return &response, err
+		return nil, err
 	}
 
-	channelBytes, err := json.Marshal(slackChannels)
+	discordWebhooks, err := getDiscordWebhooks(webhooks)
 	if err != nil {
-		return &response, e.Wrap(err, "error marshaling slack channels")
+		return nil, err
 	}
-	channelString := string(channelBytes)
-	if err := r.DB.WithContext(ctx).Model(&workspace).Updates(&model.Workspace{
-		SlackChannels: &channelString,
-	}).Error; err != nil {
-		return &response, e.Wrap(err, "error updating workspace slack channels")
+	alert, _, err := r.getAlertIntegrations(ctx, project, alertKind, alertID)
+	if err != nil {
+		return nil, err
 	}
 
-	response.NewChannelsAddedCount = newChannelsCount
-
-	return &response, nil
+	if err := r.DB.WithContext(ctx).Model(alert).Update("DiscordWebhooksToNotify", discordWebhooks).Error; err != nil {
+		return nil, e.Wrap(err, "error updating alert Discord webhooks")
+	}
+	return discordWebhooks, nil
 }
 
-// CreateMetricMonitor is the resolver for the createMetricMonitor field.
-func (r *mutationResolver) CreateMetricMonitor(ctx context.Context, projectID int, name string, aggregator modelInputs.MetricAggregator, periodMinutes *int, threshold float64, units *string, metricToMonitor string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, filters []*modelInputs.MetricTagFilterInput) (*model.MetricMonitor, error) {
+// UpdateDigestDiscordWebhooks is the resolver for the updateDigestDiscordWebhooks field.
+func (r *mutationResolver) UpdateDigestDiscordWebhooks(ctx context.Context, projectID int, webhooks []*modelInputs.DiscordWebhookInput) ([]*model.DiscordWebhook, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	admin, _ := r.getCurrentAdmin(ctx)
-	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
+	discordWebhooks, err := getDiscordWebhooks(webhooks)
 	if err != nil {
 		return nil, err
 	}
 
-	emailsString, err := r.MarshalAlertEmails(emails)
+	if err := r.DB.WithContext(ctx).Model(&model.Project{Model: model.Model{ID: project.ID}}).Update("DiscordDigestWebhooks", discordWebhooks).Error; err != nil {
+		return nil, e.Wrap(err, "error updating project Discord digest webhooks")
+	}
+	return discordWebhooks, nil
+}
+
+// UpdateMetricMonitorIsDisabled is the resolver for the updateMetricMonitorIsDisabled field.
+func (r *mutationResolver) UpdateMetricMonitorIsDisabled(ctx context.Context, id int, projectID int, disabled bool) (*model.MetricMonitor, error) {
+	_, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	var mmFilters []*model.DashboardMetricFilter
-	for _, f := range filters {
-		mmFilters = append(mmFilters, &model.DashboardMetricFilter{
-			Tag:   f.Tag,
-			Op:    f.Op,
-			Value: f.Value,
-		})
+	metricMonitor := &model.MetricMonitor{
+		Disabled: &disabled,
 	}
 
-	newMetricMonitor := &model.MetricMonitor{
-		ProjectID:         projectID,
-		Name:              name,
-		Aggregator:        aggregator,
-		PeriodMinutes:     periodMinutes,
-		Threshold:         threshold,
-		Units:             units,
-		MetricToMonitor:   metricToMonitor,
-		ChannelsToNotify:  channelsString,
-		EmailsToNotify:    emailsString,
-		LastAdminToEditID: admin.ID,
-		Filters:           mmFilters,
-		AlertIntegrations: model.AlertIntegrations{
-			DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
-			WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+	if err := r.DB.WithContext(ctx).Model(&model.MetricMonitor{
+		Model: model.Model{
+			ID: id,
 		},
+	}).Where("project_id = ?", projectID).Updates(metricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "error updating disabled field for metric monitor")
 	}
 
-	if err := r.DB.WithContext(ctx).Create(newMetricMonitor).Error; err != nil {
-		return nil, e.Wrap(err, "error creating a new error alert")
+	return metricMonitor, err
+}
+
+// CreateUptimeMonitor is the resolver for the createUptimeMonitor field.
+func (r *mutationResolver) CreateUptimeMonitor(ctx context.Context, projectID int, input modelInputs.UptimeMonitorInput) (*model.UptimeMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
-	if err := model.SendWelcomeSlackMessage(ctx, newMetricMonitor, &model.SendWelcomeSlackMessageInput{
-		Workspace:            workspace,
-		Admin:                admin,
-		OperationName:        "created",
-		OperationDescription: "Monitor alerts will be sent here",
-		ID:                   newMetricMonitor.ID,
-		Project:              project,
-		IncludeEditLink:      true,
-		URLSlug:              "alerts/monitors",
-	}); err != nil {
-		log.WithContext(ctx).Error(err)
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return newMetricMonitor, nil
+	monitor := &model.UptimeMonitor{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyUptimeMonitorInput(input, monitor); err != nil {
+		return nil, err
+	}
+	if err := r.Store.CreateUptimeMonitor(ctx, monitor); err != nil {
+		return nil, e.Wrap(err, "error creating uptime monitor")
+	}
+	return monitor, nil
 }
 
-// UpdateMetricMonitor is the resolver for the updateMetricMonitor field.
-func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonitorID int, projectID int, name *string, aggregator *modelInputs.MetricAggregator, periodMinutes *int, threshold *float64, units *string, metricToMonitor *string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, disabled *bool, filters []*modelInputs.MetricTagFilterInput) (*model.MetricMonitor, error) {
+// UpdateUptimeMonitor is the resolver for the updateUptimeMonitor field.
+func (r *mutationResolver) UpdateUptimeMonitor(ctx context.Context, projectID int, id int, input modelInputs.UptimeMonitorInput) (*model.UptimeMonitor, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	admin, _ := r.getCurrentAdmin(ctx)
-	workspace, _ := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	metricMonitor := &model.MetricMonitor{}
-	if err := r.DB.WithContext(ctx).Where(&model.MetricMonitor{Model: model.Model{ID: metricMonitorID}, ProjectID: projectID}).Find(&metricMonitor).Error; err != nil {
-		return nil, e.Wrap(err, "error querying metric monitor")
+	monitor, err := r.Store.GetUptimeMonitor(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying uptime monitor")
+	}
+	if err := applyUptimeMonitorInput(input, monitor); err != nil {
+		return nil, err
 	}
+	monitor.LastAdminToEditID = admin.ID
 
-	var createdFilterIDs []int
-	for _, f := range filters {
-		var created struct{ ID int }
-		if err := r.DB.WithContext(ctx).Where(&model.DashboardMetricFilter{
-			MetricMonitorID: metricMonitor.ID,
-			Tag:             f.Tag,
-		}).Clauses(clause.Returning{}, clause.OnConflict{
-			OnConstraint: model.DASHBOARD_METRIC_FILTERS_UNIQ,
-			DoNothing:    true,
-		}).Create(&model.DashboardMetricFilter{
-			MetricMonitorID: metricMonitor.ID,
-			Tag:             f.Tag,
-			Op:              f.Op,
-			Value:           f.Value,
-		}).Scan(&created).Error; err != nil {
-			return nil, e.Wrap(err, "failed to create metric monitor filter")
-		}
-		createdFilterIDs = append(createdFilterIDs, created.ID)
+	if err := r.Store.UpdateUptimeMonitor(ctx, monitor); err != nil {
+		return nil, e.Wrap(err, "error updating uptime monitor")
 	}
-	r.DB.Exec(`DELETE FROM dashboard_metric_filters WHERE metric_monitor_id = ? AND id NOT IN ?`, metricMonitor.ID, createdFilterIDs)
+	return monitor, nil
+}
 
-	if slackChannels != nil {
-		channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
-		if err != nil {
-			return nil, e.Wrap(err, "error marshalling slack channels")
-		}
-		metricMonitor.ChannelsToNotify = channelsString
+// DeleteUptimeMonitor is the resolver for the deleteUptimeMonitor field.
+func (r *mutationResolver) DeleteUptimeMonitor(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
 	}
 
-	metricMonitor.AlertIntegrations = model.AlertIntegrations{
-		DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
-		WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+	if err := r.Store.DeleteUptimeMonitor(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting uptime monitor")
 	}
+	return true, nil
+}
 
-	if emails != nil {
-		emailsString, err := r.MarshalAlertEmails(emails)
-		if err != nil {
-			return nil, err
-		}
-		metricMonitor.EmailsToNotify = emailsString
+// CreateHeartbeatMonitor is the resolver for the createHeartbeatMonitor field.
+func (r *mutationResolver) CreateHeartbeatMonitor(ctx context.Context, projectID int, input modelInputs.HeartbeatMonitorInput) (*model.HeartbeatMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if name != nil {
-		metricMonitor.Name = *name
+	// the secure id is used in the check-in url so that it cannot be guessed
+	secureID, err := r.GenerateRandomStringURLSafe(24)
+	if err != nil {
+		return nil, e.Wrap(err, "error generating heartbeat monitor secure id")
 	}
-	if aggregator != nil {
-		metricMonitor.Aggregator = *aggregator
+	monitor := &model.HeartbeatMonitor{ProjectID: project.ID, SecureID: secureID, LastAdminToEditID: admin.ID}
+	if err := applyHeartbeatMonitorInput(input, monitor); err != nil {
+		return nil, err
 	}
-	metricMonitor.PeriodMinutes = periodMinutes
-	if threshold != nil {
-		metricMonitor.Threshold = *threshold
+	if err := r.Store.CreateHeartbeatMonitor(ctx, monitor); err != nil {
+		return nil, e.Wrap(err, "error creating heartbeat monitor")
 	}
-	metricMonitor.Units = units
-	if metricToMonitor != nil {
-		metricMonitor.MetricToMonitor = *metricToMonitor
+	return monitor, nil
+}
+
+// UpdateHeartbeatMonitor is the resolver for the updateHeartbeatMonitor field.
+func (r *mutationResolver) UpdateHeartbeatMonitor(ctx context.Context, projectID int, id int, input modelInputs.HeartbeatMonitorInput) (*model.HeartbeatMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	metricMonitor.LastAdminToEditID = admin.ID
+	monitor, err := r.Store.GetHeartbeatMonitor(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying heartbeat monitor")
+	}
+	if err := applyHeartbeatMonitorInput(input, monitor); err != nil {
+		return nil, err
+	}
+	monitor.LastAdminToEditID = admin.ID
 
-	if disabled != nil {
-		metricMonitor.Disabled = disabled
+	if err := r.Store.UpdateHeartbeatMonitor(ctx, monitor); err != nil {
+		return nil, e.Wrap(err, "error updating heartbeat monitor")
 	}
+	return monitor, nil
+}
 
-	if err := r.DB.Save(&metricMonitor).Error; err != nil {
-		return nil, e.Wrap(err, "error updating metric monitor")
+// DeleteHeartbeatMonitor is the resolver for the deleteHeartbeatMonitor field.
+func (r *mutationResolver) DeleteHeartbeatMonitor(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
 	}
 
-	if err := model.SendWelcomeSlackMessage(ctx, metricMonitor, &model.SendWelcomeSlackMessageInput{
-		Workspace:            workspace,
-		Admin:                admin,
-		OperationName:        "updated",
-		OperationDescription: "Monitor alerts will now be sent to this channel.",
-		ID:                   metricMonitorID,
-		Project:              project,
-		IncludeEditLink:      true,
-		URLSlug:              "alerts/monitors",
-	}); err != nil {
-		log.WithContext(ctx).Error(err)
+	if err := r.Store.DeleteHeartbeatMonitor(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting heartbeat monitor")
 	}
-	return metricMonitor, nil
+	return true, nil
 }
 
-// CreateErrorAlert is the resolver for the createErrorAlert field.
-func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int, name string, countThreshold int, thresholdWindow int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency int, defaultArg *bool) (*model.ErrorAlert, error) {
+// CreateTraceAlert is the resolver for the createTraceAlert field.
+func (r *mutationResolver) CreateTraceAlert(ctx context.Context, projectID int, input modelInputs.TraceAlertInput) (*model.TraceAlert, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	admin, _ := r.getCurrentAdmin(ctx)
-	workspace, _ := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	envString, err := r.MarshalEnvironments(environments)
-	if err != nil {
+	alert := &model.TraceAlert{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := r.applyTraceAlertInput(input, alert); err != nil {
 		return nil, err
 	}
+	if err := r.Store.CreateTraceAlert(ctx, alert); err != nil {
+		return nil, e.Wrap(err, "error creating trace alert")
+	}
+	return alert, nil
+}
 
-	channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
+// UpdateTraceAlert is the resolver for the updateTraceAlert field.
+func (r *mutationResolver) UpdateTraceAlert(ctx context.Context, projectID int, id int, input modelInputs.TraceAlertInput) (*model.TraceAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
-
-	regexGroupsBytes, err := json.Marshal(regexGroups)
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
-		return nil, e.Wrap(err, "error marshalling regex groups")
+		return nil, err
 	}
-	regexGroupsString := string(regexGroupsBytes)
 
-	emailsString, err := r.MarshalAlertEmails(emails)
+	alert, err := r.Store.GetTraceAlert(ctx, project.ID, id)
 	if err != nil {
+		return nil, e.Wrap(err, "error querying trace alert")
+	}
+	if err := r.applyTraceAlertInput(input, alert); err != nil {
 		return nil, err
 	}
+	alert.LastAdminToEditID = admin.ID
 
-	if defaultArg == nil {
-		defaultArg = pointy.Bool(false)
+	if err := r.Store.UpdateTraceAlert(ctx, alert); err != nil {
+		return nil, e.Wrap(err, "error updating trace alert")
 	}
+	return alert, nil
+}
 
-	newAlert := &model.ErrorAlert{
-		Alert: model.Alert{
-			ProjectID:            projectID,
-			OrganizationID:       projectID,
-			ExcludedEnvironments: envString,
-			CountThreshold:       countThreshold,
-			ThresholdWindow:      &thresholdWindow,
-			Type:                 &model.AlertType.ERROR,
-			ChannelsToNotify:     channelsString,
-			EmailsToNotify:       emailsString,
-			Name:                 name,
-			LastAdminToEditID:    admin.ID,
-			Frequency:            frequency,
-			Default:              *defaultArg,
-		},
-		RegexGroups: &regexGroupsString,
-		AlertIntegrations: model.AlertIntegrations{
-			DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
-			WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
-		},
+// DeleteTraceAlert is the resolver for the deleteTraceAlert field.
+func (r *mutationResolver) DeleteTraceAlert(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
 	}
 
-	if err := r.DB.WithContext(ctx).Create(newAlert).Error; err != nil {
-		return nil, e.Wrap(err, "error creating a new error alert")
-	}
-	if err := model.SendWelcomeSlackMessage(ctx, newAlert, &model.SendWelcomeSlackMessageInput{
-		Workspace:            workspace,
-		Admin:                admin,
-		OperationName:        "created",
-		OperationDescription: "Alerts will now be sent to this channel.",
-		ID:                   newAlert.ID,
-		Project:              project,
-		IncludeEditLink:      true,
-		URLSlug:              "alerts/errors",
-	}); err != nil {
-		log.WithContext(ctx).Error(err)
+	if err := r.Store.DeleteTraceAlert(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting trace alert")
 	}
-
-	return newAlert, nil
+	return true, nil
 }
 
-// UpdateErrorAlert is the resolver for the updateErrorAlert field.
-func (r *mutationResolver) UpdateErrorAlert(ctx context.Context, projectID int, name *string, errorAlertID int, countThreshold *int, thresholdWindow *int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency *int, disabled *bool) (*model.ErrorAlert, error) {
+// CreateEscalationPolicy is the resolver for the createEscalationPolicy field.
+func (r *mutationResolver) CreateEscalationPolicy(ctx context.Context, projectID int, input modelInputs.EscalationPolicyInput) (*model.EscalationPolicy, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	admin, _ := r.getCurrentAdmin(ctx)
-	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
-
-	projectAlert := &model.ErrorAlert{}
-	if err := r.DB.WithContext(ctx).Where(&model.ErrorAlert{Model: model.Model{ID: errorAlertID}}).Find(&projectAlert).Error; err != nil {
-		return nil, e.Wrap(err, "error querying error alert")
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if environments != nil {
-		envString, err := r.MarshalEnvironments(environments)
-		if err != nil {
-			return nil, err
-		}
-
-		projectAlert.ExcludedEnvironments = envString
+	policy := &model.EscalationPolicy{ProjectID: project.ID, LastAdminToEditID: admin.ID}
+	if err := applyEscalationPolicyInput(input, policy); err != nil {
+		return nil, err
 	}
-
-	if slackChannels != nil {
-		channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
-		if err != nil {
-			return nil, err
-		}
-		regexGroupsBytes, err := json.Marshal(regexGroups)
-		if err != nil {
-			return nil, e.Wrap(err, "error marshalling regex groups")
-		}
-		regexGroupsString := string(regexGroupsBytes)
-
-		projectAlert.RegexGroups = &regexGroupsString
-		projectAlert.ChannelsToNotify = channelsString
-		projectAlert.RegexGroups = &regexGroupsString
+	if err := r.validateEscalationSchedules(ctx, project.ID, policy.Steps); err != nil {
+		return nil, err
 	}
-
-	if emails != nil {
-		emailsString, err := r.MarshalAlertEmails(emails)
-		if err != nil {
-			return nil, err
-		}
-
-		projectAlert.EmailsToNotify = emailsString
+	if err := r.Store.CreateEscalationPolicy(ctx, policy); err != nil {
+		return nil, e.Wrap(err, "error creating escalation policy")
 	}
+	return policy, nil
+}
 
-	if countThreshold != nil {
-		projectAlert.CountThreshold = *countThreshold
-	}
-	if thresholdWindow != nil {
-		projectAlert.ThresholdWindow = thresholdWindow
+// UpdateEscalationPolicy is the resolver for the updateEscalationPolicy field.
+func (r *mutationResolver) UpdateEscalationPolicy(ctx context.Context, projectID int, id int, input modelInputs.EscalationPolicyInput) (*model.EscalationPolicy, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
-	if name != nil {
-		projectAlert.Name = *name
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	projectAlert.LastAdminToEditID = admin.ID
-
-	if frequency != nil {
-		projectAlert.Frequency = *frequency
-	}
-	if disabled != nil {
-		projectAlert.Disabled = disabled
+	policy, err := r.Store.GetEscalationPolicy(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying escalation policy")
 	}
-
-	projectAlert.AlertIntegrations = model.AlertIntegrations{
-		DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
-		WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+	if err := applyEscalationPolicyInput(input, policy); err != nil {
+		return nil, err
 	}
-
-	if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{
-		Model: model.Model{
-			ID: errorAlertID,
-		},
-	}).Where("project_id = ?", projectID).Updates(projectAlert).Error; err != nil {
-		return nil, e.Wrap(err, "error updating org fields")
+	if err := r.validateEscalationSchedules(ctx, project.ID, policy.Steps); err != nil {
+		return nil, err
 	}
+	policy.LastAdminToEditID = admin.ID
 
-	if err := model.SendWelcomeSlackMessage(ctx, projectAlert, &model.SendWelcomeSlackMessageInput{
-		Workspace:            workspace,
-		Admin:                admin,
-		OperationName:        "updated",
-		OperationDescription: "Alerts will now be sent to this channel.",
-		ID:                   errorAlertID,
-		Project:              project,
-		IncludeEditLink:      true,
-		URLSlug:              "alerts/errors",
-	}); err != nil {
-		log.WithContext(ctx).Error(err)
+	if err := r.Store.UpdateEscalationPolicy(ctx, policy); err != nil {
+		return nil, e.Wrap(err, "error updating escalation policy")
 	}
-	return projectAlert, nil
+	return policy, nil
 }
 
-// DeleteErrorAlert is the resolver for the deleteErrorAlert field.
-func (r *mutationResolver) DeleteErrorAlert(ctx context.Context, projectID int, errorAlertID int) (*model.ErrorAlert, error) {
+// DeleteEscalationPolicy is the resolver for the deleteEscalationPolicy field.
+func (r *mutationResolver) DeleteEscalationPolicy(ctx context.Context, projectID int, id int) (bool, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	admin, _ := r.getCurrentAdmin(ctx)
-	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	projectAlert := &model.ErrorAlert{}
-	if err := r.DB.WithContext(ctx).Where(&model.ErrorAlert{Model: model.Model{ID: errorAlertID}, Alert: model.Alert{ProjectID: projectID}}).Find(&projectAlert).Error; err != nil {
-		return nil, e.Wrap(err, "this error alert does not exist in this project.")
+	if err := r.Store.DeleteEscalationPolicy(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting escalation policy")
 	}
+	return true, nil
+}
 
-	if err := r.DB.Delete(projectAlert).Error; err != nil {
-		return nil, e.Wrap(err, "error trying to delete error alert")
+// UpdateAlertEscalationPolicy is the resolver for the updateAlertEscalationPolicy field.
+func (r *mutationResolver) UpdateAlertEscalationPolicy(ctx context.Context, projectID int, alertType string, alertID int, escalationPolicyID *int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
 	}
-
-	if err := model.SendWelcomeSlackMessage(ctx, projectAlert, &model.SendWelcomeSlackMessageInput{
-		Workspace:            workspace,
-		Admin:                admin,
-		OperationName:        "deleted",
-		OperationDescription: "Alerts will no longer be sent to this channel.",
-		ID:                   errorAlertID,
-		Project:              project,
-		IncludeEditLink:      false,
-		URLSlug:              "alerts/errors",
-	}); err != nil {
-		log.WithContext(ctx).Error(err)
+	if escalationPolicyID != nil {
+		if _, err := r.Store.GetEscalationPolicy(ctx, project.ID, *escalationPolicyID); err != nil {
+			return false, e.Errorf("invalid escalation_policy_id %d", *escalationPolicyID)
+		}
 	}
 
-	return projectAlert, nil
+	if err := r.Store.UpdateAlertEscalationPolicy(ctx, project.ID, alertType, alertID, escalationPolicyID); err != nil {
+		return false, e.Wrap(err, "error updating alert escalation policy")
+	}
+	return true, nil
 }
 
-// DeleteMetricMonitor is the resolver for the deleteMetricMonitor field.
-func (r *mutationResolver) DeleteMetricMonitor(ctx context.Context, projectID int, metricMonitorID int) (*model.MetricMonitor, error) {
+// CreateOnCallSchedule is the resolver for the createOnCallSchedule field.
+func (r *mutationResolver) CreateOnCallSchedule(ctx context.Context, projectID int, input modelInputs.OnCallScheduleInput) (*model.OnCallSchedule, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
-	admin, _ := r.getCurrentAdmin(ctx)
-	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	metricMonitor := &model.MetricMonitor{}
-	if err := r.DB.WithContext(ctx).Where(&model.MetricMonitor{Model: model.Model{ID: metricMonitorID}, ProjectID: projectID}).Find(&metricMonitor).Error; err != nil {
-		return nil, e.Wrap(err, "this metric monitor does not exist in this project.")
-	}
-
-	if err := r.DB.Delete(metricMonitor).Error; err != nil {
-		return nil, e.Wrap(err, "error trying to delete metric monitor")
+	schedule := &model.OnCallSchedule{ProjectID: project.ID}
+	if err := applyOnCallScheduleInput(input, schedule); err != nil {
+		return nil, err
 	}
-
-	if err := model.SendWelcomeSlackMessage(ctx, metricMonitor, &model.SendWelcomeSlackMessageInput{
-		Workspace:            workspace,
-		Admin:                admin,
-		OperationName:        "deleted",
-		OperationDescription: "Monitor alerts will no longer be sent to this channel.",
-		ID:                   metricMonitorID,
-		Project:              project,
-		IncludeEditLink:      false,
-		URLSlug:              "alerts/monitors",
-	}); err != nil {
-		log.WithContext(ctx).Error(err)
+	if err := r.Store.CreateOnCallSchedule(ctx, schedule); err != nil {
+		return nil, e.Wrap(err, "error creating on-call schedule")
 	}
-
-	return metricMonitor, nil
+	return schedule, nil
 }
 
-// UpdateSessionAlertIsDisabled is the resolver for the updateSessionAlertIsDisabled field.
-func (r *mutationResolver) UpdateSessionAlertIsDisabled(ctx context.Context, id int, projectID int, disabled bool) (*model.SessionAlert, error) {
-	_, err := r.isAdminInProject(ctx, projectID)
+// UpdateOnCallSchedule is the resolver for the updateOnCallSchedule field.
+func (r *mutationResolver) UpdateOnCallSchedule(ctx context.Context, projectID int, id int, input modelInputs.OnCallScheduleInput) (*model.OnCallSchedule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	sessionAlert := &model.SessionAlert{
-		Alert: model.Alert{
-			Disabled: &disabled,
-		},
+	schedule, err := r.Store.GetOnCallSchedule(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying on-call schedule")
 	}
-
-	if err := r.DB.WithContext(ctx).Model(&model.SessionAlert{
-		Model: model.Model{
-			ID: id,
-		},
-	}).Where("project_id = ?", projectID).Updates(sessionAlert).Error; err != nil {
-		return nil, e.Wrap(err, "error updating org fields for new session alert")
+	if err := applyOnCallScheduleInput(input, schedule); err != nil {
+		return nil, err
 	}
-
-	return sessionAlert, err
+	if err := r.Store.UpdateOnCallSchedule(ctx, schedule); err != nil {
+		return nil, e.Wrap(err, "error updating on-call schedule")
+	}
+	return schedule, nil
 }
 
-// UpdateErrorAlertIsDisabled is the resolver for the updateErrorAlertIsDisabled field.
-func (r *mutationResolver) UpdateErrorAlertIsDisabled(ctx context.Context, id int, projectID int, disabled bool) (*model.ErrorAlert, error) {
-	_, err := r.isAdminInProject(ctx, projectID)
+// DeleteOnCallSchedule is the resolver for the deleteOnCallSchedule field.
+func (r *mutationResolver) DeleteOnCallSchedule(ctx context.Context, projectID int, id int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
-		return nil, err
-	}
-
-	errorAlert := &model.ErrorAlert{
-		Alert: model.Alert{
-			Disabled: &disabled,
-		},
+		return false, err
 	}
 
-	if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{
-		Model: model.Model{
-			ID: id,
-		},
-	}).Where("project_id = ?", projectID).Updates(errorAlert).Error; err != nil {
-		return nil, e.Wrap(err, "error updating disabled field for error alert")
+	if err := r.Store.DeleteOnCallSchedule(ctx, project.ID, id); err != nil {
+		return false, e.Wrap(err, "error deleting on-call schedule")
 	}
-
-	return errorAlert, err
+	return true, nil
 }
 
-// UpdateMetricMonitorIsDisabled is the resolver for the updateMetricMonitorIsDisabled field.
-func (r *mutationResolver) UpdateMetricMonitorIsDisabled(ctx context.Context, id int, projectID int, disabled bool) (*model.MetricMonitor, error) {
-	_, err := r.isAdminInProject(ctx, projectID)
+// AcknowledgeAlertEscalation is the resolver for the acknowledgeAlertEscalation field.
+func (r *mutationResolver) AcknowledgeAlertEscalation(ctx context.Context, projectID int, id int) (*model.AlertEscalation, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	metricMonitor := &model.MetricMonitor{
-		Disabled: &disabled,
+	escalation, err := r.Store.GetAlertEscalation(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying alert escalation")
 	}
-
-	if err := r.DB.WithContext(ctx).Model(&model.MetricMonitor{
-		Model: model.Model{
-			ID: id,
-		},
-	}).Where("project_id = ?", projectID).Updates(metricMonitor).Error; err != nil {
-		return nil, e.Wrap(err, "error updating disabled field for metric monitor")
+	acknowledged, err := alerts.AcknowledgeEscalation(ctx, r.DB, escalation, &admin.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !acknowledged {
+		return nil, e.New("the escalation was already acknowledged or resolved")
 	}
 
-	return metricMonitor, err
+	escalation, err = r.Store.GetAlertEscalation(ctx, project.ID, id)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying alert escalation")
+	}
+	return escalation, nil
 }
 
 // UpdateSessionAlert is the resolver for the updateSessionAlert field.
@@ -3335,6 +4984,7 @@ func (r *mutationResolver) UpdateLogAlertIsDisabled(ctx context.Context, id int,
 		Alert: model.Alert{
 			Disabled: &disabled,
 		},
+		State: model.AlertStateNormal,
 	}
 
 	if err := r.DB.WithContext(ctx).Model(&model.LogAlert{
@@ -3390,9 +5040,9 @@ func (r *mutationResolver) UpdateAllowMeterOverage(ctx context.Context, workspac
 		return nil, e.Wrap(err, "admin is not in workspace")
 	}
 
-	err = r.validateAdminRole(ctx, workspaceID)
+	err = r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageBilling)
 	if err != nil {
-		return nil, e.Wrap(err, "must have ADMIN role to modify meter overage settings")
+		return nil, e.Wrap(err, "must have manage billing permission to modify meter overage settings")
 	}
 
 	if err := r.DB.WithContext(ctx).Model(&workspace).Updates(map[string]interface{}{
@@ -3497,7 +5147,7 @@ func (r *mutationResolver) RequestAccess(ctx context.Context, projectID int) (*b
 	}
 
 	var workspaceAdmins []*model.Admin
-	if err := r.DB.Order("created_at ASC").Model(workspace).Limit(2).Association("Admins").Find(&workspaceAdmins, "role=?", model.AdminRole.ADMIN); err != nil {
+	if err := r.DB.Order("created_at ASC").Model(workspace).Limit(2).Association("Admins").Find(&workspaceAdmins, "role IN ?", []string{model.AdminRole.OWNER, model.AdminRole.ADMIN}); err != nil {
 		log.WithContext(ctx).Error(e.Wrap(err, "error getting admins for the workspace"))
 		return &model.T, nil
 	}
@@ -3672,13 +5322,13 @@ func (r *mutationResolver) DeleteSessions(ctx context.Context, projectID int, qu
 		firstName = *admin.FirstName
 	}
 
-	role, err := r.GetAdminRole(ctx, admin.ID, project.WorkspaceID)
+	role, err := r.getWorkspaceRole(ctx, admin.ID, project.WorkspaceID)
 	if err != nil {
 		return false, err
 	}
 
-	if role != model.AdminRole.ADMIN {
-		return false, e.New("Must be admin role to delete sessions")
+	if !role.Can(rbac.PermissionDeleteSessions) {
+		return false, e.New("Must have delete sessions permission to delete sessions")
 	}
 
 	_, err = r.StepFunctions.DeleteSessionsByQuery(ctx, utils.QuerySessionsInput{
@@ -3857,6 +5507,20 @@ func (r *mutationResolver) UpdateClickUpProjectMappings(ctx context.Context, wor
 	return true, nil
 }
 
+// RefreshClickUpMetadata is the resolver for the refreshClickUpMetadata field.
+func (r *mutationResolver) RefreshClickUpMetadata(ctx context.Context, projectID int) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
+
+	// drops the cached teams, spaces, folders and lists, ie. so that a list created in ClickUp can be picked
+	if err := clickup.NewMetadataCache(r.Redis, project.WorkspaceID).Invalidate(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // UpdateIntegrationProjectMappings is the resolver for the updateIntegrationProjectMappings field.
 func (r *mutationResolver) UpdateIntegrationProjectMappings(ctx context.Context, workspaceID int, integrationType modelInputs.IntegrationType, projectMappings []*modelInputs.IntegrationProjectMappingInput) (bool, error) {
 	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
@@ -3970,6 +5634,84 @@ func (r *mutationResolver) EditServiceGithubSettings(ctx context.Context, id int
 	return service, nil
 }
 
+// EditServiceGitlabSettings is the resolver for the editServiceGitlabSettings field.
+func (r *mutationResolver) EditServiceGitlabSettings(ctx context.Context, id int, projectID int, gitlabRepoPath *string, buildPrefix *string, repoPrefix *string) (*model.Service, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if gitlabRepoPath != nil {
+		workspace, err := r.GetWorkspace(project.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeGitLab)
+		if err != nil {
+			return nil, e.Wrap(err, "error querying GitLab integration")
+		}
+		if accessToken == nil {
+			return nil, e.New("workspace does not have a GitLab integration")
+		}
+	}
+
+	service := &model.Service{}
+	if err := r.DB.WithContext(ctx).Where(&model.Service{Model: model.Model{ID: id}, ProjectID: project.ID}).Take(&service).Error; err != nil {
+		return nil, e.Wrap(err, "error querying service")
+	}
+
+	serviceUpdates := map[string]interface{}{
+		"ErrorDetails":   make([]string, 0),
+		"BuildPrefix":    buildPrefix,
+		"GithubPrefix":   repoPrefix,
+		"GitlabRepoPath": gitlabRepoPath,
+	}
+	// the service stays healthy when it still has a github repository
+	if gitlabRepoPath != nil || service.GithubRepoPath != nil {
+		serviceUpdates["Status"] = "healthy"
+	} else {
+		serviceUpdates["Status"] = "created"
+	}
+	if err := r.DB.WithContext(ctx).Model(service).Updates(&serviceUpdates).Error; err != nil {
+		return nil, e.Wrap(err, "error updating service gitlab settings")
+	}
+
+	_ = r.Store.DeleteServiceCache(ctx, service.Name, service.ProjectID)
+	_, _ = r.Redis.ResetServiceErrorCount(ctx, service.ID)
+	return service, nil
+}
+
+// UpdateGitHubProjectRepository is the resolver for the updateGitHubProjectRepository field.
+func (r *mutationResolver) UpdateGitHubProjectRepository(ctx context.Context, projectID int, repository *string) (bool, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
+
+	if ptr.ToString(repository) != "" {
+		workspace, err := r.GetWorkspace(project.WorkspaceID)
+		if err != nil {
+			return false, err
+		}
+		repos, err := r.GetGitHubRepos(ctx, workspace)
+		if err != nil {
+			return false, e.Wrap(err, "error querying GitHub repositories")
+		}
+		if repos == nil {
+			return false, e.New("workspace does not have a GitHub integration")
+		}
+		// only repositories the app is installed in can be mapped
+		if !lo.ContainsBy(repos, func(repo *modelInputs.GitHubRepo) bool { return repo.Name == *repository }) {
+			return false, e.New("repository is not accessible to the GitHub app")
+		}
+	}
+
+	if err := githubissues.SetProjectRepository(ctx, r.DB, project.ID, ptr.ToString(repository)); err != nil {
+		return false, e.Wrap(err, "error updating GitHub repository")
+	}
+	return true, nil
+}
+
 // CreateErrorTag is the resolver for the createErrorTag field.
 func (r *mutationResolver) CreateErrorTag(ctx context.Context, title string, description string) (*model.ErrorTag, error) {
 	return r.Resolver.CreateErrorTag(ctx, title, description)
@@ -4074,6 +5816,36 @@ func (r *mutationResolver) TestErrorEnhancement(ctx context.Context, errorObject
 	return &errorObject, nil
 }
 
+// Participants is the resolver for the participants field.
+func (r *onCallScheduleResolver) Participants(ctx context.Context, obj *model.OnCallSchedule) ([]string, error) {
+	return obj.Participants, nil
+}
+
+// OnCall is the resolver for the on_call field.
+func (r *onCallScheduleResolver) OnCall(ctx context.Context, obj *model.OnCallSchedule) (string, error) {
+	return obj.GetOnCall(time.Now()), nil
+}
+
+// APIKeySet is the resolver for the api_key_set field.
+func (r *opsgenieDestinationResolver) APIKeySet(ctx context.Context, obj *model.OpsgenieDestination) (bool, error) {
+	return obj.APIKey != "", nil
+}
+
+// APIKeySet is the resolver for the api_key_set field.
+func (r *productAnalyticsExportResolver) APIKeySet(ctx context.Context, obj *model.ProductAnalyticsExport) (bool, error) {
+	return obj.APIKey != "", nil
+}
+
+// DiscordDigestWebhooks is the resolver for the discord_digest_webhooks field.
+func (r *projectResolver) DiscordDigestWebhooks(ctx context.Context, obj *model.Project) ([]*model.DiscordWebhook, error) {
+	return obj.DiscordDigestWebhooks, nil
+}
+
+// Frequency is the resolver for the frequency field.
+func (r *projectDigestSettingResolver) Frequency(ctx context.Context, obj *model.ProjectDigestSetting) (string, error) {
+	return obj.Frequency, nil
+}
+
 // Accounts is the resolver for the accounts field.
 func (r *queryResolver) Accounts(ctx context.Context) ([]*modelInputs.Account, error) {
 	if !r.isWhitelistedAccount(ctx) {
@@ -4211,6 +5983,19 @@ func (r *queryResolver) Accounts(ctx context.Context) ([]*modelInputs.Account, e
 	return accounts, nil
 }
 
+// ChaosFaults is the resolver for the chaos_faults field.
+func (r *queryResolver) ChaosFaults(ctx context.Context) ([]*modelInputs.ChaosFault, error) {
+	if !r.isWhitelistedAccount(ctx) {
+		return nil, AuthorizationError
+	}
+
+	faults, err := chaos.New(r.Redis).GetFaults(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying chaos faults")
+	}
+	return chaosFaultsOutput(faults), nil
+}
+
 // AccountDetails is the resolver for the account_details field.
 func (r *queryResolver) AccountDetails(ctx context.Context, workspaceID int) (*modelInputs.AccountDetails, error) {
 	workspace, err := r.GetWorkspace(workspaceID)
@@ -4501,6 +6286,26 @@ func (r *queryResolver) ErrorGroupsClickhouse(ctx context.Context, projectID int
 	}, nil
 }
 
+// ErrorGroupImpacts is the resolver for the error_group_impacts field.
+func (r *queryResolver) ErrorGroupImpacts(ctx context.Context, projectID int, count int, query modelInputs.ClickhouseQuery, page *int) (*model.ErrorGroupImpactResults, error) {
+	project, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getErrorGroupsByImpact(ctx, project, query, page, count)
+}
+
+// ErrorGroupTrends is the resolver for the error_group_trends field.
+func (r *queryResolver) ErrorGroupTrends(ctx context.Context, errorGroupSecureID string, params modelInputs.ErrorGroupFrequenciesParamsInput) (*model.ErrorGroupTrends, error) {
+	errorGroup, err := r.canAdminViewErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getErrorGroupTrends(ctx, errorGroup, params)
+}
+
 // ErrorsHistogramClickhouse is the resolver for the errors_histogram_clickhouse field.
 func (r *queryResolver) ErrorsHistogramClickhouse(ctx context.Context, projectID int, query modelInputs.ClickhouseQuery, histogramOptions modelInputs.DateHistogramOptions) (*model.ErrorsHistogram, error) {
 	project, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
@@ -4586,6 +6391,35 @@ func (r *queryResolver) ErrorObjectForLog(ctx context.Context, logCursor string)
 	return errorObject, nil
 }
 
+// ErrorObjectTraceLogs is the resolver for the error_object_trace_logs field.
+func (r *queryResolver) ErrorObjectTraceLogs(ctx context.Context, errorObjectID int, after *string, before *string) (*modelInputs.ErrorObjectTraceLogs, error) {
+	errorObject, err := r.canAdminViewErrorObject(ctx, errorObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if errorObject.TraceID == nil || *errorObject.TraceID == "" {
+		return nil, nil
+	}
+
+	// the logs of the trace are read within a day of the error
+	dateRange := modelInputs.DateRangeRequiredInput{
+		StartDate: errorObject.Timestamp.Add(-clickhouse.TraceLogsWindow),
+		EndDate:   errorObject.Timestamp.Add(clickhouse.TraceLogsWindow),
+	}
+	logs, err := r.ClickhouseClient.ReadLogsByTrace(ctx, errorObject.ProjectID, *errorObject.TraceID, dateRange, clickhouse.Pagination{
+		After:  after,
+		Before: before,
+	})
+	if err != nil {
+		return nil, e.Wrap(err, "error querying logs of error object trace")
+	}
+	return &modelInputs.ErrorObjectTraceLogs{
+		TraceID: *errorObject.TraceID,
+		LogsURL: getTraceLogsURL(errorObject.ProjectID, *errorObject.TraceID, dateRange),
+		Logs:    logs,
+	}, nil
+}
+
 // ErrorInstance is the resolver for the error_instance field.
 func (r *queryResolver) ErrorInstance(ctx context.Context, errorGroupSecureID string, errorObjectID *int) (*model.ErrorInstance, error) {
 	errorGroup, err := r.canAdminViewErrorGroup(ctx, errorGroupSecureID)
@@ -4816,16 +6650,16 @@ func (r *queryResolver) WebVitals(ctx context.Context, sessionSecureID string) (
 		return nil, nil
 	}
 
-	webVitalNames := []string{
-		"CLS", "FCP", "FID", "LCP", "TTFB",
-	}
-
-	webVitals, err := r.ClickhouseClient.QuerySessionCustomMetrics(ctx, s.ProjectID, sessionSecureID, webVitalNames)
+	webVitals, err := r.ClickhouseClient.QuerySessionWebVitals(ctx, s.ProjectID, sessionSecureID)
 	if err != nil {
 		return nil, err
 	}
+	if len(webVitals) > 0 {
+		return webVitals, nil
+	}
 
-	return webVitals, nil
+	// sessions recorded before web vitals had their own table
+	return r.ClickhouseClient.QuerySessionCustomMetrics(ctx, s.ProjectID, sessionSecureID, clickhouse.WebVitalNames)
 }
 
 // SessionComments is the resolver for the session_comments field.
@@ -4935,6 +6769,24 @@ func (r *queryResolver) ErrorIssue(ctx context.Context, errorGroupSecureID strin
 	return errorIssues, nil
 }
 
+// ExternalIssues is the resolver for the external_issues field.
+func (r *queryResolver) ExternalIssues(ctx context.Context, projectID int, errorGroupSecureID string) ([]*model.ExternalIssue, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	errorGroup, err := r.getProjectErrorGroup(ctx, project.ID, errorGroupSecureID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error group")
+	}
+
+	issues, err := r.Store.GetExternalIssues(ctx, errorGroup.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying external issues")
+	}
+	return issues, nil
+}
+
 // ErrorComments is the resolver for the error_comments field.
 func (r *queryResolver) ErrorComments(ctx context.Context, errorGroupSecureID string) ([]*model.ErrorComment, error) {
 	errorGroup, err := r.canAdminViewErrorGroup(ctx, errorGroupSecureID)
@@ -6015,6 +7867,10 @@ func (r *queryResolver) Projects(ctx context.Context) ([]*model.Project, error)
 		return nil, e.Wrap(err, "error getting associated projects")
 	}
 
+	projects = lo.Filter(projects, func(p *model.Project, _ int) bool {
+		return apiTokenAllowsWorkspace(ctx, p.WorkspaceID)
+	})
+
 	return projects, nil
 }
 
@@ -6389,6 +8245,8 @@ func (r *queryResolver) IsIntegratedWith(ctx context.Context, integrationType mo
 		return workspace.VercelAccessToken != nil, nil
 	} else if integrationType == modelInputs.IntegrationTypeDiscord {
 		return workspace.DiscordGuildId != nil, nil
+	} else if integrationType == modelInputs.IntegrationTypeIntercom || integrationType == modelInputs.IntegrationTypeZendesk {
+		return r.isProjectMappedTo(ctx, project, integrationType)
 	}
 
 	return false, e.New(fmt.Sprintf("invalid integrationType: %s", integrationType))
@@ -6490,15 +8348,20 @@ func (r *queryResolver) ClickupTeams(ctx context.Context, workspaceID int) ([]*m
 		return []*modelInputs.ClickUpTeam{}, nil
 	}
 
-	teams, err := clickup.GetTeams(*workspace.ClickupAccessToken)
+	client, err := r.ClickUpClient(ctx, workspace)
 	if err != nil {
 		return nil, err
 	}
 
+	teams, err := client.GetTeams(ctx)
+	if err != nil {
+		return nil, clickUpError(err)
+	}
+
 	for _, t := range teams {
-		t.Spaces, err = clickup.GetSpaces(*workspace.ClickupAccessToken, t.ID)
+		t.Spaces, err = client.GetSpaces(ctx, t.ID)
 		if err != nil {
-			return nil, err
+			return nil, clickUpError(err)
 		}
 	}
 
@@ -6563,7 +8426,17 @@ func (r *queryResolver) ClickupFolders(ctx context.Context, projectID int) ([]*m
 		return nil, e.New("Project does not have an associated ClickUp space")
 	}
 
-	return clickup.GetFolders(*workspace.ClickupAccessToken, settings.ExternalID)
+	client, err := r.ClickUpClient(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.GetFolders(ctx, settings.ExternalID)
+	if err != nil {
+		return nil, clickUpError(err)
+	}
+
+	return res, nil
 }
 
 // ClickupFolderlessLists is the resolver for the clickup_folderless_lists field.
@@ -6593,7 +8466,107 @@ func (r *queryResolver) ClickupFolderlessLists(ctx context.Context, projectID in
 		return nil, e.New("Project does not have an associated ClickUp space")
 	}
 
-	return clickup.GetFolderlessLists(*workspace.ClickupAccessToken, settings.ExternalID)
+	client, err := r.ClickUpClient(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.GetFolderlessLists(ctx, settings.ExternalID)
+	if err != nil {
+		return nil, clickUpError(err)
+	}
+
+	return res, nil
+}
+
+// ClickupListMembers is the resolver for the clickup_list_members field.
+func (r *queryResolver) ClickupListMembers(ctx context.Context, projectID int, listID string) ([]*modelInputs.ClickUpMember, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.clickUpProjectClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	// the users that tasks of the list can be assigned to
+	members, err := client.GetListMembers(ctx, listID)
+	if err != nil {
+		return nil, clickUpError(err)
+	}
+
+	return lo.Map(members, func(member *clickup.Member, _ int) *modelInputs.ClickUpMember {
+		return &modelInputs.ClickUpMember{
+			ID:             member.ID,
+			Username:       member.Username,
+			Email:          member.Email,
+			Initials:       member.Initials,
+			ProfilePicture: member.ProfilePicture,
+		}
+	}), nil
+}
+
+// ClickupListFields is the resolver for the clickup_list_fields field.
+func (r *queryResolver) ClickupListFields(ctx context.Context, projectID int, listID string) ([]*modelInputs.ClickUpCustomField, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.clickUpProjectClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := client.GetAccessibleCustomFields(ctx, listID)
+	if err != nil {
+		return nil, clickUpError(err)
+	}
+	return clickUpCustomFields(fields), nil
+}
+
+// ClickupStatus is the resolver for the clickup_status field.
+func (r *queryResolver) ClickupStatus(ctx context.Context, projectID int) (*modelInputs.ClickUpIntegrationStatus, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := clickup.VerifyIntegration(ctx, r.IntegrationsClient.WorkspaceTokenStore(workspace, modelInputs.IntegrationTypeClickUp))
+	if status.Error != "" && !status.ReconnectRequired {
+		log.WithContext(ctx).WithField("workspace_id", workspace.ID).Warn(e.New(status.Error))
+	}
+	return clickUpIntegrationStatus(status), nil
+}
+
+// ClickupTasks is the resolver for the clickup_tasks field.
+func (r *queryResolver) ClickupTasks(ctx context.Context, projectID int, teamID string, query string) ([]*modelInputs.ClickUpTask, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if teamID == "" || query == "" {
+		return nil, e.New("team_id and query are required")
+	}
+
+	client, err := r.clickUpProjectClient(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	// existing tasks are searched so that one can be linked to an error group instead of creating a duplicate
+	tasks, err := client.SearchTasks(ctx, teamID, query)
+	if err != nil {
+		return nil, clickUpError(err)
+	}
+	if tasks == nil {
+		tasks = []*modelInputs.ClickUpTask{}
+	}
+	return tasks, nil
 }
 
 // HeightLists is the resolver for the height_lists field.
@@ -6603,42 +8576,94 @@ func (r *queryResolver) HeightLists(ctx context.Context, projectID int) ([]*mode
 		return nil, err
 	}
 
-	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeHeight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := height.GetLists(*accessToken)
+
+	return lists, err
+}
+
+// HeightWorkspaces is the resolver for the height_workspaces field.
+func (r *queryResolver) HeightWorkspaces(ctx context.Context, workspaceID int) ([]*modelInputs.HeightWorkspace, error) {
+	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeHeight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if accessToken == nil {
+		return []*modelInputs.HeightWorkspace{}, nil
+	}
+
+	workspaces, err := height.GetWorkspaces(*accessToken)
+
+	return workspaces, err
+}
+
+// IssueTrackerProjects is the resolver for the issue_tracker_projects field.
+func (r *queryResolver) IssueTrackerProjects(ctx context.Context, projectID int, integrationType modelInputs.IntegrationType) ([]*modelInputs.IssueTrackerProject, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, err := r.projectIssueTracker(ctx, project, integrationType)
 	if err != nil {
 		return nil, err
 	}
-
-	accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeHeight)
-
+	projects, err := tracker.ListProjects(ctx)
 	if err != nil {
-		return nil, err
+		return nil, e.Wrap(err, "error querying issue tracker projects")
 	}
-
-	lists, err := height.GetLists(*accessToken)
-
-	return lists, err
+	return lo.Map(projects, func(p *issuetracker.Project, _ int) *modelInputs.IssueTrackerProject {
+		return &modelInputs.IssueTrackerProject{
+			ID:   p.ID,
+			Name: p.Name,
+			Path: p.Path,
+		}
+	}), nil
 }
 
-// HeightWorkspaces is the resolver for the height_workspaces field.
-func (r *queryResolver) HeightWorkspaces(ctx context.Context, workspaceID int) ([]*modelInputs.HeightWorkspace, error) {
-	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
+// IssueTrackerIssues is the resolver for the issue_tracker_issues field.
+func (r *queryResolver) IssueTrackerIssues(ctx context.Context, projectID int, integrationType modelInputs.IntegrationType, query string) ([]*modelInputs.IssueTrackerIssue, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
+	if query == "" {
+		return nil, e.New("query is required")
+	}
 
-	accessToken, err := r.IntegrationsClient.GetWorkspaceAccessToken(ctx, workspace, modelInputs.IntegrationTypeHeight)
-
+	tracker, err := r.projectIssueTracker(ctx, project, integrationType)
 	if err != nil {
 		return nil, err
 	}
-
-	if accessToken == nil {
-		return []*modelInputs.HeightWorkspace{}, nil
+	// existing issues are searched so that one can be linked to an error group instead of creating a duplicate
+	issues, err := tracker.SearchIssues(ctx, query)
+	if err != nil {
+		return nil, e.Wrap(err, "error searching issue tracker issues")
 	}
-
-	workspaces, err := height.GetWorkspaces(*accessToken)
-
-	return workspaces, err
+	return lo.Map(issues, func(i *issuetracker.Issue, _ int) *modelInputs.IssueTrackerIssue {
+		return &modelInputs.IssueTrackerIssue{
+			ID:    i.ID,
+			Title: i.Title,
+			URL:   i.URL,
+		}
+	}), nil
 }
 
 // IntegrationProjectMappings is the resolver for the integration_project_mappings field.
@@ -6684,15 +8709,13 @@ func (r *queryResolver) LinearTeams(ctx context.Context, projectID int) ([]*mode
 		return ret, nil
 	}
 
-	res, err := r.GetLinearTeams(*workspace.LinearAccessToken)
+	teams, err := linear.NewClient(*workspace.LinearAccessToken).GetTeams(ctx)
 
 	if err != nil {
 		return ret, e.Wrap(err, "error getting linear teams")
 	}
 
-	teamResponse := res.Data.Teams.Nodes
-
-	ret = lo.Map(teamResponse, func(team LinearTeam, _ int) *modelInputs.LinearTeam {
+	ret = lo.Map(teams, func(team *linear.Team, _ int) *modelInputs.LinearTeam {
 		return &modelInputs.LinearTeam{
 			TeamID: team.ID,
 			Name:   team.Name,
@@ -6703,6 +8726,35 @@ func (r *queryResolver) LinearTeams(ctx context.Context, projectID int) ([]*mode
 	return ret, nil
 }
 
+// LinearProjects is the resolver for the linear_projects field.
+func (r *queryResolver) LinearProjects(ctx context.Context, projectID int, teamID string) ([]*modelInputs.LinearProject, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.LinearAccessToken == nil || *workspace.LinearAccessToken == "" {
+		return nil, e.New("workspace does not have a Linear access token")
+	}
+
+	// new issues of the team can be added to a project by passing `<team id>/<project id>` as their team
+	projects, err := linear.NewClient(*workspace.LinearAccessToken).GetProjects(ctx, teamID)
+	if err != nil {
+		return nil, e.Wrap(err, "error getting linear projects")
+	}
+
+	return lo.Map(projects, func(project *linear.Project, _ int) *modelInputs.LinearProject {
+		return &modelInputs.LinearProject{
+			ID:   project.ID,
+			Name: project.Name,
+		}
+	}), nil
+}
+
 // JiraProjects is the resolver for the jira_projects field.
 func (r *queryResolver) JiraProjects(ctx context.Context, workspaceID int) ([]*modelInputs.JiraProject, error) {
 	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
@@ -6713,6 +8765,49 @@ func (r *queryResolver) JiraProjects(ctx context.Context, workspaceID int) ([]*m
 	return r.GetJiraProjects(ctx, workspace)
 }
 
+// JiraSites is the resolver for the jira_sites field.
+func (r *queryResolver) JiraSites(ctx context.Context, projectID int) ([]*modelInputs.AccessibleJiraResources, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, accessToken, err := r.jiraProjectAccessToken(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	// the sites that the integration can create issues in
+	sites, err := jira.GetJiraSites(accessToken)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying Jira sites")
+	}
+	if sites == nil {
+		sites = []*modelInputs.AccessibleJiraResources{}
+	}
+	return sites, nil
+}
+
+// JiraIssueTypes is the resolver for the jira_issue_types field.
+func (r *queryResolver) JiraIssueTypes(ctx context.Context, projectID int, jiraProjectID string) ([]*modelInputs.JiraIssueType, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, accessToken, err := r.jiraProjectAccessToken(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	issueTypes, err := jira.GetJiraIssueTypes(workspace, accessToken, jiraProjectID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying Jira issue types")
+	}
+	if issueTypes == nil {
+		issueTypes = []*modelInputs.JiraIssueType{}
+	}
+	return issueTypes, nil
+}
+
 // GitlabProjects is the resolver for the gitlab_projects field.
 func (r *queryResolver) GitlabProjects(ctx context.Context, workspaceID int) ([]*modelInputs.GitlabProject, error) {
 	workspace, err := r.isAdminInWorkspace(ctx, workspaceID)
@@ -6743,6 +8838,22 @@ func (r *queryResolver) GithubIssueLabels(ctx context.Context, workspaceID int,
 	return r.GetGitHubIssueLabels(ctx, workspace, repository)
 }
 
+// GithubProjectRepository is the resolver for the github_project_repository field.
+func (r *queryResolver) GithubProjectRepository(ctx context.Context, projectID int) (*string, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := githubissues.ProjectRepository(ctx, r.DB, project.ID)
+	if e.Is(err, githubissues.ErrNoRepository) {
+		return nil, nil
+	} else if err != nil {
+		return nil, e.Wrap(err, "error querying GitHub repository")
+	}
+	return &repository, nil
+}
+
 // Project is the resolver for the project field.
 func (r *queryResolver) Project(ctx context.Context, id int) (*model.Project, error) {
 	project, err := r.isAdminInProjectOrDemoProject(ctx, id)
@@ -6799,11 +8910,261 @@ func (r *queryResolver) ProjectSettings(ctx context.Context, projectID int) (*mo
 			LogExclusionQuery:      projectFilterSettings.LogExclusionQuery,
 			TraceExclusionQuery:    projectFilterSettings.TraceExclusionQuery,
 		},
+		ExcludedServiceNames: append([]string{}, projectFilterSettings.ExcludedServiceNames...),
+		ExcludedLogLevels:    append([]string{}, projectFilterSettings.ExcludedLogLevels...),
+		ErrorPayload:         errorPayloadSettings(projectFilterSettings),
+		ErrorsFromSpanStatus: projectFilterSettings.ErrorsFromSpanStatus,
 	}
 
 	return &allProjectSettings, nil
 }
 
+// SampledOutCounts is the resolver for the sampled_out_counts field.
+func (r *queryResolver) SampledOutCounts(ctx context.Context, projectID int, days *int) ([]*modelInputs.SampledOutCount, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	numDays := 30
+	if days != nil {
+		if *days <= 0 || *days > maxSampledOutDays {
+			return nil, e.Errorf("days must be between 1 and %d", maxSampledOutDays)
+		}
+		numDays = *days
+	}
+	return r.getSampledOutCounts(ctx, project.ID, numDays)
+}
+
+// IngestFilterRules is the resolver for the ingest_filter_rules field.
+func (r *queryResolver) IngestFilterRules(ctx context.Context, projectID int) ([]*model.IngestFilterRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.Store.GetIngestFilterRules(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying ingest filter rules")
+	}
+	return rules, nil
+}
+
+// ErrorWorkflowRules is the resolver for the error_workflow_rules field.
+func (r *queryResolver) ErrorWorkflowRules(ctx context.Context, projectID int) ([]*model.ErrorWorkflowRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.Store.GetErrorWorkflowRules(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error workflow rules")
+	}
+	return rules, nil
+}
+
+// ErrorWorkflowRuleActivity is the resolver for the error_workflow_rule_activity field.
+func (r *queryResolver) ErrorWorkflowRuleActivity(ctx context.Context, projectID int) ([]*modelInputs.ErrorWorkflowRuleActivity, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := r.Store.GetErrorWorkflowRuleActivity(ctx, project.ID, 1000)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error workflow rule activity")
+	}
+	return newErrorWorkflowRuleActivity(logs), nil
+}
+
+// ErrorOwnershipRules is the resolver for the error_ownership_rules field.
+func (r *queryResolver) ErrorOwnershipRules(ctx context.Context, projectID int) ([]*model.ErrorOwnershipRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.Store.GetErrorOwnershipRules(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error ownership rules")
+	}
+	return rules, nil
+}
+
+// ErrorIgnoreRules is the resolver for the error_ignore_rules field.
+func (r *queryResolver) ErrorIgnoreRules(ctx context.Context, projectID int) ([]*model.ErrorIgnoreRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.Store.GetErrorIgnoreRules(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error ignore rules")
+	}
+	return rules, nil
+}
+
+// ErrorGroupingRules is the resolver for the error_grouping_rules field.
+func (r *queryResolver) ErrorGroupingRules(ctx context.Context, projectID int) ([]*model.ErrorGroupingRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.Store.GetErrorGroupingRules(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error grouping rules")
+	}
+	return rules, nil
+}
+
+// ProjectSdks is the resolver for the project_sdks field.
+func (r *queryResolver) ProjectSdks(ctx context.Context, projectID int) ([]*model.ProjectSDK, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sdks, err := r.Store.GetProjectSDKs(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying project sdks")
+	}
+	return sdks, nil
+}
+
+// RedactionRules is the resolver for the redaction_rules field.
+func (r *queryResolver) RedactionRules(ctx context.Context, projectID int) (*modelInputs.RedactionRules, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.Store.GetProjectFilterSettings(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying redaction rules")
+	}
+	return redactionRules(settings), nil
+}
+
+// DatadogLogForwarder is the resolver for the datadog_log_forwarder field.
+func (r *queryResolver) DatadogLogForwarder(ctx context.Context, projectID int) (*model.DatadogLogForwarder, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var forwarder model.DatadogLogForwarder
+	if err := r.DB.WithContext(ctx).Where(&model.DatadogLogForwarder{ProjectID: project.ID}).Take(&forwarder).Error; err != nil {
+		if e.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, e.Wrap(err, "error querying datadog log forwarder")
+	}
+	return &forwarder, nil
+}
+
+// SourcemapBucket is the resolver for the sourcemap_bucket field.
+func (r *queryResolver) SourcemapBucket(ctx context.Context, projectID int) (*model.SourcemapBucket, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, err := r.Store.GetSourcemapBucket(ctx, project.ID)
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, e.Wrap(err, "error querying sourcemap bucket")
+	}
+	return bucket, nil
+}
+
+// ProductAnalyticsExports is the resolver for the product_analytics_exports field.
+func (r *queryResolver) ProductAnalyticsExports(ctx context.Context, projectID int) ([]*model.ProductAnalyticsExport, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := []*model.ProductAnalyticsExport{}
+	if err := r.DB.WithContext(ctx).Where(&model.ProductAnalyticsExport{ProjectID: project.ID}).Order("destination").Find(&exports).Error; err != nil {
+		return nil, e.Wrap(err, "error querying product analytics exports")
+	}
+	return exports, nil
+}
+
+// WarehouseExport is the resolver for the warehouse_export field.
+func (r *queryResolver) WarehouseExport(ctx context.Context, projectID int) (*model.WarehouseExport, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var export model.WarehouseExport
+	if err := r.DB.WithContext(ctx).Where(&model.WarehouseExport{ProjectID: project.ID}).Take(&export).Error; err != nil {
+		if e.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, e.Wrap(err, "error querying warehouse export")
+	}
+	return &export, nil
+}
+
+// DigestSetting is the resolver for the digest_setting field.
+func (r *queryResolver) DigestSetting(ctx context.Context, projectID int) (*model.ProjectDigestSetting, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	setting, err := r.Store.GetProjectDigestSetting(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying digest setting")
+	}
+	// projects whose schedule was not changed get the weekly digest
+	if setting == nil {
+		setting = &model.ProjectDigestSetting{ProjectID: project.ID, Frequency: model.DigestFrequencyWeekly, Weekday: time.Monday}
+	}
+	return setting, nil
+}
+
+// WebhookSettings is the resolver for the webhook_settings field.
+func (r *queryResolver) WebhookSettings(ctx context.Context, projectID int) (*modelInputs.WebhookSettings, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.Store.GetProjectFilterSettings(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying project filter settings")
+	}
+	return webhookSettings(settings), nil
+}
+
+// WebhookDeliveries is the resolver for the webhook_deliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, projectID int, before *int, eventType *string, limit *int) ([]*model.WebhookDelivery, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := store.ListWebhookDeliveriesParams{Before: before, EventType: eventType, Limit: store.LIMIT}
+	if limit != nil {
+		if *limit <= 0 || *limit > maxWebhookDeliveriesLimit {
+			return nil, e.Errorf("limit must be between 1 and %d", maxWebhookDeliveriesLimit)
+		}
+		params.Limit = *limit
+	}
+	deliveries, err := r.Store.ListWebhookDeliveries(ctx, project.ID, params)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying webhook deliveries")
+	}
+	return deliveries, nil
+}
+
 // Workspace is the resolver for the workspace field.
 func (r *queryResolver) Workspace(ctx context.Context, id int) (*model.Workspace, error) {
 	workspace, err := r.isAdminInWorkspace(ctx, id)
@@ -6916,17 +9277,88 @@ func (r *queryResolver) WorkspacePendingInvites(ctx context.Context, workspaceID
 		return nil, e.Wrap(queryErr, "error getting invite links for the workspace")
 	}
 
-	return pendingInvites, nil
+	return pendingInvites, nil
+}
+
+// WorkspaceSettings is the resolver for the workspaceSettings field.
+func (r *queryResolver) WorkspaceSettings(ctx context.Context, workspaceID int) (*model.AllWorkspaceSettings, error) {
+	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Store.GetAllWorkspaceSettings(ctx, workspaceID)
+}
+
+// APITokens is the resolver for the api_tokens field.
+func (r *queryResolver) APITokens(ctx context.Context, workspaceID *int) ([]*model.APIToken, error) {
+	query := r.DB.WithContext(ctx).Where("revoked_at IS NULL")
+	if workspaceID != nil {
+		if _, err := r.isAdminInWorkspace(ctx, *workspaceID); err != nil {
+			return nil, err
+		}
+		query = query.Where(&model.APIToken{WorkspaceID: workspaceID})
+	} else {
+		admin, err := r.getCurrentAdmin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(&model.APIToken{AdminID: admin.ID}).Where("workspace_id IS NULL")
+	}
+
+	apiTokens := []*model.APIToken{}
+	if err := query.Order("created_at DESC").Find(&apiTokens).Error; err != nil {
+		return nil, e.Wrap(err, "error querying api tokens")
+	}
+	return apiTokens, nil
+}
+
+// APITokenScopes is the resolver for the api_token_scopes field.
+func (r *queryResolver) APITokenScopes(ctx context.Context) ([]string, error) {
+	return lo.Map(apitoken.Scopes, func(scope apitoken.Scope, _ int) string {
+		return string(scope)
+	}), nil
 }
 
-// WorkspaceSettings is the resolver for the workspaceSettings field.
-func (r *queryResolver) WorkspaceSettings(ctx context.Context, workspaceID int) (*model.AllWorkspaceSettings, error) {
-	_, err := r.isAdminInWorkspace(ctx, workspaceID)
+// WorkspaceRoles is the resolver for the workspace_roles field.
+func (r *queryResolver) WorkspaceRoles(ctx context.Context, workspaceID int) (*modelInputs.WorkspaceRoles, error) {
+	currentRole, err := r.getCurrentWorkspaceRole(ctx, workspaceID)
 	if err != nil {
+		return nil, AuthorizationError
+	}
+	var customRoles []*model.WorkspaceRole
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceRole{WorkspaceID: workspaceID}).Order("name ASC").Find(&customRoles).Error; err != nil {
+		return nil, e.Wrap(err, "error querying workspace roles")
+	}
+
+	var roles []*modelInputs.Role
+	for _, role := range rbac.BuiltInRoles() {
+		roles = append(roles, roleOutput(role, nil))
+	}
+	for _, role := range customRoles {
+		roles = append(roles, workspaceRoleOutput(role))
+	}
+	return &modelInputs.WorkspaceRoles{
+		Roles:       roles,
+		Permissions: permissionStrings(rbac.Permissions),
+		CurrentRole: roleOutput(currentRole, nil),
+	}, nil
+}
+
+// WorkspaceSsoConfig is the resolver for the workspace_sso_config field.
+func (r *queryResolver) WorkspaceSsoConfig(ctx context.Context, workspaceID int) (*modelInputs.SSOConfig, error) {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageWorkspace); err != nil {
 		return nil, err
 	}
 
-	return r.Store.GetAllWorkspaceSettings(ctx, workspaceID)
+	config, err := r.getSSOConfig(ctx, workspaceID)
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, e.Wrap(err, "error querying sso config")
+	}
+	return ssoConfigOutput(config), nil
 }
 
 // WorkspaceForProject is the resolver for the workspace_for_project field.
@@ -7150,8 +9582,8 @@ func (r *queryResolver) CustomerPortalURL(ctx context.Context, workspaceID int)
 		return "", e.Wrap(err, "admin does not have workspace access")
 	}
 
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
-		return "", e.Wrap(err, "must have ADMIN role to access the Stripe customer portal")
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageBilling); err != nil {
+		return "", e.Wrap(err, "must have manage billing permission to access the Stripe customer portal")
 	}
 
 	returnUrl := fmt.Sprintf("%s/w/%d/current-plan", frontendUri, workspaceID)
@@ -7179,7 +9611,7 @@ func (r *queryResolver) SubscriptionDetails(ctx context.Context, workspaceID int
 		return nil, e.New("workspace has no stripe customer ID")
 	}
 
-	if err := r.validateAdminRole(ctx, workspaceID); err != nil {
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionManageBilling); err != nil {
 		return nil, err
 	}
 
@@ -7415,6 +9847,153 @@ func (r *queryResolver) MetricMonitors(ctx context.Context, projectID int, metri
 	return metricMonitors, nil
 }
 
+// UptimeMonitors is the resolver for the uptime_monitors field.
+func (r *queryResolver) UptimeMonitors(ctx context.Context, projectID int) ([]*model.UptimeMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	monitors, err := r.Store.GetUptimeMonitors(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying uptime monitors")
+	}
+	return monitors, nil
+}
+
+// UptimeChecks is the resolver for the uptime_checks field.
+func (r *queryResolver) UptimeChecks(ctx context.Context, projectID int, monitorID int, startDate *time.Time, endDate *time.Time) (*modelInputs.UptimeChecks, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Store.GetUptimeMonitor(ctx, project.ID, monitorID); err != nil {
+		return nil, e.Wrap(err, "error querying uptime monitor")
+	}
+
+	end := time.Now()
+	if endDate != nil {
+		end = *endDate
+	}
+	start := end.Add(-24 * time.Hour)
+	if startDate != nil {
+		start = *startDate
+	}
+
+	checks, err := r.ClickhouseClient.ReadUptimeChecks(ctx, project.ID, monitorID, start, end)
+	if err != nil {
+		return nil, e.Wrap(err, "error reading uptime checks")
+	}
+	// each downtime window is correlated with the backend error volume of the project
+	windows, err := r.ClickhouseClient.ReadUptimeDowntimeWindows(ctx, project.ID, monitorID, start, end)
+	if err != nil {
+		return nil, e.Wrap(err, "error reading uptime downtime windows")
+	}
+	return newUptimeChecks(checks, windows), nil
+}
+
+// HeartbeatMonitors is the resolver for the heartbeat_monitors field.
+func (r *queryResolver) HeartbeatMonitors(ctx context.Context, projectID int) ([]*model.HeartbeatMonitor, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	monitors, err := r.Store.GetHeartbeatMonitors(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying heartbeat monitors")
+	}
+	return monitors, nil
+}
+
+// HeartbeatCheckIns is the resolver for the heartbeat_check_ins field.
+func (r *queryResolver) HeartbeatCheckIns(ctx context.Context, projectID int, monitorID int, startDate *time.Time, endDate *time.Time) ([]*modelInputs.HeartbeatCheckIn, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Store.GetHeartbeatMonitor(ctx, project.ID, monitorID); err != nil {
+		return nil, e.Wrap(err, "error querying heartbeat monitor")
+	}
+
+	end := time.Now()
+	if endDate != nil {
+		end = *endDate
+	}
+	start := end.Add(-7 * 24 * time.Hour)
+	if startDate != nil {
+		start = *startDate
+	}
+
+	checkIns, err := r.ClickhouseClient.ReadHeartbeatCheckIns(ctx, project.ID, monitorID, start, end)
+	if err != nil {
+		return nil, e.Wrap(err, "error reading heartbeat check ins")
+	}
+	return lo.Map(checkIns, func(checkIn *clickhouse.HeartbeatCheckInRow, _ int) *modelInputs.HeartbeatCheckIn {
+		return &modelInputs.HeartbeatCheckIn{
+			Timestamp:  checkIn.Timestamp,
+			Status:     checkIn.Status,
+			DurationMs: int(checkIn.DurationMs),
+		}
+	}), nil
+}
+
+// TraceAlerts is the resolver for the trace_alerts field.
+func (r *queryResolver) TraceAlerts(ctx context.Context, projectID int) ([]*model.TraceAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	traceAlerts, err := r.Store.GetTraceAlerts(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying trace alerts")
+	}
+	return traceAlerts, nil
+}
+
+// EscalationPolicies is the resolver for the escalation_policies field.
+func (r *queryResolver) EscalationPolicies(ctx context.Context, projectID int) ([]*model.EscalationPolicy, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := r.Store.GetEscalationPolicies(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying escalation policies")
+	}
+	return policies, nil
+}
+
+// OnCallSchedules is the resolver for the on_call_schedules field.
+func (r *queryResolver) OnCallSchedules(ctx context.Context, projectID int) ([]*model.OnCallSchedule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := r.Store.GetOnCallSchedules(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying on-call schedules")
+	}
+	return schedules, nil
+}
+
+// AlertEscalations is the resolver for the alert_escalations field.
+func (r *queryResolver) AlertEscalations(ctx context.Context, projectID int) ([]*model.AlertEscalation, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	escalations, err := r.Store.GetAlertEscalations(ctx, project.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying alert escalations")
+	}
+	return escalations, nil
+}
+
 // EventChunkURL is the resolver for the event_chunk_url field.
 func (r *queryResolver) EventChunkURL(ctx context.Context, secureID string, index int) (string, error) {
 	session, err := r.canAdminViewSession(ctx, secureID)
@@ -7575,6 +10154,26 @@ func (r *queryResolver) Logs(ctx context.Context, projectID int, params modelInp
 	})
 }
 
+// LogsByTrace is the resolver for the logs_by_trace field.
+func (r *queryResolver) LogsByTrace(ctx context.Context, projectID int, traceID string, dateRange *modelInputs.DateRangeRequiredInput, after *string, before *string) (*modelInputs.LogConnection, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the logs of the last week are read by default
+	if dateRange == nil {
+		dateRange = &modelInputs.DateRangeRequiredInput{
+			StartDate: time.Now().AddDate(0, 0, -7),
+			EndDate:   time.Now(),
+		}
+	}
+	return r.ClickhouseClient.ReadLogsByTrace(ctx, project.ID, traceID, *dateRange, clickhouse.Pagination{
+		After:  after,
+		Before: before,
+	})
+}
+
 // SessionLogs is the resolver for the sessionLogs field.
 func (r *queryResolver) SessionLogs(ctx context.Context, projectID int, params modelInputs.QueryInput) ([]*modelInputs.LogEdge, error) {
 	project, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
@@ -7907,6 +10506,63 @@ func (r *queryResolver) TracesKeyValues(ctx context.Context, projectID int, keyN
 	return r.ClickhouseClient.TracesKeyValues(ctx, project.ID, keyName, dateRange.StartDate, dateRange.EndDate)
 }
 
+// WebVitalPercentiles is the resolver for the web_vital_percentiles field.
+func (r *queryResolver) WebVitalPercentiles(ctx context.Context, projectID int, dateRange *modelInputs.DateRangeRequiredInput, name *string, url *string, groupByURL *bool) ([]*modelInputs.WebVitalPercentiles, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	params, err := newWebVitalsParams(dateRange, name, url, groupByURL)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.ClickhouseClient.QueryWebVitalPercentiles(ctx, project.ID, params)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying web vitals")
+	}
+	return webVitalPercentiles(results), nil
+}
+
+// ServiceGraph is the resolver for the service_graph field.
+func (r *queryResolver) ServiceGraph(ctx context.Context, projectID int, dateRange *modelInputs.DateRangeRequiredInput, environment *string) (*modelInputs.ServiceGraph, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	params, err := newServiceGraphParams(dateRange, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := r.ClickhouseClient.ReadServiceGraph(ctx, project.ID, params)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying service graph")
+	}
+	return serviceGraph(graph), nil
+}
+
+// Profiles is the resolver for the profiles field.
+func (r *queryResolver) Profiles(ctx context.Context, projectID int, serviceName string, dateRange *modelInputs.DateRangeRequiredInput) ([]*modelInputs.Profile, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if serviceName == "" {
+		return nil, e.New("service_name is required")
+	}
+	startDate, endDate, err := newProfilesRange(dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.StorageClient.ListProfiles(ctx, project.ID, serviceName, startDate, endDate)
+	if err != nil {
+		return nil, e.Wrap(err, "error listing profiles")
+	}
+	return profiles(results), nil
+}
+
 // ErrorsKeys is the resolver for the errors_keys field.
 func (r *queryResolver) ErrorsKeys(ctx context.Context, projectID int, dateRange modelInputs.DateRangeRequiredInput, query *string, typeArg *modelInputs.KeyType) ([]*modelInputs.QueryKey, error) {
 	_, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
@@ -8091,6 +10747,16 @@ func (r *sessionAlertResolver) DiscordChannelsToNotify(ctx context.Context, obj
 	return ret, nil
 }
 
+// DiscordWebhooksToNotify is the resolver for the DiscordWebhooksToNotify field.
+func (r *sessionAlertResolver) DiscordWebhooksToNotify(ctx context.Context, obj *model.SessionAlert) ([]*model.DiscordWebhook, error) {
+	return obj.DiscordWebhooksToNotify, nil
+}
+
+// MicrosoftTeamsChannelsToNotify is the resolver for the MicrosoftTeamsChannelsToNotify field.
+func (r *sessionAlertResolver) MicrosoftTeamsChannelsToNotify(ctx context.Context, obj *model.SessionAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannelsToNotify, nil
+}
+
 // WebhookDestinations is the resolver for the WebhookDestinations field.
 func (r *sessionAlertResolver) WebhookDestinations(ctx context.Context, obj *model.SessionAlert) ([]*model.WebhookDestination, error) {
 	return obj.WebhookDestinations, nil
@@ -8218,6 +10884,16 @@ GROUP BY
 	return tagsResponse, nil
 }
 
+// ServiceAccountKeySet is the resolver for the service_account_key_set field.
+func (r *sourcemapBucketResolver) ServiceAccountKeySet(ctx context.Context, obj *model.SourcemapBucket) (bool, error) {
+	return obj.ServiceAccountKey != nil && *obj.ServiceAccountKey != "", nil
+}
+
+// APIKeySet is the resolver for the api_key_set field.
+func (r *splunkOnCallDestinationResolver) APIKeySet(ctx context.Context, obj *model.SplunkOnCallDestination) (bool, error) {
+	return obj.APIKey != "", nil
+}
+
 // SessionPayloadAppended is the resolver for the session_payload_appended field.
 func (r *subscriptionResolver) SessionPayloadAppended(ctx context.Context, sessionSecureID string, initialEventsCount int) (<-chan *model.SessionPayload, error) {
 	ch := make(chan *model.SessionPayload)
@@ -8266,14 +10942,143 @@ func (r *subscriptionResolver) SessionPayloadAppended(ctx context.Context, sessi
 	return ch, nil
 }
 
+// ErrorObjectCreated is the resolver for the error_object_created field.
+func (r *subscriptionResolver) ErrorObjectCreated(ctx context.Context, projectID int) (<-chan *model.ErrorObject, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return redis.Subscribe[model.ErrorObject](ctx, r.Redis, redis.LiveErrorsChannel(projectID))
+}
+
+// AlertStateChanged is the resolver for the alert_state_changed field.
+func (r *subscriptionResolver) AlertStateChanged(ctx context.Context, projectID int) (<-chan *modelInputs.AlertStateChange, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	changes, err := redis.Subscribe[redis.AlertStateChange](ctx, r.Redis, redis.AlertStateChannel(projectID))
+	if err != nil {
+		return nil, e.Wrap(err, "error subscribing to alert state changes")
+	}
+
+	ch := make(chan *modelInputs.AlertStateChange)
+	go func() {
+		defer close(ch)
+		for change := range changes {
+			select {
+			case ch <- &modelInputs.AlertStateChange{
+				ProjectID: change.ProjectID,
+				AlertID:   change.AlertID,
+				AlertType: change.AlertType,
+				Title:     change.Title,
+				State:     string(change.State),
+				Timestamp: change.Timestamp,
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// LiveSessionCount is the resolver for the live_session_count field.
+func (r *subscriptionResolver) LiveSessionCount(ctx context.Context, projectID int) (<-chan *int, error) {
+	if _, err := r.isAdminInProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *int)
+	r.SubscriptionWorkerPool.SubmitRecover(func() {
+		defer close(ch)
+		last := -1
+		for {
+			// Use context.Background() here as the original ctx may be
+			// cancelled before the subscription ends, which cancels the redis query.
+			count, err := r.Redis.GetLiveSessionCount(context.Background(), projectID)
+			if err != nil {
+				log.WithContext(ctx).Error(e.Wrap(err, "error fetching live session count"))
+			} else if count != last {
+				last = count
+				select {
+				case ch <- &count:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	})
+	return ch, nil
+}
+
 // Data is the resolver for the data field.
 func (r *timelineIndicatorEventResolver) Data(ctx context.Context, obj *model.TimelineIndicatorEvent) (interface{}, error) {
 	return obj.Data, nil
 }
 
+// Metric is the resolver for the metric field.
+func (r *traceAlertResolver) Metric(ctx context.Context, obj *model.TraceAlert) (string, error) {
+	return obj.Metric, nil
+}
+
+// State is the resolver for the state field.
+func (r *traceAlertResolver) State(ctx context.Context, obj *model.TraceAlert) (string, error) {
+	return obj.State, nil
+}
+
+// ChannelsToNotify is the resolver for the ChannelsToNotify field.
+func (r *traceAlertResolver) ChannelsToNotify(ctx context.Context, obj *model.TraceAlert) ([]*modelInputs.SanitizedSlackChannel, error) {
+	return obj.GetChannelsToNotify()
+}
+
+// DiscordChannelsToNotify is the resolver for the DiscordChannelsToNotify field.
+func (r *traceAlertResolver) DiscordChannelsToNotify(ctx context.Context, obj *model.TraceAlert) ([]*model.DiscordChannel, error) {
+	return obj.DiscordChannelsToNotify, nil
+}
+
+// WebhookDestinations is the resolver for the WebhookDestinations field.
+func (r *traceAlertResolver) WebhookDestinations(ctx context.Context, obj *model.TraceAlert) ([]*model.WebhookDestination, error) {
+	return obj.WebhookDestinations, nil
+}
+
+// Type is the resolver for the type field.
+func (r *uptimeMonitorResolver) Type(ctx context.Context, obj *model.UptimeMonitor) (string, error) {
+	return obj.Type, nil
+}
+
+// StorageSecretAccessKeySet is the resolver for the storage_secret_access_key_set field.
+func (r *warehouseExportResolver) StorageSecretAccessKeySet(ctx context.Context, obj *model.WarehouseExport) (bool, error) {
+	return obj.StorageSecretAccessKey != "", nil
+}
+
+// BigqueryCredentialsSet is the resolver for the bigquery_credentials_set field.
+func (r *warehouseExportResolver) BigqueryCredentialsSet(ctx context.Context, obj *model.WarehouseExport) (bool, error) {
+	return obj.BigQueryCredentials != "", nil
+}
+
+// SnowflakePrivateKeySet is the resolver for the snowflake_private_key_set field.
+func (r *warehouseExportResolver) SnowflakePrivateKeySet(ctx context.Context, obj *model.WarehouseExport) (bool, error) {
+	return obj.SnowflakePrivateKey != "", nil
+}
+
+// CurrentSchemaVersion is the resolver for the current_schema_version field.
+func (r *warehouseExportResolver) CurrentSchemaVersion(ctx context.Context, obj *model.WarehouseExport) (int, error) {
+	return warehouse.SchemaVersion, nil
+}
+
 // CommentReply returns generated.CommentReplyResolver implementation.
 func (r *Resolver) CommentReply() generated.CommentReplyResolver { return &commentReplyResolver{r} }
 
+// DatadogLogForwarder returns generated.DatadogLogForwarderResolver implementation.
+func (r *Resolver) DatadogLogForwarder() generated.DatadogLogForwarderResolver {
+	return &datadogLogForwarderResolver{r}
+}
+
 // ErrorAlert returns generated.ErrorAlertResolver implementation.
 func (r *Resolver) ErrorAlert() generated.ErrorAlertResolver { return &errorAlertResolver{r} }
 
@@ -8289,6 +11094,21 @@ func (r *Resolver) ErrorObject() generated.ErrorObjectResolver { return &errorOb
 // ErrorSegment returns generated.ErrorSegmentResolver implementation.
 func (r *Resolver) ErrorSegment() generated.ErrorSegmentResolver { return &errorSegmentResolver{r} }
 
+// EscalationPolicy returns generated.EscalationPolicyResolver implementation.
+func (r *Resolver) EscalationPolicy() generated.EscalationPolicyResolver {
+	return &escalationPolicyResolver{r}
+}
+
+// EscalationStep returns generated.EscalationStepResolver implementation.
+func (r *Resolver) EscalationStep() generated.EscalationStepResolver {
+	return &escalationStepResolver{r}
+}
+
+// HeartbeatMonitor returns generated.HeartbeatMonitorResolver implementation.
+func (r *Resolver) HeartbeatMonitor() generated.HeartbeatMonitorResolver {
+	return &heartbeatMonitorResolver{r}
+}
+
 // LogAlert returns generated.LogAlertResolver implementation.
 func (r *Resolver) LogAlert() generated.LogAlertResolver { return &logAlertResolver{r} }
 
@@ -8303,6 +11123,29 @@ func (r *Resolver) MetricMonitor() generated.MetricMonitorResolver { return &met
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// OnCallSchedule returns generated.OnCallScheduleResolver implementation.
+func (r *Resolver) OnCallSchedule() generated.OnCallScheduleResolver {
+	return &onCallScheduleResolver{r}
+}
+
+// OpsgenieDestination returns generated.OpsgenieDestinationResolver implementation.
+func (r *Resolver) OpsgenieDestination() generated.OpsgenieDestinationResolver {
+	return &opsgenieDestinationResolver{r}
+}
+
+// ProductAnalyticsExport returns generated.ProductAnalyticsExportResolver implementation.
+func (r *Resolver) ProductAnalyticsExport() generated.ProductAnalyticsExportResolver {
+	return &productAnalyticsExportResolver{r}
+}
+
+// Project returns generated.ProjectResolver implementation.
+func (r *Resolver) Project() generated.ProjectResolver { return &projectResolver{r} }
+
+// ProjectDigestSetting returns generated.ProjectDigestSettingResolver implementation.
+func (r *Resolver) ProjectDigestSetting() generated.ProjectDigestSettingResolver {
+	return &projectDigestSettingResolver{r}
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
@@ -8326,6 +11169,16 @@ func (r *Resolver) SessionComment() generated.SessionCommentResolver {
 	return &sessionCommentResolver{r}
 }
 
+// SourcemapBucket returns generated.SourcemapBucketResolver implementation.
+func (r *Resolver) SourcemapBucket() generated.SourcemapBucketResolver {
+	return &sourcemapBucketResolver{r}
+}
+
+// SplunkOnCallDestination returns generated.SplunkOnCallDestinationResolver implementation.
+func (r *Resolver) SplunkOnCallDestination() generated.SplunkOnCallDestinationResolver {
+	return &splunkOnCallDestinationResolver{r}
+}
+
 // Subscription returns generated.SubscriptionResolver implementation.
 func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
 
@@ -8334,16 +11187,36 @@ func (r *Resolver) TimelineIndicatorEvent() generated.TimelineIndicatorEventReso
 	return &timelineIndicatorEventResolver{r}
 }
 
+// TraceAlert returns generated.TraceAlertResolver implementation.
+func (r *Resolver) TraceAlert() generated.TraceAlertResolver { return &traceAlertResolver{r} }
+
+// UptimeMonitor returns generated.UptimeMonitorResolver implementation.
+func (r *Resolver) UptimeMonitor() generated.UptimeMonitorResolver { return &uptimeMonitorResolver{r} }
+
+// WarehouseExport returns generated.WarehouseExportResolver implementation.
+func (r *Resolver) WarehouseExport() generated.WarehouseExportResolver {
+	return &warehouseExportResolver{r}
+}
+
 type commentReplyResolver struct{ *Resolver }
+type datadogLogForwarderResolver struct{ *Resolver }
 type errorAlertResolver struct{ *Resolver }
 type errorCommentResolver struct{ *Resolver }
 type errorGroupResolver struct{ *Resolver }
 type errorObjectResolver struct{ *Resolver }
 type errorSegmentResolver struct{ *Resolver }
+type escalationPolicyResolver struct{ *Resolver }
+type escalationStepResolver struct{ *Resolver }
+type heartbeatMonitorResolver struct{ *Resolver }
 type logAlertResolver struct{ *Resolver }
 type matchedErrorObjectResolver struct{ *Resolver }
 type metricMonitorResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type onCallScheduleResolver struct{ *Resolver }
+type opsgenieDestinationResolver struct{ *Resolver }
+type productAnalyticsExportResolver struct{ *Resolver }
+type projectResolver struct{ *Resolver }
+type projectDigestSettingResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type savedSegmentResolver struct{ *Resolver }
 type segmentResolver struct{ *Resolver }
@@ -8351,5 +11224,10 @@ type serviceResolver struct{ *Resolver }
 type sessionResolver struct{ *Resolver }
 type sessionAlertResolver struct{ *Resolver }
 type sessionCommentResolver struct{ *Resolver }
+type sourcemapBucketResolver struct{ *Resolver }
+type splunkOnCallDestinationResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
 type timelineIndicatorEventResolver struct{ *Resolver }
+type traceAlertResolver struct{ *Resolver }
+type uptimeMonitorResolver struct{ *Resolver }
+type warehouseExportResolver struct{ *Resolver }
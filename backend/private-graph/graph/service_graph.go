@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// newServiceGraphParams returns the params of a service graph query, defaulting to the last hour.
+func newServiceGraphParams(dateRange *modelInputs.DateRangeRequiredInput, environment *string) (clickhouse.ServiceGraphParams, error) {
+	params := clickhouse.ServiceGraphParams{EndDate: time.Now()}
+	params.StartDate = params.EndDate.Add(-time.Hour)
+	if dateRange != nil {
+		params.StartDate = dateRange.StartDate
+		params.EndDate = dateRange.EndDate
+	}
+	if !params.StartDate.Before(params.EndDate) {
+		return params, e.New("start_date must be before end_date")
+	}
+	if environment != nil && *environment != "" {
+		params.Environment = environment
+	}
+	return params, nil
+}
+
+func serviceGraph(graph *clickhouse.ServiceGraph) *modelInputs.ServiceGraph {
+	return &modelInputs.ServiceGraph{
+		Nodes: lo.Map(graph.Nodes, func(node *clickhouse.ServiceGraphNode, _ int) *modelInputs.ServiceGraphNode {
+			result := modelInputs.ServiceGraphNode(*node)
+			return &result
+		}),
+		Edges: lo.Map(graph.Edges, func(edge *clickhouse.ServiceGraphEdge, _ int) *modelInputs.ServiceGraphEdge {
+			result := modelInputs.ServiceGraphEdge(*edge)
+			return &result
+		}),
+	}
+}
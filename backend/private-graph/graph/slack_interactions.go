@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/issuetracker"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/rbac"
+	"github.com/highlight-run/highlight/backend/store"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+const slackSnoozeDuration = 24 * time.Hour
+
+// SlackInteractionsWebhook handles the buttons of Slack error alerts, updating the state of the error
+// group or creating an issue for it as the Highlight admin with the email of the Slack user that clicked
+// the button. Slack expects a response within 3 seconds, so the action is performed in the background and
+// its result posted to the response url of the interaction.
+func (r *Resolver) SlackInteractionsWebhook(ctx context.Context, signingSecret string) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, ok := verifySlackRequest(ctx, w, req, signingSecret)
+		if !ok {
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error parsing slack interaction form"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var callback slack.InteractionCallback
+		if err := json.Unmarshal([]byte(form.Get("payload")), &callback); err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error parsing slack interaction payload"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+		if callback.Type != slack.InteractionTypeBlockActions {
+			return
+		}
+		for _, action := range callback.ActionCallback.BlockActions {
+			action := action
+			r.PrivateWorkerPool.SubmitRecover(func() {
+				ctx := context.Background()
+				reply, err := r.handleSlackErrorGroupAction(ctx, &callback, action)
+				message := &slack.WebhookMessage{Text: reply, ResponseType: "in_channel"}
+				if err != nil {
+					log.WithContext(ctx).WithField("action_id", action.ActionID).Error(e.Wrap(err, "error handling slack interaction"))
+					message = &slack.WebhookMessage{Text: fmt.Sprintf("Couldn't complete the action: %s", err), ResponseType: "ephemeral"}
+				}
+				if message.Text == "" || callback.ResponseURL == "" {
+					return
+				}
+				if err := slack.PostWebhookContext(ctx, callback.ResponseURL, message); err != nil {
+					log.WithContext(ctx).Error(e.Wrap(err, "error responding to slack interaction"))
+				}
+			})
+		}
+	}
+}
+
+// handleSlackErrorGroupAction performs the action of an error alert button, returning the message
+// to reply with. Actions of other messages are ignored.
+func (r *Resolver) handleSlackErrorGroupAction(ctx context.Context, callback *slack.InteractionCallback, action *slack.BlockAction) (string, error) {
+	if !lo.Contains([]string{
+		model.SlackActionResolveErrorGroup,
+		model.SlackActionIgnoreErrorGroup,
+		model.SlackActionSnoozeErrorGroup,
+		model.SlackActionCreateErrorTicket,
+	}, action.ActionID) {
+		return "", nil
+	}
+
+	projectID, secureID, err := model.ParseSlackErrorGroupActionValue(action.Value)
+	if err != nil {
+		return "", err
+	}
+	var project model.Project
+	if err := r.DB.WithContext(ctx).Where(&model.Project{Model: model.Model{ID: projectID}}).Take(&project).Error; err != nil {
+		return "", e.Wrap(err, "error querying project")
+	}
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return "", err
+	}
+	admin, err := r.getSlackInteractionAdmin(ctx, workspace, callback)
+	if err != nil {
+		return "", err
+	}
+	ctx, err = r.authorizeSlackInteractionAdmin(ctx, workspace.ID, admin)
+	if err != nil {
+		return "", err
+	}
+	errorGroup, err := r.getProjectErrorGroup(ctx, project.ID, secureID)
+	if err != nil {
+		return "", e.Wrap(err, "error querying error group")
+	}
+
+	errorURL := fmt.Sprintf("%s/%d/errors/%s", os.Getenv("REACT_APP_FRONTEND_URI"), project.ID, errorGroup.SecureID)
+	params := store.UpdateErrorGroupParams{ID: errorGroup.ID}
+	var verb string
+	switch action.ActionID {
+	case model.SlackActionResolveErrorGroup:
+		params.State = modelInputs.ErrorStateResolved
+		verb = "resolved"
+	case model.SlackActionIgnoreErrorGroup:
+		params.State = modelInputs.ErrorStateIgnored
+		verb = "ignored"
+	case model.SlackActionSnoozeErrorGroup:
+		params.State = modelInputs.ErrorStateOpen
+		params.SnoozedUntil = lo.ToPtr(time.Now().Add(slackSnoozeDuration))
+		verb = "snoozed for 24 hours"
+	case model.SlackActionCreateErrorTicket:
+		return r.createSlackErrorTicket(ctx, workspace, errorGroup, admin, callback.User.ID, errorURL)
+	}
+
+	if _, err := r.Store.UpdateErrorGroupStateByAdmin(ctx, *admin, params); err != nil {
+		return "", e.Wrap(err, "error updating error group state")
+	}
+	return fmt.Sprintf("<@%s> %s <%s|%s>.", callback.User.ID, verb, errorURL, errorGroupTitle(errorGroup)), nil
+}
+
+// getSlackInteractionAdmin returns the admin of the workspace with the email of the Slack user of an
+// interaction, after checking that the interaction comes from the Slack team the workspace is connected to.
+func (r *Resolver) getSlackInteractionAdmin(ctx context.Context, workspace *model.Workspace, callback *slack.InteractionCallback) (*model.Admin, error) {
+	if workspace.SlackAccessToken == nil || *workspace.SlackAccessToken == "" {
+		return nil, e.New("the workspace is not connected to Slack")
+	}
+	slackClient := slack.New(*workspace.SlackAccessToken)
+
+	teamInfo, err := slackClient.GetTeamInfoContext(ctx)
+	if err != nil {
+		return nil, e.Wrap(err, "couldn't get slack team information")
+	}
+	if teamInfo.ID != callback.Team.ID {
+		return nil, e.New("this Slack workspace is not connected to the Highlight workspace of the error")
+	}
+
+	user, err := slackClient.GetUserInfoContext(ctx, callback.User.ID)
+	if err != nil {
+		return nil, e.Wrap(err, "couldn't get slack user information")
+	}
+	if user.Profile.Email == "" {
+		return nil, e.New("your Slack profile does not have an email")
+	}
+
+	var admin model.Admin
+	if err := r.DB.WithContext(ctx).Where("lower(email) = ?", strings.ToLower(user.Profile.Email)).Take(&admin).Error; err != nil {
+		return nil, e.Errorf("no Highlight user has the email %s", user.Profile.Email)
+	}
+	if _, err := r.GetAdminRole(ctx, admin.ID, workspace.ID); err != nil {
+		return nil, e.Errorf("%s is not a member of the Highlight workspace", user.Profile.Email)
+	}
+	return &admin, nil
+}
+
+// authorizeSlackInteractionAdmin returns the context of the admin of a Slack interaction, checking that
+// their role in the workspace can change the state of its error groups.
+func (r *Resolver) authorizeSlackInteractionAdmin(ctx context.Context, workspaceID int, admin *model.Admin) (context.Context, error) {
+	if admin.UID == nil {
+		return nil, e.New("the Highlight user has not signed in")
+	}
+	ctx = context.WithValue(ctx, model.ContextKeys.UID, *admin.UID)
+	if err := r.authorizeWorkspace(ctx, workspaceID, rbac.PermissionEdit); err != nil {
+		return nil, e.New("your role in the Highlight workspace can't update errors")
+	}
+	return ctx, nil
+}
+
+// createSlackErrorTicket creates an issue for an error group in the first issue tracker with a
+// project mapped to the error group's project.
+func (r *Resolver) createSlackErrorTicket(ctx context.Context, workspace *model.Workspace, errorGroup *model.ErrorGroup, admin *model.Admin, slackUserID string, errorURL string) (string, error) {
+	var mappings []*model.IntegrationProjectMapping
+	if err := r.DB.WithContext(ctx).Where(&model.IntegrationProjectMapping{ProjectID: errorGroup.ProjectID}).Find(&mappings).Error; err != nil {
+		return "", e.Wrap(err, "error querying integration project mappings")
+	}
+	mapping, ok := lo.Find(mappings, func(m *model.IntegrationProjectMapping) bool {
+		return issuetracker.Supported(m.IntegrationType) && m.ExternalID != ""
+	})
+	if !ok {
+		return "", e.Errorf("the project does not have a default issue tracker project, create the ticket from <%s|Highlight>", errorURL)
+	}
+
+	attachment := &model.ExternalAttachment{IntegrationType: mapping.IntegrationType}
+	issue, claimed, err := r.claimErrorIssue(ctx, errorGroup.ProjectID, errorGroup.ID, &admin.ID, attachment)
+	if err != nil {
+		return "", e.Wrap(err, "error claiming external issue")
+	}
+	if !claimed {
+		return fmt.Sprintf("<%s|%s> already has a %s ticket: %s", errorURL, errorGroupTitle(errorGroup), mapping.IntegrationType, issue.Title), nil
+	}
+	defer r.completeErrorIssue(ctx, issue, attachment)
+
+	title, desc := r.Store.BuildIssueTitleAndDescription(errorGroup.Event, nil)
+	desc += "See the error page on Highlight:\n" + errorURL
+	if err := r.CreateTrackerIssueAndAttachment(ctx, workspace, attachment, title, desc, errorURL, &mapping.ExternalID, nil); err != nil {
+		return "", e.Wrap(err, "error creating ticket")
+	}
+	return fmt.Sprintf("<@%s> created a %s ticket for <%s|%s>: %s", slackUserID, mapping.IntegrationType, errorURL, errorGroupTitle(errorGroup), attachment.Title), nil
+}
+
+// errorGroupTitle is the first line of the event of an error group, truncated for messages.
+func errorGroupTitle(errorGroup *model.ErrorGroup) string {
+	title, _, _ := strings.Cut(errorGroup.Event, "\n")
+	if len(title) > 100 {
+		title = title[:100] + "..."
+	}
+	return title
+}
@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+)
+
+// applySourcemapBucketInput replaces the sourcemap bucket of a project. The service account key is
+// not returned, so the key of a bucket is kept when it is not set.
+func applySourcemapBucketInput(input modelInputs.SourcemapBucketInput, bucket *model.SourcemapBucket) error {
+	bucket.Provider = model.SourcemapBucketProvider(input.Provider)
+	bucket.Bucket = input.Bucket
+	bucket.Prefix = ptr.ToString(input.Prefix)
+	bucket.Region = input.Region
+	bucket.RoleARN = input.RoleArn
+	if input.ServiceAccountKey != nil {
+		bucket.ServiceAccountKey = input.ServiceAccountKey
+	}
+	bucket.Disabled = input.Disabled != nil && *input.Disabled
+	return bucket.Validate()
+}
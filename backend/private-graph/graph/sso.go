@@ -0,0 +1,923 @@
+package graph
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/sso"
+	"github.com/lib/pq"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ssoConfigOutput includes the urls that the app of the identity provider is set up with.
+func ssoConfigOutput(config *model.WorkspaceSSOConfig) *modelInputs.SSOConfig {
+	groupRoles := []*modelInputs.SSOGroupRole{}
+	for group, role := range config.GroupRoles {
+		groupRoles = append(groupRoles, &modelInputs.SSOGroupRole{Group: group, Role: role})
+	}
+	sort.Slice(groupRoles, func(i, j int) bool {
+		return groupRoles[i].Group < groupRoles[j].Group
+	})
+	return &modelInputs.SSOConfig{
+		WorkspaceID:                   config.WorkspaceID,
+		Provider:                      config.Provider,
+		Protocol:                      config.Protocol,
+		Domain:                        config.Domain,
+		DomainVerified:                config.DomainVerifiedAt != nil,
+		DomainVerificationRecordName:  sso.DomainVerificationRecordName(config.Domain),
+		DomainVerificationRecordValue: sso.DomainVerificationRecordValue(config.DomainVerificationToken),
+		SamlIdpEntityID:               config.SAMLIdPEntityID,
+		SamlIdpSsoURL:                 config.SAMLIdPSSOURL,
+		SamlIdpCertificate:            config.SAMLIdPCertificate,
+		OidcIssuer:                    config.OIDCIssuer,
+		OidcClientID:                  config.OIDCClientID,
+		OidcClientSecretSet:           config.OIDCClientSecret != "",
+		GroupRoles:                    groupRoles,
+		DefaultRole:                   config.DefaultRole,
+		ScimTokenSet:                  config.SCIMToken != "",
+		Enabled:                       config.Enabled,
+		LoginURL:                      ssoURL(config.WorkspaceID, "login"),
+		SamlEntityID:                  ssoURL(config.WorkspaceID, "saml/metadata"),
+		SamlAcsURL:                    ssoURL(config.WorkspaceID, "saml/acs"),
+		OidcRedirectURL:               ssoURL(config.WorkspaceID, "oidc/callback"),
+		ScimBaseURL:                   fmt.Sprintf("%s/scim/v2/%d", os.Getenv("REACT_APP_PRIVATE_GRAPH_URI"), config.WorkspaceID),
+	}
+}
+
+// ssoTXTResolver looks up the TXT records that verify the domains of sso configs.
+var ssoTXTResolver sso.TXTResolver = net.DefaultResolver
+
+func ssoURL(workspaceID int, path string) string {
+	return fmt.Sprintf("%s/sso/%d/%s", os.Getenv("REACT_APP_PRIVATE_GRAPH_URI"), workspaceID, path)
+}
+
+func samlServiceProvider(config *model.WorkspaceSSOConfig) (*sso.SAMLServiceProvider, error) {
+	cert, err := sso.ParseCertificate(config.SAMLIdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+	return &sso.SAMLServiceProvider{
+		EntityID:       ssoURL(config.WorkspaceID, "saml/metadata"),
+		ACSURL:         ssoURL(config.WorkspaceID, "saml/acs"),
+		IdPEntityID:    config.SAMLIdPEntityID,
+		IdPSSOURL:      config.SAMLIdPSSOURL,
+		IdPCertificate: cert,
+	}, nil
+}
+
+func oidcProvider(ctx context.Context, config *model.WorkspaceSSOConfig) (*sso.OIDCProvider, error) {
+	return sso.NewOIDCProvider(ctx, config.OIDCIssuer, config.OIDCClientID, config.OIDCClientSecret, ssoURL(config.WorkspaceID, "oidc/callback"))
+}
+
+func (r *Resolver) getSSOConfig(ctx context.Context, workspaceID int) (*model.WorkspaceSSOConfig, error) {
+	var config model.WorkspaceSSOConfig
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceSSOConfig{WorkspaceID: workspaceID}).Take(&config).Error; err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// getEnabledSSOConfig returns the enabled sso config of the workspace in the url, writing an error
+// response when the workspace has none.
+func (r *Resolver) getEnabledSSOConfig(w http.ResponseWriter, req *http.Request) (*model.WorkspaceSSOConfig, bool) {
+	ctx := req.Context()
+	workspaceID, err := strconv.Atoi(chi.URLParam(req, workspaceIdUrlParam))
+	if err != nil {
+		http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+		return nil, false
+	}
+	config, err := r.getSSOConfig(ctx, workspaceID)
+	if err != nil && !e.Is(err, gorm.ErrRecordNotFound) {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying sso config"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return nil, false
+	}
+	if config == nil || !config.Enabled || config.DomainVerifiedAt == nil {
+		http.Error(w, "single sign-on is not enabled for this workspace", http.StatusNotFound)
+		return nil, false
+	}
+	return config, true
+}
+
+// ssoStateValue binds the SAML request id or OIDC nonce of a login to its workspace.
+func ssoStateValue(workspaceID int, value string) string {
+	return fmt.Sprintf("%d:%s", workspaceID, value)
+}
+
+// SSODiscoveryHandler redirects to the login of the workspace that the email domain of the user
+// signs in with.
+func (r *Resolver) SSODiscoveryHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	domain := sso.EmailDomain(req.URL.Query().Get("email"))
+	if domain == "" {
+		http.Error(w, "invalid email", http.StatusBadRequest)
+		return
+	}
+	var config model.WorkspaceSSOConfig
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceSSOConfig{Domain: domain, Enabled: true}).
+		Where("domain_verified_at IS NOT NULL").Take(&config).Error; err != nil {
+		if e.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "single sign-on is not enabled for this email domain", http.StatusNotFound)
+			return
+		}
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying sso config by domain"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, req, ssoURL(config.WorkspaceID, "login"), http.StatusFound)
+}
+
+// SSOLoginHandler starts a login with the identity provider of the workspace.
+func (r *Resolver) SSOLoginHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	config, ok := r.getEnabledSSOConfig(w, req)
+	if !ok {
+		return
+	}
+
+	stateBytes, err := GenerateRandomBytes(20)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error generating sso state"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	var redirectURL string
+	switch sso.Protocol(config.Protocol) {
+	case sso.ProtocolSAML:
+		sp, err := samlServiceProvider(config)
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "invalid saml config"))
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+		requestID, err := sso.NewRequestID()
+		if err == nil {
+			err = r.Redis.SetSSOState(ctx, state, ssoStateValue(config.WorkspaceID, requestID))
+		}
+		if err == nil {
+			redirectURL, err = sp.AuthnRequestURL(requestID, state, time.Now())
+		}
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error starting saml login"))
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+	case sso.ProtocolOIDC:
+		provider, err := oidcProvider(ctx, config)
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error discovering oidc provider"))
+			http.Error(w, "identity provider is unavailable", http.StatusBadGateway)
+			return
+		}
+		nonce, err := sso.NewNonce()
+		if err == nil {
+			err = r.Redis.SetSSOState(ctx, state, ssoStateValue(config.WorkspaceID, nonce))
+		}
+		if err != nil {
+			log.WithContext(ctx).Error(e.Wrap(err, "error starting oidc login"))
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+		redirectURL = provider.AuthCodeURL(state, nonce)
+	default:
+		http.Error(w, "invalid sso protocol", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, req, redirectURL, http.StatusFound)
+}
+
+// consumeSSOState returns the SAML request id or OIDC nonce of a login that was started for the
+// workspace. A state can only be used once.
+func (r *Resolver) consumeSSOState(w http.ResponseWriter, req *http.Request, workspaceID int, state string) (string, bool) {
+	ctx := req.Context()
+	if state == "" {
+		http.Error(w, "missing sso state", http.StatusBadRequest)
+		return "", false
+	}
+	value, err := r.Redis.GetDelSSOState(ctx, state)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return "", false
+	}
+	prefix := ssoStateValue(workspaceID, "")
+	if !strings.HasPrefix(value, prefix) {
+		http.Error(w, "sso login expired, please sign in again", http.StatusBadRequest)
+		return "", false
+	}
+	return strings.TrimPrefix(value, prefix), true
+}
+
+// SAMLMetadataHandler returns the metadata of the service provider that the SAML app of the
+// identity provider is set up with.
+func (r *Resolver) SAMLMetadataHandler(w http.ResponseWriter, req *http.Request) {
+	workspaceID, err := strconv.Atoi(chi.URLParam(req, workspaceIdUrlParam))
+	if err != nil {
+		http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+		return
+	}
+	sp := &sso.SAMLServiceProvider{
+		EntityID: ssoURL(workspaceID, "saml/metadata"),
+		ACSURL:   ssoURL(workspaceID, "saml/acs"),
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	if _, err := w.Write(sp.Metadata()); err != nil {
+		log.WithContext(req.Context()).Error(e.Wrap(err, "error writing saml metadata"))
+	}
+}
+
+// SAMLACSHandler completes a SAML login with the response that the identity provider posts.
+func (r *Resolver) SAMLACSHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	config, ok := r.getEnabledSSOConfig(w, req)
+	if !ok {
+		return
+	}
+	if sso.Protocol(config.Protocol) != sso.ProtocolSAML {
+		http.Error(w, "workspace does not sign in with saml", http.StatusBadRequest)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, "invalid saml response", http.StatusBadRequest)
+		return
+	}
+	// logins started by the identity provider have no state, and are rejected
+	requestID, ok := r.consumeSSOState(w, req, config.WorkspaceID, req.PostForm.Get("RelayState"))
+	if !ok {
+		return
+	}
+
+	sp, err := samlServiceProvider(config)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "invalid saml config"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	identity, err := sp.ParseResponse(req.PostForm.Get("SAMLResponse"), requestID, time.Now())
+	if err != nil {
+		log.WithContext(ctx).WithField("workspace_id", config.WorkspaceID).Warn(e.Wrap(err, "invalid saml response"))
+		http.Error(w, "invalid saml response", http.StatusForbidden)
+		return
+	}
+	r.completeSSOLogin(w, req, config, identity)
+}
+
+// OIDCCallbackHandler completes an OIDC login with the code that the identity provider redirects with.
+func (r *Resolver) OIDCCallbackHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	config, ok := r.getEnabledSSOConfig(w, req)
+	if !ok {
+		return
+	}
+	if sso.Protocol(config.Protocol) != sso.ProtocolOIDC {
+		http.Error(w, "workspace does not sign in with oidc", http.StatusBadRequest)
+		return
+	}
+	query := req.URL.Query()
+	nonce, ok := r.consumeSSOState(w, req, config.WorkspaceID, query.Get("state"))
+	if !ok {
+		return
+	}
+	if errorCode := query.Get("error"); errorCode != "" {
+		http.Error(w, fmt.Sprintf("identity provider error %s: %s", errorCode, query.Get("error_description")), http.StatusForbidden)
+		return
+	}
+
+	provider, err := oidcProvider(ctx, config)
+	if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error discovering oidc provider"))
+		http.Error(w, "identity provider is unavailable", http.StatusBadGateway)
+		return
+	}
+	identity, err := provider.Exchange(ctx, query.Get("code"), nonce)
+	if err != nil {
+		log.WithContext(ctx).WithField("workspace_id", config.WorkspaceID).Warn(e.Wrap(err, "invalid oidc login"))
+		http.Error(w, "invalid oidc login", http.StatusForbidden)
+		return
+	}
+	r.completeSSOLogin(w, req, config, identity)
+}
+
+// completeSSOLogin signs in the user authenticated by the identity provider of the workspace,
+// adding them to the workspace with the role of their groups, and redirects to the frontend with
+// the token of the auth mode.
+func (r *Resolver) completeSSOLogin(w http.ResponseWriter, req *http.Request, config *model.WorkspaceSSOConfig, identity *sso.Identity) {
+	ctx := req.Context()
+	// the identity provider of a workspace is only trusted for the email domain of the workspace
+	if sso.EmailDomain(identity.Email) != config.Domain {
+		http.Error(w, fmt.Sprintf("%s is not an email of %s", identity.Email, config.Domain), http.StatusForbidden)
+		return
+	}
+
+	// only accounts that the workspace provisioned or that are members of it can be signed in to,
+	// so that the identity provider of a workspace cannot take over other accounts with its domain
+	var existingUID *string
+	existing, err := r.getSSOAdminByEmail(ctx, identity.Email)
+	if err == nil {
+		var managed bool
+		if managed, err = r.isWorkspaceSSOAdmin(ctx, config.WorkspaceID, existing.ID); err == nil && !managed {
+			http.Error(w, "an account with this email already exists and is not a member of this workspace", http.StatusForbidden)
+			return
+		}
+		existingUID = existing.UID
+	} else if e.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	uid, token, err := AuthClient.createSSOToken(ctx, existingUID, identity.Email, identity.Name())
+	if e.Is(err, errSSOAccountExists) {
+		http.Error(w, "an account with this email already exists and is not a member of this workspace", http.StatusForbidden)
+		return
+	} else if err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error creating sso token"))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	admin, err := r.getOrCreateSSOAdmin(ctx, identity.Email, identity.FirstName, identity.LastName, &uid)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	role := sso.RoleForGroups(config.GroupRoles, identity.Groups, config.DefaultRole)
+	if role != "" {
+		err = r.upsertWorkspaceAdminRole(ctx, config.WorkspaceID, admin.ID, role)
+	} else {
+		// users without a mapped group must have been provisioned over scim
+		_, err = r.GetAdminRole(ctx, admin.ID, config.WorkspaceID)
+		if err != nil {
+			http.Error(w, "you are not assigned to this workspace, ask your identity provider admin for access", http.StatusForbidden)
+			return
+		}
+	}
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	// the token is passed in the fragment so that it is not sent to servers or logged
+	fragment := url.Values{"token": {token}, "workspace_id": {strconv.Itoa(config.WorkspaceID)}}
+	http.Redirect(w, req, fmt.Sprintf("%s/sso/callback#%s", FrontendURI, fragment.Encode()), http.StatusFound)
+}
+
+// getOrCreateSSOAdmin returns the admin with the email, creating it when there is none. Admins
+// provisioned over scim have no uid until they first sign in, and an admin that signed in with
+// another uid is not replaced by a second admin of the same email.
+func (r *Resolver) getOrCreateSSOAdmin(ctx context.Context, email string, firstName string, lastName string, uid *string) (*model.Admin, error) {
+	db := r.DB.WithContext(ctx)
+	existing, err := r.getSSOAdminByEmail(ctx, email)
+	if err == nil {
+		admin := existing
+		if uid == nil {
+			return admin, nil
+		}
+		if admin.UID != nil && *admin.UID != *uid {
+			return nil, e.Errorf("admin %d with email %s signs in with another uid", admin.ID, email)
+		}
+		if admin.UID == nil {
+			if err := db.Model(admin).Updates(&model.Admin{UID: uid, EmailVerified: &model.T}).Error; err != nil {
+				return nil, e.Wrap(err, "error setting uid of provisioned admin")
+			}
+		}
+		return admin, nil
+	} else if !e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(firstName + " " + lastName)
+	admin := model.Admin{
+		UID:                   uid,
+		Email:                 &email,
+		Name:                  &name,
+		FirstName:             &firstName,
+		LastName:              &lastName,
+		EmailVerified:         &model.T,
+		AboutYouDetailsFilled: &model.F,
+	}
+	if err := db.Create(&admin).Error; err != nil {
+		return nil, e.Wrap(err, "error creating sso admin")
+	}
+	return &admin, nil
+}
+
+// getSSOAdminByEmail returns the first admin with the email, in any case.
+func (r *Resolver) getSSOAdminByEmail(ctx context.Context, email string) (*model.Admin, error) {
+	var admin model.Admin
+	if err := r.DB.WithContext(ctx).Where("lower(email) = ?", strings.ToLower(email)).Order("id ASC").Take(&admin).Error; err != nil {
+		if e.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, e.Wrap(err, "error querying sso admin by email")
+	}
+	return &admin, nil
+}
+
+// isWorkspaceSSOAdmin is whether the admin was provisioned over scim by the workspace and is active,
+// or is already a member of the workspace.
+func (r *Resolver) isWorkspaceSSOAdmin(ctx context.Context, workspaceID int, adminID int) (bool, error) {
+	db := r.DB.WithContext(ctx)
+	var count int64
+	if err := db.Model(&model.WorkspaceSCIMUser{}).
+		Where(&model.WorkspaceSCIMUser{WorkspaceID: workspaceID, AdminID: adminID, Active: true}).Count(&count).Error; err != nil {
+		return false, e.Wrap(err, "error querying scim user of admin")
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := db.Model(&model.WorkspaceAdmin{}).
+		Where(&model.WorkspaceAdmin{WorkspaceID: workspaceID, AdminID: adminID}).Count(&count).Error; err != nil {
+		return false, e.Wrap(err, "error querying workspace admin")
+	}
+	return count > 0, nil
+}
+
+func (r *Resolver) upsertWorkspaceAdminRole(ctx context.Context, workspaceID int, adminID int, role string) error {
+	if err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		OnConstraint: "workspace_admins_pkey",
+		DoUpdates:    clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(&model.WorkspaceAdmin{
+		AdminID:     adminID,
+		WorkspaceID: workspaceID,
+		Role:        &role,
+	}).Error; err != nil {
+		return e.Wrap(err, "error upserting workspace admin role")
+	}
+	return nil
+}
+
+func (r *Resolver) deleteWorkspaceAdmin(ctx context.Context, workspaceID int, adminID int) error {
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceAdmin{AdminID: adminID, WorkspaceID: workspaceID}).Delete(&model.WorkspaceAdmin{}).Error; err != nil {
+		return e.Wrap(err, "error deleting workspace admin")
+	}
+	return nil
+}
+
+// checkSSODomainUnclaimed returns an error when the domain was verified by another workspace, as a
+// domain can only sign in with one workspace. Unverified configs of a domain do not claim it.
+func (r *Resolver) checkSSODomainUnclaimed(ctx context.Context, workspaceID int, domain string) error {
+	var claimed int64
+	if err := r.DB.WithContext(ctx).Model(&model.WorkspaceSSOConfig{}).
+		Where("domain = ? AND workspace_id <> ? AND domain_verified_at IS NOT NULL", domain, workspaceID).Count(&claimed).Error; err != nil {
+		return e.Wrap(err, "error querying sso configs of domain")
+	}
+	if claimed > 0 {
+		return e.New("domain signs in with another workspace")
+	}
+	return nil
+}
+
+// verifySSODomain checks the TXT record of the domain of the config, which must not have been
+// verified by another workspace in the meantime.
+func (r *Resolver) verifySSODomain(ctx context.Context, config *model.WorkspaceSSOConfig) error {
+	if err := r.checkSSODomainUnclaimed(ctx, config.WorkspaceID, config.Domain); err != nil {
+		return err
+	}
+	if err := sso.VerifyDomain(ctx, ssoTXTResolver, config.Domain, config.DomainVerificationToken); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := r.DB.WithContext(ctx).Model(config).Update("domain_verified_at", now).Error; err != nil {
+		return e.Wrap(err, "error saving sso domain verification")
+	}
+	config.DomainVerifiedAt = &now
+	return nil
+}
+
+// applySSOConfigInput validates an sso config input and copies it to the config. The OIDC client
+// secret is only required when the config is created or it is replaced.
+func applySSOConfigInput(ctx context.Context, input modelInputs.SSOConfigInput, config *model.WorkspaceSSOConfig) error {
+	if !sso.Provider(input.Provider).IsValid() {
+		return e.New("invalid sso provider " + input.Provider)
+	}
+	if !sso.Protocol(input.Protocol).IsValid() {
+		return e.New("invalid sso protocol " + input.Protocol)
+	}
+	groupRoles := model.StringMap{}
+	for _, groupRole := range input.GroupRoles {
+		if !sso.IsValidRole(groupRole.Role) {
+			return e.Errorf("invalid role %s of group %s", groupRole.Role, groupRole.Group)
+		}
+		groupRoles[groupRole.Group] = groupRole.Role
+	}
+	defaultRole := ptr.ToString(input.DefaultRole)
+	if defaultRole != "" && !sso.IsValidRole(defaultRole) {
+		return e.New("invalid default role " + defaultRole)
+	}
+
+	// a new domain must be verified again before single sign-on is enabled
+	domain := strings.ToLower(strings.TrimSpace(input.Domain))
+	if domain == "" {
+		return e.New("domain is required")
+	}
+	if domain != config.Domain || config.DomainVerificationToken == "" {
+		token, err := sso.NewDomainVerificationToken()
+		if err != nil {
+			return e.Wrap(err, "error generating domain verification token")
+		}
+		config.DomainVerificationToken = token
+		config.DomainVerifiedAt = nil
+	}
+	if input.Enabled && config.DomainVerifiedAt == nil {
+		return e.Errorf("add the TXT record %s to verify %s before enabling single sign-on", sso.DomainVerificationRecordName(domain), domain)
+	}
+
+	if input.OidcClientSecret != nil {
+		config.OIDCClientSecret = strings.TrimSpace(*input.OidcClientSecret)
+	}
+	config.Provider = input.Provider
+	config.Protocol = input.Protocol
+	config.Domain = domain
+	config.SAMLIdPEntityID = strings.TrimSpace(ptr.ToString(input.SamlIdpEntityID))
+	config.SAMLIdPSSOURL = strings.TrimSpace(ptr.ToString(input.SamlIdpSsoURL))
+	config.SAMLIdPCertificate = strings.TrimSpace(ptr.ToString(input.SamlIdpCertificate))
+	config.OIDCIssuer = strings.TrimSpace(ptr.ToString(input.OidcIssuer))
+	config.OIDCClientID = strings.TrimSpace(ptr.ToString(input.OidcClientID))
+	config.GroupRoles = groupRoles
+	config.DefaultRole = defaultRole
+	config.Enabled = input.Enabled
+
+	switch sso.Protocol(config.Protocol) {
+	case sso.ProtocolSAML:
+		if config.SAMLIdPEntityID == "" || config.SAMLIdPSSOURL == "" {
+			return e.New("saml entity id and sso url are required")
+		}
+		if _, err := samlServiceProvider(config); err != nil {
+			return err
+		}
+	case sso.ProtocolOIDC:
+		if config.OIDCClientID == "" || config.OIDCClientSecret == "" {
+			return e.New("oidc client id and secret are required")
+		}
+		if _, err := oidcProvider(ctx, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSCIMResponse(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	w.Header().Set("Content-Type", sso.SCIMContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.WithContext(req.Context()).WithError(err).Error("failed to write scim response")
+	}
+}
+
+func writeSCIMError(w http.ResponseWriter, req *http.Request, status int, scimType string, detail string) {
+	writeSCIMResponse(w, req, status, sso.NewSCIMError(status, scimType, detail))
+}
+
+// authorizeSCIMRequest returns the sso config of the workspace in the url when the request has its
+// scim bearer token.
+func (r *Resolver) authorizeSCIMRequest(w http.ResponseWriter, req *http.Request) (*model.WorkspaceSSOConfig, bool) {
+	ctx := req.Context()
+	workspaceID, err := strconv.Atoi(chi.URLParam(req, workspaceIdUrlParam))
+	if err != nil {
+		writeSCIMError(w, req, http.StatusNotFound, "", "invalid workspace")
+		return nil, false
+	}
+	config, err := r.getSSOConfig(ctx, workspaceID)
+	if err != nil && !e.Is(err, gorm.ErrRecordNotFound) {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying sso config"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return nil, false
+	}
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	// admins are only provisioned in the verified domain of the workspace
+	if config == nil || config.SCIMToken == "" || config.DomainVerifiedAt == nil || !found || subtle.ConstantTimeCompare([]byte(token), []byte(config.SCIMToken)) != 1 {
+		writeSCIMError(w, req, http.StatusUnauthorized, "", "invalid scim token")
+		return nil, false
+	}
+	return config, true
+}
+
+func scimUserResource(user *model.WorkspaceSCIMUser) *sso.SCIMUser {
+	active := user.Active
+	resource := &sso.SCIMUser{
+		Schemas:    []string{sso.SCIMUserSchema},
+		ID:         strconv.Itoa(user.ID),
+		ExternalID: user.ExternalID,
+		UserName:   user.UserName,
+		Name:       &sso.SCIMName{GivenName: user.GivenName, FamilyName: user.FamilyName},
+		Emails:     []sso.SCIMMultiValue{{Value: user.Email, Type: "work", Primary: true}},
+		Active:     &active,
+		Meta: &sso.SCIMMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     fmt.Sprintf("%s/scim/v2/%d/Users/%d", os.Getenv("REACT_APP_PRIVATE_GRAPH_URI"), user.WorkspaceID, user.ID),
+		},
+	}
+	for _, role := range user.Roles {
+		resource.Roles = append(resource.Roles, sso.SCIMMultiValue{Value: role})
+	}
+	return resource
+}
+
+// setSCIMUserAttributes copies the attributes of a scim resource to a provisioned user.
+func setSCIMUserAttributes(user *model.WorkspaceSCIMUser, resource *sso.SCIMUser) {
+	user.ExternalID = resource.ExternalID
+	user.UserName = resource.UserName
+	user.Email = resource.Email()
+	if resource.Name != nil {
+		user.GivenName = resource.Name.GivenName
+		user.FamilyName = resource.Name.FamilyName
+	}
+	user.Roles = pq.StringArray(resource.RoleNames())
+	user.Active = resource.IsActive()
+}
+
+// syncSCIMUserMembership adds an active user to the workspace with the role of their scim roles,
+// and removes a deactivated user or a user without a mapped role from the workspace.
+func (r *Resolver) syncSCIMUserMembership(ctx context.Context, config *model.WorkspaceSSOConfig, user *model.WorkspaceSCIMUser) error {
+	role := sso.RoleForGroups(config.GroupRoles, user.Roles, config.DefaultRole)
+	if user.Active && role != "" {
+		return r.upsertWorkspaceAdminRole(ctx, config.WorkspaceID, user.AdminID, role)
+	}
+	return r.deleteWorkspaceAdmin(ctx, config.WorkspaceID, user.AdminID)
+}
+
+// SCIMServiceProviderConfigHandler returns the scim features that the workspace supports.
+func (r *Resolver) SCIMServiceProviderConfigHandler(w http.ResponseWriter, req *http.Request) {
+	if _, ok := r.authorizeSCIMRequest(w, req); !ok {
+		return
+	}
+	writeSCIMResponse(w, req, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{sso.SCIMServiceConfigSchema},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": 100},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the scim token of the workspace",
+		}},
+	})
+}
+
+// SCIMUsersHandler lists the provisioned users of the workspace, optionally filtered by user name.
+func (r *Resolver) SCIMUsersHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	config, ok := r.authorizeSCIMRequest(w, req)
+	if !ok {
+		return
+	}
+
+	query := r.DB.WithContext(ctx).Where(&model.WorkspaceSCIMUser{WorkspaceID: config.WorkspaceID})
+	if filter := req.URL.Query().Get("filter"); filter != "" {
+		userName, err := sso.ParseUserFilter(filter)
+		if err != nil {
+			writeSCIMError(w, req, http.StatusBadRequest, "invalidFilter", err.Error())
+			return
+		}
+		query = query.Where("lower(user_name) = ? OR lower(email) = ?", strings.ToLower(userName), strings.ToLower(userName))
+	}
+
+	// startIndex is 1-based
+	startIndex, _ := strconv.Atoi(req.URL.Query().Get("startIndex"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(req.URL.Query().Get("count"))
+	if err != nil || count < 0 || count > 100 {
+		count = 100
+	}
+
+	var total int64
+	if err := query.Model(&model.WorkspaceSCIMUser{}).Count(&total).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error counting scim users"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	var users []*model.WorkspaceSCIMUser
+	if err := query.Order("id ASC").Offset(startIndex - 1).Limit(count).Find(&users).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying scim users"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+
+	resources := make([]*sso.SCIMUser, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, scimUserResource(user))
+	}
+	writeSCIMResponse(w, req, http.StatusOK, sso.SCIMListResponse{
+		Schemas:      []string{sso.SCIMListResponseSchema},
+		TotalResults: int(total),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// CreateSCIMUserHandler provisions a user in the workspace, creating its admin when it does not exist.
+func (r *Resolver) CreateSCIMUserHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	config, ok := r.authorizeSCIMRequest(w, req)
+	if !ok {
+		return
+	}
+
+	var resource sso.SCIMUser
+	if err := json.NewDecoder(req.Body).Decode(&resource); err != nil {
+		writeSCIMError(w, req, http.StatusBadRequest, "invalidSyntax", "invalid user")
+		return
+	}
+	email := resource.Email()
+	if sso.EmailDomain(email) != config.Domain {
+		writeSCIMError(w, req, http.StatusBadRequest, "invalidValue", fmt.Sprintf("email must be an email of %s", config.Domain))
+		return
+	}
+
+	var existing int64
+	if err := r.DB.WithContext(ctx).Model(&model.WorkspaceSCIMUser{}).
+		Where(&model.WorkspaceSCIMUser{WorkspaceID: config.WorkspaceID}).
+		Where("lower(user_name) = ?", strings.ToLower(resource.UserName)).
+		Count(&existing).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying scim users"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	if existing > 0 {
+		writeSCIMError(w, req, http.StatusConflict, "uniqueness", "user already exists")
+		return
+	}
+
+	firstName, lastName := "", ""
+	if resource.Name != nil {
+		firstName, lastName = resource.Name.GivenName, resource.Name.FamilyName
+	}
+	admin, err := r.getOrCreateSSOAdmin(ctx, email, firstName, lastName, nil)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+
+	user := &model.WorkspaceSCIMUser{WorkspaceID: config.WorkspaceID, AdminID: admin.ID}
+	setSCIMUserAttributes(user, &resource)
+	if err := r.DB.WithContext(ctx).Create(user).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error creating scim user"))
+		writeSCIMError(w, req, http.StatusConflict, "uniqueness", "user already exists")
+		return
+	}
+	if err := r.syncSCIMUserMembership(ctx, config, user); err != nil {
+		log.WithContext(ctx).Error(err)
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	writeSCIMResponse(w, req, http.StatusCreated, scimUserResource(user))
+}
+
+// getSCIMUser returns the provisioned user in the url, writing an error response when it is not found.
+func (r *Resolver) getSCIMUser(w http.ResponseWriter, req *http.Request, config *model.WorkspaceSSOConfig) (*model.WorkspaceSCIMUser, bool) {
+	ctx := req.Context()
+	id, err := strconv.Atoi(chi.URLParam(req, "user_id"))
+	if err != nil {
+		writeSCIMError(w, req, http.StatusNotFound, "", "user not found")
+		return nil, false
+	}
+	var user model.WorkspaceSCIMUser
+	if err := r.DB.WithContext(ctx).Where(&model.WorkspaceSCIMUser{Model: model.Model{ID: id}, WorkspaceID: config.WorkspaceID}).Take(&user).Error; err != nil {
+		if e.Is(err, gorm.ErrRecordNotFound) {
+			writeSCIMError(w, req, http.StatusNotFound, "", "user not found")
+			return nil, false
+		}
+		log.WithContext(ctx).Error(e.Wrap(err, "error querying scim user"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return nil, false
+	}
+	return &user, true
+}
+
+// SCIMUserHandler returns a provisioned user of the workspace.
+func (r *Resolver) SCIMUserHandler(w http.ResponseWriter, req *http.Request) {
+	config, ok := r.authorizeSCIMRequest(w, req)
+	if !ok {
+		return
+	}
+	user, ok := r.getSCIMUser(w, req, config)
+	if !ok {
+		return
+	}
+	writeSCIMResponse(w, req, http.StatusOK, scimUserResource(user))
+}
+
+// ReplaceSCIMUserHandler replaces the attributes of a provisioned user, ie. when the identity
+// provider deactivates the user or changes its roles.
+func (r *Resolver) ReplaceSCIMUserHandler(w http.ResponseWriter, req *http.Request) {
+	config, ok := r.authorizeSCIMRequest(w, req)
+	if !ok {
+		return
+	}
+	user, ok := r.getSCIMUser(w, req, config)
+	if !ok {
+		return
+	}
+
+	var resource sso.SCIMUser
+	if err := json.NewDecoder(req.Body).Decode(&resource); err != nil {
+		writeSCIMError(w, req, http.StatusBadRequest, "invalidSyntax", "invalid user")
+		return
+	}
+	r.saveSCIMUser(w, req, config, user, &resource)
+}
+
+// PatchSCIMUserHandler applies a patch to a provisioned user.
+func (r *Resolver) PatchSCIMUserHandler(w http.ResponseWriter, req *http.Request) {
+	config, ok := r.authorizeSCIMRequest(w, req)
+	if !ok {
+		return
+	}
+	user, ok := r.getSCIMUser(w, req, config)
+	if !ok {
+		return
+	}
+
+	var patch sso.SCIMPatchOp
+	if err := json.NewDecoder(req.Body).Decode(&patch); err != nil {
+		writeSCIMError(w, req, http.StatusBadRequest, "invalidSyntax", "invalid patch")
+		return
+	}
+	resource := scimUserResource(user)
+	if err := patch.Apply(resource); err != nil {
+		writeSCIMError(w, req, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+	r.saveSCIMUser(w, req, config, user, resource)
+}
+
+func (r *Resolver) saveSCIMUser(w http.ResponseWriter, req *http.Request, config *model.WorkspaceSSOConfig, user *model.WorkspaceSCIMUser, resource *sso.SCIMUser) {
+	ctx := req.Context()
+	// the admin of a provisioned user does not change, so its email must stay in the domain
+	if sso.EmailDomain(resource.Email()) != config.Domain {
+		writeSCIMError(w, req, http.StatusBadRequest, "invalidValue", fmt.Sprintf("email must be an email of %s", config.Domain))
+		return
+	}
+	setSCIMUserAttributes(user, resource)
+	if err := r.DB.WithContext(ctx).Save(user).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error saving scim user"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	if err := r.syncSCIMUserMembership(ctx, config, user); err != nil {
+		log.WithContext(ctx).Error(err)
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	writeSCIMResponse(w, req, http.StatusOK, scimUserResource(user))
+}
+
+// DeleteSCIMUserHandler deprovisions a user, removing its admin from the workspace.
+func (r *Resolver) DeleteSCIMUserHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	config, ok := r.authorizeSCIMRequest(w, req)
+	if !ok {
+		return
+	}
+	user, ok := r.getSCIMUser(w, req, config)
+	if !ok {
+		return
+	}
+
+	if err := r.deleteWorkspaceAdmin(ctx, config.WorkspaceID, user.AdminID); err != nil {
+		log.WithContext(ctx).Error(err)
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	if err := r.DB.WithContext(ctx).Delete(user).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error deleting scim user"))
+		writeSCIMError(w, req, http.StatusInternalServerError, "", "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
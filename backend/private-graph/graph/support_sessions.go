@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/intercom"
+	"github.com/highlight-run/highlight/backend/integrations/zendesk"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/routing"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+)
+
+// The Intercom and Zendesk integrations show the latest sessions of the user of a conversation or
+// ticket, identified by their email, so that support agents can open the replays from their tool.
+
+// supportSessionsLimit is the number of sessions shown to support agents.
+const supportSessionsLimit = 5
+
+type supportSession struct {
+	SecureID  string
+	Title     string
+	Subtitle  string
+	URL       string
+	CreatedAt time.Time
+}
+
+// getSupportSessions returns the latest sessions of the projects identified with an email, the newest first.
+func (r *Resolver) getSupportSessions(ctx context.Context, projectIDs []int, email string, referrer routing.Referrer) ([]*supportSession, error) {
+	var sessions []*model.Session
+	for _, projectID := range projectIDs {
+		projectSessions, err := r.Store.GetLatestSessionsByEmail(ctx, projectID, email, supportSessionsLimit)
+		if err != nil {
+			return nil, e.Wrap(err, "error querying sessions")
+		}
+		sessions = append(sessions, projectSessions...)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	if len(sessions) > supportSessionsLimit {
+		sessions = sessions[:supportSessionsLimit]
+	}
+
+	results := make([]*supportSession, 0, len(sessions))
+	for _, session := range sessions {
+		url := fmt.Sprintf("%s/%d/sessions/%s", os.Getenv("REACT_APP_FRONTEND_URI"), session.ProjectID, session.SecureID)
+		results = append(results, &supportSession{
+			SecureID:  session.SecureID,
+			Title:     supportSessionTitle(session),
+			Subtitle:  supportSessionSubtitle(session),
+			URL:       routing.AttachReferrer(ctx, url, referrer),
+			CreatedAt: session.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
+// supportSessionTitle is the start time and length of a session, ie. `Jan 2, 15:04 UTC · 3m20s`.
+func supportSessionTitle(session *model.Session) string {
+	title := session.CreatedAt.UTC().Format("Jan 2, 15:04 MST")
+	if session.Length > 0 {
+		title += " · " + (time.Duration(session.Length) * time.Millisecond).Round(time.Second).String()
+	}
+	return title
+}
+
+// supportSessionSubtitle is the browser, os and city of a session, ie. `Chrome on Mac OS X · Berlin`.
+func supportSessionSubtitle(session *model.Session) string {
+	var parts []string
+	if session.BrowserName != "" && session.OSName != "" {
+		parts = append(parts, fmt.Sprintf("%s on %s", session.BrowserName, session.OSName))
+	} else if session.BrowserName != "" || session.OSName != "" {
+		parts = append(parts, session.BrowserName+session.OSName)
+	}
+	if session.City != "" {
+		parts = append(parts, session.City)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (r *Resolver) getIntegrationProjectMappings(ctx context.Context, integrationType modelInputs.IntegrationType, externalID string) ([]*model.IntegrationProjectMapping, error) {
+	var mappings []*model.IntegrationProjectMapping
+	if err := r.DB.WithContext(ctx).Where(&model.IntegrationProjectMapping{IntegrationType: integrationType, ExternalID: externalID}).Find(&mappings).Error; err != nil {
+		return nil, e.Wrap(err, "error querying integration project mappings")
+	}
+	return mappings, nil
+}
+
+func (r *Resolver) setIntegrationProjectMapping(ctx context.Context, project *model.Project, integrationType modelInputs.IntegrationType, externalID string) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "integration_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_id"}),
+	}).Create(&model.IntegrationProjectMapping{
+		IntegrationType: integrationType,
+		ProjectID:       project.ID,
+		ExternalID:      externalID,
+	}).Error
+}
+
+func (r *Resolver) removeIntegrationProjectMapping(ctx context.Context, project *model.Project, integrationType modelInputs.IntegrationType) error {
+	return r.DB.WithContext(ctx).Where(&model.IntegrationProjectMapping{
+		IntegrationType: integrationType,
+		ProjectID:       project.ID,
+	}).Delete(&model.IntegrationProjectMapping{}).Error
+}
+
+func (r *Resolver) isProjectMappedTo(ctx context.Context, project *model.Project, integrationType modelInputs.IntegrationType) (bool, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.IntegrationProjectMapping{}).Where(&model.IntegrationProjectMapping{
+		IntegrationType: integrationType,
+		ProjectID:       project.ID,
+	}).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddIntercomToProject maps the Intercom workspace that installed the Highlight app to the project, so
+// that its conversations show the sessions of the project.
+func (r *Resolver) AddIntercomToProject(ctx context.Context, project *model.Project, code string) error {
+	conf, options, err := intercom.GetOAuthConfig()
+	if err != nil {
+		return err
+	}
+	token, err := conf.Exchange(ctx, code, options...)
+	if err != nil {
+		return e.Wrap(err, "error getting Intercom oauth access token")
+	}
+	workspaceID, err := intercom.GetWorkspaceID(ctx, token.AccessToken)
+	if err != nil {
+		return err
+	}
+	return r.setIntegrationProjectMapping(ctx, project, modelInputs.IntegrationTypeIntercom, workspaceID)
+}
+
+// IntercomCanvasHandler initializes the canvas of the Highlight app in an Intercom conversation with
+// the latest sessions of the conversation's contact, from the projects mapped to the Intercom workspace.
+func (r *Resolver) IntercomCanvasHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "", http.StatusBadRequest)
+		return
+	}
+	if err := intercom.VerifySignature(body, req.Header.Get(intercom.SignatureHeader)); err != nil {
+		log.WithContext(ctx).Warn(err)
+		http.Error(w, "", http.StatusUnauthorized)
+		return
+	}
+
+	var canvasRequest intercom.CanvasRequest
+	if err := json.Unmarshal(body, &canvasRequest); err != nil || canvasRequest.WorkspaceID == "" {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	const header = "Latest Highlight sessions"
+	mappings, err := r.getIntegrationProjectMappings(ctx, modelInputs.IntegrationTypeIntercom, canvasRequest.WorkspaceID)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	if len(mappings) == 0 {
+		writeJSONResponse(w, req, http.StatusOK, intercom.NewListCanvas(header, "Connect Intercom to a project in the Highlight integrations settings to see sessions.", nil))
+		return
+	}
+	email := canvasRequest.Email()
+	if email == "" {
+		writeJSONResponse(w, req, http.StatusOK, intercom.NewListCanvas(header, "The contact does not have an email.", nil))
+		return
+	}
+
+	projectIDs := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		projectIDs = append(projectIDs, mapping.ProjectID)
+	}
+	sessions, err := r.getSupportSessions(ctx, projectIDs, email, routing.Intercom)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	items := make([]*intercom.ListItem, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, &intercom.ListItem{ID: session.SecureID, Title: session.Title, Subtitle: session.Subtitle, URL: session.URL})
+	}
+	writeJSONResponse(w, req, http.StatusOK, intercom.NewListCanvas(header, fmt.Sprintf("No sessions identified as %s.", email), items))
+}
+
+// ZendeskSessionsHandler returns the latest sessions of the requester of a ticket to the Highlight Zendesk app.
+func (r *Resolver) ZendeskSessionsHandler(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	token, err := zendesk.ParseAccessToken(req.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, "", http.StatusUnauthorized)
+		return
+	}
+	var mapping model.IntegrationProjectMapping
+	if err := r.DB.WithContext(ctx).Where(&model.IntegrationProjectMapping{
+		IntegrationType: modelInputs.IntegrationTypeZendesk,
+		ProjectID:       token.ProjectID,
+	}).Take(&mapping).Error; err != nil || mapping.ExternalID != token.Magic {
+		http.Error(w, "", http.StatusUnauthorized)
+		return
+	}
+
+	email := req.URL.Query().Get("email")
+	if email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+	sessions, err := r.getSupportSessions(ctx, []int{token.ProjectID}, email, routing.Zendesk)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	res := zendesk.SessionsResponse{Sessions: make([]*zendesk.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, &zendesk.Session{
+			ID:        session.SecureID,
+			Title:     session.Title,
+			Subtitle:  session.Subtitle,
+			URL:       session.URL,
+			CreatedAt: session.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	writeJSONResponse(w, req, http.StatusOK, res)
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// traceAlertMaxWindowMinutes is the longest window of a trace alert, so that evaluating it every
+// minute stays cheap.
+const traceAlertMaxWindowMinutes = 60
+
+// traceAlertLatencyAggregators are the aggregators that the latency of a trace alert can use.
+var traceAlertLatencyAggregators = []modelInputs.MetricAggregator{
+	modelInputs.MetricAggregatorAvg,
+	modelInputs.MetricAggregatorP50,
+	modelInputs.MetricAggregatorP90,
+	modelInputs.MetricAggregatorP95,
+	modelInputs.MetricAggregatorP99,
+	modelInputs.MetricAggregatorMin,
+	modelInputs.MetricAggregatorMax,
+}
+
+// applyTraceAlertInput sets the condition and the destinations of a trace alert, using the
+// defaults of the model for the omitted settings.
+func (r *Resolver) applyTraceAlertInput(input modelInputs.TraceAlertInput, alert *model.TraceAlert) error {
+	if input.Name == "" {
+		return e.New("name is required")
+	}
+	metric := lo.FromPtrOr(input.Metric, model.TraceAlertMetricLatency)
+	if metric != model.TraceAlertMetricLatency && metric != model.TraceAlertMetricErrorRate {
+		return e.Errorf("invalid metric %s", metric)
+	}
+	aggregator := lo.FromPtrOr(input.Aggregator, modelInputs.MetricAggregatorP95)
+	if !lo.Contains(traceAlertLatencyAggregators, aggregator) {
+		return e.Errorf("invalid aggregator %s", aggregator)
+	}
+	if input.Threshold <= 0 {
+		return e.New("threshold must be positive")
+	}
+	if metric == model.TraceAlertMetricErrorRate && input.Threshold > 100 {
+		return e.New("the threshold of an error rate is a percentage")
+	}
+	windowMinutes := lo.FromPtr(input.WindowMinutes)
+	if windowMinutes <= 0 {
+		windowMinutes = 5
+	}
+	if windowMinutes > traceAlertMaxWindowMinutes {
+		return e.Errorf("window_minutes must be at most %d", traceAlertMaxWindowMinutes)
+	}
+	minSpans := lo.FromPtr(input.MinSpans)
+	if minSpans <= 0 {
+		minSpans = 1
+	}
+	channels, err := r.MarshalSlackChannelsToSanitizedSlackChannels(input.SlackChannels)
+	if err != nil {
+		return err
+	}
+	disabled := lo.FromPtr(input.Disabled)
+
+	alert.Name = input.Name
+	alert.ServiceName = lo.FromPtr(input.ServiceName)
+	alert.SpanName = lo.FromPtr(input.SpanName)
+	alert.Query = lo.FromPtr(input.Query)
+	alert.Metric = metric
+	alert.Aggregator = aggregator
+	alert.Threshold = input.Threshold
+	alert.WindowMinutes = windowMinutes
+	alert.MinSpans = minSpans
+	alert.ChannelsToNotify = channels
+	alert.Disabled = &disabled
+	// the Microsoft Teams channels and Discord webhooks are set through their own endpoints
+	alert.DiscordChannelsToNotify = discord.GQLInputToGo(input.DiscordChannels)
+	alert.WebhookDestinations = webhook.GQLInputToGo(input.WebhookDestinations)
+	// an edited alert starts over, notifying again if its new condition holds
+	alert.State = model.AlertStateNormal
+	return nil
+}
@@ -0,0 +1,16 @@
+package graph
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+)
+
+func getTraceLogsURL(projectID int, traceID string, dateRange modelInputs.DateRangeRequiredInput) string {
+	return fmt.Sprintf("%s/%d/logs?query=%s&start_date=%s&end_date=%s", FrontendURI, projectID,
+		url.QueryEscape(clickhouse.TraceLogsQuery(traceID)),
+		url.QueryEscape(dateRange.StartDate.UTC().Format("2006-01-02T15:04:05.000Z")),
+		url.QueryEscape(dateRange.EndDate.UTC().Format("2006-01-02T15:04:05.000Z")))
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// uptimeMonitorMethods are the methods that http monitors may check with.
+var uptimeMonitorMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// applyUptimeMonitorInput sets the check and the notifications of an uptime monitor, defaulting
+// the settings that are not set.
+func applyUptimeMonitorInput(input modelInputs.UptimeMonitorInput, monitor *model.UptimeMonitor) error {
+	if input.Name == "" || input.URL == "" {
+		return e.New("name and url are required")
+	}
+	monitorType := model.UptimeMonitorTypeHTTP
+	if input.Type != nil && *input.Type != "" {
+		monitorType = *input.Type
+	}
+	if monitorType != model.UptimeMonitorTypeHTTP && monitorType != model.UptimeMonitorTypePing {
+		return e.Errorf("invalid monitor type %s", monitorType)
+	}
+	if monitorType == model.UptimeMonitorTypeHTTP {
+		u, err := url.Parse(input.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return e.New("url must be an http or https url")
+		}
+	}
+	method := http.MethodGet
+	if input.Method != nil && *input.Method != "" {
+		method = strings.ToUpper(*input.Method)
+	}
+	if !lo.Contains(uptimeMonitorMethods, method) {
+		return e.Errorf("invalid method %s", method)
+	}
+	intervalSeconds := 60
+	if input.IntervalSeconds != nil && *input.IntervalSeconds > 0 {
+		intervalSeconds = *input.IntervalSeconds
+	}
+	// a check must time out before the next check of the monitor starts
+	timeoutSeconds := lo.Min([]int{10, intervalSeconds - 1})
+	if input.TimeoutSeconds != nil && *input.TimeoutSeconds > 0 {
+		timeoutSeconds = *input.TimeoutSeconds
+	}
+	if timeoutSeconds < 1 || timeoutSeconds >= intervalSeconds {
+		return e.New("timeout_seconds must be less than interval_seconds")
+	}
+	failureThreshold := 2
+	if input.FailureThreshold != nil && *input.FailureThreshold > 0 {
+		failureThreshold = *input.FailureThreshold
+	}
+	disabled := input.Disabled != nil && *input.Disabled
+
+	monitor.Name = input.Name
+	monitor.Type = monitorType
+	monitor.URL = input.URL
+	monitor.Method = method
+	monitor.IntervalSeconds = intervalSeconds
+	monitor.TimeoutSeconds = timeoutSeconds
+	monitor.ExpectedStatusCode = input.ExpectedStatusCode
+	monitor.BodyContains = input.BodyContains
+	monitor.MaxLatencyMs = input.MaxLatencyMs
+	monitor.FailureThreshold = failureThreshold
+	monitor.ChannelsToNotify = input.ChannelsToNotify
+	monitor.EmailsToNotify = input.EmailsToNotify
+	monitor.Disabled = &disabled
+	return nil
+}
+
+// newUptimeChecks returns the check history of a monitor along with its downtime windows.
+func newUptimeChecks(checks []*clickhouse.UptimeCheckRow, windows []*clickhouse.UptimeDowntimeWindow) *modelInputs.UptimeChecks {
+	result := &modelInputs.UptimeChecks{
+		Checks:          make([]*modelInputs.UptimeCheck, 0, len(checks)),
+		DowntimeWindows: make([]*modelInputs.UptimeDowntimeWindow, 0, len(windows)),
+	}
+	for _, check := range checks {
+		result.Checks = append(result.Checks, &modelInputs.UptimeCheck{
+			Timestamp:     check.Timestamp,
+			Success:       check.Success,
+			StatusCode:    int(check.StatusCode),
+			LatencyMs:     int(check.LatencyMs),
+			FailureReason: check.FailureReason,
+		})
+	}
+	for _, window := range windows {
+		result.DowntimeWindows = append(result.DowntimeWindows, &modelInputs.UptimeDowntimeWindow{
+			StartDate:          window.StartDate,
+			EndDate:            window.EndDate,
+			FailedChecks:       window.FailedChecks,
+			ErrorCount:         window.ErrorCount,
+			BaselineErrorCount: window.BaselineErrorCount,
+		})
+	}
+	return result
+}
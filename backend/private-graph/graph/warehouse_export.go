@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/warehouse"
+	e "github.com/pkg/errors"
+)
+
+// applyWarehouseExportInput configures the daily export of a project to BigQuery or Snowflake.
+// Days are exported from the day the export is enabled.
+func applyWarehouseExportInput(input modelInputs.WarehouseExportInput, export *model.WarehouseExport) error {
+	if !warehouse.Destination(input.Destination).IsValid() {
+		return e.Errorf("invalid warehouse destination %s", input.Destination)
+	}
+	logSampleRate := 1.
+	if input.LogSampleRate != nil {
+		logSampleRate = *input.LogSampleRate
+	}
+	if logSampleRate <= 0 || logSampleRate > 1 {
+		return e.New("log sample rate must be greater than 0 and at most 1")
+	}
+
+	if input.StorageSecretAccessKey != nil {
+		export.StorageSecretAccessKey = strings.TrimSpace(*input.StorageSecretAccessKey)
+	}
+	if input.BigqueryCredentials != nil {
+		export.BigQueryCredentials = strings.TrimSpace(*input.BigqueryCredentials)
+	}
+	if input.SnowflakePrivateKey != nil {
+		export.SnowflakePrivateKey = strings.TrimSpace(*input.SnowflakePrivateKey)
+	}
+	// an export that was disabled starts exporting from when it is enabled again
+	if input.Enabled && !export.Enabled {
+		export.ExportedUntil = nil
+	}
+	export.Destination = input.Destination
+	export.StorageURL = strings.TrimSpace(input.StorageURL)
+	export.StorageAccessKeyID = strings.TrimSpace(input.StorageAccessKeyID)
+	export.BigQueryProjectID = ptr.ToString(input.BigqueryProjectID)
+	export.BigQueryDataset = ptr.ToString(input.BigqueryDataset)
+	export.SnowflakeAccount = ptr.ToString(input.SnowflakeAccount)
+	export.SnowflakeUser = ptr.ToString(input.SnowflakeUser)
+	export.SnowflakeRole = ptr.ToString(input.SnowflakeRole)
+	export.SnowflakeWarehouse = ptr.ToString(input.SnowflakeWarehouse)
+	export.SnowflakeDatabase = ptr.ToString(input.SnowflakeDatabase)
+	export.SnowflakeSchema = ptr.ToString(input.SnowflakeSchema)
+	export.SnowflakeStage = ptr.ToString(input.SnowflakeStage)
+	export.ExportLogs = input.ExportLogs
+	export.LogSampleRate = logSampleRate
+	export.Enabled = input.Enabled
+
+	if export.StorageAccessKeyID == "" || export.StorageSecretAccessKey == "" {
+		return e.New("storage access key is required")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// newWebVitalsParams returns the params of a web vitals query, defaulting to the last week.
+func newWebVitalsParams(dateRange *modelInputs.DateRangeRequiredInput, name *string, url *string, groupByURL *bool) (clickhouse.WebVitalsParams, error) {
+	params := clickhouse.WebVitalsParams{
+		EndDate:    time.Now(),
+		URL:        url,
+		GroupByURL: groupByURL != nil && *groupByURL,
+		Limit:      1000,
+	}
+	params.StartDate = params.EndDate.Add(-7 * 24 * time.Hour)
+	if dateRange != nil {
+		params.StartDate = dateRange.StartDate
+		params.EndDate = dateRange.EndDate
+	}
+	if name != nil {
+		if !clickhouse.IsWebVital(*name) {
+			return params, e.Errorf("invalid web vital %q", *name)
+		}
+		params.Name = name
+	}
+	return params, nil
+}
+
+func webVitalPercentiles(results []*clickhouse.WebVitalPercentiles) []*modelInputs.WebVitalPercentiles {
+	return lo.Map(results, func(result *clickhouse.WebVitalPercentiles, _ int) *modelInputs.WebVitalPercentiles {
+		percentiles := &modelInputs.WebVitalPercentiles{
+			Name:  result.Name,
+			Count: result.Count,
+			P50:   result.P50,
+			P75:   result.P75,
+			P90:   result.P90,
+			P99:   result.P99,
+		}
+		if result.URL != "" {
+			percentiles.URL = &result.URL
+		}
+		return percentiles
+	})
+}
@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"encoding/hex"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+)
+
+const maxWebhookDeliveriesLimit = 100
+
+func webhookSettings(settings *model.ProjectFilterSettings) *modelInputs.WebhookSettings {
+	return &modelInputs.WebhookSettings{
+		MaxRetries:    settings.WebhookMaxRetries,
+		SigningSecret: settings.WebhookSigningSecret,
+	}
+}
+
+// generateWebhookSigningSecret returns a new secret that the payloads of alert webhooks are signed with.
+func generateWebhookSigningSecret() (string, error) {
+	b, err := GenerateRandomBytes(32)
+	if err != nil {
+		return "", e.Wrap(err, "error generating webhook signing secret")
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
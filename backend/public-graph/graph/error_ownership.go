@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/highlight-run/highlight/backend/email"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/store"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+)
+
+// assignErrorGroup assigns an unassigned error group to the owner of the error by the ownership
+// rules of the project, which match the files of its stack frames.
+func (r *Resolver) assignErrorGroup(ctx context.Context, errorObj *model.ErrorObject, errorGroup *model.ErrorGroup, structuredStackTrace []*privateModel.ErrorTrace) {
+	if errorGroup.AssigneeID != nil {
+		return
+	}
+
+	rules, err := r.Store.GetEnabledErrorOwnershipRules(ctx, errorObj.ProjectID)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", errorObj.ProjectID).Error("failed to get error ownership rules")
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	fileNames := lo.FilterMap(structuredStackTrace, func(frame *privateModel.ErrorTrace, _ int) (string, bool) {
+		if frame == nil || frame.FileName == nil || *frame.FileName == "" {
+			return "", false
+		}
+		return *frame.FileName, true
+	})
+	rule := model.FindErrorOwnershipRule(rules, errorObj.ServiceName, fileNames)
+	if rule == nil {
+		return
+	}
+
+	if _, err := r.Store.AssignErrorGroupBySystem(ctx, store.AssignErrorGroupParams{
+		ID:              errorGroup.ID,
+		AssigneeID:      &rule.AssigneeID,
+		OwnershipRuleID: &rule.ID,
+	}); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("error_group_id", errorGroup.ID).Error("failed to assign error group")
+		return
+	}
+	errorGroup.AssigneeID = &rule.AssigneeID
+}
+
+// notifyErrorGroupRegression emails the assignee of a resolved error group that is seen again,
+// unless they opted out of all emails.
+func (r *Resolver) notifyErrorGroupRegression(ctx context.Context, errorGroup *model.ErrorGroup) {
+	if errorGroup.AssigneeID == nil {
+		return
+	}
+	assigneeID := *errorGroup.AssigneeID
+	message := fmt.Sprintf("A resolved error assigned to you has regressed: %s\nView error: %s/%d/errors/%s",
+		errorGroup.Event, os.Getenv("FRONTEND_URI"), errorGroup.ProjectID, errorGroup.SecureID)
+
+	go func() {
+		defer util.Recover()
+		var emails []string
+		if err := r.DB.WithContext(ctx).Raw(`
+			SELECT a.email
+			FROM admins a
+			WHERE a.id = ?
+			AND a.email IS NOT NULL
+			AND NOT EXISTS (
+				SELECT *
+				FROM email_opt_outs eoo
+				WHERE eoo.admin_id = a.id
+				AND eoo.category = 'All'
+			)
+		`, assigneeID).Scan(&emails).Error; err != nil {
+			log.WithContext(ctx).WithError(err).WithField("admin_id", assigneeID).Error("failed to query error group assignee")
+			return
+		}
+		for _, to := range emails {
+			if err := email.SendAlertEmail(ctx, r.MailClient, to, message, "Error Regression", "Assigned error regressed"); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("error_group_id", errorGroup.ID).Error("failed to notify error group assignee of regression")
+			}
+		}
+	}()
+}
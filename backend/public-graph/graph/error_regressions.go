@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+)
+
+// reopenRegressedErrorGroup records that a resolved error group was reopened by an error and
+// notifies its assignee. The error alerts of the error group are then sent regardless of their
+// thresholds.
+func (r *Resolver) reopenRegressedErrorGroup(ctx context.Context, errorGroup *model.ErrorGroup, errorObj *model.ErrorObject) error {
+	errorGroup.Regressed = true
+
+	eventData := map[string]interface{}{}
+	if errorObj.ServiceVersion != "" {
+		eventData["RegressedVersion"] = errorObj.ServiceVersion
+		// pin the regression to the deploy of its version when the ci pipeline reported one
+		if release, err := r.Store.GetRelease(ctx, errorGroup.ProjectID, errorObj.ServiceName, errorObj.ServiceVersion); err == nil {
+			if release.DeployedAt != nil {
+				eventData["RegressedDeployedAt"] = release.DeployedAt.Format(time.RFC3339)
+			}
+			if release.CommitSHA != nil {
+				eventData["RegressedCommitSHA"] = *release.CommitSHA
+			}
+		}
+	}
+	if errorGroup.ResolvedVersion != nil {
+		eventData["ResolvedVersion"] = *errorGroup.ResolvedVersion
+		if err := r.DB.WithContext(ctx).Model(errorGroup).Update("ResolvedVersion", nil).Error; err != nil {
+			return e.Wrap(err, "error clearing the resolved version of error group")
+		}
+		errorGroup.ResolvedVersion = nil
+	}
+
+	if err := r.Store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+		ErrorGroupID: errorGroup.ID,
+		EventType:    model.ErrorGroupOpenedEvent,
+		EventData:    eventData,
+	}); err != nil {
+		return e.Wrap(err, "error recording error group regression")
+	}
+
+	r.notifyErrorGroupRegression(ctx, errorGroup)
+	return nil
+}
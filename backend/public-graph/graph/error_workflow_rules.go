@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/store"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+)
+
+// releaseSnoozeMaxDuration bounds snoozes until the next release for services that stop deploying.
+const releaseSnoozeMaxDuration = 30 * 24 * time.Hour
+
+// applyErrorWorkflowRules lifts a release snooze once the error is seen in a new service version,
+// and otherwise applies the first matching ignore or snooze rule to an open error group.
+// Error groups whose state was changed by an admin are left alone.
+func (r *Resolver) applyErrorWorkflowRules(ctx context.Context, errorObj *model.ErrorObject, errorGroup *model.ErrorGroup) {
+	var params store.UpdateErrorGroupParams
+	if errorGroup.SnoozedVersion != nil {
+		if errorObj.ServiceVersion == "" || errorObj.ServiceVersion == *errorGroup.SnoozedVersion {
+			return
+		}
+		params = store.UpdateErrorGroupParams{ID: errorGroup.ID, State: errorGroup.State}
+	} else {
+		if errorGroup.State != privateModel.ErrorStateOpen || (errorGroup.SnoozedUntil != nil && errorGroup.SnoozedUntil.After(time.Now())) {
+			return
+		}
+
+		rules, err := r.Store.GetEnabledErrorWorkflowRules(ctx, errorObj.ProjectID)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("project_id", errorObj.ProjectID).Error("failed to get error workflow rules")
+			return
+		}
+		rule, found := lo.Find(rules, func(rule *model.ErrorWorkflowRule) bool {
+			return rule.Action != model.ErrorWorkflowRuleActionResolve && rule.MatchesEvent(errorObj.Event)
+		})
+		if !found {
+			return
+		}
+		if touched, err := r.Store.HasAdminErrorGroupActivity(ctx, errorGroup.ID); err != nil || touched {
+			return
+		}
+
+		params = store.UpdateErrorGroupParams{ID: errorGroup.ID, State: privateModel.ErrorStateOpen, RuleID: &rule.ID}
+		switch rule.Action {
+		case model.ErrorWorkflowRuleActionIgnore:
+			params.State = privateModel.ErrorStateIgnored
+		case model.ErrorWorkflowRuleActionSnooze:
+			var snoozedUntil time.Time
+			if rule.SnoozeMinutes != nil {
+				snoozedUntil = time.Now().Add(time.Duration(*rule.SnoozeMinutes) * time.Minute)
+			} else if errorObj.ServiceVersion != "" {
+				snoozedUntil = time.Now().Add(releaseSnoozeMaxDuration)
+				params.SnoozedVersion = &errorObj.ServiceVersion
+			} else {
+				// without a service version there is no release to wait for
+				return
+			}
+			params.SnoozedUntil = &snoozedUntil
+		}
+	}
+
+	if _, err := r.Store.UpdateErrorGroupStateBySystem(ctx, params); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("error_group_id", errorGroup.ID).Error("failed to apply error workflow rule")
+		return
+	}
+	errorGroup.State = params.State
+	errorGroup.SnoozedUntil = params.SnoozedUntil
+	errorGroup.SnoozedVersion = params.SnoozedVersion
+}
@@ -281,6 +281,7 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputReplayEventInput,
 		ec.unmarshalInputReplayEventsInput,
 		ec.unmarshalInputServiceInput,
+		ec.unmarshalInputSpanLinkInput,
 		ec.unmarshalInputStackFrameInput,
 	)
 	first := true
@@ -388,6 +389,8 @@ input BackendErrorObjectInput {
 	request_id: String
 	trace_id: String
 	span_id: String
+	parent_span_id: String
+	span_links: [SpanLinkInput!]
 	log_cursor: String
 	event: String!
 	type: String!
@@ -400,6 +403,13 @@ input BackendErrorObjectInput {
 	environment: String!
 }
 
+input SpanLinkInput {
+	trace_id: String!
+	span_id: String!
+	trace_state: String
+	attributes: Any
+}
+
 input MetricTag {
 	name: String!
 	value: String!
@@ -3744,7 +3754,7 @@ func (ec *executionContext) unmarshalInputBackendErrorObjectInput(ctx context.Co
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"session_secure_id", "request_id", "trace_id", "span_id", "log_cursor", "event", "type", "url", "source", "stackTrace", "timestamp", "payload", "service", "environment"}
+	fieldsInOrder := [...]string{"session_secure_id", "request_id", "trace_id", "span_id", "parent_span_id", "span_links", "log_cursor", "event", "type", "url", "source", "stackTrace", "timestamp", "payload", "service", "environment"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -3783,6 +3793,22 @@ func (ec *executionContext) unmarshalInputBackendErrorObjectInput(ctx context.Co
 			if err != nil {
 				return it, err
 			}
+		case "parent_span_id":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("parent_span_id"))
+			it.ParentSpanID, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "span_links":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("span_links"))
+			it.SpanLinks, err = ec.unmarshalOSpanLinkInput2ᚕᚖgithubᚗcomᚋhighlightᚑrunᚋhighlightᚋbackendᚋpublicᚑgraphᚋgraphᚋmodelᚐSpanLinkInputᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		case "log_cursor":
 			var err error
 
@@ -4213,6 +4239,58 @@ func (ec *executionContext) unmarshalInputServiceInput(ctx context.Context, obj
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputSpanLinkInput(ctx context.Context, obj interface{}) (model.SpanLinkInput, error) {
+	var it model.SpanLinkInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"trace_id", "span_id", "trace_state", "attributes"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "trace_id":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("trace_id"))
+			it.TraceID, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "span_id":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("span_id"))
+			it.SpanID, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "trace_state":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("trace_state"))
+			it.TraceState, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "attributes":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("attributes"))
+			it.Attributes, err = ec.unmarshalOAny2interface(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputStackFrameInput(ctx context.Context, obj interface{}) (model.StackFrameInput, error) {
 	var it model.StackFrameInput
 	asMap := map[string]interface{}{}
@@ -5020,6 +5098,11 @@ func (ec *executionContext) unmarshalNServiceInput2ᚖgithubᚗcomᚋhighlight
 	return &res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalNSpanLinkInput2ᚖgithubᚗcomᚋhighlightᚑrunᚋhighlightᚋbackendᚋpublicᚑgraphᚋgraphᚋmodelᚐSpanLinkInput(ctx context.Context, v interface{}) (*model.SpanLinkInput, error) {
+	res, err := ec.unmarshalInputSpanLinkInput(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) unmarshalNStackFrameInput2ᚕᚖgithubᚗcomᚋhighlightᚑrunᚋhighlightᚋbackendᚋpublicᚑgraphᚋgraphᚋmodelᚐStackFrameInput(ctx context.Context, v interface{}) ([]*model.StackFrameInput, error) {
 	var vSlice []interface{}
 	if v != nil {
@@ -5488,6 +5571,26 @@ func (ec *executionContext) unmarshalOReplayEventInput2ᚖgithubᚗcomᚋhighlig
 	return &res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalOSpanLinkInput2ᚕᚖgithubᚗcomᚋhighlightᚑrunᚋhighlightᚋbackendᚋpublicᚑgraphᚋgraphᚋmodelᚐSpanLinkInputᚄ(ctx context.Context, v interface{}) ([]*model.SpanLinkInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]*model.SpanLinkInput, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNSpanLinkInput2ᚖgithubᚗcomᚋhighlightᚑrunᚋhighlightᚋbackendᚋpublicᚑgraphᚋgraphᚋmodelᚐSpanLinkInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 func (ec *executionContext) unmarshalOStackFrameInput2ᚖgithubᚗcomᚋhighlightᚑrunᚋhighlightᚋbackendᚋpublicᚑgraphᚋgraphᚋmodelᚐStackFrameInput(ctx context.Context, v interface{}) (*model.StackFrameInput, error) {
 	if v == nil {
 		return nil, nil
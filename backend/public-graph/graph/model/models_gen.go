@@ -10,20 +10,22 @@ import (
 )
 
 type BackendErrorObjectInput struct {
-	SessionSecureID *string       `json:"session_secure_id"`
-	RequestID       *string       `json:"request_id"`
-	TraceID         *string       `json:"trace_id"`
-	SpanID          *string       `json:"span_id"`
-	LogCursor       *string       `json:"log_cursor"`
-	Event           string        `json:"event"`
-	Type            string        `json:"type"`
-	URL             string        `json:"url"`
-	Source          string        `json:"source"`
-	StackTrace      string        `json:"stackTrace"`
-	Timestamp       time.Time     `json:"timestamp"`
-	Payload         *string       `json:"payload"`
-	Service         *ServiceInput `json:"service"`
-	Environment     string        `json:"environment"`
+	SessionSecureID *string          `json:"session_secure_id"`
+	RequestID       *string          `json:"request_id"`
+	TraceID         *string          `json:"trace_id"`
+	SpanID          *string          `json:"span_id"`
+	ParentSpanID    *string          `json:"parent_span_id"`
+	SpanLinks       []*SpanLinkInput `json:"span_links"`
+	LogCursor       *string          `json:"log_cursor"`
+	Event           string           `json:"event"`
+	Type            string           `json:"type"`
+	URL             string           `json:"url"`
+	Source          string           `json:"source"`
+	StackTrace      string           `json:"stackTrace"`
+	Timestamp       time.Time        `json:"timestamp"`
+	Payload         *string          `json:"payload"`
+	Service         *ServiceInput    `json:"service"`
+	Environment     string           `json:"environment"`
 }
 
 type ErrorObjectInput struct {
@@ -77,6 +79,13 @@ type ServiceInput struct {
 	Version string `json:"version"`
 }
 
+type SpanLinkInput struct {
+	TraceID    string      `json:"trace_id"`
+	SpanID     string      `json:"span_id"`
+	TraceState *string     `json:"trace_state"`
+	Attributes interface{} `json:"attributes"`
+}
+
 type StackFrameInput struct {
 	FunctionName *string       `json:"functionName"`
 	Args         []interface{} `json:"args"`
@@ -46,6 +46,7 @@ import (
 	"github.com/highlight-run/highlight/backend/stacktraces"
 	"github.com/highlight-run/highlight/backend/storage"
 	"github.com/highlight-run/highlight/backend/store"
+	"github.com/highlight-run/highlight/backend/symbolication"
 	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight-run/highlight/backend/zapier"
@@ -79,6 +80,9 @@ type Resolver struct {
 	RH               *resthooks.Resthook
 	Store            *store.Store
 	LambdaClient     *lambda.Client
+	// Symbolicator deobfuscates java stacktraces with the proguard mappings of the project. Stacktraces are
+	// not deobfuscated when unset.
+	Symbolicator *symbolication.Symbolicator
 }
 
 type Location struct {
@@ -362,7 +366,7 @@ func (r *Resolver) GetErrorAppVersion(ctx context.Context, errorObj *model.Error
 func (r *Resolver) getMappedStackTraceString(ctx context.Context, stackTrace []*publicModel.StackFrameInput, projectID int, errorObj *model.ErrorObject) (*string, []*privateModel.ErrorTrace, error) {
 	version := r.GetErrorAppVersion(ctx, errorObj)
 	var newMappedStackTraceString *string
-	mappedStackTrace, err := stacktraces.EnhanceStackTrace(ctx, stackTrace, projectID, version, r.StorageClient)
+	mappedStackTrace, err := stacktraces.EnhanceStackTrace(ctx, stackTrace, projectID, version, r.StorageClient, r.Store)
 	if err != nil {
 		log.WithContext(ctx).Error(e.Wrapf(err, "error object: %+v", errorObj))
 	} else {
@@ -430,6 +434,8 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 			Environments:     environmentsString,
 			ServiceName:      errorObj.ServiceName,
 		}
+		newErrorGroup.SeenInVersion(errorObj.ServiceVersion)
+		newErrorGroup.Created = true
 
 		if tagGroup {
 			newErrorGroup.ErrorTagID = r.tagErrorGroup(ctx, errorObj)
@@ -438,6 +444,7 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 		if err := r.DB.WithContext(ctx).Create(newErrorGroup).Error; err != nil {
 			return nil, e.Wrap(err, "Error creating new error group")
 		}
+		zapier.Notify(ctx, r.RH, newErrorGroup.ProjectID, zapier.NewErrorGroupEvent, zapier.NewErrorGroupTrigger(newErrorGroup))
 
 		errorGroup = newErrorGroup
 	} else {
@@ -447,13 +454,25 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 			return nil, e.Wrap(err, "error retrieving top matched error group")
 		}
 
+		// the errors of a merged error group are grouped into the error group it was merged into
+		if errorGroup.MergedIntoID != nil {
+			mergedInto := &model.ErrorGroup{}
+			if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{
+				Model: model.Model{ID: *errorGroup.MergedIntoID},
+			}).Take(&mergedInto).Error; err != nil {
+				return nil, e.Wrap(err, "error retrieving merged error group")
+			}
+			errorGroup = mergedInto
+		}
+
 		environmentsString := getIncrementedEnvironmentCount(ctx, errorGroup, errorObj)
 
 		updatedState := errorGroup.State
 
-		// Reopen resolved errors
+		// Reopen resolved errors, unless they were resolved in a newer service version
 		// Note that ignored errors do change state
-		if updatedState == privateModel.ErrorStateResolved {
+		regressed := errorGroup.IsRegression(errorObj.ServiceVersion)
+		if regressed {
 			updatedState = privateModel.ErrorStateOpen
 		}
 
@@ -461,6 +480,8 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 			errorGroup.ErrorTagID = r.tagErrorGroup(ctx, errorObj)
 		}
 
+		errorGroup.SeenInVersion(errorObj.ServiceVersion)
+
 		if err := r.DB.WithContext(ctx).Model(errorGroup).Updates(&model.ErrorGroup{
 			StackTrace:       *errorObj.StackTrace,
 			MappedStackTrace: errorObj.MappedStackTrace,
@@ -469,9 +490,17 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 			State:            updatedState,
 			ServiceName:      errorObj.ServiceName,
 			ErrorTagID:       errorGroup.ErrorTagID,
+			FirstSeenVersion: errorGroup.FirstSeenVersion,
+			LastSeenVersion:  errorGroup.LastSeenVersion,
 		}).Error; err != nil {
 			return nil, e.Wrap(err, "Error updating error group")
 		}
+
+		if regressed {
+			if err := r.reopenRegressedErrorGroup(ctx, errorGroup, errorObj); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if err := r.DataSyncQueue.Submit(ctx, strconv.Itoa(errorGroup.ID), &kafka_queue.Message{Type: kafka_queue.ErrorGroupDataSync, ErrorGroupDataSync: &kafka_queue.ErrorGroupDataSyncArgs{ErrorGroupID: errorGroup.ID}}); err != nil {
@@ -644,6 +673,23 @@ func (r *Resolver) GetTopErrorGroupMatch(ctx context.Context, event string, proj
 	}
 }
 
+// GetErrorGroupMatchByGroupingRule returns the error group of the latest error with the same
+// grouping rule fingerprint.
+func (r *Resolver) GetErrorGroupMatchByGroupingRule(ctx context.Context, fingerprint *model.ErrorFingerprint) (*int, error) {
+	var errorGroupIDs []int
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorFingerprint{}).
+		Where("project_id = ? AND type = ? AND value = ? AND error_group_id IS NOT NULL", fingerprint.ProjectID, fingerprint.Type, fingerprint.Value).
+		Order("id DESC").
+		Limit(1).
+		Pluck("error_group_id", &errorGroupIDs).Error; err != nil {
+		return nil, e.Wrap(err, "error querying grouping rule fingerprint")
+	}
+	if len(errorGroupIDs) == 0 {
+		return nil, nil
+	}
+	return &errorGroupIDs[0], nil
+}
+
 // Matches the ErrorObject with an existing ErrorGroup, or creates a new one if the group does not exist
 func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.ErrorObject, structuredStackTrace []*privateModel.ErrorTrace, fields []*model.ErrorField, projectID int, workspace *model.Workspace) (*model.ErrorGroup, error) {
 	if errorObj == nil {
@@ -715,6 +761,15 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 		return nil, ErrUserFilteredError
 	}
 
+	ignoreRules, err := r.Store.GetEnabledErrorIgnoreRules(ctx, projectID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error ignore rules")
+	}
+	if rule := errorgroups.GetIgnoringRule(ignoreRules, errorObj); rule != nil {
+		hmetric.Incr(ctx, "errors.ignored.count", []attribute.KeyValue{attribute.Int(highlight.ProjectIDAttribute, projectID)}, 1)
+		return nil, e.Wrapf(ErrUserFilteredError, "ignored by error ignore rule %d", rule.ID)
+	}
+
 	withinBillingQuota, quotaPercent := r.IsWithinQuota(ctx, model.PricingProductTypeErrors, workspace, time.Now())
 	go func() {
 		defer util.Recover()
@@ -783,8 +838,28 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 		}
 	}
 
+	groupingRules, err := r.Store.GetEnabledErrorGroupingRules(ctx, projectID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error grouping rules")
+	}
+	groupingFingerprint := errorgroups.GetGroupingRuleFingerprint(projectID, groupingRules, errorObj, structuredStackTrace)
+
 	var embedding *model.ErrorObjectEmbeddings
-	if settings != nil && settings.ErrorEmbeddingsGroup {
+	if groupingFingerprint != nil {
+		// errors fingerprinted by a grouping rule are only grouped with errors of the same fingerprint
+		fingerprints = append(fingerprints, groupingFingerprint)
+		errorGroup, err = r.GetOrCreateErrorGroup(ctx, errorObj, func() (*int, error) {
+			match, err := r.GetErrorGroupMatchByGroupingRule(ctx, groupingFingerprint)
+			if err != nil {
+				return nil, e.Wrap(err, "Error getting error group match by grouping rule")
+			}
+			return match, err
+		}, settings != nil && settings.ErrorEmbeddingsTagGroup)
+		if err != nil {
+			return nil, e.Wrap(err, "Error getting or creating error group")
+		}
+		errorObj.ErrorGroupingMethod = model.ErrorGroupingMethodClassic
+	} else if settings != nil && settings.ErrorEmbeddingsGroup {
 		eCtx, cancel := context.WithTimeout(ctx, embeddings.InferenceTimeout)
 		defer cancel()
 		var emb []*model.ErrorObjectEmbeddings
@@ -823,6 +898,10 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 		}
 	}
 	errorObj.ErrorGroupID = errorGroup.ID
+	r.applyErrorWorkflowRules(ctx, errorObj, errorGroup)
+	r.assignErrorGroup(ctx, errorObj, errorGroup, structuredStackTrace)
+	r.setSuspectCommit(ctx, workspace, project, errorObj, errorGroup, structuredStackTrace)
+	r.sampleErrorPayload(ctx, errorObj)
 
 	if err := r.DB.WithContext(ctx).Create(errorObj).Error; err != nil {
 		return nil, e.Wrap(err, "Error performing error insert for error")
@@ -839,6 +918,10 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 		return nil, err
 	}
 
+	if err := r.Redis.Publish(ctx, redis.LiveErrorsChannel(projectID), errorObj); err != nil {
+		log.WithContext(ctx).WithError(err).Warn("failed to publish live error")
+	}
+
 	if err := r.AppendErrorFields(ctx, fields, errorGroup); err != nil {
 		return nil, e.Wrap(err, "error appending error fields")
 	}
@@ -1321,6 +1404,66 @@ func (r *Resolver) MarkBackendSetupImpl(ctx context.Context, projectID int, setu
 	return err
 }
 
+// PushSessionNetworkResourcesImpl adds network resources derived from otel spans to the session timeline.
+// They are staged with the resources pushed by the client, which the player orders by their start time.
+func (r *Resolver) PushSessionNetworkResourcesImpl(ctx context.Context, sessionSecureID string, resources string) error {
+	session, err := r.Store.GetSessionFromSecureID(ctx, sessionSecureID)
+	if err != nil {
+		return e.Wrap(err, "error querying session by sessionSecureID for pushing network resources")
+	}
+	if session.Processed != nil && *session.Processed {
+		log.WithContext(ctx).WithField("session_secure_id", sessionSecureID).Warn("dropping otel network resources of processed session")
+		return nil
+	}
+	return r.SaveSessionData(ctx, session.ProjectID, session.ID, 0, false, model.PayloadTypeResources, []byte(resources))
+}
+
+// segmentSessionWindow is how long before a Segment call without a session its user's session may have started.
+const segmentSessionWindow = 4 * time.Hour
+
+// PushSegmentEventImpl writes an identify call of a Segment destination to the user properties of its
+// session, and a track call to the track properties of its session like `H.track`. Calls sent without
+// a session are written to the latest session of their user, and dropped when there is none.
+func (r *Resolver) PushSegmentEventImpl(ctx context.Context, event *kafka_queue.PushSegmentEventArgs) error {
+	var session *model.Session
+	var err error
+	if event.SessionSecureID != "" {
+		if session, err = r.Store.GetSessionFromSecureID(ctx, event.SessionSecureID); err != nil {
+			return e.Wrap(err, "error querying session of segment call")
+		}
+		if session.ProjectID != event.ProjectID {
+			log.WithContext(ctx).WithField("project_id", event.ProjectID).WithField("session_secure_id", event.SessionSecureID).Warn("dropping segment call of a session of another project")
+			return nil
+		}
+	} else {
+		user := store.SessionUser{Identifier: event.UserID, Email: event.Email, IP: event.IP}
+		if session, err = r.Store.FindLatestSessionOfUser(ctx, event.ProjectID, user, event.Timestamp, segmentSessionWindow); err != nil {
+			return e.Wrap(err, "error querying session of segment call user")
+		}
+		if session == nil {
+			log.WithContext(ctx).WithField("project_id", event.ProjectID).WithField("user_id", event.UserID).Debug("dropping segment call without a session")
+			return nil
+		}
+	}
+
+	properties := make(map[string]interface{}, len(event.Properties)+1)
+	for k, v := range event.Properties {
+		properties[k] = v
+	}
+	switch event.Type {
+	case "identify":
+		identifier := event.UserID
+		if identifier == "" {
+			identifier = event.Email
+		}
+		return r.IdentifySessionImpl(ctx, session.SecureID, identifier, properties, false)
+	case "track":
+		properties["event"] = event.Event
+		return r.AddTrackPropertiesImpl(ctx, session.ID, properties)
+	}
+	return nil
+}
+
 func (r *Resolver) AddSessionFeedbackImpl(ctx context.Context, input *kafka_queue.AddSessionFeedbackArgs) error {
 	metadata := make(map[string]interface{})
 
@@ -1508,6 +1651,7 @@ func (r *Resolver) IdentifySessionImpl(ctx context.Context, sessionSecureID stri
 		session.Email = ptr.String(newUserProperties["email"])
 	}
 
+	newlyIdentified := !backfill && !session.Identified
 	if !backfill {
 		session.Identified = true
 	}
@@ -1516,6 +1660,10 @@ func (r *Resolver) IdentifySessionImpl(ctx context.Context, sessionSecureID stri
 		return e.Wrap(err, "[IdentifySession] failed to update session")
 	}
 
+	if newlyIdentified {
+		zapier.Notify(ctx, r.RH, session.ProjectID, zapier.NewIdentifiedSessionEvent, zapier.NewIdentifiedSessionTrigger(session))
+	}
+
 	if err := r.DataSyncQueue.Submit(ctx, strconv.Itoa(sessionID), &kafka_queue.Message{Type: kafka_queue.SessionDataSync, SessionDataSync: &kafka_queue.SessionDataSyncArgs{SessionID: sessionID}}); err != nil {
 		return err
 	}
@@ -1814,9 +1962,9 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				}
 			}
 
-			// Suppress alerts if ignored or snoozed.
+			// Suppress alerts if ignored or snoozed, or resolved in a newer version than the error's.
 			snoozed := group.SnoozedUntil != nil && group.SnoozedUntil.After(time.Now())
-			if group == nil || group.State == privateModel.ErrorStateIgnored || snoozed {
+			if group == nil || group.State == privateModel.ErrorStateIgnored || group.State == privateModel.ErrorStateResolved || snoozed {
 				continue
 			}
 
@@ -1832,8 +1980,34 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				log.WithContext(ctx).Error(e.Wrapf(err, "error counting errors from past %d minutes", *errorAlert.ThresholdWindow))
 				continue
 			}
-			if numErrors+1 < int64(errorAlert.CountThreshold) {
-				continue
+			// a regression alerts regardless of the threshold of the alert
+			if !group.Regressed {
+				if errorAlert.AnomalyDetection {
+					baseline, err := r.Store.GetErrorGroupBaseline(ctx, group.ID, alerts.HourOfWeek(time.Now()))
+					if err != nil {
+						log.WithContext(ctx).Error(e.Wrap(err, "error querying error group baseline"))
+						continue
+					}
+					if !alerts.IsErrorSpike(errorAlert, baseline, numErrors+1, time.Duration(*errorAlert.ThresholdWindow)*time.Minute) {
+						continue
+					}
+				} else if numErrors+1 < int64(errorAlert.CountThreshold) {
+					continue
+				}
+			}
+
+			affectedUsers := int64(0)
+			if err := r.DB.WithContext(ctx).Raw(`
+				SELECT COUNT(DISTINCT COALESCE(NULLIF(s.identifier, ''), s.client_id))
+				FROM error_objects eo
+				INNER JOIN sessions s
+				ON s.id = eo.session_id
+				WHERE
+					eo.project_id=?
+					AND eo.error_group_id=?
+					AND eo.created_at > ?
+			`, projectID, group.ID, time.Now().Add(time.Duration(-(*errorAlert.ThresholdWindow))*time.Minute)).Scan(&affectedUsers).Error; err != nil {
+				log.WithContext(ctx).Error(e.Wrapf(err, "error counting affected users from past %d minutes", *errorAlert.ThresholdWindow))
 			}
 
 			var alertCounts []AlertCountsGroupedByRecent
@@ -1869,7 +2043,7 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				}
 			}
 
-			if recentAlertCount > 0 {
+			if recentAlertCount > 0 && !group.Regressed {
 				log.WithContext(ctx).Warnf("num alerts > 0 for project_id=%d, error_group_id=%d", projectID, group.ID)
 				continue
 			}
@@ -1890,20 +2064,35 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 			}
 
 			log.WithContext(ctx).Infof("sending error alert to zapier. id=ErrorAlert_%d", errorAlert.ID)
-			if err := r.RH.Notify(sessionObj.ProjectID, fmt.Sprintf("ErrorAlert_%d", errorAlert.ID), hookPayload); err != nil {
+			if err := zapier.NotifyAlert(ctx, r.RH, zapier.ErrorAlert, errorAlert.ID, &errorAlert.Alert, hookPayload); err != nil {
 				log.WithContext(ctx).Error(e.Wrapf(err, "error sending error alert to Zapier (error alert id: %d)", errorAlert.ID))
 			}
 
-			if err := alerts.SendErrorAlert(ctx, alerts.SendErrorAlertEvent{
-				Session:         sessionObj,
-				ErrorAlert:      errorAlert,
-				ErrorGroup:      group,
-				ErrorObject:     errorObject,
-				Workspace:       workspace,
-				ErrorCount:      numErrors,
-				FirstErrorAlert: totalAlertCount <= 0,
-				VisitedURL:      visitedUrl,
+			if err := r.Redis.PublishAlertStateChange(ctx, redis.AlertStateChange{
+				ProjectID: projectID,
+				AlertID:   errorAlert.ID,
+				AlertType: model.AlertType.ERROR,
+				Title:     errorAlert.Name,
+				State:     redis.AlertStateAlerting,
 			}); err != nil {
+				log.WithContext(ctx).WithError(err).Warn("failed to publish error alert state change")
+			}
+
+			errorAlertEvent := alerts.SendErrorAlertEvent{
+				Session:           sessionObj,
+				ErrorAlert:        errorAlert,
+				ErrorGroup:        group,
+				ErrorObject:       errorObject,
+				Workspace:         workspace,
+				ErrorCount:        numErrors,
+				AffectedUserCount: affectedUsers,
+				FirstErrorAlert:   totalAlertCount <= 0,
+				VisitedURL:        visitedUrl,
+			}
+			if err := alerts.SendErrorAlert(ctx, errorAlertEvent); err != nil {
+				log.WithContext(ctx).Error(err)
+			}
+			if err := alerts.StartErrorEscalation(ctx, r.DB, errorAlertEvent); err != nil {
 				log.WithContext(ctx).Error(err)
 			}
 
@@ -1991,7 +2180,26 @@ func (r *Resolver) PushMetricsImpl(ctx context.Context, projectVerboseID *string
 	}
 
 	var traceRows []*clickhouse.TraceRow
+	var webVitalMessages []*kafka_queue.Message
 	for _, m := range metrics {
+		if clickhouse.IsWebVital(m.Name) && session.SecureID != "" {
+			webVitalMessages = append(webVitalMessages, &kafka_queue.Message{
+				Type: kafka_queue.PushWebVital,
+				PushWebVital: &kafka_queue.PushWebVitalArgs{
+					WebVitalRow: &clickhouse.WebVitalRow{
+						ProjectId:       uint32(projectID),
+						Timestamp:       m.Timestamp,
+						SecureSessionId: session.SecureID,
+						Name:            m.Name,
+						Value:           m.Value,
+						URL:             ptr.ToString(m.Group),
+						Environment:     session.Environment,
+						ServiceName:     session.ServiceName,
+					},
+				},
+			})
+		}
+
 		var spanID, parentSpanID, traceID = ptr.ToString(m.SpanID), ptr.ToString(m.ParentSpanID), ptr.ToString(m.TraceID)
 		if spanID == "" {
 			spanID = uuid.New().String()
@@ -2045,9 +2253,41 @@ func (r *Resolver) PushMetricsImpl(ctx context.Context, projectVerboseID *string
 			},
 		})
 	}
+	if len(webVitalMessages) > 0 {
+		if err := r.BatchedQueue.Submit(ctx, session.SecureID, webVitalMessages...); err != nil {
+			return err
+		}
+	}
 	return r.TracesQueue.Submit(ctx, "", messages...)
 }
 
+// getSpanLinks serializes the links of the span of a backend error in the shape of the trace links
+// returned by the private graph.
+func getSpanLinks(ctx context.Context, links []*publicModel.SpanLinkInput) *string {
+	if len(links) == 0 {
+		return nil
+	}
+	traceLinks := make([]*privateModel.TraceLink, 0, len(links))
+	for _, link := range links {
+		attributes, _ := link.Attributes.(map[string]interface{})
+		if attributes == nil {
+			attributes = map[string]interface{}{}
+		}
+		traceLinks = append(traceLinks, &privateModel.TraceLink{
+			TraceID:    link.TraceID,
+			SpanID:     link.SpanID,
+			TraceState: ptr.ToString(link.TraceState),
+			Attributes: attributes,
+		})
+	}
+	data, err := json.Marshal(traceLinks)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to marshal span links")
+		return nil
+	}
+	return ptr.String(string(data))
+}
+
 func extractErrorFields(sessionObj *model.Session, errorToProcess *model.ErrorObject) []*model.ErrorField {
 	projectID := sessionObj.ProjectID
 
@@ -2197,6 +2437,8 @@ func (r *Resolver) ProcessBackendPayloadImpl(ctx context.Context, sessionSecureI
 			SessionID:      sessionID,
 			TraceID:        v.TraceID,
 			SpanID:         v.SpanID,
+			ParentSpanID:   v.ParentSpanID,
+			SpanLinks:      getSpanLinks(ctx, v.SpanLinks),
 			LogCursor:      v.LogCursor,
 			Environment:    v.Environment,
 			Event:          v.Event,
@@ -2213,6 +2455,12 @@ func (r *Resolver) ProcessBackendPayloadImpl(ctx context.Context, sessionSecureI
 			ServiceVersion: v.Service.Version,
 		}
 
+		if errorToInsert.ServiceName != "" && errorToInsert.ServiceVersion != "" {
+			if _, err := r.Store.ObserveRelease(ctx, projectID, errorToInsert.ServiceName, errorToInsert.ServiceVersion, errorToInsert.Timestamp); err != nil {
+				log.WithContext(ctx).WithError(err).Error("Failed to observe release")
+			}
+		}
+
 		var mappedStackTrace *string
 		var structuredStackTrace []*privateModel.ErrorTrace
 		mappedStackTrace, structuredStackTrace, err = r.Store.EnhancedStackTrace(ctx, v.StackTrace, workspace, &project, errorToInsert, nil)
@@ -2223,6 +2471,16 @@ func (r *Resolver) ProcessBackendPayloadImpl(ctx context.Context, sessionSecureI
 			errorToInsert.MappedStackTrace = mappedStackTrace
 		}
 
+		// android crash reports are retraced when they are symbolicated
+		if r.Symbolicator != nil && v.Source != symbolication.PlatformAndroid &&
+			r.Symbolicator.Deobfuscate(ctx, projectID, errorToInsert.ServiceVersion, structuredStackTrace) {
+			if deobfuscated, err := json.Marshal(structuredStackTrace); err != nil {
+				log.WithContext(ctx).WithError(err).Error("Failed to marshal deobfuscated stacktrace")
+			} else {
+				errorToInsert.MappedStackTrace = pointy.String(string(deobfuscated))
+			}
+		}
+
 		group, err := r.HandleErrorAndGroup(ctx, errorToInsert, structuredStackTrace, extractErrorFields(session, errorToInsert), projectID, workspace)
 		if err != nil {
 			if e.Is(err, ErrNoisyError) {
@@ -2237,6 +2495,10 @@ func (r *Resolver) ProcessBackendPayloadImpl(ctx context.Context, sessionSecureI
 			continue
 		}
 
+		// the alert of a regression is sent with the last error of the group
+		if previous, ok := groups[group.ID]; ok && previous.Group.Regressed {
+			group.Regressed = true
+		}
 		groups[group.ID] = struct {
 			Group      *model.ErrorGroup
 			VisitedURL string
@@ -2492,6 +2754,10 @@ func (r *Resolver) ProcessPayload(ctx context.Context, sessionSecureID string, e
 		log.WithContext(ctx).WithField("session_id", sessionID).Info("processing payload")
 	}
 
+	if err := r.Redis.MarkSessionLive(ctx, sessionObj.ProjectID, sessionObj.SecureID); err != nil {
+		log.WithContext(ctx).WithError(err).Warn("failed to mark session live")
+	}
+
 	// If the session is processing or processed, set ResumedAfterProcessedTime and continue
 	if (sessionObj.Lock.Valid && !sessionObj.Lock.Time.IsZero()) || (sessionObj.Processed != nil && *sessionObj.Processed) {
 		if sessionObj.ResumedAfterProcessedTime == nil {
@@ -2790,6 +3056,10 @@ func (r *Resolver) ProcessPayload(ctx context.Context, sessionSecureID string, e
 				continue
 			}
 
+			// the alert of a regression is sent with the last error of the group
+			if previous, ok := groups[group.ID]; ok && previous.Group.Regressed {
+				group.Regressed = true
+			}
 			groups[group.ID] = struct {
 				Group      *model.ErrorGroup
 				VisitedURL string
@@ -3042,7 +3312,7 @@ func (r *Resolver) SendSessionInitAlert(ctx context.Context, workspace *model.Wo
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: sessionObj.Identifier, UserObject: sessionObj.UserObject, UserProperties: userProperties, URL: visitedUrl,
 		}
-		if err := r.RH.Notify(sessionObj.ProjectID, fmt.Sprintf("SessionAlert_%d", sessionAlert.ID), hookPayload); err != nil {
+		if err := zapier.NotifyAlert(ctx, r.RH, zapier.SessionAlert, sessionAlert.ID, &sessionAlert.Alert, hookPayload); err != nil {
 			log.WithContext(ctx).Error(e.Wrapf(err, "[project_id: %d] error sending new session alert to zapier", sessionObj.ProjectID))
 		}
 
@@ -3195,7 +3465,7 @@ func (r *Resolver) SendSessionTrackPropertiesAlert(ctx context.Context, workspac
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: session.Identifier, MatchedFields: matchedFields, RelatedFields: relatedFields, UserObject: session.UserObject,
 		}
-		if err := r.RH.Notify(session.ProjectID, fmt.Sprintf("SessionAlert_%d", sessionAlert.ID), hookPayload); err != nil {
+		if err := zapier.NotifyAlert(ctx, r.RH, zapier.SessionAlert, sessionAlert.ID, &sessionAlert.Alert, hookPayload); err != nil {
 			log.WithContext(ctx).Error(e.Wrapf(err, "error notifying zapier (session alert id: %d)", sessionAlert.ID))
 		}
 
@@ -3275,7 +3545,7 @@ func (r *Resolver) SendSessionIdentifiedAlert(ctx context.Context, workspace *mo
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: session.Identifier, UserProperties: userProperties, UserObject: session.UserObject,
 		}
-		if err := r.RH.Notify(session.ProjectID, fmt.Sprintf("SessionAlert_%d", sessionAlert.ID), hookPayload); err != nil {
+		if err := zapier.NotifyAlert(ctx, r.RH, zapier.SessionAlert, sessionAlert.ID, &sessionAlert.Alert, hookPayload); err != nil {
 			log.WithContext(ctx).Error(e.Wrapf(err, "[project_id: %d] error sending alert to zapier", session.ProjectID))
 		}
 
@@ -3365,7 +3635,7 @@ func (r *Resolver) SendSessionUserPropertiesAlert(ctx context.Context, workspace
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: session.Identifier, MatchedFields: matchedFields, UserObject: session.UserObject,
 		}
-		if err := r.RH.Notify(session.ProjectID, fmt.Sprintf("SessionAlert_%d", sessionAlert.ID), hookPayload); err != nil {
+		if err := zapier.NotifyAlert(ctx, r.RH, zapier.SessionAlert, sessionAlert.ID, &sessionAlert.Alert, hookPayload); err != nil {
 			log.WithContext(ctx).Error(e.Wrapf(err, "error notifying zapier (session alert id: %d)", sessionAlert.ID))
 		}
 
@@ -433,6 +433,43 @@ func TestMatchErrorsWithSameTracesDifferentBodies(t *testing.T) {
 	})
 }
 
+func TestErrorIgnoreRules(t *testing.T) {
+	stacktrace := `[{"fileName":"chrome-extension://abc/content.js","lineNumber":1,"functionName":"inject","columnNumber":2}]`
+	var structuredStackTrace []*privateModel.ErrorTrace
+	if err := json.Unmarshal([]byte(stacktrace), &structuredStackTrace); err != nil {
+		t.Fatal("failed to generate structured stacktrace")
+	}
+
+	util.RunTestWithDBWipe(t, resolver.DB, func(t *testing.T) {
+		project := model.Project{}
+		resolver.DB.Create(&project)
+		resolver.DB.Create(&model.ErrorIgnoreRule{
+			ProjectID:   project.ID,
+			Name:        "browser extensions",
+			SourceRegex: ptr.String("^chrome-extension://"),
+		})
+
+		errorObject := model.ErrorObject{
+			Event:      "boom",
+			ProjectID:  project.ID,
+			Source:     "chrome-extension://abc/content.js",
+			StackTrace: &stacktrace,
+		}
+		_, err := resolver.HandleErrorAndGroup(context.TODO(), &errorObject, structuredStackTrace, nil, project.ID, nil)
+		assert.ErrorIs(t, err, ErrUserFilteredError)
+
+		errorObject = model.ErrorObject{
+			Event:      "boom",
+			ProjectID:  project.ID,
+			Source:     "https://app.example.com/main.js",
+			StackTrace: &stacktrace,
+		}
+		errorGroup, err := resolver.HandleErrorAndGroup(context.TODO(), &errorObject, structuredStackTrace, nil, project.ID, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, errorGroup)
+	})
+}
+
 func TestUpdatingErrorState(t *testing.T) {
 	ctx := context.TODO()
 
@@ -492,6 +529,44 @@ func TestUpdatingErrorState(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, errorGroup.State, privateModel.ErrorStateIgnored) // Should stay ignored
 
+		// Resolve in a version
+		resolvedVersion := "1.2.0"
+		_, err = resolver.Store.UpdateErrorGroupStateBySystem(ctx, store.UpdateErrorGroupParams{
+			ID:              errorGroup.ID,
+			State:           privateModel.ErrorStateResolved,
+			ResolvedVersion: &resolvedVersion,
+		})
+		assert.NoError(t, err)
+
+		errorObject4 := model.ErrorObject{
+			Event:          "error",
+			ProjectID:      project.ID,
+			StackTrace:     &stacktrace,
+			ServiceVersion: "1.1.0",
+		}
+
+		errorGroup, err = resolver.HandleErrorAndGroup(ctx, &errorObject4, structuredStackTrace, nil, project.ID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, errorGroup.State, privateModel.ErrorStateResolved) // Should stay resolved in an older version
+		assert.False(t, errorGroup.Regressed)
+
+		errorObject5 := model.ErrorObject{
+			Event:          "error",
+			ProjectID:      project.ID,
+			StackTrace:     &stacktrace,
+			ServiceVersion: "1.2.1",
+		}
+
+		errorGroup, err = resolver.HandleErrorAndGroup(ctx, &errorObject5, structuredStackTrace, nil, project.ID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, errorGroup.State, privateModel.ErrorStateOpen) // Should reopen in a newer version
+		assert.True(t, errorGroup.Regressed)
+		assert.Nil(t, errorGroup.ResolvedVersion)
+
+		// the versions the error was seen in are tracked
+		assert.Equal(t, "1.1.0", *errorGroup.FirstSeenVersion)
+		assert.Equal(t, "1.2.1", *errorGroup.LastSeenVersion)
+
 	})
 }
 
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	trace2 "go.opentelemetry.io/otel/trace"
 
 	"github.com/aws/smithy-go/ptr"
@@ -18,12 +19,19 @@ import (
 	"github.com/highlight-run/highlight/backend/queryparser"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight/highlight/sdk/highlight-go"
+	hmetric "github.com/highlight/highlight/sdk/highlight-go/metric"
 	e "github.com/pkg/errors"
 	"github.com/segmentio/encoding/json"
 	log "github.com/sirupsen/logrus"
 )
 
 func (r *Resolver) IsTraceIngested(ctx context.Context, trace *clickhouse.TraceRow) bool {
+	ingested, _ := r.IsTraceIngestedWithReason(ctx, trace)
+	return ingested
+}
+
+// IsTraceIngestedWithReason returns whether the trace is ingested, and otherwise the reason it was dropped.
+func (r *Resolver) IsTraceIngestedWithReason(ctx context.Context, trace *clickhouse.TraceRow) (bool, privateModel.IngestReason) {
 	span := util.StartSpan(
 		"IsIngestedBy", util.ResourceName("sampling"), util.WithHighlightTracingDisabled(true), util.WithSpanKind(trace2.SpanKindServer),
 		util.Tag(highlight.ProjectIDAttribute, trace.ProjectId),
@@ -37,19 +45,19 @@ func (r *Resolver) IsTraceIngested(ctx context.Context, trace *clickhouse.TraceR
 	if !r.IsTraceIngestedByFilter(ctx, trace) {
 		span.SetAttribute("ingested", false)
 		span.SetAttribute("reason", privateModel.IngestReasonFilter)
-		return false
+		return false, privateModel.IngestReasonFilter
 	}
 	if !r.IsTraceIngestedBySample(ctx, trace) {
 		span.SetAttribute("ingested", false)
 		span.SetAttribute("reason", privateModel.IngestReasonSample)
-		return false
+		return false, privateModel.IngestReasonSample
 	}
 	if !r.IsTraceIngestedByRateLimit(ctx, trace) {
 		span.SetAttribute("ingested", false)
 		span.SetAttribute("reason", privateModel.IngestReasonRate)
-		return false
+		return false, privateModel.IngestReasonRate
 	}
-	return true
+	return true, ""
 }
 
 func (r *Resolver) IsTraceIngestedBySample(ctx context.Context, trace *clickhouse.TraceRow) bool {
@@ -65,6 +73,12 @@ func (r *Resolver) IsTraceIngestedByFilter(ctx context.Context, trace *clickhous
 }
 
 func (r *Resolver) IsLogIngested(ctx context.Context, logRow *clickhouse.LogRow) bool {
+	ingested, _ := r.IsLogIngestedWithReason(ctx, logRow)
+	return ingested
+}
+
+// IsLogIngestedWithReason returns whether the log is ingested, and otherwise the reason it was dropped.
+func (r *Resolver) IsLogIngestedWithReason(ctx context.Context, logRow *clickhouse.LogRow) (bool, privateModel.IngestReason) {
 	span := util.StartSpan(
 		"IsIngestedBy", util.ResourceName("sampling"), util.WithSpanKind(trace2.SpanKindServer),
 		util.Tag(highlight.ProjectIDAttribute, logRow.ProjectId),
@@ -78,19 +92,19 @@ func (r *Resolver) IsLogIngested(ctx context.Context, logRow *clickhouse.LogRow)
 	if !r.IsLogIngestedBySample(ctx, logRow) {
 		span.SetAttribute("ingested", false)
 		span.SetAttribute("reason", privateModel.IngestReasonSample)
-		return false
+		return false, privateModel.IngestReasonSample
 	}
 	if !r.IsLogIngestedByFilter(ctx, logRow) {
 		span.SetAttribute("ingested", false)
 		span.SetAttribute("reason", privateModel.IngestReasonFilter)
-		return false
+		return false, privateModel.IngestReasonFilter
 	}
 	if !r.IsLogIngestedByRateLimit(ctx, logRow) {
 		span.SetAttribute("ingested", false)
 		span.SetAttribute("reason", privateModel.IngestReasonRate)
-		return false
+		return false, privateModel.IngestReasonRate
 	}
-	return true
+	return true, ""
 }
 
 func (r *Resolver) IsLogIngestedBySample(ctx context.Context, logRow *clickhouse.LogRow) bool {
@@ -333,25 +347,29 @@ func (r *Resolver) isItemIngestedBySample(ctx context.Context, product privateMo
 	return ingested
 }
 
-func (r *Resolver) isItemIngestedByRate(ctx context.Context, when time.Time, product privateModel.ProductType, projectID int) bool {
+// GetMinuteRateLimit returns the per minute limit configured for the product of the project,
+// or nil if the product is not rate limited.
+func (r *Resolver) GetMinuteRateLimit(ctx context.Context, product privateModel.ProductType, projectID int) *int64 {
 	settings, err := r.getSettings(ctx, projectID, nil)
 	if err != nil {
-		return true
+		return nil
 	}
 
-	max := func() *int64 {
-		switch product {
-		case privateModel.ProductTypeSessions:
-			return settings.SessionMinuteRateLimit
-		case privateModel.ProductTypeErrors:
-			return settings.ErrorMinuteRateLimit
-		case privateModel.ProductTypeLogs:
-			return settings.LogMinuteRateLimit
-		case privateModel.ProductTypeTraces:
-			return settings.TraceMinuteRateLimit
-		}
-		return nil
-	}()
+	switch product {
+	case privateModel.ProductTypeSessions:
+		return settings.SessionMinuteRateLimit
+	case privateModel.ProductTypeErrors:
+		return settings.ErrorMinuteRateLimit
+	case privateModel.ProductTypeLogs:
+		return settings.LogMinuteRateLimit
+	case privateModel.ProductTypeTraces:
+		return settings.TraceMinuteRateLimit
+	}
+	return nil
+}
+
+func (r *Resolver) isItemIngestedByRate(ctx context.Context, when time.Time, product privateModel.ProductType, projectID int) bool {
+	max := r.GetMinuteRateLimit(ctx, product, projectID)
 	if max == nil {
 		return true
 	}
@@ -419,6 +437,32 @@ func isIngestedBySample(ctx context.Context, key string, rate float64) bool {
 	return sum < threshold
 }
 
+// sampleErrorPayload drops the payload of an error once its group has more errors in the hour than
+// the project's payload limit, unless the error is sampled. The error itself is still stored so that
+// it counts towards the aggregates of its group.
+func (r *Resolver) sampleErrorPayload(ctx context.Context, errorObj *model.ErrorObject) {
+	if errorObj.Payload == nil {
+		return
+	}
+
+	settings, err := r.getSettings(ctx, errorObj.ProjectID, nil)
+	if err != nil || settings.ErrorPayloadHourlyLimit == nil {
+		return
+	}
+
+	count, err := r.Redis.IncrementErrorGroupPayloadCount(ctx, errorObj.ErrorGroupID, time.Now())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to count error group payloads")
+		return
+	}
+	if count <= *settings.ErrorPayloadHourlyLimit || isIngestedBySample(ctx, "", settings.ErrorPayloadSamplingRate) {
+		return
+	}
+
+	errorObj.Payload = nil
+	hmetric.Incr(ctx, "errors.payloads.sampled.count", []attribute.KeyValue{attribute.Int(highlight.ProjectIDAttribute, errorObj.ProjectID)}, 1)
+}
+
 // isIngestedByRateLimit limits ingestion for a key at a max items per minute
 func (r *Resolver) isIngestedByRateLimit(ctx context.Context, key string, max int64, minute int) bool {
 	key = fmt.Sprintf("%s-%d", key, minute)
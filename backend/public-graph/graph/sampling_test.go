@@ -157,3 +157,45 @@ func Test_isExcludedError(t *testing.T) {
 	assert.False(t, resolver.IsErrorIngestedByFilter(ctx, p2.ID, &model2.BackendErrorObjectInput{Event: "foo bar baz"}))
 	assert.True(t, resolver.IsErrorIngestedByFilter(ctx, p3.ID, &model2.BackendErrorObjectInput{Event: "foo bar baz"}))
 }
+
+func Test_sampleErrorPayload(t *testing.T) {
+	ctx := context.TODO()
+
+	err := resolver.Redis.FlushDB(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+
+	project := model.Project{}
+	resolver.DB.Create(&project)
+
+	// payloads are stored without a limit
+	errorObj := model.ErrorObject{ProjectID: project.ID, ErrorGroupID: 1, Payload: pointy.String(`{"http.method": "GET"}`)}
+	resolver.sampleErrorPayload(ctx, &errorObj)
+	assert.NotNil(t, errorObj.Payload)
+
+	if _, err := resolver.Store.UpdateProjectErrorPayloadSettings(ctx, project.ID, pointy.Int64(2), 0, nil); err != nil {
+		t.Error(err)
+	}
+
+	// the payloads of the first errors of each group in the hour are stored
+	for i := 0; i < 2; i++ {
+		for _, errorGroupID := range []int{1, 2} {
+			errorObj := model.ErrorObject{ProjectID: project.ID, ErrorGroupID: errorGroupID, Payload: pointy.String(`{"http.method": "GET"}`)}
+			resolver.sampleErrorPayload(ctx, &errorObj)
+			assert.NotNil(t, errorObj.Payload)
+		}
+	}
+
+	// only the aggregates of the errors over the limit are kept
+	errorObj = model.ErrorObject{ProjectID: project.ID, ErrorGroupID: 1, Payload: pointy.String(`{"http.method": "GET"}`)}
+	resolver.sampleErrorPayload(ctx, &errorObj)
+	assert.Nil(t, errorObj.Payload)
+
+	if _, err := resolver.Store.UpdateProjectErrorPayloadSettings(ctx, project.ID, pointy.Int64(2), 1, nil); err != nil {
+		t.Error(err)
+	}
+	errorObj = model.ErrorObject{ProjectID: project.ID, ErrorGroupID: 1, Payload: pointy.String(`{"http.method": "GET"}`)}
+	resolver.sampleErrorPayload(ctx, &errorObj)
+	assert.NotNil(t, errorObj.Payload)
+}
@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// setSuspectCommit suggests the commit of the release of a new error group that most likely
+// introduced it, which is shown with the error group and in its alerts.
+func (r *Resolver) setSuspectCommit(ctx context.Context, workspace *model.Workspace, project *model.Project, errorObj *model.ErrorObject, errorGroup *model.ErrorGroup, structuredStackTrace []*privateModel.ErrorTrace) {
+	if !errorGroup.Created || workspace == nil {
+		return
+	}
+
+	commit, err := r.Store.FindSuspectCommit(ctx, workspace, project, errorObj, structuredStackTrace)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("error_group_id", errorGroup.ID).Error("failed to find suspect commit")
+		return
+	}
+	if commit == nil {
+		return
+	}
+
+	if err := r.DB.WithContext(ctx).Model(errorGroup).Update("SuspectCommit", commit).Error; err != nil {
+		log.WithContext(ctx).WithError(err).WithField("error_group_id", errorGroup.ID).Error("failed to save suspect commit")
+		return
+	}
+	errorGroup.SuspectCommit = commit
+}
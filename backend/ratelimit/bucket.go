@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// takeTokens refills a bucket for the time elapsed since it was last used and takes the requested
+// tokens if the bucket holds them, or is full for requests larger than the bucket. Large requests
+// leave the bucket in debt so that they are paid for by the following requests.
+// It returns whether the tokens were taken and otherwise the milliseconds until they can be.
+var takeTokens = goredis.NewScript(`
+	local key = KEYS[1]
+	local rate = tonumber(ARGV[1])
+	local capacity = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+	local n = tonumber(ARGV[4])
+
+	local state = redis.call("HMGET", key, "tokens", "ts")
+	local tokens = tonumber(state[1]) or capacity
+	local ts = tonumber(state[2]) or now
+	tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+	local needed = math.min(n, capacity)
+	local wait = 0
+	if tokens >= needed then
+		tokens = tokens - n
+	else
+		wait = math.ceil((needed - tokens) / rate)
+	end
+
+	redis.call("HSET", key, "tokens", tostring(tokens), "ts", now)
+	redis.call("PEXPIRE", key, math.ceil((capacity - tokens) / rate) + 1000)
+	return wait
+`)
+
+// TokenBucket limits the items ingested for a key to a per minute rate, allowing bursts of up to
+// a minute's worth. The buckets are kept in redis so that they are shared by all instances.
+type TokenBucket struct {
+	redis *redis.Client
+	name  string
+}
+
+func NewTokenBucket(redisClient *redis.Client, name string) *TokenBucket {
+	return &TokenBucket{redis: redisClient, name: name}
+}
+
+// Take takes n tokens from the bucket of the key refilling at perMinute tokens per minute.
+// When the bucket does not hold enough tokens, none are taken and Take returns how long
+// to wait before retrying.
+func (b *TokenBucket) Take(ctx context.Context, key string, perMinute int64, n int64) (bool, time.Duration, error) {
+	if perMinute <= 0 {
+		return false, time.Minute, nil
+	}
+	rate := float64(perMinute) / float64(time.Minute.Milliseconds())
+	keys := []string{fmt.Sprintf("token-bucket-%s-%s", b.name, key)}
+	wait, err := takeTokens.Run(ctx, b.redis.Client, keys, rate, perMinute, time.Now().UnixMilli(), n).Int64()
+	if err != nil {
+		return true, 0, err
+	}
+	return wait == 0, time.Duration(wait) * time.Millisecond, nil
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	ctx := context.Background()
+	bucket := NewTokenBucket(redis.NewClient(), "test")
+	key := uuid.New().String()
+
+	ok, _, err := bucket.Take(ctx, key, 60, 50)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 10 tokens are left, refilling at one per second
+	ok, retryAfter, err := bucket.Take(ctx, key, 60, 20)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.InDelta(t, 10*time.Second, retryAfter, float64(time.Second))
+
+	ok, _, err = bucket.Take(ctx, key, 60, 10)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// requests larger than the bucket need it to be full
+	other := uuid.New().String()
+	ok, _, err = bucket.Take(ctx, other, 60, 100)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, retryAfter, err = bucket.Take(ctx, other, 60, 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.InDelta(t, 41*time.Second, retryAfter, float64(time.Second))
+
+	ok, _, err = bucket.Take(ctx, other, 0, 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	redisClient := redis.NewClient()
+	limiter := New(redisClient, nil, nil, nil)
+	policy := &Policy{Name: "test", Limit: 2, Window: time.Hour, Key: KeyTypeIP}
+	key := uuid.New().String()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, policy, key)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err := limiter.Allow(ctx, policy, key)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, int64(0), result.Remaining)
+
+	// the window expires along with its first request
+	keys, err := redisClient.Client.Keys(ctx, "rate-limit-test-"+key+"-*").Result()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	ttl, err := redisClient.Client.PTTL(ctx, keys[0]).Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, time.Hour)
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+// PoliciesEnvVar holds a json list of policies overriding or extending DefaultPolicies, ie.
+// [{"name": "otel", "route": "/otel", "limit": 1000, "window_seconds": 1, "key": "ip"}]
+const PoliciesEnvVar = "RATE_LIMIT_POLICIES"
+
+// TrustedProxiesEnvVar holds a comma separated list of the ip addresses or cidr ranges of the load
+// balancers and proxies in front of the backend, ie. `10.0.0.0/8,192.168.1.10`.
+const TrustedProxiesEnvVar = "RATE_LIMIT_TRUSTED_PROXIES"
+
+// Policy limits requests to routes starting with Route to Limit per Window for each key.
+// Policies without a Route are only applied to route groups through Limiter.Policy.
+type Policy struct {
+	Name   string
+	Route  string
+	Limit  int64
+	Window time.Duration
+	Key    KeyType
+}
+
+type policyConfig struct {
+	Name          string  `json:"name"`
+	Route         string  `json:"route"`
+	Limit         int64   `json:"limit"`
+	WindowSeconds int     `json:"window_seconds"`
+	Key           KeyType `json:"key"`
+}
+
+// DefaultPolicies are generous enough to only stop misbehaving clients.
+var DefaultPolicies = []*Policy{
+	{Name: "public-graph", Limit: 1_000, Window: time.Second, Key: KeyTypeIP},
+	{Name: "otel", Route: "/otel", Limit: 1_000, Window: time.Second, Key: KeyTypeIP},
+	{Name: "vercel", Route: "/vercel", Limit: 500, Window: time.Second, Key: KeyTypeIP},
+	{Name: "otel-default-traces", Route: "/v1/traces", Limit: 1_000, Window: time.Second, Key: KeyTypeIP},
+	{Name: "otel-default-logs", Route: "/v1/logs", Limit: 1_000, Window: time.Second, Key: KeyTypeIP},
+	{Name: "otel-default-metrics", Route: "/v1/metrics", Limit: 1_000, Window: time.Second, Key: KeyTypeIP},
+	{Name: "otel-default-profiles", Route: "/v1/profiles", Limit: 100, Window: time.Second, Key: KeyTypeIP},
+	// the trailing slash only matches the http log routes below /v1/logs, not the otel logs route
+	{Name: "http-logs", Route: "/v1/logs/", Limit: 500, Window: time.Second, Key: KeyTypeIP},
+	{Name: "heartbeats", Route: "/heartbeats", Limit: 60, Window: time.Minute, Key: KeyTypeIP},
+	{Name: "mobile-crashes", Route: "/mobile/v1/crashes", Limit: 600, Window: time.Minute, Key: KeyTypeProject},
+	{Name: "mobile-mappings", Route: "/mobile/v1/mappings", Limit: 60, Window: time.Hour, Key: KeyTypeAPIKey},
+}
+
+// LoadPolicies returns the default policies with any overrides from the environment applied.
+// Overrides replace the default policy of the same name.
+func LoadPolicies() ([]*Policy, error) {
+	return ParsePolicies(os.Getenv(PoliciesEnvVar))
+}
+
+func ParsePolicies(config string) ([]*Policy, error) {
+	byName := map[string]*Policy{}
+	var names []string
+	for _, p := range DefaultPolicies {
+		policy := *p
+		byName[p.Name] = &policy
+		names = append(names, p.Name)
+	}
+
+	if config != "" {
+		var overrides []policyConfig
+		if err := json.Unmarshal([]byte(config), &overrides); err != nil {
+			return nil, e.Wrapf(err, "failed to parse %s", PoliciesEnvVar)
+		}
+		for _, o := range overrides {
+			if o.Name == "" || o.Limit <= 0 || o.WindowSeconds <= 0 {
+				return nil, e.Errorf("invalid rate limit policy %+v", o)
+			}
+			if o.Key == "" {
+				o.Key = KeyTypeIP
+			}
+			if o.Key != KeyTypeIP && o.Key != KeyTypeProject && o.Key != KeyTypeAPIKey {
+				return nil, e.Errorf("invalid rate limit key %s for policy %s", o.Key, o.Name)
+			}
+			if _, ok := byName[o.Name]; !ok {
+				names = append(names, o.Name)
+			}
+			byName[o.Name] = &Policy{
+				Name:   o.Name,
+				Route:  o.Route,
+				Limit:  o.Limit,
+				Window: time.Duration(o.WindowSeconds) * time.Second,
+				Key:    o.Key,
+			}
+		}
+	}
+
+	var policies []*Policy
+	for _, name := range names {
+		policies = append(policies, byName[name])
+	}
+	return policies, nil
+}
+
+// MatchPolicy returns the policy with the longest route prefixing the path.
+func MatchPolicy(policies []*Policy, path string) *Policy {
+	var match *Policy
+	for _, p := range policies {
+		if p.Route == "" {
+			continue
+		}
+		if path != p.Route && !strings.HasPrefix(path, strings.TrimSuffix(p.Route, "/")+"/") {
+			continue
+		}
+		if match == nil || len(p.Route) > len(match.Route) {
+			match = p
+		}
+	}
+	return match
+}
+
+// LoadTrustedProxies returns the trusted proxies configured in the environment.
+func LoadTrustedProxies() ([]*net.IPNet, error) {
+	return ParseTrustedProxies(os.Getenv(TrustedProxiesEnvVar))
+}
+
+func ParseTrustedProxies(config string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, proxy := range strings.Split(config, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, e.Errorf("invalid trusted proxy %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, e.Wrapf(err, "failed to parse %s", TrustedProxiesEnvVar)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
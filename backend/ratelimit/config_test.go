@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("")
+	assert.NoError(t, err)
+	assert.Len(t, policies, len(DefaultPolicies))
+
+	policies, err = ParsePolicies(`[{"name": "otel", "route": "/otel", "limit": 10, "window_seconds": 60, "key": "project"}, {"name": "custom", "route": "/custom", "limit": 5, "window_seconds": 1}]`)
+	assert.NoError(t, err)
+	assert.Len(t, policies, len(DefaultPolicies)+1)
+
+	otel := MatchPolicy(policies, "/otel/v1/traces")
+	assert.Equal(t, int64(10), otel.Limit)
+	assert.Equal(t, time.Minute, otel.Window)
+	assert.Equal(t, KeyTypeProject, otel.Key)
+
+	custom := MatchPolicy(policies, "/custom")
+	assert.Equal(t, KeyTypeIP, custom.Key)
+
+	// defaults are not modified by overrides
+	assert.Equal(t, int64(1_000), MatchPolicy(DefaultPolicies, "/otel/v1/traces").Limit)
+
+	_, err = ParsePolicies(`[{"name": "otel", "route": "/otel", "limit": 0, "window_seconds": 60}]`)
+	assert.Error(t, err)
+	_, err = ParsePolicies(`[{"name": "otel", "route": "/otel", "limit": 1, "window_seconds": 60, "key": "user"}]`)
+	assert.Error(t, err)
+}
+
+func TestMatchPolicy(t *testing.T) {
+	assert.Equal(t, "mobile-crashes", MatchPolicy(DefaultPolicies, "/mobile/v1/crashes").Name)
+	assert.Equal(t, "mobile-mappings", MatchPolicy(DefaultPolicies, "/mobile/v1/mappings").Name)
+	assert.Equal(t, "http-logs", MatchPolicy(DefaultPolicies, "/v1/logs/raw").Name)
+	assert.Equal(t, "otel-default-logs", MatchPolicy(DefaultPolicies, "/v1/logs").Name)
+	assert.Equal(t, "otel-default-traces", MatchPolicy(DefaultPolicies, "/v1/traces").Name)
+	assert.Equal(t, "otel-default-profiles", MatchPolicy(DefaultPolicies, "/v1/profiles").Name)
+	assert.Nil(t, MatchPolicy(DefaultPolicies, "/otelx"))
+	assert.Nil(t, MatchPolicy(DefaultPolicies, "/private"))
+}
+
+type testValidator struct{}
+
+func (testValidator) ValidateProject(_ context.Context, project string) bool {
+	return project == "abc"
+}
+
+func (testValidator) ValidateAPIKey(_ context.Context, apiKey string) bool {
+	return apiKey == "secret"
+}
+
+func TestRequestKey(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8")
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/mobile/v1/crashes?project_id=abc", nil)
+	r.RemoteAddr = "10.0.0.2:1234"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, 10.0.0.1")
+	assert.Equal(t, "project-abc", RequestKey(r, KeyTypeProject, proxies, testValidator{}))
+	assert.Equal(t, "ip-1.2.3.4", RequestKey(r, KeyTypeAPIKey, proxies, testValidator{}))
+	assert.Equal(t, "ip-1.2.3.4", RequestKey(r, KeyTypeIP, proxies, testValidator{}))
+
+	// api keys are not stored in redis
+	r.Header.Set(APIKeyHeader, "secret")
+	assert.Equal(t, "api-key-"+apitoken.Hash("secret"), RequestKey(r, KeyTypeAPIKey, proxies, testValidator{}))
+
+	// made up keys do not get a window of their own
+	r = httptest.NewRequest("POST", "/mobile/v1/crashes?project_id=made-up", nil)
+	r.RemoteAddr = "5.6.7.8:1234"
+	r.Header.Set("Authorization", "Bearer made-up")
+	assert.Equal(t, "ip-5.6.7.8", RequestKey(r, KeyTypeProject, proxies, testValidator{}))
+	assert.Equal(t, "ip-5.6.7.8", RequestKey(r, KeyTypeAPIKey, proxies, testValidator{}))
+	assert.Equal(t, "ip-5.6.7.8", RequestKey(r, KeyTypeProject, proxies, nil))
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.10")
+	assert.NoError(t, err)
+
+	// the headers of a client that is not a trusted proxy are ignored
+	r := httptest.NewRequest("POST", "/otel", nil)
+	r.RemoteAddr = "5.6.7.8:1234"
+	r.Header.Set("X-Real-Ip", "1.1.1.1")
+	r.Header.Set("X-Forwarded-For", "2.2.2.2")
+	assert.Equal(t, "5.6.7.8", ClientIP(r, proxies))
+	assert.Equal(t, "5.6.7.8", ClientIP(r, nil))
+
+	// addresses prepended by the client are skipped for the one added by the trusted proxies
+	r = httptest.NewRequest("POST", "/otel", nil)
+	r.RemoteAddr = "192.168.1.10:1234"
+	r.Header.Set("X-Forwarded-For", "2.2.2.2, 5.6.7.8, 10.0.0.1")
+	assert.Equal(t, "5.6.7.8", ClientIP(r, proxies))
+
+	r.Header.Set("X-Real-Ip", "1.1.1.1")
+	assert.Equal(t, "1.1.1.1", ClientIP(r, proxies))
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies("")
+	assert.NoError(t, err)
+	assert.Empty(t, proxies)
+
+	proxies, err = ParseTrustedProxies("10.0.0.0/8,192.168.1.10,::1")
+	assert.NoError(t, err)
+	assert.Len(t, proxies, 3)
+	assert.True(t, isTrustedProxy("10.1.2.3", proxies))
+	assert.True(t, isTrustedProxy("192.168.1.10", proxies))
+	assert.False(t, isTrustedProxy("192.168.1.11", proxies))
+	assert.True(t, isTrustedProxy("::1", proxies))
+
+	_, err = ParseTrustedProxies("proxy.internal")
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies("10.0.0.0/33")
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/highlight-run/highlight/backend/redis"
+	goredis "github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ProjectHeader = "x-highlight-project"
+	APIKeyHeader  = "x-highlight-api-key"
+
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+)
+
+type KeyType string
+
+const (
+	KeyTypeIP      KeyType = "ip"
+	KeyTypeProject KeyType = "project"
+	KeyTypeAPIKey  KeyType = "api_key"
+)
+
+// Result describes the state of a rate limit window after a request was counted.
+type Result struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	Reset     time.Duration
+}
+
+// countRequest counts a request in the window of a key, expiring the window with its first request
+// so that a key is never left without a ttl.
+var countRequest = goredis.NewScript(`
+	local count = redis.call("INCR", KEYS[1])
+	if count == 1 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	end
+	return count
+`)
+
+// Validator checks the project or api key of a request before the request is limited by it, so
+// that a client cannot get a new window for every request by sending made up keys.
+type Validator interface {
+	ValidateProject(ctx context.Context, project string) bool
+	ValidateAPIKey(ctx context.Context, apiKey string) bool
+}
+
+// Limiter is a fixed window rate limiter shared by all instances through redis.
+type Limiter struct {
+	redis          *redis.Client
+	policies       []*Policy
+	trustedProxies []*net.IPNet
+	validator      Validator
+}
+
+// New returns a limiter of the policies. The proxy headers of a request are only used to identify
+// its client when the request comes from one of the trusted proxies, and its project or api key
+// only once the validator accepts them.
+func New(redisClient *redis.Client, policies []*Policy, trustedProxies []*net.IPNet, validator Validator) *Limiter {
+	return &Limiter{redis: redisClient, policies: policies, trustedProxies: trustedProxies, validator: validator}
+}
+
+// Allow counts a request against the key and reports whether it is within the policy.
+// Errors talking to redis allow the request so that an outage does not block ingestion.
+func (l *Limiter) Allow(ctx context.Context, policy *Policy, key string) (*Result, error) {
+	now := time.Now()
+	window := now.UnixNano() / int64(policy.Window)
+	windowEnd := time.Unix(0, (window+1)*int64(policy.Window))
+	result := &Result{
+		Allowed:   true,
+		Limit:     policy.Limit,
+		Remaining: policy.Limit,
+		Reset:     windowEnd.Sub(now),
+	}
+
+	redisKey := fmt.Sprintf("rate-limit-%s-%s-%d", policy.Name, key, window)
+	count, err := countRequest.Run(ctx, l.redis.Client, []string{redisKey}, policy.Window.Milliseconds()).Int64()
+	if err != nil {
+		return result, err
+	}
+
+	result.Allowed = count <= policy.Limit
+	result.Remaining = max(policy.Limit-count, 0)
+	return result, nil
+}
+
+// Middleware limits requests to routes matching one of the limiter's policies.
+// Requests that do not match a policy are passed through.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.serve(w, r, next, MatchPolicy(l.policies, r.URL.Path))
+	})
+}
+
+// Policy returns a middleware applying the named policy to every request of a route group,
+// for routes whose path depends on the runtime such as the public graph.
+func (l *Limiter) Policy(name string) func(http.Handler) http.Handler {
+	var policy *Policy
+	for _, p := range l.policies {
+		if p.Name == name {
+			policy = p
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l.serve(w, r, next, policy)
+		})
+	}
+}
+
+func (l *Limiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler, policy *Policy) {
+	if policy == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	result, err := l.Allow(ctx, policy, RequestKey(r, policy.Key, l.trustedProxies, l.validator))
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("policy", policy.Name).Error("failed to check rate limit")
+	}
+
+	WriteHeaders(w, result)
+	if !result.Allowed {
+		w.Header().Set(RetryAfterHeader, strconv.Itoa(resetSeconds(result.Reset)))
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// WriteHeaders sets the RateLimit headers of the IETF httpapi-ratelimit-headers draft.
+func WriteHeaders(w http.ResponseWriter, result *Result) {
+	w.Header().Set(LimitHeader, strconv.FormatInt(result.Limit, 10))
+	w.Header().Set(RemainingHeader, strconv.FormatInt(result.Remaining, 10))
+	w.Header().Set(ResetHeader, strconv.Itoa(resetSeconds(result.Reset)))
+}
+
+func resetSeconds(reset time.Duration) int {
+	return int((reset + time.Second - 1) / time.Second)
+}
+
+// RequestKey returns the identity a request is limited by. Requests without a valid project or
+// api key are limited by their ip address, and api keys are hashed so that they are not stored in
+// redis.
+func RequestKey(r *http.Request, keyType KeyType, trustedProxies []*net.IPNet, validator Validator) string {
+	ctx := r.Context()
+	switch keyType {
+	case KeyTypeProject:
+		project := r.Header.Get(ProjectHeader)
+		if project == "" {
+			project = r.URL.Query().Get("project_id")
+		}
+		if project != "" && validator != nil && validator.ValidateProject(ctx, project) {
+			return "project-" + project
+		}
+	case KeyTypeAPIKey:
+		apiKey := r.Header.Get(APIKeyHeader)
+		if apiKey == "" {
+			apiKey = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if apiKey != "" && validator != nil && validator.ValidateAPIKey(ctx, apiKey) {
+			return "api-key-" + apitoken.Hash(apiKey)
+		}
+	}
+	return "ip-" + ClientIP(r, trustedProxies)
+}
+
+// ClientIP returns the ip address of the client. The proxy headers are set by the client unless
+// the request comes from a trusted proxy, so they are only used for requests of trusted proxies,
+// taking the last address of X-Forwarded-For that was not added by one of them.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" {
+		return realIP
+	}
+	forwardedFor := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		forwarded := strings.TrimSpace(forwardedFor[i])
+		if forwarded == "" {
+			continue
+		}
+		ip = forwarded
+		if !isTrustedProxy(forwarded, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package rbac
+
+import "sort"
+
+// mutationPermissions are the permissions of the private graph mutations. Mutations that are not
+// listed need PermissionEdit, and mutations that an admin runs for themselves need no permission.
+var mutationPermissions = map[string]Permission{
+	// self-service mutations
+	"updateAdminAndCreateWorkspace": "",
+	"updateAdminAboutYouDetails":    "",
+	"createAdmin":                   "",
+	"createWorkspace":               "",
+	"addAdminToWorkspace":           "",
+	"joinWorkspace":                 "",
+	"emailSignup":                   "",
+	"requestAccess":                 "",
+	"submitRegistrationForm":        "",
+	"updateEmailOptOut":             "",
+	"markErrorGroupAsViewed":        "",
+	"markSessionAsViewed":           "",
+	"muteSessionCommentThread":      "",
+	"muteErrorCommentThread":        "",
+	"exportSession":                 "",
+
+	// api tokens are personal, or authorized by the workspace of the token in the resolver
+	"createAPIToken": "",
+	"rotateAPIToken": "",
+	"revokeAPIToken": "",
+
+	// chaos faults are configured by highlight admins, which is checked in the resolver
+	"setChaosFaults":   "",
+	"clearChaosFaults": "",
+
+	"mergeErrorGroups": PermissionEdit,
+	"splitErrorGroup":  PermissionEdit,
+
+	"deleteSessions": PermissionDeleteSessions,
+
+	"createErrorAlert":                  PermissionManageAlerts,
+	"updateErrorAlert":                  PermissionManageAlerts,
+	"deleteErrorAlert":                  PermissionManageAlerts,
+	"updateErrorAlertIsDisabled":        PermissionManageAlerts,
+	"updateErrorAlertDestinations":      PermissionManageAlerts,
+	"updateAlertMicrosoftTeamsChannels": PermissionManageAlerts,
+	"updateAlertDiscordWebhooks":        PermissionManageAlerts,
+	"updateDigestDiscordWebhooks":       PermissionManageAlerts,
+	"updateDigestSetting":               PermissionManageAlerts,
+	"createSessionAlert":                PermissionManageAlerts,
+	"updateSessionAlert":                PermissionManageAlerts,
+	"deleteSessionAlert":                PermissionManageAlerts,
+	"updateSessionAlertIsDisabled":      PermissionManageAlerts,
+	"createLogAlert":                    PermissionManageAlerts,
+	"updateLogAlert":                    PermissionManageAlerts,
+	"deleteLogAlert":                    PermissionManageAlerts,
+	"updateLogAlertIsDisabled":          PermissionManageAlerts,
+	"createMetricMonitor":               PermissionManageAlerts,
+	"updateMetricMonitor":               PermissionManageAlerts,
+	"deleteMetricMonitor":               PermissionManageAlerts,
+	"updateMetricMonitorIsDisabled":     PermissionManageAlerts,
+	"createUptimeMonitor":               PermissionManageAlerts,
+	"updateUptimeMonitor":               PermissionManageAlerts,
+	"deleteUptimeMonitor":               PermissionManageAlerts,
+	"createHeartbeatMonitor":            PermissionManageAlerts,
+	"updateHeartbeatMonitor":            PermissionManageAlerts,
+	"deleteHeartbeatMonitor":            PermissionManageAlerts,
+	"createTraceAlert":                  PermissionManageAlerts,
+	"updateTraceAlert":                  PermissionManageAlerts,
+	"deleteTraceAlert":                  PermissionManageAlerts,
+	"createEscalationPolicy":            PermissionManageAlerts,
+	"updateEscalationPolicy":            PermissionManageAlerts,
+	"deleteEscalationPolicy":            PermissionManageAlerts,
+	"updateAlertEscalationPolicy":       PermissionManageAlerts,
+	"createOnCallSchedule":              PermissionManageAlerts,
+	"updateOnCallSchedule":              PermissionManageAlerts,
+	"deleteOnCallSchedule":              PermissionManageAlerts,
+	"upsertSlackChannel":                PermissionManageAlerts,
+	"upsertDiscordChannel":              PermissionManageAlerts,
+
+	"addIntegrationToProject":          PermissionManageIntegrations,
+	"removeIntegrationFromProject":     PermissionManageIntegrations,
+	"createZendeskAccessToken":         PermissionManageIntegrations,
+	"addIntegrationToWorkspace":        PermissionManageIntegrations,
+	"removeIntegrationFromWorkspace":   PermissionManageIntegrations,
+	"syncSlackIntegration":             PermissionManageIntegrations,
+	"modifyClearbitIntegration":        PermissionManageIntegrations,
+	"updateVercelProjectMappings":      PermissionManageIntegrations,
+	"updateClickUpProjectMappings":     PermissionManageIntegrations,
+	"refreshClickUpMetadata":           PermissionManageIntegrations,
+	"updateIntegrationProjectMappings": PermissionManageIntegrations,
+	"updateGitHubProjectRepository":    PermissionManageIntegrations,
+	"editServiceGitlabSettings":        PermissionManageIntegrations,
+	"editServiceGithubSettings":        PermissionManageIntegrations,
+	"testErrorEnhancement":             PermissionManageIntegrations,
+	"updateWebhookSettings":            PermissionManageIntegrations,
+	"updateDatadogLogForwarder":        PermissionManageIntegrations,
+	"deleteDatadogLogForwarder":        PermissionManageIntegrations,
+	"updateProductAnalyticsExport":     PermissionManageIntegrations,
+	"deleteProductAnalyticsExport":     PermissionManageIntegrations,
+	"updateWarehouseExport":            PermissionManageIntegrations,
+	"deleteWarehouseExport":            PermissionManageIntegrations,
+	"upsertSourcemapBucket":            PermissionManageIntegrations,
+	"deleteSourcemapBucket":            PermissionManageIntegrations,
+	"rotateWebhookSigningSecret":       PermissionManageIntegrations,
+
+	"createProject":                 PermissionManageProjects,
+	"editProject":                   PermissionManageProjects,
+	"editProjectSettings":           PermissionManageProjects,
+	"deleteProject":                 PermissionManageProjects,
+	"updateProjectRequireIngestKey": PermissionManageProjects,
+	"updateRedactionRules":          PermissionManageProjects,
+	"createIngestFilterRule":        PermissionManageProjects,
+	"updateIngestFilterRule":        PermissionManageProjects,
+	"deleteIngestFilterRule":        PermissionManageProjects,
+	"createErrorIgnoreRule":         PermissionManageProjects,
+	"updateErrorIgnoreRule":         PermissionManageProjects,
+	"deleteErrorIgnoreRule":         PermissionManageProjects,
+	"createErrorGroupingRule":       PermissionManageProjects,
+	"updateErrorGroupingRule":       PermissionManageProjects,
+	"deleteErrorGroupingRule":       PermissionManageProjects,
+
+	"sendAdminWorkspaceInvite":      PermissionInviteMembers,
+	"deleteInviteLinkFromWorkspace": PermissionManageMembers,
+	"changeAdminRole":               PermissionManageMembers,
+	"deleteAdminFromProject":        PermissionManageMembers,
+	"deleteAdminFromWorkspace":      PermissionManageMembers,
+	"updateAllowedEmailOrigins":     PermissionManageMembers,
+
+	"createWorkspaceRole": PermissionManageRoles,
+	"updateWorkspaceRole": PermissionManageRoles,
+	"deleteWorkspaceRole": PermissionManageRoles,
+
+	"createOrUpdateStripeSubscription": PermissionManageBilling,
+	"updateBillingDetails":             PermissionManageBilling,
+	"saveBillingPlan":                  PermissionManageBilling,
+	"updateAllowMeterOverage":          PermissionManageBilling,
+
+	"editWorkspace":         PermissionManageWorkspace,
+	"editWorkspaceSettings": PermissionManageWorkspace,
+
+	"updateWorkspaceSSOConfig": PermissionManageWorkspace,
+	"verifyWorkspaceSSODomain": PermissionManageWorkspace,
+	"rotateWorkspaceSCIMToken": PermissionManageWorkspace,
+	"deleteWorkspaceSSOConfig": PermissionManageWorkspace,
+}
+
+// MutationPermission returns the permission that a private graph mutation needs, which is
+// PermissionEdit unless listed otherwise, and false for mutations that need no permission.
+func MutationPermission(mutation string) (Permission, bool) {
+	permission, ok := mutationPermissions[mutation]
+	if !ok {
+		return PermissionEdit, true
+	}
+	return permission, permission != ""
+}
+
+// Mutations returns the private graph mutations that are listed with a permission.
+func Mutations() []string {
+	var mutations []string
+	for mutation, permission := range mutationPermissions {
+		if permission != "" {
+			mutations = append(mutations, mutation)
+		}
+	}
+	sort.Strings(mutations)
+	return mutations
+}
@@ -0,0 +1,191 @@
+package rbac
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Permission is an action that a role allows in a workspace. Viewing the data of a workspace needs
+// no permission.
+type Permission string
+
+const (
+	// PermissionView allows viewing the data of a workspace, which every role can do.
+	PermissionView Permission = ""
+	// PermissionEdit allows commenting, and editing segments, dashboards, sessions and errors.
+	PermissionEdit               Permission = "edit"
+	PermissionDeleteSessions     Permission = "delete_sessions"
+	PermissionManageAlerts       Permission = "manage_alerts"
+	PermissionManageIntegrations Permission = "manage_integrations"
+	PermissionManageProjects     Permission = "manage_projects"
+	PermissionInviteMembers      Permission = "invite_members"
+	// PermissionManageMembers allows changing the roles of admins and removing them from the workspace.
+	PermissionManageMembers   Permission = "manage_members"
+	PermissionManageRoles     Permission = "manage_roles"
+	PermissionManageBilling   Permission = "manage_billing"
+	PermissionManageWorkspace Permission = "manage_workspace"
+)
+
+// Permissions are all the permissions, in the order they are shown.
+var Permissions = []Permission{
+	PermissionEdit,
+	PermissionDeleteSessions,
+	PermissionManageAlerts,
+	PermissionManageIntegrations,
+	PermissionManageProjects,
+	PermissionInviteMembers,
+	PermissionManageMembers,
+	PermissionManageRoles,
+	PermissionManageBilling,
+	PermissionManageWorkspace,
+}
+
+func (p Permission) IsValid() bool {
+	for _, permission := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// the built-in roles, matching model.AdminRole
+const (
+	RoleOwner  = "OWNER"
+	RoleAdmin  = "ADMIN"
+	RoleMember = "MEMBER"
+	RoleViewer = "VIEWER"
+)
+
+// Role is a named set of permissions of the admins of a workspace.
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	// BuiltIn roles are in every workspace and cannot be changed.
+	BuiltIn bool `json:"built_in"`
+}
+
+var builtInRoles = []*Role{
+	{
+		Name:        RoleOwner,
+		Description: "Can do everything, and manage the other owners of the workspace.",
+		Permissions: Permissions,
+		BuiltIn:     true,
+	},
+	{
+		Name:        RoleAdmin,
+		Description: "Can do everything except manage the owners of the workspace.",
+		Permissions: Permissions,
+		BuiltIn:     true,
+	},
+	{
+		Name:        RoleMember,
+		Description: "Can edit data, manage projects, alerts and integrations, and invite members.",
+		Permissions: []Permission{
+			PermissionEdit,
+			PermissionManageAlerts,
+			PermissionManageIntegrations,
+			PermissionManageProjects,
+			PermissionInviteMembers,
+		},
+		BuiltIn: true,
+	},
+	{
+		Name:        RoleViewer,
+		Description: "Can view the data of the workspace.",
+		BuiltIn:     true,
+	},
+}
+
+// BuiltInRoles returns the built-in roles, from the most to the least privileged.
+func BuiltInRoles() []*Role {
+	return builtInRoles
+}
+
+// BuiltInRole returns the built-in role with the name, or nil if there is none.
+func BuiltInRole(name string) *Role {
+	for _, role := range builtInRoles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+const maxRoleNameLength = 64
+
+// NewCustomRole validates a custom role of a workspace. Custom role names cannot be the names of
+// built-in roles.
+func NewCustomRole(name string, description string, permissions []string) (*Role, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxRoleNameLength {
+		return nil, errors.Errorf("role name must be between 1 and %d characters", maxRoleNameLength)
+	}
+	for _, role := range builtInRoles {
+		if strings.EqualFold(role.Name, name) {
+			return nil, errors.Errorf("%s is a built-in role", role.Name)
+		}
+	}
+	role := &Role{Name: name, Description: strings.TrimSpace(description)}
+	for _, p := range permissions {
+		permission := Permission(p)
+		if !permission.IsValid() {
+			return nil, errors.Errorf("invalid permission %s", p)
+		}
+		if !role.Can(permission) {
+			role.Permissions = append(role.Permissions, permission)
+		}
+	}
+	return role, nil
+}
+
+// ParsePermissions returns the valid permissions of a stored custom role, ignoring permissions that
+// no longer exist.
+func ParsePermissions(permissions []string) []Permission {
+	var parsed []Permission
+	for _, p := range permissions {
+		if permission := Permission(p); permission.IsValid() {
+			parsed = append(parsed, permission)
+		}
+	}
+	return parsed
+}
+
+// Can is whether the role has the permission.
+func (r *Role) Can(permission Permission) bool {
+	if permission == PermissionView {
+		return true
+	}
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanGrant is whether an admin with the role can give the granted role to another admin, ie. by
+// inviting them with it or changing their role to it. Admins cannot grant permissions they do not
+// have, and only owners can grant the owner role.
+func (r *Role) CanGrant(granted *Role) bool {
+	if granted.Name == RoleOwner && r.Name != RoleOwner {
+		return false
+	}
+	for _, permission := range granted.Permissions {
+		if !r.Can(permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// CanManage is whether an admin with the role can change the role of, or remove, an admin with the
+// managed role. Only owners can manage owners.
+func (r *Role) CanManage(managed *Role) bool {
+	if !r.Can(PermissionManageMembers) {
+		return false
+	}
+	return managed.Name != RoleOwner || r.Name == RoleOwner
+}
@@ -0,0 +1,111 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltInRoles(t *testing.T) {
+	owner, admin, member, viewer := BuiltInRole(RoleOwner), BuiltInRole(RoleAdmin), BuiltInRole(RoleMember), BuiltInRole(RoleViewer)
+	require.NotNil(t, owner)
+	require.NotNil(t, viewer)
+	assert.Nil(t, BuiltInRole("admin"))
+
+	assert.True(t, admin.Can(PermissionDeleteSessions))
+	assert.True(t, member.Can(PermissionManageAlerts))
+	assert.False(t, member.Can(PermissionDeleteSessions))
+	assert.False(t, member.Can(PermissionManageBilling))
+	for _, permission := range Permissions {
+		assert.False(t, viewer.Can(permission), permission)
+	}
+	assert.True(t, viewer.Can(PermissionView))
+}
+
+func TestRole_CanGrant(t *testing.T) {
+	owner, admin, member, viewer := BuiltInRole(RoleOwner), BuiltInRole(RoleAdmin), BuiltInRole(RoleMember), BuiltInRole(RoleViewer)
+
+	assert.True(t, owner.CanGrant(owner))
+	assert.False(t, admin.CanGrant(owner))
+	assert.True(t, admin.CanGrant(admin))
+	assert.True(t, member.CanGrant(member))
+	assert.True(t, member.CanGrant(viewer))
+	assert.False(t, member.CanGrant(admin))
+
+	deleter, err := NewCustomRole("Deleter", "", []string{"delete_sessions"})
+	require.NoError(t, err)
+	assert.False(t, member.CanGrant(deleter))
+	assert.True(t, admin.CanGrant(deleter))
+}
+
+func TestRole_CanManage(t *testing.T) {
+	owner, admin, member := BuiltInRole(RoleOwner), BuiltInRole(RoleAdmin), BuiltInRole(RoleMember)
+
+	assert.True(t, owner.CanManage(owner))
+	assert.False(t, admin.CanManage(owner))
+	assert.True(t, admin.CanManage(admin))
+	assert.False(t, member.CanManage(member))
+
+	manager, err := NewCustomRole("Manager", "", []string{"manage_members"})
+	require.NoError(t, err)
+	assert.True(t, manager.CanManage(member))
+	assert.False(t, manager.CanManage(owner))
+}
+
+func TestNewCustomRole(t *testing.T) {
+	role, err := NewCustomRole(" Support ", "Handles support tickets", []string{"edit", "delete_sessions", "edit"})
+	require.NoError(t, err)
+	assert.Equal(t, "Support", role.Name)
+	assert.Equal(t, []Permission{PermissionEdit, PermissionDeleteSessions}, role.Permissions)
+	assert.False(t, role.BuiltIn)
+
+	_, err = NewCustomRole("admin", "", nil)
+	assert.Error(t, err)
+	_, err = NewCustomRole(" ", "", nil)
+	assert.Error(t, err)
+	_, err = NewCustomRole("Support", "", []string{"delete_workspace"})
+	assert.Error(t, err)
+}
+
+func TestParsePermissions(t *testing.T) {
+	assert.Equal(t, []Permission{PermissionEdit}, ParsePermissions([]string{"edit", "removed"}))
+}
+
+func TestMutationPermission(t *testing.T) {
+	permission, ok := MutationPermission("deleteSessions")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionDeleteSessions, permission)
+
+	permission, ok = MutationPermission("createSessionComment")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionEdit, permission)
+
+	permission, ok = MutationPermission("mergeErrorGroups")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionEdit, permission)
+
+	permission, ok = MutationPermission("refreshClickUpMetadata")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionManageIntegrations, permission)
+
+	permission, ok = MutationPermission("deleteUptimeMonitor")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionManageAlerts, permission)
+
+	permission, ok = MutationPermission("updateAlertEscalationPolicy")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionManageAlerts, permission)
+
+	// anyone who can edit may acknowledge an escalation they were paged for
+	permission, ok = MutationPermission("acknowledgeAlertEscalation")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionEdit, permission)
+
+	permission, ok = MutationPermission("deleteWorkspaceRole")
+	assert.True(t, ok)
+	assert.Equal(t, PermissionManageRoles, permission)
+
+	_, ok = MutationPermission("markSessionAsViewed")
+	assert.False(t, ok)
+}
@@ -0,0 +1,133 @@
+// Package redact scrubs personal data from telemetry attributes at ingest, according to the
+// attribute keys and value patterns configured for a project.
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	e "github.com/pkg/errors"
+)
+
+// Redacted replaces redacted values.
+const Redacted = "[REDACTED]"
+
+// Presets are named patterns that can be configured in place of a regular expression.
+var Presets = map[string]string{
+	"email":        `[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
+	"credit_card":  `\b(?:\d[ \-]?){12,15}\d\b`,
+	"ipv4":         `\b(?:\d{1,3}\.){3}\d{1,3}\b`,
+	"bearer_token": `(?i)bearer\s+[a-z0-9\-._~+/]+=*`,
+}
+
+// Redactor redacts the values of configured attribute keys and the parts of values
+// matching configured patterns.
+type Redactor struct {
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// New compiles redaction rules. Keys are matched case-insensitively against the whole attribute
+// key or its last segment, so that `authorization` also redacts `http.request.header.authorization`.
+// It returns nil when there are no rules.
+func New(keys []string, patterns []string) (*Redactor, error) {
+	if len(keys) == 0 && len(patterns) == 0 {
+		return nil, nil
+	}
+	r := &Redactor{keys: make(map[string]bool)}
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = true
+	}
+	for _, pattern := range patterns {
+		if preset, ok := Presets[pattern]; ok {
+			pattern = preset
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, e.Wrapf(err, "invalid redaction pattern %q", pattern)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+var cache sync.Map
+
+// Cached returns the redactor of the rules, compiling them once per process.
+func Cached(keys []string, patterns []string) (*Redactor, error) {
+	cacheKey := strings.Join(keys, "\x00") + "\x01" + strings.Join(patterns, "\x00")
+	if r, ok := cache.Load(cacheKey); ok {
+		return r.(*Redactor), nil
+	}
+	r, err := New(keys, patterns)
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(cacheKey, r)
+	return r, nil
+}
+
+func (r *Redactor) isRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+	if r.keys[key] {
+		return true
+	}
+	if idx := strings.LastIndex(key, "."); idx >= 0 {
+		return r.keys[key[idx+1:]]
+	}
+	return false
+}
+
+// Value returns the value of the attribute with its personal data redacted.
+func (r *Redactor) Value(key string, value string) string {
+	if r == nil {
+		return value
+	}
+	if r.isRedactedKey(key) {
+		return Redacted
+	}
+	for _, re := range r.patterns {
+		value = re.ReplaceAllString(value, Redacted)
+	}
+	return value
+}
+
+// Attributes redacts string attributes in place.
+func (r *Redactor) Attributes(attrs map[string]string) {
+	if r == nil {
+		return
+	}
+	for k, v := range attrs {
+		attrs[k] = r.Value(k, v)
+	}
+}
+
+// RawAttributes redacts the string values of raw attributes, including those of nested maps, in place.
+func (r *Redactor) RawAttributes(attrs map[string]any) {
+	if r == nil {
+		return
+	}
+	for k, v := range attrs {
+		switch value := v.(type) {
+		case string:
+			attrs[k] = r.Value(k, value)
+		case map[string]any:
+			if r.isRedactedKey(k) {
+				attrs[k] = Redacted
+			} else {
+				r.RawAttributes(value)
+			}
+		case []any:
+			if r.isRedactedKey(k) {
+				attrs[k] = Redacted
+				continue
+			}
+			for i, item := range value {
+				if s, ok := item.(string); ok {
+					value[i] = r.Value(k, s)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor(t *testing.T) {
+	r, err := New([]string{"Authorization", "cookie"}, []string{"email", `secret-\d+`})
+	assert.NoError(t, err)
+
+	attrs := map[string]string{
+		"http.request.header.authorization": "Bearer abc",
+		"Cookie":                            "session=1",
+		"user":                              "contact jane@example.com about secret-42",
+		"http.route":                        "/users",
+	}
+	r.Attributes(attrs)
+	assert.Equal(t, map[string]string{
+		"http.request.header.authorization": Redacted,
+		"Cookie":                            Redacted,
+		"user":                              "contact [REDACTED] about [REDACTED]",
+		"http.route":                        "/users",
+	}, attrs)
+
+	raw := map[string]any{
+		"headers": map[string]any{"cookie": "session=1", "accept": "*/*"},
+		"emails":  []any{"jane@example.com", 1},
+	}
+	r.RawAttributes(raw)
+	assert.Equal(t, map[string]any{
+		"headers": map[string]any{"cookie": Redacted, "accept": "*/*"},
+		"emails":  []any{Redacted, 1},
+	}, raw)
+}
+
+func TestNew(t *testing.T) {
+	r, err := New(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	assert.Equal(t, "value", r.Value("key", "value"))
+
+	_, err = New(nil, []string{"("})
+	assert.Error(t, err)
+
+	cached, err := Cached([]string{"cookie"}, nil)
+	assert.NoError(t, err)
+	again, err := Cached([]string{"cookie"}, nil)
+	assert.NoError(t, err)
+	assert.Same(t, cached, again)
+}
@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/encoding/json"
+)
+
+// Sessions that pushed a payload within this window are counted as live.
+const liveSessionWindow = time.Minute
+
+type AlertState string
+
+const (
+	AlertStateAlerting AlertState = "ALERTING"
+	AlertStateNormal   AlertState = "NORMAL"
+)
+
+// AlertStateChange is published when an alert fires or recovers.
+type AlertStateChange struct {
+	ProjectID int        `json:"project_id"`
+	AlertID   int        `json:"alert_id"`
+	AlertType string     `json:"alert_type"`
+	Title     string     `json:"title"`
+	State     AlertState `json:"state"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+func LiveErrorsChannel(projectId int) string {
+	return fmt.Sprintf("live-errors-%d", projectId)
+}
+
+func AlertStateChannel(projectId int) string {
+	return fmt.Sprintf("alert-state-%d", projectId)
+}
+
+func LiveSessionsKey(projectId int) string {
+	return fmt.Sprintf("live-sessions-%d", projectId)
+}
+
+type subscriber interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// Publish sends a json message to the subscribers of the channel on any instance.
+func (r *Client) Publish(ctx context.Context, channel string, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal pubsub message")
+	}
+	return r.Client.Publish(ctx, channel, data).Err()
+}
+
+// Subscribe returns the json messages published to the channel until the context is done.
+func Subscribe[T any](ctx context.Context, r *Client, channel string) (<-chan *T, error) {
+	s, ok := r.Client.(subscriber)
+	if !ok {
+		return nil, errors.New("redis client does not support pubsub")
+	}
+
+	pubsub := s.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, errors.Wrap(err, "failed to subscribe")
+	}
+
+	ch := make(chan *T)
+	go func() {
+		defer close(ch)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var value T
+				if err := json.Unmarshal([]byte(msg.Payload), &value); err != nil {
+					continue
+				}
+				select {
+				case ch <- &value:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (r *Client) PublishAlertStateChange(ctx context.Context, change AlertStateChange) error {
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+	return r.Publish(ctx, AlertStateChannel(change.ProjectID), change)
+}
+
+// MarkSessionLive records activity of a session so that it is included in the live session count.
+func (r *Client) MarkSessionLive(ctx context.Context, projectId int, sessionSecureId string) error {
+	key := LiveSessionsKey(projectId)
+	now := time.Now()
+	pipe := r.Client.Pipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Unix()), Member: sessionSecureId})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-liveSessionWindow).Unix(), 10))
+	pipe.Expire(ctx, key, liveSessionWindow)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *Client) GetLiveSessionCount(ctx context.Context, projectId int) (int, error) {
+	min := strconv.FormatInt(time.Now().Add(-liveSessionWindow).Unix(), 10)
+	count, err := r.Client.ZCount(ctx, LiveSessionsKey(projectId), min, "+inf").Result()
+	return int(count), err
+}
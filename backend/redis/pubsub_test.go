@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive[T any](t *testing.T, ch <-chan *T) *T {
+	select {
+	case value, ok := <-ch:
+		if !ok {
+			t.Fatal("subscription closed before receiving a message")
+		}
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+	}
+	return nil
+}
+
+func TestPublishAlertStateChange(t *testing.T) {
+	r := NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	projectID := int(time.Now().UnixNano() % 1_000_000)
+	changes, err := Subscribe[AlertStateChange](ctx, r, AlertStateChannel(projectID))
+	assert.NoError(t, err)
+
+	// messages of other projects are not received
+	assert.NoError(t, r.PublishAlertStateChange(ctx, AlertStateChange{ProjectID: projectID + 1, AlertID: 2}))
+	assert.NoError(t, r.PublishAlertStateChange(ctx, AlertStateChange{
+		ProjectID: projectID,
+		AlertID:   1,
+		AlertType: "ERRORS_ALERT",
+		Title:     "Error alert",
+		State:     AlertStateAlerting,
+	}))
+
+	change := receive(t, changes)
+	assert.Equal(t, projectID, change.ProjectID)
+	assert.Equal(t, 1, change.AlertID)
+	assert.Equal(t, "ERRORS_ALERT", change.AlertType)
+	assert.Equal(t, "Error alert", change.Title)
+	assert.Equal(t, AlertStateAlerting, change.State)
+	assert.False(t, change.Timestamp.IsZero())
+}
+
+func TestSubscribe(t *testing.T) {
+	type message struct {
+		Value string `json:"value"`
+	}
+
+	r := NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	channel := fmt.Sprintf("test-subscribe-%d", time.Now().UnixNano())
+	messages, err := Subscribe[message](ctx, r, channel)
+	assert.NoError(t, err)
+
+	// messages that cannot be decoded are skipped
+	assert.NoError(t, r.Client.Publish(ctx, channel, "not json").Err())
+	assert.NoError(t, r.Publish(ctx, channel, message{Value: "hello"}))
+	assert.Equal(t, "hello", receive(t, messages).Value)
+
+	cancel()
+	select {
+	case _, ok := <-messages:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription was not closed after the context was cancelled")
+	}
+}
+
+func TestGetLiveSessionCount(t *testing.T) {
+	r := NewClient()
+	ctx := context.Background()
+
+	projectID := int(time.Now().UnixNano() % 1_000_000)
+	defer r.Client.Del(ctx, LiveSessionsKey(projectID))
+
+	count, err := r.GetLiveSessionCount(ctx, projectID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	assert.NoError(t, r.MarkSessionLive(ctx, projectID, "a"))
+	assert.NoError(t, r.MarkSessionLive(ctx, projectID, "b"))
+	// marking a session again does not count it twice
+	assert.NoError(t, r.MarkSessionLive(ctx, projectID, "a"))
+
+	count, err = r.GetLiveSessionCount(ctx, projectID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
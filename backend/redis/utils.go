@@ -32,6 +32,28 @@ type Client struct {
 
 const LockPollInterval = 100 * time.Millisecond
 
+type DedupeKind string
+
+const (
+	DedupeKindError DedupeKind = "error"
+	DedupeKindLog   DedupeKind = "log"
+	// DedupeKindSegmentEvent dedupes the calls of Segment destinations by their message id.
+	DedupeKindSegmentEvent DedupeKind = "segment-event"
+)
+
+// DedupePeriod is how long a dedupe key is kept, covering the retries of an exporter.
+const DedupePeriod = time.Hour
+
+// LogDedupePeriod is how long the dedupe key of a log is kept. Logs are too many to keep a key
+// per row for the DedupePeriod, and otel exporters stop retrying after 5 minutes by default.
+const LogDedupePeriod = 10 * time.Minute
+
+// SampledOutRetention is how long the daily counts of the items dropped at ingest are kept.
+const SampledOutRetention = 90 * 24 * time.Hour
+
+// SSOStatePeriod is how long a user has to sign in with the identity provider of a workspace.
+const SSOStatePeriod = 10 * time.Minute
+
 var (
 	ServerAddr = os.Getenv("REDIS_EVENTS_STAGING_ENDPOINT")
 )
@@ -56,10 +78,18 @@ func BillingQuotaExceededKey(projectId int, productType model.PricingProductType
 	return fmt.Sprintf("billing-quota-exceeded-%d-%s", projectId, productType)
 }
 
+func BillingQuotaSoftCapKey(projectId int, productType model.PricingProductType) string {
+	return fmt.Sprintf("billing-quota-soft-cap-%d-%s", projectId, productType)
+}
+
 func LastLogTimestampKey(projectId int) string {
 	return fmt.Sprintf("last-log-timestamp-%d", projectId)
 }
 
+func CRMEnrichedSessionKey(projectId int) string {
+	return fmt.Sprintf("crm-enriched-session-%d", projectId)
+}
+
 func ServiceGithubErrorCountKey(serviceId int) string {
 	return fmt.Sprintf("service-github-errors-%d", serviceId)
 }
@@ -72,6 +102,27 @@ func GitHubFileErrorKey(gitHubRepo string, version string, fileName string) stri
 	return fmt.Sprintf("github-file-error-%s-%s-%s", gitHubRepo, version, fileName)
 }
 
+func ErrorGroupPayloadCountKey(errorGroupID int, hour time.Time) string {
+	return fmt.Sprintf("error-group-payloads-%d-%d", errorGroupID, hour.UTC().Truncate(time.Hour).Unix())
+}
+
+func SampledOutKey(projectID int, date time.Time) string {
+	return fmt.Sprintf("sampled-out-%d-%s", projectID, date.UTC().Format("2006-01-02"))
+}
+
+// SampledOutField names the count of the items of a product dropped at ingest for a reason.
+func SampledOutField(product string, reason string) string {
+	return fmt.Sprintf("%s:%s", product, reason)
+}
+
+func SSOStateKey(state string) string {
+	return fmt.Sprintf("sso-state-%s", state)
+}
+
+func DedupeKey(kind DedupeKind, key string) string {
+	return fmt.Sprintf("dedupe-%s-%s", kind, key)
+}
+
 func NewClient() *Client {
 	var lfu cache.LocalCache
 	// disable lfu cache locally to allow flushing cache between test-cases
@@ -513,6 +564,16 @@ func (r *Client) SetBillingQuotaExceeded(ctx context.Context, projectId int, pro
 	return r.setFlag(ctx, BillingQuotaExceededKey(projectId, productType), exceeded, 1*time.Minute)
 }
 
+// IsBillingQuotaSoftCapped is whether the project is approaching the billing limit of its workspace,
+// ie. its data is still ingested but will soon be dropped.
+func (r *Client) IsBillingQuotaSoftCapped(ctx context.Context, projectId int, productType model.PricingProductType) (*bool, error) {
+	return r.getFlagOrNil(ctx, BillingQuotaSoftCapKey(projectId, productType))
+}
+
+func (r *Client) SetBillingQuotaSoftCapped(ctx context.Context, projectId int, productType model.PricingProductType, softCapped bool) error {
+	return r.setFlag(ctx, BillingQuotaSoftCapKey(projectId, productType), softCapped, 1*time.Minute)
+}
+
 func (r *Client) GetCustomerBillingInvalid(ctx context.Context, stripeCustomerID string) (bool, error) {
 	return r.getFlag(ctx, fmt.Sprintf("billing-invalid-%s", stripeCustomerID))
 }
@@ -571,6 +632,19 @@ func (r *Client) SetLastLogTimestamp(ctx context.Context, projectId int, timesta
 	return nil
 }
 
+// GetCRMEnrichedSessionID returns the id of the last session of a project that was enriched from its CRM.
+func (r *Client) GetCRMEnrichedSessionID(ctx context.Context, projectId int) (int, error) {
+	str, err := r.getString(ctx, CRMEnrichedSessionKey(projectId))
+	if err != nil || str == "" {
+		return 0, err
+	}
+	return strconv.Atoi(str)
+}
+
+func (r *Client) SetCRMEnrichedSessionID(ctx context.Context, projectId int, sessionId int) error {
+	return set(ctx, r, CRMEnrichedSessionKey(projectId), sessionId, 7*24*time.Hour)
+}
+
 func (r *Client) SetHubspotCompanies(ctx context.Context, companies interface{}) error {
 	span, _ := util.StartSpanFromContext(ctx, "redis.cache.SetHubspotCompanies")
 	defer span.Finish()
@@ -617,6 +691,22 @@ func (r *Client) IncrementServiceErrorCount(ctx context.Context, projectId int)
 	return count, err
 }
 
+// IncrementErrorGroupPayloadCount counts the errors of an error group in the hour of a time,
+// returning the count including the error.
+func (r *Client) IncrementErrorGroupPayloadCount(ctx context.Context, errorGroupID int, when time.Time) (int64, error) {
+	key := ErrorGroupPayloadCountKey(errorGroupID, when)
+	count, err := r.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "error incrementing error group payload count in Redis")
+	}
+
+	if count == 1 {
+		r.Client.Expire(ctx, key, 2*time.Hour)
+	}
+
+	return count, nil
+}
+
 func (r *Client) ResetServiceErrorCount(ctx context.Context, projectId int) (int64, error) {
 	serviceKey := ServiceGithubErrorCountKey(projectId)
 	return r.Client.Del(ctx, serviceKey).Result()
@@ -658,3 +748,86 @@ func (r *Client) Del(ctx context.Context, key string) error {
 func (r *Client) TTL(ctx context.Context, key string) time.Duration {
 	return r.Client.TTL(ctx, key).Val()
 }
+
+// ClaimDedupeKeys marks the keys as written for the DedupePeriod (or LogDedupePeriod), returning
+// the ones that were already claimed, ie. by the messages of a retried request.
+func (r *Client) ClaimDedupeKeys(ctx context.Context, kind DedupeKind, keys []string) (map[string]bool, error) {
+	period := DedupePeriod
+	if kind == DedupeKindLog {
+		period = LogDedupePeriod
+	}
+	pipe := r.Client.Pipeline()
+	cmds := make([]*redis.BoolCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.SetNX(ctx, DedupeKey(kind, key), 1, period)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, errors.Wrap(err, "error claiming dedupe keys in Redis")
+	}
+	claimed := map[string]bool{}
+	for i, cmd := range cmds {
+		if !cmd.Val() {
+			claimed[keys[i]] = true
+		}
+	}
+	return claimed, nil
+}
+
+// ReleaseDedupeKeys releases claimed keys of rows that could not be written so that they can be retried.
+func (r *Client) ReleaseDedupeKeys(ctx context.Context, kind DedupeKind, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = DedupeKey(kind, key)
+	}
+	return errors.Wrap(r.Client.Del(ctx, redisKeys...).Err(), "error releasing dedupe keys in Redis")
+}
+
+// IncrementSampledOut adds to the daily counts of a project's items dropped at ingest,
+// keyed by a field naming the product and reason, ie. `Logs:Sample`.
+func (r *Client) IncrementSampledOut(ctx context.Context, projectID int, date time.Time, counts map[string]int64) error {
+	key := SampledOutKey(projectID, date)
+	pipe := r.Client.Pipeline()
+	for field, count := range counts {
+		pipe.HIncrBy(ctx, key, field, count)
+	}
+	pipe.Expire(ctx, key, SampledOutRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.Wrap(err, "error incrementing sampled out counts in Redis")
+	}
+	return nil
+}
+
+// GetSampledOut returns the counts of a project's items dropped at ingest on a day.
+func (r *Client) GetSampledOut(ctx context.Context, projectID int, date time.Time) (map[string]int64, error) {
+	values, err := r.Client.HGetAll(ctx, SampledOutKey(projectID, date)).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting sampled out counts from Redis")
+	}
+	counts := make(map[string]int64, len(values))
+	for field, value := range values {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid sampled out count %s", field)
+		}
+		counts[field] = count
+	}
+	return counts, nil
+}
+
+// SetSSOState stores the SAML request id or OIDC nonce of a single sign-on login for its state.
+func (r *Client) SetSSOState(ctx context.Context, state string, value string) error {
+	return set(ctx, r, SSOStateKey(state), value, SSOStatePeriod)
+}
+
+// GetDelSSOState returns and deletes the value of a single sign-on state so that the response of
+// the identity provider cannot be replayed, returning an empty value for unknown or expired states.
+func (r *Client) GetDelSSOState(ctx context.Context, state string) (string, error) {
+	value, err := r.Client.GetDel(ctx, SSOStateKey(state)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return value, errors.Wrap(err, "error getting sso state from Redis")
+}
@@ -57,3 +57,33 @@ func BenchmarkLock(b *testing.B) {
 		assert.NoError(b, err)
 	}
 }
+
+func TestClaimDedupeKeys(t *testing.T) {
+	ctx := context.Background()
+	r := NewClient()
+	defer func() {
+		assert.NoError(t, r.ReleaseDedupeKeys(ctx, DedupeKindLog, []string{"test-a", "test-b", "test-c"}))
+	}()
+
+	claimed, err := r.ClaimDedupeKeys(ctx, DedupeKindLog, []string{"test-a", "test-b"})
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	claimed, err = r.ClaimDedupeKeys(ctx, DedupeKindLog, []string{"test-b", "test-c"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"test-b": true}, claimed)
+
+	// keys are claimed per kind
+	claimed, err = r.ClaimDedupeKeys(ctx, DedupeKindError, []string{"test-a"})
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+	// the keys of logs are kept for a shorter period
+	assert.Greater(t, r.TTL(ctx, DedupeKey(DedupeKindError, "test-a")), LogDedupePeriod)
+	assert.LessOrEqual(t, r.TTL(ctx, DedupeKey(DedupeKindLog, "test-a")), LogDedupePeriod)
+	assert.NoError(t, r.ReleaseDedupeKeys(ctx, DedupeKindError, []string{"test-a"}))
+
+	assert.NoError(t, r.ReleaseDedupeKeys(ctx, DedupeKindLog, []string{"test-b"}))
+	claimed, err = r.ClaimDedupeKeys(ctx, DedupeKindLog, []string{"test-b"})
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+}
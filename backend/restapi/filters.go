@@ -0,0 +1,121 @@
+package restapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Operator compares a field of a result with the value of a filter.
+type Operator string
+
+const (
+	OperatorIs          Operator = "is"
+	OperatorIsNot       Operator = "is_not"
+	OperatorContains    Operator = "contains"
+	OperatorNotContains Operator = "not_contains"
+	OperatorMatches     Operator = "matches"
+	OperatorNotMatches  Operator = "not_matches"
+	OperatorExists      Operator = "exists"
+	OperatorNotExists   Operator = "not_exists"
+)
+
+var Operators = []Operator{
+	OperatorIs,
+	OperatorIsNot,
+	OperatorContains,
+	OperatorNotContains,
+	OperatorMatches,
+	OperatorNotMatches,
+	OperatorExists,
+	OperatorNotExists,
+}
+
+func (o Operator) IsValid() bool {
+	for _, operator := range Operators {
+		if o == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (o Operator) hasValue() bool {
+	return o != OperatorExists && o != OperatorNotExists
+}
+
+// ErrorGroupFilterFields map the fields that error groups can be filtered by to the fields of the
+// clickhouse error search rules.
+var ErrorGroupFilterFields = map[string]string{
+	"state":        "error_state",
+	"event":        "error_Event",
+	"type":         "error_Type",
+	"tag":          "error_Tag",
+	"assignee_id":  "error_assignee_id",
+	"environment":  "error-field_environment",
+	"service_name": "error-field_service_name",
+	"browser":      "error-field_browser",
+	"os_name":      "error-field_os_name",
+	"visited_url":  "error-field_visited_url",
+}
+
+// SessionFilterFields map the fields that sessions can be filtered by to the fields of the
+// clickhouse session search rules.
+var SessionFilterFields = map[string]string{
+	"identifier":      "custom_identifier",
+	"identified":      "custom_identified",
+	"environment":     "custom_environment",
+	"app_version":     "custom_app_version",
+	"city":            "custom_city",
+	"country":         "custom_country",
+	"os_name":         "custom_os_name",
+	"browser_name":    "custom_browser_name",
+	"has_errors":      "custom_has_errors",
+	"has_rage_clicks": "custom_has_rage_clicks",
+	"first_time":      "custom_first_time",
+}
+
+// FilterFieldNames returns the names of the filter fields, sorted.
+func FilterFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseFilters parses `filter` parameters to clickhouse search rules, which all must match. Filters
+// are `field:operator:value`, or `field:value` to match the value exactly, eg. `state:OPEN` or
+// `event:contains:timeout`. The exists operators take no value.
+func ParseFilters(fields map[string]string, filters []string) ([][]string, error) {
+	rules := [][]string{}
+	for _, filter := range filters {
+		name, rest, ok := strings.Cut(filter, ":")
+		if !ok {
+			return nil, errors.Errorf("invalid filter %q, filters are field:operator:value", filter)
+		}
+		field, ok := fields[name]
+		if !ok {
+			return nil, errors.Errorf("cannot filter by %s, the fields are %s", name, strings.Join(FilterFieldNames(fields), ", "))
+		}
+
+		operator, value := OperatorIs, rest
+		if op, v, ok := strings.Cut(rest, ":"); ok && Operator(op).IsValid() {
+			operator, value = Operator(op), v
+		} else if Operator(rest).IsValid() && !Operator(rest).hasValue() {
+			operator, value = Operator(rest), ""
+		}
+
+		rule := []string{field, string(operator)}
+		if operator.hasValue() {
+			if value == "" {
+				return nil, errors.Errorf("filter %q needs a value", filter)
+			}
+			rule = append(rule, value)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,37 @@
+package restapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilters(t *testing.T) {
+	rules, err := ParseFilters(ErrorGroupFilterFields, []string{
+		"state:OPEN",
+		"event:contains:timeout",
+		"visited_url:https://app.highlight.io/1/sessions",
+		"environment:not_exists",
+		"type:is_not:Backend",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"error_state", "is", "OPEN"},
+		{"error_Event", "contains", "timeout"},
+		{"error-field_visited_url", "is", "https://app.highlight.io/1/sessions"},
+		{"error-field_environment", "not_exists"},
+		{"error_Type", "is_not", "Backend"},
+	}, rules)
+
+	rules, err = ParseFilters(SessionFilterFields, nil)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestParseFilters_Invalid(t *testing.T) {
+	for _, filter := range []string{"state", "secure_id:abc", "event:contains:", "event:"} {
+		_, err := ParseFilters(ErrorGroupFilterFields, []string{filter})
+		assert.Error(t, err, filter)
+	}
+}
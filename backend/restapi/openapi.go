@@ -0,0 +1,383 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/apitoken"
+)
+
+// Endpoint is an endpoint of the REST API, that the OpenAPI spec is generated from.
+type Endpoint struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	// Scope is the scope that the API token of a request needs.
+	Scope      apitoken.Scope
+	Parameters []*Parameter
+	// Request and Response are values of the types of the request and response bodies.
+	Request  any
+	Response any
+	// RequestContentType is the content type of the request body, application/json by default.
+	RequestContentType string
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+var (
+	projectIDParameter          = &Parameter{Name: "project_id", In: "path", Required: true, Schema: &Schema{Type: "integer"}}
+	errorGroupSecureIDParameter = &Parameter{Name: "error_group_secure_id", In: "path", Required: true, Schema: &Schema{Type: "string"}}
+	startDateParameter          = &Parameter{Name: "start_date", In: "query", Description: "The start of the time range, in RFC3339 format. Defaults to a day before end_date.", Schema: &Schema{Type: "string", Format: "date-time"}}
+	endDateParameter            = &Parameter{Name: "end_date", In: "query", Description: "The end of the time range, in RFC3339 format. Defaults to now.", Schema: &Schema{Type: "string", Format: "date-time"}}
+	pageParameter               = &Parameter{Name: "page", In: "query", Description: "The page of results, starting at 1.", Schema: &Schema{Type: "integer", Minimum: 1}}
+	countParameter              = &Parameter{Name: "count", In: "query", Description: fmt.Sprintf("The number of results per page, at most %d.", MaxCount), Schema: &Schema{Type: "integer", Minimum: 1, Maximum: MaxCount}}
+	versionParameter            = &Parameter{Name: "version", In: "query", Description: "The release version. Defaults to no version.", Schema: &Schema{Type: "string"}}
+	cursorParameter             = &Parameter{Name: "cursor", In: "query", Description: "The next_cursor of the previous page.", Schema: &Schema{Type: "string"}}
+	serviceNameParameter        = &Parameter{Name: "service_name", In: "query", Description: "The service of the releases. Defaults to all services.", Schema: &Schema{Type: "string"}}
+	errorGroupSortParameter     = &Parameter{Name: "sort", In: "query", Description: fmt.Sprintf("The order of the error groups, either `%s` or `%s`, which ranks them by the users and sessions that their errors affected in the time range. Defaults to `%s`.", ErrorGroupSortUpdatedAt, ErrorGroupSortImpact, ErrorGroupSortUpdatedAt), Schema: &Schema{Type: "string"}}
+	resolutionParameter         = &Parameter{Name: "resolution_minutes", In: "query", Description: fmt.Sprintf("The minutes of each bucket, at most %d buckets per time range. Defaults to %d.", MaxTrendBuckets, DefaultResolutionMinutes), Schema: &Schema{Type: "integer", Minimum: 1}}
+	queryParameter              = &Parameter{Name: "query", In: "query", Description: "A search query in the syntax of the search bar, eg. `level:error service_name:api`.", Schema: &Schema{Type: "string"}}
+)
+
+func filterParameter(fields map[string]string) *Parameter {
+	operators := make([]string, 0, len(Operators))
+	for _, operator := range Operators {
+		operators = append(operators, string(operator))
+	}
+	return &Parameter{
+		Name: "filter",
+		In:   "query",
+		Description: fmt.Sprintf("Filters that all must match, as `field:operator:value` or `field:value`. The fields are %s, and the operators are %s.",
+			strings.Join(FilterFieldNames(fields), ", "), strings.Join(operators, ", ")),
+		Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}},
+	}
+}
+
+// Endpoints are the endpoints of the REST API, relative to BasePath.
+var Endpoints = []*Endpoint{
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects",
+		OperationID: "listProjects",
+		Summary:     "List the projects that the API token can access.",
+		Scope:       apitoken.ScopeProjectsRead,
+		Response:    []Project{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/errors",
+		OperationID: "listErrorGroups",
+		Summary:     "List the error groups of a project with errors in the time range, most recently updated or highest impact first.",
+		Scope:       apitoken.ScopeErrorsRead,
+		Parameters:  []*Parameter{projectIDParameter, startDateParameter, endDateParameter, filterParameter(ErrorGroupFilterFields), errorGroupSortParameter, pageParameter, countParameter},
+		Response:    Page[ErrorGroup]{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/errors/{error_group_secure_id}",
+		OperationID: "getErrorGroup",
+		Summary:     "Get an error group of a project.",
+		Scope:       apitoken.ScopeErrorsRead,
+		Parameters:  []*Parameter{projectIDParameter, errorGroupSecureIDParameter},
+		Response:    ErrorGroup{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/errors/{error_group_secure_id}/trends",
+		OperationID: "getErrorGroupTrends",
+		Summary:     "Get the occurrences, affected users and affected sessions of an error group of a project over the time range.",
+		Scope:       apitoken.ScopeErrorsRead,
+		Parameters:  []*Parameter{projectIDParameter, errorGroupSecureIDParameter, startDateParameter, endDateParameter, resolutionParameter},
+		Response:    ErrorGroupTrends{},
+	},
+	{
+		Method:      http.MethodPut,
+		Path:        "/projects/{project_id}/errors/{error_group_secure_id}/state",
+		OperationID: "updateErrorGroupState",
+		Summary:     "Resolve, ignore or reopen an error group of a project.",
+		Scope:       apitoken.ScopeErrorsWrite,
+		Parameters:  []*Parameter{projectIDParameter, errorGroupSecureIDParameter},
+		Request:     ErrorGroupStateInput{},
+		Response:    ErrorGroup{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/sessions",
+		OperationID: "listSessions",
+		Summary:     "List the sessions of a project created in the time range, newest first.",
+		Scope:       apitoken.ScopeSessionsRead,
+		Parameters:  []*Parameter{projectIDParameter, startDateParameter, endDateParameter, filterParameter(SessionFilterFields), pageParameter, countParameter},
+		Response:    Page[Session]{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/logs/search",
+		OperationID: "searchLogs",
+		Summary:     "Search the logs of a project in the time range, newest first.",
+		Scope:       apitoken.ScopeLogsRead,
+		Parameters:  []*Parameter{projectIDParameter, queryParameter, startDateParameter, endDateParameter, cursorParameter},
+		Response:    CursorPage[Log]{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/traces/search",
+		OperationID: "searchTraces",
+		Summary:     "Search the spans of the traces of a project in the time range, newest first.",
+		Scope:       apitoken.ScopeTracesRead,
+		Parameters:  []*Parameter{projectIDParameter, queryParameter, startDateParameter, endDateParameter, cursorParameter},
+		Response:    CursorPage[Trace]{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/sourcemaps",
+		OperationID: "listSourcemaps",
+		Summary:     "List the sourcemaps uploaded for a release version of a project.",
+		Scope:       apitoken.ScopeSourcemapsRead,
+		Parameters:  []*Parameter{projectIDParameter, versionParameter},
+		Response:    []SourcemapArtifact{},
+	},
+	{
+		Method:             http.MethodPost,
+		Path:               "/projects/{project_id}/sourcemaps",
+		OperationID:        "uploadSourcemaps",
+		Summary:            "Upload the minified files and sourcemaps of a release version of a project, which replace fetching them from their URLs to enhance the stack traces of errors of the version.",
+		Scope:              apitoken.ScopeSourcemapsWrite,
+		Parameters:         []*Parameter{projectIDParameter},
+		Request:            SourcemapUploadInput{},
+		RequestContentType: multipartContentType,
+		Response:           []SourcemapArtifact{},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/projects/{project_id}/releases",
+		OperationID: "listReleases",
+		Summary:     "List the releases of a project, which are the service versions that reported telemetry or were deployed, newest first.",
+		Scope:       apitoken.ScopeReleasesRead,
+		Parameters:  []*Parameter{projectIDParameter, serviceNameParameter, countParameter},
+		Response:    []Release{},
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/projects/{project_id}/releases",
+		OperationID: "createDeploy",
+		Summary:     "Record the deploy of a service version of a project, so that errors that regress in the version are pinned to it.",
+		Scope:       apitoken.ScopeReleasesWrite,
+		Parameters:  []*Parameter{projectIDParameter},
+		Request:     DeployInput{},
+		Response:    Release{},
+	},
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              int                `json:"minimum,omitempty"`
+	Maximum              int                `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       *Info                            `json:"info"`
+	Servers    []*Server                        `json:"servers"`
+	Security   []map[string][]string            `json:"security"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components *Components                      `json:"components"`
+}
+
+const (
+	jsonContentType      = "application/json"
+	multipartContentType = "multipart/form-data"
+)
+
+// Spec generates the OpenAPI spec of the REST API from its endpoints and the types of their bodies.
+func Spec() *Document {
+	schemas := map[string]*Schema{}
+	errorSchema := schemaOf(reflect.TypeOf(Error{}), schemas)
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: &Info{
+			Title:       "Highlight REST API",
+			Description: "Requests are authenticated with an `Authorization: Bearer` API token, which needs the scope of each endpoint.",
+			Version:     Version,
+		},
+		Servers:  []*Server{{URL: BasePath}},
+		Security: []map[string][]string{{"apiToken": {}}},
+		Paths:    map[string]map[string]*Operation{},
+		Components: &Components{
+			Schemas:         schemas,
+			SecuritySchemes: map[string]*SecurityScheme{"apiToken": {Type: "http", Scheme: "bearer"}},
+		},
+	}
+	for _, endpoint := range Endpoints {
+		operation := &Operation{
+			OperationID: endpoint.OperationID,
+			Summary:     endpoint.Summary,
+			Description: fmt.Sprintf("Requires the `%s` scope.", endpoint.Scope),
+			Parameters:  endpoint.Parameters,
+			Responses: map[string]*Response{
+				"200": {
+					Description: "OK",
+					Content:     map[string]*MediaType{jsonContentType: {Schema: schemaOf(reflect.TypeOf(endpoint.Response), schemas)}},
+				},
+				"default": {
+					Description: "Error",
+					Content:     map[string]*MediaType{jsonContentType: {Schema: errorSchema}},
+				},
+			},
+		}
+		if endpoint.Request != nil {
+			contentType := endpoint.RequestContentType
+			if contentType == "" {
+				contentType = jsonContentType
+			}
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{contentType: {Schema: schemaOf(reflect.TypeOf(endpoint.Request), schemas)}},
+			}
+		}
+		if doc.Paths[endpoint.Path] == nil {
+			doc.Paths[endpoint.Path] = map[string]*Operation{}
+		}
+		doc.Paths[endpoint.Path][strings.ToLower(endpoint.Method)] = operation
+	}
+	return doc
+}
+
+// schemaName is the name of the schema of a struct. The schemas of generic types are named after
+// their type argument, eg. `ErrorGroupPage` for `Page[ErrorGroup]`.
+func schemaName(t reflect.Type) string {
+	name, arg, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return name
+	}
+	arg = strings.TrimSuffix(arg, "]")
+	arg = arg[strings.LastIndex(arg, ".")+1:]
+	return arg + name
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	fileType = reflect.TypeOf(File{})
+)
+
+// schemaOf returns the schema of a type, adding the schemas of structs to the components.
+func schemaOf(t reflect.Type, schemas map[string]*Schema) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t == fileType {
+		return &Schema{Type: "string", Format: "binary"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := schemaOf(t.Elem(), schemas)
+		if schema.Ref != "" {
+			// the siblings of a $ref are ignored, so a nullable reference is wrapped
+			return &Schema{Nullable: true, AllOf: []*Schema{schema}}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), schemas)}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		name := schemaName(t)
+		ref := &Schema{Ref: "#/components/schemas/" + name}
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		schemas[name] = schema
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || jsonName == "-" {
+				continue
+			}
+			if jsonName == "" {
+				jsonName = field.Name
+			}
+			schema.Properties[jsonName] = schemaOf(field.Type, schemas)
+			if field.Type.Kind() != reflect.Pointer {
+				schema.Required = append(schema.Required, jsonName)
+			}
+		}
+		return ref
+	}
+	panic(fmt.Sprintf("no openapi schema for %s", t))
+}
@@ -0,0 +1,64 @@
+package restapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec(t *testing.T) {
+	spec := Spec()
+	assert.Equal(t, BasePath, spec.Servers[0].URL)
+
+	for _, endpoint := range Endpoints {
+		operation := spec.Paths[endpoint.Path][map[string]string{"GET": "get", "PUT": "put", "POST": "post"}[endpoint.Method]]
+		require.NotNil(t, operation, endpoint.Path)
+		assert.Contains(t, operation.Description, string(endpoint.Scope))
+	}
+
+	errorGroups := spec.Paths["/projects/{project_id}/errors"]["get"]
+	assert.Equal(t, "#/components/schemas/ErrorGroupPage", errorGroups.Responses["200"].Content[jsonContentType].Schema.Ref)
+	page := spec.Components.Schemas["ErrorGroupPage"]
+	require.NotNil(t, page)
+	assert.Equal(t, "#/components/schemas/ErrorGroup", page.Properties["data"].Items.Ref)
+	assert.True(t, page.Properties["next_page"].Nullable)
+	assert.NotContains(t, page.Required, "next_page")
+
+	errorGroup := spec.Components.Schemas["ErrorGroup"]
+	require.NotNil(t, errorGroup)
+	assert.Equal(t, "date-time", errorGroup.Properties["created_at"].Format)
+	assert.Equal(t, "array", errorGroup.Properties["environments"].Type)
+	assert.Equal(t, "#/components/schemas/SuspectCommit", errorGroup.Properties["suspect_commit"].AllOf[0].Ref)
+	assert.True(t, errorGroup.Properties["impact"].Nullable)
+
+	trends := spec.Paths["/projects/{project_id}/errors/{error_group_secure_id}/trends"]["get"]
+	assert.Equal(t, "#/components/schemas/ErrorGroupTrends", trends.Responses["200"].Content[jsonContentType].Schema.Ref)
+	bucket := spec.Components.Schemas["ErrorGroupTrendBucket"]
+	require.NotNil(t, bucket)
+	assert.Equal(t, "date-time", bucket.Properties["date"].Format)
+	assert.Equal(t, "number", spec.Components.Schemas["ErrorGroupImpact"].Properties["impact_score"].Type)
+
+	log := spec.Components.Schemas["Log"]
+	require.NotNil(t, log)
+	assert.Equal(t, "object", log.Properties["attributes"].Type)
+
+	updateState := spec.Paths["/projects/{project_id}/errors/{error_group_secure_id}/state"]["put"]
+	assert.Equal(t, "#/components/schemas/ErrorGroupStateInput", updateState.RequestBody.Content[jsonContentType].Schema.Ref)
+
+	uploadSourcemaps := spec.Paths["/projects/{project_id}/sourcemaps"]["post"]
+	upload := spec.Components.Schemas["SourcemapUploadInput"]
+	require.NotNil(t, upload)
+	assert.Equal(t, "#/components/schemas/SourcemapUploadInput", uploadSourcemaps.RequestBody.Content[multipartContentType].Schema.Ref)
+	assert.Equal(t, "binary", upload.Properties["file"].Items.Format)
+
+	createDeploy := spec.Paths["/projects/{project_id}/releases"]["post"]
+	assert.Equal(t, "#/components/schemas/DeployInput", createDeploy.RequestBody.Content[jsonContentType].Schema.Ref)
+	deploy := spec.Components.Schemas["DeployInput"]
+	require.NotNil(t, deploy)
+	assert.ElementsMatch(t, []string{"service_name", "version"}, deploy.Required)
+
+	_, err := json.Marshal(spec)
+	assert.NoError(t, err)
+}
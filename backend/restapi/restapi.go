@@ -0,0 +1,230 @@
+package restapi
+
+import "time"
+
+// The types in this package are the stable representations of highlight data in the versioned REST
+// API, which do not change when the private graph schema does.
+
+// Version is the version in the path of every endpoint of the REST API.
+const Version = "v1"
+
+// BasePath is the path that the endpoints of the REST API are relative to.
+const BasePath = "/api/" + Version
+
+const (
+	// DefaultLookback is the time range of requests without a start date.
+	DefaultLookback = 24 * time.Hour
+	DefaultCount    = 25
+	MaxCount        = 100
+	// DefaultResolutionMinutes is the size of the buckets of trends without a resolution.
+	DefaultResolutionMinutes = 60
+	MaxTrendBuckets          = 1000
+)
+
+// The orders that error groups can be listed in.
+const (
+	ErrorGroupSortUpdatedAt = "updated_at"
+	ErrorGroupSortImpact    = "impact"
+)
+
+type Project struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	WorkspaceID int    `json:"workspace_id"`
+}
+
+type ErrorGroup struct {
+	SecureID        string     `json:"secure_id"`
+	ProjectID       int        `json:"project_id"`
+	Type            string     `json:"type"`
+	Event           string     `json:"event"`
+	State           string     `json:"state"`
+	SnoozedUntil    *time.Time `json:"snoozed_until"`
+	Resolution      string     `json:"resolution"` // one of open, resolved, resolved_in_version, ignored or snoozed
+	ResolvedVersion *string    `json:"resolved_version"`
+	// FirstSeenVersion and LastSeenVersion are the oldest and newest service versions of its errors.
+	FirstSeenVersion *string   `json:"first_seen_version"`
+	LastSeenVersion  *string   `json:"last_seen_version"`
+	ServiceName      string    `json:"service_name"`
+	AssigneeID       *int      `json:"assignee_id"`
+	Environments     []string  `json:"environments"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	URL              string    `json:"url"`
+	// SuspectCommit is the commit that most likely introduced the error group, suggested when it is
+	// first seen in a release of a service with a repository.
+	SuspectCommit *SuspectCommit `json:"suspect_commit"`
+	// Impact is the impact of the error group in the time range, set when listing error groups by impact.
+	Impact *ErrorGroupImpact `json:"impact"`
+}
+
+// ErrorGroupImpact is how many times an error group occurred in a time range, and how many users and
+// sessions its errors affected.
+type ErrorGroupImpact struct {
+	Occurrences      int64 `json:"occurrences"`
+	AffectedUsers    int64 `json:"affected_users"`
+	AffectedSessions int64 `json:"affected_sessions"`
+	// ImpactScore ranks error groups by the users and sessions they affect, which the occurrences
+	// only add to logarithmically.
+	ImpactScore float64 `json:"impact_score"`
+}
+
+// ErrorGroupTrends are the impact of an error group in a time range and in buckets of the time range.
+type ErrorGroupTrends struct {
+	Impact  ErrorGroupImpact        `json:"impact"`
+	Buckets []ErrorGroupTrendBucket `json:"buckets"`
+}
+
+type ErrorGroupTrendBucket struct {
+	Date             time.Time `json:"date"`
+	Occurrences      int64     `json:"occurrences"`
+	AffectedUsers    int64     `json:"affected_users"`
+	AffectedSessions int64     `json:"affected_sessions"`
+}
+
+type SuspectCommit struct {
+	SHA         string `json:"sha"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	URL         string `json:"url"`
+	// Files are the files of the stack trace that the commit changed.
+	Files []string `json:"files"`
+}
+
+type Session struct {
+	SecureID       string    `json:"secure_id"`
+	ProjectID      int       `json:"project_id"`
+	Identifier     string    `json:"identifier"`
+	Identified     bool      `json:"identified"`
+	CreatedAt      time.Time `json:"created_at"`
+	LengthMs       int64     `json:"length_ms"`
+	ActiveLengthMs int64     `json:"active_length_ms"`
+	City           string    `json:"city"`
+	Country        string    `json:"country"`
+	OSName         string    `json:"os_name"`
+	BrowserName    string    `json:"browser_name"`
+	Environment    string    `json:"environment"`
+	AppVersion     *string   `json:"app_version"`
+	HasErrors      bool      `json:"has_errors"`
+	HasRageClicks  bool      `json:"has_rage_clicks"`
+	URL            string    `json:"url"`
+}
+
+type Log struct {
+	Timestamp       time.Time              `json:"timestamp"`
+	Level           string                 `json:"level"`
+	Message         string                 `json:"message"`
+	ServiceName     *string                `json:"service_name"`
+	Environment     *string                `json:"environment"`
+	TraceID         *string                `json:"trace_id"`
+	SpanID          *string                `json:"span_id"`
+	SecureSessionID *string                `json:"secure_session_id"`
+	Attributes      map[string]interface{} `json:"attributes"`
+}
+
+type Trace struct {
+	Timestamp       time.Time              `json:"timestamp"`
+	TraceID         string                 `json:"trace_id"`
+	SpanID          string                 `json:"span_id"`
+	ParentSpanID    string                 `json:"parent_span_id"`
+	SpanName        string                 `json:"span_name"`
+	DurationNs      int                    `json:"duration_ns"`
+	ServiceName     string                 `json:"service_name"`
+	Environment     string                 `json:"environment"`
+	StatusCode      string                 `json:"status_code"`
+	SecureSessionID string                 `json:"secure_session_id"`
+	Attributes      map[string]interface{} `json:"attributes"`
+}
+
+// Page is a page of results that are paginated by page number.
+type Page[T any] struct {
+	Data       []T   `json:"data"`
+	Page       int   `json:"page"`
+	TotalCount int64 `json:"total_count"`
+	// NextPage is the number of the next page, or null on the last page.
+	NextPage *int `json:"next_page"`
+}
+
+// NewPage returns the page of results, which has a next page when there are more results than in
+// this and the previous pages.
+func NewPage[T any](data []T, page int, count int, totalCount int64) *Page[T] {
+	p := &Page[T]{Data: data, Page: page, TotalCount: totalCount}
+	if int64(page*count) < totalCount {
+		next := page + 1
+		p.NextPage = &next
+	}
+	if p.Data == nil {
+		p.Data = []T{}
+	}
+	return p
+}
+
+// CursorPage is a page of results that are paginated by cursor.
+type CursorPage[T any] struct {
+	Data []T `json:"data"`
+	// NextCursor is the `cursor` parameter of the next page, or null on the last page.
+	NextCursor *string `json:"next_cursor"`
+}
+
+type ErrorGroupStateInput struct {
+	// State is one of OPEN, RESOLVED or IGNORED.
+	State string `json:"state"`
+	// SnoozedUntil ignores an open error group until the time.
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+	// ResolvedVersion resolves an error group in a service version, so that it is only reopened by
+	// errors of that version or a newer one.
+	ResolvedVersion *string `json:"resolved_version"`
+}
+
+// File is a file of a multipart request body.
+type File []byte
+
+type SourcemapUploadInput struct {
+	// Version is the release version of the sourcemaps, which is the service version of their
+	// errors. Sourcemaps without a version are used for errors without one.
+	Version *string `json:"version"`
+	// Files are the minified files and their sourcemaps, with file names that are their paths
+	// relative to where they are served from, such as `assets/index.js.map`. A file can have a
+	// `Content-SHA256` header with its hex encoded SHA-256 hash to verify the upload.
+	Files []File `json:"file"`
+}
+
+type SourcemapArtifact struct {
+	Version   string    `json:"version"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Release struct {
+	ServiceName string     `json:"service_name"`
+	Version     string     `json:"version"`
+	FirstSeenAt *time.Time `json:"first_seen_at"`
+	DeployedAt  *time.Time `json:"deployed_at"`
+	Environment *string    `json:"environment"`
+	CommitSHA   *string    `json:"commit_sha"`
+	URL         *string    `json:"url"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// DeployInput is the deploy of a service version that a CI pipeline reports, which errors that
+// regress in the version are pinned to.
+type DeployInput struct {
+	ServiceName string `json:"service_name"`
+	// Version is the service version that was deployed, which is the service.version resource
+	// attribute of its telemetry.
+	Version     string  `json:"version"`
+	Environment *string `json:"environment"`
+	// CommitSHA is the commit that was deployed.
+	CommitSHA *string `json:"commit_sha"`
+	// URL links to the deploy, such as the run of its pipeline.
+	URL *string `json:"url"`
+	// DeployedAt is when the version was deployed, which defaults to when the request is received.
+	DeployedAt *time.Time `json:"deployed_at"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,21 @@
+package restapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPage(t *testing.T) {
+	page := NewPage([]int{1, 2}, 1, 2, 5)
+	require.NotNil(t, page.NextPage)
+	assert.Equal(t, 2, *page.NextPage)
+
+	page = NewPage([]int{5}, 3, 2, 5)
+	assert.Nil(t, page.NextPage)
+
+	page = NewPage[int](nil, 1, 2, 0)
+	assert.Equal(t, []int{}, page.Data)
+	assert.Nil(t, page.NextPage)
+}
@@ -10,10 +10,16 @@ import (
 type Referrer string
 
 const (
-	Discord Referrer = "discord"
-	Email   Referrer = "email"
-	Slack   Referrer = "slack"
-	Webhook Referrer = "webhook"
+	Discord        Referrer = "discord"
+	Email          Referrer = "email"
+	Intercom       Referrer = "intercom"
+	MicrosoftTeams Referrer = "microsoft_teams"
+	Opsgenie       Referrer = "opsgenie"
+	PagerDuty      Referrer = "pagerduty"
+	Slack          Referrer = "slack"
+	SplunkOnCall   Referrer = "splunkoncall"
+	Webhook        Referrer = "webhook"
+	Zendesk        Referrer = "zendesk"
 )
 
 func AttachReferrer(ctx context.Context, u string, referrer Referrer) string {
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	e "github.com/pkg/errors"
+)
+
+func clickhouseHealth(ctx context.Context, _ []string) error {
+	client, err := clickhouse.NewClient(clickhouse.PrimaryDatabase)
+	if err != nil {
+		return e.Wrap(err, "failed to connect to clickhouse")
+	}
+
+	report, err := client.GetHealthReport(ctx)
+	if err != nil {
+		return e.Wrap(err, "failed to query clickhouse health")
+	}
+
+	fmt.Printf("version: %s\nuptime: %s\n", report.Version, time.Duration(report.UptimeSeconds)*time.Second)
+	fmt.Printf("pending mutations: %d (%d failing)\n\n", report.PendingMutations, report.FailedMutations)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS\tSIZE (MiB)\tACTIVE PARTS")
+	for _, table := range report.Tables {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\n", table.Name, table.Rows, float64(table.BytesOnDisk)/1024/1024, table.ActiveParts)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if report.FailedMutations > 0 {
+		return e.Errorf("%d mutations are failing, see system.mutations", report.FailedMutations)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+// credentialCheck is a cheap authenticated request that only succeeds with a valid token.
+type credentialCheck struct {
+	method string
+	url    string
+	body   string
+	header func(token string) string
+	// validate inspects the response for apis that report auth failures with a 200
+	validate func(body []byte) error
+}
+
+func bearer(token string) string {
+	return "Bearer " + token
+}
+
+var credentialChecks = map[modelInputs.IntegrationType]credentialCheck{
+	modelInputs.IntegrationTypeSlack: {
+		method: http.MethodPost,
+		url:    "https://slack.com/api/auth.test",
+		header: bearer,
+		validate: func(body []byte) error {
+			var resp struct {
+				Ok    bool   `json:"ok"`
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return err
+			}
+			if !resp.Ok {
+				return e.New(resp.Error)
+			}
+			return nil
+		},
+	},
+	modelInputs.IntegrationTypeLinear: {
+		method: http.MethodPost,
+		url:    "https://api.linear.app/graphql",
+		body:   `{"query": "{ viewer { id } }"}`,
+		header: bearer,
+	},
+	modelInputs.IntegrationTypeVercel: {method: http.MethodGet, url: "https://api.vercel.com/v2/user", header: bearer},
+	modelInputs.IntegrationTypeClickUp: {
+		method: http.MethodGet,
+		url:    "https://api.clickup.com/api/v2/user",
+		header: func(token string) string { return token },
+	},
+	modelInputs.IntegrationTypeFront:  {method: http.MethodGet, url: "https://api2.frontapp.com/me", header: bearer},
+	modelInputs.IntegrationTypeHeight: {method: http.MethodGet, url: "https://api.height.app/users/me", header: bearer},
+	modelInputs.IntegrationTypeJira:   {method: http.MethodGet, url: "https://api.atlassian.com/oauth/token/accessible-resources", header: bearer},
+	modelInputs.IntegrationTypeGitLab: {method: http.MethodGet, url: "https://gitlab.com/api/v4/user", header: bearer},
+}
+
+func (check credentialCheck) run(ctx context.Context, client *http.Client, token string) error {
+	req, err := http.NewRequestWithContext(ctx, check.method, check.url, bytes.NewBufferString(check.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", check.header(token))
+	if check.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return e.Errorf("unexpected response %s", resp.Status)
+	}
+	if check.validate != nil {
+		return check.validate(body)
+	}
+	return nil
+}
+
+func validateIntegrations(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("validate-integrations", flag.ExitOnError)
+	workspaceID := flags.Int("workspace", 0, "id of the workspace to validate")
+	_ = flags.Parse(args)
+
+	if *workspaceID == 0 {
+		return e.New("-workspace is required")
+	}
+
+	db, err := model.SetupDB(ctx, os.Getenv("PSQL_DB"))
+	if err != nil {
+		return e.Wrap(err, "failed to connect to postgres")
+	}
+
+	var workspace model.Workspace
+	if err := db.WithContext(ctx).Where(&model.Workspace{Model: model.Model{ID: *workspaceID}}).Take(&workspace).Error; err != nil {
+		return e.Wrap(err, "failed to find workspace")
+	}
+
+	tokens := map[modelInputs.IntegrationType]string{}
+	expiries := map[modelInputs.IntegrationType]time.Time{}
+	for integrationType, token := range map[modelInputs.IntegrationType]*string{
+		modelInputs.IntegrationTypeSlack:   workspace.SlackAccessToken,
+		modelInputs.IntegrationTypeLinear:  workspace.LinearAccessToken,
+		modelInputs.IntegrationTypeVercel:  workspace.VercelAccessToken,
+		modelInputs.IntegrationTypeClickUp: workspace.ClickupAccessToken,
+	} {
+		if token != nil && *token != "" {
+			tokens[integrationType] = *token
+		}
+	}
+
+	var mappings []*model.IntegrationWorkspaceMapping
+	if err := db.WithContext(ctx).Where(&model.IntegrationWorkspaceMapping{WorkspaceID: workspace.ID}).Find(&mappings).Error; err != nil {
+		return e.Wrap(err, "failed to query workspace integrations")
+	}
+	for _, mapping := range mappings {
+		tokens[mapping.IntegrationType] = mapping.AccessToken
+		expiries[mapping.IntegrationType] = mapping.Expiry
+	}
+
+	if len(tokens) == 0 {
+		fmt.Println("no integrations configured")
+		return nil
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	failed := 0
+	for _, integrationType := range modelInputs.AllIntegrationType {
+		token, ok := tokens[integrationType]
+		if !ok {
+			continue
+		}
+		if expiry, ok := expiries[integrationType]; ok && !expiry.IsZero() && expiry.Before(time.Now()) {
+			fmt.Printf("%-10s token expired at %s, it is refreshed on next use\n", integrationType, expiry.Format(time.RFC3339))
+		}
+
+		check, ok := credentialChecks[integrationType]
+		if !ok {
+			fmt.Printf("%-10s configured, no credential check available\n", integrationType)
+			continue
+		}
+		if err := check.run(ctx, client, token); err != nil {
+			failed++
+			fmt.Printf("%-10s INVALID: %s\n", integrationType, err)
+			continue
+		}
+		fmt.Printf("%-10s ok\n", integrationType)
+	}
+
+	if failed > 0 {
+		return e.Errorf("%d integrations have invalid credentials", failed)
+	}
+	return nil
+}
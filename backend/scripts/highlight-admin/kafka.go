@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/samber/lo"
+	"github.com/segmentio/kafka-go"
+)
+
+var payloadTypeNames = map[kafkaqueue.PayloadType]string{
+	kafkaqueue.PushPayload:                 "PushPayload",
+	kafkaqueue.InitializeSession:           "InitializeSession",
+	kafkaqueue.IdentifySession:             "IdentifySession",
+	kafkaqueue.AddTrackProperties:          "AddTrackProperties",
+	kafkaqueue.AddSessionProperties:        "AddSessionProperties",
+	kafkaqueue.PushBackendPayload:          "PushBackendPayload",
+	kafkaqueue.PushMetrics:                 "PushMetrics",
+	kafkaqueue.AddSessionFeedback:          "AddSessionFeedback",
+	kafkaqueue.PushLogs:                    "PushLogs",
+	kafkaqueue.PushTraces:                  "PushTraces",
+	kafkaqueue.SessionDataSync:             "SessionDataSync",
+	kafkaqueue.ErrorGroupDataSync:          "ErrorGroupDataSync",
+	kafkaqueue.ErrorObjectDataSync:         "ErrorObjectDataSync",
+	kafkaqueue.PushCompressedPayload:       "PushCompressedPayload",
+	kafkaqueue.PushHeartbeatCheckIn:        "PushHeartbeatCheckIn",
+	kafkaqueue.PushMobileCrash:             "PushMobileCrash",
+	kafkaqueue.PushWebVital:                "PushWebVital",
+	kafkaqueue.PushOTeLMetrics:             "PushOTeLMetrics",
+	kafkaqueue.PushSessionNetworkResources: "PushSessionNetworkResources",
+	kafkaqueue.PushSegmentEvent:            "PushSegmentEvent",
+	kafkaqueue.HealthCheck:                 "HealthCheck",
+}
+
+// summarizeMessage describes a message by its type and the ids needed to find the related data.
+func summarizeMessage(msg *kafkaqueue.Message) string {
+	name, ok := payloadTypeNames[msg.Type]
+	if !ok {
+		name = fmt.Sprintf("Unknown(%d)", msg.Type)
+	}
+
+	var details []string
+	switch {
+	case msg.PushPayload != nil:
+		details = append(details, "session="+msg.PushPayload.SessionSecureID, fmt.Sprintf("events=%d", len(msg.PushPayload.Events.Events)), fmt.Sprintf("errors=%d", len(msg.PushPayload.Errors)))
+	case msg.PushCompressedPayload != nil:
+		details = append(details, "session="+msg.PushCompressedPayload.SessionSecureID, fmt.Sprintf("payload_id=%d", msg.PushCompressedPayload.PayloadID))
+	case msg.InitializeSession != nil:
+		details = append(details, "session="+msg.InitializeSession.SessionSecureID, "project="+msg.InitializeSession.ProjectVerboseID)
+	case msg.IdentifySession != nil:
+		details = append(details, "session="+msg.IdentifySession.SessionSecureID, "identifier="+msg.IdentifySession.UserIdentifier)
+	case msg.AddSessionProperties != nil:
+		details = append(details, "session="+msg.AddSessionProperties.SessionSecureID)
+	case msg.PushBackendPayload != nil:
+		details = append(details, "project="+lo.FromPtr(msg.PushBackendPayload.ProjectVerboseID), fmt.Sprintf("errors=%d", len(msg.PushBackendPayload.Errors)))
+	case msg.PushMetrics != nil:
+		details = append(details, "session="+lo.FromPtr(msg.PushMetrics.SessionSecureID), fmt.Sprintf("metrics=%d", len(msg.PushMetrics.Metrics)))
+	case msg.AddSessionFeedback != nil:
+		details = append(details, "session="+msg.AddSessionFeedback.SessionSecureID)
+	case msg.PushLogs != nil && msg.PushLogs.LogRow != nil:
+		details = append(details, fmt.Sprintf("project=%d", msg.PushLogs.LogRow.ProjectId), "service="+msg.PushLogs.LogRow.ServiceName, "severity="+msg.PushLogs.LogRow.SeverityText)
+	case msg.PushTraces != nil && msg.PushTraces.TraceRow != nil:
+		details = append(details, fmt.Sprintf("project=%d", msg.PushTraces.TraceRow.ProjectId), "service="+msg.PushTraces.TraceRow.ServiceName, "span="+msg.PushTraces.TraceRow.SpanName)
+	case msg.SessionDataSync != nil:
+		details = append(details, fmt.Sprintf("session_id=%d", msg.SessionDataSync.SessionID))
+	case msg.ErrorGroupDataSync != nil:
+		details = append(details, fmt.Sprintf("error_group_id=%d", msg.ErrorGroupDataSync.ErrorGroupID))
+	case msg.ErrorObjectDataSync != nil:
+		details = append(details, fmt.Sprintf("error_object_id=%d", msg.ErrorObjectDataSync.ErrorObjectID))
+	case msg.PushHeartbeatCheckIn != nil && msg.PushHeartbeatCheckIn.CheckInRow != nil:
+		details = append(details, fmt.Sprintf("monitor_id=%d", msg.PushHeartbeatCheckIn.CheckInRow.MonitorId))
+	case msg.PushMobileCrash != nil:
+		details = append(details, "project="+msg.PushMobileCrash.ProjectVerboseID)
+	case msg.PushWebVital != nil && msg.PushWebVital.WebVitalRow != nil:
+		details = append(details, fmt.Sprintf("project=%d", msg.PushWebVital.WebVitalRow.ProjectId), "name="+msg.PushWebVital.WebVitalRow.Name)
+	case msg.PushOTeLMetrics != nil:
+		details = append(details, fmt.Sprintf("datapoints=%d", len(msg.PushOTeLMetrics.MetricRows)))
+	case msg.PushSegmentEvent != nil:
+		details = append(details, fmt.Sprintf("project=%d", msg.PushSegmentEvent.ProjectID), "type="+msg.PushSegmentEvent.Type, "user="+msg.PushSegmentEvent.UserID)
+	}
+	if msg.Failures > 0 {
+		details = append(details, fmt.Sprintf("failures=%d/%d", msg.Failures, msg.MaxRetries))
+	}
+	return strings.TrimSpace(name + " " + strings.Join(details, " "))
+}
+
+func tailKafka(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("tail-kafka", flag.ExitOnError)
+	topicType := flags.String("topic", string(kafkaqueue.TopicTypeDefault), "default, batched, datasync or traces")
+	limit := flags.Int("n", 0, "stop after this many messages, 0 to tail forever")
+	fromBeginning := flags.Bool("from-beginning", false, "start from the oldest retained message rather than new messages")
+	_ = flags.Parse(args)
+
+	// a dedicated consumer group so that tailing does not take messages away from the workers
+	groupID := fmt.Sprintf("highlight-admin-%d", time.Now().UnixNano())
+	startOffset := kafka.LastOffset
+	if *fromBeginning {
+		startOffset = kafka.FirstOffset
+	}
+	topic := kafkaqueue.GetTopic(kafkaqueue.GetTopicOptions{Type: kafkaqueue.TopicType(*topicType)})
+	queue := kafkaqueue.New(ctx, topic, kafkaqueue.Consumer, &kafkaqueue.ConfigOverride{
+		GroupID:     &groupID,
+		StartOffset: &startOffset,
+	})
+	defer queue.Stop(ctx)
+
+	fmt.Printf("tailing %s\n", topic)
+	for received := 0; *limit == 0 || received < *limit; {
+		msg := queue.Receive(ctx)
+		if msg == nil {
+			continue
+		}
+		received++
+		if msg.KafkaMessage == nil {
+			fmt.Println(summarizeMessage(msg))
+			continue
+		}
+		fmt.Printf("%s p%d@%d key=%s bytes=%d %s\n",
+			msg.KafkaMessage.Time.Format(time.RFC3339),
+			msg.KafkaMessage.Partition,
+			msg.KafkaMessage.Offset,
+			msg.KafkaMessage.Key,
+			len(msg.KafkaMessage.Value),
+			summarizeMessage(msg),
+		)
+	}
+	return nil
+}
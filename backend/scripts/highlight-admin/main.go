@@ -0,0 +1,56 @@
+// highlight-admin bundles the checks used when debugging a self-hosted deployment:
+// sending synthetic OTLP data, tailing kafka topics, checking clickhouse health and
+// validating integration credentials.
+//
+//	highlight-admin <command> [flags]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type command struct {
+	description string
+	run         func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"send-otlp":             {"send synthetic OTLP traces and logs to an instance", sendOTLP},
+	"tail-kafka":            {"print a summary of messages as they are written to a kafka topic", tailKafka},
+	"clickhouse-health":     {"report clickhouse version, table sizes and pending mutations", clickhouseHealth},
+	"validate-integrations": {"check that the integration credentials of a workspace are valid", validateIntegrations},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: highlight-admin <command> [flags]\n\ncommands:\n")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", name, commands[name].description)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(ctx, os.Args[2:]); err != nil {
+		log.WithContext(ctx).Fatalf("%s failed: %+v", os.Args[1], err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	e "github.com/pkg/errors"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+func sendOTLP(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("send-otlp", flag.ExitOnError)
+	endpoint := flags.String("endpoint", "https://localhost:8082/otel/v1", "base url of the otel routes of the public graph")
+	projectID := flags.String("project", "", "verbose id of the project to send data to")
+	dataType := flags.String("type", "all", "traces, logs or all")
+	count := flags.Int("count", 10, "number of spans or log lines to send")
+	service := flags.String("service", "highlight-admin", "service.name of the synthetic data")
+	insecure := flags.Bool("insecure", false, "skip tls verification, ie. for the self-signed local certificate")
+	_ = flags.Parse(args)
+
+	if *projectID == "" {
+		return e.New("-project is required")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if *insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	if *dataType == "traces" || *dataType == "all" {
+		body, err := ptraceotlp.NewExportRequestFromTraces(syntheticTraces(*projectID, *service, *count)).MarshalProto()
+		if err != nil {
+			return e.Wrap(err, "failed to marshal traces")
+		}
+		if err := postOTLP(ctx, client, *endpoint+"/traces", body); err != nil {
+			return err
+		}
+	}
+	if *dataType == "logs" || *dataType == "all" {
+		body, err := plogotlp.NewExportRequestFromLogs(syntheticLogs(*projectID, *service, *count)).MarshalProto()
+		if err != nil {
+			return e.Wrap(err, "failed to marshal logs")
+		}
+		if err := postOTLP(ctx, client, *endpoint+"/logs", body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func postOTLP(ctx context.Context, client *http.Client, url string, body []byte) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return e.Wrapf(err, "failed to send to %s", url)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	fmt.Printf("POST %s: %s in %s (%d bytes compressed)\n", url, resp.Status, time.Since(start).Round(time.Millisecond), buf.Len())
+	if resp.StatusCode >= 300 {
+		return e.Errorf("unexpected response %s: %s", resp.Status, respBody)
+	}
+	return nil
+}
+
+func randomID(n int) []byte {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return b
+}
+
+func syntheticResource(resource pcommon.Resource, projectID string, service string) {
+	resource.Attributes().PutStr(highlight.ProjectIDAttribute, projectID)
+	resource.Attributes().PutStr("service.name", service)
+	resource.Attributes().PutStr("deployment.environment", "highlight-admin")
+}
+
+func syntheticTraces(projectID string, service string, count int) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	syntheticResource(rs.Resource(), projectID, service)
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+	var traceID pcommon.TraceID
+	copy(traceID[:], randomID(16))
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		span := spans.AppendEmpty()
+		var spanID pcommon.SpanID
+		copy(spanID[:], randomID(8))
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		span.SetName(fmt.Sprintf("highlight-admin-span-%d", i))
+		span.SetKind(ptrace.SpanKindInternal)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(now))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(now.Add(time.Duration(i+1) * time.Millisecond)))
+		span.Attributes().PutStr("highlight.admin", "true")
+	}
+	return traces
+}
+
+func syntheticLogs(projectID string, service string, count int) plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	syntheticResource(rl.Resource(), projectID, service)
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		record := records.AppendEmpty()
+		record.SetTimestamp(pcommon.NewTimestampFromTime(now))
+		record.SetSeverityText("INFO")
+		record.SetSeverityNumber(plog.SeverityNumberInfo)
+		record.Body().SetStr(fmt.Sprintf("highlight-admin synthetic log %d", i))
+	}
+	return logs
+}
@@ -0,0 +1,81 @@
+// loadgen sends generated OTLP traces and logs to the otel routes of an instance and reports
+// throughput and latency, to repeatably measure the capacity of the ingest pipeline.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/loadgen"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	ctx := context.Background()
+
+	endpoint := flag.String("endpoint", "https://localhost:8082/otel/v1", "base url of the otel routes of the public graph")
+	projects := flag.String("projects", "1", "comma separated verbose ids of the projects to send data to")
+	services := flag.String("services", "", "comma separated service names forming the call chain of each trace")
+	errorRate := flag.Float64("error-rate", 0.05, "fraction of traces and logs that are errors")
+	spansPerTrace := flag.Int("spans-per-trace", 10, "spans in each trace")
+	tracesPerRequest := flag.Int("traces-per-request", 10, "traces in each export request")
+	logsPerRequest := flag.Int("logs-per-request", 100, "log records in each export request")
+	attributeBytes := flag.Int("attribute-bytes", 0, "size of the padding attribute added to each span and log")
+	rps := flag.Float64("rps", 10, "export requests per second, 0 for as fast as possible")
+	duration := flag.Duration("duration", time.Minute, "how long to send traffic for")
+	concurrency := flag.Int("concurrency", 4, "number of concurrent senders")
+	dataType := flag.String("type", "all", "traces, logs or all")
+	insecure := flag.Bool("insecure", false, "skip tls verification, ie. for the self-signed local certificate")
+	jsonOutput := flag.Bool("json", false, "print the report as json")
+	flag.Parse()
+
+	generatorConfig := loadgen.GeneratorConfig{
+		Projects:         strings.Split(*projects, ","),
+		ErrorRate:        *errorRate,
+		SpansPerTrace:    *spansPerTrace,
+		TracesPerRequest: *tracesPerRequest,
+		LogsPerRequest:   *logsPerRequest,
+		AttributeBytes:   *attributeBytes,
+	}
+	if *services != "" {
+		generatorConfig.Services = strings.Split(*services, ",")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if *insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	log.WithContext(ctx).Infof("sending load to %s for %s", *endpoint, *duration)
+	report := loadgen.Run(ctx, loadgen.RunnerConfig{
+		Endpoint:          *endpoint,
+		RequestsPerSecond: *rps,
+		Duration:          *duration,
+		Concurrency:       *concurrency,
+		SendTraces:        *dataType == "traces" || *dataType == "all",
+		SendLogs:          *dataType == "logs" || *dataType == "all",
+		Client:            client,
+	}, generatorConfig)
+
+	if *jsonOutput {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			log.WithContext(ctx).Fatal(err)
+		}
+	} else {
+		fmt.Printf("requests:   %d (%d failed)\n", report.Requests, report.Failures)
+		fmt.Printf("sent:       %d spans, %d logs, %.1f MiB compressed\n", report.Spans, report.Logs, float64(report.Bytes)/1024/1024)
+		fmt.Printf("throughput: %.1f requests/s, %.1f items/s\n", report.RequestsPerSec, report.ItemsPerSec)
+		fmt.Printf("latency:    p50 %s, p90 %s, p99 %s, max %s\n", report.LatencyP50, report.LatencyP90, report.LatencyP99, report.LatencyMax)
+	}
+
+	if report.Failures > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,167 @@
+package severity
+
+import (
+	"strconv"
+	"strings"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// Severity is the canonical severity of a log row. Number is the logrus level of Text,
+// which is what the SeverityNumber column of the logs table stores.
+type Severity struct {
+	Text   modelInputs.LogLevel
+	Number int32
+}
+
+var Default = New(modelInputs.LogLevelInfo)
+
+var aliases = map[string]modelInputs.LogLevel{
+	"trace":          modelInputs.LogLevelTrace,
+	"verbose":        modelInputs.LogLevelTrace,
+	"debug":          modelInputs.LogLevelDebug,
+	"info":           modelInputs.LogLevelInfo,
+	"information":    modelInputs.LogLevelInfo,
+	"informational":  modelInputs.LogLevelInfo,
+	"notice":         modelInputs.LogLevelInfo,
+	"log":            modelInputs.LogLevelInfo,
+	"warn":           modelInputs.LogLevelWarn,
+	"warning":        modelInputs.LogLevelWarn,
+	"console.warn":   modelInputs.LogLevelWarn,
+	"error":          modelInputs.LogLevelError,
+	"err":            modelInputs.LogLevelError,
+	"console.error":  modelInputs.LogLevelError,
+	"window.onerror": modelInputs.LogLevelError,
+	"fatal":          modelInputs.LogLevelFatal,
+	"critical":       modelInputs.LogLevelFatal,
+	"crit":           modelInputs.LogLevelFatal,
+	"alert":          modelInputs.LogLevelFatal,
+	"emergency":      modelInputs.LogLevelFatal,
+	"emerg":          modelInputs.LogLevelFatal,
+	"panic":          modelInputs.LogLevelFatal,
+}
+
+// New returns the severity of a log level.
+func New(level modelInputs.LogLevel) Severity {
+	return Severity{Text: level, Number: number(level)}
+}
+
+// Normalize returns the severity of a log from its severity text, falling back to
+// its otel severity number when the text is empty or unknown, and to info otherwise.
+func Normalize(text string, severityNumber plog.SeverityNumber) Severity {
+	if level, ok := ParseText(text); ok {
+		return New(level)
+	}
+	if level, ok := FromOTel(severityNumber); ok {
+		return New(level)
+	}
+	return Default
+}
+
+// Level returns the level of a severity text, defaulting to info when it is unknown.
+func Level(text string) modelInputs.LogLevel {
+	return Normalize(text, plog.SeverityNumberUnspecified).Text
+}
+
+// ParseText parses a severity text case-insensitively. It accepts the usual level names and
+// aliases (ie. `WARNING`, `console.error`), the otel short names (ie. `INFO3`) and otel
+// severity numbers (ie. `13`).
+func ParseText(text string) (modelInputs.LogLevel, bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if level, ok := aliases[text]; ok {
+		return level, true
+	}
+	if n, err := strconv.Atoi(text); err == nil {
+		return FromOTel(plog.SeverityNumber(n))
+	}
+	// otel short names of the finer severities, ie. `debug2` to `debug4`
+	if len(text) > 1 && text[len(text)-1] >= '2' && text[len(text)-1] <= '4' {
+		if level, ok := aliases[text[:len(text)-1]]; ok {
+			return level, true
+		}
+	}
+	return "", false
+}
+
+// FromOTel maps an otel severity number to its level.
+// See https://opentelemetry.io/docs/specs/otel/logs/data-model/#field-severitynumber
+func FromOTel(severityNumber plog.SeverityNumber) (modelInputs.LogLevel, bool) {
+	switch {
+	case severityNumber >= plog.SeverityNumberTrace && severityNumber <= plog.SeverityNumberTrace4:
+		return modelInputs.LogLevelTrace, true
+	case severityNumber >= plog.SeverityNumberDebug && severityNumber <= plog.SeverityNumberDebug4:
+		return modelInputs.LogLevelDebug, true
+	case severityNumber >= plog.SeverityNumberInfo && severityNumber <= plog.SeverityNumberInfo4:
+		return modelInputs.LogLevelInfo, true
+	case severityNumber >= plog.SeverityNumberWarn && severityNumber <= plog.SeverityNumberWarn4:
+		return modelInputs.LogLevelWarn, true
+	case severityNumber >= plog.SeverityNumberError && severityNumber <= plog.SeverityNumberError4:
+		return modelInputs.LogLevelError, true
+	case severityNumber >= plog.SeverityNumberFatal && severityNumber <= plog.SeverityNumberFatal4:
+		return modelInputs.LogLevelFatal, true
+	}
+	return "", false
+}
+
+// FromSyslog maps a syslog (or systemd journal) severity, from 0 (emergency) to 7 (debug), to its level.
+func FromSyslog(syslogSeverity int) (modelInputs.LogLevel, bool) {
+	switch syslogSeverity {
+	case 0, 1:
+		return modelInputs.LogLevelFatal, true
+	case 2, 3:
+		return modelInputs.LogLevelError, true
+	case 4, 5:
+		return modelInputs.LogLevelWarn, true
+	case 6:
+		return modelInputs.LogLevelInfo, true
+	case 7:
+		return modelInputs.LogLevelDebug, true
+	}
+	return "", false
+}
+
+// FromNumber maps the logrus level stored as the SeverityNumber of a log row to its level.
+func FromNumber(level log.Level) modelInputs.LogLevel {
+	switch level {
+	case log.TraceLevel:
+		return modelInputs.LogLevelTrace
+	case log.DebugLevel:
+		return modelInputs.LogLevelDebug
+	case log.WarnLevel:
+		return modelInputs.LogLevelWarn
+	case log.ErrorLevel:
+		return modelInputs.LogLevelError
+	case log.FatalLevel, log.PanicLevel:
+		return modelInputs.LogLevelFatal
+	default:
+		return modelInputs.LogLevelInfo
+	}
+}
+
+// NormalizeQueryValue rewrites a level searched for in the logs query to its canonical text,
+// so that `level:WARNING` matches the stored `warn` rows. Unknown values are kept as is.
+func NormalizeQueryValue(value string) string {
+	if level, ok := ParseText(value); ok {
+		return level.String()
+	}
+	return value
+}
+
+func number(level modelInputs.LogLevel) int32 {
+	switch level {
+	case modelInputs.LogLevelTrace:
+		return int32(log.TraceLevel)
+	case modelInputs.LogLevelDebug:
+		return int32(log.DebugLevel)
+	case modelInputs.LogLevelWarn:
+		return int32(log.WarnLevel)
+	case modelInputs.LogLevelError:
+		return int32(log.ErrorLevel)
+	case modelInputs.LogLevelFatal:
+		return int32(log.FatalLevel)
+	default:
+		return int32(log.InfoLevel)
+	}
+}
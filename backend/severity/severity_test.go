@@ -0,0 +1,53 @@
+package severity
+
+import (
+	"testing"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		number   plog.SeverityNumber
+		expected modelInputs.LogLevel
+	}{
+		{"WARN", plog.SeverityNumberUnspecified, modelInputs.LogLevelWarn},
+		{"warning", plog.SeverityNumberUnspecified, modelInputs.LogLevelWarn},
+		{"13", plog.SeverityNumberUnspecified, modelInputs.LogLevelWarn},
+		{"", plog.SeverityNumberWarn, modelInputs.LogLevelWarn},
+		{"Debug3", plog.SeverityNumberUnspecified, modelInputs.LogLevelDebug},
+		{"console.error", plog.SeverityNumberUnspecified, modelInputs.LogLevelError},
+		{"CRITICAL", plog.SeverityNumberUnspecified, modelInputs.LogLevelFatal},
+		{"dir", plog.SeverityNumberError, modelInputs.LogLevelError},
+		{"dir", plog.SeverityNumberUnspecified, modelInputs.LogLevelInfo},
+		{"error", plog.SeverityNumberInfo, modelInputs.LogLevelError},
+	} {
+		assert.Equal(t, tc.expected, Normalize(tc.text, tc.number).Text, "%q %d", tc.text, tc.number)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for _, level := range modelInputs.AllLogLevel {
+		s := New(level)
+		assert.Equal(t, level, FromNumber(log.Level(s.Number)))
+	}
+	assert.Equal(t, int32(log.WarnLevel), Normalize("warning", plog.SeverityNumberUnspecified).Number)
+}
+
+func TestFromSyslog(t *testing.T) {
+	level, ok := FromSyslog(4)
+	assert.True(t, ok)
+	assert.Equal(t, modelInputs.LogLevelWarn, level)
+	_, ok = FromSyslog(8)
+	assert.False(t, ok)
+}
+
+func TestNormalizeQueryValue(t *testing.T) {
+	assert.Equal(t, "warn", NormalizeQueryValue("WARNING"))
+	assert.Equal(t, "error", NormalizeQueryValue("17"))
+	assert.Equal(t, "custom", NormalizeQueryValue("custom"))
+}
@@ -0,0 +1,207 @@
+package shortcut
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	ShortcutApiBaseUrl = "https://api.app.shortcut.com/api/v3"
+)
+
+const requestTimeout = 10 * time.Second
+
+// maxSearchStories caps the stories returned by a search.
+const maxSearchStories = 20
+
+// ErrUnauthorized is returned when Shortcut rejects the api token, ie. after it was revoked.
+var ErrUnauthorized = errors.New("Shortcut API token is invalid")
+
+// Client calls the Shortcut api with an api token created by a member of the Shortcut workspace,
+// as Shortcut does not support oauth.
+type Client struct {
+	apiToken   string
+	httpClient *http.Client
+}
+
+func NewClient(apiToken string) *Client {
+	return &Client{apiToken: apiToken, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+func doRequest[T any](ctx context.Context, c *Client, method string, path string, query url.Values, input any) (T, error) {
+	var unmarshalled T
+	var body io.Reader
+	if input != nil {
+		b, err := json.Marshal(input)
+		if err != nil {
+			return unmarshalled, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	u := ShortcutApiBaseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return unmarshalled, errors.Wrap(err, "error creating api request to Shortcut")
+	}
+	req.Header.Set("Shortcut-Token", c.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return unmarshalled, errors.Wrap(err, "error getting response from Shortcut endpoint")
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return unmarshalled, errors.Wrap(err, "error reading response body from Shortcut endpoint")
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		return unmarshalled, ErrUnauthorized
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return unmarshalled, errors.New("Shortcut API responded with error; status_code=" + res.Status + "; body=" + string(b))
+	}
+
+	if err := json.Unmarshal(b, &unmarshalled); err != nil {
+		return unmarshalled, errors.Wrap(err, "error unmarshaling Shortcut response")
+	}
+	return unmarshalled, nil
+}
+
+type Member struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	MentionName string `json:"mention_name"`
+}
+
+// GetCurrentMember returns the member that created the api token, which verifies the token.
+func (c *Client) GetCurrentMember(ctx context.Context) (*Member, error) {
+	return doRequest[*Member](ctx, c, http.MethodGet, "/member", nil, nil)
+}
+
+// WorkflowState is a column of a workflow. Its type is `unstarted`, `started` or `done`.
+type WorkflowState struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Workflow struct {
+	ID             int64            `json:"id"`
+	Name           string           `json:"name"`
+	DefaultStateID int64            `json:"default_state_id"`
+	States         []*WorkflowState `json:"states"`
+}
+
+func (c *Client) GetWorkflows(ctx context.Context) ([]*Workflow, error) {
+	return doRequest[[]*Workflow](ctx, c, http.MethodGet, "/workflows", nil, nil)
+}
+
+type Epic struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	AppURL    string `json:"app_url"`
+	Completed bool   `json:"completed"`
+	Archived  bool   `json:"archived"`
+}
+
+// GetEpics returns the epics that stories can be added to, excluding the completed and archived ones.
+func (c *Client) GetEpics(ctx context.Context) ([]*Epic, error) {
+	epics, err := doRequest[[]*Epic](ctx, c, http.MethodGet, "/epics", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(epics, func(epic *Epic, _ int) bool {
+		return !epic.Completed && !epic.Archived
+	}), nil
+}
+
+type Story struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	AppURL          string   `json:"app_url"`
+	Completed       bool     `json:"completed"`
+	WorkflowStateID int64    `json:"workflow_state_id"`
+	ExternalLinks   []string `json:"external_links"`
+}
+
+type CreateStoryInput struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	StoryType       string   `json:"story_type"`
+	WorkflowStateID int64    `json:"workflow_state_id"`
+	EpicID          *int64   `json:"epic_id,omitempty"`
+	ExternalLinks   []string `json:"external_links,omitempty"`
+}
+
+// CreateStory creates a story, as a bug unless the story type is set.
+func (c *Client) CreateStory(ctx context.Context, input *CreateStoryInput) (*Story, error) {
+	if input.StoryType == "" {
+		input.StoryType = "bug"
+	}
+	return doRequest[*Story](ctx, c, http.MethodPost, "/stories", nil, input)
+}
+
+func (c *Client) GetStory(ctx context.Context, id string) (*Story, error) {
+	return doRequest[*Story](ctx, c, http.MethodGet, "/stories/"+url.PathEscape(id), nil, nil)
+}
+
+// AddExternalLink links a story to the url, unless it is linked already.
+func (c *Client) AddExternalLink(ctx context.Context, story *Story, link string) (*Story, error) {
+	if lo.Contains(story.ExternalLinks, link) {
+		return story, nil
+	}
+	return doRequest[*Story](ctx, c, http.MethodPut, fmt.Sprintf("/stories/%d", story.ID), nil, map[string]any{
+		"external_links": append(story.ExternalLinks[:len(story.ExternalLinks):len(story.ExternalLinks)], link),
+	})
+}
+
+// SearchStories returns the stories matching the query with the Shortcut search operators,
+// ie. `type:bug checkout`.
+func (c *Client) SearchStories(ctx context.Context, query string) ([]*Story, error) {
+	type searchResponse struct {
+		Data []*Story `json:"data"`
+	}
+	res, err := doRequest[*searchResponse](ctx, c, http.MethodGet, "/search/stories", url.Values{
+		"query":     {strings.TrimSpace(query)},
+		"page_size": {fmt.Sprint(maxSearchStories)},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// SplitProjectID returns the workflow and epic of an id of the issue trackers' projects, which is
+// a workflow id or `<workflow id>/<epic id>` for the epics that stories of the workflow are added to.
+func SplitProjectID(id string) (int64, *int64, error) {
+	workflow, epic, hasEpic := strings.Cut(id, "/")
+	workflowID, err := strconv.ParseInt(workflow, 10, 64)
+	if err != nil {
+		return 0, nil, errors.Errorf("invalid Shortcut workflow %q", workflow)
+	}
+	if !hasEpic {
+		return workflowID, nil, nil
+	}
+	epicID, err := strconv.ParseInt(epic, 10, 64)
+	if err != nil {
+		return 0, nil, errors.Errorf("invalid Shortcut epic %q", epic)
+	}
+	return workflowID, &epicID, nil
+}
@@ -0,0 +1,104 @@
+package shortcut
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var created, updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Shortcut-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/member":
+			_, _ = w.Write([]byte(`{"id":"m1","name":"Jane","mention_name":"jane"}`))
+		case "/epics":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Checkout"},{"id":2,"name":"Onboarding","completed":true},{"id":3,"name":"Legacy","archived":true}]`))
+		case "/stories":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":10,"name":"TypeError","app_url":"https://app.shortcut.com/acme/story/10","workflow_state_id":500,"external_links":["https://app.highlight.io/1/errors/abc"]}`))
+		case "/stories/10":
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(`{"id":10,"name":"TypeError"}`))
+		case "/search/stories":
+			assert.Equal(t, "checkout", r.URL.Query().Get("query"))
+			_, _ = w.Write([]byte(`{"data":[{"id":10,"name":"TypeError"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	baseUrl := ShortcutApiBaseUrl
+	ShortcutApiBaseUrl = server.URL
+	defer func() { ShortcutApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("token")
+
+	_, err := NewClient("revoked").GetCurrentMember(ctx)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	member, err := client.GetCurrentMember(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &Member{ID: "m1", Name: "Jane", MentionName: "jane"}, member)
+
+	epics, err := client.GetEpics(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Epic{{ID: 1, Name: "Checkout"}}, epics)
+
+	story, err := client.CreateStory(ctx, &CreateStoryInput{
+		Name:            "TypeError",
+		WorkflowStateID: 500,
+		ExternalLinks:   []string{"https://app.highlight.io/1/errors/abc"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), story.ID)
+	assert.Equal(t, map[string]any{
+		"name":              "TypeError",
+		"story_type":        "bug",
+		"workflow_state_id": float64(500),
+		"external_links":    []any{"https://app.highlight.io/1/errors/abc"},
+	}, created)
+
+	// already linked
+	_, err = client.AddExternalLink(ctx, story, "https://app.highlight.io/1/errors/abc")
+	assert.NoError(t, err)
+	assert.Nil(t, updated)
+
+	_, err = client.AddExternalLink(ctx, story, "https://app.highlight.io/1/errors/def")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"external_links": []any{"https://app.highlight.io/1/errors/abc", "https://app.highlight.io/1/errors/def"},
+	}, updated)
+	assert.Equal(t, []string{"https://app.highlight.io/1/errors/abc"}, story.ExternalLinks)
+
+	stories, err := client.SearchStories(ctx, " checkout ")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Story{{ID: 10, Name: "TypeError"}}, stories)
+}
+
+func TestSplitProjectID(t *testing.T) {
+	workflowID, epicID, err := SplitProjectID("500")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), workflowID)
+	assert.Nil(t, epicID)
+
+	workflowID, epicID, err = SplitProjectID("500/1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), workflowID)
+	assert.Equal(t, int64(1), *epicID)
+
+	_, _, err = SplitProjectID("500/")
+	assert.Error(t, err)
+	_, _, err = SplitProjectID("workflow")
+	assert.Error(t, err)
+}
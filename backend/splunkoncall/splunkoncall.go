@@ -0,0 +1,156 @@
+package splunkoncall
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+var (
+	SplunkOnCallApiBaseUrl = "https://alert.victorops.com/integrations/generic/20131114/alert"
+)
+
+const requestTimeout = 10 * time.Second
+
+// monitoringTool is the monitoring tool of the alerts, shown on the Splunk On-Call incidents.
+const monitoringTool = "Highlight"
+
+// MessageType is the type of an alert, which opens an incident for `CRITICAL` alerts, updates the
+// incident of the entity for `WARNING` and `INFO` alerts, and acknowledges or resolves it.
+type MessageType string
+
+const (
+	MessageTypeCritical        MessageType = "CRITICAL"
+	MessageTypeWarning         MessageType = "WARNING"
+	MessageTypeInfo            MessageType = "INFO"
+	MessageTypeAcknowledgement MessageType = "ACKNOWLEDGEMENT"
+	MessageTypeRecovery        MessageType = "RECOVERY"
+)
+
+// MessageTypeForSeverity maps the severity of a Highlight alert destination, which is one of
+// `critical`, `error`, `warning` or `info`, to the message type of a Splunk On-Call alert.
+func MessageTypeForSeverity(severity string) MessageType {
+	switch severity {
+	case "warning":
+		return MessageTypeWarning
+	case "info":
+		return MessageTypeInfo
+	default:
+		return MessageTypeCritical
+	}
+}
+
+// ErrorGroupEntityID returns the entity id of the alerts of an error group, so that all the alerts
+// of an error group update a single Splunk On-Call incident which is resolved with the error group.
+func ErrorGroupEntityID(errorGroupID int) string {
+	return fmt.Sprintf("highlight-error-group-%d", errorGroupID)
+}
+
+// Client sends alerts to the REST endpoint integration of a Splunk On-Call organization. The alerts
+// are routed to the team of the routing key.
+type Client struct {
+	apiKey     string
+	routingKey string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string, routingKey string) *Client {
+	return &Client{apiKey: apiKey, routingKey: routingKey, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+type Alert struct {
+	MessageType       MessageType       `json:"message_type"`
+	EntityID          string            `json:"entity_id"`
+	EntityDisplayName string            `json:"entity_display_name,omitempty"`
+	StateMessage      string            `json:"state_message,omitempty"`
+	MonitoringTool    string            `json:"monitoring_tool,omitempty"`
+	Details           map[string]string `json:"-"`
+}
+
+// MarshalJSON inlines the details of the alert, as the REST endpoint shows any additional field of
+// an alert on its incident.
+func (a *Alert) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{}
+	for key, value := range a.Details {
+		fields[key] = value
+	}
+	fields["message_type"] = a.MessageType
+	fields["entity_id"] = a.EntityID
+	if a.EntityDisplayName != "" {
+		fields["entity_display_name"] = a.EntityDisplayName
+	}
+	if a.StateMessage != "" {
+		fields["state_message"] = a.StateMessage
+	}
+	if a.MonitoringTool != "" {
+		fields["monitoring_tool"] = a.MonitoringTool
+	}
+	return json.Marshal(fields)
+}
+
+type Response struct {
+	Result   string `json:"result"`
+	EntityID string `json:"entity_id"`
+	Message  string `json:"message"`
+}
+
+// Send sends an alert to the REST endpoint.
+func (c *Client) Send(ctx context.Context, alert *Alert) (*Response, error) {
+	if c.apiKey == "" || c.routingKey == "" {
+		return nil, errors.New("Splunk On-Call api key and routing key are required")
+	}
+	if alert.MonitoringTool == "" {
+		alert.MonitoringTool = monitoringTool
+	}
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s/%s/%s", SplunkOnCallApiBaseUrl, url.PathEscape(c.apiKey), url.PathEscape(c.routingKey))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request to Splunk On-Call")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting response from Splunk On-Call endpoint")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response body from Splunk On-Call endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, errors.New("Splunk On-Call API responded with error; status_code=" + res.Status + "; body=" + string(body))
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Splunk On-Call response")
+	}
+	if response.Result != "success" {
+		return nil, errors.Errorf("Splunk On-Call API responded with error: %s", response.Message)
+	}
+	return &response, nil
+}
+
+// Acknowledge acknowledges the incident of the entity.
+func (c *Client) Acknowledge(ctx context.Context, entityID string, message string) (*Response, error) {
+	return c.Send(ctx, &Alert{MessageType: MessageTypeAcknowledgement, EntityID: entityID, StateMessage: message})
+}
+
+// Resolve resolves the incident of the entity.
+func (c *Client) Resolve(ctx context.Context, entityID string, message string) (*Response, error) {
+	return c.Send(ctx, &Alert{MessageType: MessageTypeRecovery, EntityID: entityID, StateMessage: message})
+}
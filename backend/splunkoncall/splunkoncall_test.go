@@ -0,0 +1,66 @@
+package splunkoncall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var alerts []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/key/team" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"result":"failure","message":"Missing or invalid API key"}`))
+			return
+		}
+		var alert map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts = append(alerts, alert)
+		_, _ = w.Write([]byte(`{"result":"success","entity_id":"` + alert["entity_id"].(string) + `"}`))
+	}))
+	defer server.Close()
+
+	baseUrl := SplunkOnCallApiBaseUrl
+	SplunkOnCallApiBaseUrl = server.URL
+	defer func() { SplunkOnCallApiBaseUrl = baseUrl }()
+
+	ctx := context.Background()
+	client := NewClient("key", "team")
+	entityID := ErrorGroupEntityID(1)
+
+	res, err := client.Send(ctx, &Alert{
+		MessageType:       MessageTypeForSeverity("error"),
+		EntityID:          entityID,
+		EntityDisplayName: "Checkout errors",
+		Details:           map[string]string{"error_url": "https://app.highlight.io/1/errors/abc"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, entityID, res.EntityID)
+
+	_, err = client.Acknowledge(ctx, entityID, "snoozed")
+	assert.NoError(t, err)
+	_, err = client.Resolve(ctx, entityID, "resolved")
+	assert.NoError(t, err)
+
+	_, err = NewClient("invalid", "team").Resolve(ctx, entityID, "")
+	assert.Error(t, err)
+	_, err = NewClient("key", "").Resolve(ctx, entityID, "")
+	assert.Error(t, err)
+
+	assert.Equal(t, []map[string]any{
+		{
+			"message_type":        "CRITICAL",
+			"entity_id":           entityID,
+			"entity_display_name": "Checkout errors",
+			"monitoring_tool":     "Highlight",
+			"error_url":           "https://app.highlight.io/1/errors/abc",
+		},
+		{"message_type": "ACKNOWLEDGEMENT", "entity_id": entityID, "state_message": "snoozed", "monitoring_tool": "Highlight"},
+		{"message_type": "RECOVERY", "entity_id": entityID, "state_message": "resolved", "monitoring_tool": "Highlight"},
+	}, alerts)
+}
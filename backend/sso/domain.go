@@ -0,0 +1,54 @@
+package sso
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	e "github.com/pkg/errors"
+)
+
+const domainVerificationPrefix = "highlight-domain-verification="
+
+// TXTResolver looks up the TXT records of a domain, like net.Resolver.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// NewDomainVerificationToken returns a random token that a workspace proves it owns a domain with.
+func NewDomainVerificationToken() (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// DomainVerificationRecordName is the name of the TXT record that verifies the domain.
+func DomainVerificationRecordName(domain string) string {
+	return "_highlight-challenge." + domain
+}
+
+// DomainVerificationRecordValue is the value of the TXT record that verifies the domain.
+func DomainVerificationRecordValue(token string) string {
+	return domainVerificationPrefix + token
+}
+
+// VerifyDomain checks that the domain has the TXT record of the verification token, proving that
+// whoever set up the token controls the DNS of the domain.
+func VerifyDomain(ctx context.Context, resolver TXTResolver, domain string, token string) error {
+	if domain == "" || token == "" {
+		return e.New("domain verification has not been set up")
+	}
+	name := DomainVerificationRecordName(domain)
+	records, err := resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return e.Wrapf(err, "error looking up the TXT records of %s", name)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == DomainVerificationRecordValue(token) {
+			return nil
+		}
+	}
+	return e.Errorf("%s has no TXT record %s", name, DomainVerificationRecordValue(token))
+}
@@ -0,0 +1,38 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTXTResolver map[string][]string
+
+func (r fakeTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+func TestVerifyDomain(t *testing.T) {
+	ctx := context.Background()
+	token, err := NewDomainVerificationToken()
+	require.NoError(t, err)
+	other, err := NewDomainVerificationToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	resolver := fakeTXTResolver{
+		"_highlight-challenge.highlight.io": {"v=spf1 -all", " " + DomainVerificationRecordValue(token) + " "},
+		"_highlight-challenge.example.com":  {DomainVerificationRecordValue(other)},
+	}
+	assert.NoError(t, VerifyDomain(ctx, resolver, "highlight.io", token))
+	assert.Error(t, VerifyDomain(ctx, resolver, "example.com", token))
+	assert.Error(t, VerifyDomain(ctx, resolver, "unknown.io", token))
+	assert.Error(t, VerifyDomain(ctx, resolver, "highlight.io", ""))
+}
@@ -0,0 +1,212 @@
+package sso
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+var oidcScopes = []string{"openid", "email", "profile"}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// oidcDiscovery is the openid provider metadata of an issuer.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider authenticates the users of a workspace with the authorization code flow of the
+// openid connect issuer of the workspace, ie. an Okta authorization server or an Azure AD tenant.
+type OIDCProvider struct {
+	issuer    string
+	clientID  string
+	config    *oauth2.Config
+	discovery *oidcDiscovery
+}
+
+// NewOIDCProvider discovers the endpoints of the issuer.
+func NewOIDCProvider(ctx context.Context, issuer string, clientID string, clientSecret string, redirectURL string) (*OIDCProvider, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	discovery := &oidcDiscovery{}
+	if err := getJSON(ctx, issuer+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, errors.Wrap(err, "failed to discover openid configuration")
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, errors.Errorf("openid configuration is for issuer %s", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("incomplete openid configuration")
+	}
+	return &OIDCProvider{
+		issuer:   discovery.Issuer,
+		clientID: clientID,
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  discovery.AuthorizationEndpoint,
+				TokenURL: discovery.TokenEndpoint,
+			},
+			RedirectURL: redirectURL,
+			Scopes:      oidcScopes,
+		},
+		discovery: discovery,
+	}, nil
+}
+
+// NewNonce returns a random value that binds an id token to the sign in that requested it.
+func NewNonce() (string, error) {
+	b, err := randomBytes(24)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL is the url of the issuer that users are redirected to for signing in.
+func (p *OIDCProvider) AuthCodeURL(state string, nonce string) string {
+	return p.config.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// Exchange exchanges the authorization code of the callback for an id token and returns its identity.
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, nonce string) (*Identity, error) {
+	token, err := p.config.Exchange(context.WithValue(ctx, oauth2.HTTPClient, httpClient), code)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exchange authorization code")
+	}
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return nil, errors.New("token response has no id token")
+	}
+	keys, err := p.keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.verifyIDToken(idToken, keys, nonce)
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     *bool    `json:"email_verified"`
+	PreferredUsername string   `json:"preferred_username"`
+	GivenName         string   `json:"given_name"`
+	FamilyName        string   `json:"family_name"`
+	Groups            []string `json:"groups"`
+	Roles             []string `json:"roles"`
+}
+
+func (p *OIDCProvider) verifyIDToken(idToken string, keys map[string]*rsa.PublicKey, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys[kid]
+		if !ok {
+			return nil, errors.Errorf("unknown signing key %s", kid)
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid id token")
+	}
+	if claims.Issuer != p.issuer {
+		return nil, errors.Errorf("unexpected id token issuer %s", claims.Issuer)
+	}
+	if !claims.VerifyAudience(p.clientID, true) {
+		return nil, errors.New("id token is not intended for this client")
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("invalid id token nonce")
+	}
+	if claims.EmailVerified != nil && !*claims.EmailVerified {
+		return nil, errors.New("id token email is not verified")
+	}
+
+	identity := &Identity{
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		FirstName: claims.GivenName,
+		LastName:  claims.FamilyName,
+		Groups:    append(claims.Groups, claims.Roles...),
+	}
+	// Azure AD only has the email claim for users with a mailbox
+	if identity.Email == "" && strings.Contains(claims.PreferredUsername, "@") {
+		identity.Email = claims.PreferredUsername
+	}
+	if identity.Email == "" {
+		return nil, errors.New("id token has no email")
+	}
+	return identity, nil
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// keys are the RSA signing keys of the issuer, by key id.
+func (p *OIDCProvider) keys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, p.discovery.JWKSURI, &jwks); err != nil {
+		return nil, errors.Wrap(err, "failed to get signing keys")
+	}
+	return parseJWKS(jwks.Keys), nil
+}
+
+func parseJWKS(jwks []jsonWebKey) map[string]*rsa.PublicKey {
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range jwks {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys
+}
+
+func getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed with status %d", url, res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
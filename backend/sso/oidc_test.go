@@ -0,0 +1,143 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := &testIssuer{key: key}
+
+	mux := http.NewServeMux()
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/authorize",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			JWKSURI:               issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []jsonWebKey{{
+			Kid: "key-1",
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "code-1" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.claims)
+		token.Header["kid"] = "key-1"
+		idToken, err := token.SignedString(key)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-1",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+	return issuer
+}
+
+func (i *testIssuer) validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":         i.server.URL,
+		"aud":         "client-1",
+		"sub":         "user-1",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"iat":         time.Now().Unix(),
+		"nonce":       "nonce-1",
+		"email":       "chilly@highlight.io",
+		"given_name":  "Chilly",
+		"family_name": "Highlight",
+		"groups":      []string{"Engineering"},
+	}
+}
+
+func TestOIDCProvider(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+
+	provider, err := NewOIDCProvider(ctx, issuer.server.URL+"/", "client-1", "secret-1", "https://pri.highlight.io/sso/1/oidc/callback")
+	require.NoError(t, err)
+
+	authURL, err := url.Parse(provider.AuthCodeURL("state-1", "nonce-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", authURL.Path)
+	assert.Equal(t, "state-1", authURL.Query().Get("state"))
+	assert.Equal(t, "nonce-1", authURL.Query().Get("nonce"))
+	assert.Equal(t, "openid email profile", authURL.Query().Get("scope"))
+
+	issuer.claims = issuer.validClaims()
+	identity, err := provider.Exchange(ctx, "code-1", "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", identity.Subject)
+	assert.Equal(t, "chilly@highlight.io", identity.Email)
+	assert.Equal(t, "Chilly Highlight", identity.Name())
+	assert.Equal(t, []string{"Engineering"}, identity.Groups)
+
+	_, err = provider.Exchange(ctx, "code-2", "nonce-1")
+	assert.Error(t, err)
+	_, err = provider.Exchange(ctx, "code-1", "nonce-2")
+	assert.Error(t, err)
+
+	for name, change := range map[string]func(claims jwt.MapClaims){
+		"other audience":   func(claims jwt.MapClaims) { claims["aud"] = "client-2" },
+		"other issuer":     func(claims jwt.MapClaims) { claims["iss"] = "https://example.okta.com" },
+		"expired":          func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"unverified email": func(claims jwt.MapClaims) { claims["email_verified"] = false },
+		"no email":         func(claims jwt.MapClaims) { delete(claims, "email") },
+		"no nonce":         func(claims jwt.MapClaims) { delete(claims, "nonce") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			issuer.claims = issuer.validClaims()
+			change(issuer.claims)
+			_, err := provider.Exchange(ctx, "code-1", "nonce-1")
+			assert.Error(t, err)
+		})
+	}
+
+	// azure ad users without a mailbox are identified by their user principal name
+	issuer.claims = issuer.validClaims()
+	delete(issuer.claims, "email")
+	issuer.claims["preferred_username"] = "chilly@highlight.onmicrosoft.com"
+	identity, err = provider.Exchange(ctx, "code-1", "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "chilly@highlight.onmicrosoft.com", identity.Email)
+}
+
+func TestNewOIDCProvider_IssuerMismatch(t *testing.T) {
+	issuer := newTestIssuer(t)
+	_, err := NewOIDCProvider(context.Background(), issuer.server.URL+"/tenant", "client-1", "secret-1", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,357 @@
+package sso
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/pkg/errors"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+const (
+	samlProtocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlAssertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlMetadataNamespace  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	samlPostBinding       = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlEmailNameIDFormat = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	samlStatusSuccess     = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearerMethod      = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// samlClockSkew is the tolerated difference between the clocks of the identity provider and ours.
+const samlClockSkew = 3 * time.Minute
+
+// the attributes of an assertion that are read as the email, names and groups of the user, by the
+// attribute names of Okta and the claim names of Azure AD
+var (
+	samlEmailAttributes     = []string{"email", "Email", "mail", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"}
+	samlFirstNameAttributes = []string{"firstName", "first_name", "givenName", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname"}
+	samlLastNameAttributes  = []string{"lastName", "last_name", "surname", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname"}
+	samlGroupAttributes     = []string{"groups", "Groups", "http://schemas.microsoft.com/ws/2008/06/identity/claims/groups", "http://schemas.microsoft.com/ws/2008/06/identity/claims/role"}
+)
+
+var errInvalidSignature = errors.New("invalid xml signature")
+
+// SAMLServiceProvider authenticates the users of a workspace with the SAML identity provider of
+// the workspace. Requests are sent with the HTTP-Redirect binding and assertions are received
+// with the HTTP-POST binding.
+type SAMLServiceProvider struct {
+	// EntityID identifies us to the identity provider, ie. the url of our metadata.
+	EntityID string
+	// ACSURL is the assertion consumer service url that responses are posted to.
+	ACSURL string
+
+	IdPEntityID    string
+	IdPSSOURL      string
+	IdPCertificate *x509.Certificate
+}
+
+// ParseCertificate parses the PEM or base64 encoded DER signing certificate of an identity provider.
+func ParseCertificate(certificate string) (*x509.Certificate, error) {
+	certificate = strings.TrimSpace(certificate)
+	var der []byte
+	if block, _ := pem.Decode([]byte(certificate)); block != nil {
+		der = block.Bytes
+	} else {
+		var err error
+		der, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificate), ""))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid certificate encoding")
+		}
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate")
+	}
+	return cert, nil
+}
+
+// NewRequestID returns a random identifier of a SAML request, which must not start with a digit.
+func NewRequestID() (string, error) {
+	b, err := randomBytes(20)
+	if err != nil {
+		return "", err
+	}
+	return "id-" + hex.EncodeToString(b), nil
+}
+
+// AuthnRequestURL is the url of the identity provider that users are redirected to for signing in.
+// The relay state is posted back with the response.
+func (sp *SAMLServiceProvider) AuthnRequestURL(requestID string, relayState string, now time.Time) (string, error) {
+	request := fmt.Sprintf(
+		`<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s"><saml:Issuer>%s</saml:Issuer><samlp:NameIDPolicy Format="%s" AllowCreate="true"/></samlp:AuthnRequest>`,
+		samlProtocolNamespace, samlAssertionNamespace, requestID, now.UTC().Format(time.RFC3339),
+		escapeXML(sp.IdPSSOURL), escapeXML(sp.ACSURL), samlPostBinding, escapeXML(sp.EntityID), samlEmailNameIDFormat,
+	)
+
+	var compressed bytes.Buffer
+	writer, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write([]byte(request)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(sp.IdPSSOURL)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid identity provider sso url")
+	}
+	query := u.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	query.Set("RelayState", relayState)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Metadata is the service provider metadata that identity providers are configured with.
+func (sp *SAMLServiceProvider) Metadata() []byte {
+	return []byte(fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="%s" entityID="%s">
+  <md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">
+    <md:NameIDFormat>%s</md:NameIDFormat>
+    <md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>
+`, samlMetadataNamespace, escapeXML(sp.EntityID), samlProtocolNamespace, samlEmailNameIDFormat, samlPostBinding, escapeXML(sp.ACSURL)))
+}
+
+type samlAttribute struct {
+	Name   string   `xml:"Name,attr"`
+	Values []string `xml:"AttributeValue"`
+}
+
+type samlAssertion struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:assertion Assertion"`
+	ID      string   `xml:"ID,attr"`
+	Issuer  string   `xml:"Issuer"`
+	Subject struct {
+		NameID struct {
+			Format string `xml:"Format,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"NameID"`
+		SubjectConfirmations []struct {
+			Method string `xml:"Method,attr"`
+			Data   struct {
+				InResponseTo string    `xml:"InResponseTo,attr"`
+				NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+				Recipient    string    `xml:"Recipient,attr"`
+			} `xml:"SubjectConfirmationData"`
+		} `xml:"SubjectConfirmation"`
+	} `xml:"Subject"`
+	Conditions struct {
+		NotBefore    time.Time `xml:"NotBefore,attr"`
+		NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+		Audiences    []string  `xml:"AudienceRestriction>Audience"`
+	} `xml:"Conditions"`
+	Attributes []samlAttribute `xml:"AttributeStatement>Attribute"`
+}
+
+func (a *samlAssertion) attribute(names []string) []string {
+	for _, name := range names {
+		for _, attr := range a.Attributes {
+			if attr.Name == name && len(attr.Values) > 0 {
+				return attr.Values
+			}
+		}
+	}
+	return nil
+}
+
+// ParseResponse verifies the base64 encoded SAML response posted to the assertion consumer service
+// and returns the identity of its assertion. The assertion, or the response containing it, must be
+// signed by the identity provider. The requestID is the ID of the request that the response answers.
+func (sp *SAMLServiceProvider) ParseResponse(encoded string, requestID string, now time.Time) (*Identity, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid saml response encoding")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, errors.Wrap(err, "invalid saml response")
+	}
+	root := doc.Root()
+	if root == nil || root.Tag != "Response" || root.NamespaceURI() != samlProtocolNamespace {
+		return nil, errors.New("invalid saml response")
+	}
+	if status := samlChild(root, samlProtocolNamespace, "Status"); status != nil {
+		if code := samlChild(status, samlProtocolNamespace, "StatusCode"); code != nil && code.SelectAttrValue("Value", "") != samlStatusSuccess {
+			return nil, errors.Errorf("saml authentication failed with status %s", code.SelectAttrValue("Value", ""))
+		}
+	}
+	if samlChild(root, samlAssertionNamespace, "EncryptedAssertion") != nil {
+		return nil, errors.New("encrypted saml assertions are not supported")
+	}
+	assertionElement := samlChild(root, samlAssertionNamespace, "Assertion")
+	if assertionElement == nil {
+		return nil, errors.New("saml response must have exactly one assertion")
+	}
+
+	// the assertion is read from the signed element returned by the verification, so that only
+	// the content covered by the signature is trusted
+	var assertion samlAssertion
+	if samlChild(assertionElement, dsig.Namespace, "Signature") != nil {
+		verified, err := verifySignedElement(root, assertionElement, sp.IdPCertificate)
+		if err != nil {
+			return nil, err
+		}
+		if err := xml.Unmarshal(verified, &assertion); err != nil {
+			return nil, errors.Wrap(err, "invalid saml assertion")
+		}
+	} else {
+		verified, err := verifySignedElement(root, root, sp.IdPCertificate)
+		if err != nil {
+			return nil, err
+		}
+		var response struct {
+			Assertions []samlAssertion `xml:"urn:oasis:names:tc:SAML:2.0:assertion Assertion"`
+		}
+		if err := xml.Unmarshal(verified, &response); err != nil {
+			return nil, errors.Wrap(err, "invalid saml response")
+		}
+		if len(response.Assertions) != 1 {
+			return nil, errors.New("saml response must have exactly one assertion")
+		}
+		assertion = response.Assertions[0]
+	}
+
+	if err := sp.validateAssertion(&assertion, requestID, now); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Subject: strings.TrimSpace(assertion.Subject.NameID.Value),
+		Groups:  assertion.attribute(samlGroupAttributes),
+	}
+	if emails := assertion.attribute(samlEmailAttributes); len(emails) > 0 {
+		identity.Email = strings.TrimSpace(emails[0])
+	} else if assertion.Subject.NameID.Format == samlEmailNameIDFormat {
+		identity.Email = identity.Subject
+	}
+	if names := assertion.attribute(samlFirstNameAttributes); len(names) > 0 {
+		identity.FirstName = names[0]
+	}
+	if names := assertion.attribute(samlLastNameAttributes); len(names) > 0 {
+		identity.LastName = names[0]
+	}
+	if identity.Email == "" {
+		return nil, errors.New("saml assertion has no email")
+	}
+	return identity, nil
+}
+
+func (sp *SAMLServiceProvider) validateAssertion(assertion *samlAssertion, requestID string, now time.Time) error {
+	if strings.TrimSpace(assertion.Issuer) != sp.IdPEntityID {
+		return errors.Errorf("unexpected saml issuer %s", assertion.Issuer)
+	}
+
+	conditions := assertion.Conditions
+	if !conditions.NotBefore.IsZero() && now.Add(samlClockSkew).Before(conditions.NotBefore) {
+		return errors.New("saml assertion is not yet valid")
+	}
+	if !conditions.NotOnOrAfter.IsZero() && !now.Add(-samlClockSkew).Before(conditions.NotOnOrAfter) {
+		return errors.New("saml assertion expired")
+	}
+	audienceOK := false
+	for _, audience := range conditions.Audiences {
+		if strings.TrimSpace(audience) == sp.EntityID {
+			audienceOK = true
+		}
+	}
+	if !audienceOK {
+		return errors.New("saml assertion is not intended for this service provider")
+	}
+
+	for _, confirmation := range assertion.Subject.SubjectConfirmations {
+		data := confirmation.Data
+		if confirmation.Method != samlBearerMethod || data.Recipient != sp.ACSURL {
+			continue
+		}
+		if data.NotOnOrAfter.IsZero() || !now.Add(-samlClockSkew).Before(data.NotOnOrAfter) {
+			continue
+		}
+		if data.InResponseTo != requestID {
+			continue
+		}
+		return nil
+	}
+	return errors.New("saml assertion has no valid bearer subject confirmation")
+}
+
+// verifySignedElement checks the enveloped signature of an element of the response against the
+// certificate of the identity provider, returning the element that the signature covers.
+func verifySignedElement(root *etree.Element, el *etree.Element, certificate *x509.Certificate) ([]byte, error) {
+	id := el.SelectAttrValue("ID", "")
+	if id == "" {
+		return nil, errors.Wrap(errInvalidSignature, "signed element has no ID")
+	}
+	// another element with the ID could be read in place of the signed one
+	if countIDs(root, id) != 1 {
+		return nil, errors.Wrap(errInvalidSignature, "signed element ID is not unique")
+	}
+
+	// the element is verified on its own, declaring the namespaces it inherits from the response
+	nsContext, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidSignature, err.Error())
+	}
+	detached, err := etreeutils.NSDetatch(nsContext, el)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidSignature, err.Error())
+	}
+
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: []*x509.Certificate{certificate},
+	})
+	verified, err := validationContext.Validate(detached)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidSignature, err.Error())
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(verified)
+	return doc.WriteToBytes()
+}
+
+// samlChild returns the first child element of el with the namespace and local name.
+func samlChild(el *etree.Element, namespace string, local string) *etree.Element {
+	for _, child := range el.ChildElements() {
+		if child.Tag == local && child.NamespaceURI() == namespace {
+			return child
+		}
+	}
+	return nil
+}
+
+func countIDs(el *etree.Element, id string) int {
+	count := 0
+	if el.SelectAttrValue("ID", "") == id {
+		count++
+	}
+	for _, child := range el.ChildElements() {
+		count += countIDs(child, id)
+	}
+	return count
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,216 @@
+package sso
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSAMLResponse = `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="response-1" InResponseTo="id-request" Version="2.0">
+  <saml:Issuer>http://www.okta.com/exk1</saml:Issuer>
+  <samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>
+  <saml:Assertion ID="assertion-1" Version="2.0">
+    <saml:Issuer>http://www.okta.com/exk1</saml:Issuer>
+    <saml:Subject>
+      <saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">chilly@highlight.io</saml:NameID>
+      <saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+        <saml:SubjectConfirmationData InResponseTo="id-request" NotOnOrAfter="2024-03-01T10:05:00Z" Recipient="https://pri.highlight.io/sso/1/saml/acs"/>
+      </saml:SubjectConfirmation>
+    </saml:Subject>
+    <saml:Conditions NotBefore="2024-03-01T09:55:00Z" NotOnOrAfter="2024-03-01T10:05:00Z">
+      <saml:AudienceRestriction><saml:Audience>https://pri.highlight.io/sso/1/saml/metadata</saml:Audience></saml:AudienceRestriction>
+    </saml:Conditions>
+    <saml:AttributeStatement>
+      <saml:Attribute Name="firstName"><saml:AttributeValue xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="xs:string">Chilly</saml:AttributeValue></saml:Attribute>
+      <saml:Attribute Name="groups"><saml:AttributeValue>Engineering</saml:AttributeValue><saml:AttributeValue>Highlight Admins</saml:AttributeValue></saml:Attribute>
+    </saml:AttributeStatement>
+  </saml:Assertion>
+</samlp:Response>`
+
+var testSAMLNow = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func newTestCertificate(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
+
+// signXML signs the element with the ID in the document as an identity provider does, inserting
+// the signature after the element's issuer.
+func signXML(t *testing.T, doc string, id string, key *rsa.PrivateKey, cert *x509.Certificate) string {
+	document := etree.NewDocument()
+	require.NoError(t, document.ReadFromString(doc))
+	el := document.FindElement(fmt.Sprintf("//[@ID='%s']", id))
+	require.NotNil(t, el)
+
+	nsContext, err := etreeutils.NSBuildParentContext(el)
+	require.NoError(t, err)
+	detached, err := etreeutils.NSDetatch(nsContext, el)
+	require.NoError(t, err)
+	signingContext := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(tls.Certificate{
+		Certificate: [][]byte{cert.Raw},
+		PrivateKey:  key,
+	}))
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	signature, err := signingContext.ConstructSignature(detached, true)
+	require.NoError(t, err)
+
+	el.InsertChildAt(el.ChildElements()[0].Index()+1, signature)
+	signed, err := document.WriteToString()
+	require.NoError(t, err)
+	return signed
+}
+
+func TestVerifySignedElement(t *testing.T) {
+	key, cert := newTestCertificate(t)
+	doc := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="response-1"><saml:Issuer>idp</saml:Issuer><saml:Assertion ID="assertion-1"><saml:Issuer>idp</saml:Issuer><saml:Subject>chilly@highlight.io</saml:Subject></saml:Assertion></samlp:Response>`
+	parse := func(doc string) *etree.Element {
+		document := etree.NewDocument()
+		require.NoError(t, document.ReadFromString(doc))
+		return document.Root()
+	}
+
+	signedDoc := signXML(t, doc, "assertion-1", key, cert)
+	root := parse(signedDoc)
+	assertion := samlChild(root, samlAssertionNamespace, "Assertion")
+	verified, err := verifySignedElement(root, assertion, cert)
+	require.NoError(t, err)
+	assert.Equal(t, `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="assertion-1"><saml:Issuer>idp</saml:Issuer><saml:Subject>chilly@highlight.io</saml:Subject></saml:Assertion>`, string(verified))
+
+	// the signed content cannot be changed
+	tampered := parse(strings.Replace(signedDoc, "<saml:Subject>chilly@", "<saml:Subject>evil@", 1))
+	_, err = verifySignedElement(tampered, samlChild(tampered, samlAssertionNamespace, "Assertion"), cert)
+	assert.ErrorIs(t, err, errInvalidSignature)
+
+	// another certificate did not sign the document
+	_, otherCert := newTestCertificate(t)
+	_, err = verifySignedElement(root, assertion, otherCert)
+	assert.ErrorIs(t, err, errInvalidSignature)
+
+	// an unsigned element is not verified
+	_, err = verifySignedElement(root, root, cert)
+	assert.ErrorIs(t, err, errInvalidSignature)
+
+	// the signed element must be the only element with its ID
+	wrapped := parse(strings.Replace(signedDoc, `</samlp:Response>`, `<Extensions ID="assertion-1"></Extensions></samlp:Response>`, 1))
+	_, err = verifySignedElement(wrapped, samlChild(wrapped, samlAssertionNamespace, "Assertion"), cert)
+	assert.ErrorIs(t, err, errInvalidSignature)
+}
+
+func newTestServiceProvider(t *testing.T) (*SAMLServiceProvider, func(doc string, id string) string) {
+	key, cert := newTestCertificate(t)
+	sp := &SAMLServiceProvider{
+		EntityID:       "https://pri.highlight.io/sso/1/saml/metadata",
+		ACSURL:         "https://pri.highlight.io/sso/1/saml/acs",
+		IdPEntityID:    "http://www.okta.com/exk1",
+		IdPSSOURL:      "https://example.okta.com/app/sso/saml",
+		IdPCertificate: cert,
+	}
+	return sp, func(doc string, id string) string {
+		return base64.StdEncoding.EncodeToString([]byte(signXML(t, doc, id, key, cert)))
+	}
+}
+
+func TestSAMLServiceProvider_ParseResponse(t *testing.T) {
+	sp, sign := newTestServiceProvider(t)
+
+	identity, err := sp.ParseResponse(sign(testSAMLResponse, "assertion-1"), "id-request", testSAMLNow)
+	require.NoError(t, err)
+	assert.Equal(t, "chilly@highlight.io", identity.Email)
+	assert.Equal(t, "Chilly", identity.FirstName)
+	assert.Equal(t, []string{"Engineering", "Highlight Admins"}, identity.Groups)
+
+	// a signed response covers its assertion
+	identity, err = sp.ParseResponse(sign(testSAMLResponse, "response-1"), "id-request", testSAMLNow)
+	require.NoError(t, err)
+	assert.Equal(t, "chilly@highlight.io", identity.Email)
+
+	for name, tc := range map[string]struct {
+		response  string
+		requestID string
+		now       time.Time
+	}{
+		"unsigned":             {base64.StdEncoding.EncodeToString([]byte(testSAMLResponse)), "id-request", testSAMLNow},
+		"other request":        {sign(testSAMLResponse, "assertion-1"), "id-other", testSAMLNow},
+		"expired":              {sign(testSAMLResponse, "assertion-1"), "id-request", testSAMLNow.Add(time.Hour)},
+		"not yet valid":        {sign(testSAMLResponse, "assertion-1"), "id-request", testSAMLNow.Add(-time.Hour)},
+		"other audience":       {sign(strings.Replace(testSAMLResponse, "/sso/1/saml/metadata", "/sso/2/saml/metadata", 1), "assertion-1"), "id-request", testSAMLNow},
+		"other recipient":      {sign(strings.Replace(testSAMLResponse, "/sso/1/saml/acs", "/sso/2/saml/acs", 1), "assertion-1"), "id-request", testSAMLNow},
+		"other issuer":         {sign(strings.ReplaceAll(testSAMLResponse, "exk1", "exk2"), "assertion-1"), "id-request", testSAMLNow},
+		"failed status":        {sign(strings.Replace(testSAMLResponse, "status:Success", "status:Requester", 1), "assertion-1"), "id-request", testSAMLNow},
+		"invalid base64":       {"not base64!", "id-request", testSAMLNow},
+		"not a saml response":  {base64.StdEncoding.EncodeToString([]byte("<root/>")), "id-request", testSAMLNow},
+		"unsolicited response": {sign(strings.ReplaceAll(testSAMLResponse, ` InResponseTo="id-request"`, ""), "assertion-1"), "id-request", testSAMLNow},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := sp.ParseResponse(tc.response, tc.requestID, tc.now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSAMLServiceProvider_AuthnRequestURL(t *testing.T) {
+	sp, _ := newTestServiceProvider(t)
+	u, err := sp.AuthnRequestURL("id-request", "state-1", testSAMLNow)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(u)
+	require.NoError(t, err)
+	assert.Equal(t, "example.okta.com", parsed.Host)
+	assert.Equal(t, "state-1", parsed.Query().Get("RelayState"))
+
+	compressed, err := base64.StdEncoding.DecodeString(parsed.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	request, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	require.NoError(t, err)
+	assert.Contains(t, string(request), `ID="id-request"`)
+	assert.Contains(t, string(request), `AssertionConsumerServiceURL="https://pri.highlight.io/sso/1/saml/acs"`)
+	assert.Contains(t, string(request), `<saml:Issuer>https://pri.highlight.io/sso/1/saml/metadata</saml:Issuer>`)
+
+	assert.Contains(t, string(sp.Metadata()), `Location="https://pri.highlight.io/sso/1/saml/acs"`)
+}
+
+func TestParseCertificate(t *testing.T) {
+	_, cert := newTestCertificate(t)
+	encoded := base64.StdEncoding.EncodeToString(cert.Raw)
+
+	parsed, err := ParseCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(cert))
+
+	parsed, err = ParseCertificate(fmt.Sprintf("%s\n%s", encoded[:64], encoded[64:]))
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(cert))
+
+	_, err = ParseCertificate("invalid")
+	assert.Error(t, err)
+}
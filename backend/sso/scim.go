@@ -0,0 +1,223 @@
+package sso
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+// the schemas of the SCIM v2 resources and messages, ie. https://datatracker.ietf.org/doc/html/rfc7643
+const (
+	SCIMUserSchema          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMListResponseSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMPatchOpSchema       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMErrorSchema         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMServiceConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// SCIMContentType is the media type of SCIM requests and responses.
+const SCIMContentType = "application/scim+json"
+
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+// SCIMUser is a user provisioned by an identity provider. Its id is the id of the admin, and its
+// roles are mapped to the role of the admin in the workspace.
+type SCIMUser struct {
+	Schemas    []string         `json:"schemas"`
+	ID         string           `json:"id,omitempty"`
+	ExternalID string           `json:"externalId,omitempty"`
+	UserName   string           `json:"userName"`
+	Name       *SCIMName        `json:"name,omitempty"`
+	Emails     []SCIMMultiValue `json:"emails,omitempty"`
+	Active     *bool            `json:"active,omitempty"`
+	Roles      []SCIMMultiValue `json:"roles,omitempty"`
+	Meta       *SCIMMeta        `json:"meta,omitempty"`
+}
+
+// Email is the primary email of the user, or its user name when it is an email.
+func (u *SCIMUser) Email() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	if strings.Contains(u.UserName, "@") {
+		return u.UserName
+	}
+	return ""
+}
+
+// IsActive is whether the user is active, users are active unless deactivated.
+func (u *SCIMUser) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// RoleNames are the values of the roles of the user.
+func (u *SCIMUser) RoleNames() []string {
+	var roles []string
+	for _, role := range u.Roles {
+		roles = append(roles, role.Value)
+	}
+	return roles
+}
+
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func NewSCIMError(status int, scimType string, detail string) *SCIMError {
+	return &SCIMError{
+		Schemas:  []string{SCIMErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type SCIMPatchOp struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+var errUnsupportedPatch = errors.New("unsupported patch operation")
+
+// Apply applies the add and replace operations of a patch to a user. Okta and Azure AD patch the
+// active flag, names, emails and roles of users. Azure AD sends capitalized operations and the
+// active flag as a string.
+func (p *SCIMPatchOp) Apply(user *SCIMUser) error {
+	for _, op := range p.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		case "remove":
+			if strings.EqualFold(op.Path, "roles") {
+				user.Roles = nil
+				continue
+			}
+			return errors.Wrapf(errUnsupportedPatch, "cannot remove %s", op.Path)
+		default:
+			return errors.Wrapf(errUnsupportedPatch, "unknown operation %s", op.Op)
+		}
+
+		if op.Path == "" {
+			// a path-less operation replaces the attributes of its value
+			var attributes map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attributes); err != nil {
+				return errors.Wrap(err, "invalid patch value")
+			}
+			for path, value := range attributes {
+				if err := applyPatchPath(user, path, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := applyPatchPath(user, op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyPatchPath(user *SCIMUser, path string, value json.RawMessage) error {
+	path = strings.ToLower(path)
+	if strings.HasPrefix(path, "name") && user.Name == nil {
+		user.Name = &SCIMName{}
+	}
+	var err error
+	switch path {
+	case "active":
+		var active bool
+		if err = json.Unmarshal(value, &active); err != nil {
+			var s string
+			if json.Unmarshal(value, &s) == nil {
+				active, err = strconv.ParseBool(s)
+			}
+		}
+		user.Active = &active
+	case "username":
+		err = json.Unmarshal(value, &user.UserName)
+	case "externalid":
+		err = json.Unmarshal(value, &user.ExternalID)
+	case "name":
+		err = json.Unmarshal(value, user.Name)
+	case "name.givenname":
+		err = json.Unmarshal(value, &user.Name.GivenName)
+	case "name.familyname":
+		err = json.Unmarshal(value, &user.Name.FamilyName)
+	case "emails":
+		err = json.Unmarshal(value, &user.Emails)
+	case `emails[type eq "work"].value`:
+		var email string
+		if err = json.Unmarshal(value, &email); err == nil {
+			user.Emails = []SCIMMultiValue{{Value: email, Type: "work", Primary: true}}
+		}
+	case "roles":
+		err = json.Unmarshal(value, &user.Roles)
+	case `roles[primary eq "true"].value`:
+		var role string
+		if err = json.Unmarshal(value, &role); err == nil {
+			user.Roles = []SCIMMultiValue{{Value: role, Primary: true}}
+		}
+	default:
+		// attributes that are not stored, ie. the display name or title, are ignored
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "invalid value of %s", path)
+	}
+	return nil
+}
+
+var userNameFilter = regexp.MustCompile(`(?i)^\s*(userName|emails(?:\.value)?)\s+eq\s+"([^"]*)"\s*$`)
+
+// ParseUserFilter parses the `userName eq "..."` filter that identity providers look up users with,
+// returning the filtered user name.
+func ParseUserFilter(filter string) (string, error) {
+	matches := userNameFilter.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", errors.Errorf("unsupported filter %s", filter)
+	}
+	return matches[2], nil
+}
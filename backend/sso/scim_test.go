@@ -0,0 +1,73 @@
+package sso
+
+import (
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCIMPatchOp_Apply(t *testing.T) {
+	user := &SCIMUser{UserName: "chilly@highlight.io"}
+
+	// okta deactivates users with a path-less replace
+	var patch SCIMPatchOp
+	require.NoError(t, json.Unmarshal([]byte(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","value":{"active":false,"name":{"givenName":"Chilly"}}}]}`), &patch))
+	require.NoError(t, patch.Apply(user))
+	assert.False(t, user.IsActive())
+	assert.Equal(t, "Chilly", user.Name.GivenName)
+
+	// azure ad capitalizes operations and sends the active flag as a string
+	patch = SCIMPatchOp{}
+	require.NoError(t, json.Unmarshal([]byte(`{"Operations":[{"op":"Replace","path":"active","value":"True"},{"op":"Add","path":"emails[type eq \"work\"].value","value":"chilly@highlight.io"},{"op":"Add","path":"roles[primary eq \"True\"].value","value":"Highlight Admins"},{"op":"Replace","path":"displayName","value":"Chilly"}]}`), &patch))
+	require.NoError(t, patch.Apply(user))
+	assert.True(t, user.IsActive())
+	assert.Equal(t, "chilly@highlight.io", user.Email())
+	assert.Equal(t, []string{"Highlight Admins"}, user.RoleNames())
+
+	patch = SCIMPatchOp{Operations: []SCIMPatchOperation{{Op: "remove", Path: "roles"}}}
+	require.NoError(t, patch.Apply(user))
+	assert.Empty(t, user.RoleNames())
+
+	patch = SCIMPatchOp{Operations: []SCIMPatchOperation{{Op: "remove", Path: "userName"}}}
+	assert.ErrorIs(t, patch.Apply(user), errUnsupportedPatch)
+	patch = SCIMPatchOp{Operations: []SCIMPatchOperation{{Op: "move", Path: "userName"}}}
+	assert.ErrorIs(t, patch.Apply(user), errUnsupportedPatch)
+	patch = SCIMPatchOp{Operations: []SCIMPatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)}}}
+	assert.Error(t, patch.Apply(user))
+}
+
+func TestSCIMUser_Email(t *testing.T) {
+	assert.Equal(t, "chilly@highlight.io", (&SCIMUser{UserName: "chilly@highlight.io"}).Email())
+	assert.Equal(t, "", (&SCIMUser{UserName: "chilly"}).Email())
+	assert.Equal(t, "work@highlight.io", (&SCIMUser{UserName: "chilly", Emails: []SCIMMultiValue{{Value: "home@highlight.io"}, {Value: "work@highlight.io", Primary: true}}}).Email())
+}
+
+func TestParseUserFilter(t *testing.T) {
+	userName, err := ParseUserFilter(`userName eq "chilly@highlight.io"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "chilly@highlight.io", userName)
+
+	userName, err = ParseUserFilter(`emails.value Eq "chilly@highlight.io"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "chilly@highlight.io", userName)
+
+	_, err = ParseUserFilter(`userName sw "chilly"`)
+	assert.Error(t, err)
+}
+
+func TestRoleForGroups(t *testing.T) {
+	groupRoles := map[string]string{"Highlight Admins": RoleAdmin, "Engineering": RoleMember, "Sales": RoleViewer}
+	assert.Equal(t, RoleAdmin, RoleForGroups(groupRoles, []string{"Engineering", "Highlight Admins"}, RoleMember))
+	assert.Equal(t, RoleMember, RoleForGroups(groupRoles, []string{"Engineering"}, RoleAdmin))
+	assert.Equal(t, RoleMember, RoleForGroups(groupRoles, []string{"Sales", "Engineering"}, ""))
+	assert.Equal(t, RoleViewer, RoleForGroups(groupRoles, []string{"Sales"}, ""))
+	assert.Equal(t, RoleMember, RoleForGroups(groupRoles, []string{"Support"}, RoleMember))
+	assert.Equal(t, "", RoleForGroups(groupRoles, nil, ""))
+}
+
+func TestEmailDomain(t *testing.T) {
+	assert.Equal(t, "highlight.io", EmailDomain("chilly@Highlight.IO"))
+	assert.Equal(t, "", EmailDomain("chilly"))
+}
@@ -0,0 +1,94 @@
+package sso
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// Provider is the identity provider of a workspace.
+type Provider string
+
+const (
+	ProviderOkta    Provider = "okta"
+	ProviderAzureAD Provider = "azure_ad"
+)
+
+func (p Provider) IsValid() bool {
+	return p == ProviderOkta || p == ProviderAzureAD
+}
+
+// Protocol is how users of a workspace sign in with its identity provider.
+type Protocol string
+
+const (
+	ProtocolSAML Protocol = "saml"
+	ProtocolOIDC Protocol = "oidc"
+)
+
+func (p Protocol) IsValid() bool {
+	return p == ProtocolSAML || p == ProtocolOIDC
+}
+
+// the roles of workspace admins that groups can be mapped to, matching model.AdminRole
+const (
+	RoleAdmin  = "ADMIN"
+	RoleMember = "MEMBER"
+	RoleViewer = "VIEWER"
+)
+
+// roleRanks order the roles that groups can be mapped to from the least privileged.
+var roleRanks = map[string]int{RoleViewer: 1, RoleMember: 2, RoleAdmin: 3}
+
+// IsValidRole is whether groups can be mapped to the role.
+func IsValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// Identity is a user authenticated by an identity provider.
+type Identity struct {
+	// Subject is the id of the user at the identity provider.
+	Subject   string
+	Email     string
+	FirstName string
+	LastName  string
+	// Groups are the groups or app roles of the user, that are mapped to workspace roles.
+	Groups []string
+}
+
+// Name is the display name of the user.
+func (i *Identity) Name() string {
+	return strings.TrimSpace(i.FirstName + " " + i.LastName)
+}
+
+// EmailDomain is the domain of an email address, in lower case.
+func EmailDomain(email string) string {
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(domain))
+}
+
+// RoleForGroups maps the groups of a user to a workspace role with the group to role mapping of the
+// workspace. A user in several mapped groups gets the most privileged role, and a user in no mapped
+// group gets the default role.
+func RoleForGroups(groupRoles map[string]string, groups []string, defaultRole string) string {
+	role := ""
+	for _, group := range groups {
+		if mapped := groupRoles[group]; roleRanks[mapped] > roleRanks[role] {
+			role = mapped
+		}
+	}
+	if role == "" {
+		return defaultRole
+	}
+	return role
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
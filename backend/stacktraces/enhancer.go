@@ -108,17 +108,82 @@ func limitMaxSize(value *string) *string {
 	return pointy.String(strings.Repeat((*value)[:ERROR_STACK_MAX_FIELD_SIZE], 1))
 }
 
+// SourcemapArtifactLookup finds the paths of the sourcemap artifacts uploaded for a release version.
+type SourcemapArtifactLookup interface {
+	GetSourcemapArtifactPaths(ctx context.Context, projectID int, version *string) ([]string, error)
+}
+
+// SourcemapBucketLookup finds the private bucket that the files of a project are fetched from
+// instead of their URLs, returning nil if the project has none.
+type SourcemapBucketLookup interface {
+	GetSourcemapBucketReader(ctx context.Context, projectID int) (storage.SourcemapBucketReader, error)
+}
+
+type SourcemapLookup interface {
+	SourcemapArtifactLookup
+	SourcemapBucketLookup
+}
+
+// bucketFetcher fetches files from the private sourcemap bucket of a project by the paths of their URLs.
+type bucketFetcher struct {
+	bucket  storage.SourcemapBucketReader
+	version *string
+}
+
+func (b bucketFetcher) fetchFile(ctx context.Context, href string) ([]byte, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+	return b.bucket.ReadSourceMapFile(ctx, b.version, u.Path)
+}
+
+// sourcemapArtifacts are the paths of the artifacts uploaded for the version of a stack trace.
+type sourcemapArtifacts []string
+
+// resolve returns the uploaded artifact of a file path, which is the path itself or its longest
+// suffix after a `/`, since files can be served under a prefix that is not part of the upload.
+func (a sourcemapArtifacts) resolve(filePath string) (string, bool) {
+	var resolved string
+	for _, artifact := range a {
+		if artifact != filePath && !strings.HasSuffix(filePath, "/"+artifact) {
+			continue
+		}
+		if len(artifact) > len(resolved) {
+			resolved = artifact
+		}
+	}
+	return resolved, resolved != ""
+}
+
 /*
 * EnhanceStackTrace makes no DB changes
 * It loops through the stack trace, for each :
-* fetches the sourcemap from remote
+* fetches the sourcemap from remote or the private bucket of the project, or only from the artifacts uploaded for the version
 * maps the error info into slice
  */
-func EnhanceStackTrace(ctx context.Context, input []*publicModel.StackFrameInput, projectId int, version *string, storageClient storage.Client) ([]*privateModel.ErrorTrace, error) {
+func EnhanceStackTrace(ctx context.Context, input []*publicModel.StackFrameInput, projectId int, version *string, storageClient storage.Client, lookup SourcemapLookup) ([]*privateModel.ErrorTrace, error) {
 	if input == nil {
 		return nil, e.New("stack trace input cannot be nil")
 	}
 
+	var artifacts sourcemapArtifacts
+	fileFetcher := fetch
+	if lookup != nil {
+		paths, err := lookup.GetSourcemapArtifactPaths(ctx, projectId, version)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectId).Error("failed to get sourcemap artifacts")
+		}
+		artifacts = paths
+
+		bucket, err := lookup.GetSourcemapBucketReader(ctx, projectId)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("project_id", projectId).Error("failed to get sourcemap bucket")
+		} else if bucket != nil {
+			fileFetcher = bucketFetcher{bucket: bucket, version: version}
+		}
+	}
+
 	var mappedStackTrace []*privateModel.ErrorTrace
 	for idx, stackFrame := range input {
 		if idx >= ERROR_STACK_MAX_FRAME_COUNT {
@@ -127,7 +192,7 @@ func EnhanceStackTrace(ctx context.Context, input []*publicModel.StackFrameInput
 		if stackFrame == nil || (stackFrame.FileName == nil || len(*stackFrame.FileName) < 1 || stackFrame.LineNumber == nil || stackFrame.ColumnNumber == nil) {
 			continue
 		}
-		mappedStackFrame, err, errMetadata := processStackFrame(ctx, projectId, version, *stackFrame, storageClient)
+		mappedStackFrame, err, errMetadata := processStackFrame(ctx, projectId, version, *stackFrame, storageClient, fileFetcher, artifacts)
 		if err != nil {
 			if util.IsDevOrTestEnv() {
 				log.WithContext(ctx).Error(err)
@@ -171,17 +236,32 @@ func getFileSourcemap(ctx context.Context, projectId int, version *string, stack
 	return
 }
 
-func getURLSourcemap(ctx context.Context, projectId int, version *string, stackTraceFileURL string, stackTraceFilePath string, stackFileNameIndex int, storageClient storage.Client, stackTraceError *privateModel.SourceMappingError) (string, []byte, error) {
-	// try to get file from s3
-	minifiedFileBytes, err := storageClient.ReadSourceMapFile(ctx, projectId, version, stackTraceFilePath)
+func getURLSourcemap(ctx context.Context, projectId int, version *string, stackTraceFileURL string, stackTraceFilePath string, stackFileNameIndex int, storageClient storage.Client, fileFetcher fetcher, artifacts sourcemapArtifacts, stackTraceError *privateModel.SourceMappingError) (string, []byte, error) {
+	var minifiedFileBytes []byte
+	var err error
 	minifiedFetchStrategy := "S3"
 	var stackTraceErrorCode privateModel.SourceMappingErrorCode
+	if len(artifacts) > 0 {
+		// the artifacts uploaded for the version replace fetching files from their public urls.
+		// the minified file is optional, since its sourcemap is otherwise named after it.
+		minifiedFetchStrategy = "Uploaded"
+		if artifactPath, ok := artifacts.resolve(stackTraceFilePath); ok {
+			stackTraceFilePath = artifactPath
+			minifiedFileBytes, err = storageClient.ReadSourceMapFile(ctx, projectId, version, stackTraceFilePath)
+			if err != nil {
+				log.WithContext(ctx).Warn(e.Wrapf(err, "error reading uploaded file: %v", stackTraceFilePath))
+			}
+		}
+	} else {
+		// try to get file from s3
+		minifiedFileBytes, err = storageClient.ReadSourceMapFile(ctx, projectId, version, stackTraceFilePath)
+	}
 	stackTraceError.MinifiedFetchStrategy = &minifiedFetchStrategy
 	stackTraceError.ActualMinifiedFetchedPath = &stackTraceFilePath
 
-	if err != nil {
+	if err != nil && len(artifacts) == 0 {
 		// if not in s3, get from url and put in s3
-		minifiedFileBytes, err = fetch.fetchFile(ctx, stackTraceFileURL)
+		minifiedFileBytes, err = fileFetcher.fetchFile(ctx, stackTraceFileURL)
 		minifiedFetchStrategy = "URL"
 		stackTraceError.MinifiedFetchStrategy = &minifiedFetchStrategy
 		if err != nil {
@@ -271,6 +351,25 @@ func getURLSourcemap(ctx context.Context, projectId int, version *string, stackT
 		}
 		stackTraceError.ActualSourcemapFetchedPath = &sourceMapFilePath
 
+		if len(artifacts) > 0 {
+			sourcemapFetchStrategy := "Uploaded"
+			stackTraceError.SourcemapFetchStrategy = &sourcemapFetchStrategy
+			artifactPath, ok := artifacts.resolve(sourceMapFilePath)
+			if !ok {
+				// SOURCEMAP_ERROR: the source map file was not uploaded for the version
+				stackTraceErrorCode = privateModel.SourceMappingErrorCodeMissingSourceMapFileInS3
+				stackTraceError.ErrorCode = &stackTraceErrorCode
+				return "", nil, e.Errorf("source map file was not uploaded for the version: %v", sourceMapFilePath)
+			}
+			stackTraceError.ActualSourcemapFetchedPath = &artifactPath
+			if sourceMapFileBytes, err = storageClient.ReadSourceMapFile(ctx, projectId, version, artifactPath); err != nil {
+				stackTraceErrorCode = privateModel.SourceMappingErrorCodeMissingSourceMapFileInS3
+				stackTraceError.ErrorCode = &stackTraceErrorCode
+				return "", nil, e.Wrapf(err, "error reading uploaded source map file: %v", artifactPath)
+			}
+			return sourceMapURL, sourceMapFileBytes, nil
+		}
+
 		// fetch source map file
 		// try to get file from s3
 		sourceMapFileBytes, err = storageClient.ReadSourceMapFile(ctx, projectId, version, sourceMapFilePath)
@@ -278,7 +377,7 @@ func getURLSourcemap(ctx context.Context, projectId int, version *string, stackT
 		stackTraceError.SourcemapFetchStrategy = &sourcemapFetchStrategy
 		if err != nil {
 			// if not in s3, get from url and put in s3
-			sourceMapFileBytes, err = fetch.fetchFile(ctx, sourceMapURL)
+			sourceMapFileBytes, err = fileFetcher.fetchFile(ctx, sourceMapURL)
 			sourcemapFetchStrategy = "URL"
 			stackTraceError.SourcemapFetchStrategy = &sourcemapFetchStrategy
 			if err != nil {
@@ -311,7 +410,7 @@ func getURLSourcemap(ctx context.Context, projectId int, version *string, stackT
 	return sourceMapURL, sourceMapFileBytes, nil
 }
 
-func processStackFrame(ctx context.Context, projectId int, version *string, stackTrace publicModel.StackFrameInput, storageClient storage.Client) (*privateModel.ErrorTrace, error, privateModel.SourceMappingError) {
+func processStackFrame(ctx context.Context, projectId int, version *string, stackTrace publicModel.StackFrameInput, storageClient storage.Client, fileFetcher fetcher, artifacts sourcemapArtifacts) (*privateModel.ErrorTrace, error, privateModel.SourceMappingError) {
 	stackTraceFileURL := *stackTrace.FileName
 	stackTraceLineNumber := *stackTrace.LineNumber
 	stackTraceColumnNumber := *stackTrace.ColumnNumber
@@ -360,7 +459,7 @@ func processStackFrame(ctx context.Context, projectId int, version *string, stac
 			return nil, err, stackTraceError
 		}
 	} else {
-		sourceMapURL, sourceMapFileBytes, err = getURLSourcemap(ctx, projectId, version, stackTraceFileURL, stackTraceFilePath, stackFileNameIndex, storageClient, &stackTraceError)
+		sourceMapURL, sourceMapFileBytes, err = getURLSourcemap(ctx, projectId, version, stackTraceFileURL, stackTraceFilePath, stackFileNameIndex, storageClient, fileFetcher, artifacts, &stackTraceError)
 		if err != nil {
 			return nil, err, stackTraceError
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/openlyinc/pointy"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 
 	"github.com/aws/smithy-go/ptr"
@@ -237,7 +238,7 @@ func TestEnhanceStackTrace(t *testing.T) {
 					v = &tc.version
 				}
 				fetch = tc.fetcher
-				mappedStackTrace, err := EnhanceStackTrace(ctx, tc.stackFrameInput, 1, v, client)
+				mappedStackTrace, err := EnhanceStackTrace(ctx, tc.stackFrameInput, 1, v, client, nil)
 				if err != nil {
 					if err.Error() == tc.err.Error() {
 						return
@@ -262,12 +263,94 @@ func TestGetURLSourcemap(t *testing.T) {
 		t.Fatalf("error creating storage client: %v", err)
 	}
 	sm := modelInput.SourceMappingError{}
-	_, _, err = getURLSourcemap(ctx, 1, nil, "", "", 100, fsClient, &sm)
+	_, _, err = getURLSourcemap(ctx, 1, nil, "", "", 100, fsClient, fetch, nil, &sm)
 	if err == nil {
 		t.Error("expected an error")
 	}
 }
 
+func TestSourcemapArtifactsResolve(t *testing.T) {
+	artifacts := sourcemapArtifacts{"index.js.map", "assets/index.js.map", "assets/vendor.js.map"}
+
+	path, ok := artifacts.resolve("assets/index.js.map")
+	assert.True(t, ok)
+	assert.Equal(t, "assets/index.js.map", path)
+
+	// files served under a prefix resolve to the longest uploaded suffix
+	path, ok = artifacts.resolve("app/assets/index.js.map")
+	assert.True(t, ok)
+	assert.Equal(t, "assets/index.js.map", path)
+
+	_, ok = artifacts.resolve("app/myindex.js.map")
+	assert.False(t, ok)
+	_, ok = artifacts.resolve("assets/main.js.map")
+	assert.False(t, ok)
+}
+
+type failingFetcher struct{}
+
+func (f failingFetcher) fetchFile(context.Context, string) ([]byte, error) {
+	return nil, e.New("files of uploaded versions should not be fetched")
+}
+
+func TestGetURLSourcemapUploaded(t *testing.T) {
+	ctx := context.Background()
+	fsClient, err := storage.NewFSClient(ctx, "https://localhost:8082/public", t.TempDir())
+	if err != nil {
+		t.Fatalf("error creating storage client: %v", err)
+	}
+	version := pointy.String("1.0.0")
+	_, err = fsClient.PushSourceMapFile(ctx, 1, version, "assets/index.js.map", []byte(`{"version":3}`))
+	assert.NoError(t, err)
+
+	fileURL := "https://example.com/app/assets/index.js"
+	sm := modelInput.SourceMappingError{}
+	sourceMapURL, sourceMapFileBytes, err := getURLSourcemap(ctx, 1, version, fileURL, "app/assets/index.js", strings.Index(fileURL, "index.js"), fsClient, failingFetcher{}, sourcemapArtifacts{"assets/index.js.map"}, &sm)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/app/assets/index.js.map", sourceMapURL)
+	assert.Equal(t, `{"version":3}`, string(sourceMapFileBytes))
+	assert.Equal(t, "assets/index.js.map", *sm.ActualSourcemapFetchedPath)
+
+	// a sourcemap that was not uploaded is missing rather than fetched
+	sm = modelInput.SourceMappingError{}
+	_, _, err = getURLSourcemap(ctx, 1, version, fileURL, "app/assets/index.js", strings.Index(fileURL, "index.js"), fsClient, failingFetcher{}, sourcemapArtifacts{"assets/vendor.js.map"}, &sm)
+	assert.Error(t, err)
+	assert.Equal(t, modelInput.SourceMappingErrorCodeMissingSourceMapFileInS3, *sm.ErrorCode)
+}
+
+type fakeBucket map[string][]byte
+
+func (b fakeBucket) ReadSourceMapFile(_ context.Context, _ *string, filePath string) ([]byte, error) {
+	if data, ok := b[filePath]; ok {
+		return data, nil
+	}
+	return nil, e.Errorf("%s not found", filePath)
+}
+
+func TestGetURLSourcemapBucket(t *testing.T) {
+	ctx := context.Background()
+	fsClient, err := storage.NewFSClient(ctx, "https://localhost:8082/public", t.TempDir())
+	if err != nil {
+		t.Fatalf("error creating storage client: %v", err)
+	}
+
+	sourceMap := `{"version":3,"sources":["index.ts"],"names":[],"mappings":"AAAA"}`
+	bucket := fakeBucket{
+		"/app/assets/index.js":     []byte("console.log(1)\n//# sourceMappingURL=index.js.map"),
+		"/app/assets/index.js.map": []byte(sourceMap),
+	}
+	fileURL := "https://example.com/app/assets/index.js"
+	sm := modelInput.SourceMappingError{}
+	_, sourceMapFileBytes, err := getURLSourcemap(ctx, 1, nil, fileURL, "app/assets/index.js", strings.Index(fileURL, "index.js"), fsClient, bucketFetcher{bucket: bucket}, nil, &sm)
+	assert.NoError(t, err)
+	assert.Equal(t, sourceMap, string(sourceMapFileBytes))
+
+	// files fetched from the bucket are stored like those fetched from their urls
+	stored, err := fsClient.ReadSourceMapFile(ctx, 1, nil, "app/assets/index.js.map")
+	assert.NoError(t, err)
+	assert.Equal(t, sourceMap, string(stored))
+}
+
 func TestEnhanceStackTraceProd(t *testing.T) {
 	// local only for troubleshooting stacktrace enhancement
 	t.Skip()
@@ -288,7 +371,7 @@ func TestEnhanceStackTraceProd(t *testing.T) {
 			ColumnNumber: pointy.Int(5784),
 			Source:       pointy.String("    at https://lifeat.io/bundle.js:3540:5784"),
 		},
-	}, 1703, pointy.String("dev"), s3Client)
+	}, 1703, pointy.String("dev"), s3Client, nil)
 	if err != nil {
 		t.Fatal(e.Wrap(err, "error enhancing source map"))
 	}
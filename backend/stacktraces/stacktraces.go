@@ -17,12 +17,18 @@ type Language string
 const Javascript Language = "js"
 const Python Language = "python"
 const Golang Language = "golang"
+const Java Language = "java"
+const Dotnet Language = "dotnet"
 
 // StructureOTELStackTrace processes a backend opentelemetry stacktrace into a structured ErrorTraces.
 // The operation returns the deepest frame first (reversing the order of the incoming stacktrace).
 func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, error) {
 	jsPattern := regexp.MustCompile(` {4}at ((.+) )?\(?(.+):(\d+):(\d+)\)?`)
 	jsAnonPattern := regexp.MustCompile(` {4}at (.+) \((.+)\)`)
+	dotnetPattern := regexp.MustCompile(`^ {3}at (.+?)\((.*)\)(?: in (.+):line (\d+))?\s*$`)
+	dotnetOtherPattern := regexp.MustCompile(`^\s*(--- End of .+ ---|---> .+)$`)
+	javaPattern := regexp.MustCompile(`^\s+at ([\w$.]+)\.([\w$<>\-]+)\(([^:)]*)(?::(\d+))?\)`)
+	javaOtherPattern := regexp.MustCompile(`^(\s+\.\.\. \d+ (more|common frames omitted)|Caused by: .+|\s+Suppressed: .+)$`)
 	pyPattern := regexp.MustCompile(` {2}File "(.+)", line (\d+), in (\w+)`)
 	pyExcPattern := regexp.MustCompile(`^(\S.+)`)
 	pyUnderPattern := regexp.MustCompile(`^\s*[\^~]+\s*$`)
@@ -72,6 +78,13 @@ func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, erro
 		if matches := pyMultiPattern.FindSubmatch([]byte(line)); language == Python && matches != nil {
 			continue
 		}
+		if matches := javaOtherPattern.FindSubmatch([]byte(line)); language == Java && matches != nil {
+			continue
+		}
+		// inner exceptions of .NET stacktraces start before the language is known
+		if matches := dotnetOtherPattern.FindSubmatch([]byte(line)); matches != nil {
+			continue
+		}
 		if errMsg == "" {
 			errMsg = line
 		}
@@ -95,6 +108,22 @@ func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, erro
 			frame.FunctionName = pointy.String(string(matches[1]))
 			frame.FileName = pointy.String(string(matches[2]))
 			frame.LineContent = pointy.String(string(matches[2]))
+		} else if matches := dotnetPattern.FindSubmatch([]byte(line)); matches != nil {
+			language = Dotnet
+			frame.FunctionName = pointy.String(string(matches[1]))
+			if matches[3] != nil {
+				frame.FileName = pointy.String(string(matches[3]))
+				line, _ := strconv.ParseInt(string(matches[4]), 10, 32)
+				frame.LineNumber = pointy.Int(int(line))
+			}
+		} else if matches := javaPattern.FindSubmatch([]byte(line)); matches != nil {
+			language = Java
+			frame.FunctionName = pointy.String(string(matches[1]) + "." + string(matches[2]))
+			frame.FileName = pointy.String(string(matches[3]))
+			if matches[4] != nil {
+				line, _ := strconv.ParseInt(string(matches[4]), 10, 32)
+				frame.LineNumber = pointy.Int(int(line))
+			}
 		} else if matches := pyPattern.FindSubmatch([]byte(line)); matches != nil {
 			language = Python
 			frame.FunctionName = pointy.String(string(matches[3]))
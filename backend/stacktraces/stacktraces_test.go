@@ -34,6 +34,7 @@ func TestFormatStructureStackTrace(t *testing.T) {
 		{language: "golang-panic-recover", stacktrace: "\ngithub.com/highlight/highlight/sdk/highlight-go.GraphQLRecoverFunc.func1\n\t/home/vkorolik/work/highlight/sdk/highlight-go/tracer.go:110\ngithub.com/99designs/gqlgen/graphql.(*OperationContext).Recover\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/context_operation.go:124\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query_error_instance.func1\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:40988\nruntime.gopanic\n\t/usr/local/go/src/runtime/panic.go:890\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).isAdminInProject\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/resolver.go:481\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).isAdminInProjectOrDemoProject\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/resolver.go:317\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).doesAdminOwnErrorGroup\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/resolver.go:875\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).canAdminViewErrorGroup\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/resolver.go:911\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*queryResolver).ErrorInstance\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/schema.resolvers.go:4097\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query_error_instance.func2\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:40994\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func4\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:72\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8.1\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:110\ngithub.com/highlight/highlight/sdk/highlight-go.Tracer.InterceptField\n\t/home/vkorolik/work/highlight/sdk/highlight-go/tracer.go:59\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:109\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8.1\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:110\ngithub.com/highlight-run/highlight/backend/util.Tracer.InterceptField\n\t/home/vkorolik/work/highlight/backend/util/tracer.go:45\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:109\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query_error_instance\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:40992\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query.func43\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:67991\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func3\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:69\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query.func44\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:67996\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query.func45\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:68000\ngithub.com/99designs/gqlgen/graphql.(*FieldSet).Dispatch\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/fieldset.go:34\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:70398\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executableSchema).Exec.func1\n\t/home/vkorolik/work/highlight/backend/private-graph/graph/generated/generated.go:8576\ngithub.com/99designs/gqlgen/graphql/executor.(*Executor).DispatchOperation.func1.1.1\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/executor.go:119\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func2\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:66\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6.1\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:92\ngithub.com/highlight/highlight/sdk/highlight-go.Tracer.InterceptResponse\n\t/home/vkorolik/work/highlight/sdk/highlight-go/tracer.go:93\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:91\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6.1\n\t/home/vkorolik/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:92\ngithub.com/highlight-run/highlight/backend/util.Tracer.InterceptResponse\n\t/home/vkorolik/work/highlight/backend/util/tracer.go:67", expectedFrameError: "github.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).isAdminInProject"},
 		{language: "golang-extended", stacktrace: "\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*queryResolver).SubscriptionDetails\n\t/build/backend/private-graph/graph/schema.resolvers.go:6520\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query_subscription_details.func2\n\t/build/backend/private-graph/graph/generated/generated.go:41892\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func4\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:72\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8.1\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:110\ngithub.com/highlight/highlight/sdk/highlight-go.Tracer.InterceptField\n\t/build/sdk/highlight-go/tracer.go:47\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:109\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8.1\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:110\ngithub.com/highlight-run/highlight/backend/util.Tracer.InterceptField\n\t/build/backend/util/tracer.go:45\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func8\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:109\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query_subscription_details\n\t/build/backend/private-graph/graph/generated/generated.go:41890\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query.func310\n\t/build/backend/private-graph/graph/generated/generated.go:62598\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func3\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:69\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query.func311\n\t/build/backend/private-graph/graph/generated/generated.go:62603\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query.func312\n\t/build/backend/private-graph/graph/generated/generated.go:62607\ngithub.com/99designs/gqlgen/graphql.(*FieldSet).Dispatch\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/fieldset.go:34\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executionContext)._Query\n\t/build/backend/private-graph/graph/generated/generated.go:63005\ngithub.com/highlight-run/highlight/backend/private-graph/graph/generated.(*executableSchema).Exec.func1\n\t/build/backend/private-graph/graph/generated/generated.go:7703\ngithub.com/99designs/gqlgen/graphql/executor.(*Executor).DispatchOperation.func1.1.1\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/executor.go:119\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func2\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:66\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6.1\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:92\ngithub.com/highlight/highlight/sdk/highlight-go.Tracer.InterceptResponse\n\t/build/sdk/highlight-go/tracer.go:75\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:91\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6.1\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:92\ngithub.com/highlight-run/highlight/backend/util.Tracer.InterceptResponse\n\t/build/backend/util/tracer.go:65\ngithub.com/99designs/gqlgen/graphql/executor.processExtensions.func6\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/extensions.go:91\ngithub.com/99designs/gqlgen/graphql/executor.(*Executor).DispatchOperation.func1.1\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/executor/executor.go:118\ngithub.com/99designs/gqlgen/graphql/handler/transport.POST.Do\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/handler/transport/http_post.go:89\ngithub.com/99designs/gqlgen/graphql/handler.(*Server).ServeHTTP\n\t/go/pkg/mod/github.com/99designs/gqlgen@v0.17.24/graphql/handler/server.go:121\ngithub.com/go-chi/chi.(*Mux).routeHTTP\n\t/go/pkg/mod/github.com/go-chi/chi@v4.1.2+incompatible/mux.go:431\nnet/http.HandlerFunc.ServeHTTP\n\t/usr/local/go/src/net/http/server.go:2122\ngithub.com/highlight/highlight/sdk/highlight-go/middleware/chi.Middleware.func1\n\t/build/sdk/highlight-go/middleware/chi/middleware.go:20\nnet/http.HandlerFunc.ServeHTTP\n\t/usr/local/go/src/net/http/server.go:2122", expectedFrameError: "github.com/highlight-run/highlight/backend/private-graph/graph.(*queryResolver).SubscriptionDetails"},
 		{language: "next.js-backend", stacktrace: "Error: GraphQL Error (Code: 401): {\"response\":{\"error\":\"{\\\"errors\\\":[{\\\"message\\\":\\\"token verification failed: token contains an invalid number of segments\\\"}],\\\"data\\\":null}\",\"status\":401,\"headers\":{}},\"request\":{\"query\":\"\\n      query GetPosts() {\\n        posts(orderBy: publishedAt_DESC) {\\n          slug\\n        }\\n      }\\n    \"}}\n    at /Users/jaykhatri/projects/highlight.io/node_modules/graphql-request/dist/index.js:416:31\n    at step (/Users/jaykhatri/projects/highlight.io/node_modules/graphql-request/dist/index.js:67:23)\n    at Object.next (/Users/jaykhatri/projects/highlight.io/node_modules/graphql-request/dist/index.js:48:53)\n    at fulfilled (/Users/jaykhatri/projects/highlight.io/node_modules/graphql-request/dist/index.js:39:58)\n    at process.processTicksAndRejections (node:internal/process/task_queues:95:5)", expectedFrameError: "Error: GraphQL Error (Code: 401): {\"response\":{\"error\":\"{\\\"errors\\\":[{\\\"message\\\":\\\"token verification failed: token contains an invalid number of segments\\\"}],\\\"data\\\":null}\",\"status\":401,\"headers\":{}},\"request\":{\"query\":\"\\n      query GetPosts() {\\n        posts(orderBy: publishedAt_DESC) {\\n          slug\\n        }\\n      }\\n    \"}}"},
+		{language: "java", stacktrace: "java.lang.IllegalStateException: boom\n\tat a.b.a(SourceFile:3)\n\tat a.a.b(Unknown Source)\n\tat android.os.Handler.dispatchMessage(Handler.java:106)\nCaused by: java.lang.NullPointerException\n\tat a.c.a(SourceFile:9)\n\t... 3 more\n", expectedFrameError: "java.lang.IllegalStateException: boom", expectedFrameCount: 4},
 		{language: "node.js-console", stacktrace: "\"Error\\n    at console.<computed> [as error] (webpack-internal:///(api)/../../sdk/highlight-node/dist/index.mjs:194:15)\\n    at DevServer.logErrorWithOriginalStack (/Users/vkorolik/work/highlight/e2e/nextjs/node_modules/next/dist/server/dev/next-dev-server.js:803:71)\\n    at processTicksAndRejections (node:internal/process/task_queues:96:5)\"", expectedFrameError: "Error"},
 	}
 	for _, input := range inputs {
@@ -57,3 +58,36 @@ func TestFormatStructureStackTrace(t *testing.T) {
 		})
 	}
 }
+
+func TestStructureJavaStackTrace(t *testing.T) {
+	frames, err := StructureOTELStackTrace("java.lang.IllegalStateException: boom\n\tat a.b.a(SourceFile:3)\n\tat a.a.b(Unknown Source)")
+	assert.NoError(t, err)
+	assert.Len(t, frames, 2)
+
+	// java frames are reversed like javascript frames
+	assert.Equal(t, "a.a.b", *frames[0].FunctionName)
+	assert.Equal(t, "Unknown Source", *frames[0].FileName)
+	assert.Nil(t, frames[0].LineNumber)
+	assert.Equal(t, "a.b.a", *frames[1].FunctionName)
+	assert.Equal(t, "SourceFile", *frames[1].FileName)
+	assert.Equal(t, 3, *frames[1].LineNumber)
+}
+
+func TestStructureDotnetStackTrace(t *testing.T) {
+	frames, err := StructureOTELStackTrace("System.InvalidOperationException: outer\n ---> System.Exception: inner\n   at App.Services.OrderService.Load(Int32 id) in /src/App/Services/OrderService.cs:line 42\n   --- End of inner exception stack trace ---\n   at App.Controllers.OrdersController.Get(Int32 id) in C:\\src\\App\\Controllers\\OrdersController.cs:line 17\n   at Microsoft.AspNetCore.Mvc.Infrastructure.ActionMethodExecutor.Execute(ObjectMethodExecutor executor, Object controller, Object[] arguments)")
+	assert.NoError(t, err)
+	assert.Len(t, frames, 3)
+
+	// framework frames have no source location
+	assert.Equal(t, "Microsoft.AspNetCore.Mvc.Infrastructure.ActionMethodExecutor.Execute", *frames[0].FunctionName)
+	assert.Nil(t, frames[0].FileName)
+	assert.Equal(t, "App.Controllers.OrdersController.Get", *frames[1].FunctionName)
+	assert.Equal(t, `C:\src\App\Controllers\OrdersController.cs`, *frames[1].FileName)
+	assert.Equal(t, 17, *frames[1].LineNumber)
+	assert.Equal(t, "App.Services.OrderService.Load", *frames[2].FunctionName)
+	assert.Equal(t, "/src/App/Services/OrderService.cs", *frames[2].FileName)
+	assert.Equal(t, 42, *frames[2].LineNumber)
+	for _, frame := range frames {
+		assert.Equal(t, "System.InvalidOperationException: outer", *frame.Error)
+	}
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/openlyinc/pointy"
+	"github.com/pkg/errors"
+)
+
+const profileExtension = ".pb.gz"
+
+var profileTypePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// Profile is a gzipped pprof profile of a service. Profiles are keyed by project, service and time,
+// ie. `<project>/<service>/2023-10-16/<unix nanos>.<type>.<uuid>.pb.gz`, where the part after
+// the project is the ID of the profile.
+type Profile struct {
+	ID          string    `json:"id"`
+	ServiceName string    `json:"service_name"`
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	Size        int64     `json:"size"`
+}
+
+func ValidateProfileType(profileType string) error {
+	if !profileTypePattern.MatchString(profileType) {
+		return errors.Errorf("invalid profile type %q", profileType)
+	}
+	return nil
+}
+
+func profileDayPrefix(serviceName string, day time.Time) string {
+	return fmt.Sprintf("%s/%s/", url.PathEscape(serviceName), day.UTC().Format("2006-01-02"))
+}
+
+func newProfileID(serviceName string, profileType string, timestamp time.Time) string {
+	return fmt.Sprintf("%s%019d.%s.%s%s", profileDayPrefix(serviceName, timestamp), timestamp.UnixNano(), profileType, uuid.New().String(), profileExtension)
+}
+
+// parseProfileID returns the profile of an ID, rejecting IDs that could address objects outside of a project.
+func parseProfileID(id string) (*Profile, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || strings.Contains(id, "..") || !strings.HasSuffix(parts[2], profileExtension) {
+		return nil, errors.Errorf("invalid profile id %q", id)
+	}
+	serviceName, err := url.PathUnescape(parts[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid profile id %q", id)
+	}
+	name := strings.Split(strings.TrimSuffix(parts[2], profileExtension), ".")
+	if len(name) != 3 {
+		return nil, errors.Errorf("invalid profile id %q", id)
+	}
+	nanos, err := strconv.ParseInt(name[0], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid profile id %q", id)
+	}
+	return &Profile{
+		ID:          id,
+		ServiceName: serviceName,
+		Type:        name[1],
+		Timestamp:   time.Unix(0, nanos).UTC(),
+	}, nil
+}
+
+// profileDays returns the days of a date range, which are the prefixes that its profiles are listed from.
+func profileDays(startDate time.Time, endDate time.Time) []time.Time {
+	var days []time.Time
+	for day := startDate.UTC().Truncate(24 * time.Hour); !day.After(endDate); day = day.Add(24 * time.Hour) {
+		days = append(days, day)
+	}
+	return days
+}
+
+func compressProfile(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, errors.Wrap(err, "error compressing profile")
+	}
+	if err := gz.Close(); err != nil {
+		return nil, errors.Wrap(err, "error compressing profile")
+	}
+	return buf.Bytes(), nil
+}
+
+// filterProfiles returns the profiles of the ids within the date range, oldest first.
+func filterProfiles(ids []string, sizes []int64, startDate time.Time, endDate time.Time) []*Profile {
+	var profiles []*Profile
+	for i, id := range ids {
+		profile, err := parseProfileID(id)
+		if err != nil || profile.Timestamp.Before(startDate) || profile.Timestamp.After(endDate) {
+			continue
+		}
+		profile.Size = sizes[i]
+		profiles = append(profiles, profile)
+	}
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Timestamp.Before(profiles[j].Timestamp)
+	})
+	return profiles
+}
+
+func (f *FilesystemClient) profilePath(projectId int, id string) string {
+	return fmt.Sprintf("%s/profiles/%d/%s", f.fsRoot, projectId, id)
+}
+
+func (f *FilesystemClient) PushProfile(ctx context.Context, projectId int, profile *Profile, data []byte) error {
+	compressed, err := compressProfile(data)
+	if err != nil {
+		return err
+	}
+	profile.ID = newProfileID(profile.ServiceName, profile.Type, profile.Timestamp)
+	profile.Size, err = f.writeFSBytes(ctx, f.profilePath(projectId, profile.ID), bytes.NewReader(compressed))
+	return err
+}
+
+func (f *FilesystemClient) ListProfiles(_ context.Context, projectId int, serviceName string, startDate time.Time, endDate time.Time) ([]*Profile, error) {
+	var ids []string
+	var sizes []int64
+	for _, day := range profileDays(startDate, endDate) {
+		prefix := profileDayPrefix(serviceName, day)
+		dir, err := os.ReadDir(f.profilePath(projectId, prefix))
+		if err != nil {
+			continue
+		}
+		for _, entry := range dir {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			ids = append(ids, prefix+entry.Name())
+			sizes = append(sizes, info.Size())
+		}
+	}
+	return filterProfiles(ids, sizes, startDate, endDate), nil
+}
+
+func (f *FilesystemClient) ReadProfile(ctx context.Context, projectId int, id string) ([]byte, error) {
+	if _, err := parseProfileID(id); err != nil {
+		return nil, err
+	}
+	buf, err := f.readFSBytes(ctx, f.profilePath(projectId, id))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *S3Client) profileKey(projectId int, id string) *string {
+	var key string
+	if util.IsDevEnv() {
+		key = "dev/"
+	}
+	key += fmt.Sprintf("%d/%s", projectId, id)
+	return pointy.String(key)
+}
+
+func (s *S3Client) PushProfile(ctx context.Context, projectId int, profile *Profile, data []byte) error {
+	compressed, err := compressProfile(data)
+	if err != nil {
+		return err
+	}
+	profile.ID = newProfileID(profile.ServiceName, profile.Type, profile.Timestamp)
+	_, err = s.S3ClientEast2.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      pointy.String(S3ProfilesBucketName),
+		Key:         s.profileKey(projectId, profile.ID),
+		Body:        bytes.NewReader(compressed),
+		ContentType: pointy.String("application/octet-stream"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "error 'put'ing profile in s3 bucket")
+	}
+	profile.Size = int64(len(compressed))
+	return nil
+}
+
+func (s *S3Client) ListProfiles(ctx context.Context, projectId int, serviceName string, startDate time.Time, endDate time.Time) ([]*Profile, error) {
+	projectPrefix := *s.profileKey(projectId, "")
+	var ids []string
+	var sizes []int64
+	for _, day := range profileDays(startDate, endDate) {
+		paginator := s3.NewListObjectsV2Paginator(s.S3ClientEast2, &s3.ListObjectsV2Input{
+			Bucket: pointy.String(S3ProfilesBucketName),
+			Prefix: pointy.String(projectPrefix + profileDayPrefix(serviceName, day)),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, errors.Wrap(err, "error listing profiles from s3")
+			}
+			for _, object := range page.Contents {
+				ids = append(ids, strings.TrimPrefix(ptr.ToString(object.Key), projectPrefix))
+				sizes = append(sizes, object.Size)
+			}
+		}
+	}
+	return filterProfiles(ids, sizes, startDate, endDate), nil
+}
+
+func (s *S3Client) ReadProfile(ctx context.Context, projectId int, id string) ([]byte, error) {
+	if _, err := parseProfileID(id); err != nil {
+		return nil, err
+	}
+	output, err := s.S3ClientEast2.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: pointy.String(S3ProfilesBucketName),
+		Key:    s.profileKey(projectId, id),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting profile from s3")
+	}
+	defer output.Body.Close()
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(output.Body); err != nil {
+		return nil, errors.Wrap(err, "error reading from s3 buffer")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/openlyinc/pointy"
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+	gcs "google.golang.org/api/storage/v1"
+)
+
+// SourcemapBucketReader reads the files of a project from its private sourcemap bucket.
+type SourcemapBucketReader interface {
+	ReadSourceMapFile(ctx context.Context, version *string, filePath string) ([]byte, error)
+}
+
+type sourcemapBucketReader struct {
+	bucket *model.SourcemapBucket
+	read   func(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+func (r *sourcemapBucketReader) ReadSourceMapFile(ctx context.Context, version *string, filePath string) ([]byte, error) {
+	key, ok := r.bucket.Key(version, filePath)
+	if !ok {
+		return nil, errors.Errorf("the prefix of sourcemap bucket %s needs a release version", r.bucket.Bucket)
+	}
+	body, err := r.read(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s from sourcemap bucket %s", key, r.bucket.Bucket)
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+var sourcemapBucketReaders = newSourcemapBucketReaders()
+
+func newSourcemapBucketReaders() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetTTL(time.Hour)
+	return cache
+}
+
+// GetSourcemapBucketReader returns the reader of a sourcemap bucket. Readers are cached until the
+// bucket is edited, since assuming a role or authorizing a service account is slow, and their
+// credentials are refreshed as they expire.
+func GetSourcemapBucketReader(ctx context.Context, bucket *model.SourcemapBucket) (SourcemapBucketReader, error) {
+	cacheKey := fmt.Sprintf("%d;%d", bucket.ID, bucket.UpdatedAt.UnixNano())
+	if cached, ok := sourcemapBucketReaders.Get(cacheKey); ok {
+		return cached.(SourcemapBucketReader), nil
+	}
+
+	reader := &sourcemapBucketReader{bucket: bucket}
+	switch bucket.Provider {
+	case model.SourcemapBucketProviderS3:
+		if bucket.Region == nil || bucket.RoleARN == nil {
+			return nil, errors.New("s3 sourcemap buckets need a region and role")
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*bucket.Region))
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading default from config")
+		}
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), *bucket.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.ExternalID = pointy.String(bucket.ExternalID)
+			o.RoleSessionName = fmt.Sprintf("highlight-sourcemaps-%d", bucket.ProjectID)
+		}))
+		client := s3.NewFromConfig(cfg)
+		reader.read = func(ctx context.Context, key string) (io.ReadCloser, error) {
+			output, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: pointy.String(bucket.Bucket), Key: pointy.String(key)})
+			if err != nil {
+				return nil, err
+			}
+			return output.Body, nil
+		}
+	case model.SourcemapBucketProviderGCS:
+		if bucket.ServiceAccountKey == nil {
+			return nil, errors.New("gcs sourcemap buckets need a service account key")
+		}
+		// the service outlives the request, so its token source cannot use the request context
+		service, err := gcs.NewService(context.Background(), option.WithCredentialsJSON([]byte(*bucket.ServiceAccountKey)), option.WithScopes(gcs.DevstorageReadOnlyScope))
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating gcs client")
+		}
+		reader.read = func(ctx context.Context, key string) (io.ReadCloser, error) {
+			res, err := service.Objects.Get(bucket.Bucket, key).Context(ctx).Download()
+			if err != nil {
+				return nil, err
+			}
+			return res.Body, nil
+		}
+	default:
+		return nil, errors.Errorf("invalid sourcemap bucket provider %q", bucket.Provider)
+	}
+
+	sourcemapBucketReaders.Set(cacheKey, reader)
+	return reader, nil
+}
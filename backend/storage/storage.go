@@ -47,6 +47,7 @@ var (
 	S3SourceMapBucketNameNew       = os.Getenv("AWS_S3_SOURCE_MAP_BUCKET_NAME_NEW")
 	S3ResourcesBucketName          = os.Getenv("AWS_S3_RESOURCES_BUCKET")
 	S3GithubBucketName             = os.Getenv("AWS_S3_GITHUB_BUCKET_NAME")
+	S3ProfilesBucketName           = os.Getenv("AWS_S3_PROFILES_BUCKET_NAME")
 	CloudfrontDomain               = os.Getenv("AWS_CLOUDFRONT_DOMAIN")
 	CloudfrontPublicKeyID          = os.Getenv("AWS_CLOUDFRONT_PUBLIC_KEY_ID")
 	CloudfrontPrivateKey           = os.Getenv("AWS_CLOUDFRONT_PRIVATE_KEY")
@@ -97,6 +98,9 @@ type Client interface {
 	UploadAsset(ctx context.Context, uuid string, contentType string, reader io.Reader, retentionPeriod privateModel.RetentionPeriod) error
 	ReadGitHubFile(ctx context.Context, repoPath string, fileName string, version string) ([]byte, error)
 	PushGitHubFile(ctx context.Context, repoPath string, fileName string, version string, fileBytes []byte) (*int64, error)
+	PushProfile(ctx context.Context, projectId int, profile *Profile, data []byte) error
+	ListProfiles(ctx context.Context, projectId int, serviceName string, startDate time.Time, endDate time.Time) ([]*Profile, error)
+	ReadProfile(ctx context.Context, projectId int, id string) ([]byte, error)
 }
 
 type FilesystemClient struct {
@@ -248,12 +252,11 @@ func (f *FilesystemClient) PushRawEvents(ctx context.Context, sessionId, project
 }
 
 func (f *FilesystemClient) PushSourceMapFile(ctx context.Context, projectId int, version *string, fileName string, fileBytes []byte) (*int64, error) {
-	buf := new(bytes.Buffer)
-	_, err := buf.Read(fileBytes)
-	if err != nil {
-		return nil, errors.Wrap(err, "error reading from memory buffer")
+	if version == nil {
+		unversioned := "unversioned"
+		version = &unversioned
 	}
-	if n, err := f.writeFSBytes(ctx, fmt.Sprintf("%s/%d/%s/%s", f.fsRoot, projectId, *version, fileName), buf); err != nil {
+	if n, err := f.writeFSBytes(ctx, fmt.Sprintf("%s/%d/%s/%s", f.fsRoot, projectId, *version, fileName), bytes.NewReader(fileBytes)); err != nil {
 		return pointy.Int64(0), err
 	} else {
 		return &n, nil
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/opsgenie"
+	"github.com/highlight-run/highlight/backend/pagerduty"
+	"github.com/highlight-run/highlight/backend/splunkoncall"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrorAlertDestinations are the incident management destinations of a project's error alerts,
+// deduplicated across the alerts.
+type ErrorAlertDestinations struct {
+	PagerDuty    []*model.PagerDutyDestination
+	Opsgenie     []*model.OpsgenieDestination
+	SplunkOnCall []*model.SplunkOnCallDestination
+}
+
+func (store *Store) GetErrorAlertDestinations(ctx context.Context, projectID int) (*ErrorAlertDestinations, error) {
+	var errorAlerts []*model.ErrorAlert
+	if err := store.db.WithContext(ctx).
+		Where(&model.ErrorAlert{Alert: model.Alert{ProjectID: projectID}}).
+		Where("pager_duty_destinations <> '[]'::jsonb OR opsgenie_destinations <> '[]'::jsonb OR splunk_on_call_destinations <> '[]'::jsonb").
+		Find(&errorAlerts).Error; err != nil {
+		return nil, err
+	}
+
+	destinations := &ErrorAlertDestinations{}
+	for _, errorAlert := range errorAlerts {
+		destinations.PagerDuty = append(destinations.PagerDuty, errorAlert.PagerDutyDestinations...)
+		destinations.Opsgenie = append(destinations.Opsgenie, errorAlert.OpsgenieDestinations...)
+		destinations.SplunkOnCall = append(destinations.SplunkOnCall, errorAlert.SplunkOnCallDestinations...)
+	}
+	destinations.PagerDuty = lo.UniqBy(destinations.PagerDuty, func(d *model.PagerDutyDestination) string {
+		return d.RoutingKey
+	})
+	destinations.Opsgenie = lo.UniqBy(destinations.Opsgenie, func(d *model.OpsgenieDestination) string {
+		return d.Region + "/" + d.APIKey
+	})
+	destinations.SplunkOnCall = lo.UniqBy(destinations.SplunkOnCall, func(d *model.SplunkOnCallDestination) string {
+		return d.APIKey + "/" + d.RoutingKey
+	})
+	return destinations, nil
+}
+
+// updateAlertIncidents resolves the incidents that the error alerts opened for an error group when
+// it is resolved or ignored, and acknowledges them when it is snoozed. Failures are only logged so
+// that they do not fail the state update.
+func (store *Store) updateAlertIncidents(ctx context.Context, errorGroup *model.ErrorGroup, eventType model.ErrorGroupEventType) {
+	var resolve bool
+	var err error
+	switch eventType {
+	case model.ErrorGroupResolvedEvent, model.ErrorGroupIgnoredEvent:
+		resolve = true
+	case model.ErrorGroupSnoozedEvent:
+		resolve = false
+	default:
+		return
+	}
+
+	logger := log.WithContext(ctx).WithField("error_group_id", errorGroup.ID)
+
+	escalationKey := alerts.ErrorGroupEscalationKey(errorGroup.ID)
+	if resolve {
+		err = alerts.ResolveEscalations(ctx, store.db, errorGroup.ProjectID, escalationKey)
+	} else {
+		err = alerts.AcknowledgeEscalations(ctx, store.db, errorGroup.ProjectID, escalationKey)
+	}
+	if err != nil {
+		logger.WithError(err).Error("error updating escalations of error group")
+	}
+
+	destinations, err := store.GetErrorAlertDestinations(ctx, errorGroup.ProjectID)
+	if err != nil {
+		logger.WithError(err).Error("error querying error alert destinations")
+		return
+	}
+
+	action, note := pagerduty.ActionAcknowledge, "The error group was snoozed on Highlight."
+	if resolve {
+		action, note = pagerduty.ActionResolve, "The error group was resolved on Highlight."
+	}
+
+	pagerDutyClient := pagerduty.NewClient()
+	for _, destination := range destinations.PagerDuty {
+		if _, err := pagerDutyClient.Send(ctx, &pagerduty.Event{
+			RoutingKey:  destination.RoutingKey,
+			EventAction: action,
+			DedupKey:    pagerduty.ErrorGroupDedupKey(errorGroup.ID),
+		}); err != nil {
+			logger.WithError(err).Error("error updating PagerDuty incident")
+		}
+	}
+
+	for _, destination := range destinations.Opsgenie {
+		client := opsgenie.NewClient(destination.APIKey, opsgenie.Region(destination.Region))
+		alias := opsgenie.ErrorGroupAlias(errorGroup.ID)
+		if resolve {
+			_, err = client.CloseAlert(ctx, alias, note)
+		} else {
+			_, err = client.AcknowledgeAlert(ctx, alias, note)
+		}
+		if err != nil {
+			logger.WithError(err).Error("error updating Opsgenie alert")
+		}
+	}
+
+	for _, destination := range destinations.SplunkOnCall {
+		client := splunkoncall.NewClient(destination.APIKey, destination.RoutingKey)
+		entityID := splunkoncall.ErrorGroupEntityID(errorGroup.ID)
+		if resolve {
+			_, err = client.Resolve(ctx, entityID, note)
+		} else {
+			_, err = client.Acknowledge(ctx, entityID, note)
+		}
+		if err != nil {
+			logger.WithError(err).Error("error updating Splunk On-Call incident")
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrorAlertDestinations(t *testing.T) {
+	defer teardown(t)
+	ctx := context.TODO()
+
+	errorAlerts := []*model.ErrorAlert{
+		{Alert: model.Alert{ProjectID: 1}},
+		{Alert: model.Alert{ProjectID: 1}, PagerDutyDestinations: model.PagerDutyDestinations{{RoutingKey: "abc"}, {RoutingKey: "def", Severity: "critical"}}},
+		{Alert: model.Alert{ProjectID: 1}, PagerDutyDestinations: model.PagerDutyDestinations{{RoutingKey: "abc"}}},
+		{Alert: model.Alert{ProjectID: 1}, OpsgenieDestinations: model.OpsgenieDestinations{{APIKey: "key", Region: "eu"}}},
+		{Alert: model.Alert{ProjectID: 1}, SplunkOnCallDestinations: model.SplunkOnCallDestinations{{APIKey: "key", RoutingKey: "team"}, {APIKey: "key", RoutingKey: "team"}}},
+		{Alert: model.Alert{ProjectID: 2}, PagerDutyDestinations: model.PagerDutyDestinations{{RoutingKey: "ghi"}}},
+	}
+	assert.NoError(t, store.db.Create(&errorAlerts).Error)
+
+	destinations, err := store.GetErrorAlertDestinations(ctx, 1)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"abc", "def"}, lo.Map(destinations.PagerDuty, func(d *model.PagerDutyDestination, _ int) string {
+		return d.RoutingKey
+	}))
+	assert.Equal(t, []*model.OpsgenieDestination{{APIKey: "key", Region: "eu"}}, destinations.Opsgenie)
+	assert.Equal(t, []*model.SplunkOnCallDestination{{APIKey: "key", RoutingKey: "team"}}, destinations.SplunkOnCall)
+
+	destinations, err = store.GetErrorAlertDestinations(ctx, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, destinations.PagerDuty)
+	assert.Empty(t, destinations.Opsgenie)
+	assert.Empty(t, destinations.SplunkOnCall)
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+// apiTokenLastUsedResolution is how often the last use of an API token is recorded, so that
+// automation making many requests does not update the token on every request.
+const apiTokenLastUsedResolution = time.Minute
+
+// GetActiveAPIToken returns the API token that is not revoked or expired. Tokens are not cached so
+// that revoking a token takes effect immediately.
+func (store *Store) GetActiveAPIToken(ctx context.Context, token string) (*model.APIToken, error) {
+	var apiToken model.APIToken
+	if err := store.db.WithContext(ctx).
+		Where(&model.APIToken{TokenHash: apitoken.Hash(token)}).
+		Where("revoked_at IS NULL").
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Take(&apiToken).Error; err != nil {
+		return nil, err
+	}
+	return &apiToken, nil
+}
+
+// MarkAPITokenUsed records the last use of an API token.
+func (store *Store) MarkAPITokenUsed(ctx context.Context, apiToken *model.APIToken) error {
+	now := time.Now()
+	if apiToken.LastUsedAt != nil && now.Sub(*apiToken.LastUsedAt) < apiTokenLastUsedResolution {
+		return nil
+	}
+	apiToken.LastUsedAt = &now
+	return store.db.WithContext(ctx).Model(apiToken).UpdateColumn("last_used_at", now).Error
+}
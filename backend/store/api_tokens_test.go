@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetActiveAPIToken(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tokens := map[string]*model.APIToken{}
+	for name, apiToken := range map[string]*model.APIToken{
+		"active":    {},
+		"unexpired": {ExpiresAt: &future},
+		"expired":   {ExpiresAt: &past},
+		"revoked":   {RevokedAt: &past},
+	} {
+		token, hash, err := apitoken.Generate()
+		require.NoError(t, err)
+		apiToken.Name = name
+		apiToken.TokenHash = hash
+		require.NoError(t, store.db.Create(apiToken).Error)
+		tokens[token] = apiToken
+	}
+
+	for token, apiToken := range tokens {
+		result, err := store.GetActiveAPIToken(ctx, token)
+		if apiToken.Name == "expired" || apiToken.Name == "revoked" {
+			assert.Error(t, err, apiToken.Name)
+			continue
+		}
+		require.NoError(t, err, apiToken.Name)
+		assert.Equal(t, apiToken.ID, result.ID)
+	}
+
+	_, err := store.GetActiveAPIToken(ctx, "hlt_unknown")
+	assert.Error(t, err)
+}
+
+func TestMarkAPITokenUsed(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	apiToken := model.APIToken{TokenHash: apitoken.Hash("hlt_abc")}
+	require.NoError(t, store.db.Create(&apiToken).Error)
+
+	require.NoError(t, store.MarkAPITokenUsed(ctx, &apiToken))
+	require.NotNil(t, apiToken.LastUsedAt)
+	lastUsedAt := *apiToken.LastUsedAt
+
+	require.NoError(t, store.MarkAPITokenUsed(ctx, &apiToken))
+	assert.Equal(t, lastUsedAt, *apiToken.LastUsedAt)
+
+	var stored model.APIToken
+	require.NoError(t, store.db.Take(&stored, apiToken.ID).Error)
+	require.NotNil(t, stored.LastUsedAt)
+	assert.WithinDuration(t, lastUsedAt, *stored.LastUsedAt, time.Millisecond)
+}
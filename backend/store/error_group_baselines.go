@@ -0,0 +1,20 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+// GetErrorGroupBaseline returns the baseline of an error group for an hour of the week, or nil if the
+// group has none yet.
+func (store *Store) GetErrorGroupBaseline(ctx context.Context, errorGroupID int, hourOfWeek int) (*model.ErrorGroupBaseline, error) {
+	var baselines []*model.ErrorGroupBaseline
+	if err := store.db.WithContext(ctx).Where(&model.ErrorGroupBaseline{ErrorGroupID: errorGroupID, HourOfWeek: hourOfWeek}).Limit(1).Find(&baselines).Error; err != nil {
+		return nil, err
+	}
+	if len(baselines) == 0 {
+		return nil, nil
+	}
+	return baselines[0], nil
+}
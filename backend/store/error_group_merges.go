@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	kafka_queue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// MergeErrorGroups moves the error objects of the source error groups into the target error group.
+// The source error groups are resolved and remember the target, so that their new errors are
+// grouped into it.
+func (store *Store) MergeErrorGroups(ctx context.Context, admin model.Admin, targetID int, sourceIDs []int) (model.ErrorGroup, error) {
+	sourceIDs = lo.Uniq(sourceIDs)
+	var target model.ErrorGroup
+	if err := store.db.WithContext(ctx).Where(&model.ErrorGroup{Model: model.Model{ID: targetID}}).Take(&target).Error; err != nil {
+		return target, err
+	}
+	if target.MergedIntoID != nil {
+		return target, errors.New("cannot merge into an error group that was merged")
+	}
+
+	var sources []*model.ErrorGroup
+	if err := store.db.WithContext(ctx).Where("id IN ? AND project_id = ?", sourceIDs, target.ProjectID).Find(&sources).Error; err != nil {
+		return target, err
+	}
+	if len(sources) != len(sourceIDs) {
+		return target, errors.New("error groups to merge were not found")
+	}
+	for _, source := range sources {
+		if source.ID == target.ID {
+			return target, errors.New("cannot merge an error group into itself")
+		}
+	}
+
+	var errorObjectIDs []int
+	if err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`UPDATE error_objects SET error_group_id = ? WHERE error_group_id IN ? RETURNING id`,
+			target.ID, sourceIDs).Scan(&errorObjectIDs).Error; err != nil {
+			return err
+		}
+		// error groups that were merged into a source are redirected to the target
+		if err := tx.Model(&model.ErrorGroup{}).Where("merged_into_id IN ?", sourceIDs).
+			Update("merged_into_id", target.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.ErrorGroup{}).Where("id IN ?", sourceIDs).Updates(map[string]interface{}{
+			"MergedIntoID":    target.ID,
+			"State":           privateModel.ErrorStateResolved,
+			"SnoozedUntil":    nil,
+			"SnoozedVersion":  nil,
+			"ResolvedVersion": nil,
+		}).Error
+	}); err != nil {
+		return target, err
+	}
+
+	for _, source := range sources {
+		source.MergedIntoID = &target.ID
+		source.State = privateModel.ErrorStateResolved
+		source.SnoozedUntil = nil
+		source.SnoozedVersion = nil
+		source.ResolvedVersion = nil
+		if err := store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+			Admin:        &admin,
+			EventType:    model.ErrorGroupMergedEvent,
+			ErrorGroupID: source.ID,
+			EventData:    map[string]interface{}{"MergedIntoID": target.ID},
+		}); err != nil {
+			return target, err
+		}
+	}
+	if err := store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+		Admin:        &admin,
+		EventType:    model.ErrorGroupMergedEvent,
+		ErrorGroupID: target.ID,
+		EventData:    map[string]interface{}{"MergedErrorGroupIDs": sourceIDs},
+	}); err != nil {
+		return target, err
+	}
+
+	return target, store.syncMovedErrorObjects(ctx, append(sources, &target), errorObjectIDs)
+}
+
+// SplitErrorGroup moves error objects of an error group into a new error group, created from the
+// latest of them. New errors are still grouped by their fingerprints, so an error grouping rule is
+// needed to keep grouping them apart.
+func (store *Store) SplitErrorGroup(ctx context.Context, admin model.Admin, errorGroupID int, errorObjectIDs []int) (model.ErrorGroup, error) {
+	errorObjectIDs = lo.Uniq(errorObjectIDs)
+	var errorGroup model.ErrorGroup
+	if err := store.db.WithContext(ctx).Where(&model.ErrorGroup{Model: model.Model{ID: errorGroupID}}).Take(&errorGroup).Error; err != nil {
+		return errorGroup, err
+	}
+
+	var errorObjects []*model.ErrorObject
+	if err := store.db.WithContext(ctx).Where("id IN ? AND error_group_id = ?", errorObjectIDs, errorGroup.ID).
+		Order("id DESC").Find(&errorObjects).Error; err != nil {
+		return errorGroup, err
+	}
+	if len(errorObjects) == 0 || len(errorObjects) != len(errorObjectIDs) {
+		return errorGroup, errors.New("error objects to split were not found in the error group")
+	}
+
+	var total int64
+	if err := store.db.WithContext(ctx).Model(&model.ErrorObject{}).Where("error_group_id = ?", errorGroup.ID).Count(&total).Error; err != nil {
+		return errorGroup, err
+	}
+	if int(total) == len(errorObjects) {
+		return errorGroup, errors.New("cannot split all the error objects of an error group")
+	}
+
+	latest := errorObjects[0]
+	newErrorGroup := model.ErrorGroup{
+		ProjectID:        errorGroup.ProjectID,
+		Event:            latest.Event,
+		MappedStackTrace: latest.MappedStackTrace,
+		Type:             latest.Type,
+		State:            privateModel.ErrorStateOpen,
+		Fields:           []*model.ErrorField{},
+		ServiceName:      latest.ServiceName,
+		AssigneeID:       errorGroup.AssigneeID,
+	}
+	if latest.StackTrace != nil {
+		newErrorGroup.StackTrace = *latest.StackTrace
+	}
+
+	if err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newErrorGroup).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.ErrorObject{}).Where("id IN ? AND error_group_id = ?", errorObjectIDs, errorGroup.ID).
+			Update("error_group_id", newErrorGroup.ID).Error
+	}); err != nil {
+		return newErrorGroup, err
+	}
+
+	if err := store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+		Admin:        &admin,
+		EventType:    model.ErrorGroupSplitEvent,
+		ErrorGroupID: errorGroup.ID,
+		EventData:    map[string]interface{}{"SplitErrorGroupID": newErrorGroup.ID},
+	}); err != nil {
+		return newErrorGroup, err
+	}
+	if err := store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+		Admin:        &admin,
+		EventType:    model.ErrorGroupSplitEvent,
+		ErrorGroupID: newErrorGroup.ID,
+		EventData:    map[string]interface{}{"SplitFromErrorGroupID": errorGroup.ID},
+	}); err != nil {
+		return newErrorGroup, err
+	}
+
+	return newErrorGroup, store.syncMovedErrorObjects(ctx, []*model.ErrorGroup{&errorGroup, &newErrorGroup}, errorObjectIDs)
+}
+
+// syncMovedErrorObjects writes error groups whose error objects were moved directly to Clickhouse,
+// and writes them and the moved error objects to the data sync queue.
+func (store *Store) syncMovedErrorObjects(ctx context.Context, errorGroups []*model.ErrorGroup, errorObjectIDs []int) error {
+	if err := store.clickhouseClient.WriteErrorGroups(ctx, errorGroups); err != nil {
+		return err
+	}
+
+	for _, errorGroup := range errorGroups {
+		if err := store.dataSyncQueue.Submit(ctx, strconv.Itoa(errorGroup.ID), &kafka_queue.Message{Type: kafka_queue.ErrorGroupDataSync, ErrorGroupDataSync: &kafka_queue.ErrorGroupDataSyncArgs{ErrorGroupID: errorGroup.ID}}); err != nil {
+			return err
+		}
+	}
+	for _, errorObjectID := range errorObjectIDs {
+		if err := store.dataSyncQueue.Submit(ctx, strconv.Itoa(errorObjectID), &kafka_queue.Message{Type: kafka_queue.ErrorObjectDataSync, ErrorObjectDataSync: &kafka_queue.ErrorObjectDataSyncArgs{ErrorObjectID: errorObjectID}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+)
+
+func (store *Store) GetErrorGroupingRules(ctx context.Context, projectID int) ([]*model.ErrorGroupingRule, error) {
+	var rules []*model.ErrorGroupingRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorGroupingRule{ProjectID: projectID}).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetEnabledErrorGroupingRules is called for every error that is grouped, so the lookup is
+// cached briefly.
+func (store *Store) GetEnabledErrorGroupingRules(ctx context.Context, projectID int) ([]*model.ErrorGroupingRule, error) {
+	rules, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("error-grouping-rules-%d", projectID), 150*time.Millisecond, time.Minute, func() (*[]*model.ErrorGroupingRule, error) {
+		var rules []*model.ErrorGroupingRule
+		if err := store.db.WithContext(ctx).Where(&model.ErrorGroupingRule{ProjectID: projectID}).
+			Where("disabled = ?", false).Order("created_at ASC").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+func (store *Store) GetErrorGroupingRule(ctx context.Context, projectID int, ruleID int) (*model.ErrorGroupingRule, error) {
+	var rule model.ErrorGroupingRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorGroupingRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Take(&rule).Error
+	return &rule, err
+}
+
+func (store *Store) CreateErrorGroupingRule(ctx context.Context, rule *model.ErrorGroupingRule) error {
+	return store.db.WithContext(ctx).Create(rule).Error
+}
+
+func (store *Store) UpdateErrorGroupingRule(ctx context.Context, rule *model.ErrorGroupingRule) error {
+	return store.db.WithContext(ctx).Model(rule).Select(
+		"name", "type", "event_regex", "service_name", "disabled", "last_admin_to_edit_id",
+	).Updates(rule).Error
+}
+
+func (store *Store) DeleteErrorGroupingRule(ctx context.Context, projectID int, ruleID int) error {
+	return store.db.WithContext(ctx).Where(&model.ErrorGroupingRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Delete(&model.ErrorGroupingRule{}).Error
+}
@@ -187,9 +187,14 @@ func (store *Store) ListErrorObjects(errorGroup model.ErrorGroup, params ListErr
 }
 
 type UpdateErrorGroupParams struct {
-	ID           int
-	State        privateModel.ErrorState
-	SnoozedUntil *time.Time
+	ID             int
+	State          privateModel.ErrorState
+	SnoozedUntil   *time.Time
+	SnoozedVersion *string
+	// Set when resolved in a service version, so that only errors of that version or a newer one reopen it.
+	ResolvedVersion *string
+	// The workflow rule that triggered a system update, recorded in the activity log.
+	RuleID *int
 }
 
 func (store *Store) UpdateErrorGroupStateByAdmin(ctx context.Context,
@@ -207,13 +212,19 @@ func (store *Store) updateErrorGroupState(ctx context.Context,
 
 	var errorGroup model.ErrorGroup
 
+	if params.ResolvedVersion != nil && params.State != privateModel.ErrorStateResolved {
+		return errorGroup, errors.New("only resolved error groups can have a resolved version")
+	}
+
 	if err := store.db.WithContext(ctx).Where(&model.ErrorGroup{
 		Model: model.Model{
 			ID: params.ID,
 		},
 	}).Take(&errorGroup).Updates(map[string]interface{}{
-		"State":        params.State,
-		"SnoozedUntil": params.SnoozedUntil,
+		"State":           params.State,
+		"SnoozedUntil":    params.SnoozedUntil,
+		"SnoozedVersion":  params.SnoozedVersion,
+		"ResolvedVersion": params.ResolvedVersion,
 	}).Error; err != nil {
 		return errorGroup, err
 	}
@@ -222,12 +233,24 @@ func (store *Store) updateErrorGroupState(ctx context.Context,
 	if err != nil {
 		return errorGroup, err
 	}
+	if eventType == model.ErrorGroupOpenedEvent && params.SnoozedUntil != nil {
+		eventType = model.ErrorGroupSnoozedEvent
+	}
 
 	eventData := map[string]interface{}{}
 
 	if params.SnoozedUntil != nil {
 		eventData["SnoozedUntil"] = params.SnoozedUntil
 	}
+	if params.SnoozedVersion != nil {
+		eventData["SnoozedVersion"] = params.SnoozedVersion
+	}
+	if params.ResolvedVersion != nil {
+		eventData["ResolvedVersion"] = params.ResolvedVersion
+	}
+	if params.RuleID != nil {
+		eventData["RuleID"] = params.RuleID
+	}
 
 	err = store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
 		Admin:        admin,
@@ -253,10 +276,76 @@ func (store *Store) updateErrorGroupState(ctx context.Context,
 		return errorGroup, err
 	}
 
+	store.updateAlertIncidents(ctx, &errorGroup, eventType)
+
 	return errorGroup, nil
 
 }
 
+type AssignErrorGroupParams struct {
+	ID int
+	// The admin the error group is assigned to, or nil to unassign it.
+	AssigneeID *int
+	// The ownership rule that assigned the error group, recorded in the activity log.
+	OwnershipRuleID *int
+}
+
+func (store *Store) AssignErrorGroupByAdmin(ctx context.Context,
+	admin model.Admin, params AssignErrorGroupParams) (model.ErrorGroup, error) {
+	return store.assignErrorGroup(ctx, &admin, params)
+}
+
+func (store *Store) AssignErrorGroupBySystem(ctx context.Context,
+	params AssignErrorGroupParams) (model.ErrorGroup, error) {
+	return store.assignErrorGroup(ctx, nil, params)
+}
+
+func (store *Store) assignErrorGroup(ctx context.Context,
+	admin *model.Admin, params AssignErrorGroupParams) (model.ErrorGroup, error) {
+
+	var errorGroup model.ErrorGroup
+
+	if err := store.db.WithContext(ctx).Where(&model.ErrorGroup{
+		Model: model.Model{
+			ID: params.ID,
+		},
+	}).Take(&errorGroup).Updates(map[string]interface{}{
+		"AssigneeID": params.AssigneeID,
+	}).Error; err != nil {
+		return errorGroup, err
+	}
+
+	eventData := map[string]interface{}{}
+
+	if params.AssigneeID != nil {
+		eventData["AssigneeID"] = params.AssigneeID
+	}
+	if params.OwnershipRuleID != nil {
+		eventData["OwnershipRuleID"] = params.OwnershipRuleID
+	}
+
+	if err := store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+		Admin:        admin,
+		EventType:    model.ErrorGroupAssignedEvent,
+		ErrorGroupID: errorGroup.ID,
+		EventData:    eventData,
+	}); err != nil {
+		return errorGroup, err
+	}
+
+	if admin != nil {
+		if err := store.clickhouseClient.WriteErrorGroups(ctx, []*model.ErrorGroup{&errorGroup}); err != nil {
+			return errorGroup, err
+		}
+	}
+
+	if err := store.dataSyncQueue.Submit(ctx, strconv.Itoa(errorGroup.ID), &kafka_queue.Message{Type: kafka_queue.ErrorGroupDataSync, ErrorGroupDataSync: &kafka_queue.ErrorGroupDataSyncArgs{ErrorGroupID: errorGroup.ID}}); err != nil {
+		return errorGroup, err
+	}
+
+	return errorGroup, nil
+}
+
 func getEventType(state privateModel.ErrorState) (model.ErrorGroupEventType, error) {
 	var event model.ErrorGroupEventType
 
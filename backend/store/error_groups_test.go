@@ -412,3 +412,141 @@ func TestUpdateErrorGroupStateBySystem(t *testing.T) {
 	assert.Equal(t, activityLogs[0].EventType, model.ErrorGroupIgnoredEvent)
 	assert.NotNil(t, activityLogs[0].EventData)
 }
+
+func TestAssignErrorGroupBySystem(t *testing.T) {
+	defer teardown(t)
+	errorGroup := model.ErrorGroup{
+		State: privateModel.ErrorStateOpen,
+	}
+	store.db.Create(&errorGroup)
+
+	admin := model.Admin{}
+	store.db.Create(&admin)
+
+	ruleID := 1
+	updatedErrorGroup, err := store.AssignErrorGroupBySystem(context.TODO(), AssignErrorGroupParams{
+		ID:              errorGroup.ID,
+		AssigneeID:      &admin.ID,
+		OwnershipRuleID: &ruleID,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.ID, updatedErrorGroup.AssigneeID)
+
+	activityLogs, err := store.GetErrorGroupActivityLogs(errorGroup.ID)
+	assert.NoError(t, err)
+
+	assert.Len(t, activityLogs, 1)
+	assert.Equal(t, activityLogs[0].AdminID, 0)
+	assert.Equal(t, activityLogs[0].EventType, model.ErrorGroupAssignedEvent)
+
+	updatedErrorGroup, err = store.AssignErrorGroupBySystem(context.TODO(), AssignErrorGroupParams{ID: errorGroup.ID})
+	assert.NoError(t, err)
+	assert.Nil(t, updatedErrorGroup.AssigneeID)
+}
+
+func TestUpdateErrorGroupStateResolvedVersion(t *testing.T) {
+	defer teardown(t)
+	errorGroup := model.ErrorGroup{
+		State: privateModel.ErrorStateOpen,
+	}
+	store.db.Create(&errorGroup)
+
+	version := "1.2.0"
+	_, err := store.UpdateErrorGroupStateBySystem(context.TODO(), UpdateErrorGroupParams{
+		ID:              errorGroup.ID,
+		State:           privateModel.ErrorStateIgnored,
+		ResolvedVersion: &version,
+	})
+	assert.Error(t, err)
+
+	updatedErrorGroup, err := store.UpdateErrorGroupStateBySystem(context.TODO(), UpdateErrorGroupParams{
+		ID:              errorGroup.ID,
+		State:           privateModel.ErrorStateResolved,
+		ResolvedVersion: &version,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &version, updatedErrorGroup.ResolvedVersion)
+	assert.Equal(t, model.ErrorResolutionResolvedInVersion, updatedErrorGroup.GetResolutionState(time.Now()))
+
+	// resolving without a version clears it
+	updatedErrorGroup, err = store.UpdateErrorGroupStateBySystem(context.TODO(), UpdateErrorGroupParams{
+		ID:    errorGroup.ID,
+		State: privateModel.ErrorStateResolved,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, updatedErrorGroup.ResolvedVersion)
+}
+
+func TestMergeErrorGroups(t *testing.T) {
+	defer teardown(t)
+	target := model.ErrorGroup{State: privateModel.ErrorStateOpen}
+	store.db.Create(&target)
+	source := model.ErrorGroup{State: privateModel.ErrorStateOpen}
+	store.db.Create(&source)
+	merged := model.ErrorGroup{State: privateModel.ErrorStateResolved, MergedIntoID: &source.ID}
+	store.db.Create(&merged)
+	errorObject := model.ErrorObject{ErrorGroupID: source.ID}
+	store.db.Create(&errorObject)
+
+	admin := model.Admin{}
+	store.db.Create(&admin)
+
+	_, err := store.MergeErrorGroups(context.TODO(), admin, target.ID, []int{target.ID})
+	assert.Error(t, err)
+
+	_, err = store.MergeErrorGroups(context.TODO(), admin, target.ID, []int{source.ID})
+	assert.NoError(t, err)
+
+	store.db.Take(&errorObject, errorObject.ID)
+	assert.Equal(t, target.ID, errorObject.ErrorGroupID)
+
+	var updatedSource model.ErrorGroup
+	store.db.Take(&updatedSource, source.ID)
+	assert.Equal(t, &target.ID, updatedSource.MergedIntoID)
+	assert.Equal(t, privateModel.ErrorStateResolved, updatedSource.State)
+
+	// error groups that were merged into the source now point to the target
+	var updatedMerged model.ErrorGroup
+	store.db.Take(&updatedMerged, merged.ID)
+	assert.Equal(t, &target.ID, updatedMerged.MergedIntoID)
+
+	activityLogs, err := store.GetErrorGroupActivityLogs(source.ID)
+	assert.NoError(t, err)
+	assert.Len(t, activityLogs, 1)
+	assert.Equal(t, model.ErrorGroupMergedEvent, activityLogs[0].EventType)
+
+	// error groups cannot be merged into a merged error group
+	_, err = store.MergeErrorGroups(context.TODO(), admin, source.ID, []int{target.ID})
+	assert.Error(t, err)
+}
+
+func TestSplitErrorGroup(t *testing.T) {
+	defer teardown(t)
+	errorGroup := model.ErrorGroup{State: privateModel.ErrorStateOpen}
+	store.db.Create(&errorGroup)
+	kept := model.ErrorObject{ErrorGroupID: errorGroup.ID, Event: "kept"}
+	store.db.Create(&kept)
+	split := model.ErrorObject{ErrorGroupID: errorGroup.ID, Event: "split"}
+	store.db.Create(&split)
+
+	admin := model.Admin{}
+	store.db.Create(&admin)
+
+	_, err := store.SplitErrorGroup(context.TODO(), admin, errorGroup.ID, []int{kept.ID, split.ID})
+	assert.Error(t, err)
+
+	newErrorGroup, err := store.SplitErrorGroup(context.TODO(), admin, errorGroup.ID, []int{split.ID})
+	assert.NoError(t, err)
+	assert.NotEqual(t, errorGroup.ID, newErrorGroup.ID)
+	assert.Equal(t, "split", newErrorGroup.Event)
+
+	store.db.Take(&split, split.ID)
+	assert.Equal(t, newErrorGroup.ID, split.ErrorGroupID)
+	store.db.Take(&kept, kept.ID)
+	assert.Equal(t, errorGroup.ID, kept.ErrorGroupID)
+
+	activityLogs, err := store.GetErrorGroupActivityLogs(newErrorGroup.ID)
+	assert.NoError(t, err)
+	assert.Len(t, activityLogs, 1)
+	assert.Equal(t, model.ErrorGroupSplitEvent, activityLogs[0].EventType)
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+)
+
+func (store *Store) GetErrorIgnoreRules(ctx context.Context, projectID int) ([]*model.ErrorIgnoreRule, error) {
+	var rules []*model.ErrorIgnoreRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorIgnoreRule{ProjectID: projectID}).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetEnabledErrorIgnoreRules is called for every ingested error, so the lookup is cached briefly.
+func (store *Store) GetEnabledErrorIgnoreRules(ctx context.Context, projectID int) ([]*model.ErrorIgnoreRule, error) {
+	rules, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("error-ignore-rules-%d", projectID), 150*time.Millisecond, time.Minute, func() (*[]*model.ErrorIgnoreRule, error) {
+		var rules []*model.ErrorIgnoreRule
+		if err := store.db.WithContext(ctx).Where(&model.ErrorIgnoreRule{ProjectID: projectID}).
+			Where("disabled = ?", false).Order("created_at ASC").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+func (store *Store) GetErrorIgnoreRule(ctx context.Context, projectID int, ruleID int) (*model.ErrorIgnoreRule, error) {
+	var rule model.ErrorIgnoreRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorIgnoreRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Take(&rule).Error
+	return &rule, err
+}
+
+func (store *Store) CreateErrorIgnoreRule(ctx context.Context, rule *model.ErrorIgnoreRule) error {
+	return store.db.WithContext(ctx).Create(rule).Error
+}
+
+func (store *Store) UpdateErrorIgnoreRule(ctx context.Context, rule *model.ErrorIgnoreRule) error {
+	return store.db.WithContext(ctx).Model(rule).Select(
+		"name", "type_regex", "message_regex", "browser_regex", "url_regex", "source_regex", "disabled", "last_admin_to_edit_id",
+	).Updates(rule).Error
+}
+
+func (store *Store) DeleteErrorIgnoreRule(ctx context.Context, projectID int, ruleID int) error {
+	return store.db.WithContext(ctx).Where(&model.ErrorIgnoreRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Delete(&model.ErrorIgnoreRule{}).Error
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+)
+
+func (store *Store) GetErrorOwnershipRules(ctx context.Context, projectID int) ([]*model.ErrorOwnershipRule, error) {
+	var rules []*model.ErrorOwnershipRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorOwnershipRule{ProjectID: projectID}).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetEnabledErrorOwnershipRules is called for every unassigned error group an error is processed for,
+// so the lookup is cached briefly.
+func (store *Store) GetEnabledErrorOwnershipRules(ctx context.Context, projectID int) ([]*model.ErrorOwnershipRule, error) {
+	rules, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("error-ownership-rules-%d", projectID), 150*time.Millisecond, time.Minute, func() (*[]*model.ErrorOwnershipRule, error) {
+		var rules []*model.ErrorOwnershipRule
+		if err := store.db.WithContext(ctx).Where(&model.ErrorOwnershipRule{ProjectID: projectID}).
+			Where("disabled = ?", false).Order("created_at ASC").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+func (store *Store) GetErrorOwnershipRule(ctx context.Context, projectID int, ruleID int) (*model.ErrorOwnershipRule, error) {
+	var rule model.ErrorOwnershipRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorOwnershipRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Take(&rule).Error
+	return &rule, err
+}
+
+func (store *Store) CreateErrorOwnershipRule(ctx context.Context, rule *model.ErrorOwnershipRule) error {
+	return store.db.WithContext(ctx).Create(rule).Error
+}
+
+func (store *Store) UpdateErrorOwnershipRule(ctx context.Context, rule *model.ErrorOwnershipRule) error {
+	return store.db.WithContext(ctx).Model(rule).Select(
+		"name", "pattern", "service_name", "assignee_id", "disabled", "last_admin_to_edit_id",
+	).Updates(rule).Error
+}
+
+func (store *Store) DeleteErrorOwnershipRule(ctx context.Context, projectID int, ruleID int) error {
+	return store.db.WithContext(ctx).Where(&model.ErrorOwnershipRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Delete(&model.ErrorOwnershipRule{}).Error
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// DeleteExpiredErrorPayloads deletes up to the limit of the payloads of the project's errors that
+// were created before a time, returning how many were deleted. The errors themselves are kept.
+func (store *Store) DeleteExpiredErrorPayloads(ctx context.Context, projectID int, before time.Time, limit int) (int64, error) {
+	result := store.db.WithContext(ctx).Exec(`
+		UPDATE error_objects
+		SET payload = NULL
+		WHERE id IN (
+			SELECT id
+			FROM error_objects
+			WHERE project_id = ?
+			AND created_at < ?
+			AND payload IS NOT NULL
+			LIMIT ?
+		)`, projectID, before, limit)
+	return result.RowsAffected, result.Error
+}
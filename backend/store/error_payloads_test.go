@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteExpiredErrorPayloads(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+	otherProject := model.Project{}
+	store.db.Create(&otherProject)
+
+	now := time.Now()
+	expired := model.ErrorObject{Model: model.Model{CreatedAt: now.AddDate(0, 0, -8)}, ProjectID: project.ID, Payload: pointy.String(`{"http.method": "GET"}`)}
+	store.db.Create(&expired)
+	recent := model.ErrorObject{Model: model.Model{CreatedAt: now.AddDate(0, 0, -1)}, ProjectID: project.ID, Payload: pointy.String(`{"http.method": "GET"}`)}
+	store.db.Create(&recent)
+	other := model.ErrorObject{Model: model.Model{CreatedAt: now.AddDate(0, 0, -8)}, ProjectID: otherProject.ID, Payload: pointy.String(`{"http.method": "GET"}`)}
+	store.db.Create(&other)
+
+	deleted, err := store.DeleteExpiredErrorPayloads(ctx, project.ID, now.AddDate(0, 0, -7), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	// the expired error is kept without its payload
+	var errorObject model.ErrorObject
+	assert.NoError(t, store.db.Where("id = ?", expired.ID).Take(&errorObject).Error)
+	assert.Nil(t, errorObject.Payload)
+	assert.NoError(t, store.db.Where("id = ?", recent.ID).Take(&errorObject).Error)
+	assert.NotNil(t, errorObject.Payload)
+	assert.NoError(t, store.db.Where("id = ?", other.ID).Take(&errorObject).Error)
+	assert.NotNil(t, errorObject.Payload)
+
+	deleted, err = store.DeleteExpiredErrorPayloads(ctx, project.ID, now.AddDate(0, 0, -7), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
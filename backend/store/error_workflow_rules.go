@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+)
+
+func (store *Store) GetErrorWorkflowRules(ctx context.Context, projectID int) ([]*model.ErrorWorkflowRule, error) {
+	var rules []*model.ErrorWorkflowRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorWorkflowRule{ProjectID: projectID}).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetEnabledErrorWorkflowRules is called for every error processed, so the lookup is cached briefly.
+func (store *Store) GetEnabledErrorWorkflowRules(ctx context.Context, projectID int) ([]*model.ErrorWorkflowRule, error) {
+	rules, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("error-workflow-rules-%d", projectID), 150*time.Millisecond, time.Minute, func() (*[]*model.ErrorWorkflowRule, error) {
+		var rules []*model.ErrorWorkflowRule
+		if err := store.db.WithContext(ctx).Where(&model.ErrorWorkflowRule{ProjectID: projectID}).
+			Where("disabled = ?", false).Order("created_at ASC").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+// GetErrorWorkflowRulesWithAction returns the enabled rules of all projects with the given action.
+func (store *Store) GetErrorWorkflowRulesWithAction(ctx context.Context, action model.ErrorWorkflowRuleAction) ([]*model.ErrorWorkflowRule, error) {
+	var rules []*model.ErrorWorkflowRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorWorkflowRule{Action: action}).
+		Where("disabled = ?", false).Order("project_id ASC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (store *Store) GetErrorWorkflowRule(ctx context.Context, projectID int, ruleID int) (*model.ErrorWorkflowRule, error) {
+	var rule model.ErrorWorkflowRule
+	err := store.db.WithContext(ctx).Where(&model.ErrorWorkflowRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Take(&rule).Error
+	return &rule, err
+}
+
+func (store *Store) CreateErrorWorkflowRule(ctx context.Context, rule *model.ErrorWorkflowRule) error {
+	return store.db.WithContext(ctx).Create(rule).Error
+}
+
+func (store *Store) UpdateErrorWorkflowRule(ctx context.Context, rule *model.ErrorWorkflowRule) error {
+	return store.db.WithContext(ctx).Model(rule).Select(
+		"name", "action", "event_regex", "stale_days", "snooze_minutes", "disabled", "last_admin_to_edit_id",
+	).Updates(rule).Error
+}
+
+func (store *Store) DeleteErrorWorkflowRule(ctx context.Context, projectID int, ruleID int) error {
+	return store.db.WithContext(ctx).Where(&model.ErrorWorkflowRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Delete(&model.ErrorWorkflowRule{}).Error
+}
+
+// GetErrorWorkflowRuleActivity returns the most recent error group state changes made by workflow rules.
+func (store *Store) GetErrorWorkflowRuleActivity(ctx context.Context, projectID int, limit int) ([]*model.ErrorGroupActivityLog, error) {
+	var logs []*model.ErrorGroupActivityLog
+	err := store.db.WithContext(ctx).
+		Joins("INNER JOIN error_groups ON error_groups.id = error_group_activity_logs.error_group_id").
+		Where("error_groups.project_id = ?", projectID).
+		Where("error_group_activity_logs.event_data::jsonb->>'RuleID' IS NOT NULL").
+		Order("error_group_activity_logs.created_at DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// HasAdminErrorGroupActivity returns whether an admin has changed the state of the error group,
+// in which case workflow rules should not override their decision.
+func (store *Store) HasAdminErrorGroupActivity(ctx context.Context, errorGroupID int) (bool, error) {
+	var count int64
+	err := store.db.WithContext(ctx).Model(&model.ErrorGroupActivityLog{}).
+		Where("error_group_id = ?", errorGroupID).
+		Where("admin_id IS NOT NULL AND admin_id != 0").
+		Where("event_type <> ?", model.ErrorGroupAssignedEvent).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// escalatedAlertModels are the alerts that can have an escalation policy, by their alert type.
+var escalatedAlertModels = map[string]interface{}{
+	model.AlertType.ERROR: &model.ErrorAlert{},
+	model.AlertType.LOG:   &model.LogAlert{},
+	model.AlertType.TRACE: &model.TraceAlert{},
+}
+
+func (store *Store) GetEscalationPolicies(ctx context.Context, projectID int) ([]*model.EscalationPolicy, error) {
+	var policies []*model.EscalationPolicy
+	err := store.db.WithContext(ctx).Where(&model.EscalationPolicy{ProjectID: projectID}).Order("created_at ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (store *Store) GetEscalationPolicy(ctx context.Context, projectID int, policyID int) (*model.EscalationPolicy, error) {
+	var policy model.EscalationPolicy
+	err := store.db.WithContext(ctx).Where(&model.EscalationPolicy{Model: model.Model{ID: policyID}, ProjectID: projectID}).Take(&policy).Error
+	return &policy, err
+}
+
+func (store *Store) CreateEscalationPolicy(ctx context.Context, policy *model.EscalationPolicy) error {
+	return store.db.WithContext(ctx).Create(policy).Error
+}
+
+func (store *Store) UpdateEscalationPolicy(ctx context.Context, policy *model.EscalationPolicy) error {
+	return store.db.WithContext(ctx).Model(policy).Select("name", "steps", "last_admin_to_edit_id").Updates(policy).Error
+}
+
+// DeleteEscalationPolicy deletes a policy and removes it from the alerts that use it. Its open
+// escalations are finished by the escalation worker.
+func (store *Store) DeleteEscalationPolicy(ctx context.Context, projectID int, policyID int) error {
+	return store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, alertModel := range escalatedAlertModels {
+			if err := tx.Model(alertModel).Where("project_id = ? AND escalation_policy_id = ?", projectID, policyID).
+				Update("escalation_policy_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where(&model.EscalationPolicy{Model: model.Model{ID: policyID}, ProjectID: projectID}).Delete(&model.EscalationPolicy{}).Error
+	})
+}
+
+// UpdateAlertEscalationPolicy sets the escalation policy of an alert, or removes it with a nil policy.
+func (store *Store) UpdateAlertEscalationPolicy(ctx context.Context, projectID int, alertType string, alertID int, policyID *int) error {
+	alertModel, ok := escalatedAlertModels[alertType]
+	if !ok {
+		return errors.Errorf("alerts of type %s cannot be escalated", alertType)
+	}
+	result := store.db.WithContext(ctx).Model(alertModel).Where("id = ? AND project_id = ?", alertID, projectID).
+		Update("escalation_policy_id", policyID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (store *Store) GetOnCallSchedules(ctx context.Context, projectID int) ([]*model.OnCallSchedule, error) {
+	var schedules []*model.OnCallSchedule
+	err := store.db.WithContext(ctx).Where(&model.OnCallSchedule{ProjectID: projectID}).Order("created_at ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (store *Store) GetOnCallSchedule(ctx context.Context, projectID int, scheduleID int) (*model.OnCallSchedule, error) {
+	var schedule model.OnCallSchedule
+	err := store.db.WithContext(ctx).Where(&model.OnCallSchedule{Model: model.Model{ID: scheduleID}, ProjectID: projectID}).Take(&schedule).Error
+	return &schedule, err
+}
+
+func (store *Store) CreateOnCallSchedule(ctx context.Context, schedule *model.OnCallSchedule) error {
+	return store.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (store *Store) UpdateOnCallSchedule(ctx context.Context, schedule *model.OnCallSchedule) error {
+	return store.db.WithContext(ctx).Model(schedule).Select("name", "participants", "rotation_hours", "starts_at").Updates(schedule).Error
+}
+
+func (store *Store) DeleteOnCallSchedule(ctx context.Context, projectID int, scheduleID int) error {
+	return store.db.WithContext(ctx).Where(&model.OnCallSchedule{Model: model.Model{ID: scheduleID}, ProjectID: projectID}).Delete(&model.OnCallSchedule{}).Error
+}
+
+// GetAlertEscalations returns the escalations of a project that are not resolved, including the
+// acknowledged ones.
+func (store *Store) GetAlertEscalations(ctx context.Context, projectID int) ([]*model.AlertEscalation, error) {
+	var escalations []*model.AlertEscalation
+	err := store.db.WithContext(ctx).Where(&model.AlertEscalation{ProjectID: projectID}).Where("resolved_at IS NULL").Order("created_at DESC").Find(&escalations).Error
+	return escalations, err
+}
+
+func (store *Store) GetAlertEscalation(ctx context.Context, projectID int, escalationID int) (*model.AlertEscalation, error) {
+	var escalation model.AlertEscalation
+	err := store.db.WithContext(ctx).Where(&model.AlertEscalation{Model: model.Model{ID: escalationID}, ProjectID: projectID}).Take(&escalation).Error
+	return &escalation, err
+}
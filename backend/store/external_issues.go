@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"gorm.io/gorm/clause"
+)
+
+// An issue claim that was never completed, for example because the tracker request failed,
+// may be claimed again after this long.
+const externalIssueClaimTimeout = 5 * time.Minute
+
+var ErrExternalIssuePending = e.New("an issue is already being created for this error in the tracker")
+
+type ClaimExternalIssueParams struct {
+	ProjectID       int
+	ErrorGroupID    int
+	IntegrationType privateModel.IntegrationType
+	AdminID         *int
+}
+
+// ClaimExternalIssue reserves the tracker for the error group before an issue is created.
+// Returns true if the claim was acquired and the caller should create the issue,
+// otherwise the issue already linked to the error group is returned.
+func (store *Store) ClaimExternalIssue(ctx context.Context, params ClaimExternalIssueParams) (*model.ExternalIssue, bool, error) {
+	issue := &model.ExternalIssue{
+		ProjectID:       params.ProjectID,
+		ErrorGroupID:    params.ErrorGroupID,
+		IntegrationType: params.IntegrationType,
+		AdminID:         params.AdminID,
+	}
+	result := store.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(issue)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return issue, true, nil
+	}
+
+	existing, err := store.GetExternalIssue(ctx, params.ErrorGroupID, params.IntegrationType)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing.ExternalID != "" {
+		return existing, false, nil
+	}
+
+	// take over a stale claim
+	result = store.db.WithContext(ctx).Model(&model.ExternalIssue{}).
+		Where("id = ?", existing.ID).
+		Where("external_id = ''").
+		Where("updated_at < ?", time.Now().Add(-externalIssueClaimTimeout)).
+		Updates(map[string]interface{}{"admin_id": params.AdminID, "updated_at": time.Now()})
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, false, ErrExternalIssuePending
+	}
+	existing.AdminID = params.AdminID
+	return existing, true, nil
+}
+
+// CompleteExternalIssue records the issue created in the tracker for a claim.
+func (store *Store) CompleteExternalIssue(ctx context.Context, issueID int, attachment *model.ExternalAttachment) error {
+	updates := map[string]interface{}{
+		"external_id": attachment.ExternalID,
+		"title":       attachment.Title,
+	}
+	if attachment.ID != 0 {
+		updates["external_attachment_id"] = attachment.ID
+	}
+	return store.db.WithContext(ctx).Model(&model.ExternalIssue{}).Where("id = ?", issueID).Updates(updates).Error
+}
+
+// ReleaseExternalIssue removes a claim for which no issue was created.
+func (store *Store) ReleaseExternalIssue(ctx context.Context, issueID int) error {
+	return store.db.WithContext(ctx).Where("id = ?", issueID).Where("external_id = ''").Delete(&model.ExternalIssue{}).Error
+}
+
+func (store *Store) GetExternalIssue(ctx context.Context, errorGroupID int, integrationType privateModel.IntegrationType) (*model.ExternalIssue, error) {
+	var issue model.ExternalIssue
+	err := store.db.WithContext(ctx).Where(&model.ExternalIssue{ErrorGroupID: errorGroupID, IntegrationType: integrationType}).Take(&issue).Error
+	return &issue, err
+}
+
+func (store *Store) GetExternalIssues(ctx context.Context, errorGroupID int) ([]*model.ExternalIssue, error) {
+	var issues []*model.ExternalIssue
+	err := store.db.WithContext(ctx).Where(&model.ExternalIssue{ErrorGroupID: errorGroupID}).Where("external_id != ''").Order("created_at ASC").Find(&issues).Error
+	return issues, err
+}
+
+// LinkExternalIssue links an existing tracker issue to the error group.
+// Fails if the error group is already linked to a different issue in the tracker.
+func (store *Store) LinkExternalIssue(ctx context.Context, params ClaimExternalIssueParams, externalID string, title string) (*model.ExternalIssue, error) {
+	issue, claimed, err := store.ClaimExternalIssue(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		if issue.ExternalID == externalID {
+			return issue, nil
+		}
+		return nil, e.Errorf("error is already linked to %s issue %s", params.IntegrationType, issue.Title)
+	}
+	if err := store.CompleteExternalIssue(ctx, issue.ID, &model.ExternalAttachment{ExternalID: externalID, Title: title}); err != nil {
+		return nil, err
+	}
+	issue.ExternalID, issue.Title = externalID, title
+	return issue, nil
+}
+
+// UpdateExternalIssueStatus records the status of the issues linked to an issue of the tracker.
+func (store *Store) UpdateExternalIssueStatus(ctx context.Context, integrationType privateModel.IntegrationType, externalID string, status string, closed bool) error {
+	return store.db.WithContext(ctx).Model(&model.ExternalIssue{}).
+		Where(&model.ExternalIssue{IntegrationType: integrationType, ExternalID: externalID}).
+		Updates(map[string]interface{}{"status": status, "closed": closed}).Error
+}
+
+// GetExternalIssueErrorGroups returns the error groups linked to an issue of the tracker, either
+// directly or through the attachments of their comments.
+func (store *Store) GetExternalIssueErrorGroups(ctx context.Context, integrationType privateModel.IntegrationType, externalID string) ([]*model.ErrorGroup, error) {
+	var errorGroups []*model.ErrorGroup
+	err := store.db.WithContext(ctx).
+		Where("id IN (?)", store.db.Model(&model.ExternalIssue{}).
+			Select("error_group_id").
+			Where(&model.ExternalIssue{IntegrationType: integrationType, ExternalID: externalID})).
+		Or("id IN (?)", store.db.Model(&model.ErrorComment{}).
+			Select("error_comments.error_id").
+			Joins("INNER JOIN external_attachments ON external_attachments.error_comment_id = error_comments.id").
+			Where("external_attachments.integration_type = ?", integrationType).
+			Where("external_attachments.external_id = ?", externalID).
+			Where("external_attachments.removed IS NOT TRUE")).
+		Order("id ASC").
+		Find(&errorGroups).Error
+	return errorGroups, err
+}
+
+// UnlinkExternalAttachment removes the link created from an attachment when the attachment is removed,
+// allowing a new issue to be created for the error group in the tracker.
+func (store *Store) UnlinkExternalAttachment(ctx context.Context, attachmentID int) error {
+	return store.db.WithContext(ctx).Where("external_attachment_id = ?", attachmentID).Delete(&model.ExternalIssue{}).Error
+}
+
+func (store *Store) DeleteExternalIssue(ctx context.Context, errorGroupID int, integrationType privateModel.IntegrationType) error {
+	return store.db.WithContext(ctx).Where(&model.ExternalIssue{ErrorGroupID: errorGroupID, IntegrationType: integrationType}).Delete(&model.ExternalIssue{}).Error
+}
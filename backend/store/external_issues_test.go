@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimExternalIssue(t *testing.T) {
+	defer teardown(t)
+	ctx := context.TODO()
+
+	params := ClaimExternalIssueParams{
+		ProjectID:       1,
+		ErrorGroupID:    1,
+		IntegrationType: privateModel.IntegrationTypeLinear,
+	}
+
+	issue, claimed, err := store.ClaimExternalIssue(ctx, params)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	_, _, err = store.ClaimExternalIssue(ctx, params)
+	assert.ErrorIs(t, err, ErrExternalIssuePending)
+
+	assert.NoError(t, store.CompleteExternalIssue(ctx, issue.ID, &model.ExternalAttachment{ExternalID: "abc", Title: "HIG-1"}))
+
+	existing, claimed, err := store.ClaimExternalIssue(ctx, params)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, "abc", existing.ExternalID)
+
+	// another tracker can still be linked
+	params.IntegrationType = privateModel.IntegrationTypeJira
+	_, claimed, err = store.ClaimExternalIssue(ctx, params)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	_, err = store.LinkExternalIssue(ctx, ClaimExternalIssueParams{
+		ProjectID:       1,
+		ErrorGroupID:    1,
+		IntegrationType: privateModel.IntegrationTypeLinear,
+	}, "def", "HIG-2")
+	assert.Error(t, err)
+
+	issues, err := store.GetExternalIssues(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+}
+
+func TestUpdateExternalIssueStatus(t *testing.T) {
+	defer teardown(t)
+	ctx := context.TODO()
+
+	params := ClaimExternalIssueParams{
+		ProjectID:       1,
+		ErrorGroupID:    1,
+		IntegrationType: privateModel.IntegrationTypeJira,
+	}
+	_, err := store.LinkExternalIssue(ctx, params, "https://highlight.atlassian.net/browse/ENG-1", "ENG-1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.UpdateExternalIssueStatus(ctx, privateModel.IntegrationTypeJira, "https://highlight.atlassian.net/browse/ENG-1", "Done", true))
+	assert.NoError(t, store.UpdateExternalIssueStatus(ctx, privateModel.IntegrationTypeJira, "https://highlight.atlassian.net/browse/ENG-2", "Done", true))
+
+	issue, err := store.GetExternalIssue(ctx, 1, privateModel.IntegrationTypeJira)
+	assert.NoError(t, err)
+	assert.Equal(t, "Done", issue.Status)
+	assert.True(t, issue.Closed)
+}
+
+func TestGetExternalIssueErrorGroups(t *testing.T) {
+	defer teardown(t)
+	ctx := context.TODO()
+
+	errorGroups := []*model.ErrorGroup{{ProjectID: 1}, {ProjectID: 1}, {ProjectID: 1}}
+	assert.NoError(t, store.db.Create(&errorGroups).Error)
+
+	issueURL := "https://github.com/highlight/highlight/issues/1"
+	_, err := store.LinkExternalIssue(ctx, ClaimExternalIssueParams{
+		ProjectID:       1,
+		ErrorGroupID:    errorGroups[0].ID,
+		IntegrationType: privateModel.IntegrationTypeGitHub,
+	}, issueURL, "Uncaught TypeError")
+	assert.NoError(t, err)
+
+	comment := &model.ErrorComment{ProjectID: 1, ErrorId: errorGroups[1].ID}
+	assert.NoError(t, store.db.Create(comment).Error)
+	assert.NoError(t, store.db.Create(&model.ExternalAttachment{
+		IntegrationType: privateModel.IntegrationTypeGitHub,
+		ExternalID:      issueURL,
+		ErrorCommentID:  comment.ID,
+	}).Error)
+
+	linked, err := store.GetExternalIssueErrorGroups(ctx, privateModel.IntegrationTypeGitHub, issueURL)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{errorGroups[0].ID, errorGroups[1].ID}, lo.Map(linked, func(g *model.ErrorGroup, _ int) int { return g.ID }))
+
+	linked, err = store.GetExternalIssueErrorGroups(ctx, privateModel.IntegrationTypeJira, issueURL)
+	assert.NoError(t, err)
+	assert.Empty(t, linked)
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+)
+
+func (store *Store) GetHeartbeatMonitors(ctx context.Context, projectID int) ([]*model.HeartbeatMonitor, error) {
+	var monitors []*model.HeartbeatMonitor
+	err := store.db.WithContext(ctx).Where(&model.HeartbeatMonitor{ProjectID: projectID}).Order("created_at ASC").Find(&monitors).Error
+	return monitors, err
+}
+
+func (store *Store) GetHeartbeatMonitor(ctx context.Context, projectID int, monitorID int) (*model.HeartbeatMonitor, error) {
+	var monitor model.HeartbeatMonitor
+	err := store.db.WithContext(ctx).Where(&model.HeartbeatMonitor{Model: model.Model{ID: monitorID}, ProjectID: projectID}).Take(&monitor).Error
+	return &monitor, err
+}
+
+// GetHeartbeatMonitorBySecureID is called for every check-in, so the lookup is cached briefly.
+func (store *Store) GetHeartbeatMonitorBySecureID(ctx context.Context, secureID string) (*model.HeartbeatMonitor, error) {
+	return redis.CachedEval(ctx, store.redis, fmt.Sprintf("heartbeat-monitor-secure-%s", secureID), 150*time.Millisecond, time.Minute, func() (*model.HeartbeatMonitor, error) {
+		var monitor model.HeartbeatMonitor
+		if err := store.db.WithContext(ctx).Where(&model.HeartbeatMonitor{SecureID: secureID}).Take(&monitor).Error; err != nil {
+			return nil, err
+		}
+		return &monitor, nil
+	})
+}
+
+func (store *Store) CreateHeartbeatMonitor(ctx context.Context, monitor *model.HeartbeatMonitor) error {
+	return store.db.WithContext(ctx).Create(monitor).Error
+}
+
+func (store *Store) UpdateHeartbeatMonitor(ctx context.Context, monitor *model.HeartbeatMonitor) error {
+	return store.db.WithContext(ctx).Model(monitor).Select(
+		"name", "interval_seconds", "grace_seconds", "channels_to_notify", "emails_to_notify", "last_admin_to_edit_id", "disabled",
+	).Updates(monitor).Error
+}
+
+func (store *Store) DeleteHeartbeatMonitor(ctx context.Context, projectID int, monitorID int) error {
+	return store.db.WithContext(ctx).Where(&model.HeartbeatMonitor{Model: model.Model{ID: monitorID}, ProjectID: projectID}).Delete(&model.HeartbeatMonitor{}).Error
+}
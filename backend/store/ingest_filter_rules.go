@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+)
+
+func (store *Store) GetIngestFilterRules(ctx context.Context, projectID int) ([]*model.IngestFilterRule, error) {
+	var rules []*model.IngestFilterRule
+	err := store.db.WithContext(ctx).Where(&model.IngestFilterRule{ProjectID: projectID}).Order("created_at ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetEnabledIngestFilterRules is called for every otel export request, so the lookup is cached briefly.
+func (store *Store) GetEnabledIngestFilterRules(ctx context.Context, projectID int) ([]*model.IngestFilterRule, error) {
+	rules, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("ingest-filter-rules-%d", projectID), 150*time.Millisecond, time.Minute, func() (*[]*model.IngestFilterRule, error) {
+		var rules []*model.IngestFilterRule
+		if err := store.db.WithContext(ctx).Where(&model.IngestFilterRule{ProjectID: projectID}).
+			Where("disabled = ?", false).Order("created_at ASC, id ASC").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+func (store *Store) GetIngestFilterRule(ctx context.Context, projectID int, ruleID int) (*model.IngestFilterRule, error) {
+	var rule model.IngestFilterRule
+	err := store.db.WithContext(ctx).Where(&model.IngestFilterRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Take(&rule).Error
+	return &rule, err
+}
+
+func (store *Store) CreateIngestFilterRule(ctx context.Context, rule *model.IngestFilterRule) error {
+	return store.db.WithContext(ctx).Create(rule).Error
+}
+
+func (store *Store) UpdateIngestFilterRule(ctx context.Context, rule *model.IngestFilterRule) error {
+	return store.db.WithContext(ctx).Model(rule).Select(
+		"name", "action", "service_name", "severity", "attribute_key", "attribute_regex", "disabled", "last_admin_to_edit_id",
+	).Updates(rule).Error
+}
+
+func (store *Store) DeleteIngestFilterRule(ctx context.Context, projectID int, ruleID int) error {
+	return store.db.WithContext(ctx).Where(&model.IngestFilterRule{Model: model.Model{ID: ruleID}, ProjectID: projectID}).Delete(&model.IngestFilterRule{}).Error
+}
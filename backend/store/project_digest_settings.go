@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"gorm.io/gorm/clause"
+)
+
+// GetProjectDigestSetting returns the digest schedule of a project, or nil if the project gets the
+// default weekly digest.
+func (store *Store) GetProjectDigestSetting(ctx context.Context, projectID int) (*model.ProjectDigestSetting, error) {
+	var settings []*model.ProjectDigestSetting
+	if err := store.db.WithContext(ctx).Where(&model.ProjectDigestSetting{ProjectID: projectID}).Limit(1).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	return settings[0], nil
+}
+
+// UpdateProjectDigestSetting creates or replaces the digest schedule of a project.
+func (store *Store) UpdateProjectDigestSetting(ctx context.Context, setting *model.ProjectDigestSetting) error {
+	return store.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "frequency", "weekday", "hour", "last_sent_at"}),
+	}).Create(setting).Error
+}
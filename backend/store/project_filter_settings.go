@@ -106,3 +106,74 @@ func (store *Store) FindProjectsWithAutoResolveSetting() ([]*model.ProjectFilter
 	}
 	return projectFilterSettings, nil
 }
+
+// UpdateProjectRedactionRules replaces the attribute keys and value patterns redacted from the project's otel data.
+func (store *Store) UpdateProjectRedactionRules(ctx context.Context, projectID int, keys []string, patterns []string) (*model.ProjectFilterSettings, error) {
+	projectFilterSettings, err := store.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectFilterSettings.RedactedAttributeKeys = keys
+	projectFilterSettings.RedactionPatterns = patterns
+	if err := store.db.WithContext(ctx).Save(projectFilterSettings).Error; err != nil {
+		return nil, err
+	}
+
+	return projectFilterSettings, store.redis.Client.Del(ctx, getKey(projectID)).Err()
+}
+
+// UpdateProjectIngestExclusions replaces the service names and log levels whose otel data is dropped at ingest.
+func (store *Store) UpdateProjectIngestExclusions(ctx context.Context, projectID int, serviceNames []string, logLevels []string) (*model.ProjectFilterSettings, error) {
+	projectFilterSettings, err := store.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectFilterSettings.ExcludedServiceNames = serviceNames
+	projectFilterSettings.ExcludedLogLevels = logLevels
+	if err := store.db.WithContext(ctx).Save(projectFilterSettings).Error; err != nil {
+		return nil, err
+	}
+
+	return projectFilterSettings, store.redis.Client.Del(ctx, getKey(projectID)).Err()
+}
+
+func (store *Store) UpdateProjectErrorsFromSpanStatus(ctx context.Context, projectID int, enabled bool) (*model.ProjectFilterSettings, error) {
+	projectFilterSettings, err := store.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectFilterSettings.ErrorsFromSpanStatus = enabled
+	if err := store.db.WithContext(ctx).Save(projectFilterSettings).Error; err != nil {
+		return nil, err
+	}
+
+	return projectFilterSettings, store.redis.Client.Del(ctx, getKey(projectID)).Err()
+}
+
+// UpdateProjectErrorPayloadSettings replaces how the payloads of the project's errors are sampled and retained.
+func (store *Store) UpdateProjectErrorPayloadSettings(ctx context.Context, projectID int, hourlyLimit *int64, samplingRate float64, retentionDays *int) (*model.ProjectFilterSettings, error) {
+	projectFilterSettings, err := store.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectFilterSettings.ErrorPayloadHourlyLimit = hourlyLimit
+	projectFilterSettings.ErrorPayloadSamplingRate = samplingRate
+	projectFilterSettings.ErrorPayloadRetentionDays = retentionDays
+	if err := store.db.WithContext(ctx).Save(projectFilterSettings).Error; err != nil {
+		return nil, err
+	}
+
+	return projectFilterSettings, store.redis.Client.Del(ctx, getKey(projectID)).Err()
+}
+
+func (store *Store) FindProjectsWithErrorPayloadRetention(ctx context.Context) ([]*model.ProjectFilterSettings, error) {
+	var projectFilterSettings []*model.ProjectFilterSettings
+	if err := store.db.WithContext(ctx).Where("error_payload_retention_days > ?", 0).Find(&projectFilterSettings).Error; err != nil {
+		return nil, err
+	}
+	return projectFilterSettings, nil
+}
@@ -59,6 +59,63 @@ func TestUpdateProjectFilterSettings(t *testing.T) {
 
 }
 
+func TestUpdateProjectRedactionRules(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+
+	_, err := store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+
+	_, err = store.UpdateProjectRedactionRules(ctx, project.ID, []string{"authorization"}, []string{"email"})
+	assert.NoError(t, err)
+
+	settings, err := store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"authorization"}, []string(settings.RedactedAttributeKeys))
+	assert.Equal(t, []string{"email"}, []string(settings.RedactionPatterns))
+}
+
+func TestUpdateProjectErrorsFromSpanStatus(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+
+	settings, err := store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.False(t, settings.ErrorsFromSpanStatus)
+
+	_, err = store.UpdateProjectErrorsFromSpanStatus(ctx, project.ID, true)
+	assert.NoError(t, err)
+
+	settings, err = store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.True(t, settings.ErrorsFromSpanStatus)
+}
+
+func TestUpdateProjectIngestExclusions(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+
+	_, err := store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+
+	_, err = store.UpdateProjectIngestExclusions(ctx, project.ID, []string{"health-check"}, []string{"debug", "trace"})
+	assert.NoError(t, err)
+
+	settings, err := store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"health-check"}, []string(settings.ExcludedServiceNames))
+	assert.Equal(t, []string{"debug", "trace"}, []string(settings.ExcludedLogLevels))
+}
+
 func TestFindProjectsWithAutoResolveSetting(t *testing.T) {
 	ctx := context.TODO()
 	defer teardown(t)
@@ -92,3 +149,33 @@ func TestFindProjectsWithAutoResolveSetting(t *testing.T) {
 	assert.Len(t, projectFilterSettings, 1)
 	assert.Equal(t, projectFilterSettings[0].ID, projectWithAutoResolveSetting.ID)
 }
+
+func TestUpdateProjectErrorPayloadSettings(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+
+	settings, err := store.UpdateProjectErrorPayloadSettings(ctx, project.ID, ptr.Int64(100), 0.1, ptr.Int(7))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), *settings.ErrorPayloadHourlyLimit)
+
+	// the cached settings are invalidated
+	settings, err = store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), *settings.ErrorPayloadHourlyLimit)
+	assert.Equal(t, 0.1, settings.ErrorPayloadSamplingRate)
+	assert.Equal(t, 7, *settings.ErrorPayloadRetentionDays)
+
+	projects, err := store.FindProjectsWithErrorPayloadRetention(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1)
+	assert.Equal(t, project.ID, projects[0].ProjectID)
+
+	_, err = store.UpdateProjectErrorPayloadSettings(ctx, project.ID, nil, 0, nil)
+	assert.NoError(t, err)
+	projects, err = store.FindProjectsWithErrorPayloadRetention(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, projects)
+}
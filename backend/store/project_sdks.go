@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"gorm.io/gorm/clause"
+)
+
+// RecordProjectSDK upserts an SDK version a project sent data from. It is called for otel export
+// requests, so the last seen time is only updated once per minute.
+func (store *Store) RecordProjectSDK(ctx context.Context, projectID int, name string, version string) error {
+	_, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("project-sdk-%d-%s-%s", projectID, name, version), 150*time.Millisecond, time.Minute, func() (*bool, error) {
+		sdk := model.ProjectSDK{
+			ProjectID:  projectID,
+			Name:       name,
+			Version:    version,
+			LastSeenAt: time.Now(),
+		}
+		if err := store.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "project_id"}, {Name: "name"}, {Name: "version"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_seen_at", "updated_at"}),
+		}).Create(&sdk).Error; err != nil {
+			return nil, err
+		}
+		recorded := true
+		return &recorded, nil
+	})
+	return err
+}
+
+// GetProjectSDKs returns the SDK versions a project sent data from, most recently seen first.
+func (store *Store) GetProjectSDKs(ctx context.Context, projectID int) ([]*model.ProjectSDK, error) {
+	var sdks []*model.ProjectSDK
+	err := store.db.WithContext(ctx).Where(&model.ProjectSDK{ProjectID: projectID}).Order("last_seen_at DESC").Find(&sdks).Error
+	return sdks, err
+}
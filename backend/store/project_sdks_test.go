@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordProjectSDK(t *testing.T) {
+	ctx := context.Background()
+
+	util.RunTestWithDBWipe(t, store.db, func(t *testing.T) {
+		project := model.Project{}
+		store.db.Create(&project)
+
+		for _, version := range []string{"1.20.0", "1.21.0"} {
+			assert.NoError(t, store.redis.Del(ctx, fmt.Sprintf("project-sdk-%d-opentelemetry-%s", project.ID, version)))
+			assert.NoError(t, store.RecordProjectSDK(ctx, project.ID, "opentelemetry", version))
+		}
+		// recording a version again only updates when it was last seen
+		assert.NoError(t, store.redis.Del(ctx, fmt.Sprintf("project-sdk-%d-opentelemetry-1.20.0", project.ID)))
+		assert.NoError(t, store.RecordProjectSDK(ctx, project.ID, "opentelemetry", "1.20.0"))
+
+		sdks, err := store.GetProjectSDKs(ctx, project.ID)
+		assert.NoError(t, err)
+		assert.Len(t, sdks, 2)
+		assert.Equal(t, "1.20.0", sdks[0].Version)
+		assert.Equal(t, "1.21.0", sdks[1].Version)
+		assert.Equal(t, "opentelemetry", sdks[0].Name)
+
+		sdks, err = store.GetProjectSDKs(ctx, project.ID+1)
+		assert.NoError(t, err)
+		assert.Empty(t, sdks)
+	})
+}
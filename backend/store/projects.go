@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -9,8 +11,12 @@ import (
 	"github.com/highlight-run/highlight/backend/redis"
 )
 
+func projectKey(id int) string {
+	return fmt.Sprintf("project-id-%d", id)
+}
+
 func (store *Store) GetProject(ctx context.Context, id int) (*model.Project, error) {
-	return redis.CachedEval(ctx, store.redis, fmt.Sprintf("project-id-%d", id), 150*time.Millisecond, time.Minute, func() (*model.Project, error) {
+	return redis.CachedEval(ctx, store.redis, projectKey(id), 150*time.Millisecond, time.Minute, func() (*model.Project, error) {
 		var project model.Project
 
 		err := store.db.WithContext(ctx).Where(&model.Project{
@@ -22,3 +28,27 @@ func (store *Store) GetProject(ctx context.Context, id int) (*model.Project, err
 		return &project, err
 	})
 }
+
+// GetProjectIDBySecret returns the id of the project with the api key secret, or 0 if there is none.
+func (store *Store) GetProjectIDBySecret(ctx context.Context, secret string) (int, error) {
+	hash := sha256.Sum256([]byte(secret))
+	projectID, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("project-secret-%s", hex.EncodeToString(hash[:])), 150*time.Millisecond, time.Minute, func() (*int, error) {
+		var projectID int
+		err := store.db.WithContext(ctx).Model(&model.Project{}).Select("id").
+			Where("secret = ?", secret).Scan(&projectID).Error
+		return &projectID, err
+	})
+	if err != nil || projectID == nil {
+		return 0, err
+	}
+	return *projectID, nil
+}
+
+// UpdateProjectRequireIngestKey sets whether otel data for the project must be sent with its secret.
+func (store *Store) UpdateProjectRequireIngestKey(ctx context.Context, id int, requireIngestKey bool) error {
+	if err := store.db.WithContext(ctx).Model(&model.Project{Model: model.Model{ID: id}}).
+		Update("RequireIngestKey", requireIngestKey).Error; err != nil {
+		return err
+	}
+	return store.redis.Client.Del(ctx, projectKey(id)).Err()
+}
@@ -23,3 +23,40 @@ func TestGetProject(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, project.ID, foundProject.ID)
 }
+
+func TestGetProjectIDBySecret(t *testing.T) {
+	ctx := context.Background()
+
+	defer teardown(t)
+
+	secret := "abc123"
+	project := model.Project{Secret: &secret}
+	store.db.Create(&project)
+
+	projectID, err := store.GetProjectIDBySecret(ctx, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, project.ID, projectID)
+
+	projectID, err = store.GetProjectIDBySecret(ctx, "invalid")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, projectID)
+}
+
+func TestUpdateProjectRequireIngestKey(t *testing.T) {
+	ctx := context.Background()
+
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+
+	foundProject, err := store.GetProject(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.False(t, foundProject.RequireIngestKey)
+
+	assert.NoError(t, store.UpdateProjectRequireIngestKey(ctx, project.ID, true))
+
+	foundProject, err = store.GetProject(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.True(t, foundProject.RequireIngestKey)
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/apitoken"
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+// ValidateProject is whether the project that a request is rate limited by exists.
+func (store *Store) ValidateProject(ctx context.Context, project string) bool {
+	projectID, err := model.FromVerboseID(project)
+	if err != nil {
+		return false
+	}
+	_, err = store.GetProject(ctx, projectID)
+	return err == nil
+}
+
+// ValidateAPIKey is whether the api key that a request is rate limited by is the secret of a
+// project or an active api token.
+func (store *Store) ValidateAPIKey(ctx context.Context, apiKey string) bool {
+	if strings.HasPrefix(apiKey, apitoken.Prefix) {
+		_, err := store.GetActiveAPIToken(ctx, apiKey)
+		return err == nil
+	}
+	var projectID int
+	if err := store.db.WithContext(ctx).Model(&model.Project{}).Select("id").
+		Where("secret = ?", apiKey).Scan(&projectID).Error; err != nil {
+		return false
+	}
+	return projectID != 0
+}
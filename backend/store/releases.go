@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func releaseKey(projectID int, serviceName string, version string) string {
+	return fmt.Sprintf("release-%d-%s-%s", projectID, serviceName, version)
+}
+
+var releaseColumns = []clause.Column{{Name: "project_id"}, {Name: "service_name"}, {Name: "version"}}
+
+// ObserveRelease records the release of a service version when its telemetry is received. Every
+// error and log of a version observes its release, so the release is cached after it is recorded.
+func (store *Store) ObserveRelease(ctx context.Context, projectID int, serviceName string, version string, seenAt time.Time) (*model.Release, error) {
+	return redis.CachedEval(ctx, store.redis, releaseKey(projectID, serviceName, version), 150*time.Millisecond, time.Hour, func() (*model.Release, error) {
+		release := &model.Release{
+			ProjectID:   projectID,
+			ServiceName: serviceName,
+			Version:     version,
+			FirstSeenAt: &seenAt,
+		}
+		// a release that was deployed before its telemetry was received gets its first seen time
+		if err := store.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: releaseColumns,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"first_seen_at": gorm.Expr("COALESCE(releases.first_seen_at, EXCLUDED.first_seen_at)"),
+			}),
+		}).Create(release).Error; err != nil {
+			return nil, err
+		}
+		return store.GetRelease(ctx, projectID, serviceName, version)
+	})
+}
+
+// CreateDeploy records a deploy of a release reported by a CI pipeline, creating the release if its
+// telemetry has not been received yet.
+func (store *Store) CreateDeploy(ctx context.Context, release *model.Release) error {
+	if release.DeployedAt == nil {
+		now := time.Now()
+		release.DeployedAt = &now
+	}
+	if err := store.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   releaseColumns,
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "deployed_at", "environment", "commit_sha", "url"}),
+	}).Create(release).Error; err != nil {
+		return err
+	}
+	if store.redis != nil {
+		if err := store.redis.Cache.Delete(ctx, releaseKey(release.ProjectID, release.ServiceName, release.Version)); err != nil {
+			return err
+		}
+	}
+	return store.db.WithContext(ctx).Where(&model.Release{
+		ProjectID:   release.ProjectID,
+		ServiceName: release.ServiceName,
+		Version:     release.Version,
+	}).Take(release).Error
+}
+
+func (store *Store) GetRelease(ctx context.Context, projectID int, serviceName string, version string) (*model.Release, error) {
+	var release model.Release
+	if err := store.db.WithContext(ctx).Where(&model.Release{
+		ProjectID:   projectID,
+		ServiceName: serviceName,
+		Version:     version,
+	}).Take(&release).Error; err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// GetReleases returns the latest releases of a project, optionally of a single service.
+func (store *Store) GetReleases(ctx context.Context, projectID int, serviceName string, limit int) ([]*model.Release, error) {
+	var releases []*model.Release
+	if err := store.db.WithContext(ctx).
+		Where(&model.Release{ProjectID: projectID, ServiceName: serviceName}).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&releases).Error; err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
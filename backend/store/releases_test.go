@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRelease(t *testing.T) {
+	ctx := context.Background()
+
+	util.RunTestWithDBWipe(t, store.db, func(t *testing.T) {
+		project := model.Project{}
+		store.db.Create(&project)
+
+		deployedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		deploy := &model.Release{
+			ProjectID:   project.ID,
+			ServiceName: "api",
+			Version:     "1.2.0",
+			DeployedAt:  &deployedAt,
+			CommitSHA:   pointy.String("a1b2c3d"),
+		}
+		assert.NoError(t, store.CreateDeploy(ctx, deploy))
+		assert.Nil(t, deploy.FirstSeenAt)
+
+		// telemetry of a deployed release sets its first seen time and keeps the deploy
+		seenAt := time.Now().UTC().Truncate(time.Second)
+		release, err := store.ObserveRelease(ctx, project.ID, "api", "1.2.0", seenAt)
+		assert.NoError(t, err)
+		assert.Equal(t, deploy.ID, release.ID)
+		assert.WithinDuration(t, seenAt, *release.FirstSeenAt, time.Second)
+		assert.Equal(t, "a1b2c3d", *release.CommitSHA)
+
+		// later telemetry does not move the first seen time
+		release, err = store.ObserveRelease(ctx, project.ID, "api", "1.2.0", seenAt.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.WithinDuration(t, seenAt, *release.FirstSeenAt, time.Second)
+
+		_, err = store.ObserveRelease(ctx, project.ID, "api", "1.3.0", seenAt)
+		assert.NoError(t, err)
+
+		releases, err := store.GetReleases(ctx, project.ID, "api", 10)
+		assert.NoError(t, err)
+		assert.Len(t, releases, 2)
+		assert.Equal(t, "1.3.0", releases[0].Version)
+
+		releases, err = store.GetReleases(ctx, project.ID, "worker", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, releases)
+	})
+}
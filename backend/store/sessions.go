@@ -31,3 +31,56 @@ func (store *Store) GetSession(ctx context.Context, sessionID int) (*model.Sessi
 		return &session, nil
 	})
 }
+
+// GetLatestSessionsByEmail returns the latest sessions of a project identified with an email, the newest first.
+func (store *Store) GetLatestSessionsByEmail(ctx context.Context, projectID int, email string, limit int) ([]*model.Session, error) {
+	var sessions []*model.Session
+	if err := store.db.WithContext(ctx).
+		Where(&model.Session{ProjectID: projectID, Email: &email}).
+		Where("excluded <> true").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SessionUser identifies the user of an event that was not sent with a session.
+type SessionUser struct {
+	Identifier string
+	Email      string
+	IP         string
+}
+
+// FindLatestSessionOfUser returns the latest session of a project started in the window before a time
+// that matches the identifier, email or ip of a user, which are tried in that order. It returns nil
+// when no session matches.
+func (store *Store) FindLatestSessionOfUser(ctx context.Context, projectID int, user SessionUser, before time.Time, window time.Duration) (*model.Session, error) {
+	var conditions []*model.Session
+	if user.Identifier != "" {
+		conditions = append(conditions, &model.Session{ProjectID: projectID, Identifier: user.Identifier})
+	}
+	if user.Email != "" {
+		conditions = append(conditions, &model.Session{ProjectID: projectID, Email: &user.Email})
+	}
+	if user.IP != "" {
+		conditions = append(conditions, &model.Session{ProjectID: projectID, IP: user.IP})
+	}
+	for _, condition := range conditions {
+		var sessions []*model.Session
+		if err := store.db.WithContext(ctx).
+			Where(condition).
+			Where("excluded <> true").
+			Where("created_at BETWEEN ? AND ?", before.Add(-window), before).
+			Order("created_at DESC, id DESC").
+			Limit(1).
+			Find(&sessions).Error; err != nil {
+			return nil, err
+		}
+		if len(sessions) > 0 {
+			return sessions[0], nil
+		}
+	}
+	return nil, nil
+}
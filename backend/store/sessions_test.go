@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/stretchr/testify/assert"
@@ -20,3 +21,65 @@ func TestGetSession(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, session.ID, foundSession.ID)
 }
+
+func TestGetLatestSessionsByEmail(t *testing.T) {
+	defer teardown(t)
+	ctx := context.Background()
+
+	email := "chilly@highlight.io"
+	other := "other@highlight.io"
+	sessions := []model.Session{
+		{ProjectID: 1, Email: &email},
+		{ProjectID: 1, Email: &email},
+		{ProjectID: 1, Email: &email, Excluded: true},
+		{ProjectID: 1, Email: &other},
+		{ProjectID: 2, Email: &email},
+	}
+	for idx := range sessions {
+		store.db.Create(&sessions[idx])
+	}
+
+	found, err := store.GetLatestSessionsByEmail(ctx, 1, email, 5)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, sessions[1].ID, found[0].ID)
+	assert.Equal(t, sessions[0].ID, found[1].ID)
+
+	found, err = store.GetLatestSessionsByEmail(ctx, 1, email, 1)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+}
+
+func TestFindLatestSessionOfUser(t *testing.T) {
+	defer teardown(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	email := "chilly@highlight.io"
+	sessions := []model.Session{
+		{ProjectID: 1, Identifier: "user-1", IP: "1.1.1.1", Model: model.Model{CreatedAt: now.Add(-2 * time.Hour)}},
+		{ProjectID: 1, Identifier: "user-1", Email: &email, Model: model.Model{CreatedAt: now.Add(-time.Hour)}},
+		{ProjectID: 1, IP: "1.1.1.1", Model: model.Model{CreatedAt: now.Add(-time.Minute)}},
+		{ProjectID: 1, Identifier: "user-2", Model: model.Model{CreatedAt: now.Add(-10 * time.Hour)}},
+		{ProjectID: 2, Identifier: "user-1", Model: model.Model{CreatedAt: now.Add(-time.Minute)}},
+	}
+	for idx := range sessions {
+		store.db.Create(&sessions[idx])
+	}
+
+	found, err := store.FindLatestSessionOfUser(ctx, 1, SessionUser{Identifier: "user-1", IP: "1.1.1.1"}, now, 4*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions[1].ID, found.ID)
+
+	found, err = store.FindLatestSessionOfUser(ctx, 1, SessionUser{Identifier: "unknown", Email: email}, now, 4*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions[1].ID, found.ID)
+
+	found, err = store.FindLatestSessionOfUser(ctx, 1, SessionUser{Identifier: "unknown", IP: "1.1.1.1"}, now, 4*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions[2].ID, found.ID)
+
+	found, err = store.FindLatestSessionOfUser(ctx, 1, SessionUser{Identifier: "user-2"}, now, 4*time.Hour)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
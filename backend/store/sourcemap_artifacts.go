@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/samber/lo"
+	"gorm.io/gorm/clause"
+)
+
+func sourcemapArtifactPathsKey(projectID int, version string) string {
+	return fmt.Sprintf("sourcemap-artifact-paths-%d-%s", projectID, version)
+}
+
+func (store *Store) GetSourcemapArtifacts(ctx context.Context, projectID int, version string) ([]*model.SourcemapArtifact, error) {
+	var artifacts []*model.SourcemapArtifact
+	err := store.db.WithContext(ctx).Where("project_id = ? AND version = ?", projectID, version).Order("path ASC").Find(&artifacts).Error
+	return artifacts, err
+}
+
+// GetSourcemapArtifactPaths returns the paths of the artifacts uploaded for a version, or for
+// artifacts without a version with a nil version. It is called for every enhanced stack trace, so
+// the lookup is cached briefly.
+func (store *Store) GetSourcemapArtifactPaths(ctx context.Context, projectID int, version *string) ([]string, error) {
+	var v string
+	if version != nil {
+		v = *version
+	}
+	paths, err := redis.CachedEval(ctx, store.redis, sourcemapArtifactPathsKey(projectID, v), 150*time.Millisecond, time.Minute, func() (*[]string, error) {
+		var paths []string
+		if err := store.db.WithContext(ctx).Model(&model.SourcemapArtifact{}).
+			Where("project_id = ? AND version = ?", projectID, v).Pluck("path", &paths).Error; err != nil {
+			return nil, err
+		}
+		return &paths, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *paths, nil
+}
+
+// UpsertSourcemapArtifacts saves uploaded artifacts, replacing those of the version at the same paths.
+func (store *Store) UpsertSourcemapArtifacts(ctx context.Context, artifacts []*model.SourcemapArtifact) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	if err := store.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "version"}, {Name: "path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "size", "sha256", "uploaded_by_admin_id"}),
+	}).Create(&artifacts).Error; err != nil {
+		return err
+	}
+
+	// errors of the version are enhanced with the new artifacts without waiting for the cache to expire
+	if store.redis == nil {
+		return nil
+	}
+	keys := lo.Uniq(lo.Map(artifacts, func(artifact *model.SourcemapArtifact, _ int) string {
+		return sourcemapArtifactPathsKey(artifact.ProjectID, artifact.Version)
+	}))
+	for _, key := range keys {
+		if err := store.redis.Cache.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertSourcemapArtifacts(t *testing.T) {
+	ctx := context.Background()
+
+	util.RunTestWithDBWipe(t, store.db, func(t *testing.T) {
+		project := model.Project{}
+		store.db.Create(&project)
+
+		paths, err := store.GetSourcemapArtifactPaths(ctx, project.ID, pointy.String("1.0.0"))
+		assert.NoError(t, err)
+		assert.Empty(t, paths)
+
+		assert.NoError(t, store.UpsertSourcemapArtifacts(ctx, []*model.SourcemapArtifact{
+			{ProjectID: project.ID, Version: "1.0.0", Path: "assets/index.js", Size: 10, SHA256: "a"},
+			{ProjectID: project.ID, Version: "1.0.0", Path: "assets/index.js.map", Size: 20, SHA256: "b"},
+			{ProjectID: project.ID, Path: "main.js", Size: 30, SHA256: "c"},
+		}))
+		// uploading an artifact again replaces it
+		assert.NoError(t, store.UpsertSourcemapArtifacts(ctx, []*model.SourcemapArtifact{
+			{ProjectID: project.ID, Version: "1.0.0", Path: "assets/index.js.map", Size: 25, SHA256: "d"},
+		}))
+
+		artifacts, err := store.GetSourcemapArtifacts(ctx, project.ID, "1.0.0")
+		assert.NoError(t, err)
+		assert.Len(t, artifacts, 2)
+		assert.Equal(t, "assets/index.js.map", artifacts[1].Path)
+		assert.Equal(t, int64(25), artifacts[1].Size)
+		assert.Equal(t, "d", artifacts[1].SHA256)
+
+		// the cached paths are invalidated by the upload
+		paths, err = store.GetSourcemapArtifactPaths(ctx, project.ID, pointy.String("1.0.0"))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"assets/index.js", "assets/index.js.map"}, paths)
+
+		paths, err = store.GetSourcemapArtifactPaths(ctx, project.ID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"main.js"}, paths)
+	})
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func sourcemapBucketKey(projectID int) string {
+	return fmt.Sprintf("sourcemap-bucket-%d", projectID)
+}
+
+// GetSourcemapBucket returns the sourcemap bucket of a project, or gorm.ErrRecordNotFound if it has
+// none. It is called for every enhanced stack trace, so the lookup is cached briefly.
+func (store *Store) GetSourcemapBucket(ctx context.Context, projectID int) (*model.SourcemapBucket, error) {
+	// a slice is cached so that projects without a bucket are cached as well
+	buckets, err := redis.CachedEval(ctx, store.redis, sourcemapBucketKey(projectID), 150*time.Millisecond, time.Minute, func() (*[]*model.SourcemapBucket, error) {
+		var buckets []*model.SourcemapBucket
+		if err := store.db.WithContext(ctx).Where(&model.SourcemapBucket{ProjectID: projectID}).Find(&buckets).Error; err != nil {
+			return nil, err
+		}
+		return &buckets, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(*buckets) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return (*buckets)[0], nil
+}
+
+// GetSourcemapBucketReader returns the reader of the sourcemap bucket of a project, or nil if it has
+// none or it is disabled.
+func (store *Store) GetSourcemapBucketReader(ctx context.Context, projectID int) (storage.SourcemapBucketReader, error) {
+	bucket, err := store.GetSourcemapBucket(ctx, projectID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if bucket.Disabled {
+		return nil, nil
+	}
+	return storage.GetSourcemapBucketReader(ctx, bucket)
+}
+
+// UpsertSourcemapBucket saves the sourcemap bucket of a project. The external id of the project is
+// generated with its first bucket, and kept when it is replaced so that the trust policy of the
+// role does not need to change.
+func (store *Store) UpsertSourcemapBucket(ctx context.Context, bucket *model.SourcemapBucket) error {
+	if bucket.ExternalID == "" {
+		bucket.ExternalID = uuid.New().String()
+	}
+	if err := store.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "provider", "bucket", "prefix", "region", "role_arn", "service_account_key", "disabled", "last_admin_to_edit_id",
+		}),
+	}).Create(bucket).Error; err != nil {
+		return err
+	}
+	if err := store.invalidateSourcemapBucket(ctx, bucket.ProjectID); err != nil {
+		return err
+	}
+	// a replaced bucket keeps its id and external id
+	return store.db.WithContext(ctx).Where(&model.SourcemapBucket{ProjectID: bucket.ProjectID}).Take(bucket).Error
+}
+
+func (store *Store) DeleteSourcemapBucket(ctx context.Context, projectID int) error {
+	if err := store.db.WithContext(ctx).Where(&model.SourcemapBucket{ProjectID: projectID}).Delete(&model.SourcemapBucket{}).Error; err != nil {
+		return err
+	}
+	return store.invalidateSourcemapBucket(ctx, projectID)
+}
+
+func (store *Store) invalidateSourcemapBucket(ctx context.Context, projectID int) error {
+	if store.redis == nil {
+		return nil
+	}
+	return store.redis.Cache.Delete(ctx, sourcemapBucketKey(projectID))
+}
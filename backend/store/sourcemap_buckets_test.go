@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestUpsertSourcemapBucket(t *testing.T) {
+	ctx := context.Background()
+
+	util.RunTestWithDBWipe(t, store.db, func(t *testing.T) {
+		project := model.Project{}
+		store.db.Create(&project)
+
+		_, err := store.GetSourcemapBucket(ctx, project.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		reader, err := store.GetSourcemapBucketReader(ctx, project.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, reader)
+
+		bucket := &model.SourcemapBucket{
+			ProjectID: project.ID,
+			Provider:  model.SourcemapBucketProviderS3,
+			Bucket:    "builds",
+			Region:    pointy.String("us-west-2"),
+			RoleARN:   pointy.String("arn:aws:iam::123456789012:role/highlight"),
+		}
+		assert.NoError(t, store.UpsertSourcemapBucket(ctx, bucket))
+		assert.NotEmpty(t, bucket.ExternalID)
+
+		// replacing the bucket keeps its external id
+		replaced := &model.SourcemapBucket{
+			ProjectID: project.ID,
+			Provider:  model.SourcemapBucketProviderS3,
+			Bucket:    "releases",
+			Region:    pointy.String("us-west-2"),
+			RoleARN:   pointy.String("arn:aws:iam::123456789012:role/highlight"),
+			Disabled:  true,
+		}
+		assert.NoError(t, store.UpsertSourcemapBucket(ctx, replaced))
+		assert.Equal(t, bucket.ID, replaced.ID)
+		assert.Equal(t, bucket.ExternalID, replaced.ExternalID)
+
+		saved, err := store.GetSourcemapBucket(ctx, project.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "releases", saved.Bucket)
+
+		// disabled buckets are not read
+		reader, err = store.GetSourcemapBucketReader(ctx, project.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, reader)
+
+		assert.NoError(t, store.DeleteSourcemapBucket(ctx, project.ID))
+		_, err = store.GetSourcemapBucket(ctx, project.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/highlight-run/highlight/backend/integrations/github"
+	"github.com/highlight-run/highlight/backend/integrations/gitlab"
 	"github.com/highlight-run/highlight/backend/model"
 	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/redis"
@@ -24,6 +25,9 @@ import (
 const GITHUB_ERROR_CONTEXT_LINES = 5
 const MAX_ERROR_KILLSWITCH = 5
 
+// gitSHARegex matches service versions that are commit hashes.
+var gitSHARegex = regexp.MustCompile(`^[0-9a-f]{5,40}$`)
+
 func (store *Store) GitHubFilePath(ctx context.Context, fileName string, buildPrefix *string, gitHubPrefix *string) string {
 	if buildPrefix != nil && gitHubPrefix != nil {
 		return strings.Replace(fileName, *buildPrefix, *gitHubPrefix, 1)
@@ -94,7 +98,7 @@ func (store *Store) FetchFileFromGitHub(ctx context.Context, trace *privateModel
 }
 
 func (store *Store) GitHubGitSHA(ctx context.Context, gitHubRepoPath string, serviceVersion string, gitHubClient github.ClientInterface) (*string, error) {
-	if regexp.MustCompile(`^[0-9a-f]{5,40}$`).MatchString(serviceVersion) {
+	if gitSHARegex.MatchString(serviceVersion) {
 		return &serviceVersion, nil
 	}
 
@@ -107,28 +111,100 @@ func (store *Store) GitHubGitSHA(ctx context.Context, gitHubRepoPath string, ser
 	})
 }
 
-func (store *Store) EnhanceTraceWithGitHub(ctx context.Context, trace *privateModel.ErrorTrace, service *model.Service, serviceVersion string, fileName string, gitHubClient github.ClientInterface) (*privateModel.ErrorTrace, error) {
+func (store *Store) GitLabGitSHA(ctx context.Context, gitLabRepoPath string, serviceVersion string, accessToken string) (*string, error) {
+	if gitSHARegex.MatchString(serviceVersion) {
+		return &serviceVersion, nil
+	}
+
+	return redis.CachedEval(ctx, store.redis, fmt.Sprintf("git-main-hash-gitlab-%s", gitLabRepoPath), 5*time.Second, 24*time.Hour, func() (*string, error) {
+		commitSha, err := gitlab.GetLatestCommitHash(accessToken, gitLabRepoPath)
+		if err != nil {
+			return nil, err
+		}
+		return &commitSha, nil
+	})
+}
+
+// sourceRepository is the GitHub or GitLab repository that the stacktraces of a service are enhanced with.
+type sourceRepository interface {
+	// path identifies the repository in the stored files and file error cache
+	path() string
+	source() privateModel.EnhancementSource
+	// fetchFile returns the base64 encoded content of a file at a commit
+	fetchFile(ctx context.Context, trace *privateModel.ErrorTrace, fileName string, serviceVersion string) (*string, error)
+	link(serviceVersion string, fileName string, lineNumber int) string
+	// compareCommits returns the commits after base up to head, newest first, with the files they changed
+	compareCommits(ctx context.Context, base string, head string) ([]*repositoryCommit, error)
+}
+
+type gitHubRepository struct {
+	store   *Store
+	service *model.Service
+	client  github.ClientInterface
+}
+
+func (r *gitHubRepository) path() string {
+	return *r.service.GithubRepoPath
+}
+
+func (r *gitHubRepository) source() privateModel.EnhancementSource {
+	return privateModel.EnhancementSourceGithub
+}
+
+func (r *gitHubRepository) fetchFile(ctx context.Context, trace *privateModel.ErrorTrace, fileName string, serviceVersion string) (*string, error) {
+	return r.store.FetchFileFromGitHub(ctx, trace, r.service, fileName, serviceVersion, r.client)
+}
+
+func (r *gitHubRepository) link(serviceVersion string, fileName string, lineNumber int) string {
+	return fmt.Sprintf("https://github.com/%s/blob/%s%s#L%d", *r.service.GithubRepoPath, serviceVersion, fileName, lineNumber)
+}
+
+type gitLabRepository struct {
+	service     *model.Service
+	accessToken string
+}
+
+func (r *gitLabRepository) path() string {
+	return "gitlab/" + *r.service.GitlabRepoPath
+}
+
+func (r *gitLabRepository) source() privateModel.EnhancementSource {
+	return privateModel.EnhancementSourceGitlab
+}
+
+func (r *gitLabRepository) fetchFile(ctx context.Context, trace *privateModel.ErrorTrace, fileName string, serviceVersion string) (*string, error) {
+	file, err := gitlab.GetRepoFile(r.accessToken, *r.service.GitlabRepoPath, fileName, serviceVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &file.Content, nil
+}
+
+func (r *gitLabRepository) link(serviceVersion string, fileName string, lineNumber int) string {
+	return gitlab.GetFileLink(*r.service.GitlabRepoPath, serviceVersion, fileName, lineNumber)
+}
+
+func (store *Store) enhanceTraceWithRepository(ctx context.Context, trace *privateModel.ErrorTrace, repo sourceRepository, serviceVersion string, fileName string) (*privateModel.ErrorTrace, error) {
 	lineNumber := trace.LineNumber
-	gitHubFileBytes, err := store.storageClient.ReadGitHubFile(ctx, *service.GithubRepoPath, fileName, serviceVersion)
+	fileBytes, err := store.storageClient.ReadGitHubFile(ctx, repo.path(), fileName, serviceVersion)
 
-	if err != nil || gitHubFileBytes == nil {
-		encodedFileContent, err := store.FetchFileFromGitHub(ctx, trace, service, fileName, serviceVersion, gitHubClient)
+	if err != nil || fileBytes == nil {
+		encodedFileContent, err := repo.fetchFile(ctx, trace, fileName, serviceVersion)
 		if err != nil {
 			return nil, err
 		} else if encodedFileContent == nil {
-			return nil, errors.New("Unable to fetch valid content from GitHub")
+			return nil, errors.Errorf("Unable to fetch valid content from %s", repo.source())
 		}
 
-		gitHubFileBytes = []byte(*encodedFileContent)
+		fileBytes = []byte(*encodedFileContent)
 
-		_, err = store.storageClient.PushGitHubFile(ctx, *service.GithubRepoPath, fileName, serviceVersion, gitHubFileBytes)
+		_, err = store.storageClient.PushGitHubFile(ctx, repo.path(), fileName, serviceVersion, fileBytes)
 		if err != nil {
 			log.WithContext(ctx).Error(errors.Wrap(err, "Error uploading to storage"))
 		}
 	}
 
-	gitHubFileString := string(gitHubFileBytes)
-	rawDecodedText, err := base64.StdEncoding.DecodeString(gitHubFileString)
+	rawDecodedText, err := base64.StdEncoding.DecodeString(string(fileBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -142,8 +218,8 @@ func (store *Store) EnhanceTraceWithGitHub(ctx context.Context, trace *privateMo
 		return nil, err
 	}
 
-	gitHubLink := fmt.Sprintf("https://github.com/%s/blob/%s%s#L%d", *service.GithubRepoPath, serviceVersion, fileName, *lineNumber)
-	enhancementSource := privateModel.EnhancementSourceGithub
+	link := repo.link(serviceVersion, fileName, *lineNumber)
+	enhancementSource := repo.source()
 	newStackTraceInput := privateModel.ErrorTrace{
 		FileName:                   trace.FileName,
 		LineNumber:                 trace.LineNumber,
@@ -152,7 +228,7 @@ func (store *Store) EnhanceTraceWithGitHub(ctx context.Context, trace *privateMo
 		SourceMappingErrorMetadata: trace.SourceMappingErrorMetadata,
 		EnhancementSource:          &enhancementSource,
 		EnhancementVersion:         &serviceVersion,
-		ExternalLink:               &gitHubLink,
+		ExternalLink:               &link,
 		LineContent:                lineContent,
 		LinesBefore:                beforeContent,
 		LinesAfter:                 afterContent,
@@ -160,14 +236,21 @@ func (store *Store) EnhanceTraceWithGitHub(ctx context.Context, trace *privateMo
 	return &newStackTraceInput, nil
 }
 
+// repositoryFilePath returns the path of a stacktrace file in the repository of the service.
+func (store *Store) repositoryFilePath(ctx context.Context, service *model.Service, fileName string) string {
+	filePath := store.GitHubFilePath(ctx, fileName, service.BuildPrefix, service.GithubPrefix)
+	// .NET frames of windows builds use backslash separators
+	return strings.ReplaceAll(filePath, `\`, "/")
+}
+
 // returns (1) trace to be use, (2) if the trace was attempted to be enhanced, and (3) if the trace was successfully enhanced
-func (store *Store) EnhanceTrace(ctx context.Context, trace *privateModel.ErrorTrace, service *model.Service, serviceVersion string, ignoredFiles []string, gitHubClient github.ClientInterface) (*privateModel.ErrorTrace, bool, bool) {
+func (store *Store) enhanceTrace(ctx context.Context, trace *privateModel.ErrorTrace, service *model.Service, serviceVersion string, ignoredFiles []string, repo sourceRepository) (*privateModel.ErrorTrace, bool, bool) {
 	if trace.FileName == nil || trace.LineNumber == nil {
-		log.WithContext(ctx).WithField("frame", trace).Info(fmt.Errorf("Cannot enhance trace frame with GitHub with invalid values"))
+		log.WithContext(ctx).WithField("frame", trace).Info(fmt.Errorf("Cannot enhance trace frame with %s with invalid values", repo.source()))
 		return trace, false, false
 	}
 
-	fileName := store.GitHubFilePath(ctx, *trace.FileName, service.BuildPrefix, service.GithubPrefix)
+	fileName := store.repositoryFilePath(ctx, service, *trace.FileName)
 	for _, fileExpr := range ignoredFiles {
 		if regexp.MustCompile(fileExpr).MatchString(fileName) {
 			return trace, false, false
@@ -175,15 +258,15 @@ func (store *Store) EnhanceTrace(ctx context.Context, trace *privateModel.ErrorT
 	}
 
 	// check if we've previously errored on this file
-	previousError, _ := store.redis.GetGitHubFileError(ctx, *service.GithubRepoPath, serviceVersion, fileName)
+	previousError, _ := store.redis.GetGitHubFileError(ctx, repo.path(), serviceVersion, fileName)
 	if previousError {
 		return trace, false, false
 	}
 
-	enhancedTrace, err := store.EnhanceTraceWithGitHub(ctx, trace, service, serviceVersion, fileName, gitHubClient)
+	enhancedTrace, err := store.enhanceTraceWithRepository(ctx, trace, repo, serviceVersion, fileName)
 	if err != nil {
-		log.WithContext(ctx).WithField("frame", trace).Error(errors.Wrap(err, "Error enhancing stacktrace frame from GitHub"))
-		_ = store.redis.SetGitHubFileError(ctx, *service.GithubRepoPath, serviceVersion, fileName)
+		log.WithContext(ctx).WithField("frame", trace).Error(errors.Wrapf(err, "Error enhancing stacktrace frame from %s", repo.source()))
+		_ = store.redis.SetGitHubFileError(ctx, repo.path(), serviceVersion, fileName)
 	}
 
 	if enhancedTrace == nil {
@@ -193,7 +276,46 @@ func (store *Store) EnhanceTrace(ctx context.Context, trace *privateModel.ErrorT
 	return enhancedTrace, true, true
 }
 
-func (store *Store) GitHubEnhancedStackTrace(ctx context.Context, stackTrace []*privateModel.ErrorTrace, workspace *model.Workspace, project *model.Project, errorObj *model.ErrorObject, validateService *model.Service) ([]*privateModel.ErrorTrace, error) {
+// serviceRepository returns the repository of a service, preferring GitHub over GitLab, along with
+// the commit of the service version.
+func (store *Store) serviceRepository(ctx context.Context, workspace *model.Workspace, service *model.Service, serviceVersion string) (sourceRepository, *string, error) {
+	if service.GithubRepoPath != nil {
+		gitHubAccessToken, err := store.integrationsClient.GetWorkspaceAccessToken(ctx, workspace, privateModel.IntegrationTypeGitHub)
+		if err != nil || gitHubAccessToken == nil {
+			return nil, nil, err
+		}
+
+		client, err := github.NewClient(ctx, *gitHubAccessToken, store.redis)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		validServiceVersion, err := store.GitHubGitSHA(ctx, *service.GithubRepoPath, serviceVersion, client)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &gitHubRepository{store: store, service: service, client: client}, validServiceVersion, nil
+	}
+
+	if service.GitlabRepoPath != nil {
+		gitLabAccessToken, err := store.integrationsClient.GetWorkspaceAccessToken(ctx, workspace, privateModel.IntegrationTypeGitLab)
+		if err != nil || gitLabAccessToken == nil {
+			return nil, nil, err
+		}
+
+		validServiceVersion, err := store.GitLabGitSHA(ctx, *service.GitlabRepoPath, serviceVersion, *gitLabAccessToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &gitLabRepository{service: service, accessToken: *gitLabAccessToken}, validServiceVersion, nil
+	}
+
+	return nil, nil, nil
+}
+
+// RepositoryEnhancedStackTrace links the frames of a stacktrace to the GitHub or GitLab repository of
+// the service at the commit of the service version, along with the code around each frame.
+func (store *Store) RepositoryEnhancedStackTrace(ctx context.Context, stackTrace []*privateModel.ErrorTrace, workspace *model.Workspace, project *model.Project, errorObj *model.ErrorObject, validateService *model.Service) ([]*privateModel.ErrorTrace, error) {
 	if errorObj.ServiceName == "" {
 		return nil, nil
 	}
@@ -202,25 +324,15 @@ func (store *Store) GitHubEnhancedStackTrace(ctx context.Context, stackTrace []*
 	var err error
 	if validateService == nil {
 		service, err = store.FindService(ctx, project.ID, errorObj.ServiceName)
-		if err != nil || service == nil || service.GithubRepoPath == nil || service.Status != "healthy" {
+		if err != nil || service == nil || (service.GithubRepoPath == nil && service.GitlabRepoPath == nil) || service.Status != "healthy" {
 			return nil, err
 		}
 	} else {
 		service = validateService
 	}
 
-	gitHubAccessToken, err := store.integrationsClient.GetWorkspaceAccessToken(ctx, workspace, privateModel.IntegrationTypeGitHub)
-	if err != nil || gitHubAccessToken == nil {
-		return nil, err
-	}
-
-	client, err := github.NewClient(ctx, *gitHubAccessToken, store.redis)
-	if err != nil {
-		return nil, err
-	}
-
-	validServiceVersion, err := store.GitHubGitSHA(ctx, *service.GithubRepoPath, errorObj.ServiceVersion, client)
-	if err != nil {
+	repo, validServiceVersion, err := store.serviceRepository(ctx, workspace, service, errorObj.ServiceVersion)
+	if err != nil || repo == nil {
 		return nil, err
 	}
 
@@ -234,7 +346,7 @@ func (store *Store) GitHubEnhancedStackTrace(ctx context.Context, stackTrace []*
 	failedAllEnhancements := true
 
 	for _, trace := range stackTrace {
-		enhancedTrace, fileEnhancable, fileEnhanced := store.EnhanceTrace(ctx, trace, service, *validServiceVersion, cfg.IgnoredFiles, client)
+		enhancedTrace, fileEnhancable, fileEnhanced := store.enhanceTrace(ctx, trace, service, *validServiceVersion, cfg.IgnoredFiles, repo)
 
 		newMappedStackTrace = append(newMappedStackTrace, enhancedTrace)
 		enhanceable = enhanceable || fileEnhancable
@@ -272,7 +384,7 @@ func (store *Store) EnhancedStackTrace(ctx context.Context, stackTrace string, w
 	}
 
 	var newMappedStackTraceString *string
-	mappedStackTrace, err := store.GitHubEnhancedStackTrace(ctx, structuredStackTrace, workspace, project, errorObj, validateService)
+	mappedStackTrace, err := store.RepositoryEnhancedStackTrace(ctx, structuredStackTrace, workspace, project, errorObj, validateService)
 	if err != nil {
 		return nil, structuredStackTrace, errors.Wrap(err, "Error enhancing stacktrace")
 	}
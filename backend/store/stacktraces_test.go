@@ -301,6 +301,16 @@ func TestGitHubGitSHA(t *testing.T) {
 	}
 }
 
+func TestGitLabGitSHA(t *testing.T) {
+	defer teardown(t)
+	ctx := context.Background()
+
+	// commit versions are used without querying gitlab
+	sha, err := store.GitLabGitSHA(ctx, "highlight/highlight", "1234567890", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567890", *sha)
+}
+
 type MockGithubClient struct{}
 
 func (c *MockGithubClient) GetRepoContent(ctx context.Context, githubPath string, path string, version string) (fileContent *github2.RepositoryContent, directoryContent []*github2.RepositoryContent, resp *github2.Response, err error) {
@@ -402,6 +412,12 @@ func (c *MockGithubClient) GetLatestCommitHash(ctx context.Context, githubPath s
 func (c *MockGithubClient) CreateIssue(ctx context.Context, repo string, issueRequest *github2.IssueRequest) (*github2.Issue, error) {
 	return nil, nil
 }
+func (c *MockGithubClient) CreateIssueComment(ctx context.Context, repo string, number int, body string) error {
+	return nil
+}
+func (c *MockGithubClient) GetIssueClosingCommit(ctx context.Context, repo string, number int) (string, error) {
+	return "", nil
+}
 func (c *MockGithubClient) ListLabels(ctx context.Context, repo string) ([]*github2.Label, error) {
 	return nil, nil
 }
@@ -411,3 +427,20 @@ func (c *MockGithubClient) ListRepos(ctx context.Context) ([]*github2.Repository
 func (c *MockGithubClient) DeleteInstallation(ctx context.Context, installation string) error {
 	return nil
 }
+func (c *MockGithubClient) CompareCommits(ctx context.Context, githubPath string, base string, head string) ([]*github2.RepositoryCommit, error) {
+	if githubPath == "highlight/error" {
+		return nil, errors.New("error")
+	}
+	return []*github2.RepositoryCommit{{SHA: ptr.String("1111111")}, {SHA: ptr.String("2222222")}}, nil
+}
+func (c *MockGithubClient) GetCommit(ctx context.Context, githubPath string, sha string) (*github2.RepositoryCommit, error) {
+	return &github2.RepositoryCommit{
+		SHA:     ptr.String(sha),
+		HTMLURL: ptr.String(fmt.Sprintf("https://github.com/%s/commit/%s", githubPath, sha)),
+		Commit: &github2.Commit{
+			Message: ptr.String(fmt.Sprintf("change %s\n\ndetails", sha)),
+			Author:  &github2.CommitAuthor{Name: ptr.String("Jane Doe"), Email: ptr.String("jane@example.com")},
+		},
+		Files: []*github2.CommitFile{{Filename: ptr.String(fmt.Sprintf("src/%s.js", sha))}},
+	}, nil
+}
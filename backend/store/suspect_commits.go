@@ -0,0 +1,222 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations/gitlab"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// maxSuspectCommits is the number of the newest commits of a release whose changed files are compared
+// with the stacktraces of its new error groups.
+const maxSuspectCommits = 20
+
+type repositoryCommit struct {
+	SHA         string
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+	URL         string
+	Files       []string
+}
+
+func (r *gitHubRepository) compareCommits(ctx context.Context, base string, head string) ([]*repositoryCommit, error) {
+	comparison, err := r.client.CompareCommits(ctx, *r.service.GithubRepoPath, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*repositoryCommit
+	for i := len(comparison) - 1; i >= 0 && len(commits) < maxSuspectCommits; i-- {
+		// the files of a commit are not listed in comparisons
+		commit, err := r.client.GetCommit(ctx, *r.service.GithubRepoPath, comparison[i].GetSHA())
+		if err != nil {
+			return nil, err
+		}
+		c := &repositoryCommit{
+			SHA:         commit.GetSHA(),
+			Message:     commitTitle(commit.GetCommit().GetMessage()),
+			AuthorName:  commit.GetCommit().GetAuthor().GetName(),
+			AuthorEmail: commit.GetCommit().GetAuthor().GetEmail(),
+			URL:         commit.GetHTMLURL(),
+		}
+		for _, file := range commit.Files {
+			c.Files = append(c.Files, file.GetFilename())
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+func (r *gitLabRepository) compareCommits(ctx context.Context, base string, head string) ([]*repositoryCommit, error) {
+	comparison, err := gitlab.CompareCommits(r.accessToken, *r.service.GitlabRepoPath, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*repositoryCommit
+	for i := len(comparison) - 1; i >= 0 && len(commits) < maxSuspectCommits; i-- {
+		diffs, err := gitlab.GetCommitDiff(r.accessToken, *r.service.GitlabRepoPath, comparison[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		c := &repositoryCommit{
+			SHA:         comparison[i].ID,
+			Message:     comparison[i].Title,
+			AuthorName:  comparison[i].AuthorName,
+			AuthorEmail: comparison[i].AuthorEmail,
+			URL:         comparison[i].WebURL,
+		}
+		for _, diff := range diffs {
+			c.Files = append(c.Files, diff.NewPath)
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+func commitTitle(message string) string {
+	title, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(title)
+}
+
+// releaseCommit returns the commit of a release, which is the commit of its deploy or its version
+// when the version is a commit hash.
+func releaseCommit(release *model.Release) string {
+	if release.CommitSHA != nil {
+		return *release.CommitSHA
+	}
+	if gitSHARegex.MatchString(release.Version) {
+		return release.Version
+	}
+	return ""
+}
+
+// previousReleaseCommit returns the commit of the latest release of the service that was created
+// before the release and has a commit.
+func (store *Store) previousReleaseCommit(ctx context.Context, release *model.Release) (string, error) {
+	var releases []*model.Release
+	if err := store.db.WithContext(ctx).
+		Where(&model.Release{ProjectID: release.ProjectID, ServiceName: release.ServiceName}).
+		Where("created_at < ?", release.CreatedAt).
+		Order("created_at DESC").
+		Limit(10).
+		Find(&releases).Error; err != nil {
+		return "", err
+	}
+	for _, previous := range releases {
+		if commit := releaseCommit(previous); commit != "" {
+			return commit, nil
+		}
+	}
+	return "", nil
+}
+
+// fileChanged is whether a changed file of a repository is the file of a frame, which may be an
+// absolute path or only the name of the file.
+func fileChanged(changedFile string, frameFile string) bool {
+	frameFile = strings.TrimPrefix(frameFile, "/")
+	return changedFile == frameFile ||
+		strings.HasSuffix(frameFile, "/"+changedFile) ||
+		strings.HasSuffix(changedFile, "/"+frameFile)
+}
+
+// suspectCommit returns the commit that changed the most files of a stacktrace, weighing the frames
+// closer to where the error was thrown higher. The files are in the order of the frames, deepest
+// first. Newer commits win ties, so the commits are expected newest first.
+func suspectCommit(commits []*repositoryCommit, frameFiles []string) *model.SuspectCommit {
+	var suspect *model.SuspectCommit
+	var suspectScore float64
+	for _, commit := range commits {
+		var score float64
+		var files []string
+		for idx, frameFile := range frameFiles {
+			if frameFile == "" {
+				continue
+			}
+			for _, changedFile := range commit.Files {
+				if fileChanged(changedFile, frameFile) {
+					score += 1 / float64(idx+1)
+					if !lo.Contains(files, changedFile) {
+						files = append(files, changedFile)
+					}
+					break
+				}
+			}
+		}
+		if score > suspectScore {
+			suspectScore = score
+			suspect = &model.SuspectCommit{
+				SHA:         commit.SHA,
+				Message:     commit.Message,
+				AuthorName:  commit.AuthorName,
+				AuthorEmail: commit.AuthorEmail,
+				URL:         commit.URL,
+				Files:       files,
+			}
+		}
+	}
+	return suspect
+}
+
+// FindSuspectCommit suggests the commit that most likely introduced an error, among the commits
+// since the previous release of its service that changed the files of its stacktrace. It returns
+// nil when the service has no repository, or the release of the error has no commit range.
+func (store *Store) FindSuspectCommit(ctx context.Context, workspace *model.Workspace, project *model.Project, errorObj *model.ErrorObject, stackTrace []*privateModel.ErrorTrace) (*model.SuspectCommit, error) {
+	if errorObj.ServiceName == "" || errorObj.ServiceVersion == "" || len(stackTrace) == 0 {
+		return nil, nil
+	}
+
+	service, err := store.FindService(ctx, project.ID, errorObj.ServiceName)
+	if err != nil || service == nil || (service.GithubRepoPath == nil && service.GitlabRepoPath == nil) {
+		return nil, err
+	}
+
+	release, err := store.GetRelease(ctx, project.ID, errorObj.ServiceName, errorObj.ServiceVersion)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	head := releaseCommit(release)
+	if head == "" {
+		return nil, nil
+	}
+	base, err := store.previousReleaseCommit(ctx, release)
+	if err != nil || base == "" || base == head {
+		return nil, err
+	}
+
+	repo, _, err := store.serviceRepository(ctx, workspace, service, head)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+
+	// the commits of a release do not change, and are compared with every new error group of the release
+	commits, err := redis.CachedEval(ctx, store.redis, fmt.Sprintf("suspect-commits-%s-%s-%s", repo.path(), base, head), 5*time.Second, 24*time.Hour, func() (*[]*repositoryCommit, error) {
+		commits, err := repo.compareCommits(ctx, base, head)
+		if err != nil {
+			return nil, err
+		}
+		return &commits, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error comparing commits of %s", repo.source())
+	}
+
+	frameFiles := make([]string, len(stackTrace))
+	for idx, trace := range stackTrace {
+		if trace != nil && trace.FileName != nil && *trace.FileName != "" {
+			frameFiles[idx] = store.repositoryFilePath(ctx, service, *trace.FileName)
+		}
+	}
+	return suspectCommit(*commits, frameFiles), nil
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuspectCommit(t *testing.T) {
+	commits := []*repositoryCommit{
+		{SHA: "3333333", Files: []string{"README.md"}},
+		{SHA: "2222222", Files: []string{"src/handlers/user.ts"}},
+		{SHA: "1111111", Files: []string{"src/db/client.ts", "src/handlers/user.ts"}},
+	}
+
+	// the commit that changed the deepest frame is the suspect
+	suspect := suspectCommit(commits, []string{"/app/src/db/client.ts", "/app/src/handlers/user.ts", ""})
+	assert.Equal(t, "1111111", suspect.SHA)
+	assert.Equal(t, []string{"src/db/client.ts", "src/handlers/user.ts"}, suspect.Files)
+
+	// newer commits win ties
+	suspect = suspectCommit(commits, []string{"/app/node_modules/express/index.js", "src/handlers/user.ts"})
+	assert.Equal(t, "2222222", suspect.SHA)
+
+	// file names of frames match the files of the repository
+	suspect = suspectCommit(commits, []string{"client.ts"})
+	assert.Equal(t, "1111111", suspect.SHA)
+
+	assert.Nil(t, suspectCommit(commits, []string{"/app/node_modules/express/index.js"}))
+	assert.Nil(t, suspectCommit(nil, []string{"src/db/client.ts"}))
+}
+
+func TestReleaseCommit(t *testing.T) {
+	assert.Equal(t, "a1b2c3d", releaseCommit(&model.Release{Version: "1.2.0", CommitSHA: pointy.String("a1b2c3d")}))
+	assert.Equal(t, "a1b2c3d", releaseCommit(&model.Release{Version: "a1b2c3d"}))
+	assert.Equal(t, "", releaseCommit(&model.Release{Version: "1.2.0"}))
+}
+
+func TestGitHubCompareCommits(t *testing.T) {
+	repo := &gitHubRepository{
+		store:   store,
+		service: &model.Service{GithubRepoPath: pointy.String("highlight/found")},
+		client:  &MockGithubClient{},
+	}
+
+	// commits are newest first, with their changed files
+	commits, err := repo.compareCommits(context.Background(), "0000000", "2222222")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "2222222", commits[0].SHA)
+	assert.Equal(t, "change 2222222", commits[0].Message)
+	assert.Equal(t, "Jane Doe", commits[0].AuthorName)
+	assert.Equal(t, "https://github.com/highlight/found/commit/2222222", commits[0].URL)
+	assert.Equal(t, []string{"src/2222222.js"}, commits[0].Files)
+
+	repo.service.GithubRepoPath = pointy.String("highlight/error")
+	_, err = repo.compareCommits(context.Background(), "0000000", "2222222")
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+func (store *Store) GetTraceAlerts(ctx context.Context, projectID int) ([]*model.TraceAlert, error) {
+	var alerts []*model.TraceAlert
+	err := store.db.WithContext(ctx).Where(&model.TraceAlert{ProjectID: projectID}).Order("created_at ASC").Find(&alerts).Error
+	return alerts, err
+}
+
+func (store *Store) GetTraceAlert(ctx context.Context, projectID int, alertID int) (*model.TraceAlert, error) {
+	var alert model.TraceAlert
+	err := store.db.WithContext(ctx).Where(&model.TraceAlert{Model: model.Model{ID: alertID}, ProjectID: projectID}).Take(&alert).Error
+	return &alert, err
+}
+
+func (store *Store) CreateTraceAlert(ctx context.Context, alert *model.TraceAlert) error {
+	return store.db.WithContext(ctx).Create(alert).Error
+}
+
+func (store *Store) UpdateTraceAlert(ctx context.Context, alert *model.TraceAlert) error {
+	// Select("*") so that cleared filters are written as empty. The escalation policy of an alert is
+	// set separately.
+	return store.db.WithContext(ctx).Model(alert).Select("*").Omit("created_at", "escalation_policy_id").Updates(alert).Error
+}
+
+func (store *Store) DeleteTraceAlert(ctx context.Context, projectID int, alertID int) error {
+	return store.db.WithContext(ctx).Where(&model.TraceAlert{Model: model.Model{ID: alertID}, ProjectID: projectID}).Delete(&model.TraceAlert{}).Error
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+func (store *Store) GetUptimeMonitors(ctx context.Context, projectID int) ([]*model.UptimeMonitor, error) {
+	var monitors []*model.UptimeMonitor
+	err := store.db.WithContext(ctx).Where(&model.UptimeMonitor{ProjectID: projectID}).Order("created_at ASC").Find(&monitors).Error
+	return monitors, err
+}
+
+func (store *Store) GetUptimeMonitor(ctx context.Context, projectID int, monitorID int) (*model.UptimeMonitor, error) {
+	var monitor model.UptimeMonitor
+	err := store.db.WithContext(ctx).Where(&model.UptimeMonitor{Model: model.Model{ID: monitorID}, ProjectID: projectID}).Take(&monitor).Error
+	return &monitor, err
+}
+
+func (store *Store) CreateUptimeMonitor(ctx context.Context, monitor *model.UptimeMonitor) error {
+	return store.db.WithContext(ctx).Create(monitor).Error
+}
+
+func (store *Store) UpdateUptimeMonitor(ctx context.Context, monitor *model.UptimeMonitor) error {
+	// Select("*") so that cleared optional assertions are written as null
+	return store.db.WithContext(ctx).Model(monitor).Select("*").Omit("created_at").Updates(monitor).Error
+}
+
+func (store *Store) DeleteUptimeMonitor(ctx context.Context, projectID int, monitorID int) error {
+	return store.db.WithContext(ctx).Where(&model.UptimeMonitor{Model: model.Model{ID: monitorID}, ProjectID: projectID}).Delete(&model.UptimeMonitor{}).Error
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/model"
+)
+
+type UpdateProjectWebhookSettingsParams struct {
+	MaxRetries    *int
+	SigningSecret *string
+}
+
+// UpdateProjectWebhookSettings updates the retries and the signing secret of the project's alert webhooks.
+func (store *Store) UpdateProjectWebhookSettings(ctx context.Context, projectID int, updates UpdateProjectWebhookSettingsParams) (*model.ProjectFilterSettings, error) {
+	projectFilterSettings, err := store.GetProjectFilterSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if updates.MaxRetries != nil {
+		projectFilterSettings.WebhookMaxRetries = *updates.MaxRetries
+	}
+	if updates.SigningSecret != nil {
+		projectFilterSettings.WebhookSigningSecret = updates.SigningSecret
+	}
+	if err := store.db.WithContext(ctx).Save(projectFilterSettings).Error; err != nil {
+		return nil, err
+	}
+
+	return projectFilterSettings, store.redis.Client.Del(ctx, getKey(projectID)).Err()
+}
+
+type ListWebhookDeliveriesParams struct {
+	// Return the deliveries older than this delivery id
+	Before    *int
+	EventType *string
+	Limit     int
+}
+
+// ListWebhookDeliveries returns the most recent alert webhook deliveries of the project.
+func (store *Store) ListWebhookDeliveries(ctx context.Context, projectID int, params ListWebhookDeliveriesParams) ([]*model.WebhookDelivery, error) {
+	query := store.db.WithContext(ctx).Where(&model.WebhookDelivery{ProjectID: projectID})
+	if params.Before != nil {
+		query = query.Where("id < ?", *params.Before)
+	}
+	if params.EventType != nil {
+		query = query.Where(&model.WebhookDelivery{EventType: *params.EventType})
+	}
+	if params.Limit <= 0 {
+		params.Limit = LIMIT
+	}
+
+	deliveries := []*model.WebhookDelivery{}
+	if err := query.Order("id DESC").Limit(params.Limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateProjectWebhookSettings(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	workspace := model.Workspace{}
+	store.db.Create(&workspace)
+
+	project := model.Project{WorkspaceID: workspace.ID}
+	store.db.Create(&project)
+
+	settings, err := store.GetProjectFilterSettings(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, settings.WebhookMaxRetries)
+	assert.Nil(t, settings.WebhookSigningSecret)
+
+	settings, err = store.UpdateProjectWebhookSettings(ctx, project.ID, UpdateProjectWebhookSettingsParams{
+		SigningSecret: ptr.String("whsec_abc"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "whsec_abc", *settings.WebhookSigningSecret)
+
+	settings, err = store.UpdateProjectWebhookSettings(ctx, project.ID, UpdateProjectWebhookSettingsParams{
+		MaxRetries: ptr.Int(0),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, settings.WebhookMaxRetries)
+	assert.Equal(t, "whsec_abc", *settings.WebhookSigningSecret)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	deliveries := []*model.WebhookDelivery{
+		{ProjectID: 1, EventType: "error.new"},
+		{ProjectID: 1, EventType: "log.alert"},
+		{ProjectID: 1, EventType: "error.new"},
+		{ProjectID: 2, EventType: "error.new"},
+	}
+	assert.NoError(t, store.db.Create(&deliveries).Error)
+	ids := lo.Map(deliveries, func(d *model.WebhookDelivery, _ int) int {
+		return d.ID
+	})
+
+	result, err := store.ListWebhookDeliveries(ctx, 1, ListWebhookDeliveriesParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{ids[2], ids[1], ids[0]}, lo.Map(result, func(d *model.WebhookDelivery, _ int) int {
+		return d.ID
+	}))
+
+	result, err = store.ListWebhookDeliveries(ctx, 1, ListWebhookDeliveriesParams{Before: &ids[2], EventType: ptr.String("error.new")})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{ids[0]}, lo.Map(result, func(d *model.WebhookDelivery, _ int) int {
+		return d.ID
+	}))
+
+	result, err = store.ListWebhookDeliveries(ctx, 3, ListWebhookDeliveriesParams{})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
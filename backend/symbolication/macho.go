@@ -0,0 +1,210 @@
+package symbolication
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/dwarf"
+	"debug/macho"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	e "github.com/pkg/errors"
+)
+
+const loadCmdUUID macho.LoadCmd = 0x1b
+
+type machoSymbol struct {
+	address uint64
+	name    string
+}
+
+// machoLine is a row of the dwarf line table. Rows without a file mark the end of a sequence.
+type machoLine struct {
+	address uint64
+	file    string
+	line    int
+}
+
+// SymbolTable resolves addresses of a single architecture of a dSYM or unstripped binary.
+type SymbolTable struct {
+	UUID string
+	// textAddress is the vm address of the __TEXT segment, which is where the image is loaded.
+	textAddress uint64
+	symbols     []machoSymbol
+	// lines is empty for binaries without debug information.
+	lines []machoLine
+}
+
+// NormalizeUUID formats a binary image uuid the way it is stored, lowercase without dashes.
+func NormalizeUUID(uuid string) string {
+	return strings.ToLower(strings.ReplaceAll(uuid, "-", ""))
+}
+
+func machoUUID(f *macho.File) string {
+	for _, load := range f.Loads {
+		raw := load.Raw()
+		if len(raw) >= 24 && f.ByteOrder.Uint32(raw[0:4]) == uint32(loadCmdUUID) {
+			return hex.EncodeToString(raw[8:24])
+		}
+	}
+	return ""
+}
+
+// ParseSymbolTables parses a thin or universal Mach-O file into a symbol table per architecture.
+func ParseSymbolTables(data []byte) ([]*SymbolTable, error) {
+	var files []*macho.File
+	if fat, err := macho.NewFatFile(bytes.NewReader(data)); err == nil {
+		for _, arch := range fat.Arches {
+			files = append(files, arch.File)
+		}
+	} else if f, err := macho.NewFile(bytes.NewReader(data)); err == nil {
+		files = append(files, f)
+	} else {
+		return nil, e.Wrap(err, "failed to parse mach-o file")
+	}
+
+	var tables []*SymbolTable
+	for _, f := range files {
+		table := &SymbolTable{UUID: machoUUID(f)}
+		if text := f.Segment("__TEXT"); text != nil {
+			table.textAddress = text.Addr
+		}
+		if f.Symtab != nil {
+			for _, sym := range f.Symtab.Syms {
+				// skip undefined and debugging symbols
+				if sym.Sect == 0 || sym.Type&0xe0 != 0 || sym.Name == "" {
+					continue
+				}
+				table.symbols = append(table.symbols, machoSymbol{address: sym.Value, name: sym.Name})
+			}
+		}
+		sort.Slice(table.symbols, func(i, j int) bool {
+			return table.symbols[i].address < table.symbols[j].address
+		})
+		table.lines = parseLineTable(f)
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// Lookup returns the name of the symbol containing the given offset from the image load address.
+func (table *SymbolTable) Lookup(offset uint64) (string, bool) {
+	address := table.textAddress + offset
+	idx := sort.Search(len(table.symbols), func(i int) bool {
+		return table.symbols[i].address > address
+	})
+	if idx == 0 {
+		return "", false
+	}
+	return strings.TrimPrefix(table.symbols[idx-1].name, "_"), true
+}
+
+// parseLineTable reads the dwarf line table of a dSYM, sorted by address.
+func parseLineTable(f *macho.File) []machoLine {
+	data, err := f.DWARF()
+	if err != nil {
+		return nil
+	}
+
+	var lines []machoLine
+	reader := data.Reader()
+	for {
+		entry, err := reader.Next()
+		if err != nil || entry == nil {
+			break
+		}
+		if entry.Tag == dwarf.TagCompileUnit {
+			if lineReader, err := data.LineReader(entry); err == nil && lineReader != nil {
+				var row dwarf.LineEntry
+				for lineReader.Next(&row) == nil {
+					if row.EndSequence || row.File == nil {
+						lines = append(lines, machoLine{address: row.Address})
+					} else {
+						lines = append(lines, machoLine{address: row.Address, file: row.File.Name, line: row.Line})
+					}
+				}
+			}
+		}
+		reader.SkipChildren()
+	}
+	// the end of a sequence is sorted before a sequence starting at the same address
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].address != lines[j].address {
+			return lines[i].address < lines[j].address
+		}
+		return lines[i].file == "" && lines[j].file != ""
+	})
+	return lines
+}
+
+// LookupLine returns the source file and line of the given offset from the image load address.
+func (table *SymbolTable) LookupLine(offset uint64) (string, int, bool) {
+	address := table.textAddress + offset
+	idx := sort.Search(len(table.lines), func(i int) bool {
+		return table.lines[i].address > address
+	})
+	if idx == 0 || table.lines[idx-1].file == "" {
+		return "", 0, false
+	}
+	return table.lines[idx-1].file, table.lines[idx-1].line, true
+}
+
+// ExtractDSYMBinaries returns the Mach-O files of an uploaded dSYM. A zipped .dSYM bundle holds
+// its binaries in Contents/Resources/DWARF, while any other upload is a single Mach-O file.
+func ExtractDSYMBinaries(data []byte) ([][]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return [][]byte{data}, nil
+	}
+
+	var binaries [][]byte
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.Contains(file.Name, ".dSYM/Contents/Resources/DWARF/") {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, e.Wrapf(err, "failed to open %s", file.Name)
+		}
+		binary, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, e.Wrapf(err, "failed to read %s", file.Name)
+		}
+		binaries = append(binaries, binary)
+	}
+	if len(binaries) == 0 {
+		return nil, e.New("archive does not contain a dsym")
+	}
+	return binaries, nil
+}
+
+func parseAddress(address string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(strings.ToLower(address), "0x"), 16, 64)
+}
+
+// findImage returns the image the address was loaded from: the named image if provided,
+// otherwise the image with the highest load address below the address.
+func findImage(images []*BinaryImage, name string, address uint64) (*BinaryImage, uint64) {
+	var match *BinaryImage
+	var matchAddress uint64
+	for _, image := range images {
+		loadAddress, err := parseAddress(image.LoadAddress)
+		if err != nil || loadAddress > address {
+			continue
+		}
+		if name != "" && image.Name == name {
+			return image, loadAddress
+		}
+		if match == nil || loadAddress > matchAddress {
+			match, matchAddress = image, loadAddress
+		}
+	}
+	if name != "" {
+		return nil, 0
+	}
+	return match, matchAddress
+}
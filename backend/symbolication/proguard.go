@@ -0,0 +1,173 @@
+package symbolication
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var javaFramePattern = regexp.MustCompile(`^\s*at ([\w$.]+)\.([\w$<>\-]+)\(([^:)]*)(?::(\d+))?\)`)
+
+type proGuardMethod struct {
+	startLine     int
+	endLine       int
+	originalName  string
+	originalStart int
+	originalEnd   int
+}
+
+type proGuardClass struct {
+	originalName string
+	methods      map[string][]*proGuardMethod
+}
+
+// ProGuardMapping is a parsed ProGuard / R8 mapping.txt, keyed by obfuscated class name.
+type ProGuardMapping struct {
+	classes map[string]*proGuardClass
+}
+
+// ParseProGuardMapping parses the mapping.txt emitted by ProGuard or R8.
+// Fields and comments are ignored since they do not appear in stacktraces.
+func ParseProGuardMapping(r io.Reader) (*ProGuardMapping, error) {
+	mapping := &ProGuardMapping{classes: map[string]*proGuardClass{}}
+
+	var current *proGuardClass
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		original, obfuscated, found := strings.Cut(line, " -> ")
+		if !found {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") {
+			current = &proGuardClass{
+				originalName: strings.TrimSpace(original),
+				methods:      map[string][]*proGuardMethod{},
+			}
+			mapping.classes[strings.TrimSuffix(strings.TrimSpace(obfuscated), ":")] = current
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		if method := parseProGuardMethod(strings.TrimSpace(original)); method != nil {
+			name := strings.TrimSpace(obfuscated)
+			current.methods[name] = append(current.methods[name], method)
+		}
+	}
+
+	return mapping, scanner.Err()
+}
+
+// parseProGuardMethod parses a member line such as `1:5:void foo(int):10:14`.
+// Returns nil for fields.
+func parseProGuardMethod(member string) *proGuardMethod {
+	method := &proGuardMethod{}
+
+	parts := strings.Split(member, ":")
+	if len(parts) >= 3 {
+		method.startLine, _ = strconv.Atoi(parts[0])
+		method.endLine, _ = strconv.Atoi(parts[1])
+		member = parts[2]
+		if len(parts) >= 4 {
+			method.originalStart, _ = strconv.Atoi(parts[3])
+		}
+		if len(parts) >= 5 {
+			method.originalEnd, _ = strconv.Atoi(parts[4])
+		}
+	}
+
+	// member is now `returnType name(args)`
+	paren := strings.Index(member, "(")
+	if paren < 0 {
+		return nil
+	}
+	_, name, found := strings.Cut(member[:paren], " ")
+	if !found {
+		return nil
+	}
+	method.originalName = name
+	return method
+}
+
+// originalLine maps an obfuscated line number to the original source line.
+func (m *proGuardMethod) originalLine(line int) int {
+	if m.originalStart == 0 {
+		return line
+	}
+	if m.originalEnd == 0 {
+		if m.startLine == m.endLine {
+			return m.originalStart
+		}
+		return m.originalStart + line - m.startLine
+	}
+	// ranges of different length mean the obfuscated range collapsed onto the original range start
+	if m.originalEnd-m.originalStart != m.endLine-m.startLine {
+		return m.originalStart
+	}
+	return m.originalStart + line - m.startLine
+}
+
+// Retrace returns the original class, method and line of an obfuscated java frame.
+// Names that are not present in the mapping are returned unchanged.
+func (mapping *ProGuardMapping) Retrace(className string, methodName string, line int) (string, string, int) {
+	class, ok := mapping.classes[className]
+	if !ok {
+		return className, methodName, line
+	}
+
+	methods := class.methods[methodName]
+	var match *proGuardMethod
+	for _, method := range methods {
+		if method.startLine == 0 && method.endLine == 0 {
+			if match == nil {
+				match = method
+			}
+			continue
+		}
+		if line >= method.startLine && line <= method.endLine {
+			match = method
+			break
+		}
+	}
+	if match == nil {
+		return class.originalName, methodName, line
+	}
+
+	// R8 may prefix the name with the class it was inlined from
+	originalClass, originalMethod := class.originalName, match.originalName
+	if idx := strings.LastIndex(originalMethod, "."); idx > 0 {
+		originalClass, originalMethod = originalMethod[:idx], originalMethod[idx+1:]
+	}
+	return originalClass, originalMethod, match.originalLine(line)
+}
+
+// ParseJavaStackTrace extracts frames from a java stacktrace such as `at a.b.c(SourceFile:12)`.
+func ParseJavaStackTrace(stackTrace string) []*CrashFrame {
+	var frames []*CrashFrame
+	for _, line := range strings.Split(stackTrace, "\n") {
+		matches := javaFramePattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		frame := &CrashFrame{
+			ClassName: matches[1],
+			Function:  matches[2],
+			FileName:  matches[3],
+		}
+		if matches[4] != "" {
+			frame.Line, _ = strconv.Atoi(matches[4])
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
@@ -0,0 +1,247 @@
+package symbolication
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	publicModel "github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/storage"
+	"github.com/openlyinc/pointy"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+)
+
+// dSYMs are identified by their uuid rather than the app version
+const DSYMVersion = "dsym"
+
+func ProGuardMappingFileName() string {
+	return "mobile/proguard/mapping.txt"
+}
+
+func DSYMFileName(uuid string) string {
+	return fmt.Sprintf("mobile/dsym/%s", NormalizeUUID(uuid))
+}
+
+// Symbolicator turns mobile crash reports into structured stacktraces using the
+// mapping files uploaded for the project. Parsed mappings are cached in memory since
+// crashes of the same release tend to arrive together.
+type Symbolicator struct {
+	storageClient storage.Client
+	cache         *ttlcache.Cache
+}
+
+func NewSymbolicator(storageClient storage.Client) *Symbolicator {
+	cache := ttlcache.NewCache()
+	cache.SetTTL(10 * time.Minute)
+	return &Symbolicator{storageClient: storageClient, cache: cache}
+}
+
+func (s *Symbolicator) getProGuardMapping(ctx context.Context, projectID int, version string) *ProGuardMapping {
+	key := fmt.Sprintf("proguard;%d;%s", projectID, version)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(*ProGuardMapping)
+	}
+
+	var mapping *ProGuardMapping
+	data, err := s.storageClient.ReadSourceMapFile(ctx, projectID, &version, ProGuardMappingFileName())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).WithField("version", version).Info("no proguard mapping found")
+	} else if mapping, err = ParseProGuardMapping(bytes.NewReader(data)); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to parse proguard mapping")
+		mapping = nil
+	}
+	// cache misses as well so that unmapped releases do not hit storage for every crash
+	s.cache.Set(key, mapping)
+	return mapping
+}
+
+func (s *Symbolicator) getSymbolTable(ctx context.Context, projectID int, uuid string) *SymbolTable {
+	uuid = NormalizeUUID(uuid)
+	key := fmt.Sprintf("dsym;%d;%s", projectID, uuid)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(*SymbolTable)
+	}
+
+	var table *SymbolTable
+	version := DSYMVersion
+	data, err := s.storageClient.ReadSourceMapFile(ctx, projectID, &version, DSYMFileName(uuid))
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).WithField("uuid", uuid).Info("no dsym found")
+	} else if tables, err := ParseSymbolTables(data); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to parse dsym")
+	} else {
+		for _, t := range tables {
+			if t.UUID == uuid || len(tables) == 1 {
+				table = t
+				break
+			}
+		}
+	}
+	s.cache.Set(key, table)
+	return table
+}
+
+// Symbolicate resolves the frames of the crash, returning the deepest frame first.
+// Frames that cannot be symbolicated are kept with the information reported by the SDK.
+func (s *Symbolicator) Symbolicate(ctx context.Context, projectID int, report *CrashReport) []*privateModel.ErrorTrace {
+	frames := report.Frames
+	if len(frames) == 0 && report.StackTrace != "" {
+		frames = ParseJavaStackTrace(report.StackTrace)
+	}
+
+	var traces []*privateModel.ErrorTrace
+	switch report.Platform {
+	case PlatformAndroid:
+		mapping := s.getProGuardMapping(ctx, projectID, report.AppVersion)
+		for _, frame := range frames {
+			className, function, line := frame.ClassName, frame.Function, frame.Line
+			if mapping != nil {
+				className, function, line = mapping.Retrace(className, function, line)
+			}
+			trace := &privateModel.ErrorTrace{
+				FileName:     pointy.String(frame.FileName),
+				FunctionName: pointy.String(fmt.Sprintf("%s.%s", className, function)),
+			}
+			if line > 0 {
+				trace.LineNumber = pointy.Int(line)
+			}
+			traces = append(traces, trace)
+		}
+	case PlatformIOS:
+		for idx, frame := range frames {
+			traces = append(traces, s.symbolicateNativeFrame(ctx, projectID, report.BinaryImages, frame, idx > 0))
+		}
+	default:
+		for _, frame := range frames {
+			traces = append(traces, &privateModel.ErrorTrace{
+				FileName:     pointy.String(frame.FileName),
+				FunctionName: pointy.String(frame.Function),
+				LineNumber:   pointy.Int(frame.Line),
+			})
+		}
+	}
+	return traces
+}
+
+// Deobfuscate retraces the java frames of a backend stacktrace, such as those reported through
+// opentelemetry by android and jvm services, with the proguard mapping uploaded for the release.
+// It returns whether any frame was deobfuscated.
+func (s *Symbolicator) Deobfuscate(ctx context.Context, projectID int, version string, traces []*privateModel.ErrorTrace) bool {
+	if version == "" || len(traces) == 0 {
+		return false
+	}
+	mapping := s.getProGuardMapping(ctx, projectID, version)
+	if mapping == nil {
+		return false
+	}
+
+	deobfuscated := false
+	for _, trace := range traces {
+		if trace.FunctionName == nil {
+			continue
+		}
+		idx := strings.LastIndex(*trace.FunctionName, ".")
+		if idx <= 0 {
+			continue
+		}
+		var line int
+		if trace.LineNumber != nil {
+			line = *trace.LineNumber
+		}
+		className, function, originalLine := mapping.Retrace((*trace.FunctionName)[:idx], (*trace.FunctionName)[idx+1:], line)
+		functionName := fmt.Sprintf("%s.%s", className, function)
+		if functionName == *trace.FunctionName && originalLine == line {
+			continue
+		}
+		trace.FunctionName = pointy.String(functionName)
+		if originalLine > 0 {
+			trace.LineNumber = pointy.Int(originalLine)
+		}
+		deobfuscated = true
+	}
+	return deobfuscated
+}
+
+// symbolicateNativeFrame resolves the function, file and line of a native frame from the dSYM of its
+// image. Frames other than the crashing frame hold return addresses, which point to the instruction
+// after the call, so they are resolved one byte earlier as atos does.
+func (s *Symbolicator) symbolicateNativeFrame(ctx context.Context, projectID int, images []*BinaryImage, frame *CrashFrame, isReturnAddress bool) *privateModel.ErrorTrace {
+	trace := &privateModel.ErrorTrace{
+		FileName:     pointy.String(frame.ImageName),
+		FunctionName: pointy.String(frame.Function),
+	}
+	if frame.FileName != "" {
+		trace.FileName = pointy.String(frame.FileName)
+	}
+	if frame.Line > 0 {
+		trace.LineNumber = pointy.Int(frame.Line)
+	}
+
+	address, err := parseAddress(frame.InstructionAddress)
+	if err != nil {
+		return trace
+	}
+	image, loadAddress := findImage(images, frame.ImageName, address)
+	if image == nil {
+		return trace
+	}
+	if frame.ImageName == "" {
+		trace.FileName = pointy.String(image.Name)
+	}
+
+	table := s.getSymbolTable(ctx, projectID, image.UUID)
+	if table == nil {
+		return trace
+	}
+	offset := address - loadAddress
+	if isReturnAddress && offset > 0 {
+		offset -= 1
+	}
+	if name, ok := table.Lookup(offset); ok {
+		trace.FunctionName = pointy.String(name)
+	}
+	if file, line, ok := table.LookupLine(offset); ok {
+		trace.FileName = pointy.String(file)
+		trace.LineNumber = pointy.Int(line)
+	}
+	return trace
+}
+
+// ToBackendErrorObject symbolicates the crash into an error object which is grouped
+// along with errors reported by backend SDKs.
+func (s *Symbolicator) ToBackendErrorObject(ctx context.Context, projectID int, report *CrashReport) (*publicModel.BackendErrorObjectInput, error) {
+	traces := s.Symbolicate(ctx, projectID, report)
+	stackTrace, err := json.Marshal(traces)
+	if err != nil {
+		return nil, e.Wrap(err, "failed to marshal symbolicated stacktrace")
+	}
+
+	event := report.Message
+	if report.ExceptionType != "" {
+		event = fmt.Sprintf("%s: %s", report.ExceptionType, report.Message)
+	}
+	timestamp := report.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return &publicModel.BackendErrorObjectInput{
+		SessionSecureID: report.SessionSecureID,
+		Event:           event,
+		Type:            report.ExceptionType,
+		Source:          report.Platform,
+		StackTrace:      string(stackTrace),
+		Timestamp:       timestamp,
+		Service: &publicModel.ServiceInput{
+			Name:    report.ServiceName,
+			Version: report.AppVersion,
+		},
+		Environment: report.Environment,
+	}, nil
+}
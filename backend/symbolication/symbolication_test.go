@@ -0,0 +1,188 @@
+package symbolication
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ReneKroon/ttlcache"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+const mappingTxt = `# compiler: R8
+com.example.app.MainActivity -> a.a:
+    int counter -> a
+    1:1:void <init>():10:10 -> <init>
+    3:5:void onCreate(android.os.Bundle):20:22 -> b
+    6:6:void com.example.app.Helper.inlined():44:44 -> b
+    void onClick() -> c
+com.example.app.Helper -> a.b:
+    1:4:void crash():30 -> a
+`
+
+func TestProGuardRetrace(t *testing.T) {
+	mapping, err := ParseProGuardMapping(strings.NewReader(mappingTxt))
+	assert.NoError(t, err)
+
+	class, method, line := mapping.Retrace("a.a", "b", 4)
+	assert.Equal(t, "com.example.app.MainActivity", class)
+	assert.Equal(t, "onCreate", method)
+	assert.Equal(t, 21, line)
+
+	class, method, line = mapping.Retrace("a.a", "b", 6)
+	assert.Equal(t, "com.example.app.Helper", class)
+	assert.Equal(t, "inlined", method)
+	assert.Equal(t, 44, line)
+
+	class, method, _ = mapping.Retrace("a.a", "c", 0)
+	assert.Equal(t, "com.example.app.MainActivity", class)
+	assert.Equal(t, "onClick", method)
+
+	class, method, line = mapping.Retrace("a.b", "a", 3)
+	assert.Equal(t, "com.example.app.Helper", class)
+	assert.Equal(t, "crash", method)
+	assert.Equal(t, 32, line)
+
+	class, method, line = mapping.Retrace("x.y", "z", 7)
+	assert.Equal(t, "x.y", class)
+	assert.Equal(t, "z", method)
+	assert.Equal(t, 7, line)
+}
+
+func TestDeobfuscate(t *testing.T) {
+	mapping, err := ParseProGuardMapping(strings.NewReader(mappingTxt))
+	assert.NoError(t, err)
+	s := &Symbolicator{cache: ttlcache.NewCache()}
+	s.cache.Set("proguard;1;1.0.0", mapping)
+
+	traces := []*privateModel.ErrorTrace{
+		{FunctionName: pointy.String("a.a.b"), FileName: pointy.String("SourceFile"), LineNumber: pointy.Int(4)},
+		{FunctionName: pointy.String("a.a.c"), FileName: pointy.String("Unknown Source")},
+		{FunctionName: pointy.String("android.os.Handler.dispatchMessage"), LineNumber: pointy.Int(106)},
+	}
+	assert.True(t, s.Deobfuscate(context.Background(), 1, "1.0.0", traces))
+	assert.Equal(t, "com.example.app.MainActivity.onCreate", *traces[0].FunctionName)
+	assert.Equal(t, 21, *traces[0].LineNumber)
+	assert.Equal(t, "com.example.app.MainActivity.onClick", *traces[1].FunctionName)
+	assert.Nil(t, traces[1].LineNumber)
+	assert.Equal(t, "android.os.Handler.dispatchMessage", *traces[2].FunctionName)
+	assert.Equal(t, 106, *traces[2].LineNumber)
+
+	// frames without a mapped class are left unchanged
+	assert.False(t, s.Deobfuscate(context.Background(), 1, "1.0.0", traces[2:]))
+	assert.False(t, s.Deobfuscate(context.Background(), 1, "", traces))
+}
+
+func TestParseJavaStackTrace(t *testing.T) {
+	frames := ParseJavaStackTrace(`java.lang.IllegalStateException: boom
+	at a.b.a(SourceFile:3)
+	at a.a.b(Unknown Source)
+	at android.os.Handler.dispatchMessage(Handler.java:106)`)
+	assert.Len(t, frames, 3)
+	assert.Equal(t, "a.b", frames[0].ClassName)
+	assert.Equal(t, "a", frames[0].Function)
+	assert.Equal(t, 3, frames[0].Line)
+	assert.Equal(t, 0, frames[1].Line)
+	assert.Equal(t, "Handler.java", frames[2].FileName)
+}
+
+func TestSymbolTableLookup(t *testing.T) {
+	table := &SymbolTable{
+		textAddress: 0x100000000,
+		symbols: []machoSymbol{
+			{address: 0x100001000, name: "_main"},
+			{address: 0x100001200, name: "_$s3App4crashyyF"},
+		},
+	}
+
+	_, ok := table.Lookup(0x10)
+	assert.False(t, ok)
+
+	name, ok := table.Lookup(0x1010)
+	assert.True(t, ok)
+	assert.Equal(t, "main", name)
+
+	name, _ = table.Lookup(0x1250)
+	assert.Equal(t, "$s3App4crashyyF", name)
+}
+
+func TestSymbolTableLookupLine(t *testing.T) {
+	table := &SymbolTable{
+		textAddress: 0x100000000,
+		lines: []machoLine{
+			{address: 0x100001000, file: "/src/App/main.swift", line: 3},
+			{address: 0x100001010, file: "/src/App/main.swift", line: 4},
+			{address: 0x100001020},
+			{address: 0x100001200, file: "/src/App/Crash.swift", line: 12},
+		},
+	}
+
+	_, _, ok := table.LookupLine(0x10)
+	assert.False(t, ok)
+
+	file, line, ok := table.LookupLine(0x1014)
+	assert.True(t, ok)
+	assert.Equal(t, "/src/App/main.swift", file)
+	assert.Equal(t, 4, line)
+
+	// addresses after the end of a sequence have no line
+	_, _, ok = table.LookupLine(0x1100)
+	assert.False(t, ok)
+
+	file, line, _ = table.LookupLine(0x1250)
+	assert.Equal(t, "/src/App/Crash.swift", file)
+	assert.Equal(t, 12, line)
+}
+
+func TestExtractDSYMBinaries(t *testing.T) {
+	binaries, err := ExtractDSYMBinaries([]byte("mach-o"))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("mach-o")}, binaries)
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"App.app.dSYM/Contents/Info.plist":                "plist",
+		"App.app.dSYM/Contents/Resources/DWARF/App":       "app",
+		"Kit.framework.dSYM/Contents/Resources/DWARF/Kit": "kit",
+	} {
+		f, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	binaries, err = ExtractDSYMBinaries(buf.Bytes())
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, [][]byte{[]byte("app"), []byte("kit")}, binaries)
+
+	buf.Reset()
+	writer = zip.NewWriter(&buf)
+	_, err = writer.Create("README.md")
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	_, err = ExtractDSYMBinaries(buf.Bytes())
+	assert.Error(t, err)
+}
+
+func TestFindImage(t *testing.T) {
+	images := []*BinaryImage{
+		{Name: "App", UUID: "A", LoadAddress: "0x104000000"},
+		{Name: "UIKitCore", UUID: "B", LoadAddress: "0x180000000"},
+	}
+
+	image, loadAddress := findImage(images, "", 0x104001000)
+	assert.Equal(t, "App", image.Name)
+	assert.Equal(t, uint64(0x104000000), loadAddress)
+
+	image, _ = findImage(images, "", 0x180000100)
+	assert.Equal(t, "UIKitCore", image.Name)
+
+	image, _ = findImage(images, "Missing", 0x180000100)
+	assert.Nil(t, image)
+}
@@ -0,0 +1,47 @@
+package symbolication
+
+import "time"
+
+type Platform = string
+
+const (
+	PlatformIOS     Platform = "ios"
+	PlatformAndroid Platform = "android"
+	// PlatformJVM uploads proguard mappings of jvm services, which report errors through opentelemetry.
+	PlatformJVM Platform = "jvm"
+)
+
+// CrashReport is a native crash reported by a mobile SDK.
+// Frames are ordered with the crashing frame first.
+type CrashReport struct {
+	SessionSecureID *string        `json:"session_secure_id"`
+	Platform        Platform       `json:"platform"`
+	AppVersion      string         `json:"app_version"`
+	Environment     string         `json:"environment"`
+	ServiceName     string         `json:"service_name"`
+	Timestamp       time.Time      `json:"timestamp"`
+	ExceptionType   string         `json:"exception_type"`
+	Message         string         `json:"message"`
+	Frames          []*CrashFrame  `json:"frames"`
+	BinaryImages    []*BinaryImage `json:"binary_images"`
+	// StackTrace is an optional raw java stacktrace for android crashes without structured frames.
+	StackTrace string `json:"stack_trace"`
+}
+
+type CrashFrame struct {
+	// ClassName is the (possibly obfuscated) class of a java frame.
+	ClassName string `json:"class_name"`
+	Function  string `json:"function"`
+	FileName  string `json:"file_name"`
+	Line      int    `json:"line"`
+	// InstructionAddress is the hex encoded address of a native frame.
+	InstructionAddress string `json:"instruction_address"`
+	// ImageName is the binary image containing a native frame.
+	ImageName string `json:"image_name"`
+}
+
+type BinaryImage struct {
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	LoadAddress string `json:"load_address"`
+}
@@ -417,43 +417,28 @@ func sendSlackAlert(ctx context.Context, db *gorm.DB, obj *model.Alert, input *S
 
 		eventBlock := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*<%s|Error event in %s>*\n```%s```\n%s", errorLink, locationName, errorEvent, sessionString), false, false)
 
+		// the buttons are handled by the slack interactions webhook, which updates the error group
+		actionValue := model.SlackErrorGroupActionValue(obj.ProjectID, input.Group.SecureID)
 		var actionBlocks []slack.BlockElement
-		caser := cases.Title(language.AmericanEnglish)
-		for _, action := range modelInputs.AllErrorState {
-			if input.Group.State == action {
+		for _, action := range []struct {
+			actionID string
+			title    string
+			state    modelInputs.ErrorState
+		}{
+			{actionID: model.SlackActionResolveErrorGroup, title: "Resolve", state: modelInputs.ErrorStateResolved},
+			{actionID: model.SlackActionIgnoreErrorGroup, title: "Ignore", state: modelInputs.ErrorStateIgnored},
+			{actionID: model.SlackActionSnoozeErrorGroup, title: "Snooze 24h"},
+			{actionID: model.SlackActionCreateErrorTicket, title: "Create Ticket"},
+		} {
+			if action.state != "" && input.Group.State == action.state {
 				continue
 			}
-
-			titleStr := string(action)
-			if action == modelInputs.ErrorStateIgnored || action == modelInputs.ErrorStateResolved {
-				titleStr = titleStr[:len(titleStr)-1]
-			}
-			button := slack.NewButtonBlockElement(
-				"",
-				"click",
-				slack.NewTextBlockObject(
-					slack.PlainTextType,
-					caser.String(strings.ToLower(titleStr)),
-					false,
-					false,
-				),
-			)
-			button.URL = routing.AttachQueryParam(ctx, errorLink, "action", strings.ToLower(string(action)))
-			actionBlocks = append(actionBlocks, button)
-		}
-
-		snoozeButton := slack.NewButtonBlockElement(
-			"",
-			"click",
-			slack.NewTextBlockObject(
-				slack.PlainTextType,
-				"Snooze",
-				false,
-				false,
-			),
-		)
-		snoozeButton.URL = routing.AttachQueryParam(ctx, errorLink, "action", "snooze")
-		actionBlocks = append(actionBlocks, snoozeButton)
+			actionBlocks = append(actionBlocks, slack.NewButtonBlockElement(
+				action.actionID,
+				actionValue,
+				slack.NewTextBlockObject(slack.PlainTextType, action.title, false, false),
+			))
+		}
 
 		var stackTrace []*modelInputs.ErrorTrace
 		var stackTraceBlock *slack.TextBlockObject
@@ -493,6 +478,10 @@ func sendSlackAlert(ctx context.Context, db *gorm.DB, obj *model.Alert, input *S
 			highlightLogo := *slack.NewImageBlockElement("https://app.highlight.io/logo192.png", "Highlight logo")
 			bodyBlockSet = append(bodyBlockSet, slack.NewContextBlock("", highlightLogo, stackTraceBlock))
 		}
+		if commit := input.Group.SuspectCommit; commit != nil {
+			suspectCommitBlock := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("Suspect commit: <%s|%s>", commit.URL, commit.String()), false, false)
+			bodyBlockSet = append(bodyBlockSet, slack.NewContextBlock("", suspectCommitBlock))
+		}
 	case model.AlertType.NEW_USER:
 		// header
 		headerBlock := slack.NewTextBlockObject(slack.MarkdownType, "*New User Alert*", false, false)
@@ -648,15 +637,29 @@ func GetLogAlertURL(projectId int, query string, startDate time.Time, endDate ti
 		projectId, queryStr, startDateStr, endDateStr)
 }
 
+func GetTraceAlertURL(projectId int, query string, startDate time.Time, endDate time.Time) string {
+	queryStr := url.QueryEscape(query)
+	startDateStr := url.QueryEscape(startDate.Format("2006-01-02T15:04:05.000Z"))
+	endDateStr := url.QueryEscape(endDate.Format("2006-01-02T15:04:05.000Z"))
+	frontendURL := os.Getenv("FRONTEND_URI")
+	return fmt.Sprintf("%s/%d/traces?query=%s&start_date=%s&end_date=%s", frontendURL,
+		projectId, queryStr, startDateStr, endDateStr)
+}
+
 type SendSlackAlertForLogAlertInput struct {
 	Body      string
 	Workspace *model.Workspace
 	StartDate time.Time
 	EndDate   time.Time
+	// Resolved sends the notification that the alert is back within its threshold.
+	Resolved bool
 }
 
 func SendSlackLogAlert(ctx context.Context, db *gorm.DB, obj *model.LogAlert, input *SendSlackAlertForLogAlertInput) error {
 	defer func() {
+		if input.Resolved {
+			return
+		}
 		db.Create(&model.LogAlertEvent{
 			LogAlertID: obj.ID,
 			Query:      obj.Query,
@@ -688,9 +691,16 @@ func SendSlackLogAlert(ctx context.Context, db *gorm.DB, obj *model.LogAlert, in
 	alertUrl := GetLogAlertURL(obj.ProjectID, obj.Query, input.StartDate, input.EndDate)
 
 	previewText := fmt.Sprintf("%s fired!", obj.Name)
+	header := fmt.Sprintf("*%s* fired!", obj.Name)
+	color := RED_ALERT
+	if input.Resolved {
+		previewText = fmt.Sprintf("%s resolved", obj.Name)
+		header = fmt.Sprintf("*%s* resolved", obj.Name)
+		color = GREEN_ALERT
+	}
 
 	var headerBlockSet []slack.Block
-	headerBlock := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*%s* fired!", obj.Name), false, false)
+	headerBlock := slack.NewTextBlockObject(slack.MarkdownType, header, false, false)
 	headerBlockSet = append(headerBlockSet, slack.NewSectionBlock(headerBlock, nil, nil))
 
 	var bodyBlockSet []slack.Block
@@ -714,7 +724,7 @@ func SendSlackLogAlert(ctx context.Context, db *gorm.DB, obj *model.LogAlert, in
 	bodyBlockSet = append(bodyBlockSet, slack.NewActionBlock("", actionBlocks...))
 
 	attachment := &slack.Attachment{
-		Color:  RED_ALERT,
+		Color:  color,
 		Blocks: slack.Blocks{BlockSet: bodyBlockSet},
 	}
 
@@ -817,3 +827,230 @@ func SendSlackMetricMonitorAlert(ctx context.Context, obj *model.MetricMonitor,
 
 	return nil
 }
+
+type SendSlackAlertForUptimeMonitorInput struct {
+	Message   string
+	Workspace *model.Workspace
+}
+
+func SendSlackUptimeMonitorAlert(ctx context.Context, obj *model.UptimeMonitor, input *SendSlackAlertForUptimeMonitorInput) error {
+	if obj == nil {
+		return errors.New("uptime monitor needs to be defined.")
+	}
+	if input.Workspace == nil {
+		return errors.New("workspace needs to be defined.")
+	}
+
+	channels, err := obj.GetChannelsToNotify()
+	if err != nil {
+		return errors.Wrap(err, "error getting channels to send UptimeMonitor Slack Alert")
+	}
+	if len(channels) <= 0 {
+		return nil
+	}
+
+	if input.Workspace.SlackAccessToken == nil {
+		log.WithContext(ctx).Printf("Slack Bot Client was not defined for sending uptime monitor alert")
+		return nil
+	}
+	slackClient := slack.New(*input.Workspace.SlackAccessToken)
+
+	frontendURL := os.Getenv("FRONTEND_URI")
+	alertUrl := fmt.Sprintf("%s/%d/alerts/uptime/%d", frontendURL, obj.ProjectID, obj.ID)
+	message := fmt.Sprintf("%s\n<%s|View Monitor>", input.Message, alertUrl)
+
+	log.WithContext(ctx).Info("Sending Slack Alert for Uptime Monitor")
+
+	for _, channel := range channels {
+		if channel.WebhookChannel == nil {
+			continue
+		}
+		slackChannelId := *channel.WebhookChannelID
+		slackChannelName := *channel.WebhookChannel
+
+		// The Highlight Slack bot needs to join the channel before it can send a message.
+		if strings.Contains(slackChannelName, "#") {
+			if _, _, _, err := slackClient.JoinConversation(slackChannelId); err != nil {
+				log.WithContext(ctx).WithFields(log.Fields{"project_id": obj.ProjectID}).Error(errors.Wrap(err, "failed to join slack channel while sending uptime monitor alert"))
+			}
+		}
+		_, _, err := slackClient.PostMessage(slackChannelId, slack.MsgOptionText(message, false),
+			slack.MsgOptionDisableLinkUnfurl(),
+			slack.MsgOptionDisableMediaUnfurl(),
+		)
+		if err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{"workspace_id": input.Workspace.ID, "message": message}).
+				Error(errors.Wrap(err, "error sending slack msg via bot api for uptime monitor alert"))
+		}
+	}
+
+	return nil
+}
+
+type SendSlackAlertForHeartbeatMonitorInput struct {
+	Message   string
+	Workspace *model.Workspace
+}
+
+func SendSlackHeartbeatMonitorAlert(ctx context.Context, obj *model.HeartbeatMonitor, input *SendSlackAlertForHeartbeatMonitorInput) error {
+	if obj == nil {
+		return errors.New("heartbeat monitor needs to be defined.")
+	}
+	if input.Workspace == nil {
+		return errors.New("workspace needs to be defined.")
+	}
+
+	channels, err := obj.GetChannelsToNotify()
+	if err != nil {
+		return errors.Wrap(err, "error getting channels to send HeartbeatMonitor Slack Alert")
+	}
+	if len(channels) <= 0 {
+		return nil
+	}
+
+	if input.Workspace.SlackAccessToken == nil {
+		log.WithContext(ctx).Printf("Slack Bot Client was not defined for sending heartbeat monitor alert")
+		return nil
+	}
+	slackClient := slack.New(*input.Workspace.SlackAccessToken)
+
+	frontendURL := os.Getenv("FRONTEND_URI")
+	alertUrl := fmt.Sprintf("%s/%d/alerts/heartbeats/%d", frontendURL, obj.ProjectID, obj.ID)
+	message := fmt.Sprintf("%s\n<%s|View Monitor>", input.Message, alertUrl)
+
+	log.WithContext(ctx).Info("Sending Slack Alert for Heartbeat Monitor")
+
+	for _, channel := range channels {
+		if channel.WebhookChannel == nil {
+			continue
+		}
+		slackChannelId := *channel.WebhookChannelID
+		slackChannelName := *channel.WebhookChannel
+
+		// The Highlight Slack bot needs to join the channel before it can send a message.
+		if strings.Contains(slackChannelName, "#") {
+			if _, _, _, err := slackClient.JoinConversation(slackChannelId); err != nil {
+				log.WithContext(ctx).WithFields(log.Fields{"project_id": obj.ProjectID}).Error(errors.Wrap(err, "failed to join slack channel while sending heartbeat monitor alert"))
+			}
+		}
+		_, _, err := slackClient.PostMessage(slackChannelId, slack.MsgOptionText(message, false),
+			slack.MsgOptionDisableLinkUnfurl(),
+			slack.MsgOptionDisableMediaUnfurl(),
+		)
+		if err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{"workspace_id": input.Workspace.ID, "message": message}).
+				Error(errors.Wrap(err, "error sending slack msg via bot api for heartbeat monitor alert"))
+		}
+	}
+
+	return nil
+}
+
+type SendSlackAlertForTraceAlertInput struct {
+	Message   string
+	Workspace *model.Workspace
+	AlertURL  string
+}
+
+func SendSlackTraceAlert(ctx context.Context, obj *model.TraceAlert, input *SendSlackAlertForTraceAlertInput) error {
+	if obj == nil {
+		return errors.New("trace alert needs to be defined.")
+	}
+	if input.Workspace == nil {
+		return errors.New("workspace needs to be defined.")
+	}
+
+	channels, err := obj.GetChannelsToNotify()
+	if err != nil {
+		return errors.Wrap(err, "error getting channels to send TraceAlert Slack Alert")
+	}
+	if len(channels) <= 0 {
+		return nil
+	}
+
+	if input.Workspace.SlackAccessToken == nil {
+		log.WithContext(ctx).Printf("Slack Bot Client was not defined for sending trace alert")
+		return nil
+	}
+	slackClient := slack.New(*input.Workspace.SlackAccessToken)
+
+	message := fmt.Sprintf("%s\n<%s|View Traces>", input.Message, input.AlertURL)
+
+	log.WithContext(ctx).Info("Sending Slack Alert for Trace Alert")
+
+	for _, channel := range channels {
+		if channel.WebhookChannel == nil {
+			continue
+		}
+		slackChannelId := *channel.WebhookChannelID
+		slackChannelName := *channel.WebhookChannel
+
+		// The Highlight Slack bot needs to join the channel before it can send a message.
+		if strings.Contains(slackChannelName, "#") {
+			if _, _, _, err := slackClient.JoinConversation(slackChannelId); err != nil {
+				log.WithContext(ctx).WithFields(log.Fields{"project_id": obj.ProjectID}).Error(errors.Wrap(err, "failed to join slack channel while sending trace alert"))
+			}
+		}
+		_, _, err := slackClient.PostMessage(slackChannelId, slack.MsgOptionText(message, false),
+			slack.MsgOptionDisableLinkUnfurl(),
+			slack.MsgOptionDisableMediaUnfurl(),
+		)
+		if err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{"workspace_id": input.Workspace.ID, "message": message}).
+				Error(errors.Wrap(err, "error sending slack msg via bot api for trace alert"))
+		}
+	}
+
+	return nil
+}
+
+type SendSlackEscalationInput struct {
+	Message   string
+	Workspace *model.Workspace
+	Channels  []*modelInputs.SanitizedSlackChannel
+}
+
+func SendSlackEscalation(ctx context.Context, obj *model.AlertEscalation, input *SendSlackEscalationInput) error {
+	if obj == nil {
+		return errors.New("alert escalation needs to be defined.")
+	}
+	if input.Workspace == nil {
+		return errors.New("workspace needs to be defined.")
+	}
+	if len(input.Channels) <= 0 {
+		return nil
+	}
+
+	if input.Workspace.SlackAccessToken == nil {
+		log.WithContext(ctx).Printf("Slack Bot Client was not defined for sending alert escalation")
+		return nil
+	}
+	slackClient := slack.New(*input.Workspace.SlackAccessToken)
+
+	log.WithContext(ctx).Info("Sending Slack Alert for Alert Escalation")
+
+	for _, channel := range input.Channels {
+		if channel.WebhookChannel == nil || channel.WebhookChannelID == nil {
+			continue
+		}
+		slackChannelId := *channel.WebhookChannelID
+		slackChannelName := *channel.WebhookChannel
+
+		// The Highlight Slack bot needs to join the channel before it can send a message.
+		if strings.Contains(slackChannelName, "#") {
+			if _, _, _, err := slackClient.JoinConversation(slackChannelId); err != nil {
+				log.WithContext(ctx).WithFields(log.Fields{"project_id": obj.ProjectID}).Error(errors.Wrap(err, "failed to join slack channel while sending alert escalation"))
+			}
+		}
+		_, _, err := slackClient.PostMessage(slackChannelId, slack.MsgOptionText(input.Message, false),
+			slack.MsgOptionDisableLinkUnfurl(),
+			slack.MsgOptionDisableMediaUnfurl(),
+		)
+		if err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{"workspace_id": input.Workspace.ID, "message": input.Message}).
+				Error(errors.Wrap(err, "error sending slack msg via bot api for alert escalation"))
+		}
+	}
+
+	return nil
+}
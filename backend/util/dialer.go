@@ -0,0 +1,42 @@
+package util
+
+import (
+	"net"
+	"syscall"
+	"time"
+
+	e "github.com/pkg/errors"
+)
+
+// ErrNonPublicAddress is returned when dialing an address that is not publicly routable.
+var ErrNonPublicAddress = e.New("address is not publicly routable")
+
+// IsPublicIP reports whether an ip may be dialed for a user supplied url, ie. it is not a
+// loopback, private, link-local or unspecified address.
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// NewPublicDialer returns a dialer that refuses to connect to addresses that are not public, so
+// that user supplied urls cannot reach internal services. The address is checked once it is
+// resolved, so that a hostname cannot resolve to an internal address after being validated.
+func NewPublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return ErrNonPublicAddress
+			}
+			return nil
+		},
+	}
+}
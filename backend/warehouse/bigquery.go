@@ -0,0 +1,160 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+var BigQueryApiBaseUrl = "https://bigquery.googleapis.com/bigquery/v2"
+
+const bigQueryScope = "https://www.googleapis.com/auth/bigquery"
+
+const requestTimeout = 30 * time.Second
+
+// jobPollInterval is how often load jobs and statements are polled until they complete.
+var jobPollInterval = 2 * time.Second
+
+// BigQueryLoader loads files from GCS into the tables of a BigQuery dataset with load jobs, using
+// the credentials of a service account.
+type BigQueryLoader struct {
+	projectID  string
+	dataset    string
+	httpClient *http.Client
+}
+
+// NewBigQueryLoader authenticates with the json key of a service account. The project of the
+// dataset defaults to the project of the service account.
+func NewBigQueryLoader(ctx context.Context, credentialsJSON []byte, projectID string, dataset string) (*BigQueryLoader, error) {
+	credentials, err := google.CredentialsFromJSON(ctx, credentialsJSON, bigQueryScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid BigQuery service account credentials")
+	}
+	if projectID == "" {
+		projectID = credentials.ProjectID
+	}
+	if projectID == "" || dataset == "" {
+		return nil, errors.New("BigQuery project and dataset are required")
+	}
+	return newBigQueryLoader(credentials.TokenSource, projectID, dataset), nil
+}
+
+func newBigQueryLoader(tokenSource oauth2.TokenSource, projectID string, dataset string) *BigQueryLoader {
+	httpClient := oauth2.NewClient(context.Background(), tokenSource)
+	httpClient.Timeout = requestTimeout
+	return &BigQueryLoader{projectID: projectID, dataset: dataset, httpClient: httpClient}
+}
+
+func (b *BigQueryLoader) doRequest(ctx context.Context, method string, path string, data any, result any) error {
+	var body io.Reader
+	if data != nil {
+		d, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(d)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, BigQueryApiBaseUrl+path, body)
+	if err != nil {
+		return errors.Wrap(err, "error creating api request to BigQuery")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := b.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error getting response from BigQuery endpoint")
+	}
+	defer res.Body.Close()
+
+	r, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading response body from BigQuery endpoint")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("BigQuery API responded with error; status_code=" + res.Status + "; body=" + string(r))
+	}
+	if err := json.Unmarshal(r, result); err != nil {
+		return errors.Wrap(err, "error unmarshaling BigQuery response")
+	}
+	return nil
+}
+
+type bigQueryJob struct {
+	JobReference struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference"`
+	Status struct {
+		State       string `json:"state"`
+		ErrorResult *struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errorResult"`
+	} `json:"status"`
+}
+
+func bigQuerySchema(table *Table) []map[string]string {
+	fields := make([]map[string]string, 0, len(table.Columns)+1)
+	for _, column := range table.Columns {
+		fields = append(fields, map[string]string{"name": column.Name, "type": string(column.Type), "mode": "NULLABLE"})
+	}
+	return append(fields, map[string]string{"name": ExportDateColumn, "type": string(ColumnTypeDate), "mode": "REQUIRED"})
+}
+
+// Load replaces the partition of the day of a table, which is partitioned by its export date, with
+// the rows of the file of the day. The table is created when it does not exist.
+func (b *BigQueryLoader) Load(ctx context.Context, table *Table, day time.Time, storage *Storage) error {
+	partition := day.UTC().Format("20060102")
+	job := map[string]any{
+		"jobReference": map[string]string{
+			"projectId": b.projectID,
+			"jobId":     fmt.Sprintf("%s_%s_%s", table.VersionedName(), partition, strings.ReplaceAll(uuid.New().String(), "-", "")),
+		},
+		"configuration": map[string]any{
+			"load": map[string]any{
+				"sourceUris":   []string{storage.URI(table, day)},
+				"sourceFormat": "PARQUET",
+				"destinationTable": map[string]string{
+					"projectId": b.projectID,
+					"datasetId": b.dataset,
+					"tableId":   table.VersionedName() + "$" + partition,
+				},
+				"schema":            map[string]any{"fields": bigQuerySchema(table)},
+				"timePartitioning":  map[string]string{"type": "DAY", "field": ExportDateColumn},
+				"createDisposition": "CREATE_IF_NEEDED",
+				"writeDisposition":  "WRITE_TRUNCATE",
+			},
+		},
+	}
+
+	var result bigQueryJob
+	if err := b.doRequest(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/jobs", url.PathEscape(b.projectID)), job, &result); err != nil {
+		return err
+	}
+	for result.Status.State != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobPollInterval):
+		}
+		path := fmt.Sprintf("/projects/%s/jobs/%s?location=%s", url.PathEscape(b.projectID), url.PathEscape(result.JobReference.JobID), url.QueryEscape(result.JobReference.Location))
+		if err := b.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return err
+		}
+	}
+	if result.Status.ErrorResult != nil {
+		return errors.Errorf("BigQuery load job %s failed; reason=%s; message=%s", result.JobReference.JobID, result.Status.ErrorResult.Reason, result.Status.ErrorResult.Message)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package warehouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestBigQueryLoader_Load(t *testing.T) {
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/my-project/jobs":
+			var job struct {
+				Configuration struct {
+					Load map[string]any `json:"load"`
+				} `json:"configuration"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&job))
+			load := job.Configuration.Load
+			assert.Equal(t, []any{"gs://bucket/v1/sessions/date=2024-01-02/sessions.parquet"}, load["sourceUris"])
+			assert.Equal(t, map[string]any{"projectId": "my-project", "datasetId": "highlight", "tableId": "highlight_sessions_v1$20240102"}, load["destinationTable"])
+			assert.Equal(t, "WRITE_TRUNCATE", load["writeDisposition"])
+			fields := load["schema"].(map[string]any)["fields"].([]any)
+			assert.Len(t, fields, len(SessionsTable.Columns)+1)
+			_, _ = w.Write([]byte(`{"jobReference":{"jobId":"job-1","location":"US"},"status":{"state":"RUNNING"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/my-project/jobs/job-1":
+			assert.Equal(t, "US", r.URL.Query().Get("location"))
+			polls++
+			if polls == 1 {
+				_, _ = w.Write([]byte(`{"jobReference":{"jobId":"job-1","location":"US"},"status":{"state":"RUNNING"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"jobReference":{"jobId":"job-1","location":"US"},"status":{"state":"DONE","errorResult":{"reason":"invalid","message":"bad parquet"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	baseUrl, interval := BigQueryApiBaseUrl, jobPollInterval
+	BigQueryApiBaseUrl, jobPollInterval = server.URL, time.Millisecond
+	defer func() { BigQueryApiBaseUrl, jobPollInterval = baseUrl, interval }()
+
+	storage, err := ParseStorage("gs://bucket", "", "")
+	assert.NoError(t, err)
+	loader := newBigQueryLoader(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), "my-project", "highlight")
+	err = loader.Load(context.Background(), SessionsTable, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), storage)
+	assert.ErrorContains(t, err, "bad parquet")
+	assert.Equal(t, 2, polls)
+}
@@ -0,0 +1,162 @@
+package warehouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/huandu/go-sqlbuilder"
+)
+
+// SchemaVersion is the version of the exported tables. It is part of the path of the exported
+// files and the name of the warehouse tables, so that a change to the columns of a table is loaded
+// into a new table rather than failing to load into the tables of a previous version.
+const SchemaVersion = 1
+
+// ExportDateColumn is the day that a row was exported for, which partitions the warehouse tables
+// so that the export of a day can be replaced when it is retried.
+const ExportDateColumn = "export_date"
+
+type ColumnType string
+
+const (
+	ColumnTypeString    ColumnType = "STRING"
+	ColumnTypeInt64     ColumnType = "INT64"
+	ColumnTypeFloat64   ColumnType = "FLOAT64"
+	ColumnTypeBool      ColumnType = "BOOL"
+	ColumnTypeTimestamp ColumnType = "TIMESTAMP"
+	ColumnTypeDate      ColumnType = "DATE"
+)
+
+// Column is a column of an exported table and the ClickHouse expression that it is selected as.
+// Expressions are cast to the ClickHouse types that are written as the Parquet type of the column,
+// ie. a DateTime64 is written as a timestamp and a Date32 as a date.
+type Column struct {
+	Name string
+	Type ColumnType
+	Expr string
+}
+
+// Table is an exported table, whose rows of a day are selected from a ClickHouse table.
+type Table struct {
+	Name    string
+	Columns []Column
+	// source is the ClickHouse table, and the columns of its project and of the time of a row.
+	source        string
+	projectColumn string
+	timeColumn    string
+	// final selects the latest version of the rows of a ReplacingMergeTree table.
+	final bool
+	// sampled tables export a sample of their rows, by the hash of their sampleColumn.
+	sampleColumn string
+}
+
+var SessionsTable = &Table{
+	Name: "sessions",
+	Columns: []Column{
+		{"id", ColumnTypeInt64, "toInt64(ID)"},
+		{"secure_id", ColumnTypeString, "SecureID"},
+		{"created_at", ColumnTypeTimestamp, "CreatedAt"},
+		{"updated_at", ColumnTypeTimestamp, "UpdatedAt"},
+		{"identified", ColumnTypeBool, "Identified"},
+		{"identifier", ColumnTypeString, "Identifier"},
+		{"fingerprint", ColumnTypeInt64, "toInt64(Fingerprint)"},
+		{"city", ColumnTypeString, "City"},
+		{"country", ColumnTypeString, "Country"},
+		{"os_name", ColumnTypeString, "OSName"},
+		{"os_version", ColumnTypeString, "OSVersion"},
+		{"browser_name", ColumnTypeString, "BrowserName"},
+		{"browser_version", ColumnTypeString, "BrowserVersion"},
+		{"environment", ColumnTypeString, "Environment"},
+		{"app_version", ColumnTypeString, "AppVersion"},
+		{"length_ms", ColumnTypeInt64, "toInt64(Length)"},
+		{"active_length_ms", ColumnTypeInt64, "toInt64(ActiveLength)"},
+		{"pages_visited", ColumnTypeInt64, "toInt64(ifNull(PagesVisited, 0))"},
+		{"has_errors", ColumnTypeBool, "HasErrors"},
+		{"has_rage_clicks", ColumnTypeBool, "HasRageClicks"},
+		{"first_time", ColumnTypeBool, "FirstTime"},
+		{"processed", ColumnTypeBool, "Processed"},
+		{"excluded", ColumnTypeBool, "Excluded"},
+	},
+	source:        clickhouse.SessionsTable,
+	projectColumn: "ProjectID",
+	timeColumn:    "CreatedAt",
+	final:         true,
+}
+
+var ErrorGroupsTable = &Table{
+	Name: "error_groups",
+	Columns: []Column{
+		{"id", ColumnTypeInt64, "toInt64(ID)"},
+		{"created_at", ColumnTypeTimestamp, "CreatedAt"},
+		{"updated_at", ColumnTypeTimestamp, "UpdatedAt"},
+		{"event", ColumnTypeString, "Event"},
+		{"type", ColumnTypeString, "toString(Type)"},
+		{"status", ColumnTypeString, "toString(Status)"},
+		{"tag", ColumnTypeString, "ifNull(ErrorTagTitle, '')"},
+	},
+	source:        clickhouse.ErrorGroupsTable,
+	projectColumn: "ProjectID",
+	timeColumn:    "UpdatedAt",
+	final:         true,
+}
+
+var LogsTable = &Table{
+	Name: "logs",
+	Columns: []Column{
+		{"timestamp", ColumnTypeTimestamp, "toDateTime64(Timestamp, 6)"},
+		{"uuid", ColumnTypeString, "toString(UUID)"},
+		{"trace_id", ColumnTypeString, "TraceId"},
+		{"span_id", ColumnTypeString, "SpanId"},
+		{"session_secure_id", ColumnTypeString, "SecureSessionId"},
+		{"level", ColumnTypeString, "toString(SeverityText)"},
+		{"source", ColumnTypeString, "toString(Source)"},
+		{"service_name", ColumnTypeString, "toString(ServiceName)"},
+		{"service_version", ColumnTypeString, "ServiceVersion"},
+		{"environment", ColumnTypeString, "Environment"},
+		{"message", ColumnTypeString, "Body"},
+		{"attributes", ColumnTypeString, "toJSONString(LogAttributes)"},
+	},
+	source:        clickhouse.LogsTable,
+	projectColumn: "ProjectId",
+	timeColumn:    "Timestamp",
+	sampleColumn:  "UUID",
+}
+
+// Tables are the tables that are exported every day. Logs are only exported when enabled.
+var Tables = []*Table{SessionsTable, ErrorGroupsTable, LogsTable}
+
+// VersionedName is the name of the warehouse table of the current schema version.
+func (t *Table) VersionedName() string {
+	return fmt.Sprintf("highlight_%s_v%d", t.Name, SchemaVersion)
+}
+
+// sampleBuckets is the resolution of the sample rate of sampled tables.
+const sampleBuckets = 10000
+
+// Select selects the rows of a project for a day, with the export date of the day. Sampled tables
+// export the share of their rows given by the sample rate.
+func (t *Table) Select(projectID int, day time.Time, sampleRate float64) *sqlbuilder.SelectBuilder {
+	start := day.UTC().Truncate(24 * time.Hour)
+	sb := sqlbuilder.NewSelectBuilder()
+	selects := make([]string, 0, len(t.Columns)+1)
+	for _, column := range t.Columns {
+		selects = append(selects, fmt.Sprintf("%s AS %s", column.Expr, column.Name))
+	}
+	selects = append(selects, fmt.Sprintf("toDate32(%s) AS %s", sb.Var(start.Format(time.DateOnly)), ExportDateColumn))
+
+	from := t.source
+	if t.final {
+		from += " FINAL"
+	}
+	sb.Select(strings.Join(selects, ", ")).
+		From(from).
+		Where(sb.Equal(t.projectColumn, projectID)).
+		Where(sb.GreaterEqualThan(t.timeColumn, start)).
+		Where(sb.LessThan(t.timeColumn, start.Add(24*time.Hour)))
+	if t.sampleColumn != "" && sampleRate < 1 {
+		sb.Where(fmt.Sprintf("cityHash64(%s) %% %d < %s", t.sampleColumn, sampleBuckets, sb.Var(int(sampleRate*sampleBuckets))))
+	}
+	return sb
+}
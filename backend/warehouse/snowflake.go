@@ -0,0 +1,231 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+// SnowflakeApiUrlFormat is formatted with the account identifier.
+var SnowflakeApiUrlFormat = "https://%s.snowflakecomputing.com"
+
+const (
+	snowflakeTokenExpiry = time.Hour
+	// snowflakeStatementTimeout is the timeout in seconds of a statement, after which it is canceled
+	snowflakeStatementTimeout = 600
+)
+
+var (
+	snowflakeAccount    = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	snowflakeIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+)
+
+var snowflakeTypes = map[ColumnType]string{
+	ColumnTypeString:    "VARCHAR",
+	ColumnTypeInt64:     "NUMBER(38, 0)",
+	ColumnTypeFloat64:   "FLOAT",
+	ColumnTypeBool:      "BOOLEAN",
+	ColumnTypeTimestamp: "TIMESTAMP_NTZ",
+	ColumnTypeDate:      "DATE",
+}
+
+// SnowflakeConfig configures a loader with a user that authenticates with a key pair. The stage
+// is an external stage whose url is the storage url of the export.
+type SnowflakeConfig struct {
+	Account    string
+	User       string
+	Role       string
+	Warehouse  string
+	Database   string
+	Schema     string
+	Stage      string
+	PrivateKey string
+}
+
+// SnowflakeLoader loads files from an external stage into the tables of a Snowflake schema with
+// the SQL API.
+type SnowflakeLoader struct {
+	config      SnowflakeConfig
+	apiUrl      string
+	privateKey  *rsa.PrivateKey
+	fingerprint string
+	httpClient  *http.Client
+}
+
+// NewSnowflakeLoader validates the configuration and parses the PEM encoded, unencrypted PKCS8
+// private key of the user.
+func NewSnowflakeLoader(config SnowflakeConfig) (*SnowflakeLoader, error) {
+	if !snowflakeAccount.MatchString(config.Account) || config.User == "" {
+		return nil, errors.New("Snowflake account and user are required")
+	}
+	for name, identifier := range map[string]string{"warehouse": config.Warehouse, "database": config.Database, "schema": config.Schema, "stage": config.Stage} {
+		if !snowflakeIdentifier.MatchString(identifier) {
+			return nil, errors.Errorf("invalid Snowflake %s %q", name, identifier)
+		}
+	}
+	if config.Role != "" && !snowflakeIdentifier.MatchString(config.Role) {
+		return nil, errors.Errorf("invalid Snowflake role %q", config.Role)
+	}
+
+	block, _ := pem.Decode([]byte(config.PrivateKey))
+	if block == nil {
+		return nil, errors.New("Snowflake private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Snowflake private key")
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("Snowflake private key is not an RSA key")
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Snowflake public key")
+	}
+	sum := sha256.Sum256(publicKey)
+
+	return &SnowflakeLoader{
+		config:      config,
+		apiUrl:      fmt.Sprintf(SnowflakeApiUrlFormat, config.Account),
+		privateKey:  privateKey,
+		fingerprint: "SHA256:" + base64.StdEncoding.EncodeToString(sum[:]),
+		httpClient:  &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// token is a key pair JWT of the user. The account of the claims is the account locator without
+// its region, ie. `xy12345` of `xy12345.us-east-1`.
+func (s *SnowflakeLoader) token(now time.Time) (string, error) {
+	account := strings.ToUpper(strings.Split(s.config.Account, ".")[0])
+	subject := account + "." + strings.ToUpper(s.config.User)
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    subject + "." + s.fingerprint,
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(snowflakeTokenExpiry)),
+	}).SignedString(s.privateKey)
+}
+
+type snowflakeResponse struct {
+	Code               string `json:"code"`
+	Message            string `json:"message"`
+	StatementHandle    string `json:"statementHandle"`
+	StatementStatusUrl string `json:"statementStatusUrl"`
+}
+
+// doRequest returns the response of a request, and whether the statement is still running.
+func (s *SnowflakeLoader) doRequest(ctx context.Context, method string, path string, data any) (*snowflakeResponse, bool, error) {
+	var body io.Reader
+	if data != nil {
+		d, err := json.Marshal(data)
+		if err != nil {
+			return nil, false, err
+		}
+		body = bytes.NewReader(d)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.apiUrl+path, body)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error creating api request to Snowflake")
+	}
+	token, err := s.token(time.Now())
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error signing Snowflake token")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Snowflake-Authorization-Token-Type", "KEYPAIR_JWT")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error getting response from Snowflake endpoint")
+	}
+	defer res.Body.Close()
+
+	r, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error reading response body from Snowflake endpoint")
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
+		return nil, false, errors.New("Snowflake API responded with error; status_code=" + res.Status + "; body=" + string(r))
+	}
+	var result snowflakeResponse
+	if err := json.Unmarshal(r, &result); err != nil {
+		return nil, false, errors.Wrap(err, "error unmarshaling Snowflake response")
+	}
+	return &result, res.StatusCode == http.StatusAccepted, nil
+}
+
+// execute runs statements and waits for them to complete.
+func (s *SnowflakeLoader) execute(ctx context.Context, statements ...string) error {
+	request := map[string]any{
+		"statement": strings.Join(statements, ";\n"),
+		"timeout":   snowflakeStatementTimeout,
+		"warehouse": s.config.Warehouse,
+		"database":  s.config.Database,
+		"schema":    s.config.Schema,
+		"parameters": map[string]string{
+			"MULTI_STATEMENT_COUNT": strconv.Itoa(len(statements)),
+		},
+	}
+	if s.config.Role != "" {
+		request["role"] = s.config.Role
+	}
+
+	result, running, err := s.doRequest(ctx, http.MethodPost, "/api/v2/statements", request)
+	if err != nil {
+		return err
+	}
+	for running {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobPollInterval):
+		}
+		if result, running, err = s.doRequest(ctx, http.MethodGet, result.StatementStatusUrl, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load creates the table when it does not exist, and replaces the rows of the day with the rows of
+// the file of the day in a transaction.
+func (s *SnowflakeLoader) Load(ctx context.Context, table *Table, day time.Time, storage *Storage) error {
+	columns := make([]string, 0, len(table.Columns)+1)
+	for _, column := range table.Columns {
+		columns = append(columns, column.Name+" "+snowflakeTypes[column.Type])
+	}
+	columns = append(columns, ExportDateColumn+" "+snowflakeTypes[ColumnTypeDate]+" NOT NULL")
+
+	name := table.VersionedName()
+	date := day.UTC().Format(time.DateOnly)
+	if err := s.execute(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) CLUSTER BY (%s)", name, strings.Join(columns, ", "), ExportDateColumn)); err != nil {
+		return errors.Wrapf(err, "error creating Snowflake table %s", name)
+	}
+	if err := s.execute(ctx,
+		"BEGIN",
+		fmt.Sprintf("DELETE FROM %s WHERE %s = '%s'", name, ExportDateColumn, date),
+		fmt.Sprintf("COPY INTO %s FROM @%s/%s FILE_FORMAT = (TYPE = PARQUET USE_LOGICAL_TYPE = TRUE) MATCH_BY_COLUMN_NAME = CASE_INSENSITIVE FORCE = TRUE", name, s.config.Stage, DayPath(table, day)),
+		"COMMIT",
+	); err != nil {
+		return errors.Wrapf(err, "error loading Snowflake table %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package warehouse
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnowflakeLoader_Load(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	var statements []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "KEYPAIR_JWT", r.Header.Get("X-Snowflake-Authorization-Token-Type"))
+		claims := jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "XY12345.HIGHLIGHT", claims.Subject)
+		assert.True(t, strings.HasPrefix(claims.Issuer, "XY12345.HIGHLIGHT.SHA256:"))
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/statements":
+			var request struct {
+				Statement  string            `json:"statement"`
+				Database   string            `json:"database"`
+				Parameters map[string]string `json:"parameters"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, "analytics", request.Database)
+			statements = append(statements, request.Statement)
+			if request.Parameters["MULTI_STATEMENT_COUNT"] == "4" {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"code":"333334","statementHandle":"handle-1","statementStatusUrl":"/api/v2/statements/handle-1"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":"090001","statementHandle":"handle-0"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/statements/handle-1":
+			_, _ = w.Write([]byte(`{"code":"090001","statementHandle":"handle-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	interval := jobPollInterval
+	jobPollInterval = time.Millisecond
+	defer func() { jobPollInterval = interval }()
+
+	loader, err := NewSnowflakeLoader(SnowflakeConfig{
+		Account:    "xy12345.us-east-1",
+		User:       "highlight",
+		Warehouse:  "compute_wh",
+		Database:   "analytics",
+		Schema:     "public",
+		Stage:      "highlight_stage",
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://xy12345.us-east-1.snowflakecomputing.com", loader.apiUrl)
+	loader.apiUrl = server.URL
+
+	storage, err := ParseStorage("s3://bucket", "", "")
+	assert.NoError(t, err)
+	assert.NoError(t, loader.Load(context.Background(), ErrorGroupsTable, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), storage))
+	assert.Len(t, statements, 2)
+	assert.Contains(t, statements[0], "CREATE TABLE IF NOT EXISTS highlight_error_groups_v1 (id NUMBER(38, 0), created_at TIMESTAMP_NTZ,")
+	assert.Contains(t, statements[1], "DELETE FROM highlight_error_groups_v1 WHERE export_date = '2024-01-02'")
+	assert.Contains(t, statements[1], "COPY INTO highlight_error_groups_v1 FROM @highlight_stage/v1/error_groups/date=2024-01-02/")
+}
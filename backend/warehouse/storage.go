@@ -0,0 +1,73 @@
+package warehouse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/pkg/errors"
+)
+
+// Storage is the bucket that the Parquet files of an export are written to, ie. `gs://bucket/prefix`
+// or `s3://bucket/prefix`. GCS buckets are written to with an HMAC key of a service account.
+type Storage struct {
+	Scheme          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// ParseStorage parses the url of a bucket and an optional prefix.
+func ParseStorage(storageURL string, accessKeyID string, secretAccessKey string) (*Storage, error) {
+	u, err := url.Parse(strings.TrimSpace(storageURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid storage url")
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return nil, errors.Errorf("unsupported storage url scheme %q, expected s3:// or gs://", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("storage url is missing a bucket")
+	}
+	return &Storage{
+		Scheme:          u.Scheme,
+		Bucket:          u.Host,
+		Prefix:          strings.Trim(u.Path, "/"),
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+	}, nil
+}
+
+// DayPath is the path of the files of a table for a day, relative to the prefix of the storage.
+func DayPath(table *Table, day time.Time) string {
+	return fmt.Sprintf("v%d/%s/date=%s/", SchemaVersion, table.Name, day.UTC().Format(time.DateOnly))
+}
+
+func (s *Storage) key(table *Table, day time.Time) string {
+	key := DayPath(table, day) + table.Name + ".parquet"
+	if s.Prefix != "" {
+		key = s.Prefix + "/" + key
+	}
+	return key
+}
+
+// URI is the `gs://` or `s3://` uri of the file of a table for a day.
+func (s *Storage) URI(table *Table, day time.Time) string {
+	return fmt.Sprintf("%s://%s/%s", s.Scheme, s.Bucket, s.key(table, day))
+}
+
+// Destination is the https url of the file of a table for a day, which ClickHouse writes to.
+func (s *Storage) Destination(table *Table, day time.Time) clickhouse.ObjectDestination {
+	host := fmt.Sprintf("https://%s.s3.amazonaws.com", s.Bucket)
+	if s.Scheme == "gs" {
+		host = fmt.Sprintf("https://storage.googleapis.com/%s", s.Bucket)
+	}
+	return clickhouse.ObjectDestination{
+		URL:             host + "/" + s.key(table, day),
+		AccessKeyID:     s.AccessKeyID,
+		SecretAccessKey: s.SecretAccessKey,
+	}
+}
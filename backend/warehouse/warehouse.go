@@ -0,0 +1,73 @@
+package warehouse
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+)
+
+// Destination is the warehouse that the exported files are loaded into.
+type Destination string
+
+const (
+	DestinationBigQuery  Destination = "bigquery"
+	DestinationSnowflake Destination = "snowflake"
+)
+
+func (d Destination) IsValid() bool {
+	return d == DestinationBigQuery || d == DestinationSnowflake
+}
+
+// Loader loads the file of a table for a day into a warehouse, replacing the rows previously
+// loaded for the day so that the export of a day can be retried.
+type Loader interface {
+	Load(ctx context.Context, table *Table, day time.Time, storage *Storage) error
+}
+
+// NewStorage returns the bucket of an export.
+func NewStorage(export *model.WarehouseExport) (*Storage, error) {
+	return ParseStorage(export.StorageURL, export.StorageAccessKeyID, export.StorageSecretAccessKey)
+}
+
+// NewLoader returns the loader of the destination of an export, validating its configuration.
+func NewLoader(ctx context.Context, export *model.WarehouseExport) (Loader, error) {
+	storage, err := NewStorage(export)
+	if err != nil {
+		return nil, err
+	}
+
+	switch Destination(export.Destination) {
+	case DestinationBigQuery:
+		// BigQuery only loads files from GCS
+		if storage.Scheme != "gs" {
+			return nil, errors.New("BigQuery exports require a gs:// storage url")
+		}
+		return NewBigQueryLoader(ctx, []byte(export.BigQueryCredentials), export.BigQueryProjectID, export.BigQueryDataset)
+	case DestinationSnowflake:
+		return NewSnowflakeLoader(SnowflakeConfig{
+			Account:    export.SnowflakeAccount,
+			User:       export.SnowflakeUser,
+			Role:       export.SnowflakeRole,
+			Warehouse:  export.SnowflakeWarehouse,
+			Database:   export.SnowflakeDatabase,
+			Schema:     export.SnowflakeSchema,
+			Stage:      export.SnowflakeStage,
+			PrivateKey: export.SnowflakePrivateKey,
+		})
+	}
+	return nil, errors.Errorf("invalid warehouse destination %q", export.Destination)
+}
+
+// ExportTables are the tables exported by an export.
+func ExportTables(export *model.WarehouseExport) []*Table {
+	var tables []*Table
+	for _, table := range Tables {
+		if table == LogsTable && !export.ExportLogs {
+			continue
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
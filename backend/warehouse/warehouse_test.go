@@ -0,0 +1,63 @@
+package warehouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable_Select(t *testing.T) {
+	day := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	sql, args := ErrorGroupsTable.Select(1, day, 1).BuildWithFlavor(sqlbuilder.ClickHouse)
+	assert.Equal(t, "SELECT toInt64(ID) AS id, CreatedAt AS created_at, UpdatedAt AS updated_at, Event AS event, toString(Type) AS type, toString(Status) AS status, ifNull(ErrorTagTitle, '') AS tag, toDate32(?) AS export_date FROM error_groups FINAL WHERE ProjectID = ? AND UpdatedAt >= ? AND UpdatedAt < ?", sql)
+	assert.Equal(t, []interface{}{"2024-01-02", 1, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)}, args)
+
+	sql, args = LogsTable.Select(1, day, 0.25).BuildWithFlavor(sqlbuilder.ClickHouse)
+	assert.Contains(t, sql, "FROM logs WHERE ProjectId = ?")
+	assert.Contains(t, sql, "AND cityHash64(UUID) % 10000 < ?")
+	assert.Equal(t, 2500, args[len(args)-1])
+
+	sql, _ = LogsTable.Select(1, day, 1).BuildWithFlavor(sqlbuilder.ClickHouse)
+	assert.NotContains(t, sql, "cityHash64")
+}
+
+func TestStorage(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	storage, err := ParseStorage("gs://bucket/highlight/exports/", "key", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "gs://bucket/highlight/exports/v1/sessions/date=2024-01-02/sessions.parquet", storage.URI(SessionsTable, day))
+	assert.Equal(t, "https://storage.googleapis.com/bucket/highlight/exports/v1/sessions/date=2024-01-02/sessions.parquet", storage.Destination(SessionsTable, day).URL)
+
+	storage, err = ParseStorage("s3://bucket", "key", "secret")
+	assert.NoError(t, err)
+	destination := storage.Destination(LogsTable, day)
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/v1/logs/date=2024-01-02/logs.parquet", destination.URL)
+	assert.Equal(t, "key", destination.AccessKeyID)
+	assert.Equal(t, "secret", destination.SecretAccessKey)
+
+	_, err = ParseStorage("https://bucket.s3.amazonaws.com", "", "")
+	assert.Error(t, err)
+	_, err = ParseStorage("s3:///prefix", "", "")
+	assert.Error(t, err)
+}
+
+func TestNewLoader(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewLoader(ctx, &model.WarehouseExport{Destination: "redshift", StorageURL: "s3://bucket"})
+	assert.Error(t, err)
+
+	_, err = NewLoader(ctx, &model.WarehouseExport{Destination: string(DestinationBigQuery), StorageURL: "s3://bucket"})
+	assert.ErrorContains(t, err, "gs://")
+
+	_, err = NewLoader(ctx, &model.WarehouseExport{Destination: string(DestinationSnowflake), StorageURL: "s3://bucket", SnowflakeAccount: "xy12345", SnowflakeUser: "highlight", SnowflakeWarehouse: "compute_wh", SnowflakeDatabase: "analytics", SnowflakeSchema: "public", SnowflakeStage: "highlight; DROP TABLE users"})
+	assert.ErrorContains(t, err, "invalid Snowflake stage")
+
+	assert.Equal(t, []*Table{SessionsTable, ErrorGroupsTable}, ExportTables(&model.WarehouseExport{}))
+	assert.Equal(t, Tables, ExportTables(&model.WarehouseExport{ExportLogs: true}))
+}
@@ -51,46 +51,77 @@ func (autoResolver *AutoResolver) AutoResolveStaleErrors(ctx context.Context) {
 			continue
 		}
 	}
+
+	rules, err := autoResolver.store.GetErrorWorkflowRulesWithAction(ctx, model.ErrorWorkflowRuleActionResolve)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to query error workflow resolve rules")
+		return
+	}
+
+	for _, rule := range rules {
+		if err := autoResolver.resolveStaleErrorsForRule(ctx, rule); err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{"project_id": rule.ProjectID, "rule_id": rule.ID}).Error(err)
+			continue
+		}
+	}
 }
 
 func (autoResolver *AutoResolver) resolveStaleErrorsForProject(ctx context.Context, project model.Project, interval int) error {
-	var errorGroups []model.ErrorGroup
+	errorGroups, err := autoResolver.findStaleErrorGroups(project.ID, interval)
+	if err != nil {
+		return err
+	}
 
-	db := autoResolver.db
+	for _, errorGroup := range errorGroups {
+		log.WithContext(ctx).WithFields(
+			log.Fields{
+				"project_id":     project.ID,
+				"error_group_id": errorGroup.ID,
+				"worker":         "autoresolver",
+			}).Info("Autoresolving error group")
 
-	subQuery := db.
-		Model(model.ErrorObject{}).
-		Select("error_group_id").
-		Where("error_objects.error_group_id = error_groups.id").
-		Where("created_at >= ?", time.Now().AddDate(0, 0, -interval)).
-		Where(model.ErrorObject{
-			ProjectID: project.ID,
+		_, err := autoResolver.store.UpdateErrorGroupStateBySystem(ctx, store.UpdateErrorGroupParams{
+			ID:    errorGroup.ID,
+			State: privateModel.ErrorStateResolved,
 		})
 
-	err := db.Debug().
-		Select("DISTINCT(error_groups.id), error_groups.project_id").
-		Where(model.ErrorGroup{
-			State:     privateModel.ErrorStateOpen,
-			ProjectID: project.ID,
-		}).
-		Where("NOT EXISTS (?)", subQuery).
-		Find(&errorGroups).Error
+		if err != nil {
+			return err
+		}
+	}
 
+	return nil
+}
+
+// resolveStaleErrorsForRule resolves the stale error groups matching a workflow rule,
+// recording the rule in the activity log of each error group.
+func (autoResolver *AutoResolver) resolveStaleErrorsForRule(ctx context.Context, rule *model.ErrorWorkflowRule) error {
+	if rule.StaleDays == nil || *rule.StaleDays <= 0 {
+		return nil
+	}
+
+	errorGroups, err := autoResolver.findStaleErrorGroups(rule.ProjectID, *rule.StaleDays)
 	if err != nil {
 		return err
 	}
 
 	for _, errorGroup := range errorGroups {
+		if !rule.MatchesEvent(errorGroup.Event) {
+			continue
+		}
+
 		log.WithContext(ctx).WithFields(
 			log.Fields{
-				"project_id":     project.ID,
+				"project_id":     rule.ProjectID,
 				"error_group_id": errorGroup.ID,
+				"rule_id":        rule.ID,
 				"worker":         "autoresolver",
-			}).Info("Autoresolving error group")
+			}).Info("Autoresolving error group by workflow rule")
 
 		_, err := autoResolver.store.UpdateErrorGroupStateBySystem(ctx, store.UpdateErrorGroupParams{
-			ID:    errorGroup.ID,
-			State: privateModel.ErrorStateResolved,
+			ID:     errorGroup.ID,
+			State:  privateModel.ErrorStateResolved,
+			RuleID: &rule.ID,
 		})
 
 		if err != nil {
@@ -100,3 +131,30 @@ func (autoResolver *AutoResolver) resolveStaleErrorsForProject(ctx context.Conte
 
 	return nil
 }
+
+// findStaleErrorGroups returns the open error groups of the project without errors in the past interval days.
+func (autoResolver *AutoResolver) findStaleErrorGroups(projectID int, interval int) ([]model.ErrorGroup, error) {
+	var errorGroups []model.ErrorGroup
+
+	db := autoResolver.db
+
+	subQuery := db.
+		Model(model.ErrorObject{}).
+		Select("error_group_id").
+		Where("error_objects.error_group_id = error_groups.id").
+		Where("created_at >= ?", time.Now().AddDate(0, 0, -interval)).
+		Where(model.ErrorObject{
+			ProjectID: projectID,
+		})
+
+	err := db.Debug().
+		Select("DISTINCT(error_groups.id), error_groups.project_id, error_groups.event").
+		Where(model.ErrorGroup{
+			State:     privateModel.ErrorStateOpen,
+			ProjectID: projectID,
+		}).
+		Where("NOT EXISTS (?)", subQuery).
+		Find(&errorGroups).Error
+
+	return errorGroups, err
+}
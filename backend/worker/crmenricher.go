@@ -0,0 +1,200 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/integrations"
+	"github.com/highlight-run/highlight/backend/integrations/crm"
+	"github.com/highlight-run/highlight/backend/integrations/hubspot"
+	"github.com/highlight-run/highlight/backend/integrations/salesforce"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	pubgraph "github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight-run/highlight/backend/redis"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	crmEnrichmentFreq = time.Minute
+	// crmIdentifyDelay is how long sessions are given to be identified before they are enriched,
+	// as sessions are scanned in the order they were created.
+	crmIdentifyDelay = 5 * time.Minute
+	// crmBackfill is how far back sessions are enriched when a project is connected to a CRM.
+	crmBackfill        = time.Hour
+	crmSessionsPerPage = 100
+	crmContactCacheTTL = 24 * time.Hour
+)
+
+var crmIntegrationTypes = []privateModel.IntegrationType{
+	privateModel.IntegrationTypeSalesforce,
+	privateModel.IntegrationTypeHubSpot,
+}
+
+// CRMEnricher stores the company, plan and ARR of the CRM contact of identified sessions as
+// session user properties, for the projects connected to Salesforce or HubSpot.
+type CRMEnricher struct {
+	publicResolver     *pubgraph.Resolver
+	integrationsClient *integrations.Client
+}
+
+func NewCRMEnricher(publicResolver *pubgraph.Resolver, integrationsClient *integrations.Client) *CRMEnricher {
+	return &CRMEnricher{
+		publicResolver:     publicResolver,
+		integrationsClient: integrationsClient,
+	}
+}
+
+func newCRMClient(integrationType privateModel.IntegrationType, accessToken string) crm.Client {
+	if integrationType == privateModel.IntegrationTypeSalesforce {
+		return salesforce.NewClient(accessToken)
+	}
+	return hubspot.NewClient(accessToken)
+}
+
+func (c *CRMEnricher) Watch(ctx context.Context) {
+	log.WithContext(ctx).Info("Starting to enrich sessions from CRMs")
+
+	for range time.NewTicker(crmEnrichmentFreq).C {
+		var mappings []*model.IntegrationProjectMapping
+		if err := c.publicResolver.DB.WithContext(ctx).
+			Where("integration_type IN ?", crmIntegrationTypes).
+			Find(&mappings).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("error querying crm integration project mappings")
+			continue
+		}
+
+		now := time.Now()
+		for _, mapping := range mappings {
+			if err := c.enrichProjectSessions(ctx, mapping, now); err != nil {
+				log.WithContext(ctx).WithError(err).
+					WithField("project_id", mapping.ProjectID).
+					WithField("integration_type", mapping.IntegrationType).
+					Error("error enriching sessions from crm")
+			}
+		}
+	}
+}
+
+func (c *CRMEnricher) enrichProjectSessions(ctx context.Context, mapping *model.IntegrationProjectMapping, now time.Time) error {
+	// only one worker may enrich the sessions of a project, as they share the cursor of the project
+	mutex, err := c.publicResolver.Redis.AcquireLock(ctx, fmt.Sprintf("crm-enrichment-%d-lock", mapping.ProjectID), crmEnrichmentFreq)
+	if err != nil {
+		return e.Wrap(err, "error acquiring crm enrichment lock")
+	}
+	defer func() {
+		if _, err := mutex.Unlock(); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to release crm enrichment lock")
+		}
+	}()
+
+	project, err := c.publicResolver.Store.GetProject(ctx, mapping.ProjectID)
+	if err != nil {
+		return e.Wrap(err, "error querying project")
+	}
+	workspace, err := c.publicResolver.Store.GetWorkspace(ctx, project.WorkspaceID)
+	if err != nil {
+		return e.Wrap(err, "error querying workspace")
+	}
+	accessToken, err := c.integrationsClient.GetWorkspaceAccessToken(ctx, workspace, mapping.IntegrationType)
+	if err != nil {
+		return err
+	}
+	// the workspace was disconnected from the crm
+	if accessToken == nil {
+		return nil
+	}
+	client := newCRMClient(mapping.IntegrationType, *accessToken)
+
+	cursor, err := c.publicResolver.Redis.GetCRMEnrichedSessionID(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	for {
+		query := c.publicResolver.DB.WithContext(ctx).
+			Where(&model.Session{ProjectID: project.ID, Identified: true}).
+			Where("id > ?", cursor).
+			Where("created_at < ?", now.Add(-crmIdentifyDelay)).
+			Where("email IS NOT NULL AND email <> ''")
+		if cursor == 0 {
+			query = query.Where("created_at >= ?", now.Add(-crmIdentifyDelay-crmBackfill))
+		}
+		var sessions []*model.Session
+		if err := query.Order("id ASC").Limit(crmSessionsPerPage).Find(&sessions).Error; err != nil {
+			return e.Wrap(err, "error querying identified sessions")
+		}
+
+		for _, session := range sessions {
+			if err := c.enrichSession(ctx, client, workspace, mapping.IntegrationType, session); err != nil {
+				return err
+			}
+			cursor = session.ID
+			if err := c.publicResolver.Redis.SetCRMEnrichedSessionID(ctx, project.ID, cursor); err != nil {
+				return err
+			}
+		}
+		if len(sessions) < crmSessionsPerPage {
+			return nil
+		}
+		if _, err := mutex.Extend(); err != nil {
+			return e.Wrap(err, "error extending crm enrichment lock")
+		}
+	}
+}
+
+func (c *CRMEnricher) enrichSession(ctx context.Context, client crm.Client, workspace *model.Workspace, integrationType privateModel.IntegrationType, session *model.Session) error {
+	email := *session.Email
+	// contacts are cached per workspace, as the crm is connected to the workspace. Emails without a
+	// contact are cached as an empty contact to avoid looking them up again.
+	contact, err := redis.CachedEval(ctx, c.publicResolver.Redis, fmt.Sprintf("crm-contact-%s-%d-%s", integrationType, workspace.ID, email), 10*time.Second, crmContactCacheTTL, func() (*crm.Contact, error) {
+		contact, err := client.FindContact(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if contact == nil {
+			return &crm.Contact{}, nil
+		}
+		return contact, nil
+	})
+	if err != nil {
+		return e.Wrapf(err, "error looking up %s contact", integrationType)
+	}
+
+	userProperties, err := session.GetUserProperties()
+	if err != nil {
+		userProperties = map[string]string{}
+	}
+	newProperties := mergeCRMProperties(userProperties, contact)
+	if len(newProperties) == 0 {
+		return nil
+	}
+
+	if err := session.SetUserProperties(userProperties); err != nil {
+		return err
+	}
+	if err := c.publicResolver.DB.WithContext(ctx).Model(&model.Session{Model: model.Model{ID: session.ID}}).
+		Update("user_properties", session.UserProperties).Error; err != nil {
+		return e.Wrap(err, "error updating session user properties")
+	}
+	if err := c.publicResolver.AppendProperties(ctx, session.ID, newProperties, pubgraph.PropertyType.USER); err != nil {
+		return e.Wrap(err, "error appending crm properties")
+	}
+	return c.publicResolver.DataSyncQueue.Submit(ctx, strconv.Itoa(session.ID), &kafkaqueue.Message{Type: kafkaqueue.SessionDataSync, SessionDataSync: &kafkaqueue.SessionDataSyncArgs{SessionID: session.ID}})
+}
+
+// mergeCRMProperties adds the properties of a contact to the user properties of a session and
+// returns the added properties. Properties set when identifying the session are kept.
+func mergeCRMProperties(userProperties map[string]string, contact *crm.Contact) map[string]string {
+	newProperties := map[string]string{}
+	for name, value := range contact.Properties() {
+		if userProperties[name] == "" {
+			userProperties[name] = value
+			newProperties[name] = value
+		}
+	}
+	return newProperties
+}
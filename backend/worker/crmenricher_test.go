@@ -0,0 +1,17 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/integrations/crm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeCRMProperties(t *testing.T) {
+	userProperties := map[string]string{"email": "chilly@highlight.io", "plan": "free"}
+	newProperties := mergeCRMProperties(userProperties, &crm.Contact{Company: "Acme", Plan: "enterprise", ARR: "500000"})
+	assert.Equal(t, map[string]string{"company": "Acme", "arr": "500000"}, newProperties)
+	assert.Equal(t, map[string]string{"email": "chilly@highlight.io", "plan": "free", "company": "Acme", "arr": "500000"}, userProperties)
+
+	assert.Empty(t, mergeCRMProperties(userProperties, &crm.Contact{}))
+}
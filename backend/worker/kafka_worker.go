@@ -17,6 +17,7 @@ import (
 	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
 	"github.com/highlight-run/highlight/backend/model"
 	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight/highlight/sdk/highlight-go"
 	hmetric "github.com/highlight/highlight/sdk/highlight-go/metric"
@@ -108,7 +109,11 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 	var syncErrorGroupIds []int
 	var syncErrorObjectIds []int
 	var logRows []*clickhouse.LogRow
+	var logDedupeKeys []string
 	var traceRows []*clickhouse.TraceRow
+	var checkInRows []*clickhouse.HeartbeatCheckInRow
+	var webVitalRows []*clickhouse.WebVitalRow
+	var metricRows []*clickhouse.MetricRow
 
 	var lastMsg *kafkaqueue.Message
 	var oldestMsg = time.Now()
@@ -128,12 +133,25 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 			logRow := lastMsg.PushLogs.LogRow
 			if logRow != nil {
 				logRows = append(logRows, logRow)
+				logDedupeKeys = append(logDedupeKeys, lastMsg.PushLogs.DedupeKey)
 			}
 		case kafkaqueue.PushTraces:
 			traceRow := lastMsg.PushTraces.TraceRow
 			if traceRow != nil {
 				traceRows = append(traceRows, traceRow)
 			}
+		case kafkaqueue.PushHeartbeatCheckIn:
+			checkInRow := lastMsg.PushHeartbeatCheckIn.CheckInRow
+			if checkInRow != nil {
+				checkInRows = append(checkInRows, checkInRow)
+			}
+		case kafkaqueue.PushWebVital:
+			webVitalRow := lastMsg.PushWebVital.WebVitalRow
+			if webVitalRow != nil {
+				webVitalRows = append(webVitalRows, webVitalRow)
+			}
+		case kafkaqueue.PushOTeLMetrics:
+			metricRows = append(metricRows, lastMsg.PushOTeLMetrics.MetricRows...)
 		default:
 			log.WithContext(ctx).Errorf("unknown message type received by batch worker %+v", lastMsg.Type)
 		}
@@ -151,7 +169,11 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 		}
 	}
 	if len(logRows) > 0 {
+		logRows, claimedKeys := k.dedupeLogs(wCtx, logRows, logDedupeKeys)
 		if err := k.flushLogs(wCtx, logRows); err != nil {
+			if releaseErr := k.Worker.Resolver.Redis.ReleaseDedupeKeys(wCtx, redis.DedupeKindLog, claimedKeys); releaseErr != nil {
+				log.WithContext(wCtx).WithError(releaseErr).Error("failed to release log dedupe keys")
+			}
 			workSpan.Finish(err)
 			return err
 		}
@@ -162,6 +184,24 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 			return err
 		}
 	}
+	if len(checkInRows) > 0 {
+		if err := k.flushHeartbeatCheckIns(wCtx, checkInRows); err != nil {
+			workSpan.Finish(err)
+			return err
+		}
+	}
+	if len(webVitalRows) > 0 {
+		if err := k.flushWebVitals(wCtx, webVitalRows); err != nil {
+			workSpan.Finish(err)
+			return err
+		}
+	}
+	if len(metricRows) > 0 {
+		if err := k.flushMetrics(wCtx, metricRows); err != nil {
+			workSpan.Finish(err)
+			return err
+		}
+	}
 	workSpan.Finish()
 
 	commitSpan, cCtx := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName(fmt.Sprintf("worker.kafka.%s.flush.commit", k.Name)))
@@ -224,6 +264,12 @@ func (k *KafkaBatchWorker) getQuotaExceededByProject(ctx context.Context, projec
 			log.WithContext(ctxW).Error(err)
 			return nil, err
 		}
+		// projects over 80% of their billing limit are soft capped, their data is ingested
+		// but the otel handlers report it as approaching the limit
+		if err := k.Worker.Resolver.Redis.SetBillingQuotaSoftCapped(ctxW, int(projectId), productType, withinBillingQuota && quotaPercent >= .8); err != nil {
+			log.WithContext(ctxW).Error(err)
+			return nil, err
+		}
 
 		// Send alert emails if above the relevant thresholds
 		go func() {
@@ -256,6 +302,38 @@ func (k *KafkaBatchWorker) getQuotaExceededByProject(ctx context.Context, projec
 	return quotaExceededByProject, nil
 }
 
+// dedupeLogs drops the log rows with a dedupe key that was already written, ie. by an earlier
+// message of a retried request, returning the remaining rows and the keys claimed for them.
+func (k *KafkaBatchWorker) dedupeLogs(ctx context.Context, logRows []*clickhouse.LogRow, dedupeKeys []string) ([]*clickhouse.LogRow, []string) {
+	keys := lo.Uniq(lo.Compact(dedupeKeys))
+	if len(keys) == 0 {
+		return logRows, nil
+	}
+	duplicates, err := k.Worker.Resolver.Redis.ClaimDedupeKeys(ctx, redis.DedupeKindLog, keys)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to dedupe logs")
+		return logRows, nil
+	}
+
+	var deduped []*clickhouse.LogRow
+	var claimed []string
+	seen := map[string]bool{}
+	for i, logRow := range logRows {
+		if key := dedupeKeys[i]; key != "" {
+			if duplicates[key] || seen[key] {
+				continue
+			}
+			seen[key] = true
+			claimed = append(claimed, key)
+		}
+		deduped = append(deduped, logRow)
+	}
+	if dropped := len(logRows) - len(deduped); dropped > 0 {
+		hmetric.Histogram(ctx, "worker.kafka.dedupedLogs", float64(dropped), nil, 1)
+	}
+	return deduped, claimed
+}
+
 func (k *KafkaBatchWorker) flushLogs(ctx context.Context, logRows []*clickhouse.LogRow) error {
 	timestampByProject := map[uint32]time.Time{}
 	projectIds := map[uint32]struct{}{}
@@ -294,6 +372,12 @@ func (k *KafkaBatchWorker) flushLogs(ctx context.Context, logRows []*clickhouse.
 				if err != nil {
 					log.WithContext(ctxX).Error(e.Wrap(err, "failed to create service"))
 				}
+
+				if logRow.ServiceVersion != "" {
+					if _, err := k.Worker.Resolver.Store.ObserveRelease(ctxX, project.ID, logRow.ServiceName, logRow.ServiceVersion, logRow.Timestamp); err != nil {
+						log.WithContext(ctxX).Error(e.Wrap(err, "failed to observe release"))
+					}
+				}
 			}
 
 			spanX.Finish()
@@ -350,7 +434,7 @@ func (k *KafkaBatchWorker) flushTraces(ctx context.Context, traceRows []*clickho
 		projectIds[trace.ProjectId] = struct{}{}
 	}
 
-	quotaExceededByProject, err := k.getQuotaExceededByProject(ctx, projectIds, model.PricingProductTypeLogs)
+	quotaExceededByProject, err := k.getQuotaExceededByProject(ctx, projectIds, model.PricingProductTypeTraces)
 	if err != nil {
 		return err
 	}
@@ -384,6 +468,38 @@ func (k *KafkaBatchWorker) flushTraces(ctx context.Context, traceRows []*clickho
 	return nil
 }
 
+func (k *KafkaBatchWorker) flushHeartbeatCheckIns(ctx context.Context, checkInRows []*clickhouse.HeartbeatCheckInRow) error {
+	span, ctxT := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName(fmt.Sprintf("worker.kafka.%s.flush.clickhouse.heartbeats", k.Name)))
+	span.SetAttribute("NumCheckInRows", len(checkInRows))
+	err := k.Worker.PublicResolver.Clickhouse.BatchWriteHeartbeatCheckIns(ctxT, checkInRows)
+	span.Finish(err)
+	if err != nil {
+		log.WithContext(ctxT).WithError(err).Error("failed to batch write heartbeat check ins to clickhouse")
+		return err
+	}
+
+	// only the latest check-in of each monitor is needed to evaluate missed schedules
+	latestByMonitor := map[uint32]*clickhouse.HeartbeatCheckInRow{}
+	for _, row := range checkInRows {
+		if latest, ok := latestByMonitor[row.MonitorId]; !ok || row.Timestamp.After(latest.Timestamp) {
+			latestByMonitor[row.MonitorId] = row
+		}
+	}
+	for monitorId, row := range latestByMonitor {
+		if err := k.Worker.PublicResolver.DB.WithContext(ctx).Model(&model.HeartbeatMonitor{}).
+			Where("id = ?", monitorId).
+			Where("last_check_in_at IS NULL OR last_check_in_at < ?", row.Timestamp).
+			Updates(map[string]interface{}{
+				"last_check_in_at":     row.Timestamp,
+				"last_check_in_status": row.Status,
+			}).Error; err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to update heartbeat monitor last check in")
+			return err
+		}
+	}
+	return nil
+}
+
 func (k *KafkaBatchWorker) flushDataSync(ctx context.Context, sessionIds []int, errorGroupIds []int, errorObjectIds []int) error {
 	sessionIdChunks := lo.Chunk(lo.Uniq(sessionIds), SessionsMaxRowsPostgres)
 	if len(sessionIdChunks) > 0 {
@@ -583,3 +699,27 @@ type KafkaBatchWorker struct {
 	lastFlush time.Time
 	messages  []*kafkaqueue.Message
 }
+
+func (k *KafkaBatchWorker) flushWebVitals(ctx context.Context, webVitalRows []*clickhouse.WebVitalRow) error {
+	span, ctxT := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName(fmt.Sprintf("worker.kafka.%s.flush.clickhouse.webVitals", k.Name)))
+	span.SetAttribute("NumWebVitalRows", len(webVitalRows))
+	err := k.Worker.PublicResolver.Clickhouse.BatchWriteWebVitals(ctxT, webVitalRows)
+	span.Finish(err)
+	if err != nil {
+		log.WithContext(ctxT).WithError(err).Error("failed to batch write web vitals to clickhouse")
+		return err
+	}
+	return nil
+}
+
+func (k *KafkaBatchWorker) flushMetrics(ctx context.Context, metricRows []*clickhouse.MetricRow) error {
+	span, ctxT := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName(fmt.Sprintf("worker.kafka.%s.flush.clickhouse.metrics", k.Name)))
+	span.SetAttribute("NumMetricRows", len(metricRows))
+	err := k.Worker.PublicResolver.Clickhouse.BatchWriteMetricRows(ctxT, metricRows)
+	span.Finish(err)
+	if err != nil {
+		log.WithContext(ctxT).WithError(err).Error("failed to batch write metrics to clickhouse")
+		return err
+	}
+	return nil
+}
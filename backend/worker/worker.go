@@ -23,8 +23,18 @@ import (
 	"github.com/golang/snappy"
 	"github.com/highlight-run/highlight/backend/alerts"
 	parse "github.com/highlight-run/highlight/backend/event-parse"
+	analytics_export "github.com/highlight-run/highlight/backend/jobs/analytics-export"
+	"github.com/highlight-run/highlight/backend/jobs/digests"
+	error_baselines "github.com/highlight-run/highlight/backend/jobs/error-baselines"
+	"github.com/highlight-run/highlight/backend/jobs/escalations"
+	heartbeat_monitor "github.com/highlight-run/highlight/backend/jobs/heartbeat-monitor"
 	log_alerts "github.com/highlight-run/highlight/backend/jobs/log-alerts"
+	log_forwarding "github.com/highlight-run/highlight/backend/jobs/log-forwarding"
 	metric_monitor "github.com/highlight-run/highlight/backend/jobs/metric-monitor"
+	service_graph "github.com/highlight-run/highlight/backend/jobs/service-graph"
+	trace_alerts "github.com/highlight-run/highlight/backend/jobs/trace-alerts"
+	uptime_monitor "github.com/highlight-run/highlight/backend/jobs/uptime-monitor"
+	warehouse_export "github.com/highlight-run/highlight/backend/jobs/warehouse-export"
 	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
 	journey_handlers "github.com/highlight-run/highlight/backend/lambda-functions/journeys/handlers"
 	"github.com/highlight-run/highlight/backend/model"
@@ -35,8 +45,10 @@ import (
 	backend "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	pubgraph "github.com/highlight-run/highlight/backend/public-graph/graph"
 	publicModel "github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
 	"github.com/highlight-run/highlight/backend/stacktraces"
 	"github.com/highlight-run/highlight/backend/storage"
+	"github.com/highlight-run/highlight/backend/symbolication"
 	tempalerts "github.com/highlight-run/highlight/backend/temp-alerts"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight-run/highlight/backend/zapier"
@@ -71,6 +83,7 @@ type Worker struct {
 	Resolver       *mgraph.Resolver
 	PublicResolver *pubgraph.Resolver
 	StorageClient  storage.Client
+	Symbolicator   *symbolication.Symbolicator
 }
 
 func (w *Worker) pushToObjectStorage(ctx context.Context, s *model.Session, payloadManager *payload.PayloadManager) error {
@@ -312,6 +325,17 @@ func (w *Worker) getSessionID(ctx context.Context, sessionSecureID string) (id i
 	return
 }
 
+// releaseDedupeKeyOnPanic releases the dedupe key of a message that failed to process, so that the
+// redelivered message is not dropped as a duplicate.
+func (w *Worker) releaseDedupeKeyOnPanic(ctx context.Context, kind redis.DedupeKind, key string) {
+	if rec := recover(); rec != nil {
+		if err := w.Resolver.Redis.ReleaseDedupeKeys(ctx, kind, []string{key}); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("failed to release %s dedupe key", kind)
+		}
+		panic(rec)
+	}
+}
+
 func (w *Worker) processPublicWorkerMessage(ctx context.Context, task *kafkaqueue.Message) error {
 	switch task.Type {
 	case kafkaqueue.PushPayload:
@@ -398,7 +422,31 @@ func (w *Worker) processPublicWorkerMessage(ctx context.Context, task *kafkaqueu
 		if task.PushBackendPayload == nil {
 			break
 		}
+		if key := task.PushBackendPayload.DedupeKey; key != "" {
+			duplicates, err := w.Resolver.Redis.ClaimDedupeKeys(ctx, redis.DedupeKindError, []string{key})
+			if err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to dedupe backend payload")
+			} else if duplicates[key] {
+				break
+			}
+			defer w.releaseDedupeKeyOnPanic(ctx, redis.DedupeKindError, key)
+		}
 		w.PublicResolver.ProcessBackendPayloadImpl(ctx, task.PushBackendPayload.SessionSecureID, task.PushBackendPayload.ProjectVerboseID, task.PushBackendPayload.Errors)
+	case kafkaqueue.PushMobileCrash:
+		if task.PushMobileCrash == nil || task.PushMobileCrash.Report == nil {
+			break
+		}
+		projectID, err := model.FromVerboseID(task.PushMobileCrash.ProjectVerboseID)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("type", task.Type).Error("invalid project for mobile crash")
+			break
+		}
+		errorObject, err := w.Symbolicator.ToBackendErrorObject(ctx, projectID, task.PushMobileCrash.Report)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).WithField("type", task.Type).Error("failed to symbolicate mobile crash")
+			return err
+		}
+		w.PublicResolver.ProcessBackendPayloadImpl(ctx, errorObject.SessionSecureID, &task.PushMobileCrash.ProjectVerboseID, []*publicModel.BackendErrorObjectInput{errorObject})
 	case kafkaqueue.PushMetrics:
 		if task.PushMetrics == nil {
 			break
@@ -415,6 +463,36 @@ func (w *Worker) processPublicWorkerMessage(ctx context.Context, task *kafkaqueu
 			log.WithContext(ctx).WithError(err).WithField("type", task.Type).Error("failed to process task")
 			return err
 		}
+	case kafkaqueue.PushSessionNetworkResources:
+		if task.PushSessionNetworkResources == nil {
+			break
+		}
+		if err := w.PublicResolver.PushSessionNetworkResourcesImpl(ctx, task.PushSessionNetworkResources.SessionSecureID, task.PushSessionNetworkResources.Resources); err != nil {
+			log.WithContext(ctx).WithError(err).WithField("type", task.Type).Error("failed to process task")
+			return err
+		}
+	case kafkaqueue.PushSegmentEvent:
+		if task.PushSegmentEvent == nil {
+			break
+		}
+		if key := task.PushSegmentEvent.DedupeKey; key != "" {
+			duplicates, err := w.Resolver.Redis.ClaimDedupeKeys(ctx, redis.DedupeKindSegmentEvent, []string{key})
+			if err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to dedupe segment event")
+			} else if duplicates[key] {
+				break
+			}
+		}
+		if err := w.PublicResolver.PushSegmentEventImpl(ctx, task.PushSegmentEvent); err != nil {
+			log.WithContext(ctx).WithError(err).WithField("type", task.Type).Error("failed to process task")
+			// release the key so that the retried message is processed
+			if key := task.PushSegmentEvent.DedupeKey; key != "" {
+				if err := w.Resolver.Redis.ReleaseDedupeKeys(ctx, redis.DedupeKindSegmentEvent, []string{key}); err != nil {
+					log.WithContext(ctx).WithError(err).Error("failed to release segment event dedupe key")
+				}
+			}
+			return err
+		}
 	case kafkaqueue.HealthCheck:
 	default:
 		log.WithContext(ctx).Errorf("Unknown task type %+v", task.Type)
@@ -554,6 +632,33 @@ func (w *Worker) DeleteCompletedSessions(ctx context.Context) {
 	}
 }
 
+// errorPayloadDeleteBatchSize is the number of error payloads deleted per statement, so that a large
+// backlog of expired payloads is not deleted in one long transaction.
+const errorPayloadDeleteBatchSize = 10_000
+
+// DeleteExpiredErrorPayloads deletes the payloads of errors older than the payload retention of their project.
+func (w *Worker) DeleteExpiredErrorPayloads(ctx context.Context) {
+	projectFilterSettings, err := w.PublicResolver.Store.FindProjectsWithErrorPayloadRetention(ctx)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to query error payload retention settings")
+		return
+	}
+
+	for _, settings := range projectFilterSettings {
+		before := time.Now().AddDate(0, 0, -*settings.ErrorPayloadRetentionDays)
+		for {
+			deleted, err := w.PublicResolver.Store.DeleteExpiredErrorPayloads(ctx, settings.ProjectID, before, errorPayloadDeleteBatchSize)
+			if err != nil {
+				log.WithContext(ctx).WithField("project_id", settings.ProjectID).WithError(err).Error("failed to delete expired error payloads")
+				break
+			}
+			if deleted < errorPayloadDeleteBatchSize {
+				break
+			}
+		}
+	}
+}
+
 // Autoresolves error groups that have not had any recent instances
 func (w *Worker) AutoResolveStaleErrors(ctx context.Context) {
 	autoResolver := NewAutoResolver(w.PublicResolver.Store, w.PublicResolver.DB)
@@ -992,7 +1097,7 @@ func (w *Worker) processSession(ctx context.Context, s *model.Session) error {
 				UserIdentifier: s.Identifier, UserObject: s.UserObject, RageClicksCount: &count64,
 			}
 
-			if err := w.Resolver.RH.Notify(s.ProjectID, fmt.Sprintf("SessionAlert_%d", sessionAlert.ID), hookPayload); err != nil {
+			if err := zapier.NotifyAlert(ctx, w.Resolver.RH, zapier.SessionAlert, sessionAlert.ID, &sessionAlert.Alert, hookPayload); err != nil {
 				log.WithContext(ctx).Error(e.Wrapf(err, "couldn't notify zapier on session alert (id: %d)", sessionAlert.ID))
 			}
 			tempalerts.SendSessionAlerts(ctx, w.Resolver.DB, w.Resolver.MailClient, w.Resolver.LambdaClient, sessionAlert, &slackAlertPayload)
@@ -1161,13 +1266,57 @@ func (w *Worker) MigrateDB(ctx context.Context) {
 }
 
 func (w *Worker) StartMetricMonitorWatcher(ctx context.Context) {
-	metric_monitor.WatchMetricMonitors(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.MailClient, w.Resolver.RH)
+	metric_monitor.WatchMetricMonitors(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.MailClient, w.Resolver.RH, w.Resolver.Redis)
 }
 
 func (w *Worker) StartLogAlertWatcher(ctx context.Context) {
 	log_alerts.WatchLogAlerts(ctx, w.Resolver.DB, w.Resolver.MailClient, w.Resolver.RH, w.Resolver.Redis, w.Resolver.ClickhouseClient, w.Resolver.LambdaClient)
 }
 
+func (w *Worker) StartTraceAlertWatcher(ctx context.Context) {
+	trace_alerts.WatchTraceAlerts(ctx, w.Resolver.DB, w.Resolver.Redis, w.Resolver.ClickhouseClient)
+}
+
+func (w *Worker) StartErrorBaselineWatcher(ctx context.Context) {
+	error_baselines.WatchErrorBaselines(ctx, w.Resolver.DB)
+}
+
+func (w *Worker) StartEscalationWatcher(ctx context.Context) {
+	escalations.WatchEscalations(ctx, w.Resolver.DB, w.Resolver.MailClient)
+}
+
+func (w *Worker) StartDigestWatcher(ctx context.Context) {
+	digests.WatchDigests(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.MailClient)
+}
+
+func (w *Worker) StartUptimeMonitorWatcher(ctx context.Context) {
+	uptime_monitor.WatchUptimeMonitors(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.Redis)
+}
+
+func (w *Worker) StartHeartbeatMonitorWatcher(ctx context.Context) {
+	heartbeat_monitor.WatchHeartbeatMonitors(ctx, w.Resolver.DB, w.Resolver.Redis)
+}
+
+func (w *Worker) StartServiceGraphWatcher(ctx context.Context) {
+	service_graph.WatchServiceGraph(ctx, w.Resolver.ClickhouseClient, w.Resolver.Redis)
+}
+
+func (w *Worker) StartLogForwarder(ctx context.Context) {
+	log_forwarding.WatchLogForwarders(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.Redis)
+}
+
+func (w *Worker) StartCRMEnrichment(ctx context.Context) {
+	NewCRMEnricher(w.PublicResolver, w.Resolver.IntegrationsClient).Watch(ctx)
+}
+
+func (w *Worker) StartAnalyticsExport(ctx context.Context) {
+	analytics_export.WatchAnalyticsExports(ctx, w.Resolver.DB, w.Resolver.Redis)
+}
+
+func (w *Worker) StartWarehouseExport(ctx context.Context) {
+	warehouse_export.WatchWarehouseExports(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.Redis)
+}
+
 func (w *Worker) RefreshMaterializedViews(ctx context.Context) {
 	span, _ := util.StartSpanFromContext(ctx, "worker.refreshMaterializedViews",
 		util.ResourceName("worker.refreshMaterializedViews"))
@@ -1333,7 +1482,7 @@ func (w *Worker) BackfillStackFrames(ctx context.Context) {
 			}
 
 			version := w.PublicResolver.GetErrorAppVersion(ctx, modelObj)
-			mappedStackTrace, err := stacktraces.EnhanceStackTrace(ctx, inputs, modelObj.ProjectID, version, w.Resolver.StorageClient)
+			mappedStackTrace, err := stacktraces.EnhanceStackTrace(ctx, inputs, modelObj.ProjectID, version, w.Resolver.StorageClient, w.Resolver.Store)
 			if err != nil {
 				log.WithContext(ctx).Errorf("error getting stack trace string: %+v", err)
 				return
@@ -1367,6 +1516,28 @@ func (w *Worker) GetHandler(ctx context.Context, handlerFlag string) func(ctx co
 		return w.StartMetricMonitorWatcher
 	case "log-alerts":
 		return w.StartLogAlertWatcher
+	case "trace-alerts":
+		return w.StartTraceAlertWatcher
+	case "error-baselines":
+		return w.StartErrorBaselineWatcher
+	case "escalations":
+		return w.StartEscalationWatcher
+	case "digests":
+		return w.StartDigestWatcher
+	case "uptime-monitors":
+		return w.StartUptimeMonitorWatcher
+	case "heartbeat-monitors":
+		return w.StartHeartbeatMonitorWatcher
+	case "service-graph":
+		return w.StartServiceGraphWatcher
+	case "log-forwarding":
+		return w.StartLogForwarder
+	case "crm-enrichment":
+		return w.StartCRMEnrichment
+	case "analytics-export":
+		return w.StartAnalyticsExport
+	case "warehouse-export":
+		return w.StartWarehouseExport
 	case "backfill-stack-frames":
 		return w.BackfillStackFrames
 	case "refresh-materialized-views":
@@ -1383,6 +1554,8 @@ func (w *Worker) GetHandler(ctx context.Context, handlerFlag string) func(ctx co
 		return w.GetPublicWorker(kafkaqueue.TopicTypeTraces)
 	case "auto-resolve-stale-errors":
 		return w.AutoResolveStaleErrors
+	case "delete-expired-error-payloads":
+		return w.DeleteExpiredErrorPayloads
 	default:
 		log.WithContext(ctx).Fatalf("unrecognized worker-handler [%s]", handlerFlag)
 		return nil
@@ -11,6 +11,9 @@ import (
 	"github.com/go-test/deep"
 	parse "github.com/highlight-run/highlight/backend/event-parse"
 	"github.com/highlight-run/highlight/backend/model"
+	mgraph "github.com/highlight-run/highlight/backend/private-graph/graph"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCalculateSessionLength(t *testing.T) {
@@ -814,3 +817,28 @@ func TestFullSnapshotProcessed(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseDedupeKeyOnPanic(t *testing.T) {
+	ctx := context.Background()
+	w := &Worker{Resolver: &mgraph.Resolver{Redis: redis.NewClient()}}
+	defer func() {
+		assert.NoError(t, w.Resolver.Redis.ReleaseDedupeKeys(ctx, redis.DedupeKindError, []string{"test-processed", "test-failed"}))
+	}()
+
+	process := func(key string, fail bool) {
+		duplicates, err := w.Resolver.Redis.ClaimDedupeKeys(ctx, redis.DedupeKindError, []string{key})
+		assert.NoError(t, err)
+		assert.Empty(t, duplicates)
+		defer w.releaseDedupeKeyOnPanic(ctx, redis.DedupeKindError, key)
+		if fail {
+			panic("failed to process")
+		}
+	}
+	process("test-processed", false)
+	assert.Panics(t, func() { process("test-failed", true) })
+
+	// only the key of the failed message can be claimed again
+	duplicates, err := w.Resolver.Redis.ClaimDedupeKeys(ctx, redis.DedupeKindError, []string{"test-processed", "test-failed"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"test-processed": true}, duplicates)
+}
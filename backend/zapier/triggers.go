@@ -0,0 +1,221 @@
+package zapier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/go-resthooks"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// The events of the REST hook triggers that aren't scoped to a single alert. Zapier, Make and other
+// automation tools subscribe to them through the `/hooks` endpoints, and poll the `/triggers`
+// endpoints for the sample data and as a fallback.
+const (
+	NewErrorGroupEvent        = "new_error_group"
+	NewIdentifiedSessionEvent = "new_identified_session"
+	AlertFiredEvent           = "alert_fired"
+)
+
+// AlertType is the type of alert of an alert fired trigger, and the prefix of the REST hook event
+// of each alert, ie. `ErrorAlert_1`.
+type AlertType string
+
+const (
+	ErrorAlert   AlertType = "ErrorAlert"
+	SessionAlert AlertType = "SessionAlert"
+	LogAlert     AlertType = "LogAlert"
+)
+
+// pollingLimit is the number of items returned by the polling triggers, which Zapier deduplicates by id.
+const pollingLimit = 50
+
+type ErrorGroupTrigger struct {
+	ID          int       `json:"id"`
+	SecureID    string    `json:"secure_id"`
+	Event       string    `json:"event"`
+	Type        string    `json:"type"`
+	State       string    `json:"state"`
+	ServiceName string    `json:"service_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	URL         string    `json:"url"`
+}
+
+type IdentifiedSessionTrigger struct {
+	ID         int       `json:"id"`
+	SecureID   string    `json:"secure_id"`
+	Identifier string    `json:"identifier"`
+	Email      *string   `json:"email"`
+	City       string    `json:"city"`
+	Country    string    `json:"country"`
+	CreatedAt  time.Time `json:"created_at"`
+	URL        string    `json:"url"`
+}
+
+type AlertFiredTrigger struct {
+	ID        string       `json:"id"`
+	AlertID   int          `json:"alert_id"`
+	AlertType AlertType    `json:"alert_type"`
+	AlertName string       `json:"alert_name"`
+	FiredAt   time.Time    `json:"fired_at"`
+	URL       string       `json:"url"`
+	Details   *HookPayload `json:"details,omitempty"`
+}
+
+func NewErrorGroupTrigger(errorGroup *model.ErrorGroup) ErrorGroupTrigger {
+	return ErrorGroupTrigger{
+		ID:          errorGroup.ID,
+		SecureID:    errorGroup.SecureID,
+		Event:       errorGroup.Event,
+		Type:        errorGroup.Type,
+		State:       string(errorGroup.State),
+		ServiceName: errorGroup.ServiceName,
+		CreatedAt:   errorGroup.CreatedAt,
+		URL:         fmt.Sprintf("%s/%d/errors/%s", os.Getenv("FRONTEND_URI"), errorGroup.ProjectID, errorGroup.SecureID),
+	}
+}
+
+func NewIdentifiedSessionTrigger(session *model.Session) IdentifiedSessionTrigger {
+	return IdentifiedSessionTrigger{
+		ID:         session.ID,
+		SecureID:   session.SecureID,
+		Identifier: session.Identifier,
+		Email:      session.Email,
+		City:       session.City,
+		Country:    session.Country,
+		CreatedAt:  session.CreatedAt,
+		URL:        fmt.Sprintf("%s/%d/sessions/%s", os.Getenv("FRONTEND_URI"), session.ProjectID, session.SecureID),
+	}
+}
+
+func alertURL(projectID int) string {
+	return fmt.Sprintf("%s/%d/alerts", os.Getenv("FRONTEND_URI"), projectID)
+}
+
+// Notify notifies the REST hook subscription of a trigger. Most projects don't subscribe to every
+// trigger, so a missing subscription is not logged as an error.
+func Notify(ctx context.Context, rh *resthooks.Resthook, projectID int, event string, data interface{}) {
+	if rh == nil {
+		return
+	}
+	if err := rh.Notify(projectID, event, data); err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).WithField("event", event).Debug("REST hook trigger not notified")
+	}
+}
+
+// NotifyAlert notifies the REST hook subscription of the alert, and the alert fired trigger of the project.
+func NotifyAlert(ctx context.Context, rh *resthooks.Resthook, alertType AlertType, alertID int, alert *model.Alert, payload HookPayload) error {
+	if rh == nil {
+		return nil
+	}
+	firedAt := time.Now()
+	Notify(ctx, rh, alert.ProjectID, AlertFiredEvent, AlertFiredTrigger{
+		ID:        fmt.Sprintf("%s_%d_%d", alertType, alertID, firedAt.UnixNano()),
+		AlertID:   alertID,
+		AlertType: alertType,
+		AlertName: alert.Name,
+		FiredAt:   firedAt,
+		URL:       alertURL(alert.ProjectID),
+		Details:   &payload,
+	})
+	return rh.Notify(alert.ProjectID, fmt.Sprintf("%s_%d", alertType, alertID), payload)
+}
+
+func writeTriggerResponse(ctx context.Context, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.WithContext(ctx).Error("Error sending json response: ", err)
+	}
+}
+
+// createTriggerRoutes adds the polling triggers, which return the most recent items first.
+func createTriggerRoutes(r chi.Router, db *gorm.DB) {
+	r.Get("/new-error-groups", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		project := ctx.Value(model.ContextKeys.ZapierProject).(*model.Project)
+
+		var errorGroups []*model.ErrorGroup
+		if err := db.WithContext(ctx).Select("id", "secure_id", "project_id", "event", "type", "state", "service_name", "created_at").
+			Where(&model.ErrorGroup{ProjectID: project.ID}).Order("id DESC").Limit(pollingLimit).Find(&errorGroups).Error; err != nil {
+			log.WithContext(ctx).Error("Error querying error groups: ", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		triggers := []ErrorGroupTrigger{}
+		for _, errorGroup := range errorGroups {
+			triggers = append(triggers, NewErrorGroupTrigger(errorGroup))
+		}
+		writeTriggerResponse(ctx, w, triggers)
+	})
+
+	r.Get("/new-identified-sessions", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		project := ctx.Value(model.ContextKeys.ZapierProject).(*model.Project)
+
+		var sessions []*model.Session
+		if err := db.WithContext(ctx).Select("id", "secure_id", "project_id", "identifier", "email", "city", "country", "created_at").
+			Where(&model.Session{ProjectID: project.ID, Identified: true}).Order("id DESC").Limit(pollingLimit).Find(&sessions).Error; err != nil {
+			log.WithContext(ctx).Error("Error querying identified sessions: ", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		triggers := []IdentifiedSessionTrigger{}
+		for _, session := range sessions {
+			triggers = append(triggers, NewIdentifiedSessionTrigger(session))
+		}
+		writeTriggerResponse(ctx, w, triggers)
+	})
+
+	r.Get("/alerts-fired", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		project := ctx.Value(model.ContextKeys.ZapierProject).(*model.Project)
+
+		var events []struct {
+			EventID   int64
+			AlertID   int
+			AlertType AlertType
+			AlertName string
+			SentAt    time.Time
+		}
+		if err := db.WithContext(ctx).Raw(`
+			(SELECT ev.id AS event_id, a.id AS alert_id, 'ErrorAlert' AS alert_type, a.name AS alert_name, ev.sent_at
+				FROM error_alert_events ev INNER JOIN error_alerts a ON a.id = ev.error_alert_id
+				WHERE a.project_id = @project_id ORDER BY ev.id DESC LIMIT @limit)
+			UNION ALL (SELECT ev.id, a.id, 'SessionAlert', a.name, ev.sent_at
+				FROM session_alert_events ev INNER JOIN session_alerts a ON a.id = ev.session_alert_id
+				WHERE a.project_id = @project_id ORDER BY ev.id DESC LIMIT @limit)
+			UNION ALL (SELECT ev.id, a.id, 'LogAlert', a.name, ev.sent_at
+				FROM log_alert_events ev INNER JOIN log_alerts a ON a.id = ev.log_alert_id
+				WHERE a.project_id = @project_id ORDER BY ev.id DESC LIMIT @limit)
+			ORDER BY sent_at DESC
+			LIMIT @limit
+		`, map[string]interface{}{"project_id": project.ID, "limit": pollingLimit}).Scan(&events).Error; err != nil {
+			log.WithContext(ctx).Error("Error querying alert events: ", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		triggers := []AlertFiredTrigger{}
+		for _, event := range events {
+			triggers = append(triggers, AlertFiredTrigger{
+				ID:        fmt.Sprintf("%s_%d", event.AlertType, event.EventID),
+				AlertID:   event.AlertID,
+				AlertType: event.AlertType,
+				AlertName: event.AlertName,
+				FiredAt:   event.SentAt,
+				URL:       alertURL(project.ID),
+			})
+		}
+		writeTriggerResponse(ctx, w, triggers)
+	})
+}
@@ -0,0 +1,212 @@
+package zapier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/highlight-run/go-resthooks"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/openlyinc/pointy"
+	e "github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var DB *gorm.DB
+
+func TestMain(m *testing.M) {
+	dbName := "highlight_testing_db"
+	testLogger := log.WithContext(context.TODO()).WithFields(log.Fields{"DB_HOST": os.Getenv("PSQL_HOST"), "DB_NAME": dbName})
+	var err error
+	DB, err = util.CreateAndMigrateTestDB(dbName)
+	if err != nil {
+		testLogger.Error(e.Wrap(err, "error creating testdb"))
+	}
+	signingToken = "zapier-test-signing-key"
+	code := m.Run()
+	os.Exit(code)
+}
+
+// newZapierProject creates a project with an initialized Zapier access token.
+func newZapierProject(t *testing.T) (*model.Project, string) {
+	project := model.Project{Name: pointy.String("zapier")}
+	require.NoError(t, DB.Create(&project).Error)
+
+	token, err := GenerateZapierAccessToken(project.ID)
+	require.NoError(t, err)
+	parsedToken, err := ParseZapierAccessToken(token)
+	require.NoError(t, err)
+	require.NoError(t, DB.Model(&project).Update("ZapierAccessToken", parsedToken.Magic).Error)
+	return &project, token
+}
+
+func newZapierRouter() (chi.Router, *resthooks.Resthook) {
+	rh := resthooks.NewResthook(&ZapierResthookStore{DB: DB})
+	r := chi.NewRouter()
+	CreateZapierRoutes(r, DB, &ZapierResthookStore{DB: DB}, &rh)
+	return r, &rh
+}
+
+func getTrigger(r chi.Router, path string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestNewErrorGroupTrigger(t *testing.T) {
+	t.Setenv("FRONTEND_URI", "https://app.highlight.io")
+	trigger := NewErrorGroupTrigger(&model.ErrorGroup{
+		Model:     model.Model{ID: 5},
+		SecureID:  "abc",
+		ProjectID: 1,
+		Event:     "TypeError",
+		Type:      "Backend",
+		State:     "OPEN",
+	})
+	assert.Equal(t, 5, trigger.ID)
+	assert.Equal(t, "OPEN", trigger.State)
+	assert.Equal(t, "https://app.highlight.io/1/errors/abc", trigger.URL)
+}
+
+func TestNewIdentifiedSessionTrigger(t *testing.T) {
+	t.Setenv("FRONTEND_URI", "https://app.highlight.io")
+	trigger := NewIdentifiedSessionTrigger(&model.Session{
+		Model:      model.Model{ID: 7},
+		SecureID:   "def",
+		ProjectID:  1,
+		Identifier: "vadim@highlight.io",
+		Email:      pointy.String("vadim@highlight.io"),
+	})
+	assert.Equal(t, 7, trigger.ID)
+	assert.Equal(t, "vadim@highlight.io", *trigger.Email)
+	assert.Equal(t, "https://app.highlight.io/1/sessions/def", trigger.URL)
+}
+
+func TestNotify(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		ctx := context.Background()
+		project, _ := newZapierProject(t)
+
+		// notifying without REST hooks or without a subscription is a no-op
+		Notify(ctx, nil, project.ID, NewErrorGroupEvent, ErrorGroupTrigger{})
+		assert.NoError(t, NotifyAlert(ctx, nil, ErrorAlert, 1, &model.Alert{ProjectID: project.ID}, HookPayload{}))
+
+		rh := resthooks.NewResthook(&ZapierResthookStore{DB: DB})
+		defer rh.Close()
+		Notify(ctx, &rh, project.ID, NewErrorGroupEvent, ErrorGroupTrigger{})
+		assert.Error(t, NotifyAlert(ctx, &rh, ErrorAlert, 1, &model.Alert{ProjectID: project.ID}, HookPayload{}))
+
+		bodies := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies <- string(body)
+		}))
+		defer server.Close()
+		require.NoError(t, DB.Create(&model.ResthookSubscription{
+			ProjectID: project.ID,
+			Event:     pointy.String(NewErrorGroupEvent),
+			TargetUrl: pointy.String(server.URL),
+		}).Error)
+
+		Notify(ctx, &rh, project.ID, NewErrorGroupEvent, ErrorGroupTrigger{SecureID: "notified-error-group"})
+		select {
+		case body := <-bodies:
+			assert.Contains(t, body, "notified-error-group")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the subscription was not notified")
+		}
+	})
+}
+
+func TestTriggerRoutes(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		project, token := newZapierProject(t)
+		otherProject, _ := newZapierProject(t)
+		r, rh := newZapierRouter()
+		defer rh.Close()
+
+		require.NoError(t, DB.Create(&[]*model.ErrorGroup{
+			{ProjectID: project.ID, SecureID: "first", State: "OPEN"},
+			{ProjectID: project.ID, SecureID: "second", State: "OPEN"},
+			{ProjectID: otherProject.ID, SecureID: "other", State: "OPEN"},
+		}).Error)
+		require.NoError(t, DB.Create(&[]*model.Session{
+			{ProjectID: project.ID, SecureID: "identified", Identified: true, Identifier: "vadim@highlight.io"},
+			{ProjectID: project.ID, SecureID: "anonymous"},
+		}).Error)
+
+		w := getTrigger(r, "/triggers/new-error-groups", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		var errorGroups []ErrorGroupTrigger
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&errorGroups))
+		require.Len(t, errorGroups, 2)
+		assert.Equal(t, "second", errorGroups[0].SecureID)
+		assert.Equal(t, "first", errorGroups[1].SecureID)
+
+		w = getTrigger(r, "/triggers/new-identified-sessions", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		var sessions []IdentifiedSessionTrigger
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "identified", sessions[0].SecureID)
+
+		w = getTrigger(r, "/triggers/alerts-fired", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+}
+
+func TestTriggerRoutes_Unauthorized(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		project, _ := newZapierProject(t)
+		r, rh := newZapierRouter()
+		defer rh.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, getTrigger(r, "/triggers/new-error-groups", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, getTrigger(r, "/triggers/new-error-groups", "not-a-jwt").Code)
+
+		// a token of a deleted project
+		token, err := GenerateZapierAccessToken(project.ID + 1000)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, getTrigger(r, "/triggers/new-error-groups", token).Code)
+
+		// a token that was replaced by a newer one
+		token, err = GenerateZapierAccessToken(project.ID)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, getTrigger(r, "/triggers/alerts-fired", token).Code)
+	})
+}
+
+func TestTriggerRoutes_QueryError(t *testing.T) {
+	util.RunTestWithDBWipe(t, DB, func(t *testing.T) {
+		project, _ := newZapierProject(t)
+		r := chi.NewRouter()
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), model.ContextKeys.ZapierProject, project)))
+			})
+		})
+		createTriggerRoutes(r, DB)
+
+		// the queries fail when the request is canceled
+		for _, path := range []string{"/new-error-groups", "/new-identified-sessions", "/alerts-fired"} {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+			assert.Equal(t, http.StatusInternalServerError, w.Code, path)
+		}
+	})
+}
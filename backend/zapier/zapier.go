@@ -184,6 +184,11 @@ func CreateZapierRoutes(r chi.Router, db *gorm.DB, store *ZapierResthookStore, r
 		})
 	})
 
+	r.Route("/triggers", func(r chi.Router) {
+		r.Use(RequireValidZapierAuth)
+		createTriggerRoutes(r, db)
+	})
+
 	r.Route("/hooks", func(r chi.Router) {
 		r.Use(RequireValidZapierAuth)
 		r.Handle("/*", rh.Handler())
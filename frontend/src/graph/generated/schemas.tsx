@@ -407,6 +407,7 @@ export type EnhancedUserDetailsResult = {
 
 export enum EnhancementSource {
 	Github = 'github',
+	Gitlab = 'gitlab',
 	Sourcemap = 'sourcemap',
 }
 
@@ -734,17 +735,24 @@ export type IntegrationStatus = {
 }
 
 export enum IntegrationType {
+	Asana = 'Asana',
 	ClickUp = 'ClickUp',
 	Discord = 'Discord',
 	Front = 'Front',
 	GitHub = 'GitHub',
 	GitLab = 'GitLab',
 	Height = 'Height',
+	HubSpot = 'HubSpot',
+	Intercom = 'Intercom',
 	Jira = 'Jira',
 	Linear = 'Linear',
+	Monday = 'Monday',
+	Salesforce = 'Salesforce',
+	Shortcut = 'Shortcut',
 	Slack = 'Slack',
 	Vercel = 'Vercel',
 	Zapier = 'Zapier',
+	Zendesk = 'Zendesk',
 }
 
 export type Invoice = {
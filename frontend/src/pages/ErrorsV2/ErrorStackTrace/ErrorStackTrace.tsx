@@ -366,6 +366,14 @@ const StackSection: React.FC<React.PropsWithChildren<StackSectionProps>> = ({
 						.
 					</Tooltip>
 				)}
+				{enhancementSource == 'gitlab' && (
+					<Tooltip trigger={<Badge label={versionString} />}>
+						This stacktrace was enhanced using GitLab
+						{enhancementVersion &&
+							` with commit version, ${versionString}`}
+						.
+					</Tooltip>
+				)}
 				<SourcemapError
 					errorObjectId={errorObjectId}
 					metadata={sourceMappingErrorMetadata}